@@ -4,10 +4,14 @@ import (
 	"context"
 
 	grpc_zap "github.com/grpc-ecosystem/go-grpc-middleware/logging/zap"
+	perrors "github.com/pingcap/errors"
 	"github.com/pingcap/tiflow/dm/pkg/log"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
 
 	"github.com/hanfei1991/microcosm/pb"
 	"github.com/hanfei1991/microcosm/pkg/errors"
@@ -28,8 +32,17 @@ type closeableConnIface interface {
 // baseExecutorClientImpl implements baseExecutorClient.
 // TODO unit tests.
 type baseExecutorClientImpl struct {
+	addr   string
 	conn   closeableConnIface
 	client pb.ExecutorClient
+	// breaker rejects requests to an executor that keeps failing, it is nil
+	// for clients created in tests.
+	breaker *circuitBreaker
+}
+
+// connStateGetter is implemented by *grpc.ClientConn.
+type connStateGetter interface {
+	GetState() connectivity.State
 }
 
 func newExecutorClientForTest(addr string) (*baseExecutorClientImpl, error) {
@@ -38,12 +51,13 @@ func newExecutorClientForTest(addr string) (*baseExecutorClientImpl, error) {
 		return nil, errors.ErrGrpcBuildConn.GenWithStackByArgs(addr)
 	}
 	return &baseExecutorClientImpl{
+		addr:   addr,
 		conn:   conn,
 		client: mock.NewExecutorClient(conn),
 	}, nil
 }
 
-func newBaseExecutorClient(addr string) (*baseExecutorClientImpl, error) {
+func newBaseExecutorClient(addr string, breaker *circuitBreaker) (*baseExecutorClientImpl, error) {
 	if test.GetGlobalTestFlag() {
 		return newExecutorClientForTest(addr)
 	}
@@ -60,12 +74,45 @@ func newBaseExecutorClient(addr string) (*baseExecutorClientImpl, error) {
 	}
 
 	return &baseExecutorClientImpl{
-		conn:   conn,
-		client: pb.NewExecutorClient(conn),
+		addr:    addr,
+		conn:    conn,
+		client:  pb.NewExecutorClient(conn),
+		breaker: breaker,
 	}, nil
 }
 
+// Close closes the underlying gRPC connection.
+func (c *baseExecutorClientImpl) Close() error {
+	return c.conn.Close()
+}
+
+// checkHealth trips the circuit breaker if the connection is broken, and
+// closes the breaker if the connection recovers.
+func (c *baseExecutorClientImpl) checkHealth() {
+	conn, ok := c.conn.(connStateGetter)
+	if !ok {
+		return
+	}
+	switch conn.GetState() {
+	case connectivity.TransientFailure, connectivity.Shutdown:
+		if !c.breaker.isOpen() {
+			log.L().Warn("executor connection is broken, open circuit breaker",
+				zap.String("addr", c.addr))
+		}
+		c.breaker.trip()
+	case connectivity.Ready:
+		if c.breaker.isOpen() {
+			log.L().Info("executor connection recovers, close circuit breaker",
+				zap.String("addr", c.addr))
+		}
+		c.breaker.onSuccess()
+	}
+}
+
 func (c *baseExecutorClientImpl) Send(ctx context.Context, req *ExecutorRequest) (*ExecutorResponse, error) {
+	if !c.breaker.allow() {
+		return nil, errors.ErrExecutorCircuitOpen.GenWithStackByArgs(c.addr)
+	}
 	resp := &ExecutorResponse{}
 	var err error
 	switch req.Cmd {
@@ -77,6 +124,13 @@ func (c *baseExecutorClientImpl) Send(ctx context.Context, req *ExecutorRequest)
 	if err != nil {
 		log.L().Logger.Error("send req meet error", zap.Error(err))
 	}
+	// errors returned by the executor don't mean it is unavailable
+	switch status.Code(perrors.Cause(err)) {
+	case codes.OK:
+		c.breaker.onSuccess()
+	case codes.Unavailable, codes.DeadlineExceeded:
+		c.breaker.onFailure()
+	}
 	return resp, err
 }
 
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hanfei1991/microcosm/pkg/clock"
+)
+
+const (
+	defaultBreakerFailureThreshold = 3
+	defaultBreakerCooldown         = 10 * time.Second
+)
+
+// circuitBreaker stops sending requests to an executor that keeps failing,
+// so that the callers fail fast instead of waiting for timeouts. The breaker
+// opens after threshold consecutive failures, or when the health check finds
+// the connection broken. After cooldown requests are let through again, and
+// the breaker reopens on the first failure until a request succeeds.
+type circuitBreaker struct {
+	clock     clock.Clock
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+func newCircuitBreaker(clk clock.Clock) *circuitBreaker {
+	return &circuitBreaker{
+		clock:     clk,
+		threshold: defaultBreakerFailureThreshold,
+		cooldown:  defaultBreakerCooldown,
+	}
+}
+
+// allow returns whether a request can be sent, it is always true for a nil
+// breaker.
+func (b *circuitBreaker) allow() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !b.clock.Now().Before(b.openUntil)
+}
+
+func (b *circuitBreaker) onSuccess() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.openUntil = time.Time{}
+}
+
+func (b *circuitBreaker) onFailure() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openUntil = b.clock.Now().Add(b.cooldown)
+	}
+}
+
+// trip opens the breaker regardless of the failures.
+func (b *circuitBreaker) trip() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		b.failures = b.threshold
+	}
+	b.openUntil = b.clock.Now().Add(b.cooldown)
+}
+
+// isOpen returns whether the breaker is tripped, including in cooldown.
+func (b *circuitBreaker) isOpen() bool {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.failures >= b.threshold
+}
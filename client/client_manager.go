@@ -3,12 +3,21 @@ package client
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/hanfei1991/microcosm/model"
+	"github.com/hanfei1991/microcosm/pkg/clock"
 	"github.com/pingcap/tiflow/dm/pkg/log"
 	"go.uber.org/zap"
 )
 
+const (
+	// defaultExecutorIdleTimeout is how long an executor client is kept
+	// without being used, the connection is closed after that.
+	defaultExecutorIdleTimeout = 10 * time.Minute
+	defaultCheckInterval       = 10 * time.Second
+)
+
 // ClientsManager defines interface to manage all clients, including master client
 // and executor clients.
 type ClientsManager interface {
@@ -20,17 +29,30 @@ type ClientsManager interface {
 // NewClientManager creates a new Manager instance
 func NewClientManager() *Manager {
 	return &Manager{
-		executors: make(map[model.ExecutorID]ExecutorClient),
+		clock:       clock.New(),
+		idleTimeout: defaultExecutorIdleTimeout,
+		executors:   make(map[model.ExecutorID]ExecutorClient),
+		addrs:       make(map[model.ExecutorID]string),
+		lastUsed:    make(map[model.ExecutorID]time.Time),
 	}
 }
 
 // Manager is used to maintain all clients to server master and executor.
-// TODO: We need to consider when to remove executor client and how to process transilient error.
+// TODO: We need to consider how to process transilient error.
 type Manager struct {
+	clock       clock.Clock
+	idleTimeout time.Duration
+
 	mu sync.RWMutex
 
 	master    *MasterClientImpl
 	executors map[model.ExecutorID]ExecutorClient
+	// addrs caches the addresses of executor clients, an executor client
+	// added by AddExecutorClient has no cached address.
+	addrs map[model.ExecutorID]string
+	// lastUsed records the last time the executor clients are got, idle
+	// clients are evicted by Run.
+	lastUsed map[model.ExecutorID]time.Time
 }
 
 // MasterClient implements ClientsManager.MasterClient.
@@ -40,9 +62,13 @@ func (c *Manager) MasterClient() MasterClient {
 
 // ExecutorClient implements ClientsManager.ExecutorClient
 func (c *Manager) ExecutorClient(id model.ExecutorID) ExecutorClient {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	return c.executors[id]
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	client, ok := c.executors[id]
+	if ok {
+		c.lastUsed[id] = c.clock.Now()
+	}
+	return client
 }
 
 // AddMasterClient creates a new master client.
@@ -59,19 +85,30 @@ func (c *Manager) AddMasterClient(ctx context.Context, addrs []string) error {
 
 // AddExecutor implements ClientsManager.AddExecutor
 // It creates a new executor client for the given executor. If the executor
-// client already exists, does nothing.
+// client already exists with the same address, does nothing. If the executor
+// has moved to a new address, the stale client is replaced.
 func (c *Manager) AddExecutor(id model.ExecutorID, addr string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	if _, ok := c.executors[id]; ok {
-		return nil
+		cachedAddr, hasAddr := c.addrs[id]
+		if !hasAddr || cachedAddr == addr {
+			return nil
+		}
+		log.L().Info("client manager replaces executor with stale address",
+			zap.String("id", string(id)),
+			zap.String("stale-addr", cachedAddr),
+			zap.String("addr", addr))
+		c.removeExecutorLocked(id)
 	}
 	log.L().Info("client manager adds executor", zap.String("id", string(id)), zap.String("addr", addr))
-	client, err := newExecutorClient(addr)
+	client, err := newExecutorClient(addr, newCircuitBreaker(c.clock))
 	if err != nil {
 		return err
 	}
 	c.executors[id] = client
+	c.addrs[id] = addr
+	c.lastUsed[id] = c.clock.Now()
 	return nil
 }
 
@@ -81,5 +118,109 @@ func (c *Manager) AddExecutorClient(id model.ExecutorID, client ExecutorClient)
 	defer c.mu.Unlock()
 
 	c.executors[id] = client
+	c.lastUsed[id] = c.clock.Now()
 	return nil
 }
+
+// RemoveExecutor removes and closes the client of the given executor.
+func (c *Manager) RemoveExecutor(id model.ExecutorID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.removeExecutorLocked(id)
+}
+
+func (c *Manager) removeExecutorLocked(id model.ExecutorID) {
+	client, ok := c.executors[id]
+	if !ok {
+		return
+	}
+	delete(c.executors, id)
+	delete(c.addrs, id)
+	delete(c.lastUsed, id)
+	if closer, ok := client.(closeableConnIface); ok {
+		if err := closer.Close(); err != nil {
+			log.L().Warn("close executor client failed",
+				zap.String("id", string(id)), zap.Error(err))
+		}
+	}
+}
+
+// HandleDiscoveryEvent invalidates cached executor clients according to
+// the node changes found by service discovery. The client of an executor
+// that is removed, or registers again with a new address, is dropped, and
+// AddExecutor connects to the fresh address in the next dispatching.
+// Connecting is not done here since it blocks until the executor is ready.
+func (c *Manager) HandleDiscoveryEvent(addSet, delSet map[string]model.NodeInfo) {
+	for _, node := range delSet {
+		if node.Type != model.NodeTypeExecutor {
+			continue
+		}
+		c.RemoveExecutor(node.ID)
+	}
+	for _, node := range addSet {
+		if node.Type != model.NodeTypeExecutor {
+			continue
+		}
+		c.mu.Lock()
+		if cachedAddr, ok := c.addrs[node.ID]; ok && cachedAddr != node.Addr {
+			log.L().Info("client manager invalidates executor with stale address",
+				zap.String("id", string(node.ID)),
+				zap.String("stale-addr", cachedAddr),
+				zap.String("addr", node.Addr))
+			c.removeExecutorLocked(node.ID)
+		}
+		c.mu.Unlock()
+	}
+}
+
+// healthChecker is implemented by executor clients that check the health of
+// their connections.
+type healthChecker interface {
+	checkHealth()
+}
+
+// Run evicts the executor clients that are idle, and checks the health of
+// the executor clients periodically until the context is done.
+func (c *Manager) Run(ctx context.Context) {
+	ticker := c.clock.Ticker(defaultCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		c.evictIdle()
+		c.checkHealth()
+	}
+}
+
+func (c *Manager) evictIdle() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.clock.Now()
+	for id, lastUsed := range c.lastUsed {
+		if now.Sub(lastUsed) < c.idleTimeout {
+			continue
+		}
+		log.L().Info("client manager evicts idle executor",
+			zap.String("id", string(id)), zap.Duration("idle", now.Sub(lastUsed)))
+		c.removeExecutorLocked(id)
+	}
+}
+
+func (c *Manager) checkHealth() {
+	c.mu.RLock()
+	checkers := make([]healthChecker, 0, len(c.executors))
+	for _, client := range c.executors {
+		if checker, ok := client.(healthChecker); ok {
+			checkers = append(checkers, checker)
+		}
+	}
+	c.mu.RUnlock()
+
+	for _, checker := range checkers {
+		checker.checkHealth()
+	}
+}
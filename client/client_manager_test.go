@@ -0,0 +1,134 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hanfei1991/microcosm/model"
+	"github.com/hanfei1991/microcosm/pkg/clock"
+)
+
+type closeRecordingConn struct {
+	closed bool
+}
+
+func (c *closeRecordingConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func addExecutorForTest(m *Manager, id model.ExecutorID, addr string) *closeRecordingConn {
+	conn := &closeRecordingConn{}
+	m.executors[id] = &executorClientImpl{
+		baseExecutorClientImpl: &baseExecutorClientImpl{conn: conn},
+	}
+	m.addrs[id] = addr
+	return conn
+}
+
+func TestManagerHandleDiscoveryEvent(t *testing.T) {
+	t.Parallel()
+
+	m := NewClientManager()
+	conn1 := addExecutorForTest(m, "executor-1", "127.0.0.1:10001")
+	conn2 := addExecutorForTest(m, "executor-2", "127.0.0.1:10002")
+	conn3 := addExecutorForTest(m, "executor-3", "127.0.0.1:10003")
+
+	m.HandleDiscoveryEvent(
+		map[string]model.NodeInfo{
+			// executor-1 restarts with a new address
+			"executor-1": {Type: model.NodeTypeExecutor, ID: "executor-1", Addr: "127.0.0.1:20001"},
+			// executor-2 is not changed
+			"executor-2": {Type: model.NodeTypeExecutor, ID: "executor-2", Addr: "127.0.0.1:10002"},
+			// server masters are ignored
+			"master-1": {Type: model.NodeTypeServerMaster, ID: "master-1", Addr: "127.0.0.1:10240"},
+		},
+		map[string]model.NodeInfo{
+			"executor-3": {Type: model.NodeTypeExecutor, ID: "executor-3", Addr: "127.0.0.1:10003"},
+		},
+	)
+
+	require.Nil(t, m.ExecutorClient("executor-1"))
+	require.True(t, conn1.closed)
+	require.NotNil(t, m.ExecutorClient("executor-2"))
+	require.False(t, conn2.closed)
+	require.Nil(t, m.ExecutorClient("executor-3"))
+	require.True(t, conn3.closed)
+	require.Len(t, m.addrs, 1)
+
+	// executors added without an address are never invalidated by address
+	m.RemoveExecutor("executor-2")
+	require.True(t, conn2.closed)
+	err := m.AddExecutorClient("executor-4", &executorClientImpl{
+		baseExecutorClientImpl: &baseExecutorClientImpl{conn: &closeRecordingConn{}},
+	})
+	require.NoError(t, err)
+	m.HandleDiscoveryEvent(map[string]model.NodeInfo{
+		"executor-4": {Type: model.NodeTypeExecutor, ID: "executor-4", Addr: "127.0.0.1:10004"},
+	}, nil)
+	require.NotNil(t, m.ExecutorClient("executor-4"))
+}
+
+func TestManagerEvictIdle(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.NewMock()
+	m := NewClientManager()
+	m.clock = clk
+	conn1 := &closeRecordingConn{}
+	conn2 := &closeRecordingConn{}
+	require.NoError(t, m.AddExecutorClient("executor-1", &executorClientImpl{
+		baseExecutorClientImpl: &baseExecutorClientImpl{conn: conn1},
+	}))
+	require.NoError(t, m.AddExecutorClient("executor-2", &executorClientImpl{
+		baseExecutorClientImpl: &baseExecutorClientImpl{conn: conn2},
+	}))
+
+	clk.Add(defaultExecutorIdleTimeout / 2)
+	require.NotNil(t, m.ExecutorClient("executor-1"))
+	clk.Add(defaultExecutorIdleTimeout / 2)
+	m.evictIdle()
+	require.NotNil(t, m.ExecutorClient("executor-1"))
+	require.False(t, conn1.closed)
+	require.Nil(t, m.ExecutorClient("executor-2"))
+	require.True(t, conn2.closed)
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.NewMock()
+	b := newCircuitBreaker(clk)
+	for i := 0; i < defaultBreakerFailureThreshold-1; i++ {
+		b.onFailure()
+		require.True(t, b.allow())
+	}
+	b.onSuccess()
+	for i := 0; i < defaultBreakerFailureThreshold; i++ {
+		require.True(t, b.allow())
+		b.onFailure()
+	}
+	require.False(t, b.allow())
+	require.True(t, b.isOpen())
+
+	// requests are let through after cooldown, and the first failure opens
+	// the breaker again
+	clk.Add(defaultBreakerCooldown)
+	require.True(t, b.allow())
+	b.onFailure()
+	require.False(t, b.allow())
+	clk.Add(defaultBreakerCooldown)
+	b.onSuccess()
+	require.True(t, b.allow())
+	require.False(t, b.isOpen())
+
+	b.trip()
+	require.False(t, b.allow())
+
+	// a nil breaker allows all requests
+	var nilBreaker *circuitBreaker
+	nilBreaker.onFailure()
+	nilBreaker.trip()
+	require.True(t, nilBreaker.allow())
+}
@@ -17,8 +17,8 @@ type ExecutorClient interface {
 	) error
 }
 
-func newExecutorClient(addr string) (ExecutorClient, error) {
-	base, err := newBaseExecutorClient(addr)
+func newExecutorClient(addr string, breaker *circuitBreaker) (ExecutorClient, error) {
+	base, err := newBaseExecutorClient(addr, breaker)
 	if err != nil {
 		return nil, err
 	}
@@ -28,16 +28,77 @@ type MasterClient interface {
 	) (resp *pb.ExecWorkloadResponse, err error)
 	SubmitJob(ctx context.Context, req *pb.SubmitJobRequest) (resp *pb.SubmitJobResponse, err error)
 	QueryJob(ctx context.Context, req *pb.QueryJobRequest) (resp *pb.QueryJobResponse, err error)
+	QueryJobs(ctx context.Context, req *pb.QueryJobsRequest) (resp *pb.QueryJobsResponse, err error)
+	ListWorkers(ctx context.Context, req *pb.ListWorkersRequest) (resp *pb.ListWorkersResponse, err error)
+	CordonExecutor(
+		ctx context.Context, req *pb.CordonExecutorRequest,
+	) (resp *pb.CordonExecutorResponse, err error)
+	QueryExecutorCordons(
+		ctx context.Context, req *pb.QueryExecutorCordonsRequest,
+	) (resp *pb.QueryExecutorCordonsResponse, err error)
+	ClusterHealth(ctx context.Context, req *pb.ClusterHealthRequest) (resp *pb.ClusterHealthResponse, err error)
 	PauseJob(ctx context.Context, req *pb.PauseJobRequest) (resp *pb.PauseJobResponse, err error)
 	CancelJob(ctx context.Context, req *pb.CancelJobRequest) (resp *pb.CancelJobResponse, err error)
+	UpdateJobTimeouts(
+		ctx context.Context, req *pb.UpdateJobTimeoutsRequest,
+	) (resp *pb.UpdateJobTimeoutsResponse, err error)
+	SetJobLogLevel(
+		ctx context.Context, req *pb.SetJobLogLevelRequest,
+	) (resp *pb.SetJobLogLevelResponse, err error)
+	OperateJobTasks(
+		ctx context.Context, req *pb.OperateJobTasksRequest,
+	) (resp *pb.OperateJobTasksResponse, err error)
+	UpdateJobSource(
+		ctx context.Context, req *pb.UpdateJobSourceRequest,
+	) (resp *pb.UpdateJobSourceResponse, err error)
+	CreateJobSchedule(
+		ctx context.Context, req *pb.CreateJobScheduleRequest,
+	) (resp *pb.CreateJobScheduleResponse, err error)
+	UpdateJobSchedule(
+		ctx context.Context, req *pb.UpdateJobScheduleRequest,
+	) (resp *pb.UpdateJobScheduleResponse, err error)
+	DeleteJobSchedule(
+		ctx context.Context, req *pb.DeleteJobScheduleRequest,
+	) (resp *pb.DeleteJobScheduleResponse, err error)
+	QueryJobSchedules(
+		ctx context.Context, req *pb.QueryJobSchedulesRequest,
+	) (resp *pb.QueryJobSchedulesResponse, err error)
+	RegisterJobTemplate(
+		ctx context.Context, req *pb.RegisterJobTemplateRequest,
+	) (resp *pb.RegisterJobTemplateResponse, err error)
+	DeleteJobTemplate(
+		ctx context.Context, req *pb.DeleteJobTemplateRequest,
+	) (resp *pb.DeleteJobTemplateResponse, err error)
+	QueryJobTemplates(
+		ctx context.Context, req *pb.QueryJobTemplatesRequest,
+	) (resp *pb.QueryJobTemplatesResponse, err error)
+	PutSecret(
+		ctx context.Context, req *pb.PutSecretRequest,
+	) (resp *pb.PutSecretResponse, err error)
+	DeleteSecret(
+		ctx context.Context, req *pb.DeleteSecretRequest,
+	) (resp *pb.DeleteSecretResponse, err error)
+	ListSecrets(
+		ctx context.Context, req *pb.ListSecretsRequest,
+	) (resp *pb.ListSecretsResponse, err error)
 	QueryMetaStore(
 		ctx context.Context, req *pb.QueryMetaStoreRequest, timeout time.Duration,
 	) (resp *pb.QueryMetaStoreResponse, err error)
+	MigrateMetaStore(
+		ctx context.Context, req *pb.MigrateMetaStoreRequest,
+	) (resp *pb.MigrateMetaStoreResponse, err error)
 	ScheduleTask(
 		ctx context.Context,
 		req *pb.ScheduleTaskRequest,
 		timeout time.Duration,
 	) (resp *pb.ScheduleTaskResponse, err error)
+	// Schedule opens a schedule stream to the leader, see pb.MasterClient.Schedule.
+	Schedule(ctx context.Context) (pb.Master_ScheduleClient, error)
+	ScaleUpJob(
+		ctx context.Context,
+		req *pb.ScaleUpJobRequest,
+		timeout time.Duration,
+	) (resp *pb.ScaleUpJobResponse, err error)
 	PersistResource(
 		ctx context.Context,
 		request *pb.PersistResourceRequest,
@@ -46,7 +107,8 @@ type MasterClient interface {
 	GetLeaderClient() pb.MasterClient
 }
 
-// MasterClientImpl implemeents MasterClient interface
+// MasterClientImpl implemeents MasterClient interface. RPCs are sent to the
+// leader first and fail over to the other servers, see rpcutil.DoFailoverRPC.
 type MasterClientImpl struct {
 	*rpcutil.FailoverRPCClients[pb.MasterClient]
 }
@@ -102,6 +164,37 @@ func (c *MasterClientImpl) QueryJob(ctx context.Context, req *pb.QueryJobRequest
 	return rpcutil.DoFailoverRPC(ctx, c.FailoverRPCClients, req, pb.MasterClient.QueryJob)
 }
 
+// QueryJobs implemeents MasterClient.QueryJobs
+func (c *MasterClientImpl) QueryJobs(ctx context.Context, req *pb.QueryJobsRequest) (resp *pb.QueryJobsResponse, err error) {
+	return rpcutil.DoFailoverRPC(ctx, c.FailoverRPCClients, req, pb.MasterClient.QueryJobs)
+}
+
+// ListWorkers implemeents MasterClient.ListWorkers
+func (c *MasterClientImpl) ListWorkers(ctx context.Context, req *pb.ListWorkersRequest) (resp *pb.ListWorkersResponse, err error) {
+	return rpcutil.DoFailoverRPC(ctx, c.FailoverRPCClients, req, pb.MasterClient.ListWorkers)
+}
+
+// CordonExecutor implemeents MasterClient.CordonExecutor
+func (c *MasterClientImpl) CordonExecutor(
+	ctx context.Context, req *pb.CordonExecutorRequest,
+) (resp *pb.CordonExecutorResponse, err error) {
+	return rpcutil.DoFailoverRPC(ctx, c.FailoverRPCClients, req, pb.MasterClient.CordonExecutor)
+}
+
+// QueryExecutorCordons implemeents MasterClient.QueryExecutorCordons
+func (c *MasterClientImpl) QueryExecutorCordons(
+	ctx context.Context, req *pb.QueryExecutorCordonsRequest,
+) (resp *pb.QueryExecutorCordonsResponse, err error) {
+	return rpcutil.DoFailoverRPC(ctx, c.FailoverRPCClients, req, pb.MasterClient.QueryExecutorCordons)
+}
+
+// ClusterHealth implemeents MasterClient.ClusterHealth
+func (c *MasterClientImpl) ClusterHealth(
+	ctx context.Context, req *pb.ClusterHealthRequest,
+) (resp *pb.ClusterHealthResponse, err error) {
+	return rpcutil.DoFailoverRPC(ctx, c.FailoverRPCClients, req, pb.MasterClient.ClusterHealth)
+}
+
 // PauseJob implemeents MasterClient.PauseJob
 func (c *MasterClientImpl) PauseJob(ctx context.Context, req *pb.PauseJobRequest) (resp *pb.PauseJobResponse, err error) {
 	return rpcutil.DoFailoverRPC(ctx, c.FailoverRPCClients, req, pb.MasterClient.PauseJob)
@@ -112,6 +205,111 @@ func (c *MasterClientImpl) CancelJob(ctx context.Context, req *pb.CancelJobReque
 	return rpcutil.DoFailoverRPC(ctx, c.FailoverRPCClients, req, pb.MasterClient.CancelJob)
 }
 
+// UpdateJobTimeouts implemeents MasterClient.UpdateJobTimeouts
+func (c *MasterClientImpl) UpdateJobTimeouts(
+	ctx context.Context, req *pb.UpdateJobTimeoutsRequest,
+) (resp *pb.UpdateJobTimeoutsResponse, err error) {
+	return rpcutil.DoFailoverRPC(ctx, c.FailoverRPCClients, req, pb.MasterClient.UpdateJobTimeouts)
+}
+
+// SetJobLogLevel implemeents MasterClient.SetJobLogLevel
+func (c *MasterClientImpl) SetJobLogLevel(
+	ctx context.Context, req *pb.SetJobLogLevelRequest,
+) (resp *pb.SetJobLogLevelResponse, err error) {
+	return rpcutil.DoFailoverRPC(ctx, c.FailoverRPCClients, req, pb.MasterClient.SetJobLogLevel)
+}
+
+// OperateJobTasks implements MasterClient.OperateJobTasks
+func (c *MasterClientImpl) OperateJobTasks(
+	ctx context.Context, req *pb.OperateJobTasksRequest,
+) (resp *pb.OperateJobTasksResponse, err error) {
+	return rpcutil.DoFailoverRPC(ctx, c.FailoverRPCClients, req, pb.MasterClient.OperateJobTasks)
+}
+
+// UpdateJobSource implements MasterClient.UpdateJobSource
+func (c *MasterClientImpl) UpdateJobSource(
+	ctx context.Context, req *pb.UpdateJobSourceRequest,
+) (resp *pb.UpdateJobSourceResponse, err error) {
+	return rpcutil.DoFailoverRPC(ctx, c.FailoverRPCClients, req, pb.MasterClient.UpdateJobSource)
+}
+
+// CreateJobSchedule implemeents MasterClient.CreateJobSchedule
+func (c *MasterClientImpl) CreateJobSchedule(
+	ctx context.Context, req *pb.CreateJobScheduleRequest,
+) (resp *pb.CreateJobScheduleResponse, err error) {
+	return rpcutil.DoFailoverRPC(ctx, c.FailoverRPCClients, req, pb.MasterClient.CreateJobSchedule)
+}
+
+// UpdateJobSchedule implemeents MasterClient.UpdateJobSchedule
+func (c *MasterClientImpl) UpdateJobSchedule(
+	ctx context.Context, req *pb.UpdateJobScheduleRequest,
+) (resp *pb.UpdateJobScheduleResponse, err error) {
+	return rpcutil.DoFailoverRPC(ctx, c.FailoverRPCClients, req, pb.MasterClient.UpdateJobSchedule)
+}
+
+// DeleteJobSchedule implemeents MasterClient.DeleteJobSchedule
+func (c *MasterClientImpl) DeleteJobSchedule(
+	ctx context.Context, req *pb.DeleteJobScheduleRequest,
+) (resp *pb.DeleteJobScheduleResponse, err error) {
+	return rpcutil.DoFailoverRPC(ctx, c.FailoverRPCClients, req, pb.MasterClient.DeleteJobSchedule)
+}
+
+// QueryJobSchedules implemeents MasterClient.QueryJobSchedules
+func (c *MasterClientImpl) QueryJobSchedules(
+	ctx context.Context, req *pb.QueryJobSchedulesRequest,
+) (resp *pb.QueryJobSchedulesResponse, err error) {
+	return rpcutil.DoFailoverRPC(ctx, c.FailoverRPCClients, req, pb.MasterClient.QueryJobSchedules)
+}
+
+// RegisterJobTemplate implemeents MasterClient.RegisterJobTemplate
+func (c *MasterClientImpl) RegisterJobTemplate(
+	ctx context.Context, req *pb.RegisterJobTemplateRequest,
+) (resp *pb.RegisterJobTemplateResponse, err error) {
+	return rpcutil.DoFailoverRPC(ctx, c.FailoverRPCClients, req, pb.MasterClient.RegisterJobTemplate)
+}
+
+// DeleteJobTemplate implemeents MasterClient.DeleteJobTemplate
+func (c *MasterClientImpl) DeleteJobTemplate(
+	ctx context.Context, req *pb.DeleteJobTemplateRequest,
+) (resp *pb.DeleteJobTemplateResponse, err error) {
+	return rpcutil.DoFailoverRPC(ctx, c.FailoverRPCClients, req, pb.MasterClient.DeleteJobTemplate)
+}
+
+// QueryJobTemplates implemeents MasterClient.QueryJobTemplates
+func (c *MasterClientImpl) QueryJobTemplates(
+	ctx context.Context, req *pb.QueryJobTemplatesRequest,
+) (resp *pb.QueryJobTemplatesResponse, err error) {
+	return rpcutil.DoFailoverRPC(ctx, c.FailoverRPCClients, req, pb.MasterClient.QueryJobTemplates)
+}
+
+// PutSecret implemeents MasterClient.PutSecret
+func (c *MasterClientImpl) PutSecret(
+	ctx context.Context, req *pb.PutSecretRequest,
+) (resp *pb.PutSecretResponse, err error) {
+	return rpcutil.DoFailoverRPC(ctx, c.FailoverRPCClients, req, pb.MasterClient.PutSecret)
+}
+
+// DeleteSecret implemeents MasterClient.DeleteSecret
+func (c *MasterClientImpl) DeleteSecret(
+	ctx context.Context, req *pb.DeleteSecretRequest,
+) (resp *pb.DeleteSecretResponse, err error) {
+	return rpcutil.DoFailoverRPC(ctx, c.FailoverRPCClients, req, pb.MasterClient.DeleteSecret)
+}
+
+// ListSecrets implemeents MasterClient.ListSecrets
+func (c *MasterClientImpl) ListSecrets(
+	ctx context.Context, req *pb.ListSecretsRequest,
+) (resp *pb.ListSecretsResponse, err error) {
+	return rpcutil.DoFailoverRPC(ctx, c.FailoverRPCClients, req, pb.MasterClient.ListSecrets)
+}
+
+// MigrateMetaStore implemeents MasterClient.MigrateMetaStore
+func (c *MasterClientImpl) MigrateMetaStore(
+	ctx context.Context, req *pb.MigrateMetaStoreRequest,
+) (resp *pb.MigrateMetaStoreResponse, err error) {
+	return rpcutil.DoFailoverRPC(ctx, c.FailoverRPCClients, req, pb.MasterClient.MigrateMetaStore)
+}
+
 // QueryMetaStore implemeents MasterClient.QueryMetaStore
 func (c *MasterClientImpl) QueryMetaStore(
 	ctx context.Context, req *pb.QueryMetaStoreRequest, timeout time.Duration,
@@ -133,6 +331,27 @@ func (c *MasterClientImpl) ScheduleTask(
 	return rpcutil.DoFailoverRPC(ctx1, c.FailoverRPCClients, req, pb.MasterClient.ScheduleTask)
 }
 
+// Schedule implements MasterClient.Schedule. The stream is not failed over,
+// the caller should open a new one after it is broken.
+func (c *MasterClientImpl) Schedule(ctx context.Context) (pb.Master_ScheduleClient, error) {
+	leader := c.GetLeaderClient()
+	if leader == nil {
+		return nil, errors.ErrNoRPCClient.GenWithStackByArgs()
+	}
+	return leader.Schedule(ctx)
+}
+
+// ScaleUpJob implements MasterClient.ScaleUpJob
+func (c *MasterClientImpl) ScaleUpJob(
+	ctx context.Context,
+	req *pb.ScaleUpJobRequest,
+	timeout time.Duration,
+) (resp *pb.ScaleUpJobResponse, err error) {
+	ctx1, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return rpcutil.DoFailoverRPC(ctx1, c.FailoverRPCClients, req, pb.MasterClient.ScaleUpJob)
+}
+
 // ReportExecutorWorkload implemeents MasterClient.ReportExecutorWorkload
 func (c *MasterClientImpl) ReportExecutorWorkload(
 	ctx context.Context,
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/hanfei1991/microcosm/pb"
 )
@@ -108,6 +110,57 @@ func (c *MockServerMasterClient) QueryJob(ctx context.Context, req *pb.QueryJobR
 	return args.Get(0).(*pb.QueryJobResponse), args.Error(1)
 }
 
+// QueryJobs implements MasterClient.QueryJobs
+func (c *MockServerMasterClient) QueryJobs(ctx context.Context, req *pb.QueryJobsRequest) (resp *pb.QueryJobsResponse, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	args := c.Mock.Called(ctx, req)
+	return args.Get(0).(*pb.QueryJobsResponse), args.Error(1)
+}
+
+// ListWorkers implements MasterClient.ListWorkers
+func (c *MockServerMasterClient) ListWorkers(ctx context.Context, req *pb.ListWorkersRequest) (resp *pb.ListWorkersResponse, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	args := c.Mock.Called(ctx, req)
+	return args.Get(0).(*pb.ListWorkersResponse), args.Error(1)
+}
+
+// CordonExecutor implements MasterClient.CordonExecutor
+func (c *MockServerMasterClient) CordonExecutor(
+	ctx context.Context, req *pb.CordonExecutorRequest,
+) (resp *pb.CordonExecutorResponse, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	args := c.Mock.Called(ctx, req)
+	return args.Get(0).(*pb.CordonExecutorResponse), args.Error(1)
+}
+
+// QueryExecutorCordons implements MasterClient.QueryExecutorCordons
+func (c *MockServerMasterClient) QueryExecutorCordons(
+	ctx context.Context, req *pb.QueryExecutorCordonsRequest,
+) (resp *pb.QueryExecutorCordonsResponse, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	args := c.Mock.Called(ctx, req)
+	return args.Get(0).(*pb.QueryExecutorCordonsResponse), args.Error(1)
+}
+
+// ClusterHealth implements MasterClient.ClusterHealth
+func (c *MockServerMasterClient) ClusterHealth(
+	ctx context.Context, req *pb.ClusterHealthRequest,
+) (resp *pb.ClusterHealthResponse, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	args := c.Mock.Called(ctx, req)
+	return args.Get(0).(*pb.ClusterHealthResponse), args.Error(1)
+}
+
 // PauseJob implements MasterClient.PauseJob
 func (c *MockServerMasterClient) PauseJob(ctx context.Context, req *pb.PauseJobRequest) (resp *pb.PauseJobResponse, err error) {
 	c.mu.Lock()
@@ -117,6 +170,160 @@ func (c *MockServerMasterClient) PauseJob(ctx context.Context, req *pb.PauseJobR
 	return args.Get(0).(*pb.PauseJobResponse), args.Error(1)
 }
 
+// UpdateJobTimeouts implements MasterClient.UpdateJobTimeouts
+func (c *MockServerMasterClient) UpdateJobTimeouts(
+	ctx context.Context, req *pb.UpdateJobTimeoutsRequest,
+) (resp *pb.UpdateJobTimeoutsResponse, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	args := c.Mock.Called(ctx, req)
+	return args.Get(0).(*pb.UpdateJobTimeoutsResponse), args.Error(1)
+}
+
+// SetJobLogLevel implements MasterClient.SetJobLogLevel
+func (c *MockServerMasterClient) SetJobLogLevel(
+	ctx context.Context, req *pb.SetJobLogLevelRequest,
+) (resp *pb.SetJobLogLevelResponse, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	args := c.Mock.Called(ctx, req)
+	return args.Get(0).(*pb.SetJobLogLevelResponse), args.Error(1)
+}
+
+// OperateJobTasks implements MasterClient.OperateJobTasks
+func (c *MockServerMasterClient) OperateJobTasks(
+	ctx context.Context, req *pb.OperateJobTasksRequest,
+) (resp *pb.OperateJobTasksResponse, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	args := c.Mock.Called(ctx, req)
+	return args.Get(0).(*pb.OperateJobTasksResponse), args.Error(1)
+}
+
+// UpdateJobSource implements MasterClient.UpdateJobSource
+func (c *MockServerMasterClient) UpdateJobSource(
+	ctx context.Context, req *pb.UpdateJobSourceRequest,
+) (resp *pb.UpdateJobSourceResponse, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	args := c.Mock.Called(ctx, req)
+	return args.Get(0).(*pb.UpdateJobSourceResponse), args.Error(1)
+}
+
+// CreateJobSchedule implements MasterClient.CreateJobSchedule
+func (c *MockServerMasterClient) CreateJobSchedule(
+	ctx context.Context, req *pb.CreateJobScheduleRequest,
+) (resp *pb.CreateJobScheduleResponse, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	args := c.Mock.Called(ctx, req)
+	return args.Get(0).(*pb.CreateJobScheduleResponse), args.Error(1)
+}
+
+// UpdateJobSchedule implements MasterClient.UpdateJobSchedule
+func (c *MockServerMasterClient) UpdateJobSchedule(
+	ctx context.Context, req *pb.UpdateJobScheduleRequest,
+) (resp *pb.UpdateJobScheduleResponse, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	args := c.Mock.Called(ctx, req)
+	return args.Get(0).(*pb.UpdateJobScheduleResponse), args.Error(1)
+}
+
+// DeleteJobSchedule implements MasterClient.DeleteJobSchedule
+func (c *MockServerMasterClient) DeleteJobSchedule(
+	ctx context.Context, req *pb.DeleteJobScheduleRequest,
+) (resp *pb.DeleteJobScheduleResponse, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	args := c.Mock.Called(ctx, req)
+	return args.Get(0).(*pb.DeleteJobScheduleResponse), args.Error(1)
+}
+
+// QueryJobSchedules implements MasterClient.QueryJobSchedules
+func (c *MockServerMasterClient) QueryJobSchedules(
+	ctx context.Context, req *pb.QueryJobSchedulesRequest,
+) (resp *pb.QueryJobSchedulesResponse, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	args := c.Mock.Called(ctx, req)
+	return args.Get(0).(*pb.QueryJobSchedulesResponse), args.Error(1)
+}
+
+// RegisterJobTemplate implements MasterClient.RegisterJobTemplate
+func (c *MockServerMasterClient) RegisterJobTemplate(
+	ctx context.Context, req *pb.RegisterJobTemplateRequest,
+) (resp *pb.RegisterJobTemplateResponse, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	args := c.Mock.Called(ctx, req)
+	return args.Get(0).(*pb.RegisterJobTemplateResponse), args.Error(1)
+}
+
+// DeleteJobTemplate implements MasterClient.DeleteJobTemplate
+func (c *MockServerMasterClient) DeleteJobTemplate(
+	ctx context.Context, req *pb.DeleteJobTemplateRequest,
+) (resp *pb.DeleteJobTemplateResponse, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	args := c.Mock.Called(ctx, req)
+	return args.Get(0).(*pb.DeleteJobTemplateResponse), args.Error(1)
+}
+
+// QueryJobTemplates implements MasterClient.QueryJobTemplates
+func (c *MockServerMasterClient) QueryJobTemplates(
+	ctx context.Context, req *pb.QueryJobTemplatesRequest,
+) (resp *pb.QueryJobTemplatesResponse, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	args := c.Mock.Called(ctx, req)
+	return args.Get(0).(*pb.QueryJobTemplatesResponse), args.Error(1)
+}
+
+// PutSecret implements MasterClient.PutSecret
+func (c *MockServerMasterClient) PutSecret(
+	ctx context.Context, req *pb.PutSecretRequest,
+) (resp *pb.PutSecretResponse, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	args := c.Mock.Called(ctx, req)
+	return args.Get(0).(*pb.PutSecretResponse), args.Error(1)
+}
+
+// DeleteSecret implements MasterClient.DeleteSecret
+func (c *MockServerMasterClient) DeleteSecret(
+	ctx context.Context, req *pb.DeleteSecretRequest,
+) (resp *pb.DeleteSecretResponse, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	args := c.Mock.Called(ctx, req)
+	return args.Get(0).(*pb.DeleteSecretResponse), args.Error(1)
+}
+
+// ListSecrets implements MasterClient.ListSecrets
+func (c *MockServerMasterClient) ListSecrets(
+	ctx context.Context, req *pb.ListSecretsRequest,
+) (resp *pb.ListSecretsResponse, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	args := c.Mock.Called(ctx, req)
+	return args.Get(0).(*pb.ListSecretsResponse), args.Error(1)
+}
+
 // CancelJob implements MasterClient.CancelJob
 func (c *MockServerMasterClient) CancelJob(ctx context.Context, req *pb.CancelJobRequest) (resp *pb.CancelJobResponse, err error) {
 	c.mu.Lock()
@@ -126,6 +333,17 @@ func (c *MockServerMasterClient) CancelJob(ctx context.Context, req *pb.CancelJo
 	return args.Get(0).(*pb.CancelJobResponse), args.Error(1)
 }
 
+// MigrateMetaStore implements MasterClient.MigrateMetaStore
+func (c *MockServerMasterClient) MigrateMetaStore(
+	ctx context.Context, req *pb.MigrateMetaStoreRequest,
+) (resp *pb.MigrateMetaStoreResponse, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	args := c.Mock.Called(ctx, req)
+	return args.Get(0).(*pb.MigrateMetaStoreResponse), args.Error(1)
+}
+
 // QueryMetaStore implements MasterClient.QueryMetaStore
 func (c *MockServerMasterClient) QueryMetaStore(
 	ctx context.Context,
@@ -178,6 +396,25 @@ func (c *MockServerMasterClient) ScheduleTask(
 	return args.Get(0).(*pb.ScheduleTaskResponse), args.Error(1)
 }
 
+// Schedule implements MasterClient.Schedule. The stream is not mocked, so
+// that the callers fall back to ScheduleTask.
+func (c *MockServerMasterClient) Schedule(ctx context.Context) (pb.Master_ScheduleClient, error) {
+	return nil, status.Error(codes.Unimplemented, "schedule stream is not mocked")
+}
+
+// ScaleUpJob implements MasterClient.ScaleUpJob
+func (c *MockServerMasterClient) ScaleUpJob(
+	ctx context.Context,
+	req *pb.ScaleUpJobRequest,
+	timeout time.Duration,
+) (resp *pb.ScaleUpJobResponse, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	args := c.Called(ctx, req, timeout)
+	return args.Get(0).(*pb.ScaleUpJobResponse), args.Error(1)
+}
+
 // PersistResource implements MasterClient.PersistResource
 func (c *MockServerMasterClient) PersistResource(
 	ctx context.Context,
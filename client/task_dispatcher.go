@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/gogo/status"
@@ -42,6 +43,17 @@ type DispatchTaskArgs struct {
 	MasterID     string
 	WorkerType   int64
 	WorkerConfig []byte
+
+	// Resources is the requirement of the worker in all dimensions, the
+	// executor rejects the worker if it lacks the capacity of any of them.
+	Resources map[string]int64
+	// Env is the environment variables of the worker.
+	Env map[string]string
+	// ResourceIDs are the external resources opened before the worker is
+	// initialized.
+	ResourceIDs []string
+	TenantID    string
+	ProjectID   string
 }
 
 type (
@@ -150,11 +162,16 @@ func (d *TaskDispatcher) preDispatchTaskOnce(
 	_, err := d.client.Send(ctx, &ExecutorRequest{
 		Cmd: CmdPreDispatchTask,
 		Req: &pb.PreDispatchTaskRequest{
-			TaskTypeId: args.WorkerType,
-			TaskConfig: args.WorkerConfig,
-			MasterId:   args.MasterID,
-			WorkerId:   args.WorkerID,
-			RequestId:  requestID,
+			TaskTypeId:  args.WorkerType,
+			TaskConfig:  args.WorkerConfig,
+			MasterId:    args.MasterID,
+			WorkerId:    args.WorkerID,
+			RequestId:   requestID,
+			Resources:   args.Resources,
+			Env:         args.Env,
+			ResourceIds: args.ResourceIDs,
+			TenantId:    args.TenantID,
+			ProjectId:   args.ProjectID,
 		},
 	})
 	if err != nil {
@@ -176,6 +193,15 @@ func (d *TaskDispatcher) preDispatchTaskOnce(
 		case codes.AlreadyExists:
 			// Since we are generating unique UUIDs, this should not happen.
 			log.L().Panic("Unexpected error", zap.Error(err))
+		case codes.ResourceExhausted:
+			// The dispatch queue of the executor is full, the task should be
+			// scheduled to another executor instead of retrying this one.
+			// Other ResourceExhausted errors, such as rate limiting, are retried.
+			if strings.Contains(st.Message(), string(derrors.ErrRuntimeIncomingQueueFull.RFCCode())) {
+				return "", false, derrors.ErrExecutorDispatchQueueFull.Wrap(err).GenWithStackByArgs()
+			}
+			log.L().Warn("PreDispatchTask encountered error, retrying", zap.Error(err))
+			return "", true, errors.Trace(err)
 		default:
 			log.L().Warn("PreDispatchTask encountered error, retrying", zap.Error(err))
 			return "", true, errors.Trace(err)
@@ -189,8 +215,7 @@ func (d *TaskDispatcher) confirmDispatchTask(
 	requestID string,
 	workerID string,
 ) (guaranteedFailure bool, retErr error) {
-	// The response is irrelevant because it is empty.
-	_, err := d.client.Send(ctx, &ExecutorRequest{
+	resp, err := d.client.Send(ctx, &ExecutorRequest{
 		Cmd: CmdConfirmDispatchTask,
 		Req: &pb.ConfirmDispatchTaskRequest{
 			WorkerId:  workerID,
@@ -214,5 +239,14 @@ func (d *TaskDispatcher) confirmDispatchTask(
 			return false, errors.Trace(err)
 		}
 	}
+	if resp == nil {
+		return false, nil
+	}
+	if confirmResp, ok := resp.Resp.(*pb.ConfirmDispatchTaskResponse); ok &&
+		confirmResp.GetState() == pb.DispatchState_DispatchQueued {
+		log.L().Info("worker is queued on the executor",
+			zap.String("worker-id", workerID),
+			zap.Int64("queue-position", confirmResp.GetQueuePosition()))
+	}
 	return false, nil
 }
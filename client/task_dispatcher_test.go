@@ -13,6 +13,7 @@ import (
 	"google.golang.org/grpc/codes"
 
 	"github.com/hanfei1991/microcosm/pb"
+	derrors "github.com/hanfei1991/microcosm/pkg/errors"
 )
 
 func TestDispatchTaskNormal(t *testing.T) {
@@ -26,6 +27,11 @@ func TestDispatchTaskNormal(t *testing.T) {
 		MasterID:     "master-1",
 		WorkerType:   1,
 		WorkerConfig: []byte("testtest"),
+		Resources:    map[string]int64{"cpu": 10, "gpu": 1},
+		Env:          map[string]string{"LOG_FORMAT": "json"},
+		ResourceIDs:  []string{"/local/resource-1"},
+		TenantID:     "tenant-1",
+		ProjectID:    "project-1",
 	}
 
 	var (
@@ -115,6 +121,34 @@ func TestAlreadyExistsPanics(t *testing.T) {
 	mockExecClient.AssertExpectations(t)
 }
 
+func TestDispatchQueueFull(t *testing.T) {
+	t.Parallel()
+
+	mockExecClient := &MockExecutorClient{}
+	dispatcher := newTaskDispatcher(mockExecClient)
+
+	args := &DispatchTaskArgs{
+		WorkerID:     "worker-1",
+		MasterID:     "master-1",
+		WorkerType:   1,
+		WorkerConfig: []byte("testtest"),
+	}
+	// the task is not retried on the executor whose dispatch queue is full
+	mockExecClient.On("Send", mock.Anything, mock.Anything).
+		Return((*ExecutorResponse)(nil), status.Error(codes.ResourceExhausted,
+			derrors.ErrRuntimeIncomingQueueFull.GenWithStackByArgs().Error())).
+		Once()
+
+	err := dispatcher.DispatchTask(context.Background(), args, func() {
+		require.Fail(t, "the callback should never be called")
+	}, func(error) {
+		require.Fail(t, "not expected")
+	})
+	require.Error(t, err)
+	require.Regexp(t, ".*ErrExecutorDispatchQueueFull.*", err)
+	mockExecClient.AssertExpectations(t)
+}
+
 func TestDispatchRetryCanceled(t *testing.T) {
 	t.Parallel()
 
@@ -282,4 +316,9 @@ func checkReqMatchesArgs(t *testing.T, req *pb.PreDispatchTaskRequest, args *Dis
 	require.Equal(t, args.MasterID, req.GetMasterId())
 	require.Equal(t, args.WorkerType, req.GetTaskTypeId())
 	require.Equal(t, args.WorkerConfig, req.GetTaskConfig())
+	require.Equal(t, args.Resources, req.GetResources())
+	require.Equal(t, args.Env, req.GetEnv())
+	require.Equal(t, args.ResourceIDs, req.GetResourceIds())
+	require.Equal(t, args.TenantID, req.GetTenantId())
+	require.Equal(t, args.ProjectID, req.GetProjectId())
 }
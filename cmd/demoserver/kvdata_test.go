@@ -102,6 +102,34 @@ func TestDemoLogic(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, true, rlResp.IsEof)
 
+	// Test Split File
+	splitResp, err := demoClt.SplitFile(ctx, &pb.SplitFileRequest{
+		FileIdx:  2,
+		SplitNum: 4,
+	})
+	require.Nil(t, err)
+	require.Empty(t, splitResp.ErrMsg)
+	require.Len(t, splitResp.SplitKeys, 3)
+	for i, key := range splitResp.SplitKeys {
+		require.Equal(t, strs[2][(i+1)*5], string(key))
+	}
+	readLineClt, err = demoClt.ReadLines(ctx, &pb.ReadLinesRequest{
+		FileIdx:   2,
+		LineNo:    splitResp.SplitKeys[0],
+		EndLineNo: splitResp.SplitKeys[1],
+	})
+	require.Nil(t, err)
+	for i := 5; i < 10; i++ {
+		rlResp, err := readLineClt.Recv()
+		require.Nil(t, err)
+		require.Empty(t, rlResp.ErrMsg)
+		require.Equal(t, false, rlResp.IsEof, i)
+		require.Equal(t, strs[2][i], string(rlResp.Key))
+	}
+	rlResp, err = readLineClt.Recv()
+	require.Nil(t, err)
+	require.Equal(t, true, rlResp.IsEof)
+
 	result, err := demoClt.CheckDir(ctx, &pb.CheckDirRequest{
 		Dir: demoDir,
 	})
@@ -295,6 +295,58 @@ func (s *DataRWServer) CheckDir(ctx context.Context, req *pb.CheckDirRequest) (*
 	return &pb.CheckDirResponse{}, nil
 }
 
+// SplitFile implements DataRWService.SplitFile
+func (s *DataRWServer) SplitFile(ctx context.Context, req *pb.SplitFileRequest) (*pb.SplitFileResponse, error) {
+	s.mu.Lock()
+	db, ok := s.dbMap[demoDir][int(req.FileIdx)]
+	s.mu.Unlock()
+	if !ok {
+		return &pb.SplitFileResponse{ErrMsg: fmt.Sprintf("file idx %d is out of range", req.FileIdx)}, nil
+	}
+	splitNum := int(req.SplitNum)
+	if splitNum <= 1 {
+		return &pb.SplitFileResponse{}, nil
+	}
+
+	// count the lines first, then pick the split keys evenly
+	countIter := db.Iterator([]byte{}, []byte{0xff})
+	lineNum := 0
+	for countIter.Seek([]byte{}); countIter.Valid(); countIter.Next() {
+		lineNum++
+	}
+	err := countIter.Error()
+	if releaseErr := countIter.Release(); err == nil {
+		err = releaseErr
+	}
+	if err != nil {
+		return &pb.SplitFileResponse{ErrMsg: err.Error()}, nil
+	}
+	if splitNum > lineNum {
+		splitNum = lineNum
+	}
+
+	resp := &pb.SplitFileResponse{}
+	iter := db.Iterator([]byte{}, []byte{0xff})
+	lineNo, rangeNo := 0, 1
+	for iter.Seek([]byte{}); iter.Valid() && rangeNo < splitNum; iter.Next() {
+		if lineNo == lineNum*rangeNo/splitNum {
+			// the key is reused by the iterator
+			resp.SplitKeys = append(resp.SplitKeys, append([]byte(nil), iter.Key()...))
+			rangeNo++
+		}
+		lineNo++
+	}
+	err = iter.Error()
+	if releaseErr := iter.Release(); err == nil {
+		err = releaseErr
+	}
+	if err != nil {
+		return &pb.SplitFileResponse{ErrMsg: err.Error()}, nil
+	}
+	log.L().Info("split file", zap.Any("idx", req.FileIdx), zap.Int("lines", lineNum), zap.Int("ranges", len(resp.SplitKeys)+1))
+	return resp, nil
+}
+
 // ReadLines implements DataRWService.ReadLines
 func (s *DataRWServer) ReadLines(req *pb.ReadLinesRequest, stream pb.DataRWService_ReadLinesServer) error {
 	log.L().Info("receive the request for reading file ", zap.Any("idx", req.FileIdx), zap.String("lineNo", string(req.LineNo)))
@@ -304,7 +356,11 @@ func (s *DataRWServer) ReadLines(req *pb.ReadLinesRequest, stream pb.DataRWServi
 	if !ok {
 		return stream.Send(&pb.ReadLinesResponse{ErrMsg: fmt.Sprintf("file idx %d is out of range %d", req.FileIdx, len(s.dbMap[demoAddress])), IsEof: true})
 	}
-	iter := db.Iterator([]byte{}, []byte{0xff})
+	upperBound := []byte{0xff}
+	if len(req.EndLineNo) > 0 {
+		upperBound = req.EndLineNo
+	}
+	iter := db.Iterator([]byte{}, upperBound)
 	if !iter.Seek(req.LineNo) {
 		return stream.Send(&pb.ReadLinesResponse{ErrMsg: "Cannot find key " + string(req.LineNo)})
 	}
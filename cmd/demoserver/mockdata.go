@@ -94,6 +94,17 @@ func (s *dataRWServiceMock) GenerateData(ctx context.Context, req *pb.GenerateDa
 	return &pb.GenerateDataResponse{}, nil
 }
 
+// SplitFile implements DataRWService.SplitFile
+func (s *dataRWServiceMock) SplitFile(ctx context.Context, req *pb.SplitFileRequest) (*pb.SplitFileResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.dbMap[demoDir][int(req.FileIdx)]; !ok {
+		return &pb.SplitFileResponse{ErrMsg: fmt.Sprintf("file idx %d is out of range", req.FileIdx)}, nil
+	}
+	// the lines of a mock file must be written in order, so it isn't split
+	return &pb.SplitFileResponse{}, nil
+}
+
 // ReadLines implements DataRWService.ReadLines
 func (s *dataRWServiceMock) ReadLines(req *pb.ReadLinesRequest, stream pb.DataRWService_ReadLinesServer) error {
 	log.L().Info("receive the request for reading file ", zap.Any("idx", req.FileIdx), zap.String("lineNo", string(req.LineNo)))
@@ -117,9 +128,17 @@ func (s *dataRWServiceMock) ReadLines(req *pb.ReadLinesRequest, stream pb.DataRW
 	if !ok {
 		return stream.Send(&pb.ReadLinesResponse{ErrMsg: "Cannot find key " + string(req.LineNo)})
 	}
+	end := -1
+	if len(req.EndLineNo) > 0 {
+		var err error
+		end, err = strconv.Atoi(string(req.EndLineNo))
+		if err != nil {
+			return stream.Send(&pb.ReadLinesResponse{ErrMsg: "Cannot find key " + string(req.EndLineNo)})
+		}
+	}
 	for {
 		v, ok := iter.get()
-		if !ok {
+		if !ok || (end >= 0 && v >= end) {
 			return stream.Send(&pb.ReadLinesResponse{IsEof: true})
 		}
 		vByte := strconv.Itoa(v)
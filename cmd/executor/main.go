@@ -9,6 +9,7 @@ import (
 	"syscall"
 
 	"github.com/hanfei1991/microcosm/executor"
+	"github.com/hanfei1991/microcosm/executor/subprocess"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tiflow/dm/pkg/log"
 	"go.uber.org/zap"
@@ -19,6 +20,11 @@ import (
 // 3. register singal handler
 // 4. start server
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == subprocess.WorkerProcessArg {
+		runWorkerProcess(os.Args[2:])
+		return
+	}
+
 	// 1. parse config
 	cfg := executor.NewConfig()
 	err := cfg.Parse(os.Args[1:])
@@ -40,6 +46,8 @@ func main() {
 		os.Exit(2)
 	}
 
+	server := executor.NewServer(cfg, nil)
+
 	// 3. register signal handler
 	ctx, cancel := context.WithCancel(context.Background())
 	sc := make(chan os.Signal, 1)
@@ -49,20 +57,25 @@ func main() {
 		syscall.SIGTERM,
 		syscall.SIGQUIT)
 	go func() {
-		select {
-		case <-ctx.Done():
-		case sig := <-sc:
-			log.L().Info("got signal to exit", zap.Stringer("signal", sig))
-			cancel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case sig := <-sc:
+				log.L().Info("got signal to exit", zap.Stringer("signal", sig))
+				if sig == syscall.SIGTERM {
+					// drain running workers before exiting, other signals
+					// still stop the executor immediately.
+					server.GracefulShutdown()
+					continue
+				}
+				cancel()
+				return
+			}
 		}
 	}()
 
 	// 4. run executor server
-	server := executor.NewServer(cfg, nil)
-	if err != nil {
-		log.L().Error("fail to start executor", zap.Error(err))
-		os.Exit(2)
-	}
 	err = server.Run(ctx)
 	if err != nil && errors.Cause(err) != context.Canceled {
 		log.L().Error("run executor with error", zap.Error(err))
@@ -71,3 +84,9 @@ func main() {
 	server.Stop()
 	log.L().Info("executor server exits normally")
 }
+
+// runWorkerProcess runs a single worker, which is started by an executor
+// for a worker type configured in `isolated-worker-types`.
+func runWorkerProcess(args []string) {
+	os.Exit(subprocess.RunWorkerProcessMain(args))
+}
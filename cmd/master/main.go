@@ -14,6 +14,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/hanfei1991/microcosm/servermaster"
+	"github.com/hanfei1991/microcosm/standalone"
 )
 
 // 1. parse config
@@ -44,6 +45,11 @@ func main() {
 	}
 
 	// 3. start server
+	if cfg.Standalone {
+		runStandalone(cfg)
+		return
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	server, err := servermaster.NewServer(cfg, nil)
 	if err != nil {
@@ -73,3 +79,43 @@ func main() {
 	}
 	log.L().Info("server exits normally")
 }
+
+// runStandalone runs server master and an executor in this process.
+func runStandalone(cfg *servermaster.Config) {
+	cluster, err := standalone.NewCluster(cfg)
+	if err != nil {
+		log.L().Error("fail to start standalone cluster", zap.Error(err))
+		os.Exit(2)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sc := make(chan os.Signal, 1)
+	signal.Notify(sc,
+		syscall.SIGHUP,
+		syscall.SIGINT,
+		syscall.SIGTERM,
+		syscall.SIGQUIT)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case sig := <-sc:
+				log.L().Info("got signal to exit", zap.Stringer("signal", sig))
+				if sig == syscall.SIGTERM {
+					cluster.GracefulShutdown()
+					continue
+				}
+				cancel()
+				return
+			}
+		}
+	}()
+
+	err = cluster.Run(ctx)
+	if err != nil && errors.Cause(err) != context.Canceled {
+		log.L().Error("run standalone cluster with error", zap.Error(err))
+		os.Exit(2)
+	}
+	log.L().Info("standalone cluster exits normally")
+}
@@ -0,0 +1,131 @@
+package ctl
+
+import (
+	"context"
+	"os"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+	"github.com/hanfei1991/microcosm/pkg/orm/backup"
+)
+
+// annotationOffline marks the commands which work on the metastore directly
+// and don't need to dial the master.
+const annotationOffline = "offline"
+
+func defineMetaStoreFlags(cmd *cobra.Command) {
+	cmd.Flags().String("meta-store-type", metaclient.StoreTypeMySQL, "backend type of the framework metastore, mysql or sqlite")
+	cmd.Flags().String("meta-endpoints", pkgOrm.DefaultFrameMetaEndpoints, "endpoints of the framework metastore, or the file path of a sqlite metastore")
+	cmd.Flags().String("meta-user", pkgOrm.DefaultFrameMetaUser, "user of the framework metastore")
+	cmd.Flags().String("meta-password", pkgOrm.DefaultFrameMetaPassword, "password of the framework metastore")
+	cmd.Flags().String("file", "", "path of the snapshot file")
+}
+
+func newMetaClientFromFlags(cmd *cobra.Command) (pkgOrm.Client, error) {
+	flags := cmd.Flags()
+	storeConf := metaclient.StoreConfigParams{StoreID: metaclient.FrameMetaID}
+	var err error
+	if storeConf.StoreType, err = flags.GetString("meta-store-type"); err != nil {
+		return nil, err
+	}
+	endpoints, err := flags.GetString("meta-endpoints")
+	if err != nil {
+		return nil, err
+	}
+	storeConf.SetEndpoints(endpoints)
+	if storeConf.Auth.User, err = flags.GetString("meta-user"); err != nil {
+		return nil, err
+	}
+	if storeConf.Auth.Passwd, err = flags.GetString("meta-password"); err != nil {
+		return nil, err
+	}
+	return pkgOrm.NewClient(storeConf, pkgOrm.NewDefaultDBConfig())
+}
+
+func newExportMetadata() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:         "export-metadata",
+		Short:       "export the framework metastore to a snapshot file",
+		RunE:        runExportMetadata,
+		Annotations: map[string]string{annotationOffline: "true"},
+	}
+	defineMetaStoreFlags(cmd)
+	return cmd
+}
+
+func runExportMetadata(cmd *cobra.Command, _ []string) error {
+	path, err := cmd.Flags().GetString("file")
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		return errors.New("file should not be empty")
+	}
+	cli, err := newMetaClientFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer f.Close()
+	snap, err := backup.Export(context.Background(), cli, f)
+	if err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		return errors.Trace(err)
+	}
+	log.L().Info("metadata exported", zap.String("file", path),
+		zap.Int64("epoch", snap.Epoch),
+		zap.Int("jobs", len(snap.Jobs)),
+		zap.Int("workers", len(snap.Workers)),
+		zap.Int("resources", len(snap.Resources)))
+	return nil
+}
+
+func newImportMetadata() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:         "import-metadata",
+		Short:       "import a snapshot file into a fresh framework metastore, no server master should be running on it",
+		RunE:        runImportMetadata,
+		Annotations: map[string]string{annotationOffline: "true"},
+	}
+	defineMetaStoreFlags(cmd)
+	return cmd
+}
+
+func runImportMetadata(cmd *cobra.Command, _ []string) error {
+	path, err := cmd.Flags().GetString("file")
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		return errors.New("file should not be empty")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer f.Close()
+	cli, err := newMetaClientFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+	if err := cli.Initialize(ctx); err != nil {
+		return err
+	}
+	_, err = backup.Import(ctx, cli, f)
+	return err
+}
@@ -0,0 +1,42 @@
+package ctl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/hanfei1991/microcosm/pb"
+)
+
+func newClusterHealth() *cobra.Command {
+	return &cobra.Command{
+		Use:   "cluster-health",
+		Short: "show the health summary of the cluster",
+		RunE:  runClusterHealth,
+	}
+}
+
+func runClusterHealth(cmd *cobra.Command, _ []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	resp, err := cltManager.MasterClient().ClusterHealth(ctx, &pb.ClusterHealthRequest{})
+	if err != nil {
+		return err
+	}
+	if resp.Err != nil {
+		return fmt.Errorf("%s", resp.Err.Message)
+	}
+	log.L().Info("cluster health",
+		zap.Bool("healthy", resp.Healthy),
+		zap.String("leader", resp.Leader),
+		zap.Bool("metastore-reachable", resp.MetastoreReachable),
+		zap.Int32("executors", resp.Executors),
+		zap.Strings("stale-executors", resp.StaleExecutors),
+		zap.Strings("error-jobs", resp.ErrorJobs),
+		zap.Strings("problems", resp.Problems))
+	return nil
+}
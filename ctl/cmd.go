@@ -7,15 +7,21 @@ import (
 	"io/ioutil"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/pingcap/tiflow/dm/pkg/log"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 
 	"github.com/hanfei1991/microcosm/lib"
+	"github.com/hanfei1991/microcosm/lib/fake/loadgen"
 	libModel "github.com/hanfei1991/microcosm/lib/model"
 	"github.com/hanfei1991/microcosm/pb"
 	"github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/meta/encryption"
+	"github.com/hanfei1991/microcosm/pkg/meta/kvclient"
+	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
+	"github.com/hanfei1991/microcosm/pkg/tenant"
 )
 
 func newQueryJob() *cobra.Command {
@@ -70,6 +76,143 @@ func runQueryJob(cmd *cobra.Command, _ []string) error {
 	return nil
 }
 
+func newQueryJobs() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "query-jobs",
+		Short: "list jobs matching a label selector",
+		RunE:  runQueryJobs,
+	}
+	cmd.Flags().String("project-id", "", "the targeted project id, empty means all projects")
+	cmd.Flags().String("selector", "", "label selector such as team=payment,env!=prod,tier,!canary, empty means all jobs")
+	cmd.Flags().StringSlice("status", nil, "only list the jobs in these status, "+
+		"which are uninit, init, finished and stopped")
+	cmd.Flags().Int64Slice("type", nil, "only list the jobs of these types, which are the tp of the listed jobs")
+	return cmd
+}
+
+var jobStatusCodes = map[string]libModel.MasterStatusCode{
+	"uninit":   libModel.MasterStatusUninit,
+	"init":     libModel.MasterStatusInit,
+	"finished": libModel.MasterStatusFinished,
+	"stopped":  libModel.MasterStatusStopped,
+}
+
+func runQueryJobs(cmd *cobra.Command, _ []string) error {
+	projectID, err := cmd.Flags().GetString("project-id")
+	if err != nil {
+		return err
+	}
+	selector, err := cmd.Flags().GetString("selector")
+	if err != nil {
+		return err
+	}
+	statuses, err := cmd.Flags().GetStringSlice("status")
+	if err != nil {
+		return err
+	}
+	types, err := cmd.Flags().GetInt64Slice("type")
+	if err != nil {
+		return err
+	}
+
+	req := &pb.QueryJobsRequest{
+		ProjectId:     projectID,
+		LabelSelector: selector,
+		Types:         types,
+	}
+	for _, status := range statuses {
+		code, ok := jobStatusCodes[status]
+		if !ok {
+			return fmt.Errorf("unknown job status %s", status)
+		}
+		req.StatusCodes = append(req.StatusCodes, int32(code))
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+	resp, err := cltManager.MasterClient().QueryJobs(ctx, req)
+	if err != nil {
+		log.L().Error("failed to query jobs", zap.Error(err))
+		os.Exit(1)
+	}
+	log.L().Info("query jobs result", zap.String("resp", resp.String()))
+	return nil
+}
+
+var workerStatusCodes = map[string]libModel.WorkerStatusCode{
+	"normal":   libModel.WorkerStatusNormal,
+	"created":  libModel.WorkerStatusCreated,
+	"init":     libModel.WorkerStatusInit,
+	"error":    libModel.WorkerStatusError,
+	"finished": libModel.WorkerStatusFinished,
+	"stopped":  libModel.WorkerStatusStopped,
+}
+
+func newListWorkers() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list-workers",
+		Short: "list the workers of a job",
+		RunE:  runListWorkers,
+	}
+	cmd.Flags().String("job-id", "", "the targeted job id")
+	cmd.Flags().StringSlice("status", nil, "only list the workers in these status, "+
+		"which are normal, created, init, error, finished and stopped")
+	cmd.Flags().String("executor-id", "", "only list the workers running on the executor")
+	cmd.Flags().Int32("page-size", 0, "the max number of workers to list, 0 means the default size")
+	cmd.Flags().String("page-token", "", "the next page token returned by the previous listing")
+	return cmd
+}
+
+func runListWorkers(cmd *cobra.Command, _ []string) error {
+	flags := cmd.Flags()
+	jobID, err := flags.GetString("job-id")
+	if err != nil {
+		return err
+	}
+	if jobID == "" {
+		return fmt.Errorf("job-id should not be empty")
+	}
+	statuses, err := flags.GetStringSlice("status")
+	if err != nil {
+		return err
+	}
+	executorID, err := flags.GetString("executor-id")
+	if err != nil {
+		return err
+	}
+	pageSize, err := flags.GetInt32("page-size")
+	if err != nil {
+		return err
+	}
+	pageToken, err := flags.GetString("page-token")
+	if err != nil {
+		return err
+	}
+
+	req := &pb.ListWorkersRequest{
+		JobId:      jobID,
+		ExecutorId: executorID,
+		PageSize:   pageSize,
+		PageToken:  pageToken,
+	}
+	for _, status := range statuses {
+		code, ok := workerStatusCodes[status]
+		if !ok {
+			return fmt.Errorf("unknown worker status %s", status)
+		}
+		req.StatusCodes = append(req.StatusCodes, int32(code))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+	resp, err := cltManager.MasterClient().ListWorkers(ctx, req)
+	if err != nil {
+		log.L().Error("failed to list workers", zap.Error(err))
+		os.Exit(1)
+	}
+	log.L().Info("list workers result", zap.String("resp", resp.String()))
+	return nil
+}
+
 func newSubmitJob() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "submit-job",
@@ -80,6 +223,10 @@ func newSubmitJob() *cobra.Command {
 	cmd.Flags().String("executor-id", "", "the targeted executor id")
 	cmd.Flags().String("job-type", "", "job type")
 	cmd.Flags().String("job-config", "", "config file for the demo job")
+	cmd.Flags().String("template-id", "", "submit the job from a registered template, job-type and job-config are ignored")
+	cmd.Flags().StringToString("param", nil, "parameter values of the template, such as --param table=orders")
+	cmd.Flags().Int32("max-create-worker-concurrency", 0, "max number of workers created by the job at the same time, 0 means the default limit")
+	cmd.Flags().StringToString("label", nil, "labels of the job, such as --label team=payment,env=prod")
 	return cmd
 }
 
@@ -102,6 +249,21 @@ func validJobType(job string) (pb.JobType, error) {
 }
 
 func runSubmitJob(cmd *cobra.Command, _ []string) error {
+	templateID, err := cmd.Flags().GetString("template-id")
+	if err != nil {
+		return err
+	}
+	concurrency, err := cmd.Flags().GetInt32("max-create-worker-concurrency")
+	if err != nil {
+		return err
+	}
+	labels, err := cmd.Flags().GetStringToString("label")
+	if err != nil {
+		return err
+	}
+	if templateID != "" {
+		return runSubmitJobFromTemplate(cmd, templateID, concurrency, labels)
+	}
 	tp, err := cmd.Flags().GetString("job-type")
 	if err != nil {
 		fmt.Print("error in parse `--job-type`")
@@ -128,6 +290,9 @@ func runSubmitJob(cmd *cobra.Command, _ []string) error {
 		Tp:     jobType,
 		Config: jobConfig,
 		User:   "hanfei",
+		Labels: labels,
+
+		MaxCreateWorkerConcurrency: concurrency,
 	})
 	if err != nil {
 		log.L().Error("failed to submit job", zap.Error(err))
@@ -137,6 +302,259 @@ func runSubmitJob(cmd *cobra.Command, _ []string) error {
 	return nil
 }
 
+func runSubmitJobFromTemplate(
+	cmd *cobra.Command, templateID string, concurrency int32, labels map[string]string,
+) error {
+	params, err := cmd.Flags().GetStringToString("param")
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+
+	resp, err := cltManager.MasterClient().SubmitJob(ctx, &pb.SubmitJobRequest{
+		User:           "hanfei",
+		TemplateId:     templateID,
+		TemplateParams: params,
+		Labels:         labels,
+
+		MaxCreateWorkerConcurrency: concurrency,
+	})
+	if err != nil {
+		log.L().Error("failed to submit job", zap.Error(err))
+		os.Exit(1)
+	}
+	log.L().Info("resp", zap.Any("resp", resp))
+	return nil
+}
+
+func newRegisterJobTemplate() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "register-job-template",
+		Short: "register a job config template with placeholders such as ${name} or ${name:-default}",
+		RunE:  runRegisterJobTemplate,
+	}
+	cmd.Flags().String("template-id", "", "the template id, a template of the same id is replaced")
+	cmd.Flags().String("job-type", "", "job type")
+	cmd.Flags().String("template", "", "template file of the job config")
+	return cmd
+}
+
+func runRegisterJobTemplate(cmd *cobra.Command, _ []string) error {
+	flags := cmd.Flags()
+	id, err := flags.GetString("template-id")
+	if err != nil {
+		return err
+	}
+	if id == "" {
+		return fmt.Errorf("template-id should not be empty")
+	}
+	tp, err := flags.GetString("job-type")
+	if err != nil {
+		return err
+	}
+	jobType, err := validJobType(tp)
+	if err != nil {
+		return err
+	}
+	path, err := flags.GetString("template")
+	if err != nil {
+		return err
+	}
+	content, err := openFileAndReadString(path)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+	resp, err := cltManager.MasterClient().RegisterJobTemplate(ctx, &pb.RegisterJobTemplateRequest{
+		Template: &pb.JobTemplate{
+			TemplateId: id,
+			Tp:         jobType,
+			Template:   content,
+		},
+	})
+	if err != nil {
+		log.L().Error("failed to register job template", zap.Error(err))
+		os.Exit(1)
+	}
+	log.L().Info("register job template result", zap.String("err", resp.Err.String()))
+	return nil
+}
+
+func newQueryJobTemplates() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "query-job-templates",
+		Short: "query job templates and their parameters",
+		RunE:  runQueryJobTemplates,
+	}
+	cmd.Flags().String("template-id", "", "the targeted template id, empty means all templates")
+	return cmd
+}
+
+func runQueryJobTemplates(cmd *cobra.Command, _ []string) error {
+	id, err := cmd.Flags().GetString("template-id")
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+	resp, err := cltManager.MasterClient().QueryJobTemplates(ctx, &pb.QueryJobTemplatesRequest{
+		TemplateId: id,
+	})
+	if err != nil {
+		log.L().Error("failed to query job templates", zap.Error(err))
+		os.Exit(1)
+	}
+	log.L().Info("query job templates result", zap.String("resp", resp.String()))
+	return nil
+}
+
+func newDeleteJobTemplate() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete-job-template",
+		Short: "delete a job template, the jobs submitted from it are not affected",
+		RunE:  runDeleteJobTemplate,
+	}
+	cmd.Flags().String("template-id", "", "the targeted template id")
+	return cmd
+}
+
+func runDeleteJobTemplate(cmd *cobra.Command, _ []string) error {
+	id, err := cmd.Flags().GetString("template-id")
+	if err != nil {
+		return err
+	}
+	if id == "" {
+		return fmt.Errorf("template-id should not be empty")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+	resp, err := cltManager.MasterClient().DeleteJobTemplate(ctx, &pb.DeleteJobTemplateRequest{
+		TemplateId: id,
+	})
+	if err != nil {
+		log.L().Error("failed to delete job template", zap.Error(err))
+		os.Exit(1)
+	}
+	log.L().Info("delete job template result", zap.String("err", resp.Err.String()))
+	return nil
+}
+
+func newPutSecret() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "put-secret",
+		Short: "store a secret of a project, which is referenced as secret://<name> in the env of workers",
+		RunE:  runPutSecret,
+	}
+	cmd.Flags().String("project-id", "", "the project of the secret")
+	cmd.Flags().String("name", "", "the name of the secret")
+	cmd.Flags().String("value-file", "", "the file holding the value of the secret, "+
+		"so that the value doesn't appear in the command line")
+	return cmd
+}
+
+func runPutSecret(cmd *cobra.Command, _ []string) error {
+	flags := cmd.Flags()
+	projectID, err := flags.GetString("project-id")
+	if err != nil {
+		return err
+	}
+	name, err := flags.GetString("name")
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		return fmt.Errorf("name should not be empty")
+	}
+	path, err := flags.GetString("value-file")
+	if err != nil {
+		return err
+	}
+	value, err := openFileAndReadString(path)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+	resp, err := cltManager.MasterClient().PutSecret(ctx, &pb.PutSecretRequest{
+		ProjectId: projectID,
+		Name:      name,
+		Value:     string(value),
+	})
+	if err != nil {
+		log.L().Error("failed to put secret", zap.Error(err))
+		os.Exit(1)
+	}
+	log.L().Info("put secret result", zap.String("err", resp.Err.String()))
+	return nil
+}
+
+func newListSecrets() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list-secrets",
+		Short: "list the names of the secrets of a project",
+		RunE:  runListSecrets,
+	}
+	cmd.Flags().String("project-id", "", "the targeted project id")
+	return cmd
+}
+
+func runListSecrets(cmd *cobra.Command, _ []string) error {
+	projectID, err := cmd.Flags().GetString("project-id")
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+	resp, err := cltManager.MasterClient().ListSecrets(ctx, &pb.ListSecretsRequest{
+		ProjectId: projectID,
+	})
+	if err != nil {
+		log.L().Error("failed to list secrets", zap.Error(err))
+		os.Exit(1)
+	}
+	log.L().Info("list secrets result", zap.String("resp", resp.String()))
+	return nil
+}
+
+func newDeleteSecret() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete-secret",
+		Short: "delete a secret of a project, the running workers keep the value they are given",
+		RunE:  runDeleteSecret,
+	}
+	cmd.Flags().String("project-id", "", "the project of the secret")
+	cmd.Flags().String("name", "", "the name of the secret")
+	return cmd
+}
+
+func runDeleteSecret(cmd *cobra.Command, _ []string) error {
+	projectID, err := cmd.Flags().GetString("project-id")
+	if err != nil {
+		return err
+	}
+	name, err := cmd.Flags().GetString("name")
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		return fmt.Errorf("name should not be empty")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+	resp, err := cltManager.MasterClient().DeleteSecret(ctx, &pb.DeleteSecretRequest{
+		ProjectId: projectID,
+		Name:      name,
+	})
+	if err != nil {
+		log.L().Error("failed to delete secret", zap.Error(err))
+		os.Exit(1)
+	}
+	log.L().Info("delete secret result", zap.String("err", resp.Err.String()))
+	return nil
+}
+
 func newPauseJob() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "pause-job",
@@ -169,3 +587,406 @@ func runPauseJob(cmd *cobra.Command, _ []string) error {
 	log.L().Info("pause result", zap.String("err", resp.Err.String()))
 	return nil
 }
+
+func newUpdateJobTimeouts() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update-job-timeouts",
+		Short: "update the worker timeouts of a running job",
+		RunE:  runUpdateJobTimeouts,
+	}
+	cmd.Flags().String("job-id", "", "the targeted job id")
+	cmd.Flags().Duration("worker-timeout", 0, "worker timeout, 0 means unchanged")
+	cmd.Flags().Duration("worker-timeout-graceful", 0, "worker timeout graceful duration, 0 means unchanged")
+	cmd.Flags().Duration("heartbeat-interval", 0, "worker heartbeat interval, 0 means unchanged")
+	return cmd
+}
+
+func runUpdateJobTimeouts(cmd *cobra.Command, _ []string) error {
+	flags := cmd.Flags()
+	id, err := flags.GetString("job-id")
+	if err != nil {
+		log.L().Error("error in parse `--job-id`")
+		return err
+	}
+	if id == "" {
+		return fmt.Errorf("job-id should not be empty")
+	}
+	workerTimeout, err := flags.GetDuration("worker-timeout")
+	if err != nil {
+		return err
+	}
+	gracefulDuration, err := flags.GetDuration("worker-timeout-graceful")
+	if err != nil {
+		return err
+	}
+	heartbeatInterval, err := flags.GetDuration("heartbeat-interval")
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+	resp, err := cltManager.MasterClient().UpdateJobTimeouts(ctx, &pb.UpdateJobTimeoutsRequest{
+		JobId:                     id,
+		WorkerTimeoutMs:           workerTimeout.Milliseconds(),
+		WorkerTimeoutGracefulMs:   gracefulDuration.Milliseconds(),
+		WorkerHeartbeatIntervalMs: heartbeatInterval.Milliseconds(),
+	})
+	if err != nil {
+		log.L().Error("failed to update job timeouts", zap.Error(err))
+		os.Exit(1)
+	}
+	log.L().Info("update job timeouts result", zap.String("err", resp.Err.String()))
+	return nil
+}
+
+func newSetJobLogLevel() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-job-log-level",
+		Short: "set the log level of the master and workers of a running job",
+		RunE:  runSetJobLogLevel,
+	}
+	cmd.Flags().String("job-id", "", "the targeted job id")
+	cmd.Flags().String("level", "", "log level: debug, info, warn, error, empty means the global log level")
+	return cmd
+}
+
+func runSetJobLogLevel(cmd *cobra.Command, _ []string) error {
+	flags := cmd.Flags()
+	id, err := flags.GetString("job-id")
+	if err != nil {
+		log.L().Error("error in parse `--job-id`")
+		return err
+	}
+	if id == "" {
+		return fmt.Errorf("job-id should not be empty")
+	}
+	level, err := flags.GetString("level")
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+	resp, err := cltManager.MasterClient().SetJobLogLevel(ctx, &pb.SetJobLogLevelRequest{
+		JobId: id,
+		Level: level,
+	})
+	if err != nil {
+		log.L().Error("failed to set job log level", zap.Error(err))
+		os.Exit(1)
+	}
+	log.L().Info("set job log level result", zap.String("err", resp.Err.String()))
+	return nil
+}
+
+var taskOps = map[string]pb.JobTaskOp{
+	"pause":  pb.JobTaskOp_TaskOpPause,
+	"resume": pb.JobTaskOp_TaskOpResume,
+	"stop":   pb.JobTaskOp_TaskOpStop,
+}
+
+func newOperateJobTasks() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "operate-job-tasks",
+		Short: "pause, resume or stop the tasks of a running job",
+		RunE:  runOperateJobTasks,
+	}
+	cmd.Flags().String("job-id", "", "the targeted job id")
+	cmd.Flags().String("op", "", "operation: pause, resume or stop")
+	cmd.Flags().StringSlice("tasks", nil, "the IDs of the tasks to operate, empty means all the tasks")
+	return cmd
+}
+
+func runOperateJobTasks(cmd *cobra.Command, _ []string) error {
+	flags := cmd.Flags()
+	id, err := flags.GetString("job-id")
+	if err != nil {
+		log.L().Error("error in parse `--job-id`")
+		return err
+	}
+	if id == "" {
+		return fmt.Errorf("job-id should not be empty")
+	}
+	opStr, err := flags.GetString("op")
+	if err != nil {
+		return err
+	}
+	op, ok := taskOps[opStr]
+	if !ok {
+		return fmt.Errorf("unknown operation %q", opStr)
+	}
+	tasks, err := flags.GetStringSlice("tasks")
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+	resp, err := cltManager.MasterClient().OperateJobTasks(ctx, &pb.OperateJobTasksRequest{
+		JobId: id,
+		Op:    op,
+		Tasks: tasks,
+	})
+	if err != nil {
+		log.L().Error("failed to operate job tasks", zap.Error(err))
+		os.Exit(1)
+	}
+	log.L().Info("operate job tasks result", zap.String("err", resp.Err.String()))
+	return nil
+}
+
+func newUpdateJobSource() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update-job-source",
+		Short: "update the config of an upstream source of a running job",
+		RunE:  runUpdateJobSource,
+	}
+	cmd.Flags().String("job-id", "", "the targeted job id")
+	cmd.Flags().String("source-id", "", "the id of the upstream source")
+	cmd.Flags().String("source-config", "", "config file of the upstream source")
+	return cmd
+}
+
+func runUpdateJobSource(cmd *cobra.Command, _ []string) error {
+	flags := cmd.Flags()
+	id, err := flags.GetString("job-id")
+	if err != nil {
+		log.L().Error("error in parse `--job-id`")
+		return err
+	}
+	if id == "" {
+		return fmt.Errorf("job-id should not be empty")
+	}
+	sourceID, err := flags.GetString("source-id")
+	if err != nil {
+		return err
+	}
+	if sourceID == "" {
+		return fmt.Errorf("source-id should not be empty")
+	}
+	path, err := flags.GetString("source-config")
+	if err != nil {
+		return err
+	}
+	content, err := openFileAndReadString(path)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+	resp, err := cltManager.MasterClient().UpdateJobSource(ctx, &pb.UpdateJobSourceRequest{
+		JobId:    id,
+		SourceId: sourceID,
+		Config:   content,
+	})
+	if err != nil {
+		log.L().Error("failed to update job source", zap.Error(err))
+		os.Exit(1)
+	}
+	log.L().Info("update job source result", zap.String("err", resp.Err.String()))
+	return nil
+}
+
+func newCreateJobSchedule() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create-job-schedule",
+		Short: "create a schedule that submits a job periodically",
+		RunE:  runCreateJobSchedule,
+	}
+	cmd.Flags().String("job-type", "", "job type")
+	cmd.Flags().String("job-config", "", "config file for the job")
+	cmd.Flags().String("cron", "", "cron expression of five fields in UTC, such as \"0 * * * *\"")
+	cmd.Flags().String("catch-up", pb.CatchUpPolicy_CatchUpSkip.String(),
+		"how the fires missed during a failover are handled, CatchUpSkip, CatchUpOnce or CatchUpAll")
+	return cmd
+}
+
+func runCreateJobSchedule(cmd *cobra.Command, _ []string) error {
+	flags := cmd.Flags()
+	tp, err := flags.GetString("job-type")
+	if err != nil {
+		return err
+	}
+	jobType, err := validJobType(tp)
+	if err != nil {
+		return err
+	}
+	path, err := flags.GetString("job-config")
+	if err != nil {
+		return err
+	}
+	jobConfig, err := openFileAndReadString(path)
+	if err != nil {
+		return err
+	}
+	cronExpr, err := flags.GetString("cron")
+	if err != nil {
+		return err
+	}
+	catchUpStr, err := flags.GetString("catch-up")
+	if err != nil {
+		return err
+	}
+	catchUp, ok := pb.CatchUpPolicy_value[catchUpStr]
+	if !ok {
+		return fmt.Errorf("unknown catch-up policy %s", catchUpStr)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+	resp, err := cltManager.MasterClient().CreateJobSchedule(ctx, &pb.CreateJobScheduleRequest{
+		Schedule: &pb.JobSchedule{
+			Tp:      jobType,
+			Config:  jobConfig,
+			Cron:    cronExpr,
+			CatchUp: pb.CatchUpPolicy(catchUp),
+		},
+	})
+	if err != nil {
+		log.L().Error("failed to create job schedule", zap.Error(err))
+		os.Exit(1)
+	}
+	log.L().Info("create job schedule result", zap.Any("resp", resp))
+	return nil
+}
+
+func newQueryJobSchedules() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "query-job-schedules",
+		Short: "query job schedules",
+		RunE:  runQueryJobSchedules,
+	}
+	cmd.Flags().String("schedule-id", "", "the targeted schedule id, empty means all schedules")
+	return cmd
+}
+
+func runQueryJobSchedules(cmd *cobra.Command, _ []string) error {
+	id, err := cmd.Flags().GetString("schedule-id")
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+	resp, err := cltManager.MasterClient().QueryJobSchedules(ctx, &pb.QueryJobSchedulesRequest{
+		ScheduleId: id,
+	})
+	if err != nil {
+		log.L().Error("failed to query job schedules", zap.Error(err))
+		os.Exit(1)
+	}
+	log.L().Info("query job schedules result", zap.String("resp", resp.String()))
+	return nil
+}
+
+func newDeleteJobSchedule() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete-job-schedule",
+		Short: "delete a job schedule, the jobs submitted by it are not affected",
+		RunE:  runDeleteJobSchedule,
+	}
+	cmd.Flags().String("schedule-id", "", "the targeted schedule id")
+	return cmd
+}
+
+func runDeleteJobSchedule(cmd *cobra.Command, _ []string) error {
+	id, err := cmd.Flags().GetString("schedule-id")
+	if err != nil {
+		return err
+	}
+	if id == "" {
+		return fmt.Errorf("schedule-id should not be empty")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+	resp, err := cltManager.MasterClient().DeleteJobSchedule(ctx, &pb.DeleteJobScheduleRequest{
+		ScheduleId: id,
+	})
+	if err != nil {
+		log.L().Error("failed to delete job schedule", zap.Error(err))
+		os.Exit(1)
+	}
+	log.L().Info("delete job schedule result", zap.String("err", resp.Err.String()))
+	return nil
+}
+
+func newLoadTest() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "load-test",
+		Short: "launch fake jobs to generate load, and report the statistics",
+		RunE:  runLoadTest,
+	}
+	cmd.Flags().Int("job-count", 1, "number of fake jobs")
+	cmd.Flags().Int("worker-count", 1, "number of workers of each fake job")
+	cmd.Flags().Int("target-tick", 100, "number of ticks before a fake worker finishes")
+	cmd.Flags().Duration("status-update-interval", 100*time.Millisecond, "minimum interval between two status updates of each fake worker")
+	cmd.Flags().Duration("inject-error-interval", 0, "each fake worker fails once after running for the interval, 0 means no failure")
+	cmd.Flags().Duration("timeout", 10*time.Minute, "time to wait for the fake jobs to finish")
+	cmd.Flags().Uint32("user-meta-key-id", 1, "id of the key the user metastore is encrypted with")
+	cmd.Flags().String("user-meta-key-file", "", "file of the key the user metastore is encrypted with, empty means the user metastore is not encrypted")
+	return cmd
+}
+
+func runLoadTest(cmd *cobra.Command, _ []string) error {
+	flags := cmd.Flags()
+	cfg := &loadgen.Config{}
+	var err error
+	if cfg.JobCount, err = flags.GetInt("job-count"); err != nil {
+		return err
+	}
+	if cfg.WorkerCount, err = flags.GetInt("worker-count"); err != nil {
+		return err
+	}
+	if cfg.TargetTick, err = flags.GetInt("target-tick"); err != nil {
+		return err
+	}
+	if cfg.StatusUpdateInterval, err = flags.GetDuration("status-update-interval"); err != nil {
+		return err
+	}
+	if cfg.InjectErrorInterval, err = flags.GetDuration("inject-error-interval"); err != nil {
+		return err
+	}
+	timeout, err := flags.GetDuration("timeout")
+	if err != nil {
+		return err
+	}
+	// the checkpoints are encrypted by the fake jobs if the user metastore is
+	// encrypted, the current key is enough to read them
+	encryptionCfg := &encryption.Config{}
+	keyFile, err := flags.GetString("user-meta-key-file")
+	if err != nil {
+		return err
+	}
+	if keyFile != "" {
+		keyID, err := flags.GetUint32("user-meta-key-id")
+		if err != nil {
+			return err
+		}
+		encryptionCfg.Keys = []encryption.KeyConfig{{ID: keyID, KeyFile: keyFile}}
+	}
+	if err := encryptionCfg.Adjust(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// checkpoints of fake jobs are stored in the user metastore
+	resp, err := cltManager.MasterClient().QueryMetaStore(ctx,
+		&pb.QueryMetaStoreRequest{Tp: pb.StoreType_AppMetaStore}, rpcTimeout)
+	if err != nil {
+		log.L().Error("failed to query user metastore", zap.Error(err))
+		return err
+	}
+	userRawKVClient, err := kvclient.NewKVClient(&metaclient.StoreConfigParams{
+		Endpoints: []string{resp.Address},
+	})
+	if err != nil {
+		return err
+	}
+	defer userRawKVClient.Close()
+	metaCli := kvclient.NewPrefixKVClient(userRawKVClient, tenant.DefaultUserTenantID,
+		kvclient.WithKeyring(encryptionCfg.Keyring()))
+
+	report, err := loadgen.NewGenerator(cfg, cltManager.MasterClient(), metaCli).Run(ctx)
+	if report != nil {
+		log.L().Info("load test report", zap.Stringer("report", report))
+	}
+	return err
+}
@@ -0,0 +1,95 @@
+package ctl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/hanfei1991/microcosm/pb"
+)
+
+var cordonStates = map[string]pb.CordonState{
+	"cordoned":    pb.CordonState_Cordoned,
+	"uncordoned":  pb.CordonState_Uncordoned,
+	"blacklisted": pb.CordonState_Blacklisted,
+}
+
+func newCordonExecutor() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cordon-executor",
+		Short: "cordon or blacklist an executor",
+		Long: `Set the cordon state of an executor, the state is one of
+  cordoned:    no new task is placed on the executor, the running tasks are not affected
+  blacklisted: the executor is treated as failed, the workers on it go offline and it can not register again
+  uncordoned:  the executor is scheduled as usual`,
+		RunE: runCordonExecutor,
+	}
+	cmd.Flags().String("executor-id", "", "id of the executor")
+	cmd.Flags().String("state", "cordoned", "the cordon state, one of cordoned, blacklisted and uncordoned")
+	return cmd
+}
+
+func runCordonExecutor(cmd *cobra.Command, _ []string) error {
+	flags := cmd.Flags()
+	executorID, err := flags.GetString("executor-id")
+	if err != nil {
+		return err
+	}
+	if executorID == "" {
+		return fmt.Errorf("executor-id should not be empty")
+	}
+	stateName, err := flags.GetString("state")
+	if err != nil {
+		return err
+	}
+	state, ok := cordonStates[stateName]
+	if !ok {
+		return fmt.Errorf("unknown state %s", stateName)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	resp, err := cltManager.MasterClient().CordonExecutor(ctx, &pb.CordonExecutorRequest{
+		ExecutorId: executorID,
+		State:      state,
+	})
+	if err != nil {
+		return err
+	}
+	if resp.Err != nil {
+		return fmt.Errorf("%s", resp.Err.Message)
+	}
+	log.L().Info("executor cordon state updated",
+		zap.String("executor-id", executorID), zap.String("state", stateName))
+	return nil
+}
+
+func newQueryExecutorCordons() *cobra.Command {
+	return &cobra.Command{
+		Use:   "query-executor-cordons",
+		Short: "query the executors cordoned or blacklisted",
+		RunE:  runQueryExecutorCordons,
+	}
+}
+
+func runQueryExecutorCordons(cmd *cobra.Command, _ []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	resp, err := cltManager.MasterClient().QueryExecutorCordons(ctx, &pb.QueryExecutorCordonsRequest{})
+	if err != nil {
+		return err
+	}
+	if resp.Err != nil {
+		return fmt.Errorf("%s", resp.Err.Message)
+	}
+	for _, cordon := range resp.Cordons {
+		log.L().Info("executor cordon",
+			zap.String("executor-id", cordon.ExecutorId),
+			zap.String("state", cordon.State.String()))
+	}
+	return nil
+}
@@ -31,7 +31,29 @@ func newRootCmd() *cobra.Command {
 	}
 	cmd.AddCommand(newSubmitJob())
 	cmd.AddCommand(newQueryJob())
+	cmd.AddCommand(newQueryJobs())
+	cmd.AddCommand(newListWorkers())
 	cmd.AddCommand(newPauseJob())
+	cmd.AddCommand(newUpdateJobTimeouts())
+	cmd.AddCommand(newSetJobLogLevel())
+	cmd.AddCommand(newOperateJobTasks())
+	cmd.AddCommand(newUpdateJobSource())
+	cmd.AddCommand(newCreateJobSchedule())
+	cmd.AddCommand(newQueryJobSchedules())
+	cmd.AddCommand(newDeleteJobSchedule())
+	cmd.AddCommand(newRegisterJobTemplate())
+	cmd.AddCommand(newQueryJobTemplates())
+	cmd.AddCommand(newDeleteJobTemplate())
+	cmd.AddCommand(newPutSecret())
+	cmd.AddCommand(newListSecrets())
+	cmd.AddCommand(newDeleteSecret())
+	cmd.AddCommand(newLoadTest())
+	cmd.AddCommand(newExportMetadata())
+	cmd.AddCommand(newImportMetadata())
+	cmd.AddCommand(newMigrateMetaStore())
+	cmd.AddCommand(newCordonExecutor())
+	cmd.AddCommand(newQueryExecutorCordons())
+	cmd.AddCommand(newClusterHealth())
 	helpCmd := &cobra.Command{
 		Use:   "help [command]",
 		Short: "Gets help about any commands",
@@ -171,6 +193,10 @@ func MainStart(ctx context.Context, args []string) {
 			cmd.Println(utils.GetRawInfo())
 			os.Exit(0)
 		}
+		if cmd.Annotations[annotationOffline] != "" {
+			log.SetLevel(zapcore.InfoLevel)
+			return nil
+		}
 		cfg := newConfig(cmd.Flags())
 		err := cfg.Adjust()
 		if err != nil {
@@ -0,0 +1,142 @@
+package ctl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/hanfei1991/microcosm/pb"
+	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+)
+
+const migrationWatchInterval = time.Second
+
+var migrationOps = map[string]pb.MigrateMetaStoreRequest_Op{
+	"status": pb.MigrateMetaStoreRequest_Status,
+	"start":  pb.MigrateMetaStoreRequest_Start,
+	"verify": pb.MigrateMetaStoreRequest_Verify,
+	"switch": pb.MigrateMetaStoreRequest_Switch,
+	"abort":  pb.MigrateMetaStoreRequest_Abort,
+}
+
+func newMigrateMetaStore() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate-metastore",
+		Short: "migrate the framework metastore to another backend online",
+		Long: `Migrate the framework metastore to another backend online, the steps are:
+  start:  copy the writes of the server master to the target metastore, and copy the existing records in the background
+  verify: compare the metastores and repair the target metastore, it can be run repeatedly
+  switch: fence the writes of the executors, verify again, serve with the target metastore, and wait for the executors to reconnect
+  abort:  stop copying the writes to the target metastore
+  status: query the progress of the migration
+After switching, the source metastore rejects any write. Update the framework metastore config of the server masters before removing the source metastore.`,
+		RunE: runMigrateMetaStore,
+	}
+	cmd.Flags().String("op", "status", "the step of the migration, one of start, verify, switch, abort and status")
+	cmd.Flags().String("target-store-type", metaclient.StoreTypeMySQL, "backend type of the target metastore, mysql or sqlite")
+	cmd.Flags().String("target-endpoints", "", "endpoints of the target metastore")
+	cmd.Flags().String("target-user", pkgOrm.DefaultFrameMetaUser, "user of the target metastore")
+	cmd.Flags().String("target-password", pkgOrm.DefaultFrameMetaPassword, "password of the target metastore")
+	cmd.Flags().Duration("timeout", 10*time.Minute, "timeout of the step, verify and switch may take long on large metastores")
+	cmd.Flags().Bool("watch", false, "watch the progress until the verification in the background is done")
+	return cmd
+}
+
+func runMigrateMetaStore(cmd *cobra.Command, _ []string) error {
+	flags := cmd.Flags()
+	opName, err := flags.GetString("op")
+	if err != nil {
+		return err
+	}
+	op, ok := migrationOps[opName]
+	if !ok {
+		return fmt.Errorf("unknown op %s", opName)
+	}
+	timeout, err := flags.GetDuration("timeout")
+	if err != nil {
+		return err
+	}
+	watch, err := flags.GetBool("watch")
+	if err != nil {
+		return err
+	}
+
+	req := &pb.MigrateMetaStoreRequest{Op: op}
+	if req.Op == pb.MigrateMetaStoreRequest_Start {
+		target := metaclient.StoreConfigParams{}
+		if target.StoreType, err = flags.GetString("target-store-type"); err != nil {
+			return err
+		}
+		endpoints, err := flags.GetString("target-endpoints")
+		if err != nil {
+			return err
+		}
+		if endpoints == "" {
+			return fmt.Errorf("target-endpoints should not be empty")
+		}
+		target.SetEndpoints(endpoints)
+		if target.Auth.User, err = flags.GetString("target-user"); err != nil {
+			return err
+		}
+		if target.Auth.Passwd, err = flags.GetString("target-password"); err != nil {
+			return err
+		}
+		data, err := json.Marshal(target)
+		if err != nil {
+			return err
+		}
+		req.Target = string(data)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	resp, err := cltManager.MasterClient().MigrateMetaStore(ctx, req)
+	if err != nil {
+		return err
+	}
+	if resp.Err != nil {
+		return fmt.Errorf("%s", resp.Err.Message)
+	}
+	logMigrationStatus(resp)
+
+	for watch && resp.Verifying {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(migrationWatchInterval):
+		}
+		resp, err = cltManager.MasterClient().MigrateMetaStore(ctx,
+			&pb.MigrateMetaStoreRequest{Op: pb.MigrateMetaStoreRequest_Status})
+		if err != nil {
+			return err
+		}
+		if resp.Err != nil {
+			return fmt.Errorf("%s", resp.Err.Message)
+		}
+		logMigrationStatus(resp)
+	}
+	return nil
+}
+
+func logMigrationStatus(resp *pb.MigrateMetaStoreResponse) {
+	fields := []zap.Field{
+		zap.String("phase", resp.Phase),
+		zap.Strings("target-endpoints", resp.TargetEndpoints),
+		zap.Int64("failed-writes", resp.FailedWrites),
+		zap.Bool("verifying", resp.Verifying),
+		zap.Any("mismatches", resp.Mismatches),
+	}
+	if resp.LastVerifyTime != 0 {
+		fields = append(fields, zap.Time("last-verify-time", time.Unix(resp.LastVerifyTime, 0)))
+	}
+	if resp.LastVerifyError != "" {
+		fields = append(fields, zap.String("last-verify-error", resp.LastVerifyError))
+	}
+	log.L().Info("metastore migration status", fields...)
+}
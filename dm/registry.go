@@ -14,10 +14,15 @@ func init() {
 	loadFactory := unitWorkerFactory{constructor: newLoadWorker}
 	syncFactory := unitWorkerFactory{constructor: newSyncWorker}
 
-	r := registry.GlobalWorkerRegistry()
-	r.MustRegisterWorkerType(lib.WorkerDMDump, dumpFactory)
-	r.MustRegisterWorkerType(lib.WorkerDMLoad, loadFactory)
-	r.MustRegisterWorkerType(lib.WorkerDMSync, syncFactory)
+	registry.GlobalWorkerRegistry().MustRegisterPlugin(&registry.JobPlugin{
+		Name: "dm",
+		// DM job master is registered by jobmaster/dm
+		Workers: []registry.WorkerPlugin{
+			{Type: lib.WorkerDMDump, Codec: lib.TOMLConfigCodec, Factory: dumpFactory},
+			{Type: lib.WorkerDMLoad, Codec: lib.TOMLConfigCodec, Factory: loadFactory},
+			{Type: lib.WorkerDMSync, Codec: lib.TOMLConfigCodec, Factory: syncFactory},
+		},
+	})
 }
 
 type workerConstructor func(lib.WorkerConfig) lib.WorkerImpl
@@ -18,12 +18,21 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"os"
 	"runtime"
 	"strings"
 	"time"
 
 	"github.com/BurntSushi/toml"
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/model"
 	"github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/interceptor"
+	"github.com/hanfei1991/microcosm/pkg/meta/encryption"
+	"github.com/hanfei1991/microcosm/pkg/p2p"
+	"github.com/hanfei1991/microcosm/pkg/redact"
+	"github.com/hanfei1991/microcosm/pkg/secret"
+	"github.com/hanfei1991/microcosm/pkg/sink"
 	"github.com/pingcap/tiflow/dm/pkg/log"
 )
 
@@ -34,6 +43,8 @@ var (
 	defaultKeepAliveTTL      = "20s"
 	defaultKeepAliveInterval = "500ms"
 	defaultRPCTimeout        = "3s"
+	defaultMasterGracePeriod = "60s"
+	defaultDrainTimeout      = "30s"
 	defaultDiscoverTicker    = 3 * time.Second
 	defaultMetricInterval    = 15 * time.Second
 
@@ -59,6 +70,9 @@ func NewConfig() *Config {
 	fs.StringVar(&cfg.Join, "join", "", `join to an existing cluster (usage: server masters' address)`)
 	fs.StringVar(&cfg.Name, "name", "", "human-readable name for executor")
 	fs.StringVar(&cfg.KeepAliveTTLStr, "keepalive-ttl", defaultKeepAliveTTL, "executor's TTL for keepalive with etcd (in seconds)")
+	fs.StringVar(&cfg.BootstrapMode, "bootstrap-mode", "", `how the executor is bootstrapped, "kubernetes" reads the pod metadata from the downward API`)
+	fs.StringVar(&cfg.DebugLockHoldThresholdStr, "debug-lock-hold-threshold", "", "log the stacks of locks held or waited for longer than the threshold, for debugging only")
+	fs.StringVar(&cfg.AdminAddr, "admin-addr", "", "listen address of the admin HTTP server for diagnosing, disabled if empty")
 
 	return cfg
 }
@@ -85,17 +99,148 @@ type Config struct {
 	KeepAliveTTLStr      string `toml:"keepalive-ttl" json:"keepalive-ttl"`
 	KeepAliveIntervalStr string `toml:"keepalive-interval" json:"keepalive-interval"`
 	RPCTimeoutStr        string `toml:"rpc-timeout" json:"rpc-timeout"`
+	// MasterGracePeriodStr is how long the executor keeps running its workers
+	// and tries to register again after it loses the server master.
+	MasterGracePeriodStr string `toml:"master-grace-period" json:"master-grace-period"`
+	// DrainTimeoutStr is how long running workers are given to checkpoint
+	// and exit during a graceful shutdown.
+	DrainTimeoutStr string `toml:"drain-timeout" json:"drain-timeout"`
+	// IdleEvictTimeoutStr is how long the executor runs no worker before it
+	// reports itself idle-evictable, empty means never.
+	IdleEvictTimeoutStr string `toml:"idle-evict-timeout" json:"idle-evict-timeout"`
 
 	PollConcurrency int `toml:"poll-concurrency" json:"poll-concurrency"`
 
-	KeepAliveTTL      time.Duration `toml:"-" json:"-"`
-	KeepAliveInterval time.Duration `toml:"-" json:"-"`
-	RPCTimeout        time.Duration `toml:"-" json:"-"`
+	// DispatchConcurrency is the max number of workers initialized at the
+	// same time, the others are queued.
+	DispatchConcurrency int `toml:"dispatch-concurrency" json:"dispatch-concurrency"`
+	// DispatchQueueLength is the max number of workers queued, new workers
+	// are rejected when the queue is full and scheduled to other executors.
+	DispatchQueueLength int `toml:"dispatch-queue-length" json:"dispatch-queue-length"`
+
+	// IsolatedWorkerTypes are the worker types that run in separate worker
+	// processes, so that a crashing worker does not bring down the executor.
+	IsolatedWorkerTypes []libModel.WorkerType `toml:"isolated-worker-types" json:"isolated-worker-types"`
+
+	// WarmPool keeps worker processes warmed up for the isolated worker
+	// types, so that dispatching a worker doesn't wait for a worker process
+	// to start and connect to the metastores.
+	WarmPool []WarmPoolConfig `toml:"warm-pool" json:"warm-pool"`
+
+	// Plugins are the paths of job plugin binaries to load at startup, the
+	// workers of their worker types run in worker processes started from
+	// them, see subprocess.ServePlugin for how to build one. To warm them
+	// up, list their worker types in IsolatedWorkerTypes.
+	Plugins []string `toml:"plugins" json:"plugins"`
+
+	// JobTrafficSoftLimit is the soft limit of network traffic in bytes
+	// of each job on this executor, 0 means no limit.
+	JobTrafficSoftLimit int64 `toml:"job-traffic-soft-limit" json:"job-traffic-soft-limit"`
+	// EnforceJobTrafficSoftLimit rejects the traffic of a job that has
+	// exceeded the soft limit, instead of only logging a warning.
+	EnforceJobTrafficSoftLimit bool `toml:"enforce-job-traffic-soft-limit" json:"enforce-job-traffic-soft-limit"`
+
+	// SharedCacheCapacity is the memory budget in bytes of the cache shared
+	// by workers on this executor, 0 means sharedcache.DefaultCapacity.
+	SharedCacheCapacity int64 `toml:"shared-cache-capacity" json:"shared-cache-capacity"`
+
+	// StatusFlushIntervalStr enables persisting the statuses of workers on
+	// this executor in batches every interval, empty means every status is
+	// persisted on its own.
+	StatusFlushIntervalStr string `toml:"status-flush-interval" json:"status-flush-interval"`
+	// StatusBatchSize is the max number of worker statuses persisted in a
+	// batch, 0 means metadata.DefaultStatusBatchSize.
+	StatusBatchSize int `toml:"status-batch-size" json:"status-batch-size"`
+
+	// DebugLockHoldThresholdStr enables logging the stacks of instrumented
+	// locks held or waited for longer than it, empty means disabled.
+	DebugLockHoldThresholdStr string `toml:"debug-lock-hold-threshold" json:"debug-lock-hold-threshold"`
+	// AdminAddr is the listen address of the admin HTTP server, which serves
+	// pprof, runtime stats, goroutine dumps and the in-memory states of
+	// masters for diagnosing, empty means disabled.
+	AdminAddr string `toml:"admin-addr" json:"admin-addr"`
+	// GRPCServer configures the slow request logging and rate limiting of
+	// the gRPC server.
+	GRPCServer interceptor.Config `toml:"grpc-server" json:"grpc-server"`
+	// P2PMessage configures the size limit and the compression of the p2p
+	// messages sent and received by the workers.
+	P2PMessage p2p.MessageConfig `toml:"p2p-message" json:"p2p-message"`
+
+	// Resources is the capacity of this executor in the resource dimensions
+	// other than cpu, such as memory, disk or custom resources like "gpu".
+	// Tasks requiring a dimension are only scheduled to the executors
+	// declaring enough capacity of it. The disk dimension is in
+	// model.DiskUnit, which the usage of local file resources is reported in.
+	Resources model.RescVector `toml:"resources" json:"resources"`
+	// LocalResourceQuota is the max bytes of the local file resources on
+	// this executor, and LocalResourceJobQuota is the max bytes of those of
+	// a job, 0 means unlimited. The writes exceeding the quotas fail.
+	LocalResourceQuota    int64 `toml:"local-resource-quota" json:"local-resource-quota"`
+	LocalResourceJobQuota int64 `toml:"local-resource-job-quota" json:"local-resource-job-quota"`
+
+	// Labels describe where the executor runs, such as model.LabelZone.
+	// The tasks of a job are spread across the zones of executors.
+	Labels map[string]string `toml:"labels" json:"labels"`
+
+	// BootstrapMode is how the executor is bootstrapped, empty means the
+	// executor is configured by the config file and flags only. In
+	// BootstrapModeKubernetes, the labels of the pod, node and zone are
+	// filled by the pod metadata.
+	BootstrapMode string           `toml:"bootstrap-mode" json:"bootstrap-mode"`
+	Kubernetes    KubernetesConfig `toml:"kubernetes" json:"kubernetes"`
+
+	// Sink exports worker statuses and job events to an external system,
+	// events are not exported if the type of the sink is empty.
+	Sink sink.Config `toml:"sink" json:"sink"`
+
+	// Secret configures the key of the secrets in the framework metastore,
+	// which are referenced by the env of workers.
+	Secret secret.Config `toml:"secret" json:"secret"`
+
+	// UserMetaEncryption configures the keys the values in the user
+	// metastore are encrypted with, so that the credentials in the configs
+	// of user jobs aren't stored in plaintext.
+	UserMetaEncryption encryption.Config `toml:"user-meta-encryption" json:"user-meta-encryption"`
+
+	KeepAliveTTL           time.Duration `toml:"-" json:"-"`
+	KeepAliveInterval      time.Duration `toml:"-" json:"-"`
+	RPCTimeout             time.Duration `toml:"-" json:"-"`
+	MasterGracePeriod      time.Duration `toml:"-" json:"-"`
+	DrainTimeout           time.Duration `toml:"-" json:"-"`
+	IdleEvictTimeout       time.Duration `toml:"-" json:"-"`
+	StatusFlushInterval    time.Duration `toml:"-" json:"-"`
+	DebugLockHoldThreshold time.Duration `toml:"-" json:"-"`
 
 	printVersion      bool
 	printSampleConfig bool
 }
 
+// WarmPoolConfig is the number of warm worker processes of a worker type.
+type WarmPoolConfig struct {
+	WorkerType libModel.WorkerType `toml:"worker-type" json:"worker-type"`
+	Size       int                 `toml:"size" json:"size"`
+}
+
+// warmPoolSizes returns the sizes of the warm pools by worker types.
+func (c *Config) warmPoolSizes() map[libModel.WorkerType]int {
+	sizes := make(map[libModel.WorkerType]int, len(c.WarmPool))
+	for _, pool := range c.WarmPool {
+		sizes[pool.WorkerType] += pool.Size
+	}
+	return sizes
+}
+
+// isWorkerIsolated returns whether workers of the given type should run
+// in separate worker processes.
+func (c *Config) isWorkerIsolated(tp libModel.WorkerType) bool {
+	for _, isolated := range c.IsolatedWorkerTypes {
+		if isolated == tp {
+			return true
+		}
+	}
+	return false
+}
+
 // Clone clones a config.
 func (c *Config) Clone() *Config {
 	clone := &Config{}
@@ -104,7 +249,7 @@ func (c *Config) Clone() *Config {
 }
 
 func (c *Config) String() string {
-	cfg, err := json.Marshal(c)
+	cfg, err := json.Marshal(redact.Value(c))
 	if err != nil {
 		log.L().Error("fail to marshal config to json", log.ShortError(err))
 	}
@@ -177,14 +322,98 @@ func (c *Config) Parse(arguments []string) error {
 	if err != nil {
 		return err
 	}
+
+	if c.MasterGracePeriodStr == "" {
+		c.MasterGracePeriodStr = defaultMasterGracePeriod
+	}
+	c.MasterGracePeriod, err = time.ParseDuration(c.MasterGracePeriodStr)
+	if err != nil {
+		return err
+	}
+
+	if c.DrainTimeoutStr == "" {
+		c.DrainTimeoutStr = defaultDrainTimeout
+	}
+	c.DrainTimeout, err = time.ParseDuration(c.DrainTimeoutStr)
+	if err != nil {
+		return err
+	}
+
+	if c.IdleEvictTimeoutStr != "" {
+		c.IdleEvictTimeout, err = time.ParseDuration(c.IdleEvictTimeoutStr)
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.StatusFlushIntervalStr != "" {
+		c.StatusFlushInterval, err = time.ParseDuration(c.StatusFlushIntervalStr)
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.DebugLockHoldThresholdStr != "" {
+		c.DebugLockHoldThreshold, err = time.ParseDuration(c.DebugLockHoldThresholdStr)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := c.Sink.Adjust(); err != nil {
+		return err
+	}
+	if err := c.Secret.Adjust(); err != nil {
+		return err
+	}
+	if err := c.UserMetaEncryption.Adjust(); err != nil {
+		return err
+	}
+	if err := c.GRPCServer.Adjust(); err != nil {
+		return err
+	}
+	if err := c.P2PMessage.Adjust(); err != nil {
+		return err
+	}
+
+	switch c.BootstrapMode {
+	case "":
+	case BootstrapModeKubernetes:
+		if err := c.adjustKubernetes(os.Getenv); err != nil {
+			return err
+		}
+	default:
+		return errors.ErrExecutorConfigInvalidFlag.GenWithStackByArgs("bootstrap-mode")
+	}
+
 	if c.PollConcurrency == 0 {
 		c.PollConcurrency = runtime.NumCPU()
 	}
+	// only the isolated workers run in worker processes
+	for _, pool := range c.WarmPool {
+		if pool.Size < 0 || !c.isWorkerIsolated(pool.WorkerType) {
+			return errors.ErrExecutorConfigInvalidFlag.GenWithStackByArgs("warm-pool")
+		}
+	}
+
+	if c.DispatchConcurrency <= 0 {
+		c.DispatchConcurrency = defaultRuntimeInitConcurrency
+	}
+	if c.DispatchQueueLength <= 0 {
+		c.DispatchQueueLength = defaultRuntimeIncomingQueueLen
+	}
 
 	if c.AdvertiseAddr == "" {
 		c.AdvertiseAddr = c.WorkerAddr
 	}
 
+	if c.LocalResourceQuota < 0 {
+		return errors.ErrExecutorConfigInvalidFlag.GenWithStackByArgs("local-resource-quota")
+	}
+	if c.LocalResourceJobQuota < 0 {
+		return errors.ErrExecutorConfigInvalidFlag.GenWithStackByArgs("local-resource-job-quota")
+	}
+
 	return nil
 }
 
@@ -0,0 +1,225 @@
+package connectortask
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+
+	"github.com/hanfei1991/microcosm/lib"
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/lib/registry"
+	"github.com/hanfei1991/microcosm/model"
+	"github.com/hanfei1991/microcosm/pkg/connector"
+	dcontext "github.com/hanfei1991/microcosm/pkg/context"
+	"github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/p2p"
+)
+
+const defaultFlushSize = 1024
+
+// Config is connector task config
+type Config struct {
+	Source connector.Spec `json:"source"`
+	Sink   connector.Spec `json:"sink"`
+	// Offset is the checkpointed offset of the source to resume from, the
+	// records are read from the beginning if it's empty.
+	Offset connector.Offset `json:"offset,omitempty"`
+	// FlushSize is the number of records written between flushes of the
+	// sink, default is 1024.
+	FlushSize int `json:"flush-size,omitempty"`
+}
+
+// Status represents business status of connector task, the job master can
+// recreate the task from the checkpointed Offset.
+type Status struct {
+	Offset connector.Offset `json:"offset"`
+	Count  int64            `json:"count"`
+}
+
+type connectorTask struct {
+	lib.BaseWorker
+
+	cfg      *Config
+	source   connector.Source
+	sink     connector.Sink
+	offset   *atomic.String
+	counter  *atomic.Int64
+	cancelFn func()
+
+	statusCode struct {
+		sync.RWMutex
+		code libModel.WorkerStatusCode
+	}
+	runError struct {
+		sync.RWMutex
+		err error
+	}
+
+	statusRateLimiter *rate.Limiter
+}
+
+// RegisterWorker is used to register connector task worker into global registry
+func RegisterWorker() {
+	constructor := func(ctx *dcontext.Context, id libModel.WorkerID, masterID libModel.MasterID, config lib.WorkerConfig) lib.WorkerImpl {
+		return newConnectorTask(config.(*Config))
+	}
+	factory := registry.NewSimpleWorkerFactory(constructor, &Config{})
+	registry.GlobalWorkerRegistry().MustRegisterWorkerType(lib.ConnectorTask, factory)
+}
+
+func newConnectorTask(cfg *Config) *connectorTask {
+	return &connectorTask{
+		cfg:               cfg,
+		offset:            atomic.NewString(string(cfg.Offset)),
+		counter:           atomic.NewInt64(0),
+		statusRateLimiter: rate.NewLimiter(rate.Every(time.Second), 1),
+	}
+}
+
+// InitImpl implements WorkerImpl.InitImpl
+func (task *connectorTask) InitImpl(ctx context.Context) error {
+	task.Logger().Info("init the task", zap.String("source", task.cfg.Source.Type), zap.String("sink", task.cfg.Sink.Type))
+	if err := task.createConnectors(registry.GlobalWorkerRegistry()); err != nil {
+		return err
+	}
+	flushSize := task.cfg.FlushSize
+	if flushSize <= 0 {
+		flushSize = defaultFlushSize
+	}
+
+	task.setStatusCode(libModel.WorkerStatusNormal)
+	ctx, task.cancelFn = context.WithCancel(ctx)
+	go func() {
+		err := connector.Copy(ctx, task.source, task.sink, task.cfg.Offset, flushSize,
+			func(offset connector.Offset, count int64) {
+				task.offset.Store(string(offset))
+				task.counter.Store(count)
+			})
+		if err != nil {
+			task.Logger().Error("error happened when moving data", zap.Error(err))
+			task.setRunError(err)
+			task.setStatusCode(libModel.WorkerStatusError)
+			return
+		}
+		task.Logger().Info("all the records are moved", zap.Int64("count", task.counter.Load()), zap.String("offset", task.offset.Load()))
+		task.setStatusCode(libModel.WorkerStatusFinished)
+	}()
+	return nil
+}
+
+// createConnectors creates the source and the sink from the connectors
+// registered in the registry.
+func (task *connectorTask) createConnectors(r registry.Registry) error {
+	sourceFactory, err := r.GetSourceFactory(task.cfg.Source.Type)
+	if err != nil {
+		return err
+	}
+	sinkFactory, err := r.GetSinkFactory(task.cfg.Sink.Type)
+	if err != nil {
+		return err
+	}
+	if task.source, err = sourceFactory(task.cfg.Source.Config); err != nil {
+		return err
+	}
+	if task.sink, err = sinkFactory(task.cfg.Sink.Config); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Tick is called on a fixed interval.
+func (task *connectorTask) Tick(ctx context.Context) error {
+	if task.statusRateLimiter.Allow() {
+		err := task.BaseWorker.UpdateStatus(ctx, task.Status())
+		if errors.ErrWorkerUpdateStatusTryAgain.Equal(err) {
+			task.Logger().Warn("update status try again later", zap.String("error", err.Error()))
+			return nil
+		}
+		return err
+	}
+	switch task.getStatusCode() {
+	case libModel.WorkerStatusFinished, libModel.WorkerStatusError, libModel.WorkerStatusStopped:
+		return task.BaseWorker.Exit(ctx, task.Status(), task.getRunError())
+	default:
+	}
+	return nil
+}
+
+// Status returns a short worker status to be periodically sent to the master.
+func (task *connectorTask) Status() libModel.WorkerStatus {
+	stats := &Status{
+		Offset: connector.Offset(task.offset.Load()),
+		Count:  task.counter.Load(),
+	}
+	statsBytes, err := json.Marshal(stats)
+	if err != nil {
+		task.Logger().Panic("get stats error", zap.Error(err))
+	}
+	return libModel.WorkerStatus{
+		Code:     task.getStatusCode(),
+		ExtBytes: statsBytes,
+	}
+}
+
+// Workload returns the current workload of the worker.
+func (task *connectorTask) Workload() model.RescUnit {
+	return 1
+}
+
+// OnMasterFailover is called when the master is failed over.
+func (task *connectorTask) OnMasterFailover(reason lib.MasterFailoverReason) error {
+	return nil
+}
+
+// OnMasterMessage is called when a customized message is received.
+func (task *connectorTask) OnMasterMessage(topic p2p.Topic, message p2p.MessageValue) error {
+	switch msg := message.(type) {
+	case *libModel.StatusChangeRequest:
+		switch msg.ExpectState {
+		case libModel.WorkerStatusStopped:
+			task.setStatusCode(libModel.WorkerStatusStopped)
+		default:
+			task.Logger().Info("ignore status change state", zap.Int32("state", int32(msg.ExpectState)))
+		}
+	default:
+		task.Logger().Info("unsupported message", zap.Any("message", message))
+	}
+	return nil
+}
+
+// CloseImpl tells the WorkerImpl to quit running and release resources.
+func (task *connectorTask) CloseImpl(ctx context.Context) error {
+	if task.cancelFn != nil {
+		task.cancelFn()
+	}
+	return nil
+}
+
+func (task *connectorTask) getStatusCode() libModel.WorkerStatusCode {
+	task.statusCode.RLock()
+	defer task.statusCode.RUnlock()
+	return task.statusCode.code
+}
+
+func (task *connectorTask) setStatusCode(status libModel.WorkerStatusCode) {
+	task.statusCode.Lock()
+	defer task.statusCode.Unlock()
+	task.statusCode.code = status
+}
+
+func (task *connectorTask) getRunError() error {
+	task.runError.RLock()
+	defer task.runError.RUnlock()
+	return task.runError.err
+}
+
+func (task *connectorTask) setRunError(err error) {
+	task.runError.Lock()
+	defer task.runError.Unlock()
+	task.runError.err = err
+}
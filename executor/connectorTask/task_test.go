@@ -0,0 +1,58 @@
+package connectortask
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hanfei1991/microcosm/lib"
+	"github.com/hanfei1991/microcosm/lib/registry"
+	"github.com/hanfei1991/microcosm/pkg/connector"
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+var _ lib.WorkerImpl = &connectorTask{}
+
+type fakeSource struct {
+	connector.Source
+	config string
+}
+
+type fakeSink struct {
+	connector.Sink
+	config string
+}
+
+func TestCreateConnectors(t *testing.T) {
+	t.Parallel()
+
+	r := registry.NewRegistry()
+	require.NoError(t, r.RegisterPlugin(&registry.JobPlugin{
+		Name: "fake",
+		Connectors: []registry.ConnectorPlugin{{
+			Type: "fake",
+			Source: func(config json.RawMessage) (connector.Source, error) {
+				return &fakeSource{config: string(config)}, nil
+			},
+			Sink: func(config json.RawMessage) (connector.Sink, error) {
+				return &fakeSink{config: string(config)}, nil
+			},
+		}},
+	}))
+
+	task := newConnectorTask(&Config{
+		Source: connector.Spec{Type: "fake", Config: json.RawMessage(`{"file":1}`)},
+		Sink:   connector.Spec{Type: "fake", Config: json.RawMessage(`{"dir":"d"}`)},
+	})
+	require.NoError(t, task.createConnectors(r))
+	require.Equal(t, `{"file":1}`, task.source.(*fakeSource).config)
+	require.Equal(t, `{"dir":"d"}`, task.sink.(*fakeSink).config)
+
+	task = newConnectorTask(&Config{
+		Source: connector.Spec{Type: "fake"},
+		Sink:   connector.Spec{Type: "kafka"},
+	})
+	err := task.createConnectors(r)
+	require.True(t, derror.ErrConnectorNotFound.Equal(err))
+}
@@ -0,0 +1,196 @@
+package cvstask
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	"github.com/hanfei1991/microcosm/pb"
+	"github.com/hanfei1991/microcosm/pkg/connector"
+	"github.com/hanfei1991/microcosm/pkg/partition"
+)
+
+// DemoConnectorType is the type of the connector reading and writing files of
+// the demo server.
+const DemoConnectorType = "demo"
+
+// DemoSourceConfig is the config of the source reading a file of the demo
+// server, the offset of a line is its key.
+type DemoSourceConfig struct {
+	Host    string `json:"host"`
+	FileIdx int    `json:"file-idx"`
+	// EndLoc is the key before which the lines are read, the lines are read
+	// till the end of the file if it's empty.
+	EndLoc string `json:"end-loc,omitempty"`
+}
+
+// DemoSinkConfig is the config of the sink writing files of the demo server.
+type DemoSinkConfig struct {
+	Host    string `json:"host"`
+	Dir     string `json:"dir"`
+	FileIdx int    `json:"file-idx"`
+	// Partition repartitions the lines by key into files of the directory if
+	// set, otherwise they are written into the file of FileIdx.
+	Partition *partition.Spec `json:"partition,omitempty"`
+}
+
+type demoSource struct {
+	cfg    *DemoSourceConfig
+	reader pb.DataRWService_ReadLinesClient
+}
+
+func newDemoSource(cfg *DemoSourceConfig) *demoSource {
+	return &demoSource{cfg: cfg}
+}
+
+func decodeDemoSource(config json.RawMessage) (connector.Source, error) {
+	cfg := &DemoSourceConfig{}
+	if err := json.Unmarshal(config, cfg); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return newDemoSource(cfg), nil
+}
+
+// Open implements connector.Source.Open
+func (s *demoSource) Open(ctx context.Context, offset connector.Offset) error {
+	conn, err := pool.getConn(s.cfg.Host)
+	if err != nil {
+		log.L().Error("cann't connect with the source address ", zap.Any("message", s.cfg.Host))
+		return err
+	}
+	client := pb.NewDataRWServiceClient(conn)
+	s.reader, err = client.ReadLines(ctx, &pb.ReadLinesRequest{
+		FileIdx:   int32(s.cfg.FileIdx),
+		LineNo:    []byte(offset),
+		EndLineNo: []byte(s.cfg.EndLoc),
+	})
+	if err != nil {
+		log.L().Error("read data from file failed ", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Read implements connector.Source.Read
+func (s *demoSource) Read(ctx context.Context) (*connector.Record, error) {
+	reply, err := s.reader.Recv()
+	if err != nil {
+		return nil, err
+	}
+	if reply.ErrMsg != "" {
+		return nil, errors.New(reply.ErrMsg)
+	}
+	if reply.IsEof {
+		return nil, io.EOF
+	}
+	return &connector.Record{Key: reply.Key, Value: reply.Val, Offset: connector.Offset(reply.Key)}, nil
+}
+
+// Close implements connector.Source.Close
+func (s *demoSource) Close() error {
+	// the stream is closed by the server after all the lines are sent, or
+	// canceled with the context of Open.
+	return nil
+}
+
+type demoSink struct {
+	cfg         *DemoSinkConfig
+	partitioner partition.Partitioner
+	client      pb.DataRWServiceClient
+	// a writing stream can only write one file, so a stream is opened for
+	// every file of the destination
+	writers map[int]pb.DataRWService_WriteLinesClient
+}
+
+func newDemoSink(cfg *DemoSinkConfig) (*demoSink, error) {
+	sink := &demoSink{
+		cfg:     cfg,
+		writers: make(map[int]pb.DataRWService_WriteLinesClient),
+	}
+	if cfg.Partition != nil {
+		partitioner, err := partition.New(cfg.Partition)
+		if err != nil {
+			return nil, err
+		}
+		sink.partitioner = partitioner
+	}
+	return sink, nil
+}
+
+func decodeDemoSink(config json.RawMessage) (connector.Sink, error) {
+	cfg := &DemoSinkConfig{}
+	if err := json.Unmarshal(config, cfg); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return newDemoSink(cfg)
+}
+
+// Open implements connector.Sink.Open
+func (s *demoSink) Open(ctx context.Context) error {
+	conn, err := pool.getConn(s.cfg.Host)
+	if err != nil {
+		log.L().Error("can't connect with the destination address ", zap.Error(err))
+		return err
+	}
+	s.client = pb.NewDataRWServiceClient(conn)
+	return nil
+}
+
+// Write implements connector.Sink.Write
+func (s *demoSink) Write(ctx context.Context, record *connector.Record) error {
+	idx := s.cfg.FileIdx
+	if s.partitioner != nil {
+		var err error
+		idx, err = s.partitioner.Partition(record.Key)
+		if err != nil {
+			return err
+		}
+	}
+	writer, ok := s.writers[idx]
+	if !ok {
+		var err error
+		writer, err = s.client.WriteLines(ctx)
+		if err != nil {
+			log.L().Error("call write data rpc failed", zap.Error(err))
+			return err
+		}
+		s.writers[idx] = writer
+	}
+	err := writer.Send(&pb.WriteLinesRequest{FileIdx: int32(idx), Key: record.Key, Value: record.Value, Dir: s.cfg.Dir})
+	if err != nil {
+		log.L().Error("call write data rpc failed ", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Flush implements connector.Sink.Flush, the lines sent in a stream are
+// written after the stream is closed.
+func (s *demoSink) Flush(ctx context.Context) error {
+	for idx, writer := range s.writers {
+		delete(s.writers, idx)
+		resp, err := writer.CloseAndRecv()
+		if err != nil {
+			return err
+		}
+		if len(resp.ErrMsg) > 0 {
+			return errors.Errorf("write file %d failed: %s", idx, resp.ErrMsg)
+		}
+	}
+	return nil
+}
+
+// Close implements connector.Sink.Close
+func (s *demoSink) Close() error {
+	for idx, writer := range s.writers {
+		delete(s.writers, idx)
+		if err := writer.CloseSend(); err != nil {
+			log.L().Warn("close writing stream failed", zap.Int("idx", idx), zap.Error(err))
+		}
+	}
+	return nil
+}
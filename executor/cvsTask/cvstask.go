@@ -7,7 +7,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/pingcap/tiflow/dm/pkg/log"
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
 	"golang.org/x/time/rate"
@@ -17,20 +16,16 @@ import (
 	libModel "github.com/hanfei1991/microcosm/lib/model"
 	"github.com/hanfei1991/microcosm/lib/registry"
 	"github.com/hanfei1991/microcosm/model"
-	"github.com/hanfei1991/microcosm/pb"
+	"github.com/hanfei1991/microcosm/pkg/connector"
 	dcontext "github.com/hanfei1991/microcosm/pkg/context"
 	"github.com/hanfei1991/microcosm/pkg/errors"
 	"github.com/hanfei1991/microcosm/pkg/p2p"
+	"github.com/hanfei1991/microcosm/pkg/partition"
 )
 
-const (
-	bufferSize = 1024
-)
-
-type strPair struct {
-	firstStr  string
-	secondStr string
-}
+// flushSize is the number of lines written between flushes, the location
+// reported in status is the last flushed line.
+const flushSize = 1024
 
 // Config is cvs task config
 type Config struct {
@@ -39,6 +34,14 @@ type Config struct {
 	DstHost  string `json:"DstHost"`
 	DstDir   string `json:"DstIdx"`
 	StartLoc string `json:"StartLoc"`
+	// RangeID is the id of the range of the file synced by the task, the
+	// lines before EndLoc are synced, or till the end of the file if EndLoc
+	// is empty.
+	RangeID int    `json:"RangeID"`
+	EndLoc  string `json:"EndLoc,omitempty"`
+	// Partition repartitions the lines by key into files of the destination
+	// if set, otherwise they are written into the file of Idx
+	Partition *partition.Spec `json:"Partition,omitempty"`
 }
 
 // Status represents business status of cvs task
@@ -80,10 +83,11 @@ type cvsTask struct {
 	lib.BaseWorker
 	Config
 	counter  *atomic.Int64
-	curLoc   string
+	curLoc   *atomic.String
 	cancelFn func()
-	buffer   chan strPair
-	isEOF    bool
+
+	source *demoSource
+	sink   *demoSink
 
 	statusCode struct {
 		sync.RWMutex
@@ -103,15 +107,23 @@ func RegisterWorker() {
 		return newCvsTask(ctx, id, masterID, config)
 	}
 	factory := registry.NewSimpleWorkerFactory(constructor, &Config{})
-	registry.GlobalWorkerRegistry().MustRegisterWorkerType(lib.CvsTask, factory)
+	registry.GlobalWorkerRegistry().MustRegisterPlugin(&registry.JobPlugin{
+		Name: "cvs",
+		// CVS job master is registered by jobmaster/cvsJob
+		Workers: []registry.WorkerPlugin{
+			{Type: lib.CvsTask, Codec: lib.JSONConfigCodec, Factory: factory},
+		},
+		Connectors: []registry.ConnectorPlugin{
+			{Type: DemoConnectorType, Source: decodeDemoSource, Sink: decodeDemoSink},
+		},
+	})
 }
 
 func newCvsTask(ctx *dcontext.Context, _workerID libModel.WorkerID, masterID libModel.MasterID, conf lib.WorkerConfig) *cvsTask {
 	cfg := conf.(*Config)
 	task := &cvsTask{
 		Config:            *cfg,
-		curLoc:            cfg.StartLoc,
-		buffer:            make(chan strPair, bufferSize),
+		curLoc:            atomic.NewString(cfg.StartLoc),
 		statusRateLimiter: rate.NewLimiter(rate.Every(time.Second), 1),
 		counter:           atomic.NewInt64(0),
 	}
@@ -120,24 +132,37 @@ func newCvsTask(ctx *dcontext.Context, _workerID libModel.WorkerID, masterID lib
 
 // InitImpl implements WorkerImpl.InitImpl
 func (task *cvsTask) InitImpl(ctx context.Context) error {
-	log.L().Info("init the task  ", zap.Any("task id :", task.ID()))
+	task.Logger().Info("init the task")
+	task.source = newDemoSource(&DemoSourceConfig{
+		Host:    task.SrcHost,
+		FileIdx: task.Idx,
+		EndLoc:  task.EndLoc,
+	})
+	sink, err := newDemoSink(&DemoSinkConfig{
+		Host:      task.DstHost,
+		Dir:       task.DstDir,
+		FileIdx:   task.Idx,
+		Partition: task.Partition,
+	})
+	if err != nil {
+		return err
+	}
+	task.sink = sink
 	task.setStatusCode(libModel.WorkerStatusNormal)
 	ctx, task.cancelFn = context.WithCancel(ctx)
 	go func() {
-		err := task.Receive(ctx)
-		if err != nil {
-			log.L().Error("error happened when reading data from the upstream ", zap.String("id", task.ID()), zap.Any("message", err.Error()))
-			task.setRunError(err)
-			task.setStatusCode(libModel.WorkerStatusError)
-		}
-	}()
-	go func() {
-		err := task.send(ctx)
+		err := connector.Copy(ctx, task.source, task.sink, connector.Offset(task.StartLoc), flushSize,
+			func(offset connector.Offset, count int64) {
+				task.curLoc.Store(string(offset))
+				task.counter.Store(count)
+			})
 		if err != nil {
-			log.L().Error("error happened when writing data to the downstream ", zap.String("id", task.ID()), zap.Any("message", err.Error()))
+			task.Logger().Error("error happened when syncing data", zap.Any("message", err.Error()))
 			task.setRunError(err)
 			task.setStatusCode(libModel.WorkerStatusError)
 		} else {
+			task.Logger().Info("Reach the end of the range ", zap.Any("fileID", task.Idx), zap.Int("rangeID", task.RangeID),
+				zap.Any("cnt", task.counter.Load()), zap.String("last write", task.curLoc.Load()))
 			task.setStatusCode(libModel.WorkerStatusFinished)
 		}
 	}()
@@ -151,7 +176,7 @@ func (task *cvsTask) Tick(ctx context.Context) error {
 	if task.statusRateLimiter.Allow() {
 		err := task.BaseWorker.UpdateStatus(ctx, task.Status())
 		if errors.ErrWorkerUpdateStatusTryAgain.Equal(err) {
-			log.L().Warn("update status try again later", zap.String("id", task.ID()), zap.String("error", err.Error()))
+			task.Logger().Warn("update status try again later", zap.String("error", err.Error()))
 			return nil
 		}
 		return err
@@ -168,12 +193,12 @@ func (task *cvsTask) Tick(ctx context.Context) error {
 func (task *cvsTask) Status() libModel.WorkerStatus {
 	stats := &Status{
 		TaskConfig: task.Config,
-		CurrentLoc: task.curLoc,
+		CurrentLoc: task.curLoc.Load(),
 		Count:      task.counter.Load(),
 	}
 	statsBytes, err := json.Marshal(stats)
 	if err != nil {
-		log.L().Panic("get stats error", zap.String("id", task.ID()), zap.Error(err))
+		task.Logger().Panic("get stats error", zap.Error(err))
 	}
 	return libModel.WorkerStatus{
 		Code: task.getStatusCode(), ErrorMessage: "",
@@ -198,10 +223,10 @@ func (task *cvsTask) OnMasterMessage(topic p2p.Topic, message p2p.MessageValue)
 		case libModel.WorkerStatusStopped:
 			task.setStatusCode(libModel.WorkerStatusStopped)
 		default:
-			log.L().Info("FakeWorker: ignore status change state", zap.Int32("state", int32(msg.ExpectState)))
+			task.Logger().Info("FakeWorker: ignore status change state", zap.Int32("state", int32(msg.ExpectState)))
 		}
 	default:
-		log.L().Info("unsupported message", zap.Any("message", message))
+		task.Logger().Info("unsupported message", zap.Any("message", message))
 	}
 
 	return nil
@@ -215,83 +240,6 @@ func (task *cvsTask) CloseImpl(ctx context.Context) error {
 	return nil
 }
 
-func (task *cvsTask) Receive(ctx context.Context) error {
-	conn, err := pool.getConn(task.SrcHost)
-	if err != nil {
-		log.L().Error("cann't connect with the source address ", zap.String("id", task.ID()), zap.Any("message", task.SrcHost))
-		return err
-	}
-	client := pb.NewDataRWServiceClient(conn)
-	reader, err := client.ReadLines(ctx, &pb.ReadLinesRequest{FileIdx: int32(task.Idx), LineNo: []byte(task.StartLoc)})
-	if err != nil {
-		log.L().Error("read data from file failed ", zap.String("id", task.ID()), zap.Error(err))
-		return err
-	}
-	for {
-		reply, err := reader.Recv()
-		if err != nil {
-			log.L().Error("read data failed", zap.String("id", task.ID()), zap.Error(err))
-			if !task.isEOF {
-				task.cancelFn()
-			}
-			return err
-		}
-		if reply.IsEof {
-			log.L().Info("Reach the end of the file ", zap.String("id", task.ID()), zap.Any("fileID", task.Idx))
-			close(task.buffer)
-			break
-		}
-		select {
-		case <-ctx.Done():
-			return nil
-		case task.buffer <- strPair{firstStr: string(reply.Key), secondStr: string(reply.Val)}:
-		}
-		// waiting longer time to read lines slowly
-	}
-	return nil
-}
-
-func (task *cvsTask) send(ctx context.Context) error {
-	conn, err := pool.getConn(task.DstHost)
-	if err != nil {
-		log.L().Error("can't connect with the destination address ", zap.Any("id", task.ID()), zap.Error(err))
-		return err
-	}
-	client := pb.NewDataRWServiceClient(conn)
-	writer, err := client.WriteLines(ctx)
-	if err != nil {
-		log.L().Error("call write data rpc failed", zap.String("id", task.ID()), zap.Error(err))
-		task.cancelFn()
-		return err
-	}
-	for {
-		select {
-		case kv, more := <-task.buffer:
-			if !more {
-				log.L().Info("Reach the end of the file ", zap.String("id", task.ID()), zap.Any("cnt", task.counter.Load()), zap.String("last write", task.curLoc))
-				resp, err := writer.CloseAndRecv()
-				if err != nil {
-					return err
-				}
-				if len(resp.ErrMsg) > 0 {
-					log.L().Warn("close writing meet error", zap.String("id", task.ID()))
-				}
-				return nil
-			}
-			err := writer.Send(&pb.WriteLinesRequest{FileIdx: int32(task.Idx), Key: []byte(kv.firstStr), Value: []byte(kv.secondStr), Dir: task.DstDir})
-			if err != nil {
-				log.L().Error("call write data rpc failed ", zap.String("id", task.ID()), zap.Error(err))
-				task.cancelFn()
-				return err
-			}
-			task.counter.Add(1)
-			task.curLoc = kv.firstStr
-		case <-ctx.Done():
-			return ctx.Err()
-		}
-	}
-}
-
 func (task *cvsTask) getStatusCode() libModel.WorkerStatusCode {
 	task.statusCode.RLock()
 	defer task.statusCode.RUnlock()
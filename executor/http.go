@@ -1,6 +1,9 @@
 package executor
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"net"
 	"net/http"
 	"net/http/pprof"
@@ -8,9 +11,15 @@ import (
 	"github.com/pingcap/tiflow/dm/dm/common"
 	"github.com/pingcap/tiflow/dm/pkg/log"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+
+	"github.com/hanfei1991/microcosm/executor/worker"
+	"github.com/hanfei1991/microcosm/lib"
+	"github.com/hanfei1991/microcosm/lib/master"
+	"github.com/hanfei1991/microcosm/pkg/traffic"
 )
 
-func httpHandler(lis net.Listener) error {
+func httpHandler(lis net.Listener, accountant *traffic.Accountant, taskRunner *worker.TaskRunner) error {
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("/debug/pprof/", pprof.Index)
@@ -19,6 +28,8 @@ func httpHandler(lis net.Listener) error {
 	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
 	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
 	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/api/v1/jobs/traffic", jobTrafficHandler(accountant))
+	mux.HandleFunc("/api/v1/jobs/snapshot", jobSnapshotHandler(taskRunner))
 
 	httpS := &http.Server{
 		Handler: mux,
@@ -29,3 +40,87 @@ func httpHandler(lis net.Listener) error {
 	}
 	return err
 }
+
+// jobTrafficHandler returns the traffic of jobs on this executor in json.
+// If `job-id` is given in the query, only the traffic of that job is returned.
+func jobTrafficHandler(accountant *traffic.Accountant) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var ret interface{}
+		if jobID := r.URL.Query().Get("job-id"); jobID != "" {
+			stat, ok := accountant.Stat(jobID)
+			if !ok {
+				http.Error(w, "job not found", http.StatusNotFound)
+				return
+			}
+			ret = stat
+		} else {
+			ret = accountant.Stats()
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(ret); err != nil {
+			log.L().Warn("failed to write job traffic", zap.Error(err))
+		}
+	}
+}
+
+type jobSnapshotter interface {
+	JobSnapshot(ctx context.Context) (*lib.JobSnapshot, error)
+}
+
+// jobSnapshotHandler exports a snapshot of the job master running on this
+// executor as a json file, the job is given by `job-id` in the query.
+func jobSnapshotHandler(taskRunner *worker.TaskRunner) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		jobID := r.URL.Query().Get("job-id")
+		if jobID == "" {
+			http.Error(w, "job-id is required", http.StatusBadRequest)
+			return
+		}
+		task, ok := taskRunner.GetTask(jobID)
+		if !ok {
+			http.Error(w, "job master not found on this executor", http.StatusNotFound)
+			return
+		}
+		snapshotter, ok := task.(jobSnapshotter)
+		if !ok {
+			http.Error(w, "job snapshot is not supported by the task", http.StatusNotImplemented)
+			return
+		}
+		snapshot, err := snapshotter.JobSnapshot(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition",
+			fmt.Sprintf("attachment; filename=job-%s-snapshot.json", jobID))
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(snapshot); err != nil {
+			log.L().Warn("failed to write job snapshot", zap.String("job-id", jobID), zap.Error(err))
+		}
+	}
+}
+
+type workerManagerDumper interface {
+	WorkerManagerDebugInfo() *master.DebugInfo
+}
+
+// dumpMaster implements adminserver.MasterDumper, it dumps the WorkerManager
+// of a job master running on this executor.
+func (s *Server) dumpMaster(masterID string) (interface{}, bool) {
+	if s.taskRunner == nil {
+		return nil, false
+	}
+	task, ok := s.taskRunner.GetTask(masterID)
+	if !ok {
+		return nil, false
+	}
+	dumper, ok := task.(workerManagerDumper)
+	if !ok {
+		return nil, false
+	}
+	return dumper.WorkerManagerDebugInfo(), true
+}
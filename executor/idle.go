@@ -0,0 +1,52 @@
+package executor
+
+import (
+	"sync"
+	"time"
+)
+
+// idleTracker decides whether the executor is idle-evictable, that is, it
+// has run no worker for the idle timeout. External autoscalers or the drain
+// workflow can remove an idle-evictable executor safely.
+type idleTracker struct {
+	timeout time.Duration
+
+	mu sync.Mutex
+	// busyAt is the last time the executor has workers or receives a task
+	busyAt time.Time
+}
+
+// newIdleTracker creates an idleTracker, the executor is never evictable if
+// timeout is not positive.
+func newIdleTracker(timeout time.Duration, now time.Time) *idleTracker {
+	return &idleTracker{
+		timeout: timeout,
+		busyAt:  now,
+	}
+}
+
+// markBusy is called when a task is dispatched to the executor, so that the
+// executor stops being evictable before the task starts running.
+func (t *idleTracker) markBusy(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if now.After(t.busyAt) {
+		t.busyAt = now
+	}
+}
+
+// evictable returns whether the executor is idle-evictable with taskCount
+// workers running at now.
+func (t *idleTracker) evictable(taskCount int64, now time.Time) bool {
+	if t.timeout <= 0 {
+		return false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if taskCount > 0 {
+		t.busyAt = now
+		return false
+	}
+	return now.Sub(t.busyAt) >= t.timeout
+}
@@ -0,0 +1,31 @@
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdleTracker(t *testing.T) {
+	t.Parallel()
+
+	start := time.Now()
+	tracker := newIdleTracker(time.Minute, start)
+	require.False(t, tracker.evictable(0, start.Add(59*time.Second)))
+	require.True(t, tracker.evictable(0, start.Add(time.Minute)))
+
+	// running workers make the executor busy again
+	require.False(t, tracker.evictable(1, start.Add(2*time.Minute)))
+	require.False(t, tracker.evictable(0, start.Add(2*time.Minute+59*time.Second)))
+	require.True(t, tracker.evictable(0, start.Add(3*time.Minute)))
+
+	// a dispatched task makes the executor busy before it runs
+	tracker.markBusy(start.Add(4 * time.Minute))
+	require.False(t, tracker.evictable(0, start.Add(4*time.Minute+30*time.Second)))
+	require.True(t, tracker.evictable(0, start.Add(5*time.Minute)))
+
+	// disabled
+	tracker = newIdleTracker(0, start)
+	require.False(t, tracker.evictable(0, start.Add(time.Hour)))
+}
@@ -2,6 +2,7 @@ package executor
 
 import (
 	_ "github.com/hanfei1991/microcosm/dm" // register dm
+	connectortask "github.com/hanfei1991/microcosm/executor/connectorTask"
 	cvstask "github.com/hanfei1991/microcosm/executor/cvsTask"
 	cvs "github.com/hanfei1991/microcosm/jobmaster/cvsJob"
 	"github.com/hanfei1991/microcosm/jobmaster/dm"
@@ -10,6 +11,7 @@ import (
 
 func init() {
 	cvstask.RegisterWorker()
+	connectortask.RegisterWorker()
 	cvs.RegisterWorker()
 	dm.RegisterWorker()
 	registry.RegisterFake(registry.GlobalWorkerRegistry())
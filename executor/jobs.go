@@ -0,0 +1,66 @@
+package executor
+
+import (
+	"sync"
+
+	"github.com/hanfei1991/microcosm/executor/worker"
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+)
+
+// jobTracker tracks the jobs having tasks running on the executor, so that
+// the states kept for a job, such as its traffic and its shared cache, are
+// released after the last task of the job stops.
+type jobTracker struct {
+	mu sync.Mutex
+	// taskJobs maps the tasks made to their jobs
+	taskJobs map[worker.RunnableID]libModel.MasterID
+	// running is the number of running tasks of each job
+	running map[libModel.MasterID]int
+
+	onJobExited func(jobID libModel.MasterID)
+}
+
+func newJobTracker(onJobExited func(jobID libModel.MasterID)) *jobTracker {
+	return &jobTracker{
+		taskJobs:    make(map[worker.RunnableID]libModel.MasterID),
+		running:     make(map[libModel.MasterID]int),
+		onJobExited: onJobExited,
+	}
+}
+
+// bind records the job of a task when the task is made.
+func (t *jobTracker) bind(taskID worker.RunnableID, jobID libModel.MasterID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.taskJobs[taskID] = jobID
+}
+
+// OnTaskStarted implements worker.TaskListener.OnTaskStarted
+func (t *jobTracker) OnTaskStarted(taskID worker.RunnableID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if jobID, ok := t.taskJobs[taskID]; ok {
+		t.running[jobID]++
+	}
+}
+
+// OnTaskStopped implements worker.TaskListener.OnTaskStopped
+func (t *jobTracker) OnTaskStopped(taskID worker.RunnableID) {
+	t.mu.Lock()
+	jobID, ok := t.taskJobs[taskID]
+	if !ok {
+		t.mu.Unlock()
+		return
+	}
+	delete(t.taskJobs, taskID)
+	t.running[jobID]--
+	exited := t.running[jobID] <= 0
+	if exited {
+		delete(t.running, jobID)
+	}
+	t.mu.Unlock()
+
+	if exited {
+		t.onJobExited(jobID)
+	}
+}
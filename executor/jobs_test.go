@@ -0,0 +1,36 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+)
+
+func TestJobTracker(t *testing.T) {
+	t.Parallel()
+
+	var exited []libModel.MasterID
+	tracker := newJobTracker(func(jobID libModel.MasterID) {
+		exited = append(exited, jobID)
+	})
+
+	tracker.bind("job-1", "job-1")
+	tracker.bind("worker-1", "job-1")
+	tracker.bind("worker-2", "job-2")
+	tracker.OnTaskStarted("job-1")
+	tracker.OnTaskStarted("worker-1")
+	tracker.OnTaskStarted("worker-2")
+
+	// the job exits after all its tasks stop
+	tracker.OnTaskStopped("worker-1")
+	require.Empty(t, exited)
+	tracker.OnTaskStopped("job-1")
+	require.Equal(t, []libModel.MasterID{"job-1"}, exited)
+
+	// tasks not made by the executor are ignored
+	tracker.OnTaskStopped("unknown")
+	tracker.OnTaskStopped("worker-2")
+	require.Equal(t, []libModel.MasterID{"job-1", "job-2"}, exited)
+}
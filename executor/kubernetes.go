@@ -0,0 +1,136 @@
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/hanfei1991/microcosm/model"
+	"github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+// BootstrapModeKubernetes means the executor runs in a kubernetes pod, and
+// registers with the pod metadata exposed by the downward API.
+const BootstrapModeKubernetes = "kubernetes"
+
+// The environment variables set by the downward API, for example
+//
+//	env:
+//	- name: POD_NAME
+//	  valueFrom:
+//	    fieldRef:
+//	      fieldPath: metadata.name
+const (
+	envPodName      = "POD_NAME"
+	envPodNamespace = "POD_NAMESPACE"
+	envPodIP        = "POD_IP"
+	envNodeName     = "NODE_NAME"
+	// envZone overrides the zone read from the pod labels, since the zone
+	// label of the node is not exposed by the downward API.
+	envZone = "ZONE"
+)
+
+const (
+	defaultPodLabelsFile = "/etc/podinfo/labels"
+	defaultZoneLabel     = "topology.kubernetes.io/zone"
+)
+
+// KubernetesConfig is the configuration of the kubernetes bootstrap mode
+type KubernetesConfig struct {
+	// PodLabelsFile is the path the pod labels are mounted to by a downward
+	// API volume, the zone is read from it if it exists.
+	PodLabelsFile string `toml:"pod-labels-file" json:"pod-labels-file"`
+	// ZoneLabel is the pod label of the zone.
+	ZoneLabel string `toml:"zone-label" json:"zone-label"`
+}
+
+// adjustKubernetes fills the labels, name and advertise address of the
+// executor by the pod metadata.
+func (c *Config) adjustKubernetes(getenv func(string) string) error {
+	if c.Kubernetes.PodLabelsFile == "" {
+		c.Kubernetes.PodLabelsFile = defaultPodLabelsFile
+	}
+	if c.Kubernetes.ZoneLabel == "" {
+		c.Kubernetes.ZoneLabel = defaultZoneLabel
+	}
+
+	podName := getenv(envPodName)
+	if podName == "" {
+		return errors.ErrExecutorBootstrapFailed.GenWithStackByArgs(
+			BootstrapModeKubernetes, envPodName+" is not set by the downward API")
+	}
+	labels := make(map[string]string, len(c.Labels)+4)
+	for name, value := range c.Labels {
+		labels[name] = value
+	}
+	labels[model.LabelPodName] = podName
+	setIfNotEmpty(labels, model.LabelPodNamespace, getenv(envPodNamespace))
+	setIfNotEmpty(labels, model.LabelNodeName, getenv(envNodeName))
+
+	zone := getenv(envZone)
+	if zone == "" {
+		podLabels, err := readPodLabels(c.Kubernetes.PodLabelsFile)
+		if err != nil {
+			return err
+		}
+		zone = podLabels[c.Kubernetes.ZoneLabel]
+	}
+	setIfNotEmpty(labels, model.LabelZone, zone)
+	c.Labels = labels
+
+	if c.Name == "" {
+		c.Name = podName
+	}
+	// the pod IP is advertised if the executor listens on all interfaces
+	if podIP := getenv(envPodIP); podIP != "" && c.AdvertiseAddr == "" {
+		host, port, err := net.SplitHostPort(c.WorkerAddr)
+		if err == nil && (host == "" || host == "0.0.0.0" || host == "::") {
+			c.AdvertiseAddr = net.JoinHostPort(podIP, port)
+		}
+	}
+	return nil
+}
+
+func setIfNotEmpty(labels map[string]string, name, value string) {
+	if value != "" {
+		labels[name] = value
+	}
+}
+
+// readPodLabels reads the pod labels in the format of the downward API, that
+// is, a `name="value"` pair per line. No label is returned if the file
+// doesn't exist.
+func readPodLabels(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.ErrExecutorBootstrapFailed.GenWithStackByArgs(
+			BootstrapModeKubernetes, err.Error())
+	}
+
+	labels := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		name, quoted, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, errors.ErrExecutorBootstrapFailed.GenWithStackByArgs(
+				BootstrapModeKubernetes, "invalid pod label "+line)
+		}
+		value, err := strconv.Unquote(quoted)
+		if err != nil {
+			return nil, errors.ErrExecutorBootstrapFailed.GenWithStackByArgs(
+				BootstrapModeKubernetes, "invalid pod label "+line)
+		}
+		labels[name] = value
+	}
+	return labels, nil
+}
@@ -0,0 +1,75 @@
+package executor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hanfei1991/microcosm/model"
+	"github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+func TestAdjustKubernetes(t *testing.T) {
+	t.Parallel()
+
+	labelsFile := filepath.Join(t.TempDir(), "labels")
+	err := os.WriteFile(labelsFile, []byte(
+		"app=\"executor\"\ntopology.kubernetes.io/zone=\"us-east-1a\"\n"), 0o644)
+	require.NoError(t, err)
+	env := map[string]string{
+		envPodName:      "executor-0",
+		envPodNamespace: "dataflow",
+		envNodeName:     "node-1",
+		envPodIP:        "10.0.0.1",
+	}
+	getenv := func(name string) string {
+		return env[name]
+	}
+
+	cfg := NewConfig()
+	cfg.WorkerAddr = "0.0.0.0:10241"
+	cfg.Labels = map[string]string{"rack": "rack-1"}
+	cfg.Kubernetes.PodLabelsFile = labelsFile
+	require.NoError(t, cfg.adjustKubernetes(getenv))
+	require.Equal(t, map[string]string{
+		"rack":                  "rack-1",
+		model.LabelPodName:      "executor-0",
+		model.LabelPodNamespace: "dataflow",
+		model.LabelNodeName:     "node-1",
+		model.LabelZone:         "us-east-1a",
+	}, cfg.Labels)
+	require.Equal(t, "executor-0", cfg.Name)
+	require.Equal(t, "10.0.0.1:10241", cfg.AdvertiseAddr)
+
+	// the zone in env overrides the pod label, and the advertise address
+	// in config is kept
+	env[envZone] = "us-east-1b"
+	cfg = NewConfig()
+	cfg.WorkerAddr = "0.0.0.0:10241"
+	cfg.AdvertiseAddr = "executor-0.executor:10241"
+	cfg.Kubernetes.PodLabelsFile = labelsFile
+	require.NoError(t, cfg.adjustKubernetes(getenv))
+	require.Equal(t, "us-east-1b", cfg.Labels[model.LabelZone])
+	require.Equal(t, "executor-0.executor:10241", cfg.AdvertiseAddr)
+
+	// the zone is unknown without the pod labels
+	delete(env, envZone)
+	cfg = NewConfig()
+	cfg.Kubernetes.PodLabelsFile = filepath.Join(t.TempDir(), "not-exist")
+	require.NoError(t, cfg.adjustKubernetes(getenv))
+	require.NotContains(t, cfg.Labels, model.LabelZone)
+
+	err = os.WriteFile(labelsFile, []byte("zone=us-east-1a\n"), 0o644)
+	require.NoError(t, err)
+	cfg = NewConfig()
+	cfg.Kubernetes.PodLabelsFile = labelsFile
+	err = cfg.adjustKubernetes(getenv)
+	require.True(t, errors.ErrExecutorBootstrapFailed.Equal(err))
+
+	delete(env, envPodName)
+	cfg = NewConfig()
+	err = cfg.adjustKubernetes(getenv)
+	require.True(t, errors.ErrExecutorBootstrapFailed.Equal(err))
+}
@@ -2,6 +2,17 @@ package executor
 
 import (
 	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/hanfei1991/microcosm/executor/subprocess"
+	"github.com/hanfei1991/microcosm/lib"
+	"github.com/hanfei1991/microcosm/lib/master"
+	"github.com/hanfei1991/microcosm/pkg/eventbus"
+	"github.com/hanfei1991/microcosm/pkg/interceptor"
+	"github.com/hanfei1991/microcosm/pkg/lockdiag"
+	"github.com/hanfei1991/microcosm/pkg/notifier"
+	"github.com/hanfei1991/microcosm/pkg/p2p"
+	"github.com/hanfei1991/microcosm/pkg/sink"
+	"github.com/hanfei1991/microcosm/pkg/traffic"
 )
 
 var executorTaskNumGauge = prometheus.NewGaugeVec(
@@ -15,4 +26,14 @@ var executorTaskNumGauge = prometheus.NewGaugeVec(
 // initServerMetrics registers statistics of executor server
 func initServerMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(executorTaskNumGauge)
+	traffic.InitMetrics(registry)
+	notifier.InitMetrics(registry)
+	eventbus.InitMetrics(registry)
+	p2p.InitMetrics(registry)
+	lib.InitMetrics(registry)
+	master.InitMetrics(registry)
+	lockdiag.InitMetrics(registry)
+	interceptor.InitMetrics(registry)
+	sink.InitMetrics(registry)
+	subprocess.InitMetrics(registry)
 }
@@ -0,0 +1,146 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	"github.com/hanfei1991/microcosm/pb"
+	"github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+const (
+	selfTestKey           = "/executor-self-test"
+	clockDriftProbePeriod = 10 * time.Millisecond
+	maxClockDrift         = time.Second
+)
+
+// minSaneTime is a time before the executor could have been built, a wall
+// clock earlier than it is surely wrong.
+var minSaneTime = time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// selfTest is a check the executor runs before registering to server master,
+// so that a broken executor refuses to join the cluster instead of failing
+// dispatched tasks later.
+type selfTest struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+func (s *Server) selfTests() []selfTest {
+	return []selfTest{
+		{
+			name: "resource-dir",
+			check: func(_ context.Context) error {
+				return checkResourceDir(defaultLocalResourceDir)
+			},
+		},
+		{
+			name: "clock",
+			check: func(_ context.Context) error {
+				return checkClock()
+			},
+		},
+		{
+			name: "p2p-port",
+			check: func(_ context.Context) error {
+				return checkPortBindable(s.cfg.WorkerAddr)
+			},
+		},
+		{name: "master", check: s.checkMaster},
+		{name: "metastore", check: s.checkMetaStore},
+	}
+}
+
+// runSelfTests runs all self-tests, and returns an error containing the
+// diagnostic of every failed one.
+func (s *Server) runSelfTests(ctx context.Context) error {
+	var failures []string
+	for _, st := range s.selfTests() {
+		if err := st.check(ctx); err != nil {
+			log.L().Error("executor self-test failed",
+				zap.String("test", st.name), zap.Error(err))
+			failures = append(failures, fmt.Sprintf("%s: %s", st.name, err.Error()))
+			continue
+		}
+		log.L().Info("executor self-test passed", zap.String("test", st.name))
+	}
+	if len(failures) > 0 {
+		return errors.ErrExecutorSelfTestFailed.GenWithStackByArgs(strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// checkResourceDir checks that local file resources can be created and
+// removed in dir.
+func checkResourceDir(dir string) error {
+	f, err := os.CreateTemp(dir, ".executor-self-test-*")
+	if err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Remove(f.Name())
+}
+
+// checkClock checks that the wall clock is sane and doesn't jump, since
+// heartbeats and TTLs rely on it.
+func checkClock() error {
+	start := time.Now()
+	if start.Before(minSaneTime) {
+		return fmt.Errorf("wall clock %s is earlier than %s", start, minSaneTime)
+	}
+	time.Sleep(clockDriftProbePeriod)
+	end := time.Now()
+	// Round(0) strips the monotonic clock reading.
+	wallElapsed := end.Round(0).Sub(start.Round(0))
+	drift := wallElapsed - end.Sub(start)
+	if drift > maxClockDrift || drift < -maxClockDrift {
+		return fmt.Errorf("wall clock drifts %s from monotonic clock", drift)
+	}
+	return nil
+}
+
+// checkPortBindable checks that the address used by gRPC and p2p messaging
+// is not occupied.
+func checkPortBindable(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return l.Close()
+}
+
+// checkMaster checks that server master is reachable and ready.
+func (s *Server) checkMaster(ctx context.Context) error {
+	_, err := s.masterClient.QueryMetaStore(
+		ctx,
+		&pb.QueryMetaStoreRequest{Tp: pb.StoreType_ServiceDiscovery},
+		s.cfg.RPCTimeout,
+	)
+	return err
+}
+
+// checkMetaStore connects to metastores and checks they can be read.
+func (s *Server) checkMetaStore(ctx context.Context) error {
+	if err := s.fetchMetaStore(ctx); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.RPCTimeout)
+	defer cancel()
+	if _, err := s.frameMetaClient.QueryProjects(ctx); err != nil {
+		return errors.ErrMetaOpFail.Wrap(err).GenWithStack("read framework metastore")
+	}
+	if _, err := s.userRawKVClient.Get(ctx, selfTestKey); err != nil {
+		return errors.ErrMetaOpFail.Wrap(err).GenWithStack("read user metastore")
+	}
+	return nil
+}
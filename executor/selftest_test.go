@@ -0,0 +1,33 @@
+package executor
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/phayes/freeport"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelfTestChecks(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, checkResourceDir(dir))
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 0)
+	require.Error(t, checkResourceDir(dir+"/not-exist"))
+
+	require.NoError(t, checkClock())
+
+	port, err := freeport.GetFreePort()
+	require.NoError(t, err)
+	addr := fmt.Sprintf("127.0.0.1:%d", port)
+	require.NoError(t, checkPortBindable(addr))
+	l, err := net.Listen("tcp", addr)
+	require.NoError(t, err)
+	defer l.Close()
+	require.Error(t, checkPortBindable(addr))
+}
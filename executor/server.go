@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"strings"
+	"sync"
 	"time"
 
 	pcErrors "github.com/pingcap/errors"
@@ -14,6 +15,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/tcpserver"
 	"go.etcd.io/etcd/client/pkg/v3/logutil"
 	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/atomic"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"golang.org/x/sync/errgroup"
@@ -24,24 +26,42 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/hanfei1991/microcosm/client"
+	"github.com/hanfei1991/microcosm/executor/subprocess"
 	"github.com/hanfei1991/microcosm/executor/worker"
+	"github.com/hanfei1991/microcosm/lib/metadata"
 	libModel "github.com/hanfei1991/microcosm/lib/model"
 	"github.com/hanfei1991/microcosm/lib/registry"
 	"github.com/hanfei1991/microcosm/model"
 	"github.com/hanfei1991/microcosm/pb"
+	"github.com/hanfei1991/microcosm/pkg/adminserver"
+	"github.com/hanfei1991/microcosm/pkg/compat"
 	"github.com/hanfei1991/microcosm/pkg/config"
 	dcontext "github.com/hanfei1991/microcosm/pkg/context"
 	"github.com/hanfei1991/microcosm/pkg/deps"
 	"github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/eventbus"
 	"github.com/hanfei1991/microcosm/pkg/externalresource/broker"
+	resModel "github.com/hanfei1991/microcosm/pkg/externalresource/resourcemeta/model"
 	"github.com/hanfei1991/microcosm/pkg/externalresource/storagecfg"
+	"github.com/hanfei1991/microcosm/pkg/faultinject"
+	"github.com/hanfei1991/microcosm/pkg/interceptor"
+	"github.com/hanfei1991/microcosm/pkg/lockdiag"
+	dlogutil "github.com/hanfei1991/microcosm/pkg/logutil"
+	"github.com/hanfei1991/microcosm/pkg/meta/encryption"
 	extkv "github.com/hanfei1991/microcosm/pkg/meta/extension"
 	"github.com/hanfei1991/microcosm/pkg/meta/kvclient"
 	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
 	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+	"github.com/hanfei1991/microcosm/pkg/orm/migration"
 	"github.com/hanfei1991/microcosm/pkg/p2p"
+	"github.com/hanfei1991/microcosm/pkg/redact"
 	"github.com/hanfei1991/microcosm/pkg/rpcutil"
+	"github.com/hanfei1991/microcosm/pkg/secret"
 	"github.com/hanfei1991/microcosm/pkg/serverutils"
+	"github.com/hanfei1991/microcosm/pkg/sharedcache"
+	"github.com/hanfei1991/microcosm/pkg/sink"
+	"github.com/hanfei1991/microcosm/pkg/tenant"
+	"github.com/hanfei1991/microcosm/pkg/traffic"
 	"github.com/hanfei1991/microcosm/test"
 	"github.com/hanfei1991/microcosm/test/mock"
 )
@@ -60,8 +80,18 @@ type Server struct {
 	taskCommitter  *worker.TaskCommitter
 	msgServer      *p2p.MessageRPCService
 	info           *model.NodeInfo
+	// workerPool is nil unless warm pools are configured
+	workerPool *subprocess.Pool
 
 	lastHearbeatTime time.Time
+	// status is the model.ExecutorStatus reported to server master
+	status atomic.Int32
+	// clusterVersion is the protocol version negotiated by server master,
+	// features newer than it are disabled during a rolling upgrade.
+	clusterVersion atomic.Int32
+
+	shutdownOnce sync.Once
+	shutdownCh   chan struct{}
 
 	mockSrv mock.GrpcServer
 
@@ -70,11 +100,42 @@ type Server struct {
 	etcdCli *clientv3.Client
 	// framework metastore client
 	frameMetaClient pkgOrm.Client
+	// secrets resolves the secret references in the env of workers
+	secrets *secret.Store
 	// user metastore raw kvclient(reuse for all workers)
 	userRawKVClient extkv.KVClientEx
+	// frameMetaFollower makes frameMetaClient follow the migration of the
+	// framework metastore, its config is passed to worker processes
+	frameMetaFollower *migration.Follower
+	// user metastore config, passed to worker processes which connect
+	// to the metastores on their own
+	userMetaConf    metaclient.StoreConfigParams
 	p2pMsgRouter    p2pImpl.MessageRouter
+	peerCodecs      *p2p.PeerCodecs
 	discoveryKeeper *serverutils.DiscoveryKeepaliver
 	resourceBroker  broker.Broker
+	// trafficAccountant accounts network traffic of workers per job
+	trafficAccountant *traffic.Accountant
+	// sharedCache is shared by workers on this executor, isolated by job
+	sharedCache *sharedcache.Cache
+	// clientManager caches the executor clients used by job masters on this
+	// executor, which are invalidated by service discovery events.
+	clientManager *client.Manager
+	// sinkExporter exports the events of job masters on this executor, it
+	// is nil if no sink is configured.
+	sinkExporter *sink.Exporter
+	// eventBus delivers the events of job masters on this executor to the
+	// subsystems interested in them.
+	eventBus *eventbus.Bus
+	// statusBatcher persists the statuses of workers on this executor in
+	// batches, it is nil if status batching is disabled.
+	statusBatcher *metadata.WorkerStatusBatcher
+
+	idleTracker *idleTracker
+	jobTracker  *jobTracker
+	// idleEvictable is the latest idle-evictable state reported, it is only
+	// accessed in the heartbeat loop.
+	idleEvictable bool
 }
 
 // NewServer creates a new executor server instance
@@ -83,42 +144,62 @@ func NewServer(cfg *Config, ctx *test.Context) *Server {
 		cfg:         cfg,
 		testCtx:     ctx,
 		cliUpdateCh: make(chan cliUpdateInfo),
+		trafficAccountant: traffic.NewAccountant(
+			cfg.JobTrafficSoftLimit, cfg.EnforceJobTrafficSoftLimit),
+		sharedCache:   sharedcache.NewCache(cfg.SharedCacheCapacity),
+		clientManager: client.NewClientManager(),
+		peerCodecs:    p2p.NewPeerCodecs(),
+		idleTracker:   newIdleTracker(cfg.IdleEvictTimeout, time.Now()),
+		eventBus:      eventbus.NewBus(),
+		shutdownCh:    make(chan struct{}),
 	}
+	s.jobTracker = newJobTracker(s.onJobExited)
+	s.status.Store(int32(model.Running))
 	return &s
 }
 
-func (s *Server) buildDeps() (*deps.Deps, error) {
+// onJobExited releases the states of a job after its last task on the
+// executor stops.
+func (s *Server) onJobExited(jobID libModel.MasterID) {
+	s.trafficAccountant.RemoveJob(jobID)
+	s.sharedCache.DropNamespace(jobID)
+}
+
+func (s *Server) buildDeps(jobID libModel.MasterID) (*deps.Deps, error) {
 	deps := deps.NewDeps()
 	err := deps.Provide(func() p2p.MessageHandlerManager {
-		return s.msgServer.MakeHandlerManager()
+		return traffic.NewMessageHandlerManager(
+			s.msgServer.MakeHandlerManager(), s.trafficAccountant, jobID)
 	})
 	if err != nil {
 		return nil, err
 	}
 
 	err = deps.Provide(func() p2p.MessageSender {
-		return p2p.NewMessageSender(s.p2pMsgRouter)
+		return traffic.NewMessageSender(
+			p2p.NewMessageSenderWithCodecs(s.p2pMsgRouter, &s.cfg.P2PMessage, s.peerCodecs),
+			s.trafficAccountant, jobID)
 	})
 	if err != nil {
 		return nil, err
 	}
 
 	err = deps.Provide(func() pkgOrm.Client {
-		return s.frameMetaClient
+		return traffic.NewFrameMetaClient(s.frameMetaClient, s.trafficAccountant, jobID)
 	})
 	if err != nil {
 		return nil, err
 	}
 
 	err = deps.Provide(func() extkv.KVClientEx {
-		return s.userRawKVClient
+		return traffic.NewKVClient(s.userRawKVClient, s.trafficAccountant, jobID)
 	})
 	if err != nil {
 		return nil, err
 	}
 
 	err = deps.Provide(func() client.ClientsManager {
-		return client.NewClientManager()
+		return s.clientManager
 	})
 	if err != nil {
 		return nil, err
@@ -138,37 +219,144 @@ func (s *Server) buildDeps() (*deps.Deps, error) {
 		return nil, err
 	}
 
+	err = deps.Provide(func() sharedcache.Client {
+		return s.sharedCache.Client(jobID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if s.sinkExporter != nil {
+		err = deps.Provide(func() *sink.Exporter {
+			return s.sinkExporter
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = deps.Provide(func() *eventbus.Bus {
+		return s.eventBus
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if keyring := s.cfg.UserMetaEncryption.Keyring(); keyring != nil {
+		err = deps.Provide(func() *encryption.Keyring {
+			return keyring
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if s.statusBatcher != nil {
+		err = deps.Provide(func() *metadata.WorkerStatusBatcher {
+			return s.statusBatcher
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if s.testCtx != nil && s.testCtx.FaultInjector() != nil {
+		err = deps.Provide(func() *faultinject.Injector {
+			return s.testCtx.FaultInjector()
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return deps, nil
 }
 
 func (s *Server) makeTask(
 	ctx context.Context,
-	workerID libModel.WorkerID,
-	masterID libModel.MasterID,
-	workerType libModel.WorkerType,
-	workerConfig []byte,
+	req *pb.PreDispatchTaskRequest,
 ) (worker.Runnable, error) {
-	dctx := dcontext.NewContext(ctx, log.L())
-	dp, err := s.buildDeps()
+	var (
+		workerID     = req.GetWorkerId()
+		masterID     = req.GetMasterId()
+		workerType   = libModel.WorkerType(req.GetTaskTypeId())
+		workerConfig = req.GetTaskConfig()
+	)
+	// The resources are opened by the worker at startup, the malformed
+	// ones are rejected here as the broker doesn't expect them.
+	for _, resourceID := range req.GetResourceIds() {
+		if _, _, err := resModel.ParseResourcePath(resourceID); err != nil {
+			return nil, err
+		}
+	}
+	projectInfo := tenant.ProjectInfo{
+		TenantID:  req.GetTenantId(),
+		ProjectID: req.GetProjectId(),
+	}
+	// The secrets are resolved on dispatching, so that the plaintext is
+	// only passed to the worker and never persisted or logged.
+	env, err := s.secrets.ResolveEnv(ctx, projectInfo, req.GetEnv())
 	if err != nil {
 		return nil, err
 	}
-	dctx = dctx.WithDeps(dp)
-	dctx.Environ.NodeID = p2p.NodeID(s.info.ID)
-	dctx.Environ.Addr = s.info.Addr
 
 	// NOTICE: only take effect when job type is job master
 	masterMeta := &libModel.MasterMetaKVData{
-		// TODO: ProjectID
-		ID:     workerID,
-		Tp:     workerType,
-		Config: workerConfig,
+		ProjectID: projectInfo.ProjectID,
+		ID:        workerID,
+		Tp:        workerType,
+		Config:    workerConfig,
 	}
 	metaBytes, err := masterMeta.Marshal()
 	if err != nil {
 		return nil, err
 	}
+
+	// The job ID of a job master is its worker ID, while workers
+	// belong to the job of their master.
+	jobID := masterID
+	if masterID == metadata.JobManagerUUID {
+		jobID = workerID
+	}
+	s.jobTracker.bind(workerID, jobID)
+
+	if s.cfg.isWorkerIsolated(workerType) || subprocess.IsPluginWorkerType(workerType) {
+		spec := s.workerProcessEnv()
+		spec.WorkerID = workerID
+		spec.MasterID = masterID
+		spec.WorkerType = workerType
+		spec.WorkerConfig = workerConfig
+		spec.MasterMetaBytes = metaBytes
+		spec.Env = env
+		spec.ResourceIDs = req.GetResourceIds()
+		spec.TenantID = projectInfo.TenantID
+		spec.ProjectID = projectInfo.ProjectID
+		handlerManager := traffic.NewMessageHandlerManager(
+			s.msgServer.MakeHandlerManager(), s.trafficAccountant, jobID)
+		messageSender := traffic.NewMessageSender(
+			p2p.NewMessageSenderWithCodecs(s.p2pMsgRouter, &s.cfg.P2PMessage, s.peerCodecs),
+			s.trafficAccountant, jobID)
+		recordTraffic := func(stat traffic.Stat) {
+			s.trafficAccountant.RecordStat(jobID, traffic.SourceMetastore, stat)
+		}
+		if warm := s.workerPool.Take(&spec, handlerManager, messageSender, recordTraffic); warm != nil {
+			return warm, nil
+		}
+		return subprocess.NewRunnable(&spec, handlerManager, messageSender, recordTraffic), nil
+	}
+
+	dctx := dcontext.NewContext(ctx, log.L())
+	dp, err := s.buildDeps(jobID)
+	if err != nil {
+		return nil, err
+	}
+	dctx = dctx.WithDeps(dp)
+	dctx.Environ.NodeID = p2p.NodeID(s.info.ID)
+	dctx.Environ.Addr = s.info.Addr
 	dctx.Environ.MasterMetaBytes = metaBytes
+	dctx.Environ.Env = env
+	dctx.Environ.ResourceIDs = req.GetResourceIds()
+	dctx.Environ.ProjectInfo = projectInfo
 
 	newWorker, err := registry.GlobalWorkerRegistry().CreateWorker(
 		dctx,
@@ -177,20 +365,74 @@ func (s *Server) makeTask(
 		masterID,
 		workerConfig)
 	if err != nil {
-		log.L().Error("Failed to create worker", zap.Error(err))
+		dlogutil.WithWorkerID(dlogutil.WithJobID(log.L(), jobID), workerID).
+			Error("Failed to create worker", zap.Error(err))
 		return nil, err
 	}
 	return newWorker, nil
 }
 
+// storageConfig returns the config of the local file resources on this
+// executor, which is shared by the worker processes.
+func (s *Server) storageConfig() *storagecfg.Config {
+	// TODO: make the prefix configurable later
+	return &storagecfg.Config{Local: &storagecfg.LocalFileConfig{
+		BaseDir:  defaultLocalResourceDir,
+		Quota:    s.cfg.LocalResourceQuota,
+		JobQuota: s.cfg.LocalResourceJobQuota,
+	}}
+}
+
+// workerProcessEnv returns the WorkerSpec with the environment shared by
+// the worker processes on this executor.
+func (s *Server) workerProcessEnv() subprocess.WorkerSpec {
+	var frameMetaConf metaclient.StoreConfigParams
+	if s.frameMetaFollower != nil {
+		frameMetaConf = s.frameMetaFollower.Conf()
+	}
+	return subprocess.WorkerSpec{
+		NodeID:        string(s.info.ID),
+		Addr:          s.info.Addr,
+		Join:          getJoinURLs(s.cfg.Join),
+		FrameMetaConf: frameMetaConf,
+		UserMetaConf:  s.userMetaConf,
+		Storage:       *s.storageConfig(),
+
+		UserMetaEncryption: s.cfg.UserMetaEncryption,
+	}
+}
+
 // PreDispatchTask implements Executor.PreDispatchTask
 func (s *Server) PreDispatchTask(ctx context.Context, req *pb.PreDispatchTaskRequest) (*pb.PreDispatchTaskResponse, error) {
-	task, err := s.makeTask(
-		ctx,
-		req.GetWorkerId(),
-		req.GetMasterId(),
-		libModel.WorkerType(req.GetTaskTypeId()),
-		req.GetTaskConfig())
+	if model.ExecutorStatus(s.status.Load()) != model.Running {
+		return nil, status.Error(codes.Unavailable, "executor is shutting down")
+	}
+	s.idleTracker.markBusy(time.Now())
+
+	// The task is rejected before it is made if the dispatch queue is full,
+	// ResourceExhausted tells the master to schedule it to another executor.
+	if err := s.taskRunner.Admit(s.taskCommitter.PendingCount()); err != nil {
+		log.L().Warn("dispatch queue is full, reject the task",
+			zap.String("worker-id", req.GetWorkerId()),
+			zap.Int64("queue-length", s.taskRunner.QueueLength()))
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+
+	// The scheduler has checked the capacity of the executor, but it may
+	// be reconfigured since then. The cpu dimension is not declared in
+	// the config, it is bounded by the dispatch queue above.
+	required := model.RescVector(req.GetResources()).Clone()
+	delete(required, model.ResourceCPU)
+	if !required.Fits(s.cfg.Resources) {
+		log.L().Warn("executor lacks the resources required, reject the task",
+			zap.String("worker-id", req.GetWorkerId()),
+			zap.Stringer("required", required),
+			zap.Stringer("capacity", s.cfg.Resources))
+		return nil, status.Error(codes.ResourceExhausted,
+			"executor lacks the resources required by the task")
+	}
+
+	task, err := s.makeTask(ctx, req)
 	if err != nil {
 		// We use the code Aborted here per the suggestion in gRPC's documentation
 		// "Use Aborted if the client should retry at a higher-level".
@@ -210,6 +452,7 @@ func (s *Server) PreDispatchTask(ctx context.Context, req *pb.PreDispatchTaskReq
 
 // ConfirmDispatchTask implements Executor.ConfirmDispatchTask
 func (s *Server) ConfirmDispatchTask(ctx context.Context, req *pb.ConfirmDispatchTaskRequest) (*pb.ConfirmDispatchTaskResponse, error) {
+	queued, ahead := s.taskRunner.WouldQueue()
 	ok, err := s.taskCommitter.ConfirmDispatchTask(req.GetRequestId(), req.GetWorkerId())
 	if err != nil {
 		return nil, status.Error(codes.Aborted, err.Error())
@@ -217,7 +460,76 @@ func (s *Server) ConfirmDispatchTask(ctx context.Context, req *pb.ConfirmDispatc
 	if !ok {
 		return nil, status.Error(codes.NotFound, "RequestID not found")
 	}
-	return &pb.ConfirmDispatchTaskResponse{}, nil
+	if queued {
+		return &pb.ConfirmDispatchTaskResponse{
+			State:         pb.DispatchState_DispatchQueued,
+			QueuePosition: ahead,
+		}, nil
+	}
+	return &pb.ConfirmDispatchTaskResponse{State: pb.DispatchState_DispatchStarted}, nil
+}
+
+// Shutdown implements Executor.Shutdown
+func (s *Server) Shutdown(ctx context.Context, req *pb.ShutdownRequest) (*pb.ShutdownResponse, error) {
+	s.GracefulShutdown()
+	return &pb.ShutdownResponse{}, nil
+}
+
+// GracefulShutdown starts a graceful shutdown of the executor and returns
+// immediately, Run returns after the shutdown finishes.
+func (s *Server) GracefulShutdown() {
+	s.shutdownOnce.Do(func() {
+		close(s.shutdownCh)
+	})
+}
+
+// handleShutdown waits for a graceful shutdown request, and then stops
+// accepting new tasks, gives running workers a drain timeout to checkpoint
+// and exit, and deregisters the executor from server master.
+func (s *Server) handleShutdown(ctx context.Context, cancel context.CancelFunc) error {
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-s.shutdownCh:
+	}
+
+	log.L().Info("executor starts to shut down",
+		zap.Duration("drain-timeout", s.cfg.DrainTimeout))
+	// server master is notified by the next heartbeat
+	s.status.Store(int32(model.Draining))
+
+	drainCtx, cancelDrain := context.WithTimeout(ctx, s.cfg.DrainTimeout)
+	defer cancelDrain()
+	if err := s.taskRunner.Drain(drainCtx); err != nil {
+		log.L().Warn("running workers are not drained in time",
+			zap.Int64("task-count", s.taskRunner.TaskCount()), zap.Error(err))
+	}
+
+	s.deregister(ctx)
+	cancel()
+	return nil
+}
+
+// deregister tells server master that the executor is offline, so that server
+// master doesn't need to wait for the heartbeat timeout.
+func (s *Server) deregister(ctx context.Context) {
+	s.status.Store(int32(model.Tombstone))
+	req := &pb.HeartbeatRequest{
+		ExecutorId:      string(s.info.ID),
+		Status:          int32(model.Tombstone),
+		Timestamp:       uint64(time.Now().Unix()),
+		ProtocolVersion: int32(compat.CurrentProtocolVersion),
+	}
+	resp, err := s.masterClient.Heartbeat(ctx, req, s.cfg.RPCTimeout)
+	if err != nil {
+		log.L().Warn("failed to deregister executor", zap.Error(err))
+		return
+	}
+	if resp.Err != nil {
+		log.L().Warn("failed to deregister executor", zap.Stringer("code", resp.Err.GetCode()))
+		return
+	}
+	log.L().Info("deregister executor successful", zap.Any("info", s.info))
 }
 
 // Stop stops all running goroutines and releases resources in Server
@@ -258,11 +570,29 @@ func (s *Server) Stop() {
 		}
 	}
 
+	s.eventBus.Close()
+
+	if s.sinkExporter != nil {
+		err := s.sinkExporter.Close()
+		if err != nil {
+			log.L().Warn("failed to close sink exporter", zap.Error(err))
+		}
+	}
+
 	if s.mockSrv != nil {
 		s.mockSrv.Stop()
 	}
 }
 
+// ExecutorID returns the executor ID allocated by server master, it is
+// empty before the executor registers.
+func (s *Server) ExecutorID() model.ExecutorID {
+	if s.info == nil {
+		return ""
+	}
+	return s.info.ID
+}
+
 func (s *Server) startForTest(ctx context.Context) (err error) {
 	s.mockSrv, err = mock.NewExecutorServer(s.cfg.WorkerAddr, s)
 	if err != nil {
@@ -289,6 +619,7 @@ func (s *Server) startMsgService(ctx context.Context, wg *errgroup.Group) (err e
 	if err != nil {
 		return err
 	}
+	s.msgServer.SetMessageConfig(&s.cfg.P2PMessage)
 	wg.Go(func() error {
 		// TODO refactor this
 		return s.msgServer.Serve(ctx, nil)
@@ -303,6 +634,7 @@ const (
 	defaultRuntimeIncomingQueueLen   = 256
 	defaultRuntimeInitConcurrency    = 256
 	defaultTaskPreDispatchRequestTTL = 10 * time.Second
+	defaultLocalResourceDir          = "./"
 )
 
 // Run drives server logic in independent background goroutines, and use error
@@ -313,9 +645,18 @@ func (s *Server) Run(ctx context.Context) error {
 	}
 
 	registerMetrics()
+	lockdiag.SetLongHoldThreshold(s.cfg.DebugLockHoldThreshold)
 
+	err := subprocess.LoadPlugins(ctx, s.cfg.Plugins)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 	wg, ctx := errgroup.WithContext(ctx)
-	s.taskRunner = worker.NewTaskRunner(defaultRuntimeIncomingQueueLen, defaultRuntimeInitConcurrency)
+	s.taskRunner = worker.NewTaskRunner(s.cfg.DispatchQueueLength, s.cfg.DispatchConcurrency)
+	s.taskRunner.SetTaskListener(s.jobTracker)
 	s.taskCommitter = worker.NewTaskCommitter(s.taskRunner, defaultTaskPreDispatchRequestTTL)
 	defer func() {
 		s.taskCommitter.Close()
@@ -325,35 +666,67 @@ func (s *Server) Run(ctx context.Context) error {
 		return s.taskRunner.Run(ctx)
 	})
 
-	err := s.initClients(ctx)
+	if s.cfg.Sink.Enabled() {
+		s.sinkExporter, err = sink.NewExporter(&s.cfg.Sink)
+		if err != nil {
+			return err
+		}
+		wg.Go(func() error {
+			return s.sinkExporter.Run(ctx)
+		})
+		unsubscribe := s.sinkExporter.SubscribeWorkerEvents(s.eventBus)
+		defer unsubscribe()
+	}
+
+	if s.cfg.AdminAddr != "" {
+		wg.Go(func() error {
+			return adminserver.Serve(ctx, s.cfg.AdminAddr, adminserver.NewHandler(s.dumpMaster))
+		})
+	}
+
+	err = s.initClients(ctx)
 	if err != nil {
 		return err
 	}
+	// The self-tests also connect to metastores.
+	err = s.runSelfTests(ctx)
+	if err != nil {
+		return err
+	}
+	if s.cfg.StatusFlushInterval > 0 {
+		s.statusBatcher = metadata.NewWorkerStatusBatcher(
+			s.frameMetaClient, s.cfg.StatusFlushInterval, s.cfg.StatusBatchSize)
+		wg.Go(func() error {
+			return s.statusBatcher.Run(ctx)
+		})
+	}
 	err = s.selfRegister(ctx)
 	if err != nil {
 		return err
 	}
+	wg.Go(func() error {
+		return s.frameMetaFollower.Run(ctx, migration.NewEtcdStateStore(s.etcdCli), string(s.info.ID))
+	})
 
-	// TODO: make the prefix configurable later
-	s.resourceBroker = broker.NewBroker(
-		&storagecfg.Config{Local: &storagecfg.LocalFileConfig{BaseDir: "./"}},
-		s.info.ID,
-		s.resourceClient)
+	s.resourceBroker = broker.NewBroker(s.storageConfig(), s.info.ID, s.resourceClient)
 
 	s.p2pMsgRouter = p2p.NewMessageRouter(p2p.NodeID(s.info.ID), s.info.Addr)
 
-	s.grpcSrv = grpc.NewServer()
-	err = s.startMsgService(ctx, wg)
-	if err != nil {
-		return err
+	if len(s.cfg.WarmPool) > 0 {
+		s.workerPool = subprocess.NewPool(s.cfg.warmPoolSizes(), s.workerProcessEnv())
+		wg.Go(func() error {
+			return s.workerPool.Run(ctx)
+		})
 	}
 
-	err = s.startTCPService(ctx, wg)
+	s.grpcSrv = grpc.NewServer(
+		grpc.UnaryInterceptor(interceptor.NewUnaryServerInterceptor(&s.cfg.GRPCServer)))
+	err = s.startMsgService(ctx, wg)
 	if err != nil {
 		return err
 	}
 
-	err = s.fetchMetaStore(ctx)
+	err = s.startTCPService(ctx, wg)
 	if err != nil {
 		return err
 	}
@@ -362,6 +735,12 @@ func (s *Server) Run(ctx context.Context) error {
 		s.info, s.etcdCli, s.cfg.SessionTTL, defaultDiscoverTicker,
 		s.p2pMsgRouter,
 	)
+	s.discoveryKeeper.AddListener(s.clientManager.HandleDiscoveryEvent)
+	s.discoveryKeeper.AddListener(serverutils.PeerCodecsListener(s.peerCodecs))
+	wg.Go(func() error {
+		s.clientManager.Run(ctx)
+		return nil
+	})
 	// connects to metastore and maintains a etcd session
 	wg.Go(func() error {
 		return s.discoveryKeeper.Keepalive(ctx)
@@ -383,6 +762,10 @@ func (s *Server) Run(ctx context.Context) error {
 		return s.collectMetricLoop(ctx, defaultMetricInterval)
 	})
 
+	wg.Go(func() error {
+		return s.handleShutdown(ctx, cancel)
+	})
+
 	return wg.Wait()
 }
 
@@ -405,7 +788,7 @@ func (s *Server) startTCPService(ctx context.Context, wg *errgroup.Group) error
 	})
 
 	wg.Go(func() error {
-		return httpHandler(s.tcpServer.HTTP1Listener())
+		return httpHandler(s.tcpServer.HTTP1Listener(), s.trafficAccountant, s.taskRunner)
 	})
 	return nil
 }
@@ -467,11 +850,29 @@ func (s *Server) fetchMetaStore(ctx context.Context) error {
 		return err
 	}
 	// TODO: replace the default DB config
-	s.frameMetaClient, err = pkgOrm.NewClient(conf, pkgOrm.NewDefaultDBConfig())
+	frameMetaClient, err := pkgOrm.NewClient(conf, pkgOrm.NewDefaultDBConfig())
+	if err != nil {
+		log.L().Error("connect to framework metastore fail", redact.Any("conf", conf), zap.Error(err))
+		return err
+	}
+	migrator := migration.NewMigrator(frameMetaClient)
+	s.frameMetaClient = migrator.Client()
+	s.frameMetaFollower = migration.NewFollower(migrator, conf, subprocess.ApplyMetaMigration)
+	// the writes must be fenced before they are issued if the framework
+	// metastore is being switched
+	state, err := migration.NewEtcdStateStore(s.etcdCli).Load(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.frameMetaFollower.Apply(ctx, state); err != nil {
+		log.L().Error("apply framework metastore migration fail", zap.String("phase", string(state.Phase)), zap.Error(err))
+		return err
+	}
+	kms, err := secret.NewKMS(&s.cfg.Secret)
 	if err != nil {
-		log.L().Error("connect to framework metastore fail", zap.Any("conf", conf), zap.Error(err))
 		return err
 	}
+	s.secrets = secret.NewStore(s.frameMetaClient, kms)
 	log.L().Info("update framework metastore successful", zap.String("addr", resp.Address))
 
 	// fetch user metastore connection endpoint
@@ -488,6 +889,7 @@ func (s *Server) fetchMetaStore(ctx context.Context) error {
 	conf = metaclient.StoreConfigParams{
 		Endpoints: []string{resp.Address},
 	}
+	s.userMetaConf = conf
 	s.userRawKVClient, err = kvclient.NewKVClient(&conf)
 	if err != nil {
 		log.L().Error("connect to user metastore fail", zap.Any("store-conf", conf), zap.Error(err))
@@ -531,10 +933,18 @@ func (s *Server) initClients(ctx context.Context) (err error) {
 	return nil
 }
 
+// selfRegister registers the executor to server master. If the executor has
+// been registered before, it registers again with the same executor ID.
 func (s *Server) selfRegister(ctx context.Context) (err error) {
 	registerReq := &pb.RegisterExecutorRequest{
-		Address:    s.cfg.AdvertiseAddr,
-		Capability: defaultCapability,
+		Address:         s.cfg.AdvertiseAddr,
+		Capability:      defaultCapability,
+		ProtocolVersion: int32(compat.CurrentProtocolVersion),
+		Resources:       s.cfg.Resources,
+		Labels:          s.cfg.Labels,
+	}
+	if s.info != nil {
+		registerReq.ExecutorId = string(s.info.ID)
 	}
 
 	var resp *pb.RegisterExecutorResponse
@@ -564,12 +974,22 @@ func (s *Server) selfRegister(ctx context.Context) (err error) {
 	if err != nil {
 		return
 	}
+	s.updateClusterVersion(resp.GetClusterProtocolVersion())
 
+	if s.info != nil {
+		// NodeInfo is shared with other components, keep it unchanged.
+		log.L().Logger.Info("register again successful", zap.Any("info", s.info))
+		return nil
+	}
 	s.info = &model.NodeInfo{
 		Type:       model.NodeTypeExecutor,
 		ID:         model.ExecutorID(resp.ExecutorId),
 		Addr:       s.cfg.AdvertiseAddr,
 		Capability: int(defaultCapability),
+		Resources:  s.cfg.Resources.Clone(),
+		Labels:     s.cfg.Labels,
+		// the codecs are published by service discovery
+		MessageCodecs: p2p.SupportedCodecs(),
 	}
 	log.L().Logger.Info("register successful", zap.Any("info", s.info))
 	return nil
@@ -599,29 +1019,53 @@ func (s *Server) keepHeartbeat(ctx context.Context) error {
 		case <-ctx.Done():
 			return nil
 		case t := <-ticker.C:
+			executorStatus := model.ExecutorStatus(s.status.Load())
+			if executorStatus == model.Tombstone {
+				// the executor has deregistered itself
+				return nil
+			}
 			if s.lastHearbeatTime.Add(s.cfg.KeepAliveTTL).Before(time.Now()) {
-				return errors.ErrHeartbeat.GenWithStack("heartbeat timeout")
+				if err := s.reconnectMaster(ctx, errors.ErrHeartbeat.GenWithStack("heartbeat timeout")); err != nil {
+					return err
+				}
+				continue
 			}
 			req := &pb.HeartbeatRequest{
 				ExecutorId: string(s.info.ID),
-				Status:     int32(model.Running),
+				Status:     int32(executorStatus),
 				Timestamp:  uint64(t.Unix()),
 				// We set longer ttl for master, which is "ttl + rpc timeout", to avoid that
 				// executor actually wait for a timeout when ttl is nearly up.
-				Ttl: uint64(s.cfg.KeepAliveTTL.Milliseconds() + s.cfg.RPCTimeout.Milliseconds()),
+				Ttl:             uint64(s.cfg.KeepAliveTTL.Milliseconds() + s.cfg.RPCTimeout.Milliseconds()),
+				IdleEvictable:   s.checkIdleEvictable(executorStatus, t),
+				ProtocolVersion: int32(compat.CurrentProtocolVersion),
+				ResourceUsages:  s.resourceUsage(),
 			}
 			resp, err := s.masterClient.Heartbeat(ctx, req, s.cfg.RPCTimeout)
 			if err != nil {
 				log.L().Error("heartbeat rpc meet error", zap.Error(err))
 				if s.lastHearbeatTime.Add(s.cfg.KeepAliveTTL).Before(time.Now()) {
-					return errors.Wrap(errors.ErrHeartbeat, err, "rpc")
+					if err := s.reconnectMaster(ctx, errors.Wrap(errors.ErrHeartbeat, err, "rpc")); err != nil {
+						return err
+					}
 				}
 				continue
 			}
 			if resp.Err != nil {
 				log.L().Warn("heartbeat response meet error", zap.Stringer("code", resp.Err.GetCode()))
 				switch resp.Err.Code {
-				case pb.ErrorCode_UnknownExecutor, pb.ErrorCode_TombstoneExecutor:
+				case pb.ErrorCode_UnknownExecutor:
+					// A new server master leader doesn't know this executor yet.
+					err := errors.ErrHeartbeat.GenWithStack("logic error: %s", resp.Err.GetMessage())
+					if err := s.reconnectMaster(ctx, err); err != nil {
+						return err
+					}
+					continue
+				case pb.ErrorCode_TombstoneExecutor:
+					if model.ExecutorStatus(s.status.Load()) == model.Tombstone {
+						// the executor has deregistered itself
+						return nil
+					}
 					return errors.ErrHeartbeat.GenWithStack("logic error: %s", resp.Err.GetMessage())
 				case pb.ErrorCode_MasterNotReady:
 					s.lastHearbeatTime = t
@@ -639,6 +1083,7 @@ func (s *Server) keepHeartbeat(ctx context.Context) error {
 			// later than master's, which might cause that master wait for less time than executor.
 			// This gap is unsafe.
 			s.lastHearbeatTime = t
+			s.updateClusterVersion(resp.GetClusterProtocolVersion())
 			if rl.Allow() {
 				log.L().Info("heartbeat success", zap.String("leader", resp.Leader), zap.Strings("members", resp.Addrs))
 			}
@@ -658,6 +1103,102 @@ func (s *Server) keepHeartbeat(ctx context.Context) error {
 	}
 }
 
+// resourceUsage returns the resource usage of the running tasks reported to
+// server master. The disk usage is the larger one of the declared usage of
+// the tasks and the measured usage of the local file resources, so that the
+// disk-heavy tasks are not scheduled to the executor running out of disk.
+func (s *Server) resourceUsage() model.RescVector {
+	if s.taskRunner == nil {
+		return nil
+	}
+	usage := s.taskRunner.ResourceUsage()
+	if s.resourceBroker != nil {
+		disk := s.resourceBroker.DiskUsage().Total / model.DiskUnit
+		usage = usage.Max(model.RescVector{model.ResourceDisk: disk})
+	}
+	return usage
+}
+
+// checkIdleEvictable returns whether the executor is idle-evictable, and
+// updates the discovery metadata if the state changes.
+func (s *Server) checkIdleEvictable(executorStatus model.ExecutorStatus, now time.Time) bool {
+	evictable := false
+	idleEvictionEnabled := compat.FeatureEnabled(
+		compat.FeatureIdleEviction, compat.ProtocolVersion(s.clusterVersion.Load()))
+	if idleEvictionEnabled && executorStatus == model.Running && s.taskRunner != nil {
+		evictable = s.idleTracker.evictable(s.taskRunner.TaskCount(), now)
+	}
+	if evictable == s.idleEvictable {
+		return evictable
+	}
+
+	log.L().Info("executor idle-evictable state changes",
+		zap.Bool("idle-evictable", evictable),
+		zap.Duration("idle-evict-timeout", s.cfg.IdleEvictTimeout))
+	s.idleEvictable = evictable
+	if s.discoveryKeeper != nil {
+		info := *s.info
+		info.IdleEvictable = evictable
+		s.discoveryKeeper.UpdateInfo(&info)
+	}
+	return evictable
+}
+
+// updateClusterVersion stores the protocol version negotiated by server master.
+func (s *Server) updateClusterVersion(version int32) {
+	// server master of an old version doesn't negotiate the version
+	version = int32(compat.ProtocolVersion(version).Normalize())
+	if old := s.clusterVersion.Swap(version); old != version {
+		log.L().Info("cluster protocol version changes",
+			zap.Int32("old", old), zap.Int32("new", version),
+			zap.Int32("current", int32(compat.CurrentProtocolVersion)))
+	}
+}
+
+// reconnectMaster re-discovers server masters and registers the executor
+// again with its executor ID. Running workers are left untouched, so a server
+// master failover doesn't restart the executor. It gives up and returns the
+// cause after the master grace period.
+func (s *Server) reconnectMaster(ctx context.Context, cause error) error {
+	log.L().Warn("lost server master, try to register again",
+		zap.Duration("grace-period", s.cfg.MasterGracePeriod), zap.Error(cause))
+
+	graceCtx, cancel := context.WithTimeout(ctx, s.cfg.MasterGracePeriod)
+	defer cancel()
+	ticker := time.NewTicker(s.cfg.KeepAliveInterval)
+	defer ticker.Stop()
+	for {
+		s.rediscoverMasters(graceCtx)
+		err := s.selfRegister(graceCtx)
+		if err == nil {
+			s.lastHearbeatTime = time.Now()
+			return nil
+		}
+		log.L().Warn("register executor again failed", zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-graceCtx.Done():
+			return cause
+		case <-ticker.C:
+		}
+	}
+}
+
+// rediscoverMasters updates the server master clients with join addresses
+// and the server masters found by service discovery.
+func (s *Server) rediscoverMasters(ctx context.Context) {
+	addrs := getJoinURLs(s.cfg.Join)
+	if s.discoveryKeeper != nil {
+		addrs = append(addrs, s.discoveryKeeper.MasterAddrs()...)
+	}
+	s.masterClient.UpdateClients(ctx, addrs, "")
+	if s.resourceClient != nil {
+		s.resourceClient.UpdateClients(ctx, addrs, "")
+	}
+}
+
 func getJoinURLs(addrs string) []string {
 	return strings.Split(addrs, ",")
 }
@@ -720,6 +1261,7 @@ func (s *Server) bgUpdateServerMasterClients(ctx context.Context) error {
 
 func (s *Server) collectMetricLoop(ctx context.Context, tickInterval time.Duration) error {
 	metricRunningTask := executorTaskNumGauge.WithLabelValues("running")
+	metricQueuedTask := executorTaskNumGauge.WithLabelValues("queued")
 	ticker := time.NewTicker(tickInterval)
 	defer ticker.Stop()
 	for {
@@ -728,6 +1270,7 @@ func (s *Server) collectMetricLoop(ctx context.Context, tickInterval time.Durati
 			return nil
 		case <-ticker.C:
 			metricRunningTask.Set(float64(s.taskRunner.TaskCount()))
+			metricQueuedTask.Set(float64(s.taskRunner.QueueLength()))
 		}
 	}
 }
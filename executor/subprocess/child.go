@@ -0,0 +1,376 @@
+package subprocess
+
+import (
+	"context"
+	"flag"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	"github.com/hanfei1991/microcosm/client"
+	"github.com/hanfei1991/microcosm/lib"
+	"github.com/hanfei1991/microcosm/lib/registry"
+	"github.com/hanfei1991/microcosm/pb"
+	dcontext "github.com/hanfei1991/microcosm/pkg/context"
+	"github.com/hanfei1991/microcosm/pkg/deps"
+	derrors "github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/externalresource/broker"
+	resModel "github.com/hanfei1991/microcosm/pkg/externalresource/resourcemeta/model"
+	"github.com/hanfei1991/microcosm/pkg/meta/encryption"
+	extkv "github.com/hanfei1991/microcosm/pkg/meta/extension"
+	"github.com/hanfei1991/microcosm/pkg/meta/kvclient"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+	"github.com/hanfei1991/microcosm/pkg/p2p"
+	"github.com/hanfei1991/microcosm/pkg/rpcutil"
+	"github.com/hanfei1991/microcosm/pkg/tenant"
+	"github.com/hanfei1991/microcosm/pkg/traffic"
+)
+
+// WorkerProcessArg is the first command line argument that makes the
+// executor binary run as a worker process instead of an executor server.
+const WorkerProcessArg = "worker-process"
+
+const (
+	defaultPollInterval = 50 * time.Millisecond
+	// trafficReportInterval is the interval of reporting the metastore
+	// traffic of the worker to the executor.
+	trafficReportInterval = time.Second
+)
+
+// processTrafficKey is the key the traffic is accounted to in a worker
+// process, which runs the worker of a single job.
+const processTrafficKey = "worker-process"
+
+// prepareWorkerEnvFn and createWorkerFn are replaced in the tests of worker
+// processes, which run without metastores and server master.
+var (
+	prepareWorkerEnvFn = prepareWorkerEnv
+	createWorkerFn     = func(ctx *dcontext.Context, spec *WorkerSpec) (lib.Worker, error) {
+		return registry.GlobalWorkerRegistry().CreateWorker(
+			ctx, spec.WorkerType, spec.WorkerID, spec.MasterID, spec.WorkerConfig)
+	}
+)
+
+// RunWorkerProcessMain runs a worker process with the command line
+// arguments following WorkerProcessArg, and returns the exit code. It is
+// the main function of the worker processes started from the executor
+// binary or from a job plugin binary.
+func RunWorkerProcessMain(args []string) int {
+	fs := flag.NewFlagSet(WorkerProcessArg, flag.ContinueOnError)
+	socketPath := fs.String("socket", "", "path to the back-channel socket of the executor")
+	logLevel := fs.String("L", "info", "log level: debug, info, warn, error, fatal")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	err := log.InitLogger(&log.Config{Level: strings.ToLower(*logLevel)})
+	if err != nil {
+		return 2
+	}
+
+	// The worker process exits when the executor closes the back-channel,
+	// so signals are ignored here and handled by the executor.
+	signal.Ignore(syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
+
+	err = RunWorkerProcess(context.Background(), *socketPath)
+	if err != nil && errors.Cause(err) != context.Canceled {
+		log.L().Error("run worker process with error", zap.Error(err))
+		return 2
+	}
+	return 0
+}
+
+// RunWorkerProcess is the entry of a worker process. It connects back to
+// the executor via socketPath, receives the WorkerSpec, and runs the worker
+// until the worker exits or the executor asks it to close. A warm worker
+// process prepares the environment first and waits for the WorkerSpec.
+func RunWorkerProcess(ctx context.Context, socketPath string) error {
+	rawConn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	conn := newConn(rawConn)
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	handlerManager := newProxyHandlerManager(conn)
+	meta := &metaFollower{}
+	f, err := readSetupFrame(ctx, conn, meta)
+	if err != nil {
+		return err
+	}
+	var env *workerEnv
+	if f.Tp == framePrepare && f.Spec != nil {
+		env, err = prepareWorkerEnvFn(ctx, f.Spec, conn, handlerManager, meta)
+		if err != nil {
+			return err
+		}
+		defer env.close()
+		if err := conn.WriteFrame(&frame{Tp: frameWorkerPrepared}); err != nil {
+			return err
+		}
+		log.L().Info("worker process is warmed up", zap.Int64("worker-type", int64(f.Spec.WorkerType)))
+
+		f, err = readSetupFrame(ctx, conn, meta)
+		if err != nil {
+			return err
+		}
+		if f.Tp == frameCloseWorker {
+			// the warm worker process is closed before a worker is assigned
+			return nil
+		}
+	}
+	if f.Tp != frameWorkerSpec || f.Spec == nil {
+		return derrors.ErrWorkerProcessProtocol.GenWithStackByArgs(f.Tp)
+	}
+	spec := f.Spec
+
+	go func() {
+		defer cancel()
+		for {
+			f, err := conn.ReadFrame()
+			if err != nil {
+				log.L().Info("back-channel closed", zap.Error(err))
+				return
+			}
+			switch f.Tp {
+			case frameDeliverMessage:
+				handlerManager.deliver(f.Node, f.Topic, f.Payload)
+			case frameHandlerError:
+				handlerManager.onError(errors.New(f.Error))
+			case frameMetaMigration:
+				if f.MetaMigration != nil {
+					meta.apply(ctx, conn, f.MetaMigration)
+				}
+			case frameCloseWorker:
+				return
+			default:
+				log.L().Warn("unexpected frame", zap.Any("frame", f))
+			}
+		}
+	}()
+
+	exitErr := func() error {
+		if env == nil {
+			env, err = prepareWorkerEnvFn(ctx, spec, conn, handlerManager, meta)
+			if err != nil {
+				return err
+			}
+			defer env.close()
+		}
+		return runWorker(ctx, spec, env, conn)
+	}()
+	if env != nil {
+		env.reportTraffic(conn)
+	}
+	exitFrame := &frame{Tp: frameWorkerExited}
+	if exitErr != nil {
+		exitFrame.Error = exitErr.Error()
+	}
+	if err := conn.WriteFrame(exitFrame); err != nil {
+		log.L().Warn("failed to report worker exit", zap.Error(err))
+	}
+	return exitErr
+}
+
+// readSetupFrame reads the next frame setting up the worker process, the
+// states of the metastore migration are applied on the way.
+func readSetupFrame(ctx context.Context, conn *conn, meta *metaFollower) (*frame, error) {
+	for {
+		f, err := conn.ReadFrame()
+		if err != nil {
+			return nil, err
+		}
+		if f.Tp != frameMetaMigration || f.MetaMigration == nil {
+			return f, nil
+		}
+		meta.apply(ctx, conn, f.MetaMigration)
+	}
+}
+
+// workerEnv is what a worker needs besides its own spec, which is prepared
+// before the worker is assigned if the worker process is warm.
+type workerEnv struct {
+	frameMetaClient pkgOrm.Client
+	userRawKVClient extkv.KVClientEx
+	deps            *deps.Deps
+
+	// traffic accounts the metastore traffic of the worker, which is
+	// reported to the executor since the executor owns the accounting.
+	traffic         *traffic.Accountant
+	reportedTraffic traffic.Stat
+}
+
+func prepareWorkerEnv(
+	ctx context.Context,
+	spec *WorkerSpec,
+	conn *conn,
+	handlerManager *proxyHandlerManager,
+	meta *metaFollower,
+) (_ *workerEnv, retErr error) {
+	env := &workerEnv{traffic: traffic.NewAccountant(0, false)}
+	defer func() {
+		if retErr != nil {
+			env.close()
+		}
+	}()
+	var err error
+	// the framework metastore may have been switched by a migration
+	env.frameMetaClient, err = meta.connect(ctx, spec.FrameMetaConf)
+	if err != nil {
+		return nil, err
+	}
+
+	env.userRawKVClient, err = kvclient.NewKVClient(&spec.UserMetaConf)
+	if err != nil {
+		return nil, err
+	}
+	if err := spec.UserMetaEncryption.Adjust(); err != nil {
+		return nil, err
+	}
+
+	clients := client.NewClientManager()
+	if err := clients.AddMasterClient(ctx, spec.Join); err != nil {
+		return nil, err
+	}
+
+	resourceClient, err := rpcutil.NewFailoverRPCClients[pb.ResourceManagerClient](
+		ctx, spec.Join, dialResourceManager)
+	if err != nil {
+		return nil, err
+	}
+
+	env.deps = deps.NewDeps()
+	providers := []interface{}{
+		func() p2p.MessageHandlerManager { return handlerManager },
+		func() p2p.MessageSender { return newProxyMessageSender(conn) },
+		func() pkgOrm.Client {
+			return traffic.NewFrameMetaClient(env.frameMetaClient, env.traffic, processTrafficKey)
+		},
+		func() extkv.KVClientEx {
+			return traffic.NewKVClient(env.userRawKVClient, env.traffic, processTrafficKey)
+		},
+		func() client.ClientsManager { return clients },
+		func() client.MasterClient { return clients.MasterClient() },
+		func() broker.Broker {
+			return broker.NewBroker(&spec.Storage, resModel.ExecutorID(spec.NodeID), resourceClient)
+		},
+	}
+	if keyring := spec.UserMetaEncryption.Keyring(); keyring != nil {
+		providers = append(providers, func() *encryption.Keyring { return keyring })
+	}
+	for _, provider := range providers {
+		if err := env.deps.Provide(provider); err != nil {
+			return nil, err
+		}
+	}
+	return env, nil
+}
+
+// reportTraffic reports the metastore traffic since the last report.
+func (e *workerEnv) reportTraffic(conn *conn) {
+	if e.traffic == nil {
+		return
+	}
+	stat, _ := e.traffic.Stat(processTrafficKey)
+	delta := traffic.Stat{
+		SentBytes:     stat.SentBytes - e.reportedTraffic.SentBytes,
+		ReceivedBytes: stat.ReceivedBytes - e.reportedTraffic.ReceivedBytes,
+	}
+	if delta == (traffic.Stat{}) {
+		return
+	}
+	if err := conn.WriteFrame(&frame{Tp: frameTraffic, Traffic: &delta}); err != nil {
+		log.L().Warn("failed to report traffic", zap.Error(err))
+		return
+	}
+	e.reportedTraffic = stat
+}
+
+func (e *workerEnv) close() {
+	if e.frameMetaClient != nil {
+		_ = e.frameMetaClient.Close()
+	}
+	if e.userRawKVClient != nil {
+		_ = e.userRawKVClient.Close()
+	}
+}
+
+func runWorker(
+	ctx context.Context,
+	spec *WorkerSpec,
+	env *workerEnv,
+	conn *conn,
+) error {
+	dctx := dcontext.NewContext(ctx, log.L()).WithDeps(env.deps)
+	dctx.Environ.NodeID = spec.NodeID
+	dctx.Environ.Addr = spec.Addr
+	dctx.Environ.MasterMetaBytes = spec.MasterMetaBytes
+	dctx.Environ.Env = spec.Env
+	dctx.Environ.ResourceIDs = spec.ResourceIDs
+	dctx.Environ.ProjectInfo = tenant.ProjectInfo{
+		TenantID:  spec.TenantID,
+		ProjectID: spec.ProjectID,
+	}
+	// the process runs a single worker, so its environment is the worker's
+	for key, value := range spec.Env {
+		if err := os.Setenv(key, value); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	w, err := createWorkerFn(dctx, spec)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := w.Close(context.Background()); err != nil {
+			log.L().Warn("failed to close worker", zap.Error(err))
+		}
+	}()
+
+	if err := w.Init(ctx); err != nil {
+		return err
+	}
+	if err := conn.WriteFrame(&frame{Tp: frameWorkerInitialized}); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(defaultPollInterval)
+	defer ticker.Stop()
+	reportTicker := time.NewTicker(trafficReportInterval)
+	defer reportTicker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case <-ticker.C:
+			if err := w.Poll(ctx); err != nil {
+				return err
+			}
+		case <-reportTicker.C:
+			env.reportTraffic(conn)
+		}
+	}
+}
+
+func dialResourceManager(ctx context.Context, addr string) (pb.ResourceManagerClient, rpcutil.CloseableConnIface, error) {
+	ctx, cancel := context.WithTimeout(ctx, client.DialTimeout)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, addr, grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		return nil, nil, derrors.Wrap(derrors.ErrGrpcBuildConn, err)
+	}
+	return pb.NewResourceManagerClient(conn), conn, nil
+}
@@ -0,0 +1,148 @@
+package subprocess
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+	"github.com/hanfei1991/microcosm/pkg/orm/migration"
+)
+
+// processes are the worker processes started by the executor, which follow
+// the migration of the framework metastore through the executor, since they
+// connect to the framework metastore on their own.
+var processes = struct {
+	sync.Mutex
+	runnables map[*Runnable]struct{}
+	// state is the last state of the migration applied by the executor,
+	// which is sent to the worker processes started later.
+	state *migration.State
+}{
+	runnables: make(map[*Runnable]struct{}),
+}
+
+// ApplyMetaMigration applies the state of the migration of the framework
+// metastore to all worker processes, and waits for them to apply it.
+func ApplyMetaMigration(ctx context.Context, state migration.State) error {
+	processes.Lock()
+	processes.state = &state
+	runnables := make([]*Runnable, 0, len(processes.runnables))
+	for r := range processes.runnables {
+		runnables = append(runnables, r)
+	}
+	processes.Unlock()
+
+	for _, r := range runnables {
+		if err := r.applyMetaMigration(ctx, state); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addProcess tracks a worker process whose back-channel is connected, the
+// state of the migration is sent to it before any other frame.
+func addProcess(r *Runnable) error {
+	processes.Lock()
+	defer processes.Unlock()
+
+	processes.runnables[r] = struct{}{}
+	if processes.state == nil {
+		return nil
+	}
+	return r.conn.WriteFrame(&frame{Tp: frameMetaMigration, MetaMigration: processes.state})
+}
+
+func removeProcess(r *Runnable) {
+	processes.Lock()
+	defer processes.Unlock()
+	delete(processes.runnables, r)
+}
+
+// applyMetaMigration sends state to the worker process, and waits for it to
+// be applied. An exited worker process doesn't write anymore.
+func (r *Runnable) applyMetaMigration(ctx context.Context, state migration.State) error {
+	r.metaMigrationMu.Lock()
+	defer r.metaMigrationMu.Unlock()
+
+	if err := r.conn.WriteFrame(&frame{Tp: frameMetaMigration, MetaMigration: &state}); err != nil {
+		if r.exited() {
+			return nil
+		}
+		return err
+	}
+	select {
+	case <-ctx.Done():
+		return errors.Trace(ctx.Err())
+	case <-r.exitedCh:
+		return nil
+	case errMsg := <-r.metaMigrationAppliedCh:
+		if errMsg != "" {
+			return errors.Errorf("worker process %s failed to apply metastore migration: %s", r.ID(), errMsg)
+		}
+		return nil
+	}
+}
+
+// metaFollower follows the migration of the framework metastore in a worker
+// process. The states received before the worker environment is prepared
+// are applied when the framework metastore is connected.
+type metaFollower struct {
+	mu       sync.Mutex
+	state    *migration.State
+	follower *migration.Follower
+}
+
+// apply applies the state received from the executor, and reports the
+// result to the executor.
+func (f *metaFollower) apply(ctx context.Context, conn *conn, state *migration.State) {
+	err := f.doApply(ctx, state)
+	applied := &frame{Tp: frameMetaMigrationApplied}
+	if err != nil {
+		log.L().Warn("failed to apply metastore migration", zap.Error(err))
+		applied.Error = err.Error()
+	}
+	if err := conn.WriteFrame(applied); err != nil {
+		log.L().Warn("failed to report metastore migration applied", zap.Error(err))
+	}
+}
+
+func (f *metaFollower) doApply(ctx context.Context, state *migration.State) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.state = state
+	if f.follower == nil {
+		return nil
+	}
+	return f.follower.Apply(ctx, *state)
+}
+
+// connect connects to the framework metastore of conf, or the target
+// metastore if it has been switched to.
+func (f *metaFollower) connect(ctx context.Context, conf metaclient.StoreConfigParams) (pkgOrm.Client, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.state != nil && f.state.Phase == migration.PhaseSwitched && f.state.Target != nil {
+		conf = *f.state.Target
+	}
+	cli, err := pkgOrm.NewClient(conf, pkgOrm.NewDefaultDBConfig())
+	if err != nil {
+		return nil, err
+	}
+	migrator := migration.NewMigrator(cli)
+	f.follower = migration.NewFollower(migrator, conf, nil)
+	if f.state != nil {
+		if err := f.follower.Apply(ctx, *f.state); err != nil {
+			_ = migrator.Close()
+			return nil, err
+		}
+	}
+	return migrator.Client(), nil
+}
@@ -0,0 +1,29 @@
+package subprocess
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	poolTakenCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "dataflow",
+			Subsystem: "worker_pool",
+			Name:      "taken_total",
+			Help:      "number of workers dispatched to warm worker processes",
+		}, []string{"worker_type"})
+
+	poolMissedCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "dataflow",
+			Subsystem: "worker_pool",
+			Name:      "missed_total",
+			Help:      "number of workers dispatched without a warm worker process",
+		}, []string{"worker_type"})
+)
+
+// InitMetrics registers the worker pool metrics
+func InitMetrics(registry *prometheus.Registry) {
+	registry.MustRegister(poolTakenCounter)
+	registry.MustRegister(poolMissedCounter)
+}
@@ -0,0 +1,138 @@
+package subprocess
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/lib/registry"
+	derrors "github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+// PluginDescribeArg is the first command line argument that makes a job
+// plugin binary print its PluginInfo and exit.
+const PluginDescribeArg = "describe-plugin"
+
+// PluginInfo is what the executor knows about a job plugin binary without
+// loading it, the workers of the plugin are run in worker processes started
+// from the binary.
+type PluginInfo struct {
+	Name        string                `json:"name"`
+	WorkerTypes []libModel.WorkerType `json:"worker-types"`
+}
+
+// ServePlugin is the main function of a job plugin binary, for example
+//
+//	func main() {
+//		subprocess.ServePlugin(&registry.JobPlugin{...})
+//	}
+//
+// A plugin binary is an ordinary static binary built with `make df-plugin`,
+// it is never loaded into the executor. The executor describes it on
+// startup, and starts it as the worker process of the workers of the
+// plugin. The connectors of the plugin are registered in its worker
+// processes only, so they are available to the workers of the plugin.
+func ServePlugin(plugin *registry.JobPlugin) {
+	if len(os.Args) > 1 && os.Args[1] == PluginDescribeArg {
+		if err := describePlugin(os.Stdout, plugin); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) <= 1 || os.Args[1] != WorkerProcessArg {
+		fmt.Fprintf(os.Stderr, "job plugin %s is run by the executor, add it to `plugins` of the executor config\n", plugin.Name)
+		os.Exit(2)
+	}
+
+	registry.GlobalWorkerRegistry().MustRegisterPlugin(plugin)
+	os.Exit(RunWorkerProcessMain(os.Args[2:]))
+}
+
+func describePlugin(w io.Writer, plugin *registry.JobPlugin) error {
+	info := PluginInfo{Name: plugin.Name}
+	if plugin.MasterFactory != nil {
+		info.WorkerTypes = append(info.WorkerTypes, plugin.MasterType)
+	}
+	for _, worker := range plugin.Workers {
+		info.WorkerTypes = append(info.WorkerTypes, worker.Type)
+	}
+	return json.NewEncoder(w).Encode(&info)
+}
+
+// plugins are the binaries of the job plugins loaded by the executor, by
+// the worker types they run.
+var plugins = struct {
+	sync.RWMutex
+	binaries map[libModel.WorkerType]string
+}{
+	binaries: make(map[libModel.WorkerType]string),
+}
+
+// LoadPlugins describes the job plugin binaries in order, so that the
+// workers of their worker types are run in worker processes started from
+// them. It stops at the first failure.
+func LoadPlugins(ctx context.Context, paths []string) error {
+	for _, path := range paths {
+		if err := loadPlugin(ctx, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func loadPlugin(ctx context.Context, path string) error {
+	out, err := exec.CommandContext(ctx, path, PluginDescribeArg).Output()
+	if err != nil {
+		return derrors.ErrLoadPluginFailed.GenWithStackByArgs(path, err.Error())
+	}
+	info := &PluginInfo{}
+	if err := json.Unmarshal(out, info); err != nil {
+		return derrors.ErrLoadPluginFailed.GenWithStackByArgs(path, err.Error())
+	}
+
+	plugins.Lock()
+	defer plugins.Unlock()
+	for _, tp := range info.WorkerTypes {
+		if _, exists := plugins.binaries[tp]; exists {
+			return derrors.ErrWorkerTypeDuplicated.GenWithStackByArgs(tp)
+		}
+	}
+	for _, tp := range info.WorkerTypes {
+		plugins.binaries[tp] = path
+	}
+	log.L().Info("job plugin loaded",
+		zap.String("path", path),
+		zap.String("name", info.Name),
+		zap.Any("worker-types", info.WorkerTypes))
+	return nil
+}
+
+// IsPluginWorkerType returns whether the workers of tp are run by a job
+// plugin binary, they are always run in worker processes.
+func IsPluginWorkerType(tp libModel.WorkerType) bool {
+	plugins.RLock()
+	defer plugins.RUnlock()
+	_, ok := plugins.binaries[tp]
+	return ok
+}
+
+// workerBinary returns the binary of the worker process running the workers
+// of tp, which is the executor binary unless tp is run by a job plugin.
+func workerBinary(tp libModel.WorkerType) (string, error) {
+	plugins.RLock()
+	path, ok := plugins.binaries[tp]
+	plugins.RUnlock()
+	if ok {
+		return path, nil
+	}
+	return os.Executable()
+}
@@ -0,0 +1,78 @@
+package subprocess
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/lib/registry"
+	derrors "github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/p2p"
+)
+
+// pluginWorkerType is run by the test plugin, whose worker process runs an
+// echoWorker like the executor's.
+const pluginWorkerType = libModel.WorkerType(1002)
+
+// describeTestPlugin is run when the test binary is described as a job
+// plugin binary.
+func describeTestPlugin() int {
+	err := describePlugin(os.Stdout, &registry.JobPlugin{
+		Name:    "echo-plugin",
+		Workers: []registry.WorkerPlugin{{Type: pluginWorkerType}},
+	})
+	if err != nil {
+		return 1
+	}
+	return 0
+}
+
+// writeTestPlugin writes a job plugin binary, which runs the test binary
+// with echoEnvKey set, so that its workers are told from the executor's.
+func writeTestPlugin(t *testing.T) string {
+	executable, err := os.Executable()
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "echo-plugin")
+	script := fmt.Sprintf("#!/bin/sh\n%s=plugin exec %s \"$@\"\n", echoEnvKey, executable)
+	require.NoError(t, os.WriteFile(path, []byte(script), 0o755))
+	return path
+}
+
+func TestLoadPlugins(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	err := LoadPlugins(ctx, []string{"/not-exist/plugin"})
+	require.True(t, derrors.ErrLoadPluginFailed.Equal(err))
+	require.NoError(t, LoadPlugins(ctx, nil))
+
+	path := writeTestPlugin(t)
+	require.False(t, IsPluginWorkerType(pluginWorkerType))
+	require.NoError(t, LoadPlugins(ctx, []string{path}))
+	require.True(t, IsPluginWorkerType(pluginWorkerType))
+	err = LoadPlugins(ctx, []string{path})
+	require.True(t, derrors.ErrWorkerTypeDuplicated.Equal(err))
+
+	// the worker of the plugin runs in the worker process started from the
+	// plugin binary
+	handlerManager := p2p.NewMockMessageHandlerManager()
+	sender := p2p.NewMockMessageSender()
+	r := NewRunnable(&WorkerSpec{
+		WorkerID:   "worker-1",
+		MasterID:   "master-1",
+		WorkerType: pluginWorkerType,
+	}, handlerManager, sender, nil)
+	require.NoError(t, r.Init(ctx))
+	handlerManager.AssertHasHandler(t, echoTopic, &p2p.EncodedMessage{})
+	sendEcho(t, handlerManager, 1)
+	require.Equal(t, &echoMessage{Value: 2, Env: "plugin"}, waitEchoReply(t, sender))
+	require.NoError(t, r.Close(ctx))
+}
@@ -0,0 +1,190 @@
+package subprocess
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/pkg/p2p"
+)
+
+const (
+	defaultPoolRefillInterval = time.Second
+	defaultWarmUpTimeout      = 30 * time.Second
+)
+
+// Pool keeps a number of worker processes warmed up for each worker type,
+// which have connected to the metastores and the server master. Taking a warm worker process saves the startup of a worker
+// process from dispatching a worker, and the pool is refilled in background.
+type Pool struct {
+	sizes map[libModel.WorkerType]int
+	// env is the WorkerSpec with the environment fields only
+	env WorkerSpec
+
+	mu      sync.Mutex
+	idle    map[libModel.WorkerType][]*Runnable
+	warming map[libModel.WorkerType]int
+	closed  bool
+
+	wg sync.WaitGroup
+
+	// warmUp is replaced in unit tests
+	warmUp func(ctx context.Context, r *Runnable) error
+}
+
+// NewPool creates a Pool keeping sizes[tp] worker processes for the worker
+// type tp, env is the environment shared by the workers on the executor.
+func NewPool(sizes map[libModel.WorkerType]int, env WorkerSpec) *Pool {
+	return &Pool{
+		sizes:   sizes,
+		env:     env,
+		idle:    make(map[libModel.WorkerType][]*Runnable),
+		warming: make(map[libModel.WorkerType]int),
+		warmUp: func(ctx context.Context, r *Runnable) error {
+			return r.warmUp(ctx)
+		},
+	}
+}
+
+// Run refills the pool until ctx is done, the warm worker processes are
+// closed then.
+func (p *Pool) Run(ctx context.Context) error {
+	defer p.close()
+
+	ticker := time.NewTicker(defaultPoolRefillInterval)
+	defer ticker.Stop()
+	for {
+		p.refill(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Take assigns the worker in spec to a warm worker process of its type,
+// it returns nil if there is none or the Pool is nil.
+func (p *Pool) Take(
+	spec *WorkerSpec,
+	handlerManager p2p.MessageHandlerManager,
+	messageSender p2p.MessageSender,
+	recordTraffic TrafficRecorder,
+) *Runnable {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	idle := p.idle[spec.WorkerType]
+	for len(idle) > 0 {
+		r := idle[0]
+		idle = idle[1:]
+		if r.exited() {
+			p.closeRunnable(r)
+			continue
+		}
+		p.idle[spec.WorkerType] = idle
+		r.assign(spec, handlerManager, messageSender, recordTraffic)
+		poolTakenCounter.WithLabelValues(workerTypeLabel(spec.WorkerType)).Inc()
+		return r
+	}
+	p.idle[spec.WorkerType] = idle
+	poolMissedCounter.WithLabelValues(workerTypeLabel(spec.WorkerType)).Inc()
+	return nil
+}
+
+// refill starts warming up worker processes for the worker types lacking
+// them, and drops the exited ones.
+func (p *Pool) refill(ctx context.Context) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+
+	for tp, size := range p.sizes {
+		alive := p.idle[tp][:0]
+		for _, r := range p.idle[tp] {
+			if r.exited() {
+				log.L().Warn("warm worker process exited",
+					zap.Int64("worker-type", int64(tp)), zap.Error(r.errCenter.CheckError()))
+				p.closeRunnable(r)
+				continue
+			}
+			alive = append(alive, r)
+		}
+		p.idle[tp] = alive
+
+		for i := len(alive) + p.warming[tp]; i < size; i++ {
+			p.warming[tp]++
+			p.wg.Add(1)
+			go func(tp libModel.WorkerType) {
+				defer p.wg.Done()
+				p.warmUpOne(ctx, tp)
+			}(tp)
+		}
+	}
+}
+
+func (p *Pool) warmUpOne(ctx context.Context, tp libModel.WorkerType) {
+	spec := p.env
+	spec.WorkerType = tp
+	// the p2p components are assigned with the worker
+	r := NewRunnable(&spec, nil, nil, nil)
+
+	warmUpCtx, cancel := context.WithTimeout(ctx, defaultWarmUpTimeout)
+	defer cancel()
+	err := p.warmUp(warmUpCtx, r)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.warming[tp]--
+	if err != nil {
+		log.L().Warn("failed to warm up worker process",
+			zap.Int64("worker-type", int64(tp)), zap.Error(err))
+		p.closeRunnable(r)
+		return
+	}
+	if p.closed {
+		p.closeRunnable(r)
+		return
+	}
+	p.idle[tp] = append(p.idle[tp], r)
+}
+
+func (p *Pool) close() {
+	p.mu.Lock()
+	p.closed = true
+	for tp, idle := range p.idle {
+		for _, r := range idle {
+			p.closeRunnable(r)
+		}
+		delete(p.idle, tp)
+	}
+	p.mu.Unlock()
+
+	p.wg.Wait()
+}
+
+// closeRunnable closes the worker process in background, since it may take
+// a while for the process to exit.
+func (p *Pool) closeRunnable(r *Runnable) {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		if err := r.Close(context.Background()); err != nil {
+			log.L().Warn("failed to close warm worker process", zap.Error(err))
+		}
+	}()
+}
+
+func workerTypeLabel(tp libModel.WorkerType) string {
+	return strconv.FormatInt(int64(tp), 10)
+}
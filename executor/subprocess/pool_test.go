@@ -0,0 +1,66 @@
+package subprocess
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+)
+
+func idleCount(p *Pool, tp libModel.WorkerType) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.idle[tp])
+}
+
+func TestPool(t *testing.T) {
+	t.Parallel()
+
+	const workerType = libModel.WorkerType(1)
+	pool := NewPool(map[libModel.WorkerType]int{workerType: 2}, WorkerSpec{NodeID: "executor-1"})
+	var warmedUp atomic.Int32
+	pool.warmUp = func(ctx context.Context, r *Runnable) error {
+		require.Equal(t, "executor-1", r.getSpec().NodeID)
+		require.Equal(t, workerType, r.getSpec().WorkerType)
+		warmedUp.Inc()
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pool.refill(ctx)
+	require.Eventually(t, func() bool {
+		return idleCount(pool, workerType) == 2
+	}, time.Second, 10*time.Millisecond)
+	// the worker processes being warmed up are counted
+	pool.refill(ctx)
+	require.Equal(t, int32(2), warmedUp.Load())
+
+	r := pool.Take(&WorkerSpec{WorkerID: "worker-1", WorkerType: workerType}, nil, nil, nil)
+	require.NotNil(t, r)
+	require.Equal(t, "worker-1", r.ID())
+	require.Equal(t, 1, idleCount(pool, workerType))
+	require.Nil(t, pool.Take(&WorkerSpec{WorkerID: "worker-2", WorkerType: 2}, nil, nil, nil))
+
+	// the exited worker process is dropped and the pool is refilled
+	pool.mu.Lock()
+	close(pool.idle[workerType][0].exitedCh)
+	pool.mu.Unlock()
+	pool.refill(ctx)
+	require.Eventually(t, func() bool {
+		return idleCount(pool, workerType) == 2
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, int32(4), warmedUp.Load())
+
+	pool.close()
+	require.Equal(t, 0, idleCount(pool, workerType))
+	pool.refill(ctx)
+	require.Equal(t, int32(4), warmedUp.Load())
+
+	var nilPool *Pool
+	require.Nil(t, nilPool.Take(&WorkerSpec{WorkerType: workerType}, nil, nil, nil))
+}
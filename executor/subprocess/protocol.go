@@ -0,0 +1,142 @@
+package subprocess
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/pingcap/errors"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/pkg/externalresource/storagecfg"
+	"github.com/hanfei1991/microcosm/pkg/meta/encryption"
+	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
+	"github.com/hanfei1991/microcosm/pkg/orm/migration"
+	"github.com/hanfei1991/microcosm/pkg/traffic"
+)
+
+// frameType is the type of a frame transmitted over the back-channel
+// between the executor and a worker process.
+type frameType string
+
+const (
+	// executor -> worker process
+	framePrepare        = frameType("prepare")
+	frameWorkerSpec     = frameType("spec")
+	frameDeliverMessage = frameType("deliver")
+	frameCloseWorker    = frameType("close")
+	frameHandlerError   = frameType("handler-error")
+	frameMetaMigration  = frameType("meta-migration")
+
+	// worker process -> executor
+	frameRegisterHandler   = frameType("register")
+	frameUnregisterHandler = frameType("unregister")
+	frameSendMessage       = frameType("send")
+	frameWorkerPrepared    = frameType("prepared")
+	frameWorkerInitialized = frameType("initialized")
+	frameWorkerExited      = frameType("exited")
+	frameTraffic           = frameType("traffic")
+	// frameMetaMigrationApplied is the reply to frameMetaMigration
+	frameMetaMigrationApplied = frameType("meta-migration-applied")
+)
+
+// frame is the unit of the back-channel protocol. Frames are encoded as
+// newline delimited JSON objects.
+type frame struct {
+	Tp       frameType `json:"tp"`
+	Topic    string    `json:"topic,omitempty"`
+	Node     string    `json:"node,omitempty"`
+	Blocking bool      `json:"blocking,omitempty"`
+	// Payload is a message encoded by p2p.EncodeMessageValue, which is
+	// forwarded by the executor without being decoded.
+	Payload []byte      `json:"payload,omitempty"`
+	Error   string      `json:"error,omitempty"`
+	Spec    *WorkerSpec `json:"spec,omitempty"`
+	// Traffic is the metastore traffic of the worker since the last
+	// traffic frame.
+	Traffic *traffic.Stat `json:"traffic,omitempty"`
+	// MetaMigration is the state of the migration of the framework
+	// metastore, which the worker process follows.
+	MetaMigration *migration.State `json:"meta-migration,omitempty"`
+}
+
+// WorkerSpec contains everything a worker process needs to construct
+// the worker on its own side. A warm worker process is prepared with a
+// WorkerSpec carrying the environment only, that is, the worker type and
+// the fields from NodeID on, see Pool.
+type WorkerSpec struct {
+	WorkerID        libModel.WorkerID   `json:"worker-id"`
+	MasterID        libModel.MasterID   `json:"master-id"`
+	WorkerType      libModel.WorkerType `json:"worker-type"`
+	WorkerConfig    []byte              `json:"worker-config"`
+	MasterMetaBytes []byte              `json:"master-meta"`
+
+	// Env is set in the worker process before creating the worker.
+	Env         map[string]string `json:"env,omitempty"`
+	ResourceIDs []string          `json:"resource-ids,omitempty"`
+	TenantID    string            `json:"tenant-id,omitempty"`
+	ProjectID   string            `json:"project-id,omitempty"`
+
+	NodeID string `json:"node-id"`
+	Addr   string `json:"addr"`
+	// Join is the server master addresses, used by job masters to
+	// schedule their workers.
+	Join []string `json:"join"`
+
+	FrameMetaConf metaclient.StoreConfigParams `json:"frame-meta-conf"`
+	UserMetaConf  metaclient.StoreConfigParams `json:"user-meta-conf"`
+
+	// Storage is the config of the local file resources of the executor,
+	// the worker process opens the resources of the worker in it.
+	Storage storagecfg.Config `json:"storage"`
+	// UserMetaEncryption carries the paths of the keys rather than the keys,
+	// the worker process loads them on its own.
+	UserMetaEncryption encryption.Config `json:"user-meta-encryption"`
+}
+
+// conn wraps a stream with a frame codec. Writes are serialized so that
+// multiple goroutines can send frames concurrently.
+type conn struct {
+	rwc io.ReadWriteCloser
+
+	readMu sync.Mutex
+	reader *bufio.Reader
+
+	writeMu sync.Mutex
+	encoder *json.Encoder
+}
+
+func newConn(rwc io.ReadWriteCloser) *conn {
+	return &conn{
+		rwc:     rwc,
+		reader:  bufio.NewReader(rwc),
+		encoder: json.NewEncoder(rwc),
+	}
+}
+
+func (c *conn) WriteFrame(f *frame) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	return errors.Trace(c.encoder.Encode(f))
+}
+
+func (c *conn) ReadFrame() (*frame, error) {
+	c.readMu.Lock()
+	defer c.readMu.Unlock()
+
+	line, err := c.reader.ReadBytes('\n')
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	f := &frame{}
+	if err := json.Unmarshal(line, f); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return f, nil
+}
+
+func (c *conn) Close() error {
+	return c.rwc.Close()
+}
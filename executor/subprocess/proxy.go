@@ -0,0 +1,177 @@
+package subprocess
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	"github.com/hanfei1991/microcosm/pkg/p2p"
+)
+
+// proxyMessageSender implements p2p.MessageSender inside a worker process.
+// Messages are forwarded to the executor, which owns the real p2p router.
+type proxyMessageSender struct {
+	conn *conn
+}
+
+func newProxyMessageSender(conn *conn) *proxyMessageSender {
+	return &proxyMessageSender{conn: conn}
+}
+
+// SendToNode implements p2p.MessageSender.SendToNode
+func (s *proxyMessageSender) SendToNode(
+	ctx context.Context, targetNodeID p2p.NodeID, topic p2p.Topic, message interface{},
+) (bool, error) {
+	if err := s.send(targetNodeID, topic, message, false); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// SendToNodeB implements p2p.MessageSender.SendToNodeB
+func (s *proxyMessageSender) SendToNodeB(
+	ctx context.Context, targetNodeID p2p.NodeID, topic p2p.Topic, message interface{},
+) error {
+	return s.send(targetNodeID, topic, message, true)
+}
+
+func (s *proxyMessageSender) send(
+	target p2p.NodeID, topic p2p.Topic, message interface{}, blocking bool,
+) error {
+	payload, err := p2p.EncodeMessageValue(topic, message)
+	if err != nil {
+		return err
+	}
+	return s.conn.WriteFrame(&frame{
+		Tp:       frameSendMessage,
+		Topic:    topic,
+		Node:     target,
+		Blocking: blocking,
+		Payload:  payload,
+	})
+}
+
+type proxyHandlerEntry struct {
+	tpi reflect.Type
+	fn  p2p.HandlerFunc
+}
+
+// proxyHandlerManager implements p2p.MessageHandlerManager inside a worker
+// process. The executor registers the same topics on the real message server
+// and delivers the encoded payloads back, which are decoded here using the
+// type information given at registration.
+type proxyHandlerManager struct {
+	conn *conn
+
+	mu       sync.Mutex
+	handlers map[p2p.Topic]*proxyHandlerEntry
+	errCh    chan error
+}
+
+func newProxyHandlerManager(conn *conn) *proxyHandlerManager {
+	return &proxyHandlerManager{
+		conn:     conn,
+		handlers: make(map[p2p.Topic]*proxyHandlerEntry),
+		errCh:    make(chan error, 1),
+	}
+}
+
+// RegisterHandler implements p2p.MessageHandlerManager.RegisterHandler
+func (m *proxyHandlerManager) RegisterHandler(
+	ctx context.Context, topic p2p.Topic, tpi p2p.TypeInformation, fn p2p.HandlerFunc,
+) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.handlers[topic]; exists {
+		return false, nil
+	}
+	if err := m.conn.WriteFrame(&frame{Tp: frameRegisterHandler, Topic: topic}); err != nil {
+		return false, err
+	}
+	m.handlers[topic] = &proxyHandlerEntry{
+		tpi: reflect.TypeOf(tpi),
+		fn:  fn,
+	}
+	return true, nil
+}
+
+// UnregisterHandler implements p2p.MessageHandlerManager.UnregisterHandler
+func (m *proxyHandlerManager) UnregisterHandler(ctx context.Context, topic p2p.Topic) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.handlers[topic]; !exists {
+		return false, nil
+	}
+	if err := m.conn.WriteFrame(&frame{Tp: frameUnregisterHandler, Topic: topic}); err != nil {
+		return false, err
+	}
+	delete(m.handlers, topic)
+	return true, nil
+}
+
+// CheckError implements p2p.MessageHandlerManager.CheckError
+func (m *proxyHandlerManager) CheckError(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return errors.Trace(ctx.Err())
+	case err := <-m.errCh:
+		return err
+	default:
+	}
+	return nil
+}
+
+// Clean implements p2p.MessageHandlerManager.Clean
+func (m *proxyHandlerManager) Clean(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for topic := range m.handlers {
+		if err := m.conn.WriteFrame(&frame{Tp: frameUnregisterHandler, Topic: topic}); err != nil {
+			return err
+		}
+		delete(m.handlers, topic)
+	}
+	return nil
+}
+
+// SetTimeout implements p2p.MessageHandlerManager.SetTimeout.
+// Handler operations are asynchronous in the proxy, so the timeout is ignored.
+func (m *proxyHandlerManager) SetTimeout(timeout time.Duration) {}
+
+// deliver is called when the executor forwards a message to the worker process.
+func (m *proxyHandlerManager) deliver(sender p2p.NodeID, topic p2p.Topic, payload []byte) {
+	m.mu.Lock()
+	entry, exists := m.handlers[topic]
+	m.mu.Unlock()
+
+	if !exists {
+		log.L().Debug("message dropped for unregistered topic",
+			zap.String("topic", topic), zap.String("sender", sender))
+		return
+	}
+
+	value := reflect.New(entry.tpi.Elem()).Interface()
+	if err := p2p.DecodeMessageValue(payload, value); err != nil {
+		m.onError(err)
+		return
+	}
+	if err := entry.fn(sender, value); err != nil {
+		m.onError(err)
+	}
+}
+
+func (m *proxyHandlerManager) onError(err error) {
+	select {
+	case m.errCh <- err:
+	default:
+		log.L().Warn("handler error dropped", zap.Error(err))
+	}
+}
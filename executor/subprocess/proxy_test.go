@@ -0,0 +1,117 @@
+package subprocess
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hanfei1991/microcosm/pkg/p2p"
+)
+
+type testMessage struct {
+	Value int `json:"value"`
+}
+
+func newTestConnPair() (*conn, *conn) {
+	executorSide, workerSide := net.Pipe()
+	return newConn(executorSide), newConn(workerSide)
+}
+
+func TestProxyMessageSender(t *testing.T) {
+	t.Parallel()
+
+	executorConn, workerConn := newTestConnPair()
+	defer executorConn.Close()
+	defer workerConn.Close()
+
+	sender := newProxyMessageSender(workerConn)
+	type sendResult struct {
+		ok  bool
+		err error
+	}
+	resultCh := make(chan sendResult, 1)
+	go func() {
+		ok, err := sender.SendToNode(context.Background(), "executor-1", "topic-1", &testMessage{Value: 1})
+		resultCh <- sendResult{ok: ok, err: err}
+	}()
+
+	f, err := executorConn.ReadFrame()
+	require.NoError(t, err)
+	require.Equal(t, frameSendMessage, f.Tp)
+	require.Equal(t, "executor-1", f.Node)
+	require.Equal(t, "topic-1", f.Topic)
+	require.False(t, f.Blocking)
+	require.JSONEq(t, `{"value":1}`, string(f.Payload))
+
+	result := <-resultCh
+	require.NoError(t, result.err)
+	require.True(t, result.ok)
+}
+
+func TestProxyHandlerManager(t *testing.T) {
+	t.Parallel()
+
+	executorConn, workerConn := newTestConnPair()
+	defer executorConn.Close()
+	defer workerConn.Close()
+
+	manager := newProxyHandlerManager(workerConn)
+	type delivery struct {
+		sender p2p.NodeID
+		value  *testMessage
+	}
+	received := make(chan delivery, 1)
+	registered := make(chan error, 1)
+	go func() {
+		ok, err := manager.RegisterHandler(context.Background(), "topic-1", &testMessage{},
+			func(sender p2p.NodeID, value p2p.MessageValue) error {
+				received <- delivery{sender: sender, value: value.(*testMessage)}
+				return nil
+			})
+		if err == nil && !ok {
+			err = errors.New("handler is not registered")
+		}
+		registered <- err
+	}()
+
+	f, err := executorConn.ReadFrame()
+	require.NoError(t, err)
+	require.Equal(t, frameRegisterHandler, f.Tp)
+	require.Equal(t, "topic-1", f.Topic)
+	require.NoError(t, <-registered)
+
+	// registering the same topic again is rejected locally
+	require.Eventually(t, func() bool {
+		manager.mu.Lock()
+		defer manager.mu.Unlock()
+		_, ok := manager.handlers["topic-1"]
+		return ok
+	}, defaultCloseTimeout, defaultPollInterval)
+	ok, err := manager.RegisterHandler(context.Background(), "topic-1", &testMessage{},
+		func(p2p.NodeID, p2p.MessageValue) error { return nil })
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	payload, err := p2p.EncodeMessageValue("topic-1", &testMessage{Value: 2})
+	require.NoError(t, err)
+	manager.deliver("executor-2", "topic-1", payload)
+	require.Equal(t, delivery{sender: "executor-2", value: &testMessage{Value: 2}}, <-received)
+
+	// malformed payload is reported by CheckError
+	manager.deliver("executor-2", "topic-1", []byte("not json"))
+	require.Error(t, manager.CheckError(context.Background()))
+	require.NoError(t, manager.CheckError(context.Background()))
+
+	cleaned := make(chan error, 1)
+	go func() {
+		cleaned <- manager.Clean(context.Background())
+	}()
+	f, err = executorConn.ReadFrame()
+	require.NoError(t, err)
+	require.Equal(t, frameUnregisterHandler, f.Tp)
+	require.Equal(t, "topic-1", f.Topic)
+	require.NoError(t, <-cleaned)
+}
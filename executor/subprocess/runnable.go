@@ -0,0 +1,416 @@
+package subprocess
+
+import (
+	"context"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/pkg/errctx"
+	derrors "github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/logutil"
+	"github.com/hanfei1991/microcosm/pkg/p2p"
+	"github.com/hanfei1991/microcosm/pkg/traffic"
+)
+
+const (
+	defaultCloseTimeout = 10 * time.Second
+	defaultSendTimeout  = 5 * time.Second
+	// defaultBridgeDrainTimeout is how long the frames left by an exited
+	// worker process are waited to be handled.
+	defaultBridgeDrainTimeout = time.Second
+)
+
+// Runnable runs a worker in a separate process. It implements
+// worker.Runnable, so that it can be scheduled by the executor's
+// TaskRunner in the same way as an in-process worker.
+//
+// The executor keeps owning the p2p message server and router. The worker
+// process registers handlers and sends messages through the back-channel,
+// and Runnable bridges them to the real p2p components.
+//
+// A Runnable taken from a Pool has its worker process warmed up before the
+// worker is assigned, see Pool.
+type Runnable struct {
+	// mu protects the fields assigned when a warm Runnable is taken, which
+	// are read by the bridge.
+	mu             sync.Mutex
+	spec           *WorkerSpec
+	handlerManager p2p.MessageHandlerManager
+	messageSender  p2p.MessageSender
+	recordTraffic  TrafficRecorder
+
+	sockDir  string
+	listener net.Listener
+	cmd      *exec.Cmd
+	conn     *conn
+
+	preparedCh    chan struct{}
+	initializedCh chan struct{}
+	exitedCh      chan struct{}
+	bridgeDoneCh  chan struct{}
+	closeOnce     sync.Once
+
+	// metaMigrationMu serializes the states of the metastore migration
+	// sent to the worker process, whose replies are sent to
+	// metaMigrationAppliedCh.
+	metaMigrationMu        sync.Mutex
+	metaMigrationAppliedCh chan string
+
+	errCenter *errctx.ErrCenter
+}
+
+// TrafficRecorder records the metastore traffic reported by the worker
+// process, which connects to the metastores on its own.
+type TrafficRecorder func(stat traffic.Stat)
+
+// NewRunnable creates a new Runnable. The worker process is not started
+// until Init is called.
+func NewRunnable(
+	spec *WorkerSpec,
+	handlerManager p2p.MessageHandlerManager,
+	messageSender p2p.MessageSender,
+	recordTraffic TrafficRecorder,
+) *Runnable {
+	return &Runnable{
+		spec:           spec,
+		handlerManager: handlerManager,
+		messageSender:  messageSender,
+		recordTraffic:  recordTraffic,
+		preparedCh:     make(chan struct{}),
+		initializedCh:  make(chan struct{}),
+		exitedCh:       make(chan struct{}),
+		bridgeDoneCh:   make(chan struct{}),
+		errCenter:      errctx.NewErrCenter(),
+
+		metaMigrationAppliedCh: make(chan string, 1),
+	}
+}
+
+// ID implements worker.Runnable.ID
+func (r *Runnable) ID() libModel.WorkerID {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.spec.WorkerID
+}
+
+// Init implements worker.Runnable.Init. It starts the worker process unless
+// it is warmed up, and waits for the worker in it to be initialized.
+func (r *Runnable) Init(ctx context.Context) error {
+	if r.conn == nil {
+		if err := r.start(ctx); err != nil {
+			return err
+		}
+	}
+	if err := r.conn.WriteFrame(&frame{Tp: frameWorkerSpec, Spec: r.getSpec()}); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		return errors.Trace(ctx.Err())
+	case <-r.exitedCh:
+		return r.errCenter.CheckError()
+	case <-r.initializedCh:
+	}
+	return nil
+}
+
+// warmUp starts the worker process and waits for it to prepare the
+// environment in spec, such as connecting to the metastores and the server
+// master, before any worker is assigned.
+func (r *Runnable) warmUp(ctx context.Context) error {
+	if err := r.start(ctx); err != nil {
+		return err
+	}
+	if err := r.conn.WriteFrame(&frame{Tp: framePrepare, Spec: r.getSpec()}); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		return errors.Trace(ctx.Err())
+	case <-r.exitedCh:
+		return r.errCenter.CheckError()
+	case <-r.preparedCh:
+	}
+	return nil
+}
+
+// assign assigns the worker in spec to a warm Runnable, and the p2p
+// components and the traffic recorder of the job of the worker.
+func (r *Runnable) assign(
+	spec *WorkerSpec,
+	handlerManager p2p.MessageHandlerManager,
+	messageSender p2p.MessageSender,
+	recordTraffic TrafficRecorder,
+) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spec = spec
+	r.handlerManager = handlerManager
+	r.messageSender = messageSender
+	r.recordTraffic = recordTraffic
+}
+
+// exited returns whether the worker process has exited.
+func (r *Runnable) exited() bool {
+	select {
+	case <-r.exitedCh:
+		return true
+	default:
+		return false
+	}
+}
+
+func (r *Runnable) getSpec() *WorkerSpec {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.spec
+}
+
+func (r *Runnable) getHandlerManager() p2p.MessageHandlerManager {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.handlerManager
+}
+
+func (r *Runnable) getMessageSender() p2p.MessageSender {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.messageSender
+}
+
+func (r *Runnable) getTrafficRecorder() TrafficRecorder {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.recordTraffic
+}
+
+// start starts the worker process and accepts its back-channel.
+func (r *Runnable) start(ctx context.Context) error {
+	sockDir, err := os.MkdirTemp("", "worker-process-")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	r.sockDir = sockDir
+	sockPath := filepath.Join(sockDir, "back-channel.sock")
+
+	r.listener, err = net.Listen("unix", sockPath)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	executable, err := workerBinary(r.getSpec().WorkerType)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	r.cmd = exec.Command(executable, WorkerProcessArg, "--socket", sockPath)
+	r.cmd.Stdout = os.Stdout
+	r.cmd.Stderr = os.Stderr
+	if err := r.cmd.Start(); err != nil {
+		return errors.Trace(err)
+	}
+	r.logger().Info("worker process started", zap.Int("pid", r.cmd.Process.Pid))
+
+	go func() {
+		err := r.cmd.Wait()
+		// the frames written before exiting, such as the one carrying the
+		// error of the worker, are handled before the exit is reported.
+		select {
+		case <-r.bridgeDoneCh:
+		case <-time.After(defaultBridgeDrainTimeout):
+		}
+		if err == nil {
+			err = errors.New("exit status 0")
+		}
+		r.errCenter.OnError(derrors.ErrWorkerProcessExited.GenWithStackByArgs(r.ID(), err.Error()))
+		removeProcess(r)
+		close(r.exitedCh)
+	}()
+
+	rawConn, err := r.accept(ctx)
+	if err != nil {
+		return err
+	}
+	r.conn = newConn(rawConn)
+
+	go r.bridge()
+	// the worker process follows the migration of the framework metastore
+	// before connecting to it
+	return addProcess(r)
+}
+
+func (r *Runnable) accept(ctx context.Context) (net.Conn, error) {
+	type acceptResult struct {
+		conn net.Conn
+		err  error
+	}
+	resultCh := make(chan acceptResult, 1)
+	go func() {
+		conn, err := r.listener.Accept()
+		resultCh <- acceptResult{conn: conn, err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, errors.Trace(ctx.Err())
+	case <-r.exitedCh:
+		return nil, r.errCenter.CheckError()
+	case result := <-resultCh:
+		return result.conn, errors.Trace(result.err)
+	}
+}
+
+// Poll implements worker.Runnable.Poll
+func (r *Runnable) Poll(ctx context.Context) error {
+	if err := r.getHandlerManager().CheckError(ctx); err != nil {
+		// Errors of handlers are reported to the worker process,
+		// which decides whether the worker should exit.
+		if err := r.conn.WriteFrame(&frame{Tp: frameHandlerError, Error: err.Error()}); err != nil {
+			return err
+		}
+	}
+	return r.errCenter.CheckError()
+}
+
+// Close implements worker.Runnable.Close. It asks the worker process to
+// exit, and kills it if it does not exit in time.
+func (r *Runnable) Close(ctx context.Context) error {
+	var err error
+	r.closeOnce.Do(func() {
+		err = r.doClose(ctx)
+	})
+	return err
+}
+
+func (r *Runnable) doClose(ctx context.Context) error {
+	if r.conn != nil {
+		if err := r.conn.WriteFrame(&frame{Tp: frameCloseWorker}); err != nil {
+			r.logger().Warn("failed to send close frame to worker process", zap.Error(err))
+		}
+	}
+
+	if r.cmd != nil && r.cmd.Process != nil {
+		select {
+		case <-r.exitedCh:
+		case <-time.After(defaultCloseTimeout):
+			r.logger().Warn("worker process does not exit in time, kill it")
+			if err := r.cmd.Process.Kill(); err != nil {
+				r.logger().Warn("failed to kill worker process", zap.Error(err))
+			}
+			<-r.exitedCh
+		}
+	}
+
+	if r.conn != nil {
+		_ = r.conn.Close()
+	}
+	if r.listener != nil {
+		_ = r.listener.Close()
+	}
+	if r.sockDir != "" {
+		_ = os.RemoveAll(r.sockDir)
+	}
+	// a warm Runnable closed before being taken has no handler manager
+	if handlerManager := r.getHandlerManager(); handlerManager != nil {
+		return handlerManager.Clean(ctx)
+	}
+	return nil
+}
+
+func (r *Runnable) logger() log.Logger {
+	return logutil.WithWorkerID(log.L(), r.ID())
+}
+
+// bridge forwards frames from the worker process to the p2p components.
+func (r *Runnable) bridge() {
+	defer close(r.bridgeDoneCh)
+	var prepareOnce, initializeOnce sync.Once
+	for {
+		f, err := r.conn.ReadFrame()
+		if err != nil {
+			r.errCenter.OnError(derrors.ErrWorkerProcessExited.GenWithStackByArgs(r.ID(), err.Error()))
+			return
+		}
+
+		switch f.Tp {
+		case frameRegisterHandler:
+			r.registerHandler(f.Topic)
+		case frameUnregisterHandler:
+			if _, err := r.getHandlerManager().UnregisterHandler(context.Background(), f.Topic); err != nil {
+				r.logger().Warn("failed to unregister handler",
+					zap.String("topic", f.Topic), zap.Error(err))
+			}
+		case frameSendMessage:
+			r.sendMessage(f)
+		case frameWorkerPrepared:
+			prepareOnce.Do(func() {
+				close(r.preparedCh)
+			})
+		case frameWorkerInitialized:
+			initializeOnce.Do(func() {
+				close(r.initializedCh)
+			})
+		case frameWorkerExited:
+			r.errCenter.OnError(derrors.ErrWorkerProcessExited.GenWithStackByArgs(r.ID(), f.Error))
+		case frameMetaMigrationApplied:
+			select {
+			case r.metaMigrationAppliedCh <- f.Error:
+			default:
+			}
+		case frameTraffic:
+			if recordTraffic := r.getTrafficRecorder(); recordTraffic != nil && f.Traffic != nil {
+				recordTraffic(*f.Traffic)
+			}
+		default:
+			r.errCenter.OnError(derrors.ErrWorkerProcessProtocol.GenWithStackByArgs(f.Tp))
+		}
+	}
+}
+
+func (r *Runnable) registerHandler(topic p2p.Topic) {
+	_, err := r.getHandlerManager().RegisterHandler(
+		context.Background(),
+		topic,
+		&p2p.EncodedMessage{},
+		func(sender p2p.NodeID, value p2p.MessageValue) error {
+			return r.conn.WriteFrame(&frame{
+				Tp:      frameDeliverMessage,
+				Topic:   topic,
+				Node:    sender,
+				Payload: *value.(*p2p.EncodedMessage),
+			})
+		})
+	if err != nil {
+		r.logger().Warn("failed to register handler",
+			zap.String("topic", topic), zap.Error(err))
+	}
+}
+
+func (r *Runnable) sendMessage(f *frame) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultSendTimeout)
+	defer cancel()
+
+	var err error
+	msg := p2p.EncodedMessage(f.Payload)
+	if f.Blocking {
+		err = r.getMessageSender().SendToNodeB(ctx, f.Node, f.Topic, &msg)
+	} else {
+		_, err = r.getMessageSender().SendToNode(ctx, f.Node, f.Topic, &msg)
+	}
+	if err != nil {
+		r.logger().Warn("failed to send message for worker process",
+			zap.String("topic", f.Topic),
+			zap.String("target", f.Node),
+			zap.Error(err))
+	}
+}
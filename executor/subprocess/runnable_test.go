@@ -0,0 +1,514 @@
+package subprocess
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/pkg/security"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+	"go.uber.org/dig"
+
+	"github.com/hanfei1991/microcosm/lib"
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/model"
+	"github.com/hanfei1991/microcosm/pkg/clock"
+	dcontext "github.com/hanfei1991/microcosm/pkg/context"
+	"github.com/hanfei1991/microcosm/pkg/deps"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+	"github.com/hanfei1991/microcosm/pkg/orm/migration"
+	"github.com/hanfei1991/microcosm/pkg/p2p"
+	"github.com/hanfei1991/microcosm/pkg/traffic"
+)
+
+const (
+	heartbeatMasterNode   = "master-node"
+	heartbeatExecutorNode = "executor-node"
+
+	echoTopic      = "echo"
+	echoReplyTopic = "echo-reply"
+	echoReplyNode  = "executor-2"
+	echoEnvKey     = "SUBPROCESS_TEST_ENV"
+)
+
+type echoMessage struct {
+	Value int    `json:"value"`
+	Env   string `json:"env,omitempty"`
+}
+
+// echoWorker runs in the worker process started by the tests. It replies
+// to every message with the value increased, and fails on a negative value.
+type echoWorker struct {
+	id       string
+	received chan *echoMessage
+
+	params struct {
+		dig.In
+
+		HandlerManager p2p.MessageHandlerManager
+		MessageSender  p2p.MessageSender
+	}
+}
+
+func (w *echoWorker) Init(ctx context.Context) error {
+	_, err := w.params.HandlerManager.RegisterHandler(ctx, echoTopic, &echoMessage{},
+		func(sender p2p.NodeID, value p2p.MessageValue) error {
+			w.received <- value.(*echoMessage)
+			return nil
+		})
+	return err
+}
+
+func (w *echoWorker) Poll(ctx context.Context) error {
+	select {
+	case msg := <-w.received:
+		if msg.Value < 0 {
+			return errors.New("echo worker fails")
+		}
+		reply := &echoMessage{Value: msg.Value + 1, Env: os.Getenv(echoEnvKey)}
+		_, err := w.params.MessageSender.SendToNode(ctx, echoReplyNode, echoReplyTopic, reply)
+		return err
+	default:
+		return nil
+	}
+}
+
+func (w *echoWorker) ID() string {
+	return w.id
+}
+
+func (w *echoWorker) Workload() model.RescUnit {
+	return 0
+}
+
+func (w *echoWorker) Close(ctx context.Context) error {
+	return nil
+}
+
+// heartbeatWorkerType makes the test worker process run a heartbeatWorker
+// rather than an echoWorker.
+const heartbeatWorkerType = libModel.WorkerType(1000)
+
+// heartbeatWorker runs in the worker process started by the tests. It pings
+// its master until a pong is received, and then pings with IsFinished set.
+type heartbeatWorker struct {
+	id       string
+	masterID string
+	ponged   chan struct{}
+
+	params struct {
+		dig.In
+
+		HandlerManager p2p.MessageHandlerManager
+		MessageSender  p2p.MessageSender
+	}
+}
+
+func (w *heartbeatWorker) Init(ctx context.Context) error {
+	_, err := w.params.HandlerManager.RegisterHandler(ctx,
+		libModel.HeartbeatPongTopic(w.masterID, w.id), &libModel.HeartbeatPongMessage{},
+		func(sender p2p.NodeID, value p2p.MessageValue) error {
+			if value.(*libModel.HeartbeatPongMessage).ToWorkerID != w.id {
+				return errors.New("pong is sent to another worker")
+			}
+			select {
+			case <-w.ponged:
+			default:
+				close(w.ponged)
+			}
+			return nil
+		})
+	return err
+}
+
+func (w *heartbeatWorker) Poll(ctx context.Context) error {
+	ping := &libModel.HeartbeatPingMessage{
+		SendTime:     clock.MonoNow(),
+		FromWorkerID: w.id,
+		Epoch:        1,
+	}
+	select {
+	case <-w.ponged:
+		ping.IsFinished = true
+	default:
+	}
+	_, err := w.params.MessageSender.SendToNode(ctx, heartbeatMasterNode,
+		libModel.HeartbeatPingTopic(w.masterID), ping)
+	return err
+}
+
+func (w *heartbeatWorker) ID() string {
+	return w.id
+}
+
+func (w *heartbeatWorker) Workload() model.RescUnit {
+	return 0
+}
+
+func (w *heartbeatWorker) Close(ctx context.Context) error {
+	return nil
+}
+
+// metaWorkerType makes the test worker process run a metaWorker.
+const metaWorkerType = libModel.WorkerType(1001)
+
+// metaWorker runs in the worker process started by the tests. It writes a
+// job to the framework metastore in every poll.
+type metaWorker struct {
+	id     string
+	writes int
+
+	params struct {
+		dig.In
+
+		FrameMetaClient pkgOrm.Client
+	}
+}
+
+func (w *metaWorker) Init(ctx context.Context) error {
+	return nil
+}
+
+func (w *metaWorker) Poll(ctx context.Context) error {
+	w.writes++
+	return w.params.FrameMetaClient.UpsertJob(ctx, &libModel.MasterMetaKVData{
+		ProjectID: "project-1",
+		ID:        fmt.Sprintf("%s-%d", w.id, w.writes),
+	})
+}
+
+func (w *metaWorker) ID() string {
+	return w.id
+}
+
+func (w *metaWorker) Workload() model.RescUnit {
+	return 0
+}
+
+func (w *metaWorker) Close(ctx context.Context) error {
+	return nil
+}
+
+// runTestWorkerProcess is run when the test binary is started by Runnable,
+// it replaces the metastores and the server master with an echoWorker.
+func runTestWorkerProcess(args []string) int {
+	fs := flag.NewFlagSet(WorkerProcessArg, flag.ContinueOnError)
+	socketPath := fs.String("socket", "", "")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	prepareWorkerEnvFn = func(
+		ctx context.Context, spec *WorkerSpec, conn *conn, handlerManager *proxyHandlerManager, meta *metaFollower,
+	) (*workerEnv, error) {
+		env := &workerEnv{deps: deps.NewDeps(), traffic: traffic.NewAccountant(0, false)}
+		// only the framework metastore in a local file is connected
+		if len(spec.FrameMetaConf.Endpoints) > 0 {
+			var err error
+			if env.frameMetaClient, err = meta.connect(ctx, spec.FrameMetaConf); err != nil {
+				return nil, err
+			}
+			if err := env.deps.Provide(func() pkgOrm.Client { return env.frameMetaClient }); err != nil {
+				return nil, err
+			}
+		}
+		// the metastores are not connected in tests, the traffic of
+		// preparing the env is made up to test the reporting.
+		env.traffic.Record(processTrafficKey, traffic.SourceMetastore, traffic.DirectionSent, 10)
+		if err := env.deps.Provide(func() p2p.MessageHandlerManager { return handlerManager }); err != nil {
+			return nil, err
+		}
+		if err := env.deps.Provide(func() p2p.MessageSender { return newProxyMessageSender(conn) }); err != nil {
+			return nil, err
+		}
+		return env, nil
+	}
+	createWorkerFn = func(ctx *dcontext.Context, spec *WorkerSpec) (lib.Worker, error) {
+		if spec.WorkerType == metaWorkerType {
+			w := &metaWorker{id: spec.WorkerID}
+			if err := ctx.Deps().Fill(&w.params); err != nil {
+				return nil, err
+			}
+			return w, nil
+		}
+		if spec.WorkerType == heartbeatWorkerType {
+			w := &heartbeatWorker{id: spec.WorkerID, masterID: spec.MasterID, ponged: make(chan struct{})}
+			if err := ctx.Deps().Fill(&w.params); err != nil {
+				return nil, err
+			}
+			return w, nil
+		}
+		w := &echoWorker{id: spec.WorkerID, received: make(chan *echoMessage, 16)}
+		if err := ctx.Deps().Fill(&w.params); err != nil {
+			return nil, err
+		}
+		return w, nil
+	}
+
+	err := RunWorkerProcess(context.Background(), *socketPath)
+	if err != nil && errors.Cause(err) != context.Canceled {
+		return 1
+	}
+	return 0
+}
+
+func TestMain(m *testing.M) {
+	if len(os.Args) > 1 && os.Args[1] == WorkerProcessArg {
+		os.Exit(runTestWorkerProcess(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == PluginDescribeArg {
+		os.Exit(describeTestPlugin())
+	}
+	os.Exit(m.Run())
+}
+
+func sendEcho(t *testing.T, handlerManager *p2p.MockMessageHandlerManager, value int) {
+	payload, err := p2p.EncodeMessageValue(echoTopic, &echoMessage{Value: value})
+	require.NoError(t, err)
+	require.NoError(t, handlerManager.InvokeHandler(t, echoTopic, "executor-1", &payload))
+}
+
+func waitEchoReply(t *testing.T, sender *p2p.MockMessageSender) *echoMessage {
+	var payload interface{}
+	require.Eventually(t, func() bool {
+		var ok bool
+		payload, ok = sender.TryPop(echoReplyNode, echoReplyTopic)
+		return ok
+	}, 10*time.Second, 10*time.Millisecond)
+	reply := &echoMessage{}
+	require.NoError(t, p2p.DecodeMessageValue(*payload.(*p2p.EncodedMessage), reply))
+	return reply
+}
+
+func TestRunnableInWorkerProcess(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	handlerManager := p2p.NewMockMessageHandlerManager()
+	sender := p2p.NewMockMessageSender()
+	spec := &WorkerSpec{
+		WorkerID: "worker-1",
+		MasterID: "master-1",
+		Env:      map[string]string{echoEnvKey: "env-value"},
+	}
+	var reported atomic.Int64
+	r := NewRunnable(spec, handlerManager, sender, func(stat traffic.Stat) {
+		reported.Add(stat.SentBytes)
+	})
+	require.NoError(t, r.Init(ctx))
+	// the handler is registered by the worker in its Init
+	handlerManager.AssertHasHandler(t, echoTopic, &p2p.EncodedMessage{})
+	require.NoError(t, r.Poll(ctx))
+
+	// messages are delivered to the worker process and the replies are
+	// sent by the executor, with the env of the worker applied
+	sendEcho(t, handlerManager, 1)
+	require.Equal(t, &echoMessage{Value: 2, Env: "env-value"}, waitEchoReply(t, sender))
+	sendEcho(t, handlerManager, 10)
+	require.Equal(t, &echoMessage{Value: 11, Env: "env-value"}, waitEchoReply(t, sender))
+
+	// the error of the worker is reported when the worker process exits
+	sendEcho(t, handlerManager, -1)
+	require.Eventually(t, func() bool {
+		err := r.Poll(ctx)
+		return err != nil && strings.Contains(err.Error(), "echo worker fails")
+	}, 10*time.Second, 10*time.Millisecond)
+	require.Eventually(t, r.exited, 10*time.Second, 10*time.Millisecond)
+	// the traffic is reported before the worker process exits
+	require.Equal(t, int64(10), reported.Load())
+
+	require.NoError(t, r.Close(ctx))
+	handlerManager.AssertNoHandler(t, echoTopic)
+}
+
+func TestCloseRunnableInWorkerProcess(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	handlerManager := p2p.NewMockMessageHandlerManager()
+	sender := p2p.NewMockMessageSender()
+	// the worker process is warmed up before the worker is assigned
+	r := NewRunnable(&WorkerSpec{NodeID: "executor-1"}, nil, nil, nil)
+	require.NoError(t, r.warmUp(ctx))
+	r.assign(&WorkerSpec{WorkerID: "worker-2", NodeID: "executor-1"}, handlerManager, sender, nil)
+	require.NoError(t, r.Init(ctx))
+	handlerManager.AssertHasHandler(t, echoTopic, &p2p.EncodedMessage{})
+	sendEcho(t, handlerManager, 1)
+	require.Equal(t, &echoMessage{Value: 2}, waitEchoReply(t, sender))
+
+	// the worker process exits on its own when the worker is closed,
+	// rather than being killed after defaultCloseTimeout
+	start := time.Now()
+	require.NoError(t, r.Close(ctx))
+	require.Less(t, time.Since(start), defaultCloseTimeout)
+	require.True(t, r.exited())
+	require.Equal(t, 0, r.cmd.ProcessState.ExitCode())
+	handlerManager.AssertNoHandler(t, echoTopic)
+}
+
+// testP2PNode is a p2p node serving on a local port, which encodes messages
+// in all the encodings supported by its peers.
+type testP2PNode struct {
+	handlerManager p2p.MessageHandlerManager
+	messageSender  p2p.MessageSender
+	router         p2p.MessageRouter
+	peers          *p2p.PeerCodecs
+	addr           string
+}
+
+func newTestP2PNode(ctx context.Context, t *testing.T, nodeID p2p.NodeID) *testP2PNode {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	service, err := p2p.NewMessageRPCService(nodeID, &security.Credential{})
+	require.NoError(t, err)
+	go func() {
+		_ = service.Serve(ctx, l)
+	}()
+
+	addr := l.Addr().String()
+	router := p2p.NewMessageRouter(nodeID, addr)
+	t.Cleanup(router.Close)
+	peers := p2p.NewPeerCodecs()
+	return &testP2PNode{
+		handlerManager: service.MakeHandlerManager(),
+		messageSender:  p2p.NewMessageSenderWithCodecs(router, p2p.DefaultMessageConfig(), peers),
+		router:         router,
+		peers:          peers,
+		addr:           addr,
+	}
+}
+
+func (n *testP2PNode) addPeer(nodeID p2p.NodeID, peer *testP2PNode) {
+	n.router.AddPeer(nodeID, peer.addr)
+	n.peers.Observe(nodeID, p2p.SupportedCodecs())
+}
+
+func TestHeartbeatsInWorkerProcess(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// both nodes decode msgpack, so heartbeats are sent in msgpack
+	masterNode := newTestP2PNode(ctx, t, heartbeatMasterNode)
+	executorNode := newTestP2PNode(ctx, t, heartbeatExecutorNode)
+	masterNode.addPeer(heartbeatExecutorNode, executorNode)
+	executorNode.addPeer(heartbeatMasterNode, masterNode)
+
+	const (
+		masterID = "master-1"
+		workerID = "worker-1"
+	)
+	pings := make(chan *libModel.HeartbeatPingMessage, 16)
+	_, err := masterNode.handlerManager.RegisterHandler(ctx,
+		libModel.HeartbeatPingTopic(masterID), &libModel.HeartbeatPingMessage{},
+		func(sender p2p.NodeID, value p2p.MessageValue) error {
+			select {
+			case pings <- value.(*libModel.HeartbeatPingMessage):
+			default:
+			}
+			return nil
+		})
+	require.NoError(t, err)
+
+	spec := &WorkerSpec{
+		WorkerID:   workerID,
+		MasterID:   masterID,
+		WorkerType: heartbeatWorkerType,
+		NodeID:     heartbeatExecutorNode,
+	}
+	r := NewRunnable(spec, executorNode.handlerManager, executorNode.messageSender, nil)
+	require.NoError(t, r.Init(ctx))
+	defer func() {
+		require.NoError(t, r.Close(context.Background()))
+	}()
+
+	// the pings sent by the worker process are decoded by the master, and
+	// the pongs replied by the master are decoded by the worker process,
+	// which pings with IsFinished set then.
+	for {
+		var ping *libModel.HeartbeatPingMessage
+		select {
+		case <-ctx.Done():
+			require.FailNow(t, "no ping is finished", "last error: %v", r.Poll(ctx))
+		case ping = <-pings:
+		}
+		require.Equal(t, workerID, ping.FromWorkerID)
+		require.Equal(t, libModel.Epoch(1), ping.Epoch)
+		if ping.IsFinished {
+			break
+		}
+		pong := &libModel.HeartbeatPongMessage{
+			SendTime:   ping.SendTime,
+			ReplyTime:  time.Now(),
+			ToWorkerID: workerID,
+			Epoch:      ping.Epoch,
+		}
+		_, err := masterNode.messageSender.SendToNode(ctx, heartbeatExecutorNode,
+			libModel.HeartbeatPongTopic(masterID, workerID), pong)
+		require.NoError(t, err)
+	}
+	require.NoError(t, r.Poll(ctx))
+}
+
+// TestMetaMigrationInWorkerProcess doesn't run in parallel, since the state
+// of the metastore migration is sent to all worker processes.
+func TestMetaMigrationInWorkerProcess(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	defer func() {
+		processes.Lock()
+		processes.state = nil
+		processes.Unlock()
+	}()
+
+	sourceConf, source := pkgOrm.NewSQLiteTestClient(t, "source.db")
+	targetConf, target := pkgOrm.NewSQLiteTestClient(t, "target.db")
+	countJobs := func(cli pkgOrm.Client) int {
+		jobs, err := cli.QueryJobs(ctx)
+		require.NoError(t, err)
+		return len(jobs)
+	}
+
+	spec := &WorkerSpec{
+		WorkerID:      "worker-1",
+		MasterID:      "master-1",
+		WorkerType:    metaWorkerType,
+		FrameMetaConf: sourceConf,
+	}
+	r := NewRunnable(spec, p2p.NewMockMessageHandlerManager(), p2p.NewMockMessageSender(), nil)
+	require.NoError(t, r.Init(ctx))
+	defer func() {
+		require.NoError(t, r.Close(context.Background()))
+	}()
+	require.Eventually(t, func() bool {
+		return countJobs(source) > 0
+	}, 10*time.Second, 10*time.Millisecond)
+
+	// the writes of the worker process are fenced once the state is applied
+	state := migration.State{Phase: migration.PhaseSwitching, Generation: 1, Source: &sourceConf, Target: &targetConf}
+	require.NoError(t, ApplyMetaMigration(ctx, state))
+	fenced := countJobs(source)
+	time.Sleep(200 * time.Millisecond)
+	require.Equal(t, fenced, countJobs(source))
+
+	// and are served by the target metastore after the switch
+	state.Phase, state.Generation = migration.PhaseSwitched, 2
+	require.NoError(t, ApplyMetaMigration(ctx, state))
+	require.Eventually(t, func() bool {
+		return countJobs(target) > 0
+	}, 10*time.Second, 10*time.Millisecond)
+	require.Equal(t, fenced, countJobs(source))
+	require.NoError(t, r.Poll(ctx))
+}
@@ -21,6 +21,13 @@ type Workloader interface {
 	Workload() model.RescUnit
 }
 
+// ResourceUsager defines an interface to get the resource usage in multiple
+// dimensions. Only the usage of the runnables implementing it is accounted
+// by the scheduler of server master.
+type ResourceUsager interface {
+	ResourceUsage() model.RescVector
+}
+
 // RunnableID is a unique id for the runnable
 type RunnableID = string
 
@@ -142,6 +142,13 @@ func (c *TaskCommitter) ConfirmDispatchTask(rID requestID, taskID RunnableID) (o
 	return true, nil
 }
 
+// PendingCount returns the number of tasks pre-dispatched but not confirmed.
+func (c *TaskCommitter) PendingCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.pendingRequests)
+}
+
 // Close terminates the background task of the TaskCommitter.
 func (c *TaskCommitter) Close() {
 	close(c.cancelCh)
@@ -15,6 +15,7 @@ import (
 	"github.com/hanfei1991/microcosm/model"
 	"github.com/hanfei1991/microcosm/pkg/clock"
 	derror "github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/logutil"
 )
 
 // Re-export types for public use
@@ -25,10 +26,19 @@ type (
 	RunnableID = internal.RunnableID
 	// Workloader alias internal.Workloader
 	Workloader = internal.Workloader
+	// ResourceUsager alias internal.ResourceUsager
+	ResourceUsager = internal.ResourceUsager
 	// Closer alias internal.Closer
 	Closer = internal.Closer
 )
 
+// TaskListener is notified when a task starts running and when it stops,
+// the calls for the same task are never concurrent.
+type TaskListener interface {
+	OnTaskStarted(id RunnableID)
+	OnTaskStopped(id RunnableID)
+}
+
 // TaskRunner receives RunnableContainer in a FIFO way, and runs them in
 // independent background goroutines.
 type TaskRunner struct {
@@ -41,8 +51,14 @@ type TaskRunner struct {
 	canceled bool
 
 	taskCount atomic.Int64
-
-	clock clock.Clock
+	// queued is the number of tasks added but waiting for the init quota,
+	// initializing is the number of tasks holding the init quota.
+	queued          atomic.Int64
+	initializing    atomic.Int64
+	initConcurrency int64
+
+	listener TaskListener
+	clock    clock.Clock
 }
 
 const (
@@ -75,37 +91,65 @@ func (e *taskEntry) EventLoop(ctx context.Context) error {
 // NewTaskRunner creates a new TaskRunner instance
 func NewTaskRunner(inQueueSize int, initConcurrency int) *TaskRunner {
 	return &TaskRunner{
-		inQueue:       make(chan *internal.RunnableContainer, inQueueSize),
-		initQuotaSema: semaphore.NewWeighted(int64(initConcurrency)),
-		clock:         clock.New(),
+		inQueue:         make(chan *internal.RunnableContainer, inQueueSize),
+		initQuotaSema:   semaphore.NewWeighted(int64(initConcurrency)),
+		initConcurrency: int64(initConcurrency),
+		clock:           clock.New(),
 	}
 }
 
+// SetTaskListener sets the listener of the tasks, it should be called
+// before Run.
+func (r *TaskRunner) SetTaskListener(listener TaskListener) {
+	r.listener = listener
+}
+
 // AddTask enqueues a naked task, and AddTask will wrap the task with internal.WrapRunnable.
 // Deprecated. TODO Will be removed once two-phase task dispatching is enabled.
 func (r *TaskRunner) AddTask(task Runnable) error {
 	wrappedTask := internal.WrapRunnable(task, r.clock.Now())
-	select {
-	case r.inQueue <- wrappedTask:
-		return nil
-	default:
-	}
-
-	return derror.ErrRuntimeIncomingQueueFull.GenWithStackByArgs()
+	return r.addWrappedTask(wrappedTask)
 }
 
 // addWrappedTask enqueues a task already wrapped by internal.WrapRunnable.
 // NOTE: internal.RunnableContainer contains the submit-time for the task.
 func (r *TaskRunner) addWrappedTask(task *internal.RunnableContainer) error {
+	// queued is increased before enqueuing, so that it never goes negative
+	// after the task is received by Run.
+	r.queued.Inc()
 	select {
 	case r.inQueue <- task:
 		return nil
 	default:
 	}
+	r.queued.Dec()
 
 	return derror.ErrRuntimeIncomingQueueFull.GenWithStackByArgs()
 }
 
+// Admit checks whether the queue has room for another task, reserved is the
+// number of tasks admitted but not added yet, such as the pre-dispatched
+// ones. The tasks rejected should be scheduled to other executors.
+func (r *TaskRunner) Admit(reserved int) error {
+	if r.queued.Load()+int64(reserved) >= int64(cap(r.inQueue)) {
+		return derror.ErrRuntimeIncomingQueueFull.GenWithStackByArgs()
+	}
+	return nil
+}
+
+// QueueLength returns the number of tasks waiting to be initialized.
+func (r *TaskRunner) QueueLength() int64 {
+	return r.queued.Load()
+}
+
+// WouldQueue returns whether a task added now would wait for the init quota,
+// and the number of tasks ahead of it. It is a hint since the tasks ahead
+// may be initialized concurrently.
+func (r *TaskRunner) WouldQueue() (queued bool, ahead int64) {
+	ahead = r.queued.Load()
+	return ahead > 0 || r.initializing.Load() >= r.initConcurrency, ahead
+}
+
 // Run runs forever until context is canceled or task queue is closed.
 // It receives new added task and call onNewTask with task
 func (r *TaskRunner) Run(ctx context.Context) error {
@@ -147,9 +191,48 @@ func (r *TaskRunner) Workload() (ret model.RescUnit) {
 	return
 }
 
+// ResourceUsage returns the total resource usage of the running tasks
+// implementing ResourceUsager.
+func (r *TaskRunner) ResourceUsage() model.RescVector {
+	ret := model.RescVector{}
+	r.tasks.Range(func(key, value interface{}) bool {
+		container := value.(*taskEntry).RunnableContainer
+		if container.Status() != internal.TaskRunning {
+			return true
+		}
+		usager, ok := container.Runnable.(ResourceUsager)
+		if !ok {
+			return true
+		}
+		ret = ret.Add(usager.ResourceUsage())
+		return true
+	})
+	return ret
+}
+
+// Drain stops accepting new tasks and cancels all running tasks, so that
+// they can checkpoint and exit. It waits for the tasks to exit until ctx
+// is done.
+func (r *TaskRunner) Drain(ctx context.Context) error {
+	drained := make(chan struct{})
+	go func() {
+		r.cancelAll()
+		close(drained)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return errors.Trace(ctx.Err())
+	case <-drained:
+		return nil
+	}
+}
+
 func (r *TaskRunner) cancelAll() {
 	r.cancelMu.Lock()
 	if r.canceled {
+		r.cancelMu.Unlock()
+		r.wg.Wait()
 		return
 	}
 	r.canceled = true
@@ -171,16 +254,18 @@ func (r *TaskRunner) onNewTask(ctx context.Context, task *internal.RunnableConta
 	defer cancelTimeout()
 
 	err := r.initQuotaSema.Acquire(timeoutCtx, defaultTaskWeight)
+	r.queued.Dec()
 	if err != nil {
 		return derror.ErrRuntimeInitQueuingTimeOut.Wrap(err).GenWithStackByArgs()
 	}
+	r.initializing.Inc()
 
 	defer func() {
 		if r := recover(); r != nil {
 			ret = errors.Trace(errors.Errorf("panic: %v", r))
 		}
 		if ret != nil {
-			r.initQuotaSema.Release(defaultTaskWeight)
+			r.releaseInitQuota()
 		}
 	}()
 
@@ -206,12 +291,15 @@ func (r *TaskRunner) onNewTask(ctx context.Context, task *internal.RunnableConta
 	}
 
 	r.taskCount.Inc()
+	if r.listener != nil {
+		r.listener.OnTaskStarted(task.ID())
+	}
 	runInit := func(initCtx context.Context) (ret error) {
 		defer func() {
 			if r := recover(); r != nil {
 				ret = errors.Trace(errors.Errorf("panic: %v", r))
 			}
-			r.initQuotaSema.Release(defaultTaskWeight)
+			r.releaseInitQuota()
 		}()
 
 		if err := t.Init(initCtx); err != nil {
@@ -221,6 +309,7 @@ func (r *TaskRunner) onNewTask(ctx context.Context, task *internal.RunnableConta
 		return nil
 	}
 
+	logger := logutil.WithWorkerID(log.L(), t.ID())
 	r.wg.Add(1)
 	go func() {
 		defer r.wg.Done()
@@ -228,33 +317,48 @@ func (r *TaskRunner) onNewTask(ctx context.Context, task *internal.RunnableConta
 
 		defer func() {
 			err := t.Close(rctx)
-			log.L().Info("Task Closed",
-				zap.String("id", t.ID()),
+			logger.Info("Task Closed",
 				zap.Error(err),
 				zap.Int64("runtime-task-count", r.taskCount.Load()))
 			t.OnStopped()
 
 			if _, ok := r.tasks.LoadAndDelete(t.ID()); !ok {
-				log.L().Panic("Task does not exist", zap.String("id", t.ID()))
+				logger.Panic("Task does not exist")
+			}
+			if r.listener != nil {
+				r.listener.OnTaskStopped(t.ID())
 			}
 		}()
 
 		if err := runInit(rctx); err != nil {
-			log.L().Warn("Task init returned error", zap.String("id", t.ID()), zap.Error(err))
+			logger.Warn("Task init returned error", zap.Error(err))
 			return
 		}
 
-		log.L().Info("Task initialized",
-			zap.String("id", t.ID()),
+		logger.Info("Task initialized",
 			zap.Int64("runtime-task-count", r.taskCount.Load()))
 
 		err := t.EventLoop(rctx)
-		log.L().Info("Task stopped", zap.String("id", t.ID()), zap.Error(err))
+		logger.Info("Task stopped", zap.Error(err))
 	}()
 
 	return nil
 }
 
+func (r *TaskRunner) releaseInitQuota() {
+	r.initializing.Dec()
+	r.initQuotaSema.Release(defaultTaskWeight)
+}
+
+// GetTask returns the running task with the given ID
+func (r *TaskRunner) GetTask(id RunnableID) (Runnable, bool) {
+	value, ok := r.tasks.Load(id)
+	if !ok {
+		return nil, false
+	}
+	return value.(*taskEntry).Runnable, true
+}
+
 // TaskCount returns current task count
 func (r *TaskRunner) TaskCount() int64 {
 	return r.taskCount.Load()
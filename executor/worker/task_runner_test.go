@@ -10,6 +10,7 @@ import (
 	"github.com/hanfei1991/microcosm/pkg/clock"
 
 	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
 )
 
 const (
@@ -19,11 +20,26 @@ const (
 // TaskRunner must implement WrappedTaskAdder for TaskCommitter to work.
 var _ WrappedTaskAdder = &TaskRunner{}
 
+type countingListener struct {
+	started atomic.Int64
+	stopped atomic.Int64
+}
+
+func (l *countingListener) OnTaskStarted(id RunnableID) {
+	l.started.Inc()
+}
+
+func (l *countingListener) OnTaskStopped(id RunnableID) {
+	l.stopped.Inc()
+}
+
 func TestTaskRunnerBasics(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	tr := NewTaskRunner(workerNum+1, 1)
+	listener := &countingListener{}
+	tr.SetTaskListener(listener)
 	var wg sync.WaitGroup
 	wg.Add(1)
 	go func() {
@@ -55,6 +71,10 @@ func TestTaskRunnerBasics(t *testing.T) {
 	require.Eventually(t, func() bool {
 		return tr.Workload() == 0
 	}, 1*time.Second, 100*time.Millisecond)
+	require.Eventually(t, func() bool {
+		return listener.stopped.Load() == workerNum
+	}, 1*time.Second, 10*time.Millisecond)
+	require.Equal(t, int64(workerNum), listener.started.Load())
 
 	cancel()
 	wg.Wait()
@@ -104,6 +124,61 @@ func TestTaskRunnerInitBlocked(t *testing.T) {
 	wg.Wait()
 }
 
+func TestTaskRunnerAdmission(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tr := NewTaskRunner(2, 1)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = tr.Run(ctx)
+	}()
+
+	queued, ahead := tr.WouldQueue()
+	require.False(t, queued)
+	require.Equal(t, int64(0), ahead)
+	require.NoError(t, tr.Admit(1))
+	require.Error(t, tr.Admit(2))
+
+	var workers []*dummyWorker
+	for i := 0; i < 3; i++ {
+		worker := newDummyWorker(fmt.Sprintf("worker-%d", i))
+		worker.BlockInit()
+		workers = append(workers, worker)
+		require.NoError(t, tr.AddTask(worker))
+		if i == 0 {
+			// the first worker holds the only init quota
+			require.Eventually(t, func() bool {
+				return tr.QueueLength() == 0
+			}, time.Second, time.Millisecond)
+			queued, ahead = tr.WouldQueue()
+			require.True(t, queued)
+			require.Equal(t, int64(0), ahead)
+		}
+	}
+	require.Equal(t, int64(2), tr.QueueLength())
+	queued, ahead = tr.WouldQueue()
+	require.True(t, queued)
+	require.Equal(t, int64(2), ahead)
+	err := tr.Admit(0)
+	require.Error(t, err)
+	require.Regexp(t, ".*ErrRuntimeIncomingQueueFull.*", err.Error())
+
+	for _, worker := range workers {
+		worker.UnblockInit()
+	}
+	require.Eventually(t, func() bool {
+		return tr.Workload() == 3
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, int64(0), tr.QueueLength())
+	require.NoError(t, tr.Admit(0))
+
+	cancel()
+	wg.Wait()
+}
+
 func TestTaskRunnerSubmitTime(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -140,3 +215,40 @@ func TestTaskRunnerSubmitTime(t *testing.T) {
 	cancel()
 	wg.Wait()
 }
+
+func TestTaskRunnerDrain(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tr := NewTaskRunner(workerNum+1, workerNum)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		err := tr.Run(ctx)
+		require.Error(t, err)
+		require.Regexp(t, ".*context canceled.*", err.Error())
+	}()
+
+	for i := 0; i < workerNum; i++ {
+		err := tr.AddTask(newDummyWorker(fmt.Sprintf("worker-%d", i)))
+		require.NoError(t, err)
+	}
+	require.Eventually(t, func() bool {
+		return tr.TaskCount() == workerNum
+	}, 1*time.Second, 10*time.Millisecond)
+
+	err := tr.Drain(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), tr.TaskCount())
+
+	// no new task is launched after the runner is drained
+	err = tr.AddTask(newDummyWorker("worker-new"))
+	require.NoError(t, err)
+	require.Never(t, func() bool {
+		return tr.TaskCount() != 0
+	}, 200*time.Millisecond, 10*time.Millisecond)
+
+	cancel()
+	wg.Wait()
+}
@@ -12,6 +12,7 @@ import (
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
 	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
 
 	cvsTask "github.com/hanfei1991/microcosm/executor/cvsTask"
 	"github.com/hanfei1991/microcosm/executor/worker"
@@ -19,12 +20,19 @@ import (
 	libModel "github.com/hanfei1991/microcosm/lib/model"
 	"github.com/hanfei1991/microcosm/lib/registry"
 	"github.com/hanfei1991/microcosm/model"
+	"github.com/hanfei1991/microcosm/pb"
 	"github.com/hanfei1991/microcosm/pkg/clock"
 	dcontext "github.com/hanfei1991/microcosm/pkg/context"
 	derrors "github.com/hanfei1991/microcosm/pkg/errors"
 	"github.com/hanfei1991/microcosm/pkg/p2p"
+	"github.com/hanfei1991/microcosm/pkg/partition"
 )
 
+// rangeProgressTimeout is the timeout of a range making no progress, after
+// which the worker of the range is stopped and the range is reassigned to a
+// new worker.
+var rangeProgressTimeout = time.Minute
+
 // Config records all configurations of cvs job
 type Config struct {
 	SrcHost string `toml:"srcHost" json:"srcHost"`
@@ -32,25 +40,64 @@ type Config struct {
 	DstHost string `toml:"dstHost" json:"dstHost"`
 	DstDir  string `toml:"dstDir" json:"dstDir"`
 	FileNum int    `toml:"fileNum" json:"fileNum"`
+	// Partition repartitions the lines by key into files of the destination,
+	// the files are kept as is if it is not set
+	Partition *partition.Spec `toml:"partition" json:"partition,omitempty"`
+	// SplitNum is the number of ranges every file is split into, the ranges
+	// are synced by different workers. A file is synced as a whole range if
+	// it is not set.
+	SplitNum int `toml:"splitNum" json:"splitNum,omitempty"`
 }
 
-// SyncFileInfo records sync file progress
-type SyncFileInfo struct {
+// SyncRangeInfo records sync progress of a range of a file, the lines in
+// [StartLoc, EndLoc) are synced, EndLoc is empty for the last range.
+type SyncRangeInfo struct {
+	ID       int    `json:"id"`
 	Idx      int    `json:"idx"`
+	StartLoc string `json:"start,omitempty"`
+	EndLoc   string `json:"end,omitempty"`
 	Location string `json:"loc"`
+	Finished bool   `json:"finished,omitempty"`
 }
 
 // Status records worker status of cvs job master
 type Status struct {
 	*Config `json:"cfg"`
 
-	FileInfos map[int]*SyncFileInfo `json:"files"`
+	RangeInfos map[int]*SyncRangeInfo `json:"ranges"`
 }
 
 // WorkerInfo holds handler of worker
 type WorkerInfo struct {
 	handle     atomic.UnsafePointer // a handler to get information
 	needCreate atomic.Bool
+
+	// the last location synced by the worker and when it changed, which are
+	// used to find the ranges making no progress.
+	lastLoc          string
+	lastProgressTime time.Time
+	stopping         bool
+}
+
+// updateProgress records the location synced by the worker, and returns
+// whether the worker makes no progress within rangeProgressTimeout.
+func (w *WorkerInfo) updateProgress(loc string, now time.Time) bool {
+	if w.lastProgressTime.IsZero() || loc != w.lastLoc {
+		w.lastLoc = loc
+		w.lastProgressTime = now
+		return false
+	}
+	return now.Sub(w.lastProgressTime) > rangeProgressTimeout
+}
+
+// reset is called when the worker is offline, the range is synced by a new
+// worker.
+func (w *WorkerInfo) reset() {
+	w.needCreate.Store(true)
+	w.handle.Store(nil)
+	w.lastLoc = ""
+	w.lastProgressTime = time.Time{}
+	w.stopping = false
 }
 
 // JobMaster defines cvs job master
@@ -58,11 +105,15 @@ type JobMaster struct {
 	sync.Mutex
 
 	lib.BaseJobMaster
-	jobStatus         *Status
-	syncFilesInfo     map[int]*WorkerInfo
+	jobStatus *Status
+	// rangeID -> worker of the range, finished ranges are removed
+	syncRangesInfo    map[int]*WorkerInfo
 	counter           int64
 	workerID          libModel.WorkerID
 	statusRateLimiter *rate.Limiter
+	// statusChanged is set when a range is finished, so the status is
+	// persisted in next Tick without waiting for the rate limiter.
+	statusChanged bool
 
 	launchedWorkers sync.Map
 	statusCode      struct {
@@ -79,7 +130,11 @@ func RegisterWorker() {
 		return NewCVSJobMaster(ctx, id, masterID, config)
 	}
 	factory := registry.NewSimpleWorkerFactory(constructor, &Config{})
-	registry.GlobalWorkerRegistry().MustRegisterWorkerType(lib.CvsJobMaster, factory)
+	registry.GlobalWorkerRegistry().MustRegisterPlugin(&registry.JobPlugin{
+		Name:          "cvs-master",
+		MasterType:    lib.CvsJobMaster,
+		MasterFactory: factory,
+	})
 }
 
 // NewCVSJobMaster creates a new cvs job master
@@ -87,10 +142,10 @@ func NewCVSJobMaster(ctx *dcontext.Context, workerID libModel.WorkerID, masterID
 	jm := &JobMaster{}
 	jm.workerID = workerID
 	jm.jobStatus = &Status{
-		FileInfos: make(map[int]*SyncFileInfo),
+		RangeInfos: make(map[int]*SyncRangeInfo),
 	}
 	jm.jobStatus.Config = conf.(*Config)
-	jm.syncFilesInfo = make(map[int]*WorkerInfo)
+	jm.syncRangesInfo = make(map[int]*WorkerInfo)
 	jm.statusRateLimiter = rate.NewLimiter(rate.Every(time.Second*2), 1)
 	jm.ctx = ctx.Context
 	jm.clocker = clock.New()
@@ -106,15 +161,24 @@ func (jm *JobMaster) InitImpl(ctx context.Context) (err error) {
 	if filesNum == 0 {
 		return errors.New("no file found under the folder")
 	}
+	if jm.jobStatus.Config.Partition != nil {
+		if err := jm.jobStatus.Config.Partition.Validate(); err != nil {
+			return err
+		}
+	}
 	log.L().Info("cvs jobmaster list file success", zap.Any("id", jm.workerID), zap.Any("file number", filesNum))
-	// todo: store the jobmaster information into the metastore
-	for idx := 0; idx < filesNum; idx++ {
-		jm.jobStatus.FileInfos[idx] = &SyncFileInfo{Idx: idx}
-		jm.syncFilesInfo[idx] = &WorkerInfo{
+	splitKeys, err := jm.splitFiles(ctx, filesNum)
+	if err != nil {
+		return err
+	}
+	for _, rangeInfo := range newRanges(splitKeys) {
+		jm.jobStatus.RangeInfos[rangeInfo.ID] = rangeInfo
+		jm.syncRangesInfo[rangeInfo.ID] = &WorkerInfo{
 			needCreate: *atomic.NewBool(true),
 			handle:     *atomic.NewUnsafePointer(unsafe.Pointer(nil)),
 		}
 	}
+	log.L().Info("cvs jobmaster split files into ranges", zap.Any("id", jm.workerID), zap.Int("range number", len(jm.jobStatus.RangeInfos)))
 
 	// Then persist the checkpoint for recovery
 	// This persistence has to succeed before we set this master to normal status.
@@ -142,19 +206,25 @@ func (jm *JobMaster) Tick(ctx context.Context) error {
 
 	jm.Lock()
 	defer jm.Unlock()
-	if 0 == len(jm.jobStatus.FileInfos) {
+	if 0 == len(jm.syncRangesInfo) {
+		// the finished ranges must be persisted before the job finishes
+		if err := jm.persistStatus(ctx); err != nil {
+			log.L().Warn("update job status failed, try next time", zap.Any("master id", jm.workerID), zap.Error(err))
+			return nil
+		}
 		jm.setStatusCode(libModel.WorkerStatusFinished)
 		log.L().Info("cvs job master finished")
 		return jm.BaseJobMaster.Exit(ctx, jm.Status(), nil)
 	}
-	for idx, workerInfo := range jm.syncFilesInfo {
+	now := jm.clocker.Now()
+	for id, workerInfo := range jm.syncRangesInfo {
 		// check if need to recreate worker
 		if workerInfo.needCreate.Load() {
-			workerID, err := jm.CreateWorker(lib.CvsTask, getTaskConfig(jm.jobStatus, idx), 10)
+			workerID, err := jm.CreateWorker(lib.CvsTask, getTaskConfig(jm.jobStatus, id), 10)
 			if err != nil {
 				log.L().Warn("create worker failed, try next time", zap.Any("master id", jm.workerID), zap.Error(err))
 			} else {
-				jm.launchedWorkers.Store(workerID, idx)
+				jm.launchedWorkers.Store(workerID, id)
 				workerInfo.needCreate.Store(false)
 			}
 			continue
@@ -169,30 +239,33 @@ func (jm *JobMaster) Tick(ctx context.Context) error {
 		status := handle.Status()
 		switch status.Code {
 		case libModel.WorkerStatusNormal, libModel.WorkerStatusFinished, libModel.WorkerStatusStopped:
-			taskStatus := &cvsTask.Status{}
-			err := json.Unmarshal(status.ExtBytes, taskStatus)
+			taskStatus, err := lib.GetTypedStatus[*cvsTask.Status](handle)
 			if err != nil {
 				return err
 			}
 
-			jm.jobStatus.FileInfos[idx].Location = taskStatus.CurrentLoc
+			jm.jobStatus.RangeInfos[id].Location = taskStatus.CurrentLoc
 			jm.counter += taskStatus.Count
 
-			log.L().Debug("cvs job tmp num ", zap.Any("id", idx), zap.Any("status", string(status.ExtBytes)))
+			log.L().Debug("cvs job tmp num ", zap.Any("id", id), zap.Any("status", string(status.ExtBytes)))
+			if status.Code == libModel.WorkerStatusNormal && !workerInfo.stopping &&
+				workerInfo.updateProgress(taskStatus.CurrentLoc, now) {
+				log.L().Warn("range makes no progress, reassign it to a new worker", zap.Any("id", id),
+					zap.String("worker-id", handle.ID()), zap.String("loc", taskStatus.CurrentLoc))
+				if err := jm.stopWorker(handle); err != nil {
+					log.L().Warn("stop worker failed, try next time", zap.String("worker-id", handle.ID()), zap.Error(err))
+				} else {
+					workerInfo.stopping = true
+				}
+			}
 		case libModel.WorkerStatusError:
-			log.L().Error("sync file failed ", zap.Any("idx", idx))
+			log.L().Error("sync range failed ", zap.Any("id", id))
 		default:
 			log.L().Info("worker status abnormal", zap.Any("status", status))
 		}
 	}
-	if jm.statusRateLimiter.Allow() {
-		statsBytes, err := json.Marshal(jm.jobStatus)
-		if err != nil {
-			log.L().Warn("serialize job status failed, try next time", zap.Any("master id", jm.workerID), zap.Error(err))
-			return err
-		}
-		_, err = jm.MetaKVClient().Put(ctx, jm.workerID, string(statsBytes))
-		if err != nil {
+	if jm.statusChanged || jm.statusRateLimiter.Allow() {
+		if err := jm.persistStatus(ctx); err != nil {
 			log.L().Warn("update job status failed, try next time", zap.Any("master id", jm.workerID), zap.Error(err))
 		}
 		log.L().Info("cvs job master status", zap.Any("id", jm.workerID), zap.Int64("counter", jm.counter), zap.Any("status", jm.getStatusCode()))
@@ -222,14 +295,79 @@ func (jm *JobMaster) OnMasterRecovered(ctx context.Context) (err error) {
 	if err != nil {
 		return err
 	}
-	for id := range jm.jobStatus.FileInfos {
+	// the checkpoint may be written by another version
+	if jm.jobStatus.Config.Partition != nil {
+		if err := jm.jobStatus.Config.Partition.Validate(); err != nil {
+			return err
+		}
+	}
+	// the finished ranges are not synced again
+	for id, rangeInfo := range jm.jobStatus.RangeInfos {
+		if rangeInfo.Finished {
+			continue
+		}
 		info := &WorkerInfo{}
 		info.needCreate.Store(true)
-		jm.syncFilesInfo[id] = info
+		jm.syncRangesInfo[id] = info
 	}
 	return nil
 }
 
+// persistStatus persists the job status as the checkpoint for recovery.
+func (jm *JobMaster) persistStatus(ctx context.Context) error {
+	statusBytes, err := json.Marshal(jm.jobStatus)
+	if err != nil {
+		return err
+	}
+	if _, err := jm.MetaKVClient().Put(ctx, jm.workerID, string(statusBytes)); err != nil {
+		return err
+	}
+	jm.statusChanged = false
+	return nil
+}
+
+// splitFiles returns the keys to split every file into ranges.
+func (jm *JobMaster) splitFiles(ctx context.Context, filesNum int) ([][][]byte, error) {
+	splitKeys := make([][][]byte, filesNum)
+	if jm.jobStatus.SplitNum <= 1 {
+		return splitKeys, nil
+	}
+	conn, err := grpc.DialContext(ctx, jm.jobStatus.SrcHost, grpc.WithInsecure())
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	client := pb.NewDataRWServiceClient(conn)
+	for idx := 0; idx < filesNum; idx++ {
+		resp, err := client.SplitFile(ctx, &pb.SplitFileRequest{
+			FileIdx:  int32(idx),
+			SplitNum: int32(jm.jobStatus.SplitNum),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if resp.ErrMsg != "" {
+			return nil, errors.New(resp.ErrMsg)
+		}
+		splitKeys[idx] = resp.SplitKeys
+	}
+	return splitKeys, nil
+}
+
+// newRanges splits the files into ranges by the split keys of every file.
+func newRanges(splitKeys [][][]byte) []*SyncRangeInfo {
+	var ranges []*SyncRangeInfo
+	for idx, keys := range splitKeys {
+		start := ""
+		for _, key := range keys {
+			ranges = append(ranges, &SyncRangeInfo{ID: len(ranges), Idx: idx, StartLoc: start, EndLoc: string(key), Location: start})
+			start = string(key)
+		}
+		ranges = append(ranges, &SyncRangeInfo{ID: len(ranges), Idx: idx, StartLoc: start, Location: start})
+	}
+	return ranges
+}
+
 // OnWorkerDispatched implements JobMasterImpl.OnWorkerDispatched
 func (jm *JobMaster) OnWorkerDispatched(worker lib.WorkerHandle, err error) error {
 	if err == nil {
@@ -244,8 +382,7 @@ func (jm *JobMaster) OnWorkerDispatched(worker lib.WorkerHandle, err error) erro
 	id := val.(int)
 	jm.Lock()
 	defer jm.Unlock()
-	jm.syncFilesInfo[id].needCreate.Store(true)
-	jm.syncFilesInfo[id].handle.Store(nil)
+	jm.syncRangesInfo[id].reset()
 	return nil
 }
 
@@ -264,32 +401,42 @@ func (jm *JobMaster) OnWorkerOnline(worker lib.WorkerHandle) error {
 			// bad json
 			return err
 		}
-		id = status.TaskConfig.Idx
+		id = status.TaskConfig.RangeID
 	} else {
 		log.L().Info("worker online ", zap.Any("id", worker.ID()), zap.Any("master id", jm.ID()))
 	}
 	jm.Lock()
 	defer jm.Unlock()
-	jm.syncFilesInfo[id.(int)].handle.Store(unsafe.Pointer(&worker))
-	jm.syncFilesInfo[id.(int)].needCreate.Store(false)
+	info, ok := jm.syncRangesInfo[id.(int)]
+	if !ok {
+		// the range is finished, but the finished worker is still alive
+		log.L().Info("worker of finished range online", zap.Any("id", worker.ID()), zap.Any("range id", id))
+		return nil
+	}
+	info.handle.Store(unsafe.Pointer(&worker))
+	info.needCreate.Store(false)
 	jm.launchedWorkers.Store(worker.ID(), id.(int))
 	return nil
 }
 
 func getTaskConfig(jobStatus *Status, id int) *cvsTask.Config {
+	rangeInfo := jobStatus.RangeInfos[id]
 	return &cvsTask.Config{
-		SrcHost:  jobStatus.SrcHost,
-		DstHost:  jobStatus.DstHost,
-		DstDir:   jobStatus.DstDir,
-		StartLoc: jobStatus.FileInfos[id].Location,
-		Idx:      id,
+		SrcHost:   jobStatus.SrcHost,
+		DstHost:   jobStatus.DstHost,
+		DstDir:    jobStatus.DstDir,
+		StartLoc:  rangeInfo.Location,
+		EndLoc:    rangeInfo.EndLoc,
+		Idx:       rangeInfo.Idx,
+		RangeID:   id,
+		Partition: jobStatus.Partition,
 	}
 }
 
 // OnWorkerOffline implements JobMasterImpl.OnWorkerOffline
 // When offline, we should:
-// 1. remove this file from map cache
-// 2. update checkpoint, but note that this operation might fail.
+// 1. remove this range from map cache if it's finished, otherwise recreate a worker for it
+// 2. update checkpoint in next Tick, but note that this operation might fail.
 func (jm *JobMaster) OnWorkerOffline(worker lib.WorkerHandle, reason error) error {
 	val, exist := jm.launchedWorkers.Load(worker.ID())
 	log.L().Info("on worker offline ", zap.Any("worker", worker.ID()))
@@ -300,14 +447,18 @@ func (jm *JobMaster) OnWorkerOffline(worker lib.WorkerHandle, reason error) erro
 	id := val.(int)
 	jm.Lock()
 	defer jm.Unlock()
+	info, ok := jm.syncRangesInfo[id]
+	if !ok {
+		return nil
+	}
 	if derrors.ErrWorkerFinish.Equal(reason) {
-		delete(jm.syncFilesInfo, id)
-		delete(jm.jobStatus.FileInfos, id)
+		delete(jm.syncRangesInfo, id)
+		jm.jobStatus.RangeInfos[id].Finished = true
+		jm.statusChanged = true
 		log.L().Info("worker finished", zap.String("worker-id", worker.ID()), zap.Any("status", worker.Status()), zap.Error(reason))
 		return nil
 	}
-	jm.syncFilesInfo[id].needCreate.Store(true)
-	jm.syncFilesInfo[id].handle.Store(nil)
+	info.reset()
 	return nil
 }
 
@@ -362,30 +513,13 @@ func (jm *JobMaster) OnJobManagerMessage(topic p2p.Topic, message p2p.MessageVal
 		switch msg.ExpectState {
 		case libModel.WorkerStatusStopped:
 			jm.setStatusCode(libModel.WorkerStatusStopped)
-			for _, worker := range jm.syncFilesInfo {
+			for _, worker := range jm.syncRangesInfo {
 				if worker.handle.Load() == nil {
 					continue
 				}
 				handle := *(*lib.WorkerHandle)(worker.handle.Load())
-				workerID := handle.ID()
-				wTopic := libModel.WorkerStatusChangeRequestTopic(jm.BaseJobMaster.ID(), handle.ID())
-				wMessage := &libModel.StatusChangeRequest{
-					SendTime:     jm.clocker.Mono(),
-					FromMasterID: jm.BaseJobMaster.ID(),
-					Epoch:        jm.BaseJobMaster.CurrentEpoch(),
-					ExpectState:  libModel.WorkerStatusStopped,
-				}
-
-				if handle := handle.Unwrap(); handle != nil {
-					ctx, cancel := context.WithTimeout(jm.ctx, time.Second*2)
-					if err := handle.SendMessage(ctx, wTopic, wMessage, false /*nonblocking*/); err != nil {
-						cancel()
-						return err
-					}
-					log.L().Info("sent message to worker", zap.String("topic", topic), zap.Any("message", wMessage))
-					cancel()
-				} else {
-					log.L().Info("skip sending message to tombstone worker", zap.String("worker-id", workerID))
+				if err := jm.stopWorker(handle); err != nil {
+					return err
 				}
 			}
 		default:
@@ -398,6 +532,30 @@ func (jm *JobMaster) OnJobManagerMessage(topic p2p.Topic, message p2p.MessageVal
 	return nil
 }
 
+// stopWorker sends a message to stop the worker.
+func (jm *JobMaster) stopWorker(handle lib.WorkerHandle) error {
+	workerID := handle.ID()
+	wTopic := libModel.WorkerStatusChangeRequestTopic(jm.BaseJobMaster.ID(), workerID)
+	wMessage := &libModel.StatusChangeRequest{
+		SendTime:     jm.clocker.Mono(),
+		FromMasterID: jm.BaseJobMaster.ID(),
+		Epoch:        jm.BaseJobMaster.CurrentEpoch(),
+		ExpectState:  libModel.WorkerStatusStopped,
+	}
+
+	if handle := handle.Unwrap(); handle != nil {
+		ctx, cancel := context.WithTimeout(jm.ctx, time.Second*2)
+		defer cancel()
+		if err := handle.SendMessage(ctx, wTopic, wMessage, false /*nonblocking*/); err != nil {
+			return err
+		}
+		log.L().Info("sent message to worker", zap.String("topic", wTopic), zap.Any("message", wMessage))
+	} else {
+		log.L().Info("skip sending message to tombstone worker", zap.String("worker-id", workerID))
+	}
+	return nil
+}
+
 // Status implements JobMasterImpl.Status
 func (jm *JobMaster) Status() libModel.WorkerStatus {
 	status, err := json.Marshal(jm.jobStatus)
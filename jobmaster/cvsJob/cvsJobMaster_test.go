@@ -1,7 +1,49 @@
 package cvs
 
-import "github.com/hanfei1991/microcosm/lib"
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hanfei1991/microcosm/lib"
+)
 
 // TODO more unit test cases
 
 var _ lib.JobMasterImpl = &JobMaster{}
+
+func TestNewRanges(t *testing.T) {
+	t.Parallel()
+
+	ranges := newRanges([][][]byte{
+		{[]byte("3"), []byte("6")},
+		nil,
+	})
+	require.Equal(t, []*SyncRangeInfo{
+		{ID: 0, Idx: 0, StartLoc: "", EndLoc: "3", Location: ""},
+		{ID: 1, Idx: 0, StartLoc: "3", EndLoc: "6", Location: "3"},
+		{ID: 2, Idx: 0, StartLoc: "6", Location: "6"},
+		{ID: 3, Idx: 1},
+	}, ranges)
+}
+
+func TestWorkerProgress(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	info := &WorkerInfo{}
+	require.False(t, info.updateProgress("1", now))
+	require.False(t, info.updateProgress("1", now.Add(rangeProgressTimeout)))
+	require.True(t, info.updateProgress("1", now.Add(rangeProgressTimeout+time.Second)))
+	// progress is made
+	require.False(t, info.updateProgress("2", now.Add(rangeProgressTimeout+time.Second)))
+	require.False(t, info.updateProgress("2", now.Add(rangeProgressTimeout*2)))
+
+	info.stopping = true
+	info.reset()
+	require.True(t, info.needCreate.Load())
+	require.True(t, info.handle.Load() == nil)
+	require.False(t, info.stopping)
+	require.False(t, info.updateProgress("2", now.Add(rangeProgressTimeout*3)))
+}
@@ -10,6 +10,8 @@ import (
 	router "github.com/pingcap/tidb/util/table-router"
 	dmconfig "github.com/pingcap/tiflow/dm/dm/config"
 	"gopkg.in/yaml.v2"
+
+	"github.com/hanfei1991/microcosm/pkg/redact"
 )
 
 // JobCfg copies from tiflow/dm/config/config.go and removes some deprecated fields.
@@ -108,6 +110,34 @@ func (c *JobCfg) Clone() (*JobCfg, error) {
 	return clone, err
 }
 
+// Redacted implements redact.Redactor, the passwords and the keys of the
+// databases are masked.
+func (c *JobCfg) Redacted() interface{} {
+	clone, err := c.Clone()
+	if err != nil {
+		return &JobCfg{Name: c.Name}
+	}
+	redactDBConfig(clone.TargetDB)
+	for _, upstream := range clone.Upstreams {
+		if upstream != nil {
+			redactDBConfig(upstream.DBCfg)
+		}
+	}
+	return clone
+}
+
+func redactDBConfig(db *dmconfig.DBConfig) {
+	if db == nil {
+		return
+	}
+	if db.Password != "" {
+		db.Password = redact.Mask
+	}
+	if db.Security != nil {
+		db.Security.SSLKEYBytes = nil
+	}
+}
+
 // ToTaskConfigs converts job config to a map, mapping from upstream source id
 // to task config.
 func (c *JobCfg) ToTaskConfigs() map[string]*TaskCfg {
@@ -123,6 +153,84 @@ func (c *JobCfg) ToTaskConfigs() map[string]*TaskCfg {
 	return taskCfgs
 }
 
+// UpdateUpstream returns a copy of the job config with the upstream of the
+// source replaced by the given one, which is decoded from YAML. The new
+// config is adjusted like a decoded one, so that an upstream referring to
+// rules not in the job is rejected.
+func (c *JobCfg) UpdateUpstream(sourceID string, content []byte) (*JobCfg, error) {
+	upstream := &UpstreamCfg{}
+	if err := yaml.UnmarshalStrict(content, upstream); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if upstream.SourceID != sourceID {
+		return nil, errors.Errorf("source-id %s in config mismatch, expected %s", upstream.SourceID, sourceID)
+	}
+	if upstream.DBCfg == nil {
+		return nil, errors.Errorf("db-config of source %s should not be empty", upstream.SourceID)
+	}
+
+	jobCfg, err := c.Clone()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	found := false
+	for i, u := range jobCfg.Upstreams {
+		if u.SourceID == upstream.SourceID {
+			jobCfg.Upstreams[i] = upstream
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, errors.Errorf("source %s not found", upstream.SourceID)
+	}
+
+	for _, u := range jobCfg.Upstreams {
+		// the unit configs referred by name are resolved again in adjust
+		if u.MydumperConfigName != "" {
+			u.Mydumper = nil
+		}
+		if u.LoaderConfigName != "" {
+			u.Loader = nil
+		}
+		if u.SyncerConfigName != "" {
+			u.Syncer = nil
+		}
+	}
+	// the rules no longer referred by any upstream are removed, otherwise
+	// they are rejected as unused in adjust.
+	jobCfg.removeUnusedRules()
+	if err := jobCfg.adjust(); err != nil {
+		return nil, err
+	}
+	return jobCfg, nil
+}
+
+func (c *JobCfg) removeUnusedRules() {
+	removeUnused(c.Routes, c.Upstreams, func(u *UpstreamCfg) []string { return u.RouteRules })
+	removeUnused(c.Filters, c.Upstreams, func(u *UpstreamCfg) []string { return u.FilterRules })
+	removeUnused(c.ColumnMappings, c.Upstreams, func(u *UpstreamCfg) []string { return u.ColumnMappingRules })
+	removeUnused(c.ExprFilter, c.Upstreams, func(u *UpstreamCfg) []string { return u.ExpressionFilters })
+	removeUnused(c.Mydumpers, c.Upstreams, func(u *UpstreamCfg) []string { return []string{u.MydumperConfigName} })
+	removeUnused(c.Loaders, c.Upstreams, func(u *UpstreamCfg) []string { return []string{u.LoaderConfigName} })
+	removeUnused(c.Syncers, c.Upstreams, func(u *UpstreamCfg) []string { return []string{u.SyncerConfigName} })
+	removeUnused(c.Validators, c.Upstreams, func(u *UpstreamCfg) []string { return []string{u.ContinuousValidatorConfigName} })
+}
+
+func removeUnused[T any](rules map[string]T, upstreams []*UpstreamCfg, refs func(u *UpstreamCfg) []string) {
+	used := make(map[string]struct{})
+	for _, u := range upstreams {
+		for _, name := range refs(u) {
+			used[name] = struct{}{}
+		}
+	}
+	for name := range rules {
+		if _, ok := used[name]; !ok {
+			delete(rules, name)
+		}
+	}
+}
+
 // toDMTaskCfg transform a jobCfg to dm TaskCfg.
 func (c *JobCfg) toDMTaskCfg() (*dmconfig.TaskConfig, error) {
 	dmTaskCfg := &dmconfig.TaskConfig{}
@@ -7,6 +7,8 @@ import (
 	"github.com/BurntSushi/toml"
 	dmconfig "github.com/pingcap/tiflow/dm/dm/config"
 	"github.com/stretchr/testify/require"
+
+	"github.com/hanfei1991/microcosm/pkg/redact"
 )
 
 const (
@@ -37,6 +39,22 @@ func TestJobCfg(t *testing.T) {
 	require.Error(t, jobCfg.DecodeFile("./job_not_exist.yaml"))
 }
 
+func TestJobCfgRedacted(t *testing.T) {
+	jobCfg := &JobCfg{}
+	require.NoError(t, jobCfg.DecodeFile(jobTemplatePath))
+	jobCfg.TargetDB.Password = "target-password"
+	jobCfg.Upstreams[0].DBCfg.Password = "upstream-password"
+
+	redacted := redact.Value(jobCfg).(*JobCfg)
+	require.Equal(t, redact.Mask, redacted.TargetDB.Password)
+	require.Equal(t, redact.Mask, redacted.Upstreams[0].DBCfg.Password)
+	require.Equal(t, jobCfg.Name, redacted.Name)
+	require.Len(t, redacted.Upstreams, len(jobCfg.Upstreams))
+	// the original config is not modified
+	require.Equal(t, "target-password", jobCfg.TargetDB.Password)
+	require.Equal(t, "upstream-password", jobCfg.Upstreams[0].DBCfg.Password)
+}
+
 func TestTaskCfg(t *testing.T) {
 	jobCfg := &JobCfg{}
 	require.NoError(t, jobCfg.DecodeFile(jobTemplatePath))
@@ -50,3 +68,54 @@ func TestTaskCfg(t *testing.T) {
 		require.EqualValues(t, subTaskCfg, expectCfg)
 	}
 }
+
+func TestUpdateUpstream(t *testing.T) {
+	jobCfg := &JobCfg{}
+	require.NoError(t, jobCfg.DecodeFile(jobTemplatePath))
+
+	content := `
+source-id: mysql-replica-01
+db-config:
+  host: 127.0.0.1
+  port: 3308
+  user: root
+  password: '123456'
+filter-rules:
+- filter-02
+route-rules:
+- route-01
+- route-02
+block-allow-list: balist-01
+mydumper-config-name: dump-01
+loader-config-name: load-01
+syncer-config-name: sync-01
+`
+	newCfg, err := jobCfg.UpdateUpstream("mysql-replica-01", []byte(content))
+	require.NoError(t, err)
+	require.Equal(t, 3308, newCfg.Upstreams[0].DBCfg.Port)
+	require.Equal(t, []string{"filter-02"}, newCfg.Upstreams[0].FilterRules)
+	require.Equal(t, jobCfg.Upstreams[0].Mydumper, newCfg.Upstreams[0].Mydumper)
+	// the other upstream and the old config are not changed
+	require.Equal(t, jobCfg.Upstreams[1], newCfg.Upstreams[1])
+	require.Equal(t, 3306, jobCfg.Upstreams[0].DBCfg.Port)
+	// unused rules are removed
+	require.NotContains(t, newCfg.Filters, "filter-01")
+	require.NotContains(t, newCfg.ColumnMappings, "cm-01")
+	require.Contains(t, jobCfg.Filters, "filter-01")
+
+	// source not found
+	_, err = jobCfg.UpdateUpstream("mysql-replica-03", []byte("source-id: mysql-replica-03\ndb-config:\n  host: 127.0.0.1\n"))
+	require.EqualError(t, err, "source mysql-replica-03 not found")
+	// source mismatch
+	_, err = jobCfg.UpdateUpstream("mysql-replica-02", []byte(content))
+	require.EqualError(t, err, "source-id mysql-replica-01 in config mismatch, expected mysql-replica-02")
+	// no db config
+	_, err = jobCfg.UpdateUpstream("mysql-replica-01", []byte("source-id: mysql-replica-01\n"))
+	require.EqualError(t, err, "db-config of source mysql-replica-01 should not be empty")
+	// refer to rule not in job
+	_, err = jobCfg.UpdateUpstream("mysql-replica-01", []byte("source-id: mysql-replica-01\nblock-allow-list: balist-02\ndb-config:\n  host: 127.0.0.1\n"))
+	require.Error(t, err)
+	// unknown field
+	_, err = jobCfg.UpdateUpstream("mysql-replica-01", []byte("source-id: mysql-replica-01\nunknown: 1\n"))
+	require.Error(t, err)
+}
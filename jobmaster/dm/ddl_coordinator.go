@@ -0,0 +1,272 @@
+package dm
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	dmconfig "github.com/pingcap/tiflow/dm/dm/config"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	"github.com/hanfei1991/microcosm/jobmaster/dm/config"
+	"github.com/hanfei1991/microcosm/jobmaster/dm/metadata"
+	"github.com/hanfei1991/microcosm/jobmaster/dm/ticker"
+	dmpkg "github.com/hanfei1991/microcosm/pkg/dm"
+)
+
+var (
+	ddlNormalInterval = time.Second * 30
+	ddlErrorInterval  = time.Second * 10
+)
+
+// DDLAgent defines an interface to send the coordinated result of shard DDLs
+type DDLAgent interface {
+	CoordinateDDL(ctx context.Context, taskID string, msg *dmpkg.CoordinateDDLMessage) error
+}
+
+// DDLCoordinator coordinates the DDLs of the shard tables routed to the same
+// target table. The first shard reporting the DDLs owns the lock and executes
+// them, the other shards skip the DDLs after the owner has executed them.
+// In pessimistic mode the owner executes the DDLs after all the shards have
+// reported them, in optimistic mode it executes them immediately.
+// All the tasks of the job are regarded as shards of the target table, a
+// task registers its shard tables when it reports the DDLs for the first
+// time, and a lock is synced after all the tasks have registered and all the
+// shard tables have reported.
+// The locks are persisted in the DDLStore, so they can be resolved after the
+// job master fails over.
+type DDLCoordinator struct {
+	*ticker.DefaultTicker
+
+	// mu serializes the updates of the locks
+	mu        sync.Mutex
+	shardMode string
+	tasks     []string
+	ddlStore  *metadata.DDLStore
+	ddlAgent  DDLAgent
+}
+
+// NewDDLCoordinator creates a new DDLCoordinator instance
+func NewDDLCoordinator(jobCfg *config.JobCfg, ddlStore *metadata.DDLStore, agent DDLAgent) *DDLCoordinator {
+	coordinator := &DDLCoordinator{
+		DefaultTicker: ticker.NewDefaultTicker(ddlNormalInterval, ddlErrorInterval),
+		shardMode:     jobCfg.ShardMode,
+		ddlStore:      ddlStore,
+		ddlAgent:      agent,
+	}
+	for _, upstream := range jobCfg.Upstreams {
+		coordinator.tasks = append(coordinator.tasks, upstream.SourceID)
+	}
+	coordinator.DefaultTicker.Ticker = coordinator
+	return coordinator
+}
+
+// CoordinateDDL records the DDLs reported by a shard in its lock, and triggers
+// the coordinator to send the coordinated results.
+func (c *DDLCoordinator) CoordinateDDL(ctx context.Context, shardDDL *dmpkg.ShardDDL) error {
+	log.L().Info("coordinate shard DDL", zap.String("task_id", shardDDL.TaskID), zap.String("table", shardDDL.Table),
+		zap.String("target_table", shardDDL.TargetTable), zap.Strings("ddls", shardDDL.DDLs), zap.Bool("executed", shardDDL.Executed))
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ddl, err := c.getDDL(ctx)
+	if err != nil {
+		return err
+	}
+
+	shardID := metadata.ShardID(shardDDL.TaskID, shardDDL.Table)
+	lock, ok := ddl.Locks[shardDDL.TargetTable]
+	if shardDDL.Executed {
+		// only the owner executes the DDLs
+		if !ok || lock.Owner != shardID || !equalDDLs(lock.DDLs, shardDDL.DDLs) {
+			log.L().Warn("ignore executed DDLs of non-owner shard", zap.String("shard", shardID), zap.String("target_table", shardDDL.TargetTable))
+			return nil
+		}
+		lock.Executed = true
+	} else {
+		if !ok {
+			lock = &metadata.ShardLock{
+				DDLs:   shardDDL.DDLs,
+				Owner:  shardID,
+				Shards: make(map[string]*metadata.Shard),
+			}
+			ddl.Locks[shardDDL.TargetTable] = lock
+		} else if !equalDDLs(lock.DDLs, shardDDL.DDLs) {
+			// the conflict is not recorded, the shard reports the DDLs again
+			// after the task is resumed.
+			conflictMsg := fmt.Sprintf("DDLs %v of shard %s conflict with DDLs %v of other shards on target table %s",
+				shardDDL.DDLs, shardID, lock.DDLs, shardDDL.TargetTable)
+			log.L().Warn("shard DDLs conflict", zap.String("msg", conflictMsg))
+			return c.ddlAgent.CoordinateDDL(ctx, shardDDL.TaskID, &dmpkg.CoordinateDDLMessage{
+				TaskID:      shardDDL.TaskID,
+				Table:       shardDDL.Table,
+				TargetTable: shardDDL.TargetTable,
+				DDLs:        shardDDL.DDLs,
+				ConflictMsg: conflictMsg,
+			})
+		}
+
+		for _, table := range shardDDL.Tables {
+			id := metadata.ShardID(shardDDL.TaskID, table)
+			if _, ok := lock.Shards[id]; !ok {
+				lock.Shards[id] = &metadata.Shard{TaskID: shardDDL.TaskID, Table: table}
+			}
+		}
+		// the result is sent again if the shard reports again, e.g. after
+		// the worker fails over.
+		lock.Shards[shardID] = &metadata.Shard{
+			TaskID:   shardDDL.TaskID,
+			Table:    shardDDL.Table,
+			Reported: true,
+		}
+	}
+
+	if err := c.ddlStore.Put(ctx, ddl); err != nil {
+		return err
+	}
+	c.SetNextCheckTime(time.Now())
+	return nil
+}
+
+// TickImpl implements ticker.Ticker
+func (c *DDLCoordinator) TickImpl(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ddl, err := c.getDDL(ctx)
+	if err != nil {
+		return err
+	}
+	if len(ddl.Locks) == 0 {
+		return nil
+	}
+
+	var (
+		changed bool
+		sendErr error
+	)
+	for targetTable, lock := range ddl.Locks {
+		synced := c.lockSynced(lock)
+		for shardID, shard := range lock.Shards {
+			if !shard.Reported || shard.Resolved {
+				continue
+			}
+			var exec bool
+			switch {
+			case shardID == lock.Owner && (synced || c.shardMode == dmconfig.ShardOptimistic):
+				exec = true
+			case shardID != lock.Owner && lock.Executed:
+				exec = false
+			default:
+				continue
+			}
+
+			if err := c.ddlAgent.CoordinateDDL(ctx, shard.TaskID, &dmpkg.CoordinateDDLMessage{
+				TaskID:      shard.TaskID,
+				Table:       shard.Table,
+				TargetTable: targetTable,
+				DDLs:        lock.DDLs,
+				Exec:        exec,
+			}); err != nil {
+				log.L().Error("failed to send coordinated DDLs", zap.String("shard", shardID), zap.String("target_table", targetTable), zap.Error(err))
+				sendErr = err
+				continue
+			}
+			shard.Resolved = true
+			changed = true
+		}
+
+		// the lock is kept until all the shards reach the DDLs, so that the
+		// DDLs are executed only once.
+		if synced && lock.Executed && lockResolved(lock) {
+			log.L().Info("shard DDLs are coordinated", zap.String("target_table", targetTable), zap.Strings("ddls", lock.DDLs))
+			delete(ddl.Locks, targetTable)
+			changed = true
+		}
+	}
+
+	if changed {
+		if len(ddl.Locks) == 0 {
+			err = c.ddlStore.Delete(ctx)
+		} else {
+			err = c.ddlStore.Put(ctx, ddl)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return sendErr
+}
+
+// Clear removes all the locks from metadata, which is called when the job is deleted.
+func (c *DDLCoordinator) Clear(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// the state must be loaded before it can be deleted
+	if _, err := c.ddlStore.Get(ctx); err != nil {
+		if errors.Cause(err) == metadata.ErrStateNotFound {
+			return nil
+		}
+		return err
+	}
+	return c.ddlStore.Delete(ctx)
+}
+
+// getDDL returns the DDL state in metadata, or an empty one if not stored.
+func (c *DDLCoordinator) getDDL(ctx context.Context) (*metadata.DDL, error) {
+	state, err := c.ddlStore.Get(ctx)
+	if err != nil {
+		if errors.Cause(err) != metadata.ErrStateNotFound {
+			return nil, err
+		}
+		state = c.ddlStore.CreateState()
+	}
+	ddl := state.(*metadata.DDL)
+	if ddl.Locks == nil {
+		ddl.Locks = make(map[string]*metadata.ShardLock)
+	}
+	return ddl, nil
+}
+
+// lockSynced returns whether all the shards of the lock have reported the DDLs.
+func (c *DDLCoordinator) lockSynced(lock *metadata.ShardLock) bool {
+	registered := make(map[string]struct{}, len(c.tasks))
+	for _, shard := range lock.Shards {
+		if !shard.Reported {
+			return false
+		}
+		registered[shard.TaskID] = struct{}{}
+	}
+	for _, taskID := range c.tasks {
+		if _, ok := registered[taskID]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// lockResolved returns whether the coordinated results are sent to all the shards of the lock.
+func lockResolved(lock *metadata.ShardLock) bool {
+	for _, shard := range lock.Shards {
+		if !shard.Resolved {
+			return false
+		}
+	}
+	return true
+}
+
+func equalDDLs(ddls1, ddls2 []string) bool {
+	if len(ddls1) != len(ddls2) {
+		return false
+	}
+	for i := range ddls1 {
+		if ddls1[i] != ddls2[i] {
+			return false
+		}
+	}
+	return true
+}
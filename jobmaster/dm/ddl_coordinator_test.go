@@ -0,0 +1,183 @@
+package dm
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	dmconfig "github.com/pingcap/tiflow/dm/dm/config"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hanfei1991/microcosm/jobmaster/dm/config"
+	"github.com/hanfei1991/microcosm/jobmaster/dm/metadata"
+	dmpkg "github.com/hanfei1991/microcosm/pkg/dm"
+	"github.com/hanfei1991/microcosm/pkg/meta/kvclient/mock"
+)
+
+const (
+	targetTable = "`db`.`tb`"
+	shardTable1 = "`db`.`tb1`"
+	shardTable2 = "`db`.`tb2`"
+)
+
+var shardDDLs = []string{"ALTER TABLE `db`.`tb` ADD COLUMN c INT"}
+
+func (t *testDMJobmasterSuite) TestCoordinatePessimisticDDL() {
+	jobCfg := &config.JobCfg{}
+	require.NoError(t.T(), jobCfg.DecodeFile(jobTemplatePath))
+	jobCfg.ShardMode = dmconfig.ShardPessimistic
+	task1 := jobCfg.Upstreams[0].SourceID
+	task2 := jobCfg.Upstreams[1].SourceID
+	ddlStore := metadata.NewDDLStore("ddl_coordinator_test", mock.NewMetaMock())
+	agent := &MockDDLAgent{}
+	coordinator := NewDDLCoordinator(jobCfg, ddlStore, agent)
+
+	// the owner waits for the other shards
+	require.NoError(t.T(), coordinator.CoordinateDDL(context.Background(), newShardDDL(task1, shardTable1, false)))
+	coordinator.Tick(context.Background())
+	require.Len(t.T(), agent.messages(), 0)
+	state, err := ddlStore.Get(context.Background())
+	require.NoError(t.T(), err)
+	lock := state.(*metadata.DDL).Locks[targetTable]
+	require.Equal(t.T(), metadata.ShardID(task1, shardTable1), lock.Owner)
+	require.Len(t.T(), lock.Shards, 2)
+
+	// the owner waits for the other tasks
+	require.NoError(t.T(), coordinator.CoordinateDDL(context.Background(), newShardDDL(task1, shardTable2, false)))
+	coordinator.Tick(context.Background())
+	require.Len(t.T(), agent.messages(), 0)
+
+	// the owner executes the DDLs after all the shards reach them
+	require.NoError(t.T(), coordinator.CoordinateDDL(context.Background(), newShardDDL(task2, shardTable1, false)))
+	require.NoError(t.T(), coordinator.CoordinateDDL(context.Background(), newShardDDL(task2, shardTable2, false)))
+	coordinator.Tick(context.Background())
+	messages := agent.messages()
+	require.Len(t.T(), messages, 1)
+	require.Equal(t.T(), task1, messages[0].TaskID)
+	require.Equal(t.T(), shardTable1, messages[0].Table)
+	require.True(t.T(), messages[0].Exec)
+
+	// the owner reports again after fail over, the result is sent again
+	require.NoError(t.T(), coordinator.CoordinateDDL(context.Background(), newShardDDL(task1, shardTable1, false)))
+	coordinator.Tick(context.Background())
+	messages = agent.messages()
+	require.Len(t.T(), messages, 2)
+	require.True(t.T(), messages[1].Exec)
+
+	// executed DDLs of non-owner are ignored
+	require.NoError(t.T(), coordinator.CoordinateDDL(context.Background(), newShardDDL(task2, shardTable1, true)))
+	coordinator.Tick(context.Background())
+	require.Len(t.T(), agent.messages(), 2)
+
+	// the other shards skip the DDLs after the owner executed them
+	require.NoError(t.T(), coordinator.CoordinateDDL(context.Background(), newShardDDL(task1, shardTable1, true)))
+	coordinator.Tick(context.Background())
+	messages = agent.messages()
+	require.Len(t.T(), messages, 5)
+	for _, msg := range messages[2:] {
+		require.False(t.T(), msg.Exec)
+		require.NotEqual(t.T(), metadata.ShardID(task1, shardTable1), metadata.ShardID(msg.TaskID, msg.Table))
+	}
+
+	// the lock is removed after resolved
+	_, err = ddlStore.Get(context.Background())
+	require.Equal(t.T(), metadata.ErrStateNotFound, errors.Cause(err))
+}
+
+func (t *testDMJobmasterSuite) TestCoordinateOptimisticDDL() {
+	jobCfg := &config.JobCfg{}
+	require.NoError(t.T(), jobCfg.DecodeFile(jobTemplatePath))
+	jobCfg.ShardMode = dmconfig.ShardOptimistic
+	task1 := jobCfg.Upstreams[0].SourceID
+	task2 := jobCfg.Upstreams[1].SourceID
+	ddlStore := metadata.NewDDLStore("ddl_coordinator_test", mock.NewMetaMock())
+	agent := &MockDDLAgent{}
+	coordinator := NewDDLCoordinator(jobCfg, ddlStore, agent)
+
+	// the owner executes the DDLs immediately
+	require.NoError(t.T(), coordinator.CoordinateDDL(context.Background(), newShardDDL(task1, shardTable1, false)))
+	coordinator.Tick(context.Background())
+	messages := agent.messages()
+	require.Len(t.T(), messages, 1)
+	require.True(t.T(), messages[0].Exec)
+
+	// the other shards wait for the owner
+	require.NoError(t.T(), coordinator.CoordinateDDL(context.Background(), newShardDDL(task1, shardTable2, false)))
+	coordinator.Tick(context.Background())
+	require.Len(t.T(), agent.messages(), 1)
+	require.NoError(t.T(), coordinator.CoordinateDDL(context.Background(), newShardDDL(task1, shardTable1, true)))
+	coordinator.Tick(context.Background())
+	messages = agent.messages()
+	require.Len(t.T(), messages, 2)
+	require.Equal(t.T(), shardTable2, messages[1].Table)
+	require.False(t.T(), messages[1].Exec)
+	// the lock is kept until all the tasks reach the DDLs
+	state, err := ddlStore.Get(context.Background())
+	require.NoError(t.T(), err)
+	require.True(t.T(), state.(*metadata.DDL).Locks[targetTable].Executed)
+
+	// conflict DDLs
+	conflictDDL := newShardDDL(task2, shardTable1, false)
+	conflictDDL.DDLs = []string{"ALTER TABLE `db`.`tb` ADD COLUMN c BIGINT"}
+	require.NoError(t.T(), coordinator.CoordinateDDL(context.Background(), conflictDDL))
+	messages = agent.messages()
+	require.Len(t.T(), messages, 3)
+	require.False(t.T(), messages[2].Exec)
+	require.NotEmpty(t.T(), messages[2].ConflictMsg)
+
+	require.NoError(t.T(), coordinator.CoordinateDDL(context.Background(), newShardDDL(task2, shardTable1, false)))
+	require.NoError(t.T(), coordinator.CoordinateDDL(context.Background(), newShardDDL(task2, shardTable2, false)))
+
+	// resend if failed
+	agent.setError(errors.New("send error"))
+	coordinator.Tick(context.Background())
+	require.Len(t.T(), agent.messages(), 3)
+	agent.setError(nil)
+	coordinator.SetNextCheckTime(time.Now())
+	coordinator.Tick(context.Background())
+	require.Len(t.T(), agent.messages(), 5)
+	_, err = ddlStore.Get(context.Background())
+	require.Equal(t.T(), metadata.ErrStateNotFound, errors.Cause(err))
+
+	require.NoError(t.T(), coordinator.Clear(context.Background()))
+}
+
+func newShardDDL(taskID, table string, executed bool) *dmpkg.ShardDDL {
+	return &dmpkg.ShardDDL{
+		TaskID:      taskID,
+		Table:       table,
+		Tables:      []string{shardTable1, shardTable2},
+		TargetTable: targetTable,
+		DDLs:        shardDDLs,
+		Executed:    executed,
+	}
+}
+
+type MockDDLAgent struct {
+	mu   sync.Mutex
+	msgs []*dmpkg.CoordinateDDLMessage
+	err  error
+}
+
+func (m *MockDDLAgent) CoordinateDDL(ctx context.Context, taskID string, msg *dmpkg.CoordinateDDLMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	m.msgs = append(m.msgs, msg)
+	return nil
+}
+
+func (m *MockDDLAgent) messages() []*dmpkg.CoordinateDDLMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*dmpkg.CoordinateDDLMessage(nil), m.msgs...)
+}
+
+func (m *MockDDLAgent) setError(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.err = err
+}
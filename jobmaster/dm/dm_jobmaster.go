@@ -1,7 +1,9 @@
 package dm
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"sync"
 	"time"
 
@@ -38,9 +40,21 @@ type JobMaster struct {
 	metadata              *metadata.MetaData
 	workerManager         *WorkerManager
 	taskManager           *TaskManager
+	ddlCoordinator        *DDLCoordinator
 	messageAgent          *MessageAgent
 	messageHandlerManager p2p.MessageHandlerManager
 	checkpointAgent       checkpoint.Agent
+
+	// pendingOperations and pendingSourceUpdates are received from job
+	// manager, which are handled in Tick.
+	pendingOperations    []*libModel.TasksOperateRequest
+	pendingSourceUpdates []*libModel.SourceUpdateRequest
+	lastOperationError   string
+	// lastJobStatus is the job status reported last time
+	lastJobStatus []byte
+	// pendingShardDDLs are the shard DDLs reported by sync workers, which are
+	// coordinated in Tick.
+	pendingShardDDLs []*dmpkg.ShardDDL
 }
 
 type dmJobMasterFactory struct{}
@@ -48,6 +62,15 @@ type dmJobMasterFactory struct{}
 // RegisterWorker is used to register dm job master to global registry
 func RegisterWorker() {
 	registry.GlobalWorkerRegistry().MustRegisterWorkerType(lib.DMJobMaster, dmJobMasterFactory{})
+	registerStatusCodecs()
+}
+
+// registerStatusCodecs declares that the statuses of the DM units are decoded
+// into TaskStatus.
+func registerStatusCodecs() {
+	for _, tp := range []lib.WorkerType{lib.WorkerDMDump, lib.WorkerDMLoad, lib.WorkerDMSync} {
+		lib.RegisterStatusCodec[runtime.TaskStatus](tp, runtime.UnmarshalTaskStatus)
+	}
 }
 
 // DeserializeConfig implements WorkerFactory.DeserializeConfig
@@ -86,6 +109,7 @@ func (jm *JobMaster) createComponents() error {
 	jm.messageAgent = NewMessageAgent(workerHandles, jm.ID(), jm.BaseJobMaster)
 	jm.taskManager = NewTaskManager(taskStatus, jm.metadata.JobStore(), jm.messageAgent)
 	jm.workerManager = NewWorkerManager(workerStatus, jm.metadata.JobStore(), jm.messageAgent, jm.checkpointAgent)
+	jm.ddlCoordinator = NewDDLCoordinator(jm.jobCfg, jm.metadata.DDLStore(), jm.messageAgent)
 	return nil
 }
 
@@ -109,15 +133,128 @@ func (jm *JobMaster) InitImpl(ctx context.Context) error {
 
 // Tick implements JobMasterImpl.Tick
 func (jm *JobMaster) Tick(ctx context.Context) error {
+	for _, req := range jm.pendingOperations {
+		jm.operateTasks(ctx, req)
+	}
+	jm.pendingOperations = nil
+	for _, req := range jm.pendingSourceUpdates {
+		jm.updateSource(ctx, req)
+	}
+	jm.pendingSourceUpdates = nil
+	for _, shardDDL := range jm.pendingShardDDLs {
+		// the worker reports the DDLs again if it doesn't receive the result
+		if err := jm.ddlCoordinator.CoordinateDDL(ctx, shardDDL); err != nil {
+			log.L().Error("failed to coordinate shard DDL", zap.String("id", jm.workerID), zap.String("task_id", shardDDL.TaskID), zap.Error(err))
+		}
+	}
+	jm.pendingShardDDLs = nil
+
 	jm.workerManager.Tick(ctx)
 	jm.taskManager.Tick(ctx)
+	jm.ddlCoordinator.Tick(ctx)
+	jm.reportJobStatus(ctx)
+	return nil
+}
+
+var taskOperateTypes = map[libModel.TaskOperation]OperateType{
+	libModel.TaskOperationPause:  Pause,
+	libModel.TaskOperationResume: Resume,
+	libModel.TaskOperationStop:   Stop,
+}
+
+// operateTasks persists the new stages of the tasks, the task manager and
+// the worker manager will operate the workers of the tasks accordingly.
+func (jm *JobMaster) operateTasks(ctx context.Context, req *libModel.TasksOperateRequest) {
+	log.L().Info("operate tasks", zap.String("id", jm.workerID), zap.String("op", string(req.Op)), zap.Strings("tasks", req.Tasks))
+	op, ok := taskOperateTypes[req.Op]
+	if !ok {
+		jm.lastOperationError = errors.Errorf("unknown task operation %s", req.Op).Error()
+		return
+	}
+	if err := jm.taskManager.OperateTask(ctx, op, nil, req.Tasks); err != nil {
+		log.L().Error("failed to operate tasks", zap.String("id", jm.workerID), zap.Error(err))
+		jm.lastOperationError = err.Error()
+		return
+	}
+	jm.lastOperationError = ""
+	if op == Resume {
+		// retry the failed units immediately
+		jm.workerManager.orchestrator.Reset(req.Tasks)
+	}
+	// stop or create the workers of the stopped or resumed tasks
+	jm.workerManager.SetNextCheckTime(time.Now())
+}
+
+// updateSource persists the new config of the task of the upstream source,
+// the worker manager restarts the worker of the task with it.
+func (jm *JobMaster) updateSource(ctx context.Context, req *libModel.SourceUpdateRequest) {
+	log.L().Info("update source", zap.String("id", jm.workerID), zap.String("source_id", req.SourceID))
+	if err := jm.doUpdateSource(ctx, req); err != nil {
+		log.L().Error("failed to update source", zap.String("id", jm.workerID), zap.String("source_id", req.SourceID), zap.Error(err))
+		jm.lastOperationError = err.Error()
+		return
+	}
+	jm.lastOperationError = ""
+	jm.workerManager.SetNextCheckTime(time.Now())
+}
+
+func (jm *JobMaster) doUpdateSource(ctx context.Context, req *libModel.SourceUpdateRequest) error {
+	jobCfg, err := jm.jobCfg.UpdateUpstream(req.SourceID, req.Config)
+	if err != nil {
+		return err
+	}
+	taskCfg := jobCfg.ToTaskConfigs()[req.SourceID]
+	msg, err := checker.CheckSyncConfigFunc(ctx, []*dmconfig.SubTaskConfig{taskCfg.ToDMSubTaskCfg()}, ctlcommon.DefaultErrorCnt, ctlcommon.DefaultWarnCnt)
+	if err != nil {
+		return err
+	}
+	log.L().Info("finish pre-checking source config", zap.String("id", jm.workerID), zap.String("source_id", req.SourceID), zap.String("result", msg))
+	if err := jm.metadata.JobStore().UpdateTaskCfg(ctx, req.SourceID, taskCfg); err != nil {
+		return err
+	}
+	jm.jobCfg = jobCfg
 	return nil
 }
 
+// reportJobStatus reports the stages of the tasks in the status of the job
+// master if they are changed, so that they can be queried by the job API.
+func (jm *JobMaster) reportJobStatus(ctx context.Context) {
+	jobStatus := runtime.JobStatus{
+		Tasks:              jm.taskManager.TaskStages(),
+		LastOperationError: jm.lastOperationError,
+	}
+	for taskID, errMsg := range jm.workerManager.orchestrator.Errors() {
+		if stageStatus, ok := jobStatus.Tasks[taskID]; ok {
+			stageStatus.Error = errMsg
+			jobStatus.Tasks[taskID] = stageStatus
+		}
+	}
+	extBytes, err := json.Marshal(jobStatus)
+	if err != nil {
+		log.L().Error("failed to marshal job status", zap.String("id", jm.workerID), zap.Error(err))
+		return
+	}
+	if bytes.Equal(extBytes, jm.lastJobStatus) {
+		return
+	}
+	// retry in next tick if failed
+	if err := jm.UpdateJobStatus(ctx, libModel.WorkerStatus{
+		Code:     libModel.WorkerStatusNormal,
+		ExtBytes: extBytes,
+	}); err != nil {
+		log.L().Warn("failed to report job status", zap.String("id", jm.workerID), zap.Error(err))
+		return
+	}
+	jm.lastJobStatus = extBytes
+}
+
 // OnMasterRecovered implements JobMasterImpl.OnMasterRecovered
 func (jm *JobMaster) OnMasterRecovered(ctx context.Context) error {
 	log.L().Info("recovering the dm jobmaster", zap.String("id", jm.workerID))
-	return jm.createComponents()
+	if err := jm.createComponents(); err != nil {
+		return err
+	}
+	return jm.registerMessageHandler(ctx)
 }
 
 // OnWorkerDispatched implements JobMasterImpl.OnWorkerDispatched
@@ -134,7 +271,7 @@ func (jm *JobMaster) OnWorkerDispatched(worker lib.WorkerHandle, result error) e
 // OnWorkerOnline implements JobMasterImpl.OnWorkerOnline
 func (jm *JobMaster) OnWorkerOnline(worker lib.WorkerHandle) error {
 	log.L().Debug("on worker online", zap.String("id", jm.workerID), zap.String("worker_id", worker.ID()))
-	taskStatus, err := runtime.UnmarshalTaskStatus(worker.Status().ExtBytes)
+	taskStatus, err := lib.GetTypedStatus[runtime.TaskStatus](worker)
 	if err != nil {
 		return err
 	}
@@ -148,7 +285,7 @@ func (jm *JobMaster) OnWorkerOnline(worker lib.WorkerHandle) error {
 // OnWorkerOffline implements JobMasterImpl.OnWorkerOffline
 func (jm *JobMaster) OnWorkerOffline(worker lib.WorkerHandle, reason error) error {
 	log.L().Info("on worker offline", zap.String("id", jm.workerID), zap.String("worker_id", worker.ID()))
-	taskStatus, err := runtime.UnmarshalTaskStatus(worker.Status().ExtBytes)
+	taskStatus, err := lib.GetTypedStatus[runtime.TaskStatus](worker)
 	if err != nil {
 		return err
 	}
@@ -156,6 +293,10 @@ func (jm *JobMaster) OnWorkerOffline(worker lib.WorkerHandle, reason error) erro
 	if taskStatus.GetStage() == metadata.StageFinished {
 		return jm.onWorkerFinished(taskStatus, worker)
 	}
+	// the workers stopped by the job master are not failures
+	if jm.workerManager.hasWorker(taskStatus.GetTask(), worker.ID()) {
+		jm.workerManager.orchestrator.OnUnitFailed(taskStatus.GetTask(), taskStatus.GetUnit())
+	}
 	jm.taskManager.UpdateTaskStatus(runtime.NewOfflineStatus(taskStatus.GetTask()))
 	jm.workerManager.UpdateWorkerStatus(runtime.NewWorkerStatus(taskStatus.GetTask(), taskStatus.GetUnit(), worker.ID(), runtime.WorkerOffline))
 	jm.messageAgent.UpdateWorkerHandle(taskStatus.GetTask(), nil)
@@ -165,6 +306,7 @@ func (jm *JobMaster) OnWorkerOffline(worker lib.WorkerHandle, reason error) erro
 
 func (jm *JobMaster) onWorkerFinished(taskStatus runtime.TaskStatus, worker lib.WorkerHandle) error {
 	log.L().Info("on worker finished", zap.String("id", jm.workerID), zap.String("worker_id", worker.ID()))
+	jm.workerManager.orchestrator.OnUnitFinished(taskStatus)
 	jm.taskManager.UpdateTaskStatus(taskStatus)
 	jm.workerManager.UpdateWorkerStatus(runtime.NewWorkerStatus(taskStatus.GetTask(), taskStatus.GetUnit(), worker.ID(), runtime.WorkerFinished))
 	jm.messageAgent.UpdateWorkerHandle(taskStatus.GetTask(), nil)
@@ -180,14 +322,21 @@ func (jm *JobMaster) OnWorkerStatusUpdated(worker lib.WorkerHandle, newStatus *l
 
 // OnJobManagerMessage implements JobMasterImpl.OnJobManagerMessage
 func (jm *JobMaster) OnJobManagerMessage(topic p2p.Topic, message interface{}) error {
-	// TODO: receive user request
+	switch msg := message.(type) {
+	case *libModel.TasksOperateRequest:
+		// OnJobManagerMessage is called in the same goroutine as Tick
+		jm.pendingOperations = append(jm.pendingOperations, msg)
+	case *libModel.SourceUpdateRequest:
+		jm.pendingSourceUpdates = append(jm.pendingSourceUpdates, msg)
+	default:
+		log.L().Warn("unexpected job manager message", zap.String("id", jm.workerID), zap.String("topic", topic), zap.Any("message", message))
+	}
 	return nil
 }
 
 // OnWorkerMessage implements JobMasterImpl.OnWorkerMessage
 func (jm *JobMaster) OnWorkerMessage(worker lib.WorkerHandle, topic p2p.Topic, message interface{}) error {
 	log.L().Debug("on worker message", zap.String("id", jm.workerID), zap.String("worker_id", worker.ID()))
-	// TODO: handle DDL request
 	response, ok := message.(dmpkg.MessageWithID)
 	if !ok {
 		return errors.Errorf("unexpected message type %T", message)
@@ -221,6 +370,9 @@ outer:
 				if err := jm.checkpointAgent.Remove(ctx); err != nil {
 					log.L().Error("failed to remove checkpoint", zap.Error(err))
 				}
+				if err := jm.ddlCoordinator.Clear(ctx); err != nil {
+					log.L().Error("failed to remove shard DDL locks", zap.Error(err))
+				}
 				break outer
 			}
 		}
@@ -262,16 +414,17 @@ func (jm *JobMaster) IsJobMasterImpl() {
 
 func (jm *JobMaster) registerMessageHandler(ctx context.Context) error {
 	log.L().Debug("register message handler", zap.String("id", jm.workerID))
-	// TODO: register jobmanager request and worker request/response
-	//	ok, err := jm.messageHandlerManager.RegisterHandler(
-	//		ctx,
-	//		"",
-	//		nil,
-	//		func(sender p2p.NodeID, value p2p.MessageValue) error {
-	//			return jm.OnWorkerMessage(workerHandle, topic , message)
-	//		},
-	//	)
-	return nil
+	// TODO: register worker request/response
+	return lib.RegisterWorkerMessageHandler[dmpkg.ShardDDL](ctx, jm.BaseJobMaster, dmpkg.ShardDDLTopic,
+		func(worker lib.WorkerHandle, shardDDL dmpkg.ShardDDL) error {
+			if jm.jobCfg.ShardMode == "" {
+				log.L().Warn("ignore shard DDL of non-shard job", zap.String("id", jm.workerID), zap.String("worker_id", worker.ID()))
+				return nil
+			}
+			// the handler is called in the same goroutine as Tick
+			jm.pendingShardDDLs = append(jm.pendingShardDDLs, &shardDDL)
+			return nil
+		})
 }
 
 func (jm *JobMaster) getInitStatus() ([]runtime.TaskStatus, []runtime.WorkerStatus, map[string]SendHandle, error) {
@@ -286,7 +439,7 @@ func (jm *JobMaster) getInitStatus() ([]runtime.TaskStatus, []runtime.WorkerStat
 		if workerHandle.GetTombstone() != nil {
 			continue
 		}
-		taskStatus, err := runtime.UnmarshalTaskStatus(workerHandle.Status().ExtBytes)
+		taskStatus, err := lib.GetTypedStatus[runtime.TaskStatus](workerHandle)
 		if err != nil {
 			return nil, nil, nil, errors.Trace(err)
 		}
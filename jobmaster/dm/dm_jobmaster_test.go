@@ -55,8 +55,10 @@ func (t *testDMJobmasterSuite) SetupSuite() {
 	taskErrorInterval = 100 * time.Millisecond
 	WorkerNormalInterval = time.Hour
 	WorkerErrorInterval = 100 * time.Millisecond
+	unitRetryInitialInterval = 0
 	runtime.HeartbeatInterval = 1 * time.Second
 	require.NoError(t.T(), log.InitLogger(&log.Config{Level: "debug"}))
+	registerStatusCodecs()
 }
 
 type masterParamListForTest struct {
@@ -288,6 +290,20 @@ func (t *testDMJobmasterSuite) TestDMJobmaster() {
 	// placeholder
 	require.NoError(t.T(), jm.OnWorkerStatusUpdated(workerHandle1, &libModel.WorkerStatus{ExtBytes: bytes1}))
 	require.NoError(t.T(), jm.OnJobManagerMessage("", ""))
+	// unknown task operation is reported in job status
+	require.NoError(t.T(), jm.OnJobManagerMessage("", &libModel.TasksOperateRequest{Op: "unknown"}))
+	require.NoError(t.T(), jm.Tick(context.Background()))
+	var jobStatus runtime.JobStatus
+	require.NoError(t.T(), json.Unmarshal(mockBaseJobmaster.getJobStatus().ExtBytes, &jobStatus))
+	require.Equal(t.T(), "unknown task operation unknown", jobStatus.LastOperationError)
+	// invalid source update is reported in job status
+	require.NoError(t.T(), jm.OnJobManagerMessage("", &libModel.SourceUpdateRequest{
+		SourceID: jobCfg.Upstreams[0].SourceID,
+		Config:   []byte("source-id: source-not-exist"),
+	}))
+	require.NoError(t.T(), jm.Tick(context.Background()))
+	require.NoError(t.T(), json.Unmarshal(mockBaseJobmaster.getJobStatus().ExtBytes, &jobStatus))
+	require.Equal(t.T(), "source-id source-not-exist in config mismatch, expected mysql-replica-01", jobStatus.LastOperationError)
 	require.NoError(t.T(), jm.OnMasterMessage("", ""))
 	require.NoError(t.T(), jm.OnJobManagerFailover(lib.MasterFailoverReason{}))
 	require.NoError(t.T(), jm.OnMasterFailover(lib.MasterFailoverReason{}))
@@ -323,6 +339,7 @@ type MockBaseJobmaster struct {
 	mock.Mock
 
 	lib.BaseJobMaster
+	jobStatus libModel.WorkerStatus
 }
 
 func (m *MockBaseJobmaster) JobMasterID() libModel.MasterID {
@@ -354,6 +371,28 @@ func (m *MockBaseJobmaster) CurrentEpoch() int64 {
 	return 0
 }
 
+func (m *MockBaseJobmaster) UpdateJobStatus(ctx context.Context, status libModel.WorkerStatus) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jobStatus = status
+	return nil
+}
+
+func (m *MockBaseJobmaster) getJobStatus() libModel.WorkerStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.jobStatus
+}
+
+func (m *MockBaseJobmaster) RegisterRawWorkerMessageHandler(
+	ctx context.Context,
+	topic p2p.Topic,
+	decode func(payload []byte) (interface{}, error),
+	handler func(worker lib.WorkerHandle, message interface{}) error,
+) error {
+	return nil
+}
+
 type MockCheckpointAgent struct {
 	mu sync.Mutex
 	mock.Mock
@@ -135,6 +135,19 @@ func (agent *MessageAgent) OperateTask(ctx context.Context, taskID string, stage
 	return v.(SendHandle).SendMessage(ctx, topic, message, true)
 }
 
+// CoordinateDDL sends the coordinated result of shard DDLs to the sync worker of the task.
+func (agent *MessageAgent) CoordinateDDL(ctx context.Context, taskID string, message *dmpkg.CoordinateDDLMessage) error {
+	v, ok := agent.sendHandles.Load(taskID)
+	if !ok {
+		return errors.Errorf("worker for task %s not exist", taskID)
+	}
+
+	topic := dmpkg.CoordinateDDLMessageTopic(agent.id, taskID)
+	ctx, cancel := context.WithTimeout(ctx, defaultMessageTimeOut)
+	defer cancel()
+	return v.(SendHandle).SendMessage(ctx, topic, message, true)
+}
+
 // OnWorkerMessage is the callback for worker message
 func (agent *MessageAgent) OnWorkerMessage(response dmpkg.MessageWithID) error {
 	return agent.messagePair.OnResponse(response)
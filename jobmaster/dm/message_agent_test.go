@@ -119,6 +119,14 @@ func TestOperateTask(t *testing.T) {
 	require.EqualError(t, messageAgent.OperateTask(context.Background(), task1, metadata.StageInit), fmt.Sprintf("invalid expected stage %d for task %s", metadata.StageInit, task1))
 }
 
+func TestCoordinateDDL(t *testing.T) {
+	messageAgent := NewMessageAgent(nil, "mock-jobmaster", &MockMaster{})
+	msg := &dmpkg.CoordinateDDLMessage{TaskID: "task1", Table: "`db`.`tb1`", TargetTable: "`db`.`tb`", DDLs: []string{"ALTER TABLE tb ADD COLUMN c INT"}}
+	require.EqualError(t, messageAgent.CoordinateDDL(context.Background(), "task1", msg), fmt.Sprintf("worker for task %s not exist", "task1"))
+	messageAgent.UpdateWorkerHandle("task1", &MockSender{id: "worker1"})
+	require.NoError(t, messageAgent.CoordinateDDL(context.Background(), "task1", msg))
+}
+
 func TestOnWorkerMessage(t *testing.T) {
 	messageAgent := NewMessageAgent(nil, "", nil)
 	require.EqualError(t, messageAgent.OnWorkerMessage(dmpkg.MessageWithID{ID: 0, Message: "response"}), "request 0 not found")
@@ -2,13 +2,44 @@ package metadata
 
 import (
 	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/pkg/adapter"
 	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
 )
 
 // DDL represents the state of ddls.
-// TODO: implement DDL
 type DDL struct {
 	State
+
+	// target table -> lock
+	Locks map[string]*ShardLock
+}
+
+// ShardLock coordinates the same DDLs of the shard tables routed to a target
+// table. The owner executes the DDLs, and the other shards skip them after
+// the owner has executed them.
+type ShardLock struct {
+	DDLs []string
+	// Owner is the ID of the first shard reporting the DDLs
+	Owner string
+	// Executed means the owner has executed the DDLs
+	Executed bool
+	// shard ID -> shard
+	Shards map[string]*Shard
+}
+
+// Shard is a shard table of a lock.
+type Shard struct {
+	TaskID string
+	Table  string
+	// Reported means the shard is blocked by the DDLs of the lock
+	Reported bool
+	// Resolved means the coordinated result is sent to the shard
+	Resolved bool
+}
+
+// ShardID returns the ID of a shard table in a lock
+func ShardID(taskID, table string) string {
+	return taskID + "/" + table
 }
 
 // DDLStore manages the state of ddls.
@@ -35,7 +66,6 @@ func (ddlStore *DDLStore) CreateState() State {
 }
 
 // Key returns encoded key of ddl state store
-// TODO: add ddl key
 func (ddlStore *DDLStore) Key() string {
-	return ""
+	return adapter.DMDDLKeyAdapter.Encode(ddlStore.id)
 }
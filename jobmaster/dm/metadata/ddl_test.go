@@ -0,0 +1,45 @@
+package metadata
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hanfei1991/microcosm/pkg/adapter"
+	"github.com/hanfei1991/microcosm/pkg/meta/kvclient/mock"
+)
+
+func TestDDLStore(t *testing.T) {
+	t.Parallel()
+
+	ddlStore := NewDDLStore("ddl_test", mock.NewMetaMock())
+	keys, err := adapter.DMDDLKeyAdapter.Decode(ddlStore.Key())
+	require.NoError(t, err)
+	require.Equal(t, []string{"ddl_test"}, keys)
+
+	_, err = ddlStore.Get(context.Background())
+	require.Equal(t, ErrStateNotFound, errors.Cause(err))
+
+	shardID := ShardID("task1", "`db`.`tb1`")
+	ddl := &DDL{
+		Locks: map[string]*ShardLock{
+			"`db`.`tb`": {
+				DDLs:  []string{"ALTER TABLE `db`.`tb` ADD COLUMN c INT"},
+				Owner: shardID,
+				Shards: map[string]*Shard{
+					shardID: {TaskID: "task1", Table: "`db`.`tb1`", Reported: true},
+				},
+			},
+		},
+	}
+	require.NoError(t, ddlStore.Put(context.Background(), ddl))
+	state, err := ddlStore.Get(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, ddl.Locks, state.(*DDL).Locks)
+
+	require.NoError(t, ddlStore.Delete(context.Background()))
+	_, err = ddlStore.Get(context.Background())
+	require.Equal(t, ErrStateNotFound, errors.Cause(err))
+}
@@ -24,8 +24,48 @@ const (
 	// UnScheduled means the task is not scheduled.
 	// This usually happens when the worker is offline.
 	StageUnscheduled
+	// StageStopped means the task is stopped by user, it has no worker and
+	// can be resumed from its checkpoint.
+	StageStopped
 )
 
+var stageNames = map[TaskStage]string{
+	StageInit:        "Init",
+	StageRunning:     "Running",
+	StagePaused:      "Paused",
+	StageFinished:    "Finished",
+	StageUnscheduled: "Unscheduled",
+	StageStopped:     "Stopped",
+}
+
+// String implements fmt.Stringer
+func (s TaskStage) String() string {
+	if name, ok := stageNames[s]; ok {
+		return name
+	}
+	return "Unknown"
+}
+
+// validStageTransitions records the stages a task can be operated to from
+// each expected stage.
+var validStageTransitions = map[TaskStage][]TaskStage{
+	StageRunning: {StagePaused, StageStopped},
+	StagePaused:  {StageRunning, StageStopped},
+	StageStopped: {StageRunning},
+}
+
+func canTransit(from, to TaskStage) bool {
+	if from == to {
+		return true
+	}
+	for _, stage := range validStageTransitions[from] {
+		if stage == to {
+			return true
+		}
+	}
+	return false
+}
+
 // Job represents the state of a job.
 type Job struct {
 	State
@@ -52,6 +92,9 @@ func NewJob(jobCfg *config.JobCfg) *Job {
 type Task struct {
 	Cfg   *config.TaskCfg
 	Stage TaskStage
+	// Version is increased when the config of the task is updated, the
+	// workers created with an older version are restarted.
+	Version uint64
 }
 
 // NewTask creates a new Task instance
@@ -90,6 +133,8 @@ func (jobStore *JobStore) Key() string {
 }
 
 // UpdateStages will be called if user operate job.
+// Empty taskIDs means all the tasks of the job. No task is updated if any of
+// them can't be transited to the stage.
 func (jobStore *JobStore) UpdateStages(ctx context.Context, taskIDs []string, stage TaskStage) error {
 	state, err := jobStore.Get(ctx)
 	if err != nil {
@@ -97,13 +142,42 @@ func (jobStore *JobStore) UpdateStages(ctx context.Context, taskIDs []string, st
 	}
 
 	job := state.(*Job)
+	if len(taskIDs) == 0 {
+		for taskID := range job.Tasks {
+			taskIDs = append(taskIDs, taskID)
+		}
+	}
 	for _, taskID := range taskIDs {
-		if _, ok := job.Tasks[taskID]; !ok {
+		t, ok := job.Tasks[taskID]
+		if !ok {
 			return errors.Errorf("task %s not found", taskID)
 		}
-		t := job.Tasks[taskID]
-		t.Stage = stage
+		if !canTransit(t.Stage, stage) {
+			return errors.Errorf("task %s can't be transited from %s to %s", taskID, t.Stage, stage)
+		}
+	}
+	for _, taskID := range taskIDs {
+		job.Tasks[taskID].Stage = stage
+	}
+
+	return jobStore.Put(ctx, job)
+}
+
+// UpdateTaskCfg updates the config of a task and increases its version,
+// which will be called if user updates the upstream source of the task.
+func (jobStore *JobStore) UpdateTaskCfg(ctx context.Context, taskID string, taskCfg *config.TaskCfg) error {
+	state, err := jobStore.Get(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	job := state.(*Job)
+	t, ok := job.Tasks[taskID]
+	if !ok {
+		return errors.Errorf("task %s not found", taskID)
 	}
+	t.Cfg = taskCfg
+	t.Version++
 
 	return jobStore.Put(ctx, job)
 }
@@ -71,4 +71,52 @@ func TestJobStore(t *testing.T) {
 	job = state.(*Job)
 	require.Equal(t, job.Tasks[source1].Stage, StagePaused)
 	require.Equal(t, job.Tasks[source2].Stage, StageRunning)
+
+	// empty tasks means all tasks
+	require.NoError(t, jobStore.UpdateStages(context.Background(), nil, StageStopped))
+	state, _ = jobStore.Get(context.Background())
+	job = state.(*Job)
+	require.Equal(t, job.Tasks[source1].Stage, StageStopped)
+	require.Equal(t, job.Tasks[source2].Stage, StageStopped)
+
+	// a stopped task can only be resumed
+	require.EqualError(t, jobStore.UpdateStages(context.Background(), []string{source1}, StagePaused),
+		"task mysql-replica-01 can't be transited from Stopped to Paused")
+	require.NoError(t, jobStore.UpdateStages(context.Background(), []string{source1}, StageStopped))
+	require.NoError(t, jobStore.UpdateStages(context.Background(), []string{source1}, StageRunning))
+	require.Error(t, jobStore.UpdateStages(context.Background(), nil, StagePaused))
+	state, _ = jobStore.Get(context.Background())
+	job = state.(*Job)
+	require.Equal(t, job.Tasks[source1].Stage, StageRunning)
+	require.Equal(t, job.Tasks[source2].Stage, StageStopped)
+}
+
+func TestUpdateTaskCfg(t *testing.T) {
+	t.Parallel()
+
+	jobCfg := &config.JobCfg{}
+	require.NoError(t, jobCfg.DecodeFile(jobTemplatePath))
+	source1 := jobCfg.Upstreams[0].SourceID
+	jobStore := NewJobStore("job_test", mock.NewMetaMock())
+	require.Error(t, jobStore.UpdateTaskCfg(context.Background(), source1, nil))
+	require.NoError(t, jobStore.Put(context.Background(), NewJob(jobCfg)))
+
+	taskCfg := jobCfg.ToTaskConfigs()[source1]
+	taskCfg.Upstreams[0].DBCfg.Port = 3308
+	require.NoError(t, jobStore.UpdateTaskCfg(context.Background(), source1, taskCfg))
+	require.EqualError(t, jobStore.UpdateTaskCfg(context.Background(), "task-not-exist", taskCfg), "task task-not-exist not found")
+	state, err := jobStore.Get(context.Background())
+	require.NoError(t, err)
+	job := state.(*Job)
+	require.Equal(t, uint64(1), job.Tasks[source1].Version)
+	require.Equal(t, 3308, job.Tasks[source1].Cfg.Upstreams[0].DBCfg.Port)
+	require.Equal(t, uint64(0), job.Tasks[jobCfg.Upstreams[1].SourceID].Version)
+}
+
+func TestTaskStageString(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "Running", StageRunning.String())
+	require.Equal(t, "Stopped", StageStopped.String())
+	require.Equal(t, "Unknown", TaskStage(100).String())
 }
@@ -12,6 +12,9 @@ import (
 	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
 )
 
+// ErrStateNotFound is returned by Store.Get if the state is not stored.
+var ErrStateNotFound = errors.New("state not found")
+
 // State represents the state which need to be stored in metadata.
 type State interface{}
 
@@ -133,7 +136,7 @@ func (ds *TomlStore) Get(ctx context.Context) (State, error) {
 	}
 
 	if len(resp.Kvs) == 0 {
-		return nil, errors.New("state not found")
+		return nil, ErrStateNotFound
 	}
 
 	ds.state = ds.CreateState()
@@ -0,0 +1,25 @@
+package runtime
+
+import (
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+)
+
+// JobStatus is the status of a DM job, which is reported by the job master in
+// its worker status, so that it can be queried by the job API.
+type JobStatus struct {
+	// taskID -> TaskStageStatus
+	Tasks map[string]TaskStageStatus `json:"tasks"`
+	// LastOperationError is the error of the last task operation, it's empty
+	// if the operation succeeded.
+	LastOperationError string `json:"last-operation-error,omitempty"`
+}
+
+// TaskStageStatus records the expected stage of a task and its runtime stage.
+// The runtime stage is empty if no status of the task is received.
+type TaskStageStatus struct {
+	ExpectedStage string              `json:"expected-stage"`
+	Stage         string              `json:"stage,omitempty"`
+	Unit          libModel.WorkerType `json:"unit,omitempty"`
+	// Error is set if the task can't run anymore until it's resumed
+	Error string `json:"error,omitempty"`
+}
@@ -28,6 +28,7 @@ const (
 	Resume
 	Update
 	Delete
+	Stop
 )
 
 var (
@@ -49,6 +50,9 @@ type TaskManager struct {
 	// tasks record the runtime task status
 	// taskID -> TaskStatus
 	tasks sync.Map
+	// expectedStages records the task stages in metadata read by last tick
+	// taskID -> TaskStage
+	expectedStages sync.Map
 }
 
 // NewTaskManager creates a new TaskManager instance
@@ -86,6 +90,8 @@ func (tm *TaskManager) OperateTask(ctx context.Context, op OperateType, jobCfg *
 		stage = metadata.StageRunning
 	case Pause:
 		stage = metadata.StagePaused
+	case Stop:
+		stage = metadata.StageStopped
 	default:
 		return errors.New("unknown operate type")
 	}
@@ -109,6 +115,24 @@ func (tm *TaskManager) TaskStatus() map[string]runtime.TaskStatus {
 	return result
 }
 
+// TaskStages returns the expected stages of the tasks read from metadata by
+// last tick, and their runtime stages.
+func (tm *TaskManager) TaskStages() map[string]runtime.TaskStageStatus {
+	result := make(map[string]runtime.TaskStageStatus)
+	tm.expectedStages.Range(func(key, value interface{}) bool {
+		taskID := key.(string)
+		stageStatus := runtime.TaskStageStatus{ExpectedStage: value.(metadata.TaskStage).String()}
+		if task, ok := tm.tasks.Load(taskID); ok {
+			taskStatus := task.(runtime.TaskStatus)
+			stageStatus.Stage = taskStatus.GetStage().String()
+			stageStatus.Unit = taskStatus.GetUnit()
+		}
+		result[taskID] = stageStatus
+		return true
+	})
+	return result
+}
+
 // TickImpl removes tasks that are not in the job config.
 // TickImpl checks and operates task if needed.
 func (tm *TaskManager) TickImpl(ctx context.Context) error {
@@ -122,6 +146,7 @@ func (tm *TaskManager) TickImpl(ctx context.Context) error {
 	job := state.(*metadata.Job)
 
 	tm.removeTaskStatus(job)
+	tm.updateExpectedStages(job)
 	return tm.checkAndOperateTasks(ctx, job)
 }
 
@@ -133,6 +158,12 @@ func (tm *TaskManager) checkAndOperateTasks(ctx context.Context, job *metadata.J
 
 	// check and operate task
 	for taskID, persistentTask := range job.Tasks {
+		// the worker of a stopped task is stopped by worker manager
+		if persistentTask.Stage == metadata.StageStopped {
+			log.L().Debug("task is stopped", zap.String("task_id", taskID))
+			continue
+		}
+
 		task, ok := tm.tasks.Load(taskID)
 		if ok {
 			runningTask = task.(runtime.TaskStatus)
@@ -168,6 +199,22 @@ func (tm *TaskManager) onJobNotExist(ctx context.Context) {
 		tm.tasks.Delete(key)
 		return true
 	})
+	tm.expectedStages.Range(func(key, value interface{}) bool {
+		tm.expectedStages.Delete(key)
+		return true
+	})
+}
+
+func (tm *TaskManager) updateExpectedStages(job *metadata.Job) {
+	tm.expectedStages.Range(func(key, value interface{}) bool {
+		if _, ok := job.Tasks[key.(string)]; !ok {
+			tm.expectedStages.Delete(key)
+		}
+		return true
+	})
+	for taskID, persistentTask := range job.Tasks {
+		tm.expectedStages.Store(taskID, persistentTask.Stage)
+	}
 }
 
 // remove deleted task status, usually happened when update-job delete some tasks.
@@ -135,6 +135,15 @@ func (t *testDMJobmasterSuite) TestOperateTask() {
 	require.Equal(t.T(), job.Tasks[source1].Stage, metadata.StageRunning)
 	require.Equal(t.T(), job.Tasks[source2].Stage, metadata.StagePaused)
 
+	require.NoError(t.T(), taskManager.OperateTask(context.Background(), Stop, nil, []string{source2}))
+	state, err = jobStore.Get(context.Background())
+	require.NoError(t.T(), err)
+	job = state.(*metadata.Job)
+	require.Equal(t.T(), job.Tasks[source1].Stage, metadata.StageRunning)
+	require.Equal(t.T(), job.Tasks[source2].Stage, metadata.StageStopped)
+	// a stopped task can't be paused
+	require.Error(t.T(), taskManager.OperateTask(context.Background(), Pause, nil, nil))
+
 	require.NoError(t.T(), taskManager.OperateTask(context.Background(), Update, jobCfg, nil))
 	state, err = jobStore.Get(context.Background())
 	require.NoError(t.T(), err)
@@ -237,6 +246,39 @@ func (t *testDMJobmasterSuite) TestCheckAndOperateTasks() {
 	e := errors.New("operate task failed")
 	mockAgent.SetResult([]error{e})
 	require.EqualError(t.T(), taskManager.checkAndOperateTasks(context.Background(), job), e.Error())
+
+	// stopped task is not operated
+	job.Tasks[jobCfg.Upstreams[1].SourceID].Stage = metadata.StageStopped
+	require.NoError(t.T(), taskManager.checkAndOperateTasks(context.Background(), job))
+}
+
+func (t *testDMJobmasterSuite) TestTaskStages() {
+	jobCfg := &config.JobCfg{}
+	require.NoError(t.T(), jobCfg.DecodeFile(jobTemplatePath))
+	source1 := jobCfg.Upstreams[0].SourceID
+	source2 := jobCfg.Upstreams[1].SourceID
+	job := metadata.NewJob(jobCfg)
+	job.Tasks[source2].Stage = metadata.StageStopped
+	jobStore := metadata.NewJobStore("task_manager_test", mock.NewMetaMock())
+	require.NoError(t.T(), jobStore.Put(context.Background(), job))
+	taskManager := NewTaskManager(nil, jobStore, &MockTaskAgent{})
+	require.Len(t.T(), taskManager.TaskStages(), 0)
+
+	taskManager.UpdateTaskStatus(&runtime.DumpStatus{
+		DefaultTaskStatus: runtime.DefaultTaskStatus{
+			Unit:  lib.WorkerDMDump,
+			Task:  source1,
+			Stage: metadata.StageRunning,
+		},
+	})
+	require.NoError(t.T(), taskManager.TickImpl(context.Background()))
+	require.Equal(t.T(), map[string]runtime.TaskStageStatus{
+		source1: {ExpectedStage: "Running", Stage: "Running", Unit: lib.WorkerDMDump},
+		source2: {ExpectedStage: "Stopped"},
+	}, taskManager.TaskStages())
+
+	taskManager.onJobNotExist(context.Background())
+	require.Len(t.T(), taskManager.TaskStages(), 0)
 }
 
 func (t *testDMJobmasterSuite) TestTaskManager() {
@@ -0,0 +1,190 @@
+package dm
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	dmconfig "github.com/pingcap/tiflow/dm/dm/config"
+	"github.com/pingcap/tiflow/dm/pkg/binlog"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	"github.com/hanfei1991/microcosm/jobmaster/dm/config"
+	"github.com/hanfei1991/microcosm/jobmaster/dm/runtime"
+	"github.com/hanfei1991/microcosm/lib"
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/pkg/clock"
+)
+
+var (
+	// unitRetryInitialInterval is the interval before the first retry of a failed unit
+	unitRetryInitialInterval = time.Second * 2
+	// unitRetryMaxInterval is the max interval between retries of a failed unit
+	unitRetryMaxInterval = time.Minute
+	// unitRetryResetInterval is the interval after which a failure of a unit
+	// is counted as the first one again
+	unitRetryResetInterval = time.Minute * 10
+	// unitMaxRetries is the max retries of a unit, the task needs to be
+	// resumed by user after that.
+	unitMaxRetries = 10
+)
+
+// UnitOrchestrator orchestrates the units of the tasks, which run from dump
+// to load to sync in turn according to the task mode.
+// The dump files are handed over to the next units by the local resource
+// shared by them, and the binlog position loaded by the load unit is handed
+// over to the sync unit in its config. The handoff is kept in memory, after
+// the job master fails over, the finished load unit is recreated because its
+// checkpoint is not fresh, and it reports the binlog position again.
+// A failed unit is recreated with backoff, until the retries are exhausted.
+type UnitOrchestrator struct {
+	mu      sync.Mutex
+	clocker clock.Clock
+	// taskID -> binlog position to start sync from
+	syncMetas map[string]*dmconfig.Meta
+	// taskID -> retries of the current unit
+	retries map[string]*unitRetry
+}
+
+type unitRetry struct {
+	unit          libModel.WorkerType
+	attempts      int
+	lastFailTime  time.Time
+	nextRetryTime time.Time
+}
+
+func (r *unitRetry) exhausted() bool {
+	return r.attempts > unitMaxRetries
+}
+
+func (r *unitRetry) exhaustedError(taskID string) error {
+	return errors.Errorf("unit %d of task %s failed %d times", r.unit, taskID, r.attempts)
+}
+
+// NewUnitOrchestrator creates a new UnitOrchestrator instance
+func NewUnitOrchestrator() *UnitOrchestrator {
+	return &UnitOrchestrator{
+		clocker:   clock.New(),
+		syncMetas: make(map[string]*dmconfig.Meta),
+		retries:   make(map[string]*unitRetry),
+	}
+}
+
+// OnUnitFinished records the handoff of a finished unit.
+func (o *UnitOrchestrator) OnUnitFinished(taskStatus runtime.TaskStatus) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	taskID := taskStatus.GetTask()
+	delete(o.retries, taskID)
+	loadStatus, ok := taskStatus.(*runtime.LoadStatus)
+	if !ok {
+		return
+	}
+	pos, err := binlog.PositionFromPosStr(loadStatus.MetaBinlog)
+	if err != nil {
+		log.L().Warn("invalid binlog position of finished load unit", zap.String("task_id", taskID),
+			zap.String("binlog", loadStatus.MetaBinlog), zap.Error(err))
+		return
+	}
+	log.L().Info("hand over binlog position to sync unit", zap.String("task_id", taskID),
+		zap.Stringer("binlog", pos), zap.String("gtid", loadStatus.MetaBinlogGTID))
+	o.syncMetas[taskID] = &dmconfig.Meta{
+		BinLogName: pos.Name,
+		BinLogPos:  pos.Pos,
+		BinLogGTID: loadStatus.MetaBinlogGTID,
+	}
+}
+
+// OnUnitFailed records a failure of the unit, which is retried after backoff.
+func (o *UnitOrchestrator) OnUnitFailed(taskID string, unit libModel.WorkerType) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	now := o.clocker.Now()
+	retry, ok := o.retries[taskID]
+	if !ok || retry.unit != unit || now.Sub(retry.lastFailTime) > unitRetryResetInterval {
+		retry = &unitRetry{unit: unit}
+		o.retries[taskID] = retry
+	}
+	backoff := unitRetryInitialInterval
+	for i := 0; i < retry.attempts && backoff < unitRetryMaxInterval; i++ {
+		backoff *= 2
+	}
+	if backoff > unitRetryMaxInterval {
+		backoff = unitRetryMaxInterval
+	}
+	retry.attempts++
+	retry.lastFailTime = now
+	retry.nextRetryTime = now.Add(backoff)
+	log.L().Info("unit failed", zap.String("task_id", taskID), zap.Int64("unit", int64(unit)),
+		zap.Int("attempts", retry.attempts), zap.Duration("backoff", backoff))
+}
+
+// checkRetry returns how long to wait before the unit of the task can be
+// created, and an error if the retries of the unit are exhausted.
+func (o *UnitOrchestrator) checkRetry(taskID string, unit libModel.WorkerType) (time.Duration, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	retry, ok := o.retries[taskID]
+	if !ok || retry.unit != unit {
+		return 0, nil
+	}
+	if retry.exhausted() {
+		return 0, retry.exhaustedError(taskID)
+	}
+	return retry.nextRetryTime.Sub(o.clocker.Now()), nil
+}
+
+// Reset clears the retries of the tasks, which is called when user resumes
+// the tasks. Empty taskIDs means all the tasks.
+func (o *UnitOrchestrator) Reset(taskIDs []string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if len(taskIDs) == 0 {
+		o.retries = make(map[string]*unitRetry)
+		return
+	}
+	for _, taskID := range taskIDs {
+		delete(o.retries, taskID)
+	}
+}
+
+// Errors returns the errors of the tasks whose retries are exhausted.
+// taskID -> error message
+func (o *UnitOrchestrator) Errors() map[string]string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	result := make(map[string]string)
+	for taskID, retry := range o.retries {
+		if retry.exhausted() {
+			result[taskID] = retry.exhaustedError(taskID).Error()
+		}
+	}
+	return result
+}
+
+// TaskConfig returns the config to create the unit of the task with, which
+// carries the handoff from the previous unit.
+func (o *UnitOrchestrator) TaskConfig(taskID string, unit libModel.WorkerType, taskCfg *config.TaskCfg) *config.TaskCfg {
+	if unit != lib.WorkerDMSync || taskCfg.TaskMode != dmconfig.ModeAll {
+		return taskCfg
+	}
+
+	o.mu.Lock()
+	meta, ok := o.syncMetas[taskID]
+	o.mu.Unlock()
+	if !ok {
+		return taskCfg
+	}
+	// a task has only one upstream
+	cfg := *taskCfg
+	upstream := *taskCfg.Upstreams[0]
+	upstream.Meta = meta
+	cfg.Upstreams = []*config.UpstreamCfg{&upstream}
+	return &cfg
+}
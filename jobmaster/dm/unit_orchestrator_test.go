@@ -0,0 +1,102 @@
+package dm
+
+import (
+	"time"
+
+	dmconfig "github.com/pingcap/tiflow/dm/dm/config"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hanfei1991/microcosm/jobmaster/dm/config"
+	"github.com/hanfei1991/microcosm/jobmaster/dm/metadata"
+	"github.com/hanfei1991/microcosm/jobmaster/dm/runtime"
+	"github.com/hanfei1991/microcosm/lib"
+	"github.com/hanfei1991/microcosm/pkg/clock"
+)
+
+func (t *testDMJobmasterSuite) TestUnitOrchestrator() {
+	defer func(interval time.Duration) {
+		unitRetryInitialInterval = interval
+	}(unitRetryInitialInterval)
+	unitRetryInitialInterval = time.Second
+
+	jobCfg := &config.JobCfg{}
+	require.NoError(t.T(), jobCfg.DecodeFile(jobTemplatePath))
+	source1 := jobCfg.Upstreams[0].SourceID
+	taskCfg := jobCfg.ToTaskConfigs()[source1]
+	mockClock := clock.NewMock()
+	orchestrator := NewUnitOrchestrator()
+	orchestrator.clocker = mockClock
+
+	// hand over the binlog position from load to sync
+	require.Same(t.T(), taskCfg, orchestrator.TaskConfig(source1, lib.WorkerDMSync, taskCfg))
+	orchestrator.OnUnitFinished(&runtime.LoadStatus{
+		DefaultTaskStatus: runtime.DefaultTaskStatus{
+			Unit:  lib.WorkerDMLoad,
+			Task:  source1,
+			Stage: metadata.StageFinished,
+		},
+		MetaBinlog:     "(mysql-bin.000002, 1234)",
+		MetaBinlogGTID: "gtid",
+	})
+	require.Same(t.T(), taskCfg, orchestrator.TaskConfig(source1, lib.WorkerDMLoad, taskCfg))
+	syncCfg := orchestrator.TaskConfig(source1, lib.WorkerDMSync, taskCfg)
+	require.Equal(t.T(), &dmconfig.Meta{BinLogName: "mysql-bin.000002", BinLogPos: 1234, BinLogGTID: "gtid"}, syncCfg.Upstreams[0].Meta)
+	require.Nil(t.T(), taskCfg.Upstreams[0].Meta)
+	require.Equal(t.T(), taskCfg.Upstreams[0].SourceID, syncCfg.Upstreams[0].SourceID)
+
+	// retry with backoff
+	wait, err := orchestrator.checkRetry(source1, lib.WorkerDMSync)
+	require.NoError(t.T(), err)
+	require.Zero(t.T(), wait)
+	orchestrator.OnUnitFailed(source1, lib.WorkerDMSync)
+	wait, err = orchestrator.checkRetry(source1, lib.WorkerDMSync)
+	require.NoError(t.T(), err)
+	require.Equal(t.T(), time.Second, wait)
+	mockClock.Add(time.Second)
+	wait, err = orchestrator.checkRetry(source1, lib.WorkerDMSync)
+	require.NoError(t.T(), err)
+	require.Zero(t.T(), wait)
+	orchestrator.OnUnitFailed(source1, lib.WorkerDMSync)
+	wait, err = orchestrator.checkRetry(source1, lib.WorkerDMSync)
+	require.NoError(t.T(), err)
+	require.Equal(t.T(), 2*time.Second, wait)
+
+	// the count of failures is reset if the unit fails after running long
+	mockClock.Add(unitRetryResetInterval + time.Second)
+	orchestrator.OnUnitFailed(source1, lib.WorkerDMSync)
+	wait, err = orchestrator.checkRetry(source1, lib.WorkerDMSync)
+	require.NoError(t.T(), err)
+	require.Equal(t.T(), time.Second, wait)
+
+	// retries are exhausted
+	for i := 0; i < unitMaxRetries; i++ {
+		orchestrator.OnUnitFailed(source1, lib.WorkerDMSync)
+	}
+	_, err = orchestrator.checkRetry(source1, lib.WorkerDMSync)
+	require.Error(t.T(), err)
+	require.Equal(t.T(), map[string]string{source1: err.Error()}, orchestrator.Errors())
+	// other units are not limited
+	wait, err = orchestrator.checkRetry(source1, lib.WorkerDMLoad)
+	require.NoError(t.T(), err)
+	require.Zero(t.T(), wait)
+
+	// resumed by user
+	orchestrator.Reset(nil)
+	wait, err = orchestrator.checkRetry(source1, lib.WorkerDMSync)
+	require.NoError(t.T(), err)
+	require.Zero(t.T(), wait)
+	require.Len(t.T(), orchestrator.Errors(), 0)
+
+	// finished unit clears the retries
+	orchestrator.OnUnitFailed(source1, lib.WorkerDMDump)
+	orchestrator.OnUnitFinished(&runtime.DumpStatus{
+		DefaultTaskStatus: runtime.DefaultTaskStatus{
+			Unit:  lib.WorkerDMDump,
+			Task:  source1,
+			Stage: metadata.StageFinished,
+		},
+	})
+	wait, err = orchestrator.checkRetry(source1, lib.WorkerDMDump)
+	require.NoError(t.T(), err)
+	require.Zero(t.T(), wait)
+}
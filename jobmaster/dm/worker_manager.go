@@ -43,10 +43,20 @@ type WorkerManager struct {
 	jobStore        *metadata.JobStore
 	workerAgent     WorkerAgent
 	checkpointAgent CheckpointAgent
+	orchestrator    *UnitOrchestrator
 
 	// workerStatusMap record the runtime worker status
 	// taskID -> WorkerStatus
 	workerStatusMap sync.Map
+	// cfgVersions records the version of the task config the worker of a
+	// task is created with.
+	// taskID -> workerCfgVersion
+	cfgVersions sync.Map
+}
+
+type workerCfgVersion struct {
+	workerID libModel.WorkerID
+	version  uint64
 }
 
 // NewWorkerManager creates a new WorkerManager instance
@@ -56,6 +66,7 @@ func NewWorkerManager(initWorkerStatus []runtime.WorkerStatus, jobStore *metadat
 		jobStore:        jobStore,
 		workerAgent:     workerAgent,
 		checkpointAgent: checkpointAgent,
+		orchestrator:    NewUnitOrchestrator(),
 	}
 	workerManager.DefaultTicker.Ticker = workerManager
 
@@ -136,27 +147,60 @@ func (wm *WorkerManager) onJobNotExist(ctx context.Context) error {
 	return recordError
 }
 
-// stop unneeded workers, usually happened when update-job delete some tasks.
+// stop unneeded workers, usually happened when update-job delete some tasks
+// or user stops some tasks.
+// The workers running with an outdated task config are also stopped, they're
+// recreated with the new config after they are offline.
 func (wm *WorkerManager) stopUnneededWorkers(ctx context.Context, job *metadata.Job) error {
 	var recordError error
 	wm.workerStatusMap.Range(func(key, value interface{}) bool {
 		taskID := key.(string)
-		if _, ok := job.Tasks[taskID]; !ok {
-			log.L().Info("stop unneeded worker", zap.String("task_id", taskID), zap.String("worker_id", value.(runtime.WorkerStatus).ID))
-			if err := wm.stopWorker(ctx, taskID, value.(runtime.WorkerStatus).ID); err != nil {
-				recordError = err
-			}
+		workerStatus := value.(runtime.WorkerStatus)
+		task, ok := job.Tasks[taskID]
+		switch {
+		case !ok || task.Stage == metadata.StageStopped:
+			log.L().Info("stop unneeded worker", zap.String("task_id", taskID), zap.String("worker_id", workerStatus.ID))
+		case wm.cfgOutdated(task, workerStatus):
+			log.L().Info("restart worker with updated config", zap.String("task_id", taskID), zap.String("worker_id", workerStatus.ID), zap.Uint64("version", task.Version))
+		default:
+			return true
+		}
+		if err := wm.stopWorker(ctx, taskID, workerStatus.ID); err != nil {
+			recordError = err
 		}
 		return true
 	})
 	return recordError
 }
 
+// cfgOutdated returns whether the running worker is created with an older
+// version of the task config. The version of the workers created before the
+// job master fails over is unknown, so they are restarted once if the config
+// of the task has ever been updated.
+func (wm *WorkerManager) cfgOutdated(task *metadata.Task, worker runtime.WorkerStatus) bool {
+	if worker.Stage == runtime.WorkerFinished || worker.IsOffline() {
+		return false
+	}
+	var version uint64
+	if v, ok := wm.cfgVersions.Load(worker.TaskID); ok && v.(workerCfgVersion).workerID == worker.ID {
+		version = v.(workerCfgVersion).version
+	}
+	return version != task.Version
+}
+
+// hasWorker returns whether the worker is the expected worker of the task,
+// the workers stopped by the worker manager are not expected.
+func (wm *WorkerManager) hasWorker(taskID string, workerID libModel.WorkerID) bool {
+	v, ok := wm.workerStatusMap.Load(taskID)
+	return ok && v.(runtime.WorkerStatus).ID == workerID
+}
+
 // checkAndScheduleWorkers check whether a task need a new worker.
 // If there is no related worker, create a new worker.
 // If task is finished, check whether need a new worker.
-// This function does not handle taskCfg updated(update-job).
-// TODO: support update taskCfg, or we may need to send update request manually.
+// Stopped tasks don't need workers.
+// Failed units are recreated with backoff by the orchestrator.
+// The workers with an outdated taskCfg are restarted by stopUnneededWorkers.
 func (wm *WorkerManager) checkAndScheduleWorkers(ctx context.Context, job *metadata.Job) error {
 	var (
 		runningWorker runtime.WorkerStatus
@@ -167,6 +211,11 @@ func (wm *WorkerManager) checkAndScheduleWorkers(ctx context.Context, job *metad
 
 	// check and schedule workers
 	for taskID, persistentTask := range job.Tasks {
+		if persistentTask.Stage == metadata.StageStopped {
+			log.L().Debug("task is stopped", zap.String("task_id", taskID))
+			continue
+		}
+
 		worker, ok := wm.workerStatusMap.Load(taskID)
 		if ok {
 			runningWorker = worker.(runtime.WorkerStatus)
@@ -188,6 +237,19 @@ func (wm *WorkerManager) checkAndScheduleWorkers(ctx context.Context, job *metad
 			log.L().Info("switch to next unit", zap.String("task_id", taskID), zap.Int64("next_unit", int64(runningWorker.Unit)))
 		}
 
+		wait, err := wm.orchestrator.checkRetry(taskID, nextUnit)
+		if err != nil {
+			// the error is reported in job status, no need to check again
+			// until user resumes the task.
+			log.L().Warn("retries of unit are exhausted", zap.String("task_id", taskID), zap.Error(err))
+			continue
+		}
+		if wait > 0 {
+			log.L().Info("wait to retry failed unit", zap.String("task_id", taskID), zap.Int64("unit", int64(nextUnit)), zap.Duration("wait", wait))
+			wm.SetNextCheckTime(time.Now().Add(wait))
+			continue
+		}
+
 		var resources []resourcemeta.ResourceID
 		// we can assure only first worker don't need local resource.
 		if workerIdxInSeq(persistentTask.Cfg.TaskMode, nextUnit) != 0 {
@@ -195,7 +257,8 @@ func (wm *WorkerManager) checkAndScheduleWorkers(ctx context.Context, job *metad
 		}
 
 		// createWorker should be an asynchronous operation
-		if err := wm.createWorker(ctx, taskID, nextUnit, persistentTask.Cfg, resources...); err != nil {
+		taskCfg := wm.orchestrator.TaskConfig(taskID, nextUnit, persistentTask.Cfg)
+		if err := wm.createWorker(ctx, taskID, nextUnit, taskCfg, persistentTask.Version, resources...); err != nil {
 			recordError = err
 			continue
 		}
@@ -283,6 +346,7 @@ func (wm *WorkerManager) createWorker(
 	taskID string,
 	unit libModel.WorkerType,
 	taskCfg *config.TaskCfg,
+	cfgVersion uint64,
 	resources ...resourcemeta.ResourceID,
 ) error {
 	log.L().Info("start to create worker", zap.String("task_id", taskID), zap.Int64("unit", int64(unit)))
@@ -300,6 +364,7 @@ func (wm *WorkerManager) createWorker(
 		//	We choose the second mechanism now.
 		//	If a worker is created but never receives a dispatch/online/offline event(2 ticker?), we should remove it.
 		wm.UpdateWorkerStatus(runtime.InitWorkerStatus(taskID, unit, workerID))
+		wm.cfgVersions.Store(taskID, workerCfgVersion{workerID: workerID, version: cfgVersion})
 	}
 	return err
 }
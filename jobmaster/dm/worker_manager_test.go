@@ -178,12 +178,12 @@ func (t *testDMJobmasterSuite) TestCreateWorker() {
 	worker1 := "worker1"
 	createError := errors.New("create error")
 	mockAgent.SetCreateResult([]CreateResult{{"", createError}})
-	require.EqualError(t.T(), workerManager.createWorker(ctx, task1, lib.WorkerDMDump, &config.TaskCfg{}), createError.Error())
+	require.EqualError(t.T(), workerManager.createWorker(ctx, task1, lib.WorkerDMDump, &config.TaskCfg{}, 0), createError.Error())
 	require.Len(t.T(), workerManager.WorkerStatus(), 0)
 
 	workerStatus1 := runtime.InitWorkerStatus(task1, lib.WorkerDMDump, worker1)
 	mockAgent.SetCreateResult([]CreateResult{{worker1, createError}})
-	require.EqualError(t.T(), workerManager.createWorker(ctx, task1, lib.WorkerDMDump, &config.TaskCfg{}), createError.Error())
+	require.EqualError(t.T(), workerManager.createWorker(ctx, task1, lib.WorkerDMDump, &config.TaskCfg{}, 0), createError.Error())
 	workerStatusMap := workerManager.WorkerStatus()
 	require.Len(t.T(), workerStatusMap, 1)
 	require.Contains(t.T(), workerStatusMap, task1)
@@ -193,7 +193,7 @@ func (t *testDMJobmasterSuite) TestCreateWorker() {
 	worker2 := "worker2"
 	workerStatus2 := runtime.InitWorkerStatus(task2, lib.WorkerDMLoad, worker2)
 	mockAgent.SetCreateResult([]CreateResult{{worker2, nil}})
-	require.NoError(t.T(), workerManager.createWorker(ctx, task2, lib.WorkerDMLoad, &config.TaskCfg{}))
+	require.NoError(t.T(), workerManager.createWorker(ctx, task2, lib.WorkerDMLoad, &config.TaskCfg{}, 0))
 	workerStatusMap = workerManager.WorkerStatus()
 	require.Len(t.T(), workerStatusMap, 2)
 	require.Contains(t.T(), workerStatusMap, task1)
@@ -358,6 +358,15 @@ func (t *testDMJobmasterSuite) TestCheckAndScheduleWorkers() {
 	require.Contains(t.T(), wokerStatusMap, source2)
 	require.Equal(t.T(), wokerStatusMap[source1].ID, workerStatus4.ID)
 	require.Equal(t.T(), wokerStatusMap[source2].ID, workerStatus2.ID)
+
+	// stopped
+	job.Tasks[source2].Stage = metadata.StageStopped
+	workerAgent.SetDestroyResult([]error{nil})
+	require.NoError(t.T(), workerManager.stopUnneededWorkers(context.Background(), job))
+	require.NoError(t.T(), workerManager.checkAndScheduleWorkers(context.Background(), job))
+	wokerStatusMap = workerManager.WorkerStatus()
+	require.Len(t.T(), wokerStatusMap, 1)
+	require.Contains(t.T(), wokerStatusMap, source1)
 }
 
 func (t *testDMJobmasterSuite) TestWorkerManager() {
@@ -487,6 +496,52 @@ func (t *testDMJobmasterSuite) TestWorkerManager() {
 	wg.Wait()
 }
 
+func (t *testDMJobmasterSuite) TestRestartOutdatedWorkers() {
+	jobCfg := &config.JobCfg{}
+	require.NoError(t.T(), jobCfg.DecodeFile(jobTemplatePath))
+	job := metadata.NewJob(jobCfg)
+	source1 := jobCfg.Upstreams[0].SourceID
+	source2 := jobCfg.Upstreams[1].SourceID
+	workerAgent := &MockWorkerAgent{}
+	workerManager := NewWorkerManager(nil, nil, workerAgent, nil)
+	ctx := context.Background()
+
+	// worker1 is created by the worker manager, worker2 is recovered after failover
+	workerAgent.SetCreateResult([]CreateResult{{"worker1", nil}})
+	require.NoError(t.T(), workerManager.createWorker(ctx, source1, lib.WorkerDMDump, job.Tasks[source1].Cfg, 0))
+	workerManager.UpdateWorkerStatus(runtime.NewWorkerStatus(source2, lib.WorkerDMDump, "worker2", runtime.WorkerOnline))
+	// no config is updated, no panic in mock agent
+	require.NoError(t.T(), workerManager.stopUnneededWorkers(ctx, job))
+	require.Len(t.T(), workerManager.WorkerStatus(), 2)
+
+	// config of source1 is updated
+	job.Tasks[source1].Version = 1
+	workerAgent.SetDestroyResult([]error{nil})
+	require.NoError(t.T(), workerManager.stopUnneededWorkers(ctx, job))
+	require.Len(t.T(), workerManager.WorkerStatus(), 1)
+	require.False(t.T(), workerManager.hasWorker(source1, "worker1"))
+	require.True(t.T(), workerManager.hasWorker(source2, "worker2"))
+
+	// recreated with the new config
+	workerAgent.SetCreateResult([]CreateResult{{"worker3", nil}})
+	require.NoError(t.T(), workerManager.createWorker(ctx, source1, lib.WorkerDMDump, job.Tasks[source1].Cfg, 1))
+	require.NoError(t.T(), workerManager.stopUnneededWorkers(ctx, job))
+	require.Len(t.T(), workerManager.WorkerStatus(), 2)
+
+	// the config version of recovered worker is unknown
+	job.Tasks[source2].Version = 1
+	workerAgent.SetDestroyResult([]error{nil})
+	require.NoError(t.T(), workerManager.stopUnneededWorkers(ctx, job))
+	require.Len(t.T(), workerManager.WorkerStatus(), 1)
+	require.True(t.T(), workerManager.hasWorker(source1, "worker3"))
+
+	// finished worker is not restarted, the next unit is created with the new config
+	workerManager.UpdateWorkerStatus(runtime.NewWorkerStatus(source1, lib.WorkerDMDump, "worker3", runtime.WorkerFinished))
+	job.Tasks[source1].Version = 2
+	require.NoError(t.T(), workerManager.stopUnneededWorkers(ctx, job))
+	require.Len(t.T(), workerManager.WorkerStatus(), 1)
+}
+
 type CreateResult struct {
 	workerID libModel.WorkerID
 	err      error
@@ -0,0 +1,291 @@
+package lib
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/pkg/adapter"
+	"github.com/hanfei1991/microcosm/pkg/clock"
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/logutil"
+	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
+)
+
+// barrierRefreshInterval is the interval to reload the reached workers of
+// pending barriers from metastore, in case that notifications are lost.
+const barrierRefreshInterval = 10 * time.Second
+
+// BarrierAwareMasterImpl can be implemented by a MasterImpl to be notified
+// when a barrier requested by BaseMaster.RequestBarrier is reached.
+type BarrierAwareMasterImpl interface {
+	// OnBarrierReached is called in Poll exactly once for each barrier. The
+	// barrier is marked as reached in metastore before the call, so after
+	// a failover, the master should check the barriers it is waiting for
+	// with IsBarrierReached.
+	OnBarrierReached(barrierID string) error
+}
+
+// Barrier is a named point that the workers of a master report reaching.
+type Barrier struct {
+	ID      string              `json:"id"`
+	Workers []libModel.WorkerID `json:"workers"`
+	// Quorum is the number of workers that must reach the barrier, 0 means all.
+	Quorum  int  `json:"quorum"`
+	Reached bool `json:"reached"`
+}
+
+func (b *Barrier) quorum() int {
+	if b.Quorum <= 0 || b.Quorum > len(b.Workers) {
+		return len(b.Workers)
+	}
+	return b.Quorum
+}
+
+type barrierState struct {
+	barrier     *Barrier
+	reached     map[libModel.WorkerID]struct{}
+	lastRefresh time.Time
+}
+
+func (s *barrierState) reachedCount() int {
+	count := 0
+	for _, workerID := range s.barrier.Workers {
+		if _, ok := s.reached[workerID]; ok {
+			count++
+		}
+	}
+	return count
+}
+
+// barrierManager persists the barriers of a master and tracks the workers
+// that have reached them.
+type barrierManager struct {
+	masterID libModel.MasterID
+	kv       metaclient.KV
+	clock    clock.Clock
+
+	mu       sync.Mutex
+	barriers map[string]*barrierState
+}
+
+func newBarrierManager(masterID libModel.MasterID, kv metaclient.KV, clock clock.Clock) *barrierManager {
+	return &barrierManager{
+		masterID: masterID,
+		kv:       kv,
+		clock:    clock,
+		barriers: make(map[string]*barrierState),
+	}
+}
+
+// load loads all barriers of the master from metastore, it is called when
+// the master is initialized.
+func (b *barrierManager) load(ctx context.Context) error {
+	resp, err := b.kv.Get(ctx, adapter.BarrierKeyAdapter.Curry(b.masterID).Path(), metaclient.WithPrefix())
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	barriers := make(map[string]*barrierState, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		barrier := &Barrier{}
+		if err := json.Unmarshal(kv.Value, barrier); err != nil {
+			return errors.Trace(err)
+		}
+		state := &barrierState{barrier: barrier}
+		if !barrier.Reached {
+			reached, err := b.loadReached(ctx, barrier.ID)
+			if err != nil {
+				return err
+			}
+			state.reached = reached
+			state.lastRefresh = b.clock.Now()
+		}
+		barriers[barrier.ID] = state
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.barriers = barriers
+	return nil
+}
+
+func (b *barrierManager) loadReached(ctx context.Context, barrierID string) (map[libModel.WorkerID]struct{}, error) {
+	resp, err := b.kv.Get(ctx,
+		adapter.BarrierReachedKeyAdapter.Curry(b.masterID, barrierID).Path(), metaclient.WithPrefix())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	reached := make(map[libModel.WorkerID]struct{}, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		reached[string(kv.Value)] = struct{}{}
+	}
+	return reached, nil
+}
+
+func (b *barrierManager) persist(ctx context.Context, barrier *Barrier) error {
+	value, err := json.Marshal(barrier)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, metaErr := b.kv.Put(ctx, adapter.BarrierKeyAdapter.Encode(b.masterID, barrier.ID), string(value))
+	return errors.Trace(metaErr)
+}
+
+func (b *barrierManager) request(ctx context.Context, barrier *Barrier) error {
+	b.mu.Lock()
+	_, exists := b.barriers[barrier.ID]
+	b.mu.Unlock()
+	if exists {
+		return derror.ErrBarrierDuplicated.GenWithStackByArgs(barrier.ID)
+	}
+
+	if err := b.persist(ctx, barrier); err != nil {
+		return err
+	}
+	// workers may have reached the barrier before it is requested
+	reached, err := b.loadReached(ctx, barrier.ID)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.barriers[barrier.ID] = &barrierState{
+		barrier:     barrier,
+		reached:     reached,
+		lastRefresh: b.clock.Now(),
+	}
+	return nil
+}
+
+func (b *barrierManager) onWorkerReached(workerID libModel.WorkerID, barrierID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state, ok := b.barriers[barrierID]
+	if !ok || state.barrier.Reached {
+		return
+	}
+	state.reached[workerID] = struct{}{}
+}
+
+// poll marks the barriers that have been reached by a quorum of workers,
+// and calls notify for each of them.
+func (b *barrierManager) poll(ctx context.Context, notify func(barrierID string) error) error {
+	b.mu.Lock()
+	var pending []*barrierState
+	for _, state := range b.barriers {
+		if !state.barrier.Reached {
+			pending = append(pending, state)
+		}
+	}
+	b.mu.Unlock()
+
+	for _, state := range pending {
+		if b.clock.Since(state.lastRefresh) >= barrierRefreshInterval {
+			reached, err := b.loadReached(ctx, state.barrier.ID)
+			if err != nil {
+				return err
+			}
+			b.mu.Lock()
+			for workerID := range reached {
+				state.reached[workerID] = struct{}{}
+			}
+			state.lastRefresh = b.clock.Now()
+			b.mu.Unlock()
+		}
+
+		b.mu.Lock()
+		count := state.reachedCount()
+		b.mu.Unlock()
+		if count < state.barrier.quorum() {
+			continue
+		}
+
+		reachedBarrier := *state.barrier
+		reachedBarrier.Reached = true
+		if err := b.persist(ctx, &reachedBarrier); err != nil {
+			return err
+		}
+		b.mu.Lock()
+		state.barrier = &reachedBarrier
+		b.mu.Unlock()
+
+		logutil.WithJobID(log.L(), b.masterID).Info("barrier reached",
+			zap.String("barrier", reachedBarrier.ID),
+			zap.Int("reached-count", count))
+		if err := notify(reachedBarrier.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *barrierManager) isReached(barrierID string) (reached bool, exists bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state, ok := b.barriers[barrierID]
+	if !ok {
+		return false, false
+	}
+	return state.barrier.Reached, true
+}
+
+func (b *barrierManager) remove(ctx context.Context, barrierID string) error {
+	if _, err := b.kv.Delete(ctx,
+		adapter.BarrierReachedKeyAdapter.Curry(b.masterID, barrierID).Path(), metaclient.WithPrefix()); err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := b.kv.Delete(ctx, adapter.BarrierKeyAdapter.Encode(b.masterID, barrierID)); err != nil {
+		return errors.Trace(err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.barriers, barrierID)
+	return nil
+}
+
+// RequestBarrier implements BaseMaster.RequestBarrier
+func (m *DefaultBaseMaster) RequestBarrier(
+	ctx context.Context, barrierID string, workers []libModel.WorkerID, quorum int,
+) error {
+	ctx = m.errCenter.WithCancelOnFirstError(ctx)
+	if err := m.workerCaps.check(workers, libModel.CapabilityBarrier); err != nil {
+		return err
+	}
+	return m.barrierManager.request(ctx, &Barrier{
+		ID:      barrierID,
+		Workers: workers,
+		Quorum:  quorum,
+	})
+}
+
+// IsBarrierReached implements BaseMaster.IsBarrierReached
+func (m *DefaultBaseMaster) IsBarrierReached(barrierID string) (reached bool, exists bool) {
+	return m.barrierManager.isReached(barrierID)
+}
+
+// RemoveBarrier implements BaseMaster.RemoveBarrier
+func (m *DefaultBaseMaster) RemoveBarrier(ctx context.Context, barrierID string) error {
+	ctx = m.errCenter.WithCancelOnFirstError(ctx)
+	return m.barrierManager.remove(ctx, barrierID)
+}
+
+func (m *DefaultBaseMaster) pollBarriers(ctx context.Context) error {
+	return m.barrierManager.poll(ctx, func(barrierID string) error {
+		impl, ok := m.Impl.(BarrierAwareMasterImpl)
+		if !ok {
+			return nil
+		}
+		return impl.OnBarrierReached(barrierID)
+	})
+}
@@ -0,0 +1,82 @@
+package lib
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hanfei1991/microcosm/lib/statusutil"
+	"github.com/hanfei1991/microcosm/pkg/clock"
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/meta/kvclient/mock"
+	"github.com/hanfei1991/microcosm/pkg/p2p"
+)
+
+func TestBarrierManager(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	kv := mock.NewMetaMock()
+	clk := clock.NewMock()
+	manager := newBarrierManager(masterName, kv, clk)
+	require.NoError(t, manager.load(ctx))
+
+	var notified []string
+	notify := func(barrierID string) error {
+		notified = append(notified, barrierID)
+		return nil
+	}
+
+	masterInfo := &statusutil.MockMasterInfoProvider{}
+	masterInfo.Set(masterName, masterNodeName, 1)
+	reporter := func(workerID string) *statusutil.BarrierReporter {
+		return statusutil.NewBarrierReporter(kv, p2p.NewMockMessageSender(), masterInfo, workerID)
+	}
+
+	// worker-1 reaches the barrier before it is requested
+	require.NoError(t, reporter("worker-1").Reach(ctx, "dump-finished"))
+	err := manager.request(ctx, &Barrier{
+		ID:      "dump-finished",
+		Workers: []string{"worker-1", "worker-2", "worker-3"},
+		Quorum:  2,
+	})
+	require.NoError(t, err)
+	err = manager.request(ctx, &Barrier{ID: "dump-finished"})
+	require.True(t, derror.ErrBarrierDuplicated.Equal(err))
+
+	// unknown worker doesn't count
+	manager.onWorkerReached("worker-4", "dump-finished")
+	require.NoError(t, manager.poll(ctx, notify))
+	require.Empty(t, notified)
+
+	manager.onWorkerReached("worker-2", "dump-finished")
+	require.NoError(t, manager.poll(ctx, notify))
+	require.NoError(t, manager.poll(ctx, notify))
+	require.Equal(t, []string{"dump-finished"}, notified)
+	reached, exists := manager.isReached("dump-finished")
+	require.True(t, reached)
+	require.True(t, exists)
+
+	// the notification of worker-3 is lost, and the master fails over
+	err = manager.request(ctx, &Barrier{
+		ID:      "load-finished",
+		Workers: []string{"worker-1", "worker-3"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, reporter("worker-1").Reach(ctx, "load-finished"))
+	require.NoError(t, reporter("worker-3").Reach(ctx, "load-finished"))
+
+	manager = newBarrierManager(masterName, kv, clk)
+	require.NoError(t, manager.load(ctx))
+	reached, exists = manager.isReached("dump-finished")
+	require.True(t, reached)
+	require.True(t, exists)
+	notified = nil
+	require.NoError(t, manager.poll(ctx, notify))
+	require.Equal(t, []string{"load-finished"}, notified)
+
+	require.NoError(t, manager.remove(ctx, "load-finished"))
+	_, exists = manager.isReached("load-finished")
+	require.False(t, exists)
+}
@@ -2,17 +2,23 @@ package lib
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
 
 	"github.com/hanfei1991/microcosm/executor/worker"
+	"github.com/hanfei1991/microcosm/lib/master"
 	libModel "github.com/hanfei1991/microcosm/lib/model"
 	"github.com/hanfei1991/microcosm/model"
+	"github.com/hanfei1991/microcosm/pkg/compat"
 	dcontext "github.com/hanfei1991/microcosm/pkg/context"
 	"github.com/hanfei1991/microcosm/pkg/errctx"
 	derror "github.com/hanfei1991/microcosm/pkg/errors"
 	resourcemeta "github.com/hanfei1991/microcosm/pkg/externalresource/resourcemeta/model"
+	"github.com/hanfei1991/microcosm/pkg/logutil"
 	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
 	"github.com/hanfei1991/microcosm/pkg/p2p"
 )
@@ -27,9 +33,25 @@ type BaseJobMaster interface {
 	MetaKVClient() metaclient.KVClient
 	GetWorkers() map[libModel.WorkerID]WorkerHandle
 	CreateWorker(workerType WorkerType, config WorkerConfig, cost model.RescUnit, resources ...resourcemeta.ResourceID) (libModel.WorkerID, error)
+	// RecreateWorker re-dispatches a worker lost due to executor failure,
+	// see BaseMaster.RecreateWorker.
+	RecreateWorker(workerType WorkerType, config WorkerConfig, cost model.RescUnit, resources ...resourcemeta.ResourceID) (libModel.WorkerID, error)
+	// CreateWorkerWithResources creates a worker requiring resources in
+	// multiple dimensions, see BaseMaster.CreateWorkerWithResources.
+	CreateWorkerWithResources(workerType WorkerType, config WorkerConfig, required model.RescVector, resources ...resourcemeta.ResourceID) (libModel.WorkerID, error)
+	// CreateWorkerQueued creates a worker waiting in the schedule queue if
+	// the cluster doesn't have enough resources, see
+	// BaseMaster.CreateWorkerQueued.
+	CreateWorkerQueued(workerType WorkerType, config WorkerConfig, required model.RescVector, priority int32, waitTimeout time.Duration, resources ...resourcemeta.ResourceID) (libModel.WorkerID, error)
+	// RequestScaleUp requests the capacity of more workers, see
+	// BaseMaster.RequestScaleUp.
+	RequestScaleUp(ctx context.Context, currentWorkers, workers int) (granted int, backoff time.Duration, err error)
 	JobMasterID() libModel.MasterID
 	UpdateJobStatus(ctx context.Context, status libModel.WorkerStatus) error
 	CurrentEpoch() libModel.Epoch
+	// Logger returns a logger tagged with the job and the current epoch,
+	// see BaseMaster.Logger.
+	Logger() log.Logger
 
 	// Exit should be called when job master (in user logic) wants to exit
 	// - If err is nil, it means job master exits normally
@@ -42,6 +64,36 @@ type BaseJobMaster interface {
 	// the return value is always true.
 	IsMasterReady() bool
 
+	// RegisterDependencyChecker registers a checker of a downstream dependency,
+	// see BaseMaster.RegisterDependencyChecker. The health of dependencies is
+	// also surfaced in the error message of the job status.
+	RegisterDependencyChecker(checker DependencyChecker)
+	DependencyHealth() []DependencyHealth
+	// TickStats returns the latency of the latest Poll, see
+	// BaseMaster.TickStats.
+	TickStats() TickStats
+
+	// FeatureEnabled returns whether the feature is supported by the job
+	// master, its workers and its master, see BaseMaster.FeatureEnabled.
+	FeatureEnabled(feature compat.Feature) bool
+	// WorkerCapabilities returns the capabilities negotiated with a worker,
+	// see BaseMaster.WorkerCapabilities.
+	WorkerCapabilities(workerID libModel.WorkerID) (caps libModel.CapabilitySet, ok bool)
+
+	WorkerMessageHandlerRegistrar
+
+	// RequestBarrier, IsBarrierReached and RemoveBarrier manage barriers of
+	// workers, see BaseMaster.RequestBarrier.
+	RequestBarrier(ctx context.Context, barrierID string, workers []libModel.WorkerID, quorum int) error
+	IsBarrierReached(barrierID string) (reached bool, exists bool)
+	RemoveBarrier(ctx context.Context, barrierID string) error
+
+	// JobSnapshot exports a read-only snapshot of the job, see BaseMaster.JobSnapshot.
+	JobSnapshot(ctx context.Context) (*JobSnapshot, error)
+	// WorkerManagerDebugInfo dumps the in-memory state of the workers, see
+	// BaseMaster.WorkerManagerDebugInfo.
+	WorkerManagerDebugInfo() *master.DebugInfo
+
 	// IsBaseJobMaster is an empty function used to prevent accidental implementation
 	// of this interface.
 	IsBaseJobMaster()
@@ -53,6 +105,11 @@ type DefaultBaseJobMaster struct {
 	worker    *DefaultBaseWorker
 	impl      JobMasterImpl
 	errCenter *errctx.ErrCenter
+
+	statusMu sync.Mutex
+	// jobStatus is the latest status updated by the job master impl,
+	// without the dependency health appended.
+	jobStatus *libModel.WorkerStatus
 }
 
 // JobMasterImpl is the implementation of a job master of dataflow engine.
@@ -75,19 +132,25 @@ func NewBaseJobMaster(
 	jobMasterImpl JobMasterImpl,
 	masterID libModel.MasterID,
 	workerID libModel.WorkerID,
+	opts ...BaseMasterOption,
 ) BaseJobMaster {
 	// master-worker pair: job manager <-> job master(`baseWorker` following)
 	// master-worker pair: job master(`baseMaster` following) <-> real workers
 	// `masterID` is always the ID of master role, against current object
 	// `workerID` is the ID of current object
 	baseMaster := NewBaseMaster(
-		ctx, &jobMasterImplAsMasterImpl{jobMasterImpl}, workerID)
+		ctx, &jobMasterImplAsMasterImpl{jobMasterImpl}, workerID, opts...)
 	baseWorker := NewBaseWorker(
 		// TODO: need worker_type
 		ctx, &jobMasterImplAsWorkerImpl{jobMasterImpl}, workerID, masterID)
 	errCenter := errctx.NewErrCenter()
 	baseMaster.(*DefaultBaseMaster).errCenter = errCenter
 	baseWorker.(*DefaultBaseWorker).errCenter = errCenter
+	// the job master reports its worker creation progress to job manager
+	baseWorker.(*DefaultBaseWorker).creatingWorkers = baseMaster.(*DefaultBaseMaster).CreatingWorkerCount
+	// the log level of the job sent by job manager is forwarded to workers
+	baseWorker.(*DefaultBaseWorker).onLogLevelUpdated = baseMaster.(*DefaultBaseMaster).forwardJobLogLevel
+	baseWorker.(*DefaultBaseWorker).tickProbe = newTickProbe(jobMasterImpl)
 	return &DefaultBaseJobMaster{
 		master:    baseMaster.(*DefaultBaseMaster),
 		worker:    baseWorker.(*DefaultBaseWorker),
@@ -113,18 +176,34 @@ func (d *DefaultBaseJobMaster) Init(ctx context.Context) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	ctx = logutil.NewContext(ctx, d.Logger())
+
+	if err := d.registerTimeoutsUpdateHandler(ctx); err != nil {
+		return errors.Trace(err)
+	}
+	if err := d.registerTasksOperateHandler(ctx); err != nil {
+		return errors.Trace(err)
+	}
+	if err := d.registerSourceUpdateHandler(ctx); err != nil {
+		return errors.Trace(err)
+	}
+	if err := d.registerWorkersQueryHandler(ctx); err != nil {
+		return errors.Trace(err)
+	}
 
 	if isFirstStartUp {
 		if err := d.impl.InitImpl(ctx); err != nil {
 			return errors.Trace(err)
 		}
+		d.master.dependencyMonitor.checkAll(ctx)
 		if err := d.master.markStatusCodeInMetadata(ctx, libModel.MasterStatusInit); err != nil {
 			return errors.Trace(err)
 		}
 	} else {
-		if err := d.impl.OnMasterRecovered(ctx); err != nil {
+		if err := d.impl.OnMasterRecovered(withRecoveryReport(ctx, d.master.recoveryReport)); err != nil {
 			return errors.Trace(err)
 		}
+		d.master.dependencyMonitor.checkAll(ctx)
 	}
 
 	if err := d.worker.doPostInit(ctx); err != nil {
@@ -134,23 +213,127 @@ func (d *DefaultBaseJobMaster) Init(ctx context.Context) error {
 	return nil
 }
 
+// registerTimeoutsUpdateHandler handles the requests from job manager to
+// update the worker timeouts of this job, which is done by the framework
+// without involving the JobMasterImpl.
+func (d *DefaultBaseJobMaster) registerTimeoutsUpdateHandler(ctx context.Context) error {
+	topic := libModel.TimeoutsUpdateRequestTopic(d.worker.masterID, d.worker.id)
+	ok, err := d.worker.messageHandlerManager.RegisterHandler(
+		ctx,
+		topic,
+		&libModel.TimeoutsUpdateRequest{},
+		func(sender p2p.NodeID, value p2p.MessageValue) error {
+			msg, ok := value.(*libModel.TimeoutsUpdateRequest)
+			if !ok {
+				return derror.ErrInvalidMasterMessage.GenWithStackByArgs(value)
+			}
+			err := d.master.UpdateWorkerTimeouts(
+				msg.WorkerTimeoutDuration,
+				msg.WorkerTimeoutGracefulDuration,
+				msg.WorkerHeartbeatInterval,
+			)
+			if err != nil {
+				d.Logger().Warn("failed to update worker timeouts",
+					zap.Any("request", msg), zap.Error(err))
+			}
+			return nil
+		})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !ok {
+		d.Logger().Panic("duplicate handler", zap.String("topic", topic))
+	}
+	return nil
+}
+
+// registerTasksOperateHandler routes the task operations sent by job
+// manager to JobMasterImpl.OnJobManagerMessage, which is called in the same
+// goroutine as Tick.
+func (d *DefaultBaseJobMaster) registerTasksOperateHandler(ctx context.Context) error {
+	topic := libModel.TasksOperateRequestTopic(d.worker.masterID, d.worker.id)
+	ok, err := d.worker.messageHandlerManager.RegisterHandler(
+		ctx,
+		topic,
+		&libModel.TasksOperateRequest{},
+		func(sender p2p.NodeID, value p2p.MessageValue) error {
+			msg, ok := value.(*libModel.TasksOperateRequest)
+			if !ok {
+				return derror.ErrInvalidMasterMessage.GenWithStackByArgs(value)
+			}
+			if msg.Epoch < d.worker.masterClient.Epoch() {
+				d.Logger().Info("stale tasks operate request dropped",
+					zap.Any("request", msg))
+				return nil
+			}
+			d.worker.messageRouter.AppendMessage(topic, msg)
+			return nil
+		})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !ok {
+		d.Logger().Panic("duplicate handler", zap.String("topic", topic))
+	}
+	return nil
+}
+
+// registerSourceUpdateHandler routes the source updates sent by job manager
+// to JobMasterImpl.OnJobManagerMessage, which is called in the same
+// goroutine as Tick.
+func (d *DefaultBaseJobMaster) registerSourceUpdateHandler(ctx context.Context) error {
+	topic := libModel.SourceUpdateRequestTopic(d.worker.masterID, d.worker.id)
+	ok, err := d.worker.messageHandlerManager.RegisterHandler(
+		ctx,
+		topic,
+		&libModel.SourceUpdateRequest{},
+		func(sender p2p.NodeID, value p2p.MessageValue) error {
+			msg, ok := value.(*libModel.SourceUpdateRequest)
+			if !ok {
+				return derror.ErrInvalidMasterMessage.GenWithStackByArgs(value)
+			}
+			if msg.Epoch < d.worker.masterClient.Epoch() {
+				d.Logger().Info("stale source update request dropped",
+					zap.String("source-id", msg.SourceID))
+				return nil
+			}
+			d.worker.messageRouter.AppendMessage(topic, msg)
+			return nil
+		})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !ok {
+		d.Logger().Panic("duplicate handler", zap.String("topic", topic))
+	}
+	return nil
+}
+
 // Poll implements BaseJobMaster.Poll
 func (d *DefaultBaseJobMaster) Poll(ctx context.Context) error {
 	ctx = d.errCenter.WithCancelOnFirstError(ctx)
+	ctx = logutil.NewContext(ctx, d.Logger())
 
 	if err := d.master.doPoll(ctx); err != nil {
 		return errors.Trace(err)
 	}
+	if d.master.dependencyMonitor.takeChanged() {
+		if err := d.refreshJobStatus(ctx); err != nil {
+			return errors.Trace(err)
+		}
+	}
 	if err := d.worker.doPoll(ctx); err != nil {
 		if derror.ErrWorkerHalfExit.NotEqual(err) {
 			return errors.Trace(err)
 		}
 		return nil
 	}
-	if err := d.impl.Tick(ctx); err != nil {
+	if err := d.master.tickWatchdog.measure(tickPhaseImpl, func() error {
+		return d.worker.probedTick(ctx, d.impl.Tick)
+	}); err != nil {
 		return errors.Trace(err)
 	}
-	return nil
+	return d.master.tickWatchdog.finishPoll()
 }
 
 // GetWorkers implements BaseJobMaster.GetWorkers
@@ -160,6 +343,7 @@ func (d *DefaultBaseJobMaster) GetWorkers() map[libModel.WorkerID]WorkerHandle {
 
 // Close implements BaseJobMaster.Close
 func (d *DefaultBaseJobMaster) Close(ctx context.Context) error {
+	ctx = logutil.NewContext(ctx, d.Logger())
 	if err := d.impl.CloseImpl(ctx); err != nil {
 		return errors.Trace(err)
 	}
@@ -180,11 +364,59 @@ func (d *DefaultBaseJobMaster) CreateWorker(workerType WorkerType, config Worker
 	return d.master.CreateWorker(workerType, config, cost, resources...)
 }
 
+// RecreateWorker implements BaseJobMaster.RecreateWorker
+func (d *DefaultBaseJobMaster) RecreateWorker(workerType WorkerType, config WorkerConfig, cost model.RescUnit, resources ...resourcemeta.ResourceID) (libModel.WorkerID, error) {
+	return d.master.RecreateWorker(workerType, config, cost, resources...)
+}
+
+// CreateWorkerWithResources implements BaseJobMaster.CreateWorkerWithResources
+func (d *DefaultBaseJobMaster) CreateWorkerWithResources(workerType WorkerType, config WorkerConfig, required model.RescVector, resources ...resourcemeta.ResourceID) (libModel.WorkerID, error) {
+	return d.master.CreateWorkerWithResources(workerType, config, required, resources...)
+}
+
+// CreateWorkerQueued implements BaseJobMaster.CreateWorkerQueued
+func (d *DefaultBaseJobMaster) CreateWorkerQueued(workerType WorkerType, config WorkerConfig, required model.RescVector, priority int32, waitTimeout time.Duration, resources ...resourcemeta.ResourceID) (libModel.WorkerID, error) {
+	return d.master.CreateWorkerQueued(workerType, config, required, priority, waitTimeout, resources...)
+}
+
+// RequestScaleUp implements BaseJobMaster.RequestScaleUp
+func (d *DefaultBaseJobMaster) RequestScaleUp(ctx context.Context, currentWorkers, workers int) (int, time.Duration, error) {
+	return d.master.RequestScaleUp(ctx, currentWorkers, workers)
+}
+
 // UpdateStatus delegates the UpdateStatus of inner worker
 func (d *DefaultBaseJobMaster) UpdateStatus(ctx context.Context, status libModel.WorkerStatus) error {
 	ctx = d.errCenter.WithCancelOnFirstError(ctx)
 
-	return d.worker.UpdateStatus(ctx, status)
+	d.statusMu.Lock()
+	d.jobStatus = &status
+	d.statusMu.Unlock()
+	return d.worker.UpdateStatus(ctx, d.withDependencyHealth(status))
+}
+
+// refreshJobStatus updates the job status with the latest dependency health.
+func (d *DefaultBaseJobMaster) refreshJobStatus(ctx context.Context) error {
+	d.statusMu.Lock()
+	if d.jobStatus == nil {
+		d.statusMu.Unlock()
+		return nil
+	}
+	status := *d.jobStatus
+	d.statusMu.Unlock()
+
+	return d.worker.UpdateStatus(ctx, d.withDependencyHealth(status))
+}
+
+func (d *DefaultBaseJobMaster) withDependencyHealth(status libModel.WorkerStatus) libModel.WorkerStatus {
+	msg := d.master.dependencyMonitor.unhealthyMessage()
+	if msg == "" {
+		return status
+	}
+	if status.ErrorMessage != "" {
+		status.ErrorMessage += "; "
+	}
+	status.ErrorMessage += msg
+	return status
 }
 
 // Workload delegates the Workload of inner worker
@@ -206,7 +438,7 @@ func (d *DefaultBaseJobMaster) JobMasterID() libModel.MasterID {
 func (d *DefaultBaseJobMaster) UpdateJobStatus(ctx context.Context, status libModel.WorkerStatus) error {
 	ctx = d.errCenter.WithCancelOnFirstError(ctx)
 
-	return d.worker.UpdateStatus(ctx, status)
+	return d.UpdateStatus(ctx, status)
 }
 
 // CurrentEpoch implements BaseJobMaster.CurrentEpoch
@@ -214,6 +446,11 @@ func (d *DefaultBaseJobMaster) CurrentEpoch() libModel.Epoch {
 	return d.master.currentEpoch.Load()
 }
 
+// Logger implements BaseJobMaster.Logger
+func (d *DefaultBaseJobMaster) Logger() log.Logger {
+	return d.master.Logger()
+}
+
 // IsBaseJobMaster implements BaseJobMaster.IsBaseJobMaster
 func (d *DefaultBaseJobMaster) IsBaseJobMaster() {
 }
@@ -231,6 +468,68 @@ func (d *DefaultBaseJobMaster) IsMasterReady() bool {
 	return d.master.IsMasterReady()
 }
 
+// RegisterDependencyChecker implements BaseJobMaster.RegisterDependencyChecker
+func (d *DefaultBaseJobMaster) RegisterDependencyChecker(checker DependencyChecker) {
+	d.master.RegisterDependencyChecker(checker)
+}
+
+// DependencyHealth implements BaseJobMaster.DependencyHealth
+func (d *DefaultBaseJobMaster) DependencyHealth() []DependencyHealth {
+	return d.master.DependencyHealth()
+}
+
+// TickStats implements BaseJobMaster.TickStats
+func (d *DefaultBaseJobMaster) TickStats() TickStats {
+	return d.master.TickStats()
+}
+
+// FeatureEnabled implements BaseJobMaster.FeatureEnabled
+func (d *DefaultBaseJobMaster) FeatureEnabled(feature compat.Feature) bool {
+	return d.master.FeatureEnabled(feature) && d.worker.FeatureEnabled(feature)
+}
+
+// WorkerCapabilities implements BaseJobMaster.WorkerCapabilities
+func (d *DefaultBaseJobMaster) WorkerCapabilities(workerID libModel.WorkerID) (libModel.CapabilitySet, bool) {
+	return d.master.WorkerCapabilities(workerID)
+}
+
+// RegisterRawWorkerMessageHandler implements WorkerMessageHandlerRegistrar.RegisterRawWorkerMessageHandler
+func (d *DefaultBaseJobMaster) RegisterRawWorkerMessageHandler(
+	ctx context.Context,
+	topic p2p.Topic,
+	decode func(payload []byte) (interface{}, error),
+	handler func(worker WorkerHandle, message interface{}) error,
+) error {
+	return d.master.RegisterRawWorkerMessageHandler(ctx, topic, decode, handler)
+}
+
+// RequestBarrier implements BaseJobMaster.RequestBarrier
+func (d *DefaultBaseJobMaster) RequestBarrier(
+	ctx context.Context, barrierID string, workers []libModel.WorkerID, quorum int,
+) error {
+	return d.master.RequestBarrier(ctx, barrierID, workers, quorum)
+}
+
+// IsBarrierReached implements BaseJobMaster.IsBarrierReached
+func (d *DefaultBaseJobMaster) IsBarrierReached(barrierID string) (reached bool, exists bool) {
+	return d.master.IsBarrierReached(barrierID)
+}
+
+// RemoveBarrier implements BaseJobMaster.RemoveBarrier
+func (d *DefaultBaseJobMaster) RemoveBarrier(ctx context.Context, barrierID string) error {
+	return d.master.RemoveBarrier(ctx, barrierID)
+}
+
+// JobSnapshot implements BaseJobMaster.JobSnapshot
+func (d *DefaultBaseJobMaster) JobSnapshot(ctx context.Context) (*JobSnapshot, error) {
+	return d.master.JobSnapshot(ctx)
+}
+
+// WorkerManagerDebugInfo implements BaseJobMaster.WorkerManagerDebugInfo
+func (d *DefaultBaseJobMaster) WorkerManagerDebugInfo() *master.DebugInfo {
+	return d.master.WorkerManagerDebugInfo()
+}
+
 // Exit implements BaseJobMaster.Exit
 func (d *DefaultBaseJobMaster) Exit(ctx context.Context, status libModel.WorkerStatus, err error) error {
 	ctx = d.errCenter.WithCancelOnFirstError(ctx)
@@ -318,6 +617,41 @@ func (j *jobMasterImplAsMasterImpl) OnWorkerMessage(worker WorkerHandle, topic p
 	return j.inner.OnWorkerMessage(worker, topic, message)
 }
 
+func (j *jobMasterImplAsMasterImpl) OnBarrierReached(barrierID string) error {
+	if impl, ok := j.inner.(BarrierAwareMasterImpl); ok {
+		return impl.OnBarrierReached(barrierID)
+	}
+	return nil
+}
+
+func (j *jobMasterImplAsMasterImpl) OnWorkerUnresponsive(worker WorkerHandle, missedHeartbeats int) error {
+	if impl, ok := j.inner.(WorkerUnresponsiveAwareMasterImpl); ok {
+		return impl.OnWorkerUnresponsive(worker, missedHeartbeats)
+	}
+	return nil
+}
+
+func (j *jobMasterImplAsMasterImpl) RecoverIncrementally() bool {
+	if impl, ok := j.inner.(IncrementalRecoveryMasterImpl); ok {
+		return impl.RecoverIncrementally()
+	}
+	return false
+}
+
+func (j *jobMasterImplAsMasterImpl) OnRecoveryProgress(hydrated, total int) error {
+	if impl, ok := j.inner.(IncrementalRecoveryMasterImpl); ok {
+		return impl.OnRecoveryProgress(hydrated, total)
+	}
+	return nil
+}
+
+func (j *jobMasterImplAsMasterImpl) FailoverFast() bool {
+	if impl, ok := j.inner.(FastFailoverMasterImpl); ok {
+		return impl.FailoverFast()
+	}
+	return false
+}
+
 func (j *jobMasterImplAsMasterImpl) CloseImpl(ctx context.Context) error {
 	log.L().Panic("unexpected Close call")
 	return nil
@@ -8,12 +8,15 @@ import (
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
 
 	"github.com/hanfei1991/microcosm/client"
+	"github.com/hanfei1991/microcosm/lib/config"
 	libModel "github.com/hanfei1991/microcosm/lib/model"
 	"github.com/hanfei1991/microcosm/model"
 	dcontext "github.com/hanfei1991/microcosm/pkg/context"
 	"github.com/hanfei1991/microcosm/pkg/deps"
+	"github.com/hanfei1991/microcosm/pkg/logutil"
 	mockkv "github.com/hanfei1991/microcosm/pkg/meta/kvclient/mock"
 	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
 	"github.com/hanfei1991/microcosm/pkg/p2p"
@@ -215,3 +218,142 @@ func TestBaseJobMasterBasics(t *testing.T) {
 	jobMaster.AssertNumberOfCalls(t, "CloseImpl", 1)
 	jobMaster.mu.Unlock()
 }
+
+func TestBaseJobMasterUpdateTimeouts(t *testing.T) {
+	jobMaster := &testJobMasterImpl{}
+	base := newBaseJobMasterForTests(jobMaster)
+	jobMaster.DefaultBaseJobMaster = base
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	jobMaster.mu.Lock()
+	jobMaster.On("InitImpl", mock.Anything).Return(nil)
+	jobMaster.mu.Unlock()
+
+	err := jobMaster.Init(ctx)
+	require.NoError(t, err)
+
+	handlerManager := base.worker.messageHandlerManager.(*p2p.MockMessageHandlerManager)
+	err = handlerManager.InvokeHandler(t,
+		libModel.TimeoutsUpdateRequestTopic(masterName, workerID1),
+		"job-manager-node",
+		&libModel.TimeoutsUpdateRequest{
+			FromMasterID:          masterName,
+			WorkerTimeoutDuration: time.Minute,
+		})
+	require.NoError(t, err)
+
+	timeouts := base.master.workerManager.Timeouts()
+	require.Equal(t, time.Minute, timeouts.WorkerTimeoutDuration)
+	// unchanged
+	require.Equal(t, config.DefaultTimeoutConfig().WorkerTimeoutGracefulDuration,
+		timeouts.WorkerTimeoutGracefulDuration)
+	require.Equal(t, config.DefaultTimeoutConfig().WorkerHeartbeatInterval,
+		timeouts.WorkerHeartbeatInterval)
+
+	jobMaster.mu.Lock()
+	jobMaster.On("CloseImpl", mock.Anything).Return(nil)
+	jobMaster.mu.Unlock()
+	err = jobMaster.Close(ctx)
+	require.NoError(t, err)
+}
+
+func TestBaseJobMasterUpdateLogLevel(t *testing.T) {
+	jobMaster := &testJobMasterImpl{}
+	base := newBaseJobMasterForTests(jobMaster)
+	jobMaster.DefaultBaseJobMaster = base
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	jobMaster.mu.Lock()
+	jobMaster.On("InitImpl", mock.Anything).Return(nil)
+	jobMaster.mu.Unlock()
+
+	err := jobMaster.Init(ctx)
+	require.NoError(t, err)
+
+	handlerManager := base.worker.messageHandlerManager.(*p2p.MockMessageHandlerManager)
+	updateLevel := func(level string) {
+		err := handlerManager.InvokeHandler(t,
+			libModel.LogLevelUpdateRequestTopic(masterName, workerID1),
+			"job-manager-node",
+			&libModel.LogLevelUpdateRequest{
+				FromMasterID: masterName,
+				JobID:        workerID1,
+				Level:        level,
+			})
+		require.NoError(t, err)
+	}
+
+	updateLevel("debug")
+	level, ok := logutil.JobLevel(workerID1)
+	require.True(t, ok)
+	require.Equal(t, zapcore.DebugLevel, level)
+	// the level is kept for the workers online later
+	require.Equal(t, "debug", base.master.jobLogLevel.Load())
+
+	// an invalid level is ignored
+	updateLevel("verbose")
+	level, ok = logutil.JobLevel(workerID1)
+	require.True(t, ok)
+	require.Equal(t, zapcore.DebugLevel, level)
+
+	updateLevel("")
+	_, ok = logutil.JobLevel(workerID1)
+	require.False(t, ok)
+	require.Equal(t, "", base.master.jobLogLevel.Load())
+
+	jobMaster.mu.Lock()
+	jobMaster.On("CloseImpl", mock.Anything).Return(nil)
+	jobMaster.mu.Unlock()
+	err = jobMaster.Close(ctx)
+	require.NoError(t, err)
+}
+
+func TestBaseJobMasterQueryWorkers(t *testing.T) {
+	jobMaster := &testJobMasterImpl{}
+	base := newBaseJobMasterForTests(jobMaster)
+	jobMaster.DefaultBaseJobMaster = base
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	jobMaster.mu.Lock()
+	jobMaster.On("InitImpl", mock.Anything).Return(nil)
+	jobMaster.mu.Unlock()
+
+	err := jobMaster.Init(ctx)
+	require.NoError(t, err)
+
+	base.master.workerManager.BeforeStartingWorker("worker-a", "executor-1")
+	base.master.workerManager.BeforeStartingWorker("worker-b", "executor-2")
+
+	handlerManager := base.worker.messageHandlerManager.(*p2p.MockMessageHandlerManager)
+	err = handlerManager.InvokeHandler(t,
+		libModel.WorkersQueryRequestTopic(masterName, workerID1),
+		"job-manager-node",
+		&libModel.WorkersQueryRequest{
+			RequestID:    1,
+			FromMasterID: masterName,
+			Query:        libModel.WorkersQuery{ExecutorID: "executor-2"},
+		})
+	require.NoError(t, err)
+
+	sender := base.worker.messageSender.(*p2p.MockMessageSender)
+	msg, ok := sender.TryPop("job-manager-node", libModel.WorkersQueryResponseTopic(masterName))
+	require.True(t, ok)
+	resp := msg.(*libModel.WorkersQueryResponse)
+	require.Equal(t, uint64(1), resp.RequestID)
+	require.Len(t, resp.Workers, 1)
+	require.Equal(t, "worker-b", resp.Workers[0].ID)
+	require.Equal(t, libModel.WorkerStatusCreated, resp.Workers[0].StatusCode)
+	require.Empty(t, resp.NextPageToken)
+
+	jobMaster.mu.Lock()
+	jobMaster.On("CloseImpl", mock.Anything).Return(nil)
+	jobMaster.mu.Unlock()
+	err = jobMaster.Close(ctx)
+	require.NoError(t, err)
+}
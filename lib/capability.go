@@ -0,0 +1,103 @@
+package lib
+
+import (
+	"sync"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+// workerCapabilities records the capabilities negotiated with each worker
+// of a master in the heartbeats.
+type workerCapabilities struct {
+	mu   sync.RWMutex
+	caps map[libModel.WorkerID]libModel.CapabilitySet
+}
+
+func newWorkerCapabilities() *workerCapabilities {
+	return &workerCapabilities{
+		caps: make(map[libModel.WorkerID]libModel.CapabilitySet),
+	}
+}
+
+// negotiate records the capabilities announced by a worker in a heartbeat
+// ping, and returns the capabilities supported by both sides. changed is
+// true if they differ from the ones recorded before.
+func (c *workerCapabilities) negotiate(
+	workerID libModel.WorkerID, workerCaps libModel.CapabilitySet,
+) (common libModel.CapabilitySet, changed bool) {
+	if workerCaps == nil {
+		workerCaps = libModel.LegacyCapabilities()
+	}
+	common = libModel.FrameworkCapabilities().Intersect(workerCaps)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	old, ok := c.caps[workerID]
+	c.caps[workerID] = common
+	return common, !ok || !equalCapabilities(old, common)
+}
+
+func (c *workerCapabilities) get(workerID libModel.WorkerID) (libModel.CapabilitySet, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	caps, ok := c.caps[workerID]
+	return caps, ok
+}
+
+func (c *workerCapabilities) remove(workerID libModel.WorkerID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.caps, workerID)
+}
+
+// check returns an error if any of the workers has negotiated its
+// capabilities and doesn't support the given one. Workers that haven't sent
+// a heartbeat yet are assumed to support it.
+func (c *workerCapabilities) check(workers []libModel.WorkerID, capability libModel.Capability) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, workerID := range workers {
+		caps, ok := c.caps[workerID]
+		if ok && !caps.Has(capability) {
+			return derror.ErrCapabilityNotSupported.GenWithStackByArgs(capability, "worker "+workerID)
+		}
+	}
+	return nil
+}
+
+func equalCapabilities(a, b libModel.CapabilitySet) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// WorkerCapabilities implements BaseMaster.WorkerCapabilities
+func (m *DefaultBaseMaster) WorkerCapabilities(workerID libModel.WorkerID) (libModel.CapabilitySet, bool) {
+	return m.workerCaps.get(workerID)
+}
+
+// CommonCapabilities implements BaseWorker.CommonCapabilities
+func (w *DefaultBaseWorker) CommonCapabilities() (libModel.CapabilitySet, bool) {
+	if w.masterClient == nil {
+		return nil, false
+	}
+	return w.masterClient.Capabilities()
+}
+
+// checkMasterCapability returns an error if the master is known not to
+// support the capability. The capability is assumed to be supported before
+// the first heartbeat pong is received.
+func (w *DefaultBaseWorker) checkMasterCapability(capability libModel.Capability) error {
+	caps, negotiated := w.CommonCapabilities()
+	if negotiated && !caps.Has(capability) {
+		return derror.ErrCapabilityNotSupported.GenWithStackByArgs(capability, "master "+w.masterID)
+	}
+	return nil
+}
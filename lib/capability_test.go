@@ -0,0 +1,72 @@
+package lib
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"github.com/stretchr/testify/require"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+func TestCapabilitySet(t *testing.T) {
+	t.Parallel()
+
+	set := libModel.NewCapabilitySet(libModel.CapabilityWorkerMessage,
+		libModel.CapabilityBarrier, libModel.CapabilityBarrier)
+	require.Equal(t, libModel.CapabilitySet{
+		libModel.CapabilityBarrier, libModel.CapabilityWorkerMessage,
+	}, set)
+	require.True(t, set.Has(libModel.CapabilityBarrier))
+
+	common := set.Intersect(libModel.NewCapabilitySet(libModel.CapabilityBarrier, "unknown"))
+	require.Equal(t, libModel.CapabilitySet{libModel.CapabilityBarrier}, common)
+	require.NotNil(t, set.Intersect(nil))
+	require.Empty(t, set.Intersect(nil))
+}
+
+func TestWorkerCapabilities(t *testing.T) {
+	t.Parallel()
+
+	caps := newWorkerCapabilities()
+	common, changed := caps.negotiate("worker-1", libModel.FrameworkCapabilities())
+	require.True(t, changed)
+	require.Equal(t, libModel.FrameworkCapabilities(), common)
+	_, changed = caps.negotiate("worker-1", libModel.FrameworkCapabilities())
+	require.False(t, changed)
+
+	// a worker of an old version doesn't announce capabilities
+	common, changed = caps.negotiate("worker-2", nil)
+	require.True(t, changed)
+	require.Empty(t, common)
+
+	require.NoError(t, caps.check([]libModel.WorkerID{"worker-1", "worker-3"}, libModel.CapabilityBarrier))
+	err := caps.check([]libModel.WorkerID{"worker-1", "worker-2"}, libModel.CapabilityBarrier)
+	require.True(t, derror.ErrCapabilityNotSupported.Equal(err))
+
+	caps.remove("worker-2")
+	_, ok := caps.get("worker-2")
+	require.False(t, ok)
+}
+
+func TestMasterClientCapabilities(t *testing.T) {
+	t.Parallel()
+
+	cli := newMasterClient("master-1", "worker-1", log.L(), nil, nil, 0, nil)
+	_, negotiated := cli.Capabilities()
+	require.False(t, negotiated)
+
+	cli.HandleHeartbeat("node-1", &libModel.HeartbeatPongMessage{
+		Capabilities: libModel.NewCapabilitySet(libModel.CapabilityBarrier),
+	})
+	caps, negotiated := cli.Capabilities()
+	require.True(t, negotiated)
+	require.Equal(t, libModel.CapabilitySet{libModel.CapabilityBarrier}, caps)
+
+	// the master fails over to an old version
+	cli.HandleHeartbeat("node-2", &libModel.HeartbeatPongMessage{Epoch: 1})
+	caps, negotiated = cli.Capabilities()
+	require.True(t, negotiated)
+	require.Empty(t, caps)
+}
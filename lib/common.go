@@ -5,6 +5,7 @@ import (
 
 	"github.com/hanfei1991/microcosm/lib/master"
 	"github.com/hanfei1991/microcosm/lib/model"
+	resourcemeta "github.com/hanfei1991/microcosm/pkg/externalresource/resourcemeta/model"
 )
 
 type (
@@ -14,6 +15,19 @@ type (
 	WorkerConfig = interface{}
 )
 
+// WorkerEnvConfig can be implemented by a WorkerConfig to pass environment
+// variables to the worker, see BaseWorker.Getenv.
+type WorkerEnvConfig interface {
+	WorkerEnv() map[string]string
+}
+
+// PreMountConfig can be implemented by a WorkerConfig to open the resources
+// persisted by other workers of the job before the worker is initialized,
+// the worker fails to start if any of them can't be opened.
+type PreMountConfig interface {
+	PreMountResources() []resourcemeta.ResourceID
+}
+
 // Defines all task type
 const (
 	JobManager = model.WorkerType(iota + 1)
@@ -31,6 +45,8 @@ const (
 	WorkerDMDump
 	WorkerDMLoad
 	WorkerDMSync
+	// ConnectorTask moves data from a source connector to a sink connector
+	ConnectorTask
 )
 
 // MasterFailoverReasonCode is used as reason code
@@ -51,5 +67,15 @@ type MasterFailoverReason struct {
 // WorkerHandle alias to master.WorkerHandle
 type WorkerHandle = master.WorkerHandle
 
+// RegisterStatusCodec alias to master.RegisterStatusCodec
+func RegisterStatusCodec[T any](tp WorkerType, codec master.StatusCodec[T]) (ok bool) {
+	return master.RegisterStatusCodec(tp, codec)
+}
+
+// GetTypedStatus alias to master.GetTypedStatus
+func GetTypedStatus[T any](handle WorkerHandle) (T, error) {
+	return master.GetTypedStatus[T](handle)
+}
+
 // nolint:revive
 var StopAfterTick = errors.New("stop after tick")
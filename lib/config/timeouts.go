@@ -9,14 +9,19 @@ type TimeoutConfig struct {
 	WorkerHeartbeatInterval          time.Duration
 	WorkerReportStatusInterval       time.Duration
 	MasterHeartbeatCheckLoopInterval time.Duration
+	// WorkerUnresponsiveHeartbeatMisses is the number of consecutive missed
+	// heartbeats after which a worker is reported as unresponsive, before it
+	// times out. 0 disables the report.
+	WorkerUnresponsiveHeartbeatMisses int
 }
 
 var defaultTimeoutConfig = TimeoutConfig{
-	WorkerTimeoutDuration:            time.Second * 15,
-	WorkerTimeoutGracefulDuration:    time.Second * 5,
-	WorkerHeartbeatInterval:          time.Second * 3,
-	WorkerReportStatusInterval:       time.Second * 3,
-	MasterHeartbeatCheckLoopInterval: time.Second * 1,
+	WorkerTimeoutDuration:             time.Second * 15,
+	WorkerTimeoutGracefulDuration:     time.Second * 5,
+	WorkerHeartbeatInterval:           time.Second * 3,
+	WorkerReportStatusInterval:        time.Second * 3,
+	MasterHeartbeatCheckLoopInterval:  time.Second * 1,
+	WorkerUnresponsiveHeartbeatMisses: 2,
 }.Adjust()
 
 // Adjust validates the TimeoutConfig and adjusts it
@@ -0,0 +1,191 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/pkg/clock"
+	"github.com/hanfei1991/microcosm/pkg/logutil"
+)
+
+const (
+	defaultDependencyCheckInterval = 30 * time.Second
+	dependencyCheckTimeout         = 10 * time.Second
+)
+
+// DependencyChecker checks whether a downstream dependency of a job,
+// such as a database or a storage bucket, is available.
+type DependencyChecker interface {
+	// Name identifies the dependency, it must be unique in a master.
+	Name() string
+	// Check returns a non-nil error if the dependency is unavailable.
+	Check(ctx context.Context) error
+}
+
+// DependencyHealth is the result of the latest check of a dependency.
+type DependencyHealth struct {
+	Name          string    `json:"name"`
+	Healthy       bool      `json:"healthy"`
+	Error         string    `json:"error,omitempty"`
+	LastCheckTime time.Time `json:"last-check-time"`
+}
+
+// WithDependencyCheckInterval sets the interval of periodical dependency checks.
+func WithDependencyCheckInterval(interval time.Duration) BaseMasterOption {
+	return func(m *DefaultBaseMaster) {
+		m.dependencyMonitor.interval = interval
+	}
+}
+
+// dependencyMonitor runs the registered DependencyCheckers of a master.
+type dependencyMonitor struct {
+	masterID libModel.MasterID
+	interval time.Duration
+	clock    clock.Clock
+
+	mu        sync.Mutex
+	checkers  []DependencyChecker
+	health    map[string]*DependencyHealth
+	lastCheck time.Time
+	// changed is set when the health of any dependency has changed
+	// since the last call of takeChanged.
+	changed bool
+
+	checking atomic.Bool
+}
+
+func newDependencyMonitor(masterID libModel.MasterID, clock clock.Clock) *dependencyMonitor {
+	return &dependencyMonitor{
+		masterID: masterID,
+		interval: defaultDependencyCheckInterval,
+		clock:    clock,
+		health:   make(map[string]*DependencyHealth),
+	}
+}
+
+func (d *dependencyMonitor) register(checker DependencyChecker) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.checkers = append(d.checkers, checker)
+}
+
+// checkAll runs all checkers and updates the health of dependencies.
+func (d *dependencyMonitor) checkAll(ctx context.Context) {
+	d.mu.Lock()
+	checkers := make([]DependencyChecker, len(d.checkers))
+	copy(checkers, d.checkers)
+	d.lastCheck = d.clock.Now()
+	d.mu.Unlock()
+
+	for _, checker := range checkers {
+		checkCtx, cancel := context.WithTimeout(ctx, dependencyCheckTimeout)
+		err := checker.Check(checkCtx)
+		cancel()
+
+		d.update(checker.Name(), err)
+	}
+}
+
+func (d *dependencyMonitor) update(name string, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	newHealth := &DependencyHealth{
+		Name:          name,
+		Healthy:       err == nil,
+		LastCheckTime: d.clock.Now(),
+	}
+	if err != nil {
+		newHealth.Error = err.Error()
+	}
+
+	old, exists := d.health[name]
+	if !exists || old.Healthy != newHealth.Healthy {
+		d.changed = true
+		if newHealth.Healthy {
+			logutil.WithJobID(log.L(), d.masterID).Info("dependency is healthy",
+				zap.String("dependency", name))
+		} else {
+			logutil.WithJobID(log.L(), d.masterID).Warn("dependency is unhealthy",
+				zap.String("dependency", name),
+				zap.Error(err))
+		}
+	}
+	d.health[name] = newHealth
+}
+
+// maybeCheckInBackground starts checking all dependencies in a new goroutine
+// if the check interval has elapsed and no check is running.
+func (d *dependencyMonitor) maybeCheckInBackground(ctx context.Context, wg *sync.WaitGroup) {
+	d.mu.Lock()
+	due := len(d.checkers) > 0 && d.clock.Since(d.lastCheck) >= d.interval
+	d.mu.Unlock()
+	if !due || !d.checking.CAS(false, true) {
+		return
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer d.checking.Store(false)
+		d.checkAll(ctx)
+	}()
+}
+
+// healthy returns true if no dependency is known to be unhealthy.
+func (d *dependencyMonitor) healthy() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, h := range d.health {
+		if !h.Healthy {
+			return false
+		}
+	}
+	return true
+}
+
+// unhealthyMessage describes the unhealthy dependencies, it returns an
+// empty string if all dependencies are healthy.
+func (d *dependencyMonitor) unhealthyMessage() string {
+	var msgs []string
+	for _, h := range d.list() {
+		if !h.Healthy {
+			msgs = append(msgs, fmt.Sprintf("dependency %s is unhealthy: %s", h.Name, h.Error))
+		}
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (d *dependencyMonitor) takeChanged() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	changed := d.changed
+	d.changed = false
+	return changed
+}
+
+func (d *dependencyMonitor) list() []DependencyHealth {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	ret := make([]DependencyHealth, 0, len(d.health))
+	for _, h := range d.health {
+		ret = append(ret, *h)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name < ret[j].Name
+	})
+	return ret
+}
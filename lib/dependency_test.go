@@ -0,0 +1,71 @@
+package lib
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+
+	"github.com/hanfei1991/microcosm/pkg/clock"
+)
+
+type fakeDependencyChecker struct {
+	name   string
+	err    atomic.Error
+	checks atomic.Int32
+}
+
+func (c *fakeDependencyChecker) Name() string {
+	return c.name
+}
+
+func (c *fakeDependencyChecker) Check(ctx context.Context) error {
+	c.checks.Add(1)
+	return c.err.Load()
+}
+
+func TestDependencyMonitor(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.NewMock()
+	monitor := newDependencyMonitor("master-1", clk)
+	tidb := &fakeDependencyChecker{name: "tidb"}
+	s3 := &fakeDependencyChecker{name: "s3"}
+	monitor.register(tidb)
+	monitor.register(s3)
+
+	monitor.checkAll(context.Background())
+	require.True(t, monitor.healthy())
+	require.Empty(t, monitor.unhealthyMessage())
+	require.True(t, monitor.takeChanged())
+	require.False(t, monitor.takeChanged())
+	health := monitor.list()
+	require.Len(t, health, 2)
+	require.Equal(t, "s3", health[0].Name)
+
+	s3.err.Store(errors.New("bucket not writable"))
+	var wg sync.WaitGroup
+	// the check interval has not elapsed
+	monitor.maybeCheckInBackground(context.Background(), &wg)
+	wg.Wait()
+	require.Equal(t, int32(1), s3.checks.Load())
+
+	clk.Add(defaultDependencyCheckInterval)
+	monitor.maybeCheckInBackground(context.Background(), &wg)
+	wg.Wait()
+	require.Equal(t, int32(2), s3.checks.Load())
+	require.False(t, monitor.healthy())
+	require.True(t, monitor.takeChanged())
+	require.Equal(t, "dependency s3 is unhealthy: bucket not writable", monitor.unhealthyMessage())
+
+	s3.err.Store(nil)
+	clk.Add(time.Minute)
+	monitor.maybeCheckInBackground(context.Background(), &wg)
+	wg.Wait()
+	require.True(t, monitor.healthy())
+	require.True(t, monitor.takeChanged())
+}
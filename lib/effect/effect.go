@@ -0,0 +1,157 @@
+// Package effect provides a helper to run external side effects of masters
+// and workers at most once per job, even if ticks are retried or the master
+// fails over.
+package effect
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/pkg/adapter"
+	"github.com/hanfei1991/microcosm/pkg/clock"
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/logutil"
+	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
+)
+
+// State is the state of an idempotency record
+type State string
+
+// Defines all states of idempotency records
+const (
+	// StatePending is recorded before the effect is run
+	StatePending = State("pending")
+	// StateDone is recorded after the effect has succeeded
+	StateDone = State("done")
+)
+
+// Record is the idempotency record of a side effect, which is persisted
+// under (jobID, effectID, epoch).
+type Record struct {
+	EffectID   string         `json:"effect-id"`
+	Epoch      libModel.Epoch `json:"epoch"`
+	State      State          `json:"state"`
+	UpdateTime time.Time      `json:"update-time"`
+}
+
+// Effect is an external side effect. resumed is true if a previous attempt
+// has started but not been recorded as done, for example the master failed
+// over in the middle. In that case the effect may have been applied, and the
+// function should check the external state before applying it again.
+type Effect func(ctx context.Context, resumed bool) error
+
+// Runner runs side effects of a job with idempotency records.
+type Runner struct {
+	kv    metaclient.KV
+	jobID libModel.MasterID
+	epoch libModel.Epoch
+	clock clock.Clock
+}
+
+// NewRunner creates a new Runner. The epoch should be the current epoch of
+// the job master, so that a stale master can't run effects recorded by a
+// newer one.
+func NewRunner(kv metaclient.KV, jobID libModel.MasterID, epoch libModel.Epoch) *Runner {
+	return &Runner{
+		kv:    kv,
+		jobID: jobID,
+		epoch: epoch,
+		clock: clock.New(),
+	}
+}
+
+// Do runs the effect if it hasn't been done by this job, and records it as
+// done after it succeeds. It returns whether the effect is run in this call.
+// If the effect returns an error, it is not recorded as done and will be
+// run again by the next call of Do.
+func (r *Runner) Do(ctx context.Context, effectID string, effect Effect) (executed bool, err error) {
+	records, err := r.load(ctx, effectID)
+	if err != nil {
+		return false, err
+	}
+
+	resumed := false
+	for _, record := range records {
+		if record.State == StateDone {
+			return false, nil
+		}
+		if record.Epoch > r.epoch {
+			return false, derror.ErrEffectStaleEpoch.GenWithStackByArgs(effectID, record.Epoch, r.epoch)
+		}
+		resumed = true
+	}
+
+	if err := r.store(ctx, effectID, StatePending); err != nil {
+		return false, err
+	}
+	if resumed {
+		logutil.WithEpoch(logutil.WithJobID(log.L(), r.jobID), r.epoch).Info(
+			"resume side effect that may have been started",
+			zap.String("effect-id", effectID))
+	}
+	if err := effect(ctx, resumed); err != nil {
+		return false, errors.Trace(err)
+	}
+	if err := r.store(ctx, effectID, StateDone); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
+// IsDone returns whether the effect has been recorded as done.
+func (r *Runner) IsDone(ctx context.Context, effectID string) (bool, error) {
+	records, err := r.load(ctx, effectID)
+	if err != nil {
+		return false, err
+	}
+	for _, record := range records {
+		if record.State == StateDone {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Forget removes all records of the effect, so it can be run again.
+func (r *Runner) Forget(ctx context.Context, effectID string) error {
+	_, err := r.kv.Delete(ctx, adapter.EffectKeyAdapter.Curry(r.jobID, effectID).Path(), metaclient.WithPrefix())
+	return errors.Trace(err)
+}
+
+func (r *Runner) load(ctx context.Context, effectID string) ([]*Record, error) {
+	resp, err := r.kv.Get(ctx, adapter.EffectKeyAdapter.Curry(r.jobID, effectID).Path(), metaclient.WithPrefix())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	records := make([]*Record, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		record := &Record{}
+		if err := json.Unmarshal(kv.Value, record); err != nil {
+			return nil, errors.Trace(err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func (r *Runner) store(ctx context.Context, effectID string, state State) error {
+	value, err := json.Marshal(&Record{
+		EffectID:   effectID,
+		Epoch:      r.epoch,
+		State:      state,
+		UpdateTime: r.clock.Now(),
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	key := adapter.EffectKeyAdapter.Encode(r.jobID, effectID, strconv.FormatInt(r.epoch, 10))
+	_, metaErr := r.kv.Put(ctx, key, string(value))
+	return errors.Trace(metaErr)
+}
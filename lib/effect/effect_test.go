@@ -0,0 +1,58 @@
+package effect
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/errors"
+	"github.com/stretchr/testify/require"
+
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/meta/kvclient/mock"
+)
+
+func TestRunnerDo(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	kv := mock.NewMetaMock()
+	runner := NewRunner(kv, "job-1", 1)
+
+	// the effect fails, and is retried in the next tick
+	executed, err := runner.Do(ctx, "create-table", func(ctx context.Context, resumed bool) error {
+		require.False(t, resumed)
+		return errors.New("fake error")
+	})
+	require.Error(t, err)
+	require.False(t, executed)
+
+	// the master fails over
+	runner = NewRunner(kv, "job-1", 2)
+	executed, err = runner.Do(ctx, "create-table", func(ctx context.Context, resumed bool) error {
+		require.True(t, resumed)
+		return nil
+	})
+	require.NoError(t, err)
+	require.True(t, executed)
+	done, err := runner.IsDone(ctx, "create-table")
+	require.NoError(t, err)
+	require.True(t, done)
+
+	executed, err = runner.Do(ctx, "create-table", func(ctx context.Context, resumed bool) error {
+		require.FailNow(t, "unexpected call")
+		return nil
+	})
+	require.NoError(t, err)
+	require.False(t, executed)
+
+	// a stale master can't run an effect started by a newer master
+	_, err = NewRunner(kv, "job-1", 3).Do(ctx, "notify", func(ctx context.Context, resumed bool) error {
+		return errors.New("fake error")
+	})
+	require.Error(t, err)
+	_, err = runner.Do(ctx, "notify", func(ctx context.Context, resumed bool) error {
+		require.FailNow(t, "unexpected call")
+		return nil
+	})
+	require.True(t, derror.ErrEffectStaleEpoch.Equal(err))
+}
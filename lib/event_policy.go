@@ -0,0 +1,242 @@
+package lib
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/pkg/clock"
+	"github.com/hanfei1991/microcosm/pkg/logutil"
+)
+
+// EventErrorPolicyType defines how the BaseMaster handles an error returned
+// from a worker event callback of MasterImpl.
+type EventErrorPolicyType int
+
+// Defines all event error policy types
+const (
+	// EventErrorFailFast returns the error to Poll, which fails the master.
+	// It is the default policy.
+	EventErrorFailFast = EventErrorPolicyType(iota)
+	// EventErrorRetry invokes the callback again, at most MaxRetry times
+	// with an exponential backoff, and fails the master if the error
+	// persists. The retries are made by later Polls of the master, and the
+	// later events of the worker wait for them.
+	EventErrorRetry
+	// EventErrorSkipAndRecord drops the event and records it in the event journal.
+	EventErrorSkipAndRecord
+)
+
+const (
+	defaultEventJournalCapacity = 128
+	defaultEventRetryBackoff    = 100 * time.Millisecond
+	maxEventRetryBackoff        = 5 * time.Second
+)
+
+// EventErrorPolicy is the policy applied to errors returned from
+// OnWorkerOnline, OnWorkerOffline, OnWorkerStatusUpdated and OnWorkerDispatched.
+type EventErrorPolicy struct {
+	Type EventErrorPolicyType
+	// MaxRetry is only used by EventErrorRetry
+	MaxRetry int
+	// Backoff is the wait before the first retry, which doubles after each
+	// retry up to 5s. It is 100ms if not set, and only used by
+	// EventErrorRetry.
+	Backoff time.Duration
+}
+
+func (p EventErrorPolicy) backoff(attempts int) time.Duration {
+	backoff := p.Backoff
+	if backoff <= 0 {
+		backoff = defaultEventRetryBackoff
+	}
+	for i := 1; i < attempts && backoff < maxEventRetryBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxEventRetryBackoff {
+		backoff = maxEventRetryBackoff
+	}
+	return backoff
+}
+
+// BaseMasterOption is used to customize a BaseMaster in NewBaseMaster
+type BaseMasterOption func(m *DefaultBaseMaster)
+
+// WithEventErrorPolicy sets the policy for errors returned from worker event callbacks.
+func WithEventErrorPolicy(policy EventErrorPolicy) BaseMasterOption {
+	return func(m *DefaultBaseMaster) {
+		m.eventErrorPolicy = policy
+	}
+}
+
+// EventJournalEntry records a worker event whose callback has returned an error.
+type EventJournalEntry struct {
+	Time     time.Time         `json:"time"`
+	Event    string            `json:"event"`
+	WorkerID libModel.WorkerID `json:"worker-id"`
+	// Attempts is the number of times the callback has been invoked
+	Attempts int    `json:"attempts"`
+	Skipped  bool   `json:"skipped"`
+	Error    string `json:"error"`
+}
+
+// eventJournal keeps the latest entries of retried or skipped events.
+type eventJournal struct {
+	mu       sync.Mutex
+	entries  []EventJournalEntry
+	capacity int
+}
+
+func newEventJournal(capacity int) *eventJournal {
+	return &eventJournal{capacity: capacity}
+}
+
+func (j *eventJournal) record(entry EventJournalEntry) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if len(j.entries) >= j.capacity {
+		j.entries = j.entries[1:]
+	}
+	j.entries = append(j.entries, entry)
+}
+
+func (j *eventJournal) list() []EventJournalEntry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	ret := make([]EventJournalEntry, len(j.entries))
+	copy(ret, j.entries)
+	return ret
+}
+
+// eventCallbackHandler applies an EventErrorPolicy to event callbacks. It is
+// only used in Poll of the master, so it is not protected by a lock.
+type eventCallbackHandler struct {
+	masterID libModel.MasterID
+	policy   EventErrorPolicy
+	journal  *eventJournal
+	clock    clock.Clock
+
+	// pending are the events of each worker waiting for a retry. The first
+	// one has failed and is retried once its backoff elapses, and the others
+	// are queued behind it, so that the events of a worker are handled in
+	// order while the events of other workers are not blocked.
+	pending map[libModel.WorkerID][]*pendingEvent
+}
+
+type pendingEvent struct {
+	event    string
+	callback func() error
+	attempts int
+	retryAt  time.Time
+}
+
+// handle invokes the callback of an event, or queues it if an earlier event
+// of the worker is waiting for a retry.
+func (h *eventCallbackHandler) handle(
+	event string,
+	workerID libModel.WorkerID,
+	callback func() error,
+) error {
+	e := &pendingEvent{event: event, callback: callback}
+	if len(h.pending[workerID]) > 0 {
+		h.pending[workerID] = append(h.pending[workerID], e)
+		return nil
+	}
+	retrying, err := h.invoke(workerID, e)
+	if retrying {
+		if h.pending == nil {
+			h.pending = make(map[libModel.WorkerID][]*pendingEvent)
+		}
+		h.pending[workerID] = []*pendingEvent{e}
+	}
+	return err
+}
+
+// retryPending retries the events whose backoff has elapsed, and handles the
+// events queued behind them. It is called in every Poll of the master.
+func (h *eventCallbackHandler) retryPending() error {
+	now := h.clock.Now()
+	for workerID, events := range h.pending {
+		if now.Before(events[0].retryAt) {
+			continue
+		}
+		for len(events) > 0 {
+			retrying, err := h.invoke(workerID, events[0])
+			if err != nil {
+				return err
+			}
+			if retrying {
+				break
+			}
+			events = events[1:]
+		}
+		if len(events) == 0 {
+			delete(h.pending, workerID)
+		} else {
+			h.pending[workerID] = events
+		}
+	}
+	return nil
+}
+
+// invoke invokes the callback of an event once, and returns whether the
+// event is scheduled for a retry.
+func (h *eventCallbackHandler) invoke(workerID libModel.WorkerID, e *pendingEvent) (bool, error) {
+	e.attempts++
+	err := e.callback()
+	if err == nil {
+		if e.attempts > 1 {
+			h.journal.record(EventJournalEntry{
+				Time:     h.clock.Now(),
+				Event:    e.event,
+				WorkerID: workerID,
+				Attempts: e.attempts,
+			})
+		}
+		return false, nil
+	}
+
+	switch h.policy.Type {
+	case EventErrorRetry:
+		if e.attempts <= h.policy.MaxRetry {
+			backoff := h.policy.backoff(e.attempts)
+			e.retryAt = h.clock.Now().Add(backoff)
+			logutil.WithJobID(log.L(), h.masterID).Warn("worker event callback failed, retry later",
+				zap.String("event", e.event),
+				zap.String("worker-id", workerID),
+				zap.Int("attempts", e.attempts),
+				zap.Duration("backoff", backoff),
+				zap.Error(err))
+			return true, nil
+		}
+		h.journal.record(EventJournalEntry{
+			Time:     h.clock.Now(),
+			Event:    e.event,
+			WorkerID: workerID,
+			Attempts: e.attempts,
+			Error:    err.Error(),
+		})
+		return false, err
+	case EventErrorSkipAndRecord:
+		logutil.WithJobID(log.L(), h.masterID).Warn("worker event callback failed, skip the event",
+			zap.String("event", e.event),
+			zap.String("worker-id", workerID),
+			zap.Error(err))
+		h.journal.record(EventJournalEntry{
+			Time:     h.clock.Now(),
+			Event:    e.event,
+			WorkerID: workerID,
+			Attempts: e.attempts,
+			Skipped:  true,
+			Error:    err.Error(),
+		})
+		return false, nil
+	default:
+		return false, err
+	}
+}
@@ -0,0 +1,146 @@
+package lib
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hanfei1991/microcosm/pkg/clock"
+)
+
+func newEventCallbackHandlerForTest(policy EventErrorPolicy) (*eventCallbackHandler, *clock.Mock) {
+	clk := clock.NewMock()
+	return &eventCallbackHandler{
+		masterID: "master-1",
+		policy:   policy,
+		journal:  newEventJournal(2),
+		clock:    clk,
+	}, clk
+}
+
+func failingCallback(failures int) (func() error, *int) {
+	calls := 0
+	return func() error {
+		calls++
+		if calls <= failures {
+			return errors.New("fake error")
+		}
+		return nil
+	}, &calls
+}
+
+func TestEventErrorPolicyFailFast(t *testing.T) {
+	t.Parallel()
+
+	h, _ := newEventCallbackHandlerForTest(EventErrorPolicy{})
+	callback, calls := failingCallback(1)
+	err := h.handle("worker-online", "worker-1", callback)
+	require.Error(t, err)
+	require.Equal(t, 1, *calls)
+	require.Empty(t, h.journal.list())
+}
+
+func TestEventErrorPolicyRetry(t *testing.T) {
+	t.Parallel()
+
+	h, clk := newEventCallbackHandlerForTest(EventErrorPolicy{
+		Type: EventErrorRetry, MaxRetry: 2, Backoff: time.Second,
+	})
+	callback, calls := failingCallback(2)
+	// the failed event is retried by later polls rather than blocking
+	err := h.handle("worker-online", "worker-1", callback)
+	require.NoError(t, err)
+	require.Equal(t, 1, *calls)
+	require.NoError(t, h.retryPending())
+	require.Equal(t, 1, *calls)
+	clk.Add(time.Second)
+	require.NoError(t, h.retryPending())
+	require.Equal(t, 2, *calls)
+	// the backoff doubles
+	clk.Add(time.Second)
+	require.NoError(t, h.retryPending())
+	require.Equal(t, 2, *calls)
+	clk.Add(time.Second)
+	require.NoError(t, h.retryPending())
+	require.Equal(t, 3, *calls)
+	require.Empty(t, h.pending)
+	entries := h.journal.list()
+	require.Len(t, entries, 1)
+	require.Equal(t, 3, entries[0].Attempts)
+	require.False(t, entries[0].Skipped)
+
+	callback, calls = failingCallback(3)
+	require.NoError(t, h.handle("worker-offline", "worker-2", callback))
+	clk.Add(time.Second)
+	require.NoError(t, h.retryPending())
+	clk.Add(2 * time.Second)
+	err = h.retryPending()
+	require.Error(t, err)
+	require.Equal(t, 3, *calls)
+	entries = h.journal.list()
+	require.Len(t, entries, 2)
+	require.Equal(t, "worker-2", entries[1].WorkerID)
+	require.Equal(t, "fake error", entries[1].Error)
+}
+
+func TestEventErrorPolicyRetryOrder(t *testing.T) {
+	t.Parallel()
+
+	h, clk := newEventCallbackHandlerForTest(EventErrorPolicy{
+		Type: EventErrorRetry, MaxRetry: 1, Backoff: time.Second,
+	})
+	var handled []string
+	callback := func(event string, failures int) func() error {
+		calls := 0
+		return func() error {
+			calls++
+			if calls <= failures {
+				return errors.New("fake error")
+			}
+			handled = append(handled, event)
+			return nil
+		}
+	}
+
+	require.NoError(t, h.handle("worker-online", "worker-1", callback("online-1", 1)))
+	// the later events of the worker wait for the retry, while the events
+	// of other workers are handled at once
+	require.NoError(t, h.handle("worker-offline", "worker-1", callback("offline-1", 0)))
+	require.NoError(t, h.handle("worker-online", "worker-2", callback("online-2", 0)))
+	require.Equal(t, []string{"online-2"}, handled)
+
+	clk.Add(time.Second)
+	require.NoError(t, h.retryPending())
+	require.Equal(t, []string{"online-2", "online-1", "offline-1"}, handled)
+	require.Empty(t, h.pending)
+}
+
+func TestEventErrorPolicyRetryBackoff(t *testing.T) {
+	t.Parallel()
+
+	policy := EventErrorPolicy{Type: EventErrorRetry, MaxRetry: 10}
+	require.Equal(t, defaultEventRetryBackoff, policy.backoff(1))
+	require.Equal(t, 4*defaultEventRetryBackoff, policy.backoff(3))
+	require.Equal(t, maxEventRetryBackoff, policy.backoff(10))
+}
+
+func TestEventErrorPolicySkipAndRecord(t *testing.T) {
+	t.Parallel()
+
+	h, _ := newEventCallbackHandlerForTest(EventErrorPolicy{Type: EventErrorSkipAndRecord})
+	for _, workerID := range []string{"worker-1", "worker-2", "worker-3"} {
+		callback, calls := failingCallback(1)
+		err := h.handle("worker-status-updated", workerID, callback)
+		require.NoError(t, err)
+		require.Equal(t, 1, *calls)
+	}
+
+	// the journal only keeps the latest entries
+	entries := h.journal.list()
+	require.Len(t, entries, 2)
+	require.Equal(t, "worker-2", entries[0].WorkerID)
+	require.Equal(t, "worker-3", entries[1].WorkerID)
+	require.True(t, entries[1].Skipped)
+}
@@ -0,0 +1,73 @@
+package lib
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+	"go.uber.org/zap"
+
+	"github.com/hanfei1991/microcosm/lib/exchange"
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/p2p"
+)
+
+// registerPeerLookupHandler handles the replies of the master to the lookups
+// of the executors of peer workers.
+func (w *DefaultBaseWorker) registerPeerLookupHandler(ctx context.Context) error {
+	topic := exchange.PeerLookupResponseTopic(w.masterID, w.id)
+	ok, err := w.messageHandlerManager.RegisterHandler(
+		ctx,
+		topic,
+		&exchange.PeerLookupResponse{},
+		func(sender p2p.NodeID, value p2p.MessageValue) error {
+			msg, ok := value.(*exchange.PeerLookupResponse)
+			if !ok {
+				return derror.ErrInvalidMasterMessage.GenWithStackByArgs(value)
+			}
+			w.peerResolver.OnResponse(msg)
+			return nil
+		})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !ok {
+		w.Logger().Panic("duplicate handler", zap.String("topic", topic))
+	}
+	return nil
+}
+
+// OpenChannelSender implements BaseWorker.OpenChannelSender
+func (w *DefaultBaseWorker) OpenChannelSender(
+	ctx context.Context,
+	peerID libModel.WorkerID,
+	channelID string,
+	cfg exchange.Config,
+) (*exchange.Sender, error) {
+	ctx = w.errCenter.WithCancelOnFirstError(ctx)
+	if err := w.checkMasterCapability(libModel.CapabilityPeerDiscovery); err != nil {
+		return nil, err
+	}
+	executor, err := w.peerResolver.Lookup(ctx, peerID)
+	if err != nil {
+		return nil, err
+	}
+	w.Logger().Info("open data channel to peer",
+		zap.String("channel-id", channelID),
+		zap.String("peer-id", peerID),
+		zap.String("executor", executor))
+	return exchange.NewSender(ctx, w.messageSender, w.messageHandlerManager,
+		executor, w.masterID, channelID, cfg)
+}
+
+// OpenChannelReceiver implements BaseWorker.OpenChannelReceiver
+func (w *DefaultBaseWorker) OpenChannelReceiver(
+	ctx context.Context,
+	channelID string,
+	cfg exchange.Config,
+) (*exchange.Receiver, error) {
+	ctx = w.errCenter.WithCancelOnFirstError(ctx)
+	w.Logger().Info("open data channel from peer", zap.String("channel-id", channelID))
+	return exchange.NewReceiver(ctx, w.messageSender, w.messageHandlerManager,
+		w.masterID, channelID, cfg)
+}
@@ -0,0 +1,139 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/lib/statusutil"
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/p2p"
+)
+
+// peerLookupRetryInterval is the interval to resend a lookup, in case that
+// the request or the response is lost, or the master has failed over.
+const peerLookupRetryInterval = time.Second
+
+// PeerLookupRequest is sent by a worker to look up the executor of a peer
+// worker from the master.
+type PeerLookupRequest struct {
+	FromWorkerID libModel.WorkerID `json:"from-worker-id"`
+	RequestID    uint64            `json:"request-id"`
+	PeerID       libModel.WorkerID `json:"peer-id"`
+}
+
+// PeerLookupResponse is replied by the master, Found is false if the peer is
+// not online.
+type PeerLookupResponse struct {
+	RequestID uint64            `json:"request-id"`
+	PeerID    libModel.WorkerID `json:"peer-id"`
+	Found     bool              `json:"found"`
+	Executor  p2p.NodeID        `json:"executor"`
+}
+
+// PeerLookupRequestTopic is the topic of peer lookups of a given master.
+func PeerLookupRequestTopic(masterID libModel.MasterID) p2p.Topic {
+	return fmt.Sprintf("peer-lookup-req-%s", masterID)
+}
+
+// PeerLookupResponseTopic is the topic of replies to the peer lookups of a
+// worker.
+func PeerLookupResponseTopic(masterID libModel.MasterID, workerID libModel.WorkerID) p2p.Topic {
+	return fmt.Sprintf("peer-lookup-resp-%s-%s", masterID, workerID)
+}
+
+// PeerResolver is used by a worker to look up the executors of its peers
+// from the master.
+type PeerResolver struct {
+	messageSender p2p.MessageSender
+	masterInfo    statusutil.MasterInfoProvider
+	workerID      libModel.WorkerID
+
+	mu        sync.Mutex
+	nextReqID uint64
+	waiters   map[uint64]chan *PeerLookupResponse
+}
+
+// NewPeerResolver creates a new PeerResolver, OnResponse should be called
+// with the messages received on PeerLookupResponseTopic.
+func NewPeerResolver(
+	messageSender p2p.MessageSender,
+	masterInfo statusutil.MasterInfoProvider,
+	workerID libModel.WorkerID,
+) *PeerResolver {
+	return &PeerResolver{
+		messageSender: messageSender,
+		masterInfo:    masterInfo,
+		workerID:      workerID,
+		waiters:       make(map[uint64]chan *PeerLookupResponse),
+	}
+}
+
+// Lookup returns the executor that the peer worker is running on. It blocks
+// until the master replies, and returns ErrPeerNotFound if the peer is not
+// online. The result isn't cached, because the peer may be recreated on
+// another executor.
+func (r *PeerResolver) Lookup(ctx context.Context, peerID libModel.WorkerID) (p2p.NodeID, error) {
+	r.mu.Lock()
+	r.nextReqID++
+	reqID := r.nextReqID
+	ch := make(chan *PeerLookupResponse, 1)
+	r.waiters[reqID] = ch
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		delete(r.waiters, reqID)
+		r.mu.Unlock()
+	}()
+
+	ticker := time.NewTicker(peerLookupRetryInterval)
+	defer ticker.Stop()
+	for {
+		masterID := r.masterInfo.MasterID()
+		ok, err := r.messageSender.SendToNode(ctx, r.masterInfo.MasterNode(), PeerLookupRequestTopic(masterID),
+			&PeerLookupRequest{
+				FromWorkerID: r.workerID,
+				RequestID:    reqID,
+				PeerID:       peerID,
+			})
+		if err != nil || !ok {
+			log.L().Info("failed to send peer lookup to master",
+				zap.String("worker-id", r.workerID),
+				zap.String("master-id", masterID),
+				zap.String("peer-id", peerID),
+				zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", errors.Trace(ctx.Err())
+		case resp := <-ch:
+			if !resp.Found {
+				return "", derror.ErrPeerNotFound.GenWithStackByArgs(peerID)
+			}
+			return resp.Executor, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// OnResponse delivers a reply of the master to the pending lookup.
+func (r *PeerResolver) OnResponse(resp *PeerLookupResponse) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ch, ok := r.waiters[resp.RequestID]
+	if !ok {
+		return
+	}
+	select {
+	case ch <- resp:
+	default:
+	}
+}
@@ -0,0 +1,78 @@
+// Package exchange provides data channels between the workers of a job, so
+// that shuffle and exchange operators transfer data to their peer workers
+// directly instead of routing it through the master.
+//
+// A channel is opened by exactly one sending worker and one receiving worker
+// with the same channel ID. The executor of the receiving worker is looked up
+// from the master, then the data is sent in chunks by p2p messages. The flow
+// is controlled by credits, the sender stops sending when Window chunks are
+// not consumed by the receiver.
+//
+// The channels are not persisted, if either end fails, the master should
+// recreate both ends with a new channel ID.
+package exchange
+
+import (
+	"fmt"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/pkg/p2p"
+)
+
+const (
+	defaultChunkSize = 64 * 1024
+	defaultWindow    = 16
+)
+
+// Config is the config of a data channel, both ends of a channel must use
+// the same Window.
+type Config struct {
+	// ChunkSize is the max size in bytes of the chunks that a message is
+	// split into, default is 64KiB.
+	ChunkSize int `json:"chunk-size"`
+	// Window is the max number of chunks that are sent but not consumed by
+	// the receiver, default is 16.
+	Window int `json:"window"`
+}
+
+func (c Config) withDefaults() Config {
+	if c.ChunkSize <= 0 {
+		c.ChunkSize = defaultChunkSize
+	}
+	if c.Window <= 0 {
+		c.Window = defaultWindow
+	}
+	return c
+}
+
+// DataTopic is the topic of the chunks of a channel, which are sent to the
+// receiving worker.
+func DataTopic(jobID libModel.MasterID, channelID string) p2p.Topic {
+	return fmt.Sprintf("exchange-data-%s-%s", jobID, channelID)
+}
+
+// CreditTopic is the topic of the credits of a channel, which are sent to the
+// sending worker.
+func CreditTopic(jobID libModel.MasterID, channelID string) p2p.Topic {
+	return fmt.Sprintf("exchange-credit-%s-%s", jobID, channelID)
+}
+
+// ChunkMessage is a chunk of a message sent in a channel.
+type ChunkMessage struct {
+	// Seq is the sequence number of the chunk in the channel, starting from 1.
+	Seq  uint64 `json:"seq"`
+	Data []byte `json:"data"`
+	// Last is true if it's the last chunk of a message.
+	Last bool `json:"last,omitempty"`
+	// EOF is true if the sender has closed the channel, the chunk carries no
+	// data.
+	EOF bool `json:"eof,omitempty"`
+}
+
+// CreditMessage grants credits to the sender of a channel.
+type CreditMessage struct {
+	// Consumed is the sequence number of the latest chunk consumed by the
+	// receiver, the sender can send chunks till Consumed + Window. As it is
+	// cumulative, a lost or duplicated credit message is harmless.
+	Consumed uint64 `json:"consumed"`
+}
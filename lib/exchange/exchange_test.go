@@ -0,0 +1,196 @@
+package exchange
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hanfei1991/microcosm/lib/statusutil"
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/p2p"
+)
+
+const (
+	senderNode   = p2p.NodeID("executor-1")
+	receiverNode = p2p.NodeID("executor-2")
+	masterNode   = p2p.NodeID("executor-0")
+)
+
+type channelTestSuite struct {
+	senderMessages   *p2p.MockMessageSender
+	senderHandlers   *p2p.MockMessageHandlerManager
+	receiverMessages *p2p.MockMessageSender
+	receiverHandlers *p2p.MockMessageHandlerManager
+}
+
+func newChannelTestSuite() *channelTestSuite {
+	return &channelTestSuite{
+		senderMessages:   p2p.NewMockMessageSender(),
+		senderHandlers:   p2p.NewMockMessageHandlerManager(),
+		receiverMessages: p2p.NewMockMessageSender(),
+		receiverHandlers: p2p.NewMockMessageHandlerManager(),
+	}
+}
+
+// deliver delivers the pending chunks and credits of the channel, and returns
+// whether any message is delivered. The credits are delivered to the sender
+// directly, because they may arrive after the sender is closed.
+func (s *channelTestSuite) deliver(t *testing.T, sender *Sender, channelID string) bool {
+	delivered := false
+	for {
+		msg, ok := s.senderMessages.TryPop(receiverNode, DataTopic("job-1", channelID))
+		if !ok {
+			break
+		}
+		require.NoError(t, s.receiverHandlers.InvokeHandler(t, DataTopic("job-1", channelID), senderNode, msg))
+		delivered = true
+	}
+	for {
+		msg, ok := s.receiverMessages.TryPop(senderNode, CreditTopic("job-1", channelID))
+		if !ok {
+			break
+		}
+		sender.onCredit(msg.(*CreditMessage))
+		delivered = true
+	}
+	return delivered
+}
+
+func TestChannelSendRecv(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	suite := newChannelTestSuite()
+	cfg := Config{ChunkSize: 4, Window: 2}
+	receiver, err := NewReceiver(ctx, suite.receiverMessages, suite.receiverHandlers, "job-1", "channel-1", cfg)
+	require.NoError(t, err)
+	_, err = NewReceiver(ctx, suite.receiverMessages, suite.receiverHandlers, "job-1", "channel-1", cfg)
+	require.True(t, derror.ErrChannelDuplicated.Equal(err))
+	sender, err := NewSender(ctx, suite.senderMessages, suite.senderHandlers, receiverNode, "job-1", "channel-1", cfg)
+	require.NoError(t, err)
+
+	messages := []string{"hello world, this is a long message", "a", ""}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for _, msg := range messages {
+			require.NoError(t, sender.Send(ctx, []byte(msg)))
+		}
+		require.NoError(t, sender.CloseSend(ctx))
+	}()
+
+	for _, expected := range messages {
+		var data []byte
+		for {
+			suite.deliver(t, sender, "channel-1")
+			recvCtx, recvCancel := context.WithTimeout(ctx, 10*time.Millisecond)
+			data, err = receiver.Recv(recvCtx)
+			recvCancel()
+			if err == nil {
+				break
+			}
+			require.ErrorIs(t, err, context.DeadlineExceeded)
+		}
+		require.Equal(t, expected, string(data))
+	}
+	require.Eventually(t, func() bool {
+		suite.deliver(t, sender, "channel-1")
+		recvCtx, recvCancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer recvCancel()
+		_, err = receiver.Recv(recvCtx)
+		return err == io.EOF
+	}, 5*time.Second, 10*time.Millisecond)
+	wg.Wait()
+	require.NoError(t, receiver.Close(ctx))
+	suite.senderHandlers.AssertNoHandler(t, CreditTopic("job-1", "channel-1"))
+	suite.receiverHandlers.AssertNoHandler(t, DataTopic("job-1", "channel-1"))
+}
+
+func TestChannelFlowControl(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	suite := newChannelTestSuite()
+	cfg := Config{ChunkSize: 4, Window: 2}
+	receiver, err := NewReceiver(ctx, suite.receiverMessages, suite.receiverHandlers, "job-1", "channel-1", cfg)
+	require.NoError(t, err)
+	sender, err := NewSender(ctx, suite.senderMessages, suite.senderHandlers, receiverNode, "job-1", "channel-1", cfg)
+	require.NoError(t, err)
+
+	// the sender is blocked after sending Window chunks
+	sendCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	err = sender.Send(sendCtx, []byte("123456789"))
+	cancel()
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.True(t, suite.deliver(t, sender, "channel-1"))
+
+	// the receiver rejects chunks beyond the window
+	err = suite.receiverHandlers.InvokeHandler(t, DataTopic("job-1", "channel-1"), senderNode,
+		&ChunkMessage{Seq: 3, Data: []byte("9"), Last: true})
+	require.True(t, derror.ErrChannelWindowExceeded.Equal(err))
+	_, err = receiver.Recv(ctx)
+	require.True(t, derror.ErrChannelWindowExceeded.Equal(err))
+}
+
+func TestChannelChunkLost(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	suite := newChannelTestSuite()
+	receiver, err := NewReceiver(ctx, suite.receiverMessages, suite.receiverHandlers, "job-1", "channel-1", Config{})
+	require.NoError(t, err)
+
+	topic := DataTopic("job-1", "channel-1")
+	require.NoError(t, suite.receiverHandlers.InvokeHandler(t, topic, senderNode, &ChunkMessage{Seq: 1, Data: []byte("a")}))
+	// duplicated chunks are ignored
+	require.NoError(t, suite.receiverHandlers.InvokeHandler(t, topic, senderNode, &ChunkMessage{Seq: 1, Data: []byte("a")}))
+	err = suite.receiverHandlers.InvokeHandler(t, topic, senderNode, &ChunkMessage{Seq: 3, Data: []byte("c"), Last: true})
+	require.True(t, derror.ErrChannelChunkLost.Equal(err))
+	_, err = receiver.Recv(ctx)
+	require.True(t, derror.ErrChannelChunkLost.Equal(err))
+}
+
+func TestPeerResolver(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	messageSender := p2p.NewMockMessageSender()
+	masterInfo := &statusutil.MockMasterInfoProvider{}
+	masterInfo.Set("job-1", masterNode, 1)
+	resolver := NewPeerResolver(messageSender, masterInfo, "worker-1")
+
+	reply := func(found bool) {
+		var msg interface{}
+		require.Eventually(t, func() bool {
+			var ok bool
+			msg, ok = messageSender.TryPop(masterNode, PeerLookupRequestTopic("job-1"))
+			return ok
+		}, 5*time.Second, 10*time.Millisecond)
+		req := msg.(*PeerLookupRequest)
+		require.Equal(t, "worker-1", req.FromWorkerID)
+		require.Equal(t, "worker-2", req.PeerID)
+		resp := &PeerLookupResponse{RequestID: req.RequestID, PeerID: req.PeerID, Found: found}
+		if found {
+			resp.Executor = receiverNode
+		}
+		// the responses to other requests are ignored
+		resolver.OnResponse(&PeerLookupResponse{RequestID: req.RequestID + 1, Found: true})
+		resolver.OnResponse(resp)
+	}
+
+	go reply(true)
+	executor, err := resolver.Lookup(ctx, "worker-2")
+	require.NoError(t, err)
+	require.Equal(t, receiverNode, executor)
+
+	go reply(false)
+	_, err = resolver.Lookup(ctx, "worker-2")
+	require.True(t, derror.ErrPeerNotFound.Equal(err))
+}
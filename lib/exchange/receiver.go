@@ -0,0 +1,181 @@
+package exchange
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/pingcap/errors"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/p2p"
+)
+
+type receivedChunk struct {
+	sender p2p.NodeID
+	chunk  *ChunkMessage
+}
+
+// Receiver is the receiving end of a data channel, it is not thread-safe.
+type Receiver struct {
+	jobID          libModel.MasterID
+	channelID      string
+	messageSender  p2p.MessageSender
+	handlerManager p2p.MessageHandlerManager
+	cfg            Config
+
+	// chunks buffers the chunks not consumed, it never blocks the message
+	// handler because the sender sends no more than Window chunks ahead.
+	chunks chan receivedChunk
+
+	mu sync.Mutex
+	// expectedSeq is the sequence number of the next chunk to receive
+	expectedSeq uint64
+	err         error
+
+	// partial is the data of the chunks consumed by a canceled Recv, which
+	// are a part of the next message.
+	partial  []byte
+	consumed uint64
+	credited uint64
+	eof      bool
+	closed   bool
+}
+
+// NewReceiver opens the receiving end of a channel.
+func NewReceiver(
+	ctx context.Context,
+	messageSender p2p.MessageSender,
+	handlerManager p2p.MessageHandlerManager,
+	jobID libModel.MasterID,
+	channelID string,
+	cfg Config,
+) (*Receiver, error) {
+	cfg = cfg.withDefaults()
+	r := &Receiver{
+		jobID:          jobID,
+		channelID:      channelID,
+		messageSender:  messageSender,
+		handlerManager: handlerManager,
+		cfg:            cfg,
+		chunks:         make(chan receivedChunk, cfg.Window),
+		expectedSeq:    1,
+	}
+	ok, err := handlerManager.RegisterHandler(ctx, DataTopic(jobID, channelID), &ChunkMessage{},
+		func(sender p2p.NodeID, value p2p.MessageValue) error {
+			return r.onChunk(sender, value.(*ChunkMessage))
+		})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !ok {
+		return nil, derror.ErrChannelDuplicated.GenWithStackByArgs(channelID)
+	}
+	return r, nil
+}
+
+func (r *Receiver) onChunk(sender p2p.NodeID, chunk *ChunkMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return nil
+	}
+	if chunk.Seq < r.expectedSeq {
+		// duplicated by a retry of the p2p client
+		return nil
+	}
+	if chunk.Seq > r.expectedSeq {
+		r.err = derror.ErrChannelChunkLost.GenWithStackByArgs(r.channelID, r.expectedSeq, chunk.Seq)
+		return r.err
+	}
+	select {
+	case r.chunks <- receivedChunk{sender: sender, chunk: chunk}:
+	default:
+		r.err = derror.ErrChannelWindowExceeded.GenWithStackByArgs(r.channelID)
+		return r.err
+	}
+	r.expectedSeq++
+	return nil
+}
+
+func (r *Receiver) getErr() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// Recv receives the next message, it returns io.EOF after the sender has
+// closed the channel and all the messages are received.
+func (r *Receiver) Recv(ctx context.Context) ([]byte, error) {
+	if r.eof {
+		return nil, io.EOF
+	}
+	if r.closed {
+		return nil, derror.ErrChannelClosed.GenWithStackByArgs(r.channelID)
+	}
+	for {
+		var received receivedChunk
+		select {
+		case <-ctx.Done():
+			return nil, errors.Trace(ctx.Err())
+		case received = <-r.chunks:
+		default:
+			// the chunks received before a failure are consumed first
+			if err := r.getErr(); err != nil {
+				return nil, err
+			}
+			select {
+			case <-ctx.Done():
+				return nil, errors.Trace(ctx.Err())
+			case received = <-r.chunks:
+			}
+		}
+
+		chunk := received.chunk
+		r.consumed = chunk.Seq
+		if chunk.EOF {
+			r.eof = true
+			return nil, io.EOF
+		}
+		if err := r.grantCredits(ctx, received.sender); err != nil {
+			return nil, err
+		}
+		r.partial = append(r.partial, chunk.Data...)
+		if chunk.Last {
+			data := r.partial
+			r.partial = nil
+			return data, nil
+		}
+	}
+}
+
+// grantCredits sends the consumed chunks to the sender every half Window,
+// so that the sender never runs out of credits while the receiver waits.
+func (r *Receiver) grantCredits(ctx context.Context, sender p2p.NodeID) error {
+	threshold := uint64(r.cfg.Window / 2)
+	if threshold == 0 {
+		threshold = 1
+	}
+	if r.consumed-r.credited < threshold {
+		return nil
+	}
+	err := r.messageSender.SendToNodeB(ctx, sender, CreditTopic(r.jobID, r.channelID),
+		&CreditMessage{Consumed: r.consumed})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	r.credited = r.consumed
+	return nil
+}
+
+// Close releases the channel.
+func (r *Receiver) Close(ctx context.Context) error {
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	_, err := r.handlerManager.UnregisterHandler(ctx, DataTopic(r.jobID, r.channelID))
+	return errors.Trace(err)
+}
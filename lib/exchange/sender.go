@@ -0,0 +1,143 @@
+package exchange
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+	"go.uber.org/atomic"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/p2p"
+)
+
+// Sender is the sending end of a data channel, it is not thread-safe.
+type Sender struct {
+	jobID          libModel.MasterID
+	channelID      string
+	target         p2p.NodeID
+	messageSender  p2p.MessageSender
+	handlerManager p2p.MessageHandlerManager
+	cfg            Config
+
+	// nextSeq is the sequence number of the next chunk
+	nextSeq  uint64
+	consumed atomic.Uint64
+	// creditCh is notified when credits are granted
+	creditCh chan struct{}
+	closed   bool
+}
+
+// NewSender opens the sending end of a channel to the receiving worker
+// running on the target executor.
+func NewSender(
+	ctx context.Context,
+	messageSender p2p.MessageSender,
+	handlerManager p2p.MessageHandlerManager,
+	target p2p.NodeID,
+	jobID libModel.MasterID,
+	channelID string,
+	cfg Config,
+) (*Sender, error) {
+	s := &Sender{
+		jobID:          jobID,
+		channelID:      channelID,
+		target:         target,
+		messageSender:  messageSender,
+		handlerManager: handlerManager,
+		cfg:            cfg.withDefaults(),
+		nextSeq:        1,
+		creditCh:       make(chan struct{}, 1),
+	}
+	ok, err := handlerManager.RegisterHandler(ctx, CreditTopic(jobID, channelID), &CreditMessage{},
+		func(sender p2p.NodeID, value p2p.MessageValue) error {
+			s.onCredit(value.(*CreditMessage))
+			return nil
+		})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !ok {
+		return nil, derror.ErrChannelDuplicated.GenWithStackByArgs(channelID)
+	}
+	return s, nil
+}
+
+func (s *Sender) onCredit(msg *CreditMessage) {
+	for {
+		consumed := s.consumed.Load()
+		if msg.Consumed <= consumed {
+			return
+		}
+		if s.consumed.CAS(consumed, msg.Consumed) {
+			break
+		}
+	}
+	select {
+	case s.creditCh <- struct{}{}:
+	default:
+	}
+}
+
+// Send sends a message to the receiver, the message is split into chunks of
+// ChunkSize. It blocks until all the chunks are sent, which may wait for the
+// receiver to consume the previous chunks.
+func (s *Sender) Send(ctx context.Context, data []byte) error {
+	if s.closed {
+		return derror.ErrChannelClosed.GenWithStackByArgs(s.channelID)
+	}
+	for {
+		size := len(data)
+		if size > s.cfg.ChunkSize {
+			size = s.cfg.ChunkSize
+		}
+		chunk := &ChunkMessage{Data: data[:size], Last: size == len(data)}
+		if err := s.sendChunk(ctx, chunk); err != nil {
+			return err
+		}
+		data = data[size:]
+		if chunk.Last {
+			return nil
+		}
+	}
+}
+
+// CloseSend tells the receiver that no more messages will be sent, and
+// releases the channel. The receiver gets io.EOF after all the messages are
+// received.
+func (s *Sender) CloseSend(ctx context.Context) error {
+	if s.closed {
+		return nil
+	}
+	if err := s.sendChunk(ctx, &ChunkMessage{EOF: true}); err != nil {
+		return err
+	}
+	return s.Close(ctx)
+}
+
+// Close releases the channel without telling the receiver, it is used when
+// the channel is abandoned.
+func (s *Sender) Close(ctx context.Context) error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	_, err := s.handlerManager.UnregisterHandler(ctx, CreditTopic(s.jobID, s.channelID))
+	return errors.Trace(err)
+}
+
+func (s *Sender) sendChunk(ctx context.Context, chunk *ChunkMessage) error {
+	for s.nextSeq > s.consumed.Load()+uint64(s.cfg.Window) {
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case <-s.creditCh:
+		}
+	}
+	chunk.Seq = s.nextSeq
+	if err := s.messageSender.SendToNodeB(ctx, s.target, DataTopic(s.jobID, s.channelID), chunk); err != nil {
+		return errors.Trace(err)
+	}
+	s.nextSeq++
+	return nil
+}
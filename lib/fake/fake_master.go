@@ -47,12 +47,36 @@ type Config struct {
 	EtcdWatchPrefix string   `json:"etcd-watch-prefix"`
 
 	InjectErrorInterval time.Duration `json:"inject-error-interval"`
+
+	// StatusUpdateInterval is the minimum interval between two status
+	// updates of each worker, 0 means defaultStatusUpdateInterval.
+	StatusUpdateInterval time.Duration `json:"status-update-interval"`
 }
 
 // Checkpoint defines the checkpoint of fake job
 type Checkpoint struct {
 	Ticks       map[int]int64            `json:"ticks"`
 	Checkpoints map[int]workerCheckpoint `json:"checkpoints"`
+	Stats       *LoadStats               `json:"stats,omitempty"`
+}
+
+// LoadStats is the statistics collected by fake master, which is used to
+// measure the framework when fake jobs are used for load testing.
+type LoadStats struct {
+	// SchedulingLatencies are the durations from creating each worker to
+	// the worker becoming online.
+	SchedulingLatencies []time.Duration `json:"scheduling-latencies"`
+	// StatusUpdates is the number of worker status updates received.
+	StatusUpdates int64 `json:"status-updates"`
+	// WorkerFailures is the number of workers that went offline without
+	// finishing.
+	WorkerFailures int `json:"worker-failures"`
+}
+
+func (s *LoadStats) clone() *LoadStats {
+	ret := *s
+	ret.SchedulingLatencies = append([]time.Duration(nil), s.SchedulingLatencies...)
+	return &ret
 }
 
 // String implements fmt.Stringer
@@ -93,6 +117,13 @@ type Master struct {
 	workerID2BusinessID map[libModel.WorkerID]int
 	pendingWorkerSet    map[libModel.WorkerID]int
 	finishedSet         map[libModel.WorkerID]int
+	// workerCreateTime records when each pending worker is created
+	workerCreateTime map[libModel.WorkerID]clock.MonotonicTime
+
+	stats struct {
+		sync.Mutex
+		LoadStats
+	}
 
 	// worker status
 	statusRateLimiter *rate.Limiter
@@ -113,13 +144,13 @@ type businessStatus struct {
 
 // OnJobManagerFailover implements JobMasterImpl.OnJobManagerFailover
 func (m *Master) OnJobManagerFailover(reason lib.MasterFailoverReason) error {
-	log.L().Info("FakeMaster: OnJobManagerFailover", zap.Any("reason", reason))
+	m.Logger().Info("FakeMaster: OnJobManagerFailover", zap.Any("reason", reason))
 	return nil
 }
 
 // OnJobManagerMessage implements JobMasterImpl.OnJobManagerMessage
 func (m *Master) OnJobManagerMessage(topic p2p.Topic, message p2p.MessageValue) error {
-	log.L().Info("FakeMaster: OnJobManagerMessage", zap.Any("message", message))
+	m.Logger().Info("FakeMaster: OnJobManagerMessage", zap.Any("message", message))
 	switch msg := message.(type) {
 	case *libModel.StatusChangeRequest:
 		switch msg.ExpectState {
@@ -152,10 +183,10 @@ func (m *Master) OnJobManagerMessage(topic p2p.Topic, message p2p.MessageValue)
 			}
 			m.workerListMu.Unlock()
 		default:
-			log.L().Info("FakeMaster: ignore status change state", zap.Int32("state", int32(msg.ExpectState)))
+			m.Logger().Info("FakeMaster: ignore status change state", zap.Int32("state", int32(msg.ExpectState)))
 		}
 	default:
-		log.L().Info("unsupported message", zap.Any("message", message))
+		m.Logger().Info("unsupported message", zap.Any("message", message))
 	}
 	return nil
 }
@@ -177,7 +208,7 @@ func (m *Master) Workload() model.RescUnit {
 
 // InitImpl implements BaseJobMaster.InitImpl
 func (m *Master) InitImpl(ctx context.Context) error {
-	log.L().Info("FakeMaster: Init", zap.Any("config", m.config))
+	m.Logger().Info("FakeMaster: Init", zap.Any("config", m.config))
 	return m.initWorkers()
 }
 
@@ -187,11 +218,12 @@ func (m *Master) createWorker(wcfg *WorkerConfig) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
-	log.L().Info("CreateWorker called",
+	m.Logger().Info("CreateWorker called",
 		zap.Int("BusinessID", wcfg.ID),
 		zap.String("worker-id", workerID))
 	m.pendingWorkerSet[workerID] = wcfg.ID
 	m.workerID2BusinessID[workerID] = wcfg.ID
+	m.workerCreateTime[workerID] = m.clocker.Mono()
 	return nil
 }
 
@@ -224,7 +256,7 @@ func (m *Master) tickedCheckWorkers(ctx context.Context) error {
 	if !m.initialized {
 		if !m.IsMasterReady() {
 			if m.statusRateLimiter.Allow() {
-				log.L().Info("master is not ready, wait")
+				m.Logger().Info("master is not ready, wait")
 			}
 			return nil
 		}
@@ -256,12 +288,17 @@ func (m *Master) tickedCheckWorkers(ctx context.Context) error {
 		ckpt := &Checkpoint{}
 		resp, metaErr := m.MetaKVClient().Get(ctx, CheckpointKey(m.workerID))
 		if metaErr != nil {
-			log.L().Warn("failed to load checkpoint", zap.Error(metaErr))
+			m.Logger().Warn("failed to load checkpoint", zap.Error(metaErr))
 		} else {
 			if len(resp.Kvs) > 0 {
 				if err := json.Unmarshal(resp.Kvs[0].Value, ckpt); err != nil {
 					return errors.Trace(err)
 				}
+				if ckpt.Stats != nil {
+					m.stats.Lock()
+					m.stats.LoadStats = *ckpt.Stats
+					m.stats.Unlock()
+				}
 			}
 		}
 		for i, worker := range m.workerList {
@@ -307,17 +344,17 @@ func (m *Master) tickedCheckWorkers(ctx context.Context) error {
 func (m *Master) tickedCheckStatus(ctx context.Context) error {
 	if m.statusRateLimiter.Allow() {
 		m.bStatus.RLock()
-		log.L().Info("FakeMaster: Tick", zap.Any("status", m.bStatus.status))
+		m.Logger().Info("FakeMaster: Tick", zap.Any("status", m.bStatus.status))
 		m.bStatus.RUnlock()
 		// save checkpoint, which is used in business only
 		_, metaErr := m.MetaKVClient().Put(ctx, CheckpointKey(m.workerID), m.genCheckpoint().String())
 		if metaErr != nil {
-			log.L().Warn("update checkpoint with error", zap.Error(metaErr))
+			m.Logger().Warn("update checkpoint with error", zap.Error(metaErr))
 		}
 		// update status via framework provided API
 		err := m.BaseJobMaster.UpdateJobStatus(ctx, m.Status())
 		if derrors.ErrWorkerUpdateStatusTryAgain.Equal(err) {
-			log.L().Warn("update status try again later", zap.String("error", err.Error()))
+			m.Logger().Warn("update status try again later", zap.String("error", err.Error()))
 			return nil
 		}
 		return err
@@ -325,12 +362,17 @@ func (m *Master) tickedCheckStatus(ctx context.Context) error {
 
 	// check for special worker status
 	if m.getStatusCode() == libModel.WorkerStatusStopped {
-		log.L().Info("FakeMaster: received pause command, stop now")
+		m.Logger().Info("FakeMaster: received pause command, stop now")
 		m.setStatusCode(libModel.WorkerStatusStopped)
 		return m.Exit(ctx, m.Status(), nil)
 	}
 	if len(m.finishedSet) == m.config.WorkerCount {
-		log.L().Info("FakeMaster: all worker finished, job master exits now")
+		m.Logger().Info("FakeMaster: all worker finished, job master exits now")
+		// save the final statistics for load testing
+		_, metaErr := m.MetaKVClient().Put(ctx, CheckpointKey(m.workerID), m.genCheckpoint().String())
+		if metaErr != nil {
+			m.Logger().Warn("update checkpoint with error", zap.Error(metaErr))
+		}
 		m.setStatusCode(libModel.WorkerStatusFinished)
 		return m.Exit(ctx, m.Status(), nil)
 	}
@@ -348,14 +390,14 @@ func (m *Master) Tick(ctx context.Context) error {
 
 // OnMasterRecovered implements MasterImpl.OnMasterRecovered
 func (m *Master) OnMasterRecovered(ctx context.Context) error {
-	log.L().Info("FakeMaster: OnMasterRecovered")
+	m.Logger().Info("FakeMaster: OnMasterRecovered")
 	return nil
 }
 
 // OnWorkerDispatched implements MasterImpl.OnWorkerDispatched
 func (m *Master) OnWorkerDispatched(worker lib.WorkerHandle, result error) error {
 	if result != nil {
-		log.L().Error("FakeMaster: OnWorkerDispatched", zap.Error(result))
+		m.Logger().Error("FakeMaster: OnWorkerDispatched", zap.Error(result))
 		return errors.Trace(result)
 	}
 
@@ -364,7 +406,7 @@ func (m *Master) OnWorkerDispatched(worker lib.WorkerHandle, result error) error
 
 // OnWorkerOnline implements MasterImpl.OnWorkerOnline
 func (m *Master) OnWorkerOnline(worker lib.WorkerHandle) error {
-	log.L().Info("FakeMaster: OnWorkerOnline",
+	m.Logger().Info("FakeMaster: OnWorkerOnline",
 		zap.String("worker-id", worker.ID()))
 
 	m.workerListMu.Lock()
@@ -372,12 +414,19 @@ func (m *Master) OnWorkerOnline(worker lib.WorkerHandle) error {
 
 	idx, ok := m.pendingWorkerSet[worker.ID()]
 	if !ok {
-		log.L().Panic("OnWorkerOnline is called with an unknown workerID",
+		m.Logger().Panic("OnWorkerOnline is called with an unknown workerID",
 			zap.String("worker-id", worker.ID()))
 	}
 	delete(m.pendingWorkerSet, worker.ID())
 	m.workerList[idx] = worker
 
+	if createTime, ok := m.workerCreateTime[worker.ID()]; ok {
+		delete(m.workerCreateTime, worker.ID())
+		m.stats.Lock()
+		m.stats.SchedulingLatencies = append(m.stats.SchedulingLatencies, m.clocker.Mono().Sub(createTime))
+		m.stats.Unlock()
+	}
+
 	return nil
 }
 
@@ -405,16 +454,19 @@ func (m *Master) OnWorkerOffline(worker lib.WorkerHandle, reason error) error {
 	m.bStatus.Unlock()
 
 	if derrors.ErrWorkerFinish.Equal(reason) {
-		log.L().Info("FakeMaster: OnWorkerOffline: worker finished", zap.String("worker-id", worker.ID()))
+		m.Logger().Info("FakeMaster: OnWorkerOffline: worker finished", zap.String("worker-id", worker.ID()))
 		m.finishedSet[worker.ID()] = index
 		return nil
 	}
 
-	log.L().Info("FakeMaster: OnWorkerOffline",
+	m.Logger().Info("FakeMaster: OnWorkerOffline",
 		zap.String("worker-id", worker.ID()), zap.Error(reason))
+	m.stats.Lock()
+	m.stats.WorkerFailures++
+	m.stats.Unlock()
 	workerCkpt := zeroWorkerCheckpoint()
 	if ws, err := parseExtBytes(worker.Status().ExtBytes); err != nil {
-		log.L().Warn("failed to parse worker ext bytes", zap.Error(err))
+		m.Logger().Warn("failed to parse worker ext bytes", zap.Error(err))
 	} else {
 		workerCkpt.Tick = ws.Tick
 		if ws.Checkpoint != nil {
@@ -430,7 +482,7 @@ func (m *Master) OnWorkerOffline(worker lib.WorkerHandle, reason error) error {
 
 // OnWorkerMessage implements MasterImpl.OnWorkerMessage
 func (m *Master) OnWorkerMessage(worker lib.WorkerHandle, topic p2p.Topic, message interface{}) error {
-	log.L().Info("FakeMaster: OnWorkerMessage",
+	m.Logger().Info("FakeMaster: OnWorkerMessage",
 		zap.String("topic", topic),
 		zap.Any("message", message))
 	return nil
@@ -438,27 +490,30 @@ func (m *Master) OnWorkerMessage(worker lib.WorkerHandle, topic p2p.Topic, messa
 
 // OnWorkerStatusUpdated implements MasterImpl.OnWorkerStatusUpdated
 func (m *Master) OnWorkerStatusUpdated(worker lib.WorkerHandle, newStatus *libModel.WorkerStatus) error {
-	log.L().Info("FakeMaster: worker status updated",
+	m.Logger().Info("FakeMaster: worker status updated",
 		zap.String("worker-id", worker.ID()),
 		zap.Any("worker-status", newStatus))
+	m.stats.Lock()
+	m.stats.StatusUpdates++
+	m.stats.Unlock()
 	return nil
 }
 
 // CloseImpl implements MasterImpl.CloseImpl
 func (m *Master) CloseImpl(ctx context.Context) error {
-	log.L().Info("FakeMaster: Close", zap.Stack("stack"))
+	m.Logger().Info("FakeMaster: Close", zap.Stack("stack"))
 	return nil
 }
 
 // OnMasterFailover implements MasterImpl.OnMasterFailover
 func (m *Master) OnMasterFailover(reason lib.MasterFailoverReason) error {
-	log.L().Info("FakeMaster: OnMasterFailover", zap.Stack("stack"))
+	m.Logger().Info("FakeMaster: OnMasterFailover", zap.Stack("stack"))
 	return nil
 }
 
 // OnMasterMessage implements MasterImpl.OnMasterMessage
 func (m *Master) OnMasterMessage(topic p2p.Topic, message p2p.MessageValue) error {
-	log.L().Info("FakeMaster: OnMasterMessage", zap.Any("message", message))
+	m.Logger().Info("FakeMaster: OnMasterMessage", zap.Any("message", message))
 	return nil
 }
 
@@ -467,7 +522,7 @@ func (m *Master) marshalBusinessStatus() []byte {
 	defer m.bStatus.RUnlock()
 	bytes, err := json.Marshal(m.bStatus.status)
 	if err != nil {
-		log.L().Panic("unexpected marshal error", zap.Error(err))
+		m.Logger().Panic("unexpected marshal error", zap.Error(err))
 	}
 	return bytes
 }
@@ -511,6 +566,9 @@ func (m *Master) genCheckpoint() *Checkpoint {
 		Ticks:       make(map[int]int64),
 		Checkpoints: make(map[int]workerCheckpoint),
 	}
+	m.stats.Lock()
+	cp.Stats = m.stats.LoadStats.clone()
+	m.stats.Unlock()
 	m.bStatus.RLock()
 	defer m.bStatus.RUnlock()
 	for wid, status := range m.bStatus.status {
@@ -537,14 +595,15 @@ func (m *Master) genWorkerConfig(index int, checkpoint workerCheckpoint) *Worker
 		EtcdWatchPrefix: m.config.EtcdWatchPrefix,
 
 		// loaded from checkpoint if exists
-		Checkpoint:          checkpoint,
-		InjectErrorInterval: m.config.InjectErrorInterval,
+		Checkpoint:           checkpoint,
+		InjectErrorInterval:  m.config.InjectErrorInterval,
+		StatusUpdateInterval: m.config.StatusUpdateInterval,
 	}
 }
 
 // NewFakeMaster creates a new fake master instance
 func NewFakeMaster(ctx *dcontext.Context, workerID libModel.WorkerID, masterID libModel.MasterID, config lib.WorkerConfig) *Master {
-	log.L().Info("new fake master", zap.Any("config", config))
+	ctx.L().Info("new fake master", zap.Any("config", config))
 	masterConfig := config.(*Config)
 	ret := &Master{
 		workerID:            workerID,
@@ -555,6 +614,7 @@ func NewFakeMaster(ctx *dcontext.Context, workerID libModel.WorkerID, masterID l
 		statusRateLimiter:   rate.NewLimiter(rate.Every(100*time.Millisecond), 1),
 		bStatus:             &businessStatus{status: make(map[libModel.WorkerID]*dummyWorkerStatus)},
 		finishedSet:         make(map[libModel.WorkerID]int),
+		workerCreateTime:    make(map[libModel.WorkerID]clock.MonotonicTime),
 		ctx:                 ctx.Context,
 		clocker:             clock.New(),
 		initialized:         false,
@@ -9,7 +9,6 @@ import (
 	"time"
 
 	"github.com/pingcap/errors"
-	"github.com/pingcap/tiflow/dm/pkg/log"
 	"go.etcd.io/etcd/api/v3/mvccpb"
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.uber.org/zap"
@@ -26,6 +25,8 @@ import (
 
 var _ lib.Worker = (*dummyWorker)(nil)
 
+const defaultStatusUpdateInterval = 100 * time.Millisecond
+
 type (
 	// Worker is exposed for unit test
 	Worker = dummyWorker
@@ -39,6 +40,9 @@ type (
 		EtcdEndpoints       []string      `json:"etcd-endpoints"`
 		EtcdWatchPrefix     string        `json:"etcd-watch-prefix"`
 		InjectErrorInterval time.Duration `json:"inject-error-interval"`
+		// StatusUpdateInterval is the minimum interval between two status
+		// updates, 0 means defaultStatusUpdateInterval.
+		StatusUpdateInterval time.Duration `json:"status-update-interval"`
 
 		Checkpoint workerCheckpoint `json:"checkpoint"`
 	}
@@ -125,10 +129,10 @@ func (d *dummyWorker) Tick(ctx context.Context) error {
 	d.status.tick()
 
 	if d.statusRateLimiter.Allow() {
-		log.L().Info("FakeWorker: Tick", zap.String("worker-id", d.ID()), zap.Int64("tick", d.status.Tick))
+		d.Logger().Info("FakeWorker: Tick", zap.Int64("tick", d.status.Tick))
 		err := d.BaseWorker.UpdateStatus(ctx, d.Status())
 		if derrors.ErrWorkerUpdateStatusTryAgain.Equal(err) {
-			log.L().Warn("update status try again later", zap.String("error", err.Error()))
+			d.Logger().Warn("update status try again later", zap.String("error", err.Error()))
 			return nil
 		}
 		return err
@@ -160,7 +164,7 @@ func (d *dummyWorker) Status() libModel.WorkerStatus {
 	if d.init {
 		extBytes, err := d.status.Marshal()
 		if err != nil {
-			log.L().Panic("unexpected error", zap.Error(err))
+			d.Logger().Panic("unexpected error", zap.Error(err))
 		}
 		return libModel.WorkerStatus{
 			Code:     d.getStatusCode(),
@@ -179,17 +183,17 @@ func (d *dummyWorker) OnMasterFailover(_ lib.MasterFailoverReason) error {
 }
 
 func (d *dummyWorker) OnMasterMessage(topic p2p.Topic, message p2p.MessageValue) error {
-	log.L().Info("fakeWorker: OnMasterMessage", zap.Any("message", message))
+	d.Logger().Info("fakeWorker: OnMasterMessage", zap.Any("message", message))
 	switch msg := message.(type) {
 	case *libModel.StatusChangeRequest:
 		switch msg.ExpectState {
 		case libModel.WorkerStatusStopped:
 			d.setStatusCode(libModel.WorkerStatusStopped)
 		default:
-			log.L().Info("FakeWorker: ignore status change state", zap.Int32("state", int32(msg.ExpectState)))
+			d.Logger().Info("FakeWorker: ignore status change state", zap.Int32("state", int32(msg.ExpectState)))
 		}
 	default:
-		log.L().Info("unsupported message", zap.Any("message", message))
+		d.Logger().Info("unsupported message", zap.Any("message", message))
 	}
 
 	return nil
@@ -218,7 +222,7 @@ func (d *dummyWorker) bgRunEtcdWatcher(ctx context.Context) {
 			select {
 			case d.errCh <- err:
 			default:
-				log.L().Warn("duplicated error", zap.Error(err))
+				d.Logger().Warn("duplicated error", zap.Error(err))
 			}
 		}
 	}()
@@ -249,7 +253,7 @@ watchLoop:
 		ch := cli.Watch(ctx, key, opts...)
 		for resp := range ch {
 			if resp.Err() != nil {
-				log.L().Warn("watch met error", zap.Error(resp.Err()))
+				d.Logger().Warn("watch met error", zap.Error(resp.Err()))
 				continue watchLoop
 			}
 			for _, event := range resp.Events {
@@ -285,8 +289,12 @@ func NewDummyWorker(
 			MvccCount: wcfg.Checkpoint.MvccCount,
 		},
 	}
+	statusUpdateInterval := wcfg.StatusUpdateInterval
+	if statusUpdateInterval <= 0 {
+		statusUpdateInterval = defaultStatusUpdateInterval
+	}
 	return &dummyWorker{
-		statusRateLimiter: rate.NewLimiter(rate.Every(100*time.Millisecond), 1),
+		statusRateLimiter: rate.NewLimiter(rate.Every(statusUpdateInterval), 1),
 		status:            status,
 		config:            wcfg,
 		errCh:             make(chan error, 1),
@@ -0,0 +1,216 @@
+package loadgen
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	"github.com/hanfei1991/microcosm/client"
+	"github.com/hanfei1991/microcosm/lib/fake"
+	"github.com/hanfei1991/microcosm/pb"
+	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
+)
+
+const defaultPollInterval = time.Second
+
+// Config defines the load to generate with fake jobs.
+type Config struct {
+	JobCount    int
+	WorkerCount int
+	TargetTick  int
+
+	// StatusUpdateInterval is the minimum interval between two status updates
+	// of each fake worker.
+	StatusUpdateInterval time.Duration
+	// InjectErrorInterval makes each fake worker fail once after running
+	// for the interval, 0 means no failure is injected.
+	InjectErrorInterval time.Duration
+
+	// PollInterval is the interval to query whether the jobs are finished.
+	PollInterval time.Duration
+}
+
+// Report is the statistics of a load test.
+type Report struct {
+	Jobs         int           `json:"jobs"`
+	FinishedJobs int           `json:"finished-jobs"`
+	Duration     time.Duration `json:"duration"`
+
+	// SchedulingLatency* are measured from creating a worker to the worker
+	// becoming online in the view of job master.
+	ScheduledWorkers     int           `json:"scheduled-workers"`
+	SchedulingLatencyP50 time.Duration `json:"scheduling-latency-p50"`
+	SchedulingLatencyP99 time.Duration `json:"scheduling-latency-p99"`
+	SchedulingLatencyMax time.Duration `json:"scheduling-latency-max"`
+
+	StatusUpdates  int64 `json:"status-updates"`
+	WorkerFailures int   `json:"worker-failures"`
+}
+
+// String implements fmt.Stringer
+func (r *Report) String() string {
+	data, err := json.Marshal(r)
+	if err != nil {
+		log.L().Warn("report marshal failed", zap.Error(err))
+	}
+	return string(data)
+}
+
+// Generator launches fake jobs and collects the statistics of them.
+type Generator struct {
+	cfg       *Config
+	masterCli client.MasterClient
+	// metaCli is the user metastore client, which is used to read the
+	// checkpoints of fake jobs.
+	metaCli metaclient.KVClient
+}
+
+// NewGenerator creates a new Generator instance
+func NewGenerator(cfg *Config, masterCli client.MasterClient, metaCli metaclient.KVClient) *Generator {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultPollInterval
+	}
+	return &Generator{
+		cfg:       cfg,
+		masterCli: masterCli,
+		metaCli:   metaCli,
+	}
+}
+
+// Run submits the fake jobs, waits for them to finish and returns the report.
+// If ctx is done before all jobs finish, the report of finished jobs is
+// returned together with the error.
+func (g *Generator) Run(ctx context.Context) (*Report, error) {
+	startTime := time.Now()
+	jobIDs := make([]string, 0, g.cfg.JobCount)
+	for i := 0; i < g.cfg.JobCount; i++ {
+		jobID, err := g.submitJob(ctx, i)
+		if err != nil {
+			return nil, err
+		}
+		jobIDs = append(jobIDs, jobID)
+	}
+	log.L().Info("fake jobs submitted", zap.Strings("job-ids", jobIDs))
+
+	finished, waitErr := g.waitJobsFinished(ctx, jobIDs)
+	report := &Report{
+		Jobs:         len(jobIDs),
+		FinishedJobs: len(finished),
+		Duration:     time.Since(startTime),
+	}
+	if err := g.collectStats(context.Background(), finished, report); err != nil {
+		return nil, err
+	}
+	return report, waitErr
+}
+
+func (g *Generator) submitJob(ctx context.Context, index int) (string, error) {
+	cfg := &fake.Config{
+		JobName:              fmt.Sprintf("load-test-%d", index),
+		WorkerCount:          g.cfg.WorkerCount,
+		TargetTick:           g.cfg.TargetTick,
+		InjectErrorInterval:  g.cfg.InjectErrorInterval,
+		StatusUpdateInterval: g.cfg.StatusUpdateInterval,
+	}
+	cfgBytes, err := json.Marshal(cfg)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	resp, err := g.masterCli.SubmitJob(ctx, &pb.SubmitJobRequest{
+		Tp:     pb.JobType_FakeJob,
+		Config: cfgBytes,
+	})
+	if err != nil {
+		return "", err
+	}
+	if resp.Err != nil {
+		return "", errors.New(resp.Err.String())
+	}
+	return resp.JobIdStr, nil
+}
+
+// waitJobsFinished returns the jobs that have finished.
+func (g *Generator) waitJobsFinished(ctx context.Context, jobIDs []string) ([]string, error) {
+	pending := make(map[string]struct{}, len(jobIDs))
+	for _, jobID := range jobIDs {
+		pending[jobID] = struct{}{}
+	}
+	finished := make([]string, 0, len(jobIDs))
+
+	ticker := time.NewTicker(g.cfg.PollInterval)
+	defer ticker.Stop()
+	for len(pending) > 0 {
+		select {
+		case <-ctx.Done():
+			return finished, errors.Trace(ctx.Err())
+		case <-ticker.C:
+		}
+		for jobID := range pending {
+			resp, err := g.masterCli.QueryJob(ctx, &pb.QueryJobRequest{JobId: jobID})
+			if err != nil {
+				log.L().Warn("query job failed", zap.String("job-id", jobID), zap.Error(err))
+				continue
+			}
+			if resp.Err != nil {
+				log.L().Warn("query job failed", zap.String("job-id", jobID),
+					zap.String("err", resp.Err.String()))
+				continue
+			}
+			if resp.Status == pb.QueryJobResponse_finished {
+				delete(pending, jobID)
+				finished = append(finished, jobID)
+			}
+		}
+		log.L().Info("waiting for fake jobs", zap.Int("pending", len(pending)))
+	}
+	return finished, nil
+}
+
+func (g *Generator) collectStats(ctx context.Context, jobIDs []string, report *Report) error {
+	var latencies []time.Duration
+	for _, jobID := range jobIDs {
+		resp, metaErr := g.metaCli.Get(ctx, fake.CheckpointKey(jobID))
+		if metaErr != nil {
+			return errors.New(metaErr.Error())
+		}
+		if len(resp.Kvs) == 0 {
+			log.L().Warn("no checkpoint found", zap.String("job-id", jobID))
+			continue
+		}
+		ckpt := &fake.Checkpoint{}
+		if err := json.Unmarshal(resp.Kvs[0].Value, ckpt); err != nil {
+			return errors.Trace(err)
+		}
+		if ckpt.Stats == nil {
+			continue
+		}
+		latencies = append(latencies, ckpt.Stats.SchedulingLatencies...)
+		report.StatusUpdates += ckpt.Stats.StatusUpdates
+		report.WorkerFailures += ckpt.Stats.WorkerFailures
+	}
+
+	report.ScheduledWorkers = len(latencies)
+	if len(latencies) == 0 {
+		return nil
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	report.SchedulingLatencyP50 = percentile(latencies, 50)
+	report.SchedulingLatencyP99 = percentile(latencies, 99)
+	report.SchedulingLatencyMax = latencies[len(latencies)-1]
+	return nil
+}
+
+// percentile returns the p-th percentile of sorted, which must not be empty.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	idx := (len(sorted)*p+99)/100 - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return sorted[idx]
+}
@@ -0,0 +1,60 @@
+package loadgen
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hanfei1991/microcosm/lib/fake"
+	"github.com/hanfei1991/microcosm/pkg/meta/kvclient/mock"
+)
+
+func TestPercentile(t *testing.T) {
+	t.Parallel()
+
+	sorted := make([]time.Duration, 0, 100)
+	for i := 1; i <= 100; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+	require.Equal(t, 50*time.Millisecond, percentile(sorted, 50))
+	require.Equal(t, 99*time.Millisecond, percentile(sorted, 99))
+	require.Equal(t, time.Millisecond, percentile(sorted, 0))
+	require.Equal(t, time.Second, percentile([]time.Duration{time.Second}, 99))
+}
+
+func TestCollectStats(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	metaCli := mock.NewMetaMock()
+	checkpoints := map[string]*fake.Checkpoint{
+		"job-1": {Stats: &fake.LoadStats{
+			SchedulingLatencies: []time.Duration{3 * time.Millisecond, time.Millisecond},
+			StatusUpdates:       10,
+		}},
+		"job-2": {Stats: &fake.LoadStats{
+			SchedulingLatencies: []time.Duration{2 * time.Millisecond},
+			StatusUpdates:       5,
+			WorkerFailures:      1,
+		}},
+	}
+	for jobID, ckpt := range checkpoints {
+		_, err := metaCli.Put(ctx, fake.CheckpointKey(jobID), ckpt.String())
+		require.Nil(t, err)
+	}
+
+	g := NewGenerator(&Config{}, nil, metaCli)
+	report := &Report{}
+	err := g.collectStats(ctx, []string{"job-1", "job-2", "job-3"}, report)
+	require.NoError(t, err)
+	require.Equal(t, &Report{
+		ScheduledWorkers:     3,
+		SchedulingLatencyP50: 2 * time.Millisecond,
+		SchedulingLatencyP99: 3 * time.Millisecond,
+		SchedulingLatencyMax: 3 * time.Millisecond,
+		StatusUpdates:        15,
+		WorkerFailures:       1,
+	}, report)
+}
@@ -0,0 +1,479 @@
+package lib
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/model"
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+	resourcemeta "github.com/hanfei1991/microcosm/pkg/externalresource/resourcemeta/model"
+)
+
+// JobCreator creates the sub-jobs of a JobDAG, it is implemented by BaseMaster
+// and BaseJobMaster.
+type JobCreator interface {
+	CreateWorker(
+		workerType WorkerType,
+		config WorkerConfig,
+		cost model.RescUnit,
+		resources ...resourcemeta.ResourceID,
+	) (libModel.WorkerID, error)
+}
+
+// DAGFailurePolicy decides what happens to the other sub-jobs of a JobDAG
+// when a sub-job fails.
+type DAGFailurePolicy int
+
+// defines the failure policies of a JobDAG
+const (
+	// DAGFailFast stops triggering any sub-job once a sub-job fails, the
+	// pending sub-jobs are cancelled and the DAG fails.
+	DAGFailFast = DAGFailurePolicy(iota + 1)
+	// DAGSkipDependents cancels the sub-jobs depending on the failed sub-job,
+	// directly or indirectly, other branches of the DAG keep running.
+	DAGSkipDependents
+	// DAGIgnoreFailure treats a failed sub-job as completed, so its
+	// dependents are triggered anyway.
+	DAGIgnoreFailure
+)
+
+// DAGJobState is the state of a sub-job in a JobDAG.
+type DAGJobState string
+
+// defines the states of a sub-job
+const (
+	DAGJobPending     = DAGJobState("pending")
+	DAGJobDispatching = DAGJobState("dispatching")
+	DAGJobRunning     = DAGJobState("running")
+	DAGJobSucceeded   = DAGJobState("succeeded")
+	DAGJobFailed      = DAGJobState("failed")
+	DAGJobCancelled   = DAGJobState("cancelled")
+)
+
+func (s DAGJobState) isTerminated() bool {
+	return s == DAGJobSucceeded || s == DAGJobFailed || s == DAGJobCancelled
+}
+
+// DAGState is the aggregated state of a JobDAG.
+type DAGState string
+
+// defines the states of a JobDAG
+const (
+	DAGPending   = DAGState("pending")
+	DAGRunning   = DAGState("running")
+	DAGSucceeded = DAGState("succeeded")
+	DAGFailed    = DAGState("failed")
+)
+
+// DAGJobSpec describes a sub-job of a JobDAG.
+type DAGJobSpec struct {
+	Type   WorkerType
+	Config WorkerConfig
+	Cost   model.RescUnit
+	// DependsOn are the names of the sub-jobs that must succeed before this
+	// sub-job is triggered.
+	DependsOn []string
+	// MaxRetries is the number of times the sub-job is created again after
+	// it fails, before the failure policy applies.
+	MaxRetries int
+}
+
+// DAGJobStatus is the status of a sub-job in a JobDAG.
+type DAGJobStatus struct {
+	Name      string            `json:"name"`
+	State     DAGJobState       `json:"state"`
+	WorkerID  libModel.WorkerID `json:"worker-id,omitempty"`
+	Retries   int               `json:"retries"`
+	DependsOn []string          `json:"depends-on,omitempty"`
+	Error     string            `json:"error,omitempty"`
+}
+
+// DAGStatus is the aggregated status of a JobDAG.
+type DAGStatus struct {
+	State DAGState            `json:"state"`
+	Count map[DAGJobState]int `json:"count"`
+	Jobs  []DAGJobStatus      `json:"jobs"`
+}
+
+type dagJob struct {
+	spec   DAGJobSpec
+	status DAGJobStatus
+}
+
+// JobDAG runs the sub-jobs of a coordinator master according to the
+// dependencies between them. The coordinator adds the sub-jobs with AddJob,
+// calls Start in its InitImpl, and forwards the worker callbacks of its
+// MasterImpl to OnWorkerDispatched, OnWorkerOnline and OnWorkerOffline.
+// Sub-jobs are triggered in the callbacks, which are called in Poll.
+type JobDAG struct {
+	creator JobCreator
+	policy  DAGFailurePolicy
+
+	mu       sync.Mutex
+	started  bool
+	failed   bool
+	jobs     map[string]*dagJob
+	order    []string // names of jobs in the order of AddJob
+	byWorker map[libModel.WorkerID]string
+}
+
+// NewJobDAG creates a JobDAG whose sub-jobs are created by creator.
+func NewJobDAG(creator JobCreator, policy DAGFailurePolicy) *JobDAG {
+	return &JobDAG{
+		creator:  creator,
+		policy:   policy,
+		jobs:     make(map[string]*dagJob),
+		byWorker: make(map[libModel.WorkerID]string),
+	}
+}
+
+// AddJob adds a sub-job to the DAG, it must be called before Start.
+func (d *JobDAG) AddJob(name string, spec DAGJobSpec) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.started {
+		return derror.ErrJobDAGStarted.GenWithStackByArgs()
+	}
+	if name == "" {
+		return derror.ErrJobDAGInvalid.GenWithStackByArgs("job name is empty")
+	}
+	if _, ok := d.jobs[name]; ok {
+		return derror.ErrJobDAGInvalid.GenWithStackByArgs("duplicate job " + name)
+	}
+	d.jobs[name] = &dagJob{
+		spec: spec,
+		status: DAGJobStatus{
+			Name:      name,
+			State:     DAGJobPending,
+			DependsOn: spec.DependsOn,
+		},
+	}
+	d.order = append(d.order, name)
+	return nil
+}
+
+// Start validates the DAG and triggers the sub-jobs without dependencies.
+func (d *JobDAG) Start() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.started {
+		return derror.ErrJobDAGStarted.GenWithStackByArgs()
+	}
+	if err := d.validate(); err != nil {
+		return err
+	}
+	d.started = true
+	return d.triggerReadyJobs()
+}
+
+// validate checks that all dependencies exist and that there is no cycle.
+func (d *JobDAG) validate() error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	marks := make(map[string]int, len(d.jobs))
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch marks[name] {
+		case visiting:
+			return derror.ErrJobDAGInvalid.GenWithStackByArgs("cycle found at job " + name)
+		case visited:
+			return nil
+		}
+		marks[name] = visiting
+		for _, dep := range d.jobs[name].spec.DependsOn {
+			if _, ok := d.jobs[dep]; !ok {
+				return derror.ErrJobDAGInvalid.GenWithStackByArgs(
+					"job " + name + " depends on unknown job " + dep)
+			}
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		marks[name] = visited
+		return nil
+	}
+	for _, name := range d.order {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// triggerReadyJobs creates the pending sub-jobs whose dependencies are all
+// completed, and cancels those that can never be triggered.
+func (d *JobDAG) triggerReadyJobs() error {
+	// cancelling a job may make its dependents unreachable, so loop until
+	// nothing changes.
+	for changed := true; changed; {
+		changed = false
+		for _, name := range d.order {
+			job := d.jobs[name]
+			if job.status.State != DAGJobPending {
+				continue
+			}
+			ready, cancelled := d.checkDependencies(job)
+			if cancelled {
+				d.setState(job, DAGJobCancelled, "")
+				changed = true
+				continue
+			}
+			if !ready {
+				continue
+			}
+			if err := d.createJob(job); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (d *JobDAG) checkDependencies(job *dagJob) (ready bool, cancelled bool) {
+	if d.failed && d.policy == DAGFailFast {
+		return false, true
+	}
+	ready = true
+	for _, dep := range job.spec.DependsOn {
+		switch d.jobs[dep].status.State {
+		case DAGJobSucceeded:
+		case DAGJobFailed:
+			if d.policy != DAGIgnoreFailure {
+				return false, true
+			}
+		case DAGJobCancelled:
+			return false, true
+		default:
+			ready = false
+		}
+	}
+	return ready, false
+}
+
+func (d *JobDAG) createJob(job *dagJob) error {
+	workerID, err := d.creator.CreateWorker(job.spec.Type, job.spec.Config, job.spec.Cost)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	job.status.WorkerID = workerID
+	job.status.Error = ""
+	d.byWorker[workerID] = job.status.Name
+	d.setState(job, DAGJobDispatching, "")
+	return nil
+}
+
+func (d *JobDAG) setState(job *dagJob, state DAGJobState, errMsg string) {
+	log.L().Info("job DAG sub-job state changes",
+		zap.String("job", job.status.Name),
+		zap.String("worker-id", job.status.WorkerID),
+		zap.String("old-state", string(job.status.State)),
+		zap.String("new-state", string(state)),
+		zap.String("error", errMsg))
+	job.status.State = state
+	if errMsg != "" {
+		job.status.Error = errMsg
+	}
+}
+
+// jobOfWorker returns the sub-job of the worker, or nil if the worker
+// doesn't belong to the DAG.
+func (d *JobDAG) jobOfWorker(workerID libModel.WorkerID) *dagJob {
+	name, ok := d.byWorker[workerID]
+	if !ok {
+		return nil
+	}
+	job := d.jobs[name]
+	if job.status.WorkerID != workerID {
+		// a stale worker of a retried job
+		return nil
+	}
+	return job
+}
+
+// OnWorkerDispatched should be called in MasterImpl.OnWorkerDispatched.
+// It returns false if the worker doesn't belong to the DAG.
+func (d *JobDAG) OnWorkerDispatched(worker WorkerHandle, result error) (bool, error) {
+	if result == nil {
+		return d.hasWorker(worker.ID()), nil
+	}
+	return d.onJobExit(worker.ID(), result)
+}
+
+// OnWorkerOnline should be called in MasterImpl.OnWorkerOnline.
+// It returns false if the worker doesn't belong to the DAG.
+func (d *JobDAG) OnWorkerOnline(worker WorkerHandle) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	job := d.jobOfWorker(worker.ID())
+	if job == nil {
+		return false, nil
+	}
+	if job.status.State == DAGJobDispatching {
+		d.setState(job, DAGJobRunning, "")
+	}
+	return true, nil
+}
+
+// OnWorkerOffline should be called in MasterImpl.OnWorkerOffline. A sub-job
+// succeeds if it exits with ErrWorkerFinish, otherwise it fails.
+// It returns false if the worker doesn't belong to the DAG.
+func (d *JobDAG) OnWorkerOffline(worker WorkerHandle, reason error) (bool, error) {
+	return d.onJobExit(worker.ID(), reason)
+}
+
+func (d *JobDAG) hasWorker(workerID libModel.WorkerID) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.jobOfWorker(workerID) != nil
+}
+
+func (d *JobDAG) onJobExit(workerID libModel.WorkerID, reason error) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	job := d.jobOfWorker(workerID)
+	if job == nil {
+		return false, nil
+	}
+	if job.status.State.isTerminated() {
+		return true, nil
+	}
+
+	if derror.ErrWorkerFinish.Equal(reason) {
+		d.setState(job, DAGJobSucceeded, "")
+		return true, d.triggerReadyJobs()
+	}
+
+	errMsg := "unknown error"
+	if reason != nil {
+		errMsg = reason.Error()
+	}
+	if job.status.Retries < job.spec.MaxRetries && !(d.failed && d.policy == DAGFailFast) {
+		job.status.Retries++
+		log.L().Warn("job DAG sub-job failed, retry it",
+			zap.String("job", job.status.Name),
+			zap.String("worker-id", workerID),
+			zap.Int("retries", job.status.Retries),
+			zap.Error(reason))
+		job.status.Error = errMsg
+		return true, d.createJob(job)
+	}
+
+	d.setState(job, DAGJobFailed, errMsg)
+	d.failed = true
+	return true, d.triggerReadyJobs()
+}
+
+// Status returns the aggregated status of the DAG.
+func (d *JobDAG) Status() DAGStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	status := DAGStatus{
+		Count: make(map[DAGJobState]int),
+		Jobs:  make([]DAGJobStatus, 0, len(d.order)),
+	}
+	for _, name := range d.order {
+		job := d.jobs[name]
+		status.Count[job.status.State]++
+		status.Jobs = append(status.Jobs, job.status)
+	}
+	status.State = d.aggregateState(status.Count)
+	return status
+}
+
+func (d *JobDAG) aggregateState(count map[DAGJobState]int) DAGState {
+	if !d.started {
+		return DAGPending
+	}
+	terminated := count[DAGJobSucceeded] + count[DAGJobFailed] + count[DAGJobCancelled]
+	if terminated < len(d.jobs) {
+		return DAGRunning
+	}
+	if count[DAGJobCancelled] > 0 {
+		return DAGFailed
+	}
+	if count[DAGJobFailed] > 0 && d.policy != DAGIgnoreFailure {
+		return DAGFailed
+	}
+	return DAGSucceeded
+}
+
+// Done returns whether all sub-jobs of the DAG have terminated.
+func (d *JobDAG) Done() bool {
+	state := d.Status().State
+	return state == DAGSucceeded || state == DAGFailed
+}
+
+// jobDAGState is the persisted state of a JobDAG.
+type jobDAGState struct {
+	Started bool           `json:"started"`
+	Failed  bool           `json:"failed"`
+	Jobs    []DAGJobStatus `json:"jobs"`
+}
+
+// MarshalState encodes the progress of the DAG, the coordinator should
+// persist it, for example in its ext status, and restore the DAG after
+// failover by RestoreState.
+func (d *JobDAG) MarshalState() ([]byte, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	state := jobDAGState{Started: d.started, Failed: d.failed}
+	for _, name := range d.order {
+		state.Jobs = append(state.Jobs, d.jobs[name].status)
+	}
+	data, err := json.Marshal(&state)
+	return data, errors.Trace(err)
+}
+
+// RestoreState restores the progress of the DAG encoded by MarshalState. The
+// sub-jobs must have been added by AddJob, and Start must not be called.
+// Sub-jobs that were dispatching or running are tracked by their workers,
+// which are recovered by the coordinator after failover.
+func (d *JobDAG) RestoreState(data []byte) error {
+	var state jobDAGState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return errors.Trace(err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.started {
+		return derror.ErrJobDAGStarted.GenWithStackByArgs()
+	}
+	if err := d.validate(); err != nil {
+		return err
+	}
+	for _, status := range state.Jobs {
+		job, ok := d.jobs[status.Name]
+		if !ok {
+			return derror.ErrJobDAGInvalid.GenWithStackByArgs("unknown job " + status.Name + " in state")
+		}
+		job.status.State = status.State
+		job.status.WorkerID = status.WorkerID
+		job.status.Retries = status.Retries
+		job.status.Error = status.Error
+		if status.WorkerID != "" {
+			d.byWorker[status.WorkerID] = status.Name
+		}
+	}
+	d.failed = state.Failed
+	d.started = state.Started
+	if !d.started {
+		return nil
+	}
+	return d.triggerReadyJobs()
+}
@@ -0,0 +1,181 @@
+package lib
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hanfei1991/microcosm/lib/master"
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/model"
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+	resourcemeta "github.com/hanfei1991/microcosm/pkg/externalresource/resourcemeta/model"
+)
+
+type mockJobCreator struct {
+	created []WorkerType
+}
+
+func (c *mockJobCreator) CreateWorker(
+	workerType WorkerType,
+	config WorkerConfig,
+	cost model.RescUnit,
+	resources ...resourcemeta.ResourceID,
+) (libModel.WorkerID, error) {
+	c.created = append(c.created, workerType)
+	return fmt.Sprintf("worker-%d", len(c.created)), nil
+}
+
+func dagJobStates(d *JobDAG) map[string]DAGJobState {
+	states := make(map[string]DAGJobState)
+	for _, job := range d.Status().Jobs {
+		states[job.Name] = job.State
+	}
+	return states
+}
+
+func dagWorker(d *JobDAG, name string) WorkerHandle {
+	for _, job := range d.Status().Jobs {
+		if job.Name == name {
+			return &master.MockHandle{WorkerID: job.WorkerID}
+		}
+	}
+	return nil
+}
+
+func TestJobDAGValidate(t *testing.T) {
+	t.Parallel()
+
+	dag := NewJobDAG(&mockJobCreator{}, DAGFailFast)
+	require.NoError(t, dag.AddJob("a", DAGJobSpec{DependsOn: []string{"b"}}))
+	require.NoError(t, dag.AddJob("b", DAGJobSpec{DependsOn: []string{"a"}}))
+	require.True(t, derror.ErrJobDAGInvalid.Equal(dag.AddJob("a", DAGJobSpec{})))
+	require.True(t, derror.ErrJobDAGInvalid.Equal(dag.Start()))
+
+	dag = NewJobDAG(&mockJobCreator{}, DAGFailFast)
+	require.NoError(t, dag.AddJob("a", DAGJobSpec{DependsOn: []string{"c"}}))
+	require.True(t, derror.ErrJobDAGInvalid.Equal(dag.Start()))
+
+	dag = NewJobDAG(&mockJobCreator{}, DAGFailFast)
+	require.NoError(t, dag.Start())
+	require.True(t, derror.ErrJobDAGStarted.Equal(dag.AddJob("a", DAGJobSpec{})))
+	require.Equal(t, DAGSucceeded, dag.Status().State)
+}
+
+func TestJobDAGTrigger(t *testing.T) {
+	t.Parallel()
+
+	creator := &mockJobCreator{}
+	dag := NewJobDAG(creator, DAGFailFast)
+	require.NoError(t, dag.AddJob("a", DAGJobSpec{Type: 1}))
+	require.NoError(t, dag.AddJob("b", DAGJobSpec{Type: 2}))
+	require.NoError(t, dag.AddJob("c", DAGJobSpec{Type: 3, DependsOn: []string{"a", "b"}}))
+	require.Equal(t, DAGPending, dag.Status().State)
+
+	require.NoError(t, dag.Start())
+	require.Equal(t, []WorkerType{1, 2}, creator.created)
+	require.Equal(t, DAGRunning, dag.Status().State)
+
+	handled, err := dag.OnWorkerOnline(dagWorker(dag, "a"))
+	require.NoError(t, err)
+	require.True(t, handled)
+	handled, err = dag.OnWorkerOnline(&master.MockHandle{WorkerID: "unknown"})
+	require.NoError(t, err)
+	require.False(t, handled)
+
+	_, err = dag.OnWorkerOffline(dagWorker(dag, "a"), derror.ErrWorkerFinish.GenWithStackByArgs())
+	require.NoError(t, err)
+	require.Len(t, creator.created, 2)
+	_, err = dag.OnWorkerOffline(dagWorker(dag, "b"), derror.ErrWorkerFinish.GenWithStackByArgs())
+	require.NoError(t, err)
+	require.Equal(t, []WorkerType{1, 2, 3}, creator.created)
+
+	_, err = dag.OnWorkerOffline(dagWorker(dag, "c"), derror.ErrWorkerFinish.GenWithStackByArgs())
+	require.NoError(t, err)
+	status := dag.Status()
+	require.Equal(t, DAGSucceeded, status.State)
+	require.Equal(t, 3, status.Count[DAGJobSucceeded])
+	require.True(t, dag.Done())
+}
+
+func TestJobDAGFailurePolicies(t *testing.T) {
+	t.Parallel()
+
+	// a -> b, c is independent
+	newDAG := func(policy DAGFailurePolicy) (*JobDAG, *mockJobCreator) {
+		creator := &mockJobCreator{}
+		dag := NewJobDAG(creator, policy)
+		require.NoError(t, dag.AddJob("a", DAGJobSpec{Type: 1}))
+		require.NoError(t, dag.AddJob("b", DAGJobSpec{Type: 2, DependsOn: []string{"a"}}))
+		require.NoError(t, dag.AddJob("c", DAGJobSpec{Type: 3}))
+		require.NoError(t, dag.AddJob("d", DAGJobSpec{Type: 4, DependsOn: []string{"c"}}))
+		require.NoError(t, dag.Start())
+		return dag, creator
+	}
+	failure := derror.ErrWorkerOffline.GenWithStackByArgs("worker-1", "injected")
+
+	dag, creator := newDAG(DAGFailFast)
+	_, err := dag.OnWorkerDispatched(dagWorker(dag, "a"), failure)
+	require.NoError(t, err)
+	_, err = dag.OnWorkerOffline(dagWorker(dag, "c"), derror.ErrWorkerFinish.GenWithStackByArgs())
+	require.NoError(t, err)
+	require.Equal(t, []WorkerType{1, 3}, creator.created)
+	require.Equal(t, map[string]DAGJobState{
+		"a": DAGJobFailed, "b": DAGJobCancelled, "c": DAGJobSucceeded, "d": DAGJobCancelled,
+	}, dagJobStates(dag))
+	require.Equal(t, DAGFailed, dag.Status().State)
+
+	dag, creator = newDAG(DAGSkipDependents)
+	_, err = dag.OnWorkerOffline(dagWorker(dag, "a"), failure)
+	require.NoError(t, err)
+	_, err = dag.OnWorkerOffline(dagWorker(dag, "c"), derror.ErrWorkerFinish.GenWithStackByArgs())
+	require.NoError(t, err)
+	require.Equal(t, []WorkerType{1, 3, 4}, creator.created)
+	require.Equal(t, map[string]DAGJobState{
+		"a": DAGJobFailed, "b": DAGJobCancelled, "c": DAGJobSucceeded, "d": DAGJobDispatching,
+	}, dagJobStates(dag))
+	require.Equal(t, DAGRunning, dag.Status().State)
+
+	dag, creator = newDAG(DAGIgnoreFailure)
+	_, err = dag.OnWorkerOffline(dagWorker(dag, "a"), failure)
+	require.NoError(t, err)
+	require.Equal(t, []WorkerType{1, 3, 2}, creator.created)
+}
+
+func TestJobDAGRetryAndRestore(t *testing.T) {
+	t.Parallel()
+
+	creator := &mockJobCreator{}
+	dag := NewJobDAG(creator, DAGFailFast)
+	require.NoError(t, dag.AddJob("a", DAGJobSpec{Type: 1, MaxRetries: 1}))
+	require.NoError(t, dag.AddJob("b", DAGJobSpec{Type: 2, DependsOn: []string{"a"}}))
+	require.NoError(t, dag.Start())
+
+	staleWorker := dagWorker(dag, "a")
+	failure := derror.ErrWorkerOffline.GenWithStackByArgs(staleWorker.ID(), "injected")
+	_, err := dag.OnWorkerOffline(staleWorker, failure)
+	require.NoError(t, err)
+	require.Equal(t, []WorkerType{1, 1}, creator.created)
+	// events of the stale worker are ignored
+	handled, err := dag.OnWorkerOffline(staleWorker, failure)
+	require.NoError(t, err)
+	require.False(t, handled)
+	require.Equal(t, DAGJobDispatching, dagJobStates(dag)["a"])
+
+	data, err := dag.MarshalState()
+	require.NoError(t, err)
+
+	// restore the DAG as a new coordinator after failover
+	creator2 := &mockJobCreator{}
+	dag2 := NewJobDAG(creator2, DAGFailFast)
+	require.NoError(t, dag2.AddJob("a", DAGJobSpec{Type: 1, MaxRetries: 1}))
+	require.NoError(t, dag2.AddJob("b", DAGJobSpec{Type: 2, DependsOn: []string{"a"}}))
+	require.NoError(t, dag2.RestoreState(data))
+	require.Empty(t, creator2.created)
+	require.Equal(t, dag.Status(), dag2.Status())
+
+	_, err = dag2.OnWorkerOffline(dagWorker(dag2, "a"), derror.ErrWorkerFinish.GenWithStackByArgs())
+	require.NoError(t, err)
+	require.Equal(t, []WorkerType{2}, creator2.created)
+}
@@ -0,0 +1,68 @@
+package lib
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/errors"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	resourcemeta "github.com/hanfei1991/microcosm/pkg/externalresource/resourcemeta/model"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+)
+
+// ErrorSnapshot is an error received by a master, in a serializable form
+type ErrorSnapshot struct {
+	Time     time.Time `json:"time"`
+	Severity string    `json:"severity"`
+	Error    string    `json:"error"`
+}
+
+// JobSnapshot is a read-only snapshot of a running job for troubleshooting.
+// Master, Workers and Resources are read from the framework metastore in a
+// consistent snapshot, the other fields are in-memory states of the master.
+type JobSnapshot struct {
+	CreatedAt time.Time `json:"created-at"`
+
+	Master    *libModel.MasterMetaKVData   `json:"master"`
+	Workers   []*libModel.WorkerStatus     `json:"workers"`
+	Resources []*resourcemeta.ResourceMeta `json:"resources"`
+
+	EventJournal     []EventJournalEntry `json:"event-journal"`
+	Errors           []ErrorSnapshot     `json:"errors"`
+	DependencyHealth []DependencyHealth  `json:"dependency-health"`
+	TickStats        TickStats           `json:"tick-stats"`
+}
+
+// JobSnapshot implements BaseMaster.JobSnapshot
+func (m *DefaultBaseMaster) JobSnapshot(ctx context.Context) (*JobSnapshot, error) {
+	ret := &JobSnapshot{
+		CreatedAt:        m.clock.Now(),
+		EventJournal:     m.EventJournal(),
+		DependencyHealth: m.DependencyHealth(),
+		TickStats:        m.TickStats(),
+	}
+	for _, record := range m.ErrorHistory() {
+		ret.Errors = append(ret.Errors, ErrorSnapshot{
+			Time:     record.Time,
+			Severity: record.Severity.String(),
+			Error:    record.Err.Error(),
+		})
+	}
+
+	err := m.frameMetaClient.SnapshotRead(ctx, func(snapshot pkgOrm.Client) error {
+		var err error
+		if ret.Master, err = snapshot.GetJobByID(ctx, m.id); err != nil {
+			return err
+		}
+		if ret.Workers, err = snapshot.QueryWorkersByMasterID(ctx, m.id); err != nil {
+			return err
+		}
+		ret.Resources, err = snapshot.QueryResourcesByJobID(ctx, m.id)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return ret, nil
+}
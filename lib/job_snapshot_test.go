@@ -0,0 +1,34 @@
+package lib
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobSnapshot(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	master := NewMockMasterImpl("", masterName)
+	prepareMeta(ctx, t, master.GetFrameMetaClient())
+
+	master.On("InitImpl", mock.Anything).Return(nil)
+	err := master.Init(ctx)
+	require.NoError(t, err)
+
+	master.OnError(errors.New("fake error"))
+	snapshot, err := master.JobSnapshot(ctx)
+	require.NoError(t, err)
+	require.Equal(t, masterName, snapshot.Master.ID)
+	require.Empty(t, snapshot.Workers)
+	require.Empty(t, snapshot.Resources)
+	require.Len(t, snapshot.Errors, 1)
+	require.Equal(t, "fatal", snapshot.Errors[0].Severity)
+}
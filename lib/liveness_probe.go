@@ -0,0 +1,66 @@
+package lib
+
+import (
+	"time"
+
+	"go.uber.org/atomic"
+)
+
+// LivenessProbedWorkerImpl can be implemented by a WorkerImpl to enable the
+// liveness probe. Heartbeats are sent by a separate goroutine, so a worker
+// whose Tick is deadlocked still looks alive to its master. With the probe,
+// a worker whose Tick doesn't complete within TickDeadline is reported as
+// stuck in heartbeats, and the master takes it offline with ErrWorkerStuck.
+type LivenessProbedWorkerImpl interface {
+	// TickDeadline returns the max duration of a Tick, it is called once
+	// when the worker is created. A non-positive value disables the probe.
+	TickDeadline() time.Duration
+}
+
+// tickProbe tracks the running Tick of a worker. A nil tickProbe is a
+// disabled one.
+type tickProbe struct {
+	deadline time.Duration
+	// running is true if a Tick is running, which started at startedAt in
+	// Unix nanoseconds.
+	running   atomic.Bool
+	startedAt atomic.Int64
+}
+
+func newTickProbe(impl interface{}) *tickProbe {
+	probed, ok := impl.(LivenessProbedWorkerImpl)
+	if !ok {
+		return nil
+	}
+	deadline := probed.TickDeadline()
+	if deadline <= 0 {
+		return nil
+	}
+	return &tickProbe{deadline: deadline}
+}
+
+func (p *tickProbe) begin(now time.Time) {
+	if p != nil {
+		p.startedAt.Store(now.UnixNano())
+		p.running.Store(true)
+	}
+}
+
+func (p *tickProbe) end() {
+	if p != nil {
+		p.running.Store(false)
+	}
+}
+
+// stuck returns how long the running Tick has lasted, and whether it has
+// exceeded the deadline.
+func (p *tickProbe) stuck(now time.Time) (time.Duration, bool) {
+	if p == nil {
+		return 0, false
+	}
+	if !p.running.Load() {
+		return 0, false
+	}
+	elapsed := now.Sub(time.Unix(0, p.startedAt.Load()))
+	return elapsed, elapsed > p.deadline
+}
@@ -0,0 +1,67 @@
+package lib
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hanfei1991/microcosm/pkg/clock"
+)
+
+type probedWorkerImpl struct {
+	mockWorkerImpl
+	deadline time.Duration
+}
+
+func (w *probedWorkerImpl) TickDeadline() time.Duration {
+	return w.deadline
+}
+
+func TestTickProbe(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, newTickProbe(&mockWorkerImpl{}))
+	require.Nil(t, newTickProbe(&probedWorkerImpl{}))
+	// a disabled probe is never stuck
+	var disabled *tickProbe
+	disabled.begin(time.Now())
+	_, stuck := disabled.stuck(time.Now())
+	require.False(t, stuck)
+
+	probe := newTickProbe(&probedWorkerImpl{deadline: time.Second})
+	require.NotNil(t, probe)
+	start := time.Now()
+	_, stuck = probe.stuck(start.Add(time.Hour))
+	require.False(t, stuck)
+
+	probe.begin(start)
+	elapsed, stuck := probe.stuck(start.Add(time.Second))
+	require.False(t, stuck)
+	require.Equal(t, time.Second, elapsed)
+	elapsed, stuck = probe.stuck(start.Add(2 * time.Second))
+	require.True(t, stuck)
+	require.Equal(t, 2*time.Second, elapsed)
+
+	probe.end()
+	_, stuck = probe.stuck(start.Add(time.Hour))
+	require.False(t, stuck)
+}
+
+func TestProbedTick(t *testing.T) {
+	t.Parallel()
+
+	worker := &DefaultBaseWorker{
+		tickProbe: &tickProbe{deadline: time.Second},
+		clock:     clock.NewMock(),
+	}
+	err := worker.probedTick(context.Background(), func(ctx context.Context) error {
+		_, stuck := worker.tickProbe.stuck(worker.clock.Now().Add(2 * time.Second))
+		require.True(t, stuck)
+		return nil
+	})
+	require.NoError(t, err)
+	_, stuck := worker.tickProbe.stuck(worker.clock.Now().Add(2 * time.Second))
+	require.False(t, stuck)
+}
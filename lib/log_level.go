@@ -0,0 +1,78 @@
+package lib
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+	"go.uber.org/zap"
+
+	"github.com/hanfei1991/microcosm/lib/master"
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/logutil"
+	"github.com/hanfei1991/microcosm/pkg/p2p"
+)
+
+// forwardJobLogLevel sends the log level of the job to all workers, and to
+// the workers online later until the level is reset. The overridden level
+// is not persisted, it is lost after the master fails over.
+func (m *DefaultBaseMaster) forwardJobLogLevel(level string) {
+	m.jobLogLevel.Store(level)
+	ctx := m.errCenter.WithCancelOnFirstError(context.Background())
+	for _, handle := range m.GetWorkers() {
+		if running := handle.Unwrap(); running != nil {
+			m.sendJobLogLevel(ctx, running, level)
+		}
+	}
+}
+
+func (m *DefaultBaseMaster) sendJobLogLevel(
+	ctx context.Context, handle master.RunningHandle, level string,
+) {
+	topic := libModel.LogLevelUpdateRequestTopic(m.id, handle.ID())
+	msg := &libModel.LogLevelUpdateRequest{
+		SendTime:     m.clock.Mono(),
+		FromMasterID: m.id,
+		Epoch:        m.currentEpoch.Load(),
+		JobID:        m.id,
+		Level:        level,
+	}
+	if err := handle.SendMessage(ctx, topic, msg, true /*nonblocking*/); err != nil {
+		m.Logger().Warn("failed to send log level to worker",
+			zap.String("worker-id", handle.ID()), zap.String("level", level), zap.Error(err))
+	}
+}
+
+// registerLogLevelUpdateHandler handles the requests from the master to
+// override the log level of the job in this process. A job master forwards
+// the level to its workers by onLogLevelUpdated.
+func (w *DefaultBaseWorker) registerLogLevelUpdateHandler(ctx context.Context) error {
+	topic := libModel.LogLevelUpdateRequestTopic(w.masterID, w.id)
+	ok, err := w.messageHandlerManager.RegisterHandler(
+		ctx,
+		topic,
+		&libModel.LogLevelUpdateRequest{},
+		func(sender p2p.NodeID, value p2p.MessageValue) error {
+			msg, ok := value.(*libModel.LogLevelUpdateRequest)
+			if !ok {
+				return derror.ErrInvalidMasterMessage.GenWithStackByArgs(value)
+			}
+			if err := logutil.UpdateJobLevel(msg.JobID, msg.Level); err != nil {
+				w.Logger().Warn("failed to update log level",
+					zap.Any("request", msg), zap.Error(err))
+				return nil
+			}
+			w.Logger().Info("log level of job updated", zap.String("level", msg.Level))
+			if w.onLogLevelUpdated != nil {
+				w.onLogLevelUpdated(msg.Level)
+			}
+			return nil
+		})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !ok {
+		w.Logger().Panic("duplicate handler", zap.String("topic", topic))
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package lib
+
+import (
+	"sync"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/pkg/logutil"
+	"github.com/hanfei1991/microcosm/pkg/tenant"
+)
+
+// taggedLogger tags the logger of a master or worker with the project and
+// the epoch, which are only known after the metadata is loaded. The tagged
+// logger is cached, since it is injected into the context of every Poll, so
+// that the logs of MasterImpl and WorkerImpl in callbacks are tagged by
+// getting the logger with logutil.FromContext.
+type taggedLogger struct {
+	// base is tagged with the job and the worker
+	base log.Logger
+
+	mu      sync.Mutex
+	project tenant.ProjectID
+	epoch   libModel.Epoch
+	cached  *log.Logger
+}
+
+func newTaggedLogger(base log.Logger, project tenant.ProjectID) *taggedLogger {
+	return &taggedLogger{
+		base:    base,
+		project: project,
+	}
+}
+
+// setProject tags the logger with the project, an empty project is ignored
+// so that a known project is not erased.
+func (l *taggedLogger) setProject(project tenant.ProjectID) {
+	if project == "" {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.project != project {
+		l.project = project
+		l.cached = nil
+	}
+}
+
+// get returns the logger tagged with the project and the given epoch.
+func (l *taggedLogger) get(epoch libModel.Epoch) log.Logger {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.cached == nil || l.epoch != epoch {
+		logger := logutil.WithEpoch(
+			logutil.WithProjectInfo(l.base, tenant.ProjectInfo{ProjectID: l.project}), epoch)
+		l.cached = &logger
+		l.epoch = epoch
+	}
+	return *l.cached
+}
@@ -0,0 +1,47 @@
+package lib
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/hanfei1991/microcosm/pkg/logutil"
+)
+
+func TestTaggedLogger(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	base := logutil.WithJobID(log.Logger{Logger: zap.New(core)}, "job-1")
+	logger := newTaggedLogger(base, "")
+
+	logger.get(1).Info("no project")
+	// the cached logger is reused until the epoch or project changes
+	cached := logger.cached
+	logger.get(1)
+	require.Same(t, cached, logger.cached)
+
+	logger.setProject("project-1")
+	logger.get(2).Info("tagged")
+	// an empty project doesn't erase the known one
+	logger.setProject("")
+	logger.get(2).Info("tagged")
+
+	entries := logs.All()
+	require.Len(t, entries, 3)
+	require.Equal(t, map[string]interface{}{
+		logutil.JobIDKey: "job-1",
+		logutil.EpochKey: int64(1),
+	}, entries[0].ContextMap())
+	for _, entry := range entries[1:] {
+		require.Equal(t, map[string]interface{}{
+			logutil.JobIDKey:     "job-1",
+			logutil.ProjectIDKey: "project-1",
+			logutil.EpochKey:     int64(2),
+		}, entry.ContextMap())
+	}
+}
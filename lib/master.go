@@ -1,13 +1,10 @@
 package lib
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"sync"
 	"time"
 
-	"github.com/BurntSushi/toml"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tiflow/dm/pkg/log"
 	"go.uber.org/atomic"
@@ -17,6 +14,7 @@ import (
 	"github.com/hanfei1991/microcosm/client"
 	runtime "github.com/hanfei1991/microcosm/executor/worker"
 	"github.com/hanfei1991/microcosm/lib/config"
+	"github.com/hanfei1991/microcosm/lib/exchange"
 	"github.com/hanfei1991/microcosm/lib/master"
 	"github.com/hanfei1991/microcosm/lib/metadata"
 	libModel "github.com/hanfei1991/microcosm/lib/model"
@@ -24,17 +22,25 @@ import (
 	"github.com/hanfei1991/microcosm/model"
 	"github.com/hanfei1991/microcosm/pb"
 	"github.com/hanfei1991/microcosm/pkg/clock"
+	"github.com/hanfei1991/microcosm/pkg/compat"
 	dcontext "github.com/hanfei1991/microcosm/pkg/context"
 	"github.com/hanfei1991/microcosm/pkg/deps"
 	"github.com/hanfei1991/microcosm/pkg/errctx"
 	derror "github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/eventbus"
 	resourcemeta "github.com/hanfei1991/microcosm/pkg/externalresource/resourcemeta/model"
+	"github.com/hanfei1991/microcosm/pkg/faultinject"
+	"github.com/hanfei1991/microcosm/pkg/lockdiag"
+	"github.com/hanfei1991/microcosm/pkg/logutil"
+	"github.com/hanfei1991/microcosm/pkg/meta/encryption"
 	extkv "github.com/hanfei1991/microcosm/pkg/meta/extension"
 	"github.com/hanfei1991/microcosm/pkg/meta/kvclient"
 	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
 	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
 	"github.com/hanfei1991/microcosm/pkg/p2p"
 	"github.com/hanfei1991/microcosm/pkg/quota"
+	"github.com/hanfei1991/microcosm/pkg/redact"
+	"github.com/hanfei1991/microcosm/pkg/sink"
 	"github.com/hanfei1991/microcosm/pkg/tenant"
 	"github.com/hanfei1991/microcosm/pkg/uuid"
 )
@@ -58,6 +64,7 @@ type MasterImpl interface {
 	Tick(ctx context.Context) error
 
 	// OnMasterRecovered is called when the master has recovered from an error.
+	// ctx carries a RecoveryReport, see RecoveryReportFromContext.
 	OnMasterRecovered(ctx context.Context) error
 
 	// OnWorkerDispatched is called when a request to launch a worker is finished.
@@ -80,10 +87,57 @@ type MasterImpl interface {
 	CloseImpl(ctx context.Context) error
 }
 
+// WorkerUnresponsiveAwareMasterImpl can be implemented by a MasterImpl to be
+// notified when a worker has missed consecutive heartbeats but not timed out
+// yet, so that it can stop assigning work to the worker or prepare a
+// replacement in advance. It is advisory only, the worker may recover, and
+// OnWorkerOffline is still called if it times out.
+type WorkerUnresponsiveAwareMasterImpl interface {
+	// OnWorkerUnresponsive is called at most once after each heartbeat of the
+	// worker, when the worker has missed TimeoutConfig.WorkerUnresponsiveHeartbeatMisses
+	// heartbeats.
+	OnWorkerUnresponsive(worker WorkerHandle, missedHeartbeats int) error
+}
+
+// IncrementalRecoveryMasterImpl can be implemented by a MasterImpl with lots
+// of workers to recover faster after failover. In an incremental recovery,
+// only the status codes of the workers are loaded before OnMasterRecovered is
+// called, and the full statuses are loaded in the background. Until then, the
+// Status of a WorkerHandle only has the status code, unless the worker has
+// updated its status since.
+type IncrementalRecoveryMasterImpl interface {
+	// RecoverIncrementally returns whether to recover incrementally, it is
+	// called once after failover.
+	RecoverIncrementally() bool
+	// OnRecoveryProgress is called in Poll after each batch of worker
+	// statuses is loaded, the statuses of all workers are loaded when
+	// hydrated equals total.
+	OnRecoveryProgress(hydrated, total int) error
+}
+
+// FastFailoverMasterImpl can be implemented by a latency-sensitive MasterImpl
+// to skip waiting for the heartbeats of its workers after failover, which
+// takes up to the worker timeout. In a fast failover, OnMasterRecovered is
+// called at once, and the workers recorded in meta are suspects, see
+// RecoveryReport.SuspectWorkers. OnWorkerOnline is called when a suspect
+// sends its first heartbeat, and OnWorkerOffline is called if it sends none
+// in the worker timeout.
+type FastFailoverMasterImpl interface {
+	// FailoverFast returns whether to fail over fast, it is called once
+	// after failover.
+	FailoverFast() bool
+}
+
 const (
 	createWorkerWaitQuotaTimeout = 5 * time.Second
 	createWorkerTimeout          = 10 * time.Second
-	maxCreateWorkerConcurrency   = 100
+	// defaultQueuedWorkerWaitTimeout is used if CreateWorkerQueued doesn't
+	// specify the wait timeout, it is capped by the max wait of server master.
+	defaultQueuedWorkerWaitTimeout = 10 * time.Minute
+	scaleUpJobTimeout              = 3 * time.Second
+	// defaultMaxCreateWorkerConcurrency is used if the job doesn't specify
+	// MaxCreateWorkerConcurrency in its master meta.
+	defaultMaxCreateWorkerConcurrency = 100
 )
 
 // BaseMaster defines the master interface, it embeds the Master interface and
@@ -94,10 +148,61 @@ type BaseMaster interface {
 	// MetaKVClient return user metastore kv client
 	MetaKVClient() metaclient.KVClient
 	MasterMeta() *libModel.MasterMetaKVData
+	// Logger returns a logger tagged with the job, the project and the
+	// current epoch, it should be used by MasterImpl instead of log.L().
+	// The contexts passed to InitImpl, Tick and CloseImpl carry the same
+	// logger, see logutil.FromContext.
+	Logger() log.Logger
 	GetWorkers() map[libModel.WorkerID]WorkerHandle
 	IsMasterReady() bool
 	OnError(err error)
 
+	// RegisterDependencyChecker registers a checker of a downstream dependency.
+	// Registered checkers are run before the master is marked as initialized,
+	// and periodically thereafter. No new worker is created while any
+	// dependency is unhealthy.
+	RegisterDependencyChecker(checker DependencyChecker)
+	// DependencyHealth returns the result of the latest dependency checks.
+	DependencyHealth() []DependencyHealth
+	// TickStats returns the latency of the latest Poll, see WithTickWatchdog.
+	TickStats() TickStats
+
+	// FeatureEnabled returns whether the feature is supported by the master
+	// and all its workers, it is false during a rolling upgrade until all of
+	// them have been upgraded.
+	FeatureEnabled(feature compat.Feature) bool
+	// WorkerCapabilities returns the capabilities supported by both the master
+	// and the worker, ok is false if the worker hasn't sent a heartbeat yet.
+	WorkerCapabilities(workerID libModel.WorkerID) (caps libModel.CapabilitySet, ok bool)
+
+	WorkerMessageHandlerRegistrar
+
+	// RequestBarrier requests a barrier that the given workers report reaching
+	// by BaseWorker.ReachBarrier. The impl is notified by
+	// BarrierAwareMasterImpl.OnBarrierReached once quorum workers, or all
+	// workers if quorum is 0, have reached it. Barriers are persisted, and
+	// survive failover of the master.
+	RequestBarrier(ctx context.Context, barrierID string, workers []libModel.WorkerID, quorum int) error
+	// IsBarrierReached returns whether the barrier has been reached and whether it exists.
+	IsBarrierReached(barrierID string) (reached bool, exists bool)
+	// RemoveBarrier removes a barrier and its persisted states.
+	RemoveBarrier(ctx context.Context, barrierID string) error
+
+	// JobSnapshot exports a read-only snapshot of the job for troubleshooting,
+	// without pausing the master.
+	JobSnapshot(ctx context.Context) (*JobSnapshot, error)
+	// WorkerManagerDebugInfo dumps the in-memory state of the workers kept
+	// by the master, it doesn't block even if a Tick is stuck.
+	WorkerManagerDebugInfo() *master.DebugInfo
+	// QueryWorkers lists the workers of a worker which is a job master, it
+	// is used by job manager. The query is resent until the job master
+	// replies or ctx is done.
+	QueryWorkers(
+		ctx context.Context,
+		workerID libModel.WorkerID,
+		query *libModel.WorkersQuery,
+	) (*libModel.WorkersQueryResponse, error)
+
 	// CreateWorker requires the framework to dispatch a new worker.
 	// If the worker needs to access certain file system resources,
 	// their ID's must be passed by `resources`.
@@ -107,6 +212,52 @@ type BaseMaster interface {
 		cost model.RescUnit,
 		resources ...resourcemeta.ResourceID,
 	) (libModel.WorkerID, error)
+
+	// RecreateWorker is like CreateWorker, but it should be used to
+	// re-dispatch a worker that is lost due to executor failure. Such
+	// workers can use the headroom reserved by the scheduler.
+	RecreateWorker(
+		workerType WorkerType,
+		config WorkerConfig,
+		cost model.RescUnit,
+		resources ...resourcemeta.ResourceID,
+	) (libModel.WorkerID, error)
+
+	// CreateWorkerWithResources is like CreateWorker, but the worker
+	// requires resources in multiple dimensions, such as memory or custom
+	// resources like "gpu". It is only dispatched to an executor having
+	// enough remaining resources in every required dimension.
+	CreateWorkerWithResources(
+		workerType WorkerType,
+		config WorkerConfig,
+		required model.RescVector,
+		resources ...resourcemeta.ResourceID,
+	) (libModel.WorkerID, error)
+
+	// CreateWorkerQueued is like CreateWorkerWithResources, but if the
+	// cluster doesn't have enough resources, the request waits in the
+	// schedule queue of server master until the worker is placed or the
+	// waitTimeout elapses, instead of failing at once. OnWorkerDispatched is
+	// called either way, with ErrScheduleQueueTimeout in the latter case.
+	// The requests of higher priorities are placed first.
+	CreateWorkerQueued(
+		workerType WorkerType,
+		config WorkerConfig,
+		required model.RescVector,
+		priority int32,
+		waitTimeout time.Duration,
+		resources ...resourcemeta.ResourceID,
+	) (libModel.WorkerID, error)
+
+	// RequestScaleUp requests server master to reserve the capacity of more
+	// workers for the job, within the resource spec declared when the job
+	// is submitted. It returns the number of workers granted, which may be
+	// less than requested, and how long to wait before requesting again if
+	// none is granted.
+	RequestScaleUp(
+		ctx context.Context,
+		currentWorkers, workers int,
+	) (granted int, backoff time.Duration, err error)
 }
 
 // DefaultBaseMaster implements BaseMaster interface
@@ -122,6 +273,15 @@ type DefaultBaseMaster struct {
 	userRawKVClient       extkv.KVClientEx
 	executorClientManager client.ClientsManager
 	serverMasterClient    client.MasterClient
+	// faultInjector is nil unless faults are injected by tests.
+	faultInjector *faultinject.Injector
+	// sinkExporter is nil unless worker statuses are exported.
+	sinkExporter *sink.Exporter
+	// eventBus publishes the workers online and offline, it is nil if no
+	// subsystem is interested in them.
+	eventBus *eventbus.Bus
+	// unsubscribeBlacklisted stops receiving the blacklisted executors
+	unsubscribeBlacklisted func()
 
 	clock clock.Clock
 
@@ -130,6 +290,9 @@ type DefaultBaseMaster struct {
 	workerManager *master.WorkerManager
 
 	currentEpoch atomic.Int64
+	// jobLogLevel is the overridden log level of the job sent to workers
+	// online later, empty means not overridden, see forwardJobLogLevel.
+	jobLogLevel atomic.String
 
 	wg        sync.WaitGroup
 	errCenter *errctx.ErrCenter
@@ -138,10 +301,17 @@ type DefaultBaseMaster struct {
 	closeCh chan struct{}
 
 	id            libModel.MasterID // id of this master itself
+	logger        *taggedLogger     // tagged with the job, see Logger
 	advertiseAddr string
 	nodeID        p2p.NodeID
 	timeoutConfig config.TimeoutConfig
 	masterMeta    *libModel.MasterMetaKVData
+	// metaCache caches the persisted master metadata, which is modified by
+	// refreshMetadata and markStatusCodeInMetadata.
+	metaCache *metadata.MasterMetadataCache
+	// recoveryReport is set by doInit if the master is recovered after
+	// failover, it is passed to OnMasterRecovered.
+	recoveryReport *RecoveryReport
 
 	// user metastore prefix kvclient
 	// Don't close it. It's just a prefix wrapper for underlying userRawKVClient
@@ -152,9 +322,29 @@ type DefaultBaseMaster struct {
 
 	// TODO use a shared quota for all masters.
 	createWorkerQuota quota.ConcurrencyQuota
+	// creatingWorkers is the number of workers holding the createWorkerQuota.
+	creatingWorkers atomic.Int32
 
 	// deps is a container for injected dependencies
 	deps *deps.Deps
+
+	eventErrorPolicy EventErrorPolicy
+	eventJournal     *eventJournal
+	callbackHandler  *eventCallbackHandler
+
+	workerMessageQueue chan *workerMessage
+
+	barrierManager *barrierManager
+
+	dependencyMonitor *dependencyMonitor
+	tickWatchdog      *tickWatchdog
+
+	// protocolGate tracks the protocol versions of workers
+	protocolGate *compat.Gate
+	workerCaps   *workerCapabilities
+
+	scheduleStream *scheduleStreamClient
+	workersQuerier *workersQuerier
 }
 
 type masterParams struct {
@@ -168,6 +358,13 @@ type masterParams struct {
 	UserRawKVClient       extkv.KVClientEx
 	ExecutorClientManager client.ClientsManager
 	ServerMasterClient    client.MasterClient
+	FaultInjector         *faultinject.Injector `optional:"true"`
+	// Clock is provided to run the master on a virtual clock in tests
+	Clock        clock.Clock    `optional:"true"`
+	SinkExporter *sink.Exporter `optional:"true"`
+	EventBus     *eventbus.Bus  `optional:"true"`
+	// UserMetaKeyring encrypts the values in the user metastore
+	UserMetaKeyring *encryption.Keyring `optional:"true"`
 }
 
 // NewBaseMaster creates a new DefaultBaseMaster instance
@@ -175,6 +372,7 @@ func NewBaseMaster(
 	ctx *dcontext.Context,
 	impl MasterImpl,
 	id libModel.MasterID,
+	opts ...BaseMasterOption,
 ) BaseMaster {
 	var (
 		nodeID        p2p.NodeID
@@ -182,22 +380,33 @@ func NewBaseMaster(
 		masterMeta    = &libModel.MasterMetaKVData{}
 		params        masterParams
 	)
+	jobLogger := logutil.WithJobID(log.L(), id)
 	if ctx != nil {
+		jobLogger = logutil.WithJobID(ctx.L(), id)
 		nodeID = ctx.Environ.NodeID
 		advertiseAddr = ctx.Environ.Addr
 		metaBytes := ctx.Environ.MasterMetaBytes
 		err := errors.Trace(masterMeta.Unmarshal(metaBytes))
 		if err != nil {
-			log.L().Warn("invalid master meta", zap.ByteString("data", metaBytes), zap.Error(err))
+			jobLogger.Warn("invalid master meta", zap.ByteString("data", metaBytes), zap.Error(err))
 		}
 	}
+	logger := logutil.WithProjectInfo(jobLogger, tenant.ProjectInfo{ProjectID: masterMeta.ProjectID})
+	maxCreateWorkerConcurrency := int64(defaultMaxCreateWorkerConcurrency)
+	if masterMeta.MaxCreateWorkerConcurrency > 0 {
+		maxCreateWorkerConcurrency = int64(masterMeta.MaxCreateWorkerConcurrency)
+	}
 
 	if err := ctx.Deps().Fill(&params); err != nil {
 		// TODO more elegant error handling
-		log.L().Panic("failed to provide dependencies", zap.Error(err))
+		logger.Panic("failed to provide dependencies", zap.Error(err))
 	}
 
-	return &DefaultBaseMaster{
+	clk := clock.New()
+	if params.Clock != nil {
+		clk = params.Clock
+	}
+	ret := &DefaultBaseMaster{
 		Impl:                  impl,
 		messageHandlerManager: params.MessageHandlerManager,
 		messageSender:         params.MessageSender,
@@ -205,11 +414,16 @@ func NewBaseMaster(
 		userRawKVClient:       params.UserRawKVClient,
 		executorClientManager: params.ExecutorClientManager,
 		serverMasterClient:    params.ServerMasterClient,
+		faultInjector:         params.FaultInjector,
+		sinkExporter:          params.SinkExporter,
+		eventBus:              params.EventBus,
 		id:                    id,
-		clock:                 clock.New(),
+		clock:                 clk,
+		logger:                newTaggedLogger(jobLogger, masterMeta.ProjectID),
 
 		timeoutConfig: config.DefaultTimeoutConfig(),
 		masterMeta:    masterMeta,
+		metaCache:     metadata.NewMasterMetadataCache(id, params.FrameMetaClient),
 
 		closeCh: make(chan struct{}),
 
@@ -222,9 +436,39 @@ func NewBaseMaster(
 
 		createWorkerQuota: quota.NewConcurrencyQuota(maxCreateWorkerConcurrency),
 		// [TODO] use tenantID if support muliti-tenant
-		userMetaKVClient: kvclient.NewPrefixKVClient(params.UserRawKVClient, tenant.DefaultUserTenantID),
-		deps:             ctx.Deps(),
+		userMetaKVClient: kvclient.NewPrefixKVClient(
+			params.UserRawKVClient, tenant.DefaultUserTenantID, kvclient.WithKeyring(params.UserMetaKeyring)),
+		deps: ctx.Deps(),
+
+		eventJournal:      newEventJournal(defaultEventJournalCapacity),
+		dependencyMonitor: newDependencyMonitor(id, clk),
+		tickWatchdog:      newTickWatchdog(id, logger, clk),
+
+		workerMessageQueue: make(chan *workerMessage, workerMessageQueueSize),
+		barrierManager:     newBarrierManager(id, params.UserRawKVClient, clk),
+		protocolGate:       compat.NewGate(),
+		workerCaps:         newWorkerCapabilities(),
+		workersQuerier:     newWorkersQuerier(),
 	}
+	ret.scheduleStream = newScheduleStreamClient(func(ctx context.Context) (pb.Master_ScheduleClient, error) {
+		return ret.serverMasterClient.Schedule(ctx)
+	}, ret.Logger)
+	for _, opt := range opts {
+		opt(ret)
+	}
+	return ret
+}
+
+// EventJournal returns the latest worker events that have been retried
+// or skipped according to the EventErrorPolicy.
+func (m *DefaultBaseMaster) EventJournal() []EventJournalEntry {
+	return m.eventJournal.list()
+}
+
+// ErrorHistory returns the recent errors received by the master, including
+// retryable ones that haven't terminated it.
+func (m *DefaultBaseMaster) ErrorHistory() []errctx.ErrorRecord {
+	return m.errCenter.History()
 }
 
 // MetaKVClient returns the user space metaclient
@@ -240,17 +484,20 @@ func (m *DefaultBaseMaster) Init(ctx context.Context) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	// the epoch and project are known after doInit
+	ctx = logutil.NewContext(ctx, m.Logger())
 
 	if isInit {
 		if err := m.Impl.InitImpl(ctx); err != nil {
 			return errors.Trace(err)
 		}
 	} else {
-		if err := m.Impl.OnMasterRecovered(ctx); err != nil {
+		if err := m.Impl.OnMasterRecovered(withRecoveryReport(ctx, m.recoveryReport)); err != nil {
 			return errors.Trace(err)
 		}
 	}
 
+	m.dependencyMonitor.checkAll(ctx)
 	if err := m.markStatusCodeInMetadata(ctx, libModel.MasterStatusInit); err != nil {
 		return errors.Trace(err)
 	}
@@ -258,42 +505,166 @@ func (m *DefaultBaseMaster) Init(ctx context.Context) error {
 }
 
 func (m *DefaultBaseMaster) doInit(ctx context.Context) (isFirstStartUp bool, err error) {
-	isInit, epoch, err := m.refreshMetadata(ctx)
+	incremental := false
+	if impl, ok := m.Impl.(IncrementalRecoveryMasterImpl); ok {
+		incremental = impl.RecoverIncrementally()
+	}
+	fastFailover := false
+	if impl, ok := m.Impl.(FastFailoverMasterImpl); ok {
+		fastFailover = impl.FailoverFast()
+	}
+	isInit, prevEpoch, epoch, persistedWorkers, err := m.refreshMetadata(ctx, !incremental)
 	if err != nil {
 		return false, errors.Trace(err)
 	}
 	m.currentEpoch.Store(epoch)
 
+	if err := m.barrierManager.load(ctx); err != nil {
+		return false, errors.Trace(err)
+	}
+
+	m.callbackHandler = &eventCallbackHandler{
+		masterID: m.id,
+		policy:   m.eventErrorPolicy,
+		journal:  m.eventJournal,
+		clock:    m.clock,
+	}
 	m.workerManager = master.NewWorkerManager(
 		m.id,
 		epoch,
 		m.frameMetaClient,
 		m.messageSender,
-		func(_ context.Context, handle master.WorkerHandle) error {
-			return m.Impl.OnWorkerOnline(handle)
+		func(ctx context.Context, handle master.WorkerHandle) error {
+			eventbus.Publish(m.eventBus, eventbus.WorkerOnline{
+				Time:     m.clock.Now(),
+				TenantID: m.masterMeta.ProjectID,
+				MasterID: m.id,
+				WorkerID: handle.ID(),
+				Status:   handle.Status(),
+			})
+			if level := m.jobLogLevel.Load(); level != "" {
+				if running := handle.Unwrap(); running != nil {
+					m.sendJobLogLevel(ctx, running, level)
+				}
+			}
+			return m.callbackHandler.handle("worker-online", handle.ID(), func() error {
+				return m.Impl.OnWorkerOnline(handle)
+			})
 		},
-		func(_ context.Context, handle master.WorkerHandle, err error) error {
-			return m.Impl.OnWorkerOffline(handle, err)
+		func(ctx context.Context, handle master.WorkerHandle, err error) error {
+			eventbus.Publish(m.eventBus, eventbus.WorkerOffline{
+				Time:     m.clock.Now(),
+				TenantID: m.masterMeta.ProjectID,
+				MasterID: m.id,
+				WorkerID: handle.ID(),
+				Status:   handle.Status(),
+				Reason:   err,
+			})
+			m.observeProtocolChange(m.protocolGate.Remove(handle.ID()))
+			m.workerCaps.remove(handle.ID())
+			return m.callbackHandler.handle("worker-offline", handle.ID(), func() error {
+				return m.Impl.OnWorkerOffline(handle, err)
+			})
 		},
-		func(_ context.Context, handle master.WorkerHandle) error {
-			return m.Impl.OnWorkerStatusUpdated(handle, handle.Status())
+		func(ctx context.Context, handle master.WorkerHandle) error {
+			m.emitWorkerEvent(sink.EventWorkerStatusUpdated, handle.ID(), handle.Status(), nil)
+			return m.callbackHandler.handle("worker-status-updated", handle.ID(), func() error {
+				return m.Impl.OnWorkerStatusUpdated(handle, handle.Status())
+			})
 		},
-		func(_ context.Context, handle master.WorkerHandle, err error) error {
-			return m.Impl.OnWorkerDispatched(handle, err)
-		}, isInit, m.timeoutConfig, m.clock)
+		func(ctx context.Context, handle master.WorkerHandle, err error) error {
+			if err != nil {
+				// the entry of the worker is removed already, so the
+				// status is not available.
+				m.emitWorkerEvent(sink.EventWorkerDispatchFailed, handle.ID(), nil, err)
+			}
+			return m.callbackHandler.handle("worker-dispatched", handle.ID(), func() error {
+				return m.Impl.OnWorkerDispatched(handle, err)
+			})
+		},
+		func(ctx context.Context, handle master.WorkerHandle, missedHeartbeats int) error {
+			impl, ok := m.Impl.(WorkerUnresponsiveAwareMasterImpl)
+			if !ok {
+				return nil
+			}
+			return m.callbackHandler.handle("worker-unresponsive", handle.ID(), func() error {
+				return impl.OnWorkerUnresponsive(handle, missedHeartbeats)
+			})
+		}, isInit, m.timeoutConfig, m.clock, m.faultInjector)
 
 	if err := m.registerMessageHandlers(ctx); err != nil {
 		return false, errors.Trace(err)
 	}
 
+	// the workers on a blacklisted executor go offline at once, instead of
+	// waiting for their heartbeats to time out
+	m.unsubscribeBlacklisted = eventbus.Subscribe(m.eventBus, func(event eventbus.ExecutorBlacklisted) {
+		if err := m.workerManager.OnExecutorOffline(model.ExecutorID(event.ExecutorID)); err != nil {
+			m.Logger().Warn("failed to take workers on blacklisted executor offline",
+				zap.String("executor-id", event.ExecutorID), zap.Error(err))
+		}
+	})
+
 	if !isInit {
-		if err := m.workerManager.InitAfterRecover(ctx); err != nil {
+		if fastFailover {
+			m.workerManager.EnableFastFailover()
+		}
+		if incremental {
+			err = m.workerManager.InitAfterRecoverIncrementally(ctx,
+				func(ctx context.Context, hydrated, total int) error {
+					return m.Impl.(IncrementalRecoveryMasterImpl).OnRecoveryProgress(hydrated, total)
+				})
+		} else {
+			err = m.workerManager.InitAfterRecoverWithWorkers(ctx, persistedWorkers)
+		}
+		if err != nil {
 			return false, err
 		}
+
+		result := m.workerManager.RecoveryResult()
+		m.recoveryReport = &RecoveryReport{
+			PrevEpoch:          prevEpoch,
+			Epoch:              epoch,
+			ReconnectedWorkers: result.Reconnected,
+			TombstonedWorkers:  result.Tombstoned,
+			FinishedWorkers:    result.Finished,
+			SuspectWorkers:     result.Suspects,
+			WaitDuration:       result.WaitDuration,
+		}
+		m.Logger().Info("master recovered",
+			zap.Int64("prev-epoch", prevEpoch),
+			zap.Int("reconnected-workers", len(result.Reconnected)),
+			zap.Int("tombstoned-workers", len(result.Tombstoned)),
+			zap.Int("suspect-workers", len(result.Suspects)),
+			zap.Duration("wait-duration", result.WaitDuration))
 	}
 	return isInit, nil
 }
 
+// emitWorkerEvent exports an event of the worker if a sink is configured,
+// err overrides the error message of the worker status if it is not nil.
+func (m *DefaultBaseMaster) emitWorkerEvent(
+	tp sink.EventType, workerID libModel.WorkerID, status *libModel.WorkerStatus, err error,
+) {
+	if m.sinkExporter == nil {
+		return
+	}
+	m.sinkExporter.Emit(sink.NewWorkerEvent(
+		tp, m.clock.Now(), m.masterMeta.ProjectID, m.id, workerID, status, err))
+}
+
+// FeatureEnabled implements BaseMaster.FeatureEnabled
+func (m *DefaultBaseMaster) FeatureEnabled(feature compat.Feature) bool {
+	return m.protocolGate.Enabled(feature)
+}
+
+func (m *DefaultBaseMaster) observeProtocolChange(changed bool) {
+	if changed {
+		m.Logger().Info("protocol version of workers changes",
+			zap.Int32("version", int32(m.protocolGate.ClusterVersion())))
+	}
+}
+
 func (m *DefaultBaseMaster) registerMessageHandlers(ctx context.Context) error {
 	ok, err := m.messageHandlerManager.RegisterHandler(
 		ctx,
@@ -301,9 +672,16 @@ func (m *DefaultBaseMaster) registerMessageHandlers(ctx context.Context) error {
 		&libModel.HeartbeatPingMessage{},
 		func(sender p2p.NodeID, value p2p.MessageValue) error {
 			msg := value.(*libModel.HeartbeatPingMessage)
-			log.L().Info("Heartbeat Ping received",
-				zap.Any("msg", msg),
-				zap.String("master-id", m.id))
+			m.Logger().Info("Heartbeat Ping received",
+				zap.Any("msg", msg))
+			timeouts := m.workerManager.Timeouts()
+			m.observeProtocolChange(m.protocolGate.Observe(msg.FromWorkerID, msg.ProtocolVersion))
+			caps, changed := m.workerCaps.negotiate(msg.FromWorkerID, msg.Capabilities)
+			if changed {
+				m.Logger().Info("capabilities negotiated with worker",
+					zap.String("worker-id", msg.FromWorkerID),
+					zap.Any("capabilities", caps))
+			}
 			ok, err := m.messageSender.SendToNode(
 				ctx,
 				sender,
@@ -314,6 +692,11 @@ func (m *DefaultBaseMaster) registerMessageHandlers(ctx context.Context) error {
 					ToWorkerID: msg.FromWorkerID,
 					Epoch:      m.currentEpoch.Load(),
 					IsFinished: msg.IsFinished,
+
+					HeartbeatInterval: timeouts.WorkerHeartbeatInterval,
+					WorkerTimeout:     timeouts.WorkerTimeoutDuration,
+					ProtocolVersion:   m.protocolGate.ClusterVersion(),
+					Capabilities:      caps,
 				})
 			if err != nil {
 				return err
@@ -329,7 +712,7 @@ func (m *DefaultBaseMaster) registerMessageHandlers(ctx context.Context) error {
 		return err
 	}
 	if !ok {
-		log.L().Panic("duplicate handler", zap.String("topic", libModel.HeartbeatPingTopic(m.id)))
+		m.Logger().Panic("duplicate handler", zap.String("topic", libModel.HeartbeatPingTopic(m.id)))
 	}
 
 	ok, err = m.messageHandlerManager.RegisterHandler(
@@ -345,25 +728,91 @@ func (m *DefaultBaseMaster) registerMessageHandlers(ctx context.Context) error {
 		return err
 	}
 	if !ok {
-		log.L().Panic("duplicate handler", zap.String("topic", statusutil.WorkerStatusTopic(m.id)))
+		m.Logger().Panic("duplicate handler", zap.String("topic", statusutil.WorkerStatusTopic(m.id)))
 	}
 
-	return nil
+	ok, err = m.messageHandlerManager.RegisterHandler(
+		ctx,
+		statusutil.WorkerStatusReplayTopic(m.id),
+		&statusutil.WorkerStatusReplayMessage{},
+		func(sender p2p.NodeID, value p2p.MessageValue) error {
+			msg := value.(*statusutil.WorkerStatusReplayMessage)
+			m.workerManager.OnWorkerStatusReplayMessage(msg)
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		m.Logger().Panic("duplicate handler", zap.String("topic", statusutil.WorkerStatusReplayTopic(m.id)))
+	}
+
+	ok, err = m.messageHandlerManager.RegisterHandler(
+		ctx,
+		statusutil.BarrierTopic(m.id),
+		&statusutil.BarrierReachedMessage{},
+		func(sender p2p.NodeID, value p2p.MessageValue) error {
+			msg := value.(*statusutil.BarrierReachedMessage)
+			m.barrierManager.onWorkerReached(msg.Worker, msg.Barrier)
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		m.Logger().Panic("duplicate handler", zap.String("topic", statusutil.BarrierTopic(m.id)))
+	}
+
+	ok, err = m.messageHandlerManager.RegisterHandler(
+		ctx,
+		exchange.PeerLookupRequestTopic(m.id),
+		&exchange.PeerLookupRequest{},
+		func(sender p2p.NodeID, value p2p.MessageValue) error {
+			msg := value.(*exchange.PeerLookupRequest)
+			resp := &exchange.PeerLookupResponse{
+				RequestID: msg.RequestID,
+				PeerID:    msg.PeerID,
+			}
+			if executor, ok := m.workerManager.LookupExecutor(msg.PeerID); ok {
+				resp.Found = true
+				resp.Executor = p2p.NodeID(executor)
+			}
+			// the worker resends the lookup if the response is lost
+			_, err := m.messageSender.SendToNode(ctx, sender,
+				exchange.PeerLookupResponseTopic(m.id, msg.FromWorkerID), resp)
+			if err != nil {
+				m.Logger().Info("failed to reply peer lookup",
+					zap.String("worker-id", msg.FromWorkerID),
+					zap.Error(err))
+			}
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		m.Logger().Panic("duplicate handler", zap.String("topic", exchange.PeerLookupRequestTopic(m.id)))
+	}
+
+	return m.registerWorkersQueryHandler(ctx)
 }
 
 // Poll implements BaseMaster.Poll
 func (m *DefaultBaseMaster) Poll(ctx context.Context) error {
 	ctx = m.errCenter.WithCancelOnFirstError(ctx)
+	ctx = logutil.NewContext(ctx, m.Logger())
 
 	if err := m.doPoll(ctx); err != nil {
 		return errors.Trace(err)
 	}
 
-	if err := m.Impl.Tick(ctx); err != nil {
+	if err := m.tickWatchdog.measure(tickPhaseImpl, func() error {
+		return m.Impl.Tick(ctx)
+	}); err != nil {
 		return errors.Trace(err)
 	}
 
-	return nil
+	return m.tickWatchdog.finishPoll()
 }
 
 func (m *DefaultBaseMaster) doPoll(ctx context.Context) error {
@@ -380,7 +829,20 @@ func (m *DefaultBaseMaster) doPoll(ctx context.Context) error {
 	if err := m.messageHandlerManager.CheckError(ctx); err != nil {
 		return errors.Trace(err)
 	}
-	return m.workerManager.Tick(ctx)
+	m.dependencyMonitor.maybeCheckInBackground(
+		m.errCenter.WithCancelOnFirstError(context.Background()), &m.wg)
+	if err := m.tickWatchdog.measure(tickPhaseWorkerManager, func() error {
+		return m.workerManager.Tick(ctx)
+	}); err != nil {
+		return err
+	}
+	if err := m.callbackHandler.retryPending(); err != nil {
+		return err
+	}
+	if err := m.handleWorkerMessages(ctx); err != nil {
+		return err
+	}
+	return m.pollBarriers(ctx)
 }
 
 // MasterMeta implements BaseMaster.MasterMeta
@@ -388,11 +850,22 @@ func (m *DefaultBaseMaster) MasterMeta() *libModel.MasterMetaKVData {
 	return m.masterMeta
 }
 
+// CreatingWorkerCount returns the number of workers being created, which is
+// limited by the MaxCreateWorkerConcurrency of the job.
+func (m *DefaultBaseMaster) CreatingWorkerCount() int32 {
+	return m.creatingWorkers.Load()
+}
+
 // MasterID implements BaseMaster.MasterID
 func (m *DefaultBaseMaster) MasterID() libModel.MasterID {
 	return m.id
 }
 
+// Logger implements BaseMaster.Logger
+func (m *DefaultBaseMaster) Logger() log.Logger {
+	return m.logger.get(m.currentEpoch.Load())
+}
+
 // GetWorkers implements BaseMaster.GetWorkers
 func (m *DefaultBaseMaster) GetWorkers() map[libModel.WorkerID]WorkerHandle {
 	return m.workerManager.GetWorkers()
@@ -403,15 +876,19 @@ func (m *DefaultBaseMaster) doClose() {
 	defer cancel()
 
 	close(m.closeCh)
-	m.wg.Wait()
+	m.scheduleStream.close()
+	if m.unsubscribeBlacklisted != nil {
+		m.unsubscribeBlacklisted()
+	}
+	lockdiag.WatchBlocking("base-master-close", m.Logger(), m.wg.Wait)
 	if err := m.messageHandlerManager.Clean(closeCtx); err != nil {
-		log.L().Warn("Failed to clean up message handlers",
-			zap.String("master-id", m.id))
+		m.Logger().Warn("Failed to clean up message handlers")
 	}
 }
 
 // Close implements BaseMaster.Close
 func (m *DefaultBaseMaster) Close(ctx context.Context) error {
+	ctx = logutil.NewContext(ctx, m.Logger())
 	if err := m.Impl.CloseImpl(ctx); err != nil {
 		return errors.Trace(err)
 	}
@@ -428,58 +905,90 @@ func (m *DefaultBaseMaster) OnError(err error) {
 // refreshMetadata load and update metadata by current epoch, nodeID, advertiseAddr, etc.
 // master meta is persisted before it is created, in this function we update some
 // fileds to the current value, including epoch, nodeID and advertiseAddr.
-func (m *DefaultBaseMaster) refreshMetadata(ctx context.Context) (isInit bool, epoch libModel.Epoch, err error) {
+// If loadWorkers is true, the persisted workers are loaded in the same snapshot
+// as the master meta, which are used to recover the worker manager after
+// failover.
+func (m *DefaultBaseMaster) refreshMetadata(ctx context.Context, loadWorkers bool) (
+	isInit bool,
+	prevEpoch libModel.Epoch,
+	epoch libModel.Epoch,
+	persistedWorkers map[libModel.WorkerID]*libModel.WorkerStatus,
+	err error,
+) {
 	metaClient := metadata.NewMasterMetadataClient(m.id, m.frameMetaClient)
 
-	masterMeta, err := metaClient.Load(ctx)
+	var masterMeta *libModel.MasterMetaKVData
+	if loadWorkers {
+		masterMeta, persistedWorkers, err = metaClient.LoadWithWorkers(ctx)
+	} else {
+		masterMeta, err = metaClient.Load(ctx)
+	}
 	if err != nil {
-		return false, 0, err
+		return false, 0, 0, nil, err
 	}
+	m.metaCache.Fill(masterMeta)
+	prevEpoch = masterMeta.Epoch
 
 	epoch, err = m.frameMetaClient.GenEpoch(ctx)
 	if err != nil {
-		return false, 0, err
+		return false, 0, 0, nil, err
 	}
 
+	if err := m.faultInjector.CheckMetaWrite(m.id); err != nil {
+		return false, 0, 0, nil, err
+	}
 	// We should update the master data to reflect our current information
-	masterMeta.Epoch = epoch
-	masterMeta.Addr = m.advertiseAddr
-	masterMeta.NodeID = m.nodeID
-
-	if err := metaClient.Update(ctx, masterMeta); err != nil {
-		return false, 0, errors.Trace(err)
+	masterMeta, err = m.metaCache.Update(ctx, func(meta *libModel.MasterMetaKVData) {
+		meta.Epoch = epoch
+		meta.Addr = m.advertiseAddr
+		meta.NodeID = m.nodeID
+	})
+	if err != nil {
+		return false, 0, 0, nil, errors.Trace(err)
 	}
 
 	m.masterMeta = masterMeta
+	m.logger.setProject(masterMeta.ProjectID)
 	// isInit true means the master is created but has not been initialized.
 	isInit = masterMeta.StatusCode == libModel.MasterStatusUninit
 
 	return
 }
 
+// markStatusCodeInMetadata persists the status code of the master, the
+// metadata is read from metaCache, so it costs no read of metastore unless
+// the metadata has been modified by others.
 func (m *DefaultBaseMaster) markStatusCodeInMetadata(
 	ctx context.Context, code libModel.MasterStatusCode,
 ) error {
-	metaClient := metadata.NewMasterMetadataClient(m.id, m.frameMetaClient)
-	masterMeta, err := metaClient.Load(ctx)
-	if err != nil {
-		return errors.Trace(err)
+	if err := m.faultInjector.CheckMetaWrite(m.id); err != nil {
+		return err
 	}
-
-	masterMeta.StatusCode = code
-	return metaClient.Update(ctx, masterMeta)
+	_, err := m.metaCache.Update(ctx, func(meta *libModel.MasterMetaKVData) {
+		meta.StatusCode = code
+	})
+	return errors.Trace(err)
 }
 
 // prepareWorkerConfig extracts information from WorkerConfig into detail fields.
 // - If workerType is master type, the config is a `*MasterMetaKVData` struct and
 //   contains pre allocated maseter ID, and json marshalled config.
 // - If workerType is worker type, the config is a user defined config struct, we
-//   marshal it to byte slice as returned config, and generate a random WorkerID.
+//   marshal it to byte slice as returned config with the codec declared by
+//   RegisterWorkerTypeInfo, and generate a random WorkerID.
 func (m *DefaultBaseMaster) prepareWorkerConfig(
 	workerType libModel.WorkerType, config WorkerConfig,
 ) (rawConfig []byte, workerID libModel.WorkerID, err error) {
-	switch workerType {
-	case CvsJobMaster, FakeJobMaster, DMJobMaster:
+	info, ok := getWorkerTypeInfo(workerType)
+	if !ok {
+		// Job masters are created by the job manager in server master, where
+		// job type plugins may not be loaded, so they are recognized by the
+		// type of config here.
+		_, isMasterMeta := config.(*libModel.MasterMetaKVData)
+		info = WorkerTypeInfo{IsJobMaster: isMasterMeta}
+	}
+
+	if info.IsJobMaster {
 		masterMeta, ok := config.(*libModel.MasterMetaKVData)
 		if !ok {
 			err = derror.ErrMasterInvalidMeta.GenWithStackByArgs(config)
@@ -487,21 +996,14 @@ func (m *DefaultBaseMaster) prepareWorkerConfig(
 		}
 		rawConfig = masterMeta.Config
 		workerID = masterMeta.ID
-	case WorkerDMDump, WorkerDMLoad, WorkerDMSync:
-		var b bytes.Buffer
-		err = toml.NewEncoder(&b).Encode(config)
-		if err != nil {
-			return
-		}
-		rawConfig = b.Bytes()
-		workerID = m.uuidGen.NewString()
-	default:
-		rawConfig, err = json.Marshal(config)
-		if err != nil {
-			return
-		}
-		workerID = m.uuidGen.NewString()
+		return
 	}
+
+	rawConfig, err = info.Codec.Encode(config)
+	if err != nil {
+		return
+	}
+	workerID = m.uuidGen.NewString()
 	return
 }
 
@@ -512,47 +1014,162 @@ func (m *DefaultBaseMaster) CreateWorker(
 	cost model.RescUnit,
 	resources ...resourcemeta.ResourceID,
 ) (libModel.WorkerID, error) {
-	log.L().Info("CreateWorker",
+	return m.createWorker(workerType, config, cost.Vector(), createWorkerOptions{}, resources)
+}
+
+// RecreateWorker implements BaseMaster.RecreateWorker
+func (m *DefaultBaseMaster) RecreateWorker(
+	workerType libModel.WorkerType,
+	config WorkerConfig,
+	cost model.RescUnit,
+	resources ...resourcemeta.ResourceID,
+) (libModel.WorkerID, error) {
+	return m.createWorker(workerType, config, cost.Vector(), createWorkerOptions{failover: true}, resources)
+}
+
+// CreateWorkerWithResources implements BaseMaster.CreateWorkerWithResources
+func (m *DefaultBaseMaster) CreateWorkerWithResources(
+	workerType libModel.WorkerType,
+	config WorkerConfig,
+	required model.RescVector,
+	resources ...resourcemeta.ResourceID,
+) (libModel.WorkerID, error) {
+	return m.createWorker(workerType, config, required, createWorkerOptions{}, resources)
+}
+
+// CreateWorkerQueued implements BaseMaster.CreateWorkerQueued
+func (m *DefaultBaseMaster) CreateWorkerQueued(
+	workerType libModel.WorkerType,
+	config WorkerConfig,
+	required model.RescVector,
+	priority int32,
+	waitTimeout time.Duration,
+	resources ...resourcemeta.ResourceID,
+) (libModel.WorkerID, error) {
+	if waitTimeout <= 0 {
+		waitTimeout = defaultQueuedWorkerWaitTimeout
+	}
+	return m.createWorker(workerType, config, required, createWorkerOptions{
+		queued:      true,
+		priority:    priority,
+		waitTimeout: waitTimeout,
+	}, resources)
+}
+
+// createWorkerOptions are the options of scheduling a worker
+type createWorkerOptions struct {
+	// failover is set if the worker is lost due to executor failure
+	failover bool
+	// queued is set if the request waits in the schedule queue of server
+	// master for at most waitTimeout
+	queued      bool
+	priority    int32
+	waitTimeout time.Duration
+}
+
+func (m *DefaultBaseMaster) createWorker(
+	workerType libModel.WorkerType,
+	config WorkerConfig,
+	required model.RescVector,
+	opts createWorkerOptions,
+	resources []resourcemeta.ResourceID,
+) (libModel.WorkerID, error) {
+	m.Logger().Info("CreateWorker",
 		zap.Int64("worker-type", int64(workerType)),
-		zap.Any("worker-config", config),
-		zap.Int("cost", int(cost)),
+		redact.Any("worker-config", config),
+		zap.Stringer("required", required),
 		zap.Any("resources", resources),
-		zap.String("master-id", m.id))
+		zap.Bool("failover", opts.failover),
+		zap.Bool("queued", opts.queued))
+
+	if !m.dependencyMonitor.healthy() {
+		return "", derror.ErrMasterDependencyUnhealthy.GenWithStackByArgs(
+			m.dependencyMonitor.unhealthyMessage())
+	}
 
 	ctx := m.errCenter.WithCancelOnFirstError(context.Background())
-	quotaCtx, cancel := context.WithTimeout(ctx, createWorkerWaitQuotaTimeout)
-	defer cancel()
-	if err := m.createWorkerQuota.Consume(quotaCtx); err != nil {
-		return "", derror.Wrap(derror.ErrMasterConcurrencyExceeded, err)
+	// a queued request doesn't hold the quota while it waits in the
+	// schedule queue, it takes the quota after it is placed.
+	quotaHeld := false
+	if !opts.queued {
+		if err := m.consumeCreateWorkerQuota(ctx); err != nil {
+			return "", err
+		}
+		quotaHeld = true
+	}
+	releaseQuota := func() {
+		if quotaHeld {
+			m.creatingWorkers.Dec()
+			m.createWorkerQuota.Release()
+		}
 	}
 
 	configBytes, workerID, err := m.prepareWorkerConfig(workerType, config)
 	if err != nil {
+		releaseQuota()
 		return "", err
 	}
+	// a job master belongs to the project in its meta, while a worker
+	// belongs to the project of its master
+	projectID := m.masterMeta.ProjectID
+	if masterMeta, ok := config.(*libModel.MasterMetaKVData); ok {
+		projectID = masterMeta.ProjectID
+	}
 
 	go func() {
-		defer func() {
-			m.createWorkerQuota.Release()
-		}()
+		defer releaseQuota()
 
-		requestCtx, cancel := context.WithTimeout(ctx, createWorkerTimeout)
+		// a queued request waits in the schedule queue before it is placed
+		scheduleTimeout := createWorkerTimeout
+		if opts.queued {
+			scheduleTimeout += opts.waitTimeout
+		}
+		requestCtx, cancel := context.WithTimeout(ctx, scheduleTimeout)
 		defer cancel()
 
-		resp, err := m.serverMasterClient.ScheduleTask(requestCtx, &pb.ScheduleTaskRequest{
+		resp, revoked, done, err := m.scheduleTask(requestCtx, &pb.ScheduleTaskRequest{
 			TaskId:               workerID,
-			Cost:                 int64(cost),
+			JobId:                m.id,
+			Cost:                 int64(required.CPU()),
+			Resources:            required,
 			ResourceRequirements: resources,
-		},
-			// TODO (zixiong) remove this timeout.
-			time.Second*10)
+			Failover:             opts.failover,
+			Queued:               opts.queued,
+			Priority:             opts.priority,
+			WaitTimeoutMs:        opts.waitTimeout.Milliseconds(),
+		})
 		if err != nil {
 			// TODO log the gRPC errors from a lower level such as by an interceptor.
-			log.L().Warn("ScheduleTask returned error", zap.Error(err))
+			m.Logger().Warn("ScheduleTask returned error", zap.Error(err))
 			m.workerManager.AbortCreatingWorker(workerID, err)
 			return
 		}
-		log.L().Debug("ScheduleTask succeeded", zap.Any("response", resp))
+		defer done()
+		if opts.queued {
+			// the placed worker is dispatched in createWorkerTimeout like
+			// the others
+			var cancel context.CancelFunc
+			requestCtx, cancel = context.WithTimeout(ctx, createWorkerTimeout)
+			defer cancel()
+			if err := m.consumeCreateWorkerQuota(requestCtx); err != nil {
+				m.workerManager.AbortCreatingWorker(workerID, err)
+				return
+			}
+			quotaHeld = true
+		}
+		// the dispatching is canceled if the decision is revoked
+		dispatchCtx, cancelDispatch := context.WithCancel(requestCtx)
+		defer cancelDispatch()
+		go func() {
+			select {
+			case <-revoked:
+				m.Logger().Warn("schedule decision is revoked, cancel dispatching",
+					zap.String("worker-id", workerID), zap.String("executor-id", resp.ExecutorId))
+				cancelDispatch()
+			case <-dispatchCtx.Done():
+			}
+		}()
+		m.Logger().Debug("ScheduleTask succeeded", zap.Any("response", resp))
 
 		executorID := model.ExecutorID(resp.ExecutorId)
 
@@ -568,9 +1185,21 @@ func (m *DefaultBaseMaster) CreateWorker(
 			MasterID:     m.id,
 			WorkerType:   int64(workerType),
 			WorkerConfig: configBytes,
+			Resources:    required,
+			ProjectID:    projectID,
+		}
+		if envConfig, ok := config.(WorkerEnvConfig); ok {
+			dispatchArgs.Env = envConfig.WorkerEnv()
+		}
+		if preMountConfig, ok := config.(PreMountConfig); ok {
+			dispatchArgs.ResourceIDs = preMountConfig.PreMountResources()
 		}
 
-		err = executorClient.DispatchTask(requestCtx, dispatchArgs, func() {
+		if err := m.faultInjector.WaitDispatch(dispatchCtx, workerID); err != nil {
+			m.workerManager.AbortCreatingWorker(workerID, err)
+			return
+		}
+		err = executorClient.DispatchTask(dispatchCtx, dispatchArgs, func() {
 			m.workerManager.BeforeStartingWorker(workerID, executorID)
 		}, func(err error) {
 			m.workerManager.AbortCreatingWorker(workerID, err)
@@ -578,19 +1207,121 @@ func (m *DefaultBaseMaster) CreateWorker(
 
 		if err != nil {
 			// All cleaning up should have been done in AbortCreatingWorker.
-			log.L().Info("DispatchTask failed",
+			m.Logger().Info("DispatchTask failed",
 				zap.Error(err))
 			return
 		}
 
-		log.L().Info("Dispatch Worker succeeded",
+		m.Logger().Info("Dispatch Worker succeeded",
 			zap.Any("args", dispatchArgs))
 	}()
 
 	return workerID, nil
 }
 
+// consumeCreateWorkerQuota takes the quota of creating a worker, it waits
+// for createWorkerWaitQuotaTimeout at most.
+func (m *DefaultBaseMaster) consumeCreateWorkerQuota(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, createWorkerWaitQuotaTimeout)
+	defer cancel()
+	if err := m.createWorkerQuota.Consume(ctx); err != nil {
+		return derror.Wrap(derror.ErrMasterConcurrencyExceeded, err)
+	}
+	m.creatingWorkers.Inc()
+	return nil
+}
+
+// scheduleTask schedules the task on the schedule stream, or by ScheduleTask
+// if the stream is not available. revoked is nil if the decision is not made
+// on the stream, done must be called after the task is dispatched.
+// The queued requests are always scheduled by ScheduleTask, which is failed
+// over to the new leader of server master during the long wait.
+func (m *DefaultBaseMaster) scheduleTask(
+	ctx context.Context, req *pb.ScheduleTaskRequest,
+) (resp *pb.ScheduleTaskResponse, revoked <-chan struct{}, done func(), err error) {
+	if !req.GetQueued() {
+		var ok bool
+		resp, revoked, done, ok, err = m.scheduleStream.schedule(ctx, req)
+		if ok {
+			return resp, revoked, done, err
+		}
+	}
+	// TODO (zixiong) remove this timeout.
+	resp, err = m.serverMasterClient.ScheduleTask(ctx, req, time.Second*10)
+	return resp, nil, func() {}, err
+}
+
+// RequestScaleUp implements BaseMaster.RequestScaleUp
+func (m *DefaultBaseMaster) RequestScaleUp(
+	ctx context.Context,
+	currentWorkers, workers int,
+) (granted int, backoff time.Duration, err error) {
+	resp, err := m.serverMasterClient.ScaleUpJob(ctx, &pb.ScaleUpJobRequest{
+		JobId:          m.id,
+		CurrentWorkers: int32(currentWorkers),
+		Workers:        int32(workers),
+	}, scaleUpJobTimeout)
+	if err != nil {
+		return 0, 0, errors.Trace(err)
+	}
+	if resp.Err != nil {
+		return 0, 0, errors.Errorf("scale up job %s: %s", m.id, resp.Err.GetMessage())
+	}
+	granted = int(resp.GetGrantedWorkers())
+	backoff = time.Duration(resp.GetBackoffMs()) * time.Millisecond
+	m.Logger().Info("scale up requested",
+		zap.Int("current-workers", currentWorkers),
+		zap.Int("workers", workers),
+		zap.Int("granted", granted),
+		zap.Duration("backoff", backoff))
+	return granted, backoff, nil
+}
+
+// UpdateWorkerTimeouts adjusts the worker timeouts of the running master,
+// zero durations are left unchanged. The new heartbeat interval and worker
+// timeout are propagated to workers by heartbeat pongs.
+func (m *DefaultBaseMaster) UpdateWorkerTimeouts(
+	workerTimeout, gracefulDuration, heartbeatInterval time.Duration,
+) error {
+	if workerTimeout < 0 || gracefulDuration < 0 || heartbeatInterval < 0 {
+		return derror.ErrInvalidTimeoutConfig.GenWithStackByArgs("negative duration")
+	}
+
+	timeouts := m.workerManager.Timeouts()
+	if workerTimeout > 0 {
+		timeouts.WorkerTimeoutDuration = workerTimeout
+	}
+	if gracefulDuration > 0 {
+		timeouts.WorkerTimeoutGracefulDuration = gracefulDuration
+	}
+	if heartbeatInterval > 0 {
+		timeouts.WorkerHeartbeatInterval = heartbeatInterval
+	}
+	m.workerManager.UpdateTimeouts(timeouts)
+	return nil
+}
+
 // IsMasterReady implements BaseMaster.IsMasterReady
 func (m *DefaultBaseMaster) IsMasterReady() bool {
-	return m.workerManager.IsInitialized()
+	return m.workerManager.IsInitialized() && m.dependencyMonitor.healthy()
+}
+
+// RegisterDependencyChecker implements BaseMaster.RegisterDependencyChecker
+func (m *DefaultBaseMaster) RegisterDependencyChecker(checker DependencyChecker) {
+	m.dependencyMonitor.register(checker)
+}
+
+// DependencyHealth implements BaseMaster.DependencyHealth
+func (m *DefaultBaseMaster) DependencyHealth() []DependencyHealth {
+	return m.dependencyMonitor.list()
+}
+
+// TickStats implements BaseMaster.TickStats
+func (m *DefaultBaseMaster) TickStats() TickStats {
+	return m.tickWatchdog.snapshot()
+}
+
+// WorkerManagerDebugInfo implements BaseMaster.WorkerManagerDebugInfo
+func (m *DefaultBaseMaster) WorkerManagerDebugInfo() *master.DebugInfo {
+	return m.workerManager.DebugInfo()
 }
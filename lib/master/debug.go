@@ -0,0 +1,154 @@
+package master
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/model"
+)
+
+var workerEntryStateNames = map[workerEntryState]string{
+	workerEntryWait:      "wait",
+	workerEntryCreated:   "created",
+	workerEntryNormal:    "normal",
+	workerEntryOffline:   "offline",
+	workerEntryTombstone: "tombstone",
+}
+
+// String implements fmt.Stringer
+func (s workerEntryState) String() string {
+	if name, ok := workerEntryStateNames[s]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+var workerManagerStateNames = map[workerManagerState]string{
+	workerManagerReady:            "ready",
+	workerManagerLoadingMeta:      "loading-meta",
+	workerManagerWaitingHeartbeat: "waiting-heartbeat",
+}
+
+// WorkerDebugInfo is the in-memory state of a worker kept by WorkerManager.
+type WorkerDebugInfo struct {
+	ID              libModel.WorkerID         `json:"id"`
+	ExecutorID      model.ExecutorID          `json:"executor-id"`
+	State           string                    `json:"state"`
+	StatePath       []string                  `json:"state-path"`
+	StatusCode      libModel.WorkerStatusCode `json:"status-code"`
+	ExpireAt        time.Time                 `json:"expire-at"`
+	HeartbeatAt     time.Time                 `json:"heartbeat-at"`
+	Unresponsive    bool                      `json:"unresponsive"`
+	Stuck           bool                      `json:"stuck"`
+	Finished        bool                      `json:"finished"`
+	CreatingWorkers int32                     `json:"creating-workers"`
+	ReplayPending   bool                      `json:"replay-pending"`
+}
+
+// DebugInfo is the in-memory state of a WorkerManager for troubleshooting.
+type DebugInfo struct {
+	MasterID      libModel.MasterID `json:"master-id"`
+	Epoch         libModel.Epoch    `json:"epoch"`
+	PendingEvents int               `json:"pending-events"`
+
+	// Locked is true if the WorkerManager is locked by others, such as a
+	// Tick that is stuck, the following fields are not dumped then. The
+	// holder of the lock is only known if the long hold threshold of
+	// lockdiag is set.
+	Locked      bool      `json:"locked"`
+	LockedAt    time.Time `json:"locked-at,omitempty"`
+	HolderStack string    `json:"holder-stack,omitempty"`
+
+	State   string             `json:"state,omitempty"`
+	Workers []*WorkerDebugInfo `json:"workers,omitempty"`
+}
+
+// DebugInfo dumps the in-memory state of the WorkerManager without waiting
+// for its lock, so that it can be used to diagnose a stuck master.
+func (m *WorkerManager) DebugInfo() *DebugInfo {
+	ret := &DebugInfo{
+		MasterID:      m.masterID,
+		Epoch:         m.epoch,
+		PendingEvents: len(m.eventQueue),
+	}
+	if !m.mu.TryLock() {
+		ret.Locked = true
+		lockedAt, stack := m.mu.Holder()
+		ret.LockedAt = lockedAt
+		ret.HolderStack = string(stack)
+		return ret
+	}
+	defer m.mu.Unlock()
+
+	ret.State = workerManagerStateNames[m.state]
+	ret.Workers = make([]*WorkerDebugInfo, 0, len(m.workerEntries))
+	for workerID, entry := range m.workerEntries {
+		info := &WorkerDebugInfo{
+			ID:              workerID,
+			State:           entry.State().String(),
+			StatePath:       statePathNames(entry.StatePath()),
+			ExpireAt:        entry.ExpireTime(),
+			HeartbeatAt:     entry.HeartbeatTime(),
+			Unresponsive:    entry.IsUnresponsive(),
+			Stuck:           entry.IsStuck(),
+			Finished:        entry.IsFinished(),
+			CreatingWorkers: entry.CreatingWorkers(),
+			ReplayPending:   entry.IsReplayPending(),
+		}
+		entry.mu.Lock()
+		info.ExecutorID = entry.executorID
+		entry.mu.Unlock()
+		if status := entry.Status(); status != nil {
+			info.StatusCode = status.Code
+		}
+		ret.Workers = append(ret.Workers, info)
+	}
+	sort.Slice(ret.Workers, func(i, j int) bool {
+		return ret.Workers[i].ID < ret.Workers[j].ID
+	})
+	return ret
+}
+
+// DebugDump dumps the states of the WorkerManager and its workers as text,
+// one worker per line sorted by worker ID. The timestamps are left out so
+// that the dump is deterministic and can be compared in tests.
+func (m *WorkerManager) DebugDump() string {
+	info := m.DebugInfo()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "master %s epoch %d pending-events %d", info.MasterID, info.Epoch, info.PendingEvents)
+	if info.Locked {
+		b.WriteString(" locked\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, " state %s\n", info.State)
+	for _, w := range info.Workers {
+		fmt.Fprintf(&b, "worker %s executor %s state %s path %s status %d",
+			w.ID, w.ExecutorID, w.State, strings.Join(w.StatePath, "->"), w.StatusCode)
+		if w.Unresponsive {
+			b.WriteString(" unresponsive")
+		}
+		if w.Stuck {
+			b.WriteString(" stuck")
+		}
+		if w.Finished {
+			b.WriteString(" finished")
+		}
+		if w.ReplayPending {
+			b.WriteString(" replay-pending")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func statePathNames(path []workerEntryState) []string {
+	ret := make([]string, 0, len(path))
+	for _, state := range path {
+		ret = append(ret, state.String())
+	}
+	return ret
+}
@@ -9,14 +9,21 @@ const (
 	workerOfflineEvent
 	workerStatusUpdatedEvent
 	workerDispatchFailedEvent
+	workerUnresponsiveEvent
+	recoveryProgressEvent
 )
 
 type beforeHookType = func() (ok bool)
 
 type masterEvent struct {
-	Tp         masterEventType
-	Handle     WorkerHandle
-	WorkerID   libModel.WorkerID
-	Err        error
+	Tp       masterEventType
+	Handle   WorkerHandle
+	WorkerID libModel.WorkerID
+	Err      error
+	// MissedHeartbeats is set for workerUnresponsiveEvent only.
+	MissedHeartbeats int
+	// Hydrated and Total are set for recoveryProgressEvent only.
+	Hydrated   int
+	Total      int
 	beforeHook beforeHookType
 }
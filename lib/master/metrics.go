@@ -0,0 +1,37 @@
+package master
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var workerUnresponsiveCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "dataflow",
+		Subsystem: "master",
+		Name:      "worker_unresponsive_total",
+		Help:      "number of times that a worker missed consecutive heartbeats before timing out",
+	}, []string{"job"})
+
+var heartbeatHandleDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: "dataflow",
+		Subsystem: "master",
+		Name:      "heartbeat_handle_duration_seconds",
+		Help:      "time spent by the worker manager on handling a heartbeat, including waiting for the lock",
+		Buckets:   prometheus.ExponentialBuckets(0.00001, 2, 20), // 10us ~ 5s
+	}, []string{"job"})
+
+var workerEntryTransitionCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "dataflow",
+		Subsystem: "master",
+		Name:      "worker_entry_transitions_total",
+		Help:      "number of transitions of the worker states kept by the worker manager",
+	}, []string{"job", "from", "to"})
+
+// InitMetrics registers the worker manager metrics
+func InitMetrics(registry *prometheus.Registry) {
+	registry.MustRegister(workerUnresponsiveCounter)
+	registry.MustRegister(heartbeatHandleDuration)
+	registry.MustRegister(workerEntryTransitionCounter)
+}
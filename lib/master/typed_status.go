@@ -0,0 +1,108 @@
+package master
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+
+	"github.com/pingcap/errors"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+// StatusCodec decodes the ExtBytes of a worker status into the business
+// status of type T.
+type StatusCodec[T any] func(extBytes []byte) (T, error)
+
+var statusCodecs = struct {
+	sync.RWMutex
+	// statusTypes is the status type of each registered worker type
+	statusTypes map[libModel.WorkerType]reflect.Type
+	// codecs is the StatusCodec of each registered status type
+	codecs map[reflect.Type]interface{}
+}{
+	statusTypes: make(map[libModel.WorkerType]reflect.Type),
+	codecs:      make(map[reflect.Type]interface{}),
+}
+
+// RegisterStatusCodec declares that the statuses of a worker type are decoded
+// into T by codec. It returns false if the worker type has been declared with
+// a different status type. Registering the same worker type repeatedly is
+// allowed.
+// GetTypedStatus finds the codec by T, so worker types sharing a status type,
+// such as the units of a DM task, share the codec registered first.
+func RegisterStatusCodec[T any](tp libModel.WorkerType, codec StatusCodec[T]) (ok bool) {
+	statusType := reflect.TypeOf((*T)(nil)).Elem()
+
+	statusCodecs.Lock()
+	defer statusCodecs.Unlock()
+
+	if old, exists := statusCodecs.statusTypes[tp]; exists {
+		return old == statusType
+	}
+	statusCodecs.statusTypes[tp] = statusType
+	if _, exists := statusCodecs.codecs[statusType]; !exists {
+		statusCodecs.codecs[statusType] = codec
+	}
+	return true
+}
+
+// decodedStatus is the cached value of GetTypedStatus, it is tagged by the
+// status type, as a value of one type may be asserted to another type.
+type decodedStatus struct {
+	statusType reflect.Type
+	value      interface{}
+}
+
+// decodedStatusCache is implemented by the handles managed by WorkerManager,
+// which cache the decoded status in the workerEntry.
+type decodedStatusCache interface {
+	loadDecodedStatus(status *libModel.WorkerStatus) (interface{}, bool)
+	storeDecodedStatus(status *libModel.WorkerStatus, decoded interface{})
+}
+
+// GetTypedStatus returns the business status of a worker decoded from the
+// ExtBytes of its status, by the codec registered for T or by JSON if there
+// is none.
+// The decoded status is cached until the status of the worker is updated, so
+// it is cheap to call in Tick. The caller should not modify the returned value
+// if T is a reference type.
+func GetTypedStatus[T any](handle BaseHandle) (T, error) {
+	var zero T
+	statusType := reflect.TypeOf((*T)(nil)).Elem()
+	status := handle.Status()
+	cache, cacheable := handle.(decodedStatusCache)
+	if cacheable {
+		if cached, ok := cache.loadDecodedStatus(status); ok {
+			if decoded := cached.(decodedStatus); decoded.statusType == statusType {
+				// the value may be a nil interface
+				typed, _ := decoded.value.(T)
+				return typed, nil
+			}
+		}
+	}
+
+	typed, err := decodeStatus[T](statusType, status.ExtBytes)
+	if err != nil {
+		return zero, derror.ErrDecodeWorkerStatus.GenWithStack(
+			"failed to decode status of worker %s: %v", handle.ID(), err)
+	}
+	if cacheable {
+		cache.storeDecodedStatus(status, decodedStatus{statusType: statusType, value: typed})
+	}
+	return typed, nil
+}
+
+func decodeStatus[T any](statusType reflect.Type, extBytes []byte) (T, error) {
+	statusCodecs.RLock()
+	codec, ok := statusCodecs.codecs[statusType]
+	statusCodecs.RUnlock()
+	if ok {
+		return codec.(StatusCodec[T])(extBytes)
+	}
+
+	var ret T
+	err := json.Unmarshal(extBytes, &ret)
+	return ret, errors.Trace(err)
+}
@@ -0,0 +1,99 @@
+package master
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+type jsonTestStatus struct {
+	Progress int `json:"progress"`
+}
+
+type codecTestStatus struct {
+	Progress int
+}
+
+func TestGetTypedStatusCached(t *testing.T) {
+	t.Parallel()
+
+	suite := NewWorkerManageTestSuite(true)
+	suite.manager.BeforeStartingWorker("worker-1", "executor-1")
+	suite.SimulateHeartbeat("worker-1", 1, "executor-1", false)
+	event := suite.WaitForEvent(t, "worker-1")
+	require.Equal(t, workerOnlineEvent, event.Tp)
+
+	err := suite.SimulateWorkerUpdateStatus("worker-1", &libModel.WorkerStatus{
+		Code:     libModel.WorkerStatusNormal,
+		ExtBytes: []byte(`{"progress": 1}`),
+	}, 1)
+	require.NoError(t, err)
+	event = suite.WaitForEvent(t, "worker-1")
+	require.Equal(t, workerStatusUpdatedEvent, event.Tp)
+
+	status, err := GetTypedStatus[*jsonTestStatus](event.Handle)
+	require.NoError(t, err)
+	require.Equal(t, &jsonTestStatus{Progress: 1}, status)
+	// the decoded status is cached until the status is updated
+	cached, err := GetTypedStatus[*jsonTestStatus](event.Handle)
+	require.NoError(t, err)
+	require.Same(t, status, cached)
+
+	err = suite.SimulateWorkerUpdateStatus("worker-1", &libModel.WorkerStatus{
+		Code:     libModel.WorkerStatusNormal,
+		ExtBytes: []byte(`{"progress": 2}`),
+	}, 1)
+	require.NoError(t, err)
+	event = suite.WaitForEvent(t, "worker-1")
+	require.Equal(t, workerStatusUpdatedEvent, event.Tp)
+	status, err = GetTypedStatus[*jsonTestStatus](event.Handle)
+	require.NoError(t, err)
+	require.Equal(t, &jsonTestStatus{Progress: 2}, status)
+
+	err = suite.SimulateWorkerUpdateStatus("worker-1", &libModel.WorkerStatus{
+		Code:     libModel.WorkerStatusNormal,
+		ExtBytes: []byte(`{"progress"`),
+	}, 1)
+	require.NoError(t, err)
+	event = suite.WaitForEvent(t, "worker-1")
+	require.Equal(t, workerStatusUpdatedEvent, event.Tp)
+	_, err = GetTypedStatus[*jsonTestStatus](event.Handle)
+	require.True(t, derror.ErrDecodeWorkerStatus.Equal(err))
+	suite.Close()
+}
+
+func TestGetTypedStatusWithCodec(t *testing.T) {
+	t.Parallel()
+
+	var decodeCount atomic.Int32
+	codec := func(extBytes []byte) (codecTestStatus, error) {
+		decodeCount.Inc()
+		progress, err := strconv.Atoi(string(extBytes))
+		return codecTestStatus{Progress: progress}, err
+	}
+	require.True(t, RegisterStatusCodec[codecTestStatus](1001, codec))
+	require.True(t, RegisterStatusCodec[codecTestStatus](1001, codec))
+	// the worker types sharing a status type share the codec
+	require.True(t, RegisterStatusCodec[codecTestStatus](1002, codec))
+	require.False(t, RegisterStatusCodec[*jsonTestStatus](1001, nil))
+
+	handle := &MockHandle{
+		WorkerID: "worker-1",
+		WorkerStatus: &libModel.WorkerStatus{
+			Code:     libModel.WorkerStatusNormal,
+			ExtBytes: []byte("3"),
+		},
+	}
+	status, err := GetTypedStatus[codecTestStatus](handle)
+	require.NoError(t, err)
+	require.Equal(t, codecTestStatus{Progress: 3}, status)
+	// MockHandle doesn't cache the decoded status
+	_, err = GetTypedStatus[codecTestStatus](handle)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), decodeCount.Load())
+}
@@ -27,7 +27,10 @@ const (
 )
 
 // The following is the state-transition diagram.
-// Refer to ../doc/worker_entry_fsm.puml for a UML version.
+// Refer to ../doc/worker_entry_fsm.puml for a UML version,
+// and workerEntryTransitions for the table of the transitions.
+// In addition, a finished worker can be marked as tombstone
+// in any state.
 //
 // workerEntryCreated            workerEntryWait
 //      │  │                            │  │
@@ -53,11 +56,37 @@ type workerEntry struct {
 	mu       sync.Mutex
 	expireAt time.Time
 	state    workerEntryState
+	// path is the states the worker has been in, including the current one.
+	path []workerEntryState
+	// onTransition is called after each transition of state if it is set.
+	onTransition workerEntryTransitionHook
+	// heartbeatAt is the time of the last heartbeat, or the time the worker
+	// is created if no heartbeat has been received.
+	heartbeatAt time.Time
+	// unresponsive is true if the worker has been reported unresponsive
+	// since the last heartbeat.
+	unresponsive bool
 
 	receivedFinish atomic.Bool
+	// stuck is true if the worker reported its Tick as stuck in the last
+	// heartbeat.
+	stuck atomic.Bool
+	// creatingWorkers is the number of workers being created by the worker
+	// as reported in its last heartbeat.
+	creatingWorkers atomic.Int32
 
 	statusMu sync.RWMutex
 	status   *libModel.WorkerStatus
+	// statusSeq is the sequence number of status, zero if it is unknown.
+	statusSeq uint64
+	// replaySeq is the sequence number of the latest status update to be
+	// replayed by the worker after master failover, zero if no replay is
+	// pending.
+	replaySeq uint64
+	// decodedStatus is the business status decoded from decodedFrom, which
+	// is a revision of status, see GetTypedStatus.
+	decodedFrom   *libModel.WorkerStatus
+	decodedStatus interface{}
 }
 
 func newWorkerEntry(
@@ -72,6 +101,7 @@ func newWorkerEntry(
 		executorID: executorID,
 		expireAt:   expireAt,
 		state:      state,
+		path:       []workerEntryState{state},
 		status:     initWorkerStatus,
 	}
 }
@@ -85,7 +115,7 @@ func newWaitingWorkerEntry(
 
 // String implements fmt.Stringer, note the implementation is not thread safe
 func (e *workerEntry) String() string {
-	return fmt.Sprintf("{worker-id:%s, executor-id:%s, state:%d}",
+	return fmt.Sprintf("{worker-id:%s, executor-id:%s, state:%s}",
 		e.id, e.executorID, e.state)
 }
 
@@ -96,18 +126,48 @@ func (e *workerEntry) State() workerEntryState {
 	return e.state
 }
 
-func (e *workerEntry) MarkAsTombstone() {
+// SetTransitionHook sets the hook called after each transition of state.
+func (e *workerEntry) SetTransitionHook(hook workerEntryTransitionHook) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	if e.state == workerEntryWait || e.state == workerEntryOffline || e.IsFinished() {
-		// Only workerEntryWait and workerEntryOffline are allowed
-		// to transition to workerEntryTombstone.
-		e.state = workerEntryTombstone
-		return
+	e.onTransition = hook
+}
+
+// StatePath returns the states the worker has been in, in order.
+func (e *workerEntry) StatePath() []workerEntryState {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return append([]workerEntryState(nil), e.path...)
+}
+
+// transition moves the worker to state to, see workerEntryTransitions for the
+// legal transitions. onTransit, if not nil, is called with e.mu held after the
+// state is changed. An illegal transition is a bug of WorkerManager, so it
+// panics.
+func (e *workerEntry) transition(to workerEntryState, onTransit func()) {
+	e.mu.Lock()
+	from := e.state
+	if err := checkWorkerEntryTransition(e.id, from, to, e.IsFinished()); err != nil {
+		e.mu.Unlock()
+		log.L().Panic("Unreachable", zap.Stringer("entry", e), zap.Error(err))
+	}
+	e.state = to
+	e.path = append(e.path, to)
+	if onTransit != nil {
+		onTransit()
+	}
+	hook := e.onTransition
+	e.mu.Unlock()
+
+	if hook != nil {
+		hook(e.id, from, to)
 	}
+}
 
-	log.L().Panic("Unreachable", zap.Stringer("entry", e))
+func (e *workerEntry) MarkAsTombstone() {
+	e.transition(workerEntryTombstone, nil)
 }
 
 func (e *workerEntry) IsTombstone() bool {
@@ -117,30 +177,23 @@ func (e *workerEntry) IsTombstone() bool {
 	return e.state == workerEntryTombstone
 }
 
-func (e *workerEntry) MarkAsOnline(executor model.ExecutorID, expireAt time.Time) {
+// ExecutorID returns the executor that the worker is running on
+func (e *workerEntry) ExecutorID() model.ExecutorID {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	if e.state == workerEntryCreated || e.state == workerEntryWait {
-		e.state = workerEntryNormal
+	return e.executorID
+}
+
+func (e *workerEntry) MarkAsOnline(executor model.ExecutorID, expireAt time.Time) {
+	e.transition(workerEntryNormal, func() {
 		e.expireAt = expireAt
 		e.executorID = executor
-		return
-	}
-
-	log.L().Panic("Unreachable", zap.Stringer("entry", e))
+	})
 }
 
 func (e *workerEntry) MarkAsOffline() {
-	e.mu.Lock()
-	defer e.mu.Unlock()
-
-	if e.state == workerEntryCreated || e.state == workerEntryNormal {
-		e.state = workerEntryOffline
-		return
-	}
-
-	log.L().Panic("Unreachable", zap.Stringer("entry", e))
+	e.transition(workerEntryOffline, nil)
 }
 
 func (e *workerEntry) Status() *libModel.WorkerStatus {
@@ -157,6 +210,78 @@ func (e *workerEntry) UpdateStatus(status *libModel.WorkerStatus) {
 	e.status = status
 }
 
+// HydrateStatus replaces indexStatus, which only has the status code loaded
+// from the worker index, with the full persisted status. It is ignored if the
+// status has been updated by the worker since.
+func (e *workerEntry) HydrateStatus(indexStatus, status *libModel.WorkerStatus) {
+	e.statusMu.Lock()
+	defer e.statusMu.Unlock()
+
+	if e.status == indexStatus {
+		e.status = status
+	}
+}
+
+// DecodedStatus returns the decoded business status of status, if it has been
+// decoded and status is the current status.
+func (e *workerEntry) DecodedStatus(status *libModel.WorkerStatus) (interface{}, bool) {
+	e.statusMu.RLock()
+	defer e.statusMu.RUnlock()
+
+	if e.decodedFrom != status || e.status != status {
+		return nil, false
+	}
+	return e.decodedStatus, true
+}
+
+// SetDecodedStatus caches the decoded business status of status, it is
+// ignored if status is no longer the current status.
+func (e *workerEntry) SetDecodedStatus(status *libModel.WorkerStatus, decoded interface{}) {
+	e.statusMu.Lock()
+	defer e.statusMu.Unlock()
+
+	if e.status != status {
+		return
+	}
+	e.decodedFrom = status
+	e.decodedStatus = decoded
+}
+
+// UpdateStatusWithSeq updates the status if seq is newer than the current
+// one, or if any of them is unknown. It returns false if the status is stale.
+func (e *workerEntry) UpdateStatusWithSeq(status *libModel.WorkerStatus, seq uint64) bool {
+	e.statusMu.Lock()
+	defer e.statusMu.Unlock()
+
+	if seq != 0 && seq <= e.statusSeq {
+		return false
+	}
+	e.status = status
+	e.statusSeq = seq
+	if seq >= e.replaySeq {
+		e.replaySeq = 0
+	}
+	return true
+}
+
+// SetReplaySeq records that the status updates up to seq are to be replayed.
+func (e *workerEntry) SetReplaySeq(seq uint64) {
+	e.statusMu.Lock()
+	defer e.statusMu.Unlock()
+
+	if seq > e.statusSeq {
+		e.replaySeq = seq
+	}
+}
+
+// IsReplayPending returns whether a replay of status updates is pending.
+func (e *workerEntry) IsReplayPending() bool {
+	e.statusMu.RLock()
+	defer e.statusMu.RUnlock()
+
+	return e.replaySeq != 0
+}
+
 func (e *workerEntry) SetExpireTime(expireAt time.Time) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
@@ -178,3 +303,54 @@ func (e *workerEntry) SetFinished() {
 func (e *workerEntry) IsFinished() bool {
 	return e.receivedFinish.Load()
 }
+
+func (e *workerEntry) SetStuck(stuck bool) {
+	e.stuck.Store(stuck)
+}
+
+func (e *workerEntry) IsStuck() bool {
+	return e.stuck.Load()
+}
+
+func (e *workerEntry) SetCreatingWorkers(n int32) {
+	e.creatingWorkers.Store(n)
+}
+
+func (e *workerEntry) CreatingWorkers() int32 {
+	return e.creatingWorkers.Load()
+}
+
+func (e *workerEntry) SetHeartbeatTime(heartbeatAt time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.heartbeatAt = heartbeatAt
+	e.unresponsive = false
+}
+
+func (e *workerEntry) HeartbeatTime() time.Time {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.heartbeatAt
+}
+
+// MarkAsUnresponsive returns false if the worker has been marked as
+// unresponsive since the last heartbeat.
+func (e *workerEntry) MarkAsUnresponsive() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.unresponsive {
+		return false
+	}
+	e.unresponsive = true
+	return true
+}
+
+func (e *workerEntry) IsUnresponsive() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.unresponsive
+}
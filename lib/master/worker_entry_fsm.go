@@ -0,0 +1,55 @@
+package master
+
+import (
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+// workerEntryTransition is a transition of the state of a worker entry.
+type workerEntryTransition struct {
+	from workerEntryState
+	to   workerEntryState
+}
+
+// workerEntryTransitions is the table of the legal transitions of a worker
+// entry, mapped to the events triggering them. It must be kept consistent
+// with the diagram in worker_entry.go.
+var workerEntryTransitions = map[workerEntryTransition]string{
+	{workerEntryCreated, workerEntryNormal}:    "heartbeat",
+	{workerEntryWait, workerEntryNormal}:       "heartbeat",
+	{workerEntryCreated, workerEntryOffline}:   "timeout",
+	{workerEntryNormal, workerEntryOffline}:    "timeout",
+	{workerEntryWait, workerEntryTombstone}:    "timeout",
+	{workerEntryOffline, workerEntryTombstone}: "callback",
+}
+
+// workerEntryTransitionHook is called after the state of a worker entry has
+// transitioned, without holding the lock of the entry.
+type workerEntryTransitionHook func(workerID libModel.WorkerID, from, to workerEntryState)
+
+// checkWorkerEntryTransition returns an error if the worker is not allowed to
+// transition from one state to another. A worker that has reported its
+// finish can be marked as tombstone in any state, since no heartbeat will
+// be sent by it.
+func checkWorkerEntryTransition(
+	workerID libModel.WorkerID,
+	from, to workerEntryState,
+	finished bool,
+) error {
+	if _, ok := workerEntryTransitions[workerEntryTransition{from, to}]; ok {
+		return nil
+	}
+	if finished && to == workerEntryTombstone {
+		return nil
+	}
+	return derror.ErrWorkerIllegalTransition.GenWithStackByArgs(workerID, from, to)
+}
+
+// workerEntryTransitionEvent returns the event that triggers the transition,
+// which is "finish" for the transitions only allowed for finished workers.
+func workerEntryTransitionEvent(from, to workerEntryState) string {
+	if event, ok := workerEntryTransitions[workerEntryTransition{from, to}]; ok {
+		return event
+	}
+	return "finish"
+}
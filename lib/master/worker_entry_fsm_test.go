@@ -0,0 +1,98 @@
+package master
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+func TestCheckWorkerEntryTransition(t *testing.T) {
+	t.Parallel()
+
+	states := []workerEntryState{
+		workerEntryWait,
+		workerEntryCreated,
+		workerEntryNormal,
+		workerEntryOffline,
+		workerEntryTombstone,
+	}
+	legal := map[workerEntryTransition]struct{}{
+		{workerEntryCreated, workerEntryNormal}:    {},
+		{workerEntryCreated, workerEntryOffline}:   {},
+		{workerEntryWait, workerEntryNormal}:       {},
+		{workerEntryWait, workerEntryTombstone}:    {},
+		{workerEntryNormal, workerEntryOffline}:    {},
+		{workerEntryOffline, workerEntryTombstone}: {},
+	}
+	for _, from := range states {
+		for _, to := range states {
+			err := checkWorkerEntryTransition("worker-1", from, to, false)
+			if _, ok := legal[workerEntryTransition{from, to}]; ok {
+				require.NoError(t, err, "from %s to %s", from, to)
+				continue
+			}
+			require.Error(t, err, "from %s to %s", from, to)
+			require.True(t, derror.ErrWorkerIllegalTransition.Equal(err))
+
+			// a finished worker can be marked as tombstone in any state
+			err = checkWorkerEntryTransition("worker-1", from, to, true)
+			if to == workerEntryTombstone {
+				require.NoError(t, err, "from %s to %s", from, to)
+				require.Equal(t, "finish", workerEntryTransitionEvent(from, to))
+			} else {
+				require.Error(t, err, "from %s to %s", from, to)
+			}
+		}
+	}
+}
+
+func TestWorkerEntryTransition(t *testing.T) {
+	t.Parallel()
+
+	type transition struct {
+		workerID libModel.WorkerID
+		from, to workerEntryState
+	}
+	var transitions []transition
+	entry := newWorkerEntry("worker-1", "executor-1", time.Time{}, workerEntryCreated, nil)
+	entry.SetTransitionHook(func(workerID libModel.WorkerID, from, to workerEntryState) {
+		// the lock of the entry is not held by the hook
+		require.Equal(t, to, entry.State())
+		transitions = append(transitions, transition{workerID, from, to})
+	})
+
+	expireAt := time.Now()
+	entry.MarkAsOnline("executor-2", expireAt)
+	require.Equal(t, "executor-2", string(entry.ExecutorID()))
+	require.Equal(t, expireAt, entry.ExpireTime())
+	entry.MarkAsOffline()
+	entry.MarkAsTombstone()
+	require.True(t, entry.IsTombstone())
+	require.Equal(t, []workerEntryState{
+		workerEntryCreated, workerEntryNormal, workerEntryOffline, workerEntryTombstone,
+	}, entry.StatePath())
+	require.Equal(t, []transition{
+		{"worker-1", workerEntryCreated, workerEntryNormal},
+		{"worker-1", workerEntryNormal, workerEntryOffline},
+		{"worker-1", workerEntryOffline, workerEntryTombstone},
+	}, transitions)
+
+	// illegal transitions panic and leave the state unchanged
+	require.Panics(t, func() {
+		entry.MarkAsOnline("executor-1", expireAt)
+	})
+	require.True(t, entry.IsTombstone())
+	require.Len(t, transitions, 3)
+
+	entry = newWaitingWorkerEntry("worker-2", nil)
+	require.Panics(t, func() {
+		entry.MarkAsOffline()
+	})
+	entry.SetFinished()
+	entry.MarkAsTombstone()
+	require.Equal(t, []workerEntryState{workerEntryWait, workerEntryTombstone}, entry.StatePath())
+}
@@ -3,7 +3,6 @@ package master
 import (
 	"context"
 
-	"github.com/pingcap/tiflow/dm/pkg/log"
 	"go.uber.org/zap"
 
 	libModel "github.com/hanfei1991/microcosm/lib/model"
@@ -63,12 +62,34 @@ func (h *runningHandleImpl) Status() *libModel.WorkerStatus {
 
 	entry, exists := h.manager.workerEntries[h.workerID]
 	if !exists {
-		log.L().Panic("Using a stale handle", zap.String("worker-id", h.workerID))
+		h.manager.logger.Panic("Using a stale handle", zap.String("worker-id", h.workerID))
 	}
 
 	return entry.Status()
 }
 
+func (h *runningHandleImpl) creatingWorkers() int32 {
+	entry, exists := h.manager.getEntry(h.workerID)
+	if !exists {
+		return 0
+	}
+	return entry.CreatingWorkers()
+}
+
+func (h *runningHandleImpl) loadDecodedStatus(status *libModel.WorkerStatus) (interface{}, bool) {
+	entry, exists := h.manager.getEntry(h.workerID)
+	if !exists {
+		return nil, false
+	}
+	return entry.DecodedStatus(status)
+}
+
+func (h *runningHandleImpl) storeDecodedStatus(status *libModel.WorkerStatus, decoded interface{}) {
+	if entry, exists := h.manager.getEntry(h.workerID); exists {
+		entry.SetDecodedStatus(status, decoded)
+	}
+}
+
 func (h *runningHandleImpl) ID() libModel.WorkerID {
 	return h.workerID
 }
@@ -88,9 +109,10 @@ func (h *runningHandleImpl) ToPB() (*pb.WorkerInfo, error) {
 	}
 
 	ret := &pb.WorkerInfo{
-		Id:         h.workerID,
-		ExecutorId: string(h.executorID),
-		Status:     statusBytes,
+		Id:              h.workerID,
+		ExecutorId:      string(h.executorID),
+		Status:          statusBytes,
+		CreatingWorkers: h.creatingWorkers(),
 	}
 	return ret, nil
 }
@@ -122,12 +144,26 @@ func (h *tombstoneHandleImpl) Status() *libModel.WorkerStatus {
 
 	entry, exists := h.manager.workerEntries[h.workerID]
 	if !exists {
-		log.L().Panic("Using a stale handle", zap.String("worker-id", h.workerID))
+		h.manager.logger.Panic("Using a stale handle", zap.String("worker-id", h.workerID))
 	}
 
 	return entry.Status()
 }
 
+func (h *tombstoneHandleImpl) loadDecodedStatus(status *libModel.WorkerStatus) (interface{}, bool) {
+	entry, exists := h.manager.getEntry(h.workerID)
+	if !exists {
+		return nil, false
+	}
+	return entry.DecodedStatus(status)
+}
+
+func (h *tombstoneHandleImpl) storeDecodedStatus(status *libModel.WorkerStatus, decoded interface{}) {
+	if entry, exists := h.manager.getEntry(h.workerID); exists {
+		entry.SetDecodedStatus(status, decoded)
+	}
+}
+
 func (h *tombstoneHandleImpl) ID() libModel.WorkerID {
 	return h.workerID
 }
@@ -150,11 +186,11 @@ func (h *tombstoneHandleImpl) CleanTombstone(ctx context.Context) error {
 		return err
 	}
 	if !ok {
-		log.L().Info("Tombstone already cleaned", zap.String("worker-id", h.workerID))
+		h.manager.logger.Info("Tombstone already cleaned", zap.String("worker-id", h.workerID))
 		// Idempotent for robustness.
 		return nil
 	}
-	log.L().Info("Worker tombstone is cleaned", zap.String("worker-id", h.workerID))
+	h.manager.logger.Info("Worker tombstone is cleaned", zap.String("worker-id", h.workerID))
 	h.manager.removeTombstoneEntry(h.workerID)
 
 	return nil
@@ -2,9 +2,11 @@ package master
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 
+	bclock "github.com/benbjohnson/clock"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tiflow/dm/pkg/log"
 	"go.uber.org/zap"
@@ -17,6 +19,9 @@ import (
 	"github.com/hanfei1991/microcosm/pkg/clock"
 	"github.com/hanfei1991/microcosm/pkg/errctx"
 	derror "github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/faultinject"
+	"github.com/hanfei1991/microcosm/pkg/lockdiag"
+	"github.com/hanfei1991/microcosm/pkg/logutil"
 	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
 	"github.com/hanfei1991/microcosm/pkg/p2p"
 )
@@ -26,11 +31,19 @@ type (
 	Callback = func(ctx context.Context, handle WorkerHandle) error
 	// CallbackWithError alias to worker callback function when there could be an error along with.
 	CallbackWithError = func(ctx context.Context, handle WorkerHandle, err error) error
+	// UnresponsiveCallback alias to worker callback function when a worker
+	// has missed consecutive heartbeats.
+	UnresponsiveCallback = func(ctx context.Context, handle WorkerHandle, missedHeartbeats int) error
+	// RecoveryProgressCallback alias to the callback function of the progress
+	// of hydrating worker statuses after an incremental recovery.
+	RecoveryProgressCallback = func(ctx context.Context, hydrated, total int) error
 )
 
 // WorkerManager manages all workers belonging to a job master
 type WorkerManager struct {
-	mu            sync.Mutex
+	// mu is instrumented since lock-order issues among heartbeat handlers,
+	// Tick and Close are hard to find otherwise.
+	mu            *lockdiag.Mutex
 	workerEntries map[libModel.WorkerID]*workerEntry
 	state         workerManagerState
 
@@ -39,11 +52,19 @@ type WorkerManager struct {
 
 	masterID libModel.MasterID
 	epoch    libModel.Epoch
+	logger   log.Logger
 
 	onWorkerOnlined       Callback
 	onWorkerOfflined      CallbackWithError
 	onWorkerStatusUpdated Callback
 	onWorkerDispatched    CallbackWithError
+	onWorkerUnresponsive  UnresponsiveCallback
+	// onRecoveryProgress is set by InitAfterRecoverIncrementally.
+	onRecoveryProgress RecoveryProgressCallback
+	// recoveryResult is set when the recovery after failover is done.
+	recoveryResult *RecoveryResult
+	// fastFailover is set by EnableFastFailover.
+	fastFailover bool
 
 	eventQueue chan *masterEvent
 	closeCh    chan struct{}
@@ -56,6 +77,9 @@ type WorkerManager struct {
 
 	timeouts config.TimeoutConfig
 
+	// faultInjector is nil unless faults are injected by tests.
+	faultInjector *faultinject.Injector
+
 	wg sync.WaitGroup
 }
 
@@ -67,6 +91,31 @@ const (
 	workerManagerWaitingHeartbeat
 )
 
+// RecoveryResult describes the workers recovered by WorkerManager after
+// master failover.
+type RecoveryResult struct {
+	// Reconnected are the workers that have sent heartbeats in time.
+	Reconnected []libModel.WorkerID
+	// Tombstoned are the workers that have not sent heartbeats in time, or
+	// have finished, they are offline and can be re-created.
+	Tombstoned []libModel.WorkerID
+	// Finished are the workers that had finished before failover, they are
+	// not managed by WorkerManager any more.
+	Finished []libModel.WorkerID
+	// Suspects are the workers not confirmed yet in a fast failover, see
+	// EnableFastFailover.
+	Suspects []libModel.WorkerID
+	// WaitDuration is the time spent on waiting for the heartbeats.
+	WaitDuration time.Duration
+}
+
+// requestStatusReplayTimeout is the timeout of sending a status replay request.
+const requestStatusReplayTimeout = time.Second
+
+// hydrateBatchSize is the number of worker statuses loaded in a batch after
+// an incremental recovery.
+const hydrateBatchSize = 1000
+
 // NewWorkerManager creates a new WorkerManager instance
 func NewWorkerManager(
 	masterID libModel.MasterID,
@@ -77,16 +126,20 @@ func NewWorkerManager(
 	onWorkerOffline CallbackWithError,
 	onWorkerStatusUpdated Callback,
 	onWorkerDispatched CallbackWithError,
+	onWorkerUnresponsive UnresponsiveCallback,
 	isInit bool,
 	timeoutConfig config.TimeoutConfig,
 	clock clock.Clock,
+	faultInjector *faultinject.Injector,
 ) *WorkerManager {
 	state := workerManagerReady
 	if !isInit {
 		state = workerManagerLoadingMeta
 	}
+	logger := logutil.WithEpoch(logutil.WithJobID(log.L(), masterID), epoch)
 
 	ret := &WorkerManager{
+		mu:            lockdiag.NewMutex("worker-manager", logger),
 		workerEntries: make(map[libModel.WorkerID]*workerEntry),
 		state:         state,
 
@@ -95,11 +148,13 @@ func NewWorkerManager(
 
 		masterID: masterID,
 		epoch:    epoch,
+		logger:   logger,
 
 		onWorkerOnlined:       onWorkerOnline,
 		onWorkerOfflined:      onWorkerOffline,
 		onWorkerStatusUpdated: onWorkerStatusUpdated,
 		onWorkerDispatched:    onWorkerDispatched,
+		onWorkerUnresponsive:  onWorkerUnresponsive,
 
 		eventQueue:      make(chan *masterEvent, 1024),
 		closeCh:         make(chan struct{}),
@@ -108,13 +163,18 @@ func NewWorkerManager(
 
 		clock:    clock,
 		timeouts: timeoutConfig,
+
+		faultInjector: faultInjector,
 	}
 
+	// The ticker is created before the goroutine starts, so that no tick is
+	// missed by a mock clock.
+	ticker := clock.Ticker(timeoutConfig.MasterHeartbeatCheckLoopInterval)
 	ret.wg.Add(1)
 	go func() {
 		defer ret.wg.Done()
-		if err := ret.runBackgroundChecker(); err != nil {
-			ret.errCenter.OnError(err)
+		if err := ret.runBackgroundChecker(ticker); err != nil {
+			ret.onError(err)
 		}
 	}()
 
@@ -124,25 +184,31 @@ func NewWorkerManager(
 // Close closes the WorkerManager and waits all resource released.
 func (m *WorkerManager) Close() {
 	close(m.closeCh)
-	m.wg.Wait()
+	lockdiag.WatchBlocking("worker-manager-close", m.logger, m.wg.Wait)
 }
 
-// InitAfterRecover should be called after the master has failed over.
-// This method will block until a timeout period for heartbeats has passed.
-func (m *WorkerManager) InitAfterRecover(ctx context.Context) (retErr error) {
-	defer func() {
-		if retErr != nil {
-			m.errCenter.OnError(retErr)
-		}
-	}()
+// EnableFastFailover makes InitAfterRecover return without waiting for the
+// heartbeats of the workers. The workers recorded in meta are suspects until
+// they are reconciled asynchronously: the first heartbeat of a suspect brings
+// it online as if it is just created, and a suspect that sends no heartbeat
+// in the worker timeout goes offline. It must be called before
+// InitAfterRecover.
+func (m *WorkerManager) EnableFastFailover() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-	ctx = m.errCenter.WithCancelOnFirstError(ctx)
+	m.fastFailover = true
+}
 
+// InitAfterRecover should be called after the master has failed over.
+// This method will block until a timeout period for heartbeats has passed,
+// unless EnableFastFailover has been called.
+func (m *WorkerManager) InitAfterRecover(ctx context.Context) error {
 	m.mu.Lock()
 	if m.state != workerManagerLoadingMeta {
 		// InitAfterRecover should only be called if
 		// NewWorkerManager has been called with isInit as false.
-		log.L().Panic("Unreachable", zap.String("master-id", m.masterID))
+		m.logger.Panic("Unreachable")
 	}
 
 	// Unlock here because loading meta involves I/O, which can be long.
@@ -150,31 +216,135 @@ func (m *WorkerManager) InitAfterRecover(ctx context.Context) (retErr error) {
 
 	allPersistedWorkers, err := m.workerMetaClient.LoadAllWorkers(ctx)
 	if err != nil {
+		m.onError(err)
 		return err
 	}
 
+	return m.InitAfterRecoverWithWorkers(ctx, allPersistedWorkers)
+}
+
+// InitAfterRecoverWithWorkers works like InitAfterRecover, but uses the
+// given persisted workers instead of loading them from metastore. It is used
+// when the workers are loaded in the same snapshot as the master's metadata.
+func (m *WorkerManager) InitAfterRecoverWithWorkers(
+	ctx context.Context,
+	allPersistedWorkers map[libModel.WorkerID]*libModel.WorkerStatus,
+) error {
+	return m.initAfterRecover(ctx, allPersistedWorkers, nil)
+}
+
+// InitAfterRecoverIncrementally works like InitAfterRecover, but only loads
+// the ids and status codes of the workers before waiting for heartbeats, which
+// is much faster for a master with lots of workers. The full statuses are
+// hydrated in the background in batches, and onProgress is called in Tick
+// after each batch, the last call of which has hydrated equal to total.
+// Before its status is hydrated, the Status of a worker handle only has the
+// status code, unless the worker has updated its status since.
+func (m *WorkerManager) InitAfterRecoverIncrementally(
+	ctx context.Context, onProgress RecoveryProgressCallback,
+) error {
+	m.mu.Lock()
+	if m.state != workerManagerLoadingMeta {
+		m.logger.Panic("Unreachable")
+	}
+	m.onRecoveryProgress = onProgress
+	m.mu.Unlock()
+
+	index, err := m.workerMetaClient.LoadWorkerIndex(ctx)
+	if err != nil {
+		m.onError(err)
+		return err
+	}
+
+	indexStatuses := make(map[libModel.WorkerID]*libModel.WorkerStatus, len(index))
+	toHydrate := make([]libModel.WorkerID, 0, len(index))
+	for workerID, code := range index {
+		indexStatuses[workerID] = &libModel.WorkerStatus{
+			JobID: m.masterID,
+			ID:    workerID,
+			Code:  code,
+		}
+		if code != libModel.WorkerStatusFinished {
+			toHydrate = append(toHydrate, workerID)
+		}
+	}
+
+	return m.initAfterRecover(ctx, indexStatuses, func() {
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			if err := m.hydrateStatuses(indexStatuses, toHydrate); err != nil {
+				m.onError(err)
+			}
+		}()
+	})
+}
+
+// initAfterRecover recovers the worker entries from the persisted workers,
+// afterLoaded is called if it is not nil after the entries are created and
+// before waiting for heartbeats.
+func (m *WorkerManager) initAfterRecover(
+	ctx context.Context,
+	allPersistedWorkers map[libModel.WorkerID]*libModel.WorkerStatus,
+	afterLoaded func(),
+) (retErr error) {
+	defer func() {
+		if retErr != nil {
+			m.onError(retErr)
+		}
+	}()
+
+	ctx = m.errCenter.WithCancelOnFirstError(ctx)
+
+	result := &RecoveryResult{}
 	m.mu.Lock()
+	if m.state != workerManagerLoadingMeta {
+		m.logger.Panic("Unreachable")
+	}
 	for workerID, status := range allPersistedWorkers {
 		entry := newWaitingWorkerEntry(workerID, status)
+		entry.SetTransitionHook(m.onWorkerEntryTransition)
 		// TODO: refine mapping from worker status to worker entry state
 		if status.Code == libModel.WorkerStatusFinished {
+			result.Finished = append(result.Finished, workerID)
 			continue
 		}
 		m.workerEntries[workerID] = entry
 	}
+	sort.Strings(result.Finished)
+	if afterLoaded != nil {
+		afterLoaded()
+	}
 
 	if len(m.workerEntries) == 0 {
 		// Fast path when there is no active worker.
 		m.state = workerManagerReady
+		m.recoveryResult = result
 		m.mu.Unlock()
 		return nil
 	}
 
+	timeoutInterval := m.timeouts.WorkerTimeoutDuration + m.timeouts.WorkerTimeoutGracefulDuration
+	if m.fastFailover {
+		// The suspects time out like the workers just created.
+		expireAt := m.clock.Now().Add(timeoutInterval)
+		for workerID, entry := range m.workerEntries {
+			entry.SetExpireTime(expireAt)
+			result.Suspects = append(result.Suspects, workerID)
+		}
+		sort.Strings(result.Suspects)
+		m.state = workerManagerReady
+		m.recoveryResult = result
+		m.mu.Unlock()
+
+		m.logger.Info("Master fails over fast, workers are reconciled as their heartbeats arrive",
+			zap.Int("suspect-workers", len(result.Suspects)))
+		return nil
+	}
+
 	m.state = workerManagerWaitingHeartbeat
 	m.mu.Unlock()
 
-	timeoutInterval := m.timeouts.WorkerTimeoutDuration + m.timeouts.WorkerTimeoutGracefulDuration
-
 	timer := m.clock.Timer(timeoutInterval)
 	defer timer.Stop()
 
@@ -183,26 +353,106 @@ func (m *WorkerManager) InitAfterRecover(ctx context.Context) (retErr error) {
 	case <-ctx.Done():
 		return errors.Trace(ctx.Err())
 	case <-m.allWorkersReady:
-		log.L().Info("All workers have sent heartbeats after master failover. Resuming right now.",
+		m.logger.Info("All workers have sent heartbeats after master failover. Resuming right now.",
 			zap.Duration("duration", m.clock.Since(startTime)))
 	case <-timer.C:
 		// Wait for the worker timeout to expire
 	}
+	result.WaitDuration = m.clock.Since(startTime)
 
 	m.mu.Lock()
-	for _, entry := range m.workerEntries {
+	for workerID, entry := range m.workerEntries {
 		if entry.State() == workerEntryWait || entry.IsFinished() {
 			entry.MarkAsTombstone()
 		}
+		if entry.IsTombstone() {
+			result.Tombstoned = append(result.Tombstoned, workerID)
+		} else {
+			result.Reconnected = append(result.Reconnected, workerID)
+		}
 	}
+	sort.Strings(result.Tombstoned)
+	sort.Strings(result.Reconnected)
 	m.state = workerManagerReady
+	m.recoveryResult = result
 	m.mu.Unlock()
 
 	return nil
 }
 
-// HandleHeartbeat handles heartbeat ping message from a worker
+// RecoveryResult returns the result of the recovery after master failover,
+// it is nil if the master is not recovered or the recovery is not done.
+func (m *WorkerManager) RecoveryResult() *RecoveryResult {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.recoveryResult
+}
+
+// hydrateStatuses loads the full statuses of the given workers in batches,
+// and replaces the statuses loaded from the worker index with them.
+func (m *WorkerManager) hydrateStatuses(
+	indexStatuses map[libModel.WorkerID]*libModel.WorkerStatus,
+	workerIDs []libModel.WorkerID,
+) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-m.closeCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	total := len(workerIDs)
+	startTime := m.clock.Now()
+	for start := 0; start < total || start == 0; start += hydrateBatchSize {
+		end := start + hydrateBatchSize
+		if end > total {
+			end = total
+		}
+		statuses, err := m.workerMetaClient.LoadWorkers(ctx, workerIDs[start:end])
+		if err != nil {
+			return err
+		}
+		for workerID, status := range statuses {
+			if entry, ok := m.getEntry(workerID); ok {
+				entry.HydrateStatus(indexStatuses[workerID], status)
+			}
+		}
+		err = m.enqueueEvent(&masterEvent{
+			Tp:       recoveryProgressEvent,
+			Hydrated: end,
+			Total:    total,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	m.logger.Info("worker statuses hydrated",
+		zap.Int("count", total),
+		zap.Duration("duration", m.clock.Since(startTime)))
+	return nil
+}
+
+// HandleHeartbeat handles heartbeat ping message from a worker. The heartbeats
+// of a worker are handled in the order they are sent, since the heartbeat
+// topic is not registered by p2p.RegisterTopicConcurrency.
 func (m *WorkerManager) HandleHeartbeat(msg *libModel.HeartbeatPingMessage, fromNode p2p.NodeID) {
+	startTime := time.Now()
+	defer func() {
+		heartbeatHandleDuration.WithLabelValues(m.masterID).Observe(time.Since(startTime).Seconds())
+	}()
+
+	// the request is sent after m.mu is released
+	replayRequested := false
+	defer func() {
+		if replayRequested {
+			m.requestStatusReplay(msg.FromWorkerID, fromNode)
+		}
+	}()
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -214,10 +464,15 @@ func (m *WorkerManager) HandleHeartbeat(msg *libModel.HeartbeatPingMessage, from
 		return
 	}
 
+	if m.faultInjector.ShouldDropHeartbeat(msg.FromWorkerID) {
+		m.logger.Info("Heartbeat dropped by fault injection",
+			zap.String("worker-id", msg.FromWorkerID))
+		return
+	}
+
 	entry, exists := m.workerEntries[msg.FromWorkerID]
 	if !exists {
-		log.L().Info("Message from stale worker dropped",
-			zap.String("master-id", m.masterID),
+		m.logger.Info("Message from stale worker dropped",
 			zap.Any("message", msg),
 			zap.String("from-node", fromNode))
 		return
@@ -226,8 +481,22 @@ func (m *WorkerManager) HandleHeartbeat(msg *libModel.HeartbeatPingMessage, from
 	if msg.IsFinished {
 		entry.SetFinished()
 	}
+	entry.SetStuck(msg.Stuck)
+	entry.SetCreatingWorkers(msg.CreatingWorkers)
 
 	entry.SetExpireTime(m.nextExpireTime())
+	entry.SetHeartbeatTime(m.clock.Now())
+
+	if entry.State() == workerEntryWait && msg.StatusSeq > msg.PersistedStatusSeq {
+		// The status updates after the persisted one may have been lost in
+		// failover. Keep requesting them in heartbeats until they are replayed.
+		m.logger.Info("status updates of worker are not persisted, request a replay",
+			zap.String("worker-id", msg.FromWorkerID),
+			zap.Uint64("status-seq", msg.StatusSeq),
+			zap.Uint64("persisted-status-seq", msg.PersistedStatusSeq))
+		entry.SetReplaySeq(msg.StatusSeq)
+	}
+	replayRequested = entry.IsReplayPending()
 
 	if m.state == workerManagerWaitingHeartbeat {
 		if entry.State() != workerEntryWait {
@@ -236,7 +505,7 @@ func (m *WorkerManager) HandleHeartbeat(msg *libModel.HeartbeatPingMessage, from
 			return
 		}
 
-		log.L().Info("Worker discovered", zap.String("master-id", m.masterID),
+		m.logger.Info("Worker discovered",
 			zap.Any("worker-entry", entry))
 		entry.MarkAsOnline(model.ExecutorID(fromNode), m.nextExpireTime())
 
@@ -249,14 +518,18 @@ func (m *WorkerManager) HandleHeartbeat(msg *libModel.HeartbeatPingMessage, from
 		}
 		if allReady {
 			close(m.allWorkersReady)
-			log.L().Info("All workers have sent heartbeats, sending signal to resume the master",
-				zap.String("master-id", m.masterID))
+			m.logger.Info("All workers have sent heartbeats, sending signal to resume the master")
 		}
 	} else {
-		if entry.State() != workerEntryCreated {
+		state := entry.State()
+		if state != workerEntryCreated && state != workerEntryWait {
 			// Return if it is not the first heartbeat.
 			return
 		}
+		if state == workerEntryWait {
+			m.logger.Info("Suspect worker reconciled after fast failover",
+				zap.Any("worker-entry", entry))
+		}
 
 		entry.MarkAsOnline(model.ExecutorID(fromNode), m.nextExpireTime())
 
@@ -270,11 +543,44 @@ func (m *WorkerManager) HandleHeartbeat(msg *libModel.HeartbeatPingMessage, from
 			},
 		})
 		if err != nil {
-			m.errCenter.OnError(err)
+			m.onError(err)
 		}
 	}
 }
 
+// Timeouts returns the timeouts currently used by the WorkerManager.
+func (m *WorkerManager) Timeouts() config.TimeoutConfig {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.timeouts
+}
+
+// UpdateTimeouts replaces the worker timeouts of a running master, and
+// recomputes the expire time of every live worker from its last heartbeat.
+// MasterHeartbeatCheckLoopInterval is not changed.
+func (m *WorkerManager) UpdateTimeouts(timeouts config.TimeoutConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	timeouts.MasterHeartbeatCheckLoopInterval = m.timeouts.MasterHeartbeatCheckLoopInterval
+	m.timeouts = timeouts.Adjust()
+	m.logger.Info("worker timeouts updated", zap.Any("timeouts", m.timeouts))
+
+	timeoutInterval := m.timeouts.WorkerTimeoutDuration + m.timeouts.WorkerTimeoutGracefulDuration
+	for _, entry := range m.workerEntries {
+		state := entry.State()
+		if state == workerEntryOffline || state == workerEntryTombstone {
+			continue
+		}
+		heartbeatAt := entry.HeartbeatTime()
+		if heartbeatAt.IsZero() {
+			// waiting workers are timed out by InitAfterRecover
+			continue
+		}
+		entry.SetExpireTime(heartbeatAt.Add(timeoutInterval))
+	}
+}
+
 // Tick should be called by the BaseMaster so that the callbacks can be
 // run in the main goroutine.
 func (m *WorkerManager) Tick(ctx context.Context) error {
@@ -320,6 +626,17 @@ func (m *WorkerManager) Tick(ctx context.Context) error {
 			if err := m.onWorkerDispatched(ctx, event.Handle, event.Err); err != nil {
 				return err
 			}
+		case workerUnresponsiveEvent:
+			if err := m.onWorkerUnresponsive(ctx, event.Handle, event.MissedHeartbeats); err != nil {
+				return err
+			}
+		case recoveryProgressEvent:
+			if m.onRecoveryProgress == nil {
+				continue
+			}
+			if err := m.onRecoveryProgress(ctx, event.Hydrated, event.Total); err != nil {
+				return err
+			}
 		}
 	}
 }
@@ -331,10 +648,10 @@ func (m *WorkerManager) BeforeStartingWorker(workerID libModel.WorkerID, executo
 	defer m.mu.Unlock()
 
 	if _, exists := m.workerEntries[workerID]; exists {
-		log.L().Panic("worker already exists", zap.String("worker-id", workerID))
+		m.logger.Panic("worker already exists", zap.String("worker-id", workerID))
 	}
 
-	m.workerEntries[workerID] = newWorkerEntry(
+	entry := newWorkerEntry(
 		workerID,
 		executorID,
 		m.nextExpireTime(),
@@ -342,6 +659,9 @@ func (m *WorkerManager) BeforeStartingWorker(workerID libModel.WorkerID, executo
 		&libModel.WorkerStatus{
 			Code: libModel.WorkerStatusCreated,
 		})
+	entry.SetHeartbeatTime(m.clock.Now())
+	entry.SetTransitionHook(m.onWorkerEntryTransition)
+	m.workerEntries[workerID] = entry
 }
 
 // AbortCreatingWorker is called by BaseMaster if starting the worker has failed for sure.
@@ -370,7 +690,7 @@ func (m *WorkerManager) AbortCreatingWorker(workerID libModel.WorkerID, errIn er
 
 	err := m.enqueueEvent(event)
 	if err != nil {
-		m.errCenter.OnError(err)
+		m.onError(err)
 	}
 }
 
@@ -385,8 +705,7 @@ func (m *WorkerManager) OnWorkerStatusUpdateMessage(msg *statusutil.WorkerStatus
 
 	entry, exists := m.workerEntries[msg.Worker]
 	if !exists {
-		log.L().Info("WorkerStatusMessage dropped for unknown worker",
-			zap.String("master-id", m.masterID),
+		m.logger.Info("WorkerStatusMessage dropped for unknown worker",
 			zap.Any("message", msg))
 		return
 	}
@@ -404,17 +723,83 @@ func (m *WorkerManager) OnWorkerStatusUpdateMessage(msg *statusutil.WorkerStatus
 				// Cancel the event
 				return false
 			}
-			entry.UpdateStatus(msg.Status)
-			return true
+			return entry.UpdateStatusWithSeq(msg.Status, msg.Seq)
 		},
 	}
 
 	if err := m.enqueueEvent(event); err != nil {
-		m.errCenter.OnError(err)
+		m.onError(err)
 		return
 	}
 }
 
+// requestStatusReplay requests the worker to replay its status updates
+// since the latest persisted one.
+func (m *WorkerManager) requestStatusReplay(workerID libModel.WorkerID, node p2p.NodeID) {
+	ctx, cancel := context.WithTimeout(context.Background(), requestStatusReplayTimeout)
+	defer cancel()
+
+	ok, err := m.messageSender.SendToNode(ctx, node,
+		statusutil.WorkerStatusReplayRequestTopic(m.masterID, workerID),
+		&statusutil.WorkerStatusReplayRequest{MasterEpoch: m.epoch})
+	if err != nil || !ok {
+		// the request is sent again in the next heartbeat
+		m.logger.Warn("failed to request status replay",
+			zap.String("worker-id", workerID),
+			zap.Bool("ok", ok), zap.Error(err))
+	}
+}
+
+// OnWorkerStatusReplayMessage should be called in the message handler for
+// WorkerStatusReplayMessage. The replayed updates are consolidated into one
+// status updated event with the latest status.
+func (m *WorkerManager) OnWorkerStatusReplayMessage(msg *statusutil.WorkerStatusReplayMessage) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.checkMasterEpochMatch(msg.MasterEpoch) {
+		return
+	}
+
+	entry, exists := m.workerEntries[msg.Worker]
+	if !exists || len(msg.Statuses) == 0 {
+		m.logger.Info("WorkerStatusReplayMessage dropped",
+			zap.String("worker-id", msg.Worker),
+			zap.Bool("exists", exists))
+		return
+	}
+	if !entry.IsReplayPending() {
+		// replayed already, or superseded by a newer status update
+		return
+	}
+
+	latest := msg.Statuses[len(msg.Statuses)-1]
+	m.logger.Info("status updates replayed by worker",
+		zap.String("worker-id", msg.Worker),
+		zap.Int("count", len(msg.Statuses)),
+		zap.Bool("truncated", msg.Truncated),
+		zap.Uint64("latest-seq", latest.Seq))
+
+	event := &masterEvent{
+		Tp: workerStatusUpdatedEvent,
+		Handle: &runningHandleImpl{
+			workerID:   msg.Worker,
+			executorID: entry.executorID,
+			manager:    m,
+		},
+		WorkerID: msg.Worker,
+		beforeHook: func() bool {
+			if entry.IsTombstone() {
+				return false
+			}
+			return entry.UpdateStatusWithSeq(latest.Status, latest.Seq)
+		},
+	}
+	if err := m.enqueueEvent(event); err != nil {
+		m.onError(err)
+	}
+}
+
 // GetWorkers gets all workers maintained by WorkerManager, including both running
 // workers and dead workers.
 func (m *WorkerManager) GetWorkers() map[libModel.WorkerID]WorkerHandle {
@@ -440,6 +825,73 @@ func (m *WorkerManager) GetWorkers() map[libModel.WorkerID]WorkerHandle {
 	return ret
 }
 
+// LookupExecutor returns the executor that an online worker is running on,
+// it is used by the workers to discover their peers.
+func (m *WorkerManager) LookupExecutor(workerID libModel.WorkerID) (model.ExecutorID, bool) {
+	entry, exists := m.getEntry(workerID)
+	if !exists || entry.State() != workerEntryNormal {
+		return "", false
+	}
+	return entry.executorID, true
+}
+
+// ListWorkers returns a page of the workers matching the query, sorted by
+// their IDs. nextPageToken is empty if there are no more workers.
+func (m *WorkerManager) ListWorkers(
+	query *libModel.WorkersQuery,
+) (workers []*libModel.WorkerSummary, nextPageToken libModel.WorkerID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ids := make([]libModel.WorkerID, 0, len(m.workerEntries))
+	for workerID := range m.workerEntries {
+		if workerID > query.PageToken {
+			ids = append(ids, workerID)
+		}
+	}
+	sort.Strings(ids)
+
+	for _, workerID := range ids {
+		entry := m.workerEntries[workerID]
+		entry.mu.Lock()
+		executorID := string(entry.executorID)
+		entry.mu.Unlock()
+		if query.ExecutorID != "" && executorID != query.ExecutorID {
+			continue
+		}
+		summary := &libModel.WorkerSummary{
+			ID:          workerID,
+			ExecutorID:  executorID,
+			State:       entry.State().String(),
+			HeartbeatAt: entry.HeartbeatTime(),
+		}
+		if status := entry.Status(); status != nil {
+			summary.StatusCode = status.Code
+			summary.ErrorMessage = status.ErrorMessage
+		}
+		if !matchStatusCodes(summary.StatusCode, query.StatusCodes) {
+			continue
+		}
+		if query.PageSize > 0 && len(workers) == query.PageSize {
+			return workers, workers[len(workers)-1].ID
+		}
+		workers = append(workers, summary)
+	}
+	return workers, ""
+}
+
+func matchStatusCodes(code libModel.WorkerStatusCode, codes []libModel.WorkerStatusCode) bool {
+	if len(codes) == 0 {
+		return true
+	}
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
 // IsInitialized returns true after the worker manager has checked all tombstone
 // workers are online or dead.
 func (m *WorkerManager) IsInitialized() bool {
@@ -469,15 +921,20 @@ func (m *WorkerManager) checkWorkerEntriesOnce() error {
 		}
 
 		hasTimedOut := entry.ExpireTime().Before(m.clock.Now())
-		shouldGoOffline := hasTimedOut || entry.IsFinished()
+		shouldGoOffline := hasTimedOut || entry.IsFinished() || entry.IsStuck()
 		if !shouldGoOffline {
+			if state == workerEntryWait {
+				// A suspect has sent no heartbeat to this master yet.
+				continue
+			}
+			if err := m.checkUnresponsive(workerID, entry); err != nil {
+				return err
+			}
 			continue
 		}
 
 		// The worker has timed out, or has received a heartbeat
-		// with IsFinished == true.
-		entry.MarkAsOffline()
-
+		// with IsFinished or Stuck == true.
 		var offlineError error
 		if status := entry.Status(); status != nil {
 			switch status.Code {
@@ -486,11 +943,61 @@ func (m *WorkerManager) checkWorkerEntriesOnce() error {
 			case libModel.WorkerStatusStopped:
 				offlineError = derror.ErrWorkerStop.FastGenByArgs()
 			default:
-				offlineError = derror.ErrWorkerOffline.FastGenByArgs(workerID)
+				if entry.IsStuck() {
+					m.logger.Warn("worker is stuck, mark it offline", zap.String("worker-id", workerID))
+					offlineError = derror.ErrWorkerStuck.FastGenByArgs(workerID)
+				} else {
+					offlineError = derror.ErrWorkerOffline.FastGenByArgs(workerID)
+				}
 			}
 		}
 
-		err := m.enqueueEvent(&masterEvent{
+		if err := m.markOffline(workerID, entry, offlineError); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// OnExecutorOffline marks the workers running on an executor offline at
+// once, without waiting for them to time out. It is called when the
+// executor is known to have failed, e.g. blacklisted by operators.
+func (m *WorkerManager) OnExecutorOffline(executorID model.ExecutorID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.state != workerManagerReady {
+		// The workers that do not show up during the waiting period are
+		// handled by InitAfterRecover.
+		return nil
+	}
+
+	for workerID, entry := range m.workerEntries {
+		state := entry.State()
+		if state != workerEntryCreated && state != workerEntryNormal {
+			continue
+		}
+		if entry.ExecutorID() != executorID {
+			continue
+		}
+		m.logger.Info("worker goes offline with its executor",
+			zap.String("worker-id", workerID),
+			zap.String("executor-id", string(executorID)))
+		if err := m.markOffline(workerID, entry, derror.ErrWorkerOffline.FastGenByArgs(workerID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// markOffline marks the entry offline and delivers the workerOffline event.
+// It must be called with m.mu held.
+func (m *WorkerManager) markOffline(workerID libModel.WorkerID, entry *workerEntry, offlineError error) error {
+	if entry.State() == workerEntryWait {
+		// A suspect after fast failover has never been online, so it is
+		// marked as tombstone at once.
+		entry.MarkAsTombstone()
+		return m.enqueueEvent(&masterEvent{
 			Tp:       workerOfflineEvent,
 			WorkerID: workerID,
 			Handle: &tombstoneHandleImpl{
@@ -498,35 +1005,104 @@ func (m *WorkerManager) checkWorkerEntriesOnce() error {
 				manager:  m,
 			},
 			Err: offlineError,
-			beforeHook: func() bool {
-				entry.MarkAsTombstone()
-				return true
-			},
 		})
-		if err != nil {
-			return err
-		}
 	}
-	return nil
+	entry.MarkAsOffline()
+	return m.enqueueEvent(&masterEvent{
+		Tp:       workerOfflineEvent,
+		WorkerID: workerID,
+		Handle: &tombstoneHandleImpl{
+			workerID: workerID,
+			manager:  m,
+		},
+		Err: offlineError,
+		beforeHook: func() bool {
+			entry.MarkAsTombstone()
+			return true
+		},
+	})
+}
+
+// onWorkerEntryTransition is the transition hook of the worker entries.
+func (m *WorkerManager) onWorkerEntryTransition(
+	workerID libModel.WorkerID, from, to workerEntryState,
+) {
+	workerEntryTransitionCounter.WithLabelValues(m.masterID, from.String(), to.String()).Inc()
+	m.logger.Debug("worker entry transitioned",
+		zap.String("worker-id", workerID),
+		zap.Stringer("from", from),
+		zap.Stringer("to", to),
+		zap.String("event", workerEntryTransitionEvent(from, to)))
+}
+
+// checkUnresponsive reports the worker as unresponsive once if it has missed
+// enough consecutive heartbeats, so that the master can react before the
+// worker times out. It must be called with m.mu held.
+func (m *WorkerManager) checkUnresponsive(workerID libModel.WorkerID, entry *workerEntry) error {
+	maxMisses := m.timeouts.WorkerUnresponsiveHeartbeatMisses
+	interval := m.timeouts.WorkerHeartbeatInterval
+	if maxMisses <= 0 || interval <= 0 {
+		return nil
+	}
+
+	missed := int(m.clock.Since(entry.HeartbeatTime()) / interval)
+	if missed < maxMisses || !entry.MarkAsUnresponsive() {
+		return nil
+	}
+
+	workerUnresponsiveCounter.WithLabelValues(m.masterID).Inc()
+	m.logger.Warn("worker missed heartbeats",
+		zap.String("worker-id", workerID),
+		zap.Int("missed-heartbeats", missed))
+	return m.enqueueEvent(&masterEvent{
+		Tp:       workerUnresponsiveEvent,
+		WorkerID: workerID,
+		Handle: &runningHandleImpl{
+			workerID:   workerID,
+			executorID: entry.executorID,
+			manager:    m,
+		},
+		MissedHeartbeats: missed,
+		beforeHook: func() bool {
+			// The worker may have sent a heartbeat or gone offline
+			// since the event is enqueued.
+			state := entry.State()
+			return entry.IsUnresponsive() &&
+				(state == workerEntryCreated || state == workerEntryNormal)
+		},
+	})
 }
 
-func (m *WorkerManager) runBackgroundChecker() error {
-	ticker := m.clock.Ticker(m.timeouts.MasterHeartbeatCheckLoopInterval)
+func (m *WorkerManager) runBackgroundChecker(ticker *bclock.Ticker) error {
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-m.closeCh:
-			log.L().Info("timeout checker exited", zap.String("master-id", m.masterID))
+			m.logger.Info("timeout checker exited")
 			return nil
 		case <-ticker.C:
 			if err := m.checkWorkerEntriesOnce(); err != nil {
-				return err
+				if errctx.ClassifyError(err) == errctx.SeverityFatal {
+					return err
+				}
+				m.onError(err)
 			}
 		}
 	}
 }
 
+// onError records an error in the errCenter. Fatal errors terminate the
+// master, while retryable ones are logged and recorded only.
+func (m *WorkerManager) onError(err error) {
+	m.errCenter.OnErrorWithSeverity(err, errctx.ClassifyError(err))
+}
+
+// ErrorHistory returns the recent errors of the WorkerManager
+func (m *WorkerManager) ErrorHistory() []errctx.ErrorRecord {
+	return m.errCenter.History()
+}
+
 func (m *WorkerManager) nextExpireTime() time.Time {
 	timeoutInterval := m.timeouts.WorkerTimeoutDuration + m.timeouts.WorkerTimeoutGracefulDuration
 	return m.clock.Now().Add(timeoutInterval)
@@ -538,15 +1114,13 @@ func (m *WorkerManager) checkMasterEpochMatch(msgEpoch libModel.Epoch) (ok bool)
 		// we shouldn't be running.
 		// TODO We need to do some chaos testing to determining whether and how to
 		// handle this situation.
-		log.L().Panic("We are a stale master still running",
-			zap.String("master-id", m.masterID),
+		m.logger.Panic("We are a stale master still running",
 			zap.Int64("msg-epoch", msgEpoch),
 			zap.Int64("own-epoch", m.epoch))
 	}
 
 	if msgEpoch < m.epoch {
-		log.L().Info("Message from smaller epoch dropped",
-			zap.String("master-id", m.masterID),
+		m.logger.Info("Message from smaller epoch dropped",
 			zap.Int64("msg-epoch", msgEpoch),
 			zap.Int64("own-epoch", m.epoch))
 		return false
@@ -567,6 +1141,16 @@ func (m *WorkerManager) enqueueEvent(event *masterEvent) error {
 	return nil
 }
 
+// getEntry returns the workerEntry of a worker, it should NOT be called with
+// m.mu taken.
+func (m *WorkerManager) getEntry(id libModel.WorkerID) (*workerEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, exists := m.workerEntries[id]
+	return entry, exists
+}
+
 // removeTombstoneEntry removes a tombstone workerEntry from the in-memory map.
 // NOTE: removeTombstoneEntry is expected to be used by tombstoneHandleImpl only,
 // and it should NOT be called with m.mu taken.
@@ -582,7 +1166,7 @@ func (m *WorkerManager) removeTombstoneEntry(id libModel.WorkerID) {
 	}
 
 	if !entry.IsTombstone() {
-		log.L().Panic("Unreachable: not a tombstone", zap.Stringer("entry", entry))
+		m.logger.Panic("Unreachable: not a tombstone", zap.Stringer("entry", entry))
 	}
 
 	delete(m.workerEntries, id)
@@ -15,8 +15,10 @@ import (
 	"github.com/hanfei1991/microcosm/lib/metadata"
 	libModel "github.com/hanfei1991/microcosm/lib/model"
 	"github.com/hanfei1991/microcosm/lib/statusutil"
+	"github.com/hanfei1991/microcosm/model"
 	"github.com/hanfei1991/microcosm/pkg/clock"
 	derror "github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/faultinject"
 	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
 	"github.com/hanfei1991/microcosm/pkg/p2p"
 )
@@ -27,8 +29,12 @@ type workerManageTestSuite struct {
 	meta          pkgOrm.Client
 	messageSender p2p.MessageSender
 	clock         *clock.Mock
+	faultInjector *faultinject.Injector
 
 	events map[libModel.WorkerID]*masterEvent
+	// unresponsiveEvents are recorded separately, so that they don't
+	// interfere with the other events.
+	unresponsiveEvents chan *masterEvent
 }
 
 func (s *workerManageTestSuite) AdvanceClockBy(duration time.Duration) {
@@ -118,6 +124,37 @@ func (s *workerManageTestSuite) onWorkerDispatched(ctx context.Context, handle W
 	return nil
 }
 
+func (s *workerManageTestSuite) onWorkerUnresponsive(ctx context.Context, handle WorkerHandle, missedHeartbeats int) error {
+	select {
+	case s.unresponsiveEvents <- &masterEvent{
+		Tp:               workerUnresponsiveEvent,
+		Handle:           handle,
+		MissedHeartbeats: missedHeartbeats,
+	}:
+	default:
+		// tests that don't care about unresponsive events don't drain the channel
+	}
+	return nil
+}
+
+func (s *workerManageTestSuite) WaitForUnresponsiveEvent(t *testing.T) *masterEvent {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	for {
+		err := s.manager.Tick(timeoutCtx)
+		require.NoError(t, err)
+
+		select {
+		case event := <-s.unresponsiveEvents:
+			return event
+		case <-timeoutCtx.Done():
+			t.Fatal("wait for unresponsive event timed out")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
 func (s *workerManageTestSuite) WaitForEvent(t *testing.T, workerID libModel.WorkerID) *masterEvent {
 	timeoutCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
@@ -199,7 +236,10 @@ func NewWorkerManageTestSuite(isInit bool) *workerManageTestSuite {
 		masterNode:    "executor-0",
 		messageSender: p2p.NewMockMessageSender(),
 		clock:         clock.NewMock(),
+		faultInjector: faultinject.NewInjector(),
 		events:        make(map[libModel.WorkerID]*masterEvent),
+
+		unresponsiveEvents: make(chan *masterEvent, 16),
 	}
 
 	manager := NewWorkerManager(
@@ -211,9 +251,11 @@ func NewWorkerManageTestSuite(isInit bool) *workerManageTestSuite {
 		ret.onWorkerOffline,
 		ret.onWorkerStatusUpdated,
 		ret.onWorkerDispatched,
+		ret.onWorkerUnresponsive,
 		isInit,
 		config.DefaultTimeoutConfig(),
-		ret.clock)
+		ret.clock,
+		ret.faultInjector)
 	ret.manager = manager
 	return ret
 }
@@ -230,6 +272,12 @@ func TestCreateWorkerAndWorkerOnline(t *testing.T) {
 
 	event := suite.WaitForEvent(t, "worker-1")
 	require.Equal(t, workerOnlineEvent, event.Tp)
+
+	executor, ok := suite.manager.LookupExecutor("worker-1")
+	require.True(t, ok)
+	require.Equal(t, model.ExecutorID("executor-1"), executor)
+	_, ok = suite.manager.LookupExecutor("worker-2")
+	require.False(t, ok)
 	suite.Close()
 }
 
@@ -250,6 +298,153 @@ func TestCreateWorkerAndWorkerTimesOut(t *testing.T) {
 	suite.Close()
 }
 
+func TestWorkerUnresponsive(t *testing.T) {
+	t.Parallel()
+
+	suite := NewWorkerManageTestSuite(true)
+	suite.manager.BeforeStartingWorker("worker-1", "executor-1")
+	suite.SimulateHeartbeat("worker-1", 1, "executor-1", false)
+	event := suite.WaitForEvent(t, "worker-1")
+	require.Equal(t, workerOnlineEvent, event.Tp)
+
+	// missed 2 heartbeats, but not timed out yet
+	suite.AdvanceClockBy(7 * time.Second)
+	event = suite.WaitForUnresponsiveEvent(t)
+	require.Equal(t, "worker-1", event.Handle.ID())
+	require.Equal(t, 2, event.MissedHeartbeats)
+
+	// reported only once before the next heartbeat
+	suite.AdvanceClockBy(3 * time.Second)
+	suite.AssertNoEvents(t, "worker-1", 200*time.Millisecond)
+	require.Len(t, suite.unresponsiveEvents, 0)
+
+	// reported again after the next heartbeat, the checker may run at any
+	// tick of the mock clock, so the clock is advanced to the exact time.
+	suite.SimulateHeartbeat("worker-1", 1, "executor-1", false)
+	suite.AdvanceClockBy(6 * time.Second)
+	event = suite.WaitForUnresponsiveEvent(t)
+	require.Equal(t, 2, event.MissedHeartbeats)
+	suite.Close()
+}
+
+func TestUpdateTimeouts(t *testing.T) {
+	t.Parallel()
+
+	suite := NewWorkerManageTestSuite(true)
+	suite.manager.BeforeStartingWorker("worker-1", "executor-1")
+	suite.SimulateHeartbeat("worker-1", 1, "executor-1", false)
+	event := suite.WaitForEvent(t, "worker-1")
+	require.Equal(t, workerOnlineEvent, event.Tp)
+
+	timeouts := suite.manager.Timeouts()
+	timeouts.WorkerTimeoutDuration = 60 * time.Second
+	suite.manager.UpdateTimeouts(timeouts)
+	require.Equal(t, 60*time.Second, suite.manager.Timeouts().WorkerTimeoutDuration)
+
+	// the worker would have timed out with the default 15s + 5s timeout
+	suite.AdvanceClockBy(30 * time.Second)
+	suite.AssertNoEvents(t, "worker-1", 200*time.Millisecond)
+
+	suite.AdvanceClockBy(40 * time.Second)
+	event = suite.WaitForEvent(t, "worker-1")
+	require.Equal(t, workerOfflineEvent, event.Tp)
+	suite.Close()
+}
+
+func TestHeartbeatReportsCreatingWorkers(t *testing.T) {
+	t.Parallel()
+
+	suite := NewWorkerManageTestSuite(true)
+	suite.manager.BeforeStartingWorker("worker-1", "executor-1")
+	suite.manager.HandleHeartbeat(&libModel.HeartbeatPingMessage{
+		SendTime:        suite.clock.Mono(),
+		FromWorkerID:    "worker-1",
+		Epoch:           1,
+		CreatingWorkers: 3,
+	}, "executor-1")
+	event := suite.WaitForEvent(t, "worker-1")
+	require.Equal(t, workerOnlineEvent, event.Tp)
+
+	info, err := event.Handle.ToPB()
+	require.NoError(t, err)
+	require.Equal(t, int32(3), info.CreatingWorkers)
+
+	// the count is reset by a heartbeat of a worker that creates no workers
+	suite.SimulateHeartbeat("worker-1", 1, "executor-1", false)
+	info, err = event.Handle.ToPB()
+	require.NoError(t, err)
+	require.Equal(t, int32(0), info.CreatingWorkers)
+	suite.Close()
+}
+
+func TestWorkerStuck(t *testing.T) {
+	t.Parallel()
+
+	suite := NewWorkerManageTestSuite(true)
+	suite.manager.BeforeStartingWorker("worker-1", "executor-1")
+	suite.SimulateHeartbeat("worker-1", 1, "executor-1", false)
+	event := suite.WaitForEvent(t, "worker-1")
+	require.Equal(t, workerOnlineEvent, event.Tp)
+
+	// the heartbeats are sent on time, but the Tick of the worker is stuck
+	suite.manager.HandleHeartbeat(&libModel.HeartbeatPingMessage{
+		SendTime:     suite.clock.Mono(),
+		FromWorkerID: "worker-1",
+		Epoch:        1,
+		Stuck:        true,
+	}, "executor-1")
+	suite.AdvanceClockBy(time.Second)
+	event = suite.WaitForEvent(t, "worker-1")
+	require.Equal(t, workerOfflineEvent, event.Tp)
+	require.NotNil(t, event.Handle.GetTombstone())
+	require.True(t, derror.ErrWorkerStuck.Equal(event.Err))
+	suite.Close()
+}
+
+func TestWorkerOfflineWithExecutor(t *testing.T) {
+	t.Parallel()
+
+	suite := NewWorkerManageTestSuite(true)
+	suite.manager.BeforeStartingWorker("worker-1", "executor-1")
+	suite.manager.BeforeStartingWorker("worker-2", "executor-2")
+	suite.SimulateHeartbeat("worker-1", 1, "executor-1", false)
+	suite.SimulateHeartbeat("worker-2", 1, "executor-2", false)
+	require.Equal(t, workerOnlineEvent, suite.WaitForEvent(t, "worker-1").Tp)
+	require.Equal(t, workerOnlineEvent, suite.WaitForEvent(t, "worker-2").Tp)
+
+	// the workers go offline without waiting for the timeout
+	require.NoError(t, suite.manager.OnExecutorOffline("executor-1"))
+	event := suite.WaitForEvent(t, "worker-1")
+	require.Equal(t, workerOfflineEvent, event.Tp)
+	require.True(t, derror.ErrWorkerOffline.Equal(event.Err))
+	suite.AssertNoEvents(t, "worker-2", 100*time.Millisecond)
+
+	// the offline event is delivered once
+	require.NoError(t, suite.manager.OnExecutorOffline("executor-1"))
+	suite.AssertNoEvents(t, "worker-1", 100*time.Millisecond)
+	suite.Close()
+}
+
+func TestHeartbeatDroppedByFaultInjection(t *testing.T) {
+	t.Parallel()
+
+	suite := NewWorkerManageTestSuite(true)
+	suite.manager.BeforeStartingWorker("worker-1", "executor-1")
+	suite.SimulateHeartbeat("worker-1", 1, "executor-1", false)
+	event := suite.WaitForEvent(t, "worker-1")
+	require.Equal(t, workerOnlineEvent, event.Tp)
+
+	suite.faultInjector.DropNextHeartbeat("worker-1")
+	suite.AdvanceClockBy(10 * time.Second)
+	suite.SimulateHeartbeat("worker-1", 1, "executor-1", false)
+
+	// the worker times out since the last heartbeat is dropped
+	suite.AdvanceClockBy(11 * time.Second)
+	event = suite.WaitForEvent(t, "worker-1")
+	require.Equal(t, workerOfflineEvent, event.Tp)
+	suite.Close()
+}
+
 func TestCreateWorkerAndWorkerStatusUpdatedAndTimesOut(t *testing.T) {
 	t.Parallel()
 
@@ -334,6 +529,12 @@ func TestRecoverAfterFailover(t *testing.T) {
 	require.Nil(t, suite.manager.GetWorkers()["worker-2"].GetTombstone())
 	require.Nil(t, suite.manager.GetWorkers()["worker-3"].GetTombstone())
 	require.NotNil(t, suite.manager.GetWorkers()["worker-4"].GetTombstone())
+
+	result := suite.manager.RecoveryResult()
+	require.Equal(t, []libModel.WorkerID{"worker-1", "worker-2", "worker-3"}, result.Reconnected)
+	require.Equal(t, []libModel.WorkerID{"worker-4"}, result.Tombstoned)
+	require.Empty(t, result.Finished)
+	require.Greater(t, result.WaitDuration, time.Duration(0))
 	suite.Close()
 }
 
@@ -372,6 +573,129 @@ func TestRecoverAfterFailoverFast(t *testing.T) {
 	suite.Close()
 }
 
+func TestRecoverWithFastFailover(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	suite := NewWorkerManageTestSuite(false)
+	err := suite.PutMeta("worker-1", &libModel.WorkerStatus{
+		Code: libModel.WorkerStatusNormal,
+	})
+	require.NoError(t, err)
+	err = suite.PutMeta("worker-2", &libModel.WorkerStatus{
+		Code: libModel.WorkerStatusNormal,
+	})
+	require.NoError(t, err)
+
+	// the master is ready without waiting for heartbeats
+	suite.manager.EnableFastFailover()
+	err = suite.manager.InitAfterRecover(ctx)
+	require.NoError(t, err)
+	require.True(t, suite.manager.IsInitialized())
+	result := suite.manager.RecoveryResult()
+	require.Equal(t, []libModel.WorkerID{"worker-1", "worker-2"}, result.Suspects)
+	require.Empty(t, result.Reconnected)
+	require.Empty(t, result.Tombstoned)
+	require.Len(t, suite.manager.GetWorkers(), 2)
+
+	// a suspect is reconciled by its first heartbeat
+	suite.SimulateHeartbeat("worker-1", 1, "executor-1", false)
+	event := suite.WaitForEvent(t, "worker-1")
+	require.Equal(t, workerOnlineEvent, event.Tp)
+	executor, ok := suite.manager.LookupExecutor("worker-1")
+	require.True(t, ok)
+	require.Equal(t, model.ExecutorID("executor-1"), executor)
+
+	// a suspect sending no heartbeat goes offline after the timeout
+	require.Eventually(t, func() bool {
+		suite.SimulateHeartbeat("worker-1", 1, "executor-1", false)
+		suite.AdvanceClockBy(10 * time.Second)
+		return suite.manager.GetWorkers()["worker-2"].GetTombstone() != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	event = suite.WaitForEvent(t, "worker-2")
+	require.Equal(t, workerOfflineEvent, event.Tp)
+	require.Nil(t, suite.manager.GetWorkers()["worker-1"].GetTombstone())
+	suite.Close()
+}
+
+func TestRecoverIncrementally(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	suite := NewWorkerManageTestSuite(false)
+	err := suite.PutMeta("worker-1", &libModel.WorkerStatus{
+		Code:     libModel.WorkerStatusNormal,
+		ExtBytes: []byte("1"),
+	})
+	require.NoError(t, err)
+	err = suite.PutMeta("worker-2", &libModel.WorkerStatus{
+		Code: libModel.WorkerStatusFinished,
+	})
+	require.NoError(t, err)
+
+	type progress struct{ hydrated, total int }
+	progressCh := make(chan progress, 1)
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+		err := suite.manager.InitAfterRecoverIncrementally(ctx,
+			func(ctx context.Context, hydrated, total int) error {
+				progressCh <- progress{hydrated: hydrated, total: total}
+				return nil
+			})
+		require.NoError(t, err)
+	}()
+
+	require.Eventually(t, func() bool {
+		suite.SimulateHeartbeat("worker-1", 1, "executor-1", false)
+		select {
+		case <-doneCh:
+			return true
+		default:
+		}
+		return false
+	}, 1*time.Second, 10*time.Millisecond)
+	require.True(t, suite.manager.IsInitialized())
+	require.Len(t, suite.manager.GetWorkers(), 1)
+
+	// the finished worker is not hydrated
+	var got progress
+	require.Eventually(t, func() bool {
+		require.NoError(t, suite.manager.Tick(ctx))
+		select {
+		case got = <-progressCh:
+			return true
+		default:
+		}
+		return false
+	}, 1*time.Second, 10*time.Millisecond)
+	require.Equal(t, progress{hydrated: 1, total: 1}, got)
+	status := suite.manager.GetWorkers()["worker-1"].Status()
+	require.Equal(t, libModel.WorkerStatusNormal, status.Code)
+	require.Equal(t, []byte("1"), status.ExtBytes)
+	suite.Close()
+}
+
+func TestHydrateStatusAfterUpdate(t *testing.T) {
+	t.Parallel()
+
+	indexStatus := &libModel.WorkerStatus{Code: libModel.WorkerStatusNormal}
+	entry := newWaitingWorkerEntry("worker-1", indexStatus)
+	entry.HydrateStatus(indexStatus, &libModel.WorkerStatus{ExtBytes: []byte("1")})
+	require.Equal(t, []byte("1"), entry.Status().ExtBytes)
+
+	// the status updated by the worker is newer than the persisted one
+	indexStatus = &libModel.WorkerStatus{Code: libModel.WorkerStatusNormal}
+	entry = newWaitingWorkerEntry("worker-1", indexStatus)
+	entry.UpdateStatus(&libModel.WorkerStatus{ExtBytes: []byte("2")})
+	entry.HydrateStatus(indexStatus, &libModel.WorkerStatus{ExtBytes: []byte("1")})
+	require.Equal(t, []byte("2"), entry.Status().ExtBytes)
+}
+
 func TestRecoverWithNoWorker(t *testing.T) {
 	t.Parallel()
 
@@ -497,3 +821,172 @@ func TestWorkerGracefulExitAfterFailover(t *testing.T) {
 	require.NotNil(t, suite.manager.GetWorkers()["worker-1"].GetTombstone())
 	suite.Close()
 }
+
+func TestStatusReplayAfterFailover(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	suite := NewWorkerManageTestSuite(false)
+	err := suite.PutMeta("worker-1", &libModel.WorkerStatus{
+		Code: libModel.WorkerStatusNormal,
+	})
+	require.NoError(t, err)
+
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+		err := suite.manager.InitAfterRecover(ctx)
+		require.NoError(t, err)
+	}()
+
+	// the latest two status updates of worker-1 are not persisted
+	require.Eventually(t, func() bool {
+		suite.manager.HandleHeartbeat(&libModel.HeartbeatPingMessage{
+			SendTime:           suite.clock.Mono(),
+			FromWorkerID:       "worker-1",
+			Epoch:              1,
+			StatusSeq:          5,
+			PersistedStatusSeq: 3,
+		}, "executor-1")
+		select {
+		case <-doneCh:
+			return true
+		default:
+		}
+		return false
+	}, 1*time.Second, 10*time.Millisecond)
+
+	messageSender := suite.messageSender.(*p2p.MockMessageSender)
+	topic := statusutil.WorkerStatusReplayRequestTopic("master-1", "worker-1")
+	rawMsg, ok := messageSender.TryPop("executor-1", topic)
+	require.True(t, ok)
+	require.Equal(t, libModel.Epoch(1), rawMsg.(*statusutil.WorkerStatusReplayRequest).MasterEpoch)
+
+	replay := &statusutil.WorkerStatusReplayMessage{
+		Worker:      "worker-1",
+		MasterEpoch: 1,
+		Statuses: []statusutil.SeqStatus{
+			{Seq: 4, Status: &libModel.WorkerStatus{Code: libModel.WorkerStatusNormal, ExtBytes: []byte("4")}},
+			{Seq: 5, Status: &libModel.WorkerStatus{Code: libModel.WorkerStatusNormal, ExtBytes: []byte("5")}},
+		},
+	}
+	suite.manager.OnWorkerStatusReplayMessage(replay)
+	event := suite.WaitForEvent(t, "worker-1")
+	require.Equal(t, workerStatusUpdatedEvent, event.Tp)
+	require.Equal(t, []byte("5"), event.Handle.Status().ExtBytes)
+
+	// the replay is delivered only once, and no more requests are sent
+	suite.manager.OnWorkerStatusReplayMessage(replay)
+	suite.AssertNoEvents(t, "worker-1", 100*time.Millisecond)
+	for {
+		if _, ok := messageSender.TryPop("executor-1", topic); !ok {
+			break
+		}
+	}
+	suite.SimulateHeartbeat("worker-1", 1, "executor-1", false)
+	_, ok = messageSender.TryPop("executor-1", topic)
+	require.False(t, ok)
+
+	suite.Close()
+}
+
+func TestWorkerManagerDebugInfo(t *testing.T) {
+	t.Parallel()
+
+	suite := NewWorkerManageTestSuite(true)
+	suite.manager.BeforeStartingWorker("worker-1", "executor-1")
+	suite.SimulateHeartbeat("worker-1", 1, "executor-1", false)
+	event := suite.WaitForEvent(t, "worker-1")
+	require.Equal(t, workerOnlineEvent, event.Tp)
+
+	info := suite.manager.DebugInfo()
+	require.False(t, info.Locked)
+	require.Equal(t, "ready", info.State)
+	require.Len(t, info.Workers, 1)
+	require.Equal(t, libModel.WorkerID("worker-1"), info.Workers[0].ID)
+	require.Equal(t, "executor-1", string(info.Workers[0].ExecutorID))
+	require.Equal(t, "normal", info.Workers[0].State)
+	require.False(t, info.Workers[0].HeartbeatAt.IsZero())
+
+	// the workers are not dumped while the manager is locked
+	suite.manager.mu.Lock()
+	info = suite.manager.DebugInfo()
+	suite.manager.mu.Unlock()
+	require.True(t, info.Locked)
+	require.Empty(t, info.Workers)
+	suite.Close()
+}
+
+func TestWorkerManagerDebugDump(t *testing.T) {
+	t.Parallel()
+
+	suite := NewWorkerManageTestSuite(true)
+	suite.manager.BeforeStartingWorker("worker-2", "executor-2")
+	suite.manager.BeforeStartingWorker("worker-1", "executor-1")
+	suite.SimulateHeartbeat("worker-1", 1, "executor-1", false)
+	event := suite.WaitForEvent(t, "worker-1")
+	require.Equal(t, workerOnlineEvent, event.Tp)
+
+	require.Equal(t, "master master-1 epoch 1 pending-events 0 state ready\n"+
+		"worker worker-1 executor executor-1 state normal path created->normal status 2\n"+
+		"worker worker-2 executor executor-2 state created path created status 2\n",
+		suite.manager.DebugDump())
+	suite.Close()
+}
+
+func TestWorkerManagerListWorkers(t *testing.T) {
+	t.Parallel()
+
+	suite := NewWorkerManageTestSuite(true)
+	suite.manager.BeforeStartingWorker("worker-1", "executor-1")
+	suite.manager.BeforeStartingWorker("worker-2", "executor-2")
+	suite.manager.BeforeStartingWorker("worker-3", "executor-1")
+	suite.manager.BeforeStartingWorker("worker-4", "executor-1")
+	suite.SimulateHeartbeat("worker-3", 1, "executor-1", false)
+	event := suite.WaitForEvent(t, "worker-3")
+	require.Equal(t, workerOnlineEvent, event.Tp)
+	err := suite.SimulateWorkerUpdateStatus("worker-3", &libModel.WorkerStatus{
+		JobID:        "master-1",
+		ID:           "worker-3",
+		Code:         libModel.WorkerStatusError,
+		ErrorMessage: "fake error",
+	}, 1)
+	require.NoError(t, err)
+	event = suite.WaitForEvent(t, "worker-3")
+	require.Equal(t, workerStatusUpdatedEvent, event.Tp)
+
+	ids := func(workers []*libModel.WorkerSummary) []libModel.WorkerID {
+		ret := make([]libModel.WorkerID, 0, len(workers))
+		for _, w := range workers {
+			ret = append(ret, w.ID)
+		}
+		return ret
+	}
+
+	workers, next := suite.manager.ListWorkers(&libModel.WorkersQuery{})
+	require.Equal(t, []libModel.WorkerID{"worker-1", "worker-2", "worker-3", "worker-4"}, ids(workers))
+	require.Empty(t, next)
+	require.Equal(t, "created", workers[0].State)
+	require.Equal(t, libModel.WorkerStatusCreated, workers[0].StatusCode)
+	require.Equal(t, "normal", workers[2].State)
+	require.Equal(t, libModel.WorkerStatusError, workers[2].StatusCode)
+	require.Equal(t, "fake error", workers[2].ErrorMessage)
+
+	// pages of the workers on executor-1
+	query := &libModel.WorkersQuery{ExecutorID: "executor-1", PageSize: 2}
+	workers, next = suite.manager.ListWorkers(query)
+	require.Equal(t, []libModel.WorkerID{"worker-1", "worker-3"}, ids(workers))
+	require.Equal(t, libModel.WorkerID("worker-3"), next)
+	query.PageToken = next
+	workers, next = suite.manager.ListWorkers(query)
+	require.Equal(t, []libModel.WorkerID{"worker-4"}, ids(workers))
+	require.Empty(t, next)
+
+	workers, _ = suite.manager.ListWorkers(&libModel.WorkersQuery{
+		StatusCodes: []libModel.WorkerStatusCode{libModel.WorkerStatusError, libModel.WorkerStatusFinished},
+	})
+	require.Equal(t, []libModel.WorkerID{"worker-3"}, ids(workers))
+	suite.Close()
+}
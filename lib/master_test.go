@@ -12,9 +12,14 @@ import (
 	"github.com/hanfei1991/microcosm/lib/metadata"
 	libModel "github.com/hanfei1991/microcosm/lib/model"
 	"github.com/hanfei1991/microcosm/lib/statusutil"
+	"github.com/hanfei1991/microcosm/model"
+	"github.com/hanfei1991/microcosm/pb"
+	dcontext "github.com/hanfei1991/microcosm/pkg/context"
+	"github.com/hanfei1991/microcosm/pkg/deps"
 	derror "github.com/hanfei1991/microcosm/pkg/errors"
 	resourcemeta "github.com/hanfei1991/microcosm/pkg/externalresource/resourcemeta/model"
 	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+	"github.com/hanfei1991/microcosm/pkg/quota"
 	"github.com/hanfei1991/microcosm/pkg/uuid"
 )
 
@@ -67,7 +72,10 @@ func TestMasterInit(t *testing.T) {
 	master.timeoutConfig.WorkerTimeoutDuration = 10 * time.Millisecond
 	master.timeoutConfig.WorkerTimeoutGracefulDuration = 10 * time.Millisecond
 
-	master.On("OnMasterRecovered", mock.Anything).Return(nil)
+	master.On("OnMasterRecovered", mock.MatchedBy(func(ctx context.Context) bool {
+		report, ok := RecoveryReportFromContext(ctx)
+		return ok && report.Epoch > report.PrevEpoch && len(report.ReconnectedWorkers) == 0
+	})).Return(nil)
 	err = master.Init(ctx)
 	require.NoError(t, err)
 
@@ -218,6 +226,87 @@ func TestMasterCreateWorker(t *testing.T) {
 	}, 1*time.Second, 10*time.Millisecond)
 }
 
+func TestMasterCreateWorkerConcurrency(t *testing.T) {
+	t.Parallel()
+
+	meta, err := (&libModel.MasterMetaKVData{
+		ID:                         masterName,
+		MaxCreateWorkerConcurrency: 1,
+	}).Marshal()
+	require.NoError(t, err)
+	ctx := dcontext.Background()
+	ctx.Environ.MasterMetaBytes = meta
+
+	mockMaster := NewMockMasterImpl("", masterName)
+	dp := deps.NewDeps()
+	err = dp.Provide(func() masterParamListForTest {
+		return masterParamListForTest{
+			MessageHandlerManager: mockMaster.messageHandlerManager,
+			MessageSender:         mockMaster.messageSender,
+			FrameMetaClient:       mockMaster.frameMetaClient,
+			UserRawKVClient:       mockMaster.userRawKVClient,
+			ExecutorClientManager: mockMaster.executorClientManager,
+			ServerMasterClient:    mockMaster.serverMasterClient,
+		}
+	})
+	require.NoError(t, err)
+	master := NewBaseMaster(ctx.WithDeps(dp), mockMaster, masterName).(*DefaultBaseMaster)
+
+	// the quota is released if the config can't be encoded
+	_, err = master.CreateWorker(workerTypePlaceholder, make(chan int), 100)
+	require.Error(t, err)
+	require.Equal(t, int32(0), master.CreatingWorkerCount())
+
+	// the concurrency in master meta overrides the default one
+	require.True(t, master.createWorkerQuota.TryConsume())
+	require.False(t, master.createWorkerQuota.TryConsume())
+}
+
+func TestMasterCreateWorkerQueuedQuota(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	master := NewMockMasterImpl("", masterName)
+	master.timeoutConfig.MasterHeartbeatCheckLoopInterval = time.Millisecond * 10
+	master.uuidGen = uuid.NewMock()
+	prepareMeta(ctx, t, master.GetFrameMetaClient())
+	master.On("InitImpl", mock.Anything).Return(nil)
+	require.NoError(t, master.Init(ctx))
+	master.createWorkerQuota = quota.NewConcurrencyQuota(1)
+
+	// the queued request waits in the schedule queue until placed is closed
+	placed := make(chan struct{})
+	scheduled := make(chan struct{})
+	master.serverMasterClient.On("ScheduleTask",
+		mock.Anything, mock.MatchedBy(func(req *pb.ScheduleTaskRequest) bool {
+			return req.GetQueued()
+		}), mock.Anything).
+		Return(&pb.ScheduleTaskResponse{}, derror.ErrScheduleQueueTimeout.FastGenByArgs("worker-queued")).
+		Run(func(mock.Arguments) {
+			close(scheduled)
+			<-placed
+		})
+	master.uuidGen.(*uuid.MockGenerator).Push("worker-queued")
+	_, err := master.CreateWorkerQueued(workerTypePlaceholder, &dummyConfig{param: 1}, model.RescUnit(100).Vector(), 0, time.Minute)
+	require.NoError(t, err)
+	<-scheduled
+
+	// the queued request doesn't hold the quota while waiting
+	require.Equal(t, int32(0), master.CreatingWorkerCount())
+	master.serverMasterClient.On("ScheduleTask",
+		mock.Anything, mock.MatchedBy(func(req *pb.ScheduleTaskRequest) bool {
+			return !req.GetQueued()
+		}), mock.Anything).
+		Return(&pb.ScheduleTaskResponse{}, derror.ErrClusterResourceNotEnough.FastGenByArgs())
+	master.uuidGen.(*uuid.MockGenerator).Push(workerID1)
+	_, err = master.CreateWorker(workerTypePlaceholder, &dummyConfig{param: 1}, 100)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), master.CreatingWorkerCount())
+	close(placed)
+}
+
 func TestMasterCreateWorkerMetError(t *testing.T) {
 	t.Parallel()
 
@@ -79,6 +79,17 @@ func (r *MessageRouter) AppendMessage(topic p2p.Topic, msg p2p.MessageValue) {
 	}
 }
 
+// TryAppendMessage appends a new message into buffer, it returns false
+// without evicting any message if the buffer is full.
+func (r *MessageRouter) TryAppendMessage(topic p2p.Topic, msg p2p.MessageValue) bool {
+	select {
+	case r.buffer <- messageWrapper{topic: topic, msg: msg}:
+		return true
+	default:
+		return false
+	}
+}
+
 func (r *MessageRouter) onError(err error) {
 	select {
 	case r.errCh <- err:
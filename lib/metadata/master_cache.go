@@ -0,0 +1,140 @@
+package metadata
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pingcap/errors"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+)
+
+// maxMasterMetaUpdateRetries is the max number of times MasterMetadataCache
+// retries an update after the metadata is modified by others.
+const maxMasterMetaUpdateRetries = 3
+
+// MasterMetadataCache is a read-through cache of the metadata of a master,
+// so that the master doesn't load its metadata from metastore every time it
+// modifies the metadata.
+// The cached metadata is written back with a compare-and-swap on its revision,
+// if the metadata has been modified by others since it is cached, the cache
+// is invalidated and the modification is applied to the latest metadata.
+type MasterMetadataCache struct {
+	masterID   libModel.MasterID
+	metaClient pkgOrm.Client
+
+	mu sync.Mutex
+	// cached is nil if the cache is invalid
+	cached *libModel.MasterMetaKVData
+}
+
+// NewMasterMetadataCache creates a new MasterMetadataCache
+func NewMasterMetadataCache(
+	masterID libModel.MasterID,
+	metaClient pkgOrm.Client,
+) *MasterMetadataCache {
+	return &MasterMetadataCache{
+		masterID:   masterID,
+		metaClient: metaClient,
+	}
+}
+
+// Load works like MasterMetadataClient.Load, but returns the cached metadata
+// if it is valid. The returned metadata is a copy of the cached one.
+func (c *MasterMetadataCache) Load(ctx context.Context) (*libModel.MasterMetaKVData, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	meta, err := c.loadLocked(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return cloneMasterMeta(meta), nil
+}
+
+// Fill caches the metadata loaded by the caller.
+func (c *MasterMetadataCache) Fill(meta *libModel.MasterMetaKVData) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cached = cloneMasterMeta(meta)
+}
+
+// Invalidate drops the cached metadata, so that it is loaded from metastore
+// on the next access.
+func (c *MasterMetadataCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cached = nil
+}
+
+// Update modifies the metadata by fn and writes it to metastore, fn may be
+// called more than once if the metadata is modified by others concurrently.
+// It returns a copy of the modified metadata. If the metadata doesn't exist
+// in metastore, fn modifies the default metadata returned by
+// MasterMetadataClient.Load and nothing is written.
+func (c *MasterMetadataCache) Update(
+	ctx context.Context, fn func(meta *libModel.MasterMetaKVData),
+) (*libModel.MasterMetaKVData, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := 0; ; i++ {
+		meta, err := c.loadLocked(ctx)
+		if err != nil {
+			return nil, err
+		}
+		newMeta := cloneMasterMeta(meta)
+		fn(newMeta)
+		if c.cached == nil {
+			// the metadata doesn't exist
+			return newMeta, nil
+		}
+
+		err = c.metaClient.CompareAndUpdateJob(ctx, newMeta, meta.Revision)
+		if err == nil {
+			newMeta.Revision = meta.Revision + 1
+			c.cached = newMeta
+			return cloneMasterMeta(newMeta), nil
+		}
+		c.cached = nil
+		if !derror.ErrMetaRevisionUnmatch.Equal(err) || i >= maxMasterMetaUpdateRetries {
+			return nil, errors.Trace(err)
+		}
+	}
+}
+
+// loadLocked returns the cached metadata, or loads it from metastore if the
+// cache is invalid. The default metadata is returned without being cached if
+// it doesn't exist.
+func (c *MasterMetadataCache) loadLocked(ctx context.Context) (*libModel.MasterMetaKVData, error) {
+	if c.cached != nil {
+		return c.cached, nil
+	}
+	meta, err := c.metaClient.GetJobByID(ctx, c.masterID)
+	if err != nil {
+		if pkgOrm.IsNotFoundError(err) {
+			return &libModel.MasterMetaKVData{
+				ID:         c.masterID,
+				StatusCode: libModel.MasterStatusUninit,
+			}, nil
+		}
+		return nil, errors.Trace(err)
+	}
+	c.cached = meta
+	return meta, nil
+}
+
+func cloneMasterMeta(meta *libModel.MasterMetaKVData) *libModel.MasterMetaKVData {
+	clone := *meta
+	clone.Config = append([]byte(nil), meta.Config...)
+	if meta.ResourceSpec != nil {
+		spec := *meta.ResourceSpec
+		spec.WorkerResources = spec.WorkerResources.Clone()
+		clone.ResourceSpec = &spec
+	}
+	return &clone
+}
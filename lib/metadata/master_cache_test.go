@@ -0,0 +1,77 @@
+package metadata
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+)
+
+func TestMasterMetadataCache(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	metaClient, err := pkgOrm.NewMockClient()
+	require.NoError(t, err)
+	err = metaClient.UpsertJob(ctx, &libModel.MasterMetaKVData{
+		ID:         "master-1",
+		Tp:         fakeJobMaster,
+		Addr:       "127.0.0.1:10000",
+		StatusCode: libModel.MasterStatusUninit,
+	})
+	require.NoError(t, err)
+
+	cache := NewMasterMetadataCache("master-1", metaClient)
+	meta, err := cache.Load(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), meta.Revision)
+	// the returned metadata is a copy
+	meta.StatusCode = libModel.MasterStatusStopped
+
+	meta, err = cache.Update(ctx, func(meta *libModel.MasterMetaKVData) {
+		require.Equal(t, libModel.MasterStatusUninit, meta.StatusCode)
+		meta.StatusCode = libModel.MasterStatusInit
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), meta.Revision)
+	persisted, err := metaClient.GetJobByID(ctx, "master-1")
+	require.NoError(t, err)
+	require.Equal(t, libModel.MasterStatusInit, persisted.StatusCode)
+	require.Equal(t, int64(1), persisted.Revision)
+
+	// the metadata is modified by others, the cache is not aware of it
+	// until it writes the metadata
+	persisted.Addr = "127.0.0.1:10001"
+	require.NoError(t, metaClient.UpdateJob(ctx, persisted))
+	meta, err = cache.Load(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "127.0.0.1:10000", meta.Addr)
+
+	calls := 0
+	meta, err = cache.Update(ctx, func(meta *libModel.MasterMetaKVData) {
+		calls++
+		meta.StatusCode = libModel.MasterStatusFinished
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+	require.Equal(t, "127.0.0.1:10001", meta.Addr)
+	require.Equal(t, int64(3), meta.Revision)
+	persisted, err = metaClient.GetJobByID(ctx, "master-1")
+	require.NoError(t, err)
+	require.Equal(t, libModel.MasterStatusFinished, persisted.StatusCode)
+	require.Equal(t, "127.0.0.1:10001", persisted.Addr)
+
+	// nothing is written if the metadata doesn't exist
+	cache = NewMasterMetadataCache("master-2", metaClient)
+	meta, err = cache.Update(ctx, func(meta *libModel.MasterMetaKVData) {
+		meta.Epoch = 1
+	})
+	require.NoError(t, err)
+	require.Equal(t, libModel.MasterStatusUninit, meta.StatusCode)
+	require.Equal(t, int64(1), meta.Epoch)
+	_, err = metaClient.GetJobByID(ctx, "master-2")
+	require.True(t, pkgOrm.IsNotFoundError(err))
+}
@@ -5,10 +5,10 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tiflow/dm/pkg/log"
-	"go.uber.org/zap"
 
 	libModel "github.com/hanfei1991/microcosm/lib/model"
 	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+	"github.com/hanfei1991/microcosm/pkg/redact"
 )
 
 // JobManagerUUID defines the global unique id for job manager
@@ -51,6 +51,30 @@ func (c *MasterMetadataClient) Load(ctx context.Context) (*libModel.MasterMetaKV
 	return masterMeta, nil
 }
 
+// LoadWithWorkers works like Load, and also loads all workers of the master.
+// The master metadata and the workers are read in a consistent snapshot.
+func (c *MasterMetadataClient) LoadWithWorkers(ctx context.Context) (
+	*libModel.MasterMetaKVData, map[libModel.WorkerID]*libModel.WorkerStatus, error,
+) {
+	var (
+		masterMeta *libModel.MasterMetaKVData
+		workers    map[libModel.WorkerID]*libModel.WorkerStatus
+	)
+	err := c.metaClient.SnapshotRead(ctx, func(snapshot pkgOrm.Client) error {
+		var err error
+		masterMeta, err = NewMasterMetadataClient(c.masterID, snapshot).Load(ctx)
+		if err != nil {
+			return err
+		}
+		workers, err = NewWorkerMetadataClient(c.masterID, snapshot).LoadAllWorkers(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return masterMeta, workers, nil
+}
+
 // Store upsert the data
 func (c *MasterMetadataClient) Store(ctx context.Context, data *libModel.MasterMetaKVData) error {
 	return errors.Trace(c.metaClient.UpsertJob(ctx, data))
@@ -81,6 +105,9 @@ func (c *MasterMetadataClient) LoadAllMasters(ctx context.Context) ([]*libModel.
 type WorkerMetadataClient struct {
 	masterID   libModel.MasterID
 	metaClient pkgOrm.Client
+	// statusBatcher batches Update with the updates of other workers if
+	// it is not nil.
+	statusBatcher *WorkerStatusBatcher
 }
 
 // NewWorkerMetadataClient creates a new WorkerMetadataClient instance
@@ -94,6 +121,13 @@ func NewWorkerMetadataClient(
 	}
 }
 
+// WithStatusBatcher makes Update persist the worker metadata through the
+// given batcher, a nil batcher disables batching.
+func (c *WorkerMetadataClient) WithStatusBatcher(batcher *WorkerStatusBatcher) *WorkerMetadataClient {
+	c.statusBatcher = batcher
+	return c
+}
+
 // LoadAllWorkers queries all workers of this master
 func (c *WorkerMetadataClient) LoadAllWorkers(ctx context.Context) (map[libModel.WorkerID]*libModel.WorkerStatus, error) {
 	resp, err := c.metaClient.QueryWorkersByMasterID(ctx, c.masterID)
@@ -108,6 +142,38 @@ func (c *WorkerMetadataClient) LoadAllWorkers(ctx context.Context) (map[libModel
 	return res, nil
 }
 
+// LoadWorkerIndex queries the status codes of all workers of this master,
+// it is much cheaper than LoadAllWorkers for a master with lots of workers.
+func (c *WorkerMetadataClient) LoadWorkerIndex(ctx context.Context) (map[libModel.WorkerID]libModel.WorkerStatusCode, error) {
+	resp, err := c.metaClient.QueryWorkerIndexByMasterID(ctx, c.masterID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	res := make(map[libModel.WorkerID]libModel.WorkerStatusCode, len(resp))
+	for _, m := range resp {
+		res[m.ID] = m.Code
+	}
+	return res, nil
+}
+
+// LoadWorkers queries the given workers of this master, the workers not
+// found are absent in the result.
+func (c *WorkerMetadataClient) LoadWorkers(
+	ctx context.Context, workerIDs []libModel.WorkerID,
+) (map[libModel.WorkerID]*libModel.WorkerStatus, error) {
+	resp, err := c.metaClient.QueryWorkersByIDs(ctx, c.masterID, workerIDs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	res := make(map[libModel.WorkerID]*libModel.WorkerStatus, len(resp))
+	for _, m := range resp {
+		res[m.ID] = m
+	}
+	return res, nil
+}
+
 // Load queries a worker by its worker id
 func (c *WorkerMetadataClient) Load(ctx context.Context, workerID libModel.WorkerID) (*libModel.WorkerStatus, error) {
 	resp, err := c.metaClient.GetWorkerByID(ctx, c.masterID, workerID)
@@ -134,6 +200,9 @@ func (c *WorkerMetadataClient) Store(ctx context.Context, data *libModel.WorkerS
 
 // Update updates a worker metadata
 func (c *WorkerMetadataClient) Update(ctx context.Context, data *libModel.WorkerStatus) error {
+	if c.statusBatcher != nil {
+		return c.statusBatcher.Update(ctx, data)
+	}
 	return errors.Trace(c.metaClient.UpdateWorker(ctx, data))
 }
 
@@ -153,7 +222,7 @@ func StoreMasterMeta(
 	if err != nil {
 		return err
 	}
-	log.L().Warn("master meta exits, will be overwritten", zap.Any("old-meta", masterMeta), zap.Any("meta", meta))
+	log.L().Warn("master meta exits, will be overwritten", redact.Any("old-meta", masterMeta), redact.Any("meta", meta))
 
 	return metaCli.Store(ctx, meta)
 }
@@ -188,3 +188,33 @@ func TestLoadAllWorkers(t *testing.T) {
 		},
 	)
 }
+
+func TestLoadMasterWithWorkers(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	metaClient, err := pkgOrm.NewMockClient()
+	require.Nil(t, err)
+
+	masterCli := NewMasterMetadataClient("master-1", metaClient)
+	err = masterCli.Store(ctx, &libModel.MasterMetaKVData{ID: "master-1", Tp: fakeJobMaster})
+	require.Nil(t, err)
+	workerCli := NewWorkerMetadataClient("master-1", metaClient)
+	for _, id := range []libModel.WorkerID{"worker-1", "worker-2"} {
+		err := workerCli.Store(ctx, &libModel.WorkerStatus{JobID: "master-1", ID: id})
+		require.Nil(t, err)
+	}
+
+	meta, workers, err := masterCli.LoadWithWorkers(ctx)
+	require.Nil(t, err)
+	require.Equal(t, "master-1", meta.ID)
+	require.Len(t, workers, 2)
+	require.Contains(t, workers, "worker-1")
+	require.Contains(t, workers, "worker-2")
+
+	// the master meta does not exist
+	meta, workers, err = NewMasterMetadataClient("master-2", metaClient).LoadWithWorkers(ctx)
+	require.Nil(t, err)
+	require.Equal(t, libModel.MasterStatusUninit, meta.StatusCode)
+	require.Len(t, workers, 0)
+}
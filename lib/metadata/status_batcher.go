@@ -0,0 +1,170 @@
+package metadata
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+)
+
+// DefaultStatusBatchSize is the default max number of worker statuses
+// persisted in one batch.
+const DefaultStatusBatchSize = 256
+
+type workerKey struct {
+	jobID    libModel.MasterID
+	workerID libModel.WorkerID
+}
+
+// statusBatch is the worker statuses persisted in one transaction, the
+// waiters of the batch are notified by closing done.
+type statusBatch struct {
+	statuses []*libModel.WorkerStatus
+	index    map[workerKey]int
+	done     chan struct{}
+	err      error
+}
+
+func newStatusBatch() *statusBatch {
+	return &statusBatch{
+		index: make(map[workerKey]int),
+		done:  make(chan struct{}),
+	}
+}
+
+// add adds a status to the batch, it replaces the status of the same worker
+// added before, as only the latest status needs to be persisted.
+func (b *statusBatch) add(status *libModel.WorkerStatus) {
+	key := workerKey{jobID: status.JobID, workerID: status.ID}
+	if i, ok := b.index[key]; ok {
+		b.statuses[i] = status
+		return
+	}
+	b.index[key] = len(b.statuses)
+	b.statuses = append(b.statuses, status)
+}
+
+// WorkerStatusBatcher coalesces the status updates of workers and persists
+// them in batches, so that the workers of an executor don't overwhelm the
+// metastore with a transaction per status update.
+// A batch is persisted every flush interval, or as soon as it has reached
+// the max batch size. Update returns after the batch containing the status
+// has been persisted, so the semantics of WorkerMetadataClient.Update are
+// kept.
+type WorkerStatusBatcher struct {
+	metaClient    pkgOrm.Client
+	flushInterval time.Duration
+	maxBatchSize  int
+
+	mu      sync.Mutex
+	pending *statusBatch
+	// closed is set after Run returns, Update falls back to persisting the
+	// status directly after that.
+	closed bool
+
+	flushCh chan struct{}
+}
+
+// NewWorkerStatusBatcher creates a new WorkerStatusBatcher, a non-positive
+// maxBatchSize means DefaultStatusBatchSize.
+func NewWorkerStatusBatcher(
+	metaClient pkgOrm.Client,
+	flushInterval time.Duration,
+	maxBatchSize int,
+) *WorkerStatusBatcher {
+	if maxBatchSize <= 0 {
+		maxBatchSize = DefaultStatusBatchSize
+	}
+	return &WorkerStatusBatcher{
+		metaClient:    metaClient,
+		flushInterval: flushInterval,
+		maxBatchSize:  maxBatchSize,
+		pending:       newStatusBatch(),
+		flushCh:       make(chan struct{}, 1),
+	}
+}
+
+// Update adds the status to the pending batch and waits until the batch
+// has been persisted.
+func (b *WorkerStatusBatcher) Update(ctx context.Context, status *libModel.WorkerStatus) error {
+	if status == nil {
+		return errors.Trace(b.metaClient.UpdateWorker(ctx, status))
+	}
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return errors.Trace(b.metaClient.UpdateWorker(ctx, status))
+	}
+	batch := b.pending
+	batch.add(status)
+	full := len(batch.statuses) >= b.maxBatchSize
+	b.mu.Unlock()
+
+	if full {
+		select {
+		case b.flushCh <- struct{}{}:
+		default:
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+		return errors.Trace(ctx.Err())
+	case <-batch.done:
+		return batch.err
+	}
+}
+
+// Run persists the pending batches until ctx is canceled.
+func (b *WorkerStatusBatcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(b.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			b.close(ctx.Err())
+			return errors.Trace(ctx.Err())
+		case <-ticker.C:
+		case <-b.flushCh:
+		}
+		b.flush(ctx)
+	}
+}
+
+func (b *WorkerStatusBatcher) flush(ctx context.Context) {
+	b.mu.Lock()
+	batch := b.pending
+	if len(batch.statuses) == 0 {
+		b.mu.Unlock()
+		return
+	}
+	b.pending = newStatusBatch()
+	b.mu.Unlock()
+
+	err := b.metaClient.UpdateWorkers(ctx, batch.statuses)
+	if err != nil {
+		// the writers retry on the error
+		log.L().Warn("failed to persist worker statuses",
+			zap.Int("count", len(batch.statuses)), zap.Error(err))
+		batch.err = errors.Trace(err)
+	}
+	close(batch.done)
+}
+
+func (b *WorkerStatusBatcher) close(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	b.pending.err = errors.Trace(err)
+	close(b.pending.done)
+	b.pending = newStatusBatch()
+}
@@ -0,0 +1,105 @@
+package metadata
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+)
+
+func TestStatusBatchCoalesce(t *testing.T) {
+	t.Parallel()
+
+	batch := newStatusBatch()
+	batch.add(&libModel.WorkerStatus{JobID: "master-1", ID: "worker-1", Code: libModel.WorkerStatusNormal})
+	batch.add(&libModel.WorkerStatus{JobID: "master-1", ID: "worker-2", Code: libModel.WorkerStatusNormal})
+	batch.add(&libModel.WorkerStatus{JobID: "master-1", ID: "worker-1", Code: libModel.WorkerStatusFinished})
+	// workers of different jobs are not coalesced
+	batch.add(&libModel.WorkerStatus{JobID: "master-2", ID: "worker-1", Code: libModel.WorkerStatusNormal})
+	require.Len(t, batch.statuses, 3)
+	require.Equal(t, libModel.WorkerStatusFinished, batch.statuses[0].Code)
+	require.Equal(t, "worker-2", batch.statuses[1].ID)
+	require.Equal(t, "master-2", batch.statuses[2].JobID)
+}
+
+func TestWorkerStatusBatcher(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	metaClient, err := pkgOrm.NewMockClient()
+	require.NoError(t, err)
+	workerIDs := []libModel.WorkerID{"worker-1", "worker-2"}
+	for _, workerID := range workerIDs {
+		err := metaClient.UpsertWorker(ctx, &libModel.WorkerStatus{
+			JobID: "master-1",
+			ID:    workerID,
+			Code:  libModel.WorkerStatusInit,
+		})
+		require.NoError(t, err)
+	}
+
+	// the batches are only flushed when they are full in the test
+	batcher := NewWorkerStatusBatcher(metaClient, time.Hour, len(workerIDs))
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = batcher.Run(ctx)
+	}()
+	cli := NewWorkerMetadataClient("master-1", metaClient).WithStatusBatcher(batcher)
+
+	var eg errgroup.Group
+	for _, workerID := range workerIDs {
+		workerID := workerID
+		eg.Go(func() error {
+			return cli.Update(ctx, &libModel.WorkerStatus{
+				JobID: "master-1",
+				ID:    workerID,
+				Code:  libModel.WorkerStatusNormal,
+			})
+		})
+	}
+	require.NoError(t, eg.Wait())
+	workers, err := cli.LoadAllWorkers(ctx)
+	require.NoError(t, err)
+	require.Len(t, workers, len(workerIDs))
+	for _, workerID := range workerIDs {
+		require.Equal(t, libModel.WorkerStatusNormal, workers[workerID].Code)
+	}
+
+	// the pending updates fail when the batcher exits
+	updateErrCh := make(chan error, 1)
+	go func() {
+		updateErrCh <- cli.Update(context.Background(), &libModel.WorkerStatus{
+			JobID: "master-1",
+			ID:    "worker-1",
+			Code:  libModel.WorkerStatusFinished,
+		})
+	}()
+	require.Eventually(t, func() bool {
+		batcher.mu.Lock()
+		defer batcher.mu.Unlock()
+		return len(batcher.pending.statuses) == 1
+	}, time.Second, 10*time.Millisecond)
+	cancel()
+	wg.Wait()
+	require.Error(t, <-updateErrCh)
+
+	// the updates are persisted directly after the batcher exits
+	err = cli.Update(context.Background(), &libModel.WorkerStatus{
+		JobID: "master-1",
+		ID:    "worker-1",
+		Code:  libModel.WorkerStatusFinished,
+	})
+	require.NoError(t, err)
+	status, err := cli.Load(context.Background(), "worker-1")
+	require.NoError(t, err)
+	require.Equal(t, libModel.WorkerStatusFinished, status.Code)
+}
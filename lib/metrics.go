@@ -0,0 +1,28 @@
+package lib
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var tickDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: "dataflow",
+		Subsystem: "master",
+		Name:      "tick_duration_seconds",
+		Help:      "duration of each phase in a Poll of a master",
+		Buckets:   prometheus.ExponentialBuckets(0.0001, 2, 18), // 100us ~ 13s
+	}, []string{"job", "phase"})
+
+var tickOverrunCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "dataflow",
+		Subsystem: "master",
+		Name:      "tick_overrun_total",
+		Help:      "number of times that a phase in a Poll of a master exceeded the threshold",
+	}, []string{"job", "phase"})
+
+// InitMetrics registers the master framework metrics
+func InitMetrics(registry *prometheus.Registry) {
+	registry.MustRegister(tickDuration)
+	registry.MustRegister(tickOverrunCounter)
+}
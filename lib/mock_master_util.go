@@ -75,7 +75,9 @@ func MockBaseMasterCreateWorker(
 	master.uuidGen = uuid.NewMock()
 	expectedSchedulerReq := &pb.ScheduleTaskRequest{
 		TaskId:               workerID,
+		JobId:                masterID,
 		Cost:                 int64(cost),
+		Resources:            cost.Vector(),
 		ResourceRequirements: resources,
 	}
 	master.serverMasterClient.(*client.MockServerMasterClient).On(
@@ -100,6 +102,7 @@ func MockBaseMasterCreateWorker(
 			MasterID:     masterID,
 			WorkerType:   int64(workerType),
 			WorkerConfig: configBytes,
+			Resources:    cost.Vector(),
 		}, mock.Anything, mock.Anything).
 		Return(nil).
 		Run(func(args mock.Arguments) {
@@ -123,8 +126,10 @@ func MockBaseMasterCreateWorkerMetScheduleTaskError(
 ) {
 	master.uuidGen = uuid.NewMock()
 	expectedSchedulerReq := &pb.ScheduleTaskRequest{
-		TaskId: workerID,
-		Cost:   int64(cost),
+		TaskId:    workerID,
+		JobId:     masterID,
+		Cost:      int64(cost),
+		Resources: cost.Vector(),
 	}
 	master.serverMasterClient.(*client.MockServerMasterClient).On(
 		"ScheduleTask",
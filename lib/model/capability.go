@@ -0,0 +1,76 @@
+package model
+
+import "sort"
+
+// Capability is an optional protocol of the master worker framework. A master
+// and its worker exchange the capabilities they support in heartbeats, and
+// use only the capabilities supported by both of them, so that a job can run
+// with masters and workers of different versions during a rolling upgrade.
+type Capability string
+
+// defines the capabilities of the framework
+const (
+	// CapabilityWorkerMessage means typed worker messages sent by
+	// BaseWorker.SendWorkerMessage are handled by the master.
+	CapabilityWorkerMessage = Capability("worker-message")
+	// CapabilityBarrier means barriers requested by BaseMaster.RequestBarrier
+	// are reported by BaseWorker.ReachBarrier.
+	CapabilityBarrier = Capability("barrier")
+	// CapabilityPeerDiscovery means the master replies to the lookups of the
+	// executors of peer workers, which are used to open data channels
+	// between workers.
+	CapabilityPeerDiscovery = Capability("peer-discovery")
+	// CapabilityWorkersQuery means a job master replies to the queries of
+	// its workers sent by BaseMaster.QueryWorkers.
+	CapabilityWorkersQuery = Capability("workers-query")
+)
+
+// CapabilitySet is a set of capabilities, sorted and without duplicates.
+type CapabilitySet []Capability
+
+// FrameworkCapabilities returns the capabilities supported by this binary.
+func FrameworkCapabilities() CapabilitySet {
+	return NewCapabilitySet(CapabilityWorkerMessage, CapabilityBarrier, CapabilityPeerDiscovery,
+		CapabilityWorkersQuery)
+}
+
+// LegacyCapabilities returns the capabilities assumed for a peer that doesn't
+// exchange capabilities. Such a peer is released before the handshake is
+// introduced, so none of the optional protocols is used with it.
+func LegacyCapabilities() CapabilitySet {
+	return CapabilitySet{}
+}
+
+// NewCapabilitySet creates a CapabilitySet from the given capabilities.
+func NewCapabilitySet(caps ...Capability) CapabilitySet {
+	set := make(CapabilitySet, 0, len(caps))
+	for _, c := range caps {
+		if !set.Has(c) {
+			set = append(set, c)
+		}
+	}
+	sort.Slice(set, func(i, j int) bool { return set[i] < set[j] })
+	return set
+}
+
+// Has returns whether the set contains the capability.
+func (s CapabilitySet) Has(c Capability) bool {
+	for _, c1 := range s {
+		if c1 == c {
+			return true
+		}
+	}
+	return false
+}
+
+// Intersect returns the capabilities contained in both sets, the result is
+// never nil.
+func (s CapabilitySet) Intersect(other CapabilitySet) CapabilitySet {
+	ret := CapabilitySet{}
+	for _, c := range s {
+		if other.Has(c) {
+			ret = append(ret, c)
+		}
+	}
+	return NewCapabilitySet(ret...)
+}
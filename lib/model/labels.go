@@ -0,0 +1,146 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+const maxLabelLength = 63
+
+var labelKeyRegexp = regexp.MustCompile(`^[a-zA-Z0-9]([-a-zA-Z0-9_./]*[a-zA-Z0-9])?$`)
+
+// JobLabels are arbitrary key/value pairs attached to a job, such as
+// team=payment or env=prod, operators group and filter jobs by them.
+type JobLabels map[string]string
+
+// Validate checks whether the labels are valid. A key consists of
+// alphanumerics, '-', '_', '.' and '/', and begins and ends with an
+// alphanumeric. Neither keys nor values are longer than 63 characters.
+func (l JobLabels) Validate() error {
+	for key, value := range l {
+		if len(key) > maxLabelLength || !labelKeyRegexp.MatchString(key) {
+			return errors.Errorf("invalid label key %q", key)
+		}
+		if len(value) > maxLabelLength {
+			return errors.Errorf("value of label %s is longer than %d characters", key, maxLabelLength)
+		}
+	}
+	return nil
+}
+
+// Value implements driver.Valuer, the labels are persisted as JSON.
+func (l JobLabels) Value() (driver.Value, error) {
+	if l == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(map[string]string(l))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return data, nil
+}
+
+// Scan implements sql.Scanner
+func (l *JobLabels) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return errors.Errorf("unexpected type %T of job labels", value)
+	}
+	if len(data) == 0 {
+		*l = nil
+		return nil
+	}
+	return errors.Trace(json.Unmarshal(data, (*map[string]string)(l)))
+}
+
+type labelOp int
+
+const (
+	labelOpEqual labelOp = iota + 1
+	labelOpNotEqual
+	labelOpExists
+	labelOpNotExists
+)
+
+type labelRequirement struct {
+	key   string
+	op    labelOp
+	value string
+}
+
+func (r *labelRequirement) matches(labels JobLabels) bool {
+	value, ok := labels[r.key]
+	switch r.op {
+	case labelOpEqual:
+		return ok && value == r.value
+	case labelOpNotEqual:
+		return !ok || value != r.value
+	case labelOpExists:
+		return ok
+	case labelOpNotExists:
+		return !ok
+	}
+	return false
+}
+
+// LabelSelector selects jobs by their labels, a job is selected if it
+// satisfies all the requirements of the selector.
+type LabelSelector []labelRequirement
+
+// ParseLabelSelector parses a selector of comma separated requirements,
+// each of which is one of
+//
+//	key=value   the label has the value
+//	key!=value  the label does not exist or has a different value
+//	key         the label exists
+//	!key        the label does not exist
+//
+// An empty selector selects all jobs.
+func ParseLabelSelector(selector string) (LabelSelector, error) {
+	var ret LabelSelector
+	for _, term := range strings.Split(selector, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		var req labelRequirement
+		if i := strings.Index(term, "!="); i >= 0 {
+			req = labelRequirement{key: term[:i], op: labelOpNotEqual, value: term[i+2:]}
+		} else if i := strings.Index(term, "="); i >= 0 {
+			req = labelRequirement{key: term[:i], op: labelOpEqual, value: term[i+1:]}
+		} else if strings.HasPrefix(term, "!") {
+			req = labelRequirement{key: term[1:], op: labelOpNotExists}
+		} else {
+			req = labelRequirement{key: term, op: labelOpExists}
+		}
+		req.key = strings.TrimSpace(req.key)
+		req.value = strings.TrimSpace(req.value)
+		if err := (JobLabels{req.key: req.value}).Validate(); err != nil {
+			return nil, errors.Annotatef(err, "requirement %q", term)
+		}
+		ret = append(ret, req)
+	}
+	return ret, nil
+}
+
+// Matches returns whether the labels satisfy the selector.
+func (s LabelSelector) Matches(labels JobLabels) bool {
+	for i := range s {
+		if !s[i].matches(labels) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,79 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobLabelsValidate(t *testing.T) {
+	t.Parallel()
+
+	valid := []JobLabels{
+		nil,
+		{"team": "payment"},
+		{"app.kubernetes.io/name": "", "a": "b"},
+	}
+	for _, labels := range valid {
+		require.NoError(t, labels.Validate(), "%v", labels)
+	}
+	invalid := []JobLabels{
+		{"": "payment"},
+		{"-team": "payment"},
+		{"team ": "payment"},
+		{"te=am": "payment"},
+		{"team": string(make([]byte, 64))},
+	}
+	for _, labels := range invalid {
+		require.Error(t, labels.Validate(), "%v", labels)
+	}
+}
+
+func TestJobLabelsValueScan(t *testing.T) {
+	t.Parallel()
+
+	labels := JobLabels{"team": "payment", "env": "prod"}
+	value, err := labels.Value()
+	require.NoError(t, err)
+	var scanned JobLabels
+	require.NoError(t, scanned.Scan(value))
+	require.Equal(t, labels, scanned)
+
+	value, err = JobLabels(nil).Value()
+	require.NoError(t, err)
+	require.Nil(t, value)
+	require.NoError(t, scanned.Scan(nil))
+	require.Nil(t, scanned)
+	require.Error(t, scanned.Scan(1))
+}
+
+func TestLabelSelector(t *testing.T) {
+	t.Parallel()
+
+	labels := JobLabels{"team": "payment", "env": "prod"}
+	testCases := []struct {
+		selector string
+		matches  bool
+	}{
+		{"", true},
+		{"team=payment", true},
+		{" team = payment , env ", true},
+		{"team=search", false},
+		{"team!=search", true},
+		{"team=payment,env!=prod", false},
+		{"canary", false},
+		{"!canary", true},
+		{"canary!=true", true},
+		{"!env", false},
+	}
+	for _, tc := range testCases {
+		selector, err := ParseLabelSelector(tc.selector)
+		require.NoError(t, err)
+		require.Equal(t, tc.matches, selector.Matches(labels), "selector %s", tc.selector)
+	}
+
+	for _, s := range []string{"=payment", "!", "team=payment,-env", "!=prod"} {
+		_, err := ParseLabelSelector(s)
+		require.Error(t, err, "selector %s", s)
+	}
+}
@@ -30,6 +30,9 @@ var MasterUpdateColumns = []string{
 	"address",
 	"epoch",
 	"config",
+	"max_create_worker_concurrency",
+	"resource_spec",
+	"labels",
 }
 
 // MasterMetaKVData defines the metadata of job master
@@ -37,14 +40,25 @@ type MasterMetaKVData struct {
 	ormModel.Model
 	ProjectID  tenant.ProjectID `json:"project-id" gorm:"column:project_id;type:varchar(64) not null;index:idx_mst,priority:1"`
 	ID         MasterID         `json:"id" gorm:"column:id;type:varchar(64) not null;uniqueIndex:uidx_mid"`
-	Tp         WorkerType       `json:"type" gorm:"column:type;type:tinyint not null"`
-	StatusCode MasterStatusCode `json:"status" gorm:"column:status;type:tinyint not null;index:idx_mst,priority:2"`
+	Tp         WorkerType       `json:"type" gorm:"column:type;type:tinyint not null;index:idx_mtp"`
+	StatusCode MasterStatusCode `json:"status" gorm:"column:status;type:tinyint not null;index:idx_mst,priority:2;index:idx_ms"`
 	NodeID     p2p.NodeID       `json:"node-id" gorm:"column:node_id;type:varchar(64) not null"`
 	Addr       string           `json:"addr" gorm:"column:address;type:varchar(64) not null"`
 	Epoch      Epoch            `json:"epoch" gorm:"column:epoch;type:bigint not null"`
 
-	// Config holds business-specific data
-	Config []byte `json:"config" gorm:"column:config;type:blob"`
+	// Config holds business-specific data, which may carry credentials
+	Config []byte `json:"config" gorm:"column:config;type:blob" redact:"true"`
+	// MaxCreateWorkerConcurrency limits the workers being created by the
+	// master at the same time, 0 means the default limit.
+	MaxCreateWorkerConcurrency int32 `json:"max-create-worker-concurrency,omitempty" gorm:"column:max_create_worker_concurrency;type:int not null default 0"`
+	// ResourceSpec declares the workers of the job, nil means the job
+	// reserves no capacity.
+	ResourceSpec *JobResourceSpec `json:"resource-spec,omitempty" gorm:"column:resource_spec;type:blob"`
+	// Labels are the key/value pairs attached to the job by users.
+	Labels JobLabels `json:"labels,omitempty" gorm:"column:labels;type:blob"`
+	// Revision is increased by every write of the metadata, it is used to
+	// detect concurrent modifications.
+	Revision int64 `json:"revision" gorm:"column:revision;type:bigint not null default 0"`
 	// TODO: add master status and checkpoint data
 
 	// Deleted is a nullable timestamp. Then master is deleted
@@ -65,14 +79,17 @@ func (m *MasterMetaKVData) Unmarshal(data []byte) error {
 // Map is used for update the orm model
 func (m *MasterMetaKVData) Map() map[string]interface{} {
 	return map[string]interface{}{
-		"project_id": m.ProjectID,
-		"id":         m.ID,
-		"type":       m.Tp,
-		"status":     m.StatusCode,
-		"node_id":    m.NodeID,
-		"address":    m.Addr,
-		"epoch":      m.Epoch,
-		"config":     m.Config,
+		"project_id":                    m.ProjectID,
+		"id":                            m.ID,
+		"type":                          m.Tp,
+		"status":                        m.StatusCode,
+		"node_id":                       m.NodeID,
+		"address":                       m.Addr,
+		"epoch":                         m.Epoch,
+		"config":                        m.Config,
+		"max_create_worker_concurrency": m.MaxCreateWorkerConcurrency,
+		"resource_spec":                 m.ResourceSpec,
+		"labels":                        m.Labels,
 	}
 }
 
@@ -1,22 +1,48 @@
 package model
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/hanfei1991/microcosm/pkg/clock"
+	"github.com/hanfei1991/microcosm/pkg/compat"
 	"github.com/hanfei1991/microcosm/pkg/p2p"
 )
 
+const (
+	heartbeatPingTopicPrefix = "heartbeat-ping-"
+	heartbeatPongTopicPrefix = "heartbeat-pong-"
+
+	// HeartbeatPongTTL is the TTL of heartbeat pongs. A pong that is delayed
+	// longer than it is useless, since the worker would have timed out.
+	HeartbeatPongTTL = 30 * time.Second
+)
+
+func init() {
+	p2p.RegisterTopicTTL(heartbeatPongTopicPrefix, HeartbeatPongTTL)
+	// heartbeats are the most frequent messages, they are sent in a binary
+	// encoding to the peers that decode it.
+	p2p.RegisterTopicEncoding(heartbeatPingTopicPrefix, p2p.EncodingMsgpack)
+	p2p.RegisterTopicEncoding(heartbeatPongTopicPrefix, p2p.EncodingMsgpack)
+}
+
 // HeartbeatPingTopic is heartbeat ping message topic, each master has a unique one.
 func HeartbeatPingTopic(masterID MasterID) p2p.Topic {
-	return fmt.Sprintf("heartbeat-ping-%s", masterID)
+	return heartbeatPingTopicPrefix + masterID
 }
 
 // HeartbeatPongTopic is heartbeat pong message topic, each worker has a unique one.
 func HeartbeatPongTopic(masterID MasterID, workerID WorkerID) p2p.Topic {
 	// TODO do we need hex-encoding here?
-	return fmt.Sprintf("heartbeat-pong-%s-%s", masterID, workerID)
+	return fmt.Sprintf("%s%s-%s", heartbeatPongTopicPrefix, masterID, workerID)
+}
+
+// IsHeartbeatTopic returns whether topic is a heartbeat ping or pong topic.
+func IsHeartbeatTopic(topic p2p.Topic) bool {
+	return strings.HasPrefix(topic, heartbeatPingTopicPrefix) ||
+		strings.HasPrefix(topic, heartbeatPongTopicPrefix)
 }
 
 // WorkerStatusChangeRequestTopic message topic used when updating worker status
@@ -24,12 +50,73 @@ func WorkerStatusChangeRequestTopic(masterID MasterID, workerID WorkerID) p2p.To
 	return fmt.Sprintf("worker-status-change-req-%s-%s", masterID, workerID)
 }
 
+// TimeoutsUpdateRequestTopic is the topic used by job manager to update the
+// worker timeouts of a job master.
+func TimeoutsUpdateRequestTopic(masterID MasterID, workerID WorkerID) p2p.Topic {
+	return fmt.Sprintf("timeouts-update-req-%s-%s", masterID, workerID)
+}
+
+// LogLevelUpdateRequestTopic is the topic used by a master to override the
+// log level of the job in the processes of its workers.
+func LogLevelUpdateRequestTopic(masterID MasterID, workerID WorkerID) p2p.Topic {
+	return fmt.Sprintf("log-level-update-req-%s-%s", masterID, workerID)
+}
+
+// TasksOperateRequestTopic is the topic used by job manager to operate the
+// tasks of a job master, the requests are passed to
+// JobMasterImpl.OnJobManagerMessage.
+func TasksOperateRequestTopic(masterID MasterID, workerID WorkerID) p2p.Topic {
+	return fmt.Sprintf("tasks-operate-req-%s-%s", masterID, workerID)
+}
+
+// SourceUpdateRequestTopic is the topic used by job manager to update the
+// config of an upstream source of a job master, the requests are passed to
+// JobMasterImpl.OnJobManagerMessage.
+func SourceUpdateRequestTopic(masterID MasterID, workerID WorkerID) p2p.Topic {
+	return fmt.Sprintf("source-update-req-%s-%s", masterID, workerID)
+}
+
+// WorkersQueryRequestTopic is the topic used by job manager to list the
+// workers of a job master, the requests are handled by the framework.
+func WorkersQueryRequestTopic(masterID MasterID, workerID WorkerID) p2p.Topic {
+	return fmt.Sprintf("workers-query-req-%s-%s", masterID, workerID)
+}
+
+// WorkersQueryResponseTopic is the topic of replies to the workers queries
+// of a master.
+func WorkersQueryResponseTopic(masterID MasterID) p2p.Topic {
+	return fmt.Sprintf("workers-query-resp-%s", masterID)
+}
+
+// WorkerMessageTopic is the topic of typed messages sent from workers to a
+// master, see BaseWorker.SendWorkerMessage.
+func WorkerMessageTopic(masterID MasterID, topic p2p.Topic) p2p.Topic {
+	return fmt.Sprintf("worker-message-%s-%s", masterID, topic)
+}
+
 // HeartbeatPingMessage ships information in heartbeat ping
 type HeartbeatPingMessage struct {
 	SendTime     clock.MonotonicTime `json:"send-time"`
 	FromWorkerID WorkerID            `json:"from-worker-id"`
 	Epoch        Epoch               `json:"epoch"`
 	IsFinished   bool                `json:"is-finished"`
+
+	// ProtocolVersion is the protocol version of the worker, workers of an
+	// old version don't send it.
+	ProtocolVersion compat.ProtocolVersion `json:"protocol-version,omitempty"`
+	// Capabilities are supported by the worker framework.
+	Capabilities CapabilitySet `json:"capabilities,omitempty"`
+	// StatusSeq is the sequence number of the latest status update of the
+	// worker, and PersistedStatusSeq is that of the latest persisted one. A
+	// master that has failed over requests a replay if they differ.
+	StatusSeq          uint64 `json:"status-seq,omitempty"`
+	PersistedStatusSeq uint64 `json:"persisted-status-seq,omitempty"`
+	// CreatingWorkers is the number of workers being created by the worker,
+	// it is only sent by job masters.
+	CreatingWorkers int32 `json:"creating-workers,omitempty"`
+	// Stuck is true if the Tick of the worker has exceeded the deadline of
+	// its liveness probe, see LivenessProbedWorkerImpl.
+	Stuck bool `json:"stuck,omitempty"`
 }
 
 // HeartbeatPongMessage ships information in heartbeat pong
@@ -39,6 +126,24 @@ type HeartbeatPongMessage struct {
 	ToWorkerID WorkerID            `json:"to-worker-id"`
 	Epoch      Epoch               `json:"epoch"`
 	IsFinished bool                `json:"is-finished"`
+
+	// HeartbeatInterval and WorkerTimeout are the timeouts negotiated by the
+	// master, which may be updated while the worker is running. Zero means
+	// the worker keeps its own.
+	HeartbeatInterval time.Duration `json:"heartbeat-interval,omitempty"`
+	WorkerTimeout     time.Duration `json:"worker-timeout,omitempty"`
+	// ProtocolVersion is the lowest protocol version among the master and
+	// its workers, features newer than it must not be used.
+	ProtocolVersion compat.ProtocolVersion `json:"protocol-version,omitempty"`
+	// Capabilities are supported by both the master and the worker. It is
+	// never nil if the master supports the handshake, so omitempty is not
+	// used, and nil means that the master is of an old version.
+	Capabilities CapabilitySet `json:"capabilities"`
+}
+
+// MessageSendTime implements p2p.TimedMessage.MessageSendTime
+func (m *HeartbeatPongMessage) MessageSendTime() time.Time {
+	return m.ReplyTime
 }
 
 // StatusChangeRequest ships information when updating worker status
@@ -48,3 +153,109 @@ type StatusChangeRequest struct {
 	Epoch        Epoch               `json:"epoch"`
 	ExpectState  WorkerStatusCode    `json:"expect-state"`
 }
+
+// TimeoutsUpdateRequest ships the new worker timeouts of a job, zero values
+// mean unchanged.
+type TimeoutsUpdateRequest struct {
+	SendTime     clock.MonotonicTime `json:"send-time"`
+	FromMasterID MasterID            `json:"from-master-id"`
+	Epoch        Epoch               `json:"epoch"`
+
+	WorkerTimeoutDuration         time.Duration `json:"worker-timeout-duration"`
+	WorkerTimeoutGracefulDuration time.Duration `json:"worker-timeout-graceful-duration"`
+	WorkerHeartbeatInterval       time.Duration `json:"worker-heartbeat-interval"`
+}
+
+// LogLevelUpdateRequest ships the log level of a job, which is sent from job
+// manager to the job master, and then forwarded to all workers of the job.
+// An empty Level resets the log level of the job to the global one.
+type LogLevelUpdateRequest struct {
+	SendTime     clock.MonotonicTime `json:"send-time"`
+	FromMasterID MasterID            `json:"from-master-id"`
+	Epoch        Epoch               `json:"epoch"`
+
+	JobID MasterID `json:"job-id"`
+	Level string   `json:"level"`
+}
+
+// TaskOperation is an operation on the tasks of a job
+type TaskOperation string
+
+// Defines all task operations
+const (
+	TaskOperationPause  = TaskOperation("pause")
+	TaskOperationResume = TaskOperation("resume")
+	TaskOperationStop   = TaskOperation("stop")
+)
+
+// TasksOperateRequest ships an operation on the tasks of a job, which is sent
+// from job manager to the job master. Empty Tasks means all the tasks.
+type TasksOperateRequest struct {
+	SendTime     clock.MonotonicTime `json:"send-time"`
+	FromMasterID MasterID            `json:"from-master-id"`
+	Epoch        Epoch               `json:"epoch"`
+
+	Op    TaskOperation `json:"op"`
+	Tasks []string      `json:"tasks,omitempty"`
+}
+
+// SourceUpdateRequest ships the new config of an upstream source of a job,
+// which is sent from job manager to the job master. The format of Config is
+// defined by the job master.
+type SourceUpdateRequest struct {
+	SendTime     clock.MonotonicTime `json:"send-time"`
+	FromMasterID MasterID            `json:"from-master-id"`
+	Epoch        Epoch               `json:"epoch"`
+
+	SourceID string `json:"source-id"`
+	Config   []byte `json:"config"`
+}
+
+// WorkersQuery selects the workers of a master, they are listed in the order
+// of their IDs, one page at a time.
+type WorkersQuery struct {
+	// StatusCodes selects the workers of the status codes, empty means all.
+	StatusCodes []WorkerStatusCode `json:"status-codes,omitempty"`
+	// ExecutorID selects the workers running on the executor, empty means all.
+	ExecutorID string `json:"executor-id,omitempty"`
+	// PageToken is the NextPageToken of the previous page, empty means the
+	// first page.
+	PageToken WorkerID `json:"page-token,omitempty"`
+	// PageSize is the max number of workers in a page, 0 means no limit.
+	PageSize int `json:"page-size,omitempty"`
+}
+
+// WorkersQueryRequest ships a workers query, which is sent from job manager
+// to a job master.
+type WorkersQueryRequest struct {
+	RequestID    uint64   `json:"request-id"`
+	FromMasterID MasterID `json:"from-master-id"`
+	Epoch        Epoch    `json:"epoch"`
+
+	Query WorkersQuery `json:"query"`
+}
+
+// WorkerSummary is the state of a worker kept by its master.
+type WorkerSummary struct {
+	ID           WorkerID         `json:"id"`
+	ExecutorID   string           `json:"executor-id"`
+	State        string           `json:"state"`
+	StatusCode   WorkerStatusCode `json:"status-code"`
+	ErrorMessage string           `json:"error-message,omitempty"`
+	HeartbeatAt  time.Time        `json:"heartbeat-at"`
+}
+
+// WorkersQueryResponse is replied by the job master, NextPageToken is empty
+// if there are no more workers.
+type WorkersQueryResponse struct {
+	RequestID     uint64           `json:"request-id"`
+	Workers       []*WorkerSummary `json:"workers"`
+	NextPageToken WorkerID         `json:"next-page-token,omitempty"`
+}
+
+// WorkerMessage wraps a typed message sent from a worker to its master
+type WorkerMessage struct {
+	FromWorkerID WorkerID        `json:"from-worker-id"`
+	Epoch        Epoch           `json:"epoch"`
+	Payload      json.RawMessage `json:"payload"`
+}
@@ -0,0 +1,62 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+
+	"github.com/pingcap/errors"
+
+	"github.com/hanfei1991/microcosm/model"
+)
+
+// JobResourceSpec declares the number of workers a job runs and the
+// resources each worker requires. The capacity of MinWorkers workers is
+// reserved when the job is submitted, and the job master requests more
+// capacity up to MaxWorkers workers when it scales up.
+type JobResourceSpec struct {
+	MinWorkers int32 `json:"min-workers"`
+	// MaxWorkers is the max number of workers, 0 means no limit.
+	MaxWorkers      int32            `json:"max-workers"`
+	WorkerResources model.RescVector `json:"worker-resources,omitempty"`
+}
+
+// Validate checks whether the spec is valid.
+func (s *JobResourceSpec) Validate() error {
+	if s.MinWorkers < 0 || s.MaxWorkers < 0 {
+		return errors.Errorf("negative worker count: min %d, max %d", s.MinWorkers, s.MaxWorkers)
+	}
+	if s.MaxWorkers > 0 && s.MaxWorkers < s.MinWorkers {
+		return errors.Errorf("max workers %d is less than min workers %d", s.MaxWorkers, s.MinWorkers)
+	}
+	for name, amount := range s.WorkerResources {
+		if amount < 0 {
+			return errors.Errorf("negative worker resource %s: %d", name, amount)
+		}
+	}
+	return nil
+}
+
+// Value implements driver.Valuer, the spec is persisted as JSON.
+func (s JobResourceSpec) Value() (driver.Value, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return data, nil
+}
+
+// Scan implements sql.Scanner
+func (s *JobResourceSpec) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return errors.Errorf("unexpected type %T of job resource spec", value)
+	}
+	return errors.Trace(json.Unmarshal(data, s))
+}
@@ -0,0 +1,49 @@
+package lib
+
+import (
+	"context"
+	"time"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+)
+
+// RecoveryReport describes what has been recovered when a master recovers
+// after failover. It is carried by the context passed to OnMasterRecovered,
+// see RecoveryReportFromContext, so that the master can decide which workers
+// to re-create without querying the metastore again.
+type RecoveryReport struct {
+	// PrevEpoch is the epoch of the failed master, and Epoch is the epoch of
+	// the recovered one.
+	PrevEpoch libModel.Epoch
+	Epoch     libModel.Epoch
+
+	// ReconnectedWorkers are the workers that have sent heartbeats to the
+	// recovered master in time, they are online.
+	ReconnectedWorkers []libModel.WorkerID
+	// TombstonedWorkers are the workers that have not sent heartbeats in time
+	// or have finished, they are offline and can be re-created.
+	TombstonedWorkers []libModel.WorkerID
+	// FinishedWorkers are the workers that had finished before failover.
+	FinishedWorkers []libModel.WorkerID
+	// SuspectWorkers are the workers not confirmed yet in a fast failover,
+	// see FastFailoverMasterImpl. They are reconciled asynchronously, and
+	// ReconnectedWorkers and TombstonedWorkers are empty then.
+	SuspectWorkers []libModel.WorkerID
+
+	// WaitDuration is the time spent on waiting for the heartbeats of the
+	// workers.
+	WaitDuration time.Duration
+}
+
+type recoveryReportKey struct{}
+
+func withRecoveryReport(ctx context.Context, report *RecoveryReport) context.Context {
+	return context.WithValue(ctx, recoveryReportKey{}, report)
+}
+
+// RecoveryReportFromContext returns the RecoveryReport carried by the context
+// passed to OnMasterRecovered, it returns false if ctx is not such a context.
+func RecoveryReportFromContext(ctx context.Context) (*RecoveryReport, bool) {
+	report, ok := ctx.Value(recoveryReportKey{}).(*RecoveryReport)
+	return report, ok
+}
@@ -0,0 +1,150 @@
+package registry
+
+import (
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	"github.com/hanfei1991/microcosm/lib"
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/pkg/connector"
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+const (
+	sourceConnector = "source"
+	sinkConnector   = "sink"
+)
+
+// WorkerPlugin declares a type of worker created by a job master.
+type WorkerPlugin struct {
+	Type libModel.WorkerType
+	// Codec is used by the job master to encode the worker config, it must
+	// match the DeserializeConfig of Factory.
+	Codec   lib.ConfigCodec
+	Factory WorkerFactory
+}
+
+// ConnectorPlugin declares a connector, which is run by the connector task
+// to move data between external systems. Either Source or Sink can be nil if
+// the connector only reads or writes.
+type ConnectorPlugin struct {
+	Type   string
+	Source connector.SourceFactory
+	Sink   connector.SinkFactory
+}
+
+// JobPlugin declares a job type, including its job master and workers.
+// By registering a JobPlugin, a new job type can be supported without
+// modifying the framework.
+type JobPlugin struct {
+	Name string
+	// MasterType and MasterFactory can be left empty if the job master
+	// is registered elsewhere.
+	MasterType    libModel.WorkerType
+	MasterFactory WorkerFactory
+	Workers       []WorkerPlugin
+	Connectors    []ConnectorPlugin
+}
+
+// RegisterPlugin registers all factories of a job plugin into the registry,
+// and declares the worker types to the framework. No factory is registered
+// if any worker type of the plugin has been registered.
+func (r *registryImpl) RegisterPlugin(plugin *JobPlugin) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	factories := make(map[libModel.WorkerType]WorkerFactory, len(plugin.Workers)+1)
+	infos := make(map[libModel.WorkerType]lib.WorkerTypeInfo, len(plugin.Workers)+1)
+	if plugin.MasterFactory != nil {
+		factories[plugin.MasterType] = plugin.MasterFactory
+		infos[plugin.MasterType] = lib.WorkerTypeInfo{IsJobMaster: true}
+	}
+	for _, worker := range plugin.Workers {
+		if _, exists := factories[worker.Type]; exists {
+			return derror.ErrWorkerTypeDuplicated.GenWithStackByArgs(worker.Type)
+		}
+		factories[worker.Type] = worker.Factory
+		infos[worker.Type] = lib.WorkerTypeInfo{Codec: worker.Codec}
+	}
+
+	for tp := range factories {
+		if _, exists := r.factoryMap[tp]; exists {
+			return derror.ErrWorkerTypeDuplicated.GenWithStackByArgs(tp)
+		}
+	}
+	sources := make(map[string]connector.SourceFactory)
+	sinks := make(map[string]connector.SinkFactory)
+	for _, c := range plugin.Connectors {
+		if c.Source != nil {
+			if _, exists := r.sourceMap[c.Type]; exists {
+				return derror.ErrConnectorDuplicated.GenWithStackByArgs(sourceConnector, c.Type)
+			}
+			if _, exists := sources[c.Type]; exists {
+				return derror.ErrConnectorDuplicated.GenWithStackByArgs(sourceConnector, c.Type)
+			}
+			sources[c.Type] = c.Source
+		}
+		if c.Sink != nil {
+			if _, exists := r.sinkMap[c.Type]; exists {
+				return derror.ErrConnectorDuplicated.GenWithStackByArgs(sinkConnector, c.Type)
+			}
+			if _, exists := sinks[c.Type]; exists {
+				return derror.ErrConnectorDuplicated.GenWithStackByArgs(sinkConnector, c.Type)
+			}
+			sinks[c.Type] = c.Sink
+		}
+	}
+	for tp, info := range infos {
+		if !lib.RegisterWorkerTypeInfo(tp, info) {
+			return derror.ErrWorkerTypeDuplicated.GenWithStackByArgs(tp)
+		}
+	}
+	for tp, factory := range factories {
+		r.factoryMap[tp] = factory
+	}
+	for tp, factory := range sources {
+		r.sourceMap[tp] = factory
+	}
+	for tp, factory := range sinks {
+		r.sinkMap[tp] = factory
+	}
+
+	log.L().Info("register job plugin",
+		zap.String("name", plugin.Name),
+		zap.Int64("master-type", int64(plugin.MasterType)),
+		zap.Int("worker-type-count", len(plugin.Workers)),
+		zap.Int("connector-count", len(plugin.Connectors)))
+	return nil
+}
+
+// GetSourceFactory implements Registry.GetSourceFactory
+func (r *registryImpl) GetSourceFactory(tp string) (connector.SourceFactory, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	factory, ok := r.sourceMap[tp]
+	if !ok {
+		return nil, derror.ErrConnectorNotFound.GenWithStackByArgs(sourceConnector, tp)
+	}
+	return factory, nil
+}
+
+// GetSinkFactory implements Registry.GetSinkFactory
+func (r *registryImpl) GetSinkFactory(tp string) (connector.SinkFactory, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	factory, ok := r.sinkMap[tp]
+	if !ok {
+		return nil, derror.ErrConnectorNotFound.GenWithStackByArgs(sinkConnector, tp)
+	}
+	return factory, nil
+}
+
+// MustRegisterPlugin implements Registry.MustRegisterPlugin
+func (r *registryImpl) MustRegisterPlugin(plugin *JobPlugin) {
+	if err := r.RegisterPlugin(plugin); err != nil {
+		log.L().Panic("failed to register job plugin",
+			zap.String("name", plugin.Name), zap.Error(err))
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/hanfei1991/microcosm/lib"
 	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/pkg/connector"
 	dcontext "github.com/hanfei1991/microcosm/pkg/context"
 	derror "github.com/hanfei1991/microcosm/pkg/errors"
 )
@@ -22,6 +23,14 @@ type WorkerConfig = lib.WorkerConfig
 type Registry interface {
 	MustRegisterWorkerType(tp libModel.WorkerType, factory WorkerFactory)
 	RegisterWorkerType(tp libModel.WorkerType, factory WorkerFactory) (ok bool)
+	// RegisterPlugin registers a job type, including its job master and
+	// workers, along with how their configs are encoded.
+	RegisterPlugin(plugin *JobPlugin) error
+	MustRegisterPlugin(plugin *JobPlugin)
+	// GetSourceFactory and GetSinkFactory return the factories of the
+	// connectors registered by plugins.
+	GetSourceFactory(tp string) (connector.SourceFactory, error)
+	GetSinkFactory(tp string) (connector.SinkFactory, error)
 	CreateWorker(
 		ctx *dcontext.Context,
 		tp lib.WorkerType,
@@ -34,12 +43,16 @@ type Registry interface {
 type registryImpl struct {
 	mu         sync.RWMutex
 	factoryMap map[libModel.WorkerType]WorkerFactory
+	sourceMap  map[string]connector.SourceFactory
+	sinkMap    map[string]connector.SinkFactory
 }
 
 // NewRegistry creates a new registryImpl instance
 func NewRegistry() Registry {
 	return &registryImpl{
 		factoryMap: make(map[libModel.WorkerType]WorkerFactory),
+		sourceMap:  make(map[string]connector.SourceFactory),
+		sinkMap:    make(map[string]connector.SinkFactory),
 	}
 }
 
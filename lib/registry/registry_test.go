@@ -1,6 +1,7 @@
 package registry
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -8,7 +9,9 @@ import (
 	"github.com/hanfei1991/microcosm/lib"
 	"github.com/hanfei1991/microcosm/lib/fake"
 	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/pkg/connector"
 	dcontext "github.com/hanfei1991/microcosm/pkg/context"
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
 )
 
 var (
@@ -64,6 +67,81 @@ func TestLoadFake(t *testing.T) {
 	})
 }
 
+func TestRegisterPlugin(t *testing.T) {
+	const (
+		pluginMasterType = libModel.WorkerType(200)
+		pluginWorkerType = libModel.WorkerType(201)
+	)
+
+	registry := NewRegistry()
+	plugin := &JobPlugin{
+		Name:          "test-plugin",
+		MasterType:    pluginMasterType,
+		MasterFactory: fakeWorkerFactory,
+		Workers: []WorkerPlugin{
+			{Type: pluginWorkerType, Codec: lib.TOMLConfigCodec, Factory: fakeWorkerFactory},
+		},
+	}
+	require.NoError(t, registry.RegisterPlugin(plugin))
+	_, ok := registry.(*registryImpl).getWorkerFactory(pluginMasterType)
+	require.True(t, ok)
+	_, ok = registry.(*registryImpl).getWorkerFactory(pluginWorkerType)
+	require.True(t, ok)
+
+	// the same plugin can't be registered twice into one registry
+	require.Error(t, registry.RegisterPlugin(plugin))
+	require.Panics(t, func() {
+		registry.MustRegisterPlugin(plugin)
+	})
+
+	// a worker type can't be declared with different infos
+	registry = NewRegistry()
+	err := registry.RegisterPlugin(&JobPlugin{
+		Name: "conflict-plugin",
+		Workers: []WorkerPlugin{
+			{Type: pluginWorkerType, Codec: lib.JSONConfigCodec, Factory: fakeWorkerFactory},
+		},
+	})
+	require.Error(t, err)
+	_, ok = registry.(*registryImpl).getWorkerFactory(pluginWorkerType)
+	require.False(t, ok)
+}
+
+func TestRegisterConnectors(t *testing.T) {
+	t.Parallel()
+
+	sourceFactory := func(config json.RawMessage) (connector.Source, error) { return nil, nil }
+	sinkFactory := func(config json.RawMessage) (connector.Sink, error) { return nil, nil }
+	registry := NewRegistry()
+	require.NoError(t, registry.RegisterPlugin(&JobPlugin{
+		Name: "connector-plugin",
+		Connectors: []ConnectorPlugin{
+			{Type: "both", Source: sourceFactory, Sink: sinkFactory},
+			{Type: "source-only", Source: sourceFactory},
+		},
+	}))
+	_, err := registry.GetSourceFactory("both")
+	require.NoError(t, err)
+	_, err = registry.GetSinkFactory("both")
+	require.NoError(t, err)
+	_, err = registry.GetSourceFactory("source-only")
+	require.NoError(t, err)
+	_, err = registry.GetSinkFactory("source-only")
+	require.True(t, derror.ErrConnectorNotFound.Equal(err))
+
+	// a connector can't be registered twice
+	err = registry.RegisterPlugin(&JobPlugin{
+		Name: "conflict-plugin",
+		Connectors: []ConnectorPlugin{
+			{Type: "source-only", Sink: sinkFactory},
+			{Type: "both", Source: sourceFactory},
+		},
+	})
+	require.True(t, derror.ErrConnectorDuplicated.Equal(err))
+	_, err = registry.GetSinkFactory("source-only")
+	require.True(t, derror.ErrConnectorNotFound.Equal(err))
+}
+
 func TestGetTypeNameOfVarPtr(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,227 @@
+package lib
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hanfei1991/microcosm/pb"
+)
+
+type openScheduleStreamFunc func(ctx context.Context) (pb.Master_ScheduleClient, error)
+
+// scheduleStreamClient schedules the workers of a master on a schedule stream
+// to the server master, which is opened on demand and reopened after it is
+// broken. The server master revokes a decision on the stream if the executor
+// is gone before the worker is dispatched.
+type scheduleStreamClient struct {
+	open   openScheduleStreamFunc
+	logger func() log.Logger
+
+	// sendMu serializes the sends, SendMsg is not safe to be called
+	// concurrently
+	sendMu sync.Mutex
+
+	mu          sync.Mutex
+	stream      pb.Master_ScheduleClient
+	cancel      context.CancelFunc
+	nextID      int64
+	calls       map[int64]*scheduleCall
+	unsupported bool
+	closed      bool
+}
+
+type scheduleCall struct {
+	resultCh chan scheduleResult
+	// revoked is closed if the decision is revoked
+	revoked chan struct{}
+	decided bool
+}
+
+type scheduleResult struct {
+	resp *pb.ScheduleTaskResponse
+	err  error
+}
+
+func newScheduleStreamClient(open openScheduleStreamFunc, logger func() log.Logger) *scheduleStreamClient {
+	return &scheduleStreamClient{
+		open:   open,
+		logger: logger,
+		calls:  make(map[int64]*scheduleCall),
+	}
+}
+
+// schedule schedules the task on the stream, ok is false if the stream is not
+// available, and the task should be scheduled by ScheduleTask then. revoked is
+// closed if the decision is revoked, and done must be called once the decision
+// is used or abandoned.
+func (c *scheduleStreamClient) schedule(
+	ctx context.Context, req *pb.ScheduleTaskRequest,
+) (resp *pb.ScheduleTaskResponse, revoked <-chan struct{}, done func(), ok bool, err error) {
+	stream, ok := c.getStream()
+	if !ok {
+		return nil, nil, nil, false, nil
+	}
+
+	c.mu.Lock()
+	c.nextID++
+	requestID := c.nextID
+	call := &scheduleCall{
+		resultCh: make(chan scheduleResult, 1),
+		revoked:  make(chan struct{}),
+	}
+	c.calls[requestID] = call
+	c.mu.Unlock()
+
+	if err := c.send(stream, &pb.ScheduleRequest{RequestId: requestID, Task: req}); err != nil {
+		c.removeCall(requestID)
+		c.breakStream(stream, err)
+		return nil, nil, nil, false, nil
+	}
+
+	var result scheduleResult
+	select {
+	case <-ctx.Done():
+		// the decision made later is abandoned by onResponse
+		c.removeCall(requestID)
+		select {
+		case result = <-call.resultCh:
+			if result.err == nil {
+				c.sendDone(stream, requestID)
+			}
+		default:
+		}
+		return nil, nil, nil, true, errors.Trace(ctx.Err())
+	case result = <-call.resultCh:
+	}
+	if result.err != nil {
+		return nil, nil, nil, true, result.err
+	}
+	done = func() {
+		c.removeCall(requestID)
+		c.sendDone(stream, requestID)
+	}
+	return result.resp, call.revoked, done, true, nil
+}
+
+func (c *scheduleStreamClient) getStream() (pb.Master_ScheduleClient, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.unsupported || c.closed {
+		return nil, false
+	}
+	if c.stream != nil {
+		return c.stream, true
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := c.open(ctx)
+	if err != nil {
+		cancel()
+		if status.Code(errors.Cause(err)) == codes.Unimplemented {
+			c.unsupported = true
+		}
+		c.logger().Info("schedule stream is not available, fall back to ScheduleTask",
+			zap.Error(err))
+		return nil, false
+	}
+	c.stream, c.cancel = stream, cancel
+	go c.recvLoop(stream)
+	return stream, true
+}
+
+func (c *scheduleStreamClient) send(stream pb.Master_ScheduleClient, req *pb.ScheduleRequest) error {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+	return stream.Send(req)
+}
+
+// sendDone tells the server master the decision is used or abandoned, the
+// error is ignored since all decisions on a broken stream are gone.
+func (c *scheduleStreamClient) sendDone(stream pb.Master_ScheduleClient, requestID int64) {
+	_ = c.send(stream, &pb.ScheduleRequest{RequestId: requestID, Done: true})
+}
+
+func (c *scheduleStreamClient) recvLoop(stream pb.Master_ScheduleClient) {
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			c.breakStream(stream, err)
+			return
+		}
+		if abandoned := c.onResponse(resp); abandoned {
+			c.sendDone(stream, resp.GetRequestId())
+		}
+	}
+}
+
+// onResponse delivers the response to the request, abandoned is true if the
+// response is a decision of a request that is no longer waited for.
+func (c *scheduleStreamClient) onResponse(resp *pb.ScheduleResponse) (abandoned bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	call, ok := c.calls[resp.GetRequestId()]
+	if !ok {
+		return resp.GetDecision() != nil
+	}
+	switch {
+	case resp.GetRevoked():
+		if call.decided {
+			close(call.revoked)
+		} else {
+			call.resultCh <- scheduleResult{err: errors.Errorf(
+				"schedule request %d is revoked", resp.GetRequestId())}
+		}
+		delete(c.calls, resp.GetRequestId())
+	case resp.GetErr() != nil:
+		call.resultCh <- scheduleResult{err: errors.Errorf(
+			"schedule task failed: %s", resp.GetErr().GetMessage())}
+		delete(c.calls, resp.GetRequestId())
+	case !call.decided:
+		call.decided = true
+		call.resultCh <- scheduleResult{resp: resp.GetDecision()}
+	}
+	return false
+}
+
+// breakStream fails the requests waiting for decisions on the stream, the
+// stream is reopened by the next request.
+func (c *scheduleStreamClient) breakStream(stream pb.Master_ScheduleClient, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stream != stream {
+		return
+	}
+	if !c.closed {
+		c.logger().Warn("schedule stream is broken", zap.Error(err))
+	}
+	c.cancel()
+	c.stream, c.cancel = nil, nil
+	for requestID, call := range c.calls {
+		if !call.decided {
+			call.resultCh <- scheduleResult{err: errors.Annotate(err, "schedule stream is broken")}
+		}
+		delete(c.calls, requestID)
+	}
+}
+
+func (c *scheduleStreamClient) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+func (c *scheduleStreamClient) removeCall(requestID int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.calls, requestID)
+}
@@ -0,0 +1,145 @@
+package lib
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hanfei1991/microcosm/pb"
+)
+
+type mockScheduleClientStream struct {
+	grpc.ClientStream
+	ctx    context.Context
+	sendCh chan *pb.ScheduleRequest
+	recvCh chan *pb.ScheduleResponse
+}
+
+func (s *mockScheduleClientStream) Send(req *pb.ScheduleRequest) error {
+	s.sendCh <- req
+	return nil
+}
+
+func (s *mockScheduleClientStream) Recv() (*pb.ScheduleResponse, error) {
+	select {
+	case <-s.ctx.Done():
+		return nil, s.ctx.Err()
+	case resp, ok := <-s.recvCh:
+		if !ok {
+			return nil, io.EOF
+		}
+		return resp, nil
+	}
+}
+
+func TestScheduleStreamClient(t *testing.T) {
+	c := newScheduleStreamClient(func(ctx context.Context) (pb.Master_ScheduleClient, error) {
+		return &mockScheduleClientStream{
+			ctx:    ctx,
+			sendCh: make(chan *pb.ScheduleRequest, 16),
+			recvCh: make(chan *pb.ScheduleResponse, 16),
+		}, nil
+	}, log.L)
+	defer c.close()
+	// waitStream waits for the stream opened after prev is broken
+	waitStream := func(prev *mockScheduleClientStream) (stream *mockScheduleClientStream) {
+		require.Eventually(t, func() bool {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			if c.stream == nil || c.stream == prev {
+				return false
+			}
+			stream = c.stream.(*mockScheduleClientStream)
+			return true
+		}, time.Second, 10*time.Millisecond)
+		return stream
+	}
+
+	type result struct {
+		resp    *pb.ScheduleTaskResponse
+		revoked <-chan struct{}
+		done    func()
+		err     error
+	}
+	schedule := func(taskID string) <-chan result {
+		ch := make(chan result, 1)
+		go func() {
+			resp, revoked, done, ok, err := c.schedule(context.Background(), &pb.ScheduleTaskRequest{TaskId: taskID})
+			require.True(t, ok)
+			ch <- result{resp, revoked, done, err}
+		}()
+		return ch
+	}
+
+	resultCh := schedule("worker-1")
+	stream := waitStream(nil)
+	req := <-stream.sendCh
+	require.Equal(t, "worker-1", req.GetTask().GetTaskId())
+	stream.recvCh <- &pb.ScheduleResponse{
+		RequestId: req.GetRequestId(),
+		Decision:  &pb.ScheduleTaskResponse{ExecutorId: "executor-1"},
+	}
+	res := <-resultCh
+	require.NoError(t, res.err)
+	require.Equal(t, "executor-1", res.resp.GetExecutorId())
+
+	stream.recvCh <- &pb.ScheduleResponse{RequestId: req.GetRequestId(), Revoked: true}
+	select {
+	case <-res.revoked:
+	case <-time.After(time.Second):
+		require.FailNow(t, "decision is not revoked")
+	}
+	res.done()
+	done := <-stream.sendCh
+	require.Equal(t, req.GetRequestId(), done.GetRequestId())
+	require.True(t, done.GetDone())
+
+	resultCh = schedule("worker-2")
+	req = <-stream.sendCh
+	stream.recvCh <- &pb.ScheduleResponse{
+		RequestId: req.GetRequestId(),
+		Err:       &pb.Error{Message: "not enough resource"},
+	}
+	res = <-resultCh
+	require.ErrorContains(t, res.err, "not enough resource")
+
+	// the waiting requests fail if the stream is broken, a new stream is
+	// opened by the next request
+	resultCh = schedule("worker-3")
+	<-stream.sendCh
+	brokenStream := stream
+	close(brokenStream.recvCh)
+	res = <-resultCh
+	require.Error(t, res.err)
+	resultCh = schedule("worker-4")
+	stream = waitStream(brokenStream)
+	req = <-stream.sendCh
+	stream.recvCh <- &pb.ScheduleResponse{
+		RequestId: req.GetRequestId(),
+		Decision:  &pb.ScheduleTaskResponse{ExecutorId: "executor-2"},
+	}
+	res = <-resultCh
+	require.NoError(t, res.err)
+	require.Equal(t, "executor-2", res.resp.GetExecutorId())
+}
+
+func TestScheduleStreamClientUnsupported(t *testing.T) {
+	opened := 0
+	c := newScheduleStreamClient(func(ctx context.Context) (pb.Master_ScheduleClient, error) {
+		opened++
+		return nil, status.Error(codes.Unimplemented, "unknown method Schedule")
+	}, log.L)
+	for i := 0; i < 2; i++ {
+		_, _, _, ok, err := c.schedule(context.Background(), &pb.ScheduleTaskRequest{})
+		require.False(t, ok)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 1, opened)
+}
@@ -0,0 +1,103 @@
+package lib
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/p2p"
+)
+
+// maxPendingSelfMessages limits the self messages waiting for their
+// deadlines, so that a worker rescheduling messages in a loop can't exhaust
+// the memory of the executor.
+const maxPendingSelfMessages = 1024
+
+type selfMessage struct {
+	deadline time.Time
+	// seq keeps the messages with the same deadline in the order they are
+	// scheduled.
+	seq     uint64
+	topic   p2p.Topic
+	payload p2p.MessageValue
+}
+
+type selfMessageHeap []*selfMessage
+
+func (h selfMessageHeap) Len() int { return len(h) }
+
+func (h selfMessageHeap) Less(i, j int) bool {
+	if !h[i].deadline.Equal(h[j].deadline) {
+		return h[i].deadline.Before(h[j].deadline)
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h selfMessageHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *selfMessageHeap) Push(x interface{}) { *h = append(*h, x.(*selfMessage)) }
+
+func (h *selfMessageHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	msg := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return msg
+}
+
+// selfMessageQueue holds the messages a worker schedules to itself. The
+// messages are not timed by goroutines, they are moved to the message router
+// by Poll once their deadlines pass, so they are handled by OnMasterMessage
+// like the messages from the master. The messages are dropped when the worker
+// exits.
+type selfMessageQueue struct {
+	mu      sync.Mutex
+	nextSeq uint64
+	pending selfMessageHeap
+}
+
+func newSelfMessageQueue() *selfMessageQueue {
+	return &selfMessageQueue{}
+}
+
+func (q *selfMessageQueue) schedule(deadline time.Time, topic p2p.Topic, payload p2p.MessageValue) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.pending) >= maxPendingSelfMessages {
+		return derror.ErrTooManySelfMessages.GenWithStackByArgs(len(q.pending))
+	}
+	q.nextSeq++
+	heap.Push(&q.pending, &selfMessage{
+		deadline: deadline,
+		seq:      q.nextSeq,
+		topic:    topic,
+		payload:  payload,
+	})
+	return nil
+}
+
+// deliver appends the messages due at now to the router in the order of
+// their deadlines. The messages not accepted by a full router are delivered
+// by the following calls, rather than evicting the messages of the master.
+func (q *selfMessageQueue) deliver(now time.Time, router *MessageRouter) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.pending) > 0 {
+		msg := q.pending[0]
+		if msg.deadline.After(now) {
+			return
+		}
+		if !router.TryAppendMessage(msg.topic, msg.payload) {
+			return
+		}
+		heap.Pop(&q.pending)
+	}
+}
+
+func (q *selfMessageQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending)
+}
@@ -0,0 +1,44 @@
+package lib
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/pkg/workerpool"
+	"github.com/stretchr/testify/require"
+
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/p2p"
+)
+
+func TestSelfMessageQueue(t *testing.T) {
+	t.Parallel()
+
+	router := NewMessageRouter("test-worker", workerpool.NewDefaultAsyncPool(1), 2,
+		func(topic p2p.Topic, msg p2p.MessageValue) error { return nil })
+	q := newSelfMessageQueue()
+	now := time.Now()
+	require.NoError(t, q.schedule(now.Add(2*time.Second), "topic-2", 2))
+	require.NoError(t, q.schedule(now.Add(time.Second), "topic-1", 1))
+	require.NoError(t, q.schedule(now.Add(2*time.Second), "topic-3", 3))
+
+	q.deliver(now, router)
+	require.Equal(t, 3, q.len())
+
+	// the due messages are delivered in the order of deadlines, the ones
+	// not accepted by the full router wait for the next delivery
+	q.deliver(now.Add(2*time.Second), router)
+	require.Equal(t, 1, q.len())
+	for _, expected := range []messageWrapper{{"topic-1", 1}, {"topic-2", 2}} {
+		require.Equal(t, expected, <-router.buffer)
+	}
+	q.deliver(now.Add(2*time.Second), router)
+	require.Equal(t, 0, q.len())
+	require.Equal(t, messageWrapper{"topic-3", 3}, <-router.buffer)
+
+	for i := 0; i < maxPendingSelfMessages; i++ {
+		require.NoError(t, q.schedule(now, "topic", i))
+	}
+	err := q.schedule(now, "topic", maxPendingSelfMessages)
+	require.True(t, derror.ErrTooManySelfMessages.Equal(err))
+}
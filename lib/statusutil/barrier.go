@@ -0,0 +1,78 @@
+package statusutil
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/pkg/adapter"
+	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
+	"github.com/hanfei1991/microcosm/pkg/p2p"
+)
+
+// BarrierReachedMessage is sent by a worker when it has reached a barrier
+type BarrierReachedMessage struct {
+	Worker      libModel.WorkerID `json:"worker"`
+	MasterEpoch libModel.Epoch    `json:"master-epoch"`
+	Barrier     string            `json:"barrier"`
+}
+
+// BarrierTopic returns the p2p topic for barrier notifications of a given master.
+func BarrierTopic(masterID libModel.MasterID) string {
+	return fmt.Sprintf("worker-barrier-%s", masterID)
+}
+
+// BarrierReporter is used by a worker to report reaching barriers requested
+// by its master.
+type BarrierReporter struct {
+	kv            metaclient.KV
+	messageSender p2p.MessageSender
+
+	workerID   libModel.WorkerID
+	masterInfo MasterInfoProvider
+}
+
+// NewBarrierReporter creates a new BarrierReporter.
+func NewBarrierReporter(
+	kv metaclient.KV,
+	messageSender p2p.MessageSender,
+	masterInfo MasterInfoProvider,
+	workerID libModel.WorkerID,
+) *BarrierReporter {
+	return &BarrierReporter{
+		kv:            kv,
+		messageSender: messageSender,
+		masterInfo:    masterInfo,
+		workerID:      workerID,
+	}
+}
+
+// Reach persists that the worker has reached the barrier, and notifies the
+// master. Failing to notify the master is not an error, because the master
+// also loads the persisted state periodically.
+func (r *BarrierReporter) Reach(ctx context.Context, barrierID string) error {
+	masterID := r.masterInfo.MasterID()
+	key := adapter.BarrierReachedKeyAdapter.Encode(masterID, barrierID, r.workerID)
+	if _, err := r.kv.Put(ctx, key, r.workerID); err != nil {
+		return errors.Trace(err)
+	}
+
+	ok, err := r.messageSender.SendToNode(ctx, r.masterInfo.MasterNode(), BarrierTopic(masterID),
+		&BarrierReachedMessage{
+			Worker:      r.workerID,
+			MasterEpoch: r.masterInfo.Epoch(),
+			Barrier:     barrierID,
+		})
+	if err != nil || !ok {
+		log.L().Info("failed to notify master of reaching barrier",
+			zap.String("worker-id", r.workerID),
+			zap.String("master-id", masterID),
+			zap.String("barrier", barrierID),
+			zap.Error(err))
+	}
+	return nil
+}
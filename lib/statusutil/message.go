@@ -2,19 +2,91 @@ package statusutil
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/pkg/p2p"
 )
 
+const (
+	workerStatusTopicPrefix       = "worker-status-"
+	workerStatusReplayTopicPrefix = "worker-status-replay-"
+
+	// WorkerStatusMessageTTL is the TTL of worker status messages. Significant
+	// status changes are persisted before the message is sent, so the master
+	// can still read them from metastore if the message expires.
+	WorkerStatusMessageTTL = 30 * time.Second
+)
+
+func init() {
+	p2p.RegisterTopicTTL(workerStatusTopicPrefix, WorkerStatusMessageTTL)
+	// the prefix covers the status replays too
+	p2p.RegisterTopicEncoding(workerStatusTopicPrefix, p2p.EncodingMsgpack)
+}
+
 // WorkerStatusMessage contains necessary fileds of a worker status message
 type WorkerStatusMessage struct {
 	Worker      libModel.WorkerID      `json:"worker"`
 	MasterEpoch libModel.Epoch         `json:"master-epoch"`
 	Status      *libModel.WorkerStatus `json:"status"`
+	SendTime    time.Time              `json:"send-time,omitempty"`
+	// Seq is the sequence number of the status update, it increases by one
+	// for each call of Writer.UpdateStatus. It is zero for workers of an old
+	// version.
+	Seq uint64 `json:"seq,omitempty"`
+}
+
+// MessageSendTime implements p2p.TimedMessage.MessageSendTime
+func (m *WorkerStatusMessage) MessageSendTime() time.Time {
+	return m.SendTime
 }
 
 // WorkerStatusTopic returns the p2p topic for worker status subscription of a
 // given master.
 func WorkerStatusTopic(masterID libModel.MasterID) string {
-	return fmt.Sprintf("worker-status-%s", masterID)
+	return workerStatusTopicPrefix + masterID
+}
+
+// IsWorkerStatusTopic returns whether topic is a topic of worker status
+// updates or their replays.
+func IsWorkerStatusTopic(topic string) bool {
+	return strings.HasPrefix(topic, workerStatusTopicPrefix)
+}
+
+// WorkerStatusReplayRequest is sent by a master after failover to a worker
+// whose latest status updates have not been persisted, see
+// HeartbeatPingMessage.StatusSeq.
+type WorkerStatusReplayRequest struct {
+	MasterEpoch libModel.Epoch `json:"master-epoch"`
+}
+
+// SeqStatus is a status update with its sequence number.
+type SeqStatus struct {
+	Seq    uint64                 `json:"seq"`
+	Status *libModel.WorkerStatus `json:"status"`
+}
+
+// WorkerStatusReplayMessage replays the status updates of a worker since its
+// last persisted status.
+type WorkerStatusReplayMessage struct {
+	Worker      libModel.WorkerID `json:"worker"`
+	MasterEpoch libModel.Epoch    `json:"master-epoch"`
+	// Statuses are ordered by Seq, the last one is the current status.
+	Statuses []SeqStatus `json:"statuses"`
+	// Truncated is true if some updates are missing, because the worker
+	// only keeps a bounded history of status updates.
+	Truncated bool `json:"truncated"`
+}
+
+// WorkerStatusReplayRequestTopic returns the p2p topic of replay requests
+// sent to a given worker.
+func WorkerStatusReplayRequestTopic(masterID libModel.MasterID, workerID libModel.WorkerID) string {
+	return fmt.Sprintf("worker-status-replay-req-%s-%s", masterID, workerID)
+}
+
+// WorkerStatusReplayTopic returns the p2p topic of status replays sent to a
+// given master.
+func WorkerStatusReplayTopic(masterID libModel.MasterID) string {
+	return workerStatusReplayTopicPrefix + masterID
 }
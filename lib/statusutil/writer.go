@@ -2,6 +2,7 @@ package statusutil
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/modern-go/reflect2"
@@ -11,26 +12,53 @@ import (
 	"go.uber.org/zap"
 	"golang.org/x/time/rate"
 
+	"github.com/hanfei1991/microcosm/lib/metadata"
 	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/pkg/clock"
 	derrors "github.com/hanfei1991/microcosm/pkg/errors"
-	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
 	"github.com/hanfei1991/microcosm/pkg/p2p"
 )
 
+// statusHistorySize is the number of recent status updates kept by a Writer
+// for replaying to a failed-over master.
+const statusHistorySize = 16
+
+// terminalStatusPersistTimeout is the deadline of persisting a terminal
+// status, see PersistTerminalStatus.
+const terminalStatusPersistTimeout = 30 * time.Second
+
 // Writer is used to persist WorkerStatus changes and send notifications
 // to the Master.
 type Writer struct {
-	metaclient    pkgOrm.Client
+	metaclient    *metadata.WorkerMetadataClient
 	messageSender p2p.MessageSender
 	lastStatus    *libModel.WorkerStatus
 
 	workerID   libModel.WorkerID
 	masterInfo MasterInfoProvider
+	logger     log.Logger
+
+	// coalesceWindow is set by WithCoalesceWindow, pending is the latest
+	// status update coalesced and not delivered yet.
+	coalesceWindow time.Duration
+	clock          clock.Clock
+	deliveredAt    time.Time
+	pending        *libModel.WorkerStatus
+
+	// mu protects the fields below, which are read by Seqs and Replay
+	// from other goroutines.
+	mu sync.Mutex
+	// seq is the sequence number of the latest status update, and
+	// persistedSeq is that of the latest persisted one.
+	seq          uint64
+	persistedSeq uint64
+	// history holds the latest status updates, at most statusHistorySize.
+	history []SeqStatus
 }
 
 // NewWriter creates a new Writer.
 func NewWriter(
-	metaclient pkgOrm.Client,
+	metaclient *metadata.WorkerMetadataClient,
 	messageSender p2p.MessageSender,
 	masterInfo MasterInfoProvider,
 	workerID libModel.WorkerID,
@@ -40,38 +68,219 @@ func NewWriter(
 		messageSender: messageSender,
 		masterInfo:    masterInfo,
 		workerID:      workerID,
+		logger:        log.L(),
 	}
 }
 
+// WithLogger makes the Writer log with logger, which is usually tagged with
+// the job and the worker.
+func (w *Writer) WithLogger(logger log.Logger) *Writer {
+	w.logger = logger
+	return w
+}
+
+// WithCoalesceWindow makes UpdateStatus coalesce the status updates within
+// window after the last delivered one, so that a worker updating its status
+// in a tight loop doesn't flood the master and the metastore. The latest
+// coalesced update is delivered by Flush after the window has passed, and a
+// terminal status is always delivered at once, superseding the coalesced one.
+func (w *Writer) WithCoalesceWindow(window time.Duration, clk clock.Clock) *Writer {
+	w.coalesceWindow = window
+	w.clock = clk
+	return w
+}
+
 // UpdateStatus checks if newStatus.HasSignificantChange() is true, if so, it persists the change and
 // tries to send a notification. Note that sending the notification is asynchronous.
-func (w *Writer) UpdateStatus(ctx context.Context, newStatus *libModel.WorkerStatus) (retErr error) {
+// If a coalesce window is set, the update may be coalesced and delivered by
+// Flush later, see WithCoalesceWindow.
+func (w *Writer) UpdateStatus(ctx context.Context, newStatus *libModel.WorkerStatus) error {
+	if w.coalesceWindow > 0 && !newStatus.InTerminateState() &&
+		w.clock.Since(w.deliveredAt) < w.coalesceWindow {
+		// the caller may modify the status after UpdateStatus returns
+		statusCopy := *newStatus
+		w.pending = &statusCopy
+		return nil
+	}
+	w.pending = nil
+	return w.deliver(ctx, newStatus)
+}
+
+// Flush delivers the coalesced status update if the coalesce window has
+// passed since the last delivered one, or if force is true. It is safe to
+// call Flush on a nil Writer.
+func (w *Writer) Flush(ctx context.Context, force bool) error {
+	if w == nil || w.pending == nil {
+		return nil
+	}
+	if !force && w.clock.Since(w.deliveredAt) < w.coalesceWindow {
+		return nil
+	}
+	pending := w.pending
+	w.pending = nil
+	return w.deliver(ctx, pending)
+}
+
+func (w *Writer) deliver(ctx context.Context, newStatus *libModel.WorkerStatus) (retErr error) {
 	defer func() {
 		if retErr == nil {
 			return
 		}
-		log.L().Warn("UpdateStatus failed",
+		w.logger.Warn("UpdateStatus failed",
 			zap.String("worker-id", w.workerID),
 			zap.String("master-id", w.masterInfo.MasterID()),
 			zap.String("master-node", w.masterInfo.MasterNode()),
 			zap.Int64("master-epoch", w.masterInfo.Epoch()))
 	}()
 
+	persisted := false
 	if reflect2.IsNil(w.lastStatus) || newStatus.HasSignificantChange(w.lastStatus) {
 		// Status has changed, so we need to persist the status.
 		if err := w.persistStatus(ctx, newStatus); err != nil {
 			return err
 		}
+		persisted = true
 	}
 
-	w.lastStatus = newStatus
+	// the caller may modify the status after UpdateStatus returns, which
+	// must be compared with the last one in the next update
+	lastStatus := *newStatus
+	w.lastStatus = &lastStatus
+	seq := w.record(newStatus, persisted)
+	if w.coalesceWindow > 0 {
+		w.deliveredAt = w.clock.Now()
+	}
 
 	// TODO replace the timeout with a variable.
-	return w.sendStatusMessageWithRetry(ctx, 15*time.Second, newStatus)
+	return w.sendStatusMessageWithRetry(ctx, 15*time.Second, newStatus, seq)
+}
+
+// PersistTerminalStatus persists a terminal status durably, regardless of
+// whether it has changed. It retries until the status is persisted and read
+// back from metastore, or terminalStatusPersistTimeout has passed, and then
+// notifies the master. The coalesced status update is dropped since it is
+// superseded. Failing to notify the master is not an error, since the master
+// loads the persisted status after the worker goes offline or failover.
+func (w *Writer) PersistTerminalStatus(ctx context.Context, status *libModel.WorkerStatus) error {
+	if !status.InTerminateState() {
+		return derrors.ErrWorkerStatusNotTerminal.GenWithStackByArgs(status.Code)
+	}
+	w.pending = nil
+
+	persistCtx, cancel := context.WithTimeout(ctx, terminalStatusPersistTimeout)
+	defer cancel()
+	err := retry.Do(persistCtx, func() error {
+		if err := w.metaclient.Update(persistCtx, status); err != nil {
+			return err
+		}
+		persisted, err := w.metaclient.Load(persistCtx, w.workerID)
+		if err != nil {
+			return err
+		}
+		if status.HasSignificantChange(persisted) {
+			return derrors.ErrWorkerStatusNotPersisted.GenWithStackByArgs(w.workerID)
+		}
+		return nil
+	}, retry.WithInfiniteTries(), retry.WithBackoffMaxDelay(1000 /* 1 second */),
+		retry.WithIsRetryableErr(func(err error) bool {
+			return persistCtx.Err() == nil
+		}))
+	if err != nil {
+		w.logger.Warn("failed to persist terminal status",
+			zap.String("worker-id", w.workerID),
+			zap.Any("status", status),
+			zap.Error(err))
+		return derrors.ErrWorkerStatusNotPersisted.Wrap(err).GenWithStackByArgs(w.workerID)
+	}
+
+	lastStatus := *status
+	w.lastStatus = &lastStatus
+	seq := w.record(status, true)
+	if err := w.sendStatusMessageWithRetry(ctx, 15*time.Second, status, seq); err != nil {
+		w.logger.Warn("failed to notify master of terminal status",
+			zap.String("worker-id", w.workerID),
+			zap.String("master-id", w.masterInfo.MasterID()),
+			zap.Error(err))
+	}
+	return nil
+}
+
+// record appends the status update to the history and returns its sequence
+// number.
+func (w *Writer) record(status *libModel.WorkerStatus, persisted bool) uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.seq++
+	if persisted {
+		w.persistedSeq = w.seq
+	}
+	// the caller may modify the status after UpdateStatus returns
+	statusCopy := *status
+	if len(w.history) == statusHistorySize {
+		w.history = w.history[1:]
+	}
+	w.history = append(w.history, SeqStatus{Seq: w.seq, Status: &statusCopy})
+	return w.seq
+}
+
+// Seqs returns the sequence numbers of the latest status update and the
+// latest persisted one. It is safe to call Seqs on a nil Writer.
+func (w *Writer) Seqs() (latest uint64, persisted uint64) {
+	if w == nil {
+		return 0, 0
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.seq, w.persistedSeq
+}
+
+// Replay sends the status updates since the latest persisted one to the
+// master of the given epoch on node, as the master has lost them in failover.
+func (w *Writer) Replay(ctx context.Context, node p2p.NodeID, masterEpoch libModel.Epoch) error {
+	w.mu.Lock()
+	msg := &WorkerStatusReplayMessage{
+		Worker:      w.workerID,
+		MasterEpoch: masterEpoch,
+	}
+	for _, st := range w.history {
+		if st.Seq > w.persistedSeq {
+			msg.Statuses = append(msg.Statuses, st)
+		}
+	}
+	if len(msg.Statuses) > 0 && msg.Statuses[0].Seq > w.persistedSeq+1 {
+		msg.Truncated = true
+	}
+	if len(msg.Statuses) == 0 && len(w.history) > 0 {
+		// The status has been persisted since the request, send the latest
+		// one anyway, so that the master doesn't wait for the replay.
+		msg.Statuses = append(msg.Statuses, w.history[len(w.history)-1])
+	}
+	w.mu.Unlock()
+
+	if len(msg.Statuses) == 0 {
+		return nil
+	}
+	w.logger.Info("replay status updates to master",
+		zap.String("worker-id", w.workerID),
+		zap.String("master-id", w.masterInfo.MasterID()),
+		zap.Int64("master-epoch", masterEpoch),
+		zap.Int("count", len(msg.Statuses)),
+		zap.Bool("truncated", msg.Truncated))
+	ok, err := w.messageSender.SendToNode(ctx, node, WorkerStatusReplayTopic(w.masterInfo.MasterID()), msg)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !ok {
+		// the master requests again if the replay is lost
+		w.logger.Warn("failed to replay status updates, try again later",
+			zap.String("worker-id", w.workerID))
+	}
+	return nil
 }
 
 func (w *Writer) sendStatusMessageWithRetry(
-	ctx context.Context, timeout time.Duration, newStatus *libModel.WorkerStatus,
+	ctx context.Context, timeout time.Duration, newStatus *libModel.WorkerStatus, seq uint64,
 ) error {
 	// NOTE we need this function especially to handle the situation where
 	// the p2p connection to the target executor is not established yet.
@@ -98,17 +307,19 @@ func (w *Writer) sendStatusMessageWithRetry(
 			Worker:      w.workerID,
 			MasterEpoch: w.masterInfo.Epoch(),
 			Status:      newStatus,
+			SendTime:    time.Now(),
+			Seq:         seq,
 		})
 		if err != nil {
 			if derrors.ErrExecutorNotFoundForMessage.Equal(err) {
 				if err := w.masterInfo.RefreshMasterInfo(ctx); err != nil {
-					log.L().Warn("failed to refresh master info",
+					w.logger.Warn("failed to refresh master info",
 						zap.String("worker-id", w.workerID),
 						zap.String("master-id", w.masterInfo.MasterID()),
 						zap.Error(err))
 				}
 			}
-			log.L().Warn("failed to send status to master. Retrying...",
+			w.logger.Warn("failed to send status to master. Retrying...",
 				zap.String("worker-id", w.workerID),
 				zap.String("master-id", w.masterInfo.MasterID()),
 				zap.Any("status", newStatus),
@@ -121,7 +332,7 @@ func (w *Writer) sendStatusMessageWithRetry(
 
 func (w *Writer) persistStatus(ctx context.Context, newStatus *libModel.WorkerStatus) error {
 	return retry.Do(ctx, func() error {
-		return w.metaclient.UpdateWorker(ctx, newStatus)
+		return w.metaclient.Update(ctx, newStatus)
 	}, retry.WithBackoffMaxDelay(1000 /* 1 second */), retry.WithIsRetryableErr(func(err error) bool {
 		// TODO: refine the IsRetryable method
 		//if err, ok := err.(metaclient.Error); ok {
@@ -3,10 +3,13 @@ package statusutil
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/hanfei1991/microcosm/lib/metadata"
 	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/pkg/clock"
 	derror "github.com/hanfei1991/microcosm/pkg/errors"
 	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
 	"github.com/hanfei1991/microcosm/pkg/p2p"
@@ -36,7 +39,7 @@ func newWriterTestSuite(
 		epoch:      masterEpoch,
 	}
 	return &writerTestSuite{
-		writer:        NewWriter(cli, messageSender, masterInfo, workerID),
+		writer:        NewWriter(metadata.NewWorkerMetadataClient(masterID, cli), messageSender, masterInfo, workerID),
 		cli:           cli,
 		messageSender: messageSender,
 		masterInfo:    masterInfo,
@@ -129,6 +132,153 @@ func TestWriterSendRetry(t *testing.T) {
 	}, msg)
 }
 
+func TestWriterCoalesce(t *testing.T) {
+	suite := newWriterTestSuite(t, "master-1", "executor-1", 1, "worker-1")
+	ctx := context.Background()
+	clk := clock.NewMock()
+	clk.Set(time.Now())
+	suite.writer.WithCoalesceWindow(time.Second, clk)
+
+	st := &libModel.WorkerStatus{
+		JobID: "master-1",
+		ID:    "worker-1",
+		Code:  libModel.WorkerStatusInit,
+	}
+	err := suite.cli.UpsertWorker(ctx, st)
+	require.NoError(t, err)
+
+	popStatus := func() (*libModel.WorkerStatus, bool) {
+		rawMsg, ok := suite.messageSender.TryPop("executor-1", WorkerStatusTopic("master-1"))
+		if !ok {
+			return nil, false
+		}
+		return rawMsg.(*WorkerStatusMessage).Status, true
+	}
+
+	// the first update is delivered at once
+	require.NoError(t, suite.writer.UpdateStatus(ctx, st))
+	_, ok := popStatus()
+	require.True(t, ok)
+
+	// the updates within the window are collapsed into the latest one
+	for i := 0; i < 10; i++ {
+		st.Code = libModel.WorkerStatusNormal
+		st.ExtBytes = []byte{byte(i)}
+		require.NoError(t, suite.writer.UpdateStatus(ctx, st))
+	}
+	_, ok = popStatus()
+	require.False(t, ok)
+	require.NoError(t, suite.writer.Flush(ctx, false))
+	_, ok = popStatus()
+	require.False(t, ok)
+	latest, _ := suite.writer.Seqs()
+	require.Equal(t, uint64(1), latest)
+
+	clk.Add(time.Second)
+	require.NoError(t, suite.writer.Flush(ctx, false))
+	status, ok := popStatus()
+	require.True(t, ok)
+	require.Equal(t, libModel.WorkerStatusNormal, status.Code)
+	require.Equal(t, []byte{9}, status.ExtBytes)
+	_, ok = popStatus()
+	require.False(t, ok)
+	persisted, err := suite.cli.GetWorkerByID(ctx, st.JobID, st.ID)
+	require.NoError(t, err)
+	require.Equal(t, libModel.WorkerStatusNormal, persisted.Code)
+
+	// a terminal status is delivered at once and supersedes the coalesced one
+	st.ExtBytes = []byte{10}
+	require.NoError(t, suite.writer.UpdateStatus(ctx, st))
+	st.Code = libModel.WorkerStatusFinished
+	require.NoError(t, suite.writer.UpdateStatus(ctx, st))
+	status, ok = popStatus()
+	require.True(t, ok)
+	require.Equal(t, libModel.WorkerStatusFinished, status.Code)
+	clk.Add(time.Second)
+	require.NoError(t, suite.writer.Flush(ctx, true))
+	_, ok = popStatus()
+	require.False(t, ok)
+	persisted, err = suite.cli.GetWorkerByID(ctx, st.JobID, st.ID)
+	require.NoError(t, err)
+	require.Equal(t, libModel.WorkerStatusFinished, persisted.Code)
+
+	var nilWriter *Writer
+	require.NoError(t, nilWriter.Flush(ctx, true))
+}
+
+func TestWriterPersistTerminalStatus(t *testing.T) {
+	suite := newWriterTestSuite(t, "master-1", "executor-1", 1, "worker-1")
+	ctx := context.Background()
+
+	st := &libModel.WorkerStatus{
+		JobID: "master-1",
+		ID:    "worker-1",
+		Code:  libModel.WorkerStatusNormal,
+	}
+	err := suite.cli.UpsertWorker(ctx, st)
+	require.NoError(t, err)
+	require.NoError(t, suite.writer.UpdateStatus(ctx, st))
+	_, ok := suite.messageSender.TryPop("executor-1", WorkerStatusTopic("master-1"))
+	require.True(t, ok)
+
+	err = suite.writer.PersistTerminalStatus(ctx, st)
+	require.True(t, derror.ErrWorkerStatusNotTerminal.Equal(err))
+
+	st.Code = libModel.WorkerStatusFinished
+	st.ExtBytes = []byte{1}
+	require.NoError(t, suite.writer.PersistTerminalStatus(ctx, st))
+	persisted, err := suite.cli.GetWorkerByID(ctx, st.JobID, st.ID)
+	require.NoError(t, err)
+	require.Equal(t, libModel.WorkerStatusFinished, persisted.Code)
+	rawMsg, ok := suite.messageSender.TryPop("executor-1", WorkerStatusTopic("master-1"))
+	require.True(t, ok)
+	require.Equal(t, libModel.WorkerStatusFinished, rawMsg.(*WorkerStatusMessage).Status.Code)
+	latest, persistedSeq := suite.writer.Seqs()
+	require.Equal(t, latest, persistedSeq)
+}
+
+func TestWriterReplay(t *testing.T) {
+	suite := newWriterTestSuite(t, "master-1", "executor-1", 1, "worker-1")
+	ctx := context.Background()
+
+	st := &libModel.WorkerStatus{
+		JobID: "master-1",
+		ID:    "worker-1",
+		Code:  libModel.WorkerStatusNormal,
+	}
+	err := suite.cli.UpsertWorker(ctx, st)
+	require.NoError(t, err)
+
+	// only the first update is persisted
+	for i := 0; i < statusHistorySize+2; i++ {
+		newStatus := *st
+		newStatus.ExtBytes = []byte{byte(i)}
+		err = suite.writer.UpdateStatus(ctx, &newStatus)
+		require.NoError(t, err)
+	}
+	latest, persisted := suite.writer.Seqs()
+	require.Equal(t, uint64(statusHistorySize+2), latest)
+	require.Equal(t, uint64(1), persisted)
+
+	// the master fails over to executor-2
+	err = suite.writer.Replay(ctx, "executor-2", 2)
+	require.NoError(t, err)
+	rawMsg, ok := suite.messageSender.TryPop("executor-2", WorkerStatusReplayTopic("master-1"))
+	require.True(t, ok)
+	msg := rawMsg.(*WorkerStatusReplayMessage)
+	require.Equal(t, libModel.Epoch(2), msg.MasterEpoch)
+	require.True(t, msg.Truncated)
+	require.Len(t, msg.Statuses, statusHistorySize)
+	require.Equal(t, uint64(3), msg.Statuses[0].Seq)
+	require.Equal(t, latest, msg.Statuses[statusHistorySize-1].Seq)
+	require.Equal(t, []byte{byte(statusHistorySize + 1)}, msg.Statuses[statusHistorySize-1].Status.ExtBytes)
+
+	var nilWriter *Writer
+	latest, persisted = nilWriter.Seqs()
+	require.Zero(t, latest)
+	require.Zero(t, persisted)
+}
+
 func checkWorkerStatusMsg(t *testing.T, expect, msg *WorkerStatusMessage) {
 	require.Equal(t, expect.Worker, msg.Worker)
 	require.Equal(t, expect.MasterEpoch, msg.MasterEpoch)
@@ -0,0 +1,122 @@
+package lib
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/pkg/clock"
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+const defaultTickOverrunThreshold = time.Second
+
+// Phases of a Poll measured by the tick watchdog
+const (
+	tickPhaseImpl          = "impl"
+	tickPhaseWorkerManager = "worker-manager"
+)
+
+// TickStats is the latency of the latest Poll of a master.
+type TickStats struct {
+	// LastDurations is the duration of each phase in the latest Poll, the
+	// phases are "impl" for the Tick of MasterImpl, and "worker-manager" for
+	// handling worker events.
+	LastDurations map[string]time.Duration `json:"last-durations"`
+	// ConsecutiveOverruns is the number of the latest consecutive Polls that
+	// have any phase exceeding the threshold.
+	ConsecutiveOverruns int       `json:"consecutive-overruns"`
+	TotalOverruns       int64     `json:"total-overruns"`
+	LastOverrunTime     time.Time `json:"last-overrun-time"`
+}
+
+// WithTickWatchdog sets the threshold of the duration of each phase in a
+// Poll, and fails the master after maxConsecutiveOverruns consecutive Polls
+// overran, so that the job fails over instead of limping along. A zero
+// maxConsecutiveOverruns only logs and reports the overruns.
+func WithTickWatchdog(threshold time.Duration, maxConsecutiveOverruns int) BaseMasterOption {
+	return func(m *DefaultBaseMaster) {
+		m.tickWatchdog.threshold = threshold
+		m.tickWatchdog.maxOverruns = maxConsecutiveOverruns
+	}
+}
+
+// tickWatchdog measures the phases of Polls of a master.
+type tickWatchdog struct {
+	masterID    libModel.MasterID
+	logger      log.Logger
+	clock       clock.Clock
+	threshold   time.Duration
+	maxOverruns int
+
+	mu    sync.Mutex
+	stats TickStats
+	// overran is set if any phase of the current Poll has overrun
+	overran bool
+}
+
+func newTickWatchdog(masterID libModel.MasterID, logger log.Logger, clk clock.Clock) *tickWatchdog {
+	return &tickWatchdog{
+		masterID:  masterID,
+		logger:    logger,
+		clock:     clk,
+		threshold: defaultTickOverrunThreshold,
+		stats:     TickStats{LastDurations: make(map[string]time.Duration)},
+	}
+}
+
+// measure runs fn as a phase of the current Poll.
+func (w *tickWatchdog) measure(phase string, fn func() error) error {
+	start := w.clock.Now()
+	err := fn()
+	duration := w.clock.Since(start)
+	tickDuration.WithLabelValues(w.masterID, phase).Observe(duration.Seconds())
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.stats.LastDurations[phase] = duration
+	if duration > w.threshold {
+		tickOverrunCounter.WithLabelValues(w.masterID, phase).Inc()
+		w.overran = true
+	}
+	return err
+}
+
+// finishPoll ends the current Poll, it returns ErrMasterTickOverrun if the
+// master should fail.
+func (w *tickWatchdog) finishPoll() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.overran {
+		w.stats.ConsecutiveOverruns = 0
+		return nil
+	}
+	w.overran = false
+	w.stats.ConsecutiveOverruns++
+	w.stats.TotalOverruns++
+	w.stats.LastOverrunTime = w.clock.Now()
+	w.logger.Warn("master tick overran",
+		zap.Duration("threshold", w.threshold),
+		zap.Any("durations", w.stats.LastDurations),
+		zap.Int("consecutive-overruns", w.stats.ConsecutiveOverruns))
+	if w.maxOverruns > 0 && w.stats.ConsecutiveOverruns >= w.maxOverruns {
+		return derror.ErrMasterTickOverrun.GenWithStackByArgs(w.threshold, w.stats.ConsecutiveOverruns)
+	}
+	return nil
+}
+
+func (w *tickWatchdog) snapshot() TickStats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ret := w.stats
+	ret.LastDurations = make(map[string]time.Duration, len(w.stats.LastDurations))
+	for phase, duration := range w.stats.LastDurations {
+		ret.LastDurations[phase] = duration
+	}
+	return ret
+}
@@ -0,0 +1,54 @@
+package lib
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hanfei1991/microcosm/pkg/clock"
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+func TestTickWatchdog(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.NewMock()
+	watchdog := newTickWatchdog("master-1", log.L(), clk)
+	watchdog.maxOverruns = 2
+	poll := func(workerManagerDuration, implDuration time.Duration) error {
+		require.NoError(t, watchdog.measure(tickPhaseWorkerManager, func() error {
+			clk.Add(workerManagerDuration)
+			return nil
+		}))
+		require.NoError(t, watchdog.measure(tickPhaseImpl, func() error {
+			clk.Add(implDuration)
+			return nil
+		}))
+		return watchdog.finishPoll()
+	}
+
+	require.NoError(t, poll(10*time.Millisecond, 20*time.Millisecond))
+	stats := watchdog.snapshot()
+	require.Equal(t, map[string]time.Duration{
+		tickPhaseWorkerManager: 10 * time.Millisecond,
+		tickPhaseImpl:          20 * time.Millisecond,
+	}, stats.LastDurations)
+	require.Zero(t, stats.ConsecutiveOverruns)
+
+	require.NoError(t, poll(2*time.Second, 0))
+	// the consecutive overruns are reset by a normal Poll
+	require.NoError(t, poll(0, 0))
+	require.NoError(t, poll(0, 2*time.Second))
+	stats = watchdog.snapshot()
+	require.Equal(t, 1, stats.ConsecutiveOverruns)
+	require.Equal(t, int64(2), stats.TotalOverruns)
+	require.Equal(t, clk.Now(), stats.LastOverrunTime)
+
+	err := poll(0, 2*time.Second)
+	require.True(t, derror.ErrMasterTickOverrun.Equal(err))
+	stats = watchdog.snapshot()
+	require.Equal(t, 2, stats.ConsecutiveOverruns)
+	require.Equal(t, int64(3), stats.TotalOverruns)
+}
@@ -0,0 +1,194 @@
+package twopc
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/pkg/adapter"
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/logutil"
+	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
+)
+
+// Coordinator is used by the master of a job to decide the transactions of
+// checkpoints. It is not thread-safe with Recover, which should be called
+// before the other methods.
+type Coordinator struct {
+	kv    metaclient.KV
+	jobID libModel.MasterID
+
+	mu   sync.Mutex
+	txns map[int64]*Txn
+}
+
+// NewCoordinator creates a new Coordinator
+func NewCoordinator(kv metaclient.KV, jobID libModel.MasterID) *Coordinator {
+	return &Coordinator{
+		kv:    kv,
+		jobID: jobID,
+		txns:  make(map[int64]*Txn),
+	}
+}
+
+// Recover loads the transactions of the job from metastore, it is called
+// when the master is initialized or recovered. The committed transactions are
+// replayed by the participants, and the undecided ones are aborted because
+// the pre-committed data may be lost with the failed participants.
+func (c *Coordinator) Recover(ctx context.Context) error {
+	resp, err := c.kv.Get(ctx, adapter.TxnKeyAdapter.Curry(c.jobID).Path(), metaclient.WithPrefix())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	txns := make(map[int64]*Txn, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		txn := &Txn{}
+		if err := json.Unmarshal(kv.Value, txn); err != nil {
+			return errors.Trace(err)
+		}
+		if txn.Decision == DecisionPending {
+			txn.Decision = DecisionAbort
+			if err := storeTxn(ctx, c.kv, c.jobID, txn); err != nil {
+				return err
+			}
+			logutil.WithJobID(log.L(), c.jobID).Info("abort undecided transaction after recovery",
+				zap.Int64("checkpoint-id", txn.CheckpointID))
+		}
+		txns[txn.CheckpointID] = txn
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.txns = txns
+	return nil
+}
+
+// Begin starts the transaction of the checkpoint, which is committed after
+// all the participants acknowledge it. The participants can pre-commit the
+// checkpoint only after it begins.
+func (c *Coordinator) Begin(ctx context.Context, checkpointID int64, participants []string) error {
+	c.mu.Lock()
+	_, exists := c.txns[checkpointID]
+	c.mu.Unlock()
+	if exists {
+		return derror.ErrTxnDuplicated.GenWithStackByArgs(checkpointID)
+	}
+
+	txn := &Txn{
+		CheckpointID: checkpointID,
+		Participants: participants,
+		Decision:     DecisionPending,
+	}
+	if err := storeTxn(ctx, c.kv, c.jobID, txn); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.txns[checkpointID] = txn
+	return nil
+}
+
+// Abort aborts a pending transaction, for example when a participant fails
+// before acknowledging it.
+func (c *Coordinator) Abort(ctx context.Context, checkpointID int64) error {
+	return c.decide(ctx, checkpointID, DecisionAbort)
+}
+
+// Decision returns the decision of the transaction of the checkpoint.
+func (c *Coordinator) Decision(checkpointID int64) (Decision, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	txn, ok := c.txns[checkpointID]
+	if !ok {
+		return "", false
+	}
+	return txn.Decision, true
+}
+
+// Poll commits the pending transactions acknowledged by all the
+// participants, and returns their checkpoint IDs in ascending order. The
+// decided transactions are removed after all the participants have committed
+// or aborted them.
+func (c *Coordinator) Poll(ctx context.Context) ([]int64, error) {
+	c.mu.Lock()
+	txns := make([]*Txn, 0, len(c.txns))
+	for _, txn := range c.txns {
+		txns = append(txns, txn)
+	}
+	c.mu.Unlock()
+	sort.Slice(txns, func(i, j int) bool {
+		return txns[i].CheckpointID < txns[j].CheckpointID
+	})
+
+	var committed []int64
+	for _, txn := range txns {
+		acks, err := loadAcks(ctx, c.kv, c.jobID, txn.CheckpointID)
+		if err != nil {
+			return committed, err
+		}
+
+		if txn.Decision != DecisionPending {
+			if len(acks) > 0 {
+				continue
+			}
+			if _, err := c.kv.Delete(ctx, txnKey(c.jobID, txn.CheckpointID)); err != nil {
+				return committed, errors.Trace(err)
+			}
+			c.mu.Lock()
+			delete(c.txns, txn.CheckpointID)
+			c.mu.Unlock()
+			continue
+		}
+
+		acked := true
+		for _, participant := range txn.Participants {
+			if _, ok := acks[participant]; !ok {
+				acked = false
+				break
+			}
+		}
+		if !acked {
+			continue
+		}
+		if err := c.decide(ctx, txn.CheckpointID, DecisionCommit); err != nil {
+			return committed, err
+		}
+		logutil.WithJobID(log.L(), c.jobID).Info("transaction committed",
+			zap.Int64("checkpoint-id", txn.CheckpointID),
+			zap.Int("participant-count", len(txn.Participants)))
+		committed = append(committed, txn.CheckpointID)
+	}
+	return committed, nil
+}
+
+func (c *Coordinator) decide(ctx context.Context, checkpointID int64, decision Decision) error {
+	c.mu.Lock()
+	txn, ok := c.txns[checkpointID]
+	c.mu.Unlock()
+	if !ok {
+		return derror.ErrTxnNotFound.GenWithStackByArgs(checkpointID)
+	}
+	if txn.Decision != DecisionPending {
+		if txn.Decision == decision {
+			return nil
+		}
+		return derror.ErrTxnDecided.GenWithStackByArgs(checkpointID, txn.Decision)
+	}
+
+	decided := *txn
+	decided.Decision = decision
+	if err := storeTxn(ctx, c.kv, c.jobID, &decided); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.txns[checkpointID] = &decided
+	return nil
+}
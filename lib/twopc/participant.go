@@ -0,0 +1,157 @@
+package twopc
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/pkg/adapter"
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/logutil"
+	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
+)
+
+// Participant is used by a worker to pre-commit checkpoints in its sink, and
+// to commit or abort them as decided by the master.
+type Participant struct {
+	kv    metaclient.KV
+	jobID libModel.MasterID
+	// id should be kept when the worker is recreated, such as the ID of the
+	// task of the worker, so that the recreated worker can recover the
+	// pre-committed checkpoints.
+	id   string
+	sink Sink
+
+	mu sync.Mutex
+	// the pre-committed checkpoints that are not committed or aborted
+	pending map[int64]struct{}
+}
+
+// NewParticipant creates a new Participant
+func NewParticipant(kv metaclient.KV, jobID libModel.MasterID, participantID string, sink Sink) *Participant {
+	return &Participant{
+		kv:      kv,
+		jobID:   jobID,
+		id:      participantID,
+		sink:    sink,
+		pending: make(map[int64]struct{}),
+	}
+}
+
+// Recover loads the checkpoints pre-committed by the participant from
+// metastore, which are committed or aborted by the following Poll.
+func (p *Participant) Recover(ctx context.Context) error {
+	resp, err := p.kv.Get(ctx, adapter.TxnAckKeyAdapter.Curry(p.jobID).Path(), metaclient.WithPrefix())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	pending := make(map[int64]struct{})
+	for _, kv := range resp.Kvs {
+		keys, err := adapter.TxnAckKeyAdapter.Decode(string(kv.Key))
+		if err != nil {
+			return err
+		}
+		// keys are (jobID, checkpointID, participantID)
+		if len(keys) != 3 || keys[2] != p.id {
+			continue
+		}
+		checkpointID, err := strconv.ParseInt(keys[1], 10, 64)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		pending[checkpointID] = struct{}{}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pending = pending
+	return nil
+}
+
+// PreCommit pre-commits the checkpoint in the sink and acknowledges it to
+// the master. It returns ErrTxnNotFound if the transaction of the checkpoint
+// hasn't begun, and ErrTxnDecided if it has been aborted.
+func (p *Participant) PreCommit(ctx context.Context, checkpointID int64) error {
+	txn, ok, err := loadTxn(ctx, p.kv, p.jobID, checkpointID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return derror.ErrTxnNotFound.GenWithStackByArgs(checkpointID)
+	}
+	if txn.Decision != DecisionPending {
+		return derror.ErrTxnDecided.GenWithStackByArgs(checkpointID, txn.Decision)
+	}
+
+	if err := p.sink.PreCommit(ctx, checkpointID); err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := p.kv.Put(ctx, ackKey(p.jobID, checkpointID, p.id), p.id); err != nil {
+		return errors.Trace(err)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pending[checkpointID] = struct{}{}
+	return nil
+}
+
+// Poll commits or aborts the pre-committed checkpoints in the sink as decided
+// by the master, and returns the committed checkpoint IDs in ascending order.
+// A transaction is removed by the master only after all the participants
+// have committed or aborted it, or after it's aborted, so a missing
+// transaction of a pre-committed checkpoint means it has been aborted.
+func (p *Participant) Poll(ctx context.Context) ([]int64, error) {
+	p.mu.Lock()
+	checkpointIDs := make([]int64, 0, len(p.pending))
+	for checkpointID := range p.pending {
+		checkpointIDs = append(checkpointIDs, checkpointID)
+	}
+	p.mu.Unlock()
+	sort.Slice(checkpointIDs, func(i, j int) bool {
+		return checkpointIDs[i] < checkpointIDs[j]
+	})
+
+	var committed []int64
+	for _, checkpointID := range checkpointIDs {
+		txn, ok, err := loadTxn(ctx, p.kv, p.jobID, checkpointID)
+		if err != nil {
+			return committed, err
+		}
+		decision := DecisionAbort
+		if ok {
+			decision = txn.Decision
+		}
+		switch decision {
+		case DecisionPending:
+			continue
+		case DecisionCommit:
+			err = p.sink.Commit(ctx, checkpointID)
+		default:
+			err = p.sink.Abort(ctx, checkpointID)
+		}
+		if err != nil {
+			return committed, errors.Trace(err)
+		}
+		if _, err := p.kv.Delete(ctx, ackKey(p.jobID, checkpointID, p.id)); err != nil {
+			return committed, errors.Trace(err)
+		}
+		p.mu.Lock()
+		delete(p.pending, checkpointID)
+		p.mu.Unlock()
+
+		logutil.WithJobID(log.L(), p.jobID).Info("transaction finished in sink",
+			zap.String("participant-id", p.id),
+			zap.Int64("checkpoint-id", checkpointID),
+			zap.String("decision", string(decision)))
+		if decision == DecisionCommit {
+			committed = append(committed, checkpointID)
+		}
+	}
+	return committed, nil
+}
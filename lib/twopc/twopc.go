@@ -0,0 +1,104 @@
+// Package twopc provides a two-phase commit helper for sinks to write exactly
+// once. The participants of a job, usually its workers, pre-commit the data
+// of a checkpoint in their sinks and acknowledge it, the master commits the
+// checkpoint globally after all the participants have acknowledged, then the
+// participants commit their pre-committed data. The decisions and the
+// acknowledgements are persisted in metastore, so that after a failover the
+// committed checkpoints are replayed, and the undecided ones are aborted.
+package twopc
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/pingcap/errors"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/pkg/adapter"
+	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
+)
+
+// Decision is the decision of a transaction
+type Decision string
+
+// Defines all decisions of transactions
+const (
+	// DecisionPending means the transaction is waiting for acknowledgements
+	DecisionPending = Decision("pending")
+	// DecisionCommit means the pre-committed data should be committed
+	DecisionCommit = Decision("commit")
+	// DecisionAbort means the pre-committed data should be discarded
+	DecisionAbort = Decision("abort")
+)
+
+// Txn is the transaction of a checkpoint, which is persisted under
+// (jobID, checkpointID).
+type Txn struct {
+	CheckpointID int64    `json:"checkpoint-id"`
+	Participants []string `json:"participants"`
+	Decision     Decision `json:"decision"`
+}
+
+// Sink is implemented by the sinks committing exactly once. All the methods
+// must be idempotent, because they are called again after failover if the
+// result isn't persisted.
+type Sink interface {
+	// PreCommit makes the data written before the checkpoint durable but
+	// invisible.
+	PreCommit(ctx context.Context, checkpointID int64) error
+	// Commit makes the pre-committed data of the checkpoint visible.
+	Commit(ctx context.Context, checkpointID int64) error
+	// Abort discards the pre-committed data of the checkpoint.
+	Abort(ctx context.Context, checkpointID int64) error
+}
+
+func checkpointKey(checkpointID int64) string {
+	return strconv.FormatInt(checkpointID, 10)
+}
+
+func txnKey(jobID libModel.MasterID, checkpointID int64) string {
+	return adapter.TxnKeyAdapter.Encode(jobID, checkpointKey(checkpointID))
+}
+
+func ackKey(jobID libModel.MasterID, checkpointID int64, participantID string) string {
+	return adapter.TxnAckKeyAdapter.Encode(jobID, checkpointKey(checkpointID), participantID)
+}
+
+func loadTxn(ctx context.Context, kv metaclient.KV, jobID libModel.MasterID, checkpointID int64) (*Txn, bool, error) {
+	resp, err := kv.Get(ctx, txnKey(jobID, checkpointID))
+	if err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, false, nil
+	}
+	txn := &Txn{}
+	if err := json.Unmarshal(resp.Kvs[0].Value, txn); err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	return txn, true, nil
+}
+
+func storeTxn(ctx context.Context, kv metaclient.KV, jobID libModel.MasterID, txn *Txn) error {
+	value, err := json.Marshal(txn)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, metaErr := kv.Put(ctx, txnKey(jobID, txn.CheckpointID), string(value))
+	return errors.Trace(metaErr)
+}
+
+// loadAcks returns the participants that have acknowledged the checkpoint.
+func loadAcks(ctx context.Context, kv metaclient.KV, jobID libModel.MasterID, checkpointID int64) (map[string]struct{}, error) {
+	resp, err := kv.Get(ctx,
+		adapter.TxnAckKeyAdapter.Curry(jobID, checkpointKey(checkpointID)).Path(), metaclient.WithPrefix())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	acks := make(map[string]struct{}, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		acks[string(kv.Value)] = struct{}{}
+	}
+	return acks, nil
+}
@@ -0,0 +1,171 @@
+package twopc
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/meta/kvclient/mock"
+)
+
+type mockSink struct {
+	mu           sync.Mutex
+	preCommitted []int64
+	committed    []int64
+	aborted      []int64
+}
+
+func (s *mockSink) PreCommit(ctx context.Context, checkpointID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.preCommitted = append(s.preCommitted, checkpointID)
+	return nil
+}
+
+func (s *mockSink) Commit(ctx context.Context, checkpointID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.committed = append(s.committed, checkpointID)
+	return nil
+}
+
+func (s *mockSink) Abort(ctx context.Context, checkpointID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.aborted = append(s.aborted, checkpointID)
+	return nil
+}
+
+func TestTwoPhaseCommit(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	kv := mock.NewMetaMock()
+	coordinator := NewCoordinator(kv, "job-1")
+	require.NoError(t, coordinator.Recover(ctx))
+	sink1, sink2 := &mockSink{}, &mockSink{}
+	p1 := NewParticipant(kv, "job-1", "task-1", sink1)
+	p2 := NewParticipant(kv, "job-1", "task-2", sink2)
+
+	// participants can't pre-commit before the transaction begins
+	err := p1.PreCommit(ctx, 1)
+	require.True(t, derror.ErrTxnNotFound.Equal(err))
+
+	require.NoError(t, coordinator.Begin(ctx, 1, []string{"task-1", "task-2"}))
+	err = coordinator.Begin(ctx, 1, []string{"task-1", "task-2"})
+	require.True(t, derror.ErrTxnDuplicated.Equal(err))
+
+	require.NoError(t, p1.PreCommit(ctx, 1))
+	committed, err := coordinator.Poll(ctx)
+	require.NoError(t, err)
+	require.Empty(t, committed)
+	committed, err = p1.Poll(ctx)
+	require.NoError(t, err)
+	require.Empty(t, committed)
+
+	require.NoError(t, p2.PreCommit(ctx, 1))
+	committed, err = coordinator.Poll(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []int64{1}, committed)
+	decision, ok := coordinator.Decision(1)
+	require.True(t, ok)
+	require.Equal(t, DecisionCommit, decision)
+	err = coordinator.Abort(ctx, 1)
+	require.True(t, derror.ErrTxnDecided.Equal(err))
+
+	committed, err = p1.Poll(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []int64{1}, committed)
+	require.Equal(t, []int64{1}, sink1.committed)
+
+	// the transaction is kept until all the participants have committed it
+	_, err = coordinator.Poll(ctx)
+	require.NoError(t, err)
+	_, ok = coordinator.Decision(1)
+	require.True(t, ok)
+
+	committed, err = p2.Poll(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []int64{1}, committed)
+	_, err = coordinator.Poll(ctx)
+	require.NoError(t, err)
+	_, ok = coordinator.Decision(1)
+	require.False(t, ok)
+}
+
+func TestTwoPhaseAbort(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	kv := mock.NewMetaMock()
+	coordinator := NewCoordinator(kv, "job-1")
+	sink1, sink2 := &mockSink{}, &mockSink{}
+	p1 := NewParticipant(kv, "job-1", "task-1", sink1)
+	p2 := NewParticipant(kv, "job-1", "task-2", sink2)
+
+	require.NoError(t, coordinator.Begin(ctx, 1, []string{"task-1", "task-2"}))
+	require.NoError(t, p1.PreCommit(ctx, 1))
+	// task-2 fails before acknowledging
+	require.NoError(t, coordinator.Abort(ctx, 1))
+	err := p2.PreCommit(ctx, 1)
+	require.True(t, derror.ErrTxnDecided.Equal(err))
+	require.Empty(t, sink2.preCommitted)
+
+	committed, err := p1.Poll(ctx)
+	require.NoError(t, err)
+	require.Empty(t, committed)
+	require.Equal(t, []int64{1}, sink1.aborted)
+
+	_, err = coordinator.Poll(ctx)
+	require.NoError(t, err)
+	_, ok := coordinator.Decision(1)
+	require.False(t, ok)
+}
+
+func TestTwoPhaseFailover(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	kv := mock.NewMetaMock()
+	coordinator := NewCoordinator(kv, "job-1")
+	sink := &mockSink{}
+	p := NewParticipant(kv, "job-1", "task-1", sink)
+
+	require.NoError(t, coordinator.Begin(ctx, 1, []string{"task-1"}))
+	require.NoError(t, p.PreCommit(ctx, 1))
+	committed, err := coordinator.Poll(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []int64{1}, committed)
+	require.NoError(t, coordinator.Begin(ctx, 2, []string{"task-1"}))
+	require.NoError(t, p.PreCommit(ctx, 2))
+
+	// both the master and the worker fail over, the committed transaction is
+	// replayed and the undecided one is aborted
+	coordinator = NewCoordinator(kv, "job-1")
+	require.NoError(t, coordinator.Recover(ctx))
+	decision, ok := coordinator.Decision(1)
+	require.True(t, ok)
+	require.Equal(t, DecisionCommit, decision)
+	decision, ok = coordinator.Decision(2)
+	require.True(t, ok)
+	require.Equal(t, DecisionAbort, decision)
+
+	sink = &mockSink{}
+	p = NewParticipant(kv, "job-1", "task-1", sink)
+	require.NoError(t, p.Recover(ctx))
+	committed, err = p.Poll(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []int64{1}, committed)
+	require.Equal(t, []int64{1}, sink.committed)
+	require.Equal(t, []int64{2}, sink.aborted)
+
+	_, err = coordinator.Poll(ctx)
+	require.NoError(t, err)
+	_, ok = coordinator.Decision(1)
+	require.False(t, ok)
+	_, ok = coordinator.Decision(2)
+	require.False(t, ok)
+}
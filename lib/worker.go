@@ -2,9 +2,12 @@ package lib
 
 import (
 	"context"
+	"encoding/json"
+	"os"
 	"sync"
 	"time"
 
+	bclock "github.com/benbjohnson/clock"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tiflow/dm/pkg/log"
 	"github.com/pingcap/tiflow/pkg/workerpool"
@@ -14,21 +17,27 @@ import (
 
 	runtime "github.com/hanfei1991/microcosm/executor/worker"
 	"github.com/hanfei1991/microcosm/lib/config"
+	"github.com/hanfei1991/microcosm/lib/exchange"
 	"github.com/hanfei1991/microcosm/lib/metadata"
 	libModel "github.com/hanfei1991/microcosm/lib/model"
 	"github.com/hanfei1991/microcosm/lib/statusutil"
 	"github.com/hanfei1991/microcosm/model"
 	"github.com/hanfei1991/microcosm/pkg/clock"
+	"github.com/hanfei1991/microcosm/pkg/compat"
 	dcontext "github.com/hanfei1991/microcosm/pkg/context"
 	"github.com/hanfei1991/microcosm/pkg/errctx"
 	derror "github.com/hanfei1991/microcosm/pkg/errors"
 	"github.com/hanfei1991/microcosm/pkg/externalresource/broker"
 	resourcemeta "github.com/hanfei1991/microcosm/pkg/externalresource/resourcemeta/model"
+	"github.com/hanfei1991/microcosm/pkg/faultinject"
+	"github.com/hanfei1991/microcosm/pkg/logutil"
+	"github.com/hanfei1991/microcosm/pkg/meta/encryption"
 	extkv "github.com/hanfei1991/microcosm/pkg/meta/extension"
 	"github.com/hanfei1991/microcosm/pkg/meta/kvclient"
 	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
 	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
 	"github.com/hanfei1991/microcosm/pkg/p2p"
+	"github.com/hanfei1991/microcosm/pkg/sharedcache"
 	"github.com/hanfei1991/microcosm/pkg/tenant"
 )
 
@@ -67,6 +76,26 @@ type WorkerImpl interface {
 	CloseImpl(ctx context.Context) error
 }
 
+// ResourceUsageAwareWorkerImpl can be implemented by a WorkerImpl to report
+// its resource usage in multiple dimensions, such as memory or custom
+// resources. Unlike Workload, the usage is accounted by the scheduler of
+// server master when it schedules new workers to the executor.
+type ResourceUsageAwareWorkerImpl interface {
+	ResourceUsage() model.RescVector
+}
+
+// StatusCoalescingWorkerImpl can be implemented by a WorkerImpl that updates
+// its status frequently. The status updates within the coalesce window after
+// the last delivered one are collapsed, and only the latest of them is
+// persisted and sent to the master when the window has passed. A terminal
+// status, i.e. Finished, Stopped or Error, is always delivered at once.
+type StatusCoalescingWorkerImpl interface {
+	// StatusCoalesceWindow returns the coalesce window, it is called once
+	// when the worker is initialized. A non-positive value disables
+	// coalescing.
+	StatusCoalesceWindow() time.Duration
+}
+
 // BaseWorker defines the worker interface, it embeds a Worker interface and adds
 // more utility methods
 type BaseWorker interface {
@@ -75,11 +104,60 @@ type BaseWorker interface {
 	MetaKVClient() metaclient.KVClient
 	UpdateStatus(ctx context.Context, status libModel.WorkerStatus) error
 	SendMessage(ctx context.Context, topic p2p.Topic, message interface{}) (bool, error)
+	// SendWorkerMessage sends a message to the handler registered by
+	// RegisterWorkerMessageHandler on the master side.
+	SendWorkerMessage(ctx context.Context, topic p2p.Topic, message interface{}) (bool, error)
+	// ScheduleSelfMessage schedules a message to the worker itself, it is
+	// passed to WorkerImpl.OnMasterMessage with the topic on or after the
+	// delay passes, and is dropped if the worker exits before that. It
+	// should be used for delayed retries instead of timers in goroutines.
+	ScheduleSelfMessage(delay time.Duration, topic p2p.Topic, payload p2p.MessageValue) error
+	// ReachBarrier reports that the worker has reached a barrier requested
+	// by BaseMaster.RequestBarrier.
+	ReachBarrier(ctx context.Context, barrierID string) error
+	// OpenChannelSender opens the sending end of a data channel to a peer
+	// worker of the same job, the executor of the peer is looked up from
+	// the master. The peer should open the receiving end with the same
+	// channelID and Window.
+	OpenChannelSender(ctx context.Context, peerID libModel.WorkerID, channelID string, cfg exchange.Config) (*exchange.Sender, error)
+	// OpenChannelReceiver opens the receiving end of a data channel.
+	OpenChannelReceiver(ctx context.Context, channelID string, cfg exchange.Config) (*exchange.Receiver, error)
+	// SharedCache returns the cache shared by the workers of the same job
+	// on the executor.
+	SharedCache() sharedcache.Client
+	// Logger returns a logger tagged with the job, the worker, the project
+	// and the epoch of the master, it should be used by WorkerImpl instead of
+	// log.L(). The contexts passed to InitImpl, Tick and CloseImpl carry the
+	// same logger, see logutil.FromContext.
+	Logger() log.Logger
 	OpenStorage(ctx context.Context, resourcePath resourcemeta.ResourceID) (broker.Handle, error)
+	// OpenSharedStorage opens a resource persisted by another worker of the
+	// same job in read-only mode.
+	OpenSharedStorage(ctx context.Context, resourcePath resourcemeta.ResourceID) (broker.Handle, error)
 	// Exit should be called when worker (in user logic) wants to exit.
 	// When `err` is not nil, the status code is assigned WorkerStatusError.
 	// Otherwise worker should set its status code to a meaningful value.
+	// The status is persisted by PersistTerminalStatus before Exit returns,
+	// so it is never lost if the worker crashes after exiting.
 	Exit(ctx context.Context, status libModel.WorkerStatus, err error) error
+	// PersistTerminalStatus persists a terminal status durably, with retries
+	// and a deadline, and then notifies the master. If it returns without an
+	// error, the status can be read back from the metastore. It can be used
+	// by a WorkerImpl to persist its final status before releasing resources
+	// in CloseImpl, and is called by Exit implicitly.
+	PersistTerminalStatus(ctx context.Context, status libModel.WorkerStatus) error
+	// Getenv returns the environment variable of the worker passed in the
+	// dispatch request, or that of the process if it is not passed.
+	Getenv(key string) string
+	// FeatureEnabled returns whether the feature is supported by the master
+	// and all its workers, it is false during a rolling upgrade until all of
+	// them have been upgraded.
+	FeatureEnabled(feature compat.Feature) bool
+	// CommonCapabilities returns the capabilities supported by both the worker
+	// and its master, negotiated is false before the first heartbeat pong.
+	// Methods relying on a capability the master doesn't support return
+	// ErrCapabilityNotSupported, so that the impl can fall back.
+	CommonCapabilities() (caps libModel.CapabilitySet, negotiated bool)
 }
 
 type workerExitFsmState = int32
@@ -102,18 +180,32 @@ type DefaultBaseWorker struct {
 	// user metastore raw kvclient
 	userRawKVClient extkv.KVClientEx
 	resourceBroker  broker.Broker
+	sharedCache     sharedcache.Client
+	// faultInjector is nil unless faults are injected by tests.
+	faultInjector *faultinject.Injector
+	// statusBatcher is nil unless status batching is enabled on the executor.
+	statusBatcher *metadata.WorkerStatusBatcher
 
 	masterClient *masterClient
 	masterID     libModel.MasterID
 
 	workerMetaClient *metadata.WorkerMetadataClient
 	statusSender     *statusutil.Writer
+	barrierReporter  *statusutil.BarrierReporter
+	peerResolver     *exchange.PeerResolver
 	workerStatus     *libModel.WorkerStatus
 	messageRouter    *MessageRouter
+	selfMessages     *selfMessageQueue
 
 	id            libModel.WorkerID
+	logger        *taggedLogger // tagged with the job and worker, see Logger
 	timeoutConfig config.TimeoutConfig
 
+	// env and preMountResources are passed in the dispatch request, see
+	// Getenv and doPreInit.
+	env               map[string]string
+	preMountResources []resourcemeta.ResourceID
+
 	pool workerpool.AsyncPool
 
 	wg        sync.WaitGroup
@@ -125,6 +217,16 @@ type DefaultBaseWorker struct {
 
 	exitController *workerExitController
 
+	// creatingWorkers returns the number of workers being created by the
+	// worker, it is reported in heartbeats and is nil unless the worker is
+	// a job master.
+	creatingWorkers func() int32
+	// onLogLevelUpdated is called after the log level of the job is updated
+	// by the master, it is nil unless the worker is a job master.
+	onLogLevelUpdated func(level string)
+	// tickProbe is nil unless Impl is a LivenessProbedWorkerImpl.
+	tickProbe *tickProbe
+
 	clock clock.Clock
 
 	// user metastore prefix kvclient
@@ -140,6 +242,15 @@ type workerParams struct {
 	FrameMetaClient       pkgOrm.Client
 	UserRawKVClient       extkv.KVClientEx
 	ResourceBroker        broker.Broker
+	SharedCache           sharedcache.Client    `optional:"true"`
+	FaultInjector         *faultinject.Injector `optional:"true"`
+	// StatusBatcher persists the statuses of the workers on an executor in
+	// batches
+	StatusBatcher *metadata.WorkerStatusBatcher `optional:"true"`
+	// Clock is provided to run the worker on a virtual clock in tests
+	Clock clock.Clock `optional:"true"`
+	// UserMetaKeyring encrypts the values in the user metastore
+	UserMetaKeyring *encryption.Keyring `optional:"true"`
 }
 
 // NewBaseWorker creates a new BaseWorker instance
@@ -150,11 +261,32 @@ func NewBaseWorker(
 	masterID libModel.MasterID,
 	// tp libModel.WorkerType,
 ) BaseWorker {
+	jobID := masterID
+	if masterID == metadata.JobManagerUUID {
+		// the worker is a job master
+		jobID = workerID
+	}
+	logger := logutil.WithWorkerID(logutil.WithJobID(ctx.L(), jobID), workerID)
+
 	var params workerParams
 	if err := ctx.Deps().Fill(&params); err != nil {
-		log.L().Panic("Failed to fill dependencies for BaseWorker",
+		logger.Panic("Failed to fill dependencies for BaseWorker",
 			zap.Error(err))
 	}
+	sharedCache := params.SharedCache
+	if sharedCache == nil {
+		// workers not running in an executor process, such as isolated
+		// workers, fall back to a private cache
+		sharedCache = sharedcache.NewCache(0).Client(masterID)
+	}
+	clk := clock.New()
+	if params.Clock != nil {
+		clk = params.Clock
+	}
+	userTenant := tenant.DefaultUserTenantID
+	if ctx.Environ.ProjectInfo.TenantID != "" {
+		userTenant = ctx.Environ.ProjectInfo.TenantID
+	}
 
 	return &DefaultBaseWorker{
 		Impl:                  impl,
@@ -163,9 +295,13 @@ func NewBaseWorker(
 		frameMetaClient:       params.FrameMetaClient,
 		userRawKVClient:       params.UserRawKVClient,
 		resourceBroker:        params.ResourceBroker,
+		sharedCache:           sharedCache,
+		faultInjector:         params.FaultInjector,
+		statusBatcher:         params.StatusBatcher,
 
 		masterID: masterID,
 		id:       workerID,
+		logger:   newTaggedLogger(logger, ctx.Environ.ProjectInfo.ProjectID),
 		workerStatus: &libModel.WorkerStatus{
 			// TODO ProjectID
 			JobID: masterID,
@@ -174,12 +310,18 @@ func NewBaseWorker(
 		},
 		timeoutConfig: config.DefaultTimeoutConfig(),
 
+		env:               ctx.Environ.Env,
+		preMountResources: ctx.Environ.ResourceIDs,
+
 		pool: workerpool.NewDefaultAsyncPool(1),
 
-		errCenter: errctx.NewErrCenter(),
-		clock:     clock.New(),
-		// [TODO] use tenantID if support multi-tenant
-		userMetaKVClient: kvclient.NewPrefixKVClient(params.UserRawKVClient, tenant.DefaultUserTenantID),
+		errCenter:    errctx.NewErrCenter(),
+		selfMessages: newSelfMessageQueue(),
+		tickProbe:    newTickProbe(impl),
+		clock:        clk,
+
+		userMetaKVClient: kvclient.NewPrefixKVClient(
+			params.UserRawKVClient, userTenant, kvclient.WithKeyring(params.UserMetaKeyring)),
 	}
 }
 
@@ -188,6 +330,15 @@ func (w *DefaultBaseWorker) Workload() model.RescUnit {
 	return w.Impl.Workload()
 }
 
+// ResourceUsage implements worker.ResourceUsager, it is nil if the
+// implementation is not a ResourceUsageAwareWorkerImpl.
+func (w *DefaultBaseWorker) ResourceUsage() model.RescVector {
+	if impl, ok := w.Impl.(ResourceUsageAwareWorkerImpl); ok {
+		return impl.ResourceUsage()
+	}
+	return nil
+}
+
 // Init implements BaseWorker.Init
 func (w *DefaultBaseWorker) Init(ctx context.Context) error {
 	ctx = w.errCenter.WithCancelOnFirstError(ctx)
@@ -195,6 +346,7 @@ func (w *DefaultBaseWorker) Init(ctx context.Context) error {
 	if err := w.doPreInit(ctx); err != nil {
 		return errors.Trace(err)
 	}
+	ctx = logutil.NewContext(ctx, w.Logger())
 
 	if err := w.Impl.InitImpl(ctx); err != nil {
 		return errors.Trace(err)
@@ -218,13 +370,10 @@ func (w *DefaultBaseWorker) doPreInit(ctx context.Context) error {
 	go func() {
 		defer w.wg.Done()
 		err := w.pool.Run(poolCtx)
-		log.L().Info("workerpool exited",
-			zap.String("worker-id", w.id),
+		w.Logger().Info("workerpool exited",
 			zap.Error(err))
 	}()
 
-	w.startBackgroundTasks()
-
 	initTime := w.clock.Mono()
 	rctx, ok := runtime.ToRuntimeCtx(ctx)
 	if ok {
@@ -234,6 +383,7 @@ func (w *DefaultBaseWorker) doPreInit(ctx context.Context) error {
 	w.masterClient = newMasterClient(
 		w.masterID,
 		w.id,
+		w.logger.base,
 		w.messageSender,
 		w.frameMetaClient,
 		initTime,
@@ -244,16 +394,28 @@ func (w *DefaultBaseWorker) doPreInit(ctx context.Context) error {
 			}))
 		})
 
-	w.exitController = newWorkerExitController(w.masterClient, w.errCenter, w.clock)
-	w.workerMetaClient = metadata.NewWorkerMetadataClient(w.masterID, w.frameMetaClient)
+	w.exitController = newWorkerExitController(w.masterClient, w.errCenter, w.clock, w.logger.base)
+	w.workerMetaClient = metadata.NewWorkerMetadataClient(w.masterID, w.frameMetaClient).
+		WithStatusBatcher(w.statusBatcher)
 
 	w.statusSender = statusutil.NewWriter(
-		w.frameMetaClient, w.messageSender, w.masterClient, w.id)
+		w.workerMetaClient, w.messageSender, w.masterClient, w.id).WithLogger(w.logger.base)
+	if impl, ok := w.Impl.(StatusCoalescingWorkerImpl); ok {
+		if window := impl.StatusCoalesceWindow(); window > 0 {
+			w.statusSender.WithCoalesceWindow(window, w.clock)
+		}
+	}
+	w.barrierReporter = statusutil.NewBarrierReporter(
+		w.userRawKVClient, w.messageSender, w.masterClient, w.id)
+	w.peerResolver = exchange.NewPeerResolver(w.messageSender, w.masterClient, w.id)
 	w.messageRouter = NewMessageRouter(w.id, w.pool, defaultMessageRouterBufferSize,
 		func(topic p2p.Topic, msg p2p.MessageValue) error {
 			return w.Impl.OnMasterMessage(topic, msg)
 		},
 	)
+	// the background tasks use the master client, so they are started
+	// after it is created.
+	w.startBackgroundTasks()
 
 	if err := w.initMessageHandlers(ctx); err != nil {
 		return errors.Trace(err)
@@ -262,12 +424,27 @@ func (w *DefaultBaseWorker) doPreInit(ctx context.Context) error {
 	if err := w.masterClient.InitMasterInfoFromMeta(ctx); err != nil {
 		return errors.Trace(err)
 	}
+	if w.masterID != metadata.JobManagerUUID {
+		// the worker belongs to the project of its job master
+		w.logger.setProject(w.masterClient.ProjectID())
+	}
+
+	// The resources are opened before InitImpl, so that a worker can't
+	// start without them.
+	for _, resourceID := range w.preMountResources {
+		if _, err := w.resourceBroker.OpenSharedStorage(ctx, w.id, w.masterID, resourceID); err != nil {
+			return errors.Trace(err)
+		}
+	}
 
 	return nil
 }
 
 func (w *DefaultBaseWorker) doPostInit(ctx context.Context) error {
 	// Upsert the worker to ensure we have created the worker info
+	if err := w.faultInjector.CheckMetaWrite(w.id); err != nil {
+		return errors.Trace(err)
+	}
 	if err := w.frameMetaClient.UpsertWorker(ctx, w.workerStatus); err != nil {
 		return errors.Trace(err)
 	}
@@ -290,26 +467,42 @@ func (w *DefaultBaseWorker) doPoll(ctx context.Context) error {
 		return err
 	}
 
+	if err := w.statusSender.Flush(ctx, false); err != nil {
+		return errors.Trace(err)
+	}
+
+	w.selfMessages.deliver(w.clock.Now(), w.messageRouter)
 	return w.messageRouter.Tick(ctx)
 }
 
 // Poll implements BaseWorker.Poll
 func (w *DefaultBaseWorker) Poll(ctx context.Context) error {
+	exitCtx := ctx
 	ctx = w.errCenter.WithCancelOnFirstError(ctx)
+	ctx = logutil.NewContext(ctx, w.Logger())
 
 	if err := w.doPoll(ctx); err != nil {
 		if derror.ErrWorkerHalfExit.NotEqual(err) {
+			// ctx has been canceled by the error, so exitCtx is used.
+			w.persistExitStatus(exitCtx, err)
 			return err
 		}
 		return nil
 	}
 
-	if err := w.Impl.Tick(ctx); err != nil {
+	if err := w.probedTick(ctx, w.Impl.Tick); err != nil {
 		w.errCenter.OnError(err)
 	}
 	return nil
 }
 
+// probedTick runs tick under the liveness probe.
+func (w *DefaultBaseWorker) probedTick(ctx context.Context, tick func(ctx context.Context) error) error {
+	w.tickProbe.begin(w.clock.Now())
+	defer w.tickProbe.end()
+	return tick(ctx)
+}
+
 func (w *DefaultBaseWorker) doClose() {
 	w.cancelMu.Lock()
 	if w.cancelBgTasks != nil {
@@ -324,7 +517,7 @@ func (w *DefaultBaseWorker) doClose() {
 	defer cancel()
 
 	if err := w.messageHandlerManager.Clean(closeCtx); err != nil {
-		log.L().Warn("cleaning message handlers failed",
+		w.Logger().Warn("cleaning message handlers failed",
 			zap.Error(err))
 	}
 
@@ -333,10 +526,15 @@ func (w *DefaultBaseWorker) doClose() {
 
 // Close implements BaseWorker.Close
 func (w *DefaultBaseWorker) Close(ctx context.Context) error {
+	ctx = logutil.NewContext(ctx, w.Logger())
 	if err := w.Impl.CloseImpl(ctx); err != nil {
-		log.L().Error("Failed to close WorkerImpl", zap.Error(err))
+		w.Logger().Error("Failed to close WorkerImpl", zap.Error(err))
 		return errors.Trace(err)
 	}
+	// the coalesced status update is delivered anyway
+	if err := w.statusSender.Flush(ctx, true); err != nil {
+		w.Logger().Warn("Failed to flush coalesced status", zap.Error(err))
+	}
 
 	w.doClose()
 	return nil
@@ -359,12 +557,19 @@ func (w *DefaultBaseWorker) MetaKVClient() metaclient.KVClient {
 // but there is no guarantee that the master has received a notification.
 // Note that if the master cannot handle the notifications fast enough, notifications
 // can be lost.
+// Notifications that are not persisted and lost in master failover are replayed
+// to the new master from a bounded history, as one consolidated notification.
+// If the WorkerImpl is a StatusCoalescingWorkerImpl, a non-terminal status may
+// be coalesced, and is persisted in a later Poll instead.
 func (w *DefaultBaseWorker) UpdateStatus(ctx context.Context, status libModel.WorkerStatus) error {
 	ctx = w.errCenter.WithCancelOnFirstError(ctx)
 
 	w.workerStatus.Code = status.Code
 	w.workerStatus.ErrorMessage = status.ErrorMessage
 	w.workerStatus.ExtBytes = status.ExtBytes
+	if err := w.faultInjector.CheckMetaWrite(w.id); err != nil {
+		return errors.Trace(err)
+	}
 	err := w.statusSender.UpdateStatus(ctx, w.workerStatus)
 	if err != nil {
 		return errors.Trace(err)
@@ -382,26 +587,152 @@ func (w *DefaultBaseWorker) SendMessage(
 	return w.messageSender.SendToNode(ctx, w.masterClient.MasterNode(), topic, message)
 }
 
+// ScheduleSelfMessage implements BaseWorker.ScheduleSelfMessage
+func (w *DefaultBaseWorker) ScheduleSelfMessage(
+	delay time.Duration,
+	topic p2p.Topic,
+	payload p2p.MessageValue,
+) error {
+	return w.selfMessages.schedule(w.clock.Now().Add(delay), topic, payload)
+}
+
+// SendWorkerMessage implements BaseWorker.SendWorkerMessage
+func (w *DefaultBaseWorker) SendWorkerMessage(
+	ctx context.Context,
+	topic p2p.Topic,
+	message interface{},
+) (bool, error) {
+	ctx = w.errCenter.WithCancelOnFirstError(ctx)
+	if err := w.checkMasterCapability(libModel.CapabilityWorkerMessage); err != nil {
+		return false, err
+	}
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return w.messageSender.SendToNode(ctx, w.masterClient.MasterNode(),
+		libModel.WorkerMessageTopic(w.masterClient.MasterID(), topic),
+		&libModel.WorkerMessage{
+			FromWorkerID: w.id,
+			Epoch:        w.masterClient.Epoch(),
+			Payload:      payload,
+		})
+}
+
+// Logger implements BaseWorker.Logger
+func (w *DefaultBaseWorker) Logger() log.Logger {
+	if w.masterClient == nil {
+		return w.logger.get(0)
+	}
+	return w.logger.get(w.masterClient.Epoch())
+}
+
+// SharedCache implements BaseWorker.SharedCache
+func (w *DefaultBaseWorker) SharedCache() sharedcache.Client {
+	return w.sharedCache
+}
+
+// ReachBarrier implements BaseWorker.ReachBarrier
+func (w *DefaultBaseWorker) ReachBarrier(ctx context.Context, barrierID string) error {
+	ctx = w.errCenter.WithCancelOnFirstError(ctx)
+	if err := w.checkMasterCapability(libModel.CapabilityBarrier); err != nil {
+		return err
+	}
+	return w.barrierReporter.Reach(ctx, barrierID)
+}
+
 // OpenStorage implements BaseWorker.OpenStorage
 func (w *DefaultBaseWorker) OpenStorage(ctx context.Context, resourcePath resourcemeta.ResourceID) (broker.Handle, error) {
 	ctx = w.errCenter.WithCancelOnFirstError(ctx)
 	return w.resourceBroker.OpenStorage(ctx, w.id, w.masterID, resourcePath)
 }
 
+// OpenSharedStorage implements BaseWorker.OpenSharedStorage
+func (w *DefaultBaseWorker) OpenSharedStorage(ctx context.Context, resourcePath resourcemeta.ResourceID) (broker.Handle, error) {
+	ctx = w.errCenter.WithCancelOnFirstError(ctx)
+	return w.resourceBroker.OpenSharedStorage(ctx, w.id, w.masterID, resourcePath)
+}
+
 // Exit implements BaseWorker.Exit
 func (w *DefaultBaseWorker) Exit(ctx context.Context, status libModel.WorkerStatus, err error) error {
 	if err != nil {
 		status.Code = libModel.WorkerStatusError
 	}
 
+	if !status.InTerminateState() {
+		// a non-terminal status is not required to be durable, it is
+		// updated as usual.
+		w.workerStatus.Code = status.Code
+		w.workerStatus.ErrorMessage = status.ErrorMessage
+		w.workerStatus.ExtBytes = status.ExtBytes
+		if err1 := w.statusSender.UpdateStatus(ctx, w.workerStatus); err1 != nil {
+			return err1
+		}
+		return derror.ErrWorkerFinish.FastGenByArgs()
+	}
+
+	if err1 := w.PersistTerminalStatus(ctx, status); err1 != nil {
+		return err1
+	}
+
+	return derror.ErrWorkerFinish.FastGenByArgs()
+}
+
+// PersistTerminalStatus implements BaseWorker.PersistTerminalStatus
+func (w *DefaultBaseWorker) PersistTerminalStatus(ctx context.Context, status libModel.WorkerStatus) error {
 	w.workerStatus.Code = status.Code
 	w.workerStatus.ErrorMessage = status.ErrorMessage
 	w.workerStatus.ExtBytes = status.ExtBytes
-	if err1 := w.statusSender.UpdateStatus(ctx, w.workerStatus); err1 != nil {
-		return err1
+	if err := w.faultInjector.CheckMetaWrite(w.id); err != nil {
+		return errors.Trace(err)
 	}
+	return w.statusSender.PersistTerminalStatus(ctx, w.workerStatus)
+}
 
-	return derror.ErrWorkerFinish.FastGenByArgs()
+// persistExitStatus persists an error status if the worker fails with err
+// without a terminal status, so that the master doesn't see the worker
+// offline with no final status. It is best effort since the worker exits
+// anyway.
+func (w *DefaultBaseWorker) persistExitStatus(ctx context.Context, err error) {
+	if w.workerStatus.InTerminateState() || !isWorkerFailure(err) {
+		return
+	}
+	status := libModel.WorkerStatus{
+		Code:         libModel.WorkerStatusError,
+		ErrorMessage: err.Error(),
+		ExtBytes:     w.workerStatus.ExtBytes,
+	}
+	if err1 := w.PersistTerminalStatus(ctx, status); err1 != nil {
+		w.Logger().Warn("Failed to persist exit status", zap.Error(err1))
+	}
+}
+
+// isWorkerFailure returns whether a worker exiting with err has failed. A
+// worker exits without failing if it has called Exit, whose status has been
+// written already, if it commits suicide after losing its master, which
+// takes over the status, or if it is canceled on shutdown.
+func isWorkerFailure(err error) bool {
+	if derror.ErrWorkerFinish.Equal(err) || derror.ErrWorkerSuicide.Equal(err) {
+		return false
+	}
+	return errors.Cause(err) != context.Canceled
+}
+
+// Getenv implements BaseWorker.Getenv
+func (w *DefaultBaseWorker) Getenv(key string) string {
+	if value, ok := w.env[key]; ok {
+		return value
+	}
+	return os.Getenv(key)
+}
+
+// FeatureEnabled implements BaseWorker.FeatureEnabled
+func (w *DefaultBaseWorker) FeatureEnabled(feature compat.Feature) bool {
+	if w.masterClient == nil {
+		// not initialized yet
+		return false
+	}
+	return compat.FeatureEnabled(feature, w.masterClient.ProtocolVersion())
 }
 
 func (w *DefaultBaseWorker) startBackgroundTasks() {
@@ -411,10 +742,16 @@ func (w *DefaultBaseWorker) startBackgroundTasks() {
 	w.cancelBgTasks = cancel
 	w.cancelMu.Unlock()
 
+	// The tickers are created before the goroutines start, so that no tick
+	// is missed by a mock clock.
+	interval := w.masterClient.HeartbeatInterval()
+	heartbeatTicker := w.clock.Ticker(interval)
+	watchDogTicker := w.clock.Ticker(interval)
+
 	w.wg.Add(1)
 	go func() {
 		defer w.wg.Done()
-		if err := w.runHeartbeatWorker(ctx); err != nil {
+		if err := w.runHeartbeatWorker(ctx, heartbeatTicker, interval); err != nil {
 			w.onError(err)
 		}
 	}()
@@ -422,14 +759,15 @@ func (w *DefaultBaseWorker) startBackgroundTasks() {
 	w.wg.Add(1)
 	go func() {
 		defer w.wg.Done()
-		if err := w.runWatchDog(ctx); err != nil {
+		if err := w.runWatchDog(ctx, watchDogTicker, interval); err != nil {
 			w.onError(err)
 		}
 	}()
 }
 
-func (w *DefaultBaseWorker) runHeartbeatWorker(ctx context.Context) error {
-	ticker := w.clock.Ticker(w.timeoutConfig.WorkerHeartbeatInterval)
+func (w *DefaultBaseWorker) runHeartbeatWorker(
+	ctx context.Context, ticker *bclock.Ticker, interval time.Duration,
+) error {
 	defer ticker.Stop()
 
 	for {
@@ -437,6 +775,11 @@ func (w *DefaultBaseWorker) runHeartbeatWorker(ctx context.Context) error {
 		case <-ctx.Done():
 			return errors.Trace(ctx.Err())
 		case <-ticker.C:
+			// the master may have changed the heartbeat interval
+			if newInterval := w.masterClient.HeartbeatInterval(); newInterval != interval {
+				interval = newInterval
+				ticker.Reset(interval)
+			}
 			isFinished := false
 			if w.exitController.IsExiting() {
 				// If we are in the state workerHalfExit,
@@ -444,21 +787,44 @@ func (w *DefaultBaseWorker) runHeartbeatWorker(ctx context.Context) error {
 				// marks us as exited.
 				isFinished = true
 			}
-			if err := w.masterClient.SendHeartBeat(ctx, w.clock, isFinished); err != nil {
-				return errors.Trace(err)
+			heartbeatMsg := &libModel.HeartbeatPingMessage{IsFinished: isFinished}
+			heartbeatMsg.StatusSeq, heartbeatMsg.PersistedStatusSeq = w.statusSender.Seqs()
+			if w.creatingWorkers != nil {
+				heartbeatMsg.CreatingWorkers = w.creatingWorkers()
+			}
+			if elapsed, stuck := w.tickProbe.stuck(w.clock.Now()); stuck {
+				w.Logger().Warn("worker is stuck in Tick", zap.Duration("elapsed", elapsed))
+				heartbeatMsg.Stuck = true
+			}
+			if err := w.masterClient.SendHeartBeat(ctx, w.clock, heartbeatMsg); err != nil {
+				// The master may be failing over, reattach to the new master
+				// instance if it is found in metastore, without restarting
+				// the worker. The watchdog makes the worker exit if no master
+				// acknowledges the heartbeats in time.
+				w.Logger().Warn("failed to send heartbeat, refreshing master info",
+					zap.String("master-id", w.masterID), zap.Error(err))
+				if err := w.masterClient.RefreshMasterInfo(ctx); err != nil {
+					return errors.Trace(err)
+				}
 			}
 		}
 	}
 }
 
-func (w *DefaultBaseWorker) runWatchDog(ctx context.Context) error {
-	ticker := w.clock.Ticker(w.timeoutConfig.WorkerHeartbeatInterval)
+func (w *DefaultBaseWorker) runWatchDog(
+	ctx context.Context, ticker *bclock.Ticker, interval time.Duration,
+) error {
+	defer ticker.Stop()
 	for {
 		select {
 		case <-ctx.Done():
 			return errors.Trace(ctx.Err())
 		case <-ticker.C:
 		}
+		if newInterval := w.masterClient.HeartbeatInterval(); newInterval != interval {
+			interval = newInterval
+			ticker.Reset(interval)
+		}
 
 		isNormal, err := w.masterClient.CheckMasterTimeout(ctx, w.clock)
 		if err != nil {
@@ -476,9 +842,8 @@ func (w *DefaultBaseWorker) initMessageHandlers(ctx context.Context) (retErr err
 	defer func() {
 		if retErr != nil {
 			if err := w.messageHandlerManager.Clean(context.Background()); err != nil {
-				log.L().Warn("Failed to clean up message handlers",
-					zap.String("master-id", w.masterID),
-					zap.String("worker-id", w.id))
+				w.Logger().Warn("Failed to clean up message handlers",
+					zap.String("master-id", w.masterID))
 			}
 		}
 	}()
@@ -489,7 +854,7 @@ func (w *DefaultBaseWorker) initMessageHandlers(ctx context.Context) (retErr err
 		&libModel.HeartbeatPongMessage{},
 		func(sender p2p.NodeID, value p2p.MessageValue) error {
 			msg := value.(*libModel.HeartbeatPongMessage)
-			log.L().Info("heartbeat pong received",
+			w.Logger().Info("heartbeat pong received",
 				zap.String("master-id", w.masterID),
 				zap.Any("msg", msg))
 			w.masterClient.HandleHeartbeat(sender, msg)
@@ -499,10 +864,31 @@ func (w *DefaultBaseWorker) initMessageHandlers(ctx context.Context) (retErr err
 		return errors.Trace(err)
 	}
 	if !ok {
-		log.L().Panic("duplicate handler",
+		w.Logger().Panic("duplicate handler",
 			zap.String("topic", topic))
 	}
 
+	topic = statusutil.WorkerStatusReplayRequestTopic(w.masterID, w.id)
+	ok, err = w.messageHandlerManager.RegisterHandler(
+		ctx,
+		topic,
+		&statusutil.WorkerStatusReplayRequest{},
+		func(sender p2p.NodeID, value p2p.MessageValue) error {
+			msg := value.(*statusutil.WorkerStatusReplayRequest)
+			if msg.MasterEpoch < w.masterClient.Epoch() {
+				w.Logger().Info("stale status replay request dropped",
+					zap.Int64("epoch", msg.MasterEpoch))
+				return nil
+			}
+			return w.statusSender.Replay(ctx, sender, msg.MasterEpoch)
+		})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !ok {
+		w.Logger().Panic("duplicate handler", zap.String("topic", topic))
+	}
+
 	topic = libModel.WorkerStatusChangeRequestTopic(w.masterID, w.id)
 	ok, err = w.messageHandlerManager.RegisterHandler(
 		ctx,
@@ -520,10 +906,13 @@ func (w *DefaultBaseWorker) initMessageHandlers(ctx context.Context) (retErr err
 		return errors.Trace(err)
 	}
 	if !ok {
-		log.L().Panic("duplicate handler", zap.String("topic", topic))
+		w.Logger().Panic("duplicate handler", zap.String("topic", topic))
 	}
 
-	return nil
+	if err := w.registerPeerLookupHandler(ctx); err != nil {
+		return err
+	}
+	return w.registerLogLevelUpdateHandler(ctx)
 }
 
 func (w *DefaultBaseWorker) onError(err error) {
@@ -535,18 +924,33 @@ type masterClient struct {
 	masterID    libModel.MasterID
 	masterNode  p2p.NodeID
 	masterEpoch libModel.Epoch
+	// projectID is the project of the master, it is not refreshed since it
+	// never changes.
+	projectID tenant.ProjectID
 
 	workerID libModel.WorkerID
+	logger   log.Logger
 
 	messageSender           p2p.MessageSender
 	frameMetaClient         pkgOrm.Client
 	lastMasterAckedPingTime clock.MonotonicTime
+	// ackedEpoch is the epoch of the master instance that acknowledged the
+	// last heartbeat, the worker is reattaching to a new master instance if
+	// it is less than masterEpoch.
+	ackedEpoch libModel.Epoch
 
 	// masterSideClosed records whether the master
 	// has marked us as closed
 	masterSideClosed atomic.Bool
 
 	timeoutConfig config.TimeoutConfig
+	// protocolVersion is negotiated by the master, masters of an old
+	// version don't negotiate it.
+	protocolVersion compat.ProtocolVersion
+	// capabilities are supported by both the worker and the master,
+	// capsNegotiated is false before the first heartbeat pong.
+	capabilities   libModel.CapabilitySet
+	capsNegotiated bool
 
 	onMasterFailOver func() error
 }
@@ -554,6 +958,7 @@ type masterClient struct {
 func newMasterClient(
 	masterID libModel.MasterID,
 	workerID libModel.WorkerID,
+	logger log.Logger,
 	messageRouter p2p.MessageSender,
 	metaCli pkgOrm.Client,
 	initTime clock.MonotonicTime,
@@ -562,10 +967,12 @@ func newMasterClient(
 	return &masterClient{
 		masterID:                masterID,
 		workerID:                workerID,
+		logger:                  logger,
 		messageSender:           messageRouter,
 		frameMetaClient:         metaCli,
 		lastMasterAckedPingTime: initTime,
 		timeoutConfig:           config.DefaultTimeoutConfig(),
+		protocolVersion:         compat.ProtocolVersionBase,
 		onMasterFailOver:        onMasterFailOver,
 	}
 }
@@ -582,9 +989,18 @@ func (m *masterClient) InitMasterInfoFromMeta(ctx context.Context) error {
 
 	m.masterNode = masterMeta.NodeID
 	m.masterEpoch = masterMeta.Epoch
+	m.ackedEpoch = masterMeta.Epoch
+	m.projectID = masterMeta.ProjectID
 	return nil
 }
 
+// ProjectID returns the project of the master
+func (m *masterClient) ProjectID() tenant.ProjectID {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.projectID
+}
+
 func (m *masterClient) MasterNodeID() p2p.NodeID {
 	return m.masterID
 }
@@ -599,7 +1015,7 @@ func (m *masterClient) RefreshMasterInfo(ctx context.Context) error {
 	m.mu.Lock()
 	m.masterNode = masterMeta.NodeID
 	if m.masterEpoch < masterMeta.Epoch {
-		log.L().Info("refresh master info", zap.String("masterID", m.masterID),
+		m.logger.Info("refresh master info", zap.String("masterID", m.masterID),
 			zap.Int64("oldEpoch", m.masterEpoch), zap.Int64("newEpoch", masterMeta.Epoch),
 		)
 		m.masterEpoch = masterMeta.Epoch
@@ -636,7 +1052,7 @@ func (m *masterClient) HandleHeartbeat(sender p2p.NodeID, msg *libModel.Heartbea
 	defer m.mu.Unlock()
 
 	if msg.Epoch < m.masterEpoch {
-		log.L().Info("epoch does not match, ignore stale heartbeat",
+		m.logger.Info("epoch does not match, ignore stale heartbeat",
 			zap.Any("msg", msg),
 			zap.Int64("master-epoch", m.masterEpoch))
 		return
@@ -653,22 +1069,87 @@ func (m *masterClient) HandleHeartbeat(sender p2p.NodeID, msg *libModel.Heartbea
 	if msg.IsFinished {
 		m.masterSideClosed.Store(true)
 	}
+	if m.ackedEpoch < m.masterEpoch {
+		m.logger.Info("reattached to new master instance",
+			zap.String("master-id", m.masterID),
+			zap.Int64("epoch", m.masterEpoch))
+	}
 	m.lastMasterAckedPingTime = msg.SendTime
+	m.ackedEpoch = m.masterEpoch
+
+	// follow the timeouts negotiated by the master
+	if msg.HeartbeatInterval > 0 && msg.HeartbeatInterval != m.timeoutConfig.WorkerHeartbeatInterval {
+		m.logger.Info("heartbeat interval updated by master",
+			zap.Duration("old", m.timeoutConfig.WorkerHeartbeatInterval),
+			zap.Duration("new", msg.HeartbeatInterval))
+		m.timeoutConfig.WorkerHeartbeatInterval = msg.HeartbeatInterval
+	}
+	if msg.WorkerTimeout > 0 {
+		m.timeoutConfig.WorkerTimeoutDuration = msg.WorkerTimeout
+	}
+	if version := msg.ProtocolVersion.Normalize(); version != m.protocolVersion {
+		m.logger.Info("protocol version updated by master",
+			zap.Int32("old", int32(m.protocolVersion)),
+			zap.Int32("new", int32(version)))
+		m.protocolVersion = version
+	}
+	caps := libModel.LegacyCapabilities()
+	if msg.Capabilities != nil {
+		caps = libModel.FrameworkCapabilities().Intersect(msg.Capabilities)
+	}
+	if !m.capsNegotiated || !equalCapabilities(caps, m.capabilities) {
+		m.logger.Info("capabilities negotiated with master",
+			zap.Any("capabilities", caps))
+		m.capabilities = caps
+		m.capsNegotiated = true
+	}
 }
 
+// Capabilities returns the capabilities negotiated with the master.
+func (m *masterClient) Capabilities() (libModel.CapabilitySet, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.capabilities, m.capsNegotiated
+}
+
+// ProtocolVersion returns the protocol version negotiated with the master.
+func (m *masterClient) ProtocolVersion() compat.ProtocolVersion {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.protocolVersion
+}
+
+// HeartbeatInterval returns the heartbeat interval negotiated with the master.
+func (m *masterClient) HeartbeatInterval() time.Duration {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.timeoutConfig.WorkerHeartbeatInterval
+}
+
+// CheckMasterTimeout returns false if no master has acknowledged the
+// heartbeats in time. Before that, it refreshes the master info so that the
+// worker reattaches to the new master instance after failover.
 func (m *masterClient) CheckMasterTimeout(ctx context.Context, clock clock.Clock) (ok bool, err error) {
 	m.mu.RLock()
 	lastMasterAckedPingTime := m.lastMasterAckedPingTime
+	timeoutConfig := m.timeoutConfig
+	reattaching := m.ackedEpoch < m.masterEpoch
 	m.mu.RUnlock()
 
 	sinceLastAcked := clock.Mono().Sub(lastMasterAckedPingTime)
-	if sinceLastAcked <= 2*m.timeoutConfig.WorkerHeartbeatInterval {
+	if sinceLastAcked <= 2*timeoutConfig.WorkerHeartbeatInterval {
 		return true, nil
 	}
 
-	if sinceLastAcked > 2*m.timeoutConfig.WorkerHeartbeatInterval &&
-		sinceLastAcked < m.timeoutConfig.WorkerTimeoutDuration {
-
+	timeout := timeoutConfig.WorkerTimeoutDuration
+	if reattaching {
+		// The new master instance waits for the heartbeats for the worker
+		// timeout plus the graceful duration after it starts, which is
+		// later than the last ack of the old instance. Half of the graceful
+		// duration is kept as the margin of the master.
+		timeout += timeoutConfig.WorkerTimeoutGracefulDuration / 2
+	}
+	if sinceLastAcked < timeout {
 		if err := m.RefreshMasterInfo(ctx); err != nil {
 			return false, errors.Trace(err)
 		}
@@ -678,7 +1159,13 @@ func (m *masterClient) CheckMasterTimeout(ctx context.Context, clock clock.Clock
 	return false, nil
 }
 
-func (m *masterClient) SendHeartBeat(ctx context.Context, clock clock.Clock, isFinished bool) error {
+// SendHeartBeat fills the framework fields of heartbeatMsg and sends it to
+// the master, the other fields are filled by the caller.
+func (m *masterClient) SendHeartBeat(
+	ctx context.Context,
+	clock clock.Clock,
+	heartbeatMsg *libModel.HeartbeatPingMessage,
+) error {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -686,23 +1173,21 @@ func (m *masterClient) SendHeartBeat(ctx context.Context, clock clock.Clock, isF
 	// The timestamp will be returned in a PONG for time-out check, so we need
 	// the timestamp to be a local monotonic timestamp, which is not exposed by the
 	// standard library `time`.
-	sendTime := clock.Mono()
-	heartbeatMsg := &libModel.HeartbeatPingMessage{
-		SendTime:     sendTime,
-		FromWorkerID: m.workerID,
-		Epoch:        m.masterEpoch,
-		IsFinished:   isFinished,
-	}
+	heartbeatMsg.SendTime = clock.Mono()
+	heartbeatMsg.FromWorkerID = m.workerID
+	heartbeatMsg.Epoch = m.masterEpoch
+	heartbeatMsg.ProtocolVersion = compat.CurrentProtocolVersion
+	heartbeatMsg.Capabilities = libModel.FrameworkCapabilities()
 
-	log.L().Debug("sending heartbeat", zap.String("worker", m.workerID))
+	m.logger.Debug("sending heartbeat")
 	ok, err := m.messageSender.SendToNode(ctx, m.masterNode, libModel.HeartbeatPingTopic(m.masterID), heartbeatMsg)
 	if err != nil {
 		return errors.Trace(err)
 	}
-	log.L().Info("sending heartbeat success", zap.String("worker", m.workerID),
+	m.logger.Info("sending heartbeat success",
 		zap.String("master-id", m.masterID))
 	if !ok {
-		log.L().Warn("sending heartbeat ping encountered ErrPeerMessageSendTryAgain")
+		m.logger.Warn("sending heartbeat ping encountered ErrPeerMessageSendTryAgain")
 	}
 	return nil
 }
@@ -730,6 +1215,7 @@ type workerExitController struct {
 	halfExitTime  atomic.Time
 	errCenter     *errctx.ErrCenter
 	masterClient  *masterClient
+	logger        log.Logger
 
 	// clock is to facilitate unit testing.
 	clock clock.Clock
@@ -739,11 +1225,13 @@ func newWorkerExitController(
 	masterClient *masterClient,
 	errCenter *errctx.ErrCenter,
 	clock clock.Clock,
+	logger log.Logger,
 ) *workerExitController {
 	return &workerExitController{
 		workerExitFsm: *atomic.NewInt32(workerNormal),
 		errCenter:     errCenter,
 		masterClient:  masterClient,
+		logger:        logger,
 		clock:         clock,
 	}
 }
@@ -769,15 +1257,15 @@ func (c *workerExitController) PollExit() error {
 		}
 		sinceStartExiting := c.clock.Since(c.halfExitTime.Load())
 		if sinceStartExiting > workerExitWaitForMasterTimeout {
-			// TODO log worker ID and master ID.
-			log.L().Warn("Exiting worker cannot get acknowledgement from master")
+			c.logger.Warn("Exiting worker cannot get acknowledgement from master",
+				zap.String("master-id", c.masterClient.MasterID()))
 			return err
 		}
 		return derror.ErrWorkerHalfExit.FastGenByArgs()
 	case workerExited:
 		return err
 	default:
-		log.L().Panic("unreachable")
+		c.logger.Panic("unreachable")
 	}
 	return nil
 }
@@ -0,0 +1,154 @@
+package lib
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pingcap/errors"
+	"go.uber.org/zap"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/p2p"
+)
+
+const workerMessageQueueSize = 1024
+
+// WorkerMessageValidator can be implemented by a typed worker message, the
+// message is dropped if Validate returns an error.
+type WorkerMessageValidator interface {
+	Validate() error
+}
+
+// WorkerMessageHandlerRegistrar is implemented by BaseMaster and BaseJobMaster.
+// Business logic should use RegisterWorkerMessageHandler instead of calling
+// RegisterRawWorkerMessageHandler directly.
+type WorkerMessageHandlerRegistrar interface {
+	// RegisterRawWorkerMessageHandler registers a handler for messages sent by
+	// BaseWorker.SendWorkerMessage on the given topic. Payloads are decoded by
+	// decode, messages with a stale epoch or from unknown workers are dropped,
+	// and handler is called in Poll.
+	RegisterRawWorkerMessageHandler(
+		ctx context.Context,
+		topic p2p.Topic,
+		decode func(payload []byte) (interface{}, error),
+		handler func(worker WorkerHandle, message interface{}) error,
+	) error
+}
+
+// RegisterWorkerMessageHandler registers a typed handler for the messages
+// sent by workers on the given topic, the payload is decoded into T and
+// validated if T implements WorkerMessageValidator.
+func RegisterWorkerMessageHandler[T any](
+	ctx context.Context,
+	registrar WorkerMessageHandlerRegistrar,
+	topic p2p.Topic,
+	handler func(worker WorkerHandle, message T) error,
+) error {
+	decode := func(payload []byte) (interface{}, error) {
+		var msg T
+		if err := json.Unmarshal(payload, &msg); err != nil {
+			return nil, derror.ErrInvalidWorkerMessage.Wrap(err).GenWithStackByArgs(topic)
+		}
+		var validator WorkerMessageValidator
+		if v, ok := interface{}(msg).(WorkerMessageValidator); ok {
+			validator = v
+		} else if v, ok := interface{}(&msg).(WorkerMessageValidator); ok {
+			validator = v
+		}
+		if validator != nil {
+			if err := validator.Validate(); err != nil {
+				return nil, derror.ErrInvalidWorkerMessage.Wrap(err).GenWithStackByArgs(topic)
+			}
+		}
+		return msg, nil
+	}
+	return registrar.RegisterRawWorkerMessageHandler(ctx, topic, decode,
+		func(worker WorkerHandle, message interface{}) error {
+			return handler(worker, message.(T))
+		})
+}
+
+type workerMessage struct {
+	topic    p2p.Topic
+	workerID libModel.WorkerID
+	message  interface{}
+	handler  func(worker WorkerHandle, message interface{}) error
+}
+
+// RegisterRawWorkerMessageHandler implements WorkerMessageHandlerRegistrar.RegisterRawWorkerMessageHandler
+func (m *DefaultBaseMaster) RegisterRawWorkerMessageHandler(
+	ctx context.Context,
+	topic p2p.Topic,
+	decode func(payload []byte) (interface{}, error),
+	handler func(worker WorkerHandle, message interface{}) error,
+) error {
+	p2pTopic := libModel.WorkerMessageTopic(m.id, topic)
+	ok, err := m.messageHandlerManager.RegisterHandler(
+		ctx,
+		p2pTopic,
+		&libModel.WorkerMessage{},
+		func(sender p2p.NodeID, value p2p.MessageValue) error {
+			msg := value.(*libModel.WorkerMessage)
+			if epoch := m.currentEpoch.Load(); msg.Epoch != epoch {
+				m.Logger().Info("drop worker message with stale epoch",
+					zap.String("topic", topic),
+					zap.String("worker-id", msg.FromWorkerID),
+					zap.Int64("msg-epoch", msg.Epoch))
+				return nil
+			}
+			decoded, err := decode(msg.Payload)
+			if err != nil {
+				m.Logger().Warn("drop invalid worker message",
+					zap.String("worker-id", msg.FromWorkerID),
+					zap.Error(err))
+				return nil
+			}
+			select {
+			case m.workerMessageQueue <- &workerMessage{
+				topic:    topic,
+				workerID: msg.FromWorkerID,
+				message:  decoded,
+				handler:  handler,
+			}:
+			default:
+				m.Logger().Warn("drop worker message because the queue is full",
+					zap.String("topic", topic),
+					zap.String("worker-id", msg.FromWorkerID))
+			}
+			return nil
+		})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !ok {
+		return derror.ErrWorkerMessageTopicDuplicated.GenWithStackByArgs(topic)
+	}
+	return nil
+}
+
+// handleWorkerMessages delivers the pending worker messages to their handlers.
+func (m *DefaultBaseMaster) handleWorkerMessages(ctx context.Context) error {
+	for {
+		var msg *workerMessage
+		select {
+		case msg = <-m.workerMessageQueue:
+		default:
+			return nil
+		}
+
+		handle, ok := m.workerManager.GetWorkers()[msg.workerID]
+		if !ok || handle.GetTombstone() != nil {
+			m.Logger().Info("drop message from a worker that is not online",
+				zap.String("topic", msg.topic),
+				zap.String("worker-id", msg.workerID))
+			continue
+		}
+		err := m.callbackHandler.handle("worker-message", msg.workerID, func() error {
+			return msg.handler(handle, msg.message)
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package lib
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/pkg/uuid"
+)
+
+type progressMessage struct {
+	Progress int `json:"progress"`
+}
+
+func (m *progressMessage) Validate() error {
+	if m.Progress < 0 {
+		return errors.New("negative progress")
+	}
+	return nil
+}
+
+func TestRegisterWorkerMessageHandler(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	master := NewMockMasterImpl("", masterName)
+	master.timeoutConfig.WorkerTimeoutDuration = time.Second * 1000
+	master.timeoutConfig.MasterHeartbeatCheckLoopInterval = time.Millisecond * 10
+	master.uuidGen = uuid.NewMock()
+	prepareMeta(ctx, t, master.GetFrameMetaClient())
+
+	master.On("InitImpl", mock.Anything).Return(nil)
+	err := master.Init(ctx)
+	require.NoError(t, err)
+
+	MockBaseMasterCreateWorker(
+		t,
+		master.DefaultBaseMaster,
+		workerTypePlaceholder,
+		&dummyConfig{param: 1},
+		100,
+		masterName,
+		workerID1,
+		executorNodeID1,
+		nil)
+	_, err = master.CreateWorker(workerTypePlaceholder, &dummyConfig{param: 1}, 100)
+	require.NoError(t, err)
+
+	master.On("OnWorkerDispatched", mock.AnythingOfType("*master.runningHandleImpl"), nil).Return(nil)
+	master.On("OnWorkerOnline", mock.AnythingOfType("*master.runningHandleImpl")).Return(nil)
+	master.On("Tick", mock.Anything).Return(nil)
+	require.Eventually(t, func() bool {
+		MockBaseMasterWorkerHeartbeat(t, master.DefaultBaseMaster, masterName, workerID1, executorNodeID1)
+		require.NoError(t, master.Poll(ctx))
+		return master.onlineWorkerCount.Load() == 1
+	}, time.Second*10, time.Millisecond*10)
+
+	var received []*progressMessage
+	err = RegisterWorkerMessageHandler(ctx, master.DefaultBaseMaster, "progress",
+		func(worker WorkerHandle, msg *progressMessage) error {
+			require.Equal(t, workerID1, worker.ID())
+			received = append(received, msg)
+			return nil
+		})
+	require.NoError(t, err)
+	err = RegisterWorkerMessageHandler(ctx, master.DefaultBaseMaster, "progress",
+		func(worker WorkerHandle, msg *progressMessage) error {
+			return nil
+		})
+	require.Error(t, err)
+
+	sendMessage := func(workerID libModel.WorkerID, epoch libModel.Epoch, progress int) {
+		payload, err := json.Marshal(&progressMessage{Progress: progress})
+		require.NoError(t, err)
+		err = master.messageHandlerManager.InvokeHandler(
+			t,
+			libModel.WorkerMessageTopic(masterName, "progress"),
+			executorNodeID1,
+			&libModel.WorkerMessage{
+				FromWorkerID: workerID,
+				Epoch:        epoch,
+				Payload:      payload,
+			})
+		require.NoError(t, err)
+	}
+	epoch := master.currentEpoch.Load()
+	sendMessage(workerID1, epoch, 1)
+	// stale epoch
+	sendMessage(workerID1, epoch-1, 2)
+	// invalid message
+	sendMessage(workerID1, epoch, -1)
+	// unknown worker
+	sendMessage("worker-2", epoch, 3)
+
+	require.NoError(t, master.Poll(ctx))
+	require.Len(t, received, 1)
+	require.Equal(t, 1, received[0].Progress)
+}
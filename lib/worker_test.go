@@ -14,7 +14,12 @@ import (
 	libModel "github.com/hanfei1991/microcosm/lib/model"
 	"github.com/hanfei1991/microcosm/lib/statusutil"
 	"github.com/hanfei1991/microcosm/pkg/clock"
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+	resourcemeta "github.com/hanfei1991/microcosm/pkg/externalresource/resourcemeta/model"
+	"github.com/hanfei1991/microcosm/pkg/faultinject"
 	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+	"github.com/hanfei1991/microcosm/pkg/p2p"
+	"github.com/hanfei1991/microcosm/pkg/simulation"
 )
 
 var (
@@ -105,6 +110,88 @@ func TestWorkerInitAndClose(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestWorkerDispatchEnv(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	worker := newMockWorkerImpl(workerID1, masterName)
+	worker.clock = clock.NewMock()
+	worker.clock.(*clock.Mock).Set(time.Now())
+	worker.env = map[string]string{"WORKER_ENV": "dispatched"}
+	worker.preMountResources = []resourcemeta.ResourceID{"invalid-resource"}
+	putMasterMeta(ctx, t, worker.metaClient, &libModel.MasterMetaKVData{
+		ID:         masterName,
+		NodeID:     masterNodeName,
+		Epoch:      1,
+		StatusCode: libModel.MasterStatusInit,
+	})
+
+	require.Equal(t, "dispatched", worker.Getenv("WORKER_ENV"))
+	t.Setenv("WORKER_PROCESS_ENV", "process")
+	require.Equal(t, "process", worker.Getenv("WORKER_PROCESS_ENV"))
+
+	// the worker fails to start without the pre-mounted resources
+	worker.On("InitImpl", mock.Anything).Return(nil)
+	err := worker.Init(ctx)
+	require.Error(t, err)
+	worker.AssertNotCalled(t, "InitImpl", mock.Anything)
+
+	worker.On("CloseImpl", mock.Anything).Return(nil)
+	err = worker.Close(ctx)
+	require.NoError(t, err)
+}
+
+func TestWorkerSelfMessage(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	worker := newMockWorkerImpl(workerID1, masterName)
+	worker.clock = clock.NewMock()
+	worker.clock.(*clock.Mock).Set(time.Now())
+	putMasterMeta(ctx, t, worker.metaClient, &libModel.MasterMetaKVData{
+		ID:         masterName,
+		NodeID:     masterNodeName,
+		Epoch:      1,
+		StatusCode: libModel.MasterStatusInit,
+	})
+
+	worker.On("InitImpl", mock.Anything).Return(nil)
+	worker.On("Status").Return(libModel.WorkerStatus{
+		Code: libModel.WorkerStatusNormal,
+	}, nil)
+	worker.On("Tick", mock.Anything).Return(nil)
+	err := worker.Init(ctx)
+	require.NoError(t, err)
+
+	received := make(chan p2p.MessageValue, 1)
+	worker.On("OnMasterMessage", "retry-topic", "retry").
+		Return(nil).
+		Run(func(args mock.Arguments) { received <- args.Get(1) })
+	err = worker.ScheduleSelfMessage(time.Second, "retry-topic", "retry")
+	require.NoError(t, err)
+
+	// the message is not delivered before the delay passes
+	for i := 0; i < 10; i++ {
+		require.NoError(t, worker.Poll(ctx))
+	}
+	worker.AssertNotCalled(t, "OnMasterMessage", "retry-topic", "retry")
+
+	worker.clock.(*clock.Mock).Add(time.Second)
+	require.NoError(t, worker.Poll(ctx))
+	select {
+	case msg := <-received:
+		require.Equal(t, "retry", msg)
+	case <-time.After(3 * time.Second):
+		require.FailNow(t, "self message is not delivered")
+	}
+
+	worker.On("CloseImpl").Return(nil)
+	err = worker.Close(ctx)
+	require.NoError(t, err)
+}
+
 const (
 	heartbeatPingPongTestRepeatTimes = 100
 )
@@ -169,6 +256,52 @@ func TestWorkerHeartbeatPingPong(t *testing.T) {
 	}
 }
 
+func TestWorkerFollowsNegotiatedTimeouts(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	worker := newMockWorkerImpl(workerID1, masterName)
+	worker.clock = clock.NewMock()
+	worker.clock.(*clock.Mock).Set(time.Now())
+	putMasterMeta(ctx, t, worker.metaClient, &libModel.MasterMetaKVData{
+		ID:         masterName,
+		NodeID:     masterNodeName,
+		Epoch:      1,
+		StatusCode: libModel.MasterStatusInit,
+	})
+
+	worker.On("InitImpl", mock.Anything).Return(nil)
+	worker.On("Status").Return(libModel.WorkerStatus{
+		Code: libModel.WorkerStatusNormal,
+	}, nil)
+
+	err := worker.Init(ctx)
+	require.NoError(t, err)
+	require.Equal(t, config.DefaultTimeoutConfig().WorkerHeartbeatInterval,
+		worker.masterClient.HeartbeatInterval())
+
+	pongMsg := &libModel.HeartbeatPongMessage{
+		SendTime:          worker.clock.Mono(),
+		ReplyTime:         time.Now(),
+		ToWorkerID:        workerID1,
+		Epoch:             1,
+		HeartbeatInterval: 10 * time.Second,
+		WorkerTimeout:     60 * time.Second,
+	}
+	err = worker.messageHandlerManager.InvokeHandler(
+		t, libModel.HeartbeatPongTopic(masterName, workerID1), masterNodeName, pongMsg)
+	require.NoError(t, err)
+	require.Equal(t, 10*time.Second, worker.masterClient.HeartbeatInterval())
+
+	// the master is not considered timed out within the relaxed timeout
+	worker.clock.(*clock.Mock).Add(50 * time.Second)
+	ok, err := worker.masterClient.CheckMasterTimeout(ctx, worker.clock)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
 func TestWorkerMasterFailover(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -229,6 +362,166 @@ func TestWorkerMasterFailover(t *testing.T) {
 	}, time.Second*3, time.Millisecond*10)
 }
 
+func TestWorkerReattachAfterHeartbeatFailure(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	worker := newMockWorkerImpl(workerID1, masterName)
+	worker.clock = clock.NewMock()
+	worker.clock.(*clock.Mock).Set(time.Now())
+	putMasterMeta(ctx, t, worker.metaClient, &libModel.MasterMetaKVData{
+		ID:         masterName,
+		NodeID:     masterNodeName,
+		Epoch:      1,
+		StatusCode: libModel.MasterStatusInit,
+	})
+
+	worker.On("InitImpl", mock.Anything).Return(nil)
+	worker.On("Status").Return(libModel.WorkerStatus{
+		Code: libModel.WorkerStatusNormal,
+	}, nil)
+	worker.On("Tick", mock.Anything).Return(nil)
+	err := worker.Init(ctx)
+	require.NoError(t, err)
+
+	// the master fails over to another node
+	putMasterMeta(ctx, t, worker.metaClient, &libModel.MasterMetaKVData{
+		ID:         masterName,
+		NodeID:     executorNodeID3,
+		Epoch:      2,
+		StatusCode: libModel.MasterStatusInit,
+	})
+	worker.On("OnMasterFailover", mock.Anything).Return(nil)
+
+	// the worker reattaches to the new master after failing to send a heartbeat
+	worker.messageSender.InjectError(errors.New("fake send error"))
+	worker.clock.(*clock.Mock).Add(config.DefaultTimeoutConfig().WorkerHeartbeatInterval)
+	require.Eventually(t, func() bool {
+		return worker.failoverCount.Load() == 1
+	}, time.Second*3, time.Millisecond*10)
+	require.Equal(t, executorNodeID3, worker.masterClient.MasterNode())
+	require.Equal(t, libModel.Epoch(2), worker.masterClient.Epoch())
+	require.NoError(t, worker.Poll(ctx))
+
+	// the worker waits longer than the worker timeout for the new master to
+	// acknowledge
+	worker.clock.(*clock.Mock).Add(config.DefaultTimeoutConfig().WorkerTimeoutDuration - 2*time.Second)
+	ok, err := worker.masterClient.CheckMasterTimeout(ctx, worker.clock)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	pongMsg := &libModel.HeartbeatPongMessage{
+		SendTime:   worker.clock.Mono(),
+		ReplyTime:  time.Now(),
+		ToWorkerID: workerID1,
+		Epoch:      2,
+	}
+	err = worker.messageHandlerManager.InvokeHandler(t,
+		libModel.HeartbeatPongTopic(masterName, workerID1), executorNodeID3, pongMsg)
+	require.NoError(t, err)
+	ok, err = worker.masterClient.CheckMasterTimeout(ctx, worker.clock)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, int64(1), worker.failoverCount.Load())
+}
+
+// startSimulatedMaster simulates a master replying the heartbeats of workers
+// on the given node of the simulated cluster.
+func startSimulatedMaster(
+	t *testing.T, env *simulation.Env, nodeID p2p.NodeID, epoch libModel.Epoch,
+) {
+	ctx := context.Background()
+	_, endpoint, err := env.NewContext(nodeID)
+	require.NoError(t, err)
+	ok, err := endpoint.RegisterHandler(ctx, libModel.HeartbeatPingTopic(masterName),
+		&libModel.HeartbeatPingMessage{},
+		func(sender p2p.NodeID, value p2p.MessageValue) error {
+			ping := value.(*libModel.HeartbeatPingMessage)
+			pong := &libModel.HeartbeatPongMessage{
+				SendTime:   ping.SendTime,
+				ReplyTime:  env.Scheduler().Now(),
+				ToWorkerID: ping.FromWorkerID,
+				Epoch:      epoch,
+			}
+			_, err := endpoint.SendToNode(ctx, sender,
+				libModel.HeartbeatPongTopic(masterName, ping.FromWorkerID), pong)
+			return err
+		})
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestWorkerMasterFailoverSimulation(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	env, err := simulation.NewEnv(1)
+	require.NoError(t, err)
+	sched := env.Scheduler()
+	sched.SetStep(50 * time.Millisecond)
+	network := env.Network()
+	network.SetLatency(time.Millisecond, 20*time.Millisecond)
+
+	putMasterMeta(ctx, t, env.FrameMetaClient(), &libModel.MasterMetaKVData{
+		ID:         masterName,
+		NodeID:     masterNodeName,
+		Epoch:      1,
+		StatusCode: libModel.MasterStatusInit,
+	})
+	startSimulatedMaster(t, env, masterNodeName, 1)
+
+	workerCtx, _, err := env.NewContext(executorNodeID1)
+	require.NoError(t, err)
+	worker := &mockWorkerImpl{id: workerID1}
+	worker.DefaultBaseWorker = NewBaseWorker(workerCtx, worker, workerID1, masterName).(*DefaultBaseWorker)
+	worker.On("InitImpl", mock.Anything).Return(nil)
+	worker.On("Status").Return(libModel.WorkerStatus{
+		Code: libModel.WorkerStatusNormal,
+	}, nil)
+	worker.On("Tick", mock.Anything).Return(nil)
+	worker.On("CloseImpl").Return(nil)
+	require.NoError(t, worker.Init(ctx))
+
+	var pollErr error
+	sched.Every(100*time.Millisecond, func() bool {
+		pollErr = worker.Poll(ctx)
+		return pollErr == nil
+	})
+
+	// the worker keeps alive with heartbeats
+	sched.RunFor(time.Minute)
+	require.NoError(t, pollErr)
+	require.Equal(t, int64(0), worker.failoverCount.Load())
+
+	// the old master is isolated and the master fails over to another node
+	network.Partition(executorNodeID1, masterNodeName)
+	putMasterMeta(ctx, t, env.FrameMetaClient(), &libModel.MasterMetaKVData{
+		ID:         masterName,
+		NodeID:     executorNodeID3,
+		Epoch:      2,
+		StatusCode: libModel.MasterStatusInit,
+	})
+	startSimulatedMaster(t, env, executorNodeID3, 2)
+	worker.On("OnMasterFailover", mock.Anything).Return(nil)
+
+	err = sched.RunUntil(func() bool {
+		return worker.failoverCount.Load() == 1
+	}, config.DefaultTimeoutConfig().WorkerTimeoutDuration)
+	require.NoError(t, err)
+	require.Equal(t, executorNodeID3, worker.masterClient.MasterNode())
+
+	// the worker keeps alive with the new master
+	sched.RunFor(time.Minute)
+	require.NoError(t, pollErr)
+	require.Equal(t, int64(1), worker.failoverCount.Load())
+
+	err = worker.Close(ctx)
+	require.NoError(t, err)
+	require.NoError(t, env.Close())
+}
+
 func TestWorkerStatus(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -287,6 +580,40 @@ func TestWorkerStatus(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestWorkerStatusWriteFaultInjected(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	worker := newMockWorkerImpl(workerID1, masterName)
+	worker.clock = clock.NewMock()
+	worker.clock.(*clock.Mock).Set(time.Now())
+	injector := faultinject.NewInjector()
+	worker.faultInjector = injector
+	putMasterMeta(ctx, t, worker.metaClient, &libModel.MasterMetaKVData{
+		ID:         masterName,
+		NodeID:     masterNodeName,
+		Epoch:      1,
+		StatusCode: libModel.MasterStatusInit,
+	})
+
+	worker.On("InitImpl", mock.Anything).Return(nil)
+	worker.On("CloseImpl", mock.Anything).Return(nil)
+
+	err := worker.Init(ctx)
+	require.NoError(t, err)
+
+	injector.FailNextMetaWrite(workerID1)
+	err = worker.UpdateStatus(ctx, libModel.WorkerStatus{Code: libModel.WorkerStatusNormal})
+	require.True(t, derror.ErrMetaWriteFaultInjected.Equal(errors.Cause(err)))
+
+	// the fault is injected only once
+	err = worker.UpdateStatus(ctx, libModel.WorkerStatus{Code: libModel.WorkerStatusNormal})
+	require.NoError(t, err)
+
+	err = worker.Close(ctx)
+	require.NoError(t, err)
+}
+
 func TestWorkerSuicide(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -323,6 +650,20 @@ func TestWorkerSuicide(t *testing.T) {
 	}, time.Second*1, time.Millisecond*10)
 
 	require.Regexp(t, ".*Suicide.*", exitErr.Error())
+
+	// committing suicide is not a failure, the status is left to the master
+	status, err := worker.metaClient.GetWorkerByID(ctx, masterName, workerID1)
+	require.NoError(t, err)
+	require.NotEqual(t, libModel.WorkerStatusError, status.Code)
+}
+
+func TestIsWorkerFailure(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, isWorkerFailure(errors.New("fake error")))
+	require.False(t, isWorkerFailure(derror.ErrWorkerFinish.FastGenByArgs()))
+	require.False(t, isWorkerFailure(derror.ErrWorkerSuicide.GenWithStackByArgs(masterName)))
+	require.False(t, isWorkerFailure(errors.Trace(context.Canceled)))
 }
 
 func TestWorkerSuicideAfterRuntimeDelay(t *testing.T) {
@@ -426,6 +767,12 @@ func TestWorkerGracefulExit(t *testing.T) {
 	err = worker.Poll(ctx)
 	require.Error(t, err)
 	require.Regexp(t, ".*fake error.*", err)
+
+	// the error status is persisted before the worker exits
+	status, err := worker.metaClient.GetWorkerByID(ctx, masterName, workerID1)
+	require.NoError(t, err)
+	require.Equal(t, libModel.WorkerStatusError, status.Code)
+	require.Regexp(t, ".*fake error.*", status.ErrorMessage)
 }
 
 func TestWorkerGracefulExitWhileTimeout(t *testing.T) {
@@ -0,0 +1,77 @@
+package lib
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pingcap/errors"
+)
+
+// ConfigCodec defines how the config of a worker is encoded before it is
+// dispatched to an executor. It must match the DeserializeConfig of the
+// worker factory registered for the worker type.
+type ConfigCodec int
+
+// Defines all config codecs
+const (
+	// JSONConfigCodec is the default codec
+	JSONConfigCodec = ConfigCodec(iota)
+	TOMLConfigCodec
+)
+
+// Encode encodes the config with the codec
+func (c ConfigCodec) Encode(config WorkerConfig) ([]byte, error) {
+	switch c {
+	case TOMLConfigCodec:
+		var b bytes.Buffer
+		if err := toml.NewEncoder(&b).Encode(config); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return b.Bytes(), nil
+	default:
+		rawConfig, err := json.Marshal(config)
+		return rawConfig, errors.Trace(err)
+	}
+}
+
+// WorkerTypeInfo declares how the framework should treat a worker type
+type WorkerTypeInfo struct {
+	// IsJobMaster indicates the worker type is a job master, whose config
+	// is a `*MasterMetaKVData` containing pre-allocated master ID and
+	// encoded config.
+	IsJobMaster bool
+	// Codec is the config codec for worker types that are not job masters.
+	Codec ConfigCodec
+}
+
+var workerTypeInfos = struct {
+	sync.RWMutex
+	m map[WorkerType]WorkerTypeInfo
+}{
+	m: make(map[WorkerType]WorkerTypeInfo),
+}
+
+// RegisterWorkerTypeInfo declares the WorkerTypeInfo of a worker type.
+// It returns false if the worker type has been declared with a different
+// WorkerTypeInfo. Registering the same info repeatedly is allowed.
+func RegisterWorkerTypeInfo(tp WorkerType, info WorkerTypeInfo) (ok bool) {
+	workerTypeInfos.Lock()
+	defer workerTypeInfos.Unlock()
+
+	if old, exists := workerTypeInfos.m[tp]; exists {
+		return old == info
+	}
+	workerTypeInfos.m[tp] = info
+	return true
+}
+
+// getWorkerTypeInfo returns the declared WorkerTypeInfo of a worker type.
+func getWorkerTypeInfo(tp WorkerType) (WorkerTypeInfo, bool) {
+	workerTypeInfos.RLock()
+	defer workerTypeInfos.RUnlock()
+
+	info, ok := workerTypeInfos.m[tp]
+	return info, ok
+}
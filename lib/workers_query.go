@@ -0,0 +1,159 @@
+package lib
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"go.uber.org/zap"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/p2p"
+)
+
+// workersQueryRetryInterval is the interval to resend a workers query, in
+// case that the request or the response is lost.
+const workersQueryRetryInterval = time.Second
+
+// workersQuerier tracks the workers queries of a master waiting for replies.
+type workersQuerier struct {
+	mu        sync.Mutex
+	nextReqID uint64
+	waiters   map[uint64]chan *libModel.WorkersQueryResponse
+}
+
+func newWorkersQuerier() *workersQuerier {
+	return &workersQuerier{
+		waiters: make(map[uint64]chan *libModel.WorkersQueryResponse),
+	}
+}
+
+func (q *workersQuerier) add() (uint64, chan *libModel.WorkersQueryResponse) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.nextReqID++
+	ch := make(chan *libModel.WorkersQueryResponse, 1)
+	q.waiters[q.nextReqID] = ch
+	return q.nextReqID, ch
+}
+
+func (q *workersQuerier) remove(reqID uint64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.waiters, reqID)
+}
+
+func (q *workersQuerier) onResponse(resp *libModel.WorkersQueryResponse) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	ch, ok := q.waiters[resp.RequestID]
+	if !ok {
+		return
+	}
+	select {
+	case ch <- resp:
+	default:
+	}
+}
+
+// registerWorkersQueryHandler handles the replies of job masters to the
+// workers queries sent by QueryWorkers.
+func (m *DefaultBaseMaster) registerWorkersQueryHandler(ctx context.Context) error {
+	topic := libModel.WorkersQueryResponseTopic(m.id)
+	ok, err := m.messageHandlerManager.RegisterHandler(
+		ctx,
+		topic,
+		&libModel.WorkersQueryResponse{},
+		func(sender p2p.NodeID, value p2p.MessageValue) error {
+			msg, ok := value.(*libModel.WorkersQueryResponse)
+			if !ok {
+				return derror.ErrInvalidMasterMessage.GenWithStackByArgs(value)
+			}
+			m.workersQuerier.onResponse(msg)
+			return nil
+		})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !ok {
+		m.Logger().Panic("duplicate handler", zap.String("topic", topic))
+	}
+	return nil
+}
+
+// QueryWorkers implements BaseMaster.QueryWorkers
+func (m *DefaultBaseMaster) QueryWorkers(
+	ctx context.Context, workerID libModel.WorkerID, query *libModel.WorkersQuery,
+) (*libModel.WorkersQueryResponse, error) {
+	handle, ok := m.workerManager.GetWorkers()[workerID]
+	if !ok || handle.GetTombstone() != nil {
+		return nil, derror.ErrWorkerNotFound.GenWithStackByArgs(workerID)
+	}
+	if err := m.workerCaps.check([]libModel.WorkerID{workerID}, libModel.CapabilityWorkersQuery); err != nil {
+		return nil, err
+	}
+
+	reqID, ch := m.workersQuerier.add()
+	defer m.workersQuerier.remove(reqID)
+	req := &libModel.WorkersQueryRequest{
+		RequestID:    reqID,
+		FromMasterID: m.id,
+		Epoch:        m.currentEpoch.Load(),
+		Query:        *query,
+	}
+	topic := libModel.WorkersQueryRequestTopic(m.id, workerID)
+
+	ticker := time.NewTicker(workersQueryRetryInterval)
+	defer ticker.Stop()
+	for {
+		if err := handle.Unwrap().SendMessage(ctx, topic, req, true /*nonblocking*/); err != nil {
+			m.Logger().Info("failed to send workers query",
+				zap.String("worker-id", workerID), zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return nil, errors.Trace(ctx.Err())
+		case resp := <-ch:
+			return resp, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// registerWorkersQueryHandler replies to the workers queries sent by job
+// manager with the workers kept by the master part of the job master.
+func (d *DefaultBaseJobMaster) registerWorkersQueryHandler(ctx context.Context) error {
+	topic := libModel.WorkersQueryRequestTopic(d.worker.masterID, d.worker.id)
+	ok, err := d.worker.messageHandlerManager.RegisterHandler(
+		ctx,
+		topic,
+		&libModel.WorkersQueryRequest{},
+		func(sender p2p.NodeID, value p2p.MessageValue) error {
+			msg, ok := value.(*libModel.WorkersQueryRequest)
+			if !ok {
+				return derror.ErrInvalidMasterMessage.GenWithStackByArgs(value)
+			}
+			if msg.Epoch < d.worker.masterClient.Epoch() {
+				d.Logger().Info("stale workers query dropped", zap.Any("request", msg))
+				return nil
+			}
+			resp := &libModel.WorkersQueryResponse{RequestID: msg.RequestID}
+			resp.Workers, resp.NextPageToken = d.master.workerManager.ListWorkers(&msg.Query)
+			// job manager resends the query if the response is lost
+			_, err := d.worker.messageSender.SendToNode(ctx, sender,
+				libModel.WorkersQueryResponseTopic(msg.FromMasterID), resp)
+			if err != nil {
+				d.Logger().Info("failed to reply workers query", zap.Error(err))
+			}
+			return nil
+		})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !ok {
+		d.Logger().Panic("duplicate handler", zap.String("topic", topic))
+	}
+	return nil
+}
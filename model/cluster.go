@@ -24,6 +24,17 @@ type DeployNodeID string
 // ExecutorID is an alias for executor when NodeType is NodeTypeExecutor.
 type ExecutorID = DeployNodeID
 
+// Labels of executors describing where they run, they are set by the
+// executors bootstrapped in kubernetes mode or declared in config.
+const (
+	LabelPodName      = "pod-name"
+	LabelPodNamespace = "pod-namespace"
+	LabelNodeName     = "node-name"
+	// LabelZone is used by the scheduler to spread the tasks of a job
+	// across zones.
+	LabelZone = "zone"
+)
+
 // NodeInfo describes the information of server instance, contains node type, node
 // uuid, advertise address and capability(executor node only)
 type NodeInfo struct {
@@ -31,12 +42,33 @@ type NodeInfo struct {
 	ID   DeployNodeID `json:"id"`
 	Addr string       `json:"addr"`
 
-	// The capability of executor, including
-	// 1. cpu (goroutines)
-	// 2. memory
-	// 3. disk cap
-	// TODO: So we should enrich the cap dimensions in the future.
+	// The capability of executor in the cpu (goroutines) dimension, the
+	// other dimensions such as memory and disk are in Resources.
 	Capability int `json:"cap"`
+	// Resources is the capacity of the executor in the dimensions other than
+	// cpu, including custom resources such as "gpu".
+	Resources RescVector `json:"resources,omitempty"`
+
+	// IdleEvictable is true if the executor has run no worker for a while,
+	// external autoscalers can remove such an executor safely.
+	IdleEvictable bool `json:"idle-evictable,omitempty"`
+
+	// Labels describe where the executor runs, see LabelZone for example.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// MessageCodecs are the codecs of p2p messages the node decodes, peers
+	// compress the messages sent to the node only with these codecs.
+	MessageCodecs []string `json:"message-codecs,omitempty"`
+}
+
+// Zone returns the zone the executor runs in, empty means unknown.
+func (e *NodeInfo) Zone() string {
+	return e.Labels[LabelZone]
+}
+
+// CapacityVector returns the capacity of the executor in all dimensions.
+func (e *NodeInfo) CapacityVector() RescVector {
+	return e.Resources.Merge(RescUnit(e.Capability).Vector())
 }
 
 // EtcdKey return encoded key for a node used in service discovery etcd
@@ -53,6 +85,9 @@ const (
 	Running
 	Disconnected
 	Tombstone
+	// Draining means the executor is shutting down gracefully, it doesn't
+	// accept new tasks while its running tasks are exiting.
+	Draining
 )
 
 // ExecutorStatusNameMapping maps from executor status to human-readable string
@@ -61,6 +96,7 @@ var ExecutorStatusNameMapping = map[ExecutorStatus]string{
 	Running:      "running",
 	Disconnected: "disconnected",
 	Tombstone:    "tombstone",
+	Draining:     "draining",
 }
 
 // String implements fmt.Stringer
@@ -72,6 +108,37 @@ func (s ExecutorStatus) String() string {
 	return val
 }
 
+// CordonState describes whether an executor is excluded from scheduling by
+// operators
+type CordonState int32
+
+// All CordonState
+const (
+	Uncordoned CordonState = iota
+	// Cordoned means no new task is scheduled to the executor, its running
+	// tasks are not affected.
+	Cordoned
+	// Blacklisted means the executor is treated as failed and can't register
+	// again.
+	Blacklisted
+)
+
+// CordonStateNameMapping maps from cordon state to human-readable string
+var CordonStateNameMapping = map[CordonState]string{
+	Uncordoned:  "uncordoned",
+	Cordoned:    "cordoned",
+	Blacklisted: "blacklisted",
+}
+
+// String implements fmt.Stringer
+func (s CordonState) String() string {
+	val, ok := CordonStateNameMapping[s]
+	if !ok {
+		return "unknown"
+	}
+	return val
+}
+
 // ToJSON returns json marshal of a node info
 func (e *NodeInfo) ToJSON() (string, error) {
 	data, err := json.Marshal(e)
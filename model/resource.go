@@ -0,0 +1,140 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Names of the built-in resource dimensions, other names are custom resources
+// such as "gpu".
+const (
+	// ResourceCPU is the dimension measured by RescUnit
+	ResourceCPU    = "cpu"
+	ResourceMemory = "memory"
+	ResourceDisk   = "disk"
+)
+
+// DiskUnit is the bytes of a unit of ResourceDisk, the executors report the
+// disk usage of local file resources in it.
+const DiskUnit = 1 << 20
+
+// RescVector is a multi-dimensional amount of resources keyed by the names
+// of the dimensions, a missing dimension means zero. The unit of a dimension
+// is decided by whoever declares the capacities and requirements of it.
+type RescVector map[string]int64
+
+// Vector converts the scalar RescUnit to a RescVector with the cpu dimension
+// only.
+func (r RescUnit) Vector() RescVector {
+	if r == 0 {
+		return RescVector{}
+	}
+	return RescVector{ResourceCPU: int64(r)}
+}
+
+// CPU returns the cpu dimension as a RescUnit, which is the amount known by
+// callers not aware of the other dimensions.
+func (v RescVector) CPU() RescUnit {
+	return RescUnit(v[ResourceCPU])
+}
+
+// Clone returns a copy of the vector.
+func (v RescVector) Clone() RescVector {
+	ret := make(RescVector, len(v))
+	for name, amount := range v {
+		ret[name] = amount
+	}
+	return ret
+}
+
+// Add returns the sum of two vectors.
+func (v RescVector) Add(other RescVector) RescVector {
+	ret := v.Clone()
+	for name, amount := range other {
+		ret[name] += amount
+	}
+	return ret
+}
+
+// Sub returns the difference of two vectors, the result may be negative.
+func (v RescVector) Sub(other RescVector) RescVector {
+	ret := v.Clone()
+	for name, amount := range other {
+		ret[name] -= amount
+	}
+	return ret
+}
+
+// Max returns the maximum of two vectors in each dimension.
+func (v RescVector) Max(other RescVector) RescVector {
+	ret := v.Clone()
+	for name, amount := range other {
+		if amount > ret[name] {
+			ret[name] = amount
+		}
+	}
+	return ret
+}
+
+// Merge returns a copy of v with the dimensions of other overriding those of v.
+func (v RescVector) Merge(other RescVector) RescVector {
+	ret := v.Clone()
+	for name, amount := range other {
+		ret[name] = amount
+	}
+	return ret
+}
+
+// Scale returns the vector with each dimension multiplied by percent/100.
+func (v RescVector) Scale(percent int) RescVector {
+	ret := make(RescVector, len(v))
+	for name, amount := range v {
+		ret[name] = amount * int64(percent) / 100
+	}
+	return ret
+}
+
+// Multiply returns the vector with each dimension multiplied by n.
+func (v RescVector) Multiply(n int) RescVector {
+	ret := make(RescVector, len(v))
+	for name, amount := range v {
+		ret[name] = amount * int64(n)
+	}
+	return ret
+}
+
+// Fits returns whether v is no larger than available in every dimension.
+func (v RescVector) Fits(available RescVector) bool {
+	for name, amount := range v {
+		if amount > available[name] {
+			return false
+		}
+	}
+	return true
+}
+
+// IsZero returns whether every dimension is zero.
+func (v RescVector) IsZero() bool {
+	for _, amount := range v {
+		if amount != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// String implements fmt.Stringer, the dimensions are sorted by names.
+func (v RescVector) String() string {
+	names := make([]string, 0, len(v))
+	for name := range v {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s=%d", name, v[name]))
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
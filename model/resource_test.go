@@ -0,0 +1,36 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRescVector(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, RescVector{ResourceCPU: 10}, RescUnit(10).Vector())
+	require.Equal(t, RescUnit(10), RescVector{ResourceCPU: 10, "gpu": 1}.CPU())
+	require.Equal(t, RescUnit(0), RescVector(nil).CPU())
+
+	capacity := RescVector{ResourceCPU: 100, ResourceMemory: 1024, "gpu": 2}
+	used := RescVector{ResourceCPU: 60, ResourceMemory: 512}
+	reserved := RescVector{ResourceCPU: 40, ResourceMemory: 768}
+	remaining := capacity.Sub(used.Max(reserved))
+	require.Equal(t, RescVector{ResourceCPU: 40, ResourceMemory: 256, "gpu": 2}, remaining)
+	// the operations don't modify the operands
+	require.Equal(t, RescVector{ResourceCPU: 60, ResourceMemory: 512}, used)
+	require.Equal(t, RescVector{ResourceCPU: 100, ResourceMemory: 1280}, used.Add(reserved).Sub(RescVector{}).Max(nil))
+
+	require.True(t, RescVector{ResourceCPU: 40, "gpu": 2}.Fits(remaining))
+	require.True(t, RescVector{"gpu": 0, "fpga": 0}.Fits(remaining))
+	require.False(t, RescVector{ResourceCPU: 41}.Fits(remaining))
+	require.False(t, RescVector{"fpga": 1}.Fits(remaining))
+	require.True(t, RescVector(nil).Fits(nil))
+
+	require.Equal(t, RescVector{ResourceCPU: 30, "gpu": 0}, RescVector{ResourceCPU: 100, "gpu": 1}.Scale(30))
+	require.Equal(t, RescVector{ResourceCPU: 20, "gpu": 1}, RescUnit(10).Vector().Merge(RescVector{ResourceCPU: 20, "gpu": 1}))
+	require.True(t, RescVector{"gpu": 0}.IsZero())
+	require.False(t, RescVector{"gpu": -1}.IsZero())
+	require.Equal(t, "{cpu=100,gpu=2,memory=1024}", capacity.String())
+}
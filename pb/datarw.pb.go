@@ -379,16 +379,123 @@ func (m *ListFilesResponse) GetFileNum() int32 {
 	return 0
 }
 
+type SplitFileRequest struct {
+	FileIdx  int32 `protobuf:"varint,1,opt,name=file_idx,json=fileIdx,proto3" json:"file_idx,omitempty"`
+	SplitNum int32 `protobuf:"varint,2,opt,name=split_num,json=splitNum,proto3" json:"split_num,omitempty"`
+}
+
+func (m *SplitFileRequest) Reset()         { *m = SplitFileRequest{} }
+func (m *SplitFileRequest) String() string { return proto.CompactTextString(m) }
+func (*SplitFileRequest) ProtoMessage()    {}
+func (*SplitFileRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_03dd23a8ba2c07e2, []int{8}
+}
+func (m *SplitFileRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SplitFileRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SplitFileRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SplitFileRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SplitFileRequest.Merge(m, src)
+}
+func (m *SplitFileRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *SplitFileRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SplitFileRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SplitFileRequest proto.InternalMessageInfo
+
+func (m *SplitFileRequest) GetFileIdx() int32 {
+	if m != nil {
+		return m.FileIdx
+	}
+	return 0
+}
+
+func (m *SplitFileRequest) GetSplitNum() int32 {
+	if m != nil {
+		return m.SplitNum
+	}
+	return 0
+}
+
+type SplitFileResponse struct {
+	// the ascending start keys of the ranges except the first one
+	SplitKeys [][]byte `protobuf:"bytes,1,rep,name=split_keys,json=splitKeys,proto3" json:"split_keys,omitempty"`
+	ErrMsg    string   `protobuf:"bytes,2,opt,name=err_msg,json=errMsg,proto3" json:"err_msg,omitempty"`
+}
+
+func (m *SplitFileResponse) Reset()         { *m = SplitFileResponse{} }
+func (m *SplitFileResponse) String() string { return proto.CompactTextString(m) }
+func (*SplitFileResponse) ProtoMessage()    {}
+func (*SplitFileResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_03dd23a8ba2c07e2, []int{9}
+}
+func (m *SplitFileResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SplitFileResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SplitFileResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SplitFileResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SplitFileResponse.Merge(m, src)
+}
+func (m *SplitFileResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *SplitFileResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SplitFileResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SplitFileResponse proto.InternalMessageInfo
+
+func (m *SplitFileResponse) GetSplitKeys() [][]byte {
+	if m != nil {
+		return m.SplitKeys
+	}
+	return nil
+}
+
+func (m *SplitFileResponse) GetErrMsg() string {
+	if m != nil {
+		return m.ErrMsg
+	}
+	return ""
+}
+
 type ReadLinesRequest struct {
 	FileIdx int32  `protobuf:"varint,1,opt,name=fileIdx,proto3" json:"fileIdx,omitempty"`
 	LineNo  []byte `protobuf:"bytes,2,opt,name=lineNo,proto3" json:"lineNo,omitempty"`
+	// the lines before endLineNo are read, or till the end of the file if it's empty
+	EndLineNo []byte `protobuf:"bytes,3,opt,name=endLineNo,proto3" json:"endLineNo,omitempty"`
 }
 
 func (m *ReadLinesRequest) Reset()         { *m = ReadLinesRequest{} }
 func (m *ReadLinesRequest) String() string { return proto.CompactTextString(m) }
 func (*ReadLinesRequest) ProtoMessage()    {}
 func (*ReadLinesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_03dd23a8ba2c07e2, []int{8}
+	return fileDescriptor_03dd23a8ba2c07e2, []int{10}
 }
 func (m *ReadLinesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -431,6 +538,13 @@ func (m *ReadLinesRequest) GetLineNo() []byte {
 	return nil
 }
 
+func (m *ReadLinesRequest) GetEndLineNo() []byte {
+	if m != nil {
+		return m.EndLineNo
+	}
+	return nil
+}
+
 type ReadLinesResponse struct {
 	Key    []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
 	Val    []byte `protobuf:"bytes,2,opt,name=val,proto3" json:"val,omitempty"`
@@ -442,7 +556,7 @@ func (m *ReadLinesResponse) Reset()         { *m = ReadLinesResponse{} }
 func (m *ReadLinesResponse) String() string { return proto.CompactTextString(m) }
 func (*ReadLinesResponse) ProtoMessage()    {}
 func (*ReadLinesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_03dd23a8ba2c07e2, []int{9}
+	return fileDescriptor_03dd23a8ba2c07e2, []int{11}
 }
 func (m *ReadLinesResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -510,7 +624,7 @@ func (m *WriteLinesRequest) Reset()         { *m = WriteLinesRequest{} }
 func (m *WriteLinesRequest) String() string { return proto.CompactTextString(m) }
 func (*WriteLinesRequest) ProtoMessage()    {}
 func (*WriteLinesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_03dd23a8ba2c07e2, []int{10}
+	return fileDescriptor_03dd23a8ba2c07e2, []int{12}
 }
 func (m *WriteLinesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -575,7 +689,7 @@ func (m *WriteLinesResponse) Reset()         { *m = WriteLinesResponse{} }
 func (m *WriteLinesResponse) String() string { return proto.CompactTextString(m) }
 func (*WriteLinesResponse) ProtoMessage()    {}
 func (*WriteLinesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_03dd23a8ba2c07e2, []int{11}
+	return fileDescriptor_03dd23a8ba2c07e2, []int{13}
 }
 func (m *WriteLinesResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -620,6 +734,8 @@ func init() {
 	proto.RegisterType((*IsReadyResponse)(nil), "pb.IsReadyResponse")
 	proto.RegisterType((*ListFilesReq)(nil), "pb.ListFilesReq")
 	proto.RegisterType((*ListFilesResponse)(nil), "pb.ListFilesResponse")
+	proto.RegisterType((*SplitFileRequest)(nil), "pb.SplitFileRequest")
+	proto.RegisterType((*SplitFileResponse)(nil), "pb.SplitFileResponse")
 	proto.RegisterType((*ReadLinesRequest)(nil), "pb.ReadLinesRequest")
 	proto.RegisterType((*ReadLinesResponse)(nil), "pb.ReadLinesResponse")
 	proto.RegisterType((*WriteLinesRequest)(nil), "pb.WriteLinesRequest")
@@ -629,41 +745,46 @@ func init() {
 func init() { proto.RegisterFile("datarw.proto", fileDescriptor_03dd23a8ba2c07e2) }
 
 var fileDescriptor_03dd23a8ba2c07e2 = []byte{
-	// 532 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x54, 0xcd, 0x6e, 0xd3, 0x40,
-	0x10, 0x8e, 0x13, 0xf2, 0x37, 0x32, 0xad, 0xb3, 0x35, 0xad, 0xb1, 0x84, 0x55, 0x2d, 0x07, 0x7a,
-	0x69, 0x40, 0x05, 0x6e, 0x48, 0x08, 0x08, 0xa0, 0x48, 0x6d, 0x90, 0xcc, 0xa1, 0xc7, 0xca, 0x89,
-	0xa7, 0xed, 0xaa, 0x8e, 0x9d, 0xae, 0xed, 0x10, 0xde, 0x82, 0xc7, 0xe2, 0xd8, 0x23, 0x47, 0x94,
-	0xbc, 0x01, 0x4f, 0x80, 0x76, 0xbd, 0x71, 0x6c, 0xa7, 0x52, 0x6f, 0x9e, 0xd9, 0x6f, 0xe6, 0x9b,
-	0x6f, 0xf6, 0x5b, 0x83, 0xee, 0x7b, 0x89, 0xc7, 0x7f, 0xf4, 0x67, 0x3c, 0x4a, 0x22, 0x52, 0x9f,
-	0x8d, 0xe9, 0x37, 0xd8, 0xfb, 0x8a, 0x21, 0x72, 0x2f, 0xc1, 0x81, 0x97, 0x78, 0x2e, 0xde, 0xa6,
-	0x18, 0x27, 0xe4, 0x29, 0x74, 0x2e, 0x59, 0x80, 0x17, 0x61, 0x3a, 0xb5, 0xb4, 0x43, 0xed, 0xa8,
-	0xe9, 0xb6, 0x45, 0x3c, 0x4a, 0xa7, 0xe4, 0x19, 0x00, 0xc7, 0x49, 0xc4, 0x7d, 0x79, 0x58, 0x97,
-	0x87, 0xdd, 0x2c, 0x33, 0x4a, 0xa7, 0xf4, 0x25, 0x98, 0xe5, 0x86, 0xf1, 0x2c, 0x0a, 0x63, 0x24,
-	0x07, 0xd0, 0x46, 0xce, 0x2f, 0xa6, 0xf1, 0x95, 0x6c, 0xd8, 0x75, 0x5b, 0xc8, 0xf9, 0x59, 0x7c,
-	0x45, 0x9f, 0xc3, 0xee, 0xa7, 0x6b, 0x9c, 0xdc, 0x0c, 0x18, 0x5f, 0xb3, 0x1b, 0xd0, 0xf0, 0x19,
-	0x57, 0x38, 0xf1, 0x49, 0xcf, 0xc0, 0xd8, 0x80, 0x1e, 0xe8, 0x48, 0x0e, 0x41, 0x17, 0x07, 0x52,
-	0x00, 0xf3, 0x17, 0x6a, 0x46, 0x40, 0xce, 0xbf, 0xb0, 0x00, 0x87, 0xfe, 0x82, 0x1a, 0xb0, 0x33,
-	0x8c, 0x5d, 0xf4, 0xfc, 0x9f, 0x8a, 0x92, 0xbe, 0x80, 0xdd, 0x3c, 0xa3, 0xfa, 0x9b, 0xd0, 0xe4,
-	0x22, 0x21, 0xbb, 0x77, 0xdc, 0x2c, 0xa0, 0x3b, 0xa0, 0x9f, 0xb2, 0x38, 0x11, 0x9d, 0x62, 0x17,
-	0x6f, 0xe9, 0x31, 0xf4, 0x0a, 0xb1, 0x2a, 0xb5, 0x60, 0xbd, 0xae, 0xca, 0xf6, 0xe8, 0x00, 0x0c,
-	0xc1, 0x72, 0xca, 0x42, 0x59, 0x2e, 0xe5, 0x2a, 0xf4, 0xd0, 0x5f, 0x14, 0xd1, 0x43, 0x7f, 0x41,
-	0xf6, 0xa1, 0x15, 0xb0, 0x10, 0x47, 0x91, 0xd4, 0xa0, 0xbb, 0x2a, 0xa2, 0x08, 0xbd, 0x42, 0x17,
-	0x45, 0x6a, 0x40, 0xe3, 0x06, 0xb3, 0x69, 0x75, 0x57, 0x7c, 0x8a, 0xcc, 0xdc, 0x0b, 0x54, 0xad,
-	0xf8, 0x14, 0x9a, 0x58, 0xfc, 0x39, 0xba, 0xb4, 0x1a, 0x99, 0x26, 0x19, 0x08, 0x9a, 0x6c, 0x75,
-	0xd6, 0xa3, 0xd2, 0xd5, 0x5c, 0x43, 0xef, 0x9c, 0xb3, 0x04, 0x4b, 0xd3, 0x6e, 0x5d, 0x4e, 0x6e,
-	0x96, 0xcd, 0xae, 0x73, 0x01, 0x6a, 0xa6, 0xc6, 0x66, 0x26, 0x13, 0x9a, 0x73, 0x2f, 0x48, 0x51,
-	0x52, 0xe9, 0x6e, 0x16, 0xd0, 0x63, 0x20, 0x45, 0xa6, 0x07, 0x6e, 0xf8, 0xe4, 0x5f, 0x1d, 0x1e,
-	0x4b, 0x77, 0x9d, 0x7f, 0x47, 0x3e, 0x67, 0x13, 0x24, 0xef, 0xa0, 0x9b, 0x6f, 0x84, 0x98, 0xfd,
-	0xd9, 0xb8, 0x5f, 0x5d, 0xb3, 0xfd, 0xa4, 0x92, 0xcd, 0x48, 0x68, 0xed, 0x95, 0x46, 0xde, 0x03,
-	0x6c, 0xe8, 0x89, 0x04, 0x6e, 0x09, 0xb7, 0xf7, 0xab, 0xe9, 0x75, 0x83, 0x23, 0x8d, 0x7c, 0x00,
-	0xbd, 0xe8, 0x7a, 0x72, 0x20, 0xb0, 0xf7, 0x3c, 0x2c, 0xdb, 0xda, 0x3e, 0x50, 0x62, 0xdf, 0x40,
-	0x37, 0x37, 0x12, 0x31, 0x04, 0xac, 0xe8, 0xb3, 0x6c, 0xfa, 0x6d, 0xa7, 0x9d, 0x40, 0x5b, 0xf9,
-	0x96, 0x10, 0x81, 0x28, 0xdb, 0xda, 0xde, 0x2b, 0xe5, 0x54, 0xcd, 0x5b, 0xe8, 0xac, 0x1f, 0x13,
-	0x91, 0x80, 0xca, 0xfb, 0xb3, 0xcd, 0x72, 0x32, 0x2b, 0xfb, 0x68, 0xfd, 0x5e, 0x3a, 0xda, 0xdd,
-	0xd2, 0xd1, 0xfe, 0x2e, 0x1d, 0xed, 0xd7, 0xca, 0xa9, 0xdd, 0xad, 0x9c, 0xda, 0x9f, 0x95, 0x53,
-	0x1b, 0xb7, 0xe4, 0xff, 0xe4, 0xf5, 0xff, 0x00, 0x00, 0x00, 0xff, 0xff, 0x42, 0x11, 0xc6, 0x92,
-	0x5f, 0x04, 0x00, 0x00,
+	// 611 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x54, 0xcf, 0x4e, 0xdb, 0x4e,
+	0x10, 0x8e, 0xe3, 0x1f, 0x10, 0x8f, 0xfc, 0x03, 0x67, 0x09, 0xe0, 0xba, 0xc5, 0x42, 0xdb, 0x43,
+	0xb9, 0x40, 0x2b, 0xda, 0x4a, 0x3d, 0x54, 0xaa, 0xda, 0xd2, 0x56, 0x29, 0x90, 0x4a, 0xe6, 0xc0,
+	0x11, 0x39, 0x78, 0x80, 0x15, 0x8e, 0x1d, 0x76, 0x1d, 0x9a, 0xbc, 0x45, 0x1f, 0xa4, 0x0f, 0xd2,
+	0x23, 0xc7, 0x1e, 0xab, 0xe4, 0x45, 0xaa, 0x5d, 0x6f, 0x12, 0xdb, 0xa9, 0xc4, 0x6d, 0xe7, 0x9b,
+	0x6f, 0xbe, 0xf9, 0xb3, 0x3b, 0x0b, 0x76, 0x14, 0x66, 0x21, 0xff, 0xbe, 0xdf, 0xe7, 0x69, 0x96,
+	0x92, 0x7a, 0xbf, 0x4b, 0xbf, 0xc1, 0xfa, 0x17, 0x4c, 0x90, 0x87, 0x19, 0x1e, 0x86, 0x59, 0x18,
+	0xe0, 0xed, 0x00, 0x45, 0x46, 0x1e, 0x41, 0xe3, 0x92, 0xc5, 0x78, 0x9e, 0x0c, 0x7a, 0xae, 0xb1,
+	0x63, 0xec, 0x2e, 0x05, 0x2b, 0xd2, 0xee, 0x0c, 0x7a, 0x64, 0x1b, 0x80, 0xe3, 0x45, 0xca, 0x23,
+	0xe5, 0xac, 0x2b, 0xa7, 0x95, 0x23, 0x9d, 0x41, 0x8f, 0x3e, 0x87, 0x56, 0x59, 0x50, 0xf4, 0xd3,
+	0x44, 0x20, 0xd9, 0x82, 0x15, 0xe4, 0xfc, 0xbc, 0x27, 0xae, 0x94, 0xa0, 0x15, 0x2c, 0x23, 0xe7,
+	0x27, 0xe2, 0x8a, 0x3e, 0x85, 0xb5, 0x8f, 0xd7, 0x78, 0x71, 0x73, 0xc8, 0xf8, 0x34, 0xbb, 0x03,
+	0x66, 0xc4, 0xb8, 0xe6, 0xc9, 0x23, 0x3d, 0x01, 0x67, 0x4e, 0x7a, 0x40, 0x91, 0xec, 0x80, 0x2d,
+	0x1d, 0xaa, 0x01, 0x16, 0x0d, 0x75, 0x8d, 0x80, 0x9c, 0x7f, 0x66, 0x31, 0xb6, 0xa3, 0x21, 0x75,
+	0x60, 0xb5, 0x2d, 0x02, 0x0c, 0xa3, 0x91, 0x4e, 0x49, 0x9f, 0xc1, 0xda, 0x0c, 0xd1, 0xfa, 0x2d,
+	0x58, 0xe2, 0x12, 0x50, 0xea, 0x8d, 0x20, 0x37, 0xe8, 0x2a, 0xd8, 0xc7, 0x4c, 0x64, 0x52, 0x49,
+	0x04, 0x78, 0x4b, 0xf7, 0xa0, 0x59, 0xb0, 0x75, 0xa8, 0x0b, 0xd3, 0x71, 0x55, 0xa6, 0x47, 0xbf,
+	0x82, 0x73, 0xda, 0x8f, 0x99, 0xe2, 0x57, 0x87, 0x2d, 0x6b, 0x2d, 0xd0, 0xdb, 0xd1, 0x90, 0x3c,
+	0x06, 0x4b, 0x48, 0x7a, 0x61, 0xd6, 0x0d, 0x05, 0x48, 0xad, 0x23, 0x68, 0x16, 0xb4, 0x74, 0xea,
+	0x6d, 0x80, 0x3c, 0xe2, 0x06, 0x47, 0xc2, 0x35, 0x76, 0xcc, 0x5d, 0x3b, 0xc8, 0x35, 0x8e, 0x70,
+	0x24, 0x8a, 0x43, 0xab, 0x97, 0xae, 0xa1, 0x0b, 0x8e, 0x6c, 0xff, 0x98, 0x25, 0x28, 0xa6, 0x85,
+	0xe9, 0x36, 0xda, 0x8b, 0x75, 0x6d, 0xc2, 0x72, 0xcc, 0x12, 0xec, 0xa4, 0x4a, 0xc5, 0x0e, 0xb4,
+	0x45, 0x9e, 0x80, 0x85, 0x89, 0x12, 0xe9, 0xa4, 0xae, 0xa9, 0x5c, 0x73, 0x80, 0x22, 0x34, 0x0b,
+	0x39, 0x74, 0xc1, 0x0e, 0x98, 0x37, 0x98, 0x0f, 0xd9, 0x0e, 0xe4, 0x51, 0x22, 0x77, 0x61, 0xac,
+	0x95, 0xe5, 0x51, 0x5e, 0x05, 0x13, 0x9f, 0xd2, 0x4b, 0x25, 0xd9, 0x08, 0x72, 0x43, 0x16, 0x91,
+	0x17, 0xef, 0xfe, 0x57, 0x6a, 0xe5, 0x1a, 0x9a, 0x67, 0x9c, 0x65, 0x58, 0xea, 0x65, 0xe1, 0x4d,
+	0x95, 0xc6, 0x5e, 0x2f, 0xb7, 0xa7, 0x6b, 0x32, 0xe7, 0x35, 0xb5, 0x60, 0xe9, 0x2e, 0x8c, 0x07,
+	0xa8, 0x52, 0xd9, 0x41, 0x6e, 0xd0, 0x3d, 0x20, 0xc5, 0x4c, 0x0f, 0x3c, 0xcc, 0x83, 0x9f, 0x26,
+	0xfc, 0xaf, 0x96, 0xe2, 0xec, 0x14, 0xf9, 0x1d, 0xbb, 0x40, 0xf2, 0x16, 0xac, 0xd9, 0x44, 0x48,
+	0x6b, 0xbf, 0xdf, 0xdd, 0xaf, 0x5e, 0x82, 0xb7, 0x51, 0x41, 0xf3, 0x24, 0xb4, 0xf6, 0xc2, 0x20,
+	0xef, 0x00, 0xe6, 0xe9, 0x89, 0x22, 0x2e, 0x34, 0xee, 0x6d, 0x56, 0xe1, 0xa9, 0xc0, 0xae, 0x41,
+	0xde, 0x83, 0x5d, 0x5c, 0x56, 0xb2, 0x25, 0xb9, 0xff, 0xf8, 0x0f, 0x3c, 0x77, 0xd1, 0xa1, 0x9b,
+	0x7d, 0x05, 0xd6, 0xec, 0xfd, 0x13, 0x47, 0xd2, 0x8a, 0xeb, 0xe1, 0x6d, 0x54, 0x10, 0x1d, 0x75,
+	0x00, 0x2b, 0x7a, 0xdd, 0x08, 0x91, 0x8c, 0xf2, 0x36, 0x7a, 0xeb, 0x25, 0x4c, 0xc7, 0xbc, 0x86,
+	0xc6, 0xf4, 0x0f, 0x20, 0x8a, 0x50, 0xf9, 0x36, 0xbc, 0x56, 0x19, 0xd4, 0x61, 0x6f, 0xc0, 0x9a,
+	0x6d, 0x49, 0x3e, 0xe2, 0xea, 0x02, 0x7a, 0x1b, 0x15, 0x34, 0x8f, 0xfc, 0xe0, 0xfe, 0x1a, 0xfb,
+	0xc6, 0xfd, 0xd8, 0x37, 0xfe, 0x8c, 0x7d, 0xe3, 0xc7, 0xc4, 0xaf, 0xdd, 0x4f, 0xfc, 0xda, 0xef,
+	0x89, 0x5f, 0xeb, 0x2e, 0xab, 0x0f, 0xf4, 0xe5, 0xdf, 0x01, 0x00, 0xab, 0x20, 0x43, 0x62, 0x50,
+	0x05, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -684,6 +805,8 @@ type DataRWServiceClient interface {
 	ListFiles(ctx context.Context, in *ListFilesReq, opts ...grpc.CallOption) (*ListFilesResponse, error)
 	IsReady(ctx context.Context, in *IsReadyRequest, opts ...grpc.CallOption) (*IsReadyResponse, error)
 	CheckDir(ctx context.Context, in *CheckDirRequest, opts ...grpc.CallOption) (*CheckDirResponse, error)
+	// SplitFile returns the keys to split a file into ranges with similar sizes.
+	SplitFile(ctx context.Context, in *SplitFileRequest, opts ...grpc.CallOption) (*SplitFileResponse, error)
 }
 
 type dataRWServiceClient struct {
@@ -796,6 +919,15 @@ func (c *dataRWServiceClient) CheckDir(ctx context.Context, in *CheckDirRequest,
 	return out, nil
 }
 
+func (c *dataRWServiceClient) SplitFile(ctx context.Context, in *SplitFileRequest, opts ...grpc.CallOption) (*SplitFileResponse, error) {
+	out := new(SplitFileResponse)
+	err := c.cc.Invoke(ctx, "/pb.DataRWService/SplitFile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DataRWServiceServer is the server API for DataRWService service.
 type DataRWServiceServer interface {
 	ReadLines(*ReadLinesRequest, DataRWService_ReadLinesServer) error
@@ -804,6 +936,8 @@ type DataRWServiceServer interface {
 	ListFiles(context.Context, *ListFilesReq) (*ListFilesResponse, error)
 	IsReady(context.Context, *IsReadyRequest) (*IsReadyResponse, error)
 	CheckDir(context.Context, *CheckDirRequest) (*CheckDirResponse, error)
+	// SplitFile returns the keys to split a file into ranges with similar sizes.
+	SplitFile(context.Context, *SplitFileRequest) (*SplitFileResponse, error)
 }
 
 // UnimplementedDataRWServiceServer can be embedded to have forward compatible implementations.
@@ -828,6 +962,9 @@ func (*UnimplementedDataRWServiceServer) IsReady(ctx context.Context, req *IsRea
 func (*UnimplementedDataRWServiceServer) CheckDir(ctx context.Context, req *CheckDirRequest) (*CheckDirResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CheckDir not implemented")
 }
+func (*UnimplementedDataRWServiceServer) SplitFile(ctx context.Context, req *SplitFileRequest) (*SplitFileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SplitFile not implemented")
+}
 
 func RegisterDataRWServiceServer(s *grpc.Server, srv DataRWServiceServer) {
 	s.RegisterService(&_DataRWService_serviceDesc, srv)
@@ -952,6 +1089,24 @@ func _DataRWService_CheckDir_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DataRWService_SplitFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SplitFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataRWServiceServer).SplitFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.DataRWService/SplitFile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataRWServiceServer).SplitFile(ctx, req.(*SplitFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _DataRWService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "pb.DataRWService",
 	HandlerType: (*DataRWServiceServer)(nil),
@@ -972,6 +1127,10 @@ var _DataRWService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "CheckDir",
 			Handler:    _DataRWService_CheckDir_Handler,
 		},
+		{
+			MethodName: "SplitFile",
+			Handler:    _DataRWService_SplitFile_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1223,6 +1382,78 @@ func (m *ListFilesResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *SplitFileRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SplitFileRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SplitFileRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.SplitNum != 0 {
+		i = encodeVarintDatarw(dAtA, i, uint64(m.SplitNum))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.FileIdx != 0 {
+		i = encodeVarintDatarw(dAtA, i, uint64(m.FileIdx))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SplitFileResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SplitFileResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SplitFileResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.ErrMsg) > 0 {
+		i -= len(m.ErrMsg)
+		copy(dAtA[i:], m.ErrMsg)
+		i = encodeVarintDatarw(dAtA, i, uint64(len(m.ErrMsg)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.SplitKeys) > 0 {
+		for iNdEx := len(m.SplitKeys) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.SplitKeys[iNdEx])
+			copy(dAtA[i:], m.SplitKeys[iNdEx])
+			i = encodeVarintDatarw(dAtA, i, uint64(len(m.SplitKeys[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *ReadLinesRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -1243,6 +1474,13 @@ func (m *ReadLinesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.EndLineNo) > 0 {
+		i -= len(m.EndLineNo)
+		copy(dAtA[i:], m.EndLineNo)
+		i = encodeVarintDatarw(dAtA, i, uint64(len(m.EndLineNo)))
+		i--
+		dAtA[i] = 0x1a
+	}
 	if len(m.LineNo) > 0 {
 		i -= len(m.LineNo)
 		copy(dAtA[i:], m.LineNo)
@@ -1501,6 +1739,40 @@ func (m *ListFilesResponse) Size() (n int) {
 	return n
 }
 
+func (m *SplitFileRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.FileIdx != 0 {
+		n += 1 + sovDatarw(uint64(m.FileIdx))
+	}
+	if m.SplitNum != 0 {
+		n += 1 + sovDatarw(uint64(m.SplitNum))
+	}
+	return n
+}
+
+func (m *SplitFileResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.SplitKeys) > 0 {
+		for _, b := range m.SplitKeys {
+			l = len(b)
+			n += 1 + l + sovDatarw(uint64(l))
+		}
+	}
+	l = len(m.ErrMsg)
+	if l > 0 {
+		n += 1 + l + sovDatarw(uint64(l))
+	}
+	return n
+}
+
 func (m *ReadLinesRequest) Size() (n int) {
 	if m == nil {
 		return 0
@@ -1514,6 +1786,10 @@ func (m *ReadLinesRequest) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovDatarw(uint64(l))
 	}
+	l = len(m.EndLineNo)
+	if l > 0 {
+		n += 1 + l + sovDatarw(uint64(l))
+	}
 	return n
 }
 
@@ -2176,6 +2452,208 @@ func (m *ListFilesResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *SplitFileRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDatarw
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SplitFileRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SplitFileRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FileIdx", wireType)
+			}
+			m.FileIdx = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDatarw
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FileIdx |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SplitNum", wireType)
+			}
+			m.SplitNum = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDatarw
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SplitNum |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDatarw(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthDatarw
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SplitFileResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDatarw
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SplitFileResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SplitFileResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SplitKeys", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDatarw
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDatarw
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDatarw
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SplitKeys = append(m.SplitKeys, make([]byte, postIndex-iNdEx))
+			copy(m.SplitKeys[len(m.SplitKeys)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrMsg", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDatarw
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDatarw
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDatarw
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ErrMsg = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDatarw(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthDatarw
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *ReadLinesRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
@@ -2258,6 +2736,40 @@ func (m *ReadLinesRequest) Unmarshal(dAtA []byte) error {
 				m.LineNo = []byte{}
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EndLineNo", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDatarw
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDatarw
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDatarw
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EndLineNo = append(m.EndLineNo[:0], dAtA[iNdEx:postIndex]...)
+			if m.EndLineNo == nil {
+				m.EndLineNo = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipDatarw(dAtA[iNdEx:])
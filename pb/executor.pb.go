@@ -27,6 +27,33 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
+// DispatchState tells whether a confirmed task is initialized at once or
+// queued behind other tasks for the init concurrency of the executor.
+type DispatchState int32
+
+const (
+	DispatchState_DispatchStarted DispatchState = 0
+	DispatchState_DispatchQueued  DispatchState = 1
+)
+
+var DispatchState_name = map[int32]string{
+	0: "DispatchStarted",
+	1: "DispatchQueued",
+}
+
+var DispatchState_value = map[string]int32{
+	"DispatchStarted": 0,
+	"DispatchQueued":  1,
+}
+
+func (x DispatchState) String() string {
+	return proto.EnumName(DispatchState_name, int32(x))
+}
+
+func (DispatchState) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_12d1cdcda51e000f, []int{0}
+}
+
 type PreDispatchTaskRequest struct {
 	TaskTypeId int64  `protobuf:"varint,1,opt,name=task_type_id,json=taskTypeId,proto3" json:"task_type_id,omitempty"`
 	TaskConfig []byte `protobuf:"bytes,2,opt,name=task_config,json=taskConfig,proto3" json:"task_config,omitempty"`
@@ -35,6 +62,16 @@ type PreDispatchTaskRequest struct {
 	UserId     string `protobuf:"bytes,5,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	// request_id should be a UUID unique for each RPC call.
 	RequestId string `protobuf:"bytes,6,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// resources is the requirement of the task in all dimensions, the task
+	// is rejected if the executor doesn't declare enough capacity of them.
+	Resources map[string]int64 `protobuf:"bytes,7,rep,name=resources,proto3" json:"resources,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// env is the environment variables of the worker.
+	Env map[string]string `protobuf:"bytes,8,rep,name=env,proto3" json:"env,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// resource_ids are the external resources opened before the worker is
+	// initialized, the worker fails to start if any of them can't be opened.
+	ResourceIds []string `protobuf:"bytes,9,rep,name=resource_ids,json=resourceIds,proto3" json:"resource_ids,omitempty"`
+	TenantId    string   `protobuf:"bytes,10,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	ProjectId   string   `protobuf:"bytes,11,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
 }
 
 func (m *PreDispatchTaskRequest) Reset()         { *m = PreDispatchTaskRequest{} }
@@ -112,6 +149,43 @@ func (m *PreDispatchTaskRequest) GetRequestId() string {
 	return ""
 }
 
+func (m *PreDispatchTaskRequest) GetResources() map[string]int64 {
+	if m != nil {
+		return m.Resources
+	}
+	return nil
+}
+
+func (m *PreDispatchTaskRequest) GetEnv() map[string]string {
+	if m != nil {
+		return m.Env
+	}
+	return nil
+}
+
+func (m *PreDispatchTaskRequest) GetResourceIds() []string {
+	if m != nil {
+		return m.ResourceIds
+	}
+	return nil
+}
+
+func (m *PreDispatchTaskRequest) GetTenantId() string {
+	if m != nil {
+		return m.TenantId
+	}
+	return ""
+}
+
+func (m *PreDispatchTaskRequest) GetProjectId() string {
+	if m != nil {
+		return m.ProjectId
+	}
+	return ""
+}
+
+// PreDispatchTask fails with ResourceExhausted if the dispatch queue of the
+// executor is full, the task should be scheduled to another executor then.
 type PreDispatchTaskResponse struct {
 }
 
@@ -201,6 +275,9 @@ func (m *ConfirmDispatchTaskRequest) GetRequestId() string {
 }
 
 type ConfirmDispatchTaskResponse struct {
+	State DispatchState `protobuf:"varint,1,opt,name=state,proto3,enum=pb.DispatchState" json:"state,omitempty"`
+	// queue_position is the number of tasks ahead of the task if it is queued.
+	QueuePosition int64 `protobuf:"varint,2,opt,name=queue_position,json=queuePosition,proto3" json:"queue_position,omitempty"`
 }
 
 func (m *ConfirmDispatchTaskResponse) Reset()         { *m = ConfirmDispatchTaskResponse{} }
@@ -236,6 +313,92 @@ func (m *ConfirmDispatchTaskResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_ConfirmDispatchTaskResponse proto.InternalMessageInfo
 
+func (m *ConfirmDispatchTaskResponse) GetState() DispatchState {
+	if m != nil {
+		return m.State
+	}
+	return DispatchState_DispatchStarted
+}
+
+func (m *ConfirmDispatchTaskResponse) GetQueuePosition() int64 {
+	if m != nil {
+		return m.QueuePosition
+	}
+	return 0
+}
+
+type ShutdownRequest struct {
+}
+
+func (m *ShutdownRequest) Reset()         { *m = ShutdownRequest{} }
+func (m *ShutdownRequest) String() string { return proto.CompactTextString(m) }
+func (*ShutdownRequest) ProtoMessage()    {}
+func (*ShutdownRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_12d1cdcda51e000f, []int{4}
+}
+func (m *ShutdownRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ShutdownRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ShutdownRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ShutdownRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShutdownRequest.Merge(m, src)
+}
+func (m *ShutdownRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *ShutdownRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShutdownRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ShutdownRequest proto.InternalMessageInfo
+
+type ShutdownResponse struct {
+}
+
+func (m *ShutdownResponse) Reset()         { *m = ShutdownResponse{} }
+func (m *ShutdownResponse) String() string { return proto.CompactTextString(m) }
+func (*ShutdownResponse) ProtoMessage()    {}
+func (*ShutdownResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_12d1cdcda51e000f, []int{5}
+}
+func (m *ShutdownResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ShutdownResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ShutdownResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ShutdownResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShutdownResponse.Merge(m, src)
+}
+func (m *ShutdownResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *ShutdownResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShutdownResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ShutdownResponse proto.InternalMessageInfo
+
 type RemoveLocalResourceRequest struct {
 	ResourceId string `protobuf:"bytes,1,opt,name=resource_id,json=resourceId,proto3" json:"resource_id,omitempty"`
 	CreatorId  string `protobuf:"bytes,2,opt,name=creator_id,json=creatorId,proto3" json:"creator_id,omitempty"`
@@ -245,7 +408,7 @@ func (m *RemoveLocalResourceRequest) Reset()         { *m = RemoveLocalResourceR
 func (m *RemoveLocalResourceRequest) String() string { return proto.CompactTextString(m) }
 func (*RemoveLocalResourceRequest) ProtoMessage()    {}
 func (*RemoveLocalResourceRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_12d1cdcda51e000f, []int{4}
+	return fileDescriptor_12d1cdcda51e000f, []int{6}
 }
 func (m *RemoveLocalResourceRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -295,7 +458,7 @@ func (m *RemoveLocalResourceResponse) Reset()         { *m = RemoveLocalResource
 func (m *RemoveLocalResourceResponse) String() string { return proto.CompactTextString(m) }
 func (*RemoveLocalResourceResponse) ProtoMessage()    {}
 func (*RemoveLocalResourceResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_12d1cdcda51e000f, []int{5}
+	return fileDescriptor_12d1cdcda51e000f, []int{7}
 }
 func (m *RemoveLocalResourceResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -325,10 +488,15 @@ func (m *RemoveLocalResourceResponse) XXX_DiscardUnknown() {
 var xxx_messageInfo_RemoveLocalResourceResponse proto.InternalMessageInfo
 
 func init() {
+	proto.RegisterEnum("pb.DispatchState", DispatchState_name, DispatchState_value)
 	proto.RegisterType((*PreDispatchTaskRequest)(nil), "pb.PreDispatchTaskRequest")
+	proto.RegisterMapType((map[string]string)(nil), "pb.PreDispatchTaskRequest.EnvEntry")
+	proto.RegisterMapType((map[string]int64)(nil), "pb.PreDispatchTaskRequest.ResourcesEntry")
 	proto.RegisterType((*PreDispatchTaskResponse)(nil), "pb.PreDispatchTaskResponse")
 	proto.RegisterType((*ConfirmDispatchTaskRequest)(nil), "pb.ConfirmDispatchTaskRequest")
 	proto.RegisterType((*ConfirmDispatchTaskResponse)(nil), "pb.ConfirmDispatchTaskResponse")
+	proto.RegisterType((*ShutdownRequest)(nil), "pb.ShutdownRequest")
+	proto.RegisterType((*ShutdownResponse)(nil), "pb.ShutdownResponse")
 	proto.RegisterType((*RemoveLocalResourceRequest)(nil), "pb.RemoveLocalResourceRequest")
 	proto.RegisterType((*RemoveLocalResourceResponse)(nil), "pb.RemoveLocalResourceResponse")
 }
@@ -336,32 +504,47 @@ func init() {
 func init() { proto.RegisterFile("executor.proto", fileDescriptor_12d1cdcda51e000f) }
 
 var fileDescriptor_12d1cdcda51e000f = []byte{
-	// 396 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x93, 0x41, 0x6e, 0xda, 0x40,
-	0x14, 0x86, 0x3d, 0xd0, 0x52, 0x78, 0x50, 0x2a, 0x4d, 0xa5, 0x42, 0x8d, 0x30, 0x96, 0x57, 0xac,
-	0x58, 0xd0, 0x1b, 0xd0, 0x76, 0x61, 0x89, 0x45, 0x64, 0x58, 0xb0, 0x88, 0x84, 0x8c, 0xfd, 0x92,
-	0x58, 0x0e, 0x8c, 0x33, 0x33, 0x26, 0xe1, 0x16, 0x39, 0x4b, 0x4e, 0x91, 0x4d, 0x24, 0x96, 0x59,
-	0x46, 0x70, 0x91, 0xc8, 0x1e, 0x5b, 0x09, 0xc4, 0x64, 0xe9, 0xff, 0x9f, 0x79, 0xdf, 0xfb, 0x7f,
-	0xdb, 0xd0, 0xc4, 0x3b, 0xf4, 0x62, 0xc9, 0xf8, 0x20, 0xe2, 0x4c, 0x32, 0x5a, 0x8a, 0x16, 0xd6,
-	0x13, 0x81, 0x5f, 0x67, 0x1c, 0xff, 0x05, 0x22, 0x72, 0xa5, 0x77, 0x35, 0x75, 0x45, 0xe8, 0xe0,
-	0x4d, 0x8c, 0x42, 0x52, 0x13, 0x1a, 0xd2, 0x15, 0xe1, 0x5c, 0x6e, 0x22, 0x9c, 0x07, 0x7e, 0x9b,
-	0x98, 0xa4, 0x5f, 0x76, 0x20, 0xd1, 0xa6, 0x9b, 0x08, 0x6d, 0x9f, 0xf6, 0xa0, 0x9e, 0x9e, 0xf0,
-	0xd8, 0xea, 0x22, 0xb8, 0x6c, 0x97, 0x4c, 0xd2, 0x6f, 0xa8, 0x03, 0x7f, 0x53, 0x85, 0x76, 0xa0,
-	0xb6, 0x74, 0x85, 0x44, 0x9e, 0xdc, 0x2f, 0x9b, 0xa4, 0x5f, 0x73, 0xaa, 0x4a, 0xb0, 0xfd, 0xc4,
-	0xbc, 0x65, 0x3c, 0x54, 0xe6, 0x17, 0x65, 0x2a, 0xc1, 0xf6, 0x69, 0x0b, 0xbe, 0xc5, 0x42, 0x59,
-	0x5f, 0x53, 0xab, 0x92, 0x3c, 0xda, 0x3e, 0xed, 0x02, 0x70, 0xb5, 0x60, 0xe2, 0x55, 0x52, 0xaf,
-	0x96, 0x29, 0xb6, 0x6f, 0xfd, 0x86, 0xd6, 0x87, 0x38, 0x22, 0x62, 0x2b, 0x81, 0xd6, 0x0c, 0xf4,
-	0x74, 0x2d, 0xbe, 0x2c, 0x4a, 0x7b, 0xb0, 0x0d, 0x39, 0xda, 0xe6, 0x10, 0x5a, 0x3a, 0x86, 0x76,
-	0xa1, 0x53, 0x38, 0x39, 0x03, 0x9f, 0x83, 0xee, 0xe0, 0x92, 0xad, 0x71, 0xcc, 0x3c, 0xf7, 0xda,
-	0x41, 0xc1, 0x62, 0xee, 0x61, 0x0e, 0xee, 0x41, 0x9d, 0x67, 0xd2, 0x1b, 0x1a, 0x72, 0x49, 0xc1,
-	0x3d, 0x8e, 0xae, 0x64, 0xfc, 0x1d, 0x3c, 0x53, 0x14, 0xbc, 0x70, 0xba, 0x82, 0x0f, 0x1f, 0x08,
-	0x54, 0xff, 0x67, 0xef, 0x9d, 0x8e, 0xe1, 0xc7, 0x51, 0x3b, 0x54, 0x1f, 0x44, 0x8b, 0x41, 0xf1,
-	0x17, 0xa0, 0x77, 0x0a, 0xbd, 0x2c, 0x95, 0x46, 0x67, 0xf0, 0xb3, 0x20, 0x36, 0x35, 0x92, 0x5b,
-	0xa7, 0x9b, 0xd6, 0x7b, 0x27, 0xfd, 0x7c, 0xf2, 0xd0, 0x87, 0xef, 0x23, 0xce, 0x42, 0xe4, 0x13,
-	0xe4, 0xeb, 0xc0, 0x43, 0x3a, 0x81, 0xa6, 0x0a, 0x99, 0xe7, 0x53, 0x94, 0xd3, 0xb5, 0x2a, 0xca,
-	0x27, 0xc5, 0x58, 0xda, 0xa8, 0xfd, 0xb8, 0x33, 0xc8, 0x76, 0x67, 0x90, 0x97, 0x9d, 0x41, 0xee,
-	0xf7, 0x86, 0xb6, 0xdd, 0x1b, 0xda, 0xf3, 0xde, 0xd0, 0x16, 0x95, 0xf4, 0x07, 0xf9, 0xf3, 0x1a,
-	0x00, 0x00, 0xff, 0xff, 0x16, 0x16, 0x08, 0x8a, 0x32, 0x03, 0x00, 0x00,
+	// 638 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x54, 0xcd, 0x6e, 0xd3, 0x4c,
+	0x14, 0x8d, 0xe3, 0xfe, 0xc4, 0x37, 0x6d, 0x9a, 0x4e, 0xab, 0xaf, 0xf9, 0x1c, 0xd5, 0xc9, 0xe7,
+	0x4f, 0x88, 0xc0, 0x22, 0x8b, 0x20, 0xa0, 0x42, 0xac, 0x0a, 0x15, 0xb2, 0xd4, 0x45, 0x71, 0xba,
+	0xe8, 0x02, 0x29, 0x72, 0x3d, 0x17, 0x6a, 0xd2, 0x78, 0xdc, 0x99, 0x71, 0x4a, 0xde, 0x82, 0x67,
+	0xe0, 0x69, 0x58, 0x76, 0xc9, 0x12, 0xb5, 0x4b, 0x5e, 0x02, 0x8d, 0xc7, 0xa6, 0x49, 0x48, 0x60,
+	0x97, 0x39, 0xe7, 0xde, 0x33, 0xf7, 0xdc, 0x33, 0x31, 0xd4, 0xf0, 0x13, 0x86, 0xa9, 0x64, 0xbc,
+	0x9b, 0x70, 0x26, 0x19, 0x29, 0x27, 0xe7, 0xee, 0x97, 0x15, 0xf8, 0xe7, 0x84, 0xe3, 0xeb, 0x48,
+	0x24, 0x81, 0x0c, 0x2f, 0x4e, 0x03, 0x31, 0xf4, 0xf1, 0x2a, 0x45, 0x21, 0x49, 0x1b, 0x36, 0x64,
+	0x20, 0x86, 0x03, 0x39, 0x49, 0x70, 0x10, 0xd1, 0x86, 0xd1, 0x36, 0x3a, 0xa6, 0x0f, 0x0a, 0x3b,
+	0x9d, 0x24, 0xe8, 0x51, 0xd2, 0x82, 0x6a, 0x56, 0x11, 0xb2, 0xf8, 0x7d, 0xf4, 0xa1, 0x51, 0x6e,
+	0x1b, 0x9d, 0x0d, 0x5d, 0xf0, 0x2a, 0x43, 0x48, 0x13, 0xac, 0x51, 0x20, 0x24, 0x72, 0xd5, 0x6f,
+	0xb6, 0x8d, 0x8e, 0xe5, 0x57, 0x34, 0xe0, 0x51, 0x45, 0x5e, 0x33, 0x3e, 0xd4, 0xe4, 0x8a, 0x26,
+	0x35, 0xe0, 0x51, 0xb2, 0x07, 0xeb, 0xa9, 0xd0, 0xd4, 0x6a, 0x46, 0xad, 0xa9, 0xa3, 0x47, 0xc9,
+	0x3e, 0x00, 0xd7, 0x03, 0x2a, 0x6e, 0x2d, 0xe3, 0xac, 0x1c, 0xf1, 0x28, 0x79, 0x03, 0x16, 0x47,
+	0xc1, 0x52, 0x1e, 0xa2, 0x68, 0xac, 0xb7, 0xcd, 0x4e, 0xb5, 0xf7, 0xa8, 0x9b, 0x9c, 0x77, 0x17,
+	0x7b, 0xec, 0xfa, 0x45, 0xed, 0x51, 0x2c, 0xf9, 0xc4, 0xbf, 0xef, 0x25, 0x4f, 0xc1, 0xc4, 0x78,
+	0xdc, 0xa8, 0x64, 0x12, 0xff, 0xff, 0x41, 0xe2, 0x28, 0x1e, 0xeb, 0x66, 0x55, 0x4f, 0xfe, 0x83,
+	0x8d, 0x42, 0x63, 0x10, 0x51, 0xd1, 0xb0, 0xda, 0x66, 0xc7, 0xf2, 0xab, 0x05, 0xe6, 0x51, 0xa1,
+	0x7c, 0x4b, 0x8c, 0x83, 0x38, 0x33, 0x00, 0xda, 0xb7, 0x06, 0xb4, 0xbd, 0x84, 0xb3, 0x8f, 0x18,
+	0x66, 0x6c, 0x55, 0xdb, 0xcb, 0x11, 0x8f, 0xda, 0x2f, 0xa1, 0x36, 0x3b, 0x32, 0xa9, 0x83, 0x39,
+	0xc4, 0x49, 0x16, 0x8e, 0xe5, 0xab, 0x9f, 0x64, 0x17, 0x56, 0xc7, 0xc1, 0x65, 0x8a, 0x59, 0x1e,
+	0xa6, 0xaf, 0x0f, 0x2f, 0xca, 0x07, 0x86, 0xfd, 0x0c, 0x2a, 0xc5, 0xb4, 0x7f, 0xeb, 0xb3, 0xa6,
+	0xfa, 0xdc, 0x7f, 0x61, 0xef, 0x37, 0xf3, 0x22, 0x61, 0xb1, 0x40, 0xf7, 0x0c, 0xec, 0x2c, 0x6b,
+	0x3e, 0x5a, 0xf4, 0x84, 0x66, 0x22, 0x36, 0xe6, 0x22, 0x9e, 0x4d, 0xb2, 0x3c, 0x97, 0xa4, 0x3b,
+	0x82, 0xe6, 0x42, 0x65, 0x7d, 0x31, 0x79, 0x08, 0xab, 0x42, 0x06, 0x12, 0x33, 0xd9, 0x5a, 0x6f,
+	0x5b, 0x25, 0x54, 0x14, 0xf6, 0x15, 0xe1, 0x6b, 0x9e, 0x3c, 0x80, 0xda, 0x55, 0x8a, 0x29, 0x0e,
+	0x12, 0x26, 0x22, 0x19, 0xb1, 0x38, 0xdf, 0xcb, 0x66, 0x86, 0x9e, 0xe4, 0xa0, 0xbb, 0x0d, 0x5b,
+	0xfd, 0x8b, 0x54, 0x52, 0x76, 0x1d, 0xe7, 0xd3, 0xbb, 0x04, 0xea, 0xf7, 0x50, 0xee, 0xf7, 0x1d,
+	0xd8, 0x3e, 0x8e, 0xd8, 0x18, 0x8f, 0x59, 0x18, 0x5c, 0x16, 0x59, 0x14, 0x7e, 0x5b, 0x50, 0x9d,
+	0x4a, 0x3f, 0x77, 0x0c, 0xf7, 0xe1, 0x2b, 0xcf, 0x21, 0xc7, 0x40, 0x32, 0x3e, 0xe5, 0x39, 0x47,
+	0x3c, 0xea, 0xee, 0x43, 0x73, 0xa1, 0xba, 0xbe, 0xfc, 0xf1, 0x01, 0x6c, 0xce, 0x58, 0x24, 0x3b,
+	0xb0, 0x35, 0x05, 0x70, 0x89, 0xb4, 0x5e, 0x22, 0x04, 0x6a, 0x05, 0xf8, 0x56, 0x59, 0xa4, 0x75,
+	0xa3, 0xf7, 0xc3, 0x80, 0xca, 0x51, 0xfe, 0xef, 0x27, 0xc7, 0xb0, 0x35, 0x17, 0x27, 0xb1, 0x97,
+	0x3f, 0x70, 0xbb, 0xb9, 0x90, 0xcb, 0xf7, 0x51, 0x22, 0x67, 0xb0, 0xb3, 0x20, 0x27, 0xe2, 0xa8,
+	0xae, 0xe5, 0x4f, 0xc3, 0x6e, 0x2d, 0xe5, 0x7f, 0x29, 0x3f, 0x87, 0x4a, 0xb1, 0x7f, 0xb2, 0xa3,
+	0xca, 0xe7, 0x02, 0xb2, 0x77, 0x67, 0xc1, 0xa2, 0xb1, 0x47, 0x61, 0xf3, 0x90, 0xb3, 0x21, 0xf2,
+	0x3e, 0xf2, 0x71, 0x14, 0x22, 0xe9, 0x43, 0x4d, 0xef, 0xb5, 0x58, 0xa9, 0x1e, 0x6f, 0x79, 0x92,
+	0x76, 0x6b, 0x29, 0x5f, 0xdc, 0x72, 0xd8, 0xf8, 0x7a, 0xeb, 0x18, 0x37, 0xb7, 0x8e, 0xf1, 0xfd,
+	0xd6, 0x31, 0x3e, 0xdf, 0x39, 0xa5, 0x9b, 0x3b, 0xa7, 0xf4, 0xed, 0xce, 0x29, 0x9d, 0xaf, 0x65,
+	0xdf, 0xd7, 0x27, 0x3f, 0x07, 0x00, 0xde, 0x5f, 0xea, 0x06, 0x71, 0x05, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -378,6 +561,9 @@ const _ = grpc.SupportPackageIsVersion4
 type ExecutorClient interface {
 	PreDispatchTask(ctx context.Context, in *PreDispatchTaskRequest, opts ...grpc.CallOption) (*PreDispatchTaskResponse, error)
 	ConfirmDispatchTask(ctx context.Context, in *ConfirmDispatchTaskRequest, opts ...grpc.CallOption) (*ConfirmDispatchTaskResponse, error)
+	// Shutdown starts a graceful shutdown of the executor, it returns
+	// before the shutdown finishes.
+	Shutdown(ctx context.Context, in *ShutdownRequest, opts ...grpc.CallOption) (*ShutdownResponse, error)
 }
 
 type executorClient struct {
@@ -406,10 +592,22 @@ func (c *executorClient) ConfirmDispatchTask(ctx context.Context, in *ConfirmDis
 	return out, nil
 }
 
+func (c *executorClient) Shutdown(ctx context.Context, in *ShutdownRequest, opts ...grpc.CallOption) (*ShutdownResponse, error) {
+	out := new(ShutdownResponse)
+	err := c.cc.Invoke(ctx, "/pb.Executor/Shutdown", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ExecutorServer is the server API for Executor service.
 type ExecutorServer interface {
 	PreDispatchTask(context.Context, *PreDispatchTaskRequest) (*PreDispatchTaskResponse, error)
 	ConfirmDispatchTask(context.Context, *ConfirmDispatchTaskRequest) (*ConfirmDispatchTaskResponse, error)
+	// Shutdown starts a graceful shutdown of the executor, it returns
+	// before the shutdown finishes.
+	Shutdown(context.Context, *ShutdownRequest) (*ShutdownResponse, error)
 }
 
 // UnimplementedExecutorServer can be embedded to have forward compatible implementations.
@@ -422,6 +620,9 @@ func (*UnimplementedExecutorServer) PreDispatchTask(ctx context.Context, req *Pr
 func (*UnimplementedExecutorServer) ConfirmDispatchTask(ctx context.Context, req *ConfirmDispatchTaskRequest) (*ConfirmDispatchTaskResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ConfirmDispatchTask not implemented")
 }
+func (*UnimplementedExecutorServer) Shutdown(ctx context.Context, req *ShutdownRequest) (*ShutdownResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Shutdown not implemented")
+}
 
 func RegisterExecutorServer(s *grpc.Server, srv ExecutorServer) {
 	s.RegisterService(&_Executor_serviceDesc, srv)
@@ -463,6 +664,24 @@ func _Executor_ConfirmDispatchTask_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Executor_Shutdown_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShutdownRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExecutorServer).Shutdown(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Executor/Shutdown",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExecutorServer).Shutdown(ctx, req.(*ShutdownRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Executor_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "pb.Executor",
 	HandlerType: (*ExecutorServer)(nil),
@@ -475,6 +694,10 @@ var _Executor_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ConfirmDispatchTask",
 			Handler:    _Executor_ConfirmDispatchTask_Handler,
 		},
+		{
+			MethodName: "Shutdown",
+			Handler:    _Executor_Shutdown_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "executor.proto",
@@ -572,6 +795,65 @@ func (m *PreDispatchTaskRequest) MarshalToSizedBuffer(dAtA []byte) (int, error)
 	_ = i
 	var l int
 	_ = l
+	if len(m.ProjectId) > 0 {
+		i -= len(m.ProjectId)
+		copy(dAtA[i:], m.ProjectId)
+		i = encodeVarintExecutor(dAtA, i, uint64(len(m.ProjectId)))
+		i--
+		dAtA[i] = 0x5a
+	}
+	if len(m.TenantId) > 0 {
+		i -= len(m.TenantId)
+		copy(dAtA[i:], m.TenantId)
+		i = encodeVarintExecutor(dAtA, i, uint64(len(m.TenantId)))
+		i--
+		dAtA[i] = 0x52
+	}
+	if len(m.ResourceIds) > 0 {
+		for iNdEx := len(m.ResourceIds) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.ResourceIds[iNdEx])
+			copy(dAtA[i:], m.ResourceIds[iNdEx])
+			i = encodeVarintExecutor(dAtA, i, uint64(len(m.ResourceIds[iNdEx])))
+			i--
+			dAtA[i] = 0x4a
+		}
+	}
+	if len(m.Env) > 0 {
+		for k := range m.Env {
+			v := m.Env[k]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintExecutor(dAtA, i, uint64(len(v)))
+			i--
+			dAtA[i] = 0x12
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintExecutor(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintExecutor(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x42
+		}
+	}
+	if len(m.Resources) > 0 {
+		for k := range m.Resources {
+			v := m.Resources[k]
+			baseI := i
+			i = encodeVarintExecutor(dAtA, i, uint64(v))
+			i--
+			dAtA[i] = 0x10
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintExecutor(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintExecutor(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x3a
+		}
+	}
 	if len(m.RequestId) > 0 {
 		i -= len(m.RequestId)
 		copy(dAtA[i:], m.RequestId)
@@ -691,6 +973,62 @@ func (m *ConfirmDispatchTaskResponse) MarshalTo(dAtA []byte) (int, error) {
 }
 
 func (m *ConfirmDispatchTaskResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.QueuePosition != 0 {
+		i = encodeVarintExecutor(dAtA, i, uint64(m.QueuePosition))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.State != 0 {
+		i = encodeVarintExecutor(dAtA, i, uint64(m.State))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ShutdownRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ShutdownRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ShutdownRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *ShutdownResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ShutdownResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ShutdownResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
@@ -798,6 +1136,36 @@ func (m *PreDispatchTaskRequest) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovExecutor(uint64(l))
 	}
+	if len(m.Resources) > 0 {
+		for k, v := range m.Resources {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovExecutor(uint64(len(k))) + 1 + sovExecutor(uint64(v))
+			n += mapEntrySize + 1 + sovExecutor(uint64(mapEntrySize))
+		}
+	}
+	if len(m.Env) > 0 {
+		for k, v := range m.Env {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovExecutor(uint64(len(k))) + 1 + len(v) + sovExecutor(uint64(len(v)))
+			n += mapEntrySize + 1 + sovExecutor(uint64(mapEntrySize))
+		}
+	}
+	if len(m.ResourceIds) > 0 {
+		for _, s := range m.ResourceIds {
+			l = len(s)
+			n += 1 + l + sovExecutor(uint64(l))
+		}
+	}
+	l = len(m.TenantId)
+	if l > 0 {
+		n += 1 + l + sovExecutor(uint64(l))
+	}
+	l = len(m.ProjectId)
+	if l > 0 {
+		n += 1 + l + sovExecutor(uint64(l))
+	}
 	return n
 }
 
@@ -828,6 +1196,30 @@ func (m *ConfirmDispatchTaskRequest) Size() (n int) {
 }
 
 func (m *ConfirmDispatchTaskResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.State != 0 {
+		n += 1 + sovExecutor(uint64(m.State))
+	}
+	if m.QueuePosition != 0 {
+		n += 1 + sovExecutor(uint64(m.QueuePosition))
+	}
+	return n
+}
+
+func (m *ShutdownRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *ShutdownResponse) Size() (n int) {
 	if m == nil {
 		return 0
 	}
@@ -1078,6 +1470,342 @@ func (m *PreDispatchTaskRequest) Unmarshal(dAtA []byte) error {
 			}
 			m.RequestId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Resources", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExecutor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthExecutor
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthExecutor
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Resources == nil {
+				m.Resources = make(map[string]int64)
+			}
+			var mapkey string
+			var mapvalue int64
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowExecutor
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowExecutor
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthExecutor
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthExecutor
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowExecutor
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapvalue |= int64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipExecutor(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthExecutor
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Resources[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Env", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExecutor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthExecutor
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthExecutor
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Env == nil {
+				m.Env = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowExecutor
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowExecutor
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthExecutor
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthExecutor
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowExecutor
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthExecutor
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthExecutor
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipExecutor(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthExecutor
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Env[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ResourceIds", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExecutor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthExecutor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthExecutor
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ResourceIds = append(m.ResourceIds, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TenantId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExecutor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthExecutor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthExecutor
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TenantId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ProjectId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExecutor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthExecutor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthExecutor
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ProjectId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipExecutor(dAtA[iNdEx:])
@@ -1292,6 +2020,144 @@ func (m *ConfirmDispatchTaskResponse) Unmarshal(dAtA []byte) error {
 			return fmt.Errorf("proto: ConfirmDispatchTaskResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field State", wireType)
+			}
+			m.State = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExecutor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.State |= DispatchState(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QueuePosition", wireType)
+			}
+			m.QueuePosition = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExecutor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.QueuePosition |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipExecutor(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthExecutor
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ShutdownRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowExecutor
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ShutdownRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ShutdownRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipExecutor(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthExecutor
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ShutdownResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowExecutor
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ShutdownResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ShutdownResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
 		default:
 			iNdEx = preIndex
 			skippy, err := skipExecutor(dAtA[iNdEx:])
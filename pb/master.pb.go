@@ -58,6 +58,102 @@ func (JobType) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_f9c348dec43a6705, []int{0}
 }
 
+// JobTaskOp is an operation on the tasks of a job, it is not named TaskOp
+// which is registered by the protos of DM in the same "pb" package.
+type JobTaskOp int32
+
+const (
+	JobTaskOp_TaskOpPause  JobTaskOp = 0
+	JobTaskOp_TaskOpResume JobTaskOp = 1
+	JobTaskOp_TaskOpStop   JobTaskOp = 2
+)
+
+var JobTaskOp_name = map[int32]string{
+	0: "TaskOpPause",
+	1: "TaskOpResume",
+	2: "TaskOpStop",
+}
+
+var JobTaskOp_value = map[string]int32{
+	"TaskOpPause":  0,
+	"TaskOpResume": 1,
+	"TaskOpStop":   2,
+}
+
+func (x JobTaskOp) String() string {
+	return proto.EnumName(JobTaskOp_name, int32(x))
+}
+
+func (JobTaskOp) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{1}
+}
+
+// CatchUpPolicy decides how the fire times of a schedule missed while there
+// is no server master leader are handled.
+type CatchUpPolicy int32
+
+const (
+	// skip all the missed fires
+	CatchUpPolicy_CatchUpSkip CatchUpPolicy = 0
+	// submit one job for all the missed fires
+	CatchUpPolicy_CatchUpOnce CatchUpPolicy = 1
+	// submit one job for each missed fire
+	CatchUpPolicy_CatchUpAll CatchUpPolicy = 2
+)
+
+var CatchUpPolicy_name = map[int32]string{
+	0: "CatchUpSkip",
+	1: "CatchUpOnce",
+	2: "CatchUpAll",
+}
+
+var CatchUpPolicy_value = map[string]int32{
+	"CatchUpSkip": 0,
+	"CatchUpOnce": 1,
+	"CatchUpAll":  2,
+}
+
+func (x CatchUpPolicy) String() string {
+	return proto.EnumName(CatchUpPolicy_name, int32(x))
+}
+
+func (CatchUpPolicy) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{2}
+}
+
+type CordonState int32
+
+const (
+	// Uncordoned executors are scheduled normally
+	CordonState_Uncordoned CordonState = 0
+	// no new task is scheduled to a cordoned executor, its running tasks
+	// are not affected
+	CordonState_Cordoned CordonState = 1
+	// a blacklisted executor is treated as failed, its workers go offline
+	// and it can't register again until it is uncordoned
+	CordonState_Blacklisted CordonState = 2
+)
+
+var CordonState_name = map[int32]string{
+	0: "Uncordoned",
+	1: "Cordoned",
+	2: "Blacklisted",
+}
+
+var CordonState_value = map[string]int32{
+	"Uncordoned":  0,
+	"Cordoned":    1,
+	"Blacklisted": 2,
+}
+
+func (x CordonState) String() string {
+	return proto.EnumName(CordonState_name, int32(x))
+}
+
+func (CordonState) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{3}
+}
+
 type QueryJobResponse_JobStatus int32
 
 const (
@@ -92,7 +188,49 @@ func (x QueryJobResponse_JobStatus) String() string {
 }
 
 func (QueryJobResponse_JobStatus) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_f9c348dec43a6705, []int{5, 0}
+	return fileDescriptor_f9c348dec43a6705, []int{6, 0}
+}
+
+type MigrateMetaStoreRequest_Op int32
+
+const (
+	// Status queries the progress of the migration.
+	MigrateMetaStoreRequest_Status MigrateMetaStoreRequest_Op = 0
+	// Start copies the writes of the server master to the target
+	// metastore, and copies the existing records in the background.
+	MigrateMetaStoreRequest_Start MigrateMetaStoreRequest_Op = 1
+	// Verify compares the metastores and repairs the mismatched records
+	// of the target metastore.
+	MigrateMetaStoreRequest_Verify MigrateMetaStoreRequest_Op = 2
+	// Switch pauses the writes, verifies the metastores again, and then
+	// serves the reads and writes with the target metastore.
+	MigrateMetaStoreRequest_Switch MigrateMetaStoreRequest_Op = 3
+	// Abort stops copying the writes to the target metastore.
+	MigrateMetaStoreRequest_Abort MigrateMetaStoreRequest_Op = 4
+)
+
+var MigrateMetaStoreRequest_Op_name = map[int32]string{
+	0: "Status",
+	1: "Start",
+	2: "Verify",
+	3: "Switch",
+	4: "Abort",
+}
+
+var MigrateMetaStoreRequest_Op_value = map[string]int32{
+	"Status": 0,
+	"Start":  1,
+	"Verify": 2,
+	"Switch": 3,
+	"Abort":  4,
+}
+
+func (x MigrateMetaStoreRequest_Op) String() string {
+	return proto.EnumName(MigrateMetaStoreRequest_Op_name, int32(x))
+}
+
+func (MigrateMetaStoreRequest_Op) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{63, 0}
 }
 
 type HeartbeatRequest struct {
@@ -101,6 +239,15 @@ type HeartbeatRequest struct {
 	Status        int32  `protobuf:"varint,3,opt,name=status,proto3" json:"status,omitempty"`
 	Timestamp     uint64 `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
 	Ttl           uint64 `protobuf:"varint,5,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	// idle_evictable is set when the executor has run no worker for the
+	// configured idle period, so that it can be removed safely.
+	IdleEvictable bool `protobuf:"varint,6,opt,name=idle_evictable,json=idleEvictable,proto3" json:"idle_evictable,omitempty"`
+	// protocol_version is the protocol version of the executor, 0 means
+	// the executor is released before versions are negotiated.
+	ProtocolVersion int32 `protobuf:"varint,7,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`
+	// resource_usages is the usage of the executor in all resource
+	// dimensions, resource_usage is the cpu dimension of it.
+	ResourceUsages map[string]int64 `protobuf:"bytes,8,rep,name=resource_usages,json=resourceUsages,proto3" json:"resource_usages,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
 }
 
 func (m *HeartbeatRequest) Reset()         { *m = HeartbeatRequest{} }
@@ -171,10 +318,35 @@ func (m *HeartbeatRequest) GetTtl() uint64 {
 	return 0
 }
 
+func (m *HeartbeatRequest) GetIdleEvictable() bool {
+	if m != nil {
+		return m.IdleEvictable
+	}
+	return false
+}
+
+func (m *HeartbeatRequest) GetProtocolVersion() int32 {
+	if m != nil {
+		return m.ProtocolVersion
+	}
+	return 0
+}
+
+func (m *HeartbeatRequest) GetResourceUsages() map[string]int64 {
+	if m != nil {
+		return m.ResourceUsages
+	}
+	return nil
+}
+
 type HeartbeatResponse struct {
 	Err    *Error   `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
 	Leader string   `protobuf:"bytes,2,opt,name=leader,proto3" json:"leader,omitempty"`
 	Addrs  []string `protobuf:"bytes,3,rep,name=addrs,proto3" json:"addrs,omitempty"`
+	// cluster_protocol_version is the oldest protocol version of the
+	// server master and the executors, features of newer versions are
+	// disabled until all nodes are upgraded.
+	ClusterProtocolVersion int32 `protobuf:"varint,4,opt,name=cluster_protocol_version,json=clusterProtocolVersion,proto3" json:"cluster_protocol_version,omitempty"`
 }
 
 func (m *HeartbeatResponse) Reset()         { *m = HeartbeatResponse{} }
@@ -231,11 +403,31 @@ func (m *HeartbeatResponse) GetAddrs() []string {
 	return nil
 }
 
+func (m *HeartbeatResponse) GetClusterProtocolVersion() int32 {
+	if m != nil {
+		return m.ClusterProtocolVersion
+	}
+	return 0
+}
+
 type SubmitJobRequest struct {
 	Tp     JobType `protobuf:"varint,1,opt,name=tp,proto3,enum=pb.JobType" json:"tp,omitempty"`
 	Config []byte  `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`
 	// User name, token, etc...
 	User string `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	// TODO: Resource Limit
+	// template_id submits a job from a registered template, tp and config
+	// are ignored and taken from the template rendered with template_params.
+	TemplateId     string            `protobuf:"bytes,4,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
+	TemplateParams map[string]string `protobuf:"bytes,5,rep,name=template_params,json=templateParams,proto3" json:"template_params,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// max_create_worker_concurrency limits the workers being created by
+	// the job master at the same time, 0 means the default limit.
+	MaxCreateWorkerConcurrency int32 `protobuf:"varint,6,opt,name=max_create_worker_concurrency,json=maxCreateWorkerConcurrency,proto3" json:"max_create_worker_concurrency,omitempty"`
+	// resource_spec declares the workers of the job, the capacity of the
+	// min workers is reserved when the job is submitted.
+	ResourceSpec *JobResourceSpec `protobuf:"bytes,7,opt,name=resource_spec,json=resourceSpec,proto3" json:"resource_spec,omitempty"`
+	// labels are arbitrary key/value pairs to group jobs, such as team=payment.
+	Labels map[string]string `protobuf:"bytes,8,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
 func (m *SubmitJobRequest) Reset()         { *m = SubmitJobRequest{} }
@@ -292,6 +484,102 @@ func (m *SubmitJobRequest) GetUser() string {
 	return ""
 }
 
+func (m *SubmitJobRequest) GetTemplateId() string {
+	if m != nil {
+		return m.TemplateId
+	}
+	return ""
+}
+
+func (m *SubmitJobRequest) GetTemplateParams() map[string]string {
+	if m != nil {
+		return m.TemplateParams
+	}
+	return nil
+}
+
+func (m *SubmitJobRequest) GetMaxCreateWorkerConcurrency() int32 {
+	if m != nil {
+		return m.MaxCreateWorkerConcurrency
+	}
+	return 0
+}
+
+func (m *SubmitJobRequest) GetResourceSpec() *JobResourceSpec {
+	if m != nil {
+		return m.ResourceSpec
+	}
+	return nil
+}
+
+func (m *SubmitJobRequest) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+type JobResourceSpec struct {
+	MinWorkers int32 `protobuf:"varint,1,opt,name=min_workers,json=minWorkers,proto3" json:"min_workers,omitempty"`
+	// max_workers limits the workers granted by ScaleUpJob, 0 means no limit.
+	MaxWorkers      int32            `protobuf:"varint,2,opt,name=max_workers,json=maxWorkers,proto3" json:"max_workers,omitempty"`
+	WorkerResources map[string]int64 `protobuf:"bytes,3,rep,name=worker_resources,json=workerResources,proto3" json:"worker_resources,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+}
+
+func (m *JobResourceSpec) Reset()         { *m = JobResourceSpec{} }
+func (m *JobResourceSpec) String() string { return proto.CompactTextString(m) }
+func (*JobResourceSpec) ProtoMessage()    {}
+func (*JobResourceSpec) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{3}
+}
+func (m *JobResourceSpec) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *JobResourceSpec) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_JobResourceSpec.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *JobResourceSpec) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobResourceSpec.Merge(m, src)
+}
+func (m *JobResourceSpec) XXX_Size() int {
+	return m.Size()
+}
+func (m *JobResourceSpec) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobResourceSpec.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JobResourceSpec proto.InternalMessageInfo
+
+func (m *JobResourceSpec) GetMinWorkers() int32 {
+	if m != nil {
+		return m.MinWorkers
+	}
+	return 0
+}
+
+func (m *JobResourceSpec) GetMaxWorkers() int32 {
+	if m != nil {
+		return m.MaxWorkers
+	}
+	return 0
+}
+
+func (m *JobResourceSpec) GetWorkerResources() map[string]int64 {
+	if m != nil {
+		return m.WorkerResources
+	}
+	return nil
+}
+
 type QueryJobRequest struct {
 	JobId string `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
 }
@@ -300,7 +588,7 @@ func (m *QueryJobRequest) Reset()         { *m = QueryJobRequest{} }
 func (m *QueryJobRequest) String() string { return proto.CompactTextString(m) }
 func (*QueryJobRequest) ProtoMessage()    {}
 func (*QueryJobRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f9c348dec43a6705, []int{3}
+	return fileDescriptor_f9c348dec43a6705, []int{4}
 }
 func (m *QueryJobRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -344,13 +632,16 @@ type WorkerInfo struct {
 	IsTombstone bool   `protobuf:"varint,5,opt,name=is_tombstone,json=isTombstone,proto3" json:"is_tombstone,omitempty"`
 	LastHbTime  int64  `protobuf:"varint,6,opt,name=last_hb_time,json=lastHbTime,proto3" json:"last_hb_time,omitempty"`
 	Workload    int64  `protobuf:"varint,7,opt,name=workload,proto3" json:"workload,omitempty"`
+	// creating_workers is the number of workers being created by the
+	// worker, which is reported by job masters in heartbeats.
+	CreatingWorkers int32 `protobuf:"varint,8,opt,name=creating_workers,json=creatingWorkers,proto3" json:"creating_workers,omitempty"`
 }
 
 func (m *WorkerInfo) Reset()         { *m = WorkerInfo{} }
 func (m *WorkerInfo) String() string { return proto.CompactTextString(m) }
 func (*WorkerInfo) ProtoMessage()    {}
 func (*WorkerInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f9c348dec43a6705, []int{4}
+	return fileDescriptor_f9c348dec43a6705, []int{5}
 }
 func (m *WorkerInfo) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -428,19 +719,30 @@ func (m *WorkerInfo) GetWorkload() int64 {
 	return 0
 }
 
+func (m *WorkerInfo) GetCreatingWorkers() int32 {
+	if m != nil {
+		return m.CreatingWorkers
+	}
+	return 0
+}
+
 type QueryJobResponse struct {
 	Tp            int64                      `protobuf:"varint,1,opt,name=tp,proto3" json:"tp,omitempty"`
 	Config        []byte                     `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`
 	Status        QueryJobResponse_JobStatus `protobuf:"varint,3,opt,name=status,proto3,enum=pb.QueryJobResponse_JobStatus" json:"status,omitempty"`
 	JobMasterInfo *WorkerInfo                `protobuf:"bytes,4,opt,name=job_master_info,json=jobMasterInfo,proto3" json:"job_master_info,omitempty"`
 	Err           *Error                     `protobuf:"bytes,5,opt,name=err,proto3" json:"err,omitempty"`
+	// max_create_worker_concurrency is the limit in the job spec, 0 means
+	// the default limit.
+	MaxCreateWorkerConcurrency int32             `protobuf:"varint,6,opt,name=max_create_worker_concurrency,json=maxCreateWorkerConcurrency,proto3" json:"max_create_worker_concurrency,omitempty"`
+	Labels                     map[string]string `protobuf:"bytes,7,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
 func (m *QueryJobResponse) Reset()         { *m = QueryJobResponse{} }
 func (m *QueryJobResponse) String() string { return proto.CompactTextString(m) }
 func (*QueryJobResponse) ProtoMessage()    {}
 func (*QueryJobResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f9c348dec43a6705, []int{5}
+	return fileDescriptor_f9c348dec43a6705, []int{6}
 }
 func (m *QueryJobResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -504,23 +806,45 @@ func (m *QueryJobResponse) GetErr() *Error {
 	return nil
 }
 
-type CancelJobRequest struct {
-	JobId    int32  `protobuf:"varint,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"` // Deprecated: Do not use.
-	JobIdStr string `protobuf:"bytes,2,opt,name=job_id_str,json=jobIdStr,proto3" json:"job_id_str,omitempty"`
+func (m *QueryJobResponse) GetMaxCreateWorkerConcurrency() int32 {
+	if m != nil {
+		return m.MaxCreateWorkerConcurrency
+	}
+	return 0
 }
 
-func (m *CancelJobRequest) Reset()         { *m = CancelJobRequest{} }
-func (m *CancelJobRequest) String() string { return proto.CompactTextString(m) }
-func (*CancelJobRequest) ProtoMessage()    {}
-func (*CancelJobRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f9c348dec43a6705, []int{6}
+func (m *QueryJobResponse) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
 }
-func (m *CancelJobRequest) XXX_Unmarshal(b []byte) error {
+
+// QueryJobsRequest selects the jobs to list, label_selector consists of
+// comma separated requirements such as "team=payment,env!=prod,tier,!canary",
+// and an empty project_id means all projects.
+type QueryJobsRequest struct {
+	ProjectId     string `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	LabelSelector string `protobuf:"bytes,2,opt,name=label_selector,json=labelSelector,proto3" json:"label_selector,omitempty"`
+	// status_codes are the status codes of the job metadata, which are 1
+	// (uninit), 2 (init), 3 (finished) and 4 (stopped). Empty means all.
+	StatusCodes []int32 `protobuf:"varint,3,rep,packed,name=status_codes,json=statusCodes,proto3" json:"status_codes,omitempty"`
+	// types are the worker types of the job masters, empty means all.
+	Types []int64 `protobuf:"varint,4,rep,packed,name=types,proto3" json:"types,omitempty"`
+}
+
+func (m *QueryJobsRequest) Reset()         { *m = QueryJobsRequest{} }
+func (m *QueryJobsRequest) String() string { return proto.CompactTextString(m) }
+func (*QueryJobsRequest) ProtoMessage()    {}
+func (*QueryJobsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{7}
+}
+func (m *QueryJobsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *CancelJobRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *QueryJobsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_CancelJobRequest.Marshal(b, m, deterministic)
+		return xxx_messageInfo_QueryJobsRequest.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -530,50 +854,66 @@ func (m *CancelJobRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, er
 		return b[:n], nil
 	}
 }
-func (m *CancelJobRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CancelJobRequest.Merge(m, src)
+func (m *QueryJobsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryJobsRequest.Merge(m, src)
 }
-func (m *CancelJobRequest) XXX_Size() int {
+func (m *QueryJobsRequest) XXX_Size() int {
 	return m.Size()
 }
-func (m *CancelJobRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_CancelJobRequest.DiscardUnknown(m)
+func (m *QueryJobsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryJobsRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_CancelJobRequest proto.InternalMessageInfo
+var xxx_messageInfo_QueryJobsRequest proto.InternalMessageInfo
 
-// Deprecated: Do not use.
-func (m *CancelJobRequest) GetJobId() int32 {
+func (m *QueryJobsRequest) GetProjectId() string {
 	if m != nil {
-		return m.JobId
+		return m.ProjectId
 	}
-	return 0
+	return ""
 }
 
-func (m *CancelJobRequest) GetJobIdStr() string {
+func (m *QueryJobsRequest) GetLabelSelector() string {
 	if m != nil {
-		return m.JobIdStr
+		return m.LabelSelector
 	}
 	return ""
 }
 
-type PauseJobRequest struct {
-	JobId    int32  `protobuf:"varint,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"` // Deprecated: Do not use.
-	JobIdStr string `protobuf:"bytes,2,opt,name=job_id_str,json=jobIdStr,proto3" json:"job_id_str,omitempty"`
+func (m *QueryJobsRequest) GetStatusCodes() []int32 {
+	if m != nil {
+		return m.StatusCodes
+	}
+	return nil
 }
 
-func (m *PauseJobRequest) Reset()         { *m = PauseJobRequest{} }
-func (m *PauseJobRequest) String() string { return proto.CompactTextString(m) }
-func (*PauseJobRequest) ProtoMessage()    {}
-func (*PauseJobRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f9c348dec43a6705, []int{7}
+func (m *QueryJobsRequest) GetTypes() []int64 {
+	if m != nil {
+		return m.Types
+	}
+	return nil
 }
-func (m *PauseJobRequest) XXX_Unmarshal(b []byte) error {
+
+type JobInfo struct {
+	JobId     string                     `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	ProjectId string                     `protobuf:"bytes,2,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	Tp        int64                      `protobuf:"varint,3,opt,name=tp,proto3" json:"tp,omitempty"`
+	Status    QueryJobResponse_JobStatus `protobuf:"varint,4,opt,name=status,proto3,enum=pb.QueryJobResponse_JobStatus" json:"status,omitempty"`
+	Labels    map[string]string          `protobuf:"bytes,5,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (m *JobInfo) Reset()         { *m = JobInfo{} }
+func (m *JobInfo) String() string { return proto.CompactTextString(m) }
+func (*JobInfo) ProtoMessage()    {}
+func (*JobInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{8}
+}
+func (m *JobInfo) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *PauseJobRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *JobInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_PauseJobRequest.Marshal(b, m, deterministic)
+		return xxx_messageInfo_JobInfo.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -583,51 +923,70 @@ func (m *PauseJobRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, err
 		return b[:n], nil
 	}
 }
-func (m *PauseJobRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PauseJobRequest.Merge(m, src)
+func (m *JobInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobInfo.Merge(m, src)
 }
-func (m *PauseJobRequest) XXX_Size() int {
+func (m *JobInfo) XXX_Size() int {
 	return m.Size()
 }
-func (m *PauseJobRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_PauseJobRequest.DiscardUnknown(m)
+func (m *JobInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobInfo.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_PauseJobRequest proto.InternalMessageInfo
+var xxx_messageInfo_JobInfo proto.InternalMessageInfo
 
-// Deprecated: Do not use.
-func (m *PauseJobRequest) GetJobId() int32 {
+func (m *JobInfo) GetJobId() string {
 	if m != nil {
 		return m.JobId
 	}
-	return 0
+	return ""
 }
 
-func (m *PauseJobRequest) GetJobIdStr() string {
+func (m *JobInfo) GetProjectId() string {
 	if m != nil {
-		return m.JobIdStr
+		return m.ProjectId
 	}
 	return ""
 }
 
-type SubmitJobResponse struct {
-	Err      *Error `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
-	JobId    int32  `protobuf:"varint,2,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"` // Deprecated: Do not use.
-	JobIdStr string `protobuf:"bytes,3,opt,name=job_id_str,json=jobIdStr,proto3" json:"job_id_str,omitempty"`
+func (m *JobInfo) GetTp() int64 {
+	if m != nil {
+		return m.Tp
+	}
+	return 0
 }
 
-func (m *SubmitJobResponse) Reset()         { *m = SubmitJobResponse{} }
-func (m *SubmitJobResponse) String() string { return proto.CompactTextString(m) }
-func (*SubmitJobResponse) ProtoMessage()    {}
-func (*SubmitJobResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f9c348dec43a6705, []int{8}
+func (m *JobInfo) GetStatus() QueryJobResponse_JobStatus {
+	if m != nil {
+		return m.Status
+	}
+	return QueryJobResponse_init
 }
-func (m *SubmitJobResponse) XXX_Unmarshal(b []byte) error {
+
+func (m *JobInfo) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+type QueryJobsResponse struct {
+	Err  *Error     `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
+	Jobs []*JobInfo `protobuf:"bytes,2,rep,name=jobs,proto3" json:"jobs,omitempty"`
+}
+
+func (m *QueryJobsResponse) Reset()         { *m = QueryJobsResponse{} }
+func (m *QueryJobsResponse) String() string { return proto.CompactTextString(m) }
+func (*QueryJobsResponse) ProtoMessage()    {}
+func (*QueryJobsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{9}
+}
+func (m *QueryJobsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *SubmitJobResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *QueryJobsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_SubmitJobResponse.Marshal(b, m, deterministic)
+		return xxx_messageInfo_QueryJobsResponse.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -637,56 +996,58 @@ func (m *SubmitJobResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, e
 		return b[:n], nil
 	}
 }
-func (m *SubmitJobResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SubmitJobResponse.Merge(m, src)
+func (m *QueryJobsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryJobsResponse.Merge(m, src)
 }
-func (m *SubmitJobResponse) XXX_Size() int {
+func (m *QueryJobsResponse) XXX_Size() int {
 	return m.Size()
 }
-func (m *SubmitJobResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_SubmitJobResponse.DiscardUnknown(m)
+func (m *QueryJobsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryJobsResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SubmitJobResponse proto.InternalMessageInfo
+var xxx_messageInfo_QueryJobsResponse proto.InternalMessageInfo
 
-func (m *SubmitJobResponse) GetErr() *Error {
+func (m *QueryJobsResponse) GetErr() *Error {
 	if m != nil {
 		return m.Err
 	}
 	return nil
 }
 
-// Deprecated: Do not use.
-func (m *SubmitJobResponse) GetJobId() int32 {
+func (m *QueryJobsResponse) GetJobs() []*JobInfo {
 	if m != nil {
-		return m.JobId
+		return m.Jobs
 	}
-	return 0
+	return nil
 }
 
-func (m *SubmitJobResponse) GetJobIdStr() string {
-	if m != nil {
-		return m.JobIdStr
-	}
-	return ""
-}
-
-type PauseJobResponse struct {
-	Err *Error `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
-}
-
-func (m *PauseJobResponse) Reset()         { *m = PauseJobResponse{} }
-func (m *PauseJobResponse) String() string { return proto.CompactTextString(m) }
-func (*PauseJobResponse) ProtoMessage()    {}
-func (*PauseJobResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f9c348dec43a6705, []int{9}
+// ListWorkersRequest selects the workers of a job, empty status_codes or
+// executor_id means no filtering on them. The workers are sorted by their
+// IDs, page_token is the next_page_token of the previous page, and empty
+// means the first page.
+type ListWorkersRequest struct {
+	JobId       string  `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	StatusCodes []int32 `protobuf:"varint,2,rep,packed,name=status_codes,json=statusCodes,proto3" json:"status_codes,omitempty"`
+	ExecutorId  string  `protobuf:"bytes,3,opt,name=executor_id,json=executorId,proto3" json:"executor_id,omitempty"`
+	// page_size is the max number of workers in a page, 0 means the default
+	// page size.
+	PageSize  int32  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken string `protobuf:"bytes,5,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+}
+
+func (m *ListWorkersRequest) Reset()         { *m = ListWorkersRequest{} }
+func (m *ListWorkersRequest) String() string { return proto.CompactTextString(m) }
+func (*ListWorkersRequest) ProtoMessage()    {}
+func (*ListWorkersRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{10}
 }
-func (m *PauseJobResponse) XXX_Unmarshal(b []byte) error {
+func (m *ListWorkersRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *PauseJobResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *ListWorkersRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_PauseJobResponse.Marshal(b, m, deterministic)
+		return xxx_messageInfo_ListWorkersRequest.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -696,89 +1057,77 @@ func (m *PauseJobResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, er
 		return b[:n], nil
 	}
 }
-func (m *PauseJobResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PauseJobResponse.Merge(m, src)
+func (m *ListWorkersRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListWorkersRequest.Merge(m, src)
 }
-func (m *PauseJobResponse) XXX_Size() int {
+func (m *ListWorkersRequest) XXX_Size() int {
 	return m.Size()
 }
-func (m *PauseJobResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_PauseJobResponse.DiscardUnknown(m)
+func (m *ListWorkersRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListWorkersRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_PauseJobResponse proto.InternalMessageInfo
+var xxx_messageInfo_ListWorkersRequest proto.InternalMessageInfo
 
-func (m *PauseJobResponse) GetErr() *Error {
+func (m *ListWorkersRequest) GetJobId() string {
 	if m != nil {
-		return m.Err
+		return m.JobId
 	}
-	return nil
+	return ""
 }
 
-type CancelJobResponse struct {
-	Err *Error `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
+func (m *ListWorkersRequest) GetStatusCodes() []int32 {
+	if m != nil {
+		return m.StatusCodes
+	}
+	return nil
 }
 
-func (m *CancelJobResponse) Reset()         { *m = CancelJobResponse{} }
-func (m *CancelJobResponse) String() string { return proto.CompactTextString(m) }
-func (*CancelJobResponse) ProtoMessage()    {}
-func (*CancelJobResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f9c348dec43a6705, []int{10}
-}
-func (m *CancelJobResponse) XXX_Unmarshal(b []byte) error {
-	return m.Unmarshal(b)
-}
-func (m *CancelJobResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	if deterministic {
-		return xxx_messageInfo_CancelJobResponse.Marshal(b, m, deterministic)
-	} else {
-		b = b[:cap(b)]
-		n, err := m.MarshalToSizedBuffer(b)
-		if err != nil {
-			return nil, err
-		}
-		return b[:n], nil
+func (m *ListWorkersRequest) GetExecutorId() string {
+	if m != nil {
+		return m.ExecutorId
 	}
+	return ""
 }
-func (m *CancelJobResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CancelJobResponse.Merge(m, src)
-}
-func (m *CancelJobResponse) XXX_Size() int {
-	return m.Size()
-}
-func (m *CancelJobResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_CancelJobResponse.DiscardUnknown(m)
-}
-
-var xxx_messageInfo_CancelJobResponse proto.InternalMessageInfo
 
-func (m *CancelJobResponse) GetErr() *Error {
+func (m *ListWorkersRequest) GetPageSize() int32 {
 	if m != nil {
-		return m.Err
+		return m.PageSize
 	}
-	return nil
+	return 0
 }
 
-type RegisterExecutorRequest struct {
-	// dm need 'worker-name' to locate the worker.
-	// TODO: Do we really need a "worker name"? Can we use address to identify an executor?
-	Address    string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
-	Version    string `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
-	Capability int64  `protobuf:"varint,3,opt,name=capability,proto3" json:"capability,omitempty"`
+func (m *ListWorkersRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
 }
 
-func (m *RegisterExecutorRequest) Reset()         { *m = RegisterExecutorRequest{} }
-func (m *RegisterExecutorRequest) String() string { return proto.CompactTextString(m) }
-func (*RegisterExecutorRequest) ProtoMessage()    {}
-func (*RegisterExecutorRequest) Descriptor() ([]byte, []int) {
+type WorkerSummary struct {
+	Id         string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ExecutorId string `protobuf:"bytes,2,opt,name=executor_id,json=executorId,proto3" json:"executor_id,omitempty"`
+	// state is the state of the worker kept by the job master, such as
+	// created, normal, offline and tombstone.
+	State        string `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	StatusCode   int32  `protobuf:"varint,4,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	ErrorMessage string `protobuf:"bytes,5,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	// heartbeat_time is the unix time in milliseconds of the last heartbeat.
+	HeartbeatTime int64 `protobuf:"varint,6,opt,name=heartbeat_time,json=heartbeatTime,proto3" json:"heartbeat_time,omitempty"`
+}
+
+func (m *WorkerSummary) Reset()         { *m = WorkerSummary{} }
+func (m *WorkerSummary) String() string { return proto.CompactTextString(m) }
+func (*WorkerSummary) ProtoMessage()    {}
+func (*WorkerSummary) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f9c348dec43a6705, []int{11}
 }
-func (m *RegisterExecutorRequest) XXX_Unmarshal(b []byte) error {
+func (m *WorkerSummary) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *RegisterExecutorRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *WorkerSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_RegisterExecutorRequest.Marshal(b, m, deterministic)
+		return xxx_messageInfo_WorkerSummary.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -788,56 +1137,79 @@ func (m *RegisterExecutorRequest) XXX_Marshal(b []byte, deterministic bool) ([]b
 		return b[:n], nil
 	}
 }
-func (m *RegisterExecutorRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_RegisterExecutorRequest.Merge(m, src)
+func (m *WorkerSummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WorkerSummary.Merge(m, src)
 }
-func (m *RegisterExecutorRequest) XXX_Size() int {
+func (m *WorkerSummary) XXX_Size() int {
 	return m.Size()
 }
-func (m *RegisterExecutorRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_RegisterExecutorRequest.DiscardUnknown(m)
+func (m *WorkerSummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_WorkerSummary.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_RegisterExecutorRequest proto.InternalMessageInfo
+var xxx_messageInfo_WorkerSummary proto.InternalMessageInfo
 
-func (m *RegisterExecutorRequest) GetAddress() string {
+func (m *WorkerSummary) GetId() string {
 	if m != nil {
-		return m.Address
+		return m.Id
 	}
 	return ""
 }
 
-func (m *RegisterExecutorRequest) GetVersion() string {
+func (m *WorkerSummary) GetExecutorId() string {
 	if m != nil {
-		return m.Version
+		return m.ExecutorId
 	}
 	return ""
 }
 
-func (m *RegisterExecutorRequest) GetCapability() int64 {
+func (m *WorkerSummary) GetState() string {
 	if m != nil {
-		return m.Capability
+		return m.State
+	}
+	return ""
+}
+
+func (m *WorkerSummary) GetStatusCode() int32 {
+	if m != nil {
+		return m.StatusCode
 	}
 	return 0
 }
 
-type RegisterExecutorResponse struct {
-	Err        *Error `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
-	ExecutorId string `protobuf:"bytes,2,opt,name=executor_id,json=executorId,proto3" json:"executor_id,omitempty"`
+func (m *WorkerSummary) GetErrorMessage() string {
+	if m != nil {
+		return m.ErrorMessage
+	}
+	return ""
 }
 
-func (m *RegisterExecutorResponse) Reset()         { *m = RegisterExecutorResponse{} }
-func (m *RegisterExecutorResponse) String() string { return proto.CompactTextString(m) }
-func (*RegisterExecutorResponse) ProtoMessage()    {}
-func (*RegisterExecutorResponse) Descriptor() ([]byte, []int) {
+func (m *WorkerSummary) GetHeartbeatTime() int64 {
+	if m != nil {
+		return m.HeartbeatTime
+	}
+	return 0
+}
+
+type ListWorkersResponse struct {
+	Err     *Error           `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
+	Workers []*WorkerSummary `protobuf:"bytes,2,rep,name=workers,proto3" json:"workers,omitempty"`
+	// next_page_token is empty if there are no more workers.
+	NextPageToken string `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+}
+
+func (m *ListWorkersResponse) Reset()         { *m = ListWorkersResponse{} }
+func (m *ListWorkersResponse) String() string { return proto.CompactTextString(m) }
+func (*ListWorkersResponse) ProtoMessage()    {}
+func (*ListWorkersResponse) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f9c348dec43a6705, []int{12}
 }
-func (m *RegisterExecutorResponse) XXX_Unmarshal(b []byte) error {
+func (m *ListWorkersResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *RegisterExecutorResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *ListWorkersResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_RegisterExecutorResponse.Marshal(b, m, deterministic)
+		return xxx_messageInfo_ListWorkersResponse.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -847,50 +1219,56 @@ func (m *RegisterExecutorResponse) XXX_Marshal(b []byte, deterministic bool) ([]
 		return b[:n], nil
 	}
 }
-func (m *RegisterExecutorResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_RegisterExecutorResponse.Merge(m, src)
+func (m *ListWorkersResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListWorkersResponse.Merge(m, src)
 }
-func (m *RegisterExecutorResponse) XXX_Size() int {
+func (m *ListWorkersResponse) XXX_Size() int {
 	return m.Size()
 }
-func (m *RegisterExecutorResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_RegisterExecutorResponse.DiscardUnknown(m)
+func (m *ListWorkersResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListWorkersResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_RegisterExecutorResponse proto.InternalMessageInfo
+var xxx_messageInfo_ListWorkersResponse proto.InternalMessageInfo
 
-func (m *RegisterExecutorResponse) GetErr() *Error {
+func (m *ListWorkersResponse) GetErr() *Error {
 	if m != nil {
 		return m.Err
 	}
 	return nil
 }
 
-func (m *RegisterExecutorResponse) GetExecutorId() string {
+func (m *ListWorkersResponse) GetWorkers() []*WorkerSummary {
 	if m != nil {
-		return m.ExecutorId
+		return m.Workers
+	}
+	return nil
+}
+
+func (m *ListWorkersResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
 	}
 	return ""
 }
 
-type ScheduleTaskRequest struct {
-	TaskId               string   `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
-	Cost                 int64    `protobuf:"varint,2,opt,name=cost,proto3" json:"cost,omitempty"`
-	ResourceRequirements []string `protobuf:"bytes,3,rep,name=resource_requirements,json=resourceRequirements,proto3" json:"resource_requirements,omitempty"`
+type CancelJobRequest struct {
+	JobId    int32  `protobuf:"varint,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"` // Deprecated: Do not use.
+	JobIdStr string `protobuf:"bytes,2,opt,name=job_id_str,json=jobIdStr,proto3" json:"job_id_str,omitempty"`
 }
 
-func (m *ScheduleTaskRequest) Reset()         { *m = ScheduleTaskRequest{} }
-func (m *ScheduleTaskRequest) String() string { return proto.CompactTextString(m) }
-func (*ScheduleTaskRequest) ProtoMessage()    {}
-func (*ScheduleTaskRequest) Descriptor() ([]byte, []int) {
+func (m *CancelJobRequest) Reset()         { *m = CancelJobRequest{} }
+func (m *CancelJobRequest) String() string { return proto.CompactTextString(m) }
+func (*CancelJobRequest) ProtoMessage()    {}
+func (*CancelJobRequest) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f9c348dec43a6705, []int{13}
 }
-func (m *ScheduleTaskRequest) XXX_Unmarshal(b []byte) error {
+func (m *CancelJobRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *ScheduleTaskRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *CancelJobRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_ScheduleTaskRequest.Marshal(b, m, deterministic)
+		return xxx_messageInfo_CancelJobRequest.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -900,56 +1278,50 @@ func (m *ScheduleTaskRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte,
 		return b[:n], nil
 	}
 }
-func (m *ScheduleTaskRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ScheduleTaskRequest.Merge(m, src)
+func (m *CancelJobRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelJobRequest.Merge(m, src)
 }
-func (m *ScheduleTaskRequest) XXX_Size() int {
+func (m *CancelJobRequest) XXX_Size() int {
 	return m.Size()
 }
-func (m *ScheduleTaskRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_ScheduleTaskRequest.DiscardUnknown(m)
+func (m *CancelJobRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelJobRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ScheduleTaskRequest proto.InternalMessageInfo
-
-func (m *ScheduleTaskRequest) GetTaskId() string {
-	if m != nil {
-		return m.TaskId
-	}
-	return ""
-}
+var xxx_messageInfo_CancelJobRequest proto.InternalMessageInfo
 
-func (m *ScheduleTaskRequest) GetCost() int64 {
+// Deprecated: Do not use.
+func (m *CancelJobRequest) GetJobId() int32 {
 	if m != nil {
-		return m.Cost
+		return m.JobId
 	}
 	return 0
 }
 
-func (m *ScheduleTaskRequest) GetResourceRequirements() []string {
+func (m *CancelJobRequest) GetJobIdStr() string {
 	if m != nil {
-		return m.ResourceRequirements
+		return m.JobIdStr
 	}
-	return nil
+	return ""
 }
 
-type ScheduleTaskResponse struct {
-	ExecutorId   string `protobuf:"bytes,1,opt,name=executor_id,json=executorId,proto3" json:"executor_id,omitempty"`
-	ExecutorAddr string `protobuf:"bytes,2,opt,name=executor_addr,json=executorAddr,proto3" json:"executor_addr,omitempty"`
+type PauseJobRequest struct {
+	JobId    int32  `protobuf:"varint,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"` // Deprecated: Do not use.
+	JobIdStr string `protobuf:"bytes,2,opt,name=job_id_str,json=jobIdStr,proto3" json:"job_id_str,omitempty"`
 }
 
-func (m *ScheduleTaskResponse) Reset()         { *m = ScheduleTaskResponse{} }
-func (m *ScheduleTaskResponse) String() string { return proto.CompactTextString(m) }
-func (*ScheduleTaskResponse) ProtoMessage()    {}
-func (*ScheduleTaskResponse) Descriptor() ([]byte, []int) {
+func (m *PauseJobRequest) Reset()         { *m = PauseJobRequest{} }
+func (m *PauseJobRequest) String() string { return proto.CompactTextString(m) }
+func (*PauseJobRequest) ProtoMessage()    {}
+func (*PauseJobRequest) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f9c348dec43a6705, []int{14}
 }
-func (m *ScheduleTaskResponse) XXX_Unmarshal(b []byte) error {
+func (m *PauseJobRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *ScheduleTaskResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *PauseJobRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_ScheduleTaskResponse.Marshal(b, m, deterministic)
+		return xxx_messageInfo_PauseJobRequest.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -959,49 +1331,51 @@ func (m *ScheduleTaskResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte
 		return b[:n], nil
 	}
 }
-func (m *ScheduleTaskResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ScheduleTaskResponse.Merge(m, src)
+func (m *PauseJobRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PauseJobRequest.Merge(m, src)
 }
-func (m *ScheduleTaskResponse) XXX_Size() int {
+func (m *PauseJobRequest) XXX_Size() int {
 	return m.Size()
 }
-func (m *ScheduleTaskResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_ScheduleTaskResponse.DiscardUnknown(m)
+func (m *PauseJobRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PauseJobRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ScheduleTaskResponse proto.InternalMessageInfo
+var xxx_messageInfo_PauseJobRequest proto.InternalMessageInfo
 
-func (m *ScheduleTaskResponse) GetExecutorId() string {
+// Deprecated: Do not use.
+func (m *PauseJobRequest) GetJobId() int32 {
 	if m != nil {
-		return m.ExecutorId
+		return m.JobId
 	}
-	return ""
+	return 0
 }
 
-func (m *ScheduleTaskResponse) GetExecutorAddr() string {
+func (m *PauseJobRequest) GetJobIdStr() string {
 	if m != nil {
-		return m.ExecutorAddr
+		return m.JobIdStr
 	}
 	return ""
 }
 
-type ExecWorkload struct {
-	Tp    JobType `protobuf:"varint,1,opt,name=tp,proto3,enum=pb.JobType" json:"tp,omitempty"`
-	Usage int32   `protobuf:"varint,2,opt,name=usage,proto3" json:"usage,omitempty"`
+type SubmitJobResponse struct {
+	Err      *Error `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
+	JobId    int32  `protobuf:"varint,2,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"` // Deprecated: Do not use.
+	JobIdStr string `protobuf:"bytes,3,opt,name=job_id_str,json=jobIdStr,proto3" json:"job_id_str,omitempty"`
 }
 
-func (m *ExecWorkload) Reset()         { *m = ExecWorkload{} }
-func (m *ExecWorkload) String() string { return proto.CompactTextString(m) }
-func (*ExecWorkload) ProtoMessage()    {}
-func (*ExecWorkload) Descriptor() ([]byte, []int) {
+func (m *SubmitJobResponse) Reset()         { *m = SubmitJobResponse{} }
+func (m *SubmitJobResponse) String() string { return proto.CompactTextString(m) }
+func (*SubmitJobResponse) ProtoMessage()    {}
+func (*SubmitJobResponse) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f9c348dec43a6705, []int{15}
 }
-func (m *ExecWorkload) XXX_Unmarshal(b []byte) error {
+func (m *SubmitJobResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *ExecWorkload) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *SubmitJobResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_ExecWorkload.Marshal(b, m, deterministic)
+		return xxx_messageInfo_SubmitJobResponse.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1011,50 +1385,56 @@ func (m *ExecWorkload) XXX_Marshal(b []byte, deterministic bool) ([]byte, error)
 		return b[:n], nil
 	}
 }
-func (m *ExecWorkload) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ExecWorkload.Merge(m, src)
+func (m *SubmitJobResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubmitJobResponse.Merge(m, src)
 }
-func (m *ExecWorkload) XXX_Size() int {
+func (m *SubmitJobResponse) XXX_Size() int {
 	return m.Size()
 }
-func (m *ExecWorkload) XXX_DiscardUnknown() {
-	xxx_messageInfo_ExecWorkload.DiscardUnknown(m)
+func (m *SubmitJobResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubmitJobResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ExecWorkload proto.InternalMessageInfo
+var xxx_messageInfo_SubmitJobResponse proto.InternalMessageInfo
 
-func (m *ExecWorkload) GetTp() JobType {
+func (m *SubmitJobResponse) GetErr() *Error {
 	if m != nil {
-		return m.Tp
+		return m.Err
 	}
-	return JobType_CVSDemo
+	return nil
 }
 
-func (m *ExecWorkload) GetUsage() int32 {
+// Deprecated: Do not use.
+func (m *SubmitJobResponse) GetJobId() int32 {
 	if m != nil {
-		return m.Usage
+		return m.JobId
 	}
 	return 0
 }
 
-type ExecWorkloadRequest struct {
-	ExecutorId string          `protobuf:"bytes,1,opt,name=executor_id,json=executorId,proto3" json:"executor_id,omitempty"`
-	Workloads  []*ExecWorkload `protobuf:"bytes,2,rep,name=workloads,proto3" json:"workloads,omitempty"`
-	ResourceId []string        `protobuf:"bytes,3,rep,name=resource_id,json=resourceId,proto3" json:"resource_id,omitempty"`
+func (m *SubmitJobResponse) GetJobIdStr() string {
+	if m != nil {
+		return m.JobIdStr
+	}
+	return ""
 }
 
-func (m *ExecWorkloadRequest) Reset()         { *m = ExecWorkloadRequest{} }
-func (m *ExecWorkloadRequest) String() string { return proto.CompactTextString(m) }
-func (*ExecWorkloadRequest) ProtoMessage()    {}
-func (*ExecWorkloadRequest) Descriptor() ([]byte, []int) {
+type PauseJobResponse struct {
+	Err *Error `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
+}
+
+func (m *PauseJobResponse) Reset()         { *m = PauseJobResponse{} }
+func (m *PauseJobResponse) String() string { return proto.CompactTextString(m) }
+func (*PauseJobResponse) ProtoMessage()    {}
+func (*PauseJobResponse) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f9c348dec43a6705, []int{16}
 }
-func (m *ExecWorkloadRequest) XXX_Unmarshal(b []byte) error {
+func (m *PauseJobResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *ExecWorkloadRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *PauseJobResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_ExecWorkloadRequest.Marshal(b, m, deterministic)
+		return xxx_messageInfo_PauseJobResponse.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1064,55 +1444,41 @@ func (m *ExecWorkloadRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte,
 		return b[:n], nil
 	}
 }
-func (m *ExecWorkloadRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ExecWorkloadRequest.Merge(m, src)
+func (m *PauseJobResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PauseJobResponse.Merge(m, src)
 }
-func (m *ExecWorkloadRequest) XXX_Size() int {
+func (m *PauseJobResponse) XXX_Size() int {
 	return m.Size()
 }
-func (m *ExecWorkloadRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_ExecWorkloadRequest.DiscardUnknown(m)
-}
-
-var xxx_messageInfo_ExecWorkloadRequest proto.InternalMessageInfo
-
-func (m *ExecWorkloadRequest) GetExecutorId() string {
-	if m != nil {
-		return m.ExecutorId
-	}
-	return ""
+func (m *PauseJobResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PauseJobResponse.DiscardUnknown(m)
 }
 
-func (m *ExecWorkloadRequest) GetWorkloads() []*ExecWorkload {
-	if m != nil {
-		return m.Workloads
-	}
-	return nil
-}
+var xxx_messageInfo_PauseJobResponse proto.InternalMessageInfo
 
-func (m *ExecWorkloadRequest) GetResourceId() []string {
+func (m *PauseJobResponse) GetErr() *Error {
 	if m != nil {
-		return m.ResourceId
+		return m.Err
 	}
 	return nil
 }
 
-type ExecWorkloadResponse struct {
+type CancelJobResponse struct {
 	Err *Error `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
 }
 
-func (m *ExecWorkloadResponse) Reset()         { *m = ExecWorkloadResponse{} }
-func (m *ExecWorkloadResponse) String() string { return proto.CompactTextString(m) }
-func (*ExecWorkloadResponse) ProtoMessage()    {}
-func (*ExecWorkloadResponse) Descriptor() ([]byte, []int) {
+func (m *CancelJobResponse) Reset()         { *m = CancelJobResponse{} }
+func (m *CancelJobResponse) String() string { return proto.CompactTextString(m) }
+func (*CancelJobResponse) ProtoMessage()    {}
+func (*CancelJobResponse) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f9c348dec43a6705, []int{17}
 }
-func (m *ExecWorkloadResponse) XXX_Unmarshal(b []byte) error {
+func (m *CancelJobResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *ExecWorkloadResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *CancelJobResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_ExecWorkloadResponse.Marshal(b, m, deterministic)
+		return xxx_messageInfo_CancelJobResponse.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1122,42 +1488,46 @@ func (m *ExecWorkloadResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte
 		return b[:n], nil
 	}
 }
-func (m *ExecWorkloadResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ExecWorkloadResponse.Merge(m, src)
+func (m *CancelJobResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelJobResponse.Merge(m, src)
 }
-func (m *ExecWorkloadResponse) XXX_Size() int {
+func (m *CancelJobResponse) XXX_Size() int {
 	return m.Size()
 }
-func (m *ExecWorkloadResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_ExecWorkloadResponse.DiscardUnknown(m)
+func (m *CancelJobResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelJobResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ExecWorkloadResponse proto.InternalMessageInfo
+var xxx_messageInfo_CancelJobResponse proto.InternalMessageInfo
 
-func (m *ExecWorkloadResponse) GetErr() *Error {
+func (m *CancelJobResponse) GetErr() *Error {
 	if m != nil {
 		return m.Err
 	}
 	return nil
 }
 
-type PersistResourceRequest struct {
-	ExecutorId string `protobuf:"bytes,1,opt,name=executor_id,json=executorId,proto3" json:"executor_id,omitempty"`
-	ResourceId string `protobuf:"bytes,2,opt,name=resource_id,json=resourceId,proto3" json:"resource_id,omitempty"`
+// UpdateJobTimeoutsRequest carries the new timeouts in milliseconds,
+// 0 means unchanged.
+type UpdateJobTimeoutsRequest struct {
+	JobId                     string `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	WorkerTimeoutMs           int64  `protobuf:"varint,2,opt,name=worker_timeout_ms,json=workerTimeoutMs,proto3" json:"worker_timeout_ms,omitempty"`
+	WorkerTimeoutGracefulMs   int64  `protobuf:"varint,3,opt,name=worker_timeout_graceful_ms,json=workerTimeoutGracefulMs,proto3" json:"worker_timeout_graceful_ms,omitempty"`
+	WorkerHeartbeatIntervalMs int64  `protobuf:"varint,4,opt,name=worker_heartbeat_interval_ms,json=workerHeartbeatIntervalMs,proto3" json:"worker_heartbeat_interval_ms,omitempty"`
 }
 
-func (m *PersistResourceRequest) Reset()         { *m = PersistResourceRequest{} }
-func (m *PersistResourceRequest) String() string { return proto.CompactTextString(m) }
-func (*PersistResourceRequest) ProtoMessage()    {}
-func (*PersistResourceRequest) Descriptor() ([]byte, []int) {
+func (m *UpdateJobTimeoutsRequest) Reset()         { *m = UpdateJobTimeoutsRequest{} }
+func (m *UpdateJobTimeoutsRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateJobTimeoutsRequest) ProtoMessage()    {}
+func (*UpdateJobTimeoutsRequest) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f9c348dec43a6705, []int{18}
 }
-func (m *PersistResourceRequest) XXX_Unmarshal(b []byte) error {
+func (m *UpdateJobTimeoutsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *PersistResourceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *UpdateJobTimeoutsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_PersistResourceRequest.Marshal(b, m, deterministic)
+		return xxx_messageInfo_UpdateJobTimeoutsRequest.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1167,48 +1537,62 @@ func (m *PersistResourceRequest) XXX_Marshal(b []byte, deterministic bool) ([]by
 		return b[:n], nil
 	}
 }
-func (m *PersistResourceRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PersistResourceRequest.Merge(m, src)
+func (m *UpdateJobTimeoutsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateJobTimeoutsRequest.Merge(m, src)
 }
-func (m *PersistResourceRequest) XXX_Size() int {
+func (m *UpdateJobTimeoutsRequest) XXX_Size() int {
 	return m.Size()
 }
-func (m *PersistResourceRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_PersistResourceRequest.DiscardUnknown(m)
+func (m *UpdateJobTimeoutsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateJobTimeoutsRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_PersistResourceRequest proto.InternalMessageInfo
+var xxx_messageInfo_UpdateJobTimeoutsRequest proto.InternalMessageInfo
 
-func (m *PersistResourceRequest) GetExecutorId() string {
+func (m *UpdateJobTimeoutsRequest) GetJobId() string {
 	if m != nil {
-		return m.ExecutorId
+		return m.JobId
 	}
 	return ""
 }
 
-func (m *PersistResourceRequest) GetResourceId() string {
+func (m *UpdateJobTimeoutsRequest) GetWorkerTimeoutMs() int64 {
 	if m != nil {
-		return m.ResourceId
+		return m.WorkerTimeoutMs
 	}
-	return ""
+	return 0
 }
 
-type PersistResourceResponse struct {
+func (m *UpdateJobTimeoutsRequest) GetWorkerTimeoutGracefulMs() int64 {
+	if m != nil {
+		return m.WorkerTimeoutGracefulMs
+	}
+	return 0
+}
+
+func (m *UpdateJobTimeoutsRequest) GetWorkerHeartbeatIntervalMs() int64 {
+	if m != nil {
+		return m.WorkerHeartbeatIntervalMs
+	}
+	return 0
+}
+
+type UpdateJobTimeoutsResponse struct {
 	Err *Error `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
 }
 
-func (m *PersistResourceResponse) Reset()         { *m = PersistResourceResponse{} }
-func (m *PersistResourceResponse) String() string { return proto.CompactTextString(m) }
-func (*PersistResourceResponse) ProtoMessage()    {}
-func (*PersistResourceResponse) Descriptor() ([]byte, []int) {
+func (m *UpdateJobTimeoutsResponse) Reset()         { *m = UpdateJobTimeoutsResponse{} }
+func (m *UpdateJobTimeoutsResponse) String() string { return proto.CompactTextString(m) }
+func (*UpdateJobTimeoutsResponse) ProtoMessage()    {}
+func (*UpdateJobTimeoutsResponse) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f9c348dec43a6705, []int{19}
 }
-func (m *PersistResourceResponse) XXX_Unmarshal(b []byte) error {
+func (m *UpdateJobTimeoutsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *PersistResourceResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *UpdateJobTimeoutsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_PersistResourceResponse.Marshal(b, m, deterministic)
+		return xxx_messageInfo_UpdateJobTimeoutsResponse.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1218,1834 +1602,16973 @@ func (m *PersistResourceResponse) XXX_Marshal(b []byte, deterministic bool) ([]b
 		return b[:n], nil
 	}
 }
-func (m *PersistResourceResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PersistResourceResponse.Merge(m, src)
+func (m *UpdateJobTimeoutsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateJobTimeoutsResponse.Merge(m, src)
 }
-func (m *PersistResourceResponse) XXX_Size() int {
+func (m *UpdateJobTimeoutsResponse) XXX_Size() int {
 	return m.Size()
 }
-func (m *PersistResourceResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_PersistResourceResponse.DiscardUnknown(m)
+func (m *UpdateJobTimeoutsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateJobTimeoutsResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_PersistResourceResponse proto.InternalMessageInfo
+var xxx_messageInfo_UpdateJobTimeoutsResponse proto.InternalMessageInfo
 
-func (m *PersistResourceResponse) GetErr() *Error {
+func (m *UpdateJobTimeoutsResponse) GetErr() *Error {
 	if m != nil {
 		return m.Err
 	}
 	return nil
 }
 
-func init() {
-	proto.RegisterEnum("pb.JobType", JobType_name, JobType_value)
-	proto.RegisterEnum("pb.QueryJobResponse_JobStatus", QueryJobResponse_JobStatus_name, QueryJobResponse_JobStatus_value)
-	proto.RegisterType((*HeartbeatRequest)(nil), "pb.HeartbeatRequest")
-	proto.RegisterType((*HeartbeatResponse)(nil), "pb.HeartbeatResponse")
-	proto.RegisterType((*SubmitJobRequest)(nil), "pb.SubmitJobRequest")
-	proto.RegisterType((*QueryJobRequest)(nil), "pb.QueryJobRequest")
-	proto.RegisterType((*WorkerInfo)(nil), "pb.WorkerInfo")
-	proto.RegisterType((*QueryJobResponse)(nil), "pb.QueryJobResponse")
-	proto.RegisterType((*CancelJobRequest)(nil), "pb.CancelJobRequest")
-	proto.RegisterType((*PauseJobRequest)(nil), "pb.PauseJobRequest")
-	proto.RegisterType((*SubmitJobResponse)(nil), "pb.SubmitJobResponse")
-	proto.RegisterType((*PauseJobResponse)(nil), "pb.PauseJobResponse")
-	proto.RegisterType((*CancelJobResponse)(nil), "pb.CancelJobResponse")
-	proto.RegisterType((*RegisterExecutorRequest)(nil), "pb.RegisterExecutorRequest")
-	proto.RegisterType((*RegisterExecutorResponse)(nil), "pb.RegisterExecutorResponse")
-	proto.RegisterType((*ScheduleTaskRequest)(nil), "pb.ScheduleTaskRequest")
-	proto.RegisterType((*ScheduleTaskResponse)(nil), "pb.ScheduleTaskResponse")
-	proto.RegisterType((*ExecWorkload)(nil), "pb.ExecWorkload")
-	proto.RegisterType((*ExecWorkloadRequest)(nil), "pb.ExecWorkloadRequest")
-	proto.RegisterType((*ExecWorkloadResponse)(nil), "pb.ExecWorkloadResponse")
-	proto.RegisterType((*PersistResourceRequest)(nil), "pb.PersistResourceRequest")
-	proto.RegisterType((*PersistResourceResponse)(nil), "pb.PersistResourceResponse")
+// SetJobLogLevelRequest carries the log level of a job, such as "debug", an
+// empty level resets it to the global log level.
+type SetJobLogLevelRequest struct {
+	JobId string `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Level string `protobuf:"bytes,2,opt,name=level,proto3" json:"level,omitempty"`
 }
 
-func init() { proto.RegisterFile("master.proto", fileDescriptor_f9c348dec43a6705) }
-
-var fileDescriptor_f9c348dec43a6705 = []byte{
-	// 1140 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0x4b, 0x6f, 0xdb, 0xc6,
-	0x13, 0x17, 0x49, 0x3d, 0x47, 0x8a, 0x4c, 0xaf, 0x65, 0x9b, 0x91, 0xfd, 0xd7, 0x5f, 0x65, 0x51,
-	0x40, 0xe8, 0xc1, 0x2d, 0xe4, 0xc2, 0x05, 0x7a, 0x4b, 0xec, 0x14, 0x91, 0x5b, 0xa3, 0x29, 0xe5,
-	0x36, 0x7d, 0xa1, 0x02, 0x29, 0xae, 0x9d, 0xb5, 0x25, 0x2e, 0xb3, 0xbb, 0x6a, 0xea, 0x5b, 0x6f,
-	0xbd, 0xf6, 0x33, 0xe4, 0xd3, 0xf4, 0x54, 0xe4, 0xd8, 0x63, 0x61, 0x7f, 0x91, 0x62, 0x97, 0x0f,
-	0x51, 0x94, 0xe2, 0xe8, 0xd0, 0x1b, 0x77, 0x66, 0xe7, 0x37, 0x33, 0xbf, 0x79, 0x2c, 0xa1, 0x31,
-	0x75, 0xb9, 0xc0, 0xec, 0x20, 0x64, 0x54, 0x50, 0xa4, 0x87, 0x5e, 0xbb, 0x8e, 0x19, 0xa3, 0xb1,
-	0xa0, 0xbd, 0x31, 0xc5, 0xc2, 0xe5, 0x82, 0x32, 0x1c, 0x09, 0xec, 0xd7, 0x1a, 0x98, 0x4f, 0xb1,
-	0xcb, 0x84, 0x87, 0x5d, 0xe1, 0xe0, 0x97, 0x33, 0xcc, 0x05, 0xfa, 0x3f, 0xd4, 0xf1, 0xaf, 0x78,
-	0x3c, 0x13, 0x94, 0x8d, 0x88, 0x6f, 0x69, 0x5d, 0xad, 0x57, 0x73, 0x20, 0x11, 0x0d, 0x7c, 0xf4,
-	0x01, 0x34, 0x19, 0xe6, 0x74, 0xc6, 0xc6, 0x78, 0x34, 0xe3, 0xee, 0x25, 0xb6, 0xf4, 0xae, 0xd6,
-	0x2b, 0x39, 0x0f, 0x12, 0xe9, 0x37, 0x52, 0x88, 0x76, 0xa0, 0xcc, 0x85, 0x2b, 0x66, 0xdc, 0x32,
-	0x94, 0x3a, 0x3e, 0xa1, 0x7d, 0xa8, 0x09, 0x32, 0xc5, 0x5c, 0xb8, 0xd3, 0xd0, 0x2a, 0x76, 0xb5,
-	0x5e, 0xd1, 0x99, 0x0b, 0x90, 0x09, 0x86, 0x10, 0x13, 0xab, 0xa4, 0xe4, 0xf2, 0xd3, 0xfe, 0x19,
-	0x36, 0x33, 0x31, 0xf2, 0x90, 0x06, 0x1c, 0xa3, 0x3d, 0x30, 0x30, 0x63, 0x2a, 0xb8, 0x7a, 0xbf,
-	0x76, 0x10, 0x7a, 0x07, 0x4f, 0x64, 0xa2, 0x8e, 0x94, 0x4a, 0xcf, 0x13, 0xec, 0xfa, 0x98, 0xa9,
-	0xc0, 0x6a, 0x4e, 0x7c, 0x42, 0x2d, 0x28, 0xb9, 0xbe, 0xcf, 0x64, 0x40, 0x46, 0xaf, 0xe6, 0x44,
-	0x07, 0xfb, 0x47, 0x30, 0x87, 0x33, 0x6f, 0x4a, 0xc4, 0x29, 0xf5, 0x12, 0x0e, 0xf6, 0x40, 0x17,
-	0xa1, 0x42, 0x6f, 0xf6, 0xeb, 0x12, 0xfd, 0x94, 0x7a, 0xe7, 0x37, 0x21, 0x76, 0x74, 0x11, 0x4a,
-	0xf8, 0x31, 0x0d, 0x2e, 0xc8, 0xa5, 0x82, 0x6f, 0x38, 0xf1, 0x09, 0x21, 0x28, 0xce, 0x38, 0x66,
-	0x2a, 0xdd, 0x9a, 0xa3, 0xbe, 0xed, 0x1e, 0x6c, 0x7c, 0x3d, 0xc3, 0xec, 0x26, 0x83, 0xbd, 0x0d,
-	0xe5, 0x2b, 0xea, 0xcd, 0xa9, 0x2d, 0x5d, 0x51, 0x6f, 0xe0, 0xdb, 0x7f, 0x69, 0x00, 0xcf, 0x29,
-	0xbb, 0xc6, 0x6c, 0x10, 0x5c, 0x50, 0xd4, 0x04, 0x3d, 0xbd, 0xa1, 0x13, 0x3f, 0x5f, 0x15, 0x7d,
-	0xa9, 0x2a, 0x8b, 0x74, 0x37, 0x52, 0xba, 0xe7, 0xd1, 0x16, 0x17, 0xa2, 0x7d, 0x0f, 0x1a, 0x84,
-	0x8f, 0x04, 0x9d, 0x7a, 0x5c, 0xd0, 0x00, 0x2b, 0xc6, 0xab, 0x4e, 0x9d, 0xf0, 0xf3, 0x44, 0x84,
-	0xba, 0xd0, 0x98, 0xb8, 0x5c, 0x8c, 0x5e, 0x78, 0x23, 0x59, 0x20, 0xab, 0xdc, 0xd5, 0x7a, 0x86,
-	0x03, 0x52, 0xf6, 0xd4, 0x3b, 0x27, 0x53, 0x8c, 0xda, 0x50, 0x7d, 0x45, 0xd9, 0xf5, 0x84, 0xba,
-	0xbe, 0x55, 0x51, 0xda, 0xf4, 0x6c, 0xbf, 0xd6, 0xc1, 0x9c, 0xe7, 0x1e, 0xd7, 0xad, 0x99, 0x12,
-	0x6b, 0xdc, 0xcb, 0xe5, 0xd1, 0x42, 0x36, 0xcd, 0x7e, 0x47, 0x16, 0x21, 0x8f, 0x26, 0xab, 0x32,
-	0x54, 0xb7, 0xd2, 0x6c, 0x8f, 0x60, 0x43, 0x92, 0x1b, 0xcd, 0xc1, 0x88, 0x04, 0x17, 0x54, 0xa5,
-	0x5d, 0xef, 0x37, 0x25, 0xc0, 0x9c, 0x5f, 0xe7, 0xc1, 0x15, 0xf5, 0xce, 0xd4, 0x2d, 0x45, 0x77,
-	0xdc, 0x4f, 0xa5, 0x55, 0xfd, 0x64, 0x7f, 0x0f, 0xb5, 0xd4, 0x13, 0xaa, 0x42, 0x91, 0x04, 0x44,
-	0x98, 0x05, 0x54, 0x87, 0x4a, 0x88, 0x03, 0x9f, 0x04, 0x97, 0xa6, 0x86, 0x00, 0xca, 0x34, 0x98,
-	0x90, 0x00, 0x9b, 0x3a, 0x6a, 0x02, 0xf8, 0x84, 0x87, 0xae, 0x18, 0xbf, 0xc0, 0xbe, 0x69, 0xa0,
-	0x06, 0x54, 0x2f, 0x48, 0x40, 0xb8, 0x3c, 0x15, 0xa5, 0x19, 0x17, 0x34, 0x0c, 0xb1, 0x6f, 0x96,
-	0xec, 0x2f, 0xc0, 0x3c, 0x76, 0x83, 0x31, 0x9e, 0x64, 0x1a, 0xe4, 0xe1, 0x42, 0x83, 0x94, 0x1e,
-	0xeb, 0x96, 0x16, 0x37, 0x09, 0xda, 0x07, 0x88, 0x54, 0x23, 0x2e, 0x92, 0xee, 0xae, 0x2a, 0xd5,
-	0x50, 0x30, 0xfb, 0x14, 0x36, 0x9e, 0xb9, 0x33, 0x8e, 0xff, 0x0b, 0x2c, 0x02, 0x9b, 0x99, 0xa9,
-	0x58, 0x67, 0xea, 0xe6, 0xae, 0xf4, 0xfb, 0x5d, 0x19, 0x39, 0x57, 0x1f, 0x81, 0x39, 0x0f, 0x7b,
-	0x0d, 0x4f, 0xf6, 0xc7, 0xb0, 0x99, 0x21, 0x6d, 0x1d, 0x8b, 0x29, 0xec, 0x3a, 0xf8, 0x92, 0xc8,
-	0x72, 0x3f, 0x89, 0x47, 0x26, 0x61, 0xc8, 0x82, 0x8a, 0xdc, 0x03, 0x98, 0xf3, 0x78, 0xda, 0x92,
-	0xa3, 0xd4, 0xfc, 0x82, 0x19, 0x27, 0x34, 0x88, 0xd9, 0x49, 0x8e, 0xa8, 0x03, 0x30, 0x76, 0x43,
-	0xd7, 0x23, 0x13, 0x22, 0x6e, 0x54, 0x3e, 0x86, 0x93, 0x91, 0xd8, 0xdf, 0x81, 0xb5, 0xec, 0x6e,
-	0x1d, 0x0e, 0xdf, 0x35, 0xe5, 0xf6, 0x2b, 0xd8, 0x1a, 0xca, 0xae, 0x9a, 0x4d, 0xf0, 0xb9, 0xcb,
-	0xaf, 0x93, 0x24, 0x76, 0xa1, 0x22, 0x5c, 0x7e, 0x3d, 0x5f, 0x2a, 0x65, 0x79, 0x1c, 0xf8, 0x72,
-	0x27, 0x8d, 0x29, 0x17, 0x0a, 0xc9, 0x70, 0xd4, 0x37, 0x3a, 0x84, 0xed, 0x74, 0x7f, 0x33, 0xfc,
-	0x72, 0x46, 0x18, 0x9e, 0xe2, 0x40, 0x24, 0x6b, 0xb1, 0x95, 0x28, 0x9d, 0x8c, 0xce, 0xfe, 0x09,
-	0x5a, 0x8b, 0x8e, 0xe3, 0x74, 0xde, 0xf9, 0x5a, 0xbc, 0x0f, 0x0f, 0xd2, 0x0b, 0x92, 0xd9, 0x38,
-	0xa9, 0x46, 0x22, 0x7c, 0xe4, 0xfb, 0xcc, 0x7e, 0x04, 0x0d, 0x49, 0xd4, 0xf3, 0x78, 0x77, 0xdc,
-	0xbf, 0x7f, 0x5b, 0x50, 0xca, 0x3e, 0x3b, 0xd1, 0xc1, 0xfe, 0x5d, 0x83, 0xad, 0x2c, 0xc6, 0xda,
-	0xcf, 0xd9, 0x01, 0xd4, 0x92, 0x9d, 0xc5, 0x2d, 0xbd, 0x6b, 0xf4, 0xea, 0x7d, 0x53, 0x95, 0x25,
-	0x0b, 0x36, 0xbf, 0x22, 0x01, 0x53, 0xfa, 0x88, 0x1f, 0x93, 0x06, 0x89, 0x68, 0xe0, 0xdb, 0x87,
-	0xd0, 0x5a, 0x0c, 0x64, 0x9d, 0x0e, 0xfd, 0x01, 0x76, 0x9e, 0xc9, 0xee, 0xe2, 0xf2, 0x8d, 0x4b,
-	0xe9, 0x5f, 0x2b, 0x81, 0x5c, 0x40, 0x71, 0xd3, 0x64, 0x02, 0x3a, 0x82, 0xdd, 0x25, 0xec, 0x35,
-	0x62, 0xfa, 0xf0, 0x13, 0xa8, 0xc4, 0xbc, 0xcb, 0xa5, 0x75, 0xfc, 0xed, 0xf0, 0x04, 0x4f, 0xa9,
-	0x59, 0x40, 0x65, 0xd0, 0x4f, 0xce, 0x4c, 0x0d, 0x55, 0xc0, 0x38, 0x3e, 0x39, 0x36, 0x75, 0xa9,
-	0xfd, 0xdc, 0xbd, 0x96, 0x03, 0x6c, 0x1a, 0xfd, 0xdf, 0xca, 0x50, 0x8e, 0x36, 0x2b, 0xfa, 0x0a,
-	0xcc, 0xfc, 0x1c, 0xa0, 0x3d, 0xe9, 0xe4, 0x2d, 0xc3, 0xd8, 0xde, 0x5f, 0xad, 0x8c, 0x82, 0xb5,
-	0x0b, 0xe8, 0x33, 0xa8, 0xa5, 0x5b, 0x09, 0xb5, 0xe4, 0xe5, 0xfc, 0xd3, 0xdd, 0xde, 0xce, 0x49,
-	0x53, 0xdb, 0x4f, 0xa1, 0x9a, 0x3c, 0x20, 0x68, 0x6b, 0xf1, 0x39, 0x89, 0x2c, 0x5b, 0xab, 0xde,
-	0x98, 0xc8, 0x30, 0xd9, 0x4f, 0x91, 0x61, 0x6e, 0xc9, 0x46, 0x86, 0xf9, 0x15, 0x16, 0x45, 0x9b,
-	0xee, 0xa9, 0x28, 0xda, 0xfc, 0xae, 0x8f, 0xa2, 0x5d, 0x5a, 0x66, 0x91, 0x6d, 0xfa, 0xd7, 0x13,
-	0xd9, 0xe6, 0x7f, 0xd4, 0x22, 0xdb, 0xa5, 0x5f, 0x23, 0xbb, 0x80, 0x8e, 0xa1, 0x91, 0x9d, 0x55,
-	0xb4, 0xab, 0x28, 0x59, 0x5e, 0x1b, 0x6d, 0x6b, 0x59, 0x91, 0x82, 0x38, 0xb0, 0x99, 0x14, 0xe2,
-	0x0c, 0x0b, 0x77, 0x28, 0x7f, 0x1b, 0xd1, 0x42, 0x7d, 0x52, 0x71, 0x02, 0xf7, 0xbf, 0xb7, 0x68,
-	0x53, 0xcc, 0x01, 0x34, 0x15, 0xbf, 0x73, 0xc0, 0x87, 0x29, 0xe7, 0x4b, 0x68, 0xed, 0x55, 0xaa,
-	0x14, 0xea, 0x0c, 0x76, 0x1c, 0x1c, 0x52, 0x26, 0x92, 0x2e, 0x49, 0x77, 0xc7, 0xee, 0xd2, 0xf0,
-	0x66, 0xb3, 0x5d, 0x35, 0x99, 0x76, 0x01, 0x7d, 0x09, 0x1b, 0xb9, 0x11, 0x41, 0xca, 0xff, 0xea,
-	0x99, 0x6c, 0xef, 0xad, 0xd4, 0x25, 0x68, 0x8f, 0xad, 0x3f, 0x6f, 0x3b, 0xda, 0x9b, 0xdb, 0x8e,
-	0xf6, 0xcf, 0x6d, 0x47, 0xfb, 0xe3, 0xae, 0x53, 0x78, 0x73, 0xd7, 0x29, 0xfc, 0x7d, 0xd7, 0x29,
-	0x78, 0x65, 0xf5, 0xe3, 0x7d, 0xf8, 0x6f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x1b, 0x23, 0x3a, 0x17,
-	0xaa, 0x0b, 0x00, 0x00,
+func (m *SetJobLogLevelRequest) Reset()         { *m = SetJobLogLevelRequest{} }
+func (m *SetJobLogLevelRequest) String() string { return proto.CompactTextString(m) }
+func (*SetJobLogLevelRequest) ProtoMessage()    {}
+func (*SetJobLogLevelRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{20}
 }
-
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
-
-// MasterClient is the client API for Master service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type MasterClient interface {
-	RegisterExecutor(ctx context.Context, in *RegisterExecutorRequest, opts ...grpc.CallOption) (*RegisterExecutorResponse, error)
-	SubmitJob(ctx context.Context, in *SubmitJobRequest, opts ...grpc.CallOption) (*SubmitJobResponse, error)
-	QueryJob(ctx context.Context, in *QueryJobRequest, opts ...grpc.CallOption) (*QueryJobResponse, error)
-	PauseJob(ctx context.Context, in *PauseJobRequest, opts ...grpc.CallOption) (*PauseJobResponse, error)
-	CancelJob(ctx context.Context, in *CancelJobRequest, opts ...grpc.CallOption) (*CancelJobResponse, error)
-	Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error)
-	ScheduleTask(ctx context.Context, in *ScheduleTaskRequest, opts ...grpc.CallOption) (*ScheduleTaskResponse, error)
-	// RegisterMetaStore is called from backend metastore and
-	// registers to server master metastore manager
-	RegisterMetaStore(ctx context.Context, in *RegisterMetaStoreRequest, opts ...grpc.CallOption) (*RegisterMetaStoreResponse, error)
-	// QueryMetaStore queries metastore manager and returns
-	// the information of a matching metastore
-	QueryMetaStore(ctx context.Context, in *QueryMetaStoreRequest, opts ...grpc.CallOption) (*QueryMetaStoreResponse, error)
-	// ReportExecutorWorkload is called from executor to server master to report
-	// resource usage in executor.
-	ReportExecutorWorkload(ctx context.Context, in *ExecWorkloadRequest, opts ...grpc.CallOption) (*ExecWorkloadResponse, error)
-	// PersistResource is called from executor to indicate some workers on it wants
-	// to persist resource files.
-	PersistResource(ctx context.Context, in *PersistResourceRequest, opts ...grpc.CallOption) (*PersistResourceResponse, error)
+func (m *SetJobLogLevelRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
 }
-
-type masterClient struct {
-	cc *grpc.ClientConn
+func (m *SetJobLogLevelRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SetJobLogLevelRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
 }
-
-func NewMasterClient(cc *grpc.ClientConn) MasterClient {
-	return &masterClient{cc}
+func (m *SetJobLogLevelRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetJobLogLevelRequest.Merge(m, src)
+}
+func (m *SetJobLogLevelRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *SetJobLogLevelRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetJobLogLevelRequest.DiscardUnknown(m)
 }
 
-func (c *masterClient) RegisterExecutor(ctx context.Context, in *RegisterExecutorRequest, opts ...grpc.CallOption) (*RegisterExecutorResponse, error) {
-	out := new(RegisterExecutorResponse)
-	err := c.cc.Invoke(ctx, "/pb.Master/RegisterExecutor", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_SetJobLogLevelRequest proto.InternalMessageInfo
+
+func (m *SetJobLogLevelRequest) GetJobId() string {
+	if m != nil {
+		return m.JobId
 	}
-	return out, nil
+	return ""
 }
 
-func (c *masterClient) SubmitJob(ctx context.Context, in *SubmitJobRequest, opts ...grpc.CallOption) (*SubmitJobResponse, error) {
-	out := new(SubmitJobResponse)
-	err := c.cc.Invoke(ctx, "/pb.Master/SubmitJob", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *SetJobLogLevelRequest) GetLevel() string {
+	if m != nil {
+		return m.Level
 	}
-	return out, nil
+	return ""
 }
 
-func (c *masterClient) QueryJob(ctx context.Context, in *QueryJobRequest, opts ...grpc.CallOption) (*QueryJobResponse, error) {
-	out := new(QueryJobResponse)
-	err := c.cc.Invoke(ctx, "/pb.Master/QueryJob", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+type SetJobLogLevelResponse struct {
+	Err *Error `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
 }
 
-func (c *masterClient) PauseJob(ctx context.Context, in *PauseJobRequest, opts ...grpc.CallOption) (*PauseJobResponse, error) {
-	out := new(PauseJobResponse)
-	err := c.cc.Invoke(ctx, "/pb.Master/PauseJob", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func (m *SetJobLogLevelResponse) Reset()         { *m = SetJobLogLevelResponse{} }
+func (m *SetJobLogLevelResponse) String() string { return proto.CompactTextString(m) }
+func (*SetJobLogLevelResponse) ProtoMessage()    {}
+func (*SetJobLogLevelResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{21}
 }
-
-func (c *masterClient) CancelJob(ctx context.Context, in *CancelJobRequest, opts ...grpc.CallOption) (*CancelJobResponse, error) {
-	out := new(CancelJobResponse)
-	err := c.cc.Invoke(ctx, "/pb.Master/CancelJob", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *SetJobLogLevelResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SetJobLogLevelResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SetJobLogLevelResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return out, nil
+}
+func (m *SetJobLogLevelResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetJobLogLevelResponse.Merge(m, src)
+}
+func (m *SetJobLogLevelResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *SetJobLogLevelResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetJobLogLevelResponse.DiscardUnknown(m)
 }
 
-func (c *masterClient) Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error) {
-	out := new(HeartbeatResponse)
-	err := c.cc.Invoke(ctx, "/pb.Master/Heartbeat", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_SetJobLogLevelResponse proto.InternalMessageInfo
+
+func (m *SetJobLogLevelResponse) GetErr() *Error {
+	if m != nil {
+		return m.Err
 	}
-	return out, nil
+	return nil
 }
 
-func (c *masterClient) ScheduleTask(ctx context.Context, in *ScheduleTaskRequest, opts ...grpc.CallOption) (*ScheduleTaskResponse, error) {
-	out := new(ScheduleTaskResponse)
-	err := c.cc.Invoke(ctx, "/pb.Master/ScheduleTask", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+type OperateJobTasksRequest struct {
+	JobId string    `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Op    JobTaskOp `protobuf:"varint,2,opt,name=op,proto3,enum=pb.JobTaskOp" json:"op,omitempty"`
+	// tasks are the IDs of the tasks to operate, empty means all the tasks
+	// of the job.
+	Tasks []string `protobuf:"bytes,3,rep,name=tasks,proto3" json:"tasks,omitempty"`
 }
 
-func (c *masterClient) RegisterMetaStore(ctx context.Context, in *RegisterMetaStoreRequest, opts ...grpc.CallOption) (*RegisterMetaStoreResponse, error) {
-	out := new(RegisterMetaStoreResponse)
-	err := c.cc.Invoke(ctx, "/pb.Master/RegisterMetaStore", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *OperateJobTasksRequest) Reset()         { *m = OperateJobTasksRequest{} }
+func (m *OperateJobTasksRequest) String() string { return proto.CompactTextString(m) }
+func (*OperateJobTasksRequest) ProtoMessage()    {}
+func (*OperateJobTasksRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{22}
+}
+func (m *OperateJobTasksRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *OperateJobTasksRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_OperateJobTasksRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return out, nil
+}
+func (m *OperateJobTasksRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OperateJobTasksRequest.Merge(m, src)
+}
+func (m *OperateJobTasksRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *OperateJobTasksRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_OperateJobTasksRequest.DiscardUnknown(m)
 }
 
-func (c *masterClient) QueryMetaStore(ctx context.Context, in *QueryMetaStoreRequest, opts ...grpc.CallOption) (*QueryMetaStoreResponse, error) {
-	out := new(QueryMetaStoreResponse)
-	err := c.cc.Invoke(ctx, "/pb.Master/QueryMetaStore", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_OperateJobTasksRequest proto.InternalMessageInfo
+
+func (m *OperateJobTasksRequest) GetJobId() string {
+	if m != nil {
+		return m.JobId
 	}
-	return out, nil
+	return ""
 }
 
-func (c *masterClient) ReportExecutorWorkload(ctx context.Context, in *ExecWorkloadRequest, opts ...grpc.CallOption) (*ExecWorkloadResponse, error) {
-	out := new(ExecWorkloadResponse)
-	err := c.cc.Invoke(ctx, "/pb.Master/ReportExecutorWorkload", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *OperateJobTasksRequest) GetOp() JobTaskOp {
+	if m != nil {
+		return m.Op
 	}
-	return out, nil
+	return JobTaskOp_TaskOpPause
 }
 
-func (c *masterClient) PersistResource(ctx context.Context, in *PersistResourceRequest, opts ...grpc.CallOption) (*PersistResourceResponse, error) {
-	out := new(PersistResourceResponse)
-	err := c.cc.Invoke(ctx, "/pb.Master/PersistResource", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *OperateJobTasksRequest) GetTasks() []string {
+	if m != nil {
+		return m.Tasks
 	}
-	return out, nil
+	return nil
 }
 
-// MasterServer is the server API for Master service.
-type MasterServer interface {
-	RegisterExecutor(context.Context, *RegisterExecutorRequest) (*RegisterExecutorResponse, error)
-	SubmitJob(context.Context, *SubmitJobRequest) (*SubmitJobResponse, error)
-	QueryJob(context.Context, *QueryJobRequest) (*QueryJobResponse, error)
-	PauseJob(context.Context, *PauseJobRequest) (*PauseJobResponse, error)
-	CancelJob(context.Context, *CancelJobRequest) (*CancelJobResponse, error)
-	Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error)
-	ScheduleTask(context.Context, *ScheduleTaskRequest) (*ScheduleTaskResponse, error)
-	// RegisterMetaStore is called from backend metastore and
-	// registers to server master metastore manager
-	RegisterMetaStore(context.Context, *RegisterMetaStoreRequest) (*RegisterMetaStoreResponse, error)
-	// QueryMetaStore queries metastore manager and returns
-	// the information of a matching metastore
-	QueryMetaStore(context.Context, *QueryMetaStoreRequest) (*QueryMetaStoreResponse, error)
-	// ReportExecutorWorkload is called from executor to server master to report
-	// resource usage in executor.
-	ReportExecutorWorkload(context.Context, *ExecWorkloadRequest) (*ExecWorkloadResponse, error)
-	// PersistResource is called from executor to indicate some workers on it wants
-	// to persist resource files.
-	PersistResource(context.Context, *PersistResourceRequest) (*PersistResourceResponse, error)
+type OperateJobTasksResponse struct {
+	Err *Error `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
 }
 
-// UnimplementedMasterServer can be embedded to have forward compatible implementations.
-type UnimplementedMasterServer struct {
+func (m *OperateJobTasksResponse) Reset()         { *m = OperateJobTasksResponse{} }
+func (m *OperateJobTasksResponse) String() string { return proto.CompactTextString(m) }
+func (*OperateJobTasksResponse) ProtoMessage()    {}
+func (*OperateJobTasksResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{23}
 }
-
-func (*UnimplementedMasterServer) RegisterExecutor(ctx context.Context, req *RegisterExecutorRequest) (*RegisterExecutorResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method RegisterExecutor not implemented")
+func (m *OperateJobTasksResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
 }
-func (*UnimplementedMasterServer) SubmitJob(ctx context.Context, req *SubmitJobRequest) (*SubmitJobResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SubmitJob not implemented")
+func (m *OperateJobTasksResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_OperateJobTasksResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
 }
-func (*UnimplementedMasterServer) QueryJob(ctx context.Context, req *QueryJobRequest) (*QueryJobResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method QueryJob not implemented")
+func (m *OperateJobTasksResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OperateJobTasksResponse.Merge(m, src)
 }
-func (*UnimplementedMasterServer) PauseJob(ctx context.Context, req *PauseJobRequest) (*PauseJobResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method PauseJob not implemented")
+func (m *OperateJobTasksResponse) XXX_Size() int {
+	return m.Size()
 }
-func (*UnimplementedMasterServer) CancelJob(ctx context.Context, req *CancelJobRequest) (*CancelJobResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CancelJob not implemented")
+func (m *OperateJobTasksResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_OperateJobTasksResponse.DiscardUnknown(m)
 }
-func (*UnimplementedMasterServer) Heartbeat(ctx context.Context, req *HeartbeatRequest) (*HeartbeatResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Heartbeat not implemented")
+
+var xxx_messageInfo_OperateJobTasksResponse proto.InternalMessageInfo
+
+func (m *OperateJobTasksResponse) GetErr() *Error {
+	if m != nil {
+		return m.Err
+	}
+	return nil
 }
-func (*UnimplementedMasterServer) ScheduleTask(ctx context.Context, req *ScheduleTaskRequest) (*ScheduleTaskResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ScheduleTask not implemented")
+
+type UpdateJobSourceRequest struct {
+	JobId    string `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	SourceId string `protobuf:"bytes,2,opt,name=source_id,json=sourceId,proto3" json:"source_id,omitempty"`
+	// config is the new config of the source, in the same format as an
+	// upstream in the job config.
+	Config []byte `protobuf:"bytes,3,opt,name=config,proto3" json:"config,omitempty"`
 }
-func (*UnimplementedMasterServer) RegisterMetaStore(ctx context.Context, req *RegisterMetaStoreRequest) (*RegisterMetaStoreResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method RegisterMetaStore not implemented")
+
+func (m *UpdateJobSourceRequest) Reset()         { *m = UpdateJobSourceRequest{} }
+func (m *UpdateJobSourceRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateJobSourceRequest) ProtoMessage()    {}
+func (*UpdateJobSourceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{24}
 }
-func (*UnimplementedMasterServer) QueryMetaStore(ctx context.Context, req *QueryMetaStoreRequest) (*QueryMetaStoreResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method QueryMetaStore not implemented")
+func (m *UpdateJobSourceRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
 }
-func (*UnimplementedMasterServer) ReportExecutorWorkload(ctx context.Context, req *ExecWorkloadRequest) (*ExecWorkloadResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ReportExecutorWorkload not implemented")
+func (m *UpdateJobSourceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_UpdateJobSourceRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
 }
-func (*UnimplementedMasterServer) PersistResource(ctx context.Context, req *PersistResourceRequest) (*PersistResourceResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method PersistResource not implemented")
+func (m *UpdateJobSourceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateJobSourceRequest.Merge(m, src)
 }
-
-func RegisterMasterServer(s *grpc.Server, srv MasterServer) {
-	s.RegisterService(&_Master_serviceDesc, srv)
+func (m *UpdateJobSourceRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *UpdateJobSourceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateJobSourceRequest.DiscardUnknown(m)
 }
 
-func _Master_RegisterExecutor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RegisterExecutorRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(MasterServer).RegisterExecutor(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/pb.Master/RegisterExecutor",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MasterServer).RegisterExecutor(ctx, req.(*RegisterExecutorRequest))
+var xxx_messageInfo_UpdateJobSourceRequest proto.InternalMessageInfo
+
+func (m *UpdateJobSourceRequest) GetJobId() string {
+	if m != nil {
+		return m.JobId
 	}
-	return interceptor(ctx, in, info, handler)
+	return ""
 }
 
-func _Master_SubmitJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SubmitJobRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(MasterServer).SubmitJob(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/pb.Master/SubmitJob",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MasterServer).SubmitJob(ctx, req.(*SubmitJobRequest))
+func (m *UpdateJobSourceRequest) GetSourceId() string {
+	if m != nil {
+		return m.SourceId
 	}
-	return interceptor(ctx, in, info, handler)
+	return ""
 }
 
-func _Master_QueryJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(QueryJobRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(MasterServer).QueryJob(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/pb.Master/QueryJob",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MasterServer).QueryJob(ctx, req.(*QueryJobRequest))
+func (m *UpdateJobSourceRequest) GetConfig() []byte {
+	if m != nil {
+		return m.Config
 	}
-	return interceptor(ctx, in, info, handler)
+	return nil
 }
 
-func _Master_PauseJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PauseJobRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(MasterServer).PauseJob(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/pb.Master/PauseJob",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MasterServer).PauseJob(ctx, req.(*PauseJobRequest))
-	}
-	return interceptor(ctx, in, info, handler)
+type UpdateJobSourceResponse struct {
+	Err *Error `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
 }
 
-func _Master_CancelJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CancelJobRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(MasterServer).CancelJob(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/pb.Master/CancelJob",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MasterServer).CancelJob(ctx, req.(*CancelJobRequest))
+func (m *UpdateJobSourceResponse) Reset()         { *m = UpdateJobSourceResponse{} }
+func (m *UpdateJobSourceResponse) String() string { return proto.CompactTextString(m) }
+func (*UpdateJobSourceResponse) ProtoMessage()    {}
+func (*UpdateJobSourceResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{25}
+}
+func (m *UpdateJobSourceResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *UpdateJobSourceResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_UpdateJobSourceResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return interceptor(ctx, in, info, handler)
+}
+func (m *UpdateJobSourceResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateJobSourceResponse.Merge(m, src)
+}
+func (m *UpdateJobSourceResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *UpdateJobSourceResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateJobSourceResponse.DiscardUnknown(m)
 }
 
-func _Master_Heartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(HeartbeatRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(MasterServer).Heartbeat(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/pb.Master/Heartbeat",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MasterServer).Heartbeat(ctx, req.(*HeartbeatRequest))
+var xxx_messageInfo_UpdateJobSourceResponse proto.InternalMessageInfo
+
+func (m *UpdateJobSourceResponse) GetErr() *Error {
+	if m != nil {
+		return m.Err
 	}
-	return interceptor(ctx, in, info, handler)
+	return nil
 }
 
-func _Master_ScheduleTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ScheduleTaskRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(MasterServer).ScheduleTask(ctx, in)
+type JobSchedule struct {
+	// schedule_id is assigned by server master when a schedule is created.
+	ScheduleId string  `protobuf:"bytes,1,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	Tp         JobType `protobuf:"varint,2,opt,name=tp,proto3,enum=pb.JobType" json:"tp,omitempty"`
+	Config     []byte  `protobuf:"bytes,3,opt,name=config,proto3" json:"config,omitempty"`
+	User       string  `protobuf:"bytes,4,opt,name=user,proto3" json:"user,omitempty"`
+	// cron is a standard cron expression of five fields, in UTC.
+	Cron    string        `protobuf:"bytes,5,opt,name=cron,proto3" json:"cron,omitempty"`
+	CatchUp CatchUpPolicy `protobuf:"varint,6,opt,name=catch_up,json=catchUp,proto3,enum=pb.CatchUpPolicy" json:"catch_up,omitempty"`
+	Paused  bool          `protobuf:"varint,7,opt,name=paused,proto3" json:"paused,omitempty"`
+	// last_fire_time_ms and next_fire_time_ms are unix timestamps in
+	// milliseconds, they are ignored in requests.
+	LastFireTimeMs int64 `protobuf:"varint,8,opt,name=last_fire_time_ms,json=lastFireTimeMs,proto3" json:"last_fire_time_ms,omitempty"`
+	NextFireTimeMs int64 `protobuf:"varint,9,opt,name=next_fire_time_ms,json=nextFireTimeMs,proto3" json:"next_fire_time_ms,omitempty"`
+}
+
+func (m *JobSchedule) Reset()         { *m = JobSchedule{} }
+func (m *JobSchedule) String() string { return proto.CompactTextString(m) }
+func (*JobSchedule) ProtoMessage()    {}
+func (*JobSchedule) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{26}
+}
+func (m *JobSchedule) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *JobSchedule) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_JobSchedule.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/pb.Master/ScheduleTask",
+}
+func (m *JobSchedule) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobSchedule.Merge(m, src)
+}
+func (m *JobSchedule) XXX_Size() int {
+	return m.Size()
+}
+func (m *JobSchedule) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobSchedule.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JobSchedule proto.InternalMessageInfo
+
+func (m *JobSchedule) GetScheduleId() string {
+	if m != nil {
+		return m.ScheduleId
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MasterServer).ScheduleTask(ctx, req.(*ScheduleTaskRequest))
+	return ""
+}
+
+func (m *JobSchedule) GetTp() JobType {
+	if m != nil {
+		return m.Tp
 	}
-	return interceptor(ctx, in, info, handler)
+	return JobType_CVSDemo
 }
 
-func _Master_RegisterMetaStore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RegisterMetaStoreRequest)
-	if err := dec(in); err != nil {
-		return nil, err
+func (m *JobSchedule) GetConfig() []byte {
+	if m != nil {
+		return m.Config
 	}
-	if interceptor == nil {
-		return srv.(MasterServer).RegisterMetaStore(ctx, in)
+	return nil
+}
+
+func (m *JobSchedule) GetUser() string {
+	if m != nil {
+		return m.User
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/pb.Master/RegisterMetaStore",
+	return ""
+}
+
+func (m *JobSchedule) GetCron() string {
+	if m != nil {
+		return m.Cron
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MasterServer).RegisterMetaStore(ctx, req.(*RegisterMetaStoreRequest))
+	return ""
+}
+
+func (m *JobSchedule) GetCatchUp() CatchUpPolicy {
+	if m != nil {
+		return m.CatchUp
 	}
-	return interceptor(ctx, in, info, handler)
+	return CatchUpPolicy_CatchUpSkip
 }
 
-func _Master_QueryMetaStore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(QueryMetaStoreRequest)
-	if err := dec(in); err != nil {
-		return nil, err
+func (m *JobSchedule) GetPaused() bool {
+	if m != nil {
+		return m.Paused
 	}
-	if interceptor == nil {
-		return srv.(MasterServer).QueryMetaStore(ctx, in)
+	return false
+}
+
+func (m *JobSchedule) GetLastFireTimeMs() int64 {
+	if m != nil {
+		return m.LastFireTimeMs
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/pb.Master/QueryMetaStore",
+	return 0
+}
+
+func (m *JobSchedule) GetNextFireTimeMs() int64 {
+	if m != nil {
+		return m.NextFireTimeMs
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MasterServer).QueryMetaStore(ctx, req.(*QueryMetaStoreRequest))
+	return 0
+}
+
+type CreateJobScheduleRequest struct {
+	Schedule *JobSchedule `protobuf:"bytes,1,opt,name=schedule,proto3" json:"schedule,omitempty"`
+}
+
+func (m *CreateJobScheduleRequest) Reset()         { *m = CreateJobScheduleRequest{} }
+func (m *CreateJobScheduleRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateJobScheduleRequest) ProtoMessage()    {}
+func (*CreateJobScheduleRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{27}
+}
+func (m *CreateJobScheduleRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CreateJobScheduleRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CreateJobScheduleRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return interceptor(ctx, in, info, handler)
+}
+func (m *CreateJobScheduleRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateJobScheduleRequest.Merge(m, src)
+}
+func (m *CreateJobScheduleRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *CreateJobScheduleRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateJobScheduleRequest.DiscardUnknown(m)
 }
 
-func _Master_ReportExecutorWorkload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ExecWorkloadRequest)
-	if err := dec(in); err != nil {
-		return nil, err
+var xxx_messageInfo_CreateJobScheduleRequest proto.InternalMessageInfo
+
+func (m *CreateJobScheduleRequest) GetSchedule() *JobSchedule {
+	if m != nil {
+		return m.Schedule
 	}
-	if interceptor == nil {
-		return srv.(MasterServer).ReportExecutorWorkload(ctx, in)
+	return nil
+}
+
+type CreateJobScheduleResponse struct {
+	Err        *Error `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
+	ScheduleId string `protobuf:"bytes,2,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+}
+
+func (m *CreateJobScheduleResponse) Reset()         { *m = CreateJobScheduleResponse{} }
+func (m *CreateJobScheduleResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateJobScheduleResponse) ProtoMessage()    {}
+func (*CreateJobScheduleResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{28}
+}
+func (m *CreateJobScheduleResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CreateJobScheduleResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CreateJobScheduleResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/pb.Master/ReportExecutorWorkload",
+}
+func (m *CreateJobScheduleResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateJobScheduleResponse.Merge(m, src)
+}
+func (m *CreateJobScheduleResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *CreateJobScheduleResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateJobScheduleResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateJobScheduleResponse proto.InternalMessageInfo
+
+func (m *CreateJobScheduleResponse) GetErr() *Error {
+	if m != nil {
+		return m.Err
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MasterServer).ReportExecutorWorkload(ctx, req.(*ExecWorkloadRequest))
+	return nil
+}
+
+func (m *CreateJobScheduleResponse) GetScheduleId() string {
+	if m != nil {
+		return m.ScheduleId
 	}
-	return interceptor(ctx, in, info, handler)
+	return ""
 }
 
-func _Master_PersistResource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PersistResourceRequest)
-	if err := dec(in); err != nil {
-		return nil, err
+// UpdateJobScheduleRequest replaces the spec of an existing schedule.
+type UpdateJobScheduleRequest struct {
+	Schedule *JobSchedule `protobuf:"bytes,1,opt,name=schedule,proto3" json:"schedule,omitempty"`
+}
+
+func (m *UpdateJobScheduleRequest) Reset()         { *m = UpdateJobScheduleRequest{} }
+func (m *UpdateJobScheduleRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateJobScheduleRequest) ProtoMessage()    {}
+func (*UpdateJobScheduleRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{29}
+}
+func (m *UpdateJobScheduleRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *UpdateJobScheduleRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_UpdateJobScheduleRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	if interceptor == nil {
-		return srv.(MasterServer).PersistResource(ctx, in)
+}
+func (m *UpdateJobScheduleRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateJobScheduleRequest.Merge(m, src)
+}
+func (m *UpdateJobScheduleRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *UpdateJobScheduleRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateJobScheduleRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateJobScheduleRequest proto.InternalMessageInfo
+
+func (m *UpdateJobScheduleRequest) GetSchedule() *JobSchedule {
+	if m != nil {
+		return m.Schedule
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/pb.Master/PersistResource",
+	return nil
+}
+
+type UpdateJobScheduleResponse struct {
+	Err *Error `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
+}
+
+func (m *UpdateJobScheduleResponse) Reset()         { *m = UpdateJobScheduleResponse{} }
+func (m *UpdateJobScheduleResponse) String() string { return proto.CompactTextString(m) }
+func (*UpdateJobScheduleResponse) ProtoMessage()    {}
+func (*UpdateJobScheduleResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{30}
+}
+func (m *UpdateJobScheduleResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *UpdateJobScheduleResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_UpdateJobScheduleResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MasterServer).PersistResource(ctx, req.(*PersistResourceRequest))
+}
+func (m *UpdateJobScheduleResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateJobScheduleResponse.Merge(m, src)
+}
+func (m *UpdateJobScheduleResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *UpdateJobScheduleResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateJobScheduleResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateJobScheduleResponse proto.InternalMessageInfo
+
+func (m *UpdateJobScheduleResponse) GetErr() *Error {
+	if m != nil {
+		return m.Err
 	}
-	return interceptor(ctx, in, info, handler)
+	return nil
 }
 
-var _Master_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "pb.Master",
-	HandlerType: (*MasterServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "RegisterExecutor",
-			Handler:    _Master_RegisterExecutor_Handler,
-		},
-		{
-			MethodName: "SubmitJob",
-			Handler:    _Master_SubmitJob_Handler,
-		},
-		{
-			MethodName: "QueryJob",
-			Handler:    _Master_QueryJob_Handler,
-		},
-		{
-			MethodName: "PauseJob",
-			Handler:    _Master_PauseJob_Handler,
-		},
-		{
-			MethodName: "CancelJob",
-			Handler:    _Master_CancelJob_Handler,
-		},
-		{
-			MethodName: "Heartbeat",
-			Handler:    _Master_Heartbeat_Handler,
-		},
-		{
-			MethodName: "ScheduleTask",
-			Handler:    _Master_ScheduleTask_Handler,
-		},
-		{
-			MethodName: "RegisterMetaStore",
-			Handler:    _Master_RegisterMetaStore_Handler,
-		},
-		{
-			MethodName: "QueryMetaStore",
-			Handler:    _Master_QueryMetaStore_Handler,
-		},
-		{
-			MethodName: "ReportExecutorWorkload",
-			Handler:    _Master_ReportExecutorWorkload_Handler,
-		},
-		{
-			MethodName: "PersistResource",
-			Handler:    _Master_PersistResource_Handler,
-		},
-	},
-	Streams:  []grpc.StreamDesc{},
-	Metadata: "master.proto",
+type DeleteJobScheduleRequest struct {
+	ScheduleId string `protobuf:"bytes,1,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
 }
 
-func (m *HeartbeatRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+func (m *DeleteJobScheduleRequest) Reset()         { *m = DeleteJobScheduleRequest{} }
+func (m *DeleteJobScheduleRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteJobScheduleRequest) ProtoMessage()    {}
+func (*DeleteJobScheduleRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{31}
 }
-
-func (m *HeartbeatRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *DeleteJobScheduleRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
 }
-
-func (m *HeartbeatRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Ttl != 0 {
-		i = encodeVarintMaster(dAtA, i, uint64(m.Ttl))
-		i--
-		dAtA[i] = 0x28
-	}
-	if m.Timestamp != 0 {
-		i = encodeVarintMaster(dAtA, i, uint64(m.Timestamp))
-		i--
-		dAtA[i] = 0x20
-	}
-	if m.Status != 0 {
-		i = encodeVarintMaster(dAtA, i, uint64(m.Status))
-		i--
-		dAtA[i] = 0x18
-	}
-	if m.ResourceUsage != 0 {
-		i = encodeVarintMaster(dAtA, i, uint64(m.ResourceUsage))
-		i--
-		dAtA[i] = 0x10
-	}
-	if len(m.ExecutorId) > 0 {
-		i -= len(m.ExecutorId)
-		copy(dAtA[i:], m.ExecutorId)
-		i = encodeVarintMaster(dAtA, i, uint64(len(m.ExecutorId)))
-		i--
-		dAtA[i] = 0xa
+func (m *DeleteJobScheduleRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DeleteJobScheduleRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return len(dAtA) - i, nil
+}
+func (m *DeleteJobScheduleRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteJobScheduleRequest.Merge(m, src)
+}
+func (m *DeleteJobScheduleRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *DeleteJobScheduleRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteJobScheduleRequest.DiscardUnknown(m)
 }
 
-func (m *HeartbeatResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_DeleteJobScheduleRequest proto.InternalMessageInfo
+
+func (m *DeleteJobScheduleRequest) GetScheduleId() string {
+	if m != nil {
+		return m.ScheduleId
 	}
-	return dAtA[:n], nil
+	return ""
 }
 
-func (m *HeartbeatResponse) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+type DeleteJobScheduleResponse struct {
+	Err *Error `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
 }
 
-func (m *HeartbeatResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Addrs) > 0 {
-		for iNdEx := len(m.Addrs) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.Addrs[iNdEx])
-			copy(dAtA[i:], m.Addrs[iNdEx])
-			i = encodeVarintMaster(dAtA, i, uint64(len(m.Addrs[iNdEx])))
-			i--
-			dAtA[i] = 0x1a
+func (m *DeleteJobScheduleResponse) Reset()         { *m = DeleteJobScheduleResponse{} }
+func (m *DeleteJobScheduleResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteJobScheduleResponse) ProtoMessage()    {}
+func (*DeleteJobScheduleResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{32}
+}
+func (m *DeleteJobScheduleResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DeleteJobScheduleResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DeleteJobScheduleResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if len(m.Leader) > 0 {
-		i -= len(m.Leader)
-		copy(dAtA[i:], m.Leader)
-		i = encodeVarintMaster(dAtA, i, uint64(len(m.Leader)))
-		i--
-		dAtA[i] = 0x12
+}
+func (m *DeleteJobScheduleResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteJobScheduleResponse.Merge(m, src)
+}
+func (m *DeleteJobScheduleResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *DeleteJobScheduleResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteJobScheduleResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteJobScheduleResponse proto.InternalMessageInfo
+
+func (m *DeleteJobScheduleResponse) GetErr() *Error {
+	if m != nil {
+		return m.Err
 	}
-	if m.Err != nil {
-		{
-			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintMaster(dAtA, i, uint64(size))
+	return nil
+}
+
+// QueryJobSchedulesRequest queries all schedules if schedule_id is empty.
+type QueryJobSchedulesRequest struct {
+	ScheduleId string `protobuf:"bytes,1,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+}
+
+func (m *QueryJobSchedulesRequest) Reset()         { *m = QueryJobSchedulesRequest{} }
+func (m *QueryJobSchedulesRequest) String() string { return proto.CompactTextString(m) }
+func (*QueryJobSchedulesRequest) ProtoMessage()    {}
+func (*QueryJobSchedulesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{33}
+}
+func (m *QueryJobSchedulesRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryJobSchedulesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryJobSchedulesRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
-		i--
-		dAtA[i] = 0xa
+		return b[:n], nil
 	}
-	return len(dAtA) - i, nil
+}
+func (m *QueryJobSchedulesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryJobSchedulesRequest.Merge(m, src)
+}
+func (m *QueryJobSchedulesRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryJobSchedulesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryJobSchedulesRequest.DiscardUnknown(m)
 }
 
-func (m *SubmitJobRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_QueryJobSchedulesRequest proto.InternalMessageInfo
+
+func (m *QueryJobSchedulesRequest) GetScheduleId() string {
+	if m != nil {
+		return m.ScheduleId
 	}
-	return dAtA[:n], nil
+	return ""
 }
 
-func (m *SubmitJobRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+type QueryJobSchedulesResponse struct {
+	Err       *Error         `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
+	Schedules []*JobSchedule `protobuf:"bytes,2,rep,name=schedules,proto3" json:"schedules,omitempty"`
 }
 
-func (m *SubmitJobRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.User) > 0 {
-		i -= len(m.User)
-		copy(dAtA[i:], m.User)
-		i = encodeVarintMaster(dAtA, i, uint64(len(m.User)))
-		i--
-		dAtA[i] = 0x1a
-	}
-	if len(m.Config) > 0 {
-		i -= len(m.Config)
-		copy(dAtA[i:], m.Config)
-		i = encodeVarintMaster(dAtA, i, uint64(len(m.Config)))
-		i--
-		dAtA[i] = 0x12
+func (m *QueryJobSchedulesResponse) Reset()         { *m = QueryJobSchedulesResponse{} }
+func (m *QueryJobSchedulesResponse) String() string { return proto.CompactTextString(m) }
+func (*QueryJobSchedulesResponse) ProtoMessage()    {}
+func (*QueryJobSchedulesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{34}
+}
+func (m *QueryJobSchedulesResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryJobSchedulesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryJobSchedulesResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	if m.Tp != 0 {
-		i = encodeVarintMaster(dAtA, i, uint64(m.Tp))
-		i--
-		dAtA[i] = 0x8
+}
+func (m *QueryJobSchedulesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryJobSchedulesResponse.Merge(m, src)
+}
+func (m *QueryJobSchedulesResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryJobSchedulesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryJobSchedulesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryJobSchedulesResponse proto.InternalMessageInfo
+
+func (m *QueryJobSchedulesResponse) GetErr() *Error {
+	if m != nil {
+		return m.Err
 	}
-	return len(dAtA) - i, nil
+	return nil
 }
 
-func (m *QueryJobRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *QueryJobSchedulesResponse) GetSchedules() []*JobSchedule {
+	if m != nil {
+		return m.Schedules
 	}
-	return dAtA[:n], nil
+	return nil
 }
 
-func (m *QueryJobRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+// JobTemplate is a job config with placeholders of the form ${name} or
+// ${name:-default}.
+type JobTemplate struct {
+	TemplateId string  `protobuf:"bytes,1,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
+	Tp         JobType `protobuf:"varint,2,opt,name=tp,proto3,enum=pb.JobType" json:"tp,omitempty"`
+	Template   []byte  `protobuf:"bytes,3,opt,name=template,proto3" json:"template,omitempty"`
+	// params are the placeholders in the template, they are ignored in
+	// requests.
+	Params []*JobTemplateParam `protobuf:"bytes,4,rep,name=params,proto3" json:"params,omitempty"`
 }
 
-func (m *QueryJobRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.JobId) > 0 {
-		i -= len(m.JobId)
-		copy(dAtA[i:], m.JobId)
-		i = encodeVarintMaster(dAtA, i, uint64(len(m.JobId)))
-		i--
-		dAtA[i] = 0xa
+func (m *JobTemplate) Reset()         { *m = JobTemplate{} }
+func (m *JobTemplate) String() string { return proto.CompactTextString(m) }
+func (*JobTemplate) ProtoMessage()    {}
+func (*JobTemplate) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{35}
+}
+func (m *JobTemplate) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *JobTemplate) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_JobTemplate.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return len(dAtA) - i, nil
+}
+func (m *JobTemplate) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobTemplate.Merge(m, src)
+}
+func (m *JobTemplate) XXX_Size() int {
+	return m.Size()
+}
+func (m *JobTemplate) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobTemplate.DiscardUnknown(m)
 }
 
-func (m *WorkerInfo) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_JobTemplate proto.InternalMessageInfo
+
+func (m *JobTemplate) GetTemplateId() string {
+	if m != nil {
+		return m.TemplateId
 	}
-	return dAtA[:n], nil
+	return ""
 }
 
-func (m *WorkerInfo) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *JobTemplate) GetTp() JobType {
+	if m != nil {
+		return m.Tp
+	}
+	return JobType_CVSDemo
 }
 
-func (m *WorkerInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Workload != 0 {
-		i = encodeVarintMaster(dAtA, i, uint64(m.Workload))
-		i--
-		dAtA[i] = 0x38
+func (m *JobTemplate) GetTemplate() []byte {
+	if m != nil {
+		return m.Template
 	}
-	if m.LastHbTime != 0 {
-		i = encodeVarintMaster(dAtA, i, uint64(m.LastHbTime))
-		i--
-		dAtA[i] = 0x30
+	return nil
+}
+
+func (m *JobTemplate) GetParams() []*JobTemplateParam {
+	if m != nil {
+		return m.Params
 	}
-	if m.IsTombstone {
-		i--
-		if m.IsTombstone {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
+	return nil
+}
+
+type JobTemplateParam struct {
+	Name         string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	DefaultValue string `protobuf:"bytes,2,opt,name=default_value,json=defaultValue,proto3" json:"default_value,omitempty"`
+	// required is true if the placeholder has no default value.
+	Required bool `protobuf:"varint,3,opt,name=required,proto3" json:"required,omitempty"`
+}
+
+func (m *JobTemplateParam) Reset()         { *m = JobTemplateParam{} }
+func (m *JobTemplateParam) String() string { return proto.CompactTextString(m) }
+func (*JobTemplateParam) ProtoMessage()    {}
+func (*JobTemplateParam) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{36}
+}
+func (m *JobTemplateParam) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *JobTemplateParam) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_JobTemplateParam.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
-		i--
-		dAtA[i] = 0x28
-	}
-	if len(m.Config) > 0 {
-		i -= len(m.Config)
-		copy(dAtA[i:], m.Config)
-		i = encodeVarintMaster(dAtA, i, uint64(len(m.Config)))
-		i--
-		dAtA[i] = 0x22
-	}
-	if len(m.Status) > 0 {
-		i -= len(m.Status)
-		copy(dAtA[i:], m.Status)
-		i = encodeVarintMaster(dAtA, i, uint64(len(m.Status)))
-		i--
-		dAtA[i] = 0x1a
+		return b[:n], nil
 	}
-	if len(m.ExecutorId) > 0 {
-		i -= len(m.ExecutorId)
-		copy(dAtA[i:], m.ExecutorId)
-		i = encodeVarintMaster(dAtA, i, uint64(len(m.ExecutorId)))
-		i--
-		dAtA[i] = 0x12
+}
+func (m *JobTemplateParam) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobTemplateParam.Merge(m, src)
+}
+func (m *JobTemplateParam) XXX_Size() int {
+	return m.Size()
+}
+func (m *JobTemplateParam) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobTemplateParam.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JobTemplateParam proto.InternalMessageInfo
+
+func (m *JobTemplateParam) GetName() string {
+	if m != nil {
+		return m.Name
 	}
-	if len(m.Id) > 0 {
-		i -= len(m.Id)
-		copy(dAtA[i:], m.Id)
-		i = encodeVarintMaster(dAtA, i, uint64(len(m.Id)))
-		i--
-		dAtA[i] = 0xa
+	return ""
+}
+
+func (m *JobTemplateParam) GetDefaultValue() string {
+	if m != nil {
+		return m.DefaultValue
 	}
-	return len(dAtA) - i, nil
+	return ""
 }
 
-func (m *QueryJobResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *JobTemplateParam) GetRequired() bool {
+	if m != nil {
+		return m.Required
 	}
-	return dAtA[:n], nil
+	return false
 }
 
-func (m *QueryJobResponse) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+type RegisterJobTemplateRequest struct {
+	Template *JobTemplate `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
 }
 
-func (m *QueryJobResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Err != nil {
-		{
-			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintMaster(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0x2a
-	}
-	if m.JobMasterInfo != nil {
-		{
-			size, err := m.JobMasterInfo.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintMaster(dAtA, i, uint64(size))
+func (m *RegisterJobTemplateRequest) Reset()         { *m = RegisterJobTemplateRequest{} }
+func (m *RegisterJobTemplateRequest) String() string { return proto.CompactTextString(m) }
+func (*RegisterJobTemplateRequest) ProtoMessage()    {}
+func (*RegisterJobTemplateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{37}
+}
+func (m *RegisterJobTemplateRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RegisterJobTemplateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_RegisterJobTemplateRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
-		i--
-		dAtA[i] = 0x22
-	}
-	if m.Status != 0 {
-		i = encodeVarintMaster(dAtA, i, uint64(m.Status))
-		i--
-		dAtA[i] = 0x18
-	}
-	if len(m.Config) > 0 {
-		i -= len(m.Config)
-		copy(dAtA[i:], m.Config)
-		i = encodeVarintMaster(dAtA, i, uint64(len(m.Config)))
-		i--
-		dAtA[i] = 0x12
-	}
-	if m.Tp != 0 {
-		i = encodeVarintMaster(dAtA, i, uint64(m.Tp))
-		i--
-		dAtA[i] = 0x8
+		return b[:n], nil
 	}
-	return len(dAtA) - i, nil
+}
+func (m *RegisterJobTemplateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegisterJobTemplateRequest.Merge(m, src)
+}
+func (m *RegisterJobTemplateRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *RegisterJobTemplateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegisterJobTemplateRequest.DiscardUnknown(m)
 }
 
-func (m *CancelJobRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_RegisterJobTemplateRequest proto.InternalMessageInfo
+
+func (m *RegisterJobTemplateRequest) GetTemplate() *JobTemplate {
+	if m != nil {
+		return m.Template
 	}
-	return dAtA[:n], nil
+	return nil
 }
 
-func (m *CancelJobRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+type RegisterJobTemplateResponse struct {
+	Err *Error `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
 }
 
-func (m *CancelJobRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.JobIdStr) > 0 {
-		i -= len(m.JobIdStr)
-		copy(dAtA[i:], m.JobIdStr)
-		i = encodeVarintMaster(dAtA, i, uint64(len(m.JobIdStr)))
-		i--
-		dAtA[i] = 0x12
-	}
-	if m.JobId != 0 {
-		i = encodeVarintMaster(dAtA, i, uint64(m.JobId))
-		i--
-		dAtA[i] = 0x8
+func (m *RegisterJobTemplateResponse) Reset()         { *m = RegisterJobTemplateResponse{} }
+func (m *RegisterJobTemplateResponse) String() string { return proto.CompactTextString(m) }
+func (*RegisterJobTemplateResponse) ProtoMessage()    {}
+func (*RegisterJobTemplateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{38}
+}
+func (m *RegisterJobTemplateResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RegisterJobTemplateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_RegisterJobTemplateResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return len(dAtA) - i, nil
+}
+func (m *RegisterJobTemplateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegisterJobTemplateResponse.Merge(m, src)
+}
+func (m *RegisterJobTemplateResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *RegisterJobTemplateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegisterJobTemplateResponse.DiscardUnknown(m)
 }
 
-func (m *PauseJobRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_RegisterJobTemplateResponse proto.InternalMessageInfo
+
+func (m *RegisterJobTemplateResponse) GetErr() *Error {
+	if m != nil {
+		return m.Err
 	}
-	return dAtA[:n], nil
+	return nil
 }
 
-func (m *PauseJobRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+type DeleteJobTemplateRequest struct {
+	TemplateId string `protobuf:"bytes,1,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
 }
 
-func (m *PauseJobRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.JobIdStr) > 0 {
-		i -= len(m.JobIdStr)
-		copy(dAtA[i:], m.JobIdStr)
-		i = encodeVarintMaster(dAtA, i, uint64(len(m.JobIdStr)))
-		i--
-		dAtA[i] = 0x12
-	}
-	if m.JobId != 0 {
-		i = encodeVarintMaster(dAtA, i, uint64(m.JobId))
-		i--
-		dAtA[i] = 0x8
+func (m *DeleteJobTemplateRequest) Reset()         { *m = DeleteJobTemplateRequest{} }
+func (m *DeleteJobTemplateRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteJobTemplateRequest) ProtoMessage()    {}
+func (*DeleteJobTemplateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{39}
+}
+func (m *DeleteJobTemplateRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DeleteJobTemplateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DeleteJobTemplateRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return len(dAtA) - i, nil
+}
+func (m *DeleteJobTemplateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteJobTemplateRequest.Merge(m, src)
+}
+func (m *DeleteJobTemplateRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *DeleteJobTemplateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteJobTemplateRequest.DiscardUnknown(m)
 }
 
-func (m *SubmitJobResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_DeleteJobTemplateRequest proto.InternalMessageInfo
+
+func (m *DeleteJobTemplateRequest) GetTemplateId() string {
+	if m != nil {
+		return m.TemplateId
 	}
-	return dAtA[:n], nil
+	return ""
 }
 
-func (m *SubmitJobResponse) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+type DeleteJobTemplateResponse struct {
+	Err *Error `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
 }
 
-func (m *SubmitJobResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.JobIdStr) > 0 {
-		i -= len(m.JobIdStr)
-		copy(dAtA[i:], m.JobIdStr)
-		i = encodeVarintMaster(dAtA, i, uint64(len(m.JobIdStr)))
-		i--
-		dAtA[i] = 0x1a
-	}
-	if m.JobId != 0 {
-		i = encodeVarintMaster(dAtA, i, uint64(m.JobId))
-		i--
-		dAtA[i] = 0x10
-	}
-	if m.Err != nil {
-		{
-			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintMaster(dAtA, i, uint64(size))
+func (m *DeleteJobTemplateResponse) Reset()         { *m = DeleteJobTemplateResponse{} }
+func (m *DeleteJobTemplateResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteJobTemplateResponse) ProtoMessage()    {}
+func (*DeleteJobTemplateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{40}
+}
+func (m *DeleteJobTemplateResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DeleteJobTemplateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DeleteJobTemplateResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
-		i--
-		dAtA[i] = 0xa
+		return b[:n], nil
 	}
-	return len(dAtA) - i, nil
+}
+func (m *DeleteJobTemplateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteJobTemplateResponse.Merge(m, src)
+}
+func (m *DeleteJobTemplateResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *DeleteJobTemplateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteJobTemplateResponse.DiscardUnknown(m)
 }
 
-func (m *PauseJobResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_DeleteJobTemplateResponse proto.InternalMessageInfo
+
+func (m *DeleteJobTemplateResponse) GetErr() *Error {
+	if m != nil {
+		return m.Err
 	}
-	return dAtA[:n], nil
+	return nil
 }
 
-func (m *PauseJobResponse) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+// QueryJobTemplatesRequest queries all templates if template_id is empty.
+type QueryJobTemplatesRequest struct {
+	TemplateId string `protobuf:"bytes,1,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
 }
 
-func (m *PauseJobResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Err != nil {
-		{
-			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintMaster(dAtA, i, uint64(size))
+func (m *QueryJobTemplatesRequest) Reset()         { *m = QueryJobTemplatesRequest{} }
+func (m *QueryJobTemplatesRequest) String() string { return proto.CompactTextString(m) }
+func (*QueryJobTemplatesRequest) ProtoMessage()    {}
+func (*QueryJobTemplatesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{41}
+}
+func (m *QueryJobTemplatesRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryJobTemplatesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryJobTemplatesRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
-		i--
-		dAtA[i] = 0xa
+		return b[:n], nil
 	}
-	return len(dAtA) - i, nil
+}
+func (m *QueryJobTemplatesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryJobTemplatesRequest.Merge(m, src)
+}
+func (m *QueryJobTemplatesRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryJobTemplatesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryJobTemplatesRequest.DiscardUnknown(m)
 }
 
-func (m *CancelJobResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_QueryJobTemplatesRequest proto.InternalMessageInfo
+
+func (m *QueryJobTemplatesRequest) GetTemplateId() string {
+	if m != nil {
+		return m.TemplateId
 	}
-	return dAtA[:n], nil
+	return ""
 }
 
-func (m *CancelJobResponse) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+type QueryJobTemplatesResponse struct {
+	Err       *Error         `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
+	Templates []*JobTemplate `protobuf:"bytes,2,rep,name=templates,proto3" json:"templates,omitempty"`
 }
 
-func (m *CancelJobResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Err != nil {
-		{
-			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintMaster(dAtA, i, uint64(size))
+func (m *QueryJobTemplatesResponse) Reset()         { *m = QueryJobTemplatesResponse{} }
+func (m *QueryJobTemplatesResponse) String() string { return proto.CompactTextString(m) }
+func (*QueryJobTemplatesResponse) ProtoMessage()    {}
+func (*QueryJobTemplatesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{42}
+}
+func (m *QueryJobTemplatesResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryJobTemplatesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryJobTemplatesResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
-		i--
-		dAtA[i] = 0xa
+		return b[:n], nil
 	}
-	return len(dAtA) - i, nil
 }
-
-func (m *RegisterExecutorRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+func (m *QueryJobTemplatesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryJobTemplatesResponse.Merge(m, src)
 }
-
-func (m *RegisterExecutorRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *QueryJobTemplatesResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryJobTemplatesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryJobTemplatesResponse.DiscardUnknown(m)
 }
 
-func (m *RegisterExecutorRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Capability != 0 {
-		i = encodeVarintMaster(dAtA, i, uint64(m.Capability))
-		i--
-		dAtA[i] = 0x18
-	}
-	if len(m.Version) > 0 {
-		i -= len(m.Version)
-		copy(dAtA[i:], m.Version)
-		i = encodeVarintMaster(dAtA, i, uint64(len(m.Version)))
-		i--
-		dAtA[i] = 0x12
-	}
-	if len(m.Address) > 0 {
-		i -= len(m.Address)
-		copy(dAtA[i:], m.Address)
-		i = encodeVarintMaster(dAtA, i, uint64(len(m.Address)))
-		i--
-		dAtA[i] = 0xa
+var xxx_messageInfo_QueryJobTemplatesResponse proto.InternalMessageInfo
+
+func (m *QueryJobTemplatesResponse) GetErr() *Error {
+	if m != nil {
+		return m.Err
 	}
-	return len(dAtA) - i, nil
+	return nil
 }
 
-func (m *RegisterExecutorResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *QueryJobTemplatesResponse) GetTemplates() []*JobTemplate {
+	if m != nil {
+		return m.Templates
 	}
-	return dAtA[:n], nil
+	return nil
 }
 
-func (m *RegisterExecutorResponse) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+type RegisterExecutorRequest struct {
+	// dm need 'worker-name' to locate the worker.
+	// TODO: Do we really need a "worker name"? Can we use address to identify an executor?
+	Address    string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Version    string `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	Capability int64  `protobuf:"varint,3,opt,name=capability,proto3" json:"capability,omitempty"`
+	// executor_id is set when an executor registers again after a master
+	// failover, so that it keeps the ID it was assigned before.
+	ExecutorId string `protobuf:"bytes,4,opt,name=executor_id,json=executorId,proto3" json:"executor_id,omitempty"`
+	// protocol_version is the same as HeartbeatRequest's, an executor of an
+	// incompatible version is rejected.
+	ProtocolVersion int32 `protobuf:"varint,5,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`
+	// resources is the capacity of the executor in the dimensions other than
+	// cpu, e.g. memory, disk or custom resources such as "gpu".
+	Resources map[string]int64 `protobuf:"bytes,6,rep,name=resources,proto3" json:"resources,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// labels describe where the executor runs, such as the pod, node and
+	// zone when it runs on kubernetes. The zone label is used to spread the
+	// workers of a job.
+	Labels map[string]string `protobuf:"bytes,7,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
-func (m *RegisterExecutorResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.ExecutorId) > 0 {
-		i -= len(m.ExecutorId)
-		copy(dAtA[i:], m.ExecutorId)
-		i = encodeVarintMaster(dAtA, i, uint64(len(m.ExecutorId)))
-		i--
-		dAtA[i] = 0x12
-	}
-	if m.Err != nil {
-		{
-			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintMaster(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0xa
-	}
-	return len(dAtA) - i, nil
-}
-
-func (m *ScheduleTaskRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+func (m *RegisterExecutorRequest) Reset()         { *m = RegisterExecutorRequest{} }
+func (m *RegisterExecutorRequest) String() string { return proto.CompactTextString(m) }
+func (*RegisterExecutorRequest) ProtoMessage()    {}
+func (*RegisterExecutorRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{43}
 }
-
-func (m *ScheduleTaskRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *RegisterExecutorRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
 }
-
-func (m *ScheduleTaskRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.ResourceRequirements) > 0 {
-		for iNdEx := len(m.ResourceRequirements) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.ResourceRequirements[iNdEx])
-			copy(dAtA[i:], m.ResourceRequirements[iNdEx])
-			i = encodeVarintMaster(dAtA, i, uint64(len(m.ResourceRequirements[iNdEx])))
-			i--
-			dAtA[i] = 0x1a
+func (m *RegisterExecutorRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_RegisterExecutorRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if m.Cost != 0 {
-		i = encodeVarintMaster(dAtA, i, uint64(m.Cost))
-		i--
-		dAtA[i] = 0x10
-	}
-	if len(m.TaskId) > 0 {
-		i -= len(m.TaskId)
-		copy(dAtA[i:], m.TaskId)
-		i = encodeVarintMaster(dAtA, i, uint64(len(m.TaskId)))
-		i--
-		dAtA[i] = 0xa
-	}
-	return len(dAtA) - i, nil
+}
+func (m *RegisterExecutorRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegisterExecutorRequest.Merge(m, src)
+}
+func (m *RegisterExecutorRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *RegisterExecutorRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegisterExecutorRequest.DiscardUnknown(m)
 }
 
-func (m *ScheduleTaskResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_RegisterExecutorRequest proto.InternalMessageInfo
+
+func (m *RegisterExecutorRequest) GetAddress() string {
+	if m != nil {
+		return m.Address
 	}
-	return dAtA[:n], nil
+	return ""
 }
 
-func (m *ScheduleTaskResponse) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *RegisterExecutorRequest) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
 }
 
-func (m *ScheduleTaskResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.ExecutorAddr) > 0 {
-		i -= len(m.ExecutorAddr)
-		copy(dAtA[i:], m.ExecutorAddr)
-		i = encodeVarintMaster(dAtA, i, uint64(len(m.ExecutorAddr)))
-		i--
-		dAtA[i] = 0x12
-	}
-	if len(m.ExecutorId) > 0 {
-		i -= len(m.ExecutorId)
-		copy(dAtA[i:], m.ExecutorId)
-		i = encodeVarintMaster(dAtA, i, uint64(len(m.ExecutorId)))
-		i--
-		dAtA[i] = 0xa
+func (m *RegisterExecutorRequest) GetCapability() int64 {
+	if m != nil {
+		return m.Capability
 	}
-	return len(dAtA) - i, nil
+	return 0
 }
 
-func (m *ExecWorkload) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *RegisterExecutorRequest) GetExecutorId() string {
+	if m != nil {
+		return m.ExecutorId
 	}
-	return dAtA[:n], nil
+	return ""
 }
 
-func (m *ExecWorkload) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *RegisterExecutorRequest) GetProtocolVersion() int32 {
+	if m != nil {
+		return m.ProtocolVersion
+	}
+	return 0
 }
 
-func (m *ExecWorkload) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Usage != 0 {
-		i = encodeVarintMaster(dAtA, i, uint64(m.Usage))
-		i--
-		dAtA[i] = 0x10
-	}
-	if m.Tp != 0 {
-		i = encodeVarintMaster(dAtA, i, uint64(m.Tp))
-		i--
-		dAtA[i] = 0x8
+func (m *RegisterExecutorRequest) GetResources() map[string]int64 {
+	if m != nil {
+		return m.Resources
 	}
-	return len(dAtA) - i, nil
+	return nil
 }
 
-func (m *ExecWorkloadRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *RegisterExecutorRequest) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
 	}
-	return dAtA[:n], nil
+	return nil
 }
 
-func (m *ExecWorkloadRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+type RegisterExecutorResponse struct {
+	Err        *Error `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
+	ExecutorId string `protobuf:"bytes,2,opt,name=executor_id,json=executorId,proto3" json:"executor_id,omitempty"`
+	// cluster_protocol_version is the same as HeartbeatResponse's.
+	ClusterProtocolVersion int32 `protobuf:"varint,3,opt,name=cluster_protocol_version,json=clusterProtocolVersion,proto3" json:"cluster_protocol_version,omitempty"`
 }
 
-func (m *ExecWorkloadRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.ResourceId) > 0 {
-		for iNdEx := len(m.ResourceId) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.ResourceId[iNdEx])
-			copy(dAtA[i:], m.ResourceId[iNdEx])
-			i = encodeVarintMaster(dAtA, i, uint64(len(m.ResourceId[iNdEx])))
-			i--
-			dAtA[i] = 0x1a
-		}
-	}
-	if len(m.Workloads) > 0 {
-		for iNdEx := len(m.Workloads) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Workloads[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintMaster(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x12
+func (m *RegisterExecutorResponse) Reset()         { *m = RegisterExecutorResponse{} }
+func (m *RegisterExecutorResponse) String() string { return proto.CompactTextString(m) }
+func (*RegisterExecutorResponse) ProtoMessage()    {}
+func (*RegisterExecutorResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{44}
+}
+func (m *RegisterExecutorResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RegisterExecutorResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_RegisterExecutorResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if len(m.ExecutorId) > 0 {
-		i -= len(m.ExecutorId)
-		copy(dAtA[i:], m.ExecutorId)
-		i = encodeVarintMaster(dAtA, i, uint64(len(m.ExecutorId)))
-		i--
-		dAtA[i] = 0xa
-	}
-	return len(dAtA) - i, nil
+}
+func (m *RegisterExecutorResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegisterExecutorResponse.Merge(m, src)
+}
+func (m *RegisterExecutorResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *RegisterExecutorResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegisterExecutorResponse.DiscardUnknown(m)
 }
 
-func (m *ExecWorkloadResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_RegisterExecutorResponse proto.InternalMessageInfo
+
+func (m *RegisterExecutorResponse) GetErr() *Error {
+	if m != nil {
+		return m.Err
 	}
-	return dAtA[:n], nil
+	return nil
 }
 
-func (m *ExecWorkloadResponse) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *RegisterExecutorResponse) GetExecutorId() string {
+	if m != nil {
+		return m.ExecutorId
+	}
+	return ""
 }
 
-func (m *ExecWorkloadResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Err != nil {
-		{
-			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintMaster(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0xa
+func (m *RegisterExecutorResponse) GetClusterProtocolVersion() int32 {
+	if m != nil {
+		return m.ClusterProtocolVersion
 	}
-	return len(dAtA) - i, nil
+	return 0
 }
 
-func (m *PersistResourceRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+type CordonExecutorRequest struct {
+	ExecutorId string      `protobuf:"bytes,1,opt,name=executor_id,json=executorId,proto3" json:"executor_id,omitempty"`
+	State      CordonState `protobuf:"varint,2,opt,name=state,proto3,enum=pb.CordonState" json:"state,omitempty"`
 }
 
-func (m *PersistResourceRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *CordonExecutorRequest) Reset()         { *m = CordonExecutorRequest{} }
+func (m *CordonExecutorRequest) String() string { return proto.CompactTextString(m) }
+func (*CordonExecutorRequest) ProtoMessage()    {}
+func (*CordonExecutorRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{45}
 }
-
-func (m *PersistResourceRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.ResourceId) > 0 {
-		i -= len(m.ResourceId)
-		copy(dAtA[i:], m.ResourceId)
-		i = encodeVarintMaster(dAtA, i, uint64(len(m.ResourceId)))
-		i--
-		dAtA[i] = 0x12
+func (m *CordonExecutorRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CordonExecutorRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CordonExecutorRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	if len(m.ExecutorId) > 0 {
-		i -= len(m.ExecutorId)
-		copy(dAtA[i:], m.ExecutorId)
-		i = encodeVarintMaster(dAtA, i, uint64(len(m.ExecutorId)))
-		i--
-		dAtA[i] = 0xa
+}
+func (m *CordonExecutorRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CordonExecutorRequest.Merge(m, src)
+}
+func (m *CordonExecutorRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *CordonExecutorRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CordonExecutorRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CordonExecutorRequest proto.InternalMessageInfo
+
+func (m *CordonExecutorRequest) GetExecutorId() string {
+	if m != nil {
+		return m.ExecutorId
 	}
-	return len(dAtA) - i, nil
+	return ""
 }
 
-func (m *PersistResourceResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *CordonExecutorRequest) GetState() CordonState {
+	if m != nil {
+		return m.State
 	}
-	return dAtA[:n], nil
+	return CordonState_Uncordoned
 }
 
-func (m *PersistResourceResponse) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+type CordonExecutorResponse struct {
+	Err *Error `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
 }
 
-func (m *PersistResourceResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Err != nil {
-		{
-			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintMaster(dAtA, i, uint64(size))
+func (m *CordonExecutorResponse) Reset()         { *m = CordonExecutorResponse{} }
+func (m *CordonExecutorResponse) String() string { return proto.CompactTextString(m) }
+func (*CordonExecutorResponse) ProtoMessage()    {}
+func (*CordonExecutorResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{46}
+}
+func (m *CordonExecutorResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CordonExecutorResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CordonExecutorResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
-		i--
-		dAtA[i] = 0xa
+		return b[:n], nil
 	}
-	return len(dAtA) - i, nil
+}
+func (m *CordonExecutorResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CordonExecutorResponse.Merge(m, src)
+}
+func (m *CordonExecutorResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *CordonExecutorResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CordonExecutorResponse.DiscardUnknown(m)
 }
 
-func encodeVarintMaster(dAtA []byte, offset int, v uint64) int {
-	offset -= sovMaster(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+var xxx_messageInfo_CordonExecutorResponse proto.InternalMessageInfo
+
+func (m *CordonExecutorResponse) GetErr() *Error {
+	if m != nil {
+		return m.Err
 	}
-	dAtA[offset] = uint8(v)
-	return base
+	return nil
 }
-func (m *HeartbeatRequest) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = len(m.ExecutorId)
-	if l > 0 {
-		n += 1 + l + sovMaster(uint64(l))
-	}
-	if m.ResourceUsage != 0 {
-		n += 1 + sovMaster(uint64(m.ResourceUsage))
-	}
-	if m.Status != 0 {
-		n += 1 + sovMaster(uint64(m.Status))
-	}
-	if m.Timestamp != 0 {
-		n += 1 + sovMaster(uint64(m.Timestamp))
-	}
-	if m.Ttl != 0 {
-		n += 1 + sovMaster(uint64(m.Ttl))
-	}
-	return n
+
+type ExecutorCordon struct {
+	ExecutorId string      `protobuf:"bytes,1,opt,name=executor_id,json=executorId,proto3" json:"executor_id,omitempty"`
+	State      CordonState `protobuf:"varint,2,opt,name=state,proto3,enum=pb.CordonState" json:"state,omitempty"`
 }
 
-func (m *HeartbeatResponse) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.Err != nil {
-		l = m.Err.Size()
-		n += 1 + l + sovMaster(uint64(l))
-	}
-	l = len(m.Leader)
-	if l > 0 {
-		n += 1 + l + sovMaster(uint64(l))
-	}
-	if len(m.Addrs) > 0 {
-		for _, s := range m.Addrs {
-			l = len(s)
-			n += 1 + l + sovMaster(uint64(l))
+func (m *ExecutorCordon) Reset()         { *m = ExecutorCordon{} }
+func (m *ExecutorCordon) String() string { return proto.CompactTextString(m) }
+func (*ExecutorCordon) ProtoMessage()    {}
+func (*ExecutorCordon) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{47}
+}
+func (m *ExecutorCordon) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ExecutorCordon) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ExecutorCordon.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	return n
+}
+func (m *ExecutorCordon) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExecutorCordon.Merge(m, src)
+}
+func (m *ExecutorCordon) XXX_Size() int {
+	return m.Size()
+}
+func (m *ExecutorCordon) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExecutorCordon.DiscardUnknown(m)
 }
 
-func (m *SubmitJobRequest) Size() (n int) {
-	if m == nil {
-		return 0
+var xxx_messageInfo_ExecutorCordon proto.InternalMessageInfo
+
+func (m *ExecutorCordon) GetExecutorId() string {
+	if m != nil {
+		return m.ExecutorId
 	}
-	var l int
-	_ = l
-	if m.Tp != 0 {
-		n += 1 + sovMaster(uint64(m.Tp))
+	return ""
+}
+
+func (m *ExecutorCordon) GetState() CordonState {
+	if m != nil {
+		return m.State
 	}
-	l = len(m.Config)
-	if l > 0 {
-		n += 1 + l + sovMaster(uint64(l))
+	return CordonState_Uncordoned
+}
+
+type QueryExecutorCordonsRequest struct {
+}
+
+func (m *QueryExecutorCordonsRequest) Reset()         { *m = QueryExecutorCordonsRequest{} }
+func (m *QueryExecutorCordonsRequest) String() string { return proto.CompactTextString(m) }
+func (*QueryExecutorCordonsRequest) ProtoMessage()    {}
+func (*QueryExecutorCordonsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{48}
+}
+func (m *QueryExecutorCordonsRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryExecutorCordonsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryExecutorCordonsRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	l = len(m.User)
-	if l > 0 {
-		n += 1 + l + sovMaster(uint64(l))
+}
+func (m *QueryExecutorCordonsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryExecutorCordonsRequest.Merge(m, src)
+}
+func (m *QueryExecutorCordonsRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryExecutorCordonsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryExecutorCordonsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryExecutorCordonsRequest proto.InternalMessageInfo
+
+type QueryExecutorCordonsResponse struct {
+	Err     *Error            `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
+	Cordons []*ExecutorCordon `protobuf:"bytes,2,rep,name=cordons,proto3" json:"cordons,omitempty"`
+}
+
+func (m *QueryExecutorCordonsResponse) Reset()         { *m = QueryExecutorCordonsResponse{} }
+func (m *QueryExecutorCordonsResponse) String() string { return proto.CompactTextString(m) }
+func (*QueryExecutorCordonsResponse) ProtoMessage()    {}
+func (*QueryExecutorCordonsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{49}
+}
+func (m *QueryExecutorCordonsResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryExecutorCordonsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryExecutorCordonsResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return n
+}
+func (m *QueryExecutorCordonsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryExecutorCordonsResponse.Merge(m, src)
+}
+func (m *QueryExecutorCordonsResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryExecutorCordonsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryExecutorCordonsResponse.DiscardUnknown(m)
 }
 
-func (m *QueryJobRequest) Size() (n int) {
-	if m == nil {
-		return 0
+var xxx_messageInfo_QueryExecutorCordonsResponse proto.InternalMessageInfo
+
+func (m *QueryExecutorCordonsResponse) GetErr() *Error {
+	if m != nil {
+		return m.Err
 	}
-	var l int
-	_ = l
-	l = len(m.JobId)
-	if l > 0 {
-		n += 1 + l + sovMaster(uint64(l))
+	return nil
+}
+
+func (m *QueryExecutorCordonsResponse) GetCordons() []*ExecutorCordon {
+	if m != nil {
+		return m.Cordons
 	}
-	return n
+	return nil
 }
 
-func (m *WorkerInfo) Size() (n int) {
-	if m == nil {
-		return 0
+type ClusterHealthRequest struct {
+}
+
+func (m *ClusterHealthRequest) Reset()         { *m = ClusterHealthRequest{} }
+func (m *ClusterHealthRequest) String() string { return proto.CompactTextString(m) }
+func (*ClusterHealthRequest) ProtoMessage()    {}
+func (*ClusterHealthRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{50}
+}
+func (m *ClusterHealthRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ClusterHealthRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ClusterHealthRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	var l int
-	_ = l
-	l = len(m.Id)
-	if l > 0 {
-		n += 1 + l + sovMaster(uint64(l))
-	}
-	l = len(m.ExecutorId)
-	if l > 0 {
-		n += 1 + l + sovMaster(uint64(l))
-	}
-	l = len(m.Status)
-	if l > 0 {
-		n += 1 + l + sovMaster(uint64(l))
-	}
-	l = len(m.Config)
-	if l > 0 {
-		n += 1 + l + sovMaster(uint64(l))
-	}
-	if m.IsTombstone {
-		n += 2
-	}
-	if m.LastHbTime != 0 {
-		n += 1 + sovMaster(uint64(m.LastHbTime))
-	}
-	if m.Workload != 0 {
-		n += 1 + sovMaster(uint64(m.Workload))
-	}
-	return n
+}
+func (m *ClusterHealthRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClusterHealthRequest.Merge(m, src)
+}
+func (m *ClusterHealthRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *ClusterHealthRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClusterHealthRequest.DiscardUnknown(m)
 }
 
-func (m *QueryJobResponse) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.Tp != 0 {
-		n += 1 + sovMaster(uint64(m.Tp))
-	}
-	l = len(m.Config)
-	if l > 0 {
-		n += 1 + l + sovMaster(uint64(l))
-	}
-	if m.Status != 0 {
-		n += 1 + sovMaster(uint64(m.Status))
-	}
-	if m.JobMasterInfo != nil {
-		l = m.JobMasterInfo.Size()
-		n += 1 + l + sovMaster(uint64(l))
-	}
-	if m.Err != nil {
-		l = m.Err.Size()
-		n += 1 + l + sovMaster(uint64(l))
+var xxx_messageInfo_ClusterHealthRequest proto.InternalMessageInfo
+
+type ClusterHealthResponse struct {
+	Err *Error `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
+	// healthy is false if the cluster can't serve requests, the stale
+	// executors and the jobs in error are reported in problems only.
+	Healthy bool `protobuf:"varint,2,opt,name=healthy,proto3" json:"healthy,omitempty"`
+	// leader is the name of the server master leader
+	Leader             string `protobuf:"bytes,3,opt,name=leader,proto3" json:"leader,omitempty"`
+	MetastoreReachable bool   `protobuf:"varint,4,opt,name=metastore_reachable,json=metastoreReachable,proto3" json:"metastore_reachable,omitempty"`
+	Executors          int32  `protobuf:"varint,5,opt,name=executors,proto3" json:"executors,omitempty"`
+	// stale_executors are the executors missing heartbeats for over half
+	// of their heartbeat TTL
+	StaleExecutors []string `protobuf:"bytes,6,rep,name=stale_executors,json=staleExecutors,proto3" json:"stale_executors,omitempty"`
+	// error_jobs are the jobs whose job masters are lost or report errors
+	ErrorJobs []string `protobuf:"bytes,7,rep,name=error_jobs,json=errorJobs,proto3" json:"error_jobs,omitempty"`
+	Problems  []string `protobuf:"bytes,8,rep,name=problems,proto3" json:"problems,omitempty"`
+}
+
+func (m *ClusterHealthResponse) Reset()         { *m = ClusterHealthResponse{} }
+func (m *ClusterHealthResponse) String() string { return proto.CompactTextString(m) }
+func (*ClusterHealthResponse) ProtoMessage()    {}
+func (*ClusterHealthResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{51}
+}
+func (m *ClusterHealthResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ClusterHealthResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ClusterHealthResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return n
+}
+func (m *ClusterHealthResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClusterHealthResponse.Merge(m, src)
+}
+func (m *ClusterHealthResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *ClusterHealthResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClusterHealthResponse.DiscardUnknown(m)
 }
 
-func (m *CancelJobRequest) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.JobId != 0 {
-		n += 1 + sovMaster(uint64(m.JobId))
-	}
-	l = len(m.JobIdStr)
-	if l > 0 {
-		n += 1 + l + sovMaster(uint64(l))
+var xxx_messageInfo_ClusterHealthResponse proto.InternalMessageInfo
+
+func (m *ClusterHealthResponse) GetErr() *Error {
+	if m != nil {
+		return m.Err
 	}
-	return n
+	return nil
 }
 
-func (m *PauseJobRequest) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.JobId != 0 {
-		n += 1 + sovMaster(uint64(m.JobId))
-	}
-	l = len(m.JobIdStr)
-	if l > 0 {
-		n += 1 + l + sovMaster(uint64(l))
+func (m *ClusterHealthResponse) GetHealthy() bool {
+	if m != nil {
+		return m.Healthy
 	}
-	return n
+	return false
 }
 
-func (m *SubmitJobResponse) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *ClusterHealthResponse) GetLeader() string {
+	if m != nil {
+		return m.Leader
 	}
-	var l int
-	_ = l
-	if m.Err != nil {
-		l = m.Err.Size()
-		n += 1 + l + sovMaster(uint64(l))
+	return ""
+}
+
+func (m *ClusterHealthResponse) GetMetastoreReachable() bool {
+	if m != nil {
+		return m.MetastoreReachable
 	}
-	if m.JobId != 0 {
-		n += 1 + sovMaster(uint64(m.JobId))
+	return false
+}
+
+func (m *ClusterHealthResponse) GetExecutors() int32 {
+	if m != nil {
+		return m.Executors
 	}
-	l = len(m.JobIdStr)
-	if l > 0 {
-		n += 1 + l + sovMaster(uint64(l))
+	return 0
+}
+
+func (m *ClusterHealthResponse) GetStaleExecutors() []string {
+	if m != nil {
+		return m.StaleExecutors
 	}
-	return n
+	return nil
 }
 
-func (m *PauseJobResponse) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *ClusterHealthResponse) GetErrorJobs() []string {
+	if m != nil {
+		return m.ErrorJobs
 	}
-	var l int
-	_ = l
-	if m.Err != nil {
-		l = m.Err.Size()
-		n += 1 + l + sovMaster(uint64(l))
+	return nil
+}
+
+func (m *ClusterHealthResponse) GetProblems() []string {
+	if m != nil {
+		return m.Problems
 	}
-	return n
+	return nil
 }
 
-func (m *CancelJobResponse) Size() (n int) {
-	if m == nil {
-		return 0
+type ScheduleTaskRequest struct {
+	TaskId               string   `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Cost                 int64    `protobuf:"varint,2,opt,name=cost,proto3" json:"cost,omitempty"`
+	ResourceRequirements []string `protobuf:"bytes,3,rep,name=resource_requirements,json=resourceRequirements,proto3" json:"resource_requirements,omitempty"`
+	// failover is set when the task is re-dispatched after its executor
+	// fails, so it can use the headroom reserved by the scheduler.
+	Failover bool `protobuf:"varint,4,opt,name=failover,proto3" json:"failover,omitempty"`
+	// resources is the requirement of the task in all dimensions, cost is
+	// used as the cpu dimension if it is not set.
+	Resources map[string]int64 `protobuf:"bytes,5,rep,name=resources,proto3" json:"resources,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// job_id is the job the task belongs to, the task uses the capacity
+	// reserved for the job if there is any.
+	JobId string `protobuf:"bytes,6,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	// queued makes the request wait in the schedule queue if the cluster
+	// doesn't have enough resources, instead of failing at once. The queue
+	// is persisted, a request sent again with the same task_id after the
+	// failover of server master keeps its place in the queue.
+	Queued bool `protobuf:"varint,7,opt,name=queued,proto3" json:"queued,omitempty"`
+	// priority orders the queued requests, the larger ones are placed first.
+	Priority int32 `protobuf:"varint,8,opt,name=priority,proto3" json:"priority,omitempty"`
+	// wait_timeout_ms is how long a queued request waits at most, the max
+	// wait of the schedule queue is used if it is not set.
+	WaitTimeoutMs int64 `protobuf:"varint,9,opt,name=wait_timeout_ms,json=waitTimeoutMs,proto3" json:"wait_timeout_ms,omitempty"`
+}
+
+func (m *ScheduleTaskRequest) Reset()         { *m = ScheduleTaskRequest{} }
+func (m *ScheduleTaskRequest) String() string { return proto.CompactTextString(m) }
+func (*ScheduleTaskRequest) ProtoMessage()    {}
+func (*ScheduleTaskRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{52}
+}
+func (m *ScheduleTaskRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ScheduleTaskRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ScheduleTaskRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	var l int
-	_ = l
-	if m.Err != nil {
-		l = m.Err.Size()
-		n += 1 + l + sovMaster(uint64(l))
+}
+func (m *ScheduleTaskRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ScheduleTaskRequest.Merge(m, src)
+}
+func (m *ScheduleTaskRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *ScheduleTaskRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ScheduleTaskRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ScheduleTaskRequest proto.InternalMessageInfo
+
+func (m *ScheduleTaskRequest) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
 	}
-	return n
+	return ""
 }
 
-func (m *RegisterExecutorRequest) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *ScheduleTaskRequest) GetCost() int64 {
+	if m != nil {
+		return m.Cost
 	}
-	var l int
-	_ = l
-	l = len(m.Address)
-	if l > 0 {
-		n += 1 + l + sovMaster(uint64(l))
+	return 0
+}
+
+func (m *ScheduleTaskRequest) GetResourceRequirements() []string {
+	if m != nil {
+		return m.ResourceRequirements
 	}
-	l = len(m.Version)
-	if l > 0 {
-		n += 1 + l + sovMaster(uint64(l))
+	return nil
+}
+
+func (m *ScheduleTaskRequest) GetFailover() bool {
+	if m != nil {
+		return m.Failover
 	}
-	if m.Capability != 0 {
-		n += 1 + sovMaster(uint64(m.Capability))
+	return false
+}
+
+func (m *ScheduleTaskRequest) GetResources() map[string]int64 {
+	if m != nil {
+		return m.Resources
 	}
-	return n
+	return nil
 }
 
-func (m *RegisterExecutorResponse) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *ScheduleTaskRequest) GetJobId() string {
+	if m != nil {
+		return m.JobId
 	}
-	var l int
-	_ = l
-	if m.Err != nil {
-		l = m.Err.Size()
-		n += 1 + l + sovMaster(uint64(l))
+	return ""
+}
+
+func (m *ScheduleTaskRequest) GetQueued() bool {
+	if m != nil {
+		return m.Queued
 	}
-	l = len(m.ExecutorId)
-	if l > 0 {
-		n += 1 + l + sovMaster(uint64(l))
+	return false
+}
+
+func (m *ScheduleTaskRequest) GetPriority() int32 {
+	if m != nil {
+		return m.Priority
 	}
-	return n
+	return 0
 }
 
-func (m *ScheduleTaskRequest) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *ScheduleTaskRequest) GetWaitTimeoutMs() int64 {
+	if m != nil {
+		return m.WaitTimeoutMs
 	}
-	var l int
-	_ = l
-	l = len(m.TaskId)
-	if l > 0 {
-		n += 1 + l + sovMaster(uint64(l))
+	return 0
+}
+
+type ScheduleTaskResponse struct {
+	ExecutorId   string `protobuf:"bytes,1,opt,name=executor_id,json=executorId,proto3" json:"executor_id,omitempty"`
+	ExecutorAddr string `protobuf:"bytes,2,opt,name=executor_addr,json=executorAddr,proto3" json:"executor_addr,omitempty"`
+}
+
+func (m *ScheduleTaskResponse) Reset()         { *m = ScheduleTaskResponse{} }
+func (m *ScheduleTaskResponse) String() string { return proto.CompactTextString(m) }
+func (*ScheduleTaskResponse) ProtoMessage()    {}
+func (*ScheduleTaskResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{53}
+}
+func (m *ScheduleTaskResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ScheduleTaskResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ScheduleTaskResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	if m.Cost != 0 {
-		n += 1 + sovMaster(uint64(m.Cost))
+}
+func (m *ScheduleTaskResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ScheduleTaskResponse.Merge(m, src)
+}
+func (m *ScheduleTaskResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *ScheduleTaskResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ScheduleTaskResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ScheduleTaskResponse proto.InternalMessageInfo
+
+func (m *ScheduleTaskResponse) GetExecutorId() string {
+	if m != nil {
+		return m.ExecutorId
 	}
-	if len(m.ResourceRequirements) > 0 {
-		for _, s := range m.ResourceRequirements {
-			l = len(s)
-			n += 1 + l + sovMaster(uint64(l))
+	return ""
+}
+
+func (m *ScheduleTaskResponse) GetExecutorAddr() string {
+	if m != nil {
+		return m.ExecutorAddr
+	}
+	return ""
+}
+
+type ScheduleRequest struct {
+	// request_id identifies the request in the stream, the decision and the
+	// revocation of the request carry it.
+	RequestId int64                `protobuf:"varint,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Task      *ScheduleTaskRequest `protobuf:"bytes,2,opt,name=task,proto3" json:"task,omitempty"`
+	// done tells the decision of the request is used or abandoned, it is not
+	// revoked any more.
+	Done bool `protobuf:"varint,3,opt,name=done,proto3" json:"done,omitempty"`
+}
+
+func (m *ScheduleRequest) Reset()         { *m = ScheduleRequest{} }
+func (m *ScheduleRequest) String() string { return proto.CompactTextString(m) }
+func (*ScheduleRequest) ProtoMessage()    {}
+func (*ScheduleRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{54}
+}
+func (m *ScheduleRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ScheduleRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ScheduleRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	return n
+}
+func (m *ScheduleRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ScheduleRequest.Merge(m, src)
+}
+func (m *ScheduleRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *ScheduleRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ScheduleRequest.DiscardUnknown(m)
 }
 
-func (m *ScheduleTaskResponse) Size() (n int) {
-	if m == nil {
-		return 0
+var xxx_messageInfo_ScheduleRequest proto.InternalMessageInfo
+
+func (m *ScheduleRequest) GetRequestId() int64 {
+	if m != nil {
+		return m.RequestId
 	}
-	var l int
-	_ = l
-	l = len(m.ExecutorId)
-	if l > 0 {
-		n += 1 + l + sovMaster(uint64(l))
+	return 0
+}
+
+func (m *ScheduleRequest) GetTask() *ScheduleTaskRequest {
+	if m != nil {
+		return m.Task
 	}
-	l = len(m.ExecutorAddr)
-	if l > 0 {
-		n += 1 + l + sovMaster(uint64(l))
+	return nil
+}
+
+func (m *ScheduleRequest) GetDone() bool {
+	if m != nil {
+		return m.Done
 	}
-	return n
+	return false
 }
 
-func (m *ExecWorkload) Size() (n int) {
-	if m == nil {
-		return 0
+type ScheduleResponse struct {
+	RequestId int64 `protobuf:"varint,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// decision is set if the task is scheduled.
+	Decision *ScheduleTaskResponse `protobuf:"bytes,2,opt,name=decision,proto3" json:"decision,omitempty"`
+	// err is set if the task can't be scheduled.
+	Err *Error `protobuf:"bytes,3,opt,name=err,proto3" json:"err,omitempty"`
+	// revoked is set if the executor of the decision is gone, the request
+	// should be submitted again.
+	Revoked bool `protobuf:"varint,4,opt,name=revoked,proto3" json:"revoked,omitempty"`
+}
+
+func (m *ScheduleResponse) Reset()         { *m = ScheduleResponse{} }
+func (m *ScheduleResponse) String() string { return proto.CompactTextString(m) }
+func (*ScheduleResponse) ProtoMessage()    {}
+func (*ScheduleResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{55}
+}
+func (m *ScheduleResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ScheduleResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ScheduleResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	var l int
-	_ = l
-	if m.Tp != 0 {
-		n += 1 + sovMaster(uint64(m.Tp))
+}
+func (m *ScheduleResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ScheduleResponse.Merge(m, src)
+}
+func (m *ScheduleResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *ScheduleResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ScheduleResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ScheduleResponse proto.InternalMessageInfo
+
+func (m *ScheduleResponse) GetRequestId() int64 {
+	if m != nil {
+		return m.RequestId
 	}
-	if m.Usage != 0 {
-		n += 1 + sovMaster(uint64(m.Usage))
+	return 0
+}
+
+func (m *ScheduleResponse) GetDecision() *ScheduleTaskResponse {
+	if m != nil {
+		return m.Decision
 	}
-	return n
+	return nil
 }
 
-func (m *ExecWorkloadRequest) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *ScheduleResponse) GetErr() *Error {
+	if m != nil {
+		return m.Err
 	}
-	var l int
-	_ = l
-	l = len(m.ExecutorId)
-	if l > 0 {
-		n += 1 + l + sovMaster(uint64(l))
+	return nil
+}
+
+func (m *ScheduleResponse) GetRevoked() bool {
+	if m != nil {
+		return m.Revoked
 	}
-	if len(m.Workloads) > 0 {
-		for _, e := range m.Workloads {
-			l = e.Size()
-			n += 1 + l + sovMaster(uint64(l))
+	return false
+}
+
+type ScaleUpJobRequest struct {
+	JobId string `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	// current_workers is the number of workers the job is running.
+	CurrentWorkers int32 `protobuf:"varint,2,opt,name=current_workers,json=currentWorkers,proto3" json:"current_workers,omitempty"`
+	// workers is the number of workers to add.
+	Workers int32 `protobuf:"varint,3,opt,name=workers,proto3" json:"workers,omitempty"`
+}
+
+func (m *ScaleUpJobRequest) Reset()         { *m = ScaleUpJobRequest{} }
+func (m *ScaleUpJobRequest) String() string { return proto.CompactTextString(m) }
+func (*ScaleUpJobRequest) ProtoMessage()    {}
+func (*ScaleUpJobRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{56}
+}
+func (m *ScaleUpJobRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ScaleUpJobRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ScaleUpJobRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if len(m.ResourceId) > 0 {
-		for _, s := range m.ResourceId {
-			l = len(s)
-			n += 1 + l + sovMaster(uint64(l))
+}
+func (m *ScaleUpJobRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ScaleUpJobRequest.Merge(m, src)
+}
+func (m *ScaleUpJobRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *ScaleUpJobRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ScaleUpJobRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ScaleUpJobRequest proto.InternalMessageInfo
+
+func (m *ScaleUpJobRequest) GetJobId() string {
+	if m != nil {
+		return m.JobId
+	}
+	return ""
+}
+
+func (m *ScaleUpJobRequest) GetCurrentWorkers() int32 {
+	if m != nil {
+		return m.CurrentWorkers
+	}
+	return 0
+}
+
+func (m *ScaleUpJobRequest) GetWorkers() int32 {
+	if m != nil {
+		return m.Workers
+	}
+	return 0
+}
+
+type ScaleUpJobResponse struct {
+	Err *Error `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
+	// granted_workers is the number of workers whose capacity is reserved
+	// for the job, it may be less than the requested ones.
+	GrantedWorkers int32 `protobuf:"varint,2,opt,name=granted_workers,json=grantedWorkers,proto3" json:"granted_workers,omitempty"`
+	// backoff_ms is how long the job master should wait before requesting
+	// again if no worker is granted.
+	BackoffMs int64 `protobuf:"varint,3,opt,name=backoff_ms,json=backoffMs,proto3" json:"backoff_ms,omitempty"`
+}
+
+func (m *ScaleUpJobResponse) Reset()         { *m = ScaleUpJobResponse{} }
+func (m *ScaleUpJobResponse) String() string { return proto.CompactTextString(m) }
+func (*ScaleUpJobResponse) ProtoMessage()    {}
+func (*ScaleUpJobResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{57}
+}
+func (m *ScaleUpJobResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ScaleUpJobResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ScaleUpJobResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ScaleUpJobResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ScaleUpJobResponse.Merge(m, src)
+}
+func (m *ScaleUpJobResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *ScaleUpJobResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ScaleUpJobResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ScaleUpJobResponse proto.InternalMessageInfo
+
+func (m *ScaleUpJobResponse) GetErr() *Error {
+	if m != nil {
+		return m.Err
+	}
+	return nil
+}
+
+func (m *ScaleUpJobResponse) GetGrantedWorkers() int32 {
+	if m != nil {
+		return m.GrantedWorkers
+	}
+	return 0
+}
+
+func (m *ScaleUpJobResponse) GetBackoffMs() int64 {
+	if m != nil {
+		return m.BackoffMs
+	}
+	return 0
+}
+
+type ExecWorkload struct {
+	Tp    JobType `protobuf:"varint,1,opt,name=tp,proto3,enum=pb.JobType" json:"tp,omitempty"`
+	Usage int32   `protobuf:"varint,2,opt,name=usage,proto3" json:"usage,omitempty"`
+}
+
+func (m *ExecWorkload) Reset()         { *m = ExecWorkload{} }
+func (m *ExecWorkload) String() string { return proto.CompactTextString(m) }
+func (*ExecWorkload) ProtoMessage()    {}
+func (*ExecWorkload) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{58}
+}
+func (m *ExecWorkload) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ExecWorkload) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ExecWorkload.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ExecWorkload) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExecWorkload.Merge(m, src)
+}
+func (m *ExecWorkload) XXX_Size() int {
+	return m.Size()
+}
+func (m *ExecWorkload) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExecWorkload.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExecWorkload proto.InternalMessageInfo
+
+func (m *ExecWorkload) GetTp() JobType {
+	if m != nil {
+		return m.Tp
+	}
+	return JobType_CVSDemo
+}
+
+func (m *ExecWorkload) GetUsage() int32 {
+	if m != nil {
+		return m.Usage
+	}
+	return 0
+}
+
+type ExecWorkloadRequest struct {
+	ExecutorId string          `protobuf:"bytes,1,opt,name=executor_id,json=executorId,proto3" json:"executor_id,omitempty"`
+	Workloads  []*ExecWorkload `protobuf:"bytes,2,rep,name=workloads,proto3" json:"workloads,omitempty"`
+	ResourceId []string        `protobuf:"bytes,3,rep,name=resource_id,json=resourceId,proto3" json:"resource_id,omitempty"`
+}
+
+func (m *ExecWorkloadRequest) Reset()         { *m = ExecWorkloadRequest{} }
+func (m *ExecWorkloadRequest) String() string { return proto.CompactTextString(m) }
+func (*ExecWorkloadRequest) ProtoMessage()    {}
+func (*ExecWorkloadRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{59}
+}
+func (m *ExecWorkloadRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ExecWorkloadRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ExecWorkloadRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ExecWorkloadRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExecWorkloadRequest.Merge(m, src)
+}
+func (m *ExecWorkloadRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *ExecWorkloadRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExecWorkloadRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExecWorkloadRequest proto.InternalMessageInfo
+
+func (m *ExecWorkloadRequest) GetExecutorId() string {
+	if m != nil {
+		return m.ExecutorId
+	}
+	return ""
+}
+
+func (m *ExecWorkloadRequest) GetWorkloads() []*ExecWorkload {
+	if m != nil {
+		return m.Workloads
+	}
+	return nil
+}
+
+func (m *ExecWorkloadRequest) GetResourceId() []string {
+	if m != nil {
+		return m.ResourceId
+	}
+	return nil
+}
+
+type ExecWorkloadResponse struct {
+	Err *Error `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
+}
+
+func (m *ExecWorkloadResponse) Reset()         { *m = ExecWorkloadResponse{} }
+func (m *ExecWorkloadResponse) String() string { return proto.CompactTextString(m) }
+func (*ExecWorkloadResponse) ProtoMessage()    {}
+func (*ExecWorkloadResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{60}
+}
+func (m *ExecWorkloadResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ExecWorkloadResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ExecWorkloadResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ExecWorkloadResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExecWorkloadResponse.Merge(m, src)
+}
+func (m *ExecWorkloadResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *ExecWorkloadResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExecWorkloadResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExecWorkloadResponse proto.InternalMessageInfo
+
+func (m *ExecWorkloadResponse) GetErr() *Error {
+	if m != nil {
+		return m.Err
+	}
+	return nil
+}
+
+type PersistResourceRequest struct {
+	ExecutorId string `protobuf:"bytes,1,opt,name=executor_id,json=executorId,proto3" json:"executor_id,omitempty"`
+	ResourceId string `protobuf:"bytes,2,opt,name=resource_id,json=resourceId,proto3" json:"resource_id,omitempty"`
+}
+
+func (m *PersistResourceRequest) Reset()         { *m = PersistResourceRequest{} }
+func (m *PersistResourceRequest) String() string { return proto.CompactTextString(m) }
+func (*PersistResourceRequest) ProtoMessage()    {}
+func (*PersistResourceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{61}
+}
+func (m *PersistResourceRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PersistResourceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PersistResourceRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PersistResourceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PersistResourceRequest.Merge(m, src)
+}
+func (m *PersistResourceRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *PersistResourceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PersistResourceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PersistResourceRequest proto.InternalMessageInfo
+
+func (m *PersistResourceRequest) GetExecutorId() string {
+	if m != nil {
+		return m.ExecutorId
+	}
+	return ""
+}
+
+func (m *PersistResourceRequest) GetResourceId() string {
+	if m != nil {
+		return m.ResourceId
+	}
+	return ""
+}
+
+type PersistResourceResponse struct {
+	Err *Error `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
+}
+
+func (m *PersistResourceResponse) Reset()         { *m = PersistResourceResponse{} }
+func (m *PersistResourceResponse) String() string { return proto.CompactTextString(m) }
+func (*PersistResourceResponse) ProtoMessage()    {}
+func (*PersistResourceResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{62}
+}
+func (m *PersistResourceResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PersistResourceResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PersistResourceResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PersistResourceResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PersistResourceResponse.Merge(m, src)
+}
+func (m *PersistResourceResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *PersistResourceResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PersistResourceResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PersistResourceResponse proto.InternalMessageInfo
+
+func (m *PersistResourceResponse) GetErr() *Error {
+	if m != nil {
+		return m.Err
+	}
+	return nil
+}
+
+type MigrateMetaStoreRequest struct {
+	Op MigrateMetaStoreRequest_Op `protobuf:"varint,1,opt,name=op,proto3,enum=pb.MigrateMetaStoreRequest_Op" json:"op,omitempty"`
+	// target is the JSON encoded config of the target metastore, in the
+	// same format as QueryMetaStoreResponse.address. It is only used by Start.
+	Target string `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
+}
+
+func (m *MigrateMetaStoreRequest) Reset()         { *m = MigrateMetaStoreRequest{} }
+func (m *MigrateMetaStoreRequest) String() string { return proto.CompactTextString(m) }
+func (*MigrateMetaStoreRequest) ProtoMessage()    {}
+func (*MigrateMetaStoreRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{63}
+}
+func (m *MigrateMetaStoreRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *MigrateMetaStoreRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_MigrateMetaStoreRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *MigrateMetaStoreRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MigrateMetaStoreRequest.Merge(m, src)
+}
+func (m *MigrateMetaStoreRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *MigrateMetaStoreRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MigrateMetaStoreRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MigrateMetaStoreRequest proto.InternalMessageInfo
+
+func (m *MigrateMetaStoreRequest) GetOp() MigrateMetaStoreRequest_Op {
+	if m != nil {
+		return m.Op
+	}
+	return MigrateMetaStoreRequest_Status
+}
+
+func (m *MigrateMetaStoreRequest) GetTarget() string {
+	if m != nil {
+		return m.Target
+	}
+	return ""
+}
+
+type MigrateMetaStoreResponse struct {
+	Err *Error `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
+	// phase is one of "none", "dual-write" and "switched".
+	Phase string `protobuf:"bytes,2,opt,name=phase,proto3" json:"phase,omitempty"`
+	// target_endpoints are the endpoints of the target metastore.
+	TargetEndpoints []string `protobuf:"bytes,3,rep,name=target_endpoints,json=targetEndpoints,proto3" json:"target_endpoints,omitempty"`
+	// failed_writes is the number of writes failed to be copied to the
+	// target metastore since the last verification.
+	FailedWrites int64 `protobuf:"varint,4,opt,name=failed_writes,json=failedWrites,proto3" json:"failed_writes,omitempty"`
+	Verifying    bool  `protobuf:"varint,5,opt,name=verifying,proto3" json:"verifying,omitempty"`
+	// last_verify_time is the unix time in seconds of the last finished
+	// verification, 0 means never verified.
+	LastVerifyTime  int64  `protobuf:"varint,6,opt,name=last_verify_time,json=lastVerifyTime,proto3" json:"last_verify_time,omitempty"`
+	LastVerifyError string `protobuf:"bytes,7,opt,name=last_verify_error,json=lastVerifyError,proto3" json:"last_verify_error,omitempty"`
+	// mismatches are the numbers of mismatched records of each kind found
+	// by the last verification, which have been repaired.
+	Mismatches map[string]int64 `protobuf:"bytes,8,rep,name=mismatches,proto3" json:"mismatches,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+}
+
+func (m *MigrateMetaStoreResponse) Reset()         { *m = MigrateMetaStoreResponse{} }
+func (m *MigrateMetaStoreResponse) String() string { return proto.CompactTextString(m) }
+func (*MigrateMetaStoreResponse) ProtoMessage()    {}
+func (*MigrateMetaStoreResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{64}
+}
+func (m *MigrateMetaStoreResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *MigrateMetaStoreResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_MigrateMetaStoreResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *MigrateMetaStoreResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MigrateMetaStoreResponse.Merge(m, src)
+}
+func (m *MigrateMetaStoreResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *MigrateMetaStoreResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MigrateMetaStoreResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MigrateMetaStoreResponse proto.InternalMessageInfo
+
+func (m *MigrateMetaStoreResponse) GetErr() *Error {
+	if m != nil {
+		return m.Err
+	}
+	return nil
+}
+
+func (m *MigrateMetaStoreResponse) GetPhase() string {
+	if m != nil {
+		return m.Phase
+	}
+	return ""
+}
+
+func (m *MigrateMetaStoreResponse) GetTargetEndpoints() []string {
+	if m != nil {
+		return m.TargetEndpoints
+	}
+	return nil
+}
+
+func (m *MigrateMetaStoreResponse) GetFailedWrites() int64 {
+	if m != nil {
+		return m.FailedWrites
+	}
+	return 0
+}
+
+func (m *MigrateMetaStoreResponse) GetVerifying() bool {
+	if m != nil {
+		return m.Verifying
+	}
+	return false
+}
+
+func (m *MigrateMetaStoreResponse) GetLastVerifyTime() int64 {
+	if m != nil {
+		return m.LastVerifyTime
+	}
+	return 0
+}
+
+func (m *MigrateMetaStoreResponse) GetLastVerifyError() string {
+	if m != nil {
+		return m.LastVerifyError
+	}
+	return ""
+}
+
+func (m *MigrateMetaStoreResponse) GetMismatches() map[string]int64 {
+	if m != nil {
+		return m.Mismatches
+	}
+	return nil
+}
+
+// SecretMeta describes a stored secret without its value.
+type SecretMeta struct {
+	ProjectId string `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	Name      string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (m *SecretMeta) Reset()         { *m = SecretMeta{} }
+func (m *SecretMeta) String() string { return proto.CompactTextString(m) }
+func (*SecretMeta) ProtoMessage()    {}
+func (*SecretMeta) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{65}
+}
+func (m *SecretMeta) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SecretMeta) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SecretMeta.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SecretMeta) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SecretMeta.Merge(m, src)
+}
+func (m *SecretMeta) XXX_Size() int {
+	return m.Size()
+}
+func (m *SecretMeta) XXX_DiscardUnknown() {
+	xxx_messageInfo_SecretMeta.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SecretMeta proto.InternalMessageInfo
+
+func (m *SecretMeta) GetProjectId() string {
+	if m != nil {
+		return m.ProjectId
+	}
+	return ""
+}
+
+func (m *SecretMeta) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type PutSecretRequest struct {
+	ProjectId string `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	Name      string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// value is the plaintext of the secret, it is encrypted before being
+	// persisted.
+	Value string `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *PutSecretRequest) Reset()         { *m = PutSecretRequest{} }
+func (m *PutSecretRequest) String() string { return proto.CompactTextString(m) }
+func (*PutSecretRequest) ProtoMessage()    {}
+func (*PutSecretRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{66}
+}
+func (m *PutSecretRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PutSecretRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PutSecretRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PutSecretRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PutSecretRequest.Merge(m, src)
+}
+func (m *PutSecretRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *PutSecretRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PutSecretRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PutSecretRequest proto.InternalMessageInfo
+
+func (m *PutSecretRequest) GetProjectId() string {
+	if m != nil {
+		return m.ProjectId
+	}
+	return ""
+}
+
+func (m *PutSecretRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *PutSecretRequest) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+type PutSecretResponse struct {
+	Err *Error `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
+}
+
+func (m *PutSecretResponse) Reset()         { *m = PutSecretResponse{} }
+func (m *PutSecretResponse) String() string { return proto.CompactTextString(m) }
+func (*PutSecretResponse) ProtoMessage()    {}
+func (*PutSecretResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{67}
+}
+func (m *PutSecretResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PutSecretResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PutSecretResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PutSecretResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PutSecretResponse.Merge(m, src)
+}
+func (m *PutSecretResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *PutSecretResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PutSecretResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PutSecretResponse proto.InternalMessageInfo
+
+func (m *PutSecretResponse) GetErr() *Error {
+	if m != nil {
+		return m.Err
+	}
+	return nil
+}
+
+type DeleteSecretRequest struct {
+	ProjectId string `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	Name      string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (m *DeleteSecretRequest) Reset()         { *m = DeleteSecretRequest{} }
+func (m *DeleteSecretRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteSecretRequest) ProtoMessage()    {}
+func (*DeleteSecretRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{68}
+}
+func (m *DeleteSecretRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DeleteSecretRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DeleteSecretRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *DeleteSecretRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteSecretRequest.Merge(m, src)
+}
+func (m *DeleteSecretRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *DeleteSecretRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteSecretRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteSecretRequest proto.InternalMessageInfo
+
+func (m *DeleteSecretRequest) GetProjectId() string {
+	if m != nil {
+		return m.ProjectId
+	}
+	return ""
+}
+
+func (m *DeleteSecretRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type DeleteSecretResponse struct {
+	Err *Error `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
+}
+
+func (m *DeleteSecretResponse) Reset()         { *m = DeleteSecretResponse{} }
+func (m *DeleteSecretResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteSecretResponse) ProtoMessage()    {}
+func (*DeleteSecretResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{69}
+}
+func (m *DeleteSecretResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DeleteSecretResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DeleteSecretResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *DeleteSecretResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteSecretResponse.Merge(m, src)
+}
+func (m *DeleteSecretResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *DeleteSecretResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteSecretResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteSecretResponse proto.InternalMessageInfo
+
+func (m *DeleteSecretResponse) GetErr() *Error {
+	if m != nil {
+		return m.Err
+	}
+	return nil
+}
+
+type ListSecretsRequest struct {
+	ProjectId string `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+}
+
+func (m *ListSecretsRequest) Reset()         { *m = ListSecretsRequest{} }
+func (m *ListSecretsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListSecretsRequest) ProtoMessage()    {}
+func (*ListSecretsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{70}
+}
+func (m *ListSecretsRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ListSecretsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ListSecretsRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ListSecretsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSecretsRequest.Merge(m, src)
+}
+func (m *ListSecretsRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *ListSecretsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSecretsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSecretsRequest proto.InternalMessageInfo
+
+func (m *ListSecretsRequest) GetProjectId() string {
+	if m != nil {
+		return m.ProjectId
+	}
+	return ""
+}
+
+type ListSecretsResponse struct {
+	Err     *Error        `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
+	Secrets []*SecretMeta `protobuf:"bytes,2,rep,name=secrets,proto3" json:"secrets,omitempty"`
+}
+
+func (m *ListSecretsResponse) Reset()         { *m = ListSecretsResponse{} }
+func (m *ListSecretsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListSecretsResponse) ProtoMessage()    {}
+func (*ListSecretsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f9c348dec43a6705, []int{71}
+}
+func (m *ListSecretsResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ListSecretsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ListSecretsResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ListSecretsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSecretsResponse.Merge(m, src)
+}
+func (m *ListSecretsResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *ListSecretsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSecretsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSecretsResponse proto.InternalMessageInfo
+
+func (m *ListSecretsResponse) GetErr() *Error {
+	if m != nil {
+		return m.Err
+	}
+	return nil
+}
+
+func (m *ListSecretsResponse) GetSecrets() []*SecretMeta {
+	if m != nil {
+		return m.Secrets
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterEnum("pb.JobType", JobType_name, JobType_value)
+	proto.RegisterEnum("pb.JobTaskOp", JobTaskOp_name, JobTaskOp_value)
+	proto.RegisterEnum("pb.CatchUpPolicy", CatchUpPolicy_name, CatchUpPolicy_value)
+	proto.RegisterEnum("pb.CordonState", CordonState_name, CordonState_value)
+	proto.RegisterEnum("pb.QueryJobResponse_JobStatus", QueryJobResponse_JobStatus_name, QueryJobResponse_JobStatus_value)
+	proto.RegisterEnum("pb.MigrateMetaStoreRequest_Op", MigrateMetaStoreRequest_Op_name, MigrateMetaStoreRequest_Op_value)
+	proto.RegisterType((*HeartbeatRequest)(nil), "pb.HeartbeatRequest")
+	proto.RegisterMapType((map[string]int64)(nil), "pb.HeartbeatRequest.ResourceUsagesEntry")
+	proto.RegisterType((*HeartbeatResponse)(nil), "pb.HeartbeatResponse")
+	proto.RegisterType((*SubmitJobRequest)(nil), "pb.SubmitJobRequest")
+	proto.RegisterMapType((map[string]string)(nil), "pb.SubmitJobRequest.LabelsEntry")
+	proto.RegisterMapType((map[string]string)(nil), "pb.SubmitJobRequest.TemplateParamsEntry")
+	proto.RegisterType((*JobResourceSpec)(nil), "pb.JobResourceSpec")
+	proto.RegisterMapType((map[string]int64)(nil), "pb.JobResourceSpec.WorkerResourcesEntry")
+	proto.RegisterType((*QueryJobRequest)(nil), "pb.QueryJobRequest")
+	proto.RegisterType((*WorkerInfo)(nil), "pb.WorkerInfo")
+	proto.RegisterType((*QueryJobResponse)(nil), "pb.QueryJobResponse")
+	proto.RegisterMapType((map[string]string)(nil), "pb.QueryJobResponse.LabelsEntry")
+	proto.RegisterType((*QueryJobsRequest)(nil), "pb.QueryJobsRequest")
+	proto.RegisterType((*JobInfo)(nil), "pb.JobInfo")
+	proto.RegisterMapType((map[string]string)(nil), "pb.JobInfo.LabelsEntry")
+	proto.RegisterType((*QueryJobsResponse)(nil), "pb.QueryJobsResponse")
+	proto.RegisterType((*ListWorkersRequest)(nil), "pb.ListWorkersRequest")
+	proto.RegisterType((*WorkerSummary)(nil), "pb.WorkerSummary")
+	proto.RegisterType((*ListWorkersResponse)(nil), "pb.ListWorkersResponse")
+	proto.RegisterType((*CancelJobRequest)(nil), "pb.CancelJobRequest")
+	proto.RegisterType((*PauseJobRequest)(nil), "pb.PauseJobRequest")
+	proto.RegisterType((*SubmitJobResponse)(nil), "pb.SubmitJobResponse")
+	proto.RegisterType((*PauseJobResponse)(nil), "pb.PauseJobResponse")
+	proto.RegisterType((*CancelJobResponse)(nil), "pb.CancelJobResponse")
+	proto.RegisterType((*UpdateJobTimeoutsRequest)(nil), "pb.UpdateJobTimeoutsRequest")
+	proto.RegisterType((*UpdateJobTimeoutsResponse)(nil), "pb.UpdateJobTimeoutsResponse")
+	proto.RegisterType((*SetJobLogLevelRequest)(nil), "pb.SetJobLogLevelRequest")
+	proto.RegisterType((*SetJobLogLevelResponse)(nil), "pb.SetJobLogLevelResponse")
+	proto.RegisterType((*OperateJobTasksRequest)(nil), "pb.OperateJobTasksRequest")
+	proto.RegisterType((*OperateJobTasksResponse)(nil), "pb.OperateJobTasksResponse")
+	proto.RegisterType((*UpdateJobSourceRequest)(nil), "pb.UpdateJobSourceRequest")
+	proto.RegisterType((*UpdateJobSourceResponse)(nil), "pb.UpdateJobSourceResponse")
+	proto.RegisterType((*JobSchedule)(nil), "pb.JobSchedule")
+	proto.RegisterType((*CreateJobScheduleRequest)(nil), "pb.CreateJobScheduleRequest")
+	proto.RegisterType((*CreateJobScheduleResponse)(nil), "pb.CreateJobScheduleResponse")
+	proto.RegisterType((*UpdateJobScheduleRequest)(nil), "pb.UpdateJobScheduleRequest")
+	proto.RegisterType((*UpdateJobScheduleResponse)(nil), "pb.UpdateJobScheduleResponse")
+	proto.RegisterType((*DeleteJobScheduleRequest)(nil), "pb.DeleteJobScheduleRequest")
+	proto.RegisterType((*DeleteJobScheduleResponse)(nil), "pb.DeleteJobScheduleResponse")
+	proto.RegisterType((*QueryJobSchedulesRequest)(nil), "pb.QueryJobSchedulesRequest")
+	proto.RegisterType((*QueryJobSchedulesResponse)(nil), "pb.QueryJobSchedulesResponse")
+	proto.RegisterType((*JobTemplate)(nil), "pb.JobTemplate")
+	proto.RegisterType((*JobTemplateParam)(nil), "pb.JobTemplateParam")
+	proto.RegisterType((*RegisterJobTemplateRequest)(nil), "pb.RegisterJobTemplateRequest")
+	proto.RegisterType((*RegisterJobTemplateResponse)(nil), "pb.RegisterJobTemplateResponse")
+	proto.RegisterType((*DeleteJobTemplateRequest)(nil), "pb.DeleteJobTemplateRequest")
+	proto.RegisterType((*DeleteJobTemplateResponse)(nil), "pb.DeleteJobTemplateResponse")
+	proto.RegisterType((*QueryJobTemplatesRequest)(nil), "pb.QueryJobTemplatesRequest")
+	proto.RegisterType((*QueryJobTemplatesResponse)(nil), "pb.QueryJobTemplatesResponse")
+	proto.RegisterType((*RegisterExecutorRequest)(nil), "pb.RegisterExecutorRequest")
+	proto.RegisterMapType((map[string]string)(nil), "pb.RegisterExecutorRequest.LabelsEntry")
+	proto.RegisterMapType((map[string]int64)(nil), "pb.RegisterExecutorRequest.ResourcesEntry")
+	proto.RegisterType((*RegisterExecutorResponse)(nil), "pb.RegisterExecutorResponse")
+	proto.RegisterType((*CordonExecutorRequest)(nil), "pb.CordonExecutorRequest")
+	proto.RegisterType((*CordonExecutorResponse)(nil), "pb.CordonExecutorResponse")
+	proto.RegisterType((*ExecutorCordon)(nil), "pb.ExecutorCordon")
+	proto.RegisterType((*QueryExecutorCordonsRequest)(nil), "pb.QueryExecutorCordonsRequest")
+	proto.RegisterType((*QueryExecutorCordonsResponse)(nil), "pb.QueryExecutorCordonsResponse")
+	proto.RegisterType((*ClusterHealthRequest)(nil), "pb.ClusterHealthRequest")
+	proto.RegisterType((*ClusterHealthResponse)(nil), "pb.ClusterHealthResponse")
+	proto.RegisterType((*ScheduleTaskRequest)(nil), "pb.ScheduleTaskRequest")
+	proto.RegisterMapType((map[string]int64)(nil), "pb.ScheduleTaskRequest.ResourcesEntry")
+	proto.RegisterType((*ScheduleTaskResponse)(nil), "pb.ScheduleTaskResponse")
+	proto.RegisterType((*ScheduleRequest)(nil), "pb.ScheduleRequest")
+	proto.RegisterType((*ScheduleResponse)(nil), "pb.ScheduleResponse")
+	proto.RegisterType((*ScaleUpJobRequest)(nil), "pb.ScaleUpJobRequest")
+	proto.RegisterType((*ScaleUpJobResponse)(nil), "pb.ScaleUpJobResponse")
+	proto.RegisterType((*ExecWorkload)(nil), "pb.ExecWorkload")
+	proto.RegisterType((*ExecWorkloadRequest)(nil), "pb.ExecWorkloadRequest")
+	proto.RegisterType((*ExecWorkloadResponse)(nil), "pb.ExecWorkloadResponse")
+	proto.RegisterType((*PersistResourceRequest)(nil), "pb.PersistResourceRequest")
+	proto.RegisterType((*PersistResourceResponse)(nil), "pb.PersistResourceResponse")
+	proto.RegisterType((*MigrateMetaStoreRequest)(nil), "pb.MigrateMetaStoreRequest")
+	proto.RegisterType((*MigrateMetaStoreResponse)(nil), "pb.MigrateMetaStoreResponse")
+	proto.RegisterMapType((map[string]int64)(nil), "pb.MigrateMetaStoreResponse.MismatchesEntry")
+	proto.RegisterType((*SecretMeta)(nil), "pb.SecretMeta")
+	proto.RegisterType((*PutSecretRequest)(nil), "pb.PutSecretRequest")
+	proto.RegisterType((*PutSecretResponse)(nil), "pb.PutSecretResponse")
+	proto.RegisterType((*DeleteSecretRequest)(nil), "pb.DeleteSecretRequest")
+	proto.RegisterType((*DeleteSecretResponse)(nil), "pb.DeleteSecretResponse")
+	proto.RegisterType((*ListSecretsRequest)(nil), "pb.ListSecretsRequest")
+	proto.RegisterType((*ListSecretsResponse)(nil), "pb.ListSecretsResponse")
+}
+
+func init() { proto.RegisterFile("master.proto", fileDescriptor_f9c348dec43a6705) }
+
+var fileDescriptor_f9c348dec43a6705 = []byte{
+	// 3566 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x3a, 0x4d, 0x73, 0x1b, 0xc7,
+	0xb1, 0x5c, 0x7c, 0x90, 0x40, 0x83, 0x04, 0x96, 0x43, 0x90, 0x04, 0x97, 0x22, 0xc5, 0xb7, 0xaf,
+	0x9e, 0x4d, 0xcb, 0x7a, 0xb4, 0x8b, 0xf2, 0xd3, 0x53, 0xfc, 0x19, 0x89, 0x92, 0x2d, 0x2a, 0x62,
+	0x49, 0x5e, 0x48, 0xb2, 0x1d, 0xbb, 0x0a, 0xb5, 0xd8, 0x1d, 0x82, 0x2b, 0x2e, 0xb0, 0xab, 0xdd,
+	0x01, 0x25, 0xfa, 0x9c, 0xaa, 0x54, 0x25, 0x97, 0x24, 0x55, 0xa9, 0x4a, 0x2e, 0xae, 0xca, 0x29,
+	0x87, 0xfc, 0x86, 0x9c, 0x72, 0xc9, 0xd1, 0xc7, 0x54, 0x72, 0x49, 0xd9, 0xd7, 0xdc, 0x72, 0xca,
+	0x2d, 0x35, 0x5f, 0xfb, 0x85, 0x05, 0xb9, 0x8c, 0x74, 0xc3, 0x74, 0xf7, 0xf4, 0xf4, 0x76, 0xf7,
+	0xf4, 0xf4, 0x07, 0x60, 0x7e, 0x68, 0x86, 0x04, 0x07, 0x3b, 0x7e, 0xe0, 0x11, 0x0f, 0x95, 0xfc,
+	0xbe, 0xd6, 0xc0, 0x41, 0xe0, 0x09, 0x80, 0xd6, 0x1a, 0x62, 0x62, 0x86, 0xc4, 0x0b, 0x30, 0x07,
+	0xe8, 0x3f, 0x2b, 0x83, 0x7a, 0x17, 0x9b, 0x01, 0xe9, 0x63, 0x93, 0x18, 0xf8, 0xd9, 0x18, 0x87,
+	0x04, 0x5d, 0x86, 0x06, 0x7e, 0x81, 0xad, 0x31, 0xf1, 0x82, 0x9e, 0x63, 0x77, 0x94, 0x2d, 0x65,
+	0xbb, 0x6e, 0x80, 0x04, 0xed, 0xdb, 0xe8, 0x7f, 0xa0, 0x19, 0xe0, 0xd0, 0x1b, 0x07, 0x16, 0xee,
+	0x8d, 0x43, 0x73, 0x80, 0x3b, 0xa5, 0x2d, 0x65, 0xbb, 0x6a, 0x2c, 0x48, 0xe8, 0x63, 0x0a, 0x44,
+	0x2b, 0x30, 0x1b, 0x12, 0x93, 0x8c, 0xc3, 0x4e, 0x99, 0xa1, 0xc5, 0x0a, 0x5d, 0x82, 0x3a, 0x71,
+	0x86, 0x38, 0x24, 0xe6, 0xd0, 0xef, 0x54, 0xb6, 0x94, 0xed, 0x8a, 0x11, 0x03, 0x90, 0x0a, 0x65,
+	0x42, 0xdc, 0x4e, 0x95, 0xc1, 0xe9, 0x4f, 0x7a, 0x9c, 0x63, 0xbb, 0xb8, 0x87, 0x4f, 0x1c, 0x8b,
+	0x98, 0x7d, 0x17, 0x77, 0x66, 0xb7, 0x94, 0xed, 0x9a, 0xb1, 0x40, 0xa1, 0x77, 0x24, 0x10, 0xbd,
+	0x01, 0x2a, 0xfb, 0x28, 0xcb, 0x73, 0x7b, 0x27, 0x38, 0x08, 0x1d, 0x6f, 0xd4, 0x99, 0x63, 0x07,
+	0xb7, 0x24, 0xfc, 0x09, 0x07, 0xa3, 0x4f, 0xa1, 0x95, 0xfe, 0x80, 0xb0, 0x53, 0xdb, 0x2a, 0x6f,
+	0x37, 0x76, 0xb7, 0x77, 0xfc, 0xfe, 0x4e, 0x56, 0x21, 0x3b, 0x46, 0xf2, 0xb3, 0xc2, 0x3b, 0x23,
+	0x12, 0x9c, 0x1a, 0xcd, 0xd4, 0xb7, 0x86, 0xda, 0x4d, 0x58, 0xca, 0x21, 0xa3, 0x5f, 0x73, 0x8c,
+	0x4f, 0x85, 0x0e, 0xe9, 0x4f, 0xd4, 0x86, 0xea, 0x89, 0xe9, 0x8e, 0xb9, 0xce, 0xca, 0x06, 0x5f,
+	0xbc, 0x5b, 0xba, 0xa1, 0xe8, 0xbf, 0x51, 0x60, 0x31, 0x71, 0x76, 0xe8, 0x7b, 0xa3, 0x10, 0xa3,
+	0x75, 0x28, 0xe3, 0x20, 0x60, 0x1c, 0x1a, 0xbb, 0x75, 0x2a, 0xdf, 0x1d, 0x6a, 0x51, 0x83, 0x42,
+	0xa9, 0x8a, 0x5d, 0x6c, 0xda, 0x38, 0x60, 0xdc, 0xea, 0x86, 0x58, 0xd1, 0x43, 0x4c, 0xdb, 0x0e,
+	0xa8, 0xe6, 0xcb, 0xdb, 0x75, 0x83, 0x2f, 0xd0, 0x0d, 0xe8, 0x58, 0xee, 0x98, 0x3a, 0x48, 0x6f,
+	0x42, 0x53, 0x15, 0xa6, 0xa9, 0x15, 0x81, 0x7f, 0x98, 0x56, 0x98, 0xfe, 0x93, 0x0a, 0xa8, 0xdd,
+	0x71, 0x7f, 0xe8, 0x90, 0x7b, 0x5e, 0x5f, 0xfa, 0xc9, 0x3a, 0x94, 0x88, 0xcf, 0x04, 0x6b, 0xee,
+	0x36, 0xa8, 0x60, 0xf7, 0xbc, 0xfe, 0xa3, 0x53, 0x1f, 0x1b, 0x25, 0xe2, 0x53, 0xc9, 0x2c, 0x6f,
+	0x74, 0xe8, 0x0c, 0x98, 0x64, 0xf3, 0x86, 0x58, 0x21, 0x04, 0x95, 0x71, 0x88, 0x03, 0xe6, 0x12,
+	0x75, 0x83, 0xfd, 0xa6, 0x0e, 0x47, 0xf0, 0xd0, 0x77, 0x4d, 0x82, 0xa9, 0xc3, 0x55, 0x18, 0x0a,
+	0x24, 0x68, 0xdf, 0xa6, 0xf6, 0x8a, 0x08, 0x7c, 0x33, 0x30, 0x87, 0x61, 0xa7, 0x1a, 0xdb, 0x2b,
+	0x2b, 0xd8, 0xce, 0x23, 0x41, 0xfb, 0x90, 0x91, 0x0a, 0x7b, 0x91, 0x14, 0x10, 0xdd, 0x84, 0x8d,
+	0xa1, 0xf9, 0xa2, 0x67, 0x05, 0x98, 0x32, 0x7d, 0xee, 0x05, 0xc7, 0x38, 0xe8, 0x59, 0xde, 0xc8,
+	0x1a, 0x07, 0x01, 0x1e, 0x59, 0xa7, 0xcc, 0xc7, 0xaa, 0x86, 0x36, 0x34, 0x5f, 0xec, 0x31, 0x9a,
+	0xcf, 0x18, 0xc9, 0x5e, 0x4c, 0x81, 0x6e, 0x40, 0xe4, 0xf0, 0xbd, 0xd0, 0xc7, 0x16, 0xf3, 0xb6,
+	0xc6, 0xee, 0x92, 0x50, 0x85, 0x74, 0x87, 0xae, 0x8f, 0x2d, 0x63, 0x3e, 0x48, 0xac, 0xd0, 0x0d,
+	0x98, 0x75, 0xcd, 0x3e, 0x76, 0xa5, 0xdb, 0x6d, 0xe5, 0x7e, 0xc6, 0x7d, 0x46, 0xc2, 0xc5, 0x17,
+	0xf4, 0xd4, 0xcd, 0x72, 0xbe, 0xee, 0x3c, 0x37, 0xab, 0x27, 0xdc, 0x4c, 0xfb, 0x01, 0x34, 0x12,
+	0x9c, 0x2f, 0xb2, 0x55, 0xff, 0x87, 0x02, 0xad, 0xcc, 0x97, 0x51, 0xe3, 0x0d, 0x9d, 0x91, 0xd0,
+	0x60, 0xc8, 0xf8, 0x54, 0x0d, 0x18, 0x3a, 0x23, 0xae, 0xb0, 0x90, 0x11, 0x98, 0x2f, 0x22, 0x82,
+	0x92, 0x20, 0x30, 0x5f, 0x48, 0x82, 0x2e, 0xa8, 0x42, 0xff, 0x52, 0x49, 0xdc, 0x6f, 0x85, 0x79,
+	0x33, 0x07, 0xee, 0xf0, 0x6d, 0x12, 0x24, 0xf4, 0xd3, 0x7a, 0x9e, 0x86, 0x6a, 0xb7, 0xa0, 0x9d,
+	0x47, 0x78, 0xa1, 0x0b, 0xb9, 0x0d, 0xad, 0x4f, 0xc7, 0x38, 0x38, 0x4d, 0xf8, 0xfc, 0x32, 0xcc,
+	0x3e, 0xf5, 0xfa, 0x71, 0x58, 0xac, 0x3e, 0xf5, 0xfa, 0xfb, 0xb6, 0xfe, 0x2f, 0x05, 0x80, 0x1f,
+	0xb7, 0x3f, 0x3a, 0xf4, 0x50, 0x13, 0x4a, 0x11, 0x45, 0xc9, 0xb1, 0xb3, 0x11, 0xb5, 0x34, 0x11,
+	0x51, 0xd3, 0xa1, 0x72, 0x3e, 0x0a, 0x95, 0xf1, 0x2d, 0xaa, 0xa4, 0x6e, 0xd1, 0x7f, 0xc1, 0xbc,
+	0x13, 0xf6, 0x88, 0x37, 0xec, 0x87, 0xc4, 0x1b, 0x61, 0x16, 0x2d, 0x6b, 0x46, 0xc3, 0x09, 0x1f,
+	0x49, 0x10, 0xda, 0x82, 0x79, 0xd7, 0x0c, 0x49, 0xef, 0xa8, 0xdf, 0xa3, 0xc1, 0x95, 0xf9, 0x73,
+	0xd9, 0x00, 0x0a, 0xbb, 0xdb, 0x7f, 0xe4, 0x0c, 0x31, 0xd2, 0xa0, 0x46, 0xb5, 0xe6, 0x7a, 0xa6,
+	0xcd, 0x5c, 0xb7, 0x6c, 0x44, 0x6b, 0x1a, 0x4c, 0xd9, 0xd5, 0x70, 0x46, 0x83, 0xc8, 0x72, 0x35,
+	0x1e, 0x4c, 0x25, 0x5c, 0x98, 0x4f, 0xff, 0x6b, 0x19, 0xd4, 0x58, 0x4d, 0x22, 0x6a, 0x35, 0xa3,
+	0xd8, 0x50, 0x3e, 0x33, 0x1c, 0x5c, 0x4f, 0x7d, 0x78, 0x73, 0x77, 0x93, 0x5a, 0x3c, 0xcb, 0x8d,
+	0xba, 0x40, 0x97, 0x51, 0x45, 0x8a, 0xb9, 0x0e, 0x2d, 0x6a, 0x07, 0xfe, 0xdc, 0xf5, 0x9c, 0xd1,
+	0xa1, 0xc7, 0x34, 0xd4, 0xd8, 0x6d, 0x52, 0x06, 0xb1, 0x29, 0x8c, 0x85, 0xa7, 0x5e, 0xff, 0x80,
+	0x51, 0xd1, 0xa5, 0x8c, 0xa6, 0xd5, 0xdc, 0x68, 0xfa, 0x4a, 0x62, 0x82, 0xbc, 0xd9, 0x73, 0xf1,
+	0xcd, 0x9e, 0xf8, 0x9e, 0xbc, 0x9b, 0xfd, 0x12, 0xd7, 0xf2, 0x0b, 0xa8, 0x47, 0x1a, 0x42, 0x35,
+	0xa8, 0x38, 0x23, 0x87, 0xa8, 0x33, 0xa8, 0x01, 0x73, 0x3e, 0x1e, 0xd9, 0xce, 0x68, 0xa0, 0x2a,
+	0x08, 0x60, 0xd6, 0x1b, 0xb9, 0xce, 0x08, 0xab, 0x25, 0xd4, 0x04, 0xb0, 0x9d, 0xd0, 0x37, 0x89,
+	0x75, 0x84, 0x6d, 0xb5, 0x8c, 0xe6, 0xa1, 0x76, 0xe8, 0x8c, 0x9c, 0x90, 0xae, 0x2a, 0x74, 0x5b,
+	0x48, 0x3c, 0xdf, 0xc7, 0xb6, 0x5a, 0xd5, 0x7f, 0xa9, 0xc4, 0xc6, 0x0d, 0xe5, 0x25, 0xd8, 0x00,
+	0xf0, 0x03, 0xef, 0x29, 0xb6, 0x48, 0x7c, 0x11, 0xea, 0x02, 0xc2, 0xd3, 0x03, 0xf6, 0x4d, 0xbd,
+	0x10, 0xbb, 0xd8, 0x22, 0x9e, 0x7c, 0x9c, 0x16, 0x18, 0xb4, 0x2b, 0x80, 0xd4, 0x87, 0xb9, 0x31,
+	0x7b, 0x96, 0x67, 0x8b, 0x2b, 0x5f, 0x35, 0x1a, 0x1c, 0xb6, 0x47, 0x41, 0xf4, 0x93, 0xc9, 0xa9,
+	0x8f, 0xc3, 0x4e, 0x65, 0xab, 0x4c, 0xaf, 0x26, 0x5b, 0xe8, 0xff, 0x54, 0x60, 0xee, 0x9e, 0xd7,
+	0x67, 0xf6, 0xcc, 0xbf, 0x8f, 0x19, 0x09, 0x4b, 0x59, 0x09, 0xb9, 0x77, 0x96, 0x23, 0xef, 0x8c,
+	0xbd, 0xb0, 0x72, 0x21, 0x2f, 0x7c, 0x2b, 0xb2, 0x36, 0x7f, 0x8e, 0x56, 0x45, 0xbc, 0xa2, 0xa2,
+	0xbd, 0x6a, 0x23, 0x7f, 0x0a, 0x8b, 0x09, 0x43, 0x14, 0x49, 0x0e, 0x2e, 0x43, 0xe5, 0xa9, 0xd7,
+	0xa7, 0x11, 0x97, 0xca, 0xd6, 0x48, 0xc8, 0x66, 0x30, 0x84, 0xfe, 0x07, 0x05, 0xd0, 0x7d, 0x27,
+	0x24, 0xe2, 0x26, 0x9f, 0x1d, 0xe3, 0x26, 0xec, 0x55, 0x9a, 0xb4, 0x57, 0x26, 0xce, 0x95, 0x27,
+	0xe2, 0xdc, 0x3a, 0xd4, 0x7d, 0x73, 0x80, 0x7b, 0xa1, 0xf3, 0x35, 0x16, 0x29, 0x47, 0x8d, 0x02,
+	0xba, 0xce, 0xd7, 0x98, 0x19, 0x8d, 0x22, 0x89, 0x77, 0x8c, 0x47, 0x9d, 0xaa, 0x30, 0x9a, 0x39,
+	0xc0, 0x8f, 0x28, 0x40, 0xff, 0x93, 0x02, 0x0b, 0x5c, 0xd2, 0xee, 0x78, 0x38, 0x34, 0x83, 0xd3,
+	0x8b, 0x87, 0xd9, 0x36, 0x54, 0xa9, 0xb8, 0x58, 0x48, 0xc6, 0x17, 0x74, 0x5b, 0xe2, 0xc3, 0x84,
+	0x58, 0x10, 0x7f, 0x17, 0xfa, 0x6f, 0x58, 0x60, 0x59, 0x74, 0x6f, 0x88, 0x43, 0x96, 0xee, 0x72,
+	0xd9, 0xe6, 0x19, 0xf0, 0x80, 0xc3, 0xa8, 0xd7, 0x1f, 0xc9, 0xe4, 0x2d, 0x19, 0x71, 0x17, 0x22,
+	0x28, 0x0d, 0xba, 0xfa, 0x4f, 0x15, 0x58, 0x4a, 0xe9, 0xbc, 0x88, 0x25, 0xdf, 0x84, 0xb9, 0xf8,
+	0xf9, 0xa4, 0xc6, 0x5c, 0x8c, 0xa3, 0x9c, 0x50, 0x86, 0x21, 0x29, 0xd0, 0x6b, 0xd0, 0x1a, 0xe1,
+	0x17, 0xa4, 0x97, 0xd0, 0x25, 0xff, 0xdc, 0x05, 0x0a, 0x7e, 0x18, 0xe9, 0xf3, 0x47, 0xa0, 0xee,
+	0x99, 0x23, 0x0b, 0xbb, 0x89, 0xe7, 0x6d, 0x2d, 0x65, 0xfa, 0xea, 0xad, 0x52, 0x47, 0x91, 0xe6,
+	0xbf, 0x04, 0xc0, 0x51, 0xbd, 0x90, 0xc8, 0x1b, 0x5d, 0x63, 0xa8, 0x2e, 0x09, 0xf4, 0x7b, 0xd0,
+	0x7a, 0x68, 0x8e, 0x43, 0xfc, 0x2a, 0x78, 0x39, 0xb0, 0x98, 0xc8, 0x85, 0x8a, 0xe8, 0x27, 0x3e,
+	0xaa, 0x74, 0xf6, 0x51, 0xe5, 0xcc, 0x51, 0x6f, 0x81, 0x1a, 0x8b, 0x5d, 0xe0, 0x24, 0xfd, 0x6d,
+	0x58, 0x4c, 0x28, 0xad, 0xc8, 0x8e, 0xbf, 0x29, 0xd0, 0x79, 0xec, 0xdb, 0x26, 0xa1, 0x87, 0x50,
+	0x17, 0xf0, 0xc6, 0xe4, 0xbc, 0xab, 0x76, 0x05, 0x16, 0xc5, 0xeb, 0x43, 0xf8, 0x86, 0xde, 0x30,
+	0x14, 0xe9, 0x89, 0x48, 0x74, 0x04, 0xa3, 0x83, 0x10, 0xbd, 0x07, 0x5a, 0x86, 0x76, 0x10, 0x98,
+	0x16, 0x3e, 0x1c, 0xbb, 0x74, 0x13, 0x8f, 0x71, 0xab, 0xa9, 0x4d, 0x9f, 0x08, 0xfc, 0x41, 0x88,
+	0x3e, 0x82, 0x4b, 0x62, 0x73, 0xec, 0xbb, 0xce, 0x88, 0xe0, 0xe0, 0xc4, 0x64, 0xdb, 0x2b, 0x6c,
+	0xfb, 0x1a, 0xa7, 0x89, 0x6a, 0x93, 0x7d, 0x41, 0x71, 0x10, 0xea, 0x37, 0x60, 0x2d, 0xe7, 0xe3,
+	0x8a, 0xe8, 0xe5, 0x36, 0x2c, 0x77, 0x31, 0x35, 0xf1, 0x7d, 0x6f, 0x70, 0x1f, 0x9f, 0x60, 0xf7,
+	0x1c, 0x9d, 0xb4, 0xa1, 0xea, 0x52, 0x32, 0x19, 0x19, 0xd9, 0x42, 0xff, 0x3f, 0x58, 0xc9, 0x72,
+	0x29, 0x72, 0xb8, 0x0d, 0x2b, 0x0f, 0x7c, 0x1c, 0x08, 0xb9, 0xcd, 0xf0, 0xf8, 0x3c, 0x8b, 0x6c,
+	0x40, 0xc9, 0xf3, 0xd9, 0xd1, 0xcd, 0xdd, 0x05, 0x59, 0xeb, 0x98, 0xe1, 0xf1, 0x03, 0xdf, 0x28,
+	0x79, 0x3e, 0x7b, 0xa8, 0x28, 0x17, 0x59, 0x6f, 0xb1, 0x85, 0x7e, 0x1d, 0x56, 0x27, 0x4e, 0x29,
+	0x28, 0x5d, 0xa4, 0xd4, 0x2e, 0x4b, 0x5e, 0xcf, 0x91, 0x6e, 0x1d, 0xea, 0xa2, 0x0e, 0x89, 0xc2,
+	0x5e, 0x8d, 0x03, 0x78, 0x6e, 0x29, 0x52, 0xaf, 0x72, 0x32, 0xf5, 0xa2, 0xd2, 0x4d, 0x9c, 0x52,
+	0x44, 0xba, 0xdf, 0x97, 0xa0, 0x41, 0xb7, 0xd0, 0xe4, 0x61, 0xec, 0xf2, 0xf0, 0x29, 0x7e, 0xc7,
+	0x82, 0x81, 0x04, 0x31, 0xe9, 0xe8, 0x6b, 0x5b, 0x3a, 0xaf, 0x4e, 0x2c, 0xe7, 0xd6, 0x89, 0x95,
+	0x44, 0x9d, 0x88, 0xa0, 0x62, 0x05, 0x9e, 0x7c, 0x1a, 0xd8, 0x6f, 0x74, 0x15, 0x6a, 0x16, 0x4d,
+	0x64, 0x7a, 0x63, 0x9f, 0x05, 0xdc, 0x26, 0x8f, 0x8d, 0x7b, 0x14, 0xf6, 0xd8, 0x7f, 0xe8, 0xb9,
+	0x8e, 0x75, 0x6a, 0xcc, 0x59, 0x7c, 0x49, 0x4f, 0xf3, 0xe9, 0x7d, 0xe7, 0x09, 0x6f, 0xcd, 0x10,
+	0x2b, 0xf4, 0x06, 0x2c, 0xb2, 0x64, 0xf9, 0xd0, 0x09, 0x30, 0xbb, 0x47, 0xbd, 0x21, 0xcf, 0x77,
+	0xcb, 0x46, 0x93, 0x22, 0x3e, 0x76, 0x02, 0x4c, 0xdd, 0xfb, 0x20, 0xa4, 0xa4, 0x2c, 0xbc, 0xa6,
+	0x48, 0xeb, 0x9c, 0x94, 0x22, 0x62, 0x52, 0xfd, 0x13, 0xe8, 0xf0, 0x3c, 0x31, 0xa1, 0x2e, 0x69,
+	0xc9, 0x37, 0xa1, 0x26, 0x55, 0x24, 0xf4, 0xdc, 0x12, 0xaa, 0x89, 0x28, 0x23, 0x02, 0xfd, 0x0b,
+	0x58, 0xcb, 0x61, 0x54, 0x2c, 0x07, 0x48, 0x19, 0xa7, 0x94, 0x35, 0x0e, 0x95, 0x31, 0xf6, 0x82,
+	0x97, 0x91, 0x31, 0x19, 0x09, 0x2e, 0x24, 0xa3, 0xfe, 0x1e, 0x74, 0x6e, 0x63, 0x17, 0xe7, 0x8a,
+	0x70, 0x9e, 0x73, 0xd1, 0x63, 0x73, 0x36, 0x17, 0x3c, 0x56, 0x26, 0x54, 0x72, 0x63, 0x58, 0xf8,
+	0xd8, 0x01, 0xac, 0xe5, 0x6c, 0x2e, 0x62, 0x91, 0xff, 0x85, 0xba, 0xe4, 0x23, 0x5f, 0xf3, 0x09,
+	0xad, 0xc6, 0x14, 0xfa, 0xaf, 0x15, 0x76, 0xdb, 0x64, 0xd1, 0x9f, 0xed, 0x95, 0x28, 0x13, 0xbd,
+	0x92, 0x33, 0x6f, 0x9b, 0x06, 0x35, 0x49, 0x2a, 0xee, 0x5b, 0xb4, 0x46, 0x57, 0xe9, 0xdd, 0x60,
+	0xbd, 0x95, 0x0a, 0x93, 0xaa, 0x2d, 0x37, 0x27, 0xfb, 0x0d, 0x86, 0xa0, 0xd1, 0x07, 0xa0, 0x66,
+	0x71, 0xf4, 0x7e, 0x8e, 0xcc, 0x21, 0x16, 0x42, 0xb1, 0xdf, 0x34, 0x77, 0xb2, 0xf1, 0xa1, 0x39,
+	0x76, 0x49, 0x2f, 0x99, 0xd8, 0xce, 0x0b, 0xe0, 0x13, 0x0a, 0xa3, 0x62, 0x05, 0xf8, 0xd9, 0xd8,
+	0x09, 0x30, 0x4f, 0x1a, 0x6b, 0x46, 0xb4, 0xd6, 0xf7, 0x41, 0x33, 0xf0, 0xc0, 0x09, 0x09, 0x0e,
+	0x12, 0x07, 0x26, 0x5c, 0x34, 0xfa, 0xa0, 0xb4, 0x8b, 0x46, 0x94, 0x11, 0x81, 0xfe, 0x2e, 0xac,
+	0xe7, 0xb2, 0xba, 0xa8, 0x93, 0x66, 0x85, 0x38, 0xcf, 0x26, 0x29, 0x27, 0xbd, 0xf0, 0xb1, 0xd2,
+	0xcf, 0xe4, 0xc6, 0xb0, 0xf0, 0xb1, 0x09, 0x27, 0x4d, 0x6c, 0x2e, 0xe8, 0xa4, 0x92, 0x4f, 0xd6,
+	0x49, 0x23, 0xf9, 0x63, 0x0a, 0xfd, 0x8f, 0x65, 0x58, 0x95, 0x9a, 0xbd, 0x23, 0xd2, 0x6d, 0x29,
+	0x65, 0x07, 0xe6, 0x68, 0xf7, 0x11, 0x87, 0xa1, 0x90, 0x50, 0x2e, 0x29, 0x46, 0x76, 0x1f, 0xb9,
+	0x53, 0xc8, 0x25, 0xda, 0x04, 0xb0, 0x4c, 0xdf, 0xec, 0x3b, 0xae, 0x43, 0x4e, 0x45, 0x0e, 0x93,
+	0x80, 0x64, 0x13, 0xfd, 0xca, 0x44, 0xa2, 0x9f, 0xd7, 0x0b, 0xae, 0xe6, 0xf7, 0x82, 0xef, 0x42,
+	0x3d, 0x6e, 0x3b, 0xcd, 0xb2, 0x4f, 0xbd, 0x42, 0x3f, 0x75, 0xca, 0xf7, 0xec, 0x64, 0x1a, 0x4f,
+	0xf1, 0x66, 0xf4, 0x51, 0xa6, 0xf6, 0x7f, 0xfd, 0x2c, 0x36, 0x79, 0xd5, 0xe1, 0xfb, 0xd0, 0xfc,
+	0xcf, 0xbb, 0x55, 0x2f, 0x53, 0x5b, 0xfe, 0x4a, 0x81, 0xce, 0xa4, 0xa0, 0x05, 0xdf, 0x97, 0xb3,
+	0x4b, 0xae, 0xb3, 0x7a, 0xce, 0xe5, 0x33, 0x7b, 0xce, 0x3d, 0x58, 0xde, 0xf3, 0x02, 0xdb, 0x1b,
+	0x65, 0x3d, 0xaa, 0xc0, 0x7c, 0x42, 0x94, 0x79, 0x3c, 0x0a, 0x32, 0xcf, 0xe5, 0xac, 0x68, 0xfd,
+	0x8e, 0x45, 0xdd, 0x47, 0x73, 0xc7, 0xec, 0x01, 0x45, 0xae, 0xe4, 0xe7, 0xd0, 0x94, 0x1b, 0xf8,
+	0xf6, 0x57, 0x26, 0xd0, 0x06, 0xac, 0xb3, 0xfb, 0x9a, 0x66, 0x2f, 0xef, 0xbb, 0xee, 0xc0, 0xa5,
+	0x7c, 0x74, 0x11, 0x43, 0x5d, 0x85, 0x39, 0x8b, 0xd3, 0x8b, 0xfb, 0x8c, 0x18, 0x41, 0x8a, 0x95,
+	0x21, 0x49, 0xf4, 0x15, 0x68, 0xef, 0x71, 0xab, 0xdc, 0xc5, 0xa6, 0x4b, 0x8e, 0xa4, 0x08, 0xbf,
+	0x2d, 0xc1, 0x72, 0x06, 0x51, 0xe4, 0xf0, 0x0e, 0xcc, 0x1d, 0x31, 0xf2, 0x53, 0xa6, 0x81, 0x9a,
+	0x21, 0x97, 0x89, 0x01, 0x46, 0x39, 0x35, 0xc0, 0x78, 0x0b, 0x96, 0xa2, 0x59, 0x55, 0x2f, 0xc0,
+	0xa6, 0x75, 0xc4, 0x06, 0x3f, 0x15, 0xb6, 0x1b, 0x45, 0x28, 0x43, 0x62, 0xe8, 0x50, 0x49, 0x2a,
+	0x3c, 0x14, 0x57, 0x3d, 0x06, 0xa0, 0xd7, 0xa1, 0x15, 0x12, 0x93, 0xce, 0x90, 0x22, 0x9a, 0x59,
+	0x96, 0xa9, 0x37, 0x19, 0xf8, 0x4e, 0x44, 0xb8, 0x01, 0xc0, 0x4b, 0x7d, 0xd6, 0x39, 0x99, 0x63,
+	0x34, 0x75, 0x06, 0xa1, 0x7d, 0x17, 0xfa, 0x50, 0xf9, 0x81, 0xd7, 0x77, 0xf1, 0x90, 0xb7, 0xee,
+	0xeb, 0x46, 0xb4, 0xa6, 0xb3, 0xb4, 0x25, 0xf9, 0x82, 0xd3, 0x64, 0x5f, 0xba, 0xeb, 0x2a, 0xcc,
+	0xd1, 0x72, 0x20, 0xf6, 0x8c, 0x59, 0xba, 0xdc, 0xb7, 0x59, 0x3a, 0xeb, 0x85, 0x44, 0xdc, 0x64,
+	0xf6, 0x1b, 0x5d, 0x83, 0xe5, 0x68, 0xa6, 0x20, 0x9e, 0xc0, 0x21, 0x1e, 0x11, 0x59, 0x58, 0xb4,
+	0x25, 0xd2, 0x48, 0xe0, 0xa8, 0x54, 0x87, 0xa6, 0xe3, 0x7a, 0x27, 0x22, 0x5f, 0xae, 0x19, 0xd1,
+	0x1a, 0xdd, 0x4e, 0x86, 0x37, 0xde, 0xa5, 0x7a, 0x8d, 0x4d, 0x1b, 0x26, 0x25, 0x3d, 0x23, 0xb4,
+	0xc5, 0x75, 0xc7, 0x6c, 0xb2, 0xee, 0x58, 0x81, 0xd9, 0x67, 0x63, 0x3c, 0x8e, 0xd3, 0x69, 0xbe,
+	0xe2, 0x6a, 0x72, 0xbc, 0x80, 0x46, 0xef, 0x9a, 0xe8, 0xf2, 0x88, 0x35, 0x6d, 0x4f, 0x3c, 0x37,
+	0x1d, 0x92, 0xac, 0x6c, 0x79, 0xf6, 0xbc, 0x40, 0xc1, 0x51, 0x5d, 0xfb, 0x72, 0xc1, 0x50, 0xff,
+	0x0a, 0xda, 0xe9, 0x2f, 0x14, 0x6e, 0x7a, 0xee, 0x55, 0xa5, 0xbd, 0x1e, 0x49, 0x40, 0x1f, 0x2a,
+	0x99, 0xaf, 0x48, 0xe0, 0x4d, 0xdb, 0x0e, 0xf4, 0x67, 0xd0, 0xca, 0x26, 0xaa, 0x1b, 0x00, 0x01,
+	0xff, 0x29, 0xf9, 0x96, 0x8d, 0xba, 0x80, 0xec, 0xdb, 0xe8, 0x4d, 0xa8, 0x50, 0xab, 0x33, 0x6e,
+	0xa2, 0x4f, 0x98, 0x63, 0x01, 0x83, 0x11, 0x51, 0xc7, 0xb0, 0x69, 0x57, 0x9f, 0xa7, 0x42, 0xec,
+	0xb7, 0xfe, 0x8d, 0x02, 0xea, 0x44, 0x7e, 0x7b, 0xce, 0xa1, 0xef, 0x40, 0xcd, 0xc6, 0x96, 0x13,
+	0xbd, 0xb0, 0x8d, 0xdd, 0xce, 0xe4, 0xc1, 0x9c, 0x95, 0x11, 0x51, 0xca, 0x9b, 0x5c, 0x9e, 0x76,
+	0x93, 0x03, 0x7c, 0xe2, 0x1d, 0x63, 0x5b, 0x78, 0x9a, 0x5c, 0xea, 0x43, 0x58, 0xec, 0x5a, 0xa6,
+	0x8b, 0x1f, 0xfb, 0xe7, 0x8e, 0x4b, 0xe8, 0x75, 0xe4, 0x1d, 0x73, 0x92, 0x19, 0x0b, 0x35, 0x05,
+	0x58, 0x8e, 0x86, 0x3a, 0x71, 0xe3, 0x8b, 0x3f, 0x16, 0x72, 0xa9, 0x9f, 0x02, 0x4a, 0x1e, 0x57,
+	0x24, 0x0a, 0xbd, 0x0e, 0xad, 0x41, 0x60, 0x8e, 0x08, 0xb6, 0xb3, 0xa7, 0x0a, 0xb0, 0x3c, 0x75,
+	0x03, 0xa0, 0x6f, 0x5a, 0xc7, 0xde, 0xe1, 0x61, 0xdc, 0x42, 0xa9, 0x0b, 0xc8, 0x41, 0xa8, 0xdf,
+	0x84, 0x79, 0x1a, 0x30, 0x3e, 0x93, 0xb3, 0x92, 0x33, 0xe7, 0xa0, 0x6d, 0xa8, 0x26, 0x47, 0xe4,
+	0x7c, 0xc1, 0xba, 0x80, 0x49, 0x1e, 0x85, 0x9f, 0xb6, 0x1d, 0xa8, 0xcb, 0x19, 0x8d, 0x0c, 0xe4,
+	0xaa, 0x0c, 0xe4, 0x11, 0xb3, 0x98, 0x84, 0x32, 0x8c, 0xe2, 0x89, 0x63, 0x8b, 0x28, 0x02, 0x12,
+	0xb4, 0x6f, 0xeb, 0xd7, 0xa0, 0x9d, 0x16, 0xa4, 0xc8, 0x13, 0xf8, 0x63, 0x58, 0x79, 0x48, 0x5f,
+	0xe9, 0x90, 0x18, 0x89, 0x78, 0x54, 0xe8, 0x03, 0x32, 0x02, 0x89, 0x84, 0x21, 0x21, 0xd0, 0x75,
+	0x58, 0x9d, 0xe0, 0x5d, 0x44, 0xa6, 0xdf, 0x29, 0xb0, 0x7a, 0xe0, 0x0c, 0x02, 0x93, 0xe0, 0x03,
+	0x4c, 0xcc, 0x2e, 0x7f, 0x1e, 0xb8, 0x54, 0x3b, 0xac, 0x7b, 0xa3, 0xc4, 0xbd, 0xfd, 0x29, 0x84,
+	0x3b, 0xa2, 0x9d, 0xb3, 0x02, 0xb3, 0xc4, 0x0c, 0x06, 0x98, 0xc8, 0xb1, 0x3a, 0x5f, 0xe9, 0x1f,
+	0x42, 0xe9, 0x81, 0x4f, 0x47, 0x29, 0x7c, 0x0e, 0xa0, 0xce, 0xa0, 0x3a, 0x54, 0xbb, 0xc4, 0x0c,
+	0x08, 0x9f, 0xb0, 0x3c, 0xc1, 0x81, 0x73, 0x78, 0xaa, 0x96, 0x18, 0xc9, 0x73, 0x87, 0x58, 0x47,
+	0x6a, 0x99, 0x92, 0xdc, 0xec, 0x7b, 0x01, 0x51, 0x2b, 0xfa, 0x37, 0x65, 0xe8, 0x4c, 0x1e, 0x5d,
+	0xc4, 0x77, 0xdb, 0x50, 0xf5, 0x8f, 0xcc, 0x30, 0xca, 0xdd, 0xd8, 0x82, 0xa6, 0xb9, 0x5c, 0xb2,
+	0x1e, 0x1e, 0xd9, 0xbe, 0xe7, 0xc4, 0x0f, 0x45, 0x8b, 0xc3, 0xef, 0x48, 0x30, 0x8d, 0x6b, 0xf4,
+	0x4d, 0xa0, 0xbe, 0x1f, 0x38, 0x04, 0xcb, 0xd6, 0xde, 0x3c, 0x07, 0x7e, 0xc6, 0x60, 0xf4, 0x11,
+	0x3d, 0x61, 0x9f, 0xe0, 0x8c, 0x06, 0x62, 0xa6, 0x18, 0x03, 0xd0, 0x36, 0xa8, 0xac, 0x49, 0xc2,
+	0x21, 0xc9, 0x1e, 0x37, 0xeb, 0x91, 0xf0, 0x8f, 0x67, 0x93, 0xc5, 0x2b, 0xb0, 0x98, 0xa4, 0x64,
+	0xef, 0x27, 0x7b, 0x22, 0xea, 0x46, 0x2b, 0x26, 0x65, 0x9f, 0x87, 0xee, 0x03, 0x0c, 0x9d, 0x70,
+	0xc8, 0x86, 0x51, 0x72, 0x1e, 0x7e, 0x35, 0xdf, 0x46, 0x62, 0x0e, 0x73, 0x10, 0x91, 0xf3, 0x77,
+	0x2a, 0xb1, 0x5f, 0xfb, 0x00, 0x5a, 0x19, 0xf4, 0x85, 0x9e, 0x8d, 0x8f, 0x00, 0xba, 0xd8, 0x0a,
+	0x30, 0xa1, 0xa7, 0x9e, 0x37, 0xe7, 0x92, 0xe5, 0x6e, 0x29, 0x2e, 0x77, 0xf5, 0x2f, 0x41, 0x7d,
+	0x38, 0x26, 0x9c, 0x47, 0xc1, 0x71, 0x59, 0x0e, 0x9b, 0x58, 0xc2, 0x72, 0x22, 0x55, 0xa7, 0xcd,
+	0xe7, 0x04, 0xf3, 0x22, 0x97, 0xe2, 0x2e, 0x2c, 0xf1, 0xc2, 0xf3, 0x65, 0x25, 0xa2, 0x71, 0x22,
+	0xcd, 0xa9, 0xc8, 0xf1, 0xd7, 0xf8, 0x7c, 0x89, 0x6f, 0x29, 0x38, 0x3e, 0xd4, 0xbf, 0x82, 0xa5,
+	0xd4, 0xa6, 0x22, 0xd7, 0x63, 0x1b, 0xe6, 0x42, 0x4e, 0x2f, 0x82, 0x22, 0x1b, 0x03, 0xc7, 0xa6,
+	0x34, 0x24, 0xfa, 0xca, 0x3b, 0x30, 0x27, 0xc2, 0x33, 0x1d, 0x74, 0xee, 0x3d, 0xe9, 0xde, 0xc6,
+	0x43, 0x4f, 0x9d, 0x41, 0xb3, 0x50, 0xba, 0x7d, 0xa0, 0x2a, 0x68, 0x0e, 0xca, 0x7b, 0xb7, 0xf7,
+	0xd4, 0x12, 0xc5, 0x7e, 0x6c, 0x1e, 0xd3, 0x8a, 0x5d, 0x2d, 0x5f, 0xf9, 0x90, 0x4d, 0x58, 0x79,
+	0xc3, 0x17, 0xb5, 0xa0, 0xc1, 0x7f, 0xb1, 0xd1, 0x81, 0x3a, 0x83, 0x54, 0x98, 0xe7, 0x00, 0x03,
+	0x87, 0xe3, 0x21, 0x56, 0x15, 0x3a, 0x61, 0xe5, 0x90, 0x2e, 0xf1, 0x7c, 0xb5, 0x74, 0xe5, 0x26,
+	0x2c, 0xa4, 0x3a, 0x92, 0x94, 0x87, 0x00, 0x74, 0x8f, 0x1d, 0x5f, 0x9d, 0x49, 0x00, 0x1e, 0x8c,
+	0x2c, 0xc1, 0x42, 0x00, 0x6e, 0xba, 0xae, 0x5a, 0xba, 0xf2, 0x3e, 0x34, 0x12, 0x25, 0x03, 0x45,
+	0x3f, 0x1e, 0xf1, 0x74, 0x1d, 0xdb, 0xea, 0x0c, 0x9d, 0xe1, 0xee, 0xc9, 0x95, 0x42, 0xb9, 0xdd,
+	0x72, 0x4d, 0xeb, 0xd8, 0xa5, 0x25, 0x9d, 0xad, 0x96, 0x76, 0x7f, 0xbe, 0x04, 0xb3, 0x7c, 0x0c,
+	0x8e, 0x1e, 0x80, 0x9a, 0xad, 0xf5, 0xd0, 0xfa, 0x19, 0xa5, 0xaa, 0x76, 0x29, 0x1f, 0xc9, 0xed,
+	0xa2, 0xcf, 0xa0, 0x7d, 0x68, 0xa6, 0xeb, 0x28, 0xb4, 0x16, 0x17, 0x38, 0x59, 0x66, 0x5a, 0x1e,
+	0x2a, 0x62, 0xf5, 0x25, 0xb4, 0xf3, 0x4a, 0x1c, 0x74, 0x39, 0x1a, 0xc8, 0xe6, 0xd7, 0x46, 0xda,
+	0xd6, 0x74, 0x82, 0x88, 0xf9, 0xc7, 0xb0, 0x90, 0xaa, 0x5d, 0x10, 0xcb, 0x86, 0xf2, 0xea, 0x1c,
+	0x6d, 0x2d, 0x07, 0x13, 0xf1, 0x79, 0x17, 0xea, 0xd1, 0x7c, 0x0a, 0xb5, 0xf3, 0xfe, 0xba, 0xa3,
+	0x2d, 0x67, 0xa0, 0xd1, 0xde, 0xff, 0x87, 0x9a, 0x6c, 0xc9, 0xa0, 0xa5, 0xf4, 0x94, 0x99, 0xef,
+	0x6c, 0xe7, 0x8d, 0x9e, 0xf9, 0xa1, 0x12, 0x1a, 0xa2, 0x14, 0x51, 0x98, 0x3a, 0x74, 0x62, 0x46,
+	0xac, 0xcf, 0xa0, 0x1f, 0x42, 0x23, 0x31, 0x72, 0x44, 0x2b, 0x94, 0x6e, 0x72, 0xee, 0xab, 0xad,
+	0x4e, 0xc0, 0x93, 0x62, 0xcb, 0x39, 0x19, 0x17, 0x3b, 0x33, 0xec, 0xd3, 0xda, 0x69, 0x60, 0x52,
+	0xec, 0x68, 0x5e, 0xc6, 0xc5, 0xce, 0xce, 0x1c, 0xb5, 0xe5, 0x0c, 0x34, 0xda, 0x6b, 0xc0, 0xe2,
+	0xc4, 0x6c, 0x09, 0x31, 0x67, 0x9c, 0x36, 0x4f, 0xd3, 0x36, 0xa6, 0x60, 0x93, 0xbe, 0x9a, 0x9e,
+	0x17, 0x71, 0x5f, 0xcd, 0x9d, 0x44, 0x69, 0x5a, 0x1e, 0x2a, 0x62, 0x75, 0x1f, 0x5a, 0x99, 0xe9,
+	0x0e, 0x62, 0x1b, 0xf2, 0x07, 0x4b, 0xda, 0x7a, 0x2e, 0x2e, 0xc9, 0x2d, 0x33, 0x8d, 0xe1, 0xdc,
+	0xf2, 0x07, 0x41, 0xda, 0x7a, 0x2e, 0x2e, 0xa9, 0xba, 0x89, 0x81, 0x01, 0x57, 0xdd, 0xb4, 0x81,
+	0x84, 0xb6, 0x31, 0x05, 0x9b, 0x6b, 0x8e, 0x34, 0xcf, 0x69, 0x03, 0x04, 0x6d, 0x63, 0x0a, 0x36,
+	0xc9, 0x73, 0xa2, 0x7b, 0xcf, 0x79, 0x4e, 0x9b, 0x08, 0x68, 0x1b, 0x53, 0xb0, 0x49, 0x9e, 0x13,
+	0xad, 0x79, 0xce, 0x73, 0x5a, 0xbb, 0x5f, 0xdb, 0x98, 0x82, 0x8d, 0x78, 0x7e, 0x0e, 0x4b, 0x32,
+	0x00, 0x26, 0x9b, 0xf1, 0x9b, 0xc9, 0xc8, 0x38, 0xd9, 0x18, 0xd6, 0x2e, 0x4f, 0xc5, 0xe7, 0x6a,
+	0x20, 0xe2, 0x9b, 0xd6, 0x40, 0x96, 0xeb, 0xc6, 0x14, 0x6c, 0x9e, 0x06, 0x24, 0x36, 0xa3, 0x81,
+	0x6c, 0x2f, 0x59, 0xdb, 0x98, 0x82, 0x4d, 0x5e, 0xe4, 0x28, 0xf7, 0xe0, 0x17, 0x39, 0x9b, 0xe7,
+	0x68, 0xcb, 0x19, 0x68, 0xb4, 0x77, 0x0f, 0xe6, 0x93, 0xb9, 0x03, 0x5a, 0x8d, 0x3f, 0x20, 0xcd,
+	0xa1, 0x33, 0x89, 0xc8, 0x06, 0x31, 0x0e, 0x4f, 0x04, 0xb1, 0x74, 0x72, 0xa1, 0xad, 0x4e, 0xc0,
+	0x93, 0x9f, 0x10, 0x8d, 0xb0, 0xf9, 0x27, 0x64, 0xff, 0xe9, 0xab, 0x2d, 0x67, 0xa0, 0xc9, 0x4f,
+	0x48, 0x96, 0xcd, 0x68, 0x5a, 0x05, 0xaf, 0x4d, 0xad, 0xb0, 0xf5, 0x19, 0xf4, 0x1e, 0xd4, 0x24,
+	0x86, 0x47, 0xd1, 0xac, 0x6f, 0xb7, 0xd3, 0x40, 0xb9, 0x71, 0x5b, 0x79, 0x5b, 0x41, 0x1f, 0x00,
+	0xc4, 0x05, 0x2f, 0xe2, 0x0f, 0x4c, 0xb6, 0xde, 0xd6, 0x56, 0xb2, 0xe0, 0xa4, 0x4f, 0x48, 0x47,
+	0x8c, 0x32, 0x6a, 0x94, 0x7a, 0xd9, 0xb3, 0xc5, 0x90, 0xb6, 0x31, 0x05, 0x9b, 0x0c, 0xa6, 0xcc,
+	0x65, 0x62, 0x86, 0x6b, 0x91, 0x1b, 0x4d, 0x70, 0xd3, 0xf2, 0x50, 0x11, 0xab, 0x07, 0xa0, 0x66,
+	0xf3, 0x7d, 0x9e, 0x94, 0x4c, 0xa9, 0xd4, 0xb4, 0x4b, 0xf9, 0xc8, 0x88, 0xe1, 0x01, 0xac, 0x18,
+	0xd8, 0xf7, 0x02, 0x22, 0xf3, 0x81, 0xa8, 0x5c, 0x5f, 0x9d, 0xa8, 0x97, 0x93, 0xa6, 0xcb, 0x2b,
+	0x86, 0x79, 0x78, 0xce, 0x54, 0xa5, 0x3c, 0x3c, 0xe7, 0x97, 0xc1, 0xda, 0x7a, 0x2e, 0x4e, 0x72,
+	0xbb, 0xd5, 0xf9, 0xf3, 0x77, 0x9b, 0xca, 0xb7, 0xdf, 0x6d, 0x2a, 0x7f, 0xff, 0x6e, 0x53, 0xf9,
+	0xc5, 0xf7, 0x9b, 0x33, 0xdf, 0x7e, 0xbf, 0x39, 0xf3, 0x97, 0xef, 0x37, 0x67, 0xfa, 0xb3, 0xac,
+	0x49, 0x7e, 0xed, 0xdf, 0x03, 0x00, 0xb8, 0xd7, 0x01, 0xed, 0xc9, 0x2f, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// MasterClient is the client API for Master service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type MasterClient interface {
+	RegisterExecutor(ctx context.Context, in *RegisterExecutorRequest, opts ...grpc.CallOption) (*RegisterExecutorResponse, error)
+	// CordonExecutor sets the cordon state of an executor, the state is
+	// persisted and kept after server master failover.
+	CordonExecutor(ctx context.Context, in *CordonExecutorRequest, opts ...grpc.CallOption) (*CordonExecutorResponse, error)
+	// QueryExecutorCordons returns the executors that are cordoned or
+	// blacklisted.
+	QueryExecutorCordons(ctx context.Context, in *QueryExecutorCordonsRequest, opts ...grpc.CallOption) (*QueryExecutorCordonsResponse, error)
+	// ClusterHealth summarizes the health of the cluster, including the
+	// leader, the framework metastore, the executors and the jobs in error.
+	ClusterHealth(ctx context.Context, in *ClusterHealthRequest, opts ...grpc.CallOption) (*ClusterHealthResponse, error)
+	SubmitJob(ctx context.Context, in *SubmitJobRequest, opts ...grpc.CallOption) (*SubmitJobResponse, error)
+	QueryJob(ctx context.Context, in *QueryJobRequest, opts ...grpc.CallOption) (*QueryJobResponse, error)
+	// QueryJobs lists the jobs of a project, or of all projects, whose
+	// labels match the label selector, and whose status codes and types are
+	// the given ones.
+	QueryJobs(ctx context.Context, in *QueryJobsRequest, opts ...grpc.CallOption) (*QueryJobsResponse, error)
+	// ListWorkers lists the workers of a running job kept by its job
+	// master, one page at a time.
+	ListWorkers(ctx context.Context, in *ListWorkersRequest, opts ...grpc.CallOption) (*ListWorkersResponse, error)
+	PauseJob(ctx context.Context, in *PauseJobRequest, opts ...grpc.CallOption) (*PauseJobResponse, error)
+	CancelJob(ctx context.Context, in *CancelJobRequest, opts ...grpc.CallOption) (*CancelJobResponse, error)
+	// UpdateJobTimeouts adjusts the worker timeouts of a running job without
+	// restarting it.
+	UpdateJobTimeouts(ctx context.Context, in *UpdateJobTimeoutsRequest, opts ...grpc.CallOption) (*UpdateJobTimeoutsResponse, error)
+	// SetJobLogLevel overrides the log level of the master and workers of a
+	// running job, so that debug logs can be turned on for one job only.
+	SetJobLogLevel(ctx context.Context, in *SetJobLogLevelRequest, opts ...grpc.CallOption) (*SetJobLogLevelResponse, error)
+	// OperateJobTasks pauses, resumes or stops the tasks of a running job,
+	// the operation is routed to the job master, which applies it
+	// asynchronously. The stages of the tasks are reported in the status of
+	// the job master.
+	OperateJobTasks(ctx context.Context, in *OperateJobTasksRequest, opts ...grpc.CallOption) (*OperateJobTasksResponse, error)
+	// UpdateJobSource updates the config of an upstream source of a running
+	// job, the update is routed to the job master, which validates it and
+	// restarts only the workers of the source. The result is reported in the
+	// status of the job master.
+	UpdateJobSource(ctx context.Context, in *UpdateJobSourceRequest, opts ...grpc.CallOption) (*UpdateJobSourceResponse, error)
+	// CreateJobSchedule creates a schedule that submits a job periodically
+	// according to a cron expression.
+	CreateJobSchedule(ctx context.Context, in *CreateJobScheduleRequest, opts ...grpc.CallOption) (*CreateJobScheduleResponse, error)
+	UpdateJobSchedule(ctx context.Context, in *UpdateJobScheduleRequest, opts ...grpc.CallOption) (*UpdateJobScheduleResponse, error)
+	DeleteJobSchedule(ctx context.Context, in *DeleteJobScheduleRequest, opts ...grpc.CallOption) (*DeleteJobScheduleResponse, error)
+	QueryJobSchedules(ctx context.Context, in *QueryJobSchedulesRequest, opts ...grpc.CallOption) (*QueryJobSchedulesResponse, error)
+	// RegisterJobTemplate registers a job config template with placeholders,
+	// a template of the same ID is replaced.
+	RegisterJobTemplate(ctx context.Context, in *RegisterJobTemplateRequest, opts ...grpc.CallOption) (*RegisterJobTemplateResponse, error)
+	DeleteJobTemplate(ctx context.Context, in *DeleteJobTemplateRequest, opts ...grpc.CallOption) (*DeleteJobTemplateResponse, error)
+	QueryJobTemplates(ctx context.Context, in *QueryJobTemplatesRequest, opts ...grpc.CallOption) (*QueryJobTemplatesResponse, error)
+	// PutSecret stores a named secret of a project encrypted in the
+	// metastore, a secret of the same name is replaced.
+	PutSecret(ctx context.Context, in *PutSecretRequest, opts ...grpc.CallOption) (*PutSecretResponse, error)
+	DeleteSecret(ctx context.Context, in *DeleteSecretRequest, opts ...grpc.CallOption) (*DeleteSecretResponse, error)
+	// ListSecrets returns the names of the secrets of a project, the values
+	// are never returned.
+	ListSecrets(ctx context.Context, in *ListSecretsRequest, opts ...grpc.CallOption) (*ListSecretsResponse, error)
+	Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error)
+	ScheduleTask(ctx context.Context, in *ScheduleTaskRequest, opts ...grpc.CallOption) (*ScheduleTaskResponse, error)
+	// Schedule is a stream on which a job master submits the placement
+	// requests of its workers and receives the decisions asynchronously,
+	// so that creating a worker doesn't need a full RPC. A decision is
+	// revoked if its executor is gone before the job master tells the
+	// request is done.
+	Schedule(ctx context.Context, opts ...grpc.CallOption) (Master_ScheduleClient, error)
+	// ScaleUpJob is called from a job master to request capacity for more
+	// workers within the max workers declared by the job.
+	ScaleUpJob(ctx context.Context, in *ScaleUpJobRequest, opts ...grpc.CallOption) (*ScaleUpJobResponse, error)
+	// RegisterMetaStore is called from backend metastore and
+	// registers to server master metastore manager
+	RegisterMetaStore(ctx context.Context, in *RegisterMetaStoreRequest, opts ...grpc.CallOption) (*RegisterMetaStoreResponse, error)
+	// QueryMetaStore queries metastore manager and returns
+	// the information of a matching metastore
+	QueryMetaStore(ctx context.Context, in *QueryMetaStoreRequest, opts ...grpc.CallOption) (*QueryMetaStoreResponse, error)
+	// MigrateMetaStore drives the online migration of the framework metastore
+	// to another backend, see MigrateMetaStoreRequest.Op for the steps.
+	MigrateMetaStore(ctx context.Context, in *MigrateMetaStoreRequest, opts ...grpc.CallOption) (*MigrateMetaStoreResponse, error)
+	// ReportExecutorWorkload is called from executor to server master to report
+	// resource usage in executor.
+	ReportExecutorWorkload(ctx context.Context, in *ExecWorkloadRequest, opts ...grpc.CallOption) (*ExecWorkloadResponse, error)
+	// PersistResource is called from executor to indicate some workers on it wants
+	// to persist resource files.
+	PersistResource(ctx context.Context, in *PersistResourceRequest, opts ...grpc.CallOption) (*PersistResourceResponse, error)
+}
+
+type masterClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewMasterClient(cc *grpc.ClientConn) MasterClient {
+	return &masterClient{cc}
+}
+
+func (c *masterClient) RegisterExecutor(ctx context.Context, in *RegisterExecutorRequest, opts ...grpc.CallOption) (*RegisterExecutorResponse, error) {
+	out := new(RegisterExecutorResponse)
+	err := c.cc.Invoke(ctx, "/pb.Master/RegisterExecutor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) CordonExecutor(ctx context.Context, in *CordonExecutorRequest, opts ...grpc.CallOption) (*CordonExecutorResponse, error) {
+	out := new(CordonExecutorResponse)
+	err := c.cc.Invoke(ctx, "/pb.Master/CordonExecutor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) QueryExecutorCordons(ctx context.Context, in *QueryExecutorCordonsRequest, opts ...grpc.CallOption) (*QueryExecutorCordonsResponse, error) {
+	out := new(QueryExecutorCordonsResponse)
+	err := c.cc.Invoke(ctx, "/pb.Master/QueryExecutorCordons", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) ClusterHealth(ctx context.Context, in *ClusterHealthRequest, opts ...grpc.CallOption) (*ClusterHealthResponse, error) {
+	out := new(ClusterHealthResponse)
+	err := c.cc.Invoke(ctx, "/pb.Master/ClusterHealth", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) SubmitJob(ctx context.Context, in *SubmitJobRequest, opts ...grpc.CallOption) (*SubmitJobResponse, error) {
+	out := new(SubmitJobResponse)
+	err := c.cc.Invoke(ctx, "/pb.Master/SubmitJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) QueryJob(ctx context.Context, in *QueryJobRequest, opts ...grpc.CallOption) (*QueryJobResponse, error) {
+	out := new(QueryJobResponse)
+	err := c.cc.Invoke(ctx, "/pb.Master/QueryJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) QueryJobs(ctx context.Context, in *QueryJobsRequest, opts ...grpc.CallOption) (*QueryJobsResponse, error) {
+	out := new(QueryJobsResponse)
+	err := c.cc.Invoke(ctx, "/pb.Master/QueryJobs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) ListWorkers(ctx context.Context, in *ListWorkersRequest, opts ...grpc.CallOption) (*ListWorkersResponse, error) {
+	out := new(ListWorkersResponse)
+	err := c.cc.Invoke(ctx, "/pb.Master/ListWorkers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) PauseJob(ctx context.Context, in *PauseJobRequest, opts ...grpc.CallOption) (*PauseJobResponse, error) {
+	out := new(PauseJobResponse)
+	err := c.cc.Invoke(ctx, "/pb.Master/PauseJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) CancelJob(ctx context.Context, in *CancelJobRequest, opts ...grpc.CallOption) (*CancelJobResponse, error) {
+	out := new(CancelJobResponse)
+	err := c.cc.Invoke(ctx, "/pb.Master/CancelJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) UpdateJobTimeouts(ctx context.Context, in *UpdateJobTimeoutsRequest, opts ...grpc.CallOption) (*UpdateJobTimeoutsResponse, error) {
+	out := new(UpdateJobTimeoutsResponse)
+	err := c.cc.Invoke(ctx, "/pb.Master/UpdateJobTimeouts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) SetJobLogLevel(ctx context.Context, in *SetJobLogLevelRequest, opts ...grpc.CallOption) (*SetJobLogLevelResponse, error) {
+	out := new(SetJobLogLevelResponse)
+	err := c.cc.Invoke(ctx, "/pb.Master/SetJobLogLevel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) OperateJobTasks(ctx context.Context, in *OperateJobTasksRequest, opts ...grpc.CallOption) (*OperateJobTasksResponse, error) {
+	out := new(OperateJobTasksResponse)
+	err := c.cc.Invoke(ctx, "/pb.Master/OperateJobTasks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) UpdateJobSource(ctx context.Context, in *UpdateJobSourceRequest, opts ...grpc.CallOption) (*UpdateJobSourceResponse, error) {
+	out := new(UpdateJobSourceResponse)
+	err := c.cc.Invoke(ctx, "/pb.Master/UpdateJobSource", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) CreateJobSchedule(ctx context.Context, in *CreateJobScheduleRequest, opts ...grpc.CallOption) (*CreateJobScheduleResponse, error) {
+	out := new(CreateJobScheduleResponse)
+	err := c.cc.Invoke(ctx, "/pb.Master/CreateJobSchedule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) UpdateJobSchedule(ctx context.Context, in *UpdateJobScheduleRequest, opts ...grpc.CallOption) (*UpdateJobScheduleResponse, error) {
+	out := new(UpdateJobScheduleResponse)
+	err := c.cc.Invoke(ctx, "/pb.Master/UpdateJobSchedule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) DeleteJobSchedule(ctx context.Context, in *DeleteJobScheduleRequest, opts ...grpc.CallOption) (*DeleteJobScheduleResponse, error) {
+	out := new(DeleteJobScheduleResponse)
+	err := c.cc.Invoke(ctx, "/pb.Master/DeleteJobSchedule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) QueryJobSchedules(ctx context.Context, in *QueryJobSchedulesRequest, opts ...grpc.CallOption) (*QueryJobSchedulesResponse, error) {
+	out := new(QueryJobSchedulesResponse)
+	err := c.cc.Invoke(ctx, "/pb.Master/QueryJobSchedules", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) RegisterJobTemplate(ctx context.Context, in *RegisterJobTemplateRequest, opts ...grpc.CallOption) (*RegisterJobTemplateResponse, error) {
+	out := new(RegisterJobTemplateResponse)
+	err := c.cc.Invoke(ctx, "/pb.Master/RegisterJobTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) DeleteJobTemplate(ctx context.Context, in *DeleteJobTemplateRequest, opts ...grpc.CallOption) (*DeleteJobTemplateResponse, error) {
+	out := new(DeleteJobTemplateResponse)
+	err := c.cc.Invoke(ctx, "/pb.Master/DeleteJobTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) QueryJobTemplates(ctx context.Context, in *QueryJobTemplatesRequest, opts ...grpc.CallOption) (*QueryJobTemplatesResponse, error) {
+	out := new(QueryJobTemplatesResponse)
+	err := c.cc.Invoke(ctx, "/pb.Master/QueryJobTemplates", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) PutSecret(ctx context.Context, in *PutSecretRequest, opts ...grpc.CallOption) (*PutSecretResponse, error) {
+	out := new(PutSecretResponse)
+	err := c.cc.Invoke(ctx, "/pb.Master/PutSecret", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) DeleteSecret(ctx context.Context, in *DeleteSecretRequest, opts ...grpc.CallOption) (*DeleteSecretResponse, error) {
+	out := new(DeleteSecretResponse)
+	err := c.cc.Invoke(ctx, "/pb.Master/DeleteSecret", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) ListSecrets(ctx context.Context, in *ListSecretsRequest, opts ...grpc.CallOption) (*ListSecretsResponse, error) {
+	out := new(ListSecretsResponse)
+	err := c.cc.Invoke(ctx, "/pb.Master/ListSecrets", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error) {
+	out := new(HeartbeatResponse)
+	err := c.cc.Invoke(ctx, "/pb.Master/Heartbeat", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) ScheduleTask(ctx context.Context, in *ScheduleTaskRequest, opts ...grpc.CallOption) (*ScheduleTaskResponse, error) {
+	out := new(ScheduleTaskResponse)
+	err := c.cc.Invoke(ctx, "/pb.Master/ScheduleTask", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) Schedule(ctx context.Context, opts ...grpc.CallOption) (Master_ScheduleClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Master_serviceDesc.Streams[0], "/pb.Master/Schedule", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &masterScheduleClient{stream}
+	return x, nil
+}
+
+type Master_ScheduleClient interface {
+	Send(*ScheduleRequest) error
+	Recv() (*ScheduleResponse, error)
+	grpc.ClientStream
+}
+
+type masterScheduleClient struct {
+	grpc.ClientStream
+}
+
+func (x *masterScheduleClient) Send(m *ScheduleRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *masterScheduleClient) Recv() (*ScheduleResponse, error) {
+	m := new(ScheduleResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *masterClient) ScaleUpJob(ctx context.Context, in *ScaleUpJobRequest, opts ...grpc.CallOption) (*ScaleUpJobResponse, error) {
+	out := new(ScaleUpJobResponse)
+	err := c.cc.Invoke(ctx, "/pb.Master/ScaleUpJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) RegisterMetaStore(ctx context.Context, in *RegisterMetaStoreRequest, opts ...grpc.CallOption) (*RegisterMetaStoreResponse, error) {
+	out := new(RegisterMetaStoreResponse)
+	err := c.cc.Invoke(ctx, "/pb.Master/RegisterMetaStore", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) QueryMetaStore(ctx context.Context, in *QueryMetaStoreRequest, opts ...grpc.CallOption) (*QueryMetaStoreResponse, error) {
+	out := new(QueryMetaStoreResponse)
+	err := c.cc.Invoke(ctx, "/pb.Master/QueryMetaStore", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) MigrateMetaStore(ctx context.Context, in *MigrateMetaStoreRequest, opts ...grpc.CallOption) (*MigrateMetaStoreResponse, error) {
+	out := new(MigrateMetaStoreResponse)
+	err := c.cc.Invoke(ctx, "/pb.Master/MigrateMetaStore", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) ReportExecutorWorkload(ctx context.Context, in *ExecWorkloadRequest, opts ...grpc.CallOption) (*ExecWorkloadResponse, error) {
+	out := new(ExecWorkloadResponse)
+	err := c.cc.Invoke(ctx, "/pb.Master/ReportExecutorWorkload", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) PersistResource(ctx context.Context, in *PersistResourceRequest, opts ...grpc.CallOption) (*PersistResourceResponse, error) {
+	out := new(PersistResourceResponse)
+	err := c.cc.Invoke(ctx, "/pb.Master/PersistResource", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MasterServer is the server API for Master service.
+type MasterServer interface {
+	RegisterExecutor(context.Context, *RegisterExecutorRequest) (*RegisterExecutorResponse, error)
+	// CordonExecutor sets the cordon state of an executor, the state is
+	// persisted and kept after server master failover.
+	CordonExecutor(context.Context, *CordonExecutorRequest) (*CordonExecutorResponse, error)
+	// QueryExecutorCordons returns the executors that are cordoned or
+	// blacklisted.
+	QueryExecutorCordons(context.Context, *QueryExecutorCordonsRequest) (*QueryExecutorCordonsResponse, error)
+	// ClusterHealth summarizes the health of the cluster, including the
+	// leader, the framework metastore, the executors and the jobs in error.
+	ClusterHealth(context.Context, *ClusterHealthRequest) (*ClusterHealthResponse, error)
+	SubmitJob(context.Context, *SubmitJobRequest) (*SubmitJobResponse, error)
+	QueryJob(context.Context, *QueryJobRequest) (*QueryJobResponse, error)
+	// QueryJobs lists the jobs of a project, or of all projects, whose
+	// labels match the label selector, and whose status codes and types are
+	// the given ones.
+	QueryJobs(context.Context, *QueryJobsRequest) (*QueryJobsResponse, error)
+	// ListWorkers lists the workers of a running job kept by its job
+	// master, one page at a time.
+	ListWorkers(context.Context, *ListWorkersRequest) (*ListWorkersResponse, error)
+	PauseJob(context.Context, *PauseJobRequest) (*PauseJobResponse, error)
+	CancelJob(context.Context, *CancelJobRequest) (*CancelJobResponse, error)
+	// UpdateJobTimeouts adjusts the worker timeouts of a running job without
+	// restarting it.
+	UpdateJobTimeouts(context.Context, *UpdateJobTimeoutsRequest) (*UpdateJobTimeoutsResponse, error)
+	// SetJobLogLevel overrides the log level of the master and workers of a
+	// running job, so that debug logs can be turned on for one job only.
+	SetJobLogLevel(context.Context, *SetJobLogLevelRequest) (*SetJobLogLevelResponse, error)
+	// OperateJobTasks pauses, resumes or stops the tasks of a running job,
+	// the operation is routed to the job master, which applies it
+	// asynchronously. The stages of the tasks are reported in the status of
+	// the job master.
+	OperateJobTasks(context.Context, *OperateJobTasksRequest) (*OperateJobTasksResponse, error)
+	// UpdateJobSource updates the config of an upstream source of a running
+	// job, the update is routed to the job master, which validates it and
+	// restarts only the workers of the source. The result is reported in the
+	// status of the job master.
+	UpdateJobSource(context.Context, *UpdateJobSourceRequest) (*UpdateJobSourceResponse, error)
+	// CreateJobSchedule creates a schedule that submits a job periodically
+	// according to a cron expression.
+	CreateJobSchedule(context.Context, *CreateJobScheduleRequest) (*CreateJobScheduleResponse, error)
+	UpdateJobSchedule(context.Context, *UpdateJobScheduleRequest) (*UpdateJobScheduleResponse, error)
+	DeleteJobSchedule(context.Context, *DeleteJobScheduleRequest) (*DeleteJobScheduleResponse, error)
+	QueryJobSchedules(context.Context, *QueryJobSchedulesRequest) (*QueryJobSchedulesResponse, error)
+	// RegisterJobTemplate registers a job config template with placeholders,
+	// a template of the same ID is replaced.
+	RegisterJobTemplate(context.Context, *RegisterJobTemplateRequest) (*RegisterJobTemplateResponse, error)
+	DeleteJobTemplate(context.Context, *DeleteJobTemplateRequest) (*DeleteJobTemplateResponse, error)
+	QueryJobTemplates(context.Context, *QueryJobTemplatesRequest) (*QueryJobTemplatesResponse, error)
+	// PutSecret stores a named secret of a project encrypted in the
+	// metastore, a secret of the same name is replaced.
+	PutSecret(context.Context, *PutSecretRequest) (*PutSecretResponse, error)
+	DeleteSecret(context.Context, *DeleteSecretRequest) (*DeleteSecretResponse, error)
+	// ListSecrets returns the names of the secrets of a project, the values
+	// are never returned.
+	ListSecrets(context.Context, *ListSecretsRequest) (*ListSecretsResponse, error)
+	Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error)
+	ScheduleTask(context.Context, *ScheduleTaskRequest) (*ScheduleTaskResponse, error)
+	// Schedule is a stream on which a job master submits the placement
+	// requests of its workers and receives the decisions asynchronously,
+	// so that creating a worker doesn't need a full RPC. A decision is
+	// revoked if its executor is gone before the job master tells the
+	// request is done.
+	Schedule(Master_ScheduleServer) error
+	// ScaleUpJob is called from a job master to request capacity for more
+	// workers within the max workers declared by the job.
+	ScaleUpJob(context.Context, *ScaleUpJobRequest) (*ScaleUpJobResponse, error)
+	// RegisterMetaStore is called from backend metastore and
+	// registers to server master metastore manager
+	RegisterMetaStore(context.Context, *RegisterMetaStoreRequest) (*RegisterMetaStoreResponse, error)
+	// QueryMetaStore queries metastore manager and returns
+	// the information of a matching metastore
+	QueryMetaStore(context.Context, *QueryMetaStoreRequest) (*QueryMetaStoreResponse, error)
+	// MigrateMetaStore drives the online migration of the framework metastore
+	// to another backend, see MigrateMetaStoreRequest.Op for the steps.
+	MigrateMetaStore(context.Context, *MigrateMetaStoreRequest) (*MigrateMetaStoreResponse, error)
+	// ReportExecutorWorkload is called from executor to server master to report
+	// resource usage in executor.
+	ReportExecutorWorkload(context.Context, *ExecWorkloadRequest) (*ExecWorkloadResponse, error)
+	// PersistResource is called from executor to indicate some workers on it wants
+	// to persist resource files.
+	PersistResource(context.Context, *PersistResourceRequest) (*PersistResourceResponse, error)
+}
+
+// UnimplementedMasterServer can be embedded to have forward compatible implementations.
+type UnimplementedMasterServer struct {
+}
+
+func (*UnimplementedMasterServer) RegisterExecutor(ctx context.Context, req *RegisterExecutorRequest) (*RegisterExecutorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterExecutor not implemented")
+}
+func (*UnimplementedMasterServer) CordonExecutor(ctx context.Context, req *CordonExecutorRequest) (*CordonExecutorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CordonExecutor not implemented")
+}
+func (*UnimplementedMasterServer) QueryExecutorCordons(ctx context.Context, req *QueryExecutorCordonsRequest) (*QueryExecutorCordonsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryExecutorCordons not implemented")
+}
+func (*UnimplementedMasterServer) ClusterHealth(ctx context.Context, req *ClusterHealthRequest) (*ClusterHealthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClusterHealth not implemented")
+}
+func (*UnimplementedMasterServer) SubmitJob(ctx context.Context, req *SubmitJobRequest) (*SubmitJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitJob not implemented")
+}
+func (*UnimplementedMasterServer) QueryJob(ctx context.Context, req *QueryJobRequest) (*QueryJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryJob not implemented")
+}
+func (*UnimplementedMasterServer) QueryJobs(ctx context.Context, req *QueryJobsRequest) (*QueryJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryJobs not implemented")
+}
+func (*UnimplementedMasterServer) ListWorkers(ctx context.Context, req *ListWorkersRequest) (*ListWorkersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWorkers not implemented")
+}
+func (*UnimplementedMasterServer) PauseJob(ctx context.Context, req *PauseJobRequest) (*PauseJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseJob not implemented")
+}
+func (*UnimplementedMasterServer) CancelJob(ctx context.Context, req *CancelJobRequest) (*CancelJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelJob not implemented")
+}
+func (*UnimplementedMasterServer) UpdateJobTimeouts(ctx context.Context, req *UpdateJobTimeoutsRequest) (*UpdateJobTimeoutsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateJobTimeouts not implemented")
+}
+func (*UnimplementedMasterServer) SetJobLogLevel(ctx context.Context, req *SetJobLogLevelRequest) (*SetJobLogLevelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetJobLogLevel not implemented")
+}
+func (*UnimplementedMasterServer) OperateJobTasks(ctx context.Context, req *OperateJobTasksRequest) (*OperateJobTasksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method OperateJobTasks not implemented")
+}
+func (*UnimplementedMasterServer) UpdateJobSource(ctx context.Context, req *UpdateJobSourceRequest) (*UpdateJobSourceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateJobSource not implemented")
+}
+func (*UnimplementedMasterServer) CreateJobSchedule(ctx context.Context, req *CreateJobScheduleRequest) (*CreateJobScheduleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateJobSchedule not implemented")
+}
+func (*UnimplementedMasterServer) UpdateJobSchedule(ctx context.Context, req *UpdateJobScheduleRequest) (*UpdateJobScheduleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateJobSchedule not implemented")
+}
+func (*UnimplementedMasterServer) DeleteJobSchedule(ctx context.Context, req *DeleteJobScheduleRequest) (*DeleteJobScheduleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteJobSchedule not implemented")
+}
+func (*UnimplementedMasterServer) QueryJobSchedules(ctx context.Context, req *QueryJobSchedulesRequest) (*QueryJobSchedulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryJobSchedules not implemented")
+}
+func (*UnimplementedMasterServer) RegisterJobTemplate(ctx context.Context, req *RegisterJobTemplateRequest) (*RegisterJobTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterJobTemplate not implemented")
+}
+func (*UnimplementedMasterServer) DeleteJobTemplate(ctx context.Context, req *DeleteJobTemplateRequest) (*DeleteJobTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteJobTemplate not implemented")
+}
+func (*UnimplementedMasterServer) QueryJobTemplates(ctx context.Context, req *QueryJobTemplatesRequest) (*QueryJobTemplatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryJobTemplates not implemented")
+}
+func (*UnimplementedMasterServer) PutSecret(ctx context.Context, req *PutSecretRequest) (*PutSecretResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PutSecret not implemented")
+}
+func (*UnimplementedMasterServer) DeleteSecret(ctx context.Context, req *DeleteSecretRequest) (*DeleteSecretResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSecret not implemented")
+}
+func (*UnimplementedMasterServer) ListSecrets(ctx context.Context, req *ListSecretsRequest) (*ListSecretsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSecrets not implemented")
+}
+func (*UnimplementedMasterServer) Heartbeat(ctx context.Context, req *HeartbeatRequest) (*HeartbeatResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Heartbeat not implemented")
+}
+func (*UnimplementedMasterServer) ScheduleTask(ctx context.Context, req *ScheduleTaskRequest) (*ScheduleTaskResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ScheduleTask not implemented")
+}
+func (*UnimplementedMasterServer) Schedule(srv Master_ScheduleServer) error {
+	return status.Errorf(codes.Unimplemented, "method Schedule not implemented")
+}
+func (*UnimplementedMasterServer) ScaleUpJob(ctx context.Context, req *ScaleUpJobRequest) (*ScaleUpJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ScaleUpJob not implemented")
+}
+func (*UnimplementedMasterServer) RegisterMetaStore(ctx context.Context, req *RegisterMetaStoreRequest) (*RegisterMetaStoreResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterMetaStore not implemented")
+}
+func (*UnimplementedMasterServer) QueryMetaStore(ctx context.Context, req *QueryMetaStoreRequest) (*QueryMetaStoreResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryMetaStore not implemented")
+}
+func (*UnimplementedMasterServer) MigrateMetaStore(ctx context.Context, req *MigrateMetaStoreRequest) (*MigrateMetaStoreResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MigrateMetaStore not implemented")
+}
+func (*UnimplementedMasterServer) ReportExecutorWorkload(ctx context.Context, req *ExecWorkloadRequest) (*ExecWorkloadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportExecutorWorkload not implemented")
+}
+func (*UnimplementedMasterServer) PersistResource(ctx context.Context, req *PersistResourceRequest) (*PersistResourceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PersistResource not implemented")
+}
+
+func RegisterMasterServer(s *grpc.Server, srv MasterServer) {
+	s.RegisterService(&_Master_serviceDesc, srv)
+}
+
+func _Master_RegisterExecutor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterExecutorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).RegisterExecutor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Master/RegisterExecutor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).RegisterExecutor(ctx, req.(*RegisterExecutorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_CordonExecutor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CordonExecutorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).CordonExecutor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Master/CordonExecutor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).CordonExecutor(ctx, req.(*CordonExecutorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_QueryExecutorCordons_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryExecutorCordonsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).QueryExecutorCordons(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Master/QueryExecutorCordons",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).QueryExecutorCordons(ctx, req.(*QueryExecutorCordonsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_ClusterHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClusterHealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).ClusterHealth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Master/ClusterHealth",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).ClusterHealth(ctx, req.(*ClusterHealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_SubmitJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).SubmitJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Master/SubmitJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).SubmitJob(ctx, req.(*SubmitJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_QueryJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).QueryJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Master/QueryJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).QueryJob(ctx, req.(*QueryJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_QueryJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).QueryJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Master/QueryJobs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).QueryJobs(ctx, req.(*QueryJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_ListWorkers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWorkersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).ListWorkers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Master/ListWorkers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).ListWorkers(ctx, req.(*ListWorkersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_PauseJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).PauseJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Master/PauseJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).PauseJob(ctx, req.(*PauseJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_CancelJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).CancelJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Master/CancelJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).CancelJob(ctx, req.(*CancelJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_UpdateJobTimeouts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateJobTimeoutsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).UpdateJobTimeouts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Master/UpdateJobTimeouts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).UpdateJobTimeouts(ctx, req.(*UpdateJobTimeoutsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_SetJobLogLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetJobLogLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).SetJobLogLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Master/SetJobLogLevel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).SetJobLogLevel(ctx, req.(*SetJobLogLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_OperateJobTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OperateJobTasksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).OperateJobTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Master/OperateJobTasks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).OperateJobTasks(ctx, req.(*OperateJobTasksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_UpdateJobSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateJobSourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).UpdateJobSource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Master/UpdateJobSource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).UpdateJobSource(ctx, req.(*UpdateJobSourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_CreateJobSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateJobScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).CreateJobSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Master/CreateJobSchedule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).CreateJobSchedule(ctx, req.(*CreateJobScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_UpdateJobSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateJobScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).UpdateJobSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Master/UpdateJobSchedule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).UpdateJobSchedule(ctx, req.(*UpdateJobScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_DeleteJobSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteJobScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).DeleteJobSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Master/DeleteJobSchedule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).DeleteJobSchedule(ctx, req.(*DeleteJobScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_QueryJobSchedules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryJobSchedulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).QueryJobSchedules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Master/QueryJobSchedules",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).QueryJobSchedules(ctx, req.(*QueryJobSchedulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_RegisterJobTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterJobTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).RegisterJobTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Master/RegisterJobTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).RegisterJobTemplate(ctx, req.(*RegisterJobTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_DeleteJobTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteJobTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).DeleteJobTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Master/DeleteJobTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).DeleteJobTemplate(ctx, req.(*DeleteJobTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_QueryJobTemplates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryJobTemplatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).QueryJobTemplates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Master/QueryJobTemplates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).QueryJobTemplates(ctx, req.(*QueryJobTemplatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_PutSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PutSecretRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).PutSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Master/PutSecret",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).PutSecret(ctx, req.(*PutSecretRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_DeleteSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSecretRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).DeleteSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Master/DeleteSecret",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).DeleteSecret(ctx, req.(*DeleteSecretRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_ListSecrets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSecretsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).ListSecrets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Master/ListSecrets",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).ListSecrets(ctx, req.(*ListSecretsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_Heartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HeartbeatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).Heartbeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Master/Heartbeat",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).Heartbeat(ctx, req.(*HeartbeatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_ScheduleTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduleTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).ScheduleTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Master/ScheduleTask",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).ScheduleTask(ctx, req.(*ScheduleTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_Schedule_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(MasterServer).Schedule(&masterScheduleServer{stream})
+}
+
+type Master_ScheduleServer interface {
+	Send(*ScheduleResponse) error
+	Recv() (*ScheduleRequest, error)
+	grpc.ServerStream
+}
+
+type masterScheduleServer struct {
+	grpc.ServerStream
+}
+
+func (x *masterScheduleServer) Send(m *ScheduleResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *masterScheduleServer) Recv() (*ScheduleRequest, error) {
+	m := new(ScheduleRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Master_ScaleUpJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScaleUpJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).ScaleUpJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Master/ScaleUpJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).ScaleUpJob(ctx, req.(*ScaleUpJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_RegisterMetaStore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterMetaStoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).RegisterMetaStore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Master/RegisterMetaStore",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).RegisterMetaStore(ctx, req.(*RegisterMetaStoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_QueryMetaStore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryMetaStoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).QueryMetaStore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Master/QueryMetaStore",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).QueryMetaStore(ctx, req.(*QueryMetaStoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_MigrateMetaStore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MigrateMetaStoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).MigrateMetaStore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Master/MigrateMetaStore",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).MigrateMetaStore(ctx, req.(*MigrateMetaStoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_ReportExecutorWorkload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecWorkloadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).ReportExecutorWorkload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Master/ReportExecutorWorkload",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).ReportExecutorWorkload(ctx, req.(*ExecWorkloadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_PersistResource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PersistResourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).PersistResource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Master/PersistResource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).PersistResource(ctx, req.(*PersistResourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Master_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.Master",
+	HandlerType: (*MasterServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RegisterExecutor",
+			Handler:    _Master_RegisterExecutor_Handler,
+		},
+		{
+			MethodName: "CordonExecutor",
+			Handler:    _Master_CordonExecutor_Handler,
+		},
+		{
+			MethodName: "QueryExecutorCordons",
+			Handler:    _Master_QueryExecutorCordons_Handler,
+		},
+		{
+			MethodName: "ClusterHealth",
+			Handler:    _Master_ClusterHealth_Handler,
+		},
+		{
+			MethodName: "SubmitJob",
+			Handler:    _Master_SubmitJob_Handler,
+		},
+		{
+			MethodName: "QueryJob",
+			Handler:    _Master_QueryJob_Handler,
+		},
+		{
+			MethodName: "QueryJobs",
+			Handler:    _Master_QueryJobs_Handler,
+		},
+		{
+			MethodName: "ListWorkers",
+			Handler:    _Master_ListWorkers_Handler,
+		},
+		{
+			MethodName: "PauseJob",
+			Handler:    _Master_PauseJob_Handler,
+		},
+		{
+			MethodName: "CancelJob",
+			Handler:    _Master_CancelJob_Handler,
+		},
+		{
+			MethodName: "UpdateJobTimeouts",
+			Handler:    _Master_UpdateJobTimeouts_Handler,
+		},
+		{
+			MethodName: "SetJobLogLevel",
+			Handler:    _Master_SetJobLogLevel_Handler,
+		},
+		{
+			MethodName: "OperateJobTasks",
+			Handler:    _Master_OperateJobTasks_Handler,
+		},
+		{
+			MethodName: "UpdateJobSource",
+			Handler:    _Master_UpdateJobSource_Handler,
+		},
+		{
+			MethodName: "CreateJobSchedule",
+			Handler:    _Master_CreateJobSchedule_Handler,
+		},
+		{
+			MethodName: "UpdateJobSchedule",
+			Handler:    _Master_UpdateJobSchedule_Handler,
+		},
+		{
+			MethodName: "DeleteJobSchedule",
+			Handler:    _Master_DeleteJobSchedule_Handler,
+		},
+		{
+			MethodName: "QueryJobSchedules",
+			Handler:    _Master_QueryJobSchedules_Handler,
+		},
+		{
+			MethodName: "RegisterJobTemplate",
+			Handler:    _Master_RegisterJobTemplate_Handler,
+		},
+		{
+			MethodName: "DeleteJobTemplate",
+			Handler:    _Master_DeleteJobTemplate_Handler,
+		},
+		{
+			MethodName: "QueryJobTemplates",
+			Handler:    _Master_QueryJobTemplates_Handler,
+		},
+		{
+			MethodName: "PutSecret",
+			Handler:    _Master_PutSecret_Handler,
+		},
+		{
+			MethodName: "DeleteSecret",
+			Handler:    _Master_DeleteSecret_Handler,
+		},
+		{
+			MethodName: "ListSecrets",
+			Handler:    _Master_ListSecrets_Handler,
+		},
+		{
+			MethodName: "Heartbeat",
+			Handler:    _Master_Heartbeat_Handler,
+		},
+		{
+			MethodName: "ScheduleTask",
+			Handler:    _Master_ScheduleTask_Handler,
+		},
+		{
+			MethodName: "ScaleUpJob",
+			Handler:    _Master_ScaleUpJob_Handler,
+		},
+		{
+			MethodName: "RegisterMetaStore",
+			Handler:    _Master_RegisterMetaStore_Handler,
+		},
+		{
+			MethodName: "QueryMetaStore",
+			Handler:    _Master_QueryMetaStore_Handler,
+		},
+		{
+			MethodName: "MigrateMetaStore",
+			Handler:    _Master_MigrateMetaStore_Handler,
+		},
+		{
+			MethodName: "ReportExecutorWorkload",
+			Handler:    _Master_ReportExecutorWorkload_Handler,
+		},
+		{
+			MethodName: "PersistResource",
+			Handler:    _Master_PersistResource_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Schedule",
+			Handler:       _Master_Schedule_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "master.proto",
+}
+
+func (m *HeartbeatRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *HeartbeatRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *HeartbeatRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.ResourceUsages) > 0 {
+		for k := range m.ResourceUsages {
+			v := m.ResourceUsages[k]
+			baseI := i
+			i = encodeVarintMaster(dAtA, i, uint64(v))
+			i--
+			dAtA[i] = 0x10
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintMaster(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintMaster(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x42
+		}
+	}
+	if m.ProtocolVersion != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.ProtocolVersion))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.IdleEvictable {
+		i--
+		if m.IdleEvictable {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.Ttl != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.Ttl))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.Timestamp != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.Timestamp))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Status != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.Status))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.ResourceUsage != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.ResourceUsage))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.ExecutorId) > 0 {
+		i -= len(m.ExecutorId)
+		copy(dAtA[i:], m.ExecutorId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.ExecutorId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *HeartbeatResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *HeartbeatResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *HeartbeatResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.ClusterProtocolVersion != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.ClusterProtocolVersion))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.Addrs) > 0 {
+		for iNdEx := len(m.Addrs) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Addrs[iNdEx])
+			copy(dAtA[i:], m.Addrs[iNdEx])
+			i = encodeVarintMaster(dAtA, i, uint64(len(m.Addrs[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.Leader) > 0 {
+		i -= len(m.Leader)
+		copy(dAtA[i:], m.Leader)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.Leader)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Err != nil {
+		{
+			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SubmitJobRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SubmitJobRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SubmitJobRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Labels) > 0 {
+		for k := range m.Labels {
+			v := m.Labels[k]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintMaster(dAtA, i, uint64(len(v)))
+			i--
+			dAtA[i] = 0x12
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintMaster(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintMaster(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x42
+		}
+	}
+	if m.ResourceSpec != nil {
+		{
+			size, err := m.ResourceSpec.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x3a
+	}
+	if m.MaxCreateWorkerConcurrency != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.MaxCreateWorkerConcurrency))
+		i--
+		dAtA[i] = 0x30
+	}
+	if len(m.TemplateParams) > 0 {
+		for k := range m.TemplateParams {
+			v := m.TemplateParams[k]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintMaster(dAtA, i, uint64(len(v)))
+			i--
+			dAtA[i] = 0x12
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintMaster(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintMaster(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	if len(m.TemplateId) > 0 {
+		i -= len(m.TemplateId)
+		copy(dAtA[i:], m.TemplateId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.TemplateId)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.User) > 0 {
+		i -= len(m.User)
+		copy(dAtA[i:], m.User)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.User)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Config) > 0 {
+		i -= len(m.Config)
+		copy(dAtA[i:], m.Config)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.Config)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Tp != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.Tp))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *JobResourceSpec) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *JobResourceSpec) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *JobResourceSpec) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.WorkerResources) > 0 {
+		for k := range m.WorkerResources {
+			v := m.WorkerResources[k]
+			baseI := i
+			i = encodeVarintMaster(dAtA, i, uint64(v))
+			i--
+			dAtA[i] = 0x10
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintMaster(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintMaster(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if m.MaxWorkers != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.MaxWorkers))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.MinWorkers != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.MinWorkers))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryJobRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryJobRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryJobRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.JobId) > 0 {
+		i -= len(m.JobId)
+		copy(dAtA[i:], m.JobId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.JobId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *WorkerInfo) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *WorkerInfo) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *WorkerInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.CreatingWorkers != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.CreatingWorkers))
+		i--
+		dAtA[i] = 0x40
+	}
+	if m.Workload != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.Workload))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.LastHbTime != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.LastHbTime))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.IsTombstone {
+		i--
+		if m.IsTombstone {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.Config) > 0 {
+		i -= len(m.Config)
+		copy(dAtA[i:], m.Config)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.Config)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Status) > 0 {
+		i -= len(m.Status)
+		copy(dAtA[i:], m.Status)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.Status)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.ExecutorId) > 0 {
+		i -= len(m.ExecutorId)
+		copy(dAtA[i:], m.ExecutorId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.ExecutorId)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryJobResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryJobResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryJobResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Labels) > 0 {
+		for k := range m.Labels {
+			v := m.Labels[k]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintMaster(dAtA, i, uint64(len(v)))
+			i--
+			dAtA[i] = 0x12
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintMaster(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintMaster(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x3a
+		}
+	}
+	if m.MaxCreateWorkerConcurrency != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.MaxCreateWorkerConcurrency))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.Err != nil {
+		{
+			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.JobMasterInfo != nil {
+		{
+			size, err := m.JobMasterInfo.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.Status != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.Status))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Config) > 0 {
+		i -= len(m.Config)
+		copy(dAtA[i:], m.Config)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.Config)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Tp != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.Tp))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryJobsRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryJobsRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryJobsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Types) > 0 {
+		dAtA6 := make([]byte, len(m.Types)*10)
+		var j5 int
+		for _, num1 := range m.Types {
+			num := uint64(num1)
+			for num >= 1<<7 {
+				dAtA6[j5] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j5++
+			}
+			dAtA6[j5] = uint8(num)
+			j5++
+		}
+		i -= j5
+		copy(dAtA[i:], dAtA6[:j5])
+		i = encodeVarintMaster(dAtA, i, uint64(j5))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.StatusCodes) > 0 {
+		dAtA8 := make([]byte, len(m.StatusCodes)*10)
+		var j7 int
+		for _, num1 := range m.StatusCodes {
+			num := uint64(num1)
+			for num >= 1<<7 {
+				dAtA8[j7] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j7++
+			}
+			dAtA8[j7] = uint8(num)
+			j7++
+		}
+		i -= j7
+		copy(dAtA[i:], dAtA8[:j7])
+		i = encodeVarintMaster(dAtA, i, uint64(j7))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.LabelSelector) > 0 {
+		i -= len(m.LabelSelector)
+		copy(dAtA[i:], m.LabelSelector)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.LabelSelector)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.ProjectId) > 0 {
+		i -= len(m.ProjectId)
+		copy(dAtA[i:], m.ProjectId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.ProjectId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *JobInfo) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *JobInfo) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *JobInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Labels) > 0 {
+		for k := range m.Labels {
+			v := m.Labels[k]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintMaster(dAtA, i, uint64(len(v)))
+			i--
+			dAtA[i] = 0x12
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintMaster(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintMaster(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	if m.Status != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.Status))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Tp != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.Tp))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.ProjectId) > 0 {
+		i -= len(m.ProjectId)
+		copy(dAtA[i:], m.ProjectId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.ProjectId)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.JobId) > 0 {
+		i -= len(m.JobId)
+		copy(dAtA[i:], m.JobId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.JobId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryJobsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryJobsResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryJobsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Jobs) > 0 {
+		for iNdEx := len(m.Jobs) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Jobs[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintMaster(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Err != nil {
+		{
+			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ListWorkersRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ListWorkersRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ListWorkersRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.PageToken) > 0 {
+		i -= len(m.PageToken)
+		copy(dAtA[i:], m.PageToken)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.PageToken)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.PageSize != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.PageSize))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.ExecutorId) > 0 {
+		i -= len(m.ExecutorId)
+		copy(dAtA[i:], m.ExecutorId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.ExecutorId)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.StatusCodes) > 0 {
+		dAtA11 := make([]byte, len(m.StatusCodes)*10)
+		var j10 int
+		for _, num1 := range m.StatusCodes {
+			num := uint64(num1)
+			for num >= 1<<7 {
+				dAtA11[j10] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j10++
+			}
+			dAtA11[j10] = uint8(num)
+			j10++
+		}
+		i -= j10
+		copy(dAtA[i:], dAtA11[:j10])
+		i = encodeVarintMaster(dAtA, i, uint64(j10))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.JobId) > 0 {
+		i -= len(m.JobId)
+		copy(dAtA[i:], m.JobId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.JobId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *WorkerSummary) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *WorkerSummary) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *WorkerSummary) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.HeartbeatTime != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.HeartbeatTime))
+		i--
+		dAtA[i] = 0x30
+	}
+	if len(m.ErrorMessage) > 0 {
+		i -= len(m.ErrorMessage)
+		copy(dAtA[i:], m.ErrorMessage)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.ErrorMessage)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.StatusCode != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.StatusCode))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.State) > 0 {
+		i -= len(m.State)
+		copy(dAtA[i:], m.State)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.State)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.ExecutorId) > 0 {
+		i -= len(m.ExecutorId)
+		copy(dAtA[i:], m.ExecutorId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.ExecutorId)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ListWorkersResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ListWorkersResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ListWorkersResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.NextPageToken) > 0 {
+		i -= len(m.NextPageToken)
+		copy(dAtA[i:], m.NextPageToken)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.NextPageToken)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Workers) > 0 {
+		for iNdEx := len(m.Workers) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Workers[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintMaster(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Err != nil {
+		{
+			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CancelJobRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CancelJobRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CancelJobRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.JobIdStr) > 0 {
+		i -= len(m.JobIdStr)
+		copy(dAtA[i:], m.JobIdStr)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.JobIdStr)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.JobId != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.JobId))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PauseJobRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PauseJobRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PauseJobRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.JobIdStr) > 0 {
+		i -= len(m.JobIdStr)
+		copy(dAtA[i:], m.JobIdStr)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.JobIdStr)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.JobId != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.JobId))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SubmitJobResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SubmitJobResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SubmitJobResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.JobIdStr) > 0 {
+		i -= len(m.JobIdStr)
+		copy(dAtA[i:], m.JobIdStr)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.JobIdStr)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.JobId != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.JobId))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Err != nil {
+		{
+			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PauseJobResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PauseJobResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PauseJobResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Err != nil {
+		{
+			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CancelJobResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CancelJobResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CancelJobResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Err != nil {
+		{
+			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *UpdateJobTimeoutsRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *UpdateJobTimeoutsRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *UpdateJobTimeoutsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.WorkerHeartbeatIntervalMs != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.WorkerHeartbeatIntervalMs))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.WorkerTimeoutGracefulMs != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.WorkerTimeoutGracefulMs))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.WorkerTimeoutMs != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.WorkerTimeoutMs))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.JobId) > 0 {
+		i -= len(m.JobId)
+		copy(dAtA[i:], m.JobId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.JobId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *UpdateJobTimeoutsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *UpdateJobTimeoutsResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *UpdateJobTimeoutsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Err != nil {
+		{
+			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SetJobLogLevelRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SetJobLogLevelRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SetJobLogLevelRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Level) > 0 {
+		i -= len(m.Level)
+		copy(dAtA[i:], m.Level)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.Level)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.JobId) > 0 {
+		i -= len(m.JobId)
+		copy(dAtA[i:], m.JobId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.JobId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SetJobLogLevelResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SetJobLogLevelResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SetJobLogLevelResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Err != nil {
+		{
+			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *OperateJobTasksRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *OperateJobTasksRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *OperateJobTasksRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Tasks) > 0 {
+		for iNdEx := len(m.Tasks) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Tasks[iNdEx])
+			copy(dAtA[i:], m.Tasks[iNdEx])
+			i = encodeVarintMaster(dAtA, i, uint64(len(m.Tasks[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if m.Op != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.Op))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.JobId) > 0 {
+		i -= len(m.JobId)
+		copy(dAtA[i:], m.JobId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.JobId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *OperateJobTasksResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *OperateJobTasksResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *OperateJobTasksResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Err != nil {
+		{
+			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *UpdateJobSourceRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *UpdateJobSourceRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *UpdateJobSourceRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Config) > 0 {
+		i -= len(m.Config)
+		copy(dAtA[i:], m.Config)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.Config)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.SourceId) > 0 {
+		i -= len(m.SourceId)
+		copy(dAtA[i:], m.SourceId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.SourceId)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.JobId) > 0 {
+		i -= len(m.JobId)
+		copy(dAtA[i:], m.JobId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.JobId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *UpdateJobSourceResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *UpdateJobSourceResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *UpdateJobSourceResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Err != nil {
+		{
+			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *JobSchedule) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *JobSchedule) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *JobSchedule) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.NextFireTimeMs != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.NextFireTimeMs))
+		i--
+		dAtA[i] = 0x48
+	}
+	if m.LastFireTimeMs != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.LastFireTimeMs))
+		i--
+		dAtA[i] = 0x40
+	}
+	if m.Paused {
+		i--
+		if m.Paused {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.CatchUp != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.CatchUp))
+		i--
+		dAtA[i] = 0x30
+	}
+	if len(m.Cron) > 0 {
+		i -= len(m.Cron)
+		copy(dAtA[i:], m.Cron)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.Cron)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.User) > 0 {
+		i -= len(m.User)
+		copy(dAtA[i:], m.User)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.User)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Config) > 0 {
+		i -= len(m.Config)
+		copy(dAtA[i:], m.Config)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.Config)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Tp != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.Tp))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.ScheduleId) > 0 {
+		i -= len(m.ScheduleId)
+		copy(dAtA[i:], m.ScheduleId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.ScheduleId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CreateJobScheduleRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CreateJobScheduleRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CreateJobScheduleRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Schedule != nil {
+		{
+			size, err := m.Schedule.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CreateJobScheduleResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CreateJobScheduleResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CreateJobScheduleResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.ScheduleId) > 0 {
+		i -= len(m.ScheduleId)
+		copy(dAtA[i:], m.ScheduleId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.ScheduleId)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Err != nil {
+		{
+			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *UpdateJobScheduleRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *UpdateJobScheduleRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *UpdateJobScheduleRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Schedule != nil {
+		{
+			size, err := m.Schedule.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *UpdateJobScheduleResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *UpdateJobScheduleResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *UpdateJobScheduleResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Err != nil {
+		{
+			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *DeleteJobScheduleRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DeleteJobScheduleRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DeleteJobScheduleRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.ScheduleId) > 0 {
+		i -= len(m.ScheduleId)
+		copy(dAtA[i:], m.ScheduleId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.ScheduleId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *DeleteJobScheduleResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DeleteJobScheduleResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DeleteJobScheduleResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Err != nil {
+		{
+			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryJobSchedulesRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryJobSchedulesRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryJobSchedulesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.ScheduleId) > 0 {
+		i -= len(m.ScheduleId)
+		copy(dAtA[i:], m.ScheduleId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.ScheduleId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryJobSchedulesResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryJobSchedulesResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryJobSchedulesResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Schedules) > 0 {
+		for iNdEx := len(m.Schedules) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Schedules[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintMaster(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Err != nil {
+		{
+			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *JobTemplate) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *JobTemplate) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *JobTemplate) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Params) > 0 {
+		for iNdEx := len(m.Params) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Params[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintMaster(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if len(m.Template) > 0 {
+		i -= len(m.Template)
+		copy(dAtA[i:], m.Template)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.Template)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Tp != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.Tp))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.TemplateId) > 0 {
+		i -= len(m.TemplateId)
+		copy(dAtA[i:], m.TemplateId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.TemplateId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *JobTemplateParam) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *JobTemplateParam) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *JobTemplateParam) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Required {
+		i--
+		if m.Required {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.DefaultValue) > 0 {
+		i -= len(m.DefaultValue)
+		copy(dAtA[i:], m.DefaultValue)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.DefaultValue)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *RegisterJobTemplateRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RegisterJobTemplateRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RegisterJobTemplateRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Template != nil {
+		{
+			size, err := m.Template.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *RegisterJobTemplateResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RegisterJobTemplateResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RegisterJobTemplateResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Err != nil {
+		{
+			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *DeleteJobTemplateRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DeleteJobTemplateRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DeleteJobTemplateRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.TemplateId) > 0 {
+		i -= len(m.TemplateId)
+		copy(dAtA[i:], m.TemplateId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.TemplateId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *DeleteJobTemplateResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DeleteJobTemplateResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DeleteJobTemplateResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Err != nil {
+		{
+			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryJobTemplatesRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryJobTemplatesRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryJobTemplatesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.TemplateId) > 0 {
+		i -= len(m.TemplateId)
+		copy(dAtA[i:], m.TemplateId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.TemplateId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryJobTemplatesResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryJobTemplatesResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryJobTemplatesResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Templates) > 0 {
+		for iNdEx := len(m.Templates) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Templates[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintMaster(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Err != nil {
+		{
+			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *RegisterExecutorRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RegisterExecutorRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RegisterExecutorRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Labels) > 0 {
+		for k := range m.Labels {
+			v := m.Labels[k]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintMaster(dAtA, i, uint64(len(v)))
+			i--
+			dAtA[i] = 0x12
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintMaster(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintMaster(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x3a
+		}
+	}
+	if len(m.Resources) > 0 {
+		for k := range m.Resources {
+			v := m.Resources[k]
+			baseI := i
+			i = encodeVarintMaster(dAtA, i, uint64(v))
+			i--
+			dAtA[i] = 0x10
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintMaster(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintMaster(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x32
+		}
+	}
+	if m.ProtocolVersion != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.ProtocolVersion))
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.ExecutorId) > 0 {
+		i -= len(m.ExecutorId)
+		copy(dAtA[i:], m.ExecutorId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.ExecutorId)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.Capability != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.Capability))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Version) > 0 {
+		i -= len(m.Version)
+		copy(dAtA[i:], m.Version)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.Version)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Address) > 0 {
+		i -= len(m.Address)
+		copy(dAtA[i:], m.Address)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.Address)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *RegisterExecutorResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RegisterExecutorResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RegisterExecutorResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.ClusterProtocolVersion != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.ClusterProtocolVersion))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.ExecutorId) > 0 {
+		i -= len(m.ExecutorId)
+		copy(dAtA[i:], m.ExecutorId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.ExecutorId)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Err != nil {
+		{
+			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CordonExecutorRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CordonExecutorRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CordonExecutorRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.State != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.State))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.ExecutorId) > 0 {
+		i -= len(m.ExecutorId)
+		copy(dAtA[i:], m.ExecutorId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.ExecutorId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CordonExecutorResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CordonExecutorResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CordonExecutorResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Err != nil {
+		{
+			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ExecutorCordon) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ExecutorCordon) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ExecutorCordon) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.State != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.State))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.ExecutorId) > 0 {
+		i -= len(m.ExecutorId)
+		copy(dAtA[i:], m.ExecutorId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.ExecutorId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryExecutorCordonsRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryExecutorCordonsRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryExecutorCordonsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryExecutorCordonsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryExecutorCordonsResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryExecutorCordonsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Cordons) > 0 {
+		for iNdEx := len(m.Cordons) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Cordons[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintMaster(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Err != nil {
+		{
+			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ClusterHealthRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ClusterHealthRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ClusterHealthRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *ClusterHealthResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ClusterHealthResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ClusterHealthResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Problems) > 0 {
+		for iNdEx := len(m.Problems) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Problems[iNdEx])
+			copy(dAtA[i:], m.Problems[iNdEx])
+			i = encodeVarintMaster(dAtA, i, uint64(len(m.Problems[iNdEx])))
+			i--
+			dAtA[i] = 0x42
+		}
+	}
+	if len(m.ErrorJobs) > 0 {
+		for iNdEx := len(m.ErrorJobs) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.ErrorJobs[iNdEx])
+			copy(dAtA[i:], m.ErrorJobs[iNdEx])
+			i = encodeVarintMaster(dAtA, i, uint64(len(m.ErrorJobs[iNdEx])))
+			i--
+			dAtA[i] = 0x3a
+		}
+	}
+	if len(m.StaleExecutors) > 0 {
+		for iNdEx := len(m.StaleExecutors) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.StaleExecutors[iNdEx])
+			copy(dAtA[i:], m.StaleExecutors[iNdEx])
+			i = encodeVarintMaster(dAtA, i, uint64(len(m.StaleExecutors[iNdEx])))
+			i--
+			dAtA[i] = 0x32
+		}
+	}
+	if m.Executors != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.Executors))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.MetastoreReachable {
+		i--
+		if m.MetastoreReachable {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.Leader) > 0 {
+		i -= len(m.Leader)
+		copy(dAtA[i:], m.Leader)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.Leader)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Healthy {
+		i--
+		if m.Healthy {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Err != nil {
+		{
+			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ScheduleTaskRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ScheduleTaskRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ScheduleTaskRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.WaitTimeoutMs != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.WaitTimeoutMs))
+		i--
+		dAtA[i] = 0x48
+	}
+	if m.Priority != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.Priority))
+		i--
+		dAtA[i] = 0x40
+	}
+	if m.Queued {
+		i--
+		if m.Queued {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x38
+	}
+	if len(m.JobId) > 0 {
+		i -= len(m.JobId)
+		copy(dAtA[i:], m.JobId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.JobId)))
+		i--
+		dAtA[i] = 0x32
+	}
+	if len(m.Resources) > 0 {
+		for k := range m.Resources {
+			v := m.Resources[k]
+			baseI := i
+			i = encodeVarintMaster(dAtA, i, uint64(v))
+			i--
+			dAtA[i] = 0x10
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintMaster(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintMaster(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	if m.Failover {
+		i--
+		if m.Failover {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.ResourceRequirements) > 0 {
+		for iNdEx := len(m.ResourceRequirements) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.ResourceRequirements[iNdEx])
+			copy(dAtA[i:], m.ResourceRequirements[iNdEx])
+			i = encodeVarintMaster(dAtA, i, uint64(len(m.ResourceRequirements[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if m.Cost != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.Cost))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.TaskId) > 0 {
+		i -= len(m.TaskId)
+		copy(dAtA[i:], m.TaskId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.TaskId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ScheduleTaskResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ScheduleTaskResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ScheduleTaskResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.ExecutorAddr) > 0 {
+		i -= len(m.ExecutorAddr)
+		copy(dAtA[i:], m.ExecutorAddr)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.ExecutorAddr)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.ExecutorId) > 0 {
+		i -= len(m.ExecutorId)
+		copy(dAtA[i:], m.ExecutorId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.ExecutorId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ScheduleRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ScheduleRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ScheduleRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Done {
+		i--
+		if m.Done {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Task != nil {
+		{
+			size, err := m.Task.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.RequestId != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.RequestId))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ScheduleResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ScheduleResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ScheduleResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Revoked {
+		i--
+		if m.Revoked {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Err != nil {
+		{
+			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Decision != nil {
+		{
+			size, err := m.Decision.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.RequestId != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.RequestId))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ScaleUpJobRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ScaleUpJobRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ScaleUpJobRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Workers != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.Workers))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.CurrentWorkers != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.CurrentWorkers))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.JobId) > 0 {
+		i -= len(m.JobId)
+		copy(dAtA[i:], m.JobId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.JobId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ScaleUpJobResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ScaleUpJobResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ScaleUpJobResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.BackoffMs != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.BackoffMs))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.GrantedWorkers != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.GrantedWorkers))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Err != nil {
+		{
+			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ExecWorkload) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ExecWorkload) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ExecWorkload) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Usage != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.Usage))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Tp != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.Tp))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ExecWorkloadRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ExecWorkloadRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ExecWorkloadRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.ResourceId) > 0 {
+		for iNdEx := len(m.ResourceId) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.ResourceId[iNdEx])
+			copy(dAtA[i:], m.ResourceId[iNdEx])
+			i = encodeVarintMaster(dAtA, i, uint64(len(m.ResourceId[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.Workloads) > 0 {
+		for iNdEx := len(m.Workloads) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Workloads[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintMaster(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.ExecutorId) > 0 {
+		i -= len(m.ExecutorId)
+		copy(dAtA[i:], m.ExecutorId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.ExecutorId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ExecWorkloadResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ExecWorkloadResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ExecWorkloadResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Err != nil {
+		{
+			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PersistResourceRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PersistResourceRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PersistResourceRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.ResourceId) > 0 {
+		i -= len(m.ResourceId)
+		copy(dAtA[i:], m.ResourceId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.ResourceId)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.ExecutorId) > 0 {
+		i -= len(m.ExecutorId)
+		copy(dAtA[i:], m.ExecutorId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.ExecutorId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PersistResourceResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PersistResourceResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PersistResourceResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Err != nil {
+		{
+			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *MigrateMetaStoreRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MigrateMetaStoreRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *MigrateMetaStoreRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Target) > 0 {
+		i -= len(m.Target)
+		copy(dAtA[i:], m.Target)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.Target)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Op != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.Op))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *MigrateMetaStoreResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MigrateMetaStoreResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *MigrateMetaStoreResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Mismatches) > 0 {
+		for k := range m.Mismatches {
+			v := m.Mismatches[k]
+			baseI := i
+			i = encodeVarintMaster(dAtA, i, uint64(v))
+			i--
+			dAtA[i] = 0x10
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintMaster(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintMaster(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x42
+		}
+	}
+	if len(m.LastVerifyError) > 0 {
+		i -= len(m.LastVerifyError)
+		copy(dAtA[i:], m.LastVerifyError)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.LastVerifyError)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if m.LastVerifyTime != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.LastVerifyTime))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.Verifying {
+		i--
+		if m.Verifying {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.FailedWrites != 0 {
+		i = encodeVarintMaster(dAtA, i, uint64(m.FailedWrites))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.TargetEndpoints) > 0 {
+		for iNdEx := len(m.TargetEndpoints) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.TargetEndpoints[iNdEx])
+			copy(dAtA[i:], m.TargetEndpoints[iNdEx])
+			i = encodeVarintMaster(dAtA, i, uint64(len(m.TargetEndpoints[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.Phase) > 0 {
+		i -= len(m.Phase)
+		copy(dAtA[i:], m.Phase)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.Phase)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Err != nil {
+		{
+			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SecretMeta) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SecretMeta) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SecretMeta) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.ProjectId) > 0 {
+		i -= len(m.ProjectId)
+		copy(dAtA[i:], m.ProjectId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.ProjectId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PutSecretRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PutSecretRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PutSecretRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Value) > 0 {
+		i -= len(m.Value)
+		copy(dAtA[i:], m.Value)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.Value)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.ProjectId) > 0 {
+		i -= len(m.ProjectId)
+		copy(dAtA[i:], m.ProjectId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.ProjectId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PutSecretResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PutSecretResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PutSecretResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Err != nil {
+		{
+			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *DeleteSecretRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DeleteSecretRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DeleteSecretRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.ProjectId) > 0 {
+		i -= len(m.ProjectId)
+		copy(dAtA[i:], m.ProjectId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.ProjectId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *DeleteSecretResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DeleteSecretResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DeleteSecretResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Err != nil {
+		{
+			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ListSecretsRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ListSecretsRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ListSecretsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.ProjectId) > 0 {
+		i -= len(m.ProjectId)
+		copy(dAtA[i:], m.ProjectId)
+		i = encodeVarintMaster(dAtA, i, uint64(len(m.ProjectId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ListSecretsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ListSecretsResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ListSecretsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Secrets) > 0 {
+		for iNdEx := len(m.Secrets) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Secrets[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintMaster(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Err != nil {
+		{
+			size, err := m.Err.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMaster(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintMaster(dAtA []byte, offset int, v uint64) int {
+	offset -= sovMaster(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *HeartbeatRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ExecutorId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if m.ResourceUsage != 0 {
+		n += 1 + sovMaster(uint64(m.ResourceUsage))
+	}
+	if m.Status != 0 {
+		n += 1 + sovMaster(uint64(m.Status))
+	}
+	if m.Timestamp != 0 {
+		n += 1 + sovMaster(uint64(m.Timestamp))
+	}
+	if m.Ttl != 0 {
+		n += 1 + sovMaster(uint64(m.Ttl))
+	}
+	if m.IdleEvictable {
+		n += 2
+	}
+	if m.ProtocolVersion != 0 {
+		n += 1 + sovMaster(uint64(m.ProtocolVersion))
+	}
+	if len(m.ResourceUsages) > 0 {
+		for k, v := range m.ResourceUsages {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovMaster(uint64(len(k))) + 1 + sovMaster(uint64(v))
+			n += mapEntrySize + 1 + sovMaster(uint64(mapEntrySize))
+		}
+	}
+	return n
+}
+
+func (m *HeartbeatResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Err != nil {
+		l = m.Err.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	l = len(m.Leader)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if len(m.Addrs) > 0 {
+		for _, s := range m.Addrs {
+			l = len(s)
+			n += 1 + l + sovMaster(uint64(l))
+		}
+	}
+	if m.ClusterProtocolVersion != 0 {
+		n += 1 + sovMaster(uint64(m.ClusterProtocolVersion))
+	}
+	return n
+}
+
+func (m *SubmitJobRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Tp != 0 {
+		n += 1 + sovMaster(uint64(m.Tp))
+	}
+	l = len(m.Config)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	l = len(m.User)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	l = len(m.TemplateId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if len(m.TemplateParams) > 0 {
+		for k, v := range m.TemplateParams {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovMaster(uint64(len(k))) + 1 + len(v) + sovMaster(uint64(len(v)))
+			n += mapEntrySize + 1 + sovMaster(uint64(mapEntrySize))
+		}
+	}
+	if m.MaxCreateWorkerConcurrency != 0 {
+		n += 1 + sovMaster(uint64(m.MaxCreateWorkerConcurrency))
+	}
+	if m.ResourceSpec != nil {
+		l = m.ResourceSpec.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if len(m.Labels) > 0 {
+		for k, v := range m.Labels {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovMaster(uint64(len(k))) + 1 + len(v) + sovMaster(uint64(len(v)))
+			n += mapEntrySize + 1 + sovMaster(uint64(mapEntrySize))
+		}
+	}
+	return n
+}
+
+func (m *JobResourceSpec) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.MinWorkers != 0 {
+		n += 1 + sovMaster(uint64(m.MinWorkers))
+	}
+	if m.MaxWorkers != 0 {
+		n += 1 + sovMaster(uint64(m.MaxWorkers))
+	}
+	if len(m.WorkerResources) > 0 {
+		for k, v := range m.WorkerResources {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovMaster(uint64(len(k))) + 1 + sovMaster(uint64(v))
+			n += mapEntrySize + 1 + sovMaster(uint64(mapEntrySize))
+		}
+	}
+	return n
+}
+
+func (m *QueryJobRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.JobId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *WorkerInfo) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	l = len(m.ExecutorId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	l = len(m.Status)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	l = len(m.Config)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if m.IsTombstone {
+		n += 2
+	}
+	if m.LastHbTime != 0 {
+		n += 1 + sovMaster(uint64(m.LastHbTime))
+	}
+	if m.Workload != 0 {
+		n += 1 + sovMaster(uint64(m.Workload))
+	}
+	if m.CreatingWorkers != 0 {
+		n += 1 + sovMaster(uint64(m.CreatingWorkers))
+	}
+	return n
+}
+
+func (m *QueryJobResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Tp != 0 {
+		n += 1 + sovMaster(uint64(m.Tp))
+	}
+	l = len(m.Config)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if m.Status != 0 {
+		n += 1 + sovMaster(uint64(m.Status))
+	}
+	if m.JobMasterInfo != nil {
+		l = m.JobMasterInfo.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if m.Err != nil {
+		l = m.Err.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if m.MaxCreateWorkerConcurrency != 0 {
+		n += 1 + sovMaster(uint64(m.MaxCreateWorkerConcurrency))
+	}
+	if len(m.Labels) > 0 {
+		for k, v := range m.Labels {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovMaster(uint64(len(k))) + 1 + len(v) + sovMaster(uint64(len(v)))
+			n += mapEntrySize + 1 + sovMaster(uint64(mapEntrySize))
+		}
+	}
+	return n
+}
+
+func (m *QueryJobsRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ProjectId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	l = len(m.LabelSelector)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if len(m.StatusCodes) > 0 {
+		l = 0
+		for _, e := range m.StatusCodes {
+			l += sovMaster(uint64(e))
+		}
+		n += 1 + sovMaster(uint64(l)) + l
+	}
+	if len(m.Types) > 0 {
+		l = 0
+		for _, e := range m.Types {
+			l += sovMaster(uint64(e))
+		}
+		n += 1 + sovMaster(uint64(l)) + l
+	}
+	return n
+}
+
+func (m *JobInfo) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.JobId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	l = len(m.ProjectId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if m.Tp != 0 {
+		n += 1 + sovMaster(uint64(m.Tp))
+	}
+	if m.Status != 0 {
+		n += 1 + sovMaster(uint64(m.Status))
+	}
+	if len(m.Labels) > 0 {
+		for k, v := range m.Labels {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovMaster(uint64(len(k))) + 1 + len(v) + sovMaster(uint64(len(v)))
+			n += mapEntrySize + 1 + sovMaster(uint64(mapEntrySize))
+		}
+	}
+	return n
+}
+
+func (m *QueryJobsResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Err != nil {
+		l = m.Err.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if len(m.Jobs) > 0 {
+		for _, e := range m.Jobs {
+			l = e.Size()
+			n += 1 + l + sovMaster(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *ListWorkersRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.JobId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if len(m.StatusCodes) > 0 {
+		l = 0
+		for _, e := range m.StatusCodes {
+			l += sovMaster(uint64(e))
+		}
+		n += 1 + sovMaster(uint64(l)) + l
+	}
+	l = len(m.ExecutorId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if m.PageSize != 0 {
+		n += 1 + sovMaster(uint64(m.PageSize))
+	}
+	l = len(m.PageToken)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *WorkerSummary) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	l = len(m.ExecutorId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	l = len(m.State)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if m.StatusCode != 0 {
+		n += 1 + sovMaster(uint64(m.StatusCode))
+	}
+	l = len(m.ErrorMessage)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if m.HeartbeatTime != 0 {
+		n += 1 + sovMaster(uint64(m.HeartbeatTime))
+	}
+	return n
+}
+
+func (m *ListWorkersResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Err != nil {
+		l = m.Err.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if len(m.Workers) > 0 {
+		for _, e := range m.Workers {
+			l = e.Size()
+			n += 1 + l + sovMaster(uint64(l))
+		}
+	}
+	l = len(m.NextPageToken)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *CancelJobRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.JobId != 0 {
+		n += 1 + sovMaster(uint64(m.JobId))
+	}
+	l = len(m.JobIdStr)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *PauseJobRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.JobId != 0 {
+		n += 1 + sovMaster(uint64(m.JobId))
+	}
+	l = len(m.JobIdStr)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *SubmitJobResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Err != nil {
+		l = m.Err.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if m.JobId != 0 {
+		n += 1 + sovMaster(uint64(m.JobId))
+	}
+	l = len(m.JobIdStr)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *PauseJobResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Err != nil {
+		l = m.Err.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *CancelJobResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Err != nil {
+		l = m.Err.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *UpdateJobTimeoutsRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.JobId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if m.WorkerTimeoutMs != 0 {
+		n += 1 + sovMaster(uint64(m.WorkerTimeoutMs))
+	}
+	if m.WorkerTimeoutGracefulMs != 0 {
+		n += 1 + sovMaster(uint64(m.WorkerTimeoutGracefulMs))
+	}
+	if m.WorkerHeartbeatIntervalMs != 0 {
+		n += 1 + sovMaster(uint64(m.WorkerHeartbeatIntervalMs))
+	}
+	return n
+}
+
+func (m *UpdateJobTimeoutsResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Err != nil {
+		l = m.Err.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *SetJobLogLevelRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.JobId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	l = len(m.Level)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *SetJobLogLevelResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Err != nil {
+		l = m.Err.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *OperateJobTasksRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.JobId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if m.Op != 0 {
+		n += 1 + sovMaster(uint64(m.Op))
+	}
+	if len(m.Tasks) > 0 {
+		for _, s := range m.Tasks {
+			l = len(s)
+			n += 1 + l + sovMaster(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *OperateJobTasksResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Err != nil {
+		l = m.Err.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *UpdateJobSourceRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.JobId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	l = len(m.SourceId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	l = len(m.Config)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *UpdateJobSourceResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Err != nil {
+		l = m.Err.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *JobSchedule) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ScheduleId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if m.Tp != 0 {
+		n += 1 + sovMaster(uint64(m.Tp))
+	}
+	l = len(m.Config)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	l = len(m.User)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	l = len(m.Cron)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if m.CatchUp != 0 {
+		n += 1 + sovMaster(uint64(m.CatchUp))
+	}
+	if m.Paused {
+		n += 2
+	}
+	if m.LastFireTimeMs != 0 {
+		n += 1 + sovMaster(uint64(m.LastFireTimeMs))
+	}
+	if m.NextFireTimeMs != 0 {
+		n += 1 + sovMaster(uint64(m.NextFireTimeMs))
+	}
+	return n
+}
+
+func (m *CreateJobScheduleRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Schedule != nil {
+		l = m.Schedule.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *CreateJobScheduleResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Err != nil {
+		l = m.Err.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	l = len(m.ScheduleId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *UpdateJobScheduleRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Schedule != nil {
+		l = m.Schedule.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *UpdateJobScheduleResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Err != nil {
+		l = m.Err.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *DeleteJobScheduleRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ScheduleId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *DeleteJobScheduleResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Err != nil {
+		l = m.Err.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *QueryJobSchedulesRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ScheduleId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *QueryJobSchedulesResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Err != nil {
+		l = m.Err.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if len(m.Schedules) > 0 {
+		for _, e := range m.Schedules {
+			l = e.Size()
+			n += 1 + l + sovMaster(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *JobTemplate) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.TemplateId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if m.Tp != 0 {
+		n += 1 + sovMaster(uint64(m.Tp))
+	}
+	l = len(m.Template)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if len(m.Params) > 0 {
+		for _, e := range m.Params {
+			l = e.Size()
+			n += 1 + l + sovMaster(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *JobTemplateParam) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	l = len(m.DefaultValue)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if m.Required {
+		n += 2
+	}
+	return n
+}
+
+func (m *RegisterJobTemplateRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Template != nil {
+		l = m.Template.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *RegisterJobTemplateResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Err != nil {
+		l = m.Err.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *DeleteJobTemplateRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.TemplateId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *DeleteJobTemplateResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Err != nil {
+		l = m.Err.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *QueryJobTemplatesRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.TemplateId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *QueryJobTemplatesResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Err != nil {
+		l = m.Err.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if len(m.Templates) > 0 {
+		for _, e := range m.Templates {
+			l = e.Size()
+			n += 1 + l + sovMaster(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *RegisterExecutorRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Address)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	l = len(m.Version)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if m.Capability != 0 {
+		n += 1 + sovMaster(uint64(m.Capability))
+	}
+	l = len(m.ExecutorId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if m.ProtocolVersion != 0 {
+		n += 1 + sovMaster(uint64(m.ProtocolVersion))
+	}
+	if len(m.Resources) > 0 {
+		for k, v := range m.Resources {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovMaster(uint64(len(k))) + 1 + sovMaster(uint64(v))
+			n += mapEntrySize + 1 + sovMaster(uint64(mapEntrySize))
+		}
+	}
+	if len(m.Labels) > 0 {
+		for k, v := range m.Labels {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovMaster(uint64(len(k))) + 1 + len(v) + sovMaster(uint64(len(v)))
+			n += mapEntrySize + 1 + sovMaster(uint64(mapEntrySize))
+		}
+	}
+	return n
+}
+
+func (m *RegisterExecutorResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Err != nil {
+		l = m.Err.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	l = len(m.ExecutorId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if m.ClusterProtocolVersion != 0 {
+		n += 1 + sovMaster(uint64(m.ClusterProtocolVersion))
+	}
+	return n
+}
+
+func (m *CordonExecutorRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ExecutorId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if m.State != 0 {
+		n += 1 + sovMaster(uint64(m.State))
+	}
+	return n
+}
+
+func (m *CordonExecutorResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Err != nil {
+		l = m.Err.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *ExecutorCordon) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ExecutorId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if m.State != 0 {
+		n += 1 + sovMaster(uint64(m.State))
+	}
+	return n
+}
+
+func (m *QueryExecutorCordonsRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *QueryExecutorCordonsResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Err != nil {
+		l = m.Err.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if len(m.Cordons) > 0 {
+		for _, e := range m.Cordons {
+			l = e.Size()
+			n += 1 + l + sovMaster(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *ClusterHealthRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *ClusterHealthResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Err != nil {
+		l = m.Err.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if m.Healthy {
+		n += 2
+	}
+	l = len(m.Leader)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if m.MetastoreReachable {
+		n += 2
+	}
+	if m.Executors != 0 {
+		n += 1 + sovMaster(uint64(m.Executors))
+	}
+	if len(m.StaleExecutors) > 0 {
+		for _, s := range m.StaleExecutors {
+			l = len(s)
+			n += 1 + l + sovMaster(uint64(l))
+		}
+	}
+	if len(m.ErrorJobs) > 0 {
+		for _, s := range m.ErrorJobs {
+			l = len(s)
+			n += 1 + l + sovMaster(uint64(l))
+		}
+	}
+	if len(m.Problems) > 0 {
+		for _, s := range m.Problems {
+			l = len(s)
+			n += 1 + l + sovMaster(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *ScheduleTaskRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.TaskId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if m.Cost != 0 {
+		n += 1 + sovMaster(uint64(m.Cost))
+	}
+	if len(m.ResourceRequirements) > 0 {
+		for _, s := range m.ResourceRequirements {
+			l = len(s)
+			n += 1 + l + sovMaster(uint64(l))
+		}
+	}
+	if m.Failover {
+		n += 2
+	}
+	if len(m.Resources) > 0 {
+		for k, v := range m.Resources {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovMaster(uint64(len(k))) + 1 + sovMaster(uint64(v))
+			n += mapEntrySize + 1 + sovMaster(uint64(mapEntrySize))
+		}
+	}
+	l = len(m.JobId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if m.Queued {
+		n += 2
+	}
+	if m.Priority != 0 {
+		n += 1 + sovMaster(uint64(m.Priority))
+	}
+	if m.WaitTimeoutMs != 0 {
+		n += 1 + sovMaster(uint64(m.WaitTimeoutMs))
+	}
+	return n
+}
+
+func (m *ScheduleTaskResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ExecutorId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	l = len(m.ExecutorAddr)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *ScheduleRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.RequestId != 0 {
+		n += 1 + sovMaster(uint64(m.RequestId))
+	}
+	if m.Task != nil {
+		l = m.Task.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if m.Done {
+		n += 2
+	}
+	return n
+}
+
+func (m *ScheduleResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.RequestId != 0 {
+		n += 1 + sovMaster(uint64(m.RequestId))
+	}
+	if m.Decision != nil {
+		l = m.Decision.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if m.Err != nil {
+		l = m.Err.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if m.Revoked {
+		n += 2
+	}
+	return n
+}
+
+func (m *ScaleUpJobRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.JobId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if m.CurrentWorkers != 0 {
+		n += 1 + sovMaster(uint64(m.CurrentWorkers))
+	}
+	if m.Workers != 0 {
+		n += 1 + sovMaster(uint64(m.Workers))
+	}
+	return n
+}
+
+func (m *ScaleUpJobResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Err != nil {
+		l = m.Err.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if m.GrantedWorkers != 0 {
+		n += 1 + sovMaster(uint64(m.GrantedWorkers))
+	}
+	if m.BackoffMs != 0 {
+		n += 1 + sovMaster(uint64(m.BackoffMs))
+	}
+	return n
+}
+
+func (m *ExecWorkload) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Tp != 0 {
+		n += 1 + sovMaster(uint64(m.Tp))
+	}
+	if m.Usage != 0 {
+		n += 1 + sovMaster(uint64(m.Usage))
+	}
+	return n
+}
+
+func (m *ExecWorkloadRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ExecutorId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if len(m.Workloads) > 0 {
+		for _, e := range m.Workloads {
+			l = e.Size()
+			n += 1 + l + sovMaster(uint64(l))
+		}
+	}
+	if len(m.ResourceId) > 0 {
+		for _, s := range m.ResourceId {
+			l = len(s)
+			n += 1 + l + sovMaster(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *ExecWorkloadResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Err != nil {
+		l = m.Err.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *PersistResourceRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ExecutorId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	l = len(m.ResourceId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *PersistResourceResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Err != nil {
+		l = m.Err.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *MigrateMetaStoreRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Op != 0 {
+		n += 1 + sovMaster(uint64(m.Op))
+	}
+	l = len(m.Target)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *MigrateMetaStoreResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Err != nil {
+		l = m.Err.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	l = len(m.Phase)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if len(m.TargetEndpoints) > 0 {
+		for _, s := range m.TargetEndpoints {
+			l = len(s)
+			n += 1 + l + sovMaster(uint64(l))
+		}
+	}
+	if m.FailedWrites != 0 {
+		n += 1 + sovMaster(uint64(m.FailedWrites))
+	}
+	if m.Verifying {
+		n += 2
+	}
+	if m.LastVerifyTime != 0 {
+		n += 1 + sovMaster(uint64(m.LastVerifyTime))
+	}
+	l = len(m.LastVerifyError)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if len(m.Mismatches) > 0 {
+		for k, v := range m.Mismatches {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovMaster(uint64(len(k))) + 1 + sovMaster(uint64(v))
+			n += mapEntrySize + 1 + sovMaster(uint64(mapEntrySize))
+		}
+	}
+	return n
+}
+
+func (m *SecretMeta) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ProjectId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *PutSecretRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ProjectId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *PutSecretResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Err != nil {
+		l = m.Err.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *DeleteSecretRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ProjectId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *DeleteSecretResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Err != nil {
+		l = m.Err.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *ListSecretsRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ProjectId)
+	if l > 0 {
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	return n
+}
+
+func (m *ListSecretsResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Err != nil {
+		l = m.Err.Size()
+		n += 1 + l + sovMaster(uint64(l))
+	}
+	if len(m.Secrets) > 0 {
+		for _, e := range m.Secrets {
+			l = e.Size()
+			n += 1 + l + sovMaster(uint64(l))
+		}
+	}
+	return n
+}
+
+func sovMaster(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozMaster(x uint64) (n int) {
+	return sovMaster(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *HeartbeatRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: HeartbeatRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: HeartbeatRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExecutorId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ExecutorId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ResourceUsage", wireType)
+			}
+			m.ResourceUsage = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ResourceUsage |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+			}
+			m.Status = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Status |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			m.Timestamp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Timestamp |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Ttl", wireType)
+			}
+			m.Ttl = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Ttl |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IdleEvictable", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.IdleEvictable = bool(v != 0)
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ProtocolVersion", wireType)
+			}
+			m.ProtocolVersion = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ProtocolVersion |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ResourceUsages", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ResourceUsages == nil {
+				m.ResourceUsages = make(map[string]int64)
+			}
+			var mapkey string
+			var mapvalue int64
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMaster
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMaster
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthMaster
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthMaster
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMaster
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapvalue |= int64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipMaster(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthMaster
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.ResourceUsages[mapkey] = mapvalue
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *HeartbeatResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: HeartbeatResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: HeartbeatResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Err == nil {
+				m.Err = &Error{}
+			}
+			if err := m.Err.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Leader", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Leader = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Addrs", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Addrs = append(m.Addrs, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClusterProtocolVersion", wireType)
+			}
+			m.ClusterProtocolVersion = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ClusterProtocolVersion |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SubmitJobRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SubmitJobRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SubmitJobRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tp", wireType)
+			}
+			m.Tp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Tp |= JobType(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Config", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Config = append(m.Config[:0], dAtA[iNdEx:postIndex]...)
+			if m.Config == nil {
+				m.Config = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field User", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.User = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TemplateId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TemplateId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TemplateParams", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.TemplateParams == nil {
+				m.TemplateParams = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMaster
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMaster
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthMaster
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthMaster
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMaster
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthMaster
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthMaster
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipMaster(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthMaster
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.TemplateParams[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxCreateWorkerConcurrency", wireType)
+			}
+			m.MaxCreateWorkerConcurrency = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxCreateWorkerConcurrency |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ResourceSpec", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ResourceSpec == nil {
+				m.ResourceSpec = &JobResourceSpec{}
+			}
+			if err := m.ResourceSpec.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Labels", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Labels == nil {
+				m.Labels = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMaster
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMaster
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthMaster
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthMaster
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMaster
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthMaster
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthMaster
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipMaster(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthMaster
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Labels[mapkey] = mapvalue
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *JobResourceSpec) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: JobResourceSpec: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: JobResourceSpec: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MinWorkers", wireType)
+			}
+			m.MinWorkers = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MinWorkers |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxWorkers", wireType)
+			}
+			m.MaxWorkers = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxWorkers |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WorkerResources", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.WorkerResources == nil {
+				m.WorkerResources = make(map[string]int64)
+			}
+			var mapkey string
+			var mapvalue int64
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMaster
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMaster
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthMaster
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthMaster
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMaster
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapvalue |= int64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipMaster(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthMaster
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.WorkerResources[mapkey] = mapvalue
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryJobRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryJobRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryJobRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field JobId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.JobId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *WorkerInfo) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WorkerInfo: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WorkerInfo: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExecutorId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ExecutorId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Status = append(m.Status[:0], dAtA[iNdEx:postIndex]...)
+			if m.Status == nil {
+				m.Status = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Config", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Config = append(m.Config[:0], dAtA[iNdEx:postIndex]...)
+			if m.Config == nil {
+				m.Config = []byte{}
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IsTombstone", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.IsTombstone = bool(v != 0)
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastHbTime", wireType)
+			}
+			m.LastHbTime = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LastHbTime |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Workload", wireType)
+			}
+			m.Workload = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Workload |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreatingWorkers", wireType)
+			}
+			m.CreatingWorkers = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CreatingWorkers |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryJobResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryJobResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryJobResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tp", wireType)
+			}
+			m.Tp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Tp |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Config", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Config = append(m.Config[:0], dAtA[iNdEx:postIndex]...)
+			if m.Config == nil {
+				m.Config = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+			}
+			m.Status = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Status |= QueryJobResponse_JobStatus(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field JobMasterInfo", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.JobMasterInfo == nil {
+				m.JobMasterInfo = &WorkerInfo{}
+			}
+			if err := m.JobMasterInfo.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Err == nil {
+				m.Err = &Error{}
+			}
+			if err := m.Err.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxCreateWorkerConcurrency", wireType)
+			}
+			m.MaxCreateWorkerConcurrency = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxCreateWorkerConcurrency |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Labels", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Labels == nil {
+				m.Labels = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMaster
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMaster
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthMaster
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthMaster
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMaster
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthMaster
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthMaster
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipMaster(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthMaster
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Labels[mapkey] = mapvalue
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryJobsRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryJobsRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryJobsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ProjectId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ProjectId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LabelSelector", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LabelSelector = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType == 0 {
+				var v int32
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMaster
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= int32(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.StatusCodes = append(m.StatusCodes, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMaster
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthMaster
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthMaster
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				var count int
+				for _, integer := range dAtA[iNdEx:postIndex] {
+					if integer < 128 {
+						count++
+					}
+				}
+				elementCount = count
+				if elementCount != 0 && len(m.StatusCodes) == 0 {
+					m.StatusCodes = make([]int32, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v int32
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMaster
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= int32(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.StatusCodes = append(m.StatusCodes, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field StatusCodes", wireType)
+			}
+		case 4:
+			if wireType == 0 {
+				var v int64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMaster
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= int64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.Types = append(m.Types, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMaster
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthMaster
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthMaster
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				var count int
+				for _, integer := range dAtA[iNdEx:postIndex] {
+					if integer < 128 {
+						count++
+					}
+				}
+				elementCount = count
+				if elementCount != 0 && len(m.Types) == 0 {
+					m.Types = make([]int64, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v int64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMaster
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= int64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.Types = append(m.Types, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field Types", wireType)
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *JobInfo) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: JobInfo: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: JobInfo: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field JobId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.JobId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ProjectId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ProjectId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tp", wireType)
+			}
+			m.Tp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Tp |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+			}
+			m.Status = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Status |= QueryJobResponse_JobStatus(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Labels", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Labels == nil {
+				m.Labels = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMaster
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMaster
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthMaster
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthMaster
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMaster
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthMaster
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthMaster
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipMaster(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthMaster
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Labels[mapkey] = mapvalue
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryJobsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryJobsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryJobsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Err == nil {
+				m.Err = &Error{}
+			}
+			if err := m.Err.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Jobs", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Jobs = append(m.Jobs, &JobInfo{})
+			if err := m.Jobs[len(m.Jobs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ListWorkersRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ListWorkersRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ListWorkersRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field JobId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.JobId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType == 0 {
+				var v int32
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMaster
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= int32(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.StatusCodes = append(m.StatusCodes, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMaster
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthMaster
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthMaster
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				var count int
+				for _, integer := range dAtA[iNdEx:postIndex] {
+					if integer < 128 {
+						count++
+					}
+				}
+				elementCount = count
+				if elementCount != 0 && len(m.StatusCodes) == 0 {
+					m.StatusCodes = make([]int32, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v int32
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMaster
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= int32(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.StatusCodes = append(m.StatusCodes, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field StatusCodes", wireType)
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExecutorId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ExecutorId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PageSize", wireType)
+			}
+			m.PageSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.PageSize |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PageToken", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PageToken = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *WorkerSummary) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WorkerSummary: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WorkerSummary: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExecutorId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ExecutorId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field State", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.State = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StatusCode", wireType)
+			}
+			m.StatusCode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StatusCode |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorMessage", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ErrorMessage = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HeartbeatTime", wireType)
+			}
+			m.HeartbeatTime = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.HeartbeatTime |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ListWorkersResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ListWorkersResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ListWorkersResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Err == nil {
+				m.Err = &Error{}
+			}
+			if err := m.Err.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Workers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Workers = append(m.Workers, &WorkerSummary{})
+			if err := m.Workers[len(m.Workers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NextPageToken", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NextPageToken = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CancelJobRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CancelJobRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CancelJobRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field JobId", wireType)
+			}
+			m.JobId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.JobId |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field JobIdStr", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.JobIdStr = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PauseJobRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PauseJobRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PauseJobRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field JobId", wireType)
+			}
+			m.JobId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.JobId |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field JobIdStr", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.JobIdStr = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SubmitJobResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SubmitJobResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SubmitJobResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Err == nil {
+				m.Err = &Error{}
+			}
+			if err := m.Err.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field JobId", wireType)
+			}
+			m.JobId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.JobId |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field JobIdStr", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.JobIdStr = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PauseJobResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PauseJobResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PauseJobResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Err == nil {
+				m.Err = &Error{}
+			}
+			if err := m.Err.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CancelJobResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CancelJobResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CancelJobResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Err == nil {
+				m.Err = &Error{}
+			}
+			if err := m.Err.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *UpdateJobTimeoutsRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: UpdateJobTimeoutsRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: UpdateJobTimeoutsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field JobId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.JobId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WorkerTimeoutMs", wireType)
+			}
+			m.WorkerTimeoutMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.WorkerTimeoutMs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WorkerTimeoutGracefulMs", wireType)
+			}
+			m.WorkerTimeoutGracefulMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.WorkerTimeoutGracefulMs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WorkerHeartbeatIntervalMs", wireType)
+			}
+			m.WorkerHeartbeatIntervalMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.WorkerHeartbeatIntervalMs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *UpdateJobTimeoutsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: UpdateJobTimeoutsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: UpdateJobTimeoutsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Err == nil {
+				m.Err = &Error{}
+			}
+			if err := m.Err.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SetJobLogLevelRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SetJobLogLevelRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SetJobLogLevelRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field JobId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.JobId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Level", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Level = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SetJobLogLevelResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SetJobLogLevelResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SetJobLogLevelResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Err == nil {
+				m.Err = &Error{}
+			}
+			if err := m.Err.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *OperateJobTasksRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: OperateJobTasksRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: OperateJobTasksRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field JobId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.JobId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Op", wireType)
+			}
+			m.Op = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Op |= JobTaskOp(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tasks", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Tasks = append(m.Tasks, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *OperateJobTasksResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: OperateJobTasksResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: OperateJobTasksResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Err == nil {
+				m.Err = &Error{}
+			}
+			if err := m.Err.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *UpdateJobSourceRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: UpdateJobSourceRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: UpdateJobSourceRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field JobId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.JobId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SourceId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SourceId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Config", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Config = append(m.Config[:0], dAtA[iNdEx:postIndex]...)
+			if m.Config == nil {
+				m.Config = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *UpdateJobSourceResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: UpdateJobSourceResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: UpdateJobSourceResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Err == nil {
+				m.Err = &Error{}
+			}
+			if err := m.Err.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *JobSchedule) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: JobSchedule: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: JobSchedule: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ScheduleId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ScheduleId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tp", wireType)
+			}
+			m.Tp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Tp |= JobType(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Config", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Config = append(m.Config[:0], dAtA[iNdEx:postIndex]...)
+			if m.Config == nil {
+				m.Config = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field User", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.User = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cron", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Cron = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CatchUp", wireType)
+			}
+			m.CatchUp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CatchUp |= CatchUpPolicy(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Paused", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Paused = bool(v != 0)
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastFireTimeMs", wireType)
+			}
+			m.LastFireTimeMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LastFireTimeMs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NextFireTimeMs", wireType)
+			}
+			m.NextFireTimeMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.NextFireTimeMs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CreateJobScheduleRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CreateJobScheduleRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CreateJobScheduleRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Schedule", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Schedule == nil {
+				m.Schedule = &JobSchedule{}
+			}
+			if err := m.Schedule.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CreateJobScheduleResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CreateJobScheduleResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CreateJobScheduleResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Err == nil {
+				m.Err = &Error{}
+			}
+			if err := m.Err.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ScheduleId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ScheduleId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *UpdateJobScheduleRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: UpdateJobScheduleRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: UpdateJobScheduleRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Schedule", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Schedule == nil {
+				m.Schedule = &JobSchedule{}
+			}
+			if err := m.Schedule.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *UpdateJobScheduleResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: UpdateJobScheduleResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: UpdateJobScheduleResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Err == nil {
+				m.Err = &Error{}
+			}
+			if err := m.Err.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DeleteJobScheduleRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DeleteJobScheduleRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DeleteJobScheduleRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ScheduleId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ScheduleId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DeleteJobScheduleResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DeleteJobScheduleResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DeleteJobScheduleResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Err == nil {
+				m.Err = &Error{}
+			}
+			if err := m.Err.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryJobSchedulesRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryJobSchedulesRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryJobSchedulesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ScheduleId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ScheduleId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryJobSchedulesResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryJobSchedulesResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryJobSchedulesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Err == nil {
+				m.Err = &Error{}
+			}
+			if err := m.Err.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Schedules", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Schedules = append(m.Schedules, &JobSchedule{})
+			if err := m.Schedules[len(m.Schedules)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *JobTemplate) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: JobTemplate: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: JobTemplate: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TemplateId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TemplateId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tp", wireType)
+			}
+			m.Tp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Tp |= JobType(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Template", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Template = append(m.Template[:0], dAtA[iNdEx:postIndex]...)
+			if m.Template == nil {
+				m.Template = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Params", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Params = append(m.Params, &JobTemplateParam{})
+			if err := m.Params[len(m.Params)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *JobTemplateParam) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: JobTemplateParam: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: JobTemplateParam: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DefaultValue", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DefaultValue = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Required", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Required = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RegisterJobTemplateRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RegisterJobTemplateRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RegisterJobTemplateRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Template", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Template == nil {
+				m.Template = &JobTemplate{}
+			}
+			if err := m.Template.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RegisterJobTemplateResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RegisterJobTemplateResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RegisterJobTemplateResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Err == nil {
+				m.Err = &Error{}
+			}
+			if err := m.Err.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DeleteJobTemplateRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DeleteJobTemplateRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DeleteJobTemplateRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TemplateId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TemplateId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DeleteJobTemplateResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DeleteJobTemplateResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DeleteJobTemplateResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Err == nil {
+				m.Err = &Error{}
+			}
+			if err := m.Err.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryJobTemplatesRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryJobTemplatesRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryJobTemplatesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TemplateId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TemplateId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryJobTemplatesResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryJobTemplatesResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryJobTemplatesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Err == nil {
+				m.Err = &Error{}
+			}
+			if err := m.Err.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Templates", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Templates = append(m.Templates, &JobTemplate{})
+			if err := m.Templates[len(m.Templates)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RegisterExecutorRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RegisterExecutorRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RegisterExecutorRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Address", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Address = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Version = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Capability", wireType)
+			}
+			m.Capability = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Capability |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExecutorId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ExecutorId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ProtocolVersion", wireType)
+			}
+			m.ProtocolVersion = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ProtocolVersion |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Resources", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Resources == nil {
+				m.Resources = make(map[string]int64)
+			}
+			var mapkey string
+			var mapvalue int64
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMaster
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMaster
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthMaster
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthMaster
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMaster
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapvalue |= int64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipMaster(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthMaster
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Resources[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Labels", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Labels == nil {
+				m.Labels = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMaster
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMaster
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthMaster
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthMaster
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMaster
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthMaster
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthMaster
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipMaster(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthMaster
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Labels[mapkey] = mapvalue
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RegisterExecutorResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RegisterExecutorResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RegisterExecutorResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Err == nil {
+				m.Err = &Error{}
+			}
+			if err := m.Err.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExecutorId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ExecutorId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClusterProtocolVersion", wireType)
+			}
+			m.ClusterProtocolVersion = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ClusterProtocolVersion |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CordonExecutorRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CordonExecutorRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CordonExecutorRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExecutorId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ExecutorId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field State", wireType)
+			}
+			m.State = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.State |= CordonState(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CordonExecutorResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CordonExecutorResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CordonExecutorResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Err == nil {
+				m.Err = &Error{}
+			}
+			if err := m.Err.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ExecutorCordon) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ExecutorCordon: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ExecutorCordon: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExecutorId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ExecutorId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field State", wireType)
+			}
+			m.State = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.State |= CordonState(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryExecutorCordonsRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryExecutorCordonsRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryExecutorCordonsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
 		}
 	}
-	return n
-}
 
-func (m *ExecWorkloadResponse) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.Err != nil {
-		l = m.Err.Size()
-		n += 1 + l + sovMaster(uint64(l))
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	return n
+	return nil
 }
-
-func (m *PersistResourceRequest) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = len(m.ExecutorId)
-	if l > 0 {
-		n += 1 + l + sovMaster(uint64(l))
-	}
-	l = len(m.ResourceId)
-	if l > 0 {
-		n += 1 + l + sovMaster(uint64(l))
+func (m *QueryExecutorCordonsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryExecutorCordonsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryExecutorCordonsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Err == nil {
+				m.Err = &Error{}
+			}
+			if err := m.Err.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cordons", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Cordons = append(m.Cordons, &ExecutorCordon{})
+			if err := m.Cordons[len(m.Cordons)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
 	}
-	return n
-}
 
-func (m *PersistResourceResponse) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.Err != nil {
-		l = m.Err.Size()
-		n += 1 + l + sovMaster(uint64(l))
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	return n
+	return nil
 }
+func (m *ClusterHealthRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ClusterHealthRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ClusterHealthRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
 
-func sovMaster(x uint64) (n int) {
-	return (math_bits.Len64(x|1) + 6) / 7
-}
-func sozMaster(x uint64) (n int) {
-	return sovMaster(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
 }
-func (m *HeartbeatRequest) Unmarshal(dAtA []byte) error {
+func (m *ClusterHealthResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3068,15 +18591,71 @@ func (m *HeartbeatRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: HeartbeatRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: ClusterHealthResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: HeartbeatRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ClusterHealthResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ExecutorId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Err == nil {
+				m.Err = &Error{}
+			}
+			if err := m.Err.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Healthy", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Healthy = bool(v != 0)
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Leader", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -3104,13 +18683,13 @@ func (m *HeartbeatRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ExecutorId = string(dAtA[iNdEx:postIndex])
+			m.Leader = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 2:
+		case 4:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ResourceUsage", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field MetastoreReachable", wireType)
 			}
-			m.ResourceUsage = 0
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMaster
@@ -3120,16 +18699,17 @@ func (m *HeartbeatRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.ResourceUsage |= int32(b&0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 3:
+			m.MetastoreReachable = bool(v != 0)
+		case 5:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Executors", wireType)
 			}
-			m.Status = 0
+			m.Executors = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMaster
@@ -3139,16 +18719,16 @@ func (m *HeartbeatRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Status |= int32(b&0x7F) << shift
+				m.Executors |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StaleExecutors", wireType)
 			}
-			m.Timestamp = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMaster
@@ -3158,16 +18738,29 @@ func (m *HeartbeatRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Timestamp |= uint64(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 5:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Ttl", wireType)
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
 			}
-			m.Ttl = 0
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StaleExecutors = append(m.StaleExecutors, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorJobs", wireType)
+			}
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMaster
@@ -3177,11 +18770,56 @@ func (m *HeartbeatRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Ttl |= uint64(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ErrorJobs = append(m.ErrorJobs, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Problems", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Problems = append(m.Problems, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMaster(dAtA[iNdEx:])
@@ -3203,7 +18841,7 @@ func (m *HeartbeatRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *HeartbeatResponse) Unmarshal(dAtA []byte) error {
+func (m *ScheduleTaskRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3226,15 +18864,118 @@ func (m *HeartbeatResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: HeartbeatResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: ScheduleTaskRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: HeartbeatResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ScheduleTaskRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field TaskId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TaskId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cost", wireType)
+			}
+			m.Cost = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Cost |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ResourceRequirements", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ResourceRequirements = append(m.ResourceRequirements, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Failover", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Failover = bool(v != 0)
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Resources", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -3261,18 +19002,147 @@ func (m *HeartbeatResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Err == nil {
-				m.Err = &Error{}
+			if m.Resources == nil {
+				m.Resources = make(map[string]int64)
+			}
+			var mapkey string
+			var mapvalue int64
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMaster
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMaster
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthMaster
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthMaster
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMaster
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapvalue |= int64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipMaster(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthMaster
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
 			}
-			if err := m.Err.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.Resources[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field JobId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.JobId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Queued", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			iNdEx = postIndex
-		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Leader", wireType)
+			m.Queued = bool(v != 0)
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Priority", wireType)
 			}
-			var stringLen uint64
+			m.Priority = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMaster
@@ -3282,29 +19152,16 @@ func (m *HeartbeatResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.Priority |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthMaster
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthMaster
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Leader = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Addrs", wireType)
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WaitTimeoutMs", wireType)
 			}
-			var stringLen uint64
+			m.WaitTimeoutMs = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMaster
@@ -3314,24 +19171,11 @@ func (m *HeartbeatResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.WaitTimeoutMs |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthMaster
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthMaster
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Addrs = append(m.Addrs, string(dAtA[iNdEx:postIndex]))
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMaster(dAtA[iNdEx:])
@@ -3353,7 +19197,7 @@ func (m *HeartbeatResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SubmitJobRequest) Unmarshal(dAtA []byte) error {
+func (m *ScheduleTaskResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3376,36 +19220,17 @@ func (m *SubmitJobRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SubmitJobRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: ScheduleTaskResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SubmitJobRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ScheduleTaskResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Tp", wireType)
-			}
-			m.Tp = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowMaster
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Tp |= JobType(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Config", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ExecutorId", wireType)
 			}
-			var byteLen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMaster
@@ -3415,29 +19240,27 @@ func (m *SubmitJobRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthMaster
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthMaster
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Config = append(m.Config[:0], dAtA[iNdEx:postIndex]...)
-			if m.Config == nil {
-				m.Config = []byte{}
-			}
+			m.ExecutorId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 3:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field User", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ExecutorAddr", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -3465,7 +19288,7 @@ func (m *SubmitJobRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.User = string(dAtA[iNdEx:postIndex])
+			m.ExecutorAddr = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -3488,7 +19311,7 @@ func (m *SubmitJobRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *QueryJobRequest) Unmarshal(dAtA []byte) error {
+func (m *ScheduleRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3511,17 +19334,36 @@ func (m *QueryJobRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: QueryJobRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: ScheduleRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: QueryJobRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ScheduleRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RequestId", wireType)
+			}
+			m.RequestId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RequestId |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field JobId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Task", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMaster
@@ -3531,24 +19373,48 @@ func (m *QueryJobRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthMaster
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthMaster
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.JobId = string(dAtA[iNdEx:postIndex])
+			if m.Task == nil {
+				m.Task = &ScheduleTaskRequest{}
+			}
+			if err := m.Task.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Done", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Done = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMaster(dAtA[iNdEx:])
@@ -3570,7 +19436,7 @@ func (m *QueryJobRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *WorkerInfo) Unmarshal(dAtA []byte) error {
+func (m *ScheduleResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3593,17 +19459,36 @@ func (m *WorkerInfo) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: WorkerInfo: wiretype end group for non-group")
+			return fmt.Errorf("proto: ScheduleResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: WorkerInfo: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ScheduleResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RequestId", wireType)
+			}
+			m.RequestId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RequestId |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Decision", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMaster
@@ -3613,29 +19498,33 @@ func (m *WorkerInfo) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthMaster
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthMaster
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Id = string(dAtA[iNdEx:postIndex])
+			if m.Decision == nil {
+				m.Decision = &ScheduleTaskResponse{}
+			}
+			if err := m.Decision.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
-		case 2:
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ExecutorId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMaster
@@ -3645,29 +19534,33 @@ func (m *WorkerInfo) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthMaster
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthMaster
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ExecutorId = string(dAtA[iNdEx:postIndex])
+			if m.Err == nil {
+				m.Err = &Error{}
+			}
+			if err := m.Err.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
-		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Revoked", wireType)
 			}
-			var byteLen int
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMaster
@@ -3677,31 +19570,67 @@ func (m *WorkerInfo) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
-				return ErrInvalidLengthMaster
+			m.Revoked = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
 			}
-			postIndex := iNdEx + byteLen
-			if postIndex < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthMaster
 			}
-			if postIndex > l {
+			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Status = append(m.Status[:0], dAtA[iNdEx:postIndex]...)
-			if m.Status == nil {
-				m.Status = []byte{}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ScaleUpJobRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
 			}
-			iNdEx = postIndex
-		case 4:
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ScaleUpJobRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ScaleUpJobRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Config", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field JobId", wireType)
 			}
-			var byteLen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMaster
@@ -3711,51 +19640,29 @@ func (m *WorkerInfo) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthMaster
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthMaster
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Config = append(m.Config[:0], dAtA[iNdEx:postIndex]...)
-			if m.Config == nil {
-				m.Config = []byte{}
-			}
+			m.JobId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 5:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field IsTombstone", wireType)
-			}
-			var v int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowMaster
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				v |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			m.IsTombstone = bool(v != 0)
-		case 6:
+		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field LastHbTime", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field CurrentWorkers", wireType)
 			}
-			m.LastHbTime = 0
+			m.CurrentWorkers = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMaster
@@ -3765,16 +19672,16 @@ func (m *WorkerInfo) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.LastHbTime |= int64(b&0x7F) << shift
+				m.CurrentWorkers |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 7:
+		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Workload", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Workers", wireType)
 			}
-			m.Workload = 0
+			m.Workers = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMaster
@@ -3784,7 +19691,7 @@ func (m *WorkerInfo) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Workload |= int64(b&0x7F) << shift
+				m.Workers |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -3810,7 +19717,7 @@ func (m *WorkerInfo) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *QueryJobResponse) Unmarshal(dAtA []byte) error {
+func (m *ScaleUpJobResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3833,36 +19740,17 @@ func (m *QueryJobResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: QueryJobResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: ScaleUpJobResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: QueryJobResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ScaleUpJobResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Tp", wireType)
-			}
-			m.Tp = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowMaster
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Tp |= int64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Config", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMaster
@@ -3872,31 +19760,33 @@ func (m *QueryJobResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthMaster
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthMaster
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Config = append(m.Config[:0], dAtA[iNdEx:postIndex]...)
-			if m.Config == nil {
-				m.Config = []byte{}
+			if m.Err == nil {
+				m.Err = &Error{}
+			}
+			if err := m.Err.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
 			iNdEx = postIndex
-		case 3:
+		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field GrantedWorkers", wireType)
 			}
-			m.Status = 0
+			m.GrantedWorkers = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMaster
@@ -3906,16 +19796,16 @@ func (m *QueryJobResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Status |= QueryJobResponse_JobStatus(b&0x7F) << shift
+				m.GrantedWorkers |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 4:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field JobMasterInfo", wireType)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BackoffMs", wireType)
 			}
-			var msglen int
+			m.BackoffMs = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMaster
@@ -3925,33 +19815,66 @@ func (m *QueryJobResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.BackoffMs |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthMaster
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMaster(dAtA[iNdEx:])
+			if err != nil {
+				return err
 			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthMaster
 			}
-			if postIndex > l {
+			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.JobMasterInfo == nil {
-				m.JobMasterInfo = &WorkerInfo{}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ExecWorkload) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMaster
 			}
-			if err := m.JobMasterInfo.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
 			}
-			iNdEx = postIndex
-		case 5:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
 			}
-			var msglen int
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ExecWorkload: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ExecWorkload: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tp", wireType)
+			}
+			m.Tp = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMaster
@@ -3961,28 +19884,30 @@ func (m *QueryJobResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.Tp |= JobType(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthMaster
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthMaster
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.Err == nil {
-				m.Err = &Error{}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Usage", wireType)
 			}
-			if err := m.Err.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.Usage = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Usage |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMaster(dAtA[iNdEx:])
@@ -4004,7 +19929,7 @@ func (m *QueryJobResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *CancelJobRequest) Unmarshal(dAtA []byte) error {
+func (m *ExecWorkloadRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -4027,17 +19952,17 @@ func (m *CancelJobRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: CancelJobRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: ExecWorkloadRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: CancelJobRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ExecWorkloadRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field JobId", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExecutorId", wireType)
 			}
-			m.JobId = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMaster
@@ -4047,14 +19972,61 @@ func (m *CancelJobRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.JobId |= int32(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ExecutorId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field JobIdStr", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Workloads", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Workloads = append(m.Workloads, &ExecWorkload{})
+			if err := m.Workloads[len(m.Workloads)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ResourceId", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -4082,7 +20054,7 @@ func (m *CancelJobRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.JobIdStr = string(dAtA[iNdEx:postIndex])
+			m.ResourceId = append(m.ResourceId, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -4105,7 +20077,7 @@ func (m *CancelJobRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PauseJobRequest) Unmarshal(dAtA []byte) error {
+func (m *ExecWorkloadResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -4128,36 +20100,17 @@ func (m *PauseJobRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PauseJobRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: ExecWorkloadResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PauseJobRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ExecWorkloadResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field JobId", wireType)
-			}
-			m.JobId = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowMaster
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.JobId |= int32(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field JobIdStr", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMaster
@@ -4167,23 +20120,27 @@ func (m *PauseJobRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthMaster
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthMaster
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.JobIdStr = string(dAtA[iNdEx:postIndex])
+			if m.Err == nil {
+				m.Err = &Error{}
+			}
+			if err := m.Err.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -4206,7 +20163,7 @@ func (m *PauseJobRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SubmitJobResponse) Unmarshal(dAtA []byte) error {
+func (m *PersistResourceRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -4229,17 +20186,17 @@ func (m *SubmitJobResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SubmitJobResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: PersistResourceRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SubmitJobResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PersistResourceRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ExecutorId", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMaster
@@ -4249,50 +20206,27 @@ func (m *SubmitJobResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthMaster
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthMaster
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Err == nil {
-				m.Err = &Error{}
-			}
-			if err := m.Err.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.ExecutorId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field JobId", wireType)
-			}
-			m.JobId = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowMaster
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.JobId |= int32(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field JobIdStr", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ResourceId", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -4320,7 +20254,7 @@ func (m *SubmitJobResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.JobIdStr = string(dAtA[iNdEx:postIndex])
+			m.ResourceId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -4343,7 +20277,7 @@ func (m *SubmitJobResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PauseJobResponse) Unmarshal(dAtA []byte) error {
+func (m *PersistResourceResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -4366,10 +20300,10 @@ func (m *PauseJobResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PauseJobResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: PersistResourceResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PauseJobResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PersistResourceResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -4429,7 +20363,7 @@ func (m *PauseJobResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *CancelJobResponse) Unmarshal(dAtA []byte) error {
+func (m *MigrateMetaStoreRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -4452,17 +20386,36 @@ func (m *CancelJobResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: CancelJobResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: MigrateMetaStoreRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: CancelJobResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: MigrateMetaStoreRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Op", wireType)
+			}
+			m.Op = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Op |= MigrateMetaStoreRequest_Op(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Target", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMaster
@@ -4472,27 +20425,23 @@ func (m *CancelJobResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthMaster
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthMaster
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Err == nil {
-				m.Err = &Error{}
-			}
-			if err := m.Err.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Target = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -4515,7 +20464,7 @@ func (m *CancelJobResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *RegisterExecutorRequest) Unmarshal(dAtA []byte) error {
+func (m *MigrateMetaStoreResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -4538,17 +20487,17 @@ func (m *RegisterExecutorRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: RegisterExecutorRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: MigrateMetaStoreResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: RegisterExecutorRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: MigrateMetaStoreResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Address", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMaster
@@ -4558,27 +20507,31 @@ func (m *RegisterExecutorRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthMaster
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthMaster
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Address = string(dAtA[iNdEx:postIndex])
+			if m.Err == nil {
+				m.Err = &Error{}
+			}
+			if err := m.Err.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Phase", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -4606,13 +20559,13 @@ func (m *RegisterExecutorRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Version = string(dAtA[iNdEx:postIndex])
+			m.Phase = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Capability", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TargetEndpoints", wireType)
 			}
-			m.Capability = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMaster
@@ -4622,66 +20575,87 @@ func (m *RegisterExecutorRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Capability |= int64(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		default:
-			iNdEx = preIndex
-			skippy, err := skipMaster(dAtA[iNdEx:])
-			if err != nil {
-				return err
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
 			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
 				return ErrInvalidLengthMaster
 			}
-			if (iNdEx + skippy) > l {
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *RegisterExecutorResponse) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowMaster
+			m.TargetEndpoints = append(m.TargetEndpoints, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FailedWrites", wireType)
 			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
+			m.FailedWrites = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FailedWrites |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Verifying", wireType)
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: RegisterExecutorResponse: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: RegisterExecutorResponse: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Verifying = bool(v != 0)
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastVerifyTime", wireType)
+			}
+			m.LastVerifyTime = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LastVerifyTime |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field LastVerifyError", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMaster
@@ -4691,33 +20665,29 @@ func (m *RegisterExecutorResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthMaster
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthMaster
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Err == nil {
-				m.Err = &Error{}
-			}
-			if err := m.Err.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.LastVerifyError = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 2:
+		case 8:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ExecutorId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Mismatches", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMaster
@@ -4727,23 +20697,104 @@ func (m *RegisterExecutorResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthMaster
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthMaster
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ExecutorId = string(dAtA[iNdEx:postIndex])
+			if m.Mismatches == nil {
+				m.Mismatches = make(map[string]int64)
+			}
+			var mapkey string
+			var mapvalue int64
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMaster
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMaster
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthMaster
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthMaster
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMaster
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapvalue |= int64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipMaster(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthMaster
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Mismatches[mapkey] = mapvalue
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -4766,7 +20817,7 @@ func (m *RegisterExecutorResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ScheduleTaskRequest) Unmarshal(dAtA []byte) error {
+func (m *SecretMeta) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -4789,15 +20840,15 @@ func (m *ScheduleTaskRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ScheduleTaskRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: SecretMeta: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ScheduleTaskRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SecretMeta: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TaskId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ProjectId", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -4825,30 +20876,11 @@ func (m *ScheduleTaskRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.TaskId = string(dAtA[iNdEx:postIndex])
+			m.ProjectId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Cost", wireType)
-			}
-			m.Cost = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowMaster
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Cost |= int64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ResourceRequirements", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -4876,7 +20908,7 @@ func (m *ScheduleTaskRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ResourceRequirements = append(m.ResourceRequirements, string(dAtA[iNdEx:postIndex]))
+			m.Name = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -4899,7 +20931,7 @@ func (m *ScheduleTaskRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ScheduleTaskResponse) Unmarshal(dAtA []byte) error {
+func (m *PutSecretRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -4922,15 +20954,15 @@ func (m *ScheduleTaskResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ScheduleTaskResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: PutSecretRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ScheduleTaskResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PutSecretRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ExecutorId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ProjectId", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -4958,11 +20990,11 @@ func (m *ScheduleTaskResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ExecutorId = string(dAtA[iNdEx:postIndex])
+			m.ProjectId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ExecutorAddr", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -4990,7 +21022,39 @@ func (m *ScheduleTaskResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ExecutorAddr = string(dAtA[iNdEx:postIndex])
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -5013,7 +21077,7 @@ func (m *ScheduleTaskResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ExecWorkload) Unmarshal(dAtA []byte) error {
+func (m *PutSecretResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5036,17 +21100,17 @@ func (m *ExecWorkload) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ExecWorkload: wiretype end group for non-group")
+			return fmt.Errorf("proto: PutSecretResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ExecWorkload: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PutSecretResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Tp", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
 			}
-			m.Tp = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMaster
@@ -5056,30 +21120,28 @@ func (m *ExecWorkload) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Tp |= JobType(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Usage", wireType)
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
 			}
-			m.Usage = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowMaster
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Usage |= int32(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Err == nil {
+				m.Err = &Error{}
+			}
+			if err := m.Err.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMaster(dAtA[iNdEx:])
@@ -5101,7 +21163,7 @@ func (m *ExecWorkload) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ExecWorkloadRequest) Unmarshal(dAtA []byte) error {
+func (m *DeleteSecretRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5124,15 +21186,15 @@ func (m *ExecWorkloadRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ExecWorkloadRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: DeleteSecretRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ExecWorkloadRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: DeleteSecretRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ExecutorId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ProjectId", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -5160,45 +21222,11 @@ func (m *ExecWorkloadRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ExecutorId = string(dAtA[iNdEx:postIndex])
+			m.ProjectId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Workloads", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowMaster
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			if msglen < 0 {
-				return ErrInvalidLengthMaster
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthMaster
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Workloads = append(m.Workloads, &ExecWorkload{})
-			if err := m.Workloads[len(m.Workloads)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ResourceId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -5226,7 +21254,7 @@ func (m *ExecWorkloadRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ResourceId = append(m.ResourceId, string(dAtA[iNdEx:postIndex]))
+			m.Name = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -5249,7 +21277,7 @@ func (m *ExecWorkloadRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ExecWorkloadResponse) Unmarshal(dAtA []byte) error {
+func (m *DeleteSecretResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5272,10 +21300,10 @@ func (m *ExecWorkloadResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ExecWorkloadResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: DeleteSecretResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ExecWorkloadResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: DeleteSecretResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -5335,7 +21363,7 @@ func (m *ExecWorkloadResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PersistResourceRequest) Unmarshal(dAtA []byte) error {
+func (m *ListSecretsRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5358,47 +21386,15 @@ func (m *PersistResourceRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PersistResourceRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: ListSecretsRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PersistResourceRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ListSecretsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ExecutorId", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowMaster
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthMaster
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthMaster
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.ExecutorId = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ResourceId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ProjectId", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -5426,7 +21422,7 @@ func (m *PersistResourceRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ResourceId = string(dAtA[iNdEx:postIndex])
+			m.ProjectId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -5449,7 +21445,7 @@ func (m *PersistResourceRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PersistResourceResponse) Unmarshal(dAtA []byte) error {
+func (m *ListSecretsResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5472,10 +21468,10 @@ func (m *PersistResourceResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PersistResourceResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: ListSecretsResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PersistResourceResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ListSecretsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -5514,6 +21510,40 @@ func (m *PersistResourceResponse) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Secrets", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMaster
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMaster
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Secrets = append(m.Secrets, &SecretMeta{})
+			if err := m.Secrets[len(m.Secrets)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMaster(dAtA[iNdEx:])
@@ -0,0 +1,46 @@
+package pb
+
+import (
+	"github.com/hanfei1991/microcosm/pkg/redact"
+)
+
+// The messages carrying sensitive values implement redact.Redactor, so that
+// they are redacted in the logs. The job configs are masked as a whole,
+// since their types are unknown here.
+
+// Redacted implements redact.Redactor
+func (m *PutSecretRequest) Redacted() interface{} {
+	return &PutSecretRequest{
+		ProjectId: m.GetProjectId(),
+		Name:      m.GetName(),
+		Value:     redact.Mask,
+	}
+}
+
+// Redacted implements redact.Redactor
+func (m *SubmitJobRequest) Redacted() interface{} {
+	redacted := *m
+	redacted.Config = maskConfig(m.GetConfig())
+	return &redacted
+}
+
+// Redacted implements redact.Redactor
+func (m *UpdateJobSourceRequest) Redacted() interface{} {
+	redacted := *m
+	redacted.Config = maskConfig(m.GetConfig())
+	return &redacted
+}
+
+// Redacted implements redact.Redactor
+func (m *JobSchedule) Redacted() interface{} {
+	redacted := *m
+	redacted.Config = maskConfig(m.GetConfig())
+	return &redacted
+}
+
+func maskConfig(config []byte) []byte {
+	if len(config) == 0 {
+		return config
+	}
+	return []byte(redact.Mask)
+}
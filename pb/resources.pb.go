@@ -58,6 +58,110 @@ func (ResourceErrorCode) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_cf1b13971fe4c19d, []int{0}
 }
 
+type LeaseResourceRequest struct {
+	ResourceId      string `protobuf:"bytes,1,opt,name=resource_id,json=resourceId,proto3" json:"resource_id,omitempty"`
+	CreatorExecutor string `protobuf:"bytes,2,opt,name=creator_executor,json=creatorExecutor,proto3" json:"creator_executor,omitempty"`
+	JobId           string `protobuf:"bytes,3,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	CreatorWorkerId string `protobuf:"bytes,4,opt,name=creator_worker_id,json=creatorWorkerId,proto3" json:"creator_worker_id,omitempty"`
+}
+
+func (m *LeaseResourceRequest) Reset()         { *m = LeaseResourceRequest{} }
+func (m *LeaseResourceRequest) String() string { return proto.CompactTextString(m) }
+func (*LeaseResourceRequest) ProtoMessage()    {}
+func (*LeaseResourceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cf1b13971fe4c19d, []int{0}
+}
+func (m *LeaseResourceRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *LeaseResourceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_LeaseResourceRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *LeaseResourceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LeaseResourceRequest.Merge(m, src)
+}
+func (m *LeaseResourceRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *LeaseResourceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LeaseResourceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LeaseResourceRequest proto.InternalMessageInfo
+
+func (m *LeaseResourceRequest) GetResourceId() string {
+	if m != nil {
+		return m.ResourceId
+	}
+	return ""
+}
+
+func (m *LeaseResourceRequest) GetCreatorExecutor() string {
+	if m != nil {
+		return m.CreatorExecutor
+	}
+	return ""
+}
+
+func (m *LeaseResourceRequest) GetJobId() string {
+	if m != nil {
+		return m.JobId
+	}
+	return ""
+}
+
+func (m *LeaseResourceRequest) GetCreatorWorkerId() string {
+	if m != nil {
+		return m.CreatorWorkerId
+	}
+	return ""
+}
+
+type LeaseResourceResponse struct {
+}
+
+func (m *LeaseResourceResponse) Reset()         { *m = LeaseResourceResponse{} }
+func (m *LeaseResourceResponse) String() string { return proto.CompactTextString(m) }
+func (*LeaseResourceResponse) ProtoMessage()    {}
+func (*LeaseResourceResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cf1b13971fe4c19d, []int{1}
+}
+func (m *LeaseResourceResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *LeaseResourceResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_LeaseResourceResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *LeaseResourceResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LeaseResourceResponse.Merge(m, src)
+}
+func (m *LeaseResourceResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *LeaseResourceResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_LeaseResourceResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LeaseResourceResponse proto.InternalMessageInfo
+
 type CreateResourceRequest struct {
 	ResourceId      string `protobuf:"bytes,1,opt,name=resource_id,json=resourceId,proto3" json:"resource_id,omitempty"`
 	CreatorExecutor string `protobuf:"bytes,2,opt,name=creator_executor,json=creatorExecutor,proto3" json:"creator_executor,omitempty"`
@@ -69,7 +173,7 @@ func (m *CreateResourceRequest) Reset()         { *m = CreateResourceRequest{} }
 func (m *CreateResourceRequest) String() string { return proto.CompactTextString(m) }
 func (*CreateResourceRequest) ProtoMessage()    {}
 func (*CreateResourceRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cf1b13971fe4c19d, []int{0}
+	return fileDescriptor_cf1b13971fe4c19d, []int{2}
 }
 func (m *CreateResourceRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -133,7 +237,7 @@ func (m *CreateResourceResponse) Reset()         { *m = CreateResourceResponse{}
 func (m *CreateResourceResponse) String() string { return proto.CompactTextString(m) }
 func (*CreateResourceResponse) ProtoMessage()    {}
 func (*CreateResourceResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cf1b13971fe4c19d, []int{1}
+	return fileDescriptor_cf1b13971fe4c19d, []int{3}
 }
 func (m *CreateResourceResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -170,7 +274,7 @@ func (m *QueryResourceRequest) Reset()         { *m = QueryResourceRequest{} }
 func (m *QueryResourceRequest) String() string { return proto.CompactTextString(m) }
 func (*QueryResourceRequest) ProtoMessage()    {}
 func (*QueryResourceRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cf1b13971fe4c19d, []int{2}
+	return fileDescriptor_cf1b13971fe4c19d, []int{4}
 }
 func (m *QueryResourceRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -216,7 +320,7 @@ func (m *QueryResourceResponse) Reset()         { *m = QueryResourceResponse{} }
 func (m *QueryResourceResponse) String() string { return proto.CompactTextString(m) }
 func (*QueryResourceResponse) ProtoMessage()    {}
 func (*QueryResourceResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cf1b13971fe4c19d, []int{3}
+	return fileDescriptor_cf1b13971fe4c19d, []int{5}
 }
 func (m *QueryResourceResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -274,7 +378,7 @@ func (m *RemoveResourceRequest) Reset()         { *m = RemoveResourceRequest{} }
 func (m *RemoveResourceRequest) String() string { return proto.CompactTextString(m) }
 func (*RemoveResourceRequest) ProtoMessage()    {}
 func (*RemoveResourceRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cf1b13971fe4c19d, []int{4}
+	return fileDescriptor_cf1b13971fe4c19d, []int{6}
 }
 func (m *RemoveResourceRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -317,7 +421,7 @@ func (m *RemoveResourceResponse) Reset()         { *m = RemoveResourceResponse{}
 func (m *RemoveResourceResponse) String() string { return proto.CompactTextString(m) }
 func (*RemoveResourceResponse) ProtoMessage()    {}
 func (*RemoveResourceResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cf1b13971fe4c19d, []int{5}
+	return fileDescriptor_cf1b13971fe4c19d, []int{7}
 }
 func (m *RemoveResourceResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -355,7 +459,7 @@ func (m *ResourceError) Reset()         { *m = ResourceError{} }
 func (m *ResourceError) String() string { return proto.CompactTextString(m) }
 func (*ResourceError) ProtoMessage()    {}
 func (*ResourceError) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cf1b13971fe4c19d, []int{6}
+	return fileDescriptor_cf1b13971fe4c19d, []int{8}
 }
 func (m *ResourceError) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -400,6 +504,8 @@ func (m *ResourceError) GetStackTrace() string {
 
 func init() {
 	proto.RegisterEnum("pb.ResourceErrorCode", ResourceErrorCode_name, ResourceErrorCode_value)
+	proto.RegisterType((*LeaseResourceRequest)(nil), "pb.LeaseResourceRequest")
+	proto.RegisterType((*LeaseResourceResponse)(nil), "pb.LeaseResourceResponse")
 	proto.RegisterType((*CreateResourceRequest)(nil), "pb.CreateResourceRequest")
 	proto.RegisterType((*CreateResourceResponse)(nil), "pb.CreateResourceResponse")
 	proto.RegisterType((*QueryResourceRequest)(nil), "pb.QueryResourceRequest")
@@ -412,35 +518,37 @@ func init() {
 func init() { proto.RegisterFile("resources.proto", fileDescriptor_cf1b13971fe4c19d) }
 
 var fileDescriptor_cf1b13971fe4c19d = []byte{
-	// 439 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x93, 0xbf, 0x6e, 0xd4, 0x40,
-	0x10, 0xc6, 0xbd, 0x17, 0x88, 0x94, 0x41, 0xb9, 0x73, 0x56, 0x71, 0xe4, 0x9c, 0x22, 0x13, 0x5d,
-	0x05, 0x29, 0xae, 0x08, 0x48, 0x50, 0x73, 0x24, 0x92, 0x0b, 0x90, 0xb0, 0x82, 0x28, 0x4f, 0xb6,
-	0x77, 0x12, 0x5d, 0x12, 0x76, 0xcc, 0x78, 0x0d, 0x84, 0x8e, 0x82, 0x9e, 0xa7, 0xe0, 0x59, 0x28,
-	0x53, 0x52, 0xa2, 0xbb, 0x07, 0xe0, 0x15, 0xd0, 0xfa, 0xbc, 0x48, 0x3e, 0x4c, 0x71, 0x0d, 0xdd,
-	0xea, 0x37, 0xdf, 0x7c, 0x9a, 0x7f, 0x0b, 0x03, 0xc6, 0x92, 0x2a, 0xce, 0xb1, 0x1c, 0x17, 0x4c,
-	0x86, 0x64, 0xaf, 0xc8, 0x46, 0xdf, 0x04, 0x04, 0x13, 0xc6, 0xd4, 0x60, 0xd2, 0x44, 0x13, 0x7c,
-	0x57, 0x61, 0x69, 0xe4, 0x7d, 0xb8, 0xe7, 0x12, 0xa6, 0x33, 0x15, 0x8a, 0x43, 0xf1, 0x60, 0x2b,
-	0x01, 0x87, 0x62, 0x25, 0x1f, 0x82, 0x9f, 0xdb, 0x4c, 0xe2, 0x29, 0x7e, 0xc4, 0xbc, 0x32, 0xc4,
-	0x61, 0xaf, 0x56, 0x0d, 0x1a, 0x7e, 0xd2, 0x60, 0x19, 0xc0, 0xe6, 0x25, 0x65, 0xd6, 0x66, 0xa3,
-	0x16, 0xdc, 0xbd, 0xa4, 0x2c, 0x56, 0xf2, 0x08, 0x76, 0x9c, 0xc3, 0x07, 0xe2, 0x2b, 0x64, 0xab,
-	0xb8, 0xd3, 0xb2, 0x78, 0x53, 0xf3, 0x58, 0x8d, 0x42, 0xd8, 0x5b, 0xad, 0xb3, 0x2c, 0x48, 0x97,
-	0x38, 0x7a, 0x02, 0xbb, 0xaf, 0x2a, 0xe4, 0x9b, 0x75, 0x1b, 0x18, 0x7d, 0x11, 0x10, 0xac, 0x64,
-	0x2e, 0x2d, 0xff, 0x73, 0x6b, 0x4f, 0x21, 0x48, 0xf0, 0x2d, 0xbd, 0x5f, 0x7b, 0x05, 0x76, 0x28,
-	0xab, 0x99, 0xcd, 0x50, 0xce, 0x61, 0xdb, 0xb1, 0x13, 0x66, 0x62, 0xf9, 0x18, 0x00, 0xed, 0x63,
-	0x9a, 0x93, 0xc2, 0xda, 0xaa, 0x7f, 0x1c, 0x8c, 0x8b, 0x6c, 0xdc, 0x92, 0x4d, 0x48, 0x61, 0xb2,
-	0x85, 0xee, 0x69, 0x2b, 0x28, 0x4d, 0x9a, 0x5f, 0x4d, 0x0d, 0xa7, 0x39, 0x36, 0x33, 0x80, 0x1a,
-	0x9d, 0x59, 0x72, 0xf4, 0x59, 0xc0, 0xce, 0x5f, 0x0e, 0x72, 0x0f, 0xa4, 0x83, 0xf1, 0xf3, 0x09,
-	0xe9, 0xf3, 0xeb, 0x59, 0x6e, 0x7c, 0x4f, 0x1e, 0x40, 0xe8, 0xf8, 0xd9, 0x4d, 0x81, 0xaf, 0x35,
-	0x63, 0x4e, 0x17, 0x7a, 0xf6, 0x09, 0x95, 0x2f, 0xe4, 0x21, 0x1c, 0xb8, 0xe8, 0x8b, 0x54, 0xa7,
-	0x17, 0xc8, 0xb1, 0x36, 0xc8, 0x3a, 0xbd, 0xae, 0x9d, 0xfd, 0x9e, 0xdc, 0x05, 0xdf, 0x29, 0x5e,
-	0x92, 0x39, 0xa5, 0x4a, 0x2b, 0x7f, 0xe3, 0xf8, 0x97, 0x80, 0xc1, 0x4a, 0xa2, 0x8c, 0xa1, 0xdf,
-	0x3e, 0x17, 0xb9, 0x6f, 0x9b, 0xed, 0x3c, 0xf5, 0xe1, 0xb0, 0x2b, 0xd4, 0x0c, 0xd2, 0x93, 0xa7,
-	0xb0, 0xdd, 0xba, 0x12, 0x19, 0x5a, 0x79, 0xd7, 0xc9, 0x0d, 0xf7, 0x3b, 0x22, 0x7f, 0x7c, 0x62,
-	0xe8, 0xb7, 0x97, 0xb5, 0x2c, 0xa9, 0x73, 0xf5, 0xcb, 0x92, 0xfe, 0xb1, 0x5b, 0xef, 0x59, 0xf8,
-	0x7d, 0x1e, 0x89, 0xdb, 0x79, 0x24, 0x7e, 0xce, 0x23, 0xf1, 0x75, 0x11, 0x79, 0xb7, 0x8b, 0xc8,
-	0xfb, 0xb1, 0x88, 0xbc, 0x6c, 0xb3, 0xfe, 0xda, 0x8f, 0x7e, 0x07, 0x00, 0x00, 0xff, 0xff, 0xef,
-	0xec, 0xb0, 0x4f, 0xed, 0x03, 0x00, 0x00,
+	// 466 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x54, 0x3d, 0x6f, 0xd4, 0x40,
+	0x10, 0xf5, 0x5e, 0x20, 0x52, 0x06, 0x5d, 0xce, 0x59, 0x9d, 0x83, 0x63, 0x45, 0x26, 0xba, 0x0a,
+	0x52, 0x5c, 0x11, 0x90, 0xa0, 0xe6, 0xc8, 0x49, 0x96, 0x00, 0x09, 0x2b, 0x88, 0xf2, 0xe4, 0x8f,
+	0x49, 0x74, 0x49, 0xf0, 0x98, 0xf1, 0x1a, 0x08, 0x1d, 0x05, 0x3d, 0x7f, 0x02, 0xfe, 0x02, 0x7f,
+	0x81, 0x32, 0x25, 0x25, 0xba, 0xfb, 0x23, 0xc8, 0x3e, 0x2f, 0xc2, 0x66, 0x9b, 0x6b, 0x10, 0xdd,
+	0xea, 0xbd, 0x37, 0x4f, 0x6f, 0x66, 0x67, 0x17, 0x06, 0x8c, 0x05, 0x95, 0x9c, 0x60, 0x31, 0xce,
+	0x99, 0x14, 0xc9, 0x5e, 0x1e, 0x8f, 0xbe, 0x08, 0x18, 0x3e, 0xc5, 0xa8, 0xc0, 0xb0, 0x21, 0x43,
+	0x7c, 0x53, 0x62, 0xa1, 0xe4, 0x1d, 0xb8, 0xa5, 0xf5, 0xb3, 0x79, 0xea, 0x8a, 0x03, 0x71, 0x77,
+	0x2b, 0x04, 0x0d, 0x05, 0xa9, 0xbc, 0x07, 0x76, 0xc2, 0x18, 0x29, 0xe2, 0x19, 0xbe, 0xc7, 0xa4,
+	0x54, 0xc4, 0x6e, 0xaf, 0x56, 0x0d, 0x1a, 0xfc, 0xb8, 0x81, 0xa5, 0x03, 0x9b, 0xe7, 0x14, 0x57,
+	0x36, 0x1b, 0xb5, 0xe0, 0xe6, 0x39, 0xc5, 0x41, 0x2a, 0x0f, 0x61, 0x47, 0x3b, 0xbc, 0x23, 0xbe,
+	0x40, 0xae, 0x14, 0x37, 0x5a, 0x16, 0xaf, 0x6a, 0x3c, 0x48, 0x47, 0xb7, 0xc1, 0xe9, 0xc4, 0x2c,
+	0x72, 0xca, 0x0a, 0x1c, 0x7d, 0x15, 0xe0, 0x4c, 0x2a, 0xf1, 0xff, 0xde, 0x81, 0x0b, 0xbb, 0xdd,
+	0x9c, 0x4d, 0x0b, 0x0f, 0x61, 0xf8, 0xa2, 0x44, 0xbe, 0x5a, 0xb7, 0x81, 0xd1, 0x27, 0x01, 0x4e,
+	0xa7, 0x72, 0x65, 0xf9, 0x8f, 0x5b, 0x7b, 0x04, 0x4e, 0x88, 0xaf, 0xe9, 0xed, 0xda, 0x57, 0x50,
+	0x0d, 0xa5, 0x5b, 0xd9, 0x0c, 0xe5, 0x14, 0xfa, 0x1a, 0x3b, 0x66, 0x26, 0x96, 0x0f, 0x00, 0xb0,
+	0x3a, 0xcc, 0x12, 0x4a, 0xb1, 0xb6, 0xda, 0x3e, 0x72, 0xc6, 0x79, 0x3c, 0x6e, 0xc9, 0x26, 0x94,
+	0x62, 0xb8, 0x85, 0xfa, 0x58, 0x25, 0x28, 0x54, 0x94, 0x5c, 0xcc, 0x14, 0x47, 0x09, 0x36, 0x33,
+	0x80, 0x1a, 0x3a, 0xa9, 0x90, 0xc3, 0x8f, 0x02, 0x76, 0xfe, 0x72, 0x90, 0xbb, 0x20, 0x35, 0x18,
+	0x3c, 0x99, 0x50, 0x76, 0x7a, 0x39, 0x4f, 0x94, 0x6d, 0xc9, 0x7d, 0x70, 0x35, 0x7e, 0x72, 0x95,
+	0xe3, 0xcb, 0x8c, 0x31, 0xa1, 0xb3, 0x6c, 0xfe, 0x01, 0x53, 0x5b, 0xc8, 0x03, 0xd8, 0xd7, 0xec,
+	0xb3, 0x28, 0x8b, 0xce, 0x90, 0x83, 0x4c, 0x21, 0x67, 0xd1, 0x65, 0xed, 0x6c, 0xf7, 0xe4, 0x10,
+	0x6c, 0xad, 0x78, 0x4e, 0x6a, 0x4a, 0x65, 0x96, 0xda, 0x1b, 0x47, 0xdf, 0x7a, 0x30, 0xe8, 0x14,
+	0xca, 0x29, 0xf4, 0x5b, 0x0b, 0x2f, 0xdd, 0xaa, 0x57, 0xd3, 0x53, 0xf5, 0xf6, 0x0c, 0x4c, 0x33,
+	0x45, 0x4b, 0x06, 0xb0, 0xdd, 0x5e, 0x3b, 0x59, 0xcb, 0x8d, 0x4f, 0xc6, 0xf3, 0x4c, 0xd4, 0x6f,
+	0xab, 0x29, 0xf4, 0x5b, 0xdb, 0xb6, 0x8a, 0x64, 0x5a, 0x5d, 0x6f, 0xcf, 0xc0, 0xfc, 0x19, 0xa9,
+	0x7d, 0xe9, 0xab, 0x48, 0xc6, 0x15, 0xf2, 0x3c, 0x13, 0xa5, 0xad, 0x1e, 0xbb, 0xdf, 0x17, 0xbe,
+	0xb8, 0x5e, 0xf8, 0xe2, 0xe7, 0xc2, 0x17, 0x9f, 0x97, 0xbe, 0x75, 0xbd, 0xf4, 0xad, 0x1f, 0x4b,
+	0xdf, 0x8a, 0x37, 0xeb, 0x3f, 0xee, 0xfe, 0xaf, 0x01, 0x00, 0x4d, 0x35, 0x11, 0x73, 0xf6, 0x04,
+	0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -455,6 +563,11 @@ const _ = grpc.SupportPackageIsVersion4
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type ResourceManagerClient interface {
+	// LeaseResource records the intent to create a resource before it is
+	// physically created, so that a creation interrupted by a failover of
+	// the resource manager can be reconciled. CreateResource finalizes the
+	// lease.
+	LeaseResource(ctx context.Context, in *LeaseResourceRequest, opts ...grpc.CallOption) (*LeaseResourceResponse, error)
 	CreateResource(ctx context.Context, in *CreateResourceRequest, opts ...grpc.CallOption) (*CreateResourceResponse, error)
 	QueryResource(ctx context.Context, in *QueryResourceRequest, opts ...grpc.CallOption) (*QueryResourceResponse, error)
 	// RemoveResource cleans up the metadata only of the resource.
@@ -470,6 +583,15 @@ func NewResourceManagerClient(cc *grpc.ClientConn) ResourceManagerClient {
 	return &resourceManagerClient{cc}
 }
 
+func (c *resourceManagerClient) LeaseResource(ctx context.Context, in *LeaseResourceRequest, opts ...grpc.CallOption) (*LeaseResourceResponse, error) {
+	out := new(LeaseResourceResponse)
+	err := c.cc.Invoke(ctx, "/pb.ResourceManager/LeaseResource", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *resourceManagerClient) CreateResource(ctx context.Context, in *CreateResourceRequest, opts ...grpc.CallOption) (*CreateResourceResponse, error) {
 	out := new(CreateResourceResponse)
 	err := c.cc.Invoke(ctx, "/pb.ResourceManager/CreateResource", in, out, opts...)
@@ -499,6 +621,11 @@ func (c *resourceManagerClient) RemoveResource(ctx context.Context, in *RemoveRe
 
 // ResourceManagerServer is the server API for ResourceManager service.
 type ResourceManagerServer interface {
+	// LeaseResource records the intent to create a resource before it is
+	// physically created, so that a creation interrupted by a failover of
+	// the resource manager can be reconciled. CreateResource finalizes the
+	// lease.
+	LeaseResource(context.Context, *LeaseResourceRequest) (*LeaseResourceResponse, error)
 	CreateResource(context.Context, *CreateResourceRequest) (*CreateResourceResponse, error)
 	QueryResource(context.Context, *QueryResourceRequest) (*QueryResourceResponse, error)
 	// RemoveResource cleans up the metadata only of the resource.
@@ -510,6 +637,9 @@ type ResourceManagerServer interface {
 type UnimplementedResourceManagerServer struct {
 }
 
+func (*UnimplementedResourceManagerServer) LeaseResource(ctx context.Context, req *LeaseResourceRequest) (*LeaseResourceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LeaseResource not implemented")
+}
 func (*UnimplementedResourceManagerServer) CreateResource(ctx context.Context, req *CreateResourceRequest) (*CreateResourceResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateResource not implemented")
 }
@@ -524,6 +654,24 @@ func RegisterResourceManagerServer(s *grpc.Server, srv ResourceManagerServer) {
 	s.RegisterService(&_ResourceManager_serviceDesc, srv)
 }
 
+func _ResourceManager_LeaseResource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaseResourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ResourceManagerServer).LeaseResource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.ResourceManager/LeaseResource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ResourceManagerServer).LeaseResource(ctx, req.(*LeaseResourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ResourceManager_CreateResource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CreateResourceRequest)
 	if err := dec(in); err != nil {
@@ -582,6 +730,10 @@ var _ResourceManager_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "pb.ResourceManager",
 	HandlerType: (*ResourceManagerServer)(nil),
 	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "LeaseResource",
+			Handler:    _ResourceManager_LeaseResource_Handler,
+		},
 		{
 			MethodName: "CreateResource",
 			Handler:    _ResourceManager_CreateResource_Handler,
@@ -599,6 +751,80 @@ var _ResourceManager_serviceDesc = grpc.ServiceDesc{
 	Metadata: "resources.proto",
 }
 
+func (m *LeaseResourceRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LeaseResourceRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *LeaseResourceRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.CreatorWorkerId) > 0 {
+		i -= len(m.CreatorWorkerId)
+		copy(dAtA[i:], m.CreatorWorkerId)
+		i = encodeVarintResources(dAtA, i, uint64(len(m.CreatorWorkerId)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.JobId) > 0 {
+		i -= len(m.JobId)
+		copy(dAtA[i:], m.JobId)
+		i = encodeVarintResources(dAtA, i, uint64(len(m.JobId)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.CreatorExecutor) > 0 {
+		i -= len(m.CreatorExecutor)
+		copy(dAtA[i:], m.CreatorExecutor)
+		i = encodeVarintResources(dAtA, i, uint64(len(m.CreatorExecutor)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.ResourceId) > 0 {
+		i -= len(m.ResourceId)
+		copy(dAtA[i:], m.ResourceId)
+		i = encodeVarintResources(dAtA, i, uint64(len(m.ResourceId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *LeaseResourceResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LeaseResourceResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *LeaseResourceResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
 func (m *CreateResourceRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -846,6 +1072,40 @@ func encodeVarintResources(dAtA []byte, offset int, v uint64) int {
 	dAtA[offset] = uint8(v)
 	return base
 }
+func (m *LeaseResourceRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ResourceId)
+	if l > 0 {
+		n += 1 + l + sovResources(uint64(l))
+	}
+	l = len(m.CreatorExecutor)
+	if l > 0 {
+		n += 1 + l + sovResources(uint64(l))
+	}
+	l = len(m.JobId)
+	if l > 0 {
+		n += 1 + l + sovResources(uint64(l))
+	}
+	l = len(m.CreatorWorkerId)
+	if l > 0 {
+		n += 1 + l + sovResources(uint64(l))
+	}
+	return n
+}
+
+func (m *LeaseResourceResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
 func (m *CreateResourceRequest) Size() (n int) {
 	if m == nil {
 		return 0
@@ -958,6 +1218,234 @@ func sovResources(x uint64) (n int) {
 func sozResources(x uint64) (n int) {
 	return sovResources(uint64((x << 1) ^ uint64((int64(x) >> 63))))
 }
+func (m *LeaseResourceRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowResources
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LeaseResourceRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LeaseResourceRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ResourceId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowResources
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthResources
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthResources
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ResourceId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreatorExecutor", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowResources
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthResources
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthResources
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CreatorExecutor = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field JobId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowResources
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthResources
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthResources
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.JobId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreatorWorkerId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowResources
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthResources
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthResources
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CreatorWorkerId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipResources(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthResources
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *LeaseResourceResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowResources
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LeaseResourceResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LeaseResourceResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipResources(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthResources
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *CreateResourceRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
@@ -0,0 +1,9 @@
+package pb
+
+// The service descriptions are exported for the gRPC servers created by
+// others, to which the services are registered with interceptors, see
+// interceptor.RegisterService.
+var (
+	MasterServiceDesc          = &_Master_serviceDesc
+	ResourceManagerServiceDesc = &_ResourceManager_serviceDesc
+)
@@ -22,8 +22,27 @@ var (
 
 	ResourceKeyAdapter KeyAdapter = keyHexEncoderDecoder("/data-flow/resources/")
 
+	// BarrierKeyAdapter is used to persist barriers requested by masters,
+	// BarrierReachedKeyAdapter is used to persist workers reaching them.
+	BarrierKeyAdapter        KeyAdapter = keyHexEncoderDecoder("/data-flow/barrier/")
+	BarrierReachedKeyAdapter KeyAdapter = keyHexEncoderDecoder("/data-flow/barrier-reached/")
+
+	// EffectKeyAdapter is used to persist idempotency records of side effects
+	EffectKeyAdapter KeyAdapter = keyHexEncoderDecoder("/data-flow/effect/")
+	// TxnKeyAdapter is used to persist two-phase commit transactions of jobs,
+	// TxnAckKeyAdapter is used to persist participants pre-committing them.
+	TxnKeyAdapter    KeyAdapter = keyHexEncoderDecoder("/data-flow/txn/")
+	TxnAckKeyAdapter KeyAdapter = keyHexEncoderDecoder("/data-flow/txn-ack/")
+
+	// MetaMigrationKey is used to persist the state of the online migration
+	// of the framework metastore, MetaMigrationAckKeyAdapter is used to
+	// persist executors following it.
+	MetaMigrationKey           KeyAdapter = keyHexEncoderDecoder("/data-flow/meta-migration/")
+	MetaMigrationAckKeyAdapter KeyAdapter = keyHexEncoderDecoder("/data-flow/meta-migration-ack/")
+
 	// TODO: discuss the key prefix
 	DMJobKeyAdapter KeyAdapter = keyHexEncoderDecoder("/data-flow/dm/job/")
+	DMDDLKeyAdapter KeyAdapter = keyHexEncoderDecoder("/data-flow/dm/ddl/")
 )
 
 // KeyAdapter is used to construct etcd like key
@@ -0,0 +1,129 @@
+package adminserver
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"runtime/debug"
+	runtimepprof "runtime/pprof"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+)
+
+const shutdownTimeout = 5 * time.Second
+
+// MasterDumper returns the in-memory state of the master with the given ID
+// running in this process, ok is false if the master is not found.
+type MasterDumper func(masterID string) (info interface{}, ok bool)
+
+// NewHandler returns the handler of the admin endpoints:
+//   - /debug/pprof/: the standard pprof endpoints
+//   - /debug/runtime: the runtime stats, such as the number of goroutines,
+//     the memory stats and the GC stats
+//   - /debug/goroutines: a dump of the stacks of all goroutines
+//   - /debug/jobs: the in-memory state of the WorkerManager of the master
+//     given by `master-id` in the query, if dumper is not nil
+func NewHandler(dumper MasterDumper) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("/debug/runtime", runtimeStatsHandler)
+	mux.HandleFunc("/debug/goroutines", goroutinesHandler)
+	if dumper != nil {
+		mux.HandleFunc("/debug/jobs", jobsHandler(dumper))
+	}
+	return mux
+}
+
+// Serve serves the admin endpoints on addr until ctx is done.
+func Serve(ctx context.Context, addr string, handler http.Handler) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return serve(ctx, lis, handler)
+}
+
+func serve(ctx context.Context, lis net.Listener, handler http.Handler) error {
+	srv := &http.Server{Handler: handler}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Serve(lis)
+	}()
+	log.L().Info("admin server started", zap.String("addr", lis.Addr().String()))
+
+	select {
+	case err := <-errCh:
+		return errors.Trace(err)
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.L().Warn("failed to shutdown admin server", zap.Error(err))
+	}
+	return nil
+}
+
+// RuntimeStats is the runtime stats of the process
+type RuntimeStats struct {
+	NumGoroutine int              `json:"num-goroutine"`
+	NumCPU       int              `json:"num-cpu"`
+	GOMAXPROCS   int              `json:"gomaxprocs"`
+	MemStats     runtime.MemStats `json:"mem-stats"`
+	GCStats      debug.GCStats    `json:"gc-stats"`
+}
+
+func runtimeStatsHandler(w http.ResponseWriter, _ *http.Request) {
+	stats := &RuntimeStats{
+		NumGoroutine: runtime.NumGoroutine(),
+		NumCPU:       runtime.NumCPU(),
+		GOMAXPROCS:   runtime.GOMAXPROCS(0),
+	}
+	runtime.ReadMemStats(&stats.MemStats)
+	debug.ReadGCStats(&stats.GCStats)
+	writeJSON(w, stats)
+}
+
+func goroutinesHandler(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	// debug=2 prints the stacks in the same format as a panic, with the
+	// time a goroutine has been blocked.
+	if err := runtimepprof.Lookup("goroutine").WriteTo(w, 2); err != nil {
+		log.L().Warn("failed to write goroutines", zap.Error(err))
+	}
+}
+
+func jobsHandler(dumper MasterDumper) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		masterID := r.URL.Query().Get("master-id")
+		if masterID == "" {
+			http.Error(w, "master-id is required", http.StatusBadRequest)
+			return
+		}
+		info, ok := dumper(masterID)
+		if !ok {
+			http.Error(w, "master not found in this process", http.StatusNotFound)
+			return
+		}
+		writeJSON(w, info)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		log.L().Warn("failed to write admin response", zap.Error(err))
+	}
+}
@@ -0,0 +1,75 @@
+package adminserver
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	handler := NewHandler(func(masterID string) (interface{}, bool) {
+		if masterID != "master-1" {
+			return nil, false
+		}
+		return map[string]string{"master-id": masterID}, true
+	})
+	get := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+		return w
+	}
+
+	w := get("/debug/runtime")
+	require.Equal(t, http.StatusOK, w.Code)
+	var stats RuntimeStats
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+	require.Greater(t, stats.NumGoroutine, 0)
+	require.Greater(t, stats.MemStats.Sys, uint64(0))
+
+	w = get("/debug/goroutines")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), "TestHandler")
+
+	w = get("/debug/jobs?master-id=master-1")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"master-id": "master-1"}`, w.Body.String())
+	require.Equal(t, http.StatusNotFound, get("/debug/jobs?master-id=master-2").Code)
+	require.Equal(t, http.StatusBadRequest, get("/debug/jobs").Code)
+
+	// /debug/jobs is not served without a dumper
+	w = httptest.NewRecorder()
+	NewHandler(nil).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/jobs?master-id=master-1", nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestServe(t *testing.T) {
+	t.Parallel()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- serve(ctx, lis, NewHandler(nil))
+	}()
+
+	resp, err := http.Get("http://" + lis.Addr().String() + "/debug/pprof/")
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.True(t, strings.Contains(string(body), "goroutine"))
+
+	cancel()
+	require.NoError(t, <-errCh)
+}
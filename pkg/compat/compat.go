@@ -0,0 +1,150 @@
+package compat
+
+import (
+	"sync"
+
+	"github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+// ProtocolVersion is the version of the protocol between server masters,
+// executors, masters and workers. A cluster is rolled to a new version one
+// node at a time, so nodes of adjacent versions must work together, and a
+// feature of the new version is enabled only after all nodes support it.
+type ProtocolVersion int32
+
+const (
+	// ProtocolVersionUnknown is reported by nodes released before protocol
+	// versions are negotiated, it is treated as ProtocolVersionBase.
+	ProtocolVersionUnknown ProtocolVersion = 0
+	// ProtocolVersionBase is the first version.
+	ProtocolVersionBase ProtocolVersion = 1
+	// ProtocolVersionIdleEviction adds protocol version negotiation and
+	// idle-evictable executors.
+	ProtocolVersionIdleEviction ProtocolVersion = 2
+
+	// CurrentProtocolVersion is the protocol version of this binary.
+	CurrentProtocolVersion = ProtocolVersionIdleEviction
+	// MinCompatibleProtocolVersion is the oldest version this binary works
+	// with, nodes of older versions must be upgraded first.
+	MinCompatibleProtocolVersion = CurrentProtocolVersion - 1
+	// MaxCompatibleProtocolVersion is the newest version this binary works
+	// with, which allows rolling to the next version.
+	MaxCompatibleProtocolVersion = CurrentProtocolVersion + 1
+)
+
+// Normalize returns the version a peer actually speaks.
+func (v ProtocolVersion) Normalize() ProtocolVersion {
+	if v == ProtocolVersionUnknown {
+		return ProtocolVersionBase
+	}
+	return v
+}
+
+// CheckCompatible checks that a peer of version v can work with this binary.
+func CheckCompatible(v ProtocolVersion) error {
+	v = v.Normalize()
+	if v < MinCompatibleProtocolVersion || v > MaxCompatibleProtocolVersion {
+		return errors.ErrIncompatibleProtocolVersion.GenWithStackByArgs(
+			v, MinCompatibleProtocolVersion, MaxCompatibleProtocolVersion)
+	}
+	return nil
+}
+
+// Feature is a feature that can be used only if all nodes support it.
+type Feature string
+
+// defines the features gated by protocol versions
+const (
+	// FeatureIdleEviction means executors report themselves idle-evictable,
+	// and the scheduler avoids such executors.
+	FeatureIdleEviction = Feature("idle-eviction")
+)
+
+var featureVersions = map[Feature]ProtocolVersion{
+	FeatureIdleEviction: ProtocolVersionIdleEviction,
+}
+
+// MinVersion returns the protocol version the feature is introduced in, an
+// unknown feature is never enabled.
+func (f Feature) MinVersion() ProtocolVersion {
+	v, ok := featureVersions[f]
+	if !ok {
+		return MaxCompatibleProtocolVersion + 1
+	}
+	return v
+}
+
+// FeatureEnabled returns whether the feature can be used by a cluster whose
+// nodes all speak clusterVersion or newer.
+func FeatureEnabled(f Feature, clusterVersion ProtocolVersion) bool {
+	if clusterVersion > CurrentProtocolVersion {
+		// this binary doesn't know the newer protocol
+		clusterVersion = CurrentProtocolVersion
+	}
+	return clusterVersion.Normalize() >= f.MinVersion()
+}
+
+// Gate tracks the protocol versions of the peers of a node, and negotiates
+// the cluster version, which is the oldest version of the node and its peers.
+type Gate struct {
+	mu       sync.RWMutex
+	versions map[string]ProtocolVersion
+}
+
+// NewGate creates a Gate without peers.
+func NewGate() *Gate {
+	return &Gate{
+		versions: make(map[string]ProtocolVersion),
+	}
+}
+
+// Observe records the version of a peer, it returns whether the cluster
+// version changes.
+func (g *Gate) Observe(peer string, v ProtocolVersion) (changed bool) {
+	v = v.Normalize()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if old, ok := g.versions[peer]; ok && old == v {
+		return false
+	}
+	before := g.clusterVersionLocked()
+	g.versions[peer] = v
+	return before != g.clusterVersionLocked()
+}
+
+// Remove removes a peer, it returns whether the cluster version changes.
+func (g *Gate) Remove(peer string) (changed bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, ok := g.versions[peer]; !ok {
+		return false
+	}
+	before := g.clusterVersionLocked()
+	delete(g.versions, peer)
+	return before != g.clusterVersionLocked()
+}
+
+// ClusterVersion returns the negotiated protocol version.
+func (g *Gate) ClusterVersion() ProtocolVersion {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.clusterVersionLocked()
+}
+
+func (g *Gate) clusterVersionLocked() ProtocolVersion {
+	ret := CurrentProtocolVersion
+	for _, v := range g.versions {
+		if v < ret {
+			ret = v
+		}
+	}
+	return ret
+}
+
+// Enabled returns whether the feature is supported by the node and all
+// its peers.
+func (g *Gate) Enabled(f Feature) bool {
+	return FeatureEnabled(f, g.ClusterVersion())
+}
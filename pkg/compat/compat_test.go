@@ -0,0 +1,57 @@
+package compat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+func TestCheckCompatible(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, CheckCompatible(ProtocolVersionUnknown))
+	require.NoError(t, CheckCompatible(CurrentProtocolVersion))
+	require.NoError(t, CheckCompatible(CurrentProtocolVersion+1))
+
+	err := CheckCompatible(CurrentProtocolVersion + 2)
+	require.True(t, errors.ErrIncompatibleProtocolVersion.Equal(err))
+}
+
+func TestFeatureEnabled(t *testing.T) {
+	t.Parallel()
+
+	require.False(t, FeatureEnabled(FeatureIdleEviction, ProtocolVersionUnknown))
+	require.False(t, FeatureEnabled(FeatureIdleEviction, ProtocolVersionBase))
+	require.True(t, FeatureEnabled(FeatureIdleEviction, ProtocolVersionIdleEviction))
+	require.True(t, FeatureEnabled(FeatureIdleEviction, CurrentProtocolVersion+1))
+	require.False(t, FeatureEnabled(Feature("unknown"), CurrentProtocolVersion))
+}
+
+func TestGate(t *testing.T) {
+	t.Parallel()
+
+	gate := NewGate()
+	require.Equal(t, CurrentProtocolVersion, gate.ClusterVersion())
+	require.True(t, gate.Enabled(FeatureIdleEviction))
+
+	// newer peers don't change the cluster version
+	require.False(t, gate.Observe("node-1", CurrentProtocolVersion+1))
+	require.Equal(t, CurrentProtocolVersion, gate.ClusterVersion())
+
+	// an old peer disables the new features until it is upgraded
+	require.True(t, gate.Observe("node-2", ProtocolVersionUnknown))
+	require.Equal(t, ProtocolVersionBase, gate.ClusterVersion())
+	require.False(t, gate.Enabled(FeatureIdleEviction))
+	require.False(t, gate.Observe("node-2", ProtocolVersionBase))
+
+	require.True(t, gate.Observe("node-2", CurrentProtocolVersion))
+	require.True(t, gate.Enabled(FeatureIdleEviction))
+
+	require.True(t, gate.Observe("node-3", ProtocolVersionBase))
+	require.False(t, gate.Remove("node-1"))
+	require.True(t, gate.Remove("node-3"))
+	require.False(t, gate.Remove("node-3"))
+	require.True(t, gate.Enabled(FeatureIdleEviction))
+}
@@ -0,0 +1,57 @@
+// Package connector defines the SPI of the connectors moving data in jobs.
+// A source reads records from an external system and a sink writes them into
+// another one, the offsets of the records are checkpointed so that a task
+// resumes from where it stopped. Connectors are registered by job plugins,
+// and are run by the connector task without new worker code.
+package connector
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Offset is the position of a record in its source, which is checkpointed to
+// resume reading from. It is opaque to the framework and is only interpreted
+// by the source producing it.
+type Offset string
+
+// Record is a key-value record moved from a source to a sink.
+type Record struct {
+	Key    []byte
+	Value  []byte
+	Offset Offset
+}
+
+// Source reads records from an external system.
+type Source interface {
+	// Open prepares to read the records from offset, or from the beginning
+	// if offset is empty. The record at offset may be read again, so the
+	// sinks should write records idempotently.
+	Open(ctx context.Context, offset Offset) error
+	// Read returns the next record, or io.EOF if all the records are read.
+	Read(ctx context.Context) (*Record, error)
+	Close() error
+}
+
+// Sink writes records into an external system.
+type Sink interface {
+	Open(ctx context.Context) error
+	Write(ctx context.Context, record *Record) error
+	// Flush makes the written records durable, after which the offset of
+	// the last written record can be checkpointed.
+	Flush(ctx context.Context) error
+	Close() error
+}
+
+// SourceFactory creates a Source from its config.
+type SourceFactory func(config json.RawMessage) (Source, error)
+
+// SinkFactory creates a Sink from its config.
+type SinkFactory func(config json.RawMessage) (Sink, error)
+
+// Spec specifies a connector and its config, which is decoded by the factory
+// of the connector.
+type Spec struct {
+	Type   string          `json:"type"`
+	Config json.RawMessage `json:"config,omitempty"`
+}
@@ -0,0 +1,79 @@
+package connector
+
+import (
+	"context"
+	"io"
+
+	"github.com/pingcap/errors"
+)
+
+// CheckpointFunc is called after the sink is flushed, with the offset of the
+// last durable record and the number of records written so far.
+type CheckpointFunc func(offset Offset, count int64)
+
+// Copy reads the records from offset of the source and writes them into the
+// sink, until all the records are read or an error occurs. The sink is
+// flushed every flushSize records and after the last record, checkpoint is
+// called after every flush. The source and the sink are closed before Copy
+// returns.
+func Copy(
+	ctx context.Context,
+	source Source,
+	sink Sink,
+	offset Offset,
+	flushSize int,
+	checkpoint CheckpointFunc,
+) (err error) {
+	if err := source.Open(ctx, offset); err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		if closeErr := source.Close(); err == nil {
+			err = errors.Trace(closeErr)
+		}
+	}()
+	if err := sink.Open(ctx); err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		if closeErr := sink.Close(); err == nil {
+			err = errors.Trace(closeErr)
+		}
+	}()
+
+	var (
+		count   int64
+		pending int
+	)
+	flush := func() error {
+		if err := sink.Flush(ctx); err != nil {
+			return errors.Trace(err)
+		}
+		pending = 0
+		checkpoint(offset, count)
+		return nil
+	}
+	for {
+		record, err := source.Read(ctx)
+		if err == io.EOF {
+			if pending == 0 {
+				return nil
+			}
+			return flush()
+		}
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := sink.Write(ctx, record); err != nil {
+			return errors.Trace(err)
+		}
+		offset = record.Offset
+		count++
+		pending++
+		if pending >= flushSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+}
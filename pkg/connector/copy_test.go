@@ -0,0 +1,114 @@
+package connector
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/pingcap/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type memSource struct {
+	records []*Record
+	pos     int
+	closed  bool
+}
+
+func (s *memSource) Open(ctx context.Context, offset Offset) error {
+	for i, record := range s.records {
+		if record.Offset == offset {
+			s.pos = i
+		}
+	}
+	return nil
+}
+
+func (s *memSource) Read(ctx context.Context) (*Record, error) {
+	if s.pos >= len(s.records) {
+		return nil, io.EOF
+	}
+	s.pos++
+	return s.records[s.pos-1], nil
+}
+
+func (s *memSource) Close() error {
+	s.closed = true
+	return nil
+}
+
+type memSink struct {
+	written  []*Record
+	flushed  int
+	writeErr error
+	closed   bool
+}
+
+func (s *memSink) Open(ctx context.Context) error {
+	return nil
+}
+
+func (s *memSink) Write(ctx context.Context, record *Record) error {
+	if s.writeErr != nil {
+		return s.writeErr
+	}
+	s.written = append(s.written, record)
+	return nil
+}
+
+func (s *memSink) Flush(ctx context.Context) error {
+	s.flushed = len(s.written)
+	return nil
+}
+
+func (s *memSink) Close() error {
+	s.closed = true
+	return nil
+}
+
+func newMemSource(n int) *memSource {
+	source := &memSource{}
+	for i := 0; i < n; i++ {
+		key := strconv.Itoa(i)
+		source.records = append(source.records, &Record{Key: []byte(key), Offset: Offset(key)})
+	}
+	return source
+}
+
+func TestCopy(t *testing.T) {
+	t.Parallel()
+
+	source := newMemSource(5)
+	sink := &memSink{}
+	var offsets []Offset
+	err := Copy(context.Background(), source, sink, "", 2, func(offset Offset, count int64) {
+		require.Equal(t, int64(sink.flushed), count)
+		offsets = append(offsets, offset)
+	})
+	require.NoError(t, err)
+	require.Len(t, sink.written, 5)
+	require.Equal(t, []Offset{"1", "3", "4"}, offsets)
+	require.True(t, source.closed)
+	require.True(t, sink.closed)
+
+	// resume from the checkpoint, the record at offset is read again
+	sink = &memSink{}
+	offsets = nil
+	err = Copy(context.Background(), newMemSource(5), sink, "3", 2, func(offset Offset, count int64) {
+		offsets = append(offsets, offset)
+	})
+	require.NoError(t, err)
+	require.Len(t, sink.written, 2)
+	require.Equal(t, []Offset{"4"}, offsets)
+
+	// the error of sink is returned
+	source = newMemSource(5)
+	sink = &memSink{writeErr: errors.New("write error")}
+	err = Copy(context.Background(), source, sink, "", 2, func(offset Offset, count int64) {
+		require.FailNow(t, "unexpected checkpoint")
+	})
+	require.EqualError(t, err, "write error")
+	require.True(t, source.closed)
+	require.True(t, sink.closed)
+}
@@ -19,9 +19,11 @@ import (
 
 	"github.com/hanfei1991/microcosm/client"
 	"github.com/hanfei1991/microcosm/pkg/deps"
+	"github.com/hanfei1991/microcosm/pkg/logutil"
 	extKV "github.com/hanfei1991/microcosm/pkg/meta/extension"
 	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
 	"github.com/hanfei1991/microcosm/pkg/p2p"
+	"github.com/hanfei1991/microcosm/pkg/tenant"
 	"github.com/pingcap/tiflow/dm/pkg/log"
 )
 
@@ -39,16 +41,14 @@ type Context struct {
 
 // Background return a nop context.
 func Background() *Context {
-	return &Context{
-		Context: context.Background(),
-		Logger:  log.L(),
-	}
+	return NewContext(context.Background(), log.L())
 }
 
-// NewContext return a new Context.
+// NewContext return a new Context. The logger can also be retrieved from
+// derived go contexts by logutil.FromContext.
 func NewContext(ctx context.Context, logger log.Logger) *Context {
 	return &Context{
-		Context: ctx,
+		Context: logutil.NewContext(ctx, logger),
 		Logger:  logger,
 	}
 }
@@ -56,7 +56,7 @@ func NewContext(ctx context.Context, logger log.Logger) *Context {
 // WithContext set go context.
 func (c *Context) WithContext(ctx context.Context) *Context {
 	return &Context{
-		Context: ctx,
+		Context: logutil.NewContext(ctx, c.Logger),
 		Logger:  c.Logger,
 	}
 }
@@ -73,7 +73,7 @@ func (c *Context) WithTimeout(timeout time.Duration) (*Context, context.CancelFu
 // WithLogger set logger.
 func (c *Context) WithLogger(logger log.Logger) *Context {
 	return &Context{
-		Context: c.Context,
+		Context: logutil.NewContext(c.Context, logger),
 		Logger:  logger,
 	}
 }
@@ -114,4 +114,13 @@ type Environment struct {
 	NodeID          p2p.NodeID
 	Addr            string
 	MasterMetaBytes []byte
+
+	// Env is the environment variables of the worker from the dispatch
+	// request.
+	Env map[string]string
+	// ResourceIDs are the external resources opened before the worker is
+	// initialized.
+	ResourceIDs []string
+	// ProjectInfo is the tenant and the project of the worker.
+	ProjectInfo tenant.ProjectInfo
 }
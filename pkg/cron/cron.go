@@ -0,0 +1,182 @@
+package cron
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+// Schedule is a parsed cron expression of the standard five fields:
+// minute, hour, day of month, month and day of week. A field is a list of
+// "*", single values and ranges, each may be followed by a step, such as
+// "*/15", "1-5" or "0,30". Day of week 0 and 7 are both Sunday. The
+// descriptors @yearly, @monthly, @weekly, @daily and @hourly are supported
+// as well.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	// if both day of month and day of week are restricted, a day matches
+	// either of them, which follows the behavior of the cron daemon.
+	domStar, dowStar bool
+}
+
+type bounds struct {
+	name     string
+	min, max uint
+}
+
+var (
+	minuteBounds = bounds{"minute", 0, 59}
+	hourBounds   = bounds{"hour", 0, 23}
+	domBounds    = bounds{"day of month", 1, 31}
+	monthBounds  = bounds{"month", 1, 12}
+	dowBounds    = bounds{"day of week", 0, 7}
+)
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// searchYears bounds the search of Next, an expression that can never be
+// satisfied, such as "0 0 30 2 *", stops matching after it.
+const searchYears = 5
+
+// Parse parses a cron expression.
+func Parse(expr string) (*Schedule, error) {
+	spec := strings.TrimSpace(expr)
+	if d, ok := descriptors[spec]; ok {
+		spec = d
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, errors.ErrInvalidCronExpr.GenWithStackByArgs(
+			expr, "expected 5 fields, got "+strconv.Itoa(len(fields)))
+	}
+
+	var (
+		s   Schedule
+		err error
+	)
+	if s.minute, err = parseField(expr, fields[0], minuteBounds); err != nil {
+		return nil, err
+	}
+	if s.hour, err = parseField(expr, fields[1], hourBounds); err != nil {
+		return nil, err
+	}
+	if s.dom, err = parseField(expr, fields[2], domBounds); err != nil {
+		return nil, err
+	}
+	if s.month, err = parseField(expr, fields[3], monthBounds); err != nil {
+		return nil, err
+	}
+	if s.dow, err = parseField(expr, fields[4], dowBounds); err != nil {
+		return nil, err
+	}
+	// 7 is an alias of Sunday
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.domStar = strings.HasPrefix(fields[2], "*")
+	s.dowStar = strings.HasPrefix(fields[4], "*")
+	return &s, nil
+}
+
+func parseField(expr, field string, b bounds) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, uint(1)
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.ParseUint(part[i+1:], 10, 8)
+			if err != nil || n == 0 {
+				return 0, errors.ErrInvalidCronExpr.GenWithStackByArgs(expr, "invalid step of "+b.name)
+			}
+			rangePart, step = part[:i], uint(n)
+		}
+
+		var lo, hi uint
+		switch {
+		case rangePart == "*":
+			lo, hi = b.min, b.max
+		case strings.Contains(rangePart, "-"):
+			i := strings.Index(rangePart, "-")
+			var err error
+			if lo, err = parseValue(expr, rangePart[:i], b); err != nil {
+				return 0, err
+			}
+			if hi, err = parseValue(expr, rangePart[i+1:], b); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, errors.ErrInvalidCronExpr.GenWithStackByArgs(expr, "invalid range of "+b.name)
+			}
+		default:
+			var err error
+			if lo, err = parseValue(expr, rangePart, b); err != nil {
+				return 0, err
+			}
+			hi = lo
+			// "5/10" means from 5 to the max with a step of 10
+			if step > 1 {
+				hi = b.max
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+func parseValue(expr, s string, b bounds) (uint, error) {
+	n, err := strconv.ParseUint(s, 10, 8)
+	if err != nil || uint(n) < b.min || uint(n) > b.max {
+		return 0, errors.ErrInvalidCronExpr.GenWithStackByArgs(expr, "invalid value "+s+" of "+b.name)
+	}
+	return uint(n), nil
+}
+
+// Next returns the first time matching the schedule that is strictly after
+// t, in the location of t. A zero time is returned if there is none in the
+// next few years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Year() + searchYears
+
+	for t.Year() <= limit {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *Schedule) matchDay(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
@@ -0,0 +1,61 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+func TestParseInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"@every",
+	} {
+		_, err := Parse(expr)
+		require.True(t, errors.ErrInvalidCronExpr.Equal(err), expr)
+	}
+}
+
+func TestNext(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2022, 3, 15, 10, 7, 30, 0, time.UTC) // a Tuesday
+	cases := []struct {
+		expr     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2022, 3, 15, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2022, 3, 15, 10, 15, 0, 0, time.UTC)},
+		{"7 10 * * *", time.Date(2022, 3, 16, 10, 7, 0, 0, time.UTC)},
+		{"0,30 9-17 * * *", time.Date(2022, 3, 15, 10, 30, 0, 0, time.UTC)},
+		{"5/20 * * * *", time.Date(2022, 3, 15, 10, 25, 0, 0, time.UTC)},
+		{"@daily", time.Date(2022, 3, 16, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 0", time.Date(2022, 3, 20, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2022, 3, 20, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// day of month or day of week if both are restricted
+		{"0 0 1 * 5", time.Date(2022, 3, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, c := range cases {
+		s, err := Parse(c.expr)
+		require.NoError(t, err, c.expr)
+		require.Equal(t, c.expected, s.Next(base), c.expr)
+	}
+}
@@ -3,6 +3,8 @@ package dm
 import (
 	"fmt"
 
+	"github.com/pingcap/errors"
+
 	"github.com/hanfei1991/microcosm/jobmaster/dm/metadata"
 	libModel "github.com/hanfei1991/microcosm/lib/model"
 	"github.com/hanfei1991/microcosm/pkg/p2p"
@@ -34,3 +36,50 @@ type OperateTaskMessage struct {
 	TaskID string
 	Stage  metadata.TaskStage
 }
+
+// ShardDDLTopic is the topic of the shard DDLs reported by sync workers to
+// the DM job master by BaseWorker.SendWorkerMessage.
+const ShardDDLTopic = "dm-shard-ddl"
+
+// ShardDDL is the DDLs of a shard table reported by a sync worker, the worker
+// blocks the DDLs until they are coordinated. After executing the DDLs as the
+// owner, the worker reports them again with Executed set.
+type ShardDDL struct {
+	TaskID string
+	// Table is the shard table reaching the DDLs
+	Table string
+	// Tables are all the shard tables of the task routed to the target table
+	Tables      []string
+	TargetTable string
+	DDLs        []string
+	Executed    bool
+}
+
+// Validate implements lib.WorkerMessageValidator
+func (d *ShardDDL) Validate() error {
+	if d.TaskID == "" || d.Table == "" || d.TargetTable == "" {
+		return errors.New("task, table and target table of shard DDL should not be empty")
+	}
+	if len(d.DDLs) == 0 {
+		return errors.New("DDLs of shard DDL should not be empty")
+	}
+	return nil
+}
+
+// CoordinateDDLMessageTopic is topic constructor for coordinate DDL message
+func CoordinateDDLMessageTopic(masterID libModel.MasterID, taskID string) p2p.Topic {
+	return fmt.Sprintf("coordinate-ddl-message-%s-%s", masterID, taskID)
+}
+
+// CoordinateDDLMessage is the coordinated result of the DDLs of a shard
+// table. The sync worker executes the DDLs if Exec is true or skips them,
+// and then resumes syncing. If ConflictMsg is set, the DDLs conflict with
+// the other shards, and the worker should pause the task.
+type CoordinateDDLMessage struct {
+	TaskID      string
+	Table       string
+	TargetTable string
+	DDLs        []string
+	Exec        bool
+	ConflictMsg string
+}
@@ -10,4 +10,23 @@ func TestTopic(t *testing.T) {
 	t.Parallel()
 
 	require.Equal(t, "operate-task-message-master-id-task-id", OperateTaskMessageTopic("master-id", "task-id"))
+	require.Equal(t, "coordinate-ddl-message-master-id-task-id", CoordinateDDLMessageTopic("master-id", "task-id"))
+}
+
+func TestShardDDLValidate(t *testing.T) {
+	t.Parallel()
+
+	shardDDL := &ShardDDL{
+		TaskID:      "task-id",
+		Table:       "`db`.`tb1`",
+		Tables:      []string{"`db`.`tb1`", "`db`.`tb2`"},
+		TargetTable: "`db`.`tb`",
+		DDLs:        []string{"ALTER TABLE `db`.`tb` ADD COLUMN c INT"},
+	}
+	require.NoError(t, shardDDL.Validate())
+	shardDDL.DDLs = nil
+	require.Error(t, shardDDL.Validate())
+	shardDDL.DDLs = []string{"ALTER TABLE `db`.`tb` ADD COLUMN c INT"}
+	shardDDL.TargetTable = ""
+	require.Error(t, shardDDL.Validate())
 }
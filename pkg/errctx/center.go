@@ -2,25 +2,75 @@ package errctx
 
 import (
 	"context"
+	"errors"
 	"sync"
+	"time"
 
 	"github.com/pingcap/tiflow/dm/pkg/log"
 	"go.uber.org/zap"
 )
 
+const defaultErrorHistorySize = 32
+
+// Severity classifies the errors received by ErrCenter
+type Severity int
+
+// Defines all severities
+const (
+	// SeverityFatal errors terminate the owner of the ErrCenter
+	SeverityFatal = Severity(iota)
+	// SeverityRetryable errors are only recorded in the history
+	SeverityRetryable
+)
+
+func (s Severity) String() string {
+	if s == SeverityRetryable {
+		return "retryable"
+	}
+	return "fatal"
+}
+
+// ErrorRecord is an error received by ErrCenter
+type ErrorRecord struct {
+	Time     time.Time
+	Err      error
+	Severity Severity
+}
+
+// ClassifyError returns SeverityRetryable for context errors and errors
+// implementing `IsRetryable() bool` that return true, such as metastore
+// errors, and SeverityFatal otherwise.
+func ClassifyError(err error) Severity {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return SeverityRetryable
+	}
+	var retryable interface{ IsRetryable() bool }
+	if errors.As(err, &retryable) && retryable.IsRetryable() {
+		return SeverityRetryable
+	}
+	return SeverityFatal
+}
+
 // ErrCenter is used to receive errors and provide
 // ways to detect the error(s).
 type ErrCenter struct {
 	errMu  sync.RWMutex
 	errVal error
 
+	// history is a ring of the recent errors, next is the position to
+	// write the next record.
+	history []ErrorRecord
+	next    int
+	full    bool
+
 	doneCh chan struct{}
 }
 
 // NewErrCenter creates a new ErrCenter.
 func NewErrCenter() *ErrCenter {
 	return &ErrCenter{
-		doneCh: make(chan struct{}),
+		history: make([]ErrorRecord, defaultErrorHistorySize),
+		doneCh:  make(chan struct{}),
 	}
 }
 
@@ -28,12 +78,33 @@ func NewErrCenter() *ErrCenter {
 // new error and records a warning log. Otherwise the error will be recorded and
 // doneCh will be closed to use as notification.
 func (c *ErrCenter) OnError(err error) {
+	c.OnErrorWithSeverity(err, SeverityFatal)
+}
+
+// OnErrorWithSeverity receives an error with the given severity. All errors
+// are recorded in the history, but only the first fatal error is returned by
+// CheckError and cancels the error contexts.
+func (c *ErrCenter) OnErrorWithSeverity(err error, severity Severity) {
 	c.errMu.Lock()
 	defer c.errMu.Unlock()
 
 	if err == nil {
 		return
 	}
+	c.history[c.next] = ErrorRecord{
+		Time:     time.Now(),
+		Err:      err,
+		Severity: severity,
+	}
+	c.next = (c.next + 1) % len(c.history)
+	if c.next == 0 {
+		c.full = true
+	}
+
+	if severity == SeverityRetryable {
+		log.L().Warn("Retryable error is received", zap.Error(err))
+		return
+	}
 	if c.errVal != nil {
 		// OnError is no-op after the first call with
 		// a non-nil error.
@@ -46,6 +117,22 @@ func (c *ErrCenter) OnError(err error) {
 	close(c.doneCh)
 }
 
+// History returns the recent errors in the order they are received
+func (c *ErrCenter) History() []ErrorRecord {
+	c.errMu.RLock()
+	defer c.errMu.RUnlock()
+
+	if !c.full {
+		ret := make([]ErrorRecord, c.next)
+		copy(ret, c.history[:c.next])
+		return ret
+	}
+	ret := make([]ErrorRecord, 0, len(c.history))
+	ret = append(ret, c.history[c.next:]...)
+	ret = append(ret, c.history[:c.next]...)
+	return ret
+}
+
 // CheckError retusn the recorded error
 func (c *ErrCenter) CheckError() error {
 	c.errMu.RLock()
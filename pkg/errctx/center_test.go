@@ -2,6 +2,7 @@ package errctx
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"testing"
 
@@ -91,3 +92,41 @@ func TestErrCtxDoneChSame(t *testing.T) {
 
 	require.Equal(t, doneCh1, doneCh2)
 }
+
+type retryableError struct{}
+
+func (e *retryableError) Error() string {
+	return "retryable error"
+}
+
+func (e *retryableError) IsRetryable() bool {
+	return true
+}
+
+func TestErrCenterHistory(t *testing.T) {
+	center := NewErrCenter()
+
+	require.Equal(t, SeverityRetryable, ClassifyError(errors.Trace(context.Canceled)))
+	require.Equal(t, SeverityRetryable, ClassifyError(errors.Trace(&retryableError{})))
+	require.Equal(t, SeverityFatal, ClassifyError(errors.New("fake error")))
+
+	center.OnErrorWithSeverity(&retryableError{}, SeverityRetryable)
+	require.NoError(t, center.CheckError())
+	center.OnError(errors.New("fake error"))
+	require.Regexp(t, ".*fake error.*", center.CheckError())
+
+	history := center.History()
+	require.Len(t, history, 2)
+	require.Equal(t, SeverityRetryable, history[0].Severity)
+	require.Equal(t, SeverityFatal, history[1].Severity)
+
+	for i := 0; i < defaultErrorHistorySize; i++ {
+		center.OnErrorWithSeverity(errors.Errorf("error %d", i), SeverityRetryable)
+	}
+	history = center.History()
+	require.Len(t, history, defaultErrorHistorySize)
+	require.Regexp(t, "error 0", history[0].Err)
+	require.Regexp(t, fmt.Sprintf("error %d", defaultErrorHistorySize-1), history[defaultErrorHistorySize-1].Err)
+	// the first fatal error is kept
+	require.Regexp(t, ".*fake error.*", center.CheckError())
+}
@@ -25,8 +25,13 @@ var (
 	ErrSubJobFailed             = errors.Normalize("executor %s job %d", errors.RFCCodeText("DFLOW:ErrSubJobFailed"))
 	ErrClusterResourceNotEnough = errors.Normalize("cluster resource is not enough, please scale out the cluster", errors.RFCCodeText("DFLOW:ErrClusterResourceNotEnough"))
 	ErrBuildJobFailed           = errors.Normalize("build job failed", errors.RFCCodeText("DFLOW:ErrBuildJobFailed"))
+	ErrJobNotReserved           = errors.Normalize("job %s has no resource reservation", errors.RFCCodeText("DFLOW:ErrJobNotReserved"))
+	ErrJobMaxWorkersExceeded    = errors.Normalize("job %s has reached its max workers %d", errors.RFCCodeText("DFLOW:ErrJobMaxWorkersExceeded"))
+	ErrInvalidLabelSelector     = errors.Normalize("invalid label selector: %s", errors.RFCCodeText("DFLOW:ErrInvalidLabelSelector"))
 
 	ErrExecutorDupRegister   = errors.Normalize("executor %s has been registered", errors.RFCCodeText("DFLOW:ErrExecutorDupRegister"))
+	ErrExecutorBlacklisted   = errors.Normalize("executor %s is blacklisted", errors.RFCCodeText("DFLOW:ErrExecutorBlacklisted"))
+	ErrInvalidExecutorCordon = errors.Normalize("invalid executor cordon: %s", errors.RFCCodeText("DFLOW:ErrInvalidExecutorCordon"))
 	ErrGrpcBuildConn         = errors.Normalize("dial grpc connection to %s failed", errors.RFCCodeText("DFLOW:ErrGrpcBuildConn"))
 	ErrDecodeEtcdKeyFail     = errors.Normalize("failed to decode etcd key: %s", errors.RFCCodeText("DFLOW:ErrDecodeEtcdKeyFail"))
 	ErrDecodeEtcdValueFail   = errors.Normalize("failed to decode etcd value: %s", errors.RFCCodeText("DFLOW:ErrDecodeEtcdValueFail"))
@@ -35,6 +40,8 @@ var (
 	ErrEtcdAPIError          = errors.Normalize("etcd api returns error", errors.RFCCodeText("DFLOW:ErrEtcdAPIError"))
 	ErrNoRPCClient           = errors.Normalize("no available RPC client", errors.RFCCodeText("DFLOW:ErrNoRPCClient"))
 
+	ErrIncompatibleProtocolVersion = errors.Normalize("protocol version %d is incompatible, compatible versions are [%d, %d]", errors.RFCCodeText("DFLOW:ErrIncompatibleProtocolVersion"))
+
 	// master related errors
 	ErrMasterConfigParseFlagSet       = errors.Normalize("parse config flag set failed", errors.RFCCodeText("DFLOW:ErrMasterConfigParseFlagSet"))
 	ErrMasterConfigInvalidFlag        = errors.Normalize("'%s' is an invalid flag", errors.RFCCodeText("DFLOW:ErrMasterConfigInvalidFlag"))
@@ -45,6 +52,10 @@ var (
 	ErrMasterStartEmbedEtcdFail       = errors.Normalize("failed to start embed etcd", errors.RFCCodeText("DFLOW:ErrMasterStartEmbedEtcdFail"))
 	ErrMasterParseURLFail             = errors.Normalize("failed to parse URL %s", errors.RFCCodeText("DFLOW:ErrMasterParseURLFail"))
 	ErrMasterScheduleMissTask         = errors.Normalize("task %d is not found after scheduling", errors.RFCCodeText("DFLOW:ErrMasterScheduleMissTask"))
+	ErrMasterScheduleInvalidRequest   = errors.Normalize("invalid schedule request: %s", errors.RFCCodeText("DFLOW:ErrMasterScheduleInvalidRequest"))
+	ErrScheduleQueueTimeout           = errors.Normalize("task %s is not scheduled before the wait deadline", errors.RFCCodeText("DFLOW:ErrScheduleQueueTimeout"))
+	ErrScheduleQueueFull              = errors.Normalize("schedule queue is full, max queued requests %d", errors.RFCCodeText("DFLOW:ErrScheduleQueueFull"))
+	ErrScheduleQueueInvalidConfig     = errors.Normalize("invalid schedule queue config: %s", errors.RFCCodeText("DFLOW:ErrScheduleQueueInvalidConfig"))
 	ErrMasterNewServer                = errors.Normalize("master create new server failed", errors.RFCCodeText("DFLOW:ErrMasterNewServer"))
 	ErrMasterCampaignLeader           = errors.Normalize("master campaign to be leader failed", errors.RFCCodeText("DFLOW:ErrMasterCampaignLeader"))
 	ErrMasterSessionDone              = errors.Normalize("master session is done", errors.RFCCodeText("DFLOW:ErrMasterSessionDone"))
@@ -58,10 +69,35 @@ var (
 	ErrMasterInvalidMeta              = errors.Normalize("invalid master meta data: %s", errors.RFCCodeText("DFLOW:ErrMasterInvalidMeta"))
 	ErrInvalidServerMasterID          = errors.Normalize("invalid server master id: %s", errors.RFCCodeText("DFLOW:ErrInvalidServerMasterID"))
 	ErrInvalidMasterMessage           = errors.Normalize("invalid master message: %s", errors.RFCCodeText("DFLOW:ErrInvalidMasterMessage"))
+	ErrInvalidWorkerMessage           = errors.Normalize("invalid worker message on topic %s", errors.RFCCodeText("DFLOW:ErrInvalidWorkerMessage"))
+	ErrInvalidTimeoutConfig           = errors.Normalize("invalid timeout config: %s", errors.RFCCodeText("DFLOW:ErrInvalidTimeoutConfig"))
+	ErrInvalidLogLevel                = errors.Normalize("invalid log level: %s", errors.RFCCodeText("DFLOW:ErrInvalidLogLevel"))
+	ErrInvalidTaskOperation           = errors.Normalize("invalid task operation: %s", errors.RFCCodeText("DFLOW:ErrInvalidTaskOperation"))
+	ErrInvalidSourceUpdate            = errors.Normalize("invalid source update: %s", errors.RFCCodeText("DFLOW:ErrInvalidSourceUpdate"))
+	ErrWorkerMessageTopicDuplicated   = errors.Normalize("worker message handler is registered more than once: topic %s", errors.RFCCodeText("DFLOW:ErrWorkerMessageTopicDuplicated"))
 	ErrSendingMessageToTombstone      = errors.Normalize("trying to send message to a tombstone worker handle: %s", errors.RFCCodeText("DFLOW:ErrSendingMessageToTombstone"))
 	ErrMasterNotInitialized           = errors.Normalize("master is not initialized", errors.RFCCodeText("DFLOW:ErrMasterNotInitialized"))
+	ErrMasterTickOverrun              = errors.Normalize("master tick has overrun %s for %d consecutive times", errors.RFCCodeText("DFLOW:ErrMasterTickOverrun"))
+	ErrMasterDependencyUnhealthy      = errors.Normalize("dependency of master is unhealthy: %s", errors.RFCCodeText("DFLOW:ErrMasterDependencyUnhealthy"))
+	ErrBarrierDuplicated              = errors.Normalize("barrier is requested more than once: %s", errors.RFCCodeText("DFLOW:ErrBarrierDuplicated"))
+	ErrTxnDuplicated                  = errors.Normalize("transaction of checkpoint %d is started more than once", errors.RFCCodeText("DFLOW:ErrTxnDuplicated"))
+	ErrTxnNotFound                    = errors.Normalize("transaction of checkpoint %d is not found", errors.RFCCodeText("DFLOW:ErrTxnNotFound"))
+	ErrTxnDecided                     = errors.Normalize("transaction of checkpoint %d has been decided to %s", errors.RFCCodeText("DFLOW:ErrTxnDecided"))
+	ErrEffectStaleEpoch               = errors.Normalize("side effect %s has been recorded by a newer epoch %d, current epoch %d", errors.RFCCodeText("DFLOW:ErrEffectStaleEpoch"))
+	ErrJobDAGInvalid                  = errors.Normalize("invalid job DAG: %s", errors.RFCCodeText("DFLOW:ErrJobDAGInvalid"))
+	ErrJobDAGStarted                  = errors.Normalize("job DAG has been started", errors.RFCCodeText("DFLOW:ErrJobDAGStarted"))
+	ErrCapabilityNotSupported         = errors.Normalize("capability %s is not supported by %s", errors.RFCCodeText("DFLOW:ErrCapabilityNotSupported"))
+	ErrPeerNotFound                   = errors.Normalize("peer worker %s is not found or not online", errors.RFCCodeText("DFLOW:ErrPeerNotFound"))
+	ErrChannelDuplicated              = errors.Normalize("data channel is opened more than once: %s", errors.RFCCodeText("DFLOW:ErrChannelDuplicated"))
+	ErrChannelChunkLost               = errors.Normalize("chunks of data channel %s are lost, expected seq %d, got %d", errors.RFCCodeText("DFLOW:ErrChannelChunkLost"))
+	ErrChannelWindowExceeded          = errors.Normalize("receiving window of data channel %s is exceeded", errors.RFCCodeText("DFLOW:ErrChannelWindowExceeded"))
+	ErrChannelClosed                  = errors.Normalize("data channel %s is closed", errors.RFCCodeText("DFLOW:ErrChannelClosed"))
 
 	ErrWorkerTypeNotFound         = errors.Normalize("worker type is not found: type %d", errors.RFCCodeText("DFLOW:ErrWorkerTypeNotFound"))
+	ErrWorkerTypeDuplicated       = errors.Normalize("worker type is registered more than once: type %d", errors.RFCCodeText("DFLOW:ErrWorkerTypeDuplicated"))
+	ErrLoadPluginFailed           = errors.Normalize("failed to load job plugin %s: %s", errors.RFCCodeText("DFLOW:ErrLoadPluginFailed"))
+	ErrConnectorNotFound          = errors.Normalize("%s connector is not found: type %s", errors.RFCCodeText("DFLOW:ErrConnectorNotFound"))
+	ErrConnectorDuplicated        = errors.Normalize("%s connector is registered more than once: type %s", errors.RFCCodeText("DFLOW:ErrConnectorDuplicated"))
 	ErrWorkerNotFound             = errors.Normalize("worker is not found: worker ID %s", errors.RFCCodeText("DFLOW:ErrWorkerNotFound"))
 	ErrWorkerOffline              = errors.Normalize("worker is offline: workerID: %s, error message: %s", errors.RFCCodeText("DFLOW:ErrWorkerOffline"))
 	ErrWorkerTimedOut             = errors.Normalize("worker heartbeat timed out: workerID %s", errors.RFCCodeText("DFLOW:ErrWorkerTimedOut"))
@@ -73,13 +109,21 @@ var (
 	ErrWorkerStop                 = errors.Normalize("worker is stopped", errors.RFCCodeText("DFLOW:ErrWorkerStop"))
 	ErrTooManyStatusUpdates       = errors.Normalize("there are too many pending worker status updates: %d", errors.RFCCodeText("DFLOW:ErrTooManyStatusUpdates"))
 	ErrWorkerHalfExit             = errors.Normalize("the worker is in half-exited state", errors.RFCCodeText("DFLOW:ErrWorkerHalfExit"))
+	ErrWorkerStuck                = errors.Normalize("worker is stuck in Tick: workerID %s", errors.RFCCodeText("DFLOW:ErrWorkerStuck"))
+	ErrDecodeWorkerStatus         = errors.Normalize("failed to decode status of worker %s", errors.RFCCodeText("DFLOW:ErrDecodeWorkerStatus"))
+	ErrTooManySelfMessages        = errors.Normalize("there are too many pending self messages: %d", errors.RFCCodeText("DFLOW:ErrTooManySelfMessages"))
+	ErrWorkerIllegalTransition    = errors.Normalize("illegal transition of worker %s from %s to %s", errors.RFCCodeText("DFLOW:ErrWorkerIllegalTransition"))
+	ErrWorkerStatusNotTerminal    = errors.Normalize("status code %d of worker is not terminal", errors.RFCCodeText("DFLOW:ErrWorkerStatusNotTerminal"))
+	ErrWorkerStatusNotPersisted   = errors.Normalize("terminal status of worker %s is not persisted", errors.RFCCodeText("DFLOW:ErrWorkerStatusNotPersisted"))
 
 	// master etcd related errors
 	ErrMasterEtcdCreateSessionFail    = errors.Normalize("failed to create Etcd session", errors.RFCCodeText("DFLOW:ErrMasterEtcdCreateSessionFail"))
 	ErrMasterEtcdElectionCampaignFail = errors.Normalize("failed to campaign for leader", errors.RFCCodeText("DFLOW:ErrMasterEtcdElectionCampaignFail"))
 	ErrMasterNoLeader                 = errors.Normalize("server master has no leader", errors.RFCCodeText("DFLOW:ErrMasterNoLeader"))
 	ErrEtcdLeaderChanged              = errors.Normalize("etcd leader has changed", errors.RFCCodeText("DFLOW:ErrEtcdLeaderChanged"))
-	ErrDiscoveryDuplicateWatch        = errors.Normalize("service discovery can't be watched multiple times", errors.RFCCodeText("DFLOW:ErrDiscoveryDuplicateWatch"))
+	ErrDiscoveryWatchClosed           = errors.Normalize("service discovery watch is closed unexpectedly", errors.RFCCodeText("DFLOW:ErrDiscoveryWatchClosed"))
+	ErrDiscoveryDNSLookupFail         = errors.Normalize("service discovery failed to look up dns srv records of %s", errors.RFCCodeText("DFLOW:ErrDiscoveryDNSLookupFail"))
+	ErrDiscoveryInvalidStaticFile     = errors.Normalize("service discovery failed to load static file %s", errors.RFCCodeText("DFLOW:ErrDiscoveryInvalidStaticFile"))
 	ErrMasterEtcdEpochFail            = errors.Normalize("server master generate epoch fail", errors.RFCCodeText("DFLOW:ErrMasterEtcdEpochFail"))
 
 	// executor related errors
@@ -87,6 +131,7 @@ var (
 	ErrExecutorConfigInvalidFlag  = errors.Normalize("'%s' is an invalid flag", errors.RFCCodeText("DFLOW:ErrExecutorConfigInvalidFlag"))
 	ErrExecutorDecodeConfigFile   = errors.Normalize("decode config file failed", errors.RFCCodeText("DFLOW:ErrExecutorDecodeConfigFile"))
 	ErrExecutorConfigUnknownItem  = errors.Normalize("master config contains unknown configuration options: %s", errors.RFCCodeText("DFLOW:ErrExecutorConfigUnknownItem"))
+	ErrExecutorBootstrapFailed    = errors.Normalize("executor bootstrap in %s mode failed: %s", errors.RFCCodeText("DFLOW:ErrExecutorBootstrapFailed"))
 	ErrHeartbeat                  = errors.Normalize("heartbeat error type: %s", errors.RFCCodeText("DFLOW:ErrHeartbeat"))
 	ErrTaskNotFound               = errors.Normalize("task %d is not found", errors.RFCCodeText("DFLOW:ErrTaskNotFound"))
 	ErrExecutorUnknownOperator    = errors.Normalize("operator type %d is unknown", errors.RFCCodeText("DFLOW:ErrOperatorUnknown"))
@@ -100,28 +145,93 @@ var (
 	ErrExecutorEtcdConnFail       = errors.Normalize("executor conn inner etcd fail", errors.RFCCodeText("DFLOW:ErrExecutorEtcdConnFail"))
 	ErrExecutorNotFoundForMessage = errors.Normalize("cannot find the executor for p2p messaging", errors.RFCCodeText("DFLOW:ErrExecutorNotFoundForMessage"))
 	ErrMasterTooManyPendingEvents = errors.Normalize("master has too many pending events", errors.RFCCodeText("DFLOW:ErrMasterTooManyPendingEvents"))
+	ErrExecutorSelfTestFailed     = errors.Normalize("executor self-test failed: %s", errors.RFCCodeText("DFLOW:ErrExecutorSelfTestFailed"))
+
+	// worker process related errors
+	ErrWorkerProcessExited   = errors.Normalize("worker process of %s exited: %s", errors.RFCCodeText("DFLOW:ErrWorkerProcessExited"))
+	ErrWorkerProcessProtocol = errors.Normalize("unexpected frame from worker process back-channel: %s", errors.RFCCodeText("DFLOW:ErrWorkerProcessProtocol"))
+
+	// traffic accounting related errors
+	ErrJobTrafficExceedSoftLimit = errors.Normalize("traffic of job %s exceeds soft limit %d bytes", errors.RFCCodeText("DFLOW:ErrJobTrafficExceedSoftLimit"))
+
+	// partition related errors
+	ErrPartitionSpecInvalid = errors.Normalize("invalid partition spec: %s", errors.RFCCodeText("DFLOW:ErrPartitionSpecInvalid"))
+	ErrPartitionKeyNotFound = errors.Normalize("key %q is not in any partition", errors.RFCCodeText("DFLOW:ErrPartitionKeyNotFound"))
+
+	// job deletion related errors
+	ErrJobNotFound      = errors.Normalize("job is not found: job ID %s", errors.RFCCodeText("DFLOW:ErrJobNotFound"))
+	ErrJobNotTerminated = errors.Normalize("job %s is not finished or stopped, status %d", errors.RFCCodeText("DFLOW:ErrJobNotTerminated"))
+
+	// job retention related errors
+	ErrJobRetentionInvalidConfig = errors.Normalize("job retention config is invalid: %s", errors.RFCCodeText("DFLOW:ErrJobRetentionInvalidConfig"))
+
+	// job schedule related errors
+	ErrInvalidCronExpr     = errors.Normalize("invalid cron expression %q: %s", errors.RFCCodeText("DFLOW:ErrInvalidCronExpr"))
+	ErrJobScheduleNotFound = errors.Normalize("job schedule is not found: schedule ID %s", errors.RFCCodeText("DFLOW:ErrJobScheduleNotFound"))
+
+	// job template related errors
+	ErrJobTemplateInvalid  = errors.Normalize("invalid job template: %s", errors.RFCCodeText("DFLOW:ErrJobTemplateInvalid"))
+	ErrJobTemplateParams   = errors.Normalize("invalid job template parameters: %s", errors.RFCCodeText("DFLOW:ErrJobTemplateParams"))
+	ErrJobTemplateNotFound = errors.Normalize("job template is not found: template ID %s", errors.RFCCodeText("DFLOW:ErrJobTemplateNotFound"))
 
 	// Two-Phase Task Dispatching errors
 	ErrExecutorPreDispatchFailed     = errors.Normalize("PreDispatchTask failed", errors.RFCCodeText("DFLOW:ErrExecutorPreDispatchFailed"))
 	ErrExecutorConfirmDispatchFailed = errors.Normalize("ConfirmDispatch failed", errors.RFCCodeText("DFLOW:ErrExecutorConfirmDispatchFailed"))
+	ErrExecutorDispatchQueueFull     = errors.Normalize("dispatch queue of the executor is full", errors.RFCCodeText("DFLOW:ErrExecutorDispatchQueueFull"))
+	ErrExecutorCircuitOpen           = errors.Normalize("executor %s keeps failing, requests are rejected for a while", errors.RFCCodeText("DFLOW:ErrExecutorCircuitOpen"))
 
 	// planner related errors
 	ErrPlannerDAGDepthExceeded = errors.Normalize("dag depth exceeded: %d", errors.RFCCodeText("DFLOW:ErrPlannerDAGDepthExceeded"))
 
 	// meta related errors
-	ErrMetaNewClientFail      = errors.Normalize("create meta client fail", errors.RFCCodeText("DFLOW:ErrMetaNewClientFail"))
-	ErrMetaOpFail             = errors.Normalize("meta operation fail", errors.RFCCodeText("DFLOW:ErrMetaOpFail"))
-	ErrMetaOptionInvalid      = errors.Normalize("meta option invalid", errors.RFCCodeText("DFLOW:ErrMetaOptionInvalid"))
-	ErrMetaOptionConflict     = errors.Normalize("WithRange/WithPrefix/WithFromKey, more than one option are used", errors.RFCCodeText("DFLOW:ErrMetaOptionConflict"))
-	ErrMetaEmptyKey           = errors.Normalize("meta empty key", errors.RFCCodeText("DFLOW:ErrMetaEmptyKey"))
-	ErrMetaRevisionUnmatch    = errors.Normalize("meta revision unmatch", errors.RFCCodeText("DFLOW:ErrMetaRevisionUnmatch"))
-	ErrMetaNestedTxn          = errors.Normalize("meta unsupported nested txn", errors.RFCCodeText("DFLOW:ErrMetaNestedTxn"))
-	ErrMetaCommittedTxn       = errors.Normalize("meta already committed txn", errors.RFCCodeText("DFLOW:ErrMetaCommittedTxn"))
-	ErrMetaStoreIDDuplicate   = errors.Normalize("metastore id duplicated", errors.RFCCodeText("DFLOW:ErrMetaStoreIDDuplicate"))
-	ErrMetaStoreUnfounded     = errors.Normalize("metastore unfounded:%s", errors.RFCCodeText("DFLOW:ErrMetaStoreUnfounded"))
-	ErrMetaEntryNotFound      = errors.Normalize("meta entry not found", errors.RFCCodeText("DFLOW:ErrMetaEntryNotFound"))
-	ErrMetaParamsInvalid      = errors.Normalize("meta params invalid:%s", errors.RFCCodeText("DFLOW:ErrMetaParamsInvalid"))
-	ErrMetaEntryAlreadyExists = errors.Normalize("meta entry already exists", errors.RFCCodeText("DFLOW:ErrMetaEntryAlreadyExists"))
+	ErrMetaNewClientFail           = errors.Normalize("create meta client fail", errors.RFCCodeText("DFLOW:ErrMetaNewClientFail"))
+	ErrMetaOpFail                  = errors.Normalize("meta operation fail", errors.RFCCodeText("DFLOW:ErrMetaOpFail"))
+	ErrMetaOptionInvalid           = errors.Normalize("meta option invalid", errors.RFCCodeText("DFLOW:ErrMetaOptionInvalid"))
+	ErrMetaOptionConflict          = errors.Normalize("WithRange/WithPrefix/WithFromKey, more than one option are used", errors.RFCCodeText("DFLOW:ErrMetaOptionConflict"))
+	ErrMetaEmptyKey                = errors.Normalize("meta empty key", errors.RFCCodeText("DFLOW:ErrMetaEmptyKey"))
+	ErrMetaRevisionUnmatch         = errors.Normalize("meta revision unmatch", errors.RFCCodeText("DFLOW:ErrMetaRevisionUnmatch"))
+	ErrMetaNestedTxn               = errors.Normalize("meta unsupported nested txn", errors.RFCCodeText("DFLOW:ErrMetaNestedTxn"))
+	ErrMetaCommittedTxn            = errors.Normalize("meta already committed txn", errors.RFCCodeText("DFLOW:ErrMetaCommittedTxn"))
+	ErrMetaStoreIDDuplicate        = errors.Normalize("metastore id duplicated", errors.RFCCodeText("DFLOW:ErrMetaStoreIDDuplicate"))
+	ErrMetaStoreUnfounded          = errors.Normalize("metastore unfounded:%s", errors.RFCCodeText("DFLOW:ErrMetaStoreUnfounded"))
+	ErrMetaEntryNotFound           = errors.Normalize("meta entry not found", errors.RFCCodeText("DFLOW:ErrMetaEntryNotFound"))
+	ErrMetaParamsInvalid           = errors.Normalize("meta params invalid:%s", errors.RFCCodeText("DFLOW:ErrMetaParamsInvalid"))
+	ErrMetaEntryAlreadyExists      = errors.Normalize("meta entry already exists", errors.RFCCodeText("DFLOW:ErrMetaEntryAlreadyExists"))
+	ErrMetaLeaderFenced            = errors.Normalize("meta write with fencing token %d is rejected, a newer leader has written with token %d", errors.RFCCodeText("DFLOW:ErrMetaLeaderFenced"))
+	ErrMetaWriteFaultInjected      = errors.Normalize("meta write of %s fails by fault injection", errors.RFCCodeText("DFLOW:ErrMetaWriteFaultInjected"))
+	ErrMetaSnapshotInvalid         = errors.Normalize("metadata snapshot is invalid: %s", errors.RFCCodeText("DFLOW:ErrMetaSnapshotInvalid"))
+	ErrMetaImportNotEmpty          = errors.Normalize("metastore is not empty, job %s is not in the snapshot", errors.RFCCodeText("DFLOW:ErrMetaImportNotEmpty"))
+	ErrMetaMigrationPhase          = errors.Normalize("metastore migration is in phase %s, cannot %s", errors.RFCCodeText("DFLOW:ErrMetaMigrationPhase"))
+	ErrMetaMigrationDiverged       = errors.Normalize("metastores still have %d mismatched records after repair", errors.RFCCodeText("DFLOW:ErrMetaMigrationDiverged"))
+	ErrMetaMigrationFenceTimeout   = errors.Normalize("executors %v have not fenced their writes in time", errors.RFCCodeText("DFLOW:ErrMetaMigrationFenceTimeout"))
+	ErrMetaMigrationLagging        = errors.Normalize("metastore switched, but executors %v have not reconnected in time", errors.RFCCodeText("DFLOW:ErrMetaMigrationLagging"))
+	ErrMetaEncryptionInvalidConfig = errors.Normalize("metastore encryption config is invalid: %s", errors.RFCCodeText("DFLOW:ErrMetaEncryptionInvalidConfig"))
+	ErrMetaEncryptionKeyNotFound   = errors.Normalize("encryption key %d of tenant %s is not found", errors.RFCCodeText("DFLOW:ErrMetaEncryptionKeyNotFound"))
+	ErrMetaDecryptFailed           = errors.Normalize("failed to decrypt the value of key %s: %s", errors.RFCCodeText("DFLOW:ErrMetaDecryptFailed"))
+
+	// sink related errors
+	ErrSinkInvalidConfig = errors.Normalize("sink config is invalid: %s", errors.RFCCodeText("DFLOW:ErrSinkInvalidConfig"))
+	ErrSinkWriteFailed   = errors.Normalize("writing events to %s sink failed", errors.RFCCodeText("DFLOW:ErrSinkWriteFailed"))
+
+	// secret related errors
+	ErrSecretInvalidConfig    = errors.Normalize("secret config is invalid: %s", errors.RFCCodeText("DFLOW:ErrSecretInvalidConfig"))
+	ErrSecretNotEnabled       = errors.Normalize("secrets are not enabled, the secret key is not configured", errors.RFCCodeText("DFLOW:ErrSecretNotEnabled"))
+	ErrSecretInvalidName      = errors.Normalize("invalid secret name: %s", errors.RFCCodeText("DFLOW:ErrSecretInvalidName"))
+	ErrSecretNotFound         = errors.Normalize("secret %s is not found in project %s", errors.RFCCodeText("DFLOW:ErrSecretNotFound"))
+	ErrSecretDecryptFailed    = errors.Normalize("failed to decrypt secret %s", errors.RFCCodeText("DFLOW:ErrSecretDecryptFailed"))
+	ErrSecretUnknownReference = errors.Normalize("unknown secret reference scheme: %s", errors.RFCCodeText("DFLOW:ErrSecretUnknownReference"))
+
+	// autoscaler related errors
+	ErrAutoscalerInvalidConfig  = errors.Normalize("autoscaler config is invalid: %s", errors.RFCCodeText("DFLOW:ErrAutoscalerInvalidConfig"))
+	ErrAutoscalerScaleOutFailed = errors.Normalize("scaling out executors by %s autoscaler failed", errors.RFCCodeText("DFLOW:ErrAutoscalerScaleOutFailed"))
+
+	// gRPC interceptor related errors
+	ErrInterceptorInvalidConfig = errors.Normalize("grpc interceptor config is invalid: %s", errors.RFCCodeText("DFLOW:ErrInterceptorInvalidConfig"))
+
+	// p2p message related errors
+	ErrMessageInvalidConfig = errors.Normalize("p2p message config is invalid: %s", errors.RFCCodeText("DFLOW:ErrMessageInvalidConfig"))
+	ErrMessageTooLarge      = errors.Normalize("message on topic %s is too large: %d bytes, the limit is %d bytes", errors.RFCCodeText("DFLOW:ErrMessageTooLarge"))
+	ErrMessageDecodeFailed  = errors.Normalize("failed to decode message on topic %s: %s", errors.RFCCodeText("DFLOW:ErrMessageDecodeFailed"))
 
 	// DataSet errors
 	ErrDatasetEntryNotFound = errors.Normalize("dataset entry not found. Key: %s", errors.RFCCodeText("DFLOW:ErrDatasetEntryNotFound"))
@@ -137,4 +247,8 @@ var (
 	ErrCleaningLocalTempFiles         = errors.Normalize("errors is encountered when cleaning local temp files", errors.RFCCodeText("DFLOW:ErrCleaningLocalTempFiles"))
 	ErrRemovingLocalResource          = errors.Normalize("removing a local resource file directory has failed", errors.RFCCodeText("DFLOW:ErrRemovingLocalResource"))
 	ErrFailToCreateExternalStorage    = errors.Normalize("failed to create external storage", errors.RFCCodeText("DFLOW:ErrFailToCreateExternalStorage"))
+	ErrResourceAccessDenied           = errors.Normalize("resource %s belongs to job %s, it can't be opened by job %s", errors.RFCCodeText("DFLOW:ErrResourceAccessDenied"))
+	ErrResourceNotLocal               = errors.Normalize("resource %s is on executor %s, not on this executor", errors.RFCCodeText("DFLOW:ErrResourceNotLocal"))
+	ErrResourceReadOnly               = errors.Normalize("resource %s is opened in read-only mode", errors.RFCCodeText("DFLOW:ErrResourceReadOnly"))
+	ErrLocalResourceQuotaExceeded     = errors.Normalize("local file resources of %s would use %d bytes, exceeding the quota of %d bytes", errors.RFCCodeText("DFLOW:ErrLocalResourceQuotaExceeded"))
 )
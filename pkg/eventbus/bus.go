@@ -0,0 +1,104 @@
+package eventbus
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/hanfei1991/microcosm/pkg/notifier"
+)
+
+// Bus delivers the events published by a component to the subscribers in
+// other subsystems, so that the publisher doesn't need to know who is
+// interested in the events. The events of each type are delivered by a
+// notifier, in the order they are published.
+// A nil Bus is valid, it drops all events and has no subscribers.
+type Bus struct {
+	mu sync.Mutex
+	// notifiers is reflect.Type of the event -> *notifier.Notifier[T]
+	notifiers map[reflect.Type]any
+	closed    bool
+
+	wg sync.WaitGroup
+}
+
+// NewBus creates a new Bus.
+func NewBus() *Bus {
+	return &Bus{
+		notifiers: make(map[reflect.Type]any),
+	}
+}
+
+// getNotifier returns the notifier of the events of type T, the caller must
+// hold b.mu.
+func getNotifier[T Event](b *Bus) *notifier.Notifier[T] {
+	tp := reflect.TypeOf((*T)(nil)).Elem()
+	if n, ok := b.notifiers[tp]; ok {
+		return n.(*notifier.Notifier[T])
+	}
+	n := notifier.NewNotifier[T]()
+	b.notifiers[tp] = n
+	return n
+}
+
+// Publish publishes an event to the subscribers of its type without
+// blocking. The event is dropped if the bus is closed.
+func Publish[T Event](b *Bus, event T) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	publishedEventsCounter.WithLabelValues(event.Topic()).Inc()
+	getNotifier[T](b).Notify(event)
+}
+
+// Subscribe calls handler for the events of type T published after it
+// returns, until unsubscribe is called or the bus is closed. The handler is
+// called in a background goroutine one event at a time, a slow handler
+// delays the other subscribers of the same type, so it should hand over the
+// slow work. unsubscribe must not be called in the handler.
+func Subscribe[T Event](b *Bus, handler func(event T)) (unsubscribe func()) {
+	if b == nil {
+		return func() {}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return func() {}
+	}
+
+	receiver := getNotifier[T](b).NewReceiver()
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		for event := range receiver.C {
+			handler(event)
+		}
+	}()
+	return receiver.Close
+}
+
+// Close closes the bus, the events not delivered yet are dropped. It waits
+// for the handlers being called to return.
+func (b *Bus) Close() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.closed = true
+	notifiers := b.notifiers
+	b.notifiers = nil
+	b.mu.Unlock()
+
+	for _, n := range notifiers {
+		n.(interface{ Close() }).Close()
+	}
+	b.wg.Wait()
+}
@@ -0,0 +1,72 @@
+package eventbus
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type eventRecorder[T Event] struct {
+	mu     sync.Mutex
+	events []T
+}
+
+func (r *eventRecorder[T]) record(event T) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *eventRecorder[T]) get() []T {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]T(nil), r.events...)
+}
+
+func TestBus(t *testing.T) {
+	t.Parallel()
+
+	bus := NewBus()
+	defer bus.Close()
+
+	var (
+		joined1, joined2 eventRecorder[ExecutorJoined]
+		offline          eventRecorder[ExecutorOffline]
+	)
+	unsubscribe1 := Subscribe(bus, joined1.record)
+	unsubscribe2 := Subscribe(bus, joined2.record)
+	defer unsubscribe2()
+	Subscribe(bus, offline.record)
+
+	Publish(bus, ExecutorJoined{ExecutorID: "executor-1"})
+	Publish(bus, ExecutorJoined{ExecutorID: "executor-2"})
+	Publish(bus, ExecutorOffline{ExecutorID: "executor-1"})
+	require.Eventually(t, func() bool {
+		return len(joined1.get()) == 2 && len(joined2.get()) == 2 && len(offline.get()) == 1
+	}, time.Second, 10*time.Millisecond)
+	// the events are delivered in order, only to the subscribers of the type
+	require.Equal(t, []ExecutorJoined{{ExecutorID: "executor-1"}, {ExecutorID: "executor-2"}}, joined1.get())
+	require.Equal(t, []ExecutorOffline{{ExecutorID: "executor-1"}}, offline.get())
+
+	unsubscribe1()
+	// unsubscribing again is a no-op
+	unsubscribe1()
+	Publish(bus, ExecutorJoined{ExecutorID: "executor-3"})
+	require.Eventually(t, func() bool {
+		return len(joined2.get()) == 3
+	}, time.Second, 10*time.Millisecond)
+	require.Len(t, joined1.get(), 2)
+
+	// the subscriptions end after the bus is closed
+	bus.Close()
+	Publish(bus, ExecutorJoined{ExecutorID: "executor-4"})
+	Subscribe(bus, joined1.record)()
+	require.Len(t, joined2.get(), 3)
+
+	var nilBus *Bus
+	Publish(nilBus, LeaderElected{NodeID: "server-master-1"})
+	Subscribe(nilBus, func(LeaderElected) {})()
+	nilBus.Close()
+}
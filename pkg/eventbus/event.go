@@ -0,0 +1,96 @@
+package eventbus
+
+import (
+	"time"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+)
+
+// Event is an event published on the Bus, the subscribers subscribe to the
+// events by their types.
+type Event interface {
+	// Topic returns the name of the event type, which is used in logs and
+	// metrics.
+	Topic() string
+}
+
+// WorkerOnline is published by a master when a worker comes online.
+type WorkerOnline struct {
+	Time     time.Time
+	TenantID string
+	MasterID libModel.MasterID
+	WorkerID libModel.WorkerID
+	Status   *libModel.WorkerStatus
+}
+
+// Topic implements Event.Topic
+func (WorkerOnline) Topic() string {
+	return "worker-online"
+}
+
+// WorkerOffline is published by a master when a worker exits or times out.
+type WorkerOffline struct {
+	Time     time.Time
+	TenantID string
+	MasterID libModel.MasterID
+	WorkerID libModel.WorkerID
+	Status   *libModel.WorkerStatus
+	// Reason is the error the worker exits with
+	Reason error
+}
+
+// Topic implements Event.Topic
+func (WorkerOffline) Topic() string {
+	return "worker-offline"
+}
+
+// LeaderElected is published by a server master once it becomes the leader
+// and has initialized the leader services.
+type LeaderElected struct {
+	Time   time.Time
+	NodeID string
+	Addr   string
+}
+
+// Topic implements Event.Topic
+func (LeaderElected) Topic() string {
+	return "leader-elected"
+}
+
+// ExecutorJoined is published by the server master when an executor
+// registers.
+type ExecutorJoined struct {
+	Time       time.Time
+	ExecutorID string
+	Addr       string
+}
+
+// Topic implements Event.Topic
+func (ExecutorJoined) Topic() string {
+	return "executor-joined"
+}
+
+// ExecutorOffline is published by the server master when an executor is
+// removed, since it has timed out or is deleted.
+type ExecutorOffline struct {
+	Time       time.Time
+	ExecutorID string
+}
+
+// Topic implements Event.Topic
+func (ExecutorOffline) Topic() string {
+	return "executor-offline"
+}
+
+// ExecutorBlacklisted is published by the server master when an executor is
+// blacklisted by operators, the workers on it should be regarded as offline
+// without waiting for their heartbeats to time out.
+type ExecutorBlacklisted struct {
+	Time       time.Time
+	ExecutorID string
+}
+
+// Topic implements Event.Topic
+func (ExecutorBlacklisted) Topic() string {
+	return "executor-blacklisted"
+}
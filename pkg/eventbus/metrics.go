@@ -0,0 +1,18 @@
+package eventbus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var publishedEventsCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "dataflow",
+		Subsystem: "event_bus",
+		Name:      "published_events_total",
+		Help:      "number of events published on the event bus",
+	}, []string{"topic"})
+
+// InitMetrics registers the event bus metrics
+func InitMetrics(registry *prometheus.Registry) {
+	registry.MustRegister(publishedEventsCounter)
+}
@@ -25,6 +25,8 @@ type DefaultBroker struct {
 	client     *rpcutil.FailoverRPCClients[pb.ResourceManagerClient]
 
 	fileManager FileManager
+	usage       *usageTracker
+	leases      *pendingLeases
 }
 
 // NewBroker creates a new Impl instance
@@ -39,6 +41,8 @@ func NewBroker(
 		executorID:  executorID,
 		client:      client,
 		fileManager: fm,
+		usage:       newUsageTracker(*config.Local),
+		leases:      newPendingLeases(),
 	}
 }
 
@@ -66,6 +70,62 @@ func (b *DefaultBroker) OpenStorage(
 	panic("unreachable")
 }
 
+// OpenSharedStorage implements Broker.OpenSharedStorage
+func (b *DefaultBroker) OpenSharedStorage(
+	ctx context.Context,
+	workerID resModel.WorkerID,
+	jobID resModel.JobID,
+	resourcePath resModel.ResourceID,
+) (Handle, error) {
+	tp, resName, err := resModel.ParseResourcePath(resourcePath)
+	if err != nil {
+		return nil, err
+	}
+	if tp != resModel.ResourceTypeLocalFile {
+		return nil, derrors.ErrUnexpectedResourcePath.GenWithStackByArgs(resourcePath)
+	}
+
+	record, exists, err := b.checkForExistingResource(ctx, resourcePath)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, derrors.ErrResourceDoesNotExist.GenWithStackByArgs(resourcePath)
+	}
+	if record.Job != jobID {
+		return nil, derrors.ErrResourceAccessDenied.GenWithStackByArgs(resourcePath, record.Job, jobID)
+	}
+	// local files can only be shared by the workers on the same executor,
+	// which should be guaranteed by scheduling the worker with the resource.
+	if record.Executor != b.executorID {
+		return nil, derrors.ErrResourceNotLocal.GenWithStackByArgs(resourcePath, record.Executor)
+	}
+
+	res, err := b.fileManager.GetPersistedResource(record.Worker, resName)
+	if err != nil {
+		return nil, err
+	}
+	filePath := res.AbsolutePath()
+	log.L().Info("Using shared local storage with path",
+		zap.String("worker-id", workerID),
+		zap.String("creator-id", record.Worker),
+		zap.String("path", filePath))
+
+	ls, err := newBrStorageForLocalFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return &readOnlyStorageHandle{
+		id:    resourcePath,
+		inner: &readOnlyStorage{ExternalStorage: ls, id: resourcePath},
+	}, nil
+}
+
+// DiskUsage implements Broker.DiskUsage
+func (b *DefaultBroker) DiskUsage() DiskUsage {
+	return b.usage.Usage()
+}
+
 // OnWorkerClosed implements Broker.OnWorkerClosed
 func (b *DefaultBroker) OnWorkerClosed(ctx context.Context, workerID resModel.WorkerID, jobID resModel.JobID) {
 	err := b.fileManager.RemoveTemporaryFiles(workerID)
@@ -79,6 +139,12 @@ func (b *DefaultBroker) OnWorkerClosed(ctx context.Context, workerID resModel.Wo
 			zap.String("job-id", jobID),
 			zap.Error(err))
 	}
+	b.usage.Invalidate()
+
+	// the files of the resources not persisted have been removed
+	for _, resourceID := range b.leases.take(workerID) {
+		b.releaseLease(ctx, workerID, resourceID)
+	}
 }
 
 // RemoveResource implements pb.BrokerServiceServer.
@@ -108,6 +174,7 @@ func (b *DefaultBroker) RemoveResource(
 		}
 		return nil, status.Error(codes.Unknown, err.Error())
 	}
+	b.usage.Invalidate()
 
 	return &pb.RemoveLocalResourceResponse{}, nil
 }
@@ -141,6 +208,14 @@ func (b *DefaultBroker) newHandleForLocalFile(
 
 	if !exists {
 		creatorWorkerID = workerID
+		if err := b.leaseResource(ctx, jobID, workerID, resourceID); err != nil {
+			return nil, err
+		}
+		defer func() {
+			if retErr != nil {
+				b.releaseLease(ctx, workerID, resourceID)
+			}
+		}()
 		res, err = b.fileManager.CreateResource(workerID, resName)
 		if err != nil {
 			return nil, err
@@ -151,8 +226,14 @@ func (b *DefaultBroker) newHandleForLocalFile(
 				_ = b.fileManager.RemoveResource(workerID, resName)
 			}
 		}()
+		b.usage.AddCreator(workerID, jobID)
+		// a new resource is rejected if the quotas have been exceeded
+		if err := b.usage.Reserve(workerID, 0); err != nil {
+			return nil, err
+		}
 	} else {
 		creatorWorkerID = record.Worker
+		b.usage.AddCreator(record.Worker, record.Job)
 		res, err = b.fileManager.GetPersistedResource(record.Worker, resName)
 		if err != nil {
 			return nil, err
@@ -168,7 +249,11 @@ func (b *DefaultBroker) newHandleForLocalFile(
 	}
 
 	return &BrExternalStorageHandle{
-		inner:  ls,
+		inner: &quotaStorage{
+			ExternalStorage: ls,
+			creator:         creatorWorkerID,
+			tracker:         b.usage,
+		},
 		client: b.client,
 
 		id:          resourceID,
@@ -177,6 +262,7 @@ func (b *DefaultBroker) newHandleForLocalFile(
 		workerID:    creatorWorkerID,
 		executorID:  b.executorID,
 		fileManager: b.fileManager,
+		leases:      b.leases,
 	}, nil
 }
 
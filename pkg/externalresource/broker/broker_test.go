@@ -12,6 +12,7 @@ import (
 	"google.golang.org/grpc/codes"
 
 	"github.com/hanfei1991/microcosm/pb"
+	derrors "github.com/hanfei1991/microcosm/pkg/errors"
 	"github.com/hanfei1991/microcosm/pkg/externalresource/manager"
 	"github.com/hanfei1991/microcosm/pkg/externalresource/storagecfg"
 	"github.com/hanfei1991/microcosm/pkg/rpcutil"
@@ -35,6 +36,12 @@ func TestBrokerOpenNewStorage(t *testing.T) {
 	innerClient := client.GetLeaderClient().(*manager.MockClient)
 	innerClient.On("QueryResource", mock.Anything, &pb.QueryResourceRequest{ResourceId: "/local/test-1"}, mock.Anything).
 		Return((*pb.QueryResourceResponse)(nil), status.Error(codes.NotFound, "resource manager error"))
+	innerClient.On("LeaseResource", mock.Anything, &pb.LeaseResourceRequest{
+		ResourceId:      "/local/test-1",
+		CreatorExecutor: "executor-1",
+		JobId:           "job-1",
+		CreatorWorkerId: "worker-1",
+	}, mock.Anything).Return(&pb.LeaseResourceResponse{}, nil)
 	hdl, err := brk.OpenStorage(context.Background(), "worker-1", "job-1", "/local/test-1")
 	require.NoError(t, err)
 	require.Equal(t, "/local/test-1", hdl.ID())
@@ -70,6 +77,8 @@ func TestBrokerOpenExistingStorage(t *testing.T) {
 	innerClient := client.GetLeaderClient().(*manager.MockClient)
 	innerClient.On("QueryResource", mock.Anything, &pb.QueryResourceRequest{ResourceId: "/local/test-2"}, mock.Anything).
 		Return((*pb.QueryResourceResponse)(nil), status.Error(codes.NotFound, "resource manager error")).Once()
+	innerClient.On("LeaseResource", mock.Anything, mock.Anything, mock.Anything).
+		Return(&pb.LeaseResourceResponse{}, nil).Once()
 	innerClient.On("CreateResource", mock.Anything, &pb.CreateResourceRequest{
 		ResourceId:      "/local/test-2",
 		CreatorExecutor: "executor-1",
@@ -180,3 +189,139 @@ func TestBrokerRemoveResource(t *testing.T) {
 	code = status.Convert(err).Code()
 	require.Equal(t, codes.InvalidArgument, code)
 }
+
+func TestBrokerOpenSharedStorage(t *testing.T) {
+	brk, client, _ := newBroker(t)
+	ctx := context.Background()
+
+	innerClient := client.GetLeaderClient().(*manager.MockClient)
+	innerClient.On("QueryResource", mock.Anything, &pb.QueryResourceRequest{ResourceId: "/local/test-3"}, mock.Anything).
+		Return((*pb.QueryResourceResponse)(nil), status.Error(codes.NotFound, "resource manager error")).Once()
+	innerClient.On("LeaseResource", mock.Anything, mock.Anything, mock.Anything).
+		Return(&pb.LeaseResourceResponse{}, nil).Once()
+	innerClient.On("CreateResource", mock.Anything, &pb.CreateResourceRequest{
+		ResourceId:      "/local/test-3",
+		CreatorExecutor: "executor-1",
+		JobId:           "job-1",
+		CreatorWorkerId: "worker-2",
+	}, mock.Anything).
+		Return(&pb.CreateResourceResponse{}, nil)
+
+	hdl, err := brk.OpenStorage(ctx, "worker-2", "job-1", "/local/test-3")
+	require.NoError(t, err)
+	require.NoError(t, hdl.BrExternalStorage().WriteFile(ctx, "1.txt", []byte("dumped")))
+	require.NoError(t, hdl.Persist(ctx))
+
+	innerClient.On("QueryResource", mock.Anything, &pb.QueryResourceRequest{ResourceId: "/local/test-3"}, mock.Anything).
+		Return(&pb.QueryResourceResponse{
+			CreatorExecutor: "executor-1",
+			JobId:           "job-1",
+			CreatorWorkerId: "worker-2",
+		}, nil)
+
+	hdl, err = brk.OpenSharedStorage(ctx, "worker-1", "job-1", "/local/test-3")
+	require.NoError(t, err)
+	require.Equal(t, "/local/test-3", hdl.ID())
+	data, err := hdl.BrExternalStorage().ReadFile(ctx, "1.txt")
+	require.NoError(t, err)
+	require.Equal(t, "dumped", string(data))
+
+	// the shared resource can't be modified
+	_, err = hdl.BrExternalStorage().Create(ctx, "2.txt")
+	require.True(t, derrors.ErrResourceReadOnly.Equal(err))
+	err = hdl.BrExternalStorage().DeleteFile(ctx, "1.txt")
+	require.True(t, derrors.ErrResourceReadOnly.Equal(err))
+	err = hdl.Persist(ctx)
+	require.True(t, derrors.ErrResourceReadOnly.Equal(err))
+
+	// the workers of another job can't open the resource
+	_, err = brk.OpenSharedStorage(ctx, "worker-3", "job-2", "/local/test-3")
+	require.True(t, derrors.ErrResourceAccessDenied.Equal(err))
+
+	innerClient.On("QueryResource", mock.Anything, &pb.QueryResourceRequest{ResourceId: "/local/test-4"}, mock.Anything).
+		Return((*pb.QueryResourceResponse)(nil), status.Error(codes.NotFound, "resource manager error"))
+	_, err = brk.OpenSharedStorage(ctx, "worker-1", "job-1", "/local/test-4")
+	require.True(t, derrors.ErrResourceDoesNotExist.Equal(err))
+}
+
+func TestBrokerLocalResourceQuota(t *testing.T) {
+	client := manager.NewWrappedMockClient()
+	brk := NewBroker(&storagecfg.Config{Local: &storagecfg.LocalFileConfig{
+		BaseDir:  t.TempDir(),
+		Quota:    10,
+		JobQuota: 6,
+	}}, "executor-1", client)
+
+	innerClient := client.GetLeaderClient().(*manager.MockClient)
+	innerClient.On("QueryResource", mock.Anything, mock.Anything, mock.Anything).
+		Return((*pb.QueryResourceResponse)(nil), status.Error(codes.NotFound, "resource manager error"))
+	innerClient.On("LeaseResource", mock.Anything, mock.Anything, mock.Anything).
+		Return(&pb.LeaseResourceResponse{}, nil)
+	innerClient.On("RemoveResource", mock.Anything, mock.Anything, mock.Anything).
+		Return(&pb.RemoveResourceResponse{}, nil)
+
+	ctx := context.Background()
+	hdl1, err := brk.OpenStorage(ctx, "worker-1", "job-1", "/local/test-1")
+	require.NoError(t, err)
+	require.NoError(t, hdl1.BrExternalStorage().WriteFile(ctx, "1.txt", []byte("12345")))
+	// exceeds the quota of job-1
+	err = hdl1.BrExternalStorage().WriteFile(ctx, "2.txt", []byte("12"))
+	require.True(t, derrors.ErrLocalResourceQuotaExceeded.Equal(err))
+
+	hdl2, err := brk.OpenStorage(ctx, "worker-2", "job-2", "/local/test-2")
+	require.NoError(t, err)
+	f, err := hdl2.BrExternalStorage().Create(ctx, "1.txt")
+	require.NoError(t, err)
+	_, err = f.Write(ctx, []byte("1234"))
+	require.NoError(t, err)
+	// exceeds the quota of the executor
+	_, err = f.Write(ctx, []byte("12"))
+	require.True(t, derrors.ErrLocalResourceQuotaExceeded.Equal(err))
+	require.NoError(t, f.Close(ctx))
+
+	usage := brk.DiskUsage()
+	require.Equal(t, int64(9), usage.Total)
+	require.Equal(t, map[string]int64{"job-1": 5, "job-2": 4}, usage.Jobs)
+
+	// the temporary files of worker-1 are removed
+	brk.OnWorkerClosed(ctx, "worker-1", "job-1")
+	usage = brk.DiskUsage()
+	require.Equal(t, int64(4), usage.Total)
+	require.Equal(t, int64(4), usage.Jobs["job-2"])
+	_, err = brk.OpenStorage(ctx, "worker-3", "job-1", "/local/test-3")
+	require.NoError(t, err)
+}
+
+func TestBrokerResourceLease(t *testing.T) {
+	brk, client, dir := newBroker(t)
+	ctx := context.Background()
+
+	innerClient := client.GetLeaderClient().(*manager.MockClient)
+	innerClient.On("QueryResource", mock.Anything, mock.Anything, mock.Anything).
+		Return((*pb.QueryResourceResponse)(nil), status.Error(codes.NotFound, "resource manager error"))
+
+	// the resource is being created by another worker
+	innerClient.On("LeaseResource", mock.Anything, mock.Anything, mock.Anything).
+		Return((*pb.LeaseResourceResponse)(nil), status.Error(codes.AlreadyExists, "resource manager error")).Once()
+	_, err := brk.OpenStorage(ctx, "worker-1", "job-1", "/local/test-1")
+	require.True(t, derrors.ErrDuplicateResourceID.Equal(err))
+	require.NoDirExists(t, filepath.Join(dir, "worker-1", "test-1"))
+
+	innerClient.On("LeaseResource", mock.Anything, mock.Anything, mock.Anything).
+		Return(&pb.LeaseResourceResponse{}, nil)
+	innerClient.On("CreateResource", mock.Anything, mock.Anything, mock.Anything).
+		Return(&pb.CreateResourceResponse{}, nil)
+	persisted, err := brk.OpenStorage(ctx, "worker-1", "job-1", "/local/test-1")
+	require.NoError(t, err)
+	require.NoError(t, persisted.Persist(ctx))
+	_, err = brk.OpenStorage(ctx, "worker-1", "job-1", "/local/test-2")
+	require.NoError(t, err)
+
+	// only the lease of the resource not persisted is released
+	innerClient.On("RemoveResource", mock.Anything, &pb.RemoveResourceRequest{ResourceId: "/local/test-2"}, mock.Anything).
+		Return(&pb.RemoveResourceResponse{}, nil).Once()
+	brk.OnWorkerClosed(ctx, "worker-1", "job-1")
+	innerClient.AssertExpectations(t)
+	require.DirExists(t, filepath.Join(dir, "worker-1", "test-1"))
+	require.NoDirExists(t, filepath.Join(dir, "worker-1", "test-2"))
+}
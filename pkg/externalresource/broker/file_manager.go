@@ -50,7 +50,6 @@ func (m *LocalFileManager) CreateResource(
 	if err := os.MkdirAll(res.AbsolutePath(), 0o700); err != nil {
 		return nil, derrors.ErrCreateLocalFileDirectoryFailed.Wrap(err)
 	}
-	// TODO check for quota when we implement quota.
 	return res, nil
 }
 
@@ -21,6 +21,21 @@ type Broker interface {
 		resourcePath resModel.ResourceID,
 	) (Handle, error)
 
+	// OpenSharedStorage opens a resource persisted by another worker of the
+	// same job in read-only mode, for example, the files dumped by a worker
+	// are loaded by another one. It returns ErrResourceAccessDenied if the
+	// resource belongs to another job.
+	OpenSharedStorage(
+		ctx context.Context,
+		workerID resModel.WorkerID,
+		jobID resModel.JobID,
+		resourcePath resModel.ResourceID,
+	) (Handle, error)
+
+	// DiskUsage returns the bytes used by the local file resources on the
+	// executor, which are limited by the quotas in storagecfg.LocalFileConfig.
+	DiskUsage() DiskUsage
+
 	// OnWorkerClosed in called when a worker is closing.
 	// The implementation should do necessary garbage collection
 	// for the worker, especially local temporary files.
@@ -0,0 +1,127 @@
+package broker
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gogo/status"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"github.com/hanfei1991/microcosm/pb"
+	derrors "github.com/hanfei1991/microcosm/pkg/errors"
+	resModel "github.com/hanfei1991/microcosm/pkg/externalresource/resourcemeta/model"
+	"github.com/hanfei1991/microcosm/pkg/rpcutil"
+)
+
+// pendingLeases records the resources leased by the workers and not
+// persisted yet, whose leases are released when the workers are closed.
+type pendingLeases struct {
+	mu       sync.Mutex
+	byWorker map[resModel.WorkerID]map[resModel.ResourceID]struct{}
+}
+
+func newPendingLeases() *pendingLeases {
+	return &pendingLeases{
+		byWorker: make(map[resModel.WorkerID]map[resModel.ResourceID]struct{}),
+	}
+}
+
+func (l *pendingLeases) add(workerID resModel.WorkerID, resourceID resModel.ResourceID) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.byWorker[workerID] == nil {
+		l.byWorker[workerID] = make(map[resModel.ResourceID]struct{})
+	}
+	l.byWorker[workerID][resourceID] = struct{}{}
+}
+
+func (l *pendingLeases) remove(workerID resModel.WorkerID, resourceID resModel.ResourceID) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.byWorker[workerID], resourceID)
+	if len(l.byWorker[workerID]) == 0 {
+		delete(l.byWorker, workerID)
+	}
+}
+
+func (l *pendingLeases) take(workerID resModel.WorkerID) []resModel.ResourceID {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	ret := make([]resModel.ResourceID, 0, len(l.byWorker[workerID]))
+	for resourceID := range l.byWorker[workerID] {
+		ret = append(ret, resourceID)
+	}
+	delete(l.byWorker, workerID)
+	return ret
+}
+
+// leaseResource records the creation of a new resource in the resource
+// manager before the resource is physically created. It returns
+// ErrDuplicateResourceID if the resource is being created by another worker.
+func (b *DefaultBroker) leaseResource(
+	ctx context.Context,
+	jobID resModel.JobID,
+	workerID resModel.WorkerID,
+	resourceID resModel.ResourceID,
+) error {
+	_, err := rpcutil.DoFailoverRPC(
+		ctx,
+		b.client,
+		&pb.LeaseResourceRequest{
+			ResourceId:      resourceID,
+			CreatorExecutor: string(b.executorID),
+			JobId:           jobID,
+			CreatorWorkerId: workerID,
+		},
+		pb.ResourceManagerClient.LeaseResource,
+	)
+	if err != nil {
+		st, ok := status.FromError(err)
+		if !ok {
+			return errors.Trace(err)
+		}
+		switch st.Code() {
+		case codes.AlreadyExists:
+			return derrors.ErrDuplicateResourceID.GenWithStackByArgs(resourceID)
+		case codes.Unimplemented:
+			// The server master has not been upgraded, the resource is
+			// created without a lease.
+			log.L().Info("Resource manager does not support leases",
+				zap.String("resource-id", resourceID))
+			return nil
+		default:
+			return errors.Trace(err)
+		}
+	}
+	b.leases.add(workerID, resourceID)
+	return nil
+}
+
+// releaseLease removes the lease of a resource not persisted.
+func (b *DefaultBroker) releaseLease(
+	ctx context.Context,
+	workerID resModel.WorkerID,
+	resourceID resModel.ResourceID,
+) {
+	b.leases.remove(workerID, resourceID)
+	_, err := rpcutil.DoFailoverRPC(
+		ctx,
+		b.client,
+		&pb.RemoveResourceRequest{ResourceId: resourceID},
+		pb.ResourceManagerClient.RemoveResource,
+	)
+	if err != nil && status.Code(err) != codes.NotFound {
+		// The lease left is removed by the resource manager after this
+		// executor goes offline.
+		log.L().Warn("Failed to release resource lease",
+			zap.String("worker-id", workerID),
+			zap.String("resource-id", resourceID),
+			zap.Error(err))
+	}
+}
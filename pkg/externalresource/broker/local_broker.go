@@ -60,6 +60,8 @@ func (b *LocalBroker) OpenStorage(
 
 	b.client.On("QueryResource", mock.Anything, &pb.QueryResourceRequest{ResourceId: resourcePath}, mock.Anything).
 		Return((*pb.QueryResourceResponse)(nil), st.Err())
+	b.client.On("LeaseResource", mock.Anything, mock.Anything, mock.Anything).
+		Return(&pb.LeaseResourceResponse{}, nil)
 	defer func() {
 		b.client.ExpectedCalls = nil
 	}()
@@ -71,6 +73,23 @@ func (b *LocalBroker) OpenStorage(
 	return &brExternalStorageHandleForTesting{parent: b, Handle: h}, nil
 }
 
+// OnWorkerClosed wraps broker.OnWorkerClosed
+func (b *LocalBroker) OnWorkerClosed(
+	ctx context.Context,
+	workerID resourcemeta.WorkerID,
+	jobID resourcemeta.JobID,
+) {
+	b.clientMu.Lock()
+	defer b.clientMu.Unlock()
+
+	b.client.On("RemoveResource", mock.Anything, mock.Anything, mock.Anything).
+		Return(&pb.RemoveResourceResponse{}, nil)
+	defer func() {
+		b.client.ExpectedCalls = nil
+	}()
+	b.DefaultBroker.OnWorkerClosed(ctx, workerID, jobID)
+}
+
 // AssertPersisted checks resource is in persisted list
 func (b *LocalBroker) AssertPersisted(t *testing.T, id resourcemeta.ResourceID) {
 	b.mu.Lock()
@@ -7,6 +7,7 @@ import (
 	brStorage "github.com/pingcap/tidb/br/pkg/storage"
 
 	"github.com/hanfei1991/microcosm/pb"
+	derrors "github.com/hanfei1991/microcosm/pkg/errors"
 	resModel "github.com/hanfei1991/microcosm/pkg/externalresource/resourcemeta/model"
 	"github.com/hanfei1991/microcosm/pkg/rpcutil"
 )
@@ -32,6 +33,7 @@ type BrExternalStorageHandle struct {
 	inner       brStorage.ExternalStorage
 	client      *rpcutil.FailoverRPCClients[pb.ResourceManagerClient]
 	fileManager FileManager
+	leases      *pendingLeases
 }
 
 // ID implements Handle.ID
@@ -65,6 +67,8 @@ func (h *BrExternalStorageHandle) Persist(ctx context.Context) error {
 		return errors.Trace(err)
 	}
 	h.fileManager.SetPersisted(h.workerID, h.name)
+	// the lease has been finalized by the resource manager
+	h.leases.remove(h.workerID, h.id)
 	return nil
 }
 
@@ -72,3 +76,57 @@ func (h *BrExternalStorageHandle) Persist(ctx context.Context) error {
 func (h *BrExternalStorageHandle) Discard(ctx context.Context) error {
 	return nil
 }
+
+// readOnlyStorageHandle is the Handle of a resource shared by another worker,
+// see Broker.OpenSharedStorage.
+type readOnlyStorageHandle struct {
+	id    resModel.ResourceID
+	inner brStorage.ExternalStorage
+}
+
+// ID implements Handle.ID
+func (h *readOnlyStorageHandle) ID() resModel.ResourceID {
+	return h.id
+}
+
+// BrExternalStorage implements Handle.BrExternalStorage
+func (h *readOnlyStorageHandle) BrExternalStorage() brStorage.ExternalStorage {
+	return h.inner
+}
+
+// Persist implements Handle.Persist, a shared resource has been persisted by
+// its creator.
+func (h *readOnlyStorageHandle) Persist(ctx context.Context) error {
+	return derrors.ErrResourceReadOnly.GenWithStackByArgs(h.id)
+}
+
+// Discard implements Handle.Discard
+func (h *readOnlyStorageHandle) Discard(ctx context.Context) error {
+	return nil
+}
+
+// readOnlyStorage rejects the modifications to a shared resource.
+type readOnlyStorage struct {
+	brStorage.ExternalStorage
+	id resModel.ResourceID
+}
+
+// WriteFile implements brStorage.ExternalStorage.WriteFile
+func (s *readOnlyStorage) WriteFile(ctx context.Context, name string, data []byte) error {
+	return derrors.ErrResourceReadOnly.GenWithStackByArgs(s.id)
+}
+
+// DeleteFile implements brStorage.ExternalStorage.DeleteFile
+func (s *readOnlyStorage) DeleteFile(ctx context.Context, name string) error {
+	return derrors.ErrResourceReadOnly.GenWithStackByArgs(s.id)
+}
+
+// Create implements brStorage.ExternalStorage.Create
+func (s *readOnlyStorage) Create(ctx context.Context, path string) (brStorage.ExternalFileWriter, error) {
+	return nil, derrors.ErrResourceReadOnly.GenWithStackByArgs(s.id)
+}
+
+// Rename implements brStorage.ExternalStorage.Rename
+func (s *readOnlyStorage) Rename(ctx context.Context, oldFileName, newFileName string) error {
+	return derrors.ErrResourceReadOnly.GenWithStackByArgs(s.id)
+}
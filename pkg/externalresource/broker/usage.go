@@ -0,0 +1,197 @@
+package broker
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	brStorage "github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	derrors "github.com/hanfei1991/microcosm/pkg/errors"
+	resModel "github.com/hanfei1991/microcosm/pkg/externalresource/resourcemeta/model"
+	"github.com/hanfei1991/microcosm/pkg/externalresource/storagecfg"
+)
+
+// usageRefreshInterval is the interval to measure the usage of the local
+// file resources by walking their directories. Between two measurements,
+// the usage is increased by the writes through the storage handles.
+const usageRefreshInterval = 10 * time.Second
+
+// DiskUsage is the bytes used by the local file resources on an executor.
+type DiskUsage struct {
+	Total int64
+	Jobs  map[resModel.JobID]int64
+}
+
+// usageTracker tracks the bytes used by the local file resources of each
+// creator, and enforces the quotas of the executor and the jobs.
+// Only the creators whose resources have been opened since the executor
+// started are tracked.
+type usageTracker struct {
+	config storagecfg.LocalFileConfig
+
+	mu          sync.Mutex
+	jobs        map[resModel.WorkerID]resModel.JobID
+	usage       map[resModel.WorkerID]int64
+	lastRefresh time.Time
+}
+
+func newUsageTracker(config storagecfg.LocalFileConfig) *usageTracker {
+	return &usageTracker{
+		config: config,
+		jobs:   make(map[resModel.WorkerID]resModel.JobID),
+		usage:  make(map[resModel.WorkerID]int64),
+	}
+}
+
+// AddCreator starts tracking the resources created by the given worker.
+func (t *usageTracker) AddCreator(creator resModel.WorkerID, jobID resModel.JobID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.jobs[creator]; ok {
+		return
+	}
+	t.jobs[creator] = jobID
+	t.usage[creator] = dirSize(filepath.Join(t.config.BaseDir, creator))
+}
+
+// Invalidate makes the next call measure the usage again, it is called
+// after the files are removed.
+func (t *usageTracker) Invalidate() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.lastRefresh = time.Time{}
+}
+
+// Reserve accounts n bytes to be written to the resources of the creator.
+// It returns ErrLocalResourceQuotaExceeded without accounting them if the
+// quota of the executor or the job would be exceeded. A zero n checks
+// whether the quotas have been exceeded.
+func (t *usageTracker) Reserve(creator resModel.WorkerID, n int64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.refreshIfStaleLocked()
+	jobID := t.jobs[creator]
+	var total, jobTotal int64
+	for worker, bytes := range t.usage {
+		total += bytes
+		if t.jobs[worker] == jobID {
+			jobTotal += bytes
+		}
+	}
+	if quota := t.config.Quota; quota > 0 && total+n > quota {
+		return derrors.ErrLocalResourceQuotaExceeded.GenWithStackByArgs("executor", total+n, quota)
+	}
+	if quota := t.config.JobQuota; quota > 0 && jobTotal+n > quota {
+		return derrors.ErrLocalResourceQuotaExceeded.GenWithStackByArgs("job "+jobID, jobTotal+n, quota)
+	}
+	t.usage[creator] += n
+	return nil
+}
+
+// Usage returns the bytes used by the tracked resources.
+func (t *usageTracker) Usage() DiskUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.refreshIfStaleLocked()
+	ret := DiskUsage{Jobs: make(map[resModel.JobID]int64)}
+	for worker, bytes := range t.usage {
+		ret.Total += bytes
+		ret.Jobs[t.jobs[worker]] += bytes
+	}
+	return ret
+}
+
+func (t *usageTracker) refreshIfStaleLocked() {
+	if time.Since(t.lastRefresh) < usageRefreshInterval {
+		return
+	}
+	for worker := range t.jobs {
+		dir := filepath.Join(t.config.BaseDir, worker)
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			// all the resources of the creator have been removed
+			delete(t.jobs, worker)
+			delete(t.usage, worker)
+			continue
+		}
+		t.usage[worker] = dirSize(dir)
+	}
+	t.lastRefresh = time.Now()
+}
+
+// dirSize returns the total size of the regular files in a directory.
+func dirSize(dir string) int64 {
+	var size int64
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	if err != nil {
+		log.L().Warn("failed to measure the usage of local resources",
+			zap.String("dir", dir), zap.Error(err))
+	}
+	return size
+}
+
+// quotaStorage accounts the writes to a local file resource to its creator,
+// and rejects them if a quota would be exceeded.
+type quotaStorage struct {
+	brStorage.ExternalStorage
+	creator resModel.WorkerID
+	tracker *usageTracker
+}
+
+// WriteFile implements brStorage.ExternalStorage.WriteFile
+func (s *quotaStorage) WriteFile(ctx context.Context, name string, data []byte) error {
+	if err := s.tracker.Reserve(s.creator, int64(len(data))); err != nil {
+		return err
+	}
+	return s.ExternalStorage.WriteFile(ctx, name, data)
+}
+
+// Create implements brStorage.ExternalStorage.Create
+func (s *quotaStorage) Create(ctx context.Context, path string) (brStorage.ExternalFileWriter, error) {
+	w, err := s.ExternalStorage.Create(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return &quotaWriter{ExternalFileWriter: w, storage: s}, nil
+}
+
+type quotaWriter struct {
+	brStorage.ExternalFileWriter
+	storage *quotaStorage
+}
+
+// Write implements brStorage.ExternalFileWriter.Write
+func (w *quotaWriter) Write(ctx context.Context, p []byte) (int, error) {
+	if err := w.storage.tracker.Reserve(w.storage.creator, int64(len(p))); err != nil {
+		return 0, err
+	}
+	return w.ExternalFileWriter.Write(ctx, p)
+}
@@ -23,6 +23,12 @@ func NewWrappedMockClient() *rpcutil.FailoverRPCClients[pb.ResourceManagerClient
 	return rpcutil.NewFailoverRPCClientsForTest[pb.ResourceManagerClient](&MockClient{})
 }
 
+// LeaseResource implements ResourceManagerClient.LeaseResource
+func (m *MockClient) LeaseResource(ctx context.Context, in *pb.LeaseResourceRequest, opts ...grpc.CallOption) (*pb.LeaseResourceResponse, error) {
+	args := m.Called(ctx, in, opts)
+	return args.Get(0).(*pb.LeaseResourceResponse), args.Error(1)
+}
+
 // CreateResource implements ResourceManagerClient.CreateResource
 func (m *MockClient) CreateResource(ctx context.Context, in *pb.CreateResourceRequest, opts ...grpc.CallOption) (*pb.CreateResourceResponse, error) {
 	args := m.Called(ctx, in, opts)
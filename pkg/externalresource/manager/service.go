@@ -73,6 +73,63 @@ func (s *Service) QueryResource(ctx context.Context, request *pb.QueryResourceRe
 	return record.ToQueryResourceResponse(), nil
 }
 
+// LeaseResource implements ResourceManagerClient.LeaseResource
+func (s *Service) LeaseResource(
+	ctx context.Context,
+	request *pb.LeaseResourceRequest,
+) (*pb.LeaseResourceResponse, error) {
+	var resp2 *pb.LeaseResourceResponse
+	shouldRet, err := s.preRPCHook.PreRPC(ctx, request, &resp2)
+	if shouldRet {
+		return resp2, err
+	}
+
+	record, err := s.metaclient.GetResourceByID(ctx, request.GetResourceId())
+	if err == nil && !record.Deleted {
+		return nil, status.Error(codes.AlreadyExists, "resource manager error")
+	}
+	if err != nil && !pkgOrm.IsNotFoundError(err) {
+		return nil, status.Error(codes.Aborted, err.Error())
+	}
+
+	lease := &resModel.ResourceLease{
+		ID:       request.GetResourceId(),
+		Job:      request.GetJobId(),
+		Worker:   request.GetCreatorWorkerId(),
+		Executor: resModel.ExecutorID(request.GetCreatorExecutor()),
+	}
+	existing, err := s.metaclient.GetResourceLeaseByID(ctx, lease.ID)
+	if err != nil {
+		if !pkgOrm.IsNotFoundError(err) {
+			return nil, status.Error(codes.Aborted, err.Error())
+		}
+		err = s.metaclient.CreateResourceLease(ctx, lease)
+		if derror.ErrDuplicateResourceID.Equal(err) {
+			return nil, status.Error(codes.AlreadyExists, "resource manager error")
+		}
+		if err != nil {
+			return nil, status.Error(codes.Unknown, err.Error())
+		}
+		return &pb.LeaseResourceResponse{}, nil
+	}
+
+	if existing.IsHeldBy(lease.Job, lease.Worker, lease.Executor) {
+		// a retry of the same creator
+		return &pb.LeaseResourceResponse{}, nil
+	}
+	if !existing.Finalized && s.executors.HasExecutor(string(existing.Executor)) {
+		return nil, status.Error(codes.AlreadyExists, "resource manager error")
+	}
+	// Either the resource has been removed, or its creation on the offline
+	// executor can never be finalized, so the lease is taken over.
+	log.L().Info("Taking over stale resource lease",
+		zap.Any("lease", existing), zap.Any("new-lease", lease))
+	if err := s.metaclient.UpsertResourceLease(ctx, lease); err != nil {
+		return nil, status.Error(codes.Unknown, err.Error())
+	}
+	return &pb.LeaseResourceResponse{}, nil
+}
+
 // CreateResource implements ResourceManagerClient.CreateResource
 func (s *Service) CreateResource(
 	ctx context.Context,
@@ -84,6 +141,26 @@ func (s *Service) CreateResource(
 		return resp2, err
 	}
 
+	lease, err := s.metaclient.GetResourceLeaseByID(ctx, request.GetResourceId())
+	if err != nil {
+		if !pkgOrm.IsNotFoundError(err) {
+			return nil, status.Error(codes.Aborted, err.Error())
+		}
+		// the creators not leasing resources are still accepted
+		lease = nil
+	}
+	if lease != nil {
+		if !lease.IsHeldBy(request.GetJobId(), request.GetCreatorWorkerId(),
+			resModel.ExecutorID(request.GetCreatorExecutor())) {
+			return nil, status.Error(codes.AlreadyExists, "resource manager error")
+		}
+		if lease.Finalized {
+			// The creation has been finalized, but the response was lost,
+			// for example, because of a failover.
+			return &pb.CreateResourceResponse{}, nil
+		}
+	}
+
 	resourceRecord := &resModel.ResourceMeta{
 		// TODO: projectID
 		ID:       request.GetResourceId(),
@@ -101,6 +178,13 @@ func (s *Service) CreateResource(
 		return nil, status.Error(codes.Unknown, err.Error())
 	}
 
+	if lease != nil {
+		// If we fail here, the lease is finalized by the reconciliation.
+		lease.Finalized = true
+		if err := s.metaclient.UpsertResourceLease(ctx, lease); err != nil {
+			return nil, status.Error(codes.Unknown, err.Error())
+		}
+	}
 	return &pb.CreateResourceResponse{}, nil
 }
 
@@ -123,13 +207,18 @@ func (s *Service) RemoveResource(
 	if err != nil {
 		return nil, status.Error(codes.Aborted, err.Error())
 	}
-	if res.RowsAffected() == 0 {
-		return nil, status.Error(codes.NotFound, "resource not found")
-	}
 	if res.RowsAffected() > 1 {
 		log.L().Panic("unexpected RowsAffected",
 			zap.String("resource-id", request.GetResourceId()))
 	}
+	// the lease is removed too, which releases a creation in flight
+	leaseRes, err := s.metaclient.DeleteResourceLease(ctx, request.GetResourceId())
+	if err != nil {
+		return nil, status.Error(codes.Aborted, err.Error())
+	}
+	if res.RowsAffected() == 0 && leaseRes.RowsAffected() == 0 {
+		return nil, status.Error(codes.NotFound, "resource not found")
+	}
 
 	return &pb.RemoveResourceResponse{}, nil
 }
@@ -181,7 +270,9 @@ func (s *Service) GetPlacementConstraint(
 	return record.Executor, true, nil
 }
 
-func (s *Service) onExecutorOffline(executorID resModel.ExecutorID) error {
+// OnExecutorOffline queues the offline executor, whose resources are cleaned
+// up by the background worker.
+func (s *Service) OnExecutorOffline(executorID resModel.ExecutorID) error {
 	select {
 	case s.offlinedExecutors <- executorID:
 		return nil
@@ -218,6 +309,11 @@ func (s *Service) Stop() {
 }
 
 func (s *Service) runBackgroundWorker(ctx context.Context) {
+	if err := s.reconcileLeases(ctx); err != nil {
+		// the leases left are reconciled by the next leader or when their
+		// executors go offline
+		log.L().Warn("Failed to reconcile resource leases", zap.Error(err))
+	}
 	for {
 		select {
 		case <-ctx.Done():
@@ -229,5 +325,54 @@ func (s *Service) runBackgroundWorker(ctx context.Context) {
 }
 
 func (s *Service) handleExecutorOffline(ctx context.Context, executorID resModel.ExecutorID) {
-	// TODO
+	leases, err := s.metaclient.QueryResourceLeases(ctx)
+	if err != nil {
+		log.L().Warn("Failed to query resource leases",
+			zap.String("executor-id", string(executorID)), zap.Error(err))
+		return
+	}
+	for _, lease := range leases {
+		if lease.Executor != executorID || lease.Finalized {
+			continue
+		}
+		// the files of the creation have gone with the executor
+		if _, err := s.metaclient.DeleteResourceLease(ctx, lease.ID); err != nil {
+			log.L().Warn("Failed to remove resource lease",
+				zap.Any("lease", lease), zap.Error(err))
+		}
+	}
+}
+
+// reconcileLeases is called after a leader of the resource manager takes
+// over, it finalizes or cleans up the creations interrupted by a failover.
+// The creations whose executors are online are still in flight, and they
+// are left untouched.
+func (s *Service) reconcileLeases(ctx context.Context) error {
+	leases, err := s.metaclient.QueryResourceLeases(ctx)
+	if err != nil {
+		return err
+	}
+	for _, lease := range leases {
+		record, err := s.metaclient.GetResourceByID(ctx, lease.ID)
+		if err != nil && !pkgOrm.IsNotFoundError(err) {
+			return err
+		}
+		exists := err == nil && !record.Deleted
+		switch {
+		case exists && !lease.Finalized && lease.IsHeldBy(record.Job, record.Worker, record.Executor):
+			log.L().Info("Finalizing resource lease", zap.Any("lease", lease))
+			lease.Finalized = true
+			if err := s.metaclient.UpsertResourceLease(ctx, lease); err != nil {
+				return err
+			}
+		case !exists && (lease.Finalized || !s.executors.HasExecutor(string(lease.Executor))):
+			// either the resource has been removed, or its creation can
+			// never be finalized
+			log.L().Info("Removing resource lease", zap.Any("lease", lease))
+			if _, err := s.metaclient.DeleteResourceLease(ctx, lease.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/gogo/status"
 	"github.com/stretchr/testify/require"
@@ -94,7 +95,7 @@ func (s *serviceTestSuite) Stop() {
 
 func (s *serviceTestSuite) OfflineExecutor(t *testing.T, executor resourcemeta.ExecutorID) {
 	s.executorInfoProvider.RemoveExecutor(string(executor))
-	err := s.service.onExecutorOffline(executor)
+	err := s.service.OnExecutorOffline(executor)
 	require.NoError(t, err)
 }
 
@@ -213,3 +214,139 @@ func TestServiceResourceTypeNoConstraint(t *testing.T) {
 
 	suite.Stop()
 }
+
+func TestServiceResourceLease(t *testing.T) {
+	suite := newServiceTestSuite(t)
+	suite.LoadMockData()
+	suite.Start()
+	defer suite.Stop()
+
+	ctx := context.Background()
+	leaseReq := &pb.LeaseResourceRequest{
+		ResourceId:      "/local/test/7",
+		CreatorExecutor: "executor-1",
+		JobId:           "test-job-1",
+		CreatorWorkerId: "test-worker-5",
+	}
+	_, err := suite.service.LeaseResource(ctx, leaseReq)
+	require.NoError(t, err)
+	// retries are idempotent
+	_, err = suite.service.LeaseResource(ctx, leaseReq)
+	require.NoError(t, err)
+	_, err = suite.service.LeaseResource(ctx, &pb.LeaseResourceRequest{
+		ResourceId:      "/local/test/7",
+		CreatorExecutor: "executor-2",
+		JobId:           "test-job-1",
+		CreatorWorkerId: "test-worker-6",
+	})
+	require.Equal(t, codes.AlreadyExists, status.Convert(err).Code())
+	_, err = suite.service.LeaseResource(ctx, &pb.LeaseResourceRequest{
+		ResourceId:      "/local/test/1",
+		CreatorExecutor: "executor-1",
+		JobId:           "test-job-1",
+		CreatorWorkerId: "test-worker-5",
+	})
+	require.Equal(t, codes.AlreadyExists, status.Convert(err).Code())
+
+	// the resource leased is not visible before the creation is finalized
+	_, err = suite.service.QueryResource(ctx, &pb.QueryResourceRequest{ResourceId: "/local/test/7"})
+	require.Equal(t, codes.NotFound, status.Convert(err).Code())
+	_, err = suite.service.CreateResource(ctx, &pb.CreateResourceRequest{
+		ResourceId:      "/local/test/7",
+		CreatorExecutor: "executor-2",
+		JobId:           "test-job-1",
+		CreatorWorkerId: "test-worker-6",
+	})
+	require.Equal(t, codes.AlreadyExists, status.Convert(err).Code())
+	createReq := &pb.CreateResourceRequest{
+		ResourceId:      leaseReq.ResourceId,
+		CreatorExecutor: leaseReq.CreatorExecutor,
+		JobId:           leaseReq.JobId,
+		CreatorWorkerId: leaseReq.CreatorWorkerId,
+	}
+	_, err = suite.service.CreateResource(ctx, createReq)
+	require.NoError(t, err)
+	// a retry after the response is lost
+	_, err = suite.service.CreateResource(ctx, createReq)
+	require.NoError(t, err)
+	lease, err := suite.meta.GetResourceLeaseByID(ctx, "/local/test/7")
+	require.NoError(t, err)
+	require.True(t, lease.Finalized)
+
+	// removing a resource in creation releases its lease
+	_, err = suite.service.LeaseResource(ctx, &pb.LeaseResourceRequest{
+		ResourceId:      "/local/test/8",
+		CreatorExecutor: "executor-2",
+		JobId:           "test-job-1",
+		CreatorWorkerId: "test-worker-6",
+	})
+	require.NoError(t, err)
+	_, err = suite.service.RemoveResource(ctx, &pb.RemoveResourceRequest{ResourceId: "/local/test/8"})
+	require.NoError(t, err)
+	_, err = suite.service.RemoveResource(ctx, &pb.RemoveResourceRequest{ResourceId: "/local/test/8"})
+	require.Equal(t, codes.NotFound, status.Convert(err).Code())
+
+	// the leases of an offline executor are removed
+	_, err = suite.service.LeaseResource(ctx, &pb.LeaseResourceRequest{
+		ResourceId:      "/local/test/9",
+		CreatorExecutor: "executor-2",
+		JobId:           "test-job-1",
+		CreatorWorkerId: "test-worker-6",
+	})
+	require.NoError(t, err)
+	suite.OfflineExecutor(t, "executor-2")
+	require.Eventually(t, func() bool {
+		_, err := suite.meta.GetResourceLeaseByID(ctx, "/local/test/9")
+		return pkgOrm.IsNotFoundError(err)
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestServiceReconcileResourceLeases(t *testing.T) {
+	suite := newServiceTestSuite(t)
+	suite.LoadMockData()
+
+	ctx := context.Background()
+	// the old leader failed before finalizing the lease
+	require.NoError(t, suite.meta.UpsertResourceLease(ctx, &resourcemeta.ResourceLease{
+		ID:       "/local/test/1",
+		Job:      "test-job-1",
+		Worker:   "test-worker-1",
+		Executor: "executor-1",
+	}))
+	// the creation in flight
+	require.NoError(t, suite.meta.UpsertResourceLease(ctx, &resourcemeta.ResourceLease{
+		ID:       "/local/test/7",
+		Job:      "test-job-1",
+		Worker:   "test-worker-5",
+		Executor: "executor-1",
+	}))
+	// the creation on an offline executor
+	require.NoError(t, suite.meta.UpsertResourceLease(ctx, &resourcemeta.ResourceLease{
+		ID:       "/local/test/8",
+		Job:      "test-job-1",
+		Worker:   "test-worker-6",
+		Executor: "executor-5",
+	}))
+	// the lease of a removed resource
+	require.NoError(t, suite.meta.UpsertResourceLease(ctx, &resourcemeta.ResourceLease{
+		ID:        "/local/test/9",
+		Job:       "test-job-1",
+		Worker:    "test-worker-6",
+		Executor:  "executor-1",
+		Finalized: true,
+	}))
+
+	suite.Start()
+	defer suite.Stop()
+
+	require.Eventually(t, func() bool {
+		leases, err := suite.meta.QueryResourceLeases(ctx)
+		require.NoError(t, err)
+		lease, err := suite.meta.GetResourceLeaseByID(ctx, "/local/test/1")
+		require.NoError(t, err)
+		return len(leases) == 2 && lease.Finalized
+	}, 5*time.Second, 10*time.Millisecond)
+	lease, err := suite.meta.GetResourceLeaseByID(ctx, "/local/test/7")
+	require.NoError(t, err)
+	require.False(t, lease.Finalized)
+}
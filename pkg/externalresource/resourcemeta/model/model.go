@@ -72,6 +72,36 @@ func (m *ResourceMeta) Map() map[string]interface{} {
 	}
 }
 
+// ResourceLeaseUpdateColumns is used in gorm update
+var ResourceLeaseUpdateColumns = []string{
+	"updated_at",
+	"job_id",
+	"worker_id",
+	"executor_id",
+	"finalized",
+}
+
+// ResourceLease records the creation of a resource. It is written before the
+// resource is physically created and finalized after the ResourceMeta is
+// written, so that a new leader of the resource manager can reconcile the
+// creations interrupted by a failover.
+type ResourceLease struct {
+	ormModel.Model
+	ID       ResourceID `json:"id" gorm:"column:id;type:varchar(64) not null;uniqueIndex:uidx_lid"`
+	Job      JobID      `json:"job" gorm:"column:job_id;type:varchar(64) not null"`
+	Worker   WorkerID   `json:"worker" gorm:"column:worker_id;type:varchar(64) not null"`
+	Executor ExecutorID `json:"executor" gorm:"column:executor_id;type:varchar(64) not null;index:idx_lei"`
+	// Finalized is true after the ResourceMeta is written, the lease is kept
+	// until the resource is removed, so that the retries of the creation are
+	// idempotent.
+	Finalized bool `json:"finalized" gorm:"column:finalized;type:BOOLEAN"`
+}
+
+// IsHeldBy returns whether the lease is held by the given creator.
+func (l *ResourceLease) IsHeldBy(job JobID, worker WorkerID, executor ExecutorID) bool {
+	return l.Job == job && l.Worker == worker && l.Executor == executor
+}
+
 // GCTodoEntry records a future need for GC'ing a resource.
 type GCTodoEntry struct {
 	ID           ResourceID `json:"id"`
@@ -8,4 +8,11 @@ type Config struct {
 // LocalFileConfig defines configurations for a local file based resource
 type LocalFileConfig struct {
 	BaseDir string `json:"base-dir" toml:"base-dir"`
+
+	// Quota is the max bytes of the local file resources on the executor,
+	// 0 means unlimited.
+	Quota int64 `json:"quota" toml:"quota"`
+	// JobQuota is the max bytes of the local file resources of a job on the
+	// executor, 0 means unlimited.
+	JobQuota int64 `json:"job-quota" toml:"job-quota"`
 }
@@ -0,0 +1,133 @@
+// Package faultinject provides hooks to inject faults into the framework
+// runtime, such as dropping heartbeats, delaying worker dispatching and
+// failing metastore writes. It is used by integration and chaos tests to
+// exercise failover paths deterministically.
+//
+// An Injector is provided to masters and workers through deps, and all
+// methods of a nil *Injector are no-ops, so the framework calls the hooks
+// unconditionally.
+package faultinject
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+// Injector records the faults to inject. Every fault is armed by a test and
+// consumed by the first hook point that hits it.
+type Injector struct {
+	mu sync.Mutex
+	// droppedHeartbeats is the number of heartbeats to drop for each worker.
+	droppedHeartbeats map[string]int
+	// dispatchDelays is the delay of the next dispatching for each worker,
+	// anyWorker matches the next dispatching of any worker.
+	dispatchDelays map[string]time.Duration
+	// failedMetaWrites is the set of masters and workers whose next
+	// metastore write fails.
+	failedMetaWrites map[string]struct{}
+}
+
+// anyWorker is the wildcard key matching any worker ID.
+const anyWorker = ""
+
+// NewInjector creates a new Injector with no fault armed.
+func NewInjector() *Injector {
+	return &Injector{
+		droppedHeartbeats: make(map[string]int),
+		dispatchDelays:    make(map[string]time.Duration),
+		failedMetaWrites:  make(map[string]struct{}),
+	}
+}
+
+// DropNextHeartbeat makes the master drop the next heartbeat received from
+// the worker. Calling it n times drops the next n heartbeats.
+func (i *Injector) DropNextHeartbeat(workerID string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.droppedHeartbeats[workerID]++
+}
+
+// ShouldDropHeartbeat returns whether the heartbeat from the worker should
+// be dropped, and consumes the fault if so.
+func (i *Injector) ShouldDropHeartbeat(workerID string) bool {
+	if i == nil {
+		return false
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.droppedHeartbeats[workerID] == 0 {
+		return false
+	}
+	i.droppedHeartbeats[workerID]--
+	if i.droppedHeartbeats[workerID] == 0 {
+		delete(i.droppedHeartbeats, workerID)
+	}
+	return true
+}
+
+// DelayNextDispatch delays dispatching the worker to the executor by delay.
+// An empty workerID delays the next dispatching of any worker, which is
+// useful when the worker ID is generated by the master.
+func (i *Injector) DelayNextDispatch(workerID string, delay time.Duration) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.dispatchDelays[workerID] = delay
+}
+
+// WaitDispatch blocks for the delay armed for the worker, it returns early
+// with the error of ctx if ctx is done.
+func (i *Injector) WaitDispatch(ctx context.Context, workerID string) error {
+	if i == nil {
+		return nil
+	}
+	delay, ok := i.takeDispatchDelay(workerID)
+	if !ok {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (i *Injector) takeDispatchDelay(workerID string) (time.Duration, bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for _, key := range []string{workerID, anyWorker} {
+		if delay, ok := i.dispatchDelays[key]; ok {
+			delete(i.dispatchDelays, key)
+			return delay, true
+		}
+	}
+	return 0, false
+}
+
+// FailNextMetaWrite makes the next framework metastore write of the master
+// or worker fail once.
+func (i *Injector) FailNextMetaWrite(id string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.failedMetaWrites[id] = struct{}{}
+}
+
+// CheckMetaWrite returns an error if a metastore write of the master or
+// worker should fail, and consumes the fault if so.
+func (i *Injector) CheckMetaWrite(id string) error {
+	if i == nil {
+		return nil
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if _, ok := i.failedMetaWrites[id]; !ok {
+		return nil
+	}
+	delete(i.failedMetaWrites, id)
+	return derror.ErrMetaWriteFaultInjected.GenWithStackByArgs(id)
+}
@@ -0,0 +1,72 @@
+package faultinject
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+func TestNilInjector(t *testing.T) {
+	t.Parallel()
+
+	var inj *Injector
+	require.False(t, inj.ShouldDropHeartbeat("worker-1"))
+	require.NoError(t, inj.WaitDispatch(context.Background(), "worker-1"))
+	require.NoError(t, inj.CheckMetaWrite("worker-1"))
+}
+
+func TestDropHeartbeat(t *testing.T) {
+	t.Parallel()
+
+	inj := NewInjector()
+	require.False(t, inj.ShouldDropHeartbeat("worker-1"))
+
+	inj.DropNextHeartbeat("worker-1")
+	inj.DropNextHeartbeat("worker-1")
+	require.False(t, inj.ShouldDropHeartbeat("worker-2"))
+	require.True(t, inj.ShouldDropHeartbeat("worker-1"))
+	require.True(t, inj.ShouldDropHeartbeat("worker-1"))
+	require.False(t, inj.ShouldDropHeartbeat("worker-1"))
+}
+
+func TestDelayDispatch(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	inj := NewInjector()
+
+	inj.DelayNextDispatch("worker-1", 50*time.Millisecond)
+	start := time.Now()
+	require.NoError(t, inj.WaitDispatch(ctx, "worker-1"))
+	require.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	// the delay is consumed
+	start = time.Now()
+	require.NoError(t, inj.WaitDispatch(ctx, "worker-1"))
+	require.Less(t, time.Since(start), 50*time.Millisecond)
+
+	// wildcard
+	inj.DelayNextDispatch("", time.Hour)
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	err := inj.WaitDispatch(cancelCtx, "worker-2")
+	require.ErrorIs(t, err, context.Canceled)
+	require.NoError(t, inj.WaitDispatch(ctx, "worker-3"))
+}
+
+func TestFailMetaWrite(t *testing.T) {
+	t.Parallel()
+
+	inj := NewInjector()
+	require.NoError(t, inj.CheckMetaWrite("master-1"))
+
+	inj.FailNextMetaWrite("master-1")
+	require.NoError(t, inj.CheckMetaWrite("worker-1"))
+	err := inj.CheckMetaWrite("master-1")
+	require.True(t, derror.ErrMetaWriteFaultInjected.Equal(err))
+	require.NoError(t, inj.CheckMetaWrite("master-1"))
+}
@@ -0,0 +1,55 @@
+package interceptor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+const defaultSlowThreshold = "1s"
+
+// Config configures the interceptors of gRPC servers.
+type Config struct {
+	// SlowThresholdStr is the duration above which requests are logged as
+	// slow requests.
+	SlowThresholdStr string `toml:"slow-threshold" json:"slow-threshold"`
+	// RateLimit is the number of requests per second allowed for each
+	// caller, 0 means unlimited.
+	RateLimit float64 `toml:"rate-limit" json:"rate-limit"`
+	// RateBurst is the max number of requests a caller can send at once, it
+	// defaults to RateLimit.
+	RateBurst int `toml:"rate-burst" json:"rate-burst"`
+
+	SlowThreshold time.Duration `toml:"-" json:"-"`
+}
+
+// Adjust validates the config and fills the default values
+func (c *Config) Adjust() error {
+	if c.SlowThresholdStr == "" {
+		c.SlowThresholdStr = defaultSlowThreshold
+	}
+	threshold, err := time.ParseDuration(c.SlowThresholdStr)
+	if err != nil {
+		return errors.ErrInterceptorInvalidConfig.GenWithStackByArgs(
+			fmt.Sprintf("slow-threshold %s: %v", c.SlowThresholdStr, err))
+	}
+	if threshold <= 0 {
+		return errors.ErrInterceptorInvalidConfig.GenWithStackByArgs("slow-threshold must be positive")
+	}
+	c.SlowThreshold = threshold
+
+	if c.RateLimit < 0 {
+		return errors.ErrInterceptorInvalidConfig.GenWithStackByArgs("rate-limit can't be negative")
+	}
+	if c.RateBurst < 0 {
+		return errors.ErrInterceptorInvalidConfig.GenWithStackByArgs("rate-burst can't be negative")
+	}
+	if c.RateBurst == 0 {
+		c.RateBurst = int(c.RateLimit)
+		if c.RateBurst < 1 {
+			c.RateBurst = 1
+		}
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package interceptor
+
+import (
+	"context"
+	"net"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/hanfei1991/microcosm/pkg/redact"
+)
+
+// CallerIDHeader is the gRPC metadata key with which a client identifies
+// itself, the address of the peer is the identity of clients without it.
+const CallerIDHeader = "x-caller-id"
+
+// AuthFunc authenticates a request of the full method, such as
+// "/pb.Master/SubmitJob". The request is rejected if an error is returned,
+// which should be a gRPC status such as codes.Unauthenticated.
+type AuthFunc func(ctx context.Context, fullMethod string) error
+
+var authHook struct {
+	sync.RWMutex
+	fn AuthFunc
+}
+
+// RegisterAuthHook sets the AuthFunc used by the interceptors created by
+// NewUnaryServerInterceptor, requests are not authenticated if it is never
+// called. It should be called before the servers are started.
+func RegisterAuthHook(fn AuthFunc) {
+	authHook.Lock()
+	defer authHook.Unlock()
+	authHook.fn = fn
+}
+
+func getAuthHook() AuthFunc {
+	authHook.RLock()
+	defer authHook.RUnlock()
+	return authHook.fn
+}
+
+// NewUnaryServerInterceptor creates the interceptor chain shared by the gRPC
+// servers, from the outermost: panic recovery, metrics, slow request logging,
+// auth and rate limiting.
+func NewUnaryServerInterceptor(cfg *Config) grpc.UnaryServerInterceptor {
+	interceptors := []grpc.UnaryServerInterceptor{
+		Recovery(),
+		Metrics(),
+	}
+	// the threshold is zero if the config is not adjusted, such as in tests
+	if cfg.SlowThreshold > 0 {
+		interceptors = append(interceptors, SlowLog(cfg.SlowThreshold))
+	}
+	if auth := getAuthHook(); auth != nil {
+		interceptors = append(interceptors, Auth(auth))
+	}
+	if cfg.RateLimit > 0 {
+		interceptors = append(interceptors, RateLimit(cfg.RateLimit, cfg.RateBurst))
+	}
+	return Chain(interceptors...)
+}
+
+// Chain combines interceptors into one, the first one is the outermost.
+func Chain(interceptors ...grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		return chainHandler(interceptors, info, handler)(ctx, req)
+	}
+}
+
+func chainHandler(
+	interceptors []grpc.UnaryServerInterceptor, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+) grpc.UnaryHandler {
+	if len(interceptors) == 0 {
+		return handler
+	}
+	return func(ctx context.Context, req interface{}) (interface{}, error) {
+		return interceptors[0](ctx, req, info, chainHandler(interceptors[1:], info, handler))
+	}
+}
+
+// Recovery converts a panic in handling a request to an Internal error, so
+// that the server is not crashed by a bad request.
+func Recovery() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+	) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				panicCounter.WithLabelValues(info.FullMethod).Inc()
+				log.L().Error("panic in handling grpc request",
+					zap.String("method", info.FullMethod),
+					zap.Any("panic", r),
+					zap.ByteString("stack", debug.Stack()))
+				resp, err = nil, status.Errorf(codes.Internal, "panic in %s: %v", info.FullMethod, r)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// Metrics records the duration of requests by methods and status codes.
+func Metrics() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		handleDuration.WithLabelValues(info.FullMethod, status.Code(err).String()).
+			Observe(time.Since(start).Seconds())
+		return resp, err
+	}
+}
+
+// SlowLog logs the requests taking longer than threshold.
+func SlowLog(threshold time.Duration) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		if duration := time.Since(start); duration >= threshold {
+			log.L().Warn("slow grpc request",
+				zap.String("method", info.FullMethod),
+				zap.String("caller", CallerIdentity(ctx)),
+				zap.Duration("duration", duration),
+				redact.Any("request", req),
+				zap.Error(err))
+		}
+		return resp, err
+	}
+}
+
+// Auth rejects the requests that fail to be authenticated by fn.
+func Auth(fn AuthFunc) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if err := fn(ctx, info.FullMethod); err != nil {
+			rejectedCounter.WithLabelValues(info.FullMethod, "auth").Inc()
+			log.L().Warn("grpc request is not authenticated",
+				zap.String("method", info.FullMethod),
+				zap.String("caller", CallerIdentity(ctx)),
+				zap.Error(err))
+			if _, ok := status.FromError(err); !ok {
+				err = status.Error(codes.Unauthenticated, err.Error())
+			}
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// RateLimit rejects the requests of callers sending faster than limit per
+// second with ResourceExhausted, which can be retried later by clients. Each
+// caller has its own token bucket of size burst.
+func RateLimit(limit float64, burst int) grpc.UnaryServerInterceptor {
+	limiters := newCallerLimiters(limit, burst)
+	return func(
+		ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		caller := CallerIdentity(ctx)
+		if !limiters.allow(caller) {
+			rejectedCounter.WithLabelValues(info.FullMethod, "rate-limit").Inc()
+			return nil, status.Errorf(codes.ResourceExhausted,
+				"too many requests from %s, please retry later", caller)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// CallerIdentity returns the identity of the caller of a request, which is
+// the CallerIDHeader set by the client, or the host of the peer.
+func CallerIdentity(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(CallerIDHeader); len(ids) > 0 && ids[0] != "" {
+			return ids[0]
+		}
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "unknown"
+	}
+	addr := p.Addr.String()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// limiterIdleTimeout is how long the limiter of a caller is kept after its
+// last request.
+const limiterIdleTimeout = 10 * time.Minute
+
+// callerLimiters are the token buckets of callers.
+type callerLimiters struct {
+	limit rate.Limit
+	burst int
+
+	mu        sync.Mutex
+	limiters  map[string]*callerLimiter
+	lastSweep time.Time
+}
+
+type callerLimiter struct {
+	*rate.Limiter
+	lastSeen time.Time
+}
+
+func newCallerLimiters(limit float64, burst int) *callerLimiters {
+	return &callerLimiters{
+		limit:     rate.Limit(limit),
+		burst:     burst,
+		limiters:  make(map[string]*callerLimiter),
+		lastSweep: time.Now(),
+	}
+}
+
+func (l *callerLimiters) allow(caller string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.lastSweep) >= limiterIdleTimeout {
+		l.lastSweep = now
+		for id, limiter := range l.limiters {
+			if now.Sub(limiter.lastSeen) >= limiterIdleTimeout {
+				delete(l.limiters, id)
+			}
+		}
+	}
+
+	limiter, ok := l.limiters[caller]
+	if !ok {
+		limiter = &callerLimiter{Limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.limiters[caller] = limiter
+	}
+	limiter.lastSeen = now
+	return limiter.AllowN(now, 1)
+}
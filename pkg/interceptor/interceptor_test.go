@@ -0,0 +1,109 @@
+package interceptor
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+var testInfo = &grpc.UnaryServerInfo{FullMethod: "/pb.Test/Method"}
+
+func okHandler(ctx context.Context, req interface{}) (interface{}, error) {
+	return req, nil
+}
+
+func TestChain(t *testing.T) {
+	t.Parallel()
+
+	var order []string
+	record := func(name string) grpc.UnaryServerInterceptor {
+		return func(
+			ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+		) (interface{}, error) {
+			order = append(order, name)
+			return handler(ctx, req)
+		}
+	}
+	resp, err := Chain(record("a"), record("b"))(context.Background(), "req", testInfo, okHandler)
+	require.NoError(t, err)
+	require.Equal(t, "req", resp)
+	require.Equal(t, []string{"a", "b"}, order)
+
+	resp, err = Chain()(context.Background(), "req", testInfo, okHandler)
+	require.NoError(t, err)
+	require.Equal(t, "req", resp)
+}
+
+func TestRecovery(t *testing.T) {
+	t.Parallel()
+
+	_, err := Recovery()(context.Background(), nil, testInfo,
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			panic("mock panic")
+		})
+	require.Equal(t, codes.Internal, status.Code(err))
+	require.Contains(t, err.Error(), "mock panic")
+}
+
+func TestAuth(t *testing.T) {
+	t.Parallel()
+
+	auth := Auth(func(ctx context.Context, fullMethod string) error {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if len(md.Get("token")) == 0 {
+			return errors.ErrInterceptorInvalidConfig.GenWithStackByArgs("no token")
+		}
+		return nil
+	})
+	_, err := auth(context.Background(), nil, testInfo, okHandler)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("token", "t"))
+	resp, err := auth(ctx, "req", testInfo, okHandler)
+	require.NoError(t, err)
+	require.Equal(t, "req", resp)
+}
+
+func TestRateLimit(t *testing.T) {
+	t.Parallel()
+
+	limit := RateLimit(0.001, 2)
+	ctx1 := metadata.NewIncomingContext(context.Background(), metadata.Pairs(CallerIDHeader, "caller-1"))
+	ctx2 := peer.NewContext(context.Background(),
+		&peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 2), Port: 10240}})
+	require.Equal(t, "127.0.0.2", CallerIdentity(ctx2))
+
+	for i := 0; i < 2; i++ {
+		_, err := limit(ctx1, nil, testInfo, okHandler)
+		require.NoError(t, err)
+	}
+	_, err := limit(ctx1, nil, testInfo, okHandler)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	// callers are limited separately
+	_, err = limit(ctx2, nil, testInfo, okHandler)
+	require.NoError(t, err)
+}
+
+func TestConfigAdjust(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{RateLimit: 0.5}
+	require.NoError(t, cfg.Adjust())
+	require.Equal(t, time.Second, cfg.SlowThreshold)
+	require.Equal(t, 1, cfg.RateBurst)
+
+	cfg = &Config{SlowThresholdStr: "abc"}
+	require.True(t, errors.ErrInterceptorInvalidConfig.Equal(cfg.Adjust()))
+	cfg = &Config{RateLimit: -1}
+	require.True(t, errors.ErrInterceptorInvalidConfig.Equal(cfg.Adjust()))
+}
@@ -0,0 +1,37 @@
+package interceptor
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	handleDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "dataflow",
+			Subsystem: "grpc_server",
+			Name:      "handle_duration_seconds",
+			Help:      "time spent handling gRPC requests",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 18), // 0.5ms ~ 65s
+		}, []string{"method", "code"})
+	rejectedCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "dataflow",
+			Subsystem: "grpc_server",
+			Name:      "rejected_total",
+			Help:      "number of gRPC requests rejected by auth or rate limiting",
+		}, []string{"method", "reason"})
+	panicCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "dataflow",
+			Subsystem: "grpc_server",
+			Name:      "panic_total",
+			Help:      "number of gRPC requests that panicked",
+		}, []string{"method"})
+)
+
+// InitMetrics registers the gRPC server metrics
+func InitMetrics(registry *prometheus.Registry) {
+	registry.MustRegister(handleDuration)
+	registry.MustRegister(rejectedCounter)
+	registry.MustRegister(panicCounter)
+}
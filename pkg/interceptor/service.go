@@ -0,0 +1,56 @@
+package interceptor
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// RegisterService registers srv to gs with the unary methods intercepted by
+// interceptor. It is used for the gRPC servers created by others, such as the
+// one of the embedded etcd, to which no interceptor can be added by options.
+// The interceptor of gs is still called before interceptor, and the streams
+// are not intercepted.
+func RegisterService(
+	gs *grpc.Server,
+	desc *grpc.ServiceDesc,
+	srv interface{},
+	interceptor grpc.UnaryServerInterceptor,
+) {
+	gs.RegisterService(WrapServiceDesc(desc, interceptor), srv)
+}
+
+// WrapServiceDesc returns a copy of desc whose unary methods are intercepted
+// by interceptor, see RegisterService.
+func WrapServiceDesc(desc *grpc.ServiceDesc, interceptor grpc.UnaryServerInterceptor) *grpc.ServiceDesc {
+	wrapped := *desc
+	wrapped.Methods = make([]grpc.MethodDesc, 0, len(desc.Methods))
+	for _, method := range desc.Methods {
+		wrapped.Methods = append(wrapped.Methods, grpc.MethodDesc{
+			MethodName: method.MethodName,
+			Handler:    wrapMethodHandler(method.Handler, interceptor),
+		})
+	}
+	return &wrapped
+}
+
+type methodHandler = func(
+	srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error)
+
+func wrapMethodHandler(handler methodHandler, interceptor grpc.UnaryServerInterceptor) methodHandler {
+	return func(
+		srv interface{}, ctx context.Context, dec func(interface{}) error, serverInterceptor grpc.UnaryServerInterceptor,
+	) (interface{}, error) {
+		if serverInterceptor == nil {
+			return handler(srv, ctx, dec, interceptor)
+		}
+		return handler(srv, ctx, dec, func(
+			ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, h grpc.UnaryHandler,
+		) (interface{}, error) {
+			return serverInterceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return interceptor(ctx, req, info, h)
+			})
+		})
+	}
+}
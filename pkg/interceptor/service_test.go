@@ -0,0 +1,82 @@
+package interceptor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+type echoServer interface {
+	Echo(context.Context, string) (string, error)
+}
+
+type echoServerImpl struct{}
+
+func (s *echoServerImpl) Echo(ctx context.Context, req string) (string, error) {
+	return req, nil
+}
+
+// echoHandler is like the handlers generated by protoc-gen-go.
+func echoHandler(
+	srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var in string
+	if err := dec(&in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(echoServer).Echo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Echo/Echo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(echoServer).Echo(ctx, req.(string))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func TestWrapServiceDesc(t *testing.T) {
+	t.Parallel()
+
+	desc := &grpc.ServiceDesc{
+		ServiceName: "pb.Echo",
+		HandlerType: (*echoServer)(nil),
+		Methods: []grpc.MethodDesc{
+			{MethodName: "Echo", Handler: echoHandler},
+		},
+	}
+	var calls []string
+	record := func(name string) grpc.UnaryServerInterceptor {
+		return func(
+			ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+		) (interface{}, error) {
+			calls = append(calls, name+":"+info.FullMethod)
+			return handler(ctx, req)
+		}
+	}
+	wrapped := WrapServiceDesc(desc, record("inner"))
+	require.Equal(t, "pb.Echo", wrapped.ServiceName)
+	require.Len(t, wrapped.Methods, 1)
+	// the original description is not changed
+	require.NotEqual(t, &desc.Methods[0], &wrapped.Methods[0])
+
+	dec := func(in interface{}) error {
+		*(in.(*string)) = "hello"
+		return nil
+	}
+	resp, err := wrapped.Methods[0].Handler(&echoServerImpl{}, context.Background(), dec, nil)
+	require.NoError(t, err)
+	require.Equal(t, "hello", resp)
+	require.Equal(t, []string{"inner:/pb.Echo/Echo"}, calls)
+
+	// the interceptor of the server is called first
+	calls = nil
+	resp, err = wrapped.Methods[0].Handler(&echoServerImpl{}, context.Background(), dec, record("outer"))
+	require.NoError(t, err)
+	require.Equal(t, "hello", resp)
+	require.Equal(t, []string{"outer:/pb.Echo/Echo", "inner:/pb.Echo/Echo"}, calls)
+}
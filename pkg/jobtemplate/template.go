@@ -0,0 +1,159 @@
+// Package jobtemplate renders job config templates. A template is a job
+// config with placeholders of the form ${name} or ${name:-default}, a
+// placeholder without a default value must be given a parameter value when
+// the template is rendered. A parameter name consists of letters, digits,
+// '_' and '-'. "$$" is rendered as a literal '$'.
+package jobtemplate
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+// Placeholder is a placeholder in a template.
+type Placeholder struct {
+	Name string
+	// Default is used if no value is given, a placeholder without a default
+	// value is required.
+	Default    string
+	HasDefault bool
+}
+
+type segment struct {
+	literal     []byte
+	placeholder *Placeholder
+}
+
+// Template is a parsed job config template.
+type Template struct {
+	segments     []segment
+	placeholders map[string]*Placeholder
+}
+
+// Parse parses a template.
+func Parse(content []byte) (*Template, error) {
+	t := &Template{placeholders: make(map[string]*Placeholder)}
+	var literal []byte
+	for i := 0; i < len(content); i++ {
+		if content[i] != '$' || i+1 == len(content) {
+			literal = append(literal, content[i])
+			continue
+		}
+		switch content[i+1] {
+		case '$':
+			literal = append(literal, '$')
+			i++
+			continue
+		case '{':
+		default:
+			literal = append(literal, content[i])
+			continue
+		}
+
+		end := bytes.IndexByte(content[i+2:], '}')
+		if end < 0 {
+			return nil, errors.ErrJobTemplateInvalid.GenWithStackByArgs("unterminated placeholder at offset " + strconv.Itoa(i))
+		}
+		ph, err := parsePlaceholder(string(content[i+2 : i+2+end]))
+		if err != nil {
+			return nil, err
+		}
+		if old, ok := t.placeholders[ph.Name]; ok {
+			if old.HasDefault != ph.HasDefault || old.Default != ph.Default {
+				return nil, errors.ErrJobTemplateInvalid.GenWithStackByArgs(
+					"placeholder " + ph.Name + " has different default values")
+			}
+			ph = old
+		} else {
+			t.placeholders[ph.Name] = ph
+		}
+		if len(literal) > 0 {
+			t.segments = append(t.segments, segment{literal: literal})
+			literal = nil
+		}
+		t.segments = append(t.segments, segment{placeholder: ph})
+		i += 2 + end
+	}
+	if len(literal) > 0 {
+		t.segments = append(t.segments, segment{literal: literal})
+	}
+	return t, nil
+}
+
+func parsePlaceholder(s string) (*Placeholder, error) {
+	ph := &Placeholder{Name: s}
+	if i := strings.Index(s, ":-"); i >= 0 {
+		ph.Name, ph.Default, ph.HasDefault = s[:i], s[i+2:], true
+	}
+	if !validName(ph.Name) {
+		return nil, errors.ErrJobTemplateInvalid.GenWithStackByArgs("invalid parameter name " + ph.Name)
+	}
+	return ph, nil
+}
+
+func validName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '_', c == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Placeholders returns the placeholders of the template sorted by name.
+func (t *Template) Placeholders() []Placeholder {
+	ret := make([]Placeholder, 0, len(t.placeholders))
+	for _, ph := range t.placeholders {
+		ret = append(ret, *ph)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+	return ret
+}
+
+// Render renders the template with the parameter values. All the required
+// placeholders must be given a value, and a value must not be given to a
+// name that is not a placeholder, which is usually a typo.
+func (t *Template) Render(params map[string]string) ([]byte, error) {
+	var missing, unknown []string
+	for _, ph := range t.placeholders {
+		if _, ok := params[ph.Name]; !ok && !ph.HasDefault {
+			missing = append(missing, ph.Name)
+		}
+	}
+	for name := range params {
+		if _, ok := t.placeholders[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, errors.ErrJobTemplateParams.GenWithStackByArgs("missing " + strings.Join(missing, ", "))
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, errors.ErrJobTemplateParams.GenWithStackByArgs("unknown " + strings.Join(unknown, ", "))
+	}
+
+	var buf bytes.Buffer
+	for _, seg := range t.segments {
+		if seg.placeholder == nil {
+			buf.Write(seg.literal)
+			continue
+		}
+		if v, ok := params[seg.placeholder.Name]; ok {
+			buf.WriteString(v)
+		} else {
+			buf.WriteString(seg.placeholder.Default)
+		}
+	}
+	return buf.Bytes(), nil
+}
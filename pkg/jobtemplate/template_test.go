@@ -0,0 +1,47 @@
+package jobtemplate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+func TestParseInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, content := range []string{
+		"name: ${table",
+		"name: ${}",
+		"name: ${a b}",
+		"name: ${a:-x} ${a:-y}",
+	} {
+		_, err := Parse([]byte(content))
+		require.True(t, errors.ErrJobTemplateInvalid.Equal(err), content)
+	}
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	tmpl, err := Parse([]byte("name: ${table}-sync\nworkers: ${workers:-4}\ncost: $$5 $HOME ${table}\n"))
+	require.NoError(t, err)
+	require.Equal(t, []Placeholder{
+		{Name: "table"},
+		{Name: "workers", Default: "4", HasDefault: true},
+	}, tmpl.Placeholders())
+
+	content, err := tmpl.Render(map[string]string{"table": "orders"})
+	require.NoError(t, err)
+	require.Equal(t, "name: orders-sync\nworkers: 4\ncost: $5 $HOME orders\n", string(content))
+
+	content, err = tmpl.Render(map[string]string{"table": "users", "workers": "8"})
+	require.NoError(t, err)
+	require.Equal(t, "name: users-sync\nworkers: 8\ncost: $5 $HOME users\n", string(content))
+
+	_, err = tmpl.Render(nil)
+	require.True(t, errors.ErrJobTemplateParams.Equal(err))
+	_, err = tmpl.Render(map[string]string{"table": "orders", "tabel": "orders"})
+	require.True(t, errors.ErrJobTemplateParams.Equal(err))
+}
@@ -0,0 +1,171 @@
+// Package lockdiag provides instrumented locking to diagnose deadlocks and
+// lock contention. The time spent waiting for a lock is always reported to
+// metrics, and locks held or waited for longer than a threshold are logged
+// with goroutine stacks if the threshold is set by SetLongHoldThreshold,
+// which should only be done for debugging since capturing stacks is
+// expensive.
+package lockdiag
+
+import (
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+)
+
+// maxAllStacksSize limits the size of the stacks of all goroutines dumped
+// when a wait blocks for too long.
+const maxAllStacksSize = 1 << 20
+
+var longHoldThreshold atomic.Duration
+
+// SetLongHoldThreshold sets the threshold above which holding or waiting
+// for a lock is logged with stacks, 0 disables the logging.
+func SetLongHoldThreshold(threshold time.Duration) {
+	longHoldThreshold.Store(threshold)
+}
+
+// LongHoldThreshold returns the threshold set by SetLongHoldThreshold.
+func LongHoldThreshold() time.Duration {
+	return longHoldThreshold.Load()
+}
+
+// Mutex is a sync.Mutex instrumented for diagnostics.
+type Mutex struct {
+	mu     sync.Mutex
+	name   string
+	logger log.Logger
+
+	waitDuration prometheus.Observer
+
+	// holderMu protects the information of the current holder, which is
+	// recorded only if the long hold threshold is set.
+	holderMu    sync.Mutex
+	lockedAt    time.Time
+	holderStack []byte
+}
+
+// NewMutex creates a Mutex, name identifies the lock in metrics and logs.
+func NewMutex(name string, logger log.Logger) *Mutex {
+	return &Mutex{
+		name:         name,
+		logger:       logger,
+		waitDuration: lockWaitDuration.WithLabelValues(name),
+	}
+}
+
+// Lock locks the mutex.
+func (m *Mutex) Lock() {
+	threshold := LongHoldThreshold()
+	startTime := time.Now()
+	if threshold <= 0 {
+		m.mu.Lock()
+		m.waitDuration.Observe(time.Since(startTime).Seconds())
+		return
+	}
+
+	waiterStack := debug.Stack()
+	timer := time.AfterFunc(threshold, func() {
+		m.holderMu.Lock()
+		holderStack := m.holderStack
+		lockedAt := m.lockedAt
+		m.holderMu.Unlock()
+		m.logger.Warn("waiting for lock for too long, it may be a deadlock",
+			zap.String("lock", m.name),
+			zap.Duration("threshold", threshold),
+			zap.Time("locked-at", lockedAt),
+			zap.ByteString("waiter-stack", waiterStack),
+			zap.ByteString("holder-stack", holderStack))
+	})
+	m.mu.Lock()
+	timer.Stop()
+	m.waitDuration.Observe(time.Since(startTime).Seconds())
+
+	m.holderMu.Lock()
+	m.lockedAt = time.Now()
+	m.holderStack = waiterStack
+	m.holderMu.Unlock()
+}
+
+// TryLock tries to lock the mutex without blocking, and reports whether it
+// succeeds. It is used by diagnostics that must not be blocked by a holder
+// that is stuck.
+func (m *Mutex) TryLock() bool {
+	if !m.mu.TryLock() {
+		return false
+	}
+	if LongHoldThreshold() > 0 {
+		m.holderMu.Lock()
+		m.lockedAt = time.Now()
+		m.holderStack = debug.Stack()
+		m.holderMu.Unlock()
+	}
+	return true
+}
+
+// Holder returns the time the mutex is locked and the stack of the holder,
+// they are only recorded if the long hold threshold is set. A zero lockedAt
+// means unknown.
+func (m *Mutex) Holder() (lockedAt time.Time, stack []byte) {
+	m.holderMu.Lock()
+	defer m.holderMu.Unlock()
+	return m.lockedAt, m.holderStack
+}
+
+// Unlock unlocks the mutex.
+func (m *Mutex) Unlock() {
+	m.holderMu.Lock()
+	lockedAt, holderStack := m.lockedAt, m.holderStack
+	m.lockedAt, m.holderStack = time.Time{}, nil
+	m.holderMu.Unlock()
+
+	m.mu.Unlock()
+
+	// lockedAt is zero if the threshold was not set when locking.
+	if lockedAt.IsZero() {
+		return
+	}
+	threshold := LongHoldThreshold()
+	if held := time.Since(lockedAt); threshold > 0 && held > threshold {
+		m.logger.Warn("lock held for too long",
+			zap.String("lock", m.name),
+			zap.Duration("held", held),
+			zap.ByteString("holder-stack", holderStack))
+	}
+}
+
+// WatchBlocking calls wait, which blocks until something is done such as
+// goroutines exiting, and reports the time blocked to metrics. If the long
+// hold threshold is set and wait blocks for longer, the stacks of all
+// goroutines are logged to find out what is blocking.
+func WatchBlocking(name string, logger log.Logger, wait func()) {
+	startTime := time.Now()
+	defer func() {
+		lockWaitDuration.WithLabelValues(name).Observe(time.Since(startTime).Seconds())
+	}()
+
+	threshold := LongHoldThreshold()
+	if threshold <= 0 {
+		wait()
+		return
+	}
+	timer := time.AfterFunc(threshold, func() {
+		logger.Warn("blocked for too long, it may be a deadlock",
+			zap.String("lock", name),
+			zap.Duration("threshold", threshold),
+			zap.ByteString("all-stacks", allStacks()))
+	})
+	defer timer.Stop()
+	wait()
+}
+
+func allStacks() []byte {
+	buf := make([]byte, maxAllStacksSize)
+	n := runtime.Stack(buf, true /* all */)
+	return buf[:n]
+}
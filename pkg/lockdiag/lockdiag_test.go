@@ -0,0 +1,86 @@
+package lockdiag
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"github.com/stretchr/testify/require"
+)
+
+func testMutexExclusion(t *testing.T, mu *Mutex) {
+	var (
+		wg      sync.WaitGroup
+		counter int
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				mu.Lock()
+				counter++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, 1000, counter)
+}
+
+func TestMutex(t *testing.T) {
+	mu := NewMutex("test-mutex", log.L())
+	testMutexExclusion(t, mu)
+
+	SetLongHoldThreshold(time.Millisecond)
+	defer SetLongHoldThreshold(0)
+	testMutexExclusion(t, mu)
+
+	// holding for too long is logged, and the holder is reset after unlock
+	mu.Lock()
+	require.False(t, mu.lockedAt.IsZero())
+	require.NotEmpty(t, mu.holderStack)
+	time.Sleep(5 * time.Millisecond)
+	mu.Unlock()
+	require.True(t, mu.lockedAt.IsZero())
+	require.Nil(t, mu.holderStack)
+
+	// TryLock fails while the lock is held, and the holder is recorded
+	mu.Lock()
+	require.False(t, mu.TryLock())
+	lockedAt, stack := mu.Holder()
+	require.False(t, lockedAt.IsZero())
+	require.NotEmpty(t, stack)
+	mu.Unlock()
+	require.True(t, mu.TryLock())
+	mu.Unlock()
+
+	// the threshold is disabled while the lock is held
+	mu.Lock()
+	SetLongHoldThreshold(0)
+	mu.Unlock()
+	require.True(t, mu.lockedAt.IsZero())
+	lockedAt, _ = mu.Holder()
+	require.True(t, lockedAt.IsZero())
+}
+
+func TestWatchBlocking(t *testing.T) {
+	called := false
+	WatchBlocking("test-wait", log.L(), func() {
+		called = true
+	})
+	require.True(t, called)
+
+	SetLongHoldThreshold(time.Millisecond)
+	defer SetLongHoldThreshold(0)
+	done := make(chan struct{})
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(done)
+	}()
+	WatchBlocking("test-wait", log.L(), func() {
+		<-done
+	})
+	require.NotEmpty(t, allStacks())
+}
@@ -0,0 +1,19 @@
+package lockdiag
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var lockWaitDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: "dataflow",
+		Subsystem: "lock",
+		Name:      "wait_duration_seconds",
+		Help:      "time spent waiting for an instrumented lock or blocking operation",
+		Buckets:   prometheus.ExponentialBuckets(0.000001, 4, 16), // 1us ~ 1073s
+	}, []string{"lock"})
+
+// InitMetrics registers the lock diagnostics metrics
+func InitMetrics(registry *prometheus.Registry) {
+	registry.MustRegister(lockWaitDuration)
+}
@@ -0,0 +1,119 @@
+package logutil
+
+import (
+	"sync"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+// jobLevels are the log levels overridden for jobs in this process, so that
+// the logs of a misbehaving job can be turned up without flooding the
+// cluster.
+var jobLevels = struct {
+	sync.Map // job ID -> zapcore.Level
+	// count is the number of overridden jobs, the map is not looked up by
+	// every log line if it is zero.
+	count atomic.Int32
+	mu    sync.Mutex
+}{}
+
+// ParseLevel parses a log level, such as "debug" or "warn".
+func ParseLevel(text string) (zapcore.Level, error) {
+	var level zapcore.Level
+	err := level.UnmarshalText([]byte(text))
+	return level, err
+}
+
+// SetJobLevel overrides the log level of the loggers tagged with the job in
+// this process, see WithJobID.
+func SetJobLevel(jobID string, level zapcore.Level) {
+	jobLevels.mu.Lock()
+	defer jobLevels.mu.Unlock()
+
+	if _, loaded := jobLevels.Load(jobID); !loaded {
+		jobLevels.count.Inc()
+	}
+	jobLevels.Store(jobID, level)
+}
+
+// ResetJobLevel removes the overridden log level of the job, the global log
+// level is used again.
+func ResetJobLevel(jobID string) {
+	jobLevels.mu.Lock()
+	defer jobLevels.mu.Unlock()
+
+	if _, loaded := jobLevels.LoadAndDelete(jobID); loaded {
+		jobLevels.count.Dec()
+	}
+}
+
+// UpdateJobLevel overrides the log level of the job by the name of a level,
+// such as "debug", an empty name resets the log level of the job.
+func UpdateJobLevel(jobID string, name string) error {
+	if name == "" {
+		ResetJobLevel(jobID)
+		return nil
+	}
+	level, err := ParseLevel(name)
+	if err != nil {
+		return errors.ErrInvalidLogLevel.GenWithStackByArgs(name)
+	}
+	SetJobLevel(jobID, level)
+	return nil
+}
+
+// JobLevel returns the overridden log level of the job.
+func JobLevel(jobID string) (level zapcore.Level, ok bool) {
+	if jobLevels.count.Load() == 0 {
+		return level, false
+	}
+	value, ok := jobLevels.Load(jobID)
+	if !ok {
+		return level, false
+	}
+	return value.(zapcore.Level), true
+}
+
+// withJobLevel returns a logger following the overridden log level of the
+// job, which may be lower than the level of the logger.
+func withJobLevel(logger log.Logger, jobID string) log.Logger {
+	return log.Logger{Logger: logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &jobLevelCore{Core: core, jobID: jobID}
+	}))}
+}
+
+// jobLevelCore checks the level of log entries by the overridden log level
+// of the job if any, and by the wrapped core otherwise.
+type jobLevelCore struct {
+	zapcore.Core
+	jobID string
+}
+
+func (c *jobLevelCore) Enabled(level zapcore.Level) bool {
+	if jobLevel, ok := JobLevel(c.jobID); ok {
+		return jobLevel.Enabled(level)
+	}
+	return c.Core.Enabled(level)
+}
+
+func (c *jobLevelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &jobLevelCore{Core: c.Core.With(fields), jobID: c.jobID}
+}
+
+func (c *jobLevelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	jobLevel, ok := JobLevel(c.jobID)
+	if !ok {
+		return c.Core.Check(entry, checked)
+	}
+	if jobLevel.Enabled(entry.Level) {
+		// The wrapped core is bypassed since it checks the entry by its own
+		// level, Write of the wrapped core doesn't check the level.
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
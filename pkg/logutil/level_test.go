@@ -0,0 +1,75 @@
+package logutil
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+func TestJobLevel(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	base := log.Logger{Logger: zap.New(core)}
+	logger := WithWorkerID(WithJobID(base, "level-job-1"), "worker-1")
+	otherLogger := WithJobID(base, "level-job-2")
+
+	logger.Debug("dropped")
+	require.Equal(t, 0, logs.Len())
+
+	SetJobLevel("level-job-1", zapcore.DebugLevel)
+	level, ok := JobLevel("level-job-1")
+	require.True(t, ok)
+	require.Equal(t, zapcore.DebugLevel, level)
+	_, ok = JobLevel("level-job-2")
+	require.False(t, ok)
+
+	logger.Debug("debug")
+	otherLogger.Debug("dropped")
+	entries := logs.TakeAll()
+	require.Len(t, entries, 1)
+	require.Equal(t, "debug", entries[0].Message)
+	require.Equal(t, map[string]interface{}{
+		JobIDKey:    "level-job-1",
+		WorkerIDKey: "worker-1",
+	}, entries[0].ContextMap())
+
+	// the level can be raised as well
+	SetJobLevel("level-job-1", zapcore.WarnLevel)
+	logger.Info("dropped")
+	otherLogger.Info("info")
+	require.Equal(t, 1, logs.Len())
+
+	ResetJobLevel("level-job-1")
+	_, ok = JobLevel("level-job-1")
+	require.False(t, ok)
+	logger.Debug("dropped")
+	logger.Info("info")
+	require.Equal(t, 2, logs.Len())
+}
+
+func TestParseLevel(t *testing.T) {
+	t.Parallel()
+
+	level, err := ParseLevel("debug")
+	require.NoError(t, err)
+	require.Equal(t, zapcore.DebugLevel, level)
+	_, err = ParseLevel("verbose")
+	require.Error(t, err)
+
+	err = UpdateJobLevel("level-job-3", "verbose")
+	require.True(t, errors.ErrInvalidLogLevel.Equal(err))
+	require.NoError(t, UpdateJobLevel("level-job-3", "debug"))
+	level, ok := JobLevel("level-job-3")
+	require.True(t, ok)
+	require.Equal(t, zapcore.DebugLevel, level)
+	require.NoError(t, UpdateJobLevel("level-job-3", ""))
+	_, ok = JobLevel("level-job-3")
+	require.False(t, ok)
+}
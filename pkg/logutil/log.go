@@ -0,0 +1,73 @@
+// Package logutil provides loggers tagged with the identities of tenants,
+// jobs and workers. All log lines of a job carry the same field keys, so
+// that they can be found reliably across the logs of a cluster.
+package logutil
+
+import (
+	"context"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	"github.com/hanfei1991/microcosm/pkg/tenant"
+)
+
+// Field keys used in all tagged loggers
+const (
+	TenantIDKey  = "tenant-id"
+	ProjectIDKey = "project-id"
+	JobIDKey     = "job-id"
+	WorkerIDKey  = "worker-id"
+	EpochKey     = "epoch"
+)
+
+// WithProjectInfo returns a logger tagged with the tenant and project, the
+// empty ones are not tagged.
+func WithProjectInfo(logger log.Logger, project tenant.ProjectInfo) log.Logger {
+	var fields []zap.Field
+	if project.TenantID != "" {
+		fields = append(fields, zap.String(TenantIDKey, project.TenantID))
+	}
+	if project.ProjectID != "" {
+		fields = append(fields, zap.String(ProjectIDKey, project.ProjectID))
+	}
+	if len(fields) == 0 {
+		return logger
+	}
+	return logger.WithFields(fields...)
+}
+
+// WithJobID returns a logger tagged with the job ID, the logger follows
+// the log level of the job if it is overridden, see SetJobLevel.
+func WithJobID(logger log.Logger, jobID string) log.Logger {
+	return withJobLevel(logger, jobID).WithFields(zap.String(JobIDKey, jobID))
+}
+
+// WithWorkerID returns a logger tagged with the worker ID.
+func WithWorkerID(logger log.Logger, workerID string) log.Logger {
+	return logger.WithFields(zap.String(WorkerIDKey, workerID))
+}
+
+// WithEpoch returns a logger tagged with the epoch of the master.
+func WithEpoch(logger log.Logger, epoch int64) log.Logger {
+	return logger.WithFields(zap.Int64(EpochKey, epoch))
+}
+
+type loggerKey struct{}
+
+// NewContext returns a context carrying the logger.
+func NewContext(ctx context.Context, logger log.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the logger carried by ctx, which is the logger of
+// the dcontext.Context that ctx is derived from. log.L() is returned if
+// ctx carries no logger.
+func FromContext(ctx context.Context) log.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerKey{}).(log.Logger); ok {
+			return logger
+		}
+	}
+	return log.L()
+}
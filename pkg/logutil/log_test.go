@@ -0,0 +1,32 @@
+package logutil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestFromContext(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, log.L(), FromContext(context.Background()))
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	logger := WithEpoch(WithWorkerID(WithJobID(log.Logger{Logger: zap.New(core)}, "job-1"), "worker-1"), 2)
+	ctx, cancel := context.WithCancel(NewContext(context.Background(), logger))
+	defer cancel()
+
+	FromContext(ctx).Info("test")
+	entries := logs.All()
+	require.Len(t, entries, 1)
+	require.Equal(t, map[string]interface{}{
+		JobIDKey:    "job-1",
+		WorkerIDKey: "worker-1",
+		EpochKey:    int64(2),
+	}, entries[0].ContextMap())
+}
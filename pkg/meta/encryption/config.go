@@ -0,0 +1,91 @@
+package encryption
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"strings"
+
+	"github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+// Config is the configuration of encrypting the values in the user
+// metastore, the server masters and the executors must share the same keys.
+type Config struct {
+	// Keys are the keys of the tenants without keys of their own, empty
+	// means the values of these tenants are stored in plaintext.
+	Keys []KeyConfig `toml:"keys" json:"keys"`
+	// Tenants are the keys of each tenant by the tenant ID.
+	Tenants map[string][]KeyConfig `toml:"tenants" json:"tenants"`
+
+	keyring *Keyring
+}
+
+// KeyConfig is a version of a key. New values are encrypted by the key with
+// the largest ID, and a value is decrypted by the key with the ID recorded
+// in it. So a key is rotated by adding a key with a larger ID, the old key
+// must be kept until all the values encrypted by it are rewritten.
+type KeyConfig struct {
+	ID uint32 `toml:"id" json:"id"`
+	// KeyFile is the path of a file holding the base64 encoded AES key of
+	// 16, 24 or 32 bytes, the key is read from a file so that it never
+	// appears in the config or the logs.
+	KeyFile string `toml:"key-file" json:"key-file"`
+}
+
+// Enabled returns whether the values of any tenant are encrypted
+func (c *Config) Enabled() bool {
+	return c != nil && (len(c.Keys) > 0 || len(c.Tenants) > 0)
+}
+
+// Adjust validates the config and loads the keys
+func (c *Config) Adjust() error {
+	if !c.Enabled() {
+		return nil
+	}
+	keyring := &Keyring{tenants: make(map[string]*keySet, len(c.Tenants))}
+	var err error
+	if len(c.Keys) > 0 {
+		keyring.defaultKeys, err = loadKeySet(c.Keys)
+		if err != nil {
+			return err
+		}
+	}
+	for tenantID, keys := range c.Tenants {
+		if len(keys) == 0 {
+			return errors.ErrMetaEncryptionInvalidConfig.GenWithStackByArgs(
+				"no key is configured for tenant " + tenantID)
+		}
+		keyring.tenants[tenantID], err = loadKeySet(keys)
+		if err != nil {
+			return err
+		}
+	}
+	c.keyring = keyring
+	return nil
+}
+
+// Keyring returns the keys loaded by Adjust, it returns nil if encryption
+// is not enabled.
+func (c *Config) Keyring() *Keyring {
+	if c == nil {
+		return nil
+	}
+	return c.keyring
+}
+
+func loadKeyFile(path string) ([]byte, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.ErrMetaEncryptionInvalidConfig.GenWithStackByArgs(err.Error())
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(content)))
+	if err != nil {
+		return nil, errors.ErrMetaEncryptionInvalidConfig.GenWithStackByArgs("key is not base64 encoded")
+	}
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return nil, errors.ErrMetaEncryptionInvalidConfig.GenWithStackByArgs("key must be 16, 24 or 32 bytes")
+	}
+	return key, nil
+}
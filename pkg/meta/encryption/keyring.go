@@ -0,0 +1,128 @@
+package encryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+
+	"github.com/pingcap/errors"
+
+	derrors "github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+// magic is the header of the encrypted values, a value without it is
+// written before encryption is enabled and is read as plaintext.
+var magic = []byte{0x00, 'e', 'n', 'c'}
+
+const keyIDLen = 4
+
+// Keyring holds the keys of the tenants
+type Keyring struct {
+	defaultKeys *keySet
+	tenants     map[string]*keySet
+}
+
+// keysOf returns the keys of the tenant, nil means the values of the tenant
+// are not encrypted.
+func (k *Keyring) keysOf(tenantID string) *keySet {
+	if k == nil {
+		return nil
+	}
+	if keys, ok := k.tenants[tenantID]; ok {
+		return keys
+	}
+	return k.defaultKeys
+}
+
+// keySet is the versions of the key of a tenant, values are encrypted in
+// AES-GCM by the current version.
+type keySet struct {
+	current uint32
+	aeads   map[uint32]cipher.AEAD
+}
+
+// seal encrypts the value of the key, the value is laid out as the magic,
+// the big endian key ID, the nonce and the ciphertext. The tenant ID and
+// the key are the additional data, so that a value can't be copied to
+// another key or tenant.
+func (s *keySet) seal(tenantID, key string, value []byte) ([]byte, error) {
+	aead := s.aeads[s.current]
+	headerLen := len(magic) + keyIDLen
+	out := make([]byte, headerLen+aead.NonceSize(), headerLen+aead.NonceSize()+len(value)+aead.Overhead())
+	copy(out, magic)
+	binary.BigEndian.PutUint32(out[len(magic):], s.current)
+	if _, err := io.ReadFull(rand.Reader, out[headerLen:]); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return aead.Seal(out, out[headerLen:], value, additionalData(tenantID, key)), nil
+}
+
+// open decrypts the value of the key, a value without the magic is
+// returned as is.
+func (s *keySet) open(tenantID, key string, value []byte) ([]byte, error) {
+	if !bytes.HasPrefix(value, magic) {
+		return value, nil
+	}
+	headerLen := len(magic) + keyIDLen
+	if len(value) < headerLen {
+		return nil, derrors.ErrMetaDecryptFailed.GenWithStackByArgs(key, "value is too short")
+	}
+	keyID := binary.BigEndian.Uint32(value[len(magic):])
+	aead, ok := s.aeads[keyID]
+	if !ok {
+		return nil, derrors.ErrMetaEncryptionKeyNotFound.GenWithStackByArgs(keyID, tenantID)
+	}
+	if len(value) < headerLen+aead.NonceSize() {
+		return nil, derrors.ErrMetaDecryptFailed.GenWithStackByArgs(key, "value is too short")
+	}
+	nonce, sealed := value[headerLen:headerLen+aead.NonceSize()], value[headerLen+aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, additionalData(tenantID, key))
+	if err != nil {
+		return nil, derrors.ErrMetaDecryptFailed.GenWithStackByArgs(key, err.Error())
+	}
+	return plaintext, nil
+}
+
+func additionalData(tenantID, key string) []byte {
+	return []byte(tenantID + "/" + key)
+}
+
+func loadKeySet(keys []KeyConfig) (*keySet, error) {
+	set := &keySet{aeads: make(map[uint32]cipher.AEAD, len(keys))}
+	for _, cfg := range keys {
+		if cfg.ID == 0 {
+			return nil, derrors.ErrMetaEncryptionInvalidConfig.GenWithStackByArgs("key id must be positive")
+		}
+		if _, ok := set.aeads[cfg.ID]; ok {
+			return nil, derrors.ErrMetaEncryptionInvalidConfig.GenWithStackByArgs("key id is duplicated")
+		}
+		key, err := loadKeyFile(cfg.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+		set.aeads[cfg.ID] = aead
+		if cfg.ID > set.current {
+			set.current = cfg.ID
+		}
+	}
+	return set, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, derrors.ErrMetaEncryptionInvalidConfig.GenWithStackByArgs(err.Error())
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, derrors.ErrMetaEncryptionInvalidConfig.GenWithStackByArgs(err.Error())
+	}
+	return aead, nil
+}
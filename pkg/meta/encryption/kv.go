@@ -0,0 +1,150 @@
+package encryption
+
+import (
+	"context"
+
+	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
+)
+
+type encryptionError struct {
+	cause error
+}
+
+func (e *encryptionError) IsRetryable() bool {
+	return false
+}
+
+func (e *encryptionError) Error() string {
+	return e.cause.Error()
+}
+
+type kvEncrypted struct {
+	metaclient.KV
+	keys     *keySet
+	tenantID string
+}
+
+// NewKV wraps a KV of the tenant so that the values are encrypted by the
+// keys of the tenant in the keyring, the KV is returned as is if the values
+// of the tenant are not encrypted. Only the values are encrypted, the keys
+// are stored in plaintext to be ranged over.
+func NewKV(kv metaclient.KV, keyring *Keyring, tenantID string) metaclient.KV {
+	keys := keyring.keysOf(tenantID)
+	if keys == nil {
+		return kv
+	}
+	return &kvEncrypted{KV: kv, keys: keys, tenantID: tenantID}
+}
+
+func (kv *kvEncrypted) Put(ctx context.Context, key, val string) (*metaclient.PutResponse, metaclient.Error) {
+	sealed, err := kv.keys.seal(kv.tenantID, key, []byte(val))
+	if err != nil {
+		return nil, &encryptionError{err}
+	}
+	return kv.KV.Put(ctx, key, string(sealed))
+}
+
+func (kv *kvEncrypted) Get(ctx context.Context, key string, opts ...metaclient.OpOption) (*metaclient.GetResponse, metaclient.Error) {
+	resp, err := kv.KV.Get(ctx, key, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := kv.openGetResponse(resp); err != nil {
+		return nil, &encryptionError{err}
+	}
+	return resp, nil
+}
+
+type txnEncrypted struct {
+	metaclient.Txn
+	kv *kvEncrypted
+	// err is the failure of encrypting the ops, which is returned on commit
+	err error
+}
+
+func (kv *kvEncrypted) Txn(ctx context.Context) metaclient.Txn {
+	return &txnEncrypted{Txn: kv.KV.Txn(ctx), kv: kv}
+}
+
+func (txn *txnEncrypted) Do(ops ...metaclient.Op) metaclient.Txn {
+	if txn.err != nil {
+		return txn
+	}
+	sealed, err := txn.kv.sealOps(ops)
+	if err != nil {
+		txn.err = err
+		return txn
+	}
+	txn.Txn = txn.Txn.Do(sealed...)
+	return txn
+}
+
+func (txn *txnEncrypted) Commit() (*metaclient.TxnResponse, metaclient.Error) {
+	if txn.err != nil {
+		return nil, &encryptionError{txn.err}
+	}
+	resp, err := txn.Txn.Commit()
+	if err != nil {
+		return nil, err
+	}
+	if err := txn.kv.openTxnResponse(resp); err != nil {
+		return nil, &encryptionError{err}
+	}
+	return resp, nil
+}
+
+func (kv *kvEncrypted) sealOps(ops []metaclient.Op) ([]metaclient.Op, error) {
+	newOps := make([]metaclient.Op, len(ops))
+	for i, op := range ops {
+		switch {
+		case op.IsPut():
+			key := string(op.KeyBytes())
+			sealed, err := kv.keys.seal(kv.tenantID, key, op.ValueBytes())
+			if err != nil {
+				return nil, err
+			}
+			newOps[i] = metaclient.OpPut(key, string(sealed))
+		case op.IsTxn():
+			txnOps, err := kv.sealOps(op.Txn())
+			if err != nil {
+				return nil, err
+			}
+			newOps[i] = metaclient.OpTxn(txnOps)
+		default:
+			newOps[i] = op
+		}
+	}
+	return newOps, nil
+}
+
+func (kv *kvEncrypted) openGetResponse(resp *metaclient.GetResponse) error {
+	for _, item := range resp.Kvs {
+		value, err := kv.keys.open(kv.tenantID, string(item.Key), item.Value)
+		if err != nil {
+			return err
+		}
+		item.Value = value
+	}
+	return nil
+}
+
+func (kv *kvEncrypted) openTxnResponse(resp *metaclient.TxnResponse) error {
+	for _, r := range resp.Responses {
+		switch tv := r.Response.(type) {
+		case *metaclient.ResponseOpResponseGet:
+			if tv.ResponseGet != nil {
+				if err := kv.openGetResponse(tv.ResponseGet); err != nil {
+					return err
+				}
+			}
+		case *metaclient.ResponseOpResponseTxn:
+			if tv.ResponseTxn != nil {
+				if err := kv.openTxnResponse(tv.ResponseTxn); err != nil {
+					return err
+				}
+			}
+		default:
+		}
+	}
+	return nil
+}
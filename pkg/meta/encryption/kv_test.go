@@ -0,0 +1,156 @@
+package encryption
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	derrors "github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/meta/kvclient/mock"
+	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
+)
+
+func writeKeyFile(t *testing.T, dir string, id uint32) KeyConfig {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	path := filepath.Join(dir, fmt.Sprintf("key-%d", id))
+	require.NoError(t, os.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(key)), 0o600))
+	return KeyConfig{ID: id, KeyFile: path}
+}
+
+func newKeyring(t *testing.T, keys []KeyConfig, tenants map[string][]KeyConfig) *Keyring {
+	cfg := &Config{Keys: keys, Tenants: tenants}
+	require.NoError(t, cfg.Adjust())
+	return cfg.Keyring()
+}
+
+func valuesOf(resp *metaclient.GetResponse) map[string]string {
+	values := make(map[string]string, len(resp.Kvs))
+	for _, item := range resp.Kvs {
+		values[string(item.Key)] = string(item.Value)
+	}
+	return values
+}
+
+func TestEncryptedKV(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	key1 := writeKeyFile(t, dir, 1)
+	tenantKey := writeKeyFile(t, dir, 7)
+	keyring := newKeyring(t, []KeyConfig{key1}, map[string][]KeyConfig{"tenant": {tenantKey}})
+
+	raw := mock.NewMetaMock()
+	kv := NewKV(raw, keyring, "default")
+	_, err := kv.Put(ctx, "job-1", "password=123")
+	require.Nil(t, err)
+	rawResp, err := raw.Get(ctx, "job-1")
+	require.Nil(t, err)
+	require.NotContains(t, string(rawResp.Kvs[0].Value), "password")
+	resp, err := kv.Get(ctx, "job-1")
+	require.Nil(t, err)
+	require.Equal(t, "password=123", string(resp.Kvs[0].Value))
+
+	// values written before encryption is enabled are read as plaintext
+	_, err = raw.Put(ctx, "job-2", "legacy")
+	require.Nil(t, err)
+	resp, err = kv.Get(ctx, "job-", metaclient.WithPrefix())
+	require.Nil(t, err)
+	require.Equal(t, map[string]string{"job-1": "password=123", "job-2": "legacy"}, valuesOf(resp))
+
+	// a value can't be decrypted as the value of another key or tenant
+	_, err = raw.Put(ctx, "job-3", string(rawResp.Kvs[0].Value))
+	require.Nil(t, err)
+	_, err = kv.Get(ctx, "job-3")
+	require.True(t, derrors.ErrMetaDecryptFailed.Equal(err.(*encryptionError).cause))
+	_, err = NewKV(raw, keyring, "tenant").Get(ctx, "job-1")
+	require.True(t, derrors.ErrMetaEncryptionKeyNotFound.Equal(err.(*encryptionError).cause))
+
+	// the values of a tenant without keys are not encrypted
+	require.Equal(t, raw, NewKV(raw, newKeyring(t, nil, map[string][]KeyConfig{"tenant": {tenantKey}}), "default"))
+	require.Equal(t, raw, NewKV(raw, nil, "default"))
+}
+
+func TestEncryptedTxn(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	keyring := newKeyring(t, []KeyConfig{writeKeyFile(t, t.TempDir(), 1)}, nil)
+	raw := mock.NewMetaMock()
+	kv := NewKV(raw, keyring, "default")
+
+	_, err := kv.Txn(ctx).Do(
+		metaclient.OpPut("key-1", "value-1"),
+		metaclient.OpPut("key-2", "value-2"),
+	).Commit()
+	require.Nil(t, err)
+	rawResp, err := raw.Get(ctx, "key-1")
+	require.Nil(t, err)
+	require.NotEqual(t, "value-1", string(rawResp.Kvs[0].Value))
+
+	txnResp, err := kv.Txn(ctx).Do(metaclient.OpGet("key-", metaclient.WithPrefix())).Commit()
+	require.Nil(t, err)
+	require.Equal(t, map[string]string{"key-1": "value-1", "key-2": "value-2"},
+		valuesOf(txnResp.Responses[0].GetResponseGet()))
+}
+
+func TestKeyRotation(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	key1, key2 := writeKeyFile(t, dir, 1), writeKeyFile(t, dir, 2)
+	raw := mock.NewMetaMock()
+
+	_, err := NewKV(raw, newKeyring(t, []KeyConfig{key1}, nil), "default").Put(ctx, "old", "value-1")
+	require.Nil(t, err)
+
+	// the old values are still readable after a new key is added, and new
+	// values are encrypted by the new key
+	rotated := NewKV(raw, newKeyring(t, []KeyConfig{key2, key1}, nil), "default")
+	_, err = rotated.Put(ctx, "new", "value-2")
+	require.Nil(t, err)
+	for key, value := range map[string]string{"old": "value-1", "new": "value-2"} {
+		resp, err := rotated.Get(ctx, key)
+		require.Nil(t, err)
+		require.Equal(t, value, string(resp.Kvs[0].Value))
+	}
+
+	retired := NewKV(raw, newKeyring(t, []KeyConfig{key2}, nil), "default")
+	resp, err := retired.Get(ctx, "new")
+	require.Nil(t, err)
+	require.Equal(t, "value-2", string(resp.Kvs[0].Value))
+	_, err = retired.Get(ctx, "old")
+	require.True(t, derrors.ErrMetaEncryptionKeyNotFound.Equal(err.(*encryptionError).cause))
+}
+
+func TestConfigAdjust(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	key1 := writeKeyFile(t, dir, 1)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "short"), []byte(base64.StdEncoding.EncodeToString([]byte("short"))), 0o600))
+
+	cases := []*Config{
+		{Keys: []KeyConfig{{ID: 0, KeyFile: key1.KeyFile}}},
+		{Keys: []KeyConfig{key1, key1}},
+		{Keys: []KeyConfig{{ID: 2, KeyFile: filepath.Join(dir, "short")}}},
+		{Keys: []KeyConfig{{ID: 2, KeyFile: filepath.Join(dir, "missing")}}},
+		{Tenants: map[string][]KeyConfig{"tenant": nil}},
+	}
+	for _, cfg := range cases {
+		require.True(t, derrors.ErrMetaEncryptionInvalidConfig.Equal(cfg.Adjust()))
+	}
+
+	cfg := &Config{}
+	require.NoError(t, cfg.Adjust())
+	require.Nil(t, cfg.Keyring())
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/hanfei1991/microcosm/pkg/meta/encryption"
 	"github.com/hanfei1991/microcosm/pkg/meta/extension"
 	"github.com/hanfei1991/microcosm/pkg/meta/kvclient/etcdkv"
 	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
@@ -20,12 +21,33 @@ type etcdKVClient struct {
 	tenantID string
 }
 
+// PrefixKVClientOption configures the kvclient created by NewPrefixKVClient
+type PrefixKVClientOption func(*prefixKVClientOptions)
+
+type prefixKVClientOptions struct {
+	keyring *encryption.Keyring
+}
+
+// WithKeyring encrypts the values of the tenant by its keys in the keyring,
+// a nil keyring means the values are not encrypted.
+func WithKeyring(keyring *encryption.Keyring) PrefixKVClientOption {
+	return func(opts *prefixKVClientOptions) {
+		opts.keyring = keyring
+	}
+}
+
 // NewPrefixKVClient return a kvclient with namespace
-func NewPrefixKVClient(cli extension.KVClientEx, tenantID string) metaclient.KVClient {
+func NewPrefixKVClient(cli extension.KVClientEx, tenantID string, opts ...PrefixKVClientOption) metaclient.KVClient {
+	options := &prefixKVClientOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
 	pfKV := namespace.NewPrefixKV(cli, namespace.MakeNamespacePrefix(tenantID))
 	return &etcdKVClient{
-		Client:   cli,
-		KV:       pfKV,
+		Client: cli,
+		// the values are encrypted above the namespace, so that the keys
+		// bound to the values are the same as the ones seen by the tenant
+		KV:       encryption.NewKV(pfKV, options.keyring, tenantID),
 		tenantID: tenantID,
 	}
 }
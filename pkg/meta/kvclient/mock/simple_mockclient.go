@@ -92,7 +92,15 @@ func (m *MetaMock) Delete(ctx context.Context, key string, opts ...metaclient.Op
 }
 
 func (m *MetaMock) deleteNoLock(ctx context.Context, key string, opts ...metaclient.OpOption) (*metaclient.DeleteResponse, metaclient.Error) {
-	delete(m.store, key)
+	if op := metaclient.OpDelete(key, opts...); op.IsOptsWithPrefix() {
+		for k := range m.store {
+			if strings.HasPrefix(k, key) {
+				delete(m.store, k)
+			}
+		}
+	} else {
+		delete(m.store, key)
+	}
 	m.revision++
 	return &metaclient.DeleteResponse{
 		Header: &metaclient.ResponseHeader{
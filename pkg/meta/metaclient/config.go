@@ -13,16 +13,27 @@ const (
 	DefaultUserMetaEndpoints = "127.0.0.1:12479"
 )
 
+// defines the backend types of framework metastore
+const (
+	// StoreTypeMySQL is a MySQL compatible database, it is the default type
+	StoreTypeMySQL = "mysql"
+	// StoreTypeSQLite is a local SQLite database file, which is only used by
+	// the standalone deployment. Endpoints[0] is the path of the file.
+	StoreTypeSQLite = "sqlite"
+)
+
 // AuthConfParams is basic password authentication configurations
 type AuthConfParams struct {
 	User   string `toml:"user" json:"user"`
-	Passwd string `toml:"passwd" json:"passwd"`
+	Passwd string `toml:"passwd" json:"passwd" redact:"true"`
 }
 
 // StoreConfigParams is metastore connection configurations
 type StoreConfigParams struct {
 	// storeID is the unique readable identifier for a store
 	StoreID string `toml:"store-id" json:"store-id"`
+	// StoreType is the backend type of the store, empty means StoreTypeMySQL
+	StoreType string `toml:"store-type" json:"store-type"`
 	// TODO: replace the slice when we migrate to db
 	Endpoints []string       `toml:"endpoints" json:"endpoints"`
 	Auth      AuthConfParams `toml:"auth" json:"auth"`
@@ -0,0 +1,18 @@
+package notifier
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var receiverOverflowCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "dataflow",
+		Subsystem: "notifier",
+		Name:      "receiver_overflow_total",
+		Help:      "number of times that the buffer of a notifier receiver overflowed",
+	}, []string{"policy"})
+
+// InitMetrics registers the notifier metrics
+func InitMetrics(registry *prometheus.Registry) {
+	registry.MustRegister(receiverOverflowCounter)
+}
@@ -13,6 +13,79 @@ import (
 
 type receiverID = int64
 
+const defaultReceiverBufferSize = 16
+
+// OverflowPolicy defines what the notifier does when the buffer of a
+// receiver is full.
+type OverflowPolicy int
+
+// Defines all overflow policies
+const (
+	// OverflowBlock waits until the receiver has room for the event. Note that
+	// a congested receiver prevents all other receivers from receiving events,
+	// and the events pile up in the queue of the notifier, which is unbounded
+	// unless the notifier is created WithQueueCapacity.
+	OverflowBlock = OverflowPolicy(iota)
+	// OverflowDropOldest drops the oldest event in the buffer of the receiver.
+	OverflowDropOldest
+	// OverflowCloseReceiver closes the receiver, the consumer will find `C`
+	// closed and is expected to create a new receiver and resynchronize.
+	OverflowCloseReceiver
+)
+
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowBlock:
+		return "block"
+	case OverflowDropOldest:
+		return "drop-oldest"
+	case OverflowCloseReceiver:
+		return "close-receiver"
+	default:
+		return "unknown"
+	}
+}
+
+type receiverOptions struct {
+	bufferSize int
+	policy     OverflowPolicy
+}
+
+// ReceiverOption configures a Receiver
+type ReceiverOption func(opts *receiverOptions)
+
+// WithBufferSize sets the size of the buffered channel of a receiver
+func WithBufferSize(size int) ReceiverOption {
+	return func(opts *receiverOptions) {
+		opts.bufferSize = size
+	}
+}
+
+// WithOverflowPolicy sets the policy to apply when the buffer of a receiver is full
+func WithOverflowPolicy(policy OverflowPolicy) ReceiverOption {
+	return func(opts *receiverOptions) {
+		opts.policy = policy
+	}
+}
+
+type notifierOptions struct {
+	queueCapacity int
+}
+
+// NotifierOption configures a Notifier
+type NotifierOption func(opts *notifierOptions)
+
+// WithQueueCapacity bounds the number of events queued in the notifier, which
+// are not sent to the receivers yet. Notify blocks while the queue is full,
+// so that a congested receiver with the OverflowBlock policy slows down the
+// producer rather than growing the queue. A capacity not larger than 0 means
+// unbounded, which is the default.
+func WithQueueCapacity(capacity int) NotifierOption {
+	return func(opts *notifierOptions) {
+		opts.queueCapacity = capacity
+	}
+}
+
 // Notifier is the sending endpoint of an event
 // notification mechanism. It broadcasts a stream of
 // events to a number of receivers.
@@ -20,8 +93,11 @@ type Notifier[T any] struct {
 	receivers sync.Map // receiverID -> *Receiver[T]
 	nextID    atomic.Int64
 
-	// queue is unbounded.
+	// queue is unbounded if queueSlots is nil.
 	queue *containers.SliceQueue[T]
+	// queueSlots has an element for each event in queue, it is nil if the
+	// queue is unbounded.
+	queueSlots chan struct{}
 
 	closed        atomic.Bool
 	closeCh       chan struct{}
@@ -37,39 +113,109 @@ type Receiver[T any] struct {
 	// Note that it is part of the public interface of this package.
 	C chan T
 
-	id receiverID
-
-	closeOnce sync.Once
+	id     receiverID
+	policy OverflowPolicy
 
-	// closed MUST be set to true before closing `C`.
-	closed atomic.Bool
+	// closed MUST be set to true before closing `C`, closingCh is closed
+	// once it is set, so that run() stops blocking on `C`.
+	closed    atomic.Bool
+	closingCh chan struct{}
+	// chClosed makes `C` closed exactly once, by Close or by run().
+	chClosed atomic.Bool
 
 	notifier *Notifier[T]
 }
 
 // Close closes the receiver
 func (r *Receiver[T]) Close() {
-	r.closeOnce.Do(
-		func() {
-			r.closed.Store(true)
-			// Waits for the synchronization barrier, which
-			// means that run() has finished the last iteration,
-			// and since we have set `closed` to true, the `C` channel,
-			// will not be written to anymore. So it is safe to close it now.
-			<-r.notifier.synchronizeCh
-			close(r.C)
-			r.notifier.receivers.Delete(r.id)
-		})
+	r.markClosed()
+	// Waits for the synchronization barrier, which
+	// means that run() has finished the last iteration,
+	// and since we have set `closed` to true, the `C` channel,
+	// will not be written to anymore. So it is safe to close it now,
+	// unless run() has closed it on overflow.
+	<-r.notifier.synchronizeCh
+	r.closeC()
+}
+
+// closeInRun closes the receiver from the run() goroutine, which is the only
+// writer of `C`, so there is no need to wait for the synchronization barrier.
+// If the consumer is closing the receiver, `C` is closed by Close instead.
+func (r *Receiver[T]) closeInRun() {
+	if r.markClosed() {
+		r.closeC()
+	}
+}
+
+// markClosed sets closed, it returns false if it has been set.
+func (r *Receiver[T]) markClosed() bool {
+	if !r.closed.CAS(false, true) {
+		return false
+	}
+	close(r.closingCh)
+	return true
+}
+
+func (r *Receiver[T]) closeC() {
+	if !r.chClosed.CAS(false, true) {
+		return
+	}
+	close(r.C)
+	r.notifier.receivers.Delete(r.id)
+}
+
+// send sends the event to the receiver according to its overflow policy,
+// it returns false if the notifier is closed.
+func (r *Receiver[T]) send(event T, closeCh <-chan struct{}) bool {
+	if r.policy == OverflowBlock {
+		select {
+		case <-closeCh:
+			return false
+		case <-r.closingCh:
+		case r.C <- event:
+		}
+		return true
+	}
+
+	for {
+		select {
+		case r.C <- event:
+			return true
+		default:
+		}
+
+		if r.policy == OverflowCloseReceiver {
+			receiverOverflowCounter.WithLabelValues(r.policy.String()).Inc()
+			r.closeInRun()
+			return true
+		}
+
+		// OverflowDropOldest. The consumer may have read an event
+		// concurrently, in which case nothing is dropped.
+		select {
+		case <-r.C:
+			receiverOverflowCounter.WithLabelValues(r.policy.String()).Inc()
+		default:
+		}
+	}
 }
 
 // NewNotifier creates a new Notifier.
-func NewNotifier[T any]() *Notifier[T] {
+func NewNotifier[T any](opts ...NotifierOption) *Notifier[T] {
+	options := &notifierOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	ret := &Notifier[T]{
 		receivers:     sync.Map{},
 		queue:         containers.NewSliceQueue[T](),
 		closeCh:       make(chan struct{}),
 		synchronizeCh: make(chan struct{}),
 	}
+	if options.queueCapacity > 0 {
+		ret.queueSlots = make(chan struct{}, options.queueCapacity)
+	}
 
 	ret.wg.Add(1)
 	go func() {
@@ -80,21 +226,45 @@ func NewNotifier[T any]() *Notifier[T] {
 }
 
 // NewReceiver creates a new Receiver associated with
-// the given Notifier.
-func (n *Notifier[T]) NewReceiver() *Receiver[T] {
-	ch := make(chan T, 16)
+// the given Notifier. By default, the receiver has a buffer of 16 events
+// and the OverflowBlock policy.
+func (n *Notifier[T]) NewReceiver(opts ...ReceiverOption) *Receiver[T] {
+	options := &receiverOptions{
+		bufferSize: defaultReceiverBufferSize,
+		policy:     OverflowBlock,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.policy != OverflowBlock && options.bufferSize < 1 {
+		// An unbuffered channel can't hold the events to drop.
+		options.bufferSize = 1
+	}
+
+	ch := make(chan T, options.bufferSize)
 	receiver := &Receiver[T]{
-		id:       n.nextID.Add(1),
-		C:        ch,
-		notifier: n,
+		id:        n.nextID.Add(1),
+		policy:    options.policy,
+		C:         ch,
+		closingCh: make(chan struct{}),
+		notifier:  n,
 	}
 
 	n.receivers.Store(receiver.id, receiver)
 	return receiver
 }
 
-// Notify sends a new notification event.
+// Notify sends a new notification event. It blocks while the queue is
+// full if the notifier is created WithQueueCapacity, the event is dropped
+// if the notifier is closed meanwhile.
 func (n *Notifier[T]) Notify(event T) {
+	if n.queueSlots != nil {
+		select {
+		case <-n.closeCh:
+			return
+		case n.queueSlots <- struct{}{}:
+		}
+	}
 	n.queue.Push(event)
 }
 
@@ -156,24 +326,19 @@ func (n *Notifier[T]) run() {
 				if !ok {
 					break Inner
 				}
+				if n.queueSlots != nil {
+					<-n.queueSlots
+				}
 
-				// TODO In the current implementation, congestion
-				// in once receiver will prevent all other receivers
-				// from receiving events.
+				// Congestion in a receiver with OverflowBlock policy
+				// will prevent all other receivers from receiving events.
 				n.receivers.Range(func(_, value any) bool {
 					receiver := value.(*Receiver[T])
 
 					if receiver.closed.Load() {
 						return true
 					}
-
-					select {
-					case <-n.closeCh:
-						return false
-					case receiver.C <- event:
-						// send the event to the receiver.
-					}
-					return true
+					return receiver.send(event, n.closeCh)
 				})
 
 				select {
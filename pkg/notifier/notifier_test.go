@@ -3,11 +3,13 @@ package notifier
 import (
 	"context"
 	"math"
+	"runtime"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
 )
 
 func TestNotifierBasics(t *testing.T) {
@@ -22,11 +24,12 @@ func TestNotifierBasics(t *testing.T) {
 	var wg sync.WaitGroup
 
 	for i := 0; i < numReceivers; i++ {
+		// The receivers are created before notifying, otherwise
+		// a late one may miss finEv.
+		r := n.NewReceiver()
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-
-			r := n.NewReceiver()
 			defer r.Close()
 
 			var ev, lastEv int
@@ -85,3 +88,148 @@ func TestNotifierClose(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestReceiverDropOldest(t *testing.T) {
+	n := NewNotifier[int]()
+	defer n.Close()
+
+	r := n.NewReceiver(WithBufferSize(2), WithOverflowPolicy(OverflowDropOldest))
+	defer r.Close()
+	// a blocking receiver with enough buffer is not affected
+	r1 := n.NewReceiver(WithBufferSize(8))
+	defer r1.Close()
+
+	for i := 1; i <= 5; i++ {
+		n.Notify(i)
+	}
+	err := n.Flush(context.Background())
+	require.NoError(t, err)
+
+	require.Equal(t, 4, <-r.C)
+	require.Equal(t, 5, <-r.C)
+	for i := 1; i <= 5; i++ {
+		require.Equal(t, i, <-r1.C)
+	}
+}
+
+func TestReceiverCloseOnOverflow(t *testing.T) {
+	n := NewNotifier[int]()
+	defer n.Close()
+
+	r := n.NewReceiver(WithBufferSize(2), WithOverflowPolicy(OverflowCloseReceiver))
+	for i := 1; i <= 3; i++ {
+		n.Notify(i)
+	}
+	err := n.Flush(context.Background())
+	require.NoError(t, err)
+
+	require.Equal(t, 1, <-r.C)
+	require.Equal(t, 2, <-r.C)
+	_, ok := <-r.C
+	require.False(t, ok)
+	// closing again is a no-op
+	r.Close()
+}
+
+func TestReceiverCloseWhileOverflowing(t *testing.T) {
+	// the queue is bounded, since the events are notified without a pause
+	n := NewNotifier[int](WithQueueCapacity(16))
+
+	stopCh := make(chan struct{})
+	notifyDone := make(chan struct{})
+	go func() {
+		defer close(notifyDone)
+		for i := 0; ; i++ {
+			select {
+			case <-stopCh:
+				return
+			default:
+			}
+			n.Notify(i)
+		}
+	}()
+
+	// the receivers are closed by the consumer while run() finds them
+	// overflowing and closes them too
+	for i := 0; i < 100; i++ {
+		r := n.NewReceiver(WithBufferSize(1), WithOverflowPolicy(OverflowCloseReceiver))
+		for len(r.C) == 0 && !r.closed.Load() {
+			runtime.Gosched()
+		}
+		r.Close()
+		_, ok := <-r.C
+		for ok {
+			_, ok = <-r.C
+		}
+	}
+	// a blocking receiver which is full is closed as well
+	r := n.NewReceiver(WithBufferSize(1))
+	require.Eventually(t, func() bool {
+		return len(r.C) == 1
+	}, 5*time.Second, time.Millisecond)
+	r.Close()
+
+	close(stopCh)
+	<-notifyDone
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, n.Flush(ctx))
+	n.Close()
+}
+
+func TestNotifierQueueCapacity(t *testing.T) {
+	const (
+		queueCapacity = 4
+		numEvents     = 1000
+	)
+	n := NewNotifier[int](WithQueueCapacity(queueCapacity))
+	defer n.Close()
+
+	// the receiver is stalled, so the producer is blocked rather than the
+	// events piling up in the queue
+	r := n.NewReceiver(WithBufferSize(1))
+	var notified atomic.Int64
+	notifyDone := make(chan struct{})
+	go func() {
+		defer close(notifyDone)
+		for i := 1; i <= numEvents; i++ {
+			n.Notify(i)
+			notified.Add(1)
+		}
+	}()
+
+	// one event in the buffer of the receiver, one being sent by run()
+	// and the others in the queue
+	require.Eventually(t, func() bool {
+		return notified.Load() == queueCapacity+2
+	}, 5*time.Second, time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, int64(queueCapacity+2), notified.Load())
+	require.LessOrEqual(t, n.queue.Size(), queueCapacity)
+
+	for i := 1; i <= numEvents; i++ {
+		require.Equal(t, i, <-r.C)
+	}
+	<-notifyDone
+	r.Close()
+}
+
+func TestNotifierStalledDropOldestReceiver(t *testing.T) {
+	n := NewNotifier[int]()
+	defer n.Close()
+
+	// a stalled receiver with a bounded policy doesn't make the queue
+	// grow, even if it is unbounded
+	r := n.NewReceiver(WithBufferSize(2), WithOverflowPolicy(OverflowDropOldest))
+	defer r.Close()
+	for i := 1; i <= 10000; i++ {
+		n.Notify(i)
+		if i%1000 == 0 {
+			require.NoError(t, n.Flush(context.Background()))
+			require.Equal(t, 0, n.queue.Size())
+			require.Equal(t, 2, len(r.C))
+		}
+	}
+	require.Equal(t, 9999, <-r.C)
+	require.Equal(t, 10000, <-r.C)
+}
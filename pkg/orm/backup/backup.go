@@ -0,0 +1,263 @@
+// Package backup exports the state of the framework metastore to a portable
+// snapshot and imports it into another metastore, which is used for disaster
+// recovery and migrating between metastore backends, e.g. from the SQLite
+// file of a standalone deployment to MySQL.
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	derrors "github.com/hanfei1991/microcosm/pkg/errors"
+	resModel "github.com/hanfei1991/microcosm/pkg/externalresource/resourcemeta/model"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+	"github.com/hanfei1991/microcosm/pkg/orm/model"
+)
+
+// SnapshotVersion is the version of the snapshot format, it is increased on
+// incompatible changes of the format.
+const SnapshotVersion = 1
+
+// Snapshot is the state of the framework metastore at a point in time. The
+// leader fence is not included, because it belongs to the cluster the
+// metastore serves.
+type Snapshot struct {
+	Version   int            `json:"version"`
+	CreatedAt time.Time      `json:"created-at"`
+	Epoch     libModel.Epoch `json:"epoch"`
+
+	Projects          []*model.ProjectInfo         `json:"projects"`
+	ProjectOperations []*model.ProjectOperation    `json:"project-operations"`
+	Jobs              []*libModel.MasterMetaKVData `json:"jobs"`
+	Workers           []*libModel.WorkerStatus     `json:"workers"`
+	Resources         []*resModel.ResourceMeta     `json:"resources"`
+	ResourceLeases    []*resModel.ResourceLease    `json:"resource-leases"`
+	JobDeletions      []*model.JobDeletion         `json:"job-deletions"`
+	JobSchedules      []*model.JobSchedule         `json:"job-schedules"`
+	JobTemplates      []*model.JobTemplate         `json:"job-templates"`
+	Secrets           []*model.Secret              `json:"secrets"`
+	ExecutorCordons   []*model.ExecutorCordon      `json:"executor-cordons"`
+	QueuedTasks       []*model.QueuedTask          `json:"queued-tasks"`
+}
+
+// Take reads a consistent snapshot of the metastore.
+func Take(ctx context.Context, cli pkgOrm.Client) (*Snapshot, error) {
+	snap := &Snapshot{
+		Version:   SnapshotVersion,
+		CreatedAt: time.Now(),
+	}
+	err := cli.SnapshotRead(ctx, func(cli pkgOrm.Client) error {
+		var err error
+		if snap.Epoch, err = cli.GetEpoch(ctx); err != nil {
+			return err
+		}
+		if snap.Projects, err = cli.QueryProjects(ctx); err != nil {
+			return err
+		}
+		for _, project := range snap.Projects {
+			ops, err := cli.QueryProjectOperations(ctx, project.ID)
+			if err != nil {
+				return err
+			}
+			snap.ProjectOperations = append(snap.ProjectOperations, ops...)
+		}
+		if snap.Jobs, err = cli.QueryJobs(ctx); err != nil {
+			return err
+		}
+		if snap.Workers, err = cli.QueryWorkers(ctx); err != nil {
+			return err
+		}
+		if snap.Resources, err = cli.QueryResources(ctx); err != nil {
+			return err
+		}
+		if snap.ResourceLeases, err = cli.QueryResourceLeases(ctx); err != nil {
+			return err
+		}
+		if snap.JobDeletions, err = cli.QueryJobDeletions(ctx); err != nil {
+			return err
+		}
+		if snap.JobSchedules, err = cli.QueryJobSchedules(ctx); err != nil {
+			return err
+		}
+		if snap.JobTemplates, err = cli.QueryJobTemplates(ctx); err != nil {
+			return err
+		}
+		if snap.Secrets, err = cli.QuerySecrets(ctx); err != nil {
+			return err
+		}
+		if snap.ExecutorCordons, err = cli.QueryExecutorCordons(ctx); err != nil {
+			return err
+		}
+		snap.QueuedTasks, err = cli.QueryQueuedTasks(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// Export writes a snapshot of the metastore to w in JSON.
+func Export(ctx context.Context, cli pkgOrm.Client, w io.Writer) (*Snapshot, error) {
+	snap, err := Take(ctx, cli)
+	if err != nil {
+		return nil, err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(snap); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return snap, nil
+}
+
+// Import reads a snapshot written by Export from r and restores it.
+func Import(ctx context.Context, cli pkgOrm.Client, r io.Reader) (*Snapshot, error) {
+	snap := &Snapshot{}
+	if err := json.NewDecoder(r).Decode(snap); err != nil {
+		return nil, derrors.ErrMetaSnapshotInvalid.GenWithStackByArgs(err.Error())
+	}
+	if err := Restore(ctx, cli, snap); err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// Restore writes a snapshot into a fresh metastore, the metastore should be
+// initialized and no server master should be running on it. It returns
+// ErrMetaImportNotEmpty if the metastore has jobs not in the snapshot. The
+// records are upserted, so an interrupted restore can be run again.
+func Restore(ctx context.Context, cli pkgOrm.Client, snap *Snapshot) error {
+	if snap.Version != SnapshotVersion {
+		return derrors.ErrMetaSnapshotInvalid.GenWithStackByArgs(
+			fmt.Sprintf("unsupported version %d", snap.Version))
+	}
+
+	jobs := make(map[string]struct{}, len(snap.Jobs))
+	for _, job := range snap.Jobs {
+		jobs[job.ID] = struct{}{}
+	}
+	existingJobs, err := cli.QueryJobs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, job := range existingJobs {
+		if _, ok := jobs[job.ID]; !ok {
+			return derrors.ErrMetaImportNotEmpty.GenWithStackByArgs(job.ID)
+		}
+	}
+
+	// The epochs are advanced first, so that no epoch generated after the
+	// jobs are restored is less than theirs.
+	if err := cli.AdvanceEpoch(ctx, snap.Epoch); err != nil {
+		return err
+	}
+
+	existingProjects, err := cli.QueryProjects(ctx)
+	if err != nil {
+		return err
+	}
+	projects := make(map[string]struct{}, len(existingProjects))
+	for _, project := range existingProjects {
+		projects[project.ID] = struct{}{}
+	}
+	newProjects := make(map[string]struct{})
+	for _, project := range snap.Projects {
+		if _, ok := projects[project.ID]; ok {
+			continue
+		}
+		project.SeqID = 0
+		if err := cli.CreateProject(ctx, project); err != nil {
+			return err
+		}
+		newProjects[project.ID] = struct{}{}
+	}
+	// the operations of the existing projects are not duplicated
+	for _, op := range snap.ProjectOperations {
+		if _, ok := newProjects[op.ProjectID]; !ok {
+			continue
+		}
+		op.SeqID = 0
+		if err := cli.CreateProjectOperation(ctx, op); err != nil {
+			return err
+		}
+	}
+
+	// the sequence ids are generated by the metastore
+	for _, job := range snap.Jobs {
+		job.SeqID = 0
+		if err := cli.UpsertJob(ctx, job); err != nil {
+			return err
+		}
+	}
+	for _, worker := range snap.Workers {
+		worker.SeqID = 0
+		if err := cli.UpsertWorker(ctx, worker); err != nil {
+			return err
+		}
+	}
+	for _, resource := range snap.Resources {
+		resource.SeqID = 0
+		if err := cli.UpsertResource(ctx, resource); err != nil {
+			return err
+		}
+	}
+	for _, lease := range snap.ResourceLeases {
+		lease.SeqID = 0
+		if err := cli.UpsertResourceLease(ctx, lease); err != nil {
+			return err
+		}
+	}
+	for _, deletion := range snap.JobDeletions {
+		deletion.SeqID = 0
+		if err := cli.UpsertJobDeletion(ctx, deletion); err != nil {
+			return err
+		}
+	}
+	for _, schedule := range snap.JobSchedules {
+		schedule.SeqID = 0
+		if err := cli.UpsertJobSchedule(ctx, schedule); err != nil {
+			return err
+		}
+	}
+	for _, template := range snap.JobTemplates {
+		template.SeqID = 0
+		if err := cli.UpsertJobTemplate(ctx, template); err != nil {
+			return err
+		}
+	}
+	for _, secret := range snap.Secrets {
+		secret.SeqID = 0
+		if err := cli.UpsertSecret(ctx, secret); err != nil {
+			return err
+		}
+	}
+	for _, cordon := range snap.ExecutorCordons {
+		cordon.SeqID = 0
+		if err := cli.UpsertExecutorCordon(ctx, cordon); err != nil {
+			return err
+		}
+	}
+	for _, task := range snap.QueuedTasks {
+		task.SeqID = 0
+		if err := cli.UpsertQueuedTask(ctx, task); err != nil {
+			return err
+		}
+	}
+
+	log.L().Info("metadata snapshot restored",
+		zap.Time("created-at", snap.CreatedAt),
+		zap.Int64("epoch", snap.Epoch),
+		zap.Int("jobs", len(snap.Jobs)),
+		zap.Int("workers", len(snap.Workers)),
+		zap.Int("resources", len(snap.Resources)))
+	return nil
+}
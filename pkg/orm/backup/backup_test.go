@@ -0,0 +1,130 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	derrors "github.com/hanfei1991/microcosm/pkg/errors"
+	resModel "github.com/hanfei1991/microcosm/pkg/externalresource/resourcemeta/model"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+	"github.com/hanfei1991/microcosm/pkg/orm/model"
+)
+
+func TestExportImport(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	src, err := pkgOrm.NewMockClient()
+	require.NoError(t, err)
+	defer src.Close()
+
+	require.NoError(t, src.CreateProject(ctx, &model.ProjectInfo{ID: "project-1", Name: "project"}))
+	require.NoError(t, src.CreateProjectOperation(ctx, &model.ProjectOperation{
+		ProjectID: "project-1", Operation: "Submit", JobID: "job-1",
+	}))
+	var epoch libModel.Epoch
+	for i := 0; i < 3; i++ {
+		epoch, err = src.GenEpoch(ctx)
+		require.NoError(t, err)
+	}
+	require.NoError(t, src.UpsertJob(ctx, &libModel.MasterMetaKVData{
+		ProjectID: "project-1", ID: "job-1", Epoch: epoch, Config: []byte("config"),
+	}))
+	require.NoError(t, src.UpsertWorker(ctx, &libModel.WorkerStatus{
+		ProjectID: "project-1", JobID: "job-1", ID: "worker-1", Code: libModel.WorkerStatusNormal,
+	}))
+	require.NoError(t, src.UpsertResource(ctx, &resModel.ResourceMeta{
+		ProjectID: "project-1", ID: "/local/resource-1", Job: "job-1", Worker: "worker-1", Executor: "executor-1",
+	}))
+	require.NoError(t, src.UpsertResourceLease(ctx, &resModel.ResourceLease{
+		ID: "/local/resource-2", Job: "job-1", Worker: "worker-1", Executor: "executor-1",
+	}))
+	require.NoError(t, src.UpsertJobDeletion(ctx, &model.JobDeletion{JobID: "job-2", Stage: "workers"}))
+	require.NoError(t, src.UpsertJobSchedule(ctx, &model.JobSchedule{
+		ScheduleID: "schedule-1", ProjectID: "project-1", Cron: "@hourly", CatchUp: model.CatchUpSkip,
+	}))
+	require.NoError(t, src.UpsertJobTemplate(ctx, &model.JobTemplate{TemplateID: "template-1", Template: []byte("{}")}))
+	require.NoError(t, src.UpsertExecutorCordon(ctx, &model.ExecutorCordon{ExecutorID: "executor-1", Blacklisted: true}))
+	require.NoError(t, src.UpsertQueuedTask(ctx, &model.QueuedTask{TaskID: "task-1", Priority: 1, Deadline: time.Now()}))
+
+	var buf bytes.Buffer
+	snap, err := Export(ctx, src, &buf)
+	require.NoError(t, err)
+	require.Equal(t, epoch, snap.Epoch)
+	require.Len(t, snap.Jobs, 1)
+
+	dst, err := pkgOrm.NewMockClient()
+	require.NoError(t, err)
+	defer dst.Close()
+	data := buf.Bytes()
+	_, err = Import(ctx, dst, bytes.NewReader(data))
+	require.NoError(t, err)
+	// an interrupted import can be run again
+	_, err = Import(ctx, dst, bytes.NewReader(data))
+	require.NoError(t, err)
+
+	projects, err := dst.QueryProjects(ctx)
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+	ops, err := dst.QueryProjectOperations(ctx, "project-1")
+	require.NoError(t, err)
+	require.Len(t, ops, 1)
+	job, err := dst.GetJobByID(ctx, "job-1")
+	require.NoError(t, err)
+	require.Equal(t, []byte("config"), job.Config)
+	require.Equal(t, epoch, job.Epoch)
+	worker, err := dst.GetWorkerByID(ctx, "job-1", "worker-1")
+	require.NoError(t, err)
+	require.Equal(t, libModel.WorkerStatusNormal, worker.Code)
+	resource, err := dst.GetResourceByID(ctx, "/local/resource-1")
+	require.NoError(t, err)
+	require.Equal(t, "executor-1", string(resource.Executor))
+	lease, err := dst.GetResourceLeaseByID(ctx, "/local/resource-2")
+	require.NoError(t, err)
+	require.True(t, lease.IsHeldBy("job-1", "worker-1", "executor-1"))
+	deletions, err := dst.QueryJobDeletions(ctx)
+	require.NoError(t, err)
+	require.Len(t, deletions, 1)
+	_, err = dst.GetJobScheduleByID(ctx, "schedule-1")
+	require.NoError(t, err)
+	_, err = dst.GetJobTemplateByID(ctx, "template-1")
+	require.NoError(t, err)
+	cordon, err := dst.GetExecutorCordonByID(ctx, "executor-1")
+	require.NoError(t, err)
+	require.True(t, cordon.Blacklisted)
+	task, err := dst.GetQueuedTaskByID(ctx, "task-1")
+	require.NoError(t, err)
+	require.Equal(t, int32(1), task.Priority)
+
+	// the epochs generated after the import are greater than the restored ones
+	newEpoch, err := dst.GenEpoch(ctx)
+	require.NoError(t, err)
+	require.Greater(t, newEpoch, epoch)
+}
+
+func TestImportInvalid(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cli, err := pkgOrm.NewMockClient()
+	require.NoError(t, err)
+	defer cli.Close()
+
+	_, err = Import(ctx, cli, bytes.NewReader([]byte("not a snapshot")))
+	require.True(t, derrors.ErrMetaSnapshotInvalid.Equal(err))
+	_, err = Import(ctx, cli, bytes.NewReader([]byte(`{"version": 100}`)))
+	require.True(t, derrors.ErrMetaSnapshotInvalid.Equal(err))
+
+	// the metastore has a job not in the snapshot
+	require.NoError(t, cli.UpsertJob(ctx, &libModel.MasterMetaKVData{ProjectID: "project-1", ID: "job-1"}))
+	err = Restore(ctx, cli, &Snapshot{
+		Version: SnapshotVersion,
+		Jobs:    []*libModel.MasterMetaKVData{{ProjectID: "project-1", ID: "job-2"}},
+	})
+	require.True(t, derrors.ErrMetaImportNotEmpty.Equal(err))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"math"
 	"time"
 
 	dmysql "github.com/go-sql-driver/mysql"
@@ -27,7 +28,15 @@ var globalModels = []interface{}{
 	&libModel.MasterMetaKVData{},
 	&libModel.WorkerStatus{},
 	&resourcemeta.ResourceMeta{},
+	&resourcemeta.ResourceLease{},
 	&model.LogicEpoch{},
+	&model.LeaderFence{},
+	&model.JobDeletion{},
+	&model.JobSchedule{},
+	&model.JobTemplate{},
+	&model.Secret{},
+	&model.ExecutorCordon{},
+	&model.QueuedTask{},
 }
 
 // TODO: retry and idempotent??
@@ -54,6 +63,28 @@ type Client interface {
 	WorkerClient
 	// resource meta
 	ResourceClient
+	// leases of resource creations
+	ResourceLeaseClient
+	// job deletion progress
+	JobDeletionClient
+	// job schedule
+	JobScheduleClient
+	// job template
+	JobTemplateClient
+	// project secret
+	SecretClient
+	// executor cordon
+	ExecutorCordonClient
+	// schedule queue
+	QueuedTaskClient
+	// consistent snapshot read
+	SnapshotClient
+	// logic epoch restoring
+	LogicEpochClient
+	// leader fencing
+	FencingClient
+	// statement observing
+	ObserverClient
 
 	// Initialize will create all tables for backend operation
 	Initialize(ctx context.Context) error
@@ -74,28 +105,56 @@ type ProjectOperationClient interface {
 	CreateProjectOperation(ctx context.Context, op *model.ProjectOperation) error
 	QueryProjectOperations(ctx context.Context, projectID string) ([]*model.ProjectOperation, error)
 	QueryProjectOperationsByTimeRange(ctx context.Context, projectID string, tr TimeRange) ([]*model.ProjectOperation, error)
+	DeleteProjectOperationsByJobID(ctx context.Context, jobID string) (Result, error)
 }
 
 // JobClient defines interface that manages job in metastore
 type JobClient interface {
 	UpsertJob(ctx context.Context, job *libModel.MasterMetaKVData) error
 	UpdateJob(ctx context.Context, job *libModel.MasterMetaKVData) error
+	// CompareAndUpdateJob updates the job if its revision is still the given
+	// one, or it returns ErrMetaRevisionUnmatch.
+	CompareAndUpdateJob(ctx context.Context, job *libModel.MasterMetaKVData, revision int64) error
 	DeleteJob(ctx context.Context, jobID string) (Result, error)
 
 	GetJobByID(ctx context.Context, jobID string) (*libModel.MasterMetaKVData, error)
 	QueryJobs(ctx context.Context) ([]*libModel.MasterMetaKVData, error)
 	QueryJobsByProjectID(ctx context.Context, projectID string) ([]*libModel.MasterMetaKVData, error)
 	QueryJobsByStatus(ctx context.Context, jobID string, status int) ([]*libModel.MasterMetaKVData, error)
+	// QueryJobsByStatusCode returns all the jobs in the status, the least
+	// recently updated ones come first.
+	QueryJobsByStatusCode(ctx context.Context, status libModel.MasterStatusCode) ([]*libModel.MasterMetaKVData, error)
+	// QueryJobsByFilter returns the jobs selected by the filter, which are
+	// looked up by the indexes of the job table.
+	QueryJobsByFilter(ctx context.Context, filter *JobFilter) ([]*libModel.MasterMetaKVData, error)
+}
+
+// JobFilter selects jobs by the indexed columns, the zero values of the
+// fields mean no filtering on them.
+type JobFilter struct {
+	ProjectID   string
+	StatusCodes []libModel.MasterStatusCode
+	Types       []libModel.WorkerType
 }
 
 // WorkerClient defines interface that manages worker in metastore
 type WorkerClient interface {
 	UpsertWorker(ctx context.Context, worker *libModel.WorkerStatus) error
 	UpdateWorker(ctx context.Context, worker *libModel.WorkerStatus) error
+	// UpdateWorkers updates the workers in a single transaction
+	UpdateWorkers(ctx context.Context, workers []*libModel.WorkerStatus) error
 	DeleteWorker(ctx context.Context, masterID string, workerID string) (Result, error)
+	DeleteWorkersByMasterID(ctx context.Context, masterID string) (Result, error)
 	GetWorkerByID(ctx context.Context, masterID string, workerID string) (*libModel.WorkerStatus, error)
 	QueryWorkersByMasterID(ctx context.Context, masterID string) ([]*libModel.WorkerStatus, error)
 	QueryWorkersByStatus(ctx context.Context, masterID string, status int) ([]*libModel.WorkerStatus, error)
+	// QueryWorkerIndexByMasterID queries all workers of masterID with only
+	// their ids and status codes, which is much cheaper than loading the
+	// full statuses of a job with lots of workers.
+	QueryWorkerIndexByMasterID(ctx context.Context, masterID string) ([]*libModel.WorkerStatus, error)
+	QueryWorkersByIDs(ctx context.Context, masterID string, workerIDs []string) ([]*libModel.WorkerStatus, error)
+	// QueryWorkers queries the workers of all masters
+	QueryWorkers(ctx context.Context) ([]*libModel.WorkerStatus, error)
 }
 
 // ResourceClient defines interface that manages resource in metastore
@@ -104,14 +163,107 @@ type ResourceClient interface {
 	UpsertResource(ctx context.Context, resource *resourcemeta.ResourceMeta) error
 	UpdateResource(ctx context.Context, resource *resourcemeta.ResourceMeta) error
 	DeleteResource(ctx context.Context, resourceID string) (Result, error)
+	DeleteResourcesByJobID(ctx context.Context, jobID string) (Result, error)
 	GetResourceByID(ctx context.Context, resourceID string) (*resourcemeta.ResourceMeta, error)
 	QueryResources(ctx context.Context) ([]*resourcemeta.ResourceMeta, error)
 	QueryResourcesByJobID(ctx context.Context, jobID string) ([]*resourcemeta.ResourceMeta, error)
 	QueryResourcesByExecutorID(ctx context.Context, executorID string) ([]*resourcemeta.ResourceMeta, error)
 }
 
+// ResourceLeaseClient defines interface that manages the leases of resource
+// creations in metastore
+type ResourceLeaseClient interface {
+	CreateResourceLease(ctx context.Context, lease *resourcemeta.ResourceLease) error
+	UpsertResourceLease(ctx context.Context, lease *resourcemeta.ResourceLease) error
+	DeleteResourceLease(ctx context.Context, resourceID string) (Result, error)
+	GetResourceLeaseByID(ctx context.Context, resourceID string) (*resourcemeta.ResourceLease, error)
+	QueryResourceLeases(ctx context.Context) ([]*resourcemeta.ResourceLease, error)
+}
+
+// JobDeletionClient defines interface that manages the progress of job
+// deletions in metastore
+type JobDeletionClient interface {
+	UpsertJobDeletion(ctx context.Context, deletion *model.JobDeletion) error
+	DeleteJobDeletion(ctx context.Context, jobID string) (Result, error)
+	QueryJobDeletions(ctx context.Context) ([]*model.JobDeletion, error)
+}
+
+// JobScheduleClient defines interface that manages job schedules in metastore
+type JobScheduleClient interface {
+	UpsertJobSchedule(ctx context.Context, schedule *model.JobSchedule) error
+	DeleteJobSchedule(ctx context.Context, scheduleID string) (Result, error)
+	GetJobScheduleByID(ctx context.Context, scheduleID string) (*model.JobSchedule, error)
+	QueryJobSchedules(ctx context.Context) ([]*model.JobSchedule, error)
+}
+
+// JobTemplateClient defines interface that manages job templates in metastore
+type JobTemplateClient interface {
+	UpsertJobTemplate(ctx context.Context, template *model.JobTemplate) error
+	DeleteJobTemplate(ctx context.Context, templateID string) (Result, error)
+	GetJobTemplateByID(ctx context.Context, templateID string) (*model.JobTemplate, error)
+	QueryJobTemplates(ctx context.Context) ([]*model.JobTemplate, error)
+}
+
+// SecretClient defines interface that manages project secrets in metastore
+type SecretClient interface {
+	UpsertSecret(ctx context.Context, secret *model.Secret) error
+	DeleteSecret(ctx context.Context, projectID, name string) (Result, error)
+	GetSecret(ctx context.Context, projectID, name string) (*model.Secret, error)
+	QuerySecrets(ctx context.Context) ([]*model.Secret, error)
+}
+
+// ExecutorCordonClient defines interface that manages executor cordons in metastore
+type ExecutorCordonClient interface {
+	UpsertExecutorCordon(ctx context.Context, cordon *model.ExecutorCordon) error
+	DeleteExecutorCordon(ctx context.Context, executorID string) (Result, error)
+	GetExecutorCordonByID(ctx context.Context, executorID string) (*model.ExecutorCordon, error)
+	QueryExecutorCordons(ctx context.Context) ([]*model.ExecutorCordon, error)
+}
+
+// QueuedTaskClient defines interface that manages the schedule queue in metastore
+type QueuedTaskClient interface {
+	UpsertQueuedTask(ctx context.Context, task *model.QueuedTask) error
+	DeleteQueuedTask(ctx context.Context, taskID string) (Result, error)
+	GetQueuedTaskByID(ctx context.Context, taskID string) (*model.QueuedTask, error)
+	QueryQueuedTasks(ctx context.Context) ([]*model.QueuedTask, error)
+}
+
+// SnapshotClient defines interface that reads metastore consistently
+type SnapshotClient interface {
+	// SnapshotRead calls fn with a Client bound to a single transaction, all
+	// reads made by fn observe the same state of metastore. fn should only
+	// read through the given Client.
+	SnapshotRead(ctx context.Context, fn func(snapshot Client) error) error
+}
+
+// LogicEpochClient defines interface that reads and restores the logic epoch,
+// the epochs are generated by metaclient.Client.GenEpoch
+type LogicEpochClient interface {
+	// GetEpoch returns the current epoch without increasing it
+	GetEpoch(ctx context.Context) (libModel.Epoch, error)
+	// AdvanceEpoch makes the current epoch no less than the given one, so
+	// that the epochs generated after restoring a metastore never go back
+	AdvanceEpoch(ctx context.Context, epoch libModel.Epoch) error
+}
+
+// FencingClient defines interface that fences metastore writes of stale leaders
+type FencingClient interface {
+	// WithFencingToken returns a Client whose writes are rejected with
+	// ErrMetaLeaderFenced once a write with a larger token has been made,
+	// so a leader can't overwrite the metastore after losing leadership.
+	// Reads are not fenced.
+	WithFencingToken(token int64) Client
+	// FenceAll rejects the writes with any fencing token from now on, it
+	// fences the metastore retired by a migration.
+	FenceAll(ctx context.Context) error
+}
+
 // NewClient return the client to operate framework metastore
 func NewClient(mc metaclient.StoreConfigParams, conf DBConfig) (Client, error) {
+	if mc.StoreType == metaclient.StoreTypeSQLite {
+		return newSQLiteClient(mc)
+	}
+
 	err := createDatabaseForProject(mc, tenant.FrameTenantID, conf)
 	if err != nil {
 		return nil, err
@@ -187,7 +339,8 @@ func generateDSNByParams(mc metaclient.StoreConfigParams, projectID tenant.Proje
 func newSQLDB(driver string, dsn string, conf DBConfig) (*sql.DB, error) {
 	db, err := sql.Open(driver, dsn)
 	if err != nil {
-		log.L().Error("open dsn fail", zap.String("dsn", dsn), zap.Any("config", conf), zap.Error(err))
+		// the dsn is not logged as it carries the password
+		log.L().Error("open dsn fail", zap.Any("config", conf), zap.Error(err))
 		return nil, cerrors.ErrMetaOpFail.Wrap(err)
 	}
 
@@ -211,8 +364,36 @@ func newClient(sqlDB *sql.DB) (*metaOpsClient, error) {
 		return nil, cerrors.ErrMetaNewClientFail.Wrap(err)
 	}
 
+	return newMetaOpsClient(db)
+}
+
+// newSQLiteClient creates an orm client backed by a local SQLite file,
+// connections in the same process share the same database.
+func newSQLiteClient(mc metaclient.StoreConfigParams) (*metaOpsClient, error) {
+	if len(mc.Endpoints) == 0 {
+		return nil, cerrors.ErrMetaNewClientFail.GenWithStackByArgs()
+	}
+	dsn := fmt.Sprintf("file:%s?cache=shared&%s", mc.Endpoints[0], sqliteBusyTimeoutParam)
+	log.L().Info("sqlite connection", zap.String("dsn", dsn))
+	db, err := gorm.Open(sqliteDialector(dsn), &gorm.Config{
+		SkipDefaultTransaction: true,
+		// TODO: logger
+	})
+	if err != nil {
+		log.L().Error("create gorm client fail", zap.Error(err))
+		return nil, cerrors.ErrMetaNewClientFail.Wrap(err)
+	}
+
+	return newMetaOpsClient(db)
+}
+
+func newMetaOpsClient(db *gorm.DB) (*metaOpsClient, error) {
+	if err := registerObserverCallbacks(db); err != nil {
+		return nil, cerrors.ErrMetaNewClientFail.Wrap(err)
+	}
 	return &metaOpsClient{
-		db: db,
+		db:     db,
+		epochs: newEpochBatcher(db),
 	}, nil
 }
 
@@ -220,6 +401,9 @@ func newClient(sqlDB *sql.DB) (*metaOpsClient, error) {
 type metaOpsClient struct {
 	// gorm claim to be thread safe
 	db *gorm.DB
+	// epochs batches the epoch allocations, it is nil for the clients of
+	// transactions, which allocate epochs in the transactions.
+	epochs *epochBatcher
 }
 
 func (c *metaOpsClient) Close() error {
@@ -228,7 +412,9 @@ func (c *metaOpsClient) Close() error {
 		return err
 	}
 	if impl != nil {
-		return cerrors.ErrMetaOpFail.Wrap(impl.Close())
+		if err := impl.Close(); err != nil {
+			return cerrors.ErrMetaOpFail.Wrap(err)
+		}
 	}
 
 	return nil
@@ -244,14 +430,75 @@ func (c *metaOpsClient) Initialize(ctx context.Context) error {
 	}
 
 	// check first record in logic_epochs
-	return model.InitializeEpoch(ctx, c.db)
+	if err := model.InitializeEpoch(ctx, c.db); err != nil {
+		return err
+	}
+
+	return model.InitializeLeaderFence(ctx, c.db)
 }
 
 /////////////////////////////// Logic Epoch
 func (c *metaOpsClient) GenEpoch(ctx context.Context) (libModel.Epoch, error) {
+	if c.epochs != nil {
+		return c.epochs.GenEpoch(ctx)
+	}
 	return model.GenEpoch(ctx, c.db)
 }
 
+// GetEpoch implements LogicEpochClient.GetEpoch
+func (c *metaOpsClient) GetEpoch(ctx context.Context) (libModel.Epoch, error) {
+	epoch, err := model.GetEpoch(ctx, c.db)
+	if err != nil {
+		return 0, cerrors.ErrMetaOpFail.Wrap(err)
+	}
+	return epoch, nil
+}
+
+// AdvanceEpoch implements LogicEpochClient.AdvanceEpoch
+func (c *metaOpsClient) AdvanceEpoch(ctx context.Context, epoch libModel.Epoch) error {
+	if err := model.AdvanceEpoch(ctx, c.db, epoch); err != nil {
+		return cerrors.ErrMetaOpFail.Wrap(err)
+	}
+	return nil
+}
+
+/////////////////////////////// Snapshot Read
+// SnapshotRead reads within one transaction. Under the default REPEATABLE READ
+// isolation level of MySQL, all consistent reads in the transaction see the
+// snapshot established by the first read.
+func (c *metaOpsClient) SnapshotRead(ctx context.Context, fn func(snapshot Client) error) error {
+	// errors returned by fn are not wrapped, so that callers can still
+	// check them, e.g. by IsNotFoundError
+	return c.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&metaOpsClient{db: tx})
+	})
+}
+
+/////////////////////////////// Leader Fencing
+// WithFencingToken implements FencingClient.WithFencingToken
+func (c *metaOpsClient) WithFencingToken(token int64) Client {
+	return &fencedClient{metaOpsClient: c, token: token}
+}
+
+// FenceAll implements FencingClient.FenceAll
+func (c *metaOpsClient) FenceAll(ctx context.Context) error {
+	return c.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := model.AdvanceLeaderFence(ctx, tx, math.MaxInt64); err != nil {
+			return cerrors.ErrMetaOpFail.Wrap(err)
+		}
+		return nil
+	})
+}
+
+/////////////////////////////// Statement Observing
+// WithObserver implements ObserverClient.WithObserver
+func (c *metaOpsClient) WithObserver(observer StatementObserver) Client {
+	return &metaOpsClient{
+		db:     withObserver(c.db, observer),
+		epochs: c.epochs,
+	}
+}
+
 ///////////////////////// Project Operation
 // CreateProject insert the model.ProjectInfo
 func (c *metaOpsClient) CreateProject(ctx context.Context, project *model.ProjectInfo) error {
@@ -334,6 +581,16 @@ func (c *metaOpsClient) QueryProjectOperationsByTimeRange(ctx context.Context,
 	return projectOps, nil
 }
 
+// DeleteProjectOperationsByJobID delete all operations of the jobID
+func (c *metaOpsClient) DeleteProjectOperationsByJobID(ctx context.Context, jobID string) (Result, error) {
+	result := c.db.Where("job_id = ?", jobID).Delete(&model.ProjectOperation{})
+	if result.Error != nil {
+		return nil, cerrors.ErrMetaOpFail.Wrap(result.Error)
+	}
+
+	return &ormResult{rowsAffected: result.RowsAffected}, nil
+}
+
 /////////////////////////////// Job Operation
 // UpsertJob upsert the jobInfo
 func (c *metaOpsClient) UpsertJob(ctx context.Context, job *libModel.MasterMetaKVData) error {
@@ -341,9 +598,14 @@ func (c *metaOpsClient) UpsertJob(ctx context.Context, job *libModel.MasterMetaK
 		return cerrors.ErrMetaParamsInvalid.GenWithStackByArgs("input master meta is nil")
 	}
 
+	doUpdates := clause.AssignmentColumns(libModel.MasterUpdateColumns)
+	doUpdates = append(doUpdates, clause.Assignment{
+		Column: clause.Column{Name: "revision"},
+		Value:  gorm.Expr("revision + 1"),
+	})
 	if err := c.db.Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "id"}},
-		DoUpdates: clause.AssignmentColumns(libModel.MasterUpdateColumns),
+		DoUpdates: doUpdates,
 	}).Create(job).Error; err != nil {
 		return cerrors.ErrMetaOpFail.Wrap(err)
 	}
@@ -358,13 +620,38 @@ func (c *metaOpsClient) UpdateJob(ctx context.Context, job *libModel.MasterMetaK
 	}
 	// we don't use `Save` here to avoid user dealing with the basic model
 	// expected SQL: UPDATE xxx SET xxx='xxx', updated_at='2013-11-17 21:34:10' WHERE id=xxx;
-	if err := c.db.Model(&libModel.MasterMetaKVData{}).Where("id = ?", job.ID).Updates(job.Map()).Error; err != nil {
+	if err := c.db.Model(&libModel.MasterMetaKVData{}).Where("id = ?", job.ID).Updates(jobUpdates(job)).Error; err != nil {
 		return cerrors.ErrMetaOpFail.Wrap(err)
 	}
 
 	return nil
 }
 
+// CompareAndUpdateJob updates the jobInfo if its revision is still revision
+func (c *metaOpsClient) CompareAndUpdateJob(ctx context.Context, job *libModel.MasterMetaKVData, revision int64) error {
+	if job == nil {
+		return cerrors.ErrMetaParamsInvalid.GenWithStackByArgs("input master meta is nil")
+	}
+	result := c.db.Model(&libModel.MasterMetaKVData{}).
+		Where("id = ? AND revision = ?", job.ID, revision).Updates(jobUpdates(job))
+	if result.Error != nil {
+		return cerrors.ErrMetaOpFail.Wrap(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return cerrors.ErrMetaRevisionUnmatch.GenWithStackByArgs()
+	}
+
+	return nil
+}
+
+// jobUpdates returns the columns of an update of the jobInfo, which increases
+// the revision.
+func jobUpdates(job *libModel.MasterMetaKVData) map[string]interface{} {
+	updates := job.Map()
+	updates["revision"] = gorm.Expr("revision + 1")
+	return updates
+}
+
 // DeleteJob delete the specified jobInfo
 func (c *metaOpsClient) DeleteJob(ctx context.Context, jobID string) (Result, error) {
 	result := c.db.Where("id = ?", jobID).Delete(&libModel.MasterMetaKVData{})
@@ -421,6 +708,40 @@ func (c *metaOpsClient) QueryJobsByStatus(ctx context.Context,
 	return jobs, nil
 }
 
+// QueryJobsByStatusCode implements JobClient.QueryJobsByStatusCode
+func (c *metaOpsClient) QueryJobsByStatusCode(ctx context.Context,
+	status libModel.MasterStatusCode,
+) ([]*libModel.MasterMetaKVData, error) {
+	var jobs []*libModel.MasterMetaKVData
+	if result := c.db.Where("status = ?", status).Order("updated_at").Find(&jobs); result.Error != nil {
+		return nil, cerrors.ErrMetaOpFail.Wrap(result.Error)
+	}
+
+	return jobs, nil
+}
+
+// QueryJobsByFilter implements JobClient.QueryJobsByFilter
+func (c *metaOpsClient) QueryJobsByFilter(ctx context.Context,
+	filter *JobFilter,
+) ([]*libModel.MasterMetaKVData, error) {
+	db := c.db
+	if filter.ProjectID != "" {
+		db = db.Where("project_id = ?", filter.ProjectID)
+	}
+	if len(filter.StatusCodes) > 0 {
+		db = db.Where("status IN ?", filter.StatusCodes)
+	}
+	if len(filter.Types) > 0 {
+		db = db.Where("type IN ?", filter.Types)
+	}
+	var jobs []*libModel.MasterMetaKVData
+	if result := db.Find(&jobs); result.Error != nil {
+		return nil, cerrors.ErrMetaOpFail.Wrap(result.Error)
+	}
+
+	return jobs, nil
+}
+
 /////////////////////////////// Worker Operation
 // UpsertWorker insert the workerInfo
 func (c *metaOpsClient) UpsertWorker(ctx context.Context, worker *libModel.WorkerStatus) error {
@@ -450,6 +771,22 @@ func (c *metaOpsClient) UpdateWorker(ctx context.Context, worker *libModel.Worke
 	return nil
 }
 
+// UpdateWorkers updates the workers in a single transaction
+func (c *metaOpsClient) UpdateWorkers(ctx context.Context, workers []*libModel.WorkerStatus) error {
+	return c.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return (&metaOpsClient{db: tx}).updateWorkers(ctx, workers)
+	})
+}
+
+func (c *metaOpsClient) updateWorkers(ctx context.Context, workers []*libModel.WorkerStatus) error {
+	for _, worker := range workers {
+		if err := c.UpdateWorker(ctx, worker); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // DeleteWorker delete the specified workInfo
 func (c *metaOpsClient) DeleteWorker(ctx context.Context, masterID string, workerID string) (Result, error) {
 	result := c.db.Where("job_id = ? AND id = ?", masterID, workerID).Delete(&libModel.WorkerStatus{})
@@ -460,6 +797,16 @@ func (c *metaOpsClient) DeleteWorker(ctx context.Context, masterID string, worke
 	return &ormResult{rowsAffected: result.RowsAffected}, nil
 }
 
+// DeleteWorkersByMasterID delete all workInfos of the masterID
+func (c *metaOpsClient) DeleteWorkersByMasterID(ctx context.Context, masterID string) (Result, error) {
+	result := c.db.Where("job_id = ?", masterID).Delete(&libModel.WorkerStatus{})
+	if result.Error != nil {
+		return nil, cerrors.ErrMetaOpFail.Wrap(result.Error)
+	}
+
+	return &ormResult{rowsAffected: result.RowsAffected}, nil
+}
+
 // GetWorkerByID query worker info by workerID
 func (c *metaOpsClient) GetWorkerByID(ctx context.Context, masterID string, workerID string) (*libModel.WorkerStatus, error) {
 	var worker libModel.WorkerStatus
@@ -485,6 +832,16 @@ func (c *metaOpsClient) QueryWorkersByMasterID(ctx context.Context, masterID str
 	return workers, nil
 }
 
+// QueryWorkers query the workers of all masters
+func (c *metaOpsClient) QueryWorkers(ctx context.Context) ([]*libModel.WorkerStatus, error) {
+	var workers []*libModel.WorkerStatus
+	if result := c.db.Find(&workers); result.Error != nil {
+		return nil, cerrors.ErrMetaOpFail.Wrap(result.Error)
+	}
+
+	return workers, nil
+}
+
 // QueryWorkersByStatus query all workers with specified status of masterID
 func (c *metaOpsClient) QueryWorkersByStatus(ctx context.Context, masterID string, status int) ([]*libModel.WorkerStatus, error) {
 	var workers []*libModel.WorkerStatus
@@ -496,6 +853,31 @@ func (c *metaOpsClient) QueryWorkersByStatus(ctx context.Context, masterID strin
 	return workers, nil
 }
 
+// QueryWorkerIndexByMasterID query the ids and status codes of all workers of masterID
+func (c *metaOpsClient) QueryWorkerIndexByMasterID(ctx context.Context, masterID string) ([]*libModel.WorkerStatus, error) {
+	var workers []*libModel.WorkerStatus
+	if result := c.db.Select("job_id", "id", "status").Where("job_id = ?", masterID).
+		Find(&workers); result.Error != nil {
+		return nil, cerrors.ErrMetaOpFail.Wrap(result.Error)
+	}
+
+	return workers, nil
+}
+
+// QueryWorkersByIDs query the workers of masterID with the given workerIDs
+func (c *metaOpsClient) QueryWorkersByIDs(ctx context.Context, masterID string, workerIDs []string) ([]*libModel.WorkerStatus, error) {
+	var workers []*libModel.WorkerStatus
+	if len(workerIDs) == 0 {
+		return workers, nil
+	}
+	if result := c.db.Where("job_id = ? AND id IN ?", masterID,
+		workerIDs).Find(&workers); result.Error != nil {
+		return nil, cerrors.ErrMetaOpFail.Wrap(result.Error)
+	}
+
+	return workers, nil
+}
+
 /////////////////////////////// Resource Operation
 // UpsertResource upsert the ResourceMeta
 func (c *metaOpsClient) UpsertResource(ctx context.Context, resource *resourcemeta.ResourceMeta) error {
@@ -565,6 +947,16 @@ func (c *metaOpsClient) DeleteResource(ctx context.Context, resourceID string) (
 	return &ormResult{rowsAffected: result.RowsAffected}, nil
 }
 
+// DeleteResourcesByJobID delete all resources of the jobID
+func (c *metaOpsClient) DeleteResourcesByJobID(ctx context.Context, jobID string) (Result, error) {
+	result := c.db.Where("job_id = ?", jobID).Delete(&resourcemeta.ResourceMeta{})
+	if result.Error != nil {
+		return nil, cerrors.ErrMetaOpFail.Wrap(result.Error)
+	}
+
+	return &ormResult{rowsAffected: result.RowsAffected}, nil
+}
+
 // GetResourceByID query resource of the resource_id
 func (c *metaOpsClient) GetResourceByID(ctx context.Context, resourceID string) (*resourcemeta.ResourceMeta, error) {
 	var resource resourcemeta.ResourceMeta
@@ -608,6 +1000,380 @@ func (c *metaOpsClient) QueryResourcesByExecutorID(ctx context.Context, executor
 	return resources, nil
 }
 
+/////////////////////////////// Resource Lease Operation
+// CreateResourceLease creates the lease of a resource, it fails if the
+// resource has been leased
+func (c *metaOpsClient) CreateResourceLease(ctx context.Context, lease *resourcemeta.ResourceLease) error {
+	if lease == nil {
+		return cerrors.ErrMetaParamsInvalid.GenWithStackByArgs("input resource lease is nil")
+	}
+
+	err := c.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		err := tx.Model(&resourcemeta.ResourceLease{}).
+			Where("id = ?", lease.ID).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+
+		if count > 0 {
+			return cerrors.ErrDuplicateResourceID.GenWithStackByArgs(lease.ID)
+		}
+
+		if err := tx.Create(lease).Error; err != nil {
+			return cerrors.ErrMetaOpFail.Wrap(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return cerrors.ErrMetaOpFail.Wrap(err)
+	}
+	return nil
+}
+
+// UpsertResourceLease upsert the lease of a resource
+func (c *metaOpsClient) UpsertResourceLease(ctx context.Context, lease *resourcemeta.ResourceLease) error {
+	if lease == nil {
+		return cerrors.ErrMetaParamsInvalid.GenWithStackByArgs("input resource lease is nil")
+	}
+
+	if err := c.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns(resourcemeta.ResourceLeaseUpdateColumns),
+	}).Create(lease).Error; err != nil {
+		return cerrors.ErrMetaOpFail.Wrap(err)
+	}
+
+	return nil
+}
+
+// DeleteResourceLease delete the lease of the resource
+func (c *metaOpsClient) DeleteResourceLease(ctx context.Context, resourceID string) (Result, error) {
+	result := c.db.Where("id = ?", resourceID).Delete(&resourcemeta.ResourceLease{})
+	if result.Error != nil {
+		return nil, cerrors.ErrMetaOpFail.Wrap(result.Error)
+	}
+
+	return &ormResult{rowsAffected: result.RowsAffected}, nil
+}
+
+// GetResourceLeaseByID query the lease of the resource
+func (c *metaOpsClient) GetResourceLeaseByID(ctx context.Context, resourceID string) (*resourcemeta.ResourceLease, error) {
+	var lease resourcemeta.ResourceLease
+	if result := c.db.Where("id = ?", resourceID).First(&lease); result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, cerrors.ErrMetaEntryNotFound.Wrap(result.Error)
+		}
+
+		return nil, cerrors.ErrMetaOpFail.Wrap(result.Error)
+	}
+
+	return &lease, nil
+}
+
+// QueryResourceLeases query all resource leases
+func (c *metaOpsClient) QueryResourceLeases(ctx context.Context) ([]*resourcemeta.ResourceLease, error) {
+	var leases []*resourcemeta.ResourceLease
+	if result := c.db.Find(&leases); result.Error != nil {
+		return nil, cerrors.ErrMetaOpFail.Wrap(result.Error)
+	}
+
+	return leases, nil
+}
+
+/////////////////////////////// Job Deletion Operation
+// UpsertJobDeletion upsert the deletion progress of a job
+func (c *metaOpsClient) UpsertJobDeletion(ctx context.Context, deletion *model.JobDeletion) error {
+	if deletion == nil {
+		return cerrors.ErrMetaParamsInvalid.GenWithStackByArgs("input job deletion is nil")
+	}
+
+	if err := c.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "job_id"}},
+		DoUpdates: clause.AssignmentColumns(model.JobDeletionUpdateColumns),
+	}).Create(deletion).Error; err != nil {
+		return cerrors.ErrMetaOpFail.Wrap(err)
+	}
+
+	return nil
+}
+
+// DeleteJobDeletion delete the deletion progress of the jobID
+func (c *metaOpsClient) DeleteJobDeletion(ctx context.Context, jobID string) (Result, error) {
+	result := c.db.Where("job_id = ?", jobID).Delete(&model.JobDeletion{})
+	if result.Error != nil {
+		return nil, cerrors.ErrMetaOpFail.Wrap(result.Error)
+	}
+
+	return &ormResult{rowsAffected: result.RowsAffected}, nil
+}
+
+// QueryJobDeletions query all unfinished job deletions
+func (c *metaOpsClient) QueryJobDeletions(ctx context.Context) ([]*model.JobDeletion, error) {
+	var deletions []*model.JobDeletion
+	if result := c.db.Find(&deletions); result.Error != nil {
+		return nil, cerrors.ErrMetaOpFail.Wrap(result.Error)
+	}
+
+	return deletions, nil
+}
+
+/////////////////////////////// Job Schedule Operation
+// UpsertJobSchedule upsert the job schedule
+func (c *metaOpsClient) UpsertJobSchedule(ctx context.Context, schedule *model.JobSchedule) error {
+	if schedule == nil {
+		return cerrors.ErrMetaParamsInvalid.GenWithStackByArgs("input job schedule is nil")
+	}
+
+	if err := c.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "schedule_id"}},
+		DoUpdates: clause.AssignmentColumns(model.JobScheduleUpdateColumns),
+	}).Create(schedule).Error; err != nil {
+		return cerrors.ErrMetaOpFail.Wrap(err)
+	}
+
+	return nil
+}
+
+// DeleteJobSchedule delete the job schedule of the scheduleID
+func (c *metaOpsClient) DeleteJobSchedule(ctx context.Context, scheduleID string) (Result, error) {
+	result := c.db.Where("schedule_id = ?", scheduleID).Delete(&model.JobSchedule{})
+	if result.Error != nil {
+		return nil, cerrors.ErrMetaOpFail.Wrap(result.Error)
+	}
+
+	return &ormResult{rowsAffected: result.RowsAffected}, nil
+}
+
+// GetJobScheduleByID query the job schedule of the scheduleID
+func (c *metaOpsClient) GetJobScheduleByID(ctx context.Context, scheduleID string) (*model.JobSchedule, error) {
+	var schedule model.JobSchedule
+	if result := c.db.Where("schedule_id = ?", scheduleID).First(&schedule); result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, cerrors.ErrMetaEntryNotFound.Wrap(result.Error)
+		}
+
+		return nil, cerrors.ErrMetaOpFail.Wrap(result.Error)
+	}
+
+	return &schedule, nil
+}
+
+// QueryJobSchedules query all job schedules
+func (c *metaOpsClient) QueryJobSchedules(ctx context.Context) ([]*model.JobSchedule, error) {
+	var schedules []*model.JobSchedule
+	if result := c.db.Find(&schedules); result.Error != nil {
+		return nil, cerrors.ErrMetaOpFail.Wrap(result.Error)
+	}
+
+	return schedules, nil
+}
+
+/////////////////////////////// Job Template Operation
+// UpsertJobTemplate upsert the job template
+func (c *metaOpsClient) UpsertJobTemplate(ctx context.Context, template *model.JobTemplate) error {
+	if template == nil {
+		return cerrors.ErrMetaParamsInvalid.GenWithStackByArgs("input job template is nil")
+	}
+
+	if err := c.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "template_id"}},
+		DoUpdates: clause.AssignmentColumns(model.JobTemplateUpdateColumns),
+	}).Create(template).Error; err != nil {
+		return cerrors.ErrMetaOpFail.Wrap(err)
+	}
+
+	return nil
+}
+
+// DeleteJobTemplate delete the job template of the templateID
+func (c *metaOpsClient) DeleteJobTemplate(ctx context.Context, templateID string) (Result, error) {
+	result := c.db.Where("template_id = ?", templateID).Delete(&model.JobTemplate{})
+	if result.Error != nil {
+		return nil, cerrors.ErrMetaOpFail.Wrap(result.Error)
+	}
+
+	return &ormResult{rowsAffected: result.RowsAffected}, nil
+}
+
+// GetJobTemplateByID query the job template of the templateID
+func (c *metaOpsClient) GetJobTemplateByID(ctx context.Context, templateID string) (*model.JobTemplate, error) {
+	var template model.JobTemplate
+	if result := c.db.Where("template_id = ?", templateID).First(&template); result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, cerrors.ErrMetaEntryNotFound.Wrap(result.Error)
+		}
+
+		return nil, cerrors.ErrMetaOpFail.Wrap(result.Error)
+	}
+
+	return &template, nil
+}
+
+// QueryJobTemplates query all job templates
+func (c *metaOpsClient) QueryJobTemplates(ctx context.Context) ([]*model.JobTemplate, error) {
+	var templates []*model.JobTemplate
+	if result := c.db.Find(&templates); result.Error != nil {
+		return nil, cerrors.ErrMetaOpFail.Wrap(result.Error)
+	}
+
+	return templates, nil
+}
+
+/////////////////////////////// Secret Operation
+// UpsertSecret upsert the secret
+func (c *metaOpsClient) UpsertSecret(ctx context.Context, secret *model.Secret) error {
+	if secret == nil {
+		return cerrors.ErrMetaParamsInvalid.GenWithStackByArgs("input secret is nil")
+	}
+
+	if err := c.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "project_id"}, {Name: "name"}},
+		DoUpdates: clause.AssignmentColumns(model.SecretUpdateColumns),
+	}).Create(secret).Error; err != nil {
+		return cerrors.ErrMetaOpFail.Wrap(err)
+	}
+
+	return nil
+}
+
+// DeleteSecret delete the secret of the project
+func (c *metaOpsClient) DeleteSecret(ctx context.Context, projectID, name string) (Result, error) {
+	result := c.db.Where("project_id = ? AND name = ?", projectID, name).Delete(&model.Secret{})
+	if result.Error != nil {
+		return nil, cerrors.ErrMetaOpFail.Wrap(result.Error)
+	}
+
+	return &ormResult{rowsAffected: result.RowsAffected}, nil
+}
+
+// GetSecret query the secret of the project
+func (c *metaOpsClient) GetSecret(ctx context.Context, projectID, name string) (*model.Secret, error) {
+	var secret model.Secret
+	if result := c.db.Where("project_id = ? AND name = ?", projectID, name).First(&secret); result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, cerrors.ErrMetaEntryNotFound.Wrap(result.Error)
+		}
+
+		return nil, cerrors.ErrMetaOpFail.Wrap(result.Error)
+	}
+
+	return &secret, nil
+}
+
+// QuerySecrets query the secrets of all projects
+func (c *metaOpsClient) QuerySecrets(ctx context.Context) ([]*model.Secret, error) {
+	var secrets []*model.Secret
+	if result := c.db.Find(&secrets); result.Error != nil {
+		return nil, cerrors.ErrMetaOpFail.Wrap(result.Error)
+	}
+
+	return secrets, nil
+}
+
+/////////////////////////////// Executor Cordon Operation
+// UpsertExecutorCordon upsert the executor cordon
+func (c *metaOpsClient) UpsertExecutorCordon(ctx context.Context, cordon *model.ExecutorCordon) error {
+	if cordon == nil {
+		return cerrors.ErrMetaParamsInvalid.GenWithStackByArgs("input executor cordon is nil")
+	}
+
+	if err := c.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "executor_id"}},
+		DoUpdates: clause.AssignmentColumns(model.ExecutorCordonUpdateColumns),
+	}).Create(cordon).Error; err != nil {
+		return cerrors.ErrMetaOpFail.Wrap(err)
+	}
+
+	return nil
+}
+
+// DeleteExecutorCordon delete the cordon of the executorID
+func (c *metaOpsClient) DeleteExecutorCordon(ctx context.Context, executorID string) (Result, error) {
+	result := c.db.Where("executor_id = ?", executorID).Delete(&model.ExecutorCordon{})
+	if result.Error != nil {
+		return nil, cerrors.ErrMetaOpFail.Wrap(result.Error)
+	}
+
+	return &ormResult{rowsAffected: result.RowsAffected}, nil
+}
+
+// GetExecutorCordonByID query the cordon of the executorID
+func (c *metaOpsClient) GetExecutorCordonByID(ctx context.Context, executorID string) (*model.ExecutorCordon, error) {
+	var cordon model.ExecutorCordon
+	if result := c.db.Where("executor_id = ?", executorID).First(&cordon); result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, cerrors.ErrMetaEntryNotFound.Wrap(result.Error)
+		}
+
+		return nil, cerrors.ErrMetaOpFail.Wrap(result.Error)
+	}
+
+	return &cordon, nil
+}
+
+// QueryExecutorCordons query all executor cordons
+func (c *metaOpsClient) QueryExecutorCordons(ctx context.Context) ([]*model.ExecutorCordon, error) {
+	var cordons []*model.ExecutorCordon
+	if result := c.db.Find(&cordons); result.Error != nil {
+		return nil, cerrors.ErrMetaOpFail.Wrap(result.Error)
+	}
+
+	return cordons, nil
+}
+
+/////////////////////////////// Queued Task Operation
+// UpsertQueuedTask upsert the queued task
+func (c *metaOpsClient) UpsertQueuedTask(ctx context.Context, task *model.QueuedTask) error {
+	if task == nil {
+		return cerrors.ErrMetaParamsInvalid.GenWithStackByArgs("input queued task is nil")
+	}
+
+	if err := c.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "task_id"}},
+		DoUpdates: clause.AssignmentColumns(model.QueuedTaskUpdateColumns),
+	}).Create(task).Error; err != nil {
+		return cerrors.ErrMetaOpFail.Wrap(err)
+	}
+
+	return nil
+}
+
+// DeleteQueuedTask delete the queued task of the taskID
+func (c *metaOpsClient) DeleteQueuedTask(ctx context.Context, taskID string) (Result, error) {
+	result := c.db.Where("task_id = ?", taskID).Delete(&model.QueuedTask{})
+	if result.Error != nil {
+		return nil, cerrors.ErrMetaOpFail.Wrap(result.Error)
+	}
+
+	return &ormResult{rowsAffected: result.RowsAffected}, nil
+}
+
+// GetQueuedTaskByID query the queued task of the taskID
+func (c *metaOpsClient) GetQueuedTaskByID(ctx context.Context, taskID string) (*model.QueuedTask, error) {
+	var task model.QueuedTask
+	if result := c.db.Where("task_id = ?", taskID).First(&task); result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, cerrors.ErrMetaEntryNotFound.Wrap(result.Error)
+		}
+
+		return nil, cerrors.ErrMetaOpFail.Wrap(result.Error)
+	}
+
+	return &task, nil
+}
+
+// QueryQueuedTasks query all queued tasks, in the order they are queued
+func (c *metaOpsClient) QueryQueuedTasks(ctx context.Context) ([]*model.QueuedTask, error) {
+	var tasks []*model.QueuedTask
+	if result := c.db.Order("seq_id").Find(&tasks); result.Error != nil {
+		return nil, cerrors.ErrMetaOpFail.Wrap(result.Error)
+	}
+
+	return tasks, nil
+}
+
 // Result defines a query result interface
 type Result interface {
 	RowsAffected() int64
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"errors"
+	"path/filepath"
 	"reflect"
 	"regexp"
 	"testing"
@@ -587,6 +588,35 @@ func TestJob(t *testing.T) {
 					errors.New("QueryJobsByStatus error"))
 			},
 		},
+		{
+			fn: "QueryJobsByStatusCode",
+			inputs: []interface{}{
+				libModel.MasterStatusFinished,
+			},
+			err: cerrors.ErrMetaOpFail.GenWithStackByArgs(),
+			mockExpectResFn: func(mock sqlmock.Sqlmock) {
+				expectedSQL := "SELECT * FROM `master_meta_kv_data` WHERE status = ? AND `master_meta_kv_data`.`deleted` IS NULL ORDER BY updated_at"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedSQL)).WithArgs(libModel.MasterStatusFinished).WillReturnError(
+					errors.New("QueryJobsByStatusCode error"))
+			},
+		},
+		{
+			fn: "QueryJobsByFilter",
+			inputs: []interface{}{
+				&JobFilter{
+					ProjectID:   "p111",
+					StatusCodes: []libModel.MasterStatusCode{libModel.MasterStatusInit, libModel.MasterStatusFinished},
+					Types:       []libModel.WorkerType{1},
+				},
+			},
+			err: cerrors.ErrMetaOpFail.GenWithStackByArgs(),
+			mockExpectResFn: func(mock sqlmock.Sqlmock) {
+				expectedSQL := "SELECT * FROM `master_meta_kv_data` WHERE project_id = ? AND status IN (?,?) AND type IN (?) AND `master_meta_kv_data`.`deleted` IS NULL"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedSQL)).
+					WithArgs("p111", libModel.MasterStatusInit, libModel.MasterStatusFinished, 1).
+					WillReturnError(errors.New("QueryJobsByFilter error"))
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -1211,3 +1241,28 @@ func testInner(t *testing.T, m sqlmock.Sqlmock, cli Client, c tCase) {
 		}
 	}
 }
+
+func TestNewSQLiteClient(t *testing.T) {
+	t.Parallel()
+
+	store := metaclient.StoreConfigParams{
+		StoreType: metaclient.StoreTypeSQLite,
+		Endpoints: []string{filepath.Join(t.TempDir(), "meta.db")},
+	}
+	cli1, err := NewClient(store, NewDefaultDBConfig())
+	require.Nil(t, err)
+	defer cli1.Close()
+	cli2, err := NewClient(store, NewDefaultDBConfig())
+	require.Nil(t, err)
+	defer cli2.Close()
+
+	ctx := context.Background()
+	require.Nil(t, cli1.Initialize(ctx))
+	err = cli1.CreateProject(ctx, &model.ProjectInfo{ID: "p1", Name: "project1"})
+	require.Nil(t, err)
+
+	// clients on the same file share the data
+	project, err := cli2.GetProjectByID(ctx, "p1")
+	require.Nil(t, err)
+	require.Equal(t, "project1", project.Name)
+}
@@ -0,0 +1,107 @@
+package orm
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"gorm.io/gorm"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/pkg/orm/model"
+)
+
+const (
+	// maxEpochBatchSize is the max number of epochs allocated by one
+	// transaction.
+	maxEpochBatchSize = 256
+	// epochBatchTimeout is the timeout of allocating a batch of epochs, which
+	// is not bound to the context of any caller since the batch is shared.
+	epochBatchTimeout = 10 * time.Second
+)
+
+type epochResult struct {
+	epoch libModel.Epoch
+	err   error
+}
+
+// epochBatcher allocates the epochs requested concurrently by one transaction,
+// which increases the epoch counter by the number of the requests, so that
+// the masters recovering at the same time, e.g. after the failover of an
+// executor, don't contend on the counter.
+//
+// The epochs are not leased in advance. A range leased by one process would
+// hand out epochs smaller than the ones returned meanwhile by other
+// processes. Instead, a range of epochs is only handed out to the requests
+// that are waiting when the range is allocated, so an epoch is always larger
+// than the ones returned before it is requested, by this process or others,
+// which a master relies on to fence its old incarnations.
+type epochBatcher struct {
+	genEpochs func(ctx context.Context, n int64) (int64, error)
+
+	mu      sync.Mutex
+	waiters []chan epochResult
+	running bool
+}
+
+func newEpochBatcher(db *gorm.DB) *epochBatcher {
+	return &epochBatcher{
+		genEpochs: func(ctx context.Context, n int64) (int64, error) {
+			return model.GenEpochs(ctx, db.WithContext(ctx), n)
+		},
+	}
+}
+
+// GenEpoch returns a new epoch, which is allocated along with the epochs
+// requested at the same time.
+func (b *epochBatcher) GenEpoch(ctx context.Context) (libModel.Epoch, error) {
+	ch := make(chan epochResult, 1)
+	b.mu.Lock()
+	b.waiters = append(b.waiters, ch)
+	if !b.running {
+		b.running = true
+		go b.run()
+	}
+	b.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		// the epoch allocated for the request is skipped, which is harmless
+		// since epochs only need to increase.
+		return 0, errors.Trace(ctx.Err())
+	case result := <-ch:
+		return result.epoch, result.err
+	}
+}
+
+// run allocates the epochs for the waiting requests batch by batch, until no
+// request is waiting.
+func (b *epochBatcher) run() {
+	for {
+		b.mu.Lock()
+		if len(b.waiters) == 0 {
+			b.running = false
+			b.mu.Unlock()
+			return
+		}
+		n := len(b.waiters)
+		if n > maxEpochBatchSize {
+			n = maxEpochBatchSize
+		}
+		waiters := b.waiters[:n:n]
+		b.waiters = b.waiters[n:]
+		b.mu.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), epochBatchTimeout)
+		last, err := b.genEpochs(ctx, int64(n))
+		cancel()
+		for i, ch := range waiters {
+			if err != nil {
+				ch <- epochResult{err: err}
+				continue
+			}
+			ch <- epochResult{epoch: last - int64(n-1-i)}
+		}
+	}
+}
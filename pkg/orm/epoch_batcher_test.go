@@ -0,0 +1,170 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+)
+
+// epochCounter simulates the epoch counter in metastore shared by several
+// processes.
+type epochCounter struct {
+	mu    sync.Mutex
+	epoch int64
+	calls int
+	err   error
+}
+
+func (c *epochCounter) genEpochs(ctx context.Context, n int64) (int64, error) {
+	// makes the requests arriving meanwhile batched
+	time.Sleep(5 * time.Millisecond)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+	if c.err != nil {
+		return 0, c.err
+	}
+	c.epoch += n
+	return c.epoch, nil
+}
+
+func TestEpochBatcherConcurrentFailovers(t *testing.T) {
+	t.Parallel()
+
+	const (
+		processes = 3
+		masters   = 100
+	)
+	counter := &epochCounter{}
+	batchers := make([]*epochBatcher, processes)
+	for i := range batchers {
+		batchers[i] = &epochBatcher{genEpochs: counter.genEpochs}
+	}
+
+	type result struct {
+		epoch          libModel.Epoch
+		returnedBefore libModel.Epoch
+		err            error
+	}
+	var (
+		wg sync.WaitGroup
+		// maxReturned is the max epoch returned so far
+		maxReturned atomic.Int64
+		results     = make(chan result, processes*masters)
+	)
+	for i := 0; i < processes*masters; i++ {
+		wg.Add(1)
+		go func(b *epochBatcher) {
+			defer wg.Done()
+			returnedBefore := maxReturned.Load()
+			epoch, err := b.GenEpoch(context.Background())
+			for err == nil {
+				max := maxReturned.Load()
+				if epoch <= max || maxReturned.CAS(max, epoch) {
+					break
+				}
+			}
+			results <- result{epoch: epoch, returnedBefore: returnedBefore, err: err}
+		}(batchers[i%processes])
+	}
+	wg.Wait()
+	close(results)
+
+	allocated := make(map[libModel.Epoch]struct{})
+	for r := range results {
+		require.NoError(t, r.err)
+		// an epoch is larger than the ones returned before it is
+		// requested, so a recovered master fences its old incarnations
+		require.Greater(t, r.epoch, r.returnedBefore)
+		require.NotContains(t, allocated, r.epoch)
+		allocated[r.epoch] = struct{}{}
+	}
+	require.Len(t, allocated, processes*masters)
+	counter.mu.Lock()
+	defer counter.mu.Unlock()
+	require.Equal(t, int64(processes*masters), counter.epoch)
+	require.Less(t, counter.calls, processes*masters)
+}
+
+func TestEpochBatcherError(t *testing.T) {
+	t.Parallel()
+
+	counter := &epochCounter{err: errors.New("fake error")}
+	b := &epochBatcher{genEpochs: counter.genEpochs}
+	_, err := b.GenEpoch(context.Background())
+	require.Error(t, err)
+
+	counter.mu.Lock()
+	counter.err = nil
+	counter.mu.Unlock()
+	epoch, err := b.GenEpoch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(1), epoch)
+
+	// a canceled request skips its epoch
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = b.GenEpoch(ctx)
+	require.Error(t, err)
+	require.Eventually(t, func() bool {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		return !b.running
+	}, time.Second, 10*time.Millisecond)
+	epoch, err = b.GenEpoch(context.Background())
+	require.NoError(t, err)
+	require.Greater(t, epoch, int64(1))
+}
+
+func TestGenEpochBatched(t *testing.T) {
+	t.Parallel()
+
+	cli, err := NewMockClient()
+	require.NoError(t, err)
+	defer cli.Close()
+
+	ctx := context.Background()
+	start, err := cli.GetEpoch(ctx)
+	require.NoError(t, err)
+
+	const masters = 50
+	epochs := make(chan libModel.Epoch, masters)
+	errCh := make(chan error, masters)
+	var wg sync.WaitGroup
+	for i := 0; i < masters; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			epoch, err := cli.GenEpoch(ctx)
+			if err != nil {
+				errCh <- err
+				return
+			}
+			epochs <- epoch
+		}()
+	}
+	wg.Wait()
+	close(epochs)
+	close(errCh)
+
+	for err := range errCh {
+		require.NoError(t, err)
+	}
+	allocated := make(map[libModel.Epoch]struct{})
+	for epoch := range epochs {
+		require.Greater(t, epoch, start)
+		require.LessOrEqual(t, epoch, start+masters)
+		allocated[epoch] = struct{}{}
+	}
+	require.Len(t, allocated, masters)
+	end, err := cli.GetEpoch(ctx)
+	require.NoError(t, err)
+	require.Equal(t, start+masters, end)
+}
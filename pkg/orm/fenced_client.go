@@ -0,0 +1,275 @@
+package orm
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	cerrors "github.com/hanfei1991/microcosm/pkg/errors"
+	resourcemeta "github.com/hanfei1991/microcosm/pkg/externalresource/resourcemeta/model"
+	"github.com/hanfei1991/microcosm/pkg/orm/model"
+)
+
+// fencedClient runs every write in a transaction together with a check of
+// the leader fence, reads are delegated to metaOpsClient directly.
+//
+// The writes left to metaOpsClient are not fenced on purpose:
+//   - GenEpoch only allocates an epoch, a stale leader can't make the epochs
+//     go back or collide by generating one, and a master must be able to
+//     generate its epoch before it knows its fencing token.
+//   - Initialize creates the tables and the leader fence itself, so there is
+//     no fence to check before it, and it never overwrites existing rows.
+//   - FenceAll advances the leader fence itself.
+//
+// Any other write of Client must be overridden here, which is checked by
+// TestFencedClientOverridesWrites.
+type fencedClient struct {
+	*metaOpsClient
+	token int64
+}
+
+// fenced runs fn in a transaction after advancing the leader fence to
+// c.token, the transaction is aborted if the fence has a larger token.
+func (c *fencedClient) fenced(ctx context.Context, fn func(cli *metaOpsClient) error) error {
+	// errors returned by fn are not wrapped, they have been wrapped
+	// by metaOpsClient already
+	return c.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		fenceToken, err := model.AdvanceLeaderFence(ctx, tx, c.token)
+		if err != nil {
+			return cerrors.ErrMetaOpFail.Wrap(err)
+		}
+		if fenceToken > c.token {
+			return cerrors.ErrMetaLeaderFenced.GenWithStackByArgs(c.token, fenceToken)
+		}
+		return fn(&metaOpsClient{db: tx})
+	})
+}
+
+func (c *fencedClient) fencedWithResult(ctx context.Context, fn func(cli *metaOpsClient) (Result, error)) (Result, error) {
+	var result Result
+	err := c.fenced(ctx, func(cli *metaOpsClient) error {
+		var err error
+		result, err = fn(cli)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *fencedClient) WithFencingToken(token int64) Client {
+	return c.metaOpsClient.WithFencingToken(token)
+}
+
+func (c *fencedClient) WithObserver(observer StatementObserver) Client {
+	return &fencedClient{
+		metaOpsClient: c.metaOpsClient.WithObserver(observer).(*metaOpsClient),
+		token:         c.token,
+	}
+}
+
+func (c *fencedClient) AdvanceEpoch(ctx context.Context, epoch libModel.Epoch) error {
+	return c.fenced(ctx, func(cli *metaOpsClient) error {
+		return cli.AdvanceEpoch(ctx, epoch)
+	})
+}
+
+func (c *fencedClient) CreateProject(ctx context.Context, project *model.ProjectInfo) error {
+	return c.fenced(ctx, func(cli *metaOpsClient) error {
+		return cli.CreateProject(ctx, project)
+	})
+}
+
+func (c *fencedClient) DeleteProject(ctx context.Context, projectID string) error {
+	return c.fenced(ctx, func(cli *metaOpsClient) error {
+		return cli.DeleteProject(ctx, projectID)
+	})
+}
+
+func (c *fencedClient) CreateProjectOperation(ctx context.Context, op *model.ProjectOperation) error {
+	return c.fenced(ctx, func(cli *metaOpsClient) error {
+		return cli.CreateProjectOperation(ctx, op)
+	})
+}
+
+func (c *fencedClient) DeleteProjectOperationsByJobID(ctx context.Context, jobID string) (Result, error) {
+	return c.fencedWithResult(ctx, func(cli *metaOpsClient) (Result, error) {
+		return cli.DeleteProjectOperationsByJobID(ctx, jobID)
+	})
+}
+
+func (c *fencedClient) UpsertJob(ctx context.Context, job *libModel.MasterMetaKVData) error {
+	return c.fenced(ctx, func(cli *metaOpsClient) error {
+		return cli.UpsertJob(ctx, job)
+	})
+}
+
+func (c *fencedClient) UpdateJob(ctx context.Context, job *libModel.MasterMetaKVData) error {
+	return c.fenced(ctx, func(cli *metaOpsClient) error {
+		return cli.UpdateJob(ctx, job)
+	})
+}
+
+func (c *fencedClient) CompareAndUpdateJob(ctx context.Context, job *libModel.MasterMetaKVData, revision int64) error {
+	return c.fenced(ctx, func(cli *metaOpsClient) error {
+		return cli.CompareAndUpdateJob(ctx, job, revision)
+	})
+}
+
+func (c *fencedClient) DeleteJob(ctx context.Context, jobID string) (Result, error) {
+	return c.fencedWithResult(ctx, func(cli *metaOpsClient) (Result, error) {
+		return cli.DeleteJob(ctx, jobID)
+	})
+}
+
+func (c *fencedClient) UpsertWorker(ctx context.Context, worker *libModel.WorkerStatus) error {
+	return c.fenced(ctx, func(cli *metaOpsClient) error {
+		return cli.UpsertWorker(ctx, worker)
+	})
+}
+
+func (c *fencedClient) UpdateWorker(ctx context.Context, worker *libModel.WorkerStatus) error {
+	return c.fenced(ctx, func(cli *metaOpsClient) error {
+		return cli.UpdateWorker(ctx, worker)
+	})
+}
+
+func (c *fencedClient) UpdateWorkers(ctx context.Context, workers []*libModel.WorkerStatus) error {
+	return c.fenced(ctx, func(cli *metaOpsClient) error {
+		return cli.updateWorkers(ctx, workers)
+	})
+}
+
+func (c *fencedClient) DeleteWorker(ctx context.Context, masterID string, workerID string) (Result, error) {
+	return c.fencedWithResult(ctx, func(cli *metaOpsClient) (Result, error) {
+		return cli.DeleteWorker(ctx, masterID, workerID)
+	})
+}
+
+func (c *fencedClient) DeleteWorkersByMasterID(ctx context.Context, masterID string) (Result, error) {
+	return c.fencedWithResult(ctx, func(cli *metaOpsClient) (Result, error) {
+		return cli.DeleteWorkersByMasterID(ctx, masterID)
+	})
+}
+
+func (c *fencedClient) CreateResource(ctx context.Context, resource *resourcemeta.ResourceMeta) error {
+	return c.fenced(ctx, func(cli *metaOpsClient) error {
+		return cli.CreateResource(ctx, resource)
+	})
+}
+
+func (c *fencedClient) UpsertResource(ctx context.Context, resource *resourcemeta.ResourceMeta) error {
+	return c.fenced(ctx, func(cli *metaOpsClient) error {
+		return cli.UpsertResource(ctx, resource)
+	})
+}
+
+func (c *fencedClient) UpdateResource(ctx context.Context, resource *resourcemeta.ResourceMeta) error {
+	return c.fenced(ctx, func(cli *metaOpsClient) error {
+		return cli.UpdateResource(ctx, resource)
+	})
+}
+
+func (c *fencedClient) DeleteResource(ctx context.Context, resourceID string) (Result, error) {
+	return c.fencedWithResult(ctx, func(cli *metaOpsClient) (Result, error) {
+		return cli.DeleteResource(ctx, resourceID)
+	})
+}
+
+func (c *fencedClient) DeleteResourcesByJobID(ctx context.Context, jobID string) (Result, error) {
+	return c.fencedWithResult(ctx, func(cli *metaOpsClient) (Result, error) {
+		return cli.DeleteResourcesByJobID(ctx, jobID)
+	})
+}
+
+func (c *fencedClient) CreateResourceLease(ctx context.Context, lease *resourcemeta.ResourceLease) error {
+	return c.fenced(ctx, func(cli *metaOpsClient) error {
+		return cli.CreateResourceLease(ctx, lease)
+	})
+}
+
+func (c *fencedClient) UpsertResourceLease(ctx context.Context, lease *resourcemeta.ResourceLease) error {
+	return c.fenced(ctx, func(cli *metaOpsClient) error {
+		return cli.UpsertResourceLease(ctx, lease)
+	})
+}
+
+func (c *fencedClient) DeleteResourceLease(ctx context.Context, resourceID string) (Result, error) {
+	return c.fencedWithResult(ctx, func(cli *metaOpsClient) (Result, error) {
+		return cli.DeleteResourceLease(ctx, resourceID)
+	})
+}
+
+func (c *fencedClient) UpsertJobDeletion(ctx context.Context, deletion *model.JobDeletion) error {
+	return c.fenced(ctx, func(cli *metaOpsClient) error {
+		return cli.UpsertJobDeletion(ctx, deletion)
+	})
+}
+
+func (c *fencedClient) DeleteJobDeletion(ctx context.Context, jobID string) (Result, error) {
+	return c.fencedWithResult(ctx, func(cli *metaOpsClient) (Result, error) {
+		return cli.DeleteJobDeletion(ctx, jobID)
+	})
+}
+
+func (c *fencedClient) UpsertJobSchedule(ctx context.Context, schedule *model.JobSchedule) error {
+	return c.fenced(ctx, func(cli *metaOpsClient) error {
+		return cli.UpsertJobSchedule(ctx, schedule)
+	})
+}
+
+func (c *fencedClient) DeleteJobSchedule(ctx context.Context, scheduleID string) (Result, error) {
+	return c.fencedWithResult(ctx, func(cli *metaOpsClient) (Result, error) {
+		return cli.DeleteJobSchedule(ctx, scheduleID)
+	})
+}
+
+func (c *fencedClient) UpsertJobTemplate(ctx context.Context, template *model.JobTemplate) error {
+	return c.fenced(ctx, func(cli *metaOpsClient) error {
+		return cli.UpsertJobTemplate(ctx, template)
+	})
+}
+
+func (c *fencedClient) DeleteJobTemplate(ctx context.Context, templateID string) (Result, error) {
+	return c.fencedWithResult(ctx, func(cli *metaOpsClient) (Result, error) {
+		return cli.DeleteJobTemplate(ctx, templateID)
+	})
+}
+
+func (c *fencedClient) UpsertSecret(ctx context.Context, secret *model.Secret) error {
+	return c.fenced(ctx, func(cli *metaOpsClient) error {
+		return cli.UpsertSecret(ctx, secret)
+	})
+}
+
+func (c *fencedClient) DeleteSecret(ctx context.Context, projectID, name string) (Result, error) {
+	return c.fencedWithResult(ctx, func(cli *metaOpsClient) (Result, error) {
+		return cli.DeleteSecret(ctx, projectID, name)
+	})
+}
+
+func (c *fencedClient) UpsertExecutorCordon(ctx context.Context, cordon *model.ExecutorCordon) error {
+	return c.fenced(ctx, func(cli *metaOpsClient) error {
+		return cli.UpsertExecutorCordon(ctx, cordon)
+	})
+}
+
+func (c *fencedClient) DeleteExecutorCordon(ctx context.Context, executorID string) (Result, error) {
+	return c.fencedWithResult(ctx, func(cli *metaOpsClient) (Result, error) {
+		return cli.DeleteExecutorCordon(ctx, executorID)
+	})
+}
+
+func (c *fencedClient) UpsertQueuedTask(ctx context.Context, task *model.QueuedTask) error {
+	return c.fenced(ctx, func(cli *metaOpsClient) error {
+		return cli.UpsertQueuedTask(ctx, task)
+	})
+}
+
+func (c *fencedClient) DeleteQueuedTask(ctx context.Context, taskID string) (Result, error) {
+	return c.fencedWithResult(ctx, func(cli *metaOpsClient) (Result, error) {
+		return cli.DeleteQueuedTask(ctx, taskID)
+	})
+}
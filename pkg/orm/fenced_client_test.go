@@ -0,0 +1,87 @@
+package orm
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	cerrors "github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+func TestFencedClientMock(t *testing.T) {
+	t.Parallel()
+
+	cli, err := NewMockClient()
+	require.NoError(t, err)
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	oldLeader := cli.WithFencingToken(10)
+	newLeader := cli.WithFencingToken(20)
+
+	err = oldLeader.UpsertJob(ctx, &libModel.MasterMetaKVData{ID: "job-1", Epoch: 1})
+	require.NoError(t, err)
+	err = newLeader.UpsertJob(ctx, &libModel.MasterMetaKVData{ID: "job-1", Epoch: 2})
+	require.NoError(t, err)
+
+	// writes of the old leader are rejected after the new leader has written
+	err = oldLeader.UpdateJob(ctx, &libModel.MasterMetaKVData{ID: "job-1", Epoch: 3})
+	require.True(t, cerrors.ErrMetaLeaderFenced.Equal(err))
+	_, err = oldLeader.DeleteJob(ctx, "job-1")
+	require.True(t, cerrors.ErrMetaLeaderFenced.Equal(err))
+	err = oldLeader.AdvanceEpoch(ctx, 100)
+	require.True(t, cerrors.ErrMetaLeaderFenced.Equal(err))
+
+	// reads are not fenced
+	job, err := oldLeader.GetJobByID(ctx, "job-1")
+	require.NoError(t, err)
+	require.Equal(t, libModel.Epoch(2), job.Epoch)
+
+	res, err := newLeader.DeleteJob(ctx, "job-1")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), res.RowsAffected())
+
+	// no leader writes once the metastore is fenced for all
+	require.NoError(t, cli.FenceAll(ctx))
+	err = newLeader.UpsertJob(ctx, &libModel.MasterMetaKVData{ID: "job-1", Epoch: 3})
+	require.True(t, cerrors.ErrMetaLeaderFenced.Equal(err))
+}
+
+func TestFencedClientOverridesWrites(t *testing.T) {
+	t.Parallel()
+
+	// the writes that are not fenced on purpose, see fencedClient
+	unfenced := map[string]struct{}{
+		"GenEpoch":   {},
+		"Initialize": {},
+		"FenceAll":   {},
+	}
+	isRead := func(name string) bool {
+		return strings.HasPrefix(name, "Get") || strings.HasPrefix(name, "Query") ||
+			name == "SnapshotRead" || name == "Close"
+	}
+
+	clientType := reflect.TypeOf((*Client)(nil)).Elem()
+	fencedType := reflect.TypeOf(&fencedClient{})
+	for i := 0; i < clientType.NumMethod(); i++ {
+		name := clientType.Method(i).Name
+		if _, ok := unfenced[name]; ok || isRead(name) {
+			continue
+		}
+		method, ok := fencedType.MethodByName(name)
+		require.True(t, ok, name)
+		// a method promoted from the embedded metaOpsClient is a generated
+		// wrapper, which has no source file
+		file, _ := runtime.FuncForPC(method.Func.Pointer()).FileLine(method.Func.Pointer())
+		require.True(t, strings.HasSuffix(file, "fenced_client.go"),
+			"write %s is not fenced", name)
+	}
+}
@@ -0,0 +1,479 @@
+package migration
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	resModel "github.com/hanfei1991/microcosm/pkg/externalresource/resourcemeta/model"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+	"github.com/hanfei1991/microcosm/pkg/orm/model"
+)
+
+// client serves the reads with the current metastore of the migrator, and
+// copies the writes to the target metastore while migrating.
+type client struct {
+	m        *Migrator
+	fenced   bool
+	token    int64
+	observer pkgOrm.StatementObserver
+}
+
+// currentLocked returns the current metastore, the caller should hold m.mu.
+func (c *client) currentLocked() pkgOrm.Client {
+	cli := c.m.primary
+	if c.observer != nil {
+		cli = cli.WithObserver(c.observer)
+	}
+	if c.fenced {
+		return cli.WithFencingToken(c.token)
+	}
+	return cli
+}
+
+func (c *client) reader() pkgOrm.Client {
+	c.m.mu.RLock()
+	defer c.m.mu.RUnlock()
+	return c.currentLocked()
+}
+
+// write writes to the current metastore, and then copies the records
+// written to the target metastore. It waits if the writes are fenced.
+func (c *client) write(ctx context.Context, fn func(cli pkgOrm.Client) error, sync syncFunc) error {
+	c.m.mu.RLock()
+	for c.m.fenced != nil {
+		fenced := c.m.fenced
+		c.m.mu.RUnlock()
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case <-fenced:
+		}
+		c.m.mu.RLock()
+	}
+	defer c.m.mu.RUnlock()
+
+	if err := fn(c.currentLocked()); err != nil {
+		return err
+	}
+	c.m.copyLocked(ctx, sync)
+	return nil
+}
+
+func (c *client) writeWithResult(
+	ctx context.Context, fn func(cli pkgOrm.Client) (pkgOrm.Result, error), sync syncFunc,
+) (pkgOrm.Result, error) {
+	var result pkgOrm.Result
+	err := c.write(ctx, func(cli pkgOrm.Client) error {
+		var err error
+		result, err = fn(cli)
+		return err
+	}, sync)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Close implements metaclient.Client.Close
+func (c *client) Close() error {
+	return c.m.Close()
+}
+
+// GenEpoch implements metaclient.Client.GenEpoch
+func (c *client) GenEpoch(ctx context.Context) (int64, error) {
+	var epoch int64
+	err := c.write(ctx, func(cli pkgOrm.Client) error {
+		var err error
+		epoch, err = cli.GenEpoch(ctx)
+		return err
+	}, syncEpoch)
+	return epoch, err
+}
+
+// Initialize implements pkgOrm.Client.Initialize
+func (c *client) Initialize(ctx context.Context) error {
+	c.m.mu.RLock()
+	defer c.m.mu.RUnlock()
+
+	if err := c.m.primary.Initialize(ctx); err != nil {
+		return err
+	}
+	if c.m.secondary != nil {
+		if err := c.m.secondary.Initialize(ctx); err != nil {
+			log.L().Warn("failed to initialize the target metastore", zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// WithFencingToken implements pkgOrm.FencingClient.WithFencingToken
+func (c *client) WithFencingToken(token int64) pkgOrm.Client {
+	return &client{m: c.m, fenced: true, token: token, observer: c.observer}
+}
+
+// FenceAll implements pkgOrm.FencingClient.FenceAll
+func (c *client) FenceAll(ctx context.Context) error {
+	return c.reader().FenceAll(ctx)
+}
+
+// WithObserver implements pkgOrm.ObserverClient.WithObserver
+func (c *client) WithObserver(observer pkgOrm.StatementObserver) pkgOrm.Client {
+	return &client{m: c.m, fenced: c.fenced, token: c.token, observer: observer}
+}
+
+// SnapshotRead implements pkgOrm.SnapshotClient.SnapshotRead
+func (c *client) SnapshotRead(ctx context.Context, fn func(snapshot pkgOrm.Client) error) error {
+	return c.reader().SnapshotRead(ctx, fn)
+}
+
+// GetEpoch implements pkgOrm.LogicEpochClient.GetEpoch
+func (c *client) GetEpoch(ctx context.Context) (libModel.Epoch, error) {
+	return c.reader().GetEpoch(ctx)
+}
+
+// AdvanceEpoch implements pkgOrm.LogicEpochClient.AdvanceEpoch
+func (c *client) AdvanceEpoch(ctx context.Context, epoch libModel.Epoch) error {
+	return c.write(ctx, func(cli pkgOrm.Client) error {
+		return cli.AdvanceEpoch(ctx, epoch)
+	}, syncEpoch)
+}
+
+func (c *client) CreateProject(ctx context.Context, project *model.ProjectInfo) error {
+	return c.write(ctx, func(cli pkgOrm.Client) error {
+		return cli.CreateProject(ctx, project)
+	}, syncProject(project.ID))
+}
+
+func (c *client) DeleteProject(ctx context.Context, projectID string) error {
+	return c.write(ctx, func(cli pkgOrm.Client) error {
+		return cli.DeleteProject(ctx, projectID)
+	}, syncProject(projectID))
+}
+
+func (c *client) QueryProjects(ctx context.Context) ([]*model.ProjectInfo, error) {
+	return c.reader().QueryProjects(ctx)
+}
+
+func (c *client) GetProjectByID(ctx context.Context, projectID string) (*model.ProjectInfo, error) {
+	return c.reader().GetProjectByID(ctx, projectID)
+}
+
+func (c *client) CreateProjectOperation(ctx context.Context, op *model.ProjectOperation) error {
+	return c.write(ctx, func(cli pkgOrm.Client) error {
+		return cli.CreateProjectOperation(ctx, op)
+	}, syncProjectOperations(op.ProjectID, op.JobID))
+}
+
+func (c *client) QueryProjectOperations(ctx context.Context, projectID string) ([]*model.ProjectOperation, error) {
+	return c.reader().QueryProjectOperations(ctx, projectID)
+}
+
+func (c *client) QueryProjectOperationsByTimeRange(
+	ctx context.Context, projectID string, tr pkgOrm.TimeRange,
+) ([]*model.ProjectOperation, error) {
+	return c.reader().QueryProjectOperationsByTimeRange(ctx, projectID, tr)
+}
+
+func (c *client) DeleteProjectOperationsByJobID(ctx context.Context, jobID string) (pkgOrm.Result, error) {
+	return c.writeWithResult(ctx, func(cli pkgOrm.Client) (pkgOrm.Result, error) {
+		return cli.DeleteProjectOperationsByJobID(ctx, jobID)
+	}, syncProjectOperations("", jobID))
+}
+
+func (c *client) UpsertJob(ctx context.Context, job *libModel.MasterMetaKVData) error {
+	return c.write(ctx, func(cli pkgOrm.Client) error {
+		return cli.UpsertJob(ctx, job)
+	}, syncJob(job.ID))
+}
+
+func (c *client) UpdateJob(ctx context.Context, job *libModel.MasterMetaKVData) error {
+	return c.write(ctx, func(cli pkgOrm.Client) error {
+		return cli.UpdateJob(ctx, job)
+	}, syncJob(job.ID))
+}
+
+func (c *client) CompareAndUpdateJob(ctx context.Context, job *libModel.MasterMetaKVData, revision int64) error {
+	return c.write(ctx, func(cli pkgOrm.Client) error {
+		return cli.CompareAndUpdateJob(ctx, job, revision)
+	}, syncJob(job.ID))
+}
+
+func (c *client) DeleteJob(ctx context.Context, jobID string) (pkgOrm.Result, error) {
+	return c.writeWithResult(ctx, func(cli pkgOrm.Client) (pkgOrm.Result, error) {
+		return cli.DeleteJob(ctx, jobID)
+	}, syncJob(jobID))
+}
+
+func (c *client) GetJobByID(ctx context.Context, jobID string) (*libModel.MasterMetaKVData, error) {
+	return c.reader().GetJobByID(ctx, jobID)
+}
+
+func (c *client) QueryJobs(ctx context.Context) ([]*libModel.MasterMetaKVData, error) {
+	return c.reader().QueryJobs(ctx)
+}
+
+func (c *client) QueryJobsByProjectID(ctx context.Context, projectID string) ([]*libModel.MasterMetaKVData, error) {
+	return c.reader().QueryJobsByProjectID(ctx, projectID)
+}
+
+func (c *client) QueryJobsByStatus(ctx context.Context, jobID string, status int) ([]*libModel.MasterMetaKVData, error) {
+	return c.reader().QueryJobsByStatus(ctx, jobID, status)
+}
+
+func (c *client) QueryJobsByStatusCode(
+	ctx context.Context, status libModel.MasterStatusCode,
+) ([]*libModel.MasterMetaKVData, error) {
+	return c.reader().QueryJobsByStatusCode(ctx, status)
+}
+
+func (c *client) QueryJobsByFilter(
+	ctx context.Context, filter *pkgOrm.JobFilter,
+) ([]*libModel.MasterMetaKVData, error) {
+	return c.reader().QueryJobsByFilter(ctx, filter)
+}
+
+func (c *client) UpsertWorker(ctx context.Context, worker *libModel.WorkerStatus) error {
+	return c.write(ctx, func(cli pkgOrm.Client) error {
+		return cli.UpsertWorker(ctx, worker)
+	}, syncWorker(worker.JobID, worker.ID))
+}
+
+func (c *client) UpdateWorker(ctx context.Context, worker *libModel.WorkerStatus) error {
+	return c.write(ctx, func(cli pkgOrm.Client) error {
+		return cli.UpdateWorker(ctx, worker)
+	}, syncWorker(worker.JobID, worker.ID))
+}
+
+func (c *client) UpdateWorkers(ctx context.Context, workers []*libModel.WorkerStatus) error {
+	return c.write(ctx, func(cli pkgOrm.Client) error {
+		return cli.UpdateWorkers(ctx, workers)
+	}, syncWorkers(workers))
+}
+
+func (c *client) DeleteWorker(ctx context.Context, masterID string, workerID string) (pkgOrm.Result, error) {
+	return c.writeWithResult(ctx, func(cli pkgOrm.Client) (pkgOrm.Result, error) {
+		return cli.DeleteWorker(ctx, masterID, workerID)
+	}, syncWorker(masterID, workerID))
+}
+
+func (c *client) DeleteWorkersByMasterID(ctx context.Context, masterID string) (pkgOrm.Result, error) {
+	return c.writeWithResult(ctx, func(cli pkgOrm.Client) (pkgOrm.Result, error) {
+		return cli.DeleteWorkersByMasterID(ctx, masterID)
+	}, syncWorkersOfMaster(masterID))
+}
+
+func (c *client) GetWorkerByID(ctx context.Context, masterID string, workerID string) (*libModel.WorkerStatus, error) {
+	return c.reader().GetWorkerByID(ctx, masterID, workerID)
+}
+
+func (c *client) QueryWorkersByMasterID(ctx context.Context, masterID string) ([]*libModel.WorkerStatus, error) {
+	return c.reader().QueryWorkersByMasterID(ctx, masterID)
+}
+
+func (c *client) QueryWorkersByStatus(ctx context.Context, masterID string, status int) ([]*libModel.WorkerStatus, error) {
+	return c.reader().QueryWorkersByStatus(ctx, masterID, status)
+}
+
+func (c *client) QueryWorkerIndexByMasterID(ctx context.Context, masterID string) ([]*libModel.WorkerStatus, error) {
+	return c.reader().QueryWorkerIndexByMasterID(ctx, masterID)
+}
+
+func (c *client) QueryWorkersByIDs(ctx context.Context, masterID string, workerIDs []string) ([]*libModel.WorkerStatus, error) {
+	return c.reader().QueryWorkersByIDs(ctx, masterID, workerIDs)
+}
+
+func (c *client) QueryWorkers(ctx context.Context) ([]*libModel.WorkerStatus, error) {
+	return c.reader().QueryWorkers(ctx)
+}
+
+func (c *client) CreateResource(ctx context.Context, resource *resModel.ResourceMeta) error {
+	return c.write(ctx, func(cli pkgOrm.Client) error {
+		return cli.CreateResource(ctx, resource)
+	}, syncResource(resource.ID))
+}
+
+func (c *client) UpsertResource(ctx context.Context, resource *resModel.ResourceMeta) error {
+	return c.write(ctx, func(cli pkgOrm.Client) error {
+		return cli.UpsertResource(ctx, resource)
+	}, syncResource(resource.ID))
+}
+
+func (c *client) UpdateResource(ctx context.Context, resource *resModel.ResourceMeta) error {
+	return c.write(ctx, func(cli pkgOrm.Client) error {
+		return cli.UpdateResource(ctx, resource)
+	}, syncResource(resource.ID))
+}
+
+func (c *client) DeleteResource(ctx context.Context, resourceID string) (pkgOrm.Result, error) {
+	return c.writeWithResult(ctx, func(cli pkgOrm.Client) (pkgOrm.Result, error) {
+		return cli.DeleteResource(ctx, resourceID)
+	}, syncResource(resourceID))
+}
+
+func (c *client) DeleteResourcesByJobID(ctx context.Context, jobID string) (pkgOrm.Result, error) {
+	return c.writeWithResult(ctx, func(cli pkgOrm.Client) (pkgOrm.Result, error) {
+		return cli.DeleteResourcesByJobID(ctx, jobID)
+	}, syncResourcesOfJob(jobID))
+}
+
+func (c *client) GetResourceByID(ctx context.Context, resourceID string) (*resModel.ResourceMeta, error) {
+	return c.reader().GetResourceByID(ctx, resourceID)
+}
+
+func (c *client) QueryResources(ctx context.Context) ([]*resModel.ResourceMeta, error) {
+	return c.reader().QueryResources(ctx)
+}
+
+func (c *client) QueryResourcesByJobID(ctx context.Context, jobID string) ([]*resModel.ResourceMeta, error) {
+	return c.reader().QueryResourcesByJobID(ctx, jobID)
+}
+
+func (c *client) QueryResourcesByExecutorID(ctx context.Context, executorID string) ([]*resModel.ResourceMeta, error) {
+	return c.reader().QueryResourcesByExecutorID(ctx, executorID)
+}
+
+func (c *client) CreateResourceLease(ctx context.Context, lease *resModel.ResourceLease) error {
+	return c.write(ctx, func(cli pkgOrm.Client) error {
+		return cli.CreateResourceLease(ctx, lease)
+	}, syncResourceLease(lease.ID))
+}
+
+func (c *client) UpsertResourceLease(ctx context.Context, lease *resModel.ResourceLease) error {
+	return c.write(ctx, func(cli pkgOrm.Client) error {
+		return cli.UpsertResourceLease(ctx, lease)
+	}, syncResourceLease(lease.ID))
+}
+
+func (c *client) DeleteResourceLease(ctx context.Context, resourceID string) (pkgOrm.Result, error) {
+	return c.writeWithResult(ctx, func(cli pkgOrm.Client) (pkgOrm.Result, error) {
+		return cli.DeleteResourceLease(ctx, resourceID)
+	}, syncResourceLease(resourceID))
+}
+
+func (c *client) GetResourceLeaseByID(ctx context.Context, resourceID string) (*resModel.ResourceLease, error) {
+	return c.reader().GetResourceLeaseByID(ctx, resourceID)
+}
+
+func (c *client) QueryResourceLeases(ctx context.Context) ([]*resModel.ResourceLease, error) {
+	return c.reader().QueryResourceLeases(ctx)
+}
+
+func (c *client) UpsertJobDeletion(ctx context.Context, deletion *model.JobDeletion) error {
+	return c.write(ctx, func(cli pkgOrm.Client) error {
+		return cli.UpsertJobDeletion(ctx, deletion)
+	}, syncJobDeletion(deletion.JobID))
+}
+
+func (c *client) DeleteJobDeletion(ctx context.Context, jobID string) (pkgOrm.Result, error) {
+	return c.writeWithResult(ctx, func(cli pkgOrm.Client) (pkgOrm.Result, error) {
+		return cli.DeleteJobDeletion(ctx, jobID)
+	}, syncJobDeletion(jobID))
+}
+
+func (c *client) QueryJobDeletions(ctx context.Context) ([]*model.JobDeletion, error) {
+	return c.reader().QueryJobDeletions(ctx)
+}
+
+func (c *client) UpsertJobSchedule(ctx context.Context, schedule *model.JobSchedule) error {
+	return c.write(ctx, func(cli pkgOrm.Client) error {
+		return cli.UpsertJobSchedule(ctx, schedule)
+	}, syncJobSchedule(schedule.ScheduleID))
+}
+
+func (c *client) DeleteJobSchedule(ctx context.Context, scheduleID string) (pkgOrm.Result, error) {
+	return c.writeWithResult(ctx, func(cli pkgOrm.Client) (pkgOrm.Result, error) {
+		return cli.DeleteJobSchedule(ctx, scheduleID)
+	}, syncJobSchedule(scheduleID))
+}
+
+func (c *client) GetJobScheduleByID(ctx context.Context, scheduleID string) (*model.JobSchedule, error) {
+	return c.reader().GetJobScheduleByID(ctx, scheduleID)
+}
+
+func (c *client) QueryJobSchedules(ctx context.Context) ([]*model.JobSchedule, error) {
+	return c.reader().QueryJobSchedules(ctx)
+}
+
+func (c *client) UpsertJobTemplate(ctx context.Context, template *model.JobTemplate) error {
+	return c.write(ctx, func(cli pkgOrm.Client) error {
+		return cli.UpsertJobTemplate(ctx, template)
+	}, syncJobTemplate(template.TemplateID))
+}
+
+func (c *client) DeleteJobTemplate(ctx context.Context, templateID string) (pkgOrm.Result, error) {
+	return c.writeWithResult(ctx, func(cli pkgOrm.Client) (pkgOrm.Result, error) {
+		return cli.DeleteJobTemplate(ctx, templateID)
+	}, syncJobTemplate(templateID))
+}
+
+func (c *client) GetJobTemplateByID(ctx context.Context, templateID string) (*model.JobTemplate, error) {
+	return c.reader().GetJobTemplateByID(ctx, templateID)
+}
+
+func (c *client) QueryJobTemplates(ctx context.Context) ([]*model.JobTemplate, error) {
+	return c.reader().QueryJobTemplates(ctx)
+}
+
+func (c *client) UpsertSecret(ctx context.Context, secret *model.Secret) error {
+	return c.write(ctx, func(cli pkgOrm.Client) error {
+		return cli.UpsertSecret(ctx, secret)
+	}, syncSecret(secret.ProjectID, secret.Name))
+}
+
+func (c *client) DeleteSecret(ctx context.Context, projectID, name string) (pkgOrm.Result, error) {
+	return c.writeWithResult(ctx, func(cli pkgOrm.Client) (pkgOrm.Result, error) {
+		return cli.DeleteSecret(ctx, projectID, name)
+	}, syncSecret(projectID, name))
+}
+
+func (c *client) GetSecret(ctx context.Context, projectID, name string) (*model.Secret, error) {
+	return c.reader().GetSecret(ctx, projectID, name)
+}
+
+func (c *client) QuerySecrets(ctx context.Context) ([]*model.Secret, error) {
+	return c.reader().QuerySecrets(ctx)
+}
+
+func (c *client) UpsertExecutorCordon(ctx context.Context, cordon *model.ExecutorCordon) error {
+	return c.write(ctx, func(cli pkgOrm.Client) error {
+		return cli.UpsertExecutorCordon(ctx, cordon)
+	}, syncExecutorCordon(cordon.ExecutorID))
+}
+
+func (c *client) DeleteExecutorCordon(ctx context.Context, executorID string) (pkgOrm.Result, error) {
+	return c.writeWithResult(ctx, func(cli pkgOrm.Client) (pkgOrm.Result, error) {
+		return cli.DeleteExecutorCordon(ctx, executorID)
+	}, syncExecutorCordon(executorID))
+}
+
+func (c *client) GetExecutorCordonByID(ctx context.Context, executorID string) (*model.ExecutorCordon, error) {
+	return c.reader().GetExecutorCordonByID(ctx, executorID)
+}
+
+func (c *client) QueryExecutorCordons(ctx context.Context) ([]*model.ExecutorCordon, error) {
+	return c.reader().QueryExecutorCordons(ctx)
+}
+
+func (c *client) UpsertQueuedTask(ctx context.Context, task *model.QueuedTask) error {
+	return c.write(ctx, func(cli pkgOrm.Client) error {
+		return cli.UpsertQueuedTask(ctx, task)
+	}, syncQueuedTask(task.TaskID))
+}
+
+func (c *client) DeleteQueuedTask(ctx context.Context, taskID string) (pkgOrm.Result, error) {
+	return c.writeWithResult(ctx, func(cli pkgOrm.Client) (pkgOrm.Result, error) {
+		return cli.DeleteQueuedTask(ctx, taskID)
+	}, syncQueuedTask(taskID))
+}
+
+func (c *client) GetQueuedTaskByID(ctx context.Context, taskID string) (*model.QueuedTask, error) {
+	return c.reader().GetQueuedTaskByID(ctx, taskID)
+}
+
+func (c *client) QueryQueuedTasks(ctx context.Context) ([]*model.QueuedTask, error) {
+	return c.reader().QueryQueuedTasks(ctx)
+}
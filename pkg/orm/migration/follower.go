@@ -0,0 +1,120 @@
+package migration
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+)
+
+// followerRetryInterval is the interval of watching the State again after
+// the watch fails or a State fails to be applied.
+const followerRetryInterval = time.Second
+
+// Follower makes the framework metastore client of an executor, or of a
+// worker process, follow the migration driven by the leader. The writes
+// through its Migrator are fenced while the leader switches the metastores,
+// and are served by the target metastore after the switch.
+type Follower struct {
+	migrator *Migrator
+	// onApply applies a State to the worker processes, it may be nil.
+	onApply func(ctx context.Context, state State) error
+
+	mu   sync.Mutex
+	conf metaclient.StoreConfigParams
+}
+
+// NewFollower creates a Follower of migrator, which serves with the
+// metastore of conf.
+func NewFollower(
+	migrator *Migrator,
+	conf metaclient.StoreConfigParams,
+	onApply func(ctx context.Context, state State) error,
+) *Follower {
+	return &Follower{
+		migrator: migrator,
+		onApply:  onApply,
+		conf:     conf,
+	}
+}
+
+// Conf returns the config of the metastore followed.
+func (f *Follower) Conf() metaclient.StoreConfigParams {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.conf
+}
+
+// Apply fences the writes if the leader is switching, connects to the
+// target metastore once it is switched, and resumes the writes otherwise.
+func (f *Follower) Apply(ctx context.Context, state State) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch state.Phase {
+	case PhaseSwitching:
+		f.migrator.Fence()
+	case PhaseSwitched:
+		if state.Target != nil && !reflect.DeepEqual(*state.Target, f.conf) {
+			cli, err := pkgOrm.NewClient(*state.Target, pkgOrm.NewDefaultDBConfig())
+			if err != nil {
+				return err
+			}
+			if err := f.migrator.Reconnect(cli); err != nil {
+				_ = cli.Close()
+				return err
+			}
+			f.conf = *state.Target
+		}
+		f.migrator.Unfence()
+	default:
+		f.migrator.Unfence()
+	}
+
+	if f.onApply != nil {
+		return f.onApply(ctx, state)
+	}
+	return nil
+}
+
+// Run applies the States watched from store, and acknowledges them as
+// executorID, until ctx is done.
+func (f *Follower) Run(ctx context.Context, store StateStore, executorID string) error {
+	for {
+		f.follow(ctx, store, executorID)
+
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case <-time.After(followerRetryInterval):
+		}
+	}
+}
+
+// follow returns when the watch fails or a State fails to be applied, the
+// State is applied again when it is watched again.
+func (f *Follower) follow(ctx context.Context, store StateStore, executorID string) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for state := range store.Watch(ctx) {
+		if err := f.Apply(ctx, state); err != nil {
+			log.L().Warn("failed to apply metastore migration state",
+				zap.String("phase", string(state.Phase)), zap.Error(err))
+			return
+		}
+		ack := Ack{Phase: state.Phase, Generation: state.Generation}
+		if err := store.Ack(ctx, executorID, ack); err != nil {
+			log.L().Warn("failed to acknowledge metastore migration state",
+				zap.String("phase", string(state.Phase)), zap.Error(err))
+			return
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package migration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+)
+
+func TestFollower(t *testing.T) {
+	t.Parallel()
+
+	sourceConf, source := pkgOrm.NewSQLiteTestClient(t, "source.db")
+	targetConf, target := pkgOrm.NewSQLiteTestClient(t, "target.db")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	executorCli, err := pkgOrm.NewClient(sourceConf, pkgOrm.NewDefaultDBConfig())
+	require.NoError(t, err)
+	m := NewMigrator(executorCli)
+	defer m.Close()
+	cli := m.Client()
+
+	applied := make(chan Phase, 10)
+	f := NewFollower(m, sourceConf, func(ctx context.Context, state State) error {
+		applied <- state.Phase
+		return nil
+	})
+	store := NewMockStateStore()
+	waitAck := func(phase Phase, generation int64) {
+		require.Eventually(t, func() bool {
+			acks, err := store.Acks(ctx)
+			require.NoError(t, err)
+			return acks["executor-1"] == Ack{Phase: phase, Generation: generation}
+		}, 5*time.Second, 10*time.Millisecond)
+		require.Equal(t, phase, <-applied)
+	}
+	go func() {
+		_ = f.Run(ctx, store, "executor-1")
+	}()
+	waitAck(PhaseNone, 0)
+
+	state := State{Phase: PhaseDualWrite, Generation: 1, Source: &sourceConf, Target: &targetConf}
+	require.NoError(t, store.Save(ctx, state))
+	waitAck(PhaseDualWrite, 1)
+	require.NoError(t, cli.UpsertJob(ctx, &libModel.MasterMetaKVData{ProjectID: "project-1", ID: "job-1"}))
+
+	// the writes are fenced while the leader is switching
+	state.Phase, state.Generation = PhaseSwitching, 2
+	require.NoError(t, store.Save(ctx, state))
+	waitAck(PhaseSwitching, 2)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- cli.UpsertJob(ctx, &libModel.MasterMetaKVData{ProjectID: "project-1", ID: "job-2"})
+	}()
+	select {
+	case err := <-errCh:
+		require.FailNow(t, "write is not fenced", "error: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// and are served by the target metastore after the switch
+	state.Phase, state.Generation = PhaseSwitched, 3
+	require.NoError(t, store.Save(ctx, state))
+	waitAck(PhaseSwitched, 3)
+	require.NoError(t, <-errCh)
+	require.Equal(t, targetConf, f.Conf())
+	_, err = target.GetJobByID(ctx, "job-2")
+	require.NoError(t, err)
+	_, err = source.GetJobByID(ctx, "job-2")
+	require.True(t, pkgOrm.IsNotFoundError(err))
+}
@@ -0,0 +1,334 @@
+// Package migration migrates the framework metastore to another backend
+// online. While migrating, the writes of the server master are copied to
+// the target metastore and the existing records are copied in the
+// background. To switch, the server master fences the writes of the
+// executors, switches to the target metastore after a final verification,
+// and then the executors reconnect to it, which only pauses the writes for
+// a short while. The State of the migration is persisted, so that the next
+// leader resumes it.
+package migration
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+
+	derrors "github.com/hanfei1991/microcosm/pkg/errors"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+)
+
+// Phase is the phase of a metastore migration.
+type Phase string
+
+// Defines all phases of a metastore migration
+const (
+	// PhaseNone means no migration is running.
+	PhaseNone = Phase("none")
+	// PhaseDualWrite means the writes are copied to the target metastore,
+	// and the reads are still served by the source metastore.
+	PhaseDualWrite = Phase("dual-write")
+	// PhaseSwitching means the writes of the executors are fenced, and the
+	// server master is verifying the metastores to switch.
+	PhaseSwitching = Phase("switching")
+	// PhaseSwitched means the reads and writes are served by the target
+	// metastore.
+	PhaseSwitched = Phase("switched")
+)
+
+// maxVerifyRounds limits the rounds of verification when switching, the
+// metastores are expected to be consistent after the first round repairs
+// them, unless the records are modified by the executors meanwhile.
+const maxVerifyRounds = 3
+
+// Status is the progress of a metastore migration.
+type Status struct {
+	Phase Phase
+	// FailedWrites is the number of writes failed to be copied to the
+	// target metastore since the last verification.
+	FailedWrites int64
+	Verifying    bool
+	// LastVerifyTime is the time the last verification finished.
+	LastVerifyTime  time.Time
+	LastVerifyError error
+	// Mismatches are the numbers of mismatched records of each kind found
+	// by the last verification.
+	Mismatches map[string]int
+}
+
+// Migrator switches the framework metastore used by the clients it creates.
+// The records written by the executors are not copied on writing, they are
+// copied by the verifications. The executors switch their own Migrators by
+// following the migration, see Follower.
+type Migrator struct {
+	// mu is held for reading by the writes through the clients, so that no
+	// write is in flight while the metastores are switched.
+	mu        sync.RWMutex
+	phase     Phase
+	primary   pkgOrm.Client
+	secondary pkgOrm.Client
+	// fenced is closed when the writes fenced by Fence are resumed, it is
+	// nil if the writes are not fenced.
+	fenced chan struct{}
+	// retired are the metastores switched from, they are closed with the
+	// migrator since the reads in flight may still use them.
+	retired []pkgOrm.Client
+
+	verifyMu     sync.Mutex
+	verifying    atomic.Bool
+	failedWrites atomic.Int64
+
+	statusMu        sync.Mutex
+	lastVerifyTime  time.Time
+	lastVerifyError error
+	mismatches      map[string]int
+}
+
+// NewMigrator creates a Migrator serving with the given metastore.
+func NewMigrator(source pkgOrm.Client) *Migrator {
+	return &Migrator{
+		phase:   PhaseNone,
+		primary: source,
+	}
+}
+
+// Client returns a client that always uses the current metastore.
+func (m *Migrator) Client() pkgOrm.Client {
+	return &client{m: m}
+}
+
+// Start starts copying the writes to the target metastore, the migrator
+// takes the ownership of target. Verify should be called to copy the
+// existing records.
+func (m *Migrator) Start(ctx context.Context, target pkgOrm.Client) error {
+	if err := target.Initialize(ctx); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.phase == PhaseDualWrite {
+		return derrors.ErrMetaMigrationPhase.GenWithStackByArgs(m.phase, "start")
+	}
+	m.phase = PhaseDualWrite
+	m.secondary = target
+	m.failedWrites.Store(0)
+
+	m.statusMu.Lock()
+	m.lastVerifyTime = time.Time{}
+	m.lastVerifyError = nil
+	m.mismatches = nil
+	m.statusMu.Unlock()
+
+	log.L().Info("metastore migration started")
+	return nil
+}
+
+// Verify compares the records of the metastores, and copies the mismatched
+// ones from the source metastore to the target one. The records modified
+// during the verification may still mismatch, they are repaired by the next
+// verification.
+func (m *Migrator) Verify(ctx context.Context) (map[string]int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.phase != PhaseDualWrite {
+		return nil, derrors.ErrMetaMigrationPhase.GenWithStackByArgs(m.phase, "verify")
+	}
+	return m.verifyLocked(ctx)
+}
+
+// Switch blocks the writes, verifies the metastores until they are
+// consistent, and then serves the reads and writes with the target
+// metastore. It returns ErrMetaMigrationDiverged if the metastores are
+// still inconsistent after maxVerifyRounds. commit is called before
+// switching, which persists the switch, the metastores are not switched if
+// it fails.
+func (m *Migrator) Switch(ctx context.Context, commit func() error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.phase != PhaseDualWrite {
+		return derrors.ErrMetaMigrationPhase.GenWithStackByArgs(m.phase, "switch")
+	}
+	for round := 1; ; round++ {
+		mismatches, err := m.verifyLocked(ctx)
+		if err != nil {
+			return err
+		}
+		if len(mismatches) == 0 {
+			break
+		}
+		if round == maxVerifyRounds {
+			total := 0
+			for _, n := range mismatches {
+				total += n
+			}
+			return derrors.ErrMetaMigrationDiverged.GenWithStackByArgs(total)
+		}
+	}
+	if err := commit(); err != nil {
+		return err
+	}
+
+	m.retired = append(m.retired, m.primary)
+	m.primary, m.secondary = m.secondary, nil
+	m.phase = PhaseSwitched
+	log.L().Info("switched to the target metastore")
+	return nil
+}
+
+// Reconnect serves the reads and writes with cli, which is switched to by
+// the leader, and resumes the writes fenced. The migrator takes the
+// ownership of cli.
+func (m *Migrator) Reconnect(cli pkgOrm.Client) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.phase == PhaseDualWrite {
+		return derrors.ErrMetaMigrationPhase.GenWithStackByArgs(m.phase, "reconnect")
+	}
+	m.retired = append(m.retired, m.primary)
+	m.primary = cli
+	m.phase = PhaseSwitched
+	m.unfenceLocked()
+	log.L().Info("reconnected to the target metastore")
+	return nil
+}
+
+// FenceRetired rejects the writes with fencing tokens to the metastores
+// switched from, so that a stale leader can't write to them.
+func (m *Migrator) FenceRetired(ctx context.Context) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, cli := range m.retired {
+		if err := cli.FenceAll(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Fence blocks the writes through the clients until Unfence or Reconnect is
+// called, the writes in flight are finished when it returns.
+func (m *Migrator) Fence() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.fenced == nil {
+		m.fenced = make(chan struct{})
+		log.L().Info("writes to the framework metastore are fenced")
+	}
+}
+
+// Unfence resumes the writes blocked by Fence.
+func (m *Migrator) Unfence() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.unfenceLocked()
+}
+
+func (m *Migrator) unfenceLocked() {
+	if m.fenced != nil {
+		close(m.fenced)
+		m.fenced = nil
+		log.L().Info("writes to the framework metastore are resumed")
+	}
+}
+
+// Abort stops copying the writes to the target metastore, and closes it.
+func (m *Migrator) Abort() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.phase != PhaseDualWrite {
+		return derrors.ErrMetaMigrationPhase.GenWithStackByArgs(m.phase, "abort")
+	}
+	if err := m.secondary.Close(); err != nil {
+		log.L().Warn("failed to close the target metastore", zap.Error(err))
+	}
+	m.secondary = nil
+	m.phase = PhaseNone
+	log.L().Info("metastore migration aborted")
+	return nil
+}
+
+// Status returns the progress of the migration.
+func (m *Migrator) Status() Status {
+	m.mu.RLock()
+	phase := m.phase
+	m.mu.RUnlock()
+
+	m.statusMu.Lock()
+	defer m.statusMu.Unlock()
+	return Status{
+		Phase:           phase,
+		FailedWrites:    m.failedWrites.Load(),
+		Verifying:       m.verifying.Load(),
+		LastVerifyTime:  m.lastVerifyTime,
+		LastVerifyError: m.lastVerifyError,
+		Mismatches:      m.mismatches,
+	}
+}
+
+// Close closes all the metastores.
+func (m *Migrator) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.unfenceLocked()
+	var firstErr error
+	for _, cli := range append(m.retired, m.primary, m.secondary) {
+		if cli == nil {
+			continue
+		}
+		if err := cli.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (m *Migrator) verifyLocked(ctx context.Context) (map[string]int, error) {
+	m.verifyMu.Lock()
+	defer m.verifyMu.Unlock()
+
+	m.verifying.Store(true)
+	defer m.verifying.Store(false)
+
+	// the writes failed to be copied before are repaired by this round
+	failedWrites := m.failedWrites.Load()
+	mismatches, err := repair(ctx, m.primary, m.secondary)
+
+	m.statusMu.Lock()
+	defer m.statusMu.Unlock()
+	m.lastVerifyTime = time.Now()
+	m.lastVerifyError = err
+	if err != nil {
+		log.L().Warn("failed to verify metastores", zap.Error(err))
+		return nil, err
+	}
+	m.failedWrites.Sub(failedWrites)
+	m.mismatches = mismatches
+	log.L().Info("metastores verified", zap.Any("mismatches", mismatches))
+	return mismatches, nil
+}
+
+// copyLocked copies a write to the target metastore. The write has
+// succeeded in the source metastore, so a failure is only recorded and
+// repaired by the next verification.
+func (m *Migrator) copyLocked(ctx context.Context, sync syncFunc) {
+	if m.secondary == nil {
+		return
+	}
+	if err := sync(ctx, m.primary, m.secondary); err != nil {
+		m.failedWrites.Inc()
+		log.L().Warn("failed to copy write to the target metastore", zap.Error(err))
+	}
+}
@@ -0,0 +1,172 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	derrors "github.com/hanfei1991/microcosm/pkg/errors"
+	resModel "github.com/hanfei1991/microcosm/pkg/externalresource/resourcemeta/model"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+	"github.com/hanfei1991/microcosm/pkg/orm/model"
+)
+
+var _ pkgOrm.Client = &client{}
+
+func TestMigrator(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	source, err := pkgOrm.NewMockClient()
+	require.NoError(t, err)
+	target, err := pkgOrm.NewMockClient()
+	require.NoError(t, err)
+	m := NewMigrator(source)
+	defer m.Close()
+	cli := m.Client()
+
+	// the records written before the migration
+	require.NoError(t, cli.CreateProject(ctx, &model.ProjectInfo{ID: "project-1", Name: "project"}))
+	require.NoError(t, cli.UpsertJob(ctx, &libModel.MasterMetaKVData{ProjectID: "project-1", ID: "job-1"}))
+	require.NoError(t, cli.CreateProjectOperation(ctx, &model.ProjectOperation{
+		ProjectID: "project-1", Operation: "Submit", JobID: "job-1",
+	}))
+	_, err = m.Verify(ctx)
+	require.True(t, derrors.ErrMetaMigrationPhase.Equal(err))
+	require.True(t, derrors.ErrMetaMigrationPhase.Equal(m.Switch(ctx, noCommit)))
+
+	require.NoError(t, m.Start(ctx, target))
+	require.Equal(t, PhaseDualWrite, m.Status().Phase)
+	require.True(t, derrors.ErrMetaMigrationPhase.Equal(m.Start(ctx, target)))
+
+	// the writes are copied to the target metastore
+	fenced := cli.WithFencingToken(1)
+	require.NoError(t, fenced.UpsertWorker(ctx, &libModel.WorkerStatus{
+		ProjectID: "project-1", JobID: "job-1", ID: "worker-1", Code: libModel.WorkerStatusNormal,
+	}))
+	require.NoError(t, fenced.UpsertJobTemplate(ctx, &model.JobTemplate{TemplateID: "template-1"}))
+	epoch, err := fenced.GenEpoch(ctx)
+	require.NoError(t, err)
+	_, err = target.GetWorkerByID(ctx, "job-1", "worker-1")
+	require.NoError(t, err)
+	_, err = target.GetJobTemplateByID(ctx, "template-1")
+	require.NoError(t, err)
+	targetEpoch, err := target.GetEpoch(ctx)
+	require.NoError(t, err)
+	require.Equal(t, epoch, targetEpoch)
+	_, err = fenced.DeleteJobTemplate(ctx, "template-1")
+	require.NoError(t, err)
+	_, err = target.GetJobTemplateByID(ctx, "template-1")
+	require.True(t, pkgOrm.IsNotFoundError(err))
+
+	// the existing records and the writes not through the migrator are
+	// copied by the verification
+	require.NoError(t, source.UpsertResource(ctx, &resModel.ResourceMeta{
+		ProjectID: "project-1", ID: "/local/resource-1", Job: "job-1", Worker: "worker-1", Executor: "executor-1",
+	}))
+	require.NoError(t, target.UpsertJobTemplate(ctx, &model.JobTemplate{TemplateID: "template-2"}))
+	mismatches, err := m.Verify(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{
+		"project":           1,
+		"project-operation": 1,
+		"job":               1,
+		"resource":          1,
+		"job-template":      1,
+	}, mismatches)
+	mismatches, err = m.Verify(ctx)
+	require.NoError(t, err)
+	require.Empty(t, mismatches)
+	_, err = target.GetJobTemplateByID(ctx, "template-2")
+	require.True(t, pkgOrm.IsNotFoundError(err))
+
+	// the reads and writes are served by the target metastore after switching
+	require.NoError(t, source.UpsertJobTemplate(ctx, &model.JobTemplate{TemplateID: "template-3"}))
+	// the metastores are not switched if the switch fails to be persisted
+	require.Error(t, m.Switch(ctx, func() error { return errors.New("fake error") }))
+	require.Equal(t, PhaseDualWrite, m.Status().Phase)
+	require.NoError(t, m.Switch(ctx, noCommit))
+	require.Equal(t, PhaseSwitched, m.Status().Phase)
+	require.NoError(t, fenced.UpsertJob(ctx, &libModel.MasterMetaKVData{ProjectID: "project-1", ID: "job-2"}))
+	_, err = target.GetJobByID(ctx, "job-2")
+	require.NoError(t, err)
+	_, err = source.GetJobByID(ctx, "job-2")
+	require.True(t, pkgOrm.IsNotFoundError(err))
+	templates, err := cli.QueryJobTemplates(ctx)
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+	require.Equal(t, "template-3", templates[0].TemplateID)
+	require.True(t, derrors.ErrMetaMigrationPhase.Equal(m.Abort()))
+
+	// stale leaders can't write to the source metastore
+	require.NoError(t, m.FenceRetired(ctx))
+	err = source.WithFencingToken(1).UpsertJob(ctx, &libModel.MasterMetaKVData{ProjectID: "project-1", ID: "job-3"})
+	require.True(t, derrors.ErrMetaLeaderFenced.Equal(err))
+}
+
+func noCommit() error {
+	return nil
+}
+
+func TestMigratorAbort(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	source, err := pkgOrm.NewMockClient()
+	require.NoError(t, err)
+	target, err := pkgOrm.NewMockClient()
+	require.NoError(t, err)
+	m := NewMigrator(source)
+	defer m.Close()
+	cli := m.Client()
+
+	require.NoError(t, m.Start(ctx, target))
+	require.NoError(t, m.Abort())
+	require.Equal(t, PhaseNone, m.Status().Phase)
+	require.NoError(t, cli.UpsertJob(ctx, &libModel.MasterMetaKVData{ProjectID: "project-1", ID: "job-1"}))
+	_, err = source.GetJobByID(ctx, "job-1")
+	require.NoError(t, err)
+}
+
+func TestMigratorFence(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	source, err := pkgOrm.NewMockClient()
+	require.NoError(t, err)
+	target, err := pkgOrm.NewMockClient()
+	require.NoError(t, err)
+	m := NewMigrator(source)
+	defer m.Close()
+	cli := m.Client()
+
+	m.Fence()
+	// the reads are not fenced
+	_, err = cli.QueryJobs(ctx)
+	require.NoError(t, err)
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	err = cli.UpsertJob(timeoutCtx, &libModel.MasterMetaKVData{ProjectID: "project-1", ID: "job-1"})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// the fenced writes are served by the metastore reconnected to
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- cli.UpsertJob(ctx, &libModel.MasterMetaKVData{ProjectID: "project-1", ID: "job-2"})
+	}()
+	select {
+	case err := <-errCh:
+		require.FailNow(t, "write is not fenced", "error: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	require.NoError(t, m.Reconnect(target))
+	require.NoError(t, <-errCh)
+	_, err = target.GetJobByID(ctx, "job-2")
+	require.NoError(t, err)
+	_, err = source.GetJobByID(ctx, "job-2")
+	require.True(t, pkgOrm.IsNotFoundError(err))
+}
@@ -0,0 +1,184 @@
+package migration
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+
+	"github.com/hanfei1991/microcosm/pkg/adapter"
+	derrors "github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
+)
+
+// State is the state of a migration persisted by the leader, so that the
+// next leader resumes the migration, and the executors follow it.
+type State struct {
+	Phase Phase `json:"phase"`
+	// Generation increases every time the phase changes, the executors
+	// acknowledge the states they have applied by it.
+	Generation int64                         `json:"generation"`
+	Source     *metaclient.StoreConfigParams `json:"source,omitempty"`
+	Target     *metaclient.StoreConfigParams `json:"target,omitempty"`
+}
+
+// Ack is the acknowledgement of an executor that has applied a State.
+type Ack struct {
+	Phase      Phase `json:"phase"`
+	Generation int64 `json:"generation"`
+}
+
+// StateStore persists the State of a migration, and the Acks of the
+// executors following it.
+type StateStore interface {
+	// Load returns the State, its phase is PhaseNone if no migration has
+	// been started.
+	Load(ctx context.Context) (State, error)
+	Save(ctx context.Context, state State) error
+	// Watch sends the State and then its changes, the channel is closed
+	// when ctx is done or the watch fails.
+	Watch(ctx context.Context) <-chan State
+
+	Ack(ctx context.Context, executorID string, ack Ack) error
+	Acks(ctx context.Context) (map[string]Ack, error)
+	// ClearAcks removes the Acks of all executors, including the ones that
+	// have gone.
+	ClearAcks(ctx context.Context) error
+}
+
+type etcdStateStore struct {
+	cli *clientv3.Client
+}
+
+// NewEtcdStateStore creates a StateStore in the etcd of the server masters.
+func NewEtcdStateStore(cli *clientv3.Client) StateStore {
+	return &etcdStateStore{cli: cli}
+}
+
+func (s *etcdStateStore) Load(ctx context.Context) (State, error) {
+	resp, err := s.cli.Get(ctx, adapter.MetaMigrationKey.Path())
+	if err != nil {
+		return State{}, derrors.Wrap(derrors.ErrEtcdAPIError, err)
+	}
+	if len(resp.Kvs) == 0 {
+		return State{Phase: PhaseNone}, nil
+	}
+	return decodeState(resp.Kvs[0].Value)
+}
+
+func (s *etcdStateStore) Save(ctx context.Context, state State) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := s.cli.Put(ctx, adapter.MetaMigrationKey.Path(), string(data)); err != nil {
+		return derrors.Wrap(derrors.ErrEtcdAPIError, err)
+	}
+	return nil
+}
+
+func (s *etcdStateStore) Watch(ctx context.Context) <-chan State {
+	ch := make(chan State, 1)
+	go func() {
+		defer close(ch)
+
+		key := adapter.MetaMigrationKey.Path()
+		resp, err := s.cli.Get(ctx, key)
+		if err != nil {
+			log.L().Warn("failed to load metastore migration state", zap.Error(err))
+			return
+		}
+		state := State{Phase: PhaseNone}
+		if len(resp.Kvs) > 0 {
+			if state, err = decodeState(resp.Kvs[0].Value); err != nil {
+				log.L().Warn("invalid metastore migration state", zap.Error(err))
+				return
+			}
+		}
+		if !sendState(ctx, ch, state) {
+			return
+		}
+
+		wch := s.cli.Watch(ctx, key, clientv3.WithRev(resp.Header.Revision+1))
+		for wresp := range wch {
+			if err := wresp.Err(); err != nil {
+				log.L().Warn("failed to watch metastore migration state", zap.Error(err))
+				return
+			}
+			for _, ev := range wresp.Events {
+				state := State{Phase: PhaseNone}
+				if ev.Type == mvccpb.PUT {
+					if state, err = decodeState(ev.Kv.Value); err != nil {
+						log.L().Warn("invalid metastore migration state", zap.Error(err))
+						return
+					}
+				}
+				if !sendState(ctx, ch, state) {
+					return
+				}
+			}
+		}
+	}()
+	return ch
+}
+
+func (s *etcdStateStore) Ack(ctx context.Context, executorID string, ack Ack) error {
+	data, err := json.Marshal(ack)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	key := adapter.MetaMigrationAckKeyAdapter.Encode(executorID)
+	if _, err := s.cli.Put(ctx, key, string(data)); err != nil {
+		return derrors.Wrap(derrors.ErrEtcdAPIError, err)
+	}
+	return nil
+}
+
+func (s *etcdStateStore) Acks(ctx context.Context) (map[string]Ack, error) {
+	resp, err := s.cli.Get(ctx, adapter.MetaMigrationAckKeyAdapter.Path(), clientv3.WithPrefix())
+	if err != nil {
+		return nil, derrors.Wrap(derrors.ErrEtcdAPIError, err)
+	}
+	acks := make(map[string]Ack, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		keys, err := adapter.MetaMigrationAckKeyAdapter.Decode(string(kv.Key))
+		if err != nil {
+			return nil, err
+		}
+		var ack Ack
+		if err := json.Unmarshal(kv.Value, &ack); err != nil {
+			return nil, errors.Trace(err)
+		}
+		acks[keys[0]] = ack
+	}
+	return acks, nil
+}
+
+func (s *etcdStateStore) ClearAcks(ctx context.Context) error {
+	_, err := s.cli.Delete(ctx, adapter.MetaMigrationAckKeyAdapter.Path(), clientv3.WithPrefix())
+	if err != nil {
+		return derrors.Wrap(derrors.ErrEtcdAPIError, err)
+	}
+	return nil
+}
+
+func decodeState(data []byte) (State, error) {
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return State{}, errors.Trace(err)
+	}
+	return state, nil
+}
+
+func sendState(ctx context.Context, ch chan<- State, state State) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case ch <- state:
+		return true
+	}
+}
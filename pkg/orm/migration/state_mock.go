@@ -0,0 +1,108 @@
+package migration
+
+import (
+	"context"
+	"sync"
+)
+
+// MockStateStore keeps the State and the Acks in memory, it is used in
+// tests.
+type MockStateStore struct {
+	mu       sync.Mutex
+	state    State
+	acks     map[string]Ack
+	watchers []chan struct{}
+}
+
+// NewMockStateStore creates a MockStateStore.
+func NewMockStateStore() *MockStateStore {
+	return &MockStateStore{
+		state: State{Phase: PhaseNone},
+		acks:  make(map[string]Ack),
+	}
+}
+
+// Load implements StateStore.Load
+func (s *MockStateStore) Load(ctx context.Context) (State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state, nil
+}
+
+// Save implements StateStore.Save
+func (s *MockStateStore) Save(ctx context.Context, state State) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state = state
+	for _, notify := range s.watchers {
+		select {
+		case notify <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// Watch implements StateStore.Watch, the States saved in a row may be sent
+// as the last one only.
+func (s *MockStateStore) Watch(ctx context.Context) <-chan State {
+	notify := make(chan struct{}, 1)
+	notify <- struct{}{}
+	s.mu.Lock()
+	s.watchers = append(s.watchers, notify)
+	s.mu.Unlock()
+
+	ch := make(chan State)
+	go func() {
+		defer close(ch)
+		defer func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			for i, watcher := range s.watchers {
+				if watcher == notify {
+					s.watchers = append(s.watchers[:i], s.watchers[i+1:]...)
+					break
+				}
+			}
+		}()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-notify:
+			}
+			state, _ := s.Load(ctx)
+			if !sendState(ctx, ch, state) {
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// Ack implements StateStore.Ack
+func (s *MockStateStore) Ack(ctx context.Context, executorID string, ack Ack) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.acks[executorID] = ack
+	return nil
+}
+
+// Acks implements StateStore.Acks
+func (s *MockStateStore) Acks(ctx context.Context) (map[string]Ack, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	acks := make(map[string]Ack, len(s.acks))
+	for id, ack := range s.acks {
+		acks[id] = ack
+	}
+	return acks, nil
+}
+
+// ClearAcks implements StateStore.ClearAcks
+func (s *MockStateStore) ClearAcks(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.acks = make(map[string]Ack)
+	return nil
+}
@@ -0,0 +1,302 @@
+package migration
+
+import (
+	"context"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	resModel "github.com/hanfei1991/microcosm/pkg/externalresource/resourcemeta/model"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+	"github.com/hanfei1991/microcosm/pkg/orm/model"
+)
+
+// syncFunc copies the records of a key from one metastore to another, the
+// records not in from are deleted from to. The sequence ids are not copied,
+// they are generated by each metastore.
+type syncFunc func(ctx context.Context, from, to pkgOrm.Client) error
+
+func syncAll(fns ...syncFunc) syncFunc {
+	return func(ctx context.Context, from, to pkgOrm.Client) error {
+		for _, fn := range fns {
+			if err := fn(ctx, from, to); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func syncEpoch(ctx context.Context, from, to pkgOrm.Client) error {
+	epoch, err := from.GetEpoch(ctx)
+	if err != nil {
+		return err
+	}
+	return to.AdvanceEpoch(ctx, epoch)
+}
+
+func syncProject(projectID string) syncFunc {
+	return func(ctx context.Context, from, to pkgOrm.Client) error {
+		project, err := from.GetProjectByID(ctx, projectID)
+		if pkgOrm.IsNotFoundError(err) {
+			return to.DeleteProject(ctx, projectID)
+		}
+		if err != nil {
+			return err
+		}
+		// projects are never updated
+		_, err = to.GetProjectByID(ctx, projectID)
+		if !pkgOrm.IsNotFoundError(err) {
+			return err
+		}
+		project.SeqID = 0
+		return to.CreateProject(ctx, project)
+	}
+}
+
+// syncProjectOperations copies the operations of a job, projectID is empty
+// if the operations have been deleted.
+func syncProjectOperations(projectID string, jobID string) syncFunc {
+	return func(ctx context.Context, from, to pkgOrm.Client) error {
+		var ops []*model.ProjectOperation
+		if projectID != "" {
+			projectOps, err := from.QueryProjectOperations(ctx, projectID)
+			if err != nil {
+				return err
+			}
+			for _, op := range projectOps {
+				if op.JobID == jobID {
+					ops = append(ops, op)
+				}
+			}
+		}
+		if _, err := to.DeleteProjectOperationsByJobID(ctx, jobID); err != nil {
+			return err
+		}
+		for _, op := range ops {
+			op.SeqID = 0
+			if err := to.CreateProjectOperation(ctx, op); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func syncJob(jobID string) syncFunc {
+	return func(ctx context.Context, from, to pkgOrm.Client) error {
+		job, err := from.GetJobByID(ctx, jobID)
+		if pkgOrm.IsNotFoundError(err) {
+			_, err = to.DeleteJob(ctx, jobID)
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		job.SeqID = 0
+		return to.UpsertJob(ctx, job)
+	}
+}
+
+func syncWorker(masterID, workerID string) syncFunc {
+	return func(ctx context.Context, from, to pkgOrm.Client) error {
+		worker, err := from.GetWorkerByID(ctx, masterID, workerID)
+		if pkgOrm.IsNotFoundError(err) {
+			_, err = to.DeleteWorker(ctx, masterID, workerID)
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		worker.SeqID = 0
+		return to.UpsertWorker(ctx, worker)
+	}
+}
+
+func syncWorkers(workers []*libModel.WorkerStatus) syncFunc {
+	fns := make([]syncFunc, 0, len(workers))
+	for _, worker := range workers {
+		fns = append(fns, syncWorker(worker.JobID, worker.ID))
+	}
+	return syncAll(fns...)
+}
+
+func syncWorkersOfMaster(masterID string) syncFunc {
+	return func(ctx context.Context, from, to pkgOrm.Client) error {
+		workers, err := from.QueryWorkersByMasterID(ctx, masterID)
+		if err != nil {
+			return err
+		}
+		existing, err := to.QueryWorkerIndexByMasterID(ctx, masterID)
+		if err != nil {
+			return err
+		}
+		kept := make(map[string]struct{}, len(workers))
+		for _, worker := range workers {
+			kept[worker.ID] = struct{}{}
+			worker.SeqID = 0
+			if err := to.UpsertWorker(ctx, worker); err != nil {
+				return err
+			}
+		}
+		for _, worker := range existing {
+			if _, ok := kept[worker.ID]; ok {
+				continue
+			}
+			if _, err := to.DeleteWorker(ctx, masterID, worker.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func syncResource(resourceID resModel.ResourceID) syncFunc {
+	return func(ctx context.Context, from, to pkgOrm.Client) error {
+		resource, err := from.GetResourceByID(ctx, resourceID)
+		if pkgOrm.IsNotFoundError(err) {
+			_, err = to.DeleteResource(ctx, resourceID)
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		resource.SeqID = 0
+		return to.UpsertResource(ctx, resource)
+	}
+}
+
+func syncResourcesOfJob(jobID resModel.JobID) syncFunc {
+	return func(ctx context.Context, from, to pkgOrm.Client) error {
+		resources, err := from.QueryResourcesByJobID(ctx, jobID)
+		if err != nil {
+			return err
+		}
+		existing, err := to.QueryResourcesByJobID(ctx, jobID)
+		if err != nil {
+			return err
+		}
+		kept := make(map[resModel.ResourceID]struct{}, len(resources))
+		for _, resource := range resources {
+			kept[resource.ID] = struct{}{}
+			resource.SeqID = 0
+			if err := to.UpsertResource(ctx, resource); err != nil {
+				return err
+			}
+		}
+		for _, resource := range existing {
+			if _, ok := kept[resource.ID]; ok {
+				continue
+			}
+			if _, err := to.DeleteResource(ctx, resource.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func syncResourceLease(resourceID resModel.ResourceID) syncFunc {
+	return func(ctx context.Context, from, to pkgOrm.Client) error {
+		lease, err := from.GetResourceLeaseByID(ctx, resourceID)
+		if pkgOrm.IsNotFoundError(err) {
+			_, err = to.DeleteResourceLease(ctx, resourceID)
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		lease.SeqID = 0
+		return to.UpsertResourceLease(ctx, lease)
+	}
+}
+
+func syncJobDeletion(jobID string) syncFunc {
+	return func(ctx context.Context, from, to pkgOrm.Client) error {
+		deletions, err := from.QueryJobDeletions(ctx)
+		if err != nil {
+			return err
+		}
+		for _, deletion := range deletions {
+			if deletion.JobID == jobID {
+				deletion.SeqID = 0
+				return to.UpsertJobDeletion(ctx, deletion)
+			}
+		}
+		_, err = to.DeleteJobDeletion(ctx, jobID)
+		return err
+	}
+}
+
+func syncJobSchedule(scheduleID string) syncFunc {
+	return func(ctx context.Context, from, to pkgOrm.Client) error {
+		schedule, err := from.GetJobScheduleByID(ctx, scheduleID)
+		if pkgOrm.IsNotFoundError(err) {
+			_, err = to.DeleteJobSchedule(ctx, scheduleID)
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		schedule.SeqID = 0
+		return to.UpsertJobSchedule(ctx, schedule)
+	}
+}
+
+func syncJobTemplate(templateID string) syncFunc {
+	return func(ctx context.Context, from, to pkgOrm.Client) error {
+		template, err := from.GetJobTemplateByID(ctx, templateID)
+		if pkgOrm.IsNotFoundError(err) {
+			_, err = to.DeleteJobTemplate(ctx, templateID)
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		template.SeqID = 0
+		return to.UpsertJobTemplate(ctx, template)
+	}
+}
+
+func syncSecret(projectID, name string) syncFunc {
+	return func(ctx context.Context, from, to pkgOrm.Client) error {
+		secret, err := from.GetSecret(ctx, projectID, name)
+		if pkgOrm.IsNotFoundError(err) {
+			_, err = to.DeleteSecret(ctx, projectID, name)
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		secret.SeqID = 0
+		return to.UpsertSecret(ctx, secret)
+	}
+}
+
+func syncExecutorCordon(executorID string) syncFunc {
+	return func(ctx context.Context, from, to pkgOrm.Client) error {
+		cordon, err := from.GetExecutorCordonByID(ctx, executorID)
+		if pkgOrm.IsNotFoundError(err) {
+			_, err = to.DeleteExecutorCordon(ctx, executorID)
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		cordon.SeqID = 0
+		return to.UpsertExecutorCordon(ctx, cordon)
+	}
+}
+
+func syncQueuedTask(taskID string) syncFunc {
+	return func(ctx context.Context, from, to pkgOrm.Client) error {
+		task, err := from.GetQueuedTaskByID(ctx, taskID)
+		if pkgOrm.IsNotFoundError(err) {
+			_, err = to.DeleteQueuedTask(ctx, taskID)
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		task.SeqID = 0
+		return to.UpsertQueuedTask(ctx, task)
+	}
+}
@@ -0,0 +1,193 @@
+package migration
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pingcap/errors"
+
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+	"github.com/hanfei1991/microcosm/pkg/orm/backup"
+)
+
+// record is a record of the metastore to compare, value is its encoding
+// without the fields generated by the metastore.
+type record struct {
+	kind  string
+	value string
+	sync  syncFunc
+}
+
+// generatedFields are the JSON keys of the fields generated by each
+// metastore, which are different in the metastores.
+var generatedFields = []string{"seq-id", "created-at", "updated-at", "revision"}
+
+// encode encodes a record without the generated fields. The timestamps are
+// truncated to milliseconds, which is the precision of the MySQL backend.
+func encode(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	fields := make(map[string]interface{})
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return "", errors.Trace(err)
+	}
+	for _, field := range generatedFields {
+		delete(fields, field)
+	}
+	for key, value := range fields {
+		str, ok := value.(string)
+		if !ok {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339Nano, str); err == nil {
+			fields[key] = t.UTC().Truncate(time.Millisecond).Format(time.RFC3339Nano)
+		}
+	}
+	data, err = json.Marshal(fields)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return string(data), nil
+}
+
+// records indexes the records of a snapshot by their kinds and keys.
+func records(snap *backup.Snapshot) (map[string]record, error) {
+	ret := make(map[string]record)
+	add := func(kind, key string, v interface{}, sync syncFunc) error {
+		value, err := encode(v)
+		if err != nil {
+			return err
+		}
+		ret[kind+"/"+key] = record{kind: kind, value: value, sync: sync}
+		return nil
+	}
+
+	for _, project := range snap.Projects {
+		if err := add("project", project.ID, project, syncProject(project.ID)); err != nil {
+			return nil, err
+		}
+	}
+	// the operations are compared by jobs, since their creation times are
+	// generated by the metastores
+	jobOps := make(map[string][]string)
+	jobProjects := make(map[string]string)
+	for _, op := range snap.ProjectOperations {
+		jobOps[op.JobID] = append(jobOps[op.JobID], op.Operation)
+		jobProjects[op.JobID] = op.ProjectID
+	}
+	for jobID, ops := range jobOps {
+		value := map[string][]string{"operations": ops}
+		if err := add("project-operation", jobID, value,
+			syncProjectOperations(jobProjects[jobID], jobID)); err != nil {
+			return nil, err
+		}
+	}
+	for _, job := range snap.Jobs {
+		if err := add("job", job.ID, job, syncJob(job.ID)); err != nil {
+			return nil, err
+		}
+	}
+	for _, worker := range snap.Workers {
+		if err := add("worker", worker.JobID+"/"+worker.ID, worker,
+			syncWorker(worker.JobID, worker.ID)); err != nil {
+			return nil, err
+		}
+	}
+	for _, resource := range snap.Resources {
+		if err := add("resource", resource.ID, resource, syncResource(resource.ID)); err != nil {
+			return nil, err
+		}
+	}
+	for _, lease := range snap.ResourceLeases {
+		if err := add("resource-lease", lease.ID, lease, syncResourceLease(lease.ID)); err != nil {
+			return nil, err
+		}
+	}
+	for _, deletion := range snap.JobDeletions {
+		if err := add("job-deletion", deletion.JobID, deletion, syncJobDeletion(deletion.JobID)); err != nil {
+			return nil, err
+		}
+	}
+	for _, schedule := range snap.JobSchedules {
+		if err := add("job-schedule", schedule.ScheduleID, schedule,
+			syncJobSchedule(schedule.ScheduleID)); err != nil {
+			return nil, err
+		}
+	}
+	for _, template := range snap.JobTemplates {
+		if err := add("job-template", template.TemplateID, template,
+			syncJobTemplate(template.TemplateID)); err != nil {
+			return nil, err
+		}
+	}
+	for _, secret := range snap.Secrets {
+		if err := add("secret", secret.ProjectID+"/"+secret.Name, secret,
+			syncSecret(secret.ProjectID, secret.Name)); err != nil {
+			return nil, err
+		}
+	}
+	for _, cordon := range snap.ExecutorCordons {
+		if err := add("executor-cordon", cordon.ExecutorID, cordon,
+			syncExecutorCordon(cordon.ExecutorID)); err != nil {
+			return nil, err
+		}
+	}
+	for _, task := range snap.QueuedTasks {
+		if err := add("queued-task", task.TaskID, task,
+			syncQueuedTask(task.TaskID)); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// repair compares the records of the metastores, and copies the mismatched
+// ones from one to another. It returns the numbers of mismatched records of
+// each kind.
+func repair(ctx context.Context, from, to pkgOrm.Client) (map[string]int, error) {
+	fromSnap, err := backup.Take(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	toSnap, err := backup.Take(ctx, to)
+	if err != nil {
+		return nil, err
+	}
+	fromRecords, err := records(fromSnap)
+	if err != nil {
+		return nil, err
+	}
+	toRecords, err := records(toSnap)
+	if err != nil {
+		return nil, err
+	}
+
+	mismatches := make(map[string]int)
+	for key, expected := range fromRecords {
+		if actual, ok := toRecords[key]; ok && actual.value == expected.value {
+			continue
+		}
+		mismatches[expected.kind]++
+		if err := expected.sync(ctx, from, to); err != nil {
+			return nil, err
+		}
+	}
+	for key, actual := range toRecords {
+		if _, ok := fromRecords[key]; ok {
+			continue
+		}
+		mismatches[actual.kind]++
+		if err := actual.sync(ctx, from, to); err != nil {
+			return nil, err
+		}
+	}
+	if fromSnap.Epoch > toSnap.Epoch {
+		if err := to.AdvanceEpoch(ctx, fromSnap.Epoch); err != nil {
+			return nil, err
+		}
+	}
+	return mismatches, nil
+}
@@ -3,14 +3,18 @@ package orm
 import (
 	"context"
 	"fmt"
+	"path/filepath"
+	"testing"
 	"time"
 
-	cerrors "github.com/hanfei1991/microcosm/pkg/errors"
-	"github.com/hanfei1991/microcosm/pkg/uuid"
 	"github.com/pingcap/log"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
-	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+
+	cerrors "github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
+	"github.com/hanfei1991/microcosm/pkg/uuid"
 )
 
 func randomDBFile() string {
@@ -24,7 +28,7 @@ func NewMockClient() (Client, error) {
 	// 1. Create different DB for different TestXXX()
 	// 2. Enable DB shared for different connection
 	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", randomDBFile())
-	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+	db, err := gorm.Open(sqliteDialector(dsn), &gorm.Config{
 		SkipDefaultTransaction: true,
 		// TODO: logger
 	})
@@ -33,8 +37,9 @@ func NewMockClient() (Client, error) {
 		return nil, cerrors.ErrMetaNewClientFail.Wrap(err)
 	}
 
-	cli := &metaOpsClient{
-		db: db,
+	cli, err := newMetaOpsClient(db)
+	if err != nil {
+		return nil, err
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
@@ -46,3 +51,22 @@ func NewMockClient() (Client, error) {
 
 	return cli, nil
 }
+
+// NewSQLiteTestClient creates an initialized client of a frame metastore
+// backed by the SQLite file name in a temporary directory of t, the client
+// is closed when t finishes. Unlike NewMockClient, the metastore can be
+// opened again with the returned config, e.g. by another process.
+func NewSQLiteTestClient(t *testing.T, name string) (metaclient.StoreConfigParams, Client) {
+	conf := metaclient.StoreConfigParams{
+		StoreID:   metaclient.FrameMetaID,
+		StoreType: metaclient.StoreTypeSQLite,
+		Endpoints: []string{filepath.Join(t.TempDir(), name)},
+	}
+	cli, err := NewClient(conf, NewDefaultDBConfig())
+	require.NoError(t, err)
+	require.NoError(t, cli.Initialize(context.Background()))
+	t.Cleanup(func() {
+		_ = cli.Close()
+	})
+	return conf, cli
+}
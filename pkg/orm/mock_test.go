@@ -427,6 +427,66 @@ func TestJobMock(t *testing.T) {
 			},
 			output: []*libModel.MasterMetaKVData{},
 		},
+		{
+			fn: "QueryJobsByStatusCode",
+			inputs: []interface{}{
+				libModel.MasterStatusCode(2),
+			},
+			output: []*libModel.MasterMetaKVData{
+				{
+					Model: model.Model{
+						SeqID:     1,
+						CreatedAt: createdAt,
+						UpdatedAt: updatedAt,
+					},
+					ProjectID:  "p111",
+					ID:         "j111",
+					Tp:         1,
+					NodeID:     "n111",
+					Epoch:      1,
+					StatusCode: 2,
+					Addr:       "127.0.0.1",
+					Config:     []byte{0x11, 0x22},
+				},
+			},
+		},
+		{
+			fn: "QueryJobsByFilter",
+			inputs: []interface{}{
+				&JobFilter{
+					ProjectID:   "p111",
+					StatusCodes: []libModel.MasterStatusCode{2, 3},
+					Types:       []libModel.WorkerType{1},
+				},
+			},
+			output: []*libModel.MasterMetaKVData{
+				{
+					Model: model.Model{
+						SeqID:     1,
+						CreatedAt: createdAt,
+						UpdatedAt: updatedAt,
+					},
+					ProjectID:  "p111",
+					ID:         "j111",
+					Tp:         1,
+					NodeID:     "n111",
+					Epoch:      1,
+					StatusCode: 2,
+					Addr:       "127.0.0.1",
+					Config:     []byte{0x11, 0x22},
+				},
+			},
+		},
+		{
+			fn: "QueryJobsByFilter",
+			inputs: []interface{}{
+				&JobFilter{
+					StatusCodes: []libModel.MasterStatusCode{2},
+					Types:       []libModel.WorkerType{2},
+				},
+			},
+			output: []*libModel.MasterMetaKVData{},
+		},
 	}
 
 	for _, tc := range testCases {
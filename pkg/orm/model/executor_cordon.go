@@ -0,0 +1,16 @@
+package model
+
+// ExecutorCordon records an executor cordoned by operators, no new task is
+// scheduled to it. A blacklisted executor is also treated as failed and
+// can't register again.
+type ExecutorCordon struct {
+	Model
+	ExecutorID  string `gorm:"column:executor_id;type:varchar(64) not null;uniqueIndex:uidx_executor_id"`
+	Blacklisted bool   `gorm:"column:blacklisted;type:bool not null default false"`
+}
+
+// ExecutorCordonUpdateColumns is used in gorm update
+var ExecutorCordonUpdateColumns = []string{
+	"updated_at",
+	"blacklisted",
+}
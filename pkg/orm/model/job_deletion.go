@@ -0,0 +1,18 @@
+package model
+
+// JobDeletion records a job deletion in progress, so that a new server
+// master leader can resume it if the old one fails midway
+type JobDeletion struct {
+	Model
+	JobID string `gorm:"column:job_id;type:varchar(64) not null;uniqueIndex:uidx_job_id"`
+	Force bool   `gorm:"column:forced;type:bool not null default false"`
+	// Stage is the next cleanup stage to run
+	Stage string `gorm:"column:stage;type:varchar(16) not null"`
+}
+
+// JobDeletionUpdateColumns is used in gorm update
+var JobDeletionUpdateColumns = []string{
+	"updated_at",
+	"forced",
+	"stage",
+}
@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+// Catch-up policies of a job schedule, they decide how the fire times missed
+// while there is no server master leader are handled.
+const (
+	// CatchUpSkip skips all the missed fires.
+	CatchUpSkip = "skip"
+	// CatchUpOnce submits one job for all the missed fires.
+	CatchUpOnce = "once"
+	// CatchUpAll submits one job for each missed fire.
+	CatchUpAll = "all"
+)
+
+// JobSchedule records a job spec that is submitted periodically according
+// to a cron expression
+type JobSchedule struct {
+	Model
+	ScheduleID string `gorm:"column:schedule_id;type:varchar(64) not null;uniqueIndex:uidx_schedule_id"`
+	ProjectID  string `gorm:"column:project_id;type:varchar(64) not null"`
+	JobType    int32  `gorm:"column:job_type;type:int not null"`
+	Config     []byte `gorm:"column:config;type:blob"`
+	Cron       string `gorm:"column:cron;type:varchar(128) not null"`
+	CatchUp    string `gorm:"column:catch_up;type:varchar(16) not null"`
+	Paused     bool   `gorm:"column:paused;type:bool not null default false"`
+	// LastFireTime is the latest fire time whose job has been submitted, it
+	// is the creation time of the schedule before the first fire.
+	LastFireTime time.Time `gorm:"column:last_fire_time"`
+}
+
+// JobScheduleUpdateColumns is used in gorm update
+var JobScheduleUpdateColumns = []string{
+	"updated_at",
+	"project_id",
+	"job_type",
+	"config",
+	"cron",
+	"catch_up",
+	"paused",
+	"last_fire_time",
+}
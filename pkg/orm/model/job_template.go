@@ -0,0 +1,17 @@
+package model
+
+// JobTemplate records a job config template, jobs are submitted from it by
+// supplying the values of its parameters
+type JobTemplate struct {
+	Model
+	TemplateID string `gorm:"column:template_id;type:varchar(64) not null;uniqueIndex:uidx_template_id"`
+	JobType    int32  `gorm:"column:job_type;type:int not null"`
+	Template   []byte `gorm:"column:template;type:blob"`
+}
+
+// JobTemplateUpdateColumns is used in gorm update
+var JobTemplateUpdateColumns = []string{
+	"updated_at",
+	"job_type",
+	"template",
+}
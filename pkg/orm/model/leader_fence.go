@@ -0,0 +1,50 @@
+package model
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const defaultLeaderFencePK = 1
+
+// LeaderFence records the largest fencing token of the server master leaders
+// that have written to the metastore
+type LeaderFence struct {
+	Model
+	Token int64 `gorm:"type:bigint not null default 0"`
+}
+
+// InitializeLeaderFence insert the only record into the backend table `leader_fences`
+func InitializeLeaderFence(ctx context.Context, db *gorm.DB) error {
+	// Do nothing on conflict
+	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(&LeaderFence{
+		Model: Model{
+			SeqID: defaultLeaderFencePK,
+		},
+	}).Error
+}
+
+// AdvanceLeaderFence locks the fence record in the transaction tx, and
+// advances it to token if token is larger. It returns the token recorded
+// before, the caller should abort tx if it is larger than token.
+func AdvanceLeaderFence(ctx context.Context, tx *gorm.DB, token int64) (int64, error) {
+	var fence LeaderFence
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&fence, defaultLeaderFencePK).Error; err != nil {
+		return 0, err
+	}
+	if fence.Token >= token {
+		return fence.Token, nil
+	}
+
+	if err := tx.Model(&LeaderFence{
+		Model: Model{
+			SeqID: defaultLeaderFencePK,
+		},
+	}).Update("token", token).Error; err != nil {
+		return 0, err
+	}
+	return fence.Token, nil
+}
@@ -31,16 +31,40 @@ func InitializeEpoch(ctx context.Context, db *gorm.DB) error {
 	}).Error
 }
 
+// GetEpoch returns the backend epoch without increasing it
+func GetEpoch(ctx context.Context, db *gorm.DB) (int64, error) {
+	var logicEp LogicEpoch
+	if err := db.First(&logicEp, defaultEpochPK).Error; err != nil {
+		return 0, err
+	}
+	return logicEp.Epoch, nil
+}
+
+// AdvanceEpoch sets the backend epoch to the given one if it is larger
+func AdvanceEpoch(ctx context.Context, db *gorm.DB, epoch int64) error {
+	return db.Model(&LogicEpoch{
+		Model: Model{
+			SeqID: defaultEpochPK,
+		},
+	}).Where("epoch < ?", epoch).Update("epoch", epoch).Error
+}
+
 // GenEpoch will increasing the backend epoch by 1 and return the new epoch
 func GenEpoch(ctx context.Context, db *gorm.DB) (int64, error) {
+	return GenEpochs(ctx, db, 1)
+}
+
+// GenEpochs increases the backend epoch by n and returns the new epoch, the
+// epochs in (new epoch - n, new epoch] are allocated to the caller.
+func GenEpochs(ctx context.Context, db *gorm.DB, n int64) (int64, error) {
 	var epoch int64
 	err := db.Transaction(func(tx *gorm.DB) error {
-		//(1)update epoch = epoch + 1
+		//(1)update epoch = epoch + n
 		if err := tx.Model(&LogicEpoch{
 			Model: Model{
 				SeqID: defaultEpochPK,
 			},
-		}).Update("epoch", gorm.Expr("epoch + ?", 1)).Error; err != nil {
+		}).Update("epoch", gorm.Expr("epoch + ?", n)).Error; err != nil {
 			// return any error will rollback
 			return err
 		}
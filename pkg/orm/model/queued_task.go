@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// QueuedTask records a schedule request waiting in the schedule queue of
+// server master for the resources of the cluster, so that the queue survives
+// the failover of server master.
+type QueuedTask struct {
+	Model
+	TaskID   string    `gorm:"column:task_id;type:varchar(128) not null;uniqueIndex:uidx_task_id"`
+	JobID    string    `gorm:"column:job_id;type:varchar(128) not null"`
+	Priority int32     `gorm:"column:priority;type:int not null default 0"`
+	Deadline time.Time `gorm:"column:deadline"`
+	// Request is the marshaled schedule request
+	Request []byte `gorm:"column:request;type:blob"`
+}
+
+// QueuedTaskUpdateColumns is used in gorm update
+var QueuedTaskUpdateColumns = []string{
+	"updated_at",
+	"job_id",
+	"priority",
+	"deadline",
+	"request",
+}
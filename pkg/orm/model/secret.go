@@ -0,0 +1,16 @@
+package model
+
+// Secret records a named secret of a project, its value is encrypted by the
+// secret KMS and never stored in plaintext
+type Secret struct {
+	Model
+	ProjectID  string `gorm:"column:project_id;type:varchar(64) not null;uniqueIndex:uidx_project_name,priority:1"`
+	Name       string `gorm:"column:name;type:varchar(128) not null;uniqueIndex:uidx_project_name,priority:2"`
+	Ciphertext []byte `gorm:"column:ciphertext;type:blob"`
+}
+
+// SecretUpdateColumns is used in gorm update
+var SecretUpdateColumns = []string{
+	"updated_at",
+	"ciphertext",
+}
@@ -0,0 +1,127 @@
+package orm
+
+import (
+	"reflect"
+
+	"gorm.io/gorm"
+)
+
+// StatementObserver is told the approximate bytes sent and received by each
+// statement executed by a Client returned by WithObserver. The bytes sent
+// are the SQL text and its arguments, and the bytes received are the values
+// of the rows scanned.
+type StatementObserver func(sent, received int)
+
+// ObserverClient defines interface that observes the statements of a Client
+type ObserverClient interface {
+	// WithObserver returns a Client which reports its statements to
+	// observer, it shares the connections with the original one.
+	WithObserver(observer StatementObserver) Client
+}
+
+const observerSettingKey = "microcosm:statement_observer"
+
+// withObserver returns a gorm.DB whose statements are reported to observer.
+func withObserver(db *gorm.DB, observer StatementObserver) *gorm.DB {
+	// A new session is needed to reuse the returned db, see gorm.DB.getInstance.
+	return db.Set(observerSettingKey, observer).Session(&gorm.Session{})
+}
+
+// registerObserverCallbacks registers the callbacks that report the
+// statements executed to the observer set by withObserver.
+func registerObserverCallbacks(db *gorm.DB) error {
+	cb := db.Callback()
+	if err := cb.Create().After("gorm:create").Register("microcosm:observe_create", observeStatement); err != nil {
+		return err
+	}
+	if err := cb.Query().After("gorm:query").Register("microcosm:observe_query", observeQuery); err != nil {
+		return err
+	}
+	if err := cb.Update().After("gorm:update").Register("microcosm:observe_update", observeStatement); err != nil {
+		return err
+	}
+	if err := cb.Delete().After("gorm:delete").Register("microcosm:observe_delete", observeStatement); err != nil {
+		return err
+	}
+	if err := cb.Row().After("gorm:row").Register("microcosm:observe_row", observeStatement); err != nil {
+		return err
+	}
+	return cb.Raw().After("gorm:raw").Register("microcosm:observe_raw", observeStatement)
+}
+
+func observeStatement(db *gorm.DB) {
+	observe(db, false)
+}
+
+// observeQuery also counts the rows scanned into the destination.
+func observeQuery(db *gorm.DB) {
+	observe(db, true)
+}
+
+func observe(db *gorm.DB, scanned bool) {
+	v, ok := db.Get(observerSettingKey)
+	if !ok {
+		return
+	}
+	observer, ok := v.(StatementObserver)
+	if !ok || observer == nil {
+		return
+	}
+
+	sent := db.Statement.SQL.Len()
+	for _, arg := range db.Statement.Vars {
+		sent += valueSize(reflect.ValueOf(arg))
+	}
+	received := 0
+	if scanned && db.Error == nil {
+		received = valueSize(db.Statement.ReflectValue)
+	}
+	observer(sent, received)
+}
+
+// valueSize estimates the size of v in a statement or a row, the numbers
+// and the times are counted as 8 bytes.
+func valueSize(v reflect.Value) int {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return 0
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return 0
+		}
+		return valueSize(v.Elem())
+	case reflect.String:
+		return v.Len()
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Len()
+		}
+		size := 0
+		for i := 0; i < v.Len(); i++ {
+			size += valueSize(v.Index(i))
+		}
+		return size
+	case reflect.Struct:
+		size, exported := 0, false
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				size += valueSize(v.Field(i))
+				exported = true
+			}
+		}
+		if !exported {
+			// time.Time and the like keep their values unexported
+			return 8
+		}
+		return size
+	case reflect.Map:
+		size := 0
+		iter := v.MapRange()
+		for iter.Next() {
+			size += valueSize(iter.Key()) + valueSize(iter.Value())
+		}
+		return size
+	default:
+		return 8
+	}
+}
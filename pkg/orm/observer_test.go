@@ -0,0 +1,45 @@
+package orm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+)
+
+func TestWithObserverMock(t *testing.T) {
+	t.Parallel()
+
+	cli, err := NewMockClient()
+	require.NoError(t, err)
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var sent, received atomic.Int64
+	observed := cli.WithObserver(func(s, r int) {
+		sent.Add(int64(s))
+		received.Add(int64(r))
+	})
+
+	// the original client is not observed
+	err = cli.UpsertJob(ctx, &libModel.MasterMetaKVData{ID: "job-1", Config: []byte("config")})
+	require.NoError(t, err)
+	require.Zero(t, sent.Load())
+
+	_, err = observed.GetJobByID(ctx, "job-1")
+	require.NoError(t, err)
+	require.Greater(t, sent.Load(), int64(0))
+	require.Greater(t, received.Load(), int64(len("job-1config")))
+
+	// the writes of a fenced client are observed too
+	sentBefore := sent.Load()
+	err = observed.WithFencingToken(1).UpsertJob(ctx, &libModel.MasterMetaKVData{ID: "job-2"})
+	require.NoError(t, err)
+	require.Greater(t, sent.Load(), sentBefore)
+}
@@ -0,0 +1,21 @@
+package orm
+
+import (
+	// register the pure Go SQLite driver as "sqlite", so that the server
+	// master built without cgo can run in standalone mode. The same driver
+	// is used with cgo, so that the tests cover it.
+	_ "github.com/glebarez/go-sqlite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// sqliteBusyTimeoutParam is the DSN parameter of the SQLite driver for
+// waiting for a locked database instead of failing at once.
+const sqliteBusyTimeoutParam = "_pragma=busy_timeout(5000)"
+
+// sqliteDialector opens a SQLite database by the pure Go driver, rather
+// than the cgo one used by gorm.io/driver/sqlite by default, which fails at
+// runtime without cgo.
+func sqliteDialector(dsn string) gorm.Dialector {
+	return &sqlite.Dialector{DriverName: "sqlite", DSN: dsn}
+}
@@ -0,0 +1,276 @@
+package p2p
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pingcap/errors"
+
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+// Codec is a compression codec of message payloads.
+type Codec string
+
+// defines the codecs of message payloads
+const (
+	CodecNone   = Codec("")
+	CodecSnappy = Codec("snappy")
+	CodecZstd   = Codec("zstd")
+)
+
+// codecIDs are the IDs of the codecs in compressed frames, they must not be
+// changed once released.
+var codecIDs = map[Codec]byte{
+	CodecSnappy: 1,
+	CodecZstd:   2,
+}
+
+// SupportedCodecs returns the codecs and the encodings this binary decodes, a
+// node publishes them in its node info so that peers know how to encode and
+// compress the messages sent to it.
+func SupportedCodecs() []string {
+	return []string{string(CodecZstd), string(CodecSnappy), string(EncodingMsgpack)}
+}
+
+const (
+	// defaultMaxMessageSize is the default max message size, which is the
+	// default max size of the messages received by gRPC servers.
+	defaultMaxMessageSize = 4 * 1024 * 1024
+	// defaultCompressThreshold is the default size above which payloads are
+	// compressed.
+	defaultCompressThreshold = 64 * 1024
+)
+
+// MessageConfig configures the size limit and the compression of messages.
+type MessageConfig struct {
+	// MaxMessageSize is the max bytes of an encoded message before it is
+	// compressed. Senders reject larger messages with ErrMessageTooLarge, and
+	// receivers reject them with ErrMessageDecodeFailed.
+	MaxMessageSize int `toml:"max-message-size" json:"max-message-size"`
+	// CompressThreshold is the size above which payloads are compressed,
+	// a negative threshold disables compression.
+	CompressThreshold int `toml:"compress-threshold" json:"compress-threshold"`
+	// Codec is the preferred codec, another codec is used if the peer only
+	// decodes that one, and the payload is sent uncompressed if the peer
+	// decodes none of them.
+	Codec Codec `toml:"codec" json:"codec"`
+}
+
+// DefaultMessageConfig returns the default MessageConfig
+func DefaultMessageConfig() *MessageConfig {
+	cfg := &MessageConfig{}
+	_ = cfg.Adjust()
+	return cfg
+}
+
+// Adjust validates the config and fills the default values
+func (c *MessageConfig) Adjust() error {
+	if c.MaxMessageSize < 0 {
+		return derror.ErrMessageInvalidConfig.GenWithStackByArgs("max-message-size can't be negative")
+	}
+	if c.MaxMessageSize == 0 {
+		c.MaxMessageSize = defaultMaxMessageSize
+	}
+	if c.CompressThreshold == 0 {
+		c.CompressThreshold = defaultCompressThreshold
+	}
+	if c.Codec == CodecNone {
+		c.Codec = CodecZstd
+	}
+	if _, ok := codecIDs[c.Codec]; !ok {
+		return derror.ErrMessageInvalidConfig.GenWithStackByArgs(
+			fmt.Sprintf("unknown codec %s", c.Codec))
+	}
+	return nil
+}
+
+// PeerCodecs records the codecs and the encodings decoded by each peer, which
+// are found by service discovery. Peers of old versions publish no codecs, so
+// they always receive uncompressed json messages.
+type PeerCodecs struct {
+	mu    sync.RWMutex
+	peers map[NodeID][]string
+}
+
+// NewPeerCodecs creates a PeerCodecs without peers.
+func NewPeerCodecs() *PeerCodecs {
+	return &PeerCodecs{
+		peers: make(map[NodeID][]string),
+	}
+}
+
+// Observe records the codecs and the encodings decoded by a peer.
+func (p *PeerCodecs) Observe(peer NodeID, codecs []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.peers[peer] = append([]string(nil), codecs...)
+}
+
+// Remove removes a peer.
+func (p *PeerCodecs) Remove(peer NodeID) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.peers, peer)
+}
+
+// choose returns the codec to compress the messages sent to peer, it prefers
+// the preferred codec, and returns CodecNone if the peer decodes no codec
+// known by this binary.
+func (p *PeerCodecs) choose(peer NodeID, preferred Codec) Codec {
+	if p == nil {
+		return CodecNone
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	ret := CodecNone
+	for _, name := range p.peers[peer] {
+		codec := Codec(name)
+		if codec == preferred {
+			return codec
+		}
+		if _, ok := codecIDs[codec]; ok && ret == CodecNone {
+			ret = codec
+		}
+	}
+	return ret
+}
+
+// chooseEncoding returns encoding if the peer decodes it, and EncodingJSON
+// otherwise.
+func (p *PeerCodecs) chooseEncoding(peer NodeID, encoding Encoding) Encoding {
+	if p == nil || encoding == EncodingJSON {
+		return EncodingJSON
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	for _, name := range p.peers[peer] {
+		if Encoding(name) == encoding {
+			return encoding
+		}
+	}
+	return EncodingJSON
+}
+
+// payload is the encoded message passed to the p2p library, which sends the
+// bytes as they are since payload implements p2pImpl.Serializable.
+type payload []byte
+
+// Marshal implements p2pImpl.Serializable.Marshal
+func (p *payload) Marshal() ([]byte, error) {
+	return *p, nil
+}
+
+// Unmarshal implements p2pImpl.Serializable.Unmarshal
+func (p *payload) Unmarshal(data []byte) error {
+	*p = append((*p)[:0], data...)
+	return nil
+}
+
+// compressedFrameMagic starts a compressed payload. An uncompressed payload
+// never starts with it, since a json value doesn't start with a zero byte,
+// and neither does a non-empty protobuf message whose field numbers start
+// from 1.
+const compressedFrameMagic = 0x00
+
+var (
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil)
+)
+
+// encodeMessage encodes a message in encoding, and compresses it with codec
+// if it is larger than the compress threshold. A
+// compressed frame is the magic, the codec ID, the size of the uncompressed
+// payload in uvarint, and the compressed payload.
+func encodeMessage(
+	topic Topic, value interface{}, encoding Encoding, codec Codec, cfg *MessageConfig,
+) (*payload, error) {
+	data, err := marshalValue(value, encoding)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(data) > cfg.MaxMessageSize {
+		return nil, derror.ErrMessageTooLarge.GenWithStackByArgs(topic, len(data), cfg.MaxMessageSize)
+	}
+	codecID, ok := codecIDs[codec]
+	if !ok || cfg.CompressThreshold < 0 || len(data) <= cfg.CompressThreshold {
+		ret := payload(data)
+		return &ret, nil
+	}
+
+	frame := make([]byte, 2+binary.MaxVarintLen64, 2+binary.MaxVarintLen64+len(data)/2)
+	frame[0], frame[1] = compressedFrameMagic, codecID
+	frame = frame[:2+binary.PutUvarint(frame[2:], uint64(len(data)))]
+	switch codec {
+	case CodecSnappy:
+		frame = append(frame, snappy.Encode(nil, data)...)
+	case CodecZstd:
+		frame = zstdEncoder.EncodeAll(data, frame)
+	}
+	if len(frame) >= len(data) {
+		// the payload is not compressible
+		ret := payload(data)
+		return &ret, nil
+	}
+	ret := payload(frame)
+	return &ret, nil
+}
+
+// decodeMessage decompresses the payload if it is compressed, and decodes it
+// into a new value of the type of tpi.
+func decodeMessage(topic Topic, data []byte, tp reflect.Type, maxSize int) (MessageValue, error) {
+	if len(data) > 0 && data[0] == compressedFrameMagic {
+		var err error
+		if data, err = decompress(data, maxSize); err != nil {
+			return nil, derror.ErrMessageDecodeFailed.GenWithStackByArgs(topic, err.Error())
+		}
+	}
+	if len(data) > maxSize {
+		return nil, derror.ErrMessageDecodeFailed.GenWithStackByArgs(
+			topic, fmt.Sprintf("%d bytes exceed the limit %d", len(data), maxSize))
+	}
+	value := reflect.New(tp.Elem()).Interface()
+	if err := unmarshalValue(data, value); err != nil {
+		return nil, derror.ErrMessageDecodeFailed.GenWithStackByArgs(topic, err.Error())
+	}
+	return value, nil
+}
+
+func decompress(frame []byte, maxSize int) ([]byte, error) {
+	if len(frame) < 2 {
+		return nil, fmt.Errorf("truncated frame")
+	}
+	codecID := frame[1]
+	size, n := binary.Uvarint(frame[2:])
+	if n <= 0 {
+		return nil, fmt.Errorf("invalid uncompressed size")
+	}
+	if size > uint64(maxSize) {
+		return nil, fmt.Errorf("%d bytes exceed the limit %d", size, maxSize)
+	}
+	body := frame[2+n:]
+
+	var (
+		data []byte
+		err  error
+	)
+	switch codecID {
+	case codecIDs[CodecSnappy]:
+		data, err = snappy.Decode(make([]byte, size), body)
+	case codecIDs[CodecZstd]:
+		data, err = zstdDecoder.DecodeAll(body, make([]byte, 0, size))
+	default:
+		return nil, fmt.Errorf("unknown codec %d", codecID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(data)) != size {
+		return nil, fmt.Errorf("uncompressed size %d mismatches %d", len(data), size)
+	}
+	return data, nil
+}
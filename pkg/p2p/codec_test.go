@@ -0,0 +1,114 @@
+package p2p
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+type codecTestMessage struct {
+	Data string `json:"data"`
+}
+
+func TestMessageConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := DefaultMessageConfig()
+	require.Equal(t, defaultMaxMessageSize, cfg.MaxMessageSize)
+	require.Equal(t, defaultCompressThreshold, cfg.CompressThreshold)
+	require.Equal(t, CodecZstd, cfg.Codec)
+
+	for _, cfg := range []*MessageConfig{
+		{MaxMessageSize: -1},
+		{Codec: "gzip"},
+	} {
+		require.True(t, derror.ErrMessageInvalidConfig.Equal(cfg.Adjust()), "config: %+v", cfg)
+	}
+}
+
+func TestPeerCodecs(t *testing.T) {
+	t.Parallel()
+
+	var nilCodecs *PeerCodecs
+	require.Equal(t, CodecNone, nilCodecs.choose("node-1", CodecZstd))
+
+	peers := NewPeerCodecs()
+	require.Equal(t, CodecNone, peers.choose("node-1", CodecZstd))
+	peers.Observe("node-1", SupportedCodecs())
+	require.Equal(t, CodecZstd, peers.choose("node-1", CodecZstd))
+	require.Equal(t, CodecSnappy, peers.choose("node-1", CodecSnappy))
+	// the peer doesn't decode the preferred codec
+	peers.Observe("node-2", []string{"lz4", string(CodecSnappy)})
+	require.Equal(t, CodecSnappy, peers.choose("node-2", CodecZstd))
+	// the peer of an old version decodes no codec
+	peers.Observe("node-3", nil)
+	require.Equal(t, CodecNone, peers.choose("node-3", CodecZstd))
+	peers.Remove("node-1")
+	require.Equal(t, CodecNone, peers.choose("node-1", CodecZstd))
+}
+
+func TestEncodeDecodeMessage(t *testing.T) {
+	t.Parallel()
+
+	cfg := &MessageConfig{CompressThreshold: 1024}
+	require.NoError(t, cfg.Adjust())
+	tp := reflect.TypeOf(&codecTestMessage{})
+	small := &codecTestMessage{Data: "small"}
+	large := &codecTestMessage{Data: string(bytes.Repeat([]byte("large"), 1024))}
+	plain, err := json.Marshal(large)
+	require.NoError(t, err)
+
+	for _, codec := range []Codec{CodecNone, CodecSnappy, CodecZstd} {
+		// small messages are not compressed
+		data, err := encodeMessage("topic", small, EncodingJSON, codec, cfg)
+		require.NoError(t, err)
+		expected, err := json.Marshal(small)
+		require.NoError(t, err)
+		require.Equal(t, expected, []byte(*data))
+
+		data, err = encodeMessage("topic", large, EncodingJSON, codec, cfg)
+		require.NoError(t, err)
+		if codec == CodecNone {
+			require.Equal(t, plain, []byte(*data))
+		} else {
+			require.Less(t, len(*data), len(plain))
+		}
+		value, err := decodeMessage("topic", *data, tp, cfg.MaxMessageSize)
+		require.NoError(t, err)
+		require.Equal(t, large, value)
+	}
+
+	// messages sent by peers of old versions are not compressed
+	value, err := decodeMessage("topic", plain, tp, cfg.MaxMessageSize)
+	require.NoError(t, err)
+	require.Equal(t, large, value)
+}
+
+func TestMessageSizeLimit(t *testing.T) {
+	t.Parallel()
+
+	cfg := &MessageConfig{MaxMessageSize: 1024}
+	require.NoError(t, cfg.Adjust())
+	tp := reflect.TypeOf(&codecTestMessage{})
+	msg := &codecTestMessage{Data: string(bytes.Repeat([]byte("a"), 2048))}
+	_, err := encodeMessage("topic", msg, EncodingJSON, CodecZstd, cfg)
+	require.True(t, derror.ErrMessageTooLarge.Equal(err))
+
+	// the receiver checks the size of the decompressed payload
+	data, err := encodeMessage("topic", msg, EncodingJSON, CodecZstd, &MessageConfig{
+		MaxMessageSize: 4096, CompressThreshold: 1024, Codec: CodecZstd,
+	})
+	require.NoError(t, err)
+	require.Less(t, len(*data), 1024)
+	_, err = decodeMessage("topic", *data, tp, cfg.MaxMessageSize)
+	require.True(t, derror.ErrMessageDecodeFailed.Equal(err))
+
+	// corrupted frames are rejected
+	_, err = decodeMessage("topic", []byte{compressedFrameMagic, 9, 1, 0}, tp, cfg.MaxMessageSize)
+	require.True(t, derror.ErrMessageDecodeFailed.Equal(err))
+}
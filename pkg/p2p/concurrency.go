@@ -0,0 +1,129 @@
+package p2p
+
+import (
+	"hash/fnv"
+	"strings"
+	"sync"
+
+	"go.uber.org/atomic"
+)
+
+// concurrentHandlerQueueSize is the number of messages a goroutine of a
+// concurrent handler buffers, the message server blocks when it is full.
+const concurrentHandlerQueueSize = 128
+
+var topicConcurrencies struct {
+	sync.RWMutex
+	concurrencies map[string]int
+}
+
+// RegisterTopicConcurrency sets the number of goroutines that run the handler
+// of the topics that start with topicPrefix. If several prefixes match a
+// topic, the longest one is used.
+//
+// By default a handler is called by one goroutine at a time, in the order the
+// messages are received, see MessageHandlerManager. A concurrent handler is
+// called by several goroutines, and the messages from the same sender are
+// still handled in order by one of them, so it suits the topics whose
+// messages from different senders are independent and slow to handle. A
+// concurrency less than 2 restores the default.
+func RegisterTopicConcurrency(topicPrefix string, concurrency int) {
+	topicConcurrencies.Lock()
+	defer topicConcurrencies.Unlock()
+
+	if concurrency < 2 {
+		delete(topicConcurrencies.concurrencies, topicPrefix)
+		return
+	}
+	if topicConcurrencies.concurrencies == nil {
+		topicConcurrencies.concurrencies = make(map[string]int)
+	}
+	topicConcurrencies.concurrencies[topicPrefix] = concurrency
+}
+
+// topicConcurrency returns the number of goroutines that run the handler of
+// topic, which is 1 if no prefix of topic is registered.
+func topicConcurrency(topic Topic) int {
+	topicConcurrencies.RLock()
+	defer topicConcurrencies.RUnlock()
+
+	var prefix string
+	ret := 1
+	for p, c := range topicConcurrencies.concurrencies {
+		if strings.HasPrefix(topic, p) && len(p) >= len(prefix) {
+			prefix, ret = p, c
+		}
+	}
+	return ret
+}
+
+type handlerTask struct {
+	sender NodeID
+	value  MessageValue
+}
+
+// concurrentHandler runs a handler in several goroutines, the messages of a
+// sender are always handled by the same goroutine. Like the handlers run by
+// the message server, it drops the messages after the handler fails.
+type concurrentHandler struct {
+	fn      HandlerFunc
+	queues  []chan handlerTask
+	closeCh chan struct{}
+	closed  sync.Once
+	wg      sync.WaitGroup
+
+	errOnce sync.Once
+	err     atomic.Error
+}
+
+func newConcurrentHandler(fn HandlerFunc, concurrency int) *concurrentHandler {
+	h := &concurrentHandler{
+		fn:      fn,
+		queues:  make([]chan handlerTask, concurrency),
+		closeCh: make(chan struct{}),
+	}
+	for i := range h.queues {
+		h.queues[i] = make(chan handlerTask, concurrentHandlerQueueSize)
+		h.wg.Add(1)
+		go h.run(h.queues[i])
+	}
+	return h
+}
+
+func (h *concurrentHandler) run(queue <-chan handlerTask) {
+	defer h.wg.Done()
+	for {
+		select {
+		case <-h.closeCh:
+			return
+		case task := <-queue:
+			if h.err.Load() != nil {
+				continue
+			}
+			if err := h.fn(task.sender, task.value); err != nil {
+				h.errOnce.Do(func() { h.err.Store(err) })
+			}
+		}
+	}
+}
+
+// dispatch queues a message to the goroutine of its sender, it returns the
+// error of the handler if the handler has failed.
+func (h *concurrentHandler) dispatch(sender NodeID, value MessageValue) error {
+	if err := h.err.Load(); err != nil {
+		return err
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(sender))
+	select {
+	case <-h.closeCh:
+	case h.queues[hash.Sum32()%uint32(len(h.queues))] <- handlerTask{sender: sender, value: value}:
+	}
+	return nil
+}
+
+// close stops the goroutines, the queued messages are dropped.
+func (h *concurrentHandler) close() {
+	h.closed.Do(func() { close(h.closeCh) })
+	h.wg.Wait()
+}
@@ -0,0 +1,126 @@
+package p2p
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopicConcurrency(t *testing.T) {
+	RegisterTopicConcurrency("test-concurrency-", 4)
+	RegisterTopicConcurrency("test-concurrency-ordered-", 1)
+	defer RegisterTopicConcurrency("test-concurrency-", 0)
+
+	require.Equal(t, 4, topicConcurrency("test-concurrency-1"))
+	require.Equal(t, 4, topicConcurrency("test-concurrency-ordered-1"))
+	require.Equal(t, 1, topicConcurrency("test-no-concurrency"))
+	RegisterTopicConcurrency("test-concurrency-ordered-", 8)
+	defer RegisterTopicConcurrency("test-concurrency-ordered-", 0)
+	// the longest prefix is used
+	require.Equal(t, 8, topicConcurrency("test-concurrency-ordered-1"))
+}
+
+func TestConcurrentHandler(t *testing.T) {
+	t.Parallel()
+
+	const (
+		senders  = 8
+		messages = 100
+	)
+	var (
+		mu       sync.Mutex
+		received = make(map[NodeID][]int)
+	)
+	h := newConcurrentHandler(func(sender NodeID, value MessageValue) error {
+		mu.Lock()
+		defer mu.Unlock()
+		received[sender] = append(received[sender], value.(int))
+		return nil
+	}, 4)
+	defer h.close()
+
+	for i := 0; i < messages; i++ {
+		for j := 0; j < senders; j++ {
+			require.NoError(t, h.dispatch(fmt.Sprintf("node-%d", j), i))
+		}
+	}
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, values := range received {
+			if len(values) < messages {
+				return false
+			}
+		}
+		return len(received) == senders
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the messages from a sender are handled in order
+	mu.Lock()
+	defer mu.Unlock()
+	for sender, values := range received {
+		for i, value := range values {
+			require.Equal(t, i, value, "sender: %s", sender)
+		}
+	}
+}
+
+func TestConcurrentHandlerError(t *testing.T) {
+	t.Parallel()
+
+	h := newConcurrentHandler(func(sender NodeID, value MessageValue) error {
+		return errors.New("fake error")
+	}, 2)
+	require.NoError(t, h.dispatch("node-1", 1))
+	require.Eventually(t, func() bool {
+		return h.dispatch("node-1", 2) != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	h.close()
+	// close is idempotent
+	h.close()
+}
+
+func TestMessageHandlerManagerConcurrentTopic(t *testing.T) {
+	RegisterTopicConcurrency("test-concurrent-topic-", 2)
+	defer RegisterTopicConcurrency("test-concurrent-topic-", 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	registrar := &mockHandlerRegistrar{}
+	manager := newMessageHandlerManager(registrar, DefaultMessageConfig())
+
+	var serverHandler HandlerFunc
+	registrar.On("SyncAddHandler", mock.Anything, "test-concurrent-topic-1", &payload{}, mock.Anything).
+		Return((<-chan error)(make(chan error, 1)), nil).
+		Run(func(args mock.Arguments) {
+			serverHandler = args.Get(3).(HandlerFunc)
+		})
+	receivedCh := make(chan NodeID, 1)
+	ok, err := manager.RegisterHandler(ctx, "test-concurrent-topic-1", &msgContent{},
+		func(sender NodeID, value MessageValue) error {
+			receivedCh <- sender
+			return errors.New("fake error")
+		})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	data := payload("{}")
+	require.NoError(t, serverHandler("node-1", &data))
+	require.Equal(t, "node-1", <-receivedCh)
+	// the error of a concurrent handler is reported by CheckError
+	require.Eventually(t, func() bool {
+		return manager.CheckError(ctx) != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	registrar.On("SyncRemoveHandler", mock.Anything, "test-concurrent-topic-1").Return(nil)
+	ok, err = manager.UnregisterHandler(ctx, "test-concurrent-topic-1")
+	require.NoError(t, err)
+	require.True(t, ok)
+}
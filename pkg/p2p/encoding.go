@@ -0,0 +1,177 @@
+package p2p
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/pingcap/errors"
+	p2pImpl "github.com/pingcap/tiflow/pkg/p2p"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Encoding is the encoding of message values. Values implementing
+// p2pImpl.Serializable, such as protobuf messages, are always encoded by
+// their own Marshal, other values are encoded in the encoding of their topic.
+type Encoding string
+
+// defines the encodings of message values
+const (
+	EncodingJSON    = Encoding("json")
+	EncodingMsgpack = Encoding("msgpack")
+)
+
+// encodingIDs are the IDs of the encodings in encoded frames, they must not
+// be changed once released.
+var encodingIDs = map[Encoding]byte{
+	EncodingMsgpack: 1,
+}
+
+// encodedFrameMagic starts a value in a binary encoding, which is followed by
+// the encoding ID and the encoded value. Like compressedFrameMagic, neither a
+// json value nor a protobuf message starts with it.
+const encodedFrameMagic = 0x01
+
+var topicEncodings struct {
+	sync.RWMutex
+	encodings map[string]Encoding
+}
+
+// RegisterTopicEncoding sets the encoding of the messages of the topics that
+// start with topicPrefix. If several prefixes match a topic, the longest one
+// is used. It suits the topics of frequent messages, such as heartbeats.
+// The messages sent to the peers that don't decode the encoding, which are of
+// old versions, are still encoded in json. EncodingJSON removes the encoding.
+func RegisterTopicEncoding(topicPrefix string, encoding Encoding) {
+	topicEncodings.Lock()
+	defer topicEncodings.Unlock()
+
+	if encoding == EncodingJSON {
+		delete(topicEncodings.encodings, topicPrefix)
+		return
+	}
+	if topicEncodings.encodings == nil {
+		topicEncodings.encodings = make(map[string]Encoding)
+	}
+	topicEncodings.encodings[topicPrefix] = encoding
+}
+
+// topicEncoding returns the encoding of topic, which is EncodingJSON if no
+// prefix of topic is registered.
+func topicEncoding(topic Topic) Encoding {
+	topicEncodings.RLock()
+	defer topicEncodings.RUnlock()
+
+	var prefix string
+	ret := EncodingJSON
+	for p, e := range topicEncodings.encodings {
+		if strings.HasPrefix(topic, p) && len(p) >= len(prefix) {
+			prefix, ret = p, e
+		}
+	}
+	return ret
+}
+
+func marshalValue(value interface{}, encoding Encoding) ([]byte, error) {
+	if value, ok := value.(p2pImpl.Serializable); ok {
+		return value.Marshal()
+	}
+	encodingID, ok := encodingIDs[encoding]
+	if !ok {
+		return json.Marshal(value)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte(encodedFrameMagic)
+	buf.WriteByte(encodingID)
+	enc := msgpack.NewEncoder(&buf)
+	// the field names are shared with json, so that a message type needs no
+	// tags for each encoding
+	enc.SetCustomStructTag("json")
+	enc.UseCompactInts(true)
+	if err := enc.Encode(value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func unmarshalValue(data []byte, value interface{}) error {
+	if value, ok := value.(p2pImpl.Serializable); ok {
+		return value.Unmarshal(data)
+	}
+	if len(data) == 0 || data[0] != encodedFrameMagic {
+		return json.Unmarshal(data, value)
+	}
+
+	if len(data) < 2 || data[1] != encodingIDs[EncodingMsgpack] {
+		// a peer never sends an encoding this binary doesn't publish
+		return fmt.Errorf("unknown encoding of frame")
+	}
+	dec := msgpack.NewDecoder(bytes.NewReader(data[2:]))
+	dec.SetCustomStructTag("json")
+	return dec.Decode(value)
+}
+
+// EncodedMessage is a message value encoded by EncodeMessageValue. It is sent
+// and received as it is, so that a process forwarding messages for another
+// one, such as an executor forwarding messages for its worker processes,
+// needn't know the types of the messages.
+type EncodedMessage []byte
+
+// Marshal implements p2pImpl.Serializable.Marshal
+func (m *EncodedMessage) Marshal() ([]byte, error) {
+	return *m, nil
+}
+
+// Unmarshal implements p2pImpl.Serializable.Unmarshal
+func (m *EncodedMessage) Unmarshal(data []byte) error {
+	*m = append((*m)[:0], data...)
+	return nil
+}
+
+// EncodeMessageValue encodes value in the encoding of topic.
+func EncodeMessageValue(topic Topic, value interface{}) (EncodedMessage, error) {
+	data, err := marshalValue(value, topicEncoding(topic))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return data, nil
+}
+
+// DecodeMessageValue decodes an EncodedMessage into value, whichever encoding
+// it is in.
+func DecodeMessageValue(data EncodedMessage, value interface{}) error {
+	return errors.Trace(unmarshalValue(data, value))
+}
+
+// encoding returns the encoding the message is in.
+func (m EncodedMessage) encoding() Encoding {
+	if len(m) < 2 || m[0] != encodedFrameMagic {
+		return EncodingJSON
+	}
+	for encoding, id := range encodingIDs {
+		if id == m[1] {
+			return encoding
+		}
+	}
+	return EncodingJSON
+}
+
+// toJSON re-encodes the message in json, for the peers that don't decode the
+// encoding the message is in.
+func (m EncodedMessage) toJSON() (EncodedMessage, error) {
+	if m.encoding() == EncodingJSON {
+		return m, nil
+	}
+	var value interface{}
+	if err := unmarshalValue(m, &value); err != nil {
+		return nil, errors.Trace(err)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return data, nil
+}
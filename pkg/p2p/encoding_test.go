@@ -0,0 +1,120 @@
+package p2p
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type encodingTestMessage struct {
+	Data     string        `json:"data"`
+	Seq      uint64        `json:"seq,omitempty"`
+	Interval time.Duration `json:"interval"`
+	Tags     []string      `json:"tags,omitempty"`
+	Ignored  string        `json:"-"`
+}
+
+func TestTopicEncoding(t *testing.T) {
+	RegisterTopicEncoding("test-encoding-", EncodingMsgpack)
+	RegisterTopicEncoding("test-encoding-json-", EncodingMsgpack)
+	defer RegisterTopicEncoding("test-encoding-", EncodingJSON)
+
+	require.Equal(t, EncodingMsgpack, topicEncoding("test-encoding-1"))
+	require.Equal(t, EncodingJSON, topicEncoding("test-no-encoding"))
+	// the longest prefix is used
+	RegisterTopicEncoding("test-encoding-json-", EncodingJSON)
+	require.Equal(t, EncodingMsgpack, topicEncoding("test-encoding-json-1"))
+
+	var nilCodecs *PeerCodecs
+	require.Equal(t, EncodingJSON, nilCodecs.chooseEncoding("node-1", EncodingMsgpack))
+	peers := NewPeerCodecs()
+	peers.Observe("node-1", SupportedCodecs())
+	require.Equal(t, EncodingMsgpack, peers.chooseEncoding("node-1", EncodingMsgpack))
+	// the peer of an old version decodes json only
+	peers.Observe("node-2", []string{string(CodecZstd)})
+	require.Equal(t, EncodingJSON, peers.chooseEncoding("node-2", EncodingMsgpack))
+}
+
+func TestEncodeDecodeMsgpack(t *testing.T) {
+	t.Parallel()
+
+	cfg := &MessageConfig{CompressThreshold: 1024}
+	require.NoError(t, cfg.Adjust())
+	tp := reflect.TypeOf(&encodingTestMessage{})
+	msg := &encodingTestMessage{
+		Data:     "data",
+		Seq:      10,
+		Interval: time.Second,
+		Tags:     []string{"a", "b"},
+		Ignored:  "ignored",
+	}
+	expected := *msg
+	expected.Ignored = ""
+
+	plain, err := json.Marshal(msg)
+	require.NoError(t, err)
+	data, err := encodeMessage("topic", msg, EncodingMsgpack, CodecNone, cfg)
+	require.NoError(t, err)
+	require.Equal(t, byte(encodedFrameMagic), []byte(*data)[0])
+	require.Less(t, len(*data), len(plain))
+	value, err := decodeMessage("topic", *data, tp, cfg.MaxMessageSize)
+	require.NoError(t, err)
+	require.Equal(t, &expected, value)
+
+	// large messages are encoded and then compressed
+	large := &encodingTestMessage{Data: string(bytes.Repeat([]byte("large"), 1024))}
+	data, err = encodeMessage("topic", large, EncodingMsgpack, CodecZstd, cfg)
+	require.NoError(t, err)
+	require.Equal(t, byte(compressedFrameMagic), []byte(*data)[0])
+	value, err = decodeMessage("topic", *data, tp, cfg.MaxMessageSize)
+	require.NoError(t, err)
+	require.Equal(t, large, value)
+
+	// Serializable values are encoded by themselves
+	raw := payload("raw")
+	data, err = encodeMessage("topic", &raw, EncodingMsgpack, CodecNone, cfg)
+	require.NoError(t, err)
+	require.Equal(t, raw, *data)
+
+	_, err = decodeMessage("topic", []byte{encodedFrameMagic, 9}, tp, cfg.MaxMessageSize)
+	require.Error(t, err)
+}
+
+func TestEncodedMessage(t *testing.T) {
+	RegisterTopicEncoding("test-encoded-", EncodingMsgpack)
+	defer RegisterTopicEncoding("test-encoded-", EncodingJSON)
+
+	cfg := DefaultMessageConfig()
+	tp := reflect.TypeOf(&encodingTestMessage{})
+	msg := &encodingTestMessage{Data: "data", Seq: 10, Interval: time.Second}
+
+	encoded, err := EncodeMessageValue("test-encoded-1", msg)
+	require.NoError(t, err)
+	require.Equal(t, EncodingMsgpack, encoded.encoding())
+	decoded := &encodingTestMessage{}
+	require.NoError(t, DecodeMessageValue(encoded, decoded))
+	require.Equal(t, msg, decoded)
+
+	// an encoded message is sent as it is, and received without being decoded
+	data, err := encodeMessage("test-encoded-1", &encoded, EncodingMsgpack, CodecNone, cfg)
+	require.NoError(t, err)
+	value, err := decodeMessage("test-encoded-1", *data, reflect.TypeOf(&EncodedMessage{}), cfg.MaxMessageSize)
+	require.NoError(t, err)
+	require.Equal(t, &encoded, value)
+
+	// the peers of old versions receive it in json
+	jsonData, err := encoded.toJSON()
+	require.NoError(t, err)
+	require.Equal(t, EncodingJSON, jsonData.encoding())
+	value, err = decodeMessage("test-encoded-1", jsonData, tp, cfg.MaxMessageSize)
+	require.NoError(t, err)
+	require.Equal(t, msg, value)
+
+	encoded, err = EncodeMessageValue("test-not-encoded", msg)
+	require.NoError(t, err)
+	require.Equal(t, EncodingJSON, encoded.encoding())
+}
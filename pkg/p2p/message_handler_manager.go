@@ -2,6 +2,7 @@ package p2p
 
 import (
 	"context"
+	"reflect"
 	"sync"
 	"time"
 
@@ -17,6 +18,13 @@ const (
 
 // MessageHandlerManager is for managing message topic handlers.
 // NOTE: for each topic, only one handler is allowed.
+//
+// The handler of a topic is called by one goroutine at a time, and the
+// messages from the same sender are handled in the order they are sent, so a
+// handler can rely on the order of heartbeats. The messages from different
+// senders are interleaved in the order they are received. The handlers of the
+// topics registered by RegisterTopicConcurrency are called concurrently for
+// different senders instead.
 type MessageHandlerManager interface {
 	RegisterHandler(ctx context.Context, topic Topic, tpi TypeInformation, fn HandlerFunc) (bool, error)
 	UnregisterHandler(ctx context.Context, topic Topic) (bool, error)
@@ -31,11 +39,12 @@ type MessageHandlerManager interface {
 	SetTimeout(timeout time.Duration)
 }
 
-func newMessageHandlerManager(registrar handlerRegistrar) MessageHandlerManager {
+func newMessageHandlerManager(registrar handlerRegistrar, cfg *MessageConfig) MessageHandlerManager {
 	return &messageHandlerManagerImpl{
 		messageServer: registrar,
+		cfg:           cfg,
 		timeout:       atomic.NewDuration(defaultHandlerOperationTimeout),
-		topics:        make(map[Topic]<-chan error),
+		topics:        make(map[Topic]*topicHandler),
 	}
 }
 
@@ -49,12 +58,41 @@ type handlerRegistrar interface {
 
 type messageHandlerManagerImpl struct {
 	messageServer handlerRegistrar
+	cfg           *MessageConfig
 	// timeout is atomic to avoid unnecessary blocking
 	timeout *atomic.Duration
 
 	// mu protects topics
 	mu     sync.Mutex
-	topics map[Topic]<-chan error
+	topics map[Topic]*topicHandler
+}
+
+type topicHandler struct {
+	errCh <-chan error
+	// concurrent is nil unless the topic is registered by
+	// RegisterTopicConcurrency.
+	concurrent *concurrentHandler
+}
+
+// close stops the goroutines of the handler, it must be called after the
+// handler is removed from the message server.
+func (h *topicHandler) close() {
+	if h.concurrent != nil {
+		h.concurrent.close()
+	}
+}
+
+// checkError returns the error of the handler if it has failed.
+func (h *topicHandler) checkError() error {
+	select {
+	case err := <-h.errCh:
+		return err
+	default:
+	}
+	if h.concurrent != nil {
+		return h.concurrent.err.Load()
+	}
+	return nil
 }
 
 func (m *messageHandlerManagerImpl) RegisterHandler(
@@ -71,14 +109,48 @@ func (m *messageHandlerManagerImpl) RegisterHandler(
 		return false, nil
 	}
 
+	observer := wireSizeObserver(ctx)
 	ctx, cancel := m.makeContext(ctx)
 	defer cancel()
 
-	errCh, err := m.messageServer.SyncAddHandler(ctx, topic, tpi, fn)
+	handler := &topicHandler{}
+	// the message server calls the handler of a topic by one goroutine, mu
+	// guarantees it in case the message server changes.
+	var mu sync.Mutex
+	deliver := func(sender NodeID, value MessageValue) error {
+		mu.Lock()
+		defer mu.Unlock()
+		return fn(sender, value)
+	}
+	if concurrency := topicConcurrency(topic); concurrency > 1 {
+		handler.concurrent = newConcurrentHandler(fn, concurrency)
+		deliver = handler.concurrent.dispatch
+	}
+
+	// the message server passes the payloads to the handler as they are, they
+	// are decompressed and decoded into the type of tpi here.
+	tp := reflect.TypeOf(tpi)
+	errCh, err := m.messageServer.SyncAddHandler(ctx, topic, &payload{},
+		func(sender NodeID, raw MessageValue) error {
+			data := *raw.(*payload)
+			if observer != nil {
+				observer(len(data))
+			}
+			value, err := decodeMessage(topic, data, tp, m.cfg.MaxMessageSize)
+			if err != nil {
+				return err
+			}
+			if isMessageExpired(topic, value, time.Now()) {
+				return nil
+			}
+			return deliver(sender, value)
+		})
 	if err != nil {
+		handler.close()
 		return false, errors.Trace(err)
 	}
-	m.topics[topic] = errCh
+	handler.errCh = errCh
+	m.topics[topic] = handler
 
 	return true, nil
 }
@@ -87,7 +159,8 @@ func (m *messageHandlerManagerImpl) UnregisterHandler(ctx context.Context, topic
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if _, ok := m.topics[topic]; !ok {
+	handler, ok := m.topics[topic]
+	if !ok {
 		// The handler for this topic does not exist
 		return false, nil
 	}
@@ -98,6 +171,7 @@ func (m *messageHandlerManagerImpl) UnregisterHandler(ctx context.Context, topic
 	if err := m.messageServer.SyncRemoveHandler(ctx, topic); err != nil {
 		return false, errors.Trace(err)
 	}
+	handler.close()
 	delete(m.topics, topic)
 
 	return true, nil
@@ -107,18 +181,14 @@ func (m *messageHandlerManagerImpl) CheckError(ctx context.Context) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	for topic, errCh := range m.topics {
-		select {
-		case <-ctx.Done():
+	for topic, handler := range m.topics {
+		if ctx.Err() != nil {
 			return errors.Trace(ctx.Err())
-		case err := <-errCh:
-			if err == nil {
-				continue
-			}
+		}
+		if err := handler.checkError(); err != nil {
 			log.L().Warn("handler error received",
 				zap.String("topic", topic))
 			return errors.Trace(err)
-		default:
 		}
 	}
 	return nil
@@ -128,10 +198,11 @@ func (m *messageHandlerManagerImpl) Clean(ctx context.Context) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	for topic := range m.topics {
+	for topic, handler := range m.topics {
 		if err := m.messageServer.SyncRemoveHandler(ctx, topic); err != nil {
 			return errors.Trace(err)
 		}
+		handler.close()
 	}
 
 	return nil
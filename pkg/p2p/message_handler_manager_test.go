@@ -40,10 +40,10 @@ func TestMessageHandlerManagerBasics(t *testing.T) {
 	defer cancel()
 
 	registrar := &mockHandlerRegistrar{}
-	manager := newMessageHandlerManager(registrar)
+	manager := newMessageHandlerManager(registrar, DefaultMessageConfig())
 
 	errCh1 := make(chan error, 1)
-	registrar.On("SyncAddHandler", mock.Anything, "test-topic-1", &msgContent{}, mock.Anything).
+	registrar.On("SyncAddHandler", mock.Anything, "test-topic-1", &payload{}, mock.Anything).
 		Return((<-chan error)(errCh1), nil)
 	ok, err := manager.RegisterHandler(ctx, "test-topic-1", &msgContent{}, func(NodeID, MessageValue) error {
 		// This function does not matter here
@@ -63,7 +63,7 @@ func TestMessageHandlerManagerBasics(t *testing.T) {
 
 	errCh2 := make(chan error, 1)
 	registrar.ExpectedCalls = nil
-	registrar.On("SyncAddHandler", mock.Anything, "test-topic-2", &msgContent{}, mock.Anything).
+	registrar.On("SyncAddHandler", mock.Anything, "test-topic-2", &payload{}, mock.Anything).
 		Return((<-chan error)(errCh2), nil)
 	ok, err = manager.RegisterHandler(ctx, "test-topic-2", &msgContent{}, func(NodeID, MessageValue) error {
 		// This function does not matter here
@@ -103,10 +103,10 @@ func TestMessageHandlerManagerTimeout(t *testing.T) {
 	defer cancel()
 
 	registrar := &mockHandlerRegistrar{}
-	manager := newMessageHandlerManager(registrar)
+	manager := newMessageHandlerManager(registrar, DefaultMessageConfig())
 	manager.SetTimeout(time.Duration(0))
 
-	registrar.On("SyncAddHandler", mock.Anything, "test-topic-1", &msgContent{}, mock.Anything).
+	registrar.On("SyncAddHandler", mock.Anything, "test-topic-1", &payload{}, mock.Anything).
 		Return((<-chan error)(nil), errors.New("fake error")).
 		Run(func(args mock.Arguments) {
 			ctx := args.Get(0).(context.Context)
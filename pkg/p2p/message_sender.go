@@ -26,11 +26,41 @@ type MessageSender interface {
 
 type messageSenderImpl struct {
 	router MessageRouter
+	cfg    *MessageConfig
+	peers  *PeerCodecs
 }
 
-// NewMessageSender returns a new message sender.
+// NewMessageSender returns a new message sender, which never compresses
+// messages, and encodes them in json unless they are Serializable.
 func NewMessageSender(router MessageRouter) MessageSender {
-	return &messageSenderImpl{router: router}
+	return NewMessageSenderWithCodecs(router, DefaultMessageConfig(), nil)
+}
+
+// NewMessageSenderWithCodecs returns a new message sender, which encodes and
+// compresses the messages sent to a peer with the codecs the peer decodes.
+func NewMessageSenderWithCodecs(router MessageRouter, cfg *MessageConfig, peers *PeerCodecs) MessageSender {
+	return &messageSenderImpl{
+		router: router,
+		cfg:    cfg,
+		peers:  peers,
+	}
+}
+
+func (m *messageSenderImpl) encode(targetNodeID NodeID, topic Topic, message interface{}) (*payload, error) {
+	if msg, ok := message.(*EncodedMessage); ok {
+		// the message is forwarded as it is unless the peer doesn't decode
+		// its encoding
+		if m.peers.chooseEncoding(targetNodeID, msg.encoding()) != msg.encoding() {
+			data, err := msg.toJSON()
+			if err != nil {
+				return nil, err
+			}
+			message = &data
+		}
+	}
+	return encodeMessage(topic, message,
+		m.peers.chooseEncoding(targetNodeID, topicEncoding(topic)),
+		m.peers.choose(targetNodeID, m.cfg.Codec), m.cfg)
 }
 
 // SendToNodeB implements MessageSender.SendToNodeB
@@ -43,9 +73,16 @@ func (m *messageSenderImpl) SendToNodeB(
 		return derror.ErrExecutorNotFoundForMessage.GenWithStackByArgs()
 	}
 
+	data, err := m.encode(targetNodeID, topic, message)
+	if err != nil {
+		return err
+	}
 	// TODO: blocking send in p2p library may have performance issue
-	_, err := client.SendMessage(ctx, topic, message)
-	return err
+	if _, err = client.SendMessage(ctx, topic, data); err != nil {
+		return err
+	}
+	observeWireSize(ctx, data)
+	return nil
 }
 
 func (m *messageSenderImpl) SendToNode(ctx context.Context, targetNodeID NodeID, topic Topic, message interface{}) (bool, error) {
@@ -54,13 +91,18 @@ func (m *messageSenderImpl) SendToNode(ctx context.Context, targetNodeID NodeID,
 		return false, nil
 	}
 
-	_, err := client.TrySendMessage(ctx, topic, message)
+	data, err := m.encode(targetNodeID, topic, message)
+	if err != nil {
+		return false, err
+	}
+	_, err = client.TrySendMessage(ctx, topic, data)
 	if err != nil {
 		if cerror.ErrPeerMessageSendTryAgain.Equal(err) {
 			return false, nil
 		}
 		return false, errors.Trace(err)
 	}
+	observeWireSize(ctx, data)
 	return true, nil
 }
 
@@ -0,0 +1,18 @@
+package p2p
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var expiredMessageCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "dataflow",
+		Subsystem: "p2p",
+		Name:      "expired_message_total",
+		Help:      "number of received messages dropped because they outlived the TTL of the topic",
+	}, []string{"topic"})
+
+// InitMetrics registers the p2p metrics
+func InitMetrics(registry *prometheus.Registry) {
+	registry.MustRegister(expiredMessageCounter)
+}
@@ -84,6 +84,13 @@ func (m *MockMessageHandlerManager) RegisterHandler(
 		return false, nil
 	}
 
+	if wireSizeObserver(ctx) != nil {
+		handlerFn := fn
+		fn = func(sender NodeID, value MessageValue) error {
+			observeMockWireSize(ctx, topic, value)
+			return handlerFn(sender, value)
+		}
+	}
 	m.handlers[topic] = fn
 	m.tpi[topic] = tpi
 	return true, nil
@@ -48,12 +48,13 @@ func (m *MockMessageSender) SendToNodeB(
 	// TODO Handle the `m.isBlocked == true` case
 	q := m.getQueue(targetNodeID, topic)
 	q.PushBack(message)
+	observeMockWireSize(ctx, topic, message)
 	return nil
 }
 
 // SendToNode implements pkg/p2p.MessageSender.SendToNode
 func (m *MockMessageSender) SendToNode(
-	_ context.Context,
+	ctx context.Context,
 	targetNodeID NodeID,
 	topic Topic,
 	message interface{},
@@ -73,10 +74,24 @@ func (m *MockMessageSender) SendToNode(
 
 	q := m.getQueue(targetNodeID, topic)
 	q.PushBack(message)
+	observeMockWireSize(ctx, topic, message)
 
 	return true, nil
 }
 
+// observeMockWireSize reports the size of message encoded without
+// compression, as the mocks don't know the codecs of peers.
+func observeMockWireSize(ctx context.Context, topic Topic, message interface{}) {
+	if wireSizeObserver(ctx) == nil {
+		return
+	}
+	data, err := marshalValue(message, topicEncoding(topic))
+	if err != nil {
+		return
+	}
+	observeWireSize(ctx, (*payload)(&data))
+}
+
 // TryPop tries to get a message from message sender
 func (m *MockMessageSender) TryPop(targetNodeID NodeID, topic Topic) (interface{}, bool) {
 	m.mu.Lock()
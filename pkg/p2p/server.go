@@ -56,6 +56,7 @@ var defaultServerConfig = Config{
 type MessageRPCService struct {
 	messageServer *p2pImpl.MessageServer
 	grpcServer    *grpc.Server
+	messageConfig *MessageConfig
 
 	noNeedToRunGRPCServer bool
 }
@@ -94,6 +95,7 @@ func NewMessageRPCServiceWithRPCServer(
 	return &MessageRPCService{
 		messageServer: messageServer,
 		grpcServer:    grpcSvr,
+		messageConfig: DefaultMessageConfig(),
 	}
 }
 
@@ -176,7 +178,13 @@ func (s *MessageRPCService) GetMessageServer() *p2pImpl.MessageServer {
 	return s.messageServer
 }
 
+// SetMessageConfig sets the config of the messages received by the handler
+// managers made afterwards.
+func (s *MessageRPCService) SetMessageConfig(cfg *MessageConfig) {
+	s.messageConfig = cfg
+}
+
 // MakeHandlerManager returns a MessageHandlerManager
 func (s *MessageRPCService) MakeHandlerManager() MessageHandlerManager {
-	return newMessageHandlerManager(s.messageServer)
+	return newMessageHandlerManager(s.messageServer, s.messageConfig)
 }
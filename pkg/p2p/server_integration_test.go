@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math"
 	"net"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -81,3 +82,47 @@ func TestMessageRPCServiceBasics(t *testing.T) {
 	cancel()
 	wg.Wait()
 }
+
+func TestMessageRPCServiceCompressed(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	l, addr := makeListenerForServerTests(t)
+	messageSrvc, err := NewMessageRPCService("test-node-1", &security.Credential{} /* no TLS */)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = messageSrvc.Serve(ctx, l)
+	}()
+
+	msg := &codecTestMessage{Data: strings.Repeat("compressed", 100*1024)}
+	received := make(chan MessageValue, 1)
+	handlerManager := messageSrvc.MakeHandlerManager()
+	ok, err := handlerManager.RegisterHandler(ctx, "test-topic-1", &codecTestMessage{}, func(sender NodeID, value MessageValue) error {
+		received <- value
+		return nil
+	})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	router := NewMessageRouter("test-client-1", "fake-addr:8300")
+	defer router.Close()
+	router.AddPeer("test-node-1", addr)
+	peers := NewPeerCodecs()
+	peers.Observe("test-node-1", SupportedCodecs())
+	sender := NewMessageSenderWithCodecs(router, DefaultMessageConfig(), peers)
+	err = sender.SendToNodeB(ctx, "test-node-1", "test-topic-1", msg)
+	require.NoError(t, err)
+	select {
+	case value := <-received:
+		require.Equal(t, msg, value)
+	case <-ctx.Done():
+		require.FailNow(t, "message is not received")
+	}
+
+	cancel()
+	wg.Wait()
+}
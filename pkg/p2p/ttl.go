@@ -0,0 +1,83 @@
+package p2p
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+)
+
+// TimedMessage is implemented by messages that carry the time they are sent.
+// A TimedMessage that is received later than the TTL of its topic is dropped
+// instead of being handled, see RegisterTopicTTL.
+type TimedMessage interface {
+	// MessageSendTime returns the wall-clock time when the message is sent.
+	// A zero time means the message never expires.
+	MessageSendTime() time.Time
+}
+
+var topicTTLs struct {
+	sync.RWMutex
+	ttls map[string]time.Duration
+}
+
+// RegisterTopicTTL sets the TTL of the messages of the topics that start with
+// topicPrefix. If several prefixes match a topic, the longest one is used.
+// Since the send time is set by the sender, the TTL should be much larger
+// than the clock skew between nodes. A non-positive ttl removes the TTL.
+func RegisterTopicTTL(topicPrefix string, ttl time.Duration) {
+	topicTTLs.Lock()
+	defer topicTTLs.Unlock()
+
+	if ttl <= 0 {
+		delete(topicTTLs.ttls, topicPrefix)
+		return
+	}
+	if topicTTLs.ttls == nil {
+		topicTTLs.ttls = make(map[string]time.Duration)
+	}
+	topicTTLs.ttls[topicPrefix] = ttl
+}
+
+// topicTTL returns the TTL of topic and the prefix it is registered with.
+func topicTTL(topic Topic) (prefix string, ttl time.Duration, ok bool) {
+	topicTTLs.RLock()
+	defer topicTTLs.RUnlock()
+
+	for p, t := range topicTTLs.ttls {
+		if strings.HasPrefix(topic, p) && len(p) >= len(prefix) {
+			prefix, ttl, ok = p, t, true
+		}
+	}
+	return
+}
+
+// isMessageExpired checks whether value has outlived the TTL of topic, the
+// expired messages are counted by the prefix of the topic.
+func isMessageExpired(topic Topic, value MessageValue, now time.Time) bool {
+	msg, ok := value.(TimedMessage)
+	if !ok {
+		return false
+	}
+	sendTime := msg.MessageSendTime()
+	if sendTime.IsZero() {
+		return false
+	}
+	prefix, ttl, ok := topicTTL(topic)
+	if !ok {
+		return false
+	}
+	age := now.Sub(sendTime)
+	if age <= ttl {
+		return false
+	}
+
+	expiredMessageCounter.WithLabelValues(prefix).Inc()
+	log.L().Warn("drop expired message",
+		zap.String("topic", topic),
+		zap.Duration("age", age),
+		zap.Duration("ttl", ttl))
+	return true
+}
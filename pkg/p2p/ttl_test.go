@@ -0,0 +1,36 @@
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type timedMsg struct {
+	sendTime time.Time
+}
+
+func (m *timedMsg) MessageSendTime() time.Time {
+	return m.sendTime
+}
+
+func TestIsMessageExpired(t *testing.T) {
+	RegisterTopicTTL("test-ttl-", time.Minute)
+	RegisterTopicTTL("test-ttl-short-", time.Second)
+	defer func() {
+		RegisterTopicTTL("test-ttl-", 0)
+		RegisterTopicTTL("test-ttl-short-", 0)
+	}()
+
+	now := time.Now()
+	msg := &timedMsg{sendTime: now.Add(-10 * time.Second)}
+	require.False(t, isMessageExpired("test-ttl-1", msg, now))
+	// the longest prefix is used
+	require.True(t, isMessageExpired("test-ttl-short-1", msg, now))
+	// topics without a TTL never expire
+	require.False(t, isMessageExpired("test-no-ttl", msg, now))
+	// messages without a send time never expire
+	require.False(t, isMessageExpired("test-ttl-short-1", &timedMsg{}, now))
+	require.False(t, isMessageExpired("test-ttl-short-1", &msgContent{}, now))
+}
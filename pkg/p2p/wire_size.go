@@ -0,0 +1,32 @@
+package p2p
+
+import (
+	"context"
+)
+
+// WireSizeObserver is told the size in bytes of each message on the wire,
+// that is after the message is encoded and compressed.
+type WireSizeObserver func(size int)
+
+type wireSizeObserverKey struct{}
+
+// WithWireSizeObserver returns a context with which the message senders
+// report the size of the messages sent to observer. A handler registered
+// with the context reports the size of the messages received on its topic.
+func WithWireSizeObserver(ctx context.Context, observer WireSizeObserver) context.Context {
+	return context.WithValue(ctx, wireSizeObserverKey{}, observer)
+}
+
+// wireSizeObserver returns the observer carried by ctx, or nil if there is
+// none.
+func wireSizeObserver(ctx context.Context) WireSizeObserver {
+	observer, _ := ctx.Value(wireSizeObserverKey{}).(WireSizeObserver)
+	return observer
+}
+
+// observeWireSize reports the size of data to the observer carried by ctx.
+func observeWireSize(ctx context.Context, data *payload) {
+	if observer := wireSizeObserver(ctx); observer != nil {
+		observer(len(*data))
+	}
+}
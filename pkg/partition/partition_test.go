@@ -0,0 +1,123 @@
+package partition
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+func TestRangePartitioner(t *testing.T) {
+	t.Parallel()
+
+	p, err := New(NewRangeSpec([]byte("b"), []byte("d")))
+	require.NoError(t, err)
+	require.Equal(t, 3, p.NumPartitions())
+	for key, expected := range map[string]int{
+		"":   0,
+		"a":  0,
+		"b":  1,
+		"c":  1,
+		"d":  2,
+		"zz": 2,
+	} {
+		idx, err := p.Partition([]byte(key))
+		require.NoError(t, err)
+		require.Equal(t, expected, idx, key)
+	}
+
+	p, err = New(NewRangeSpec())
+	require.NoError(t, err)
+	require.Equal(t, 1, p.NumPartitions())
+}
+
+func TestHashPartitioner(t *testing.T) {
+	t.Parallel()
+
+	p, err := New(NewHashSpec(4))
+	require.NoError(t, err)
+	require.Equal(t, 4, p.NumPartitions())
+	// the results are part of the spec format, they must not change
+	for key, expected := range map[string]int{
+		"":      1,
+		"a":     0,
+		"key-1": 2,
+		"key-2": 3,
+		"key-3": 0,
+	} {
+		idx, err := p.Partition([]byte(key))
+		require.NoError(t, err)
+		require.Equal(t, expected, idx, key)
+	}
+}
+
+func TestListPartitioner(t *testing.T) {
+	t.Parallel()
+
+	p, err := New(NewListSpec(
+		[][]byte{[]byte("a"), []byte("b")},
+		[][]byte{[]byte("c")},
+	))
+	require.NoError(t, err)
+	require.Equal(t, 2, p.NumPartitions())
+	idx, err := p.Partition([]byte("b"))
+	require.NoError(t, err)
+	require.Equal(t, 0, idx)
+	idx, err = p.Partition([]byte("c"))
+	require.NoError(t, err)
+	require.Equal(t, 1, idx)
+	_, err = p.Partition([]byte("d"))
+	require.True(t, errors.ErrPartitionKeyNotFound.Equal(err))
+}
+
+func TestValidateSpec(t *testing.T) {
+	t.Parallel()
+
+	invalidSpecs := []*Spec{
+		nil,
+		{Version: SpecVersion},
+		{Version: SpecVersion + 1, Kind: KindHash, Partitions: 1},
+		{Kind: KindHash, Partitions: 1},
+		NewHashSpec(0),
+		NewRangeSpec([]byte("b"), []byte("a")),
+		NewRangeSpec([]byte("a"), []byte("a")),
+		NewListSpec(),
+		NewListSpec([][]byte{[]byte("a")}, [][]byte{[]byte("a")}),
+		{Version: SpecVersion, Kind: KindList, Lists: []*List{nil}},
+	}
+	for i, spec := range invalidSpecs {
+		require.True(t, errors.ErrPartitionSpecInvalid.Equal(spec.Validate()), "case %d", i)
+		_, err := New(spec)
+		require.Error(t, err)
+	}
+}
+
+func TestEncodeDecodeSpec(t *testing.T) {
+	t.Parallel()
+
+	specs := []*Spec{
+		NewRangeSpec([]byte("b"), []byte("d")),
+		NewHashSpec(16),
+		NewListSpec([][]byte{[]byte("a"), []byte("b")}, [][]byte{[]byte("c")}),
+	}
+	for _, spec := range specs {
+		data, err := Encode(spec)
+		require.NoError(t, err)
+		decoded, err := Decode(data)
+		require.NoError(t, err)
+		require.Equal(t, spec, decoded)
+
+		data, err = json.Marshal(spec)
+		require.NoError(t, err)
+		decoded = &Spec{}
+		require.NoError(t, json.Unmarshal(data, decoded))
+		require.Equal(t, spec, decoded)
+	}
+
+	_, err := Encode(NewHashSpec(0))
+	require.True(t, errors.ErrPartitionSpecInvalid.Equal(err))
+	_, err = Decode([]byte{0xff})
+	require.True(t, errors.ErrPartitionSpecInvalid.Equal(err))
+}
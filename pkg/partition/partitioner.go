@@ -0,0 +1,94 @@
+package partition
+
+import (
+	"bytes"
+	"hash/fnv"
+	"sort"
+
+	"github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+// Partitioner maps keys to partitions. Partitioners created from equal specs
+// map a key to the same partition, no matter on which node or version (with
+// the same SpecVersion) they run.
+type Partitioner interface {
+	// Partition returns the index of the partition of key, in [0, NumPartitions)
+	Partition(key []byte) (int, error)
+	// NumPartitions returns the number of partitions
+	NumPartitions() int
+}
+
+// New creates a Partitioner from a spec
+func New(spec *Spec) (Partitioner, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	switch spec.Kind {
+	case KindRange:
+		return &rangePartitioner{bounds: spec.Bounds}, nil
+	case KindHash:
+		return &hashPartitioner{partitions: int(spec.Partitions)}, nil
+	case KindList:
+		p := &listPartitioner{
+			index:      make(map[string]int),
+			partitions: len(spec.Lists),
+		}
+		for i, list := range spec.Lists {
+			for _, key := range list.Keys {
+				p.index[string(key)] = i
+			}
+		}
+		return p, nil
+	}
+	// unreachable as the spec has been validated
+	return nil, errors.ErrPartitionSpecInvalid.GenWithStackByArgs(spec.Kind.String())
+}
+
+// rangePartitioner puts key into partition i if bounds[i-1] <= key < bounds[i]
+type rangePartitioner struct {
+	bounds [][]byte
+}
+
+func (p *rangePartitioner) Partition(key []byte) (int, error) {
+	return sort.Search(len(p.bounds), func(i int) bool {
+		return bytes.Compare(key, p.bounds[i]) < 0
+	}), nil
+}
+
+func (p *rangePartitioner) NumPartitions() int {
+	return len(p.bounds) + 1
+}
+
+// hashPartitioner uses 64-bit FNV-1a, which is part of the spec format and
+// must not be changed without bumping SpecVersion.
+type hashPartitioner struct {
+	partitions int
+}
+
+func (p *hashPartitioner) Partition(key []byte) (int, error) {
+	h := fnv.New64a()
+	// Write of hash.Hash never returns an error
+	_, _ = h.Write(key)
+	return int(h.Sum64() % uint64(p.partitions)), nil
+}
+
+func (p *hashPartitioner) NumPartitions() int {
+	return p.partitions
+}
+
+type listPartitioner struct {
+	index      map[string]int
+	partitions int
+}
+
+func (p *listPartitioner) Partition(key []byte) (int, error) {
+	idx, ok := p.index[string(key)]
+	if !ok {
+		return 0, errors.ErrPartitionKeyNotFound.GenWithStackByArgs(key)
+	}
+	return idx, nil
+}
+
+func (p *listPartitioner) NumPartitions() int {
+	return p.partitions
+}
@@ -0,0 +1,168 @@
+package partition
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+
+	"github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+// SpecVersion is the version of the partition spec format and of the way
+// specs are interpreted. It must be increased whenever a partitioner would
+// map a key to another partition, so that workers of an older version
+// refuse specs they may interpret differently.
+const SpecVersion int32 = 1
+
+// Kind is the kind of partitioner
+type Kind int32
+
+// Defines all partitioner kinds, the values are persisted, don't change them
+const (
+	KindUnknown Kind = 0
+	KindRange   Kind = 1
+	KindHash    Kind = 2
+	KindList    Kind = 3
+)
+
+// String implements fmt.Stringer
+func (k Kind) String() string {
+	switch k {
+	case KindRange:
+		return "range"
+	case KindHash:
+		return "hash"
+	case KindList:
+		return "list"
+	}
+	return fmt.Sprintf("unknown(%d)", int32(k))
+}
+
+// Spec defines how keys are partitioned. It is a protobuf message, so it can
+// be embedded in checkpoints and RPCs, and it can be encoded in json as well.
+type Spec struct {
+	Version int32 `protobuf:"varint,1,opt,name=version,proto3" json:"version" toml:"version"`
+	Kind    Kind  `protobuf:"varint,2,opt,name=kind,proto3" json:"kind" toml:"kind"`
+	// Partitions is the number of partitions of a hash partitioner
+	Partitions int32 `protobuf:"varint,3,opt,name=partitions,proto3" json:"partitions,omitempty" toml:"partitions"`
+	// Bounds are the strictly increasing exclusive upper bounds of all
+	// partitions but the last one of a range partitioner
+	Bounds [][]byte `protobuf:"bytes,4,rep,name=bounds,proto3" json:"bounds,omitempty" toml:"bounds"`
+	// Lists are the keys of each partition of a list partitioner
+	Lists []*List `protobuf:"bytes,5,rep,name=lists,proto3" json:"lists,omitempty" toml:"lists"`
+}
+
+// Reset implements proto.Message
+func (m *Spec) Reset() { *m = Spec{} }
+
+// String implements proto.Message
+func (m *Spec) String() string { return proto.CompactTextString(m) }
+
+// ProtoMessage implements proto.Message
+func (*Spec) ProtoMessage() {}
+
+// List is the keys of a partition of a list partitioner
+type List struct {
+	Keys [][]byte `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys" toml:"keys"`
+}
+
+// Reset implements proto.Message
+func (m *List) Reset() { *m = List{} }
+
+// String implements proto.Message
+func (m *List) String() string { return proto.CompactTextString(m) }
+
+// ProtoMessage implements proto.Message
+func (*List) ProtoMessage() {}
+
+// NewRangeSpec creates a spec of range partitioner, there are
+// len(bounds)+1 partitions
+func NewRangeSpec(bounds ...[]byte) *Spec {
+	return &Spec{Version: SpecVersion, Kind: KindRange, Bounds: bounds}
+}
+
+// NewHashSpec creates a spec of hash partitioner
+func NewHashSpec(partitions int) *Spec {
+	return &Spec{Version: SpecVersion, Kind: KindHash, Partitions: int32(partitions)}
+}
+
+// NewListSpec creates a spec of list partitioner, each list is the keys of
+// one partition
+func NewListSpec(lists ...[][]byte) *Spec {
+	spec := &Spec{Version: SpecVersion, Kind: KindList}
+	for _, keys := range lists {
+		spec.Lists = append(spec.Lists, &List{Keys: keys})
+	}
+	return spec
+}
+
+// Validate checks whether the spec can be interpreted by this version
+func (m *Spec) Validate() error {
+	if m == nil {
+		return errors.ErrPartitionSpecInvalid.GenWithStackByArgs("spec is nil")
+	}
+	if m.Version <= 0 || m.Version > SpecVersion {
+		return errors.ErrPartitionSpecInvalid.GenWithStackByArgs(
+			fmt.Sprintf("unsupported version %d, supported version %d", m.Version, SpecVersion))
+	}
+
+	switch m.Kind {
+	case KindRange:
+		for i := 1; i < len(m.Bounds); i++ {
+			if bytes.Compare(m.Bounds[i-1], m.Bounds[i]) >= 0 {
+				return errors.ErrPartitionSpecInvalid.GenWithStackByArgs(
+					fmt.Sprintf("range bounds are not strictly increasing at %d", i))
+			}
+		}
+	case KindHash:
+		if m.Partitions <= 0 {
+			return errors.ErrPartitionSpecInvalid.GenWithStackByArgs(
+				fmt.Sprintf("hash partitions %d is not positive", m.Partitions))
+		}
+	case KindList:
+		if len(m.Lists) == 0 {
+			return errors.ErrPartitionSpecInvalid.GenWithStackByArgs("no list is given")
+		}
+		seen := make(map[string]int)
+		for i, list := range m.Lists {
+			if list == nil {
+				return errors.ErrPartitionSpecInvalid.GenWithStackByArgs(fmt.Sprintf("list %d is nil", i))
+			}
+			for _, key := range list.Keys {
+				if j, ok := seen[string(key)]; ok {
+					return errors.ErrPartitionSpecInvalid.GenWithStackByArgs(
+						fmt.Sprintf("key %q is in both list %d and list %d", key, j, i))
+				}
+				seen[string(key)] = i
+			}
+		}
+	default:
+		return errors.ErrPartitionSpecInvalid.GenWithStackByArgs(fmt.Sprintf("unknown kind %s", m.Kind))
+	}
+	return nil
+}
+
+// Encode encodes a spec in protobuf format
+func Encode(spec *Spec) ([]byte, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	data, err := proto.Marshal(spec)
+	if err != nil {
+		return nil, errors.ErrPartitionSpecInvalid.GenWithStackByArgs(fmt.Sprintf("marshal fail: %v", err))
+	}
+	return data, nil
+}
+
+// Decode decodes a spec encoded by Encode, and validates it
+func Decode(data []byte) (*Spec, error) {
+	spec := &Spec{}
+	if err := proto.Unmarshal(data, spec); err != nil {
+		return nil, errors.ErrPartitionSpecInvalid.GenWithStackByArgs(fmt.Sprintf("unmarshal fail: %v", err))
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
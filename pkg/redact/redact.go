@@ -0,0 +1,207 @@
+package redact
+
+import (
+	"reflect"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// Mask replaces the sensitive values
+const Mask = "******"
+
+// tagName is the struct tag marking a field as sensitive, a field tagged
+// with `redact:"true"` is sensitive.
+const tagName = "redact"
+
+// Redactor is implemented by the types redacting themselves, such as the
+// types whose sensitive fields are defined in other modules and can't be
+// tagged.
+type Redactor interface {
+	// Redacted returns a copy with the sensitive values replaced, it should
+	// be of the same type as the receiver.
+	Redacted() interface{}
+}
+
+var redactorType = reflect.TypeOf((*Redactor)(nil)).Elem()
+
+// Value returns a copy of v with the sensitive values replaced, so that v
+// can be logged or returned by the APIs. A sensitive string or []byte is
+// replaced by Mask, and other sensitive values are zeroed, a zero value is
+// kept as is to tell it is not set. The sensitive values are looked up
+// through pointers, slices, maps and exported struct fields, v must not
+// contain pointer cycles. v is returned as is if its type can't carry any
+// sensitive value.
+func Value(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	if !sensitive(rv.Type()) {
+		return v
+	}
+	return redactValue(rv).Interface()
+}
+
+// Any constructs a zap field of the redacted value, it should be used in
+// place of zap.Any for the values carrying credentials, such as configs.
+func Any(key string, v interface{}) zap.Field {
+	return zap.Any(key, Value(v))
+}
+
+// sensitiveTypes caches whether a type can carry sensitive values
+var sensitiveTypes sync.Map
+
+func sensitive(t reflect.Type) bool {
+	if ret, ok := sensitiveTypes.Load(t); ok {
+		return ret.(bool)
+	}
+	ret := inspect(t, make(map[reflect.Type]struct{}))
+	sensitiveTypes.Store(t, ret)
+	return ret
+}
+
+func inspect(t reflect.Type, visiting map[reflect.Type]struct{}) bool {
+	if t.Implements(redactorType) || reflect.PtrTo(t).Implements(redactorType) {
+		return true
+	}
+	// a recursive type is inspected once, the rest of its fields are
+	// inspected by the outer call
+	if _, ok := visiting[t]; ok {
+		return false
+	}
+	visiting[t] = struct{}{}
+	defer delete(visiting, t)
+
+	switch t.Kind() {
+	case reflect.Interface:
+		// the dynamic value is inspected on redacting
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return inspect(t.Elem(), visiting)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if isSensitiveField(field) || inspect(field.Type, visiting) {
+				return true
+			}
+		}
+	default:
+	}
+	return false
+}
+
+func isSensitiveField(field reflect.StructField) bool {
+	return field.Tag.Get(tagName) == "true"
+}
+
+func redactValue(v reflect.Value) reflect.Value {
+	t := v.Type()
+	if !sensitive(t) {
+		return v
+	}
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+	default:
+	}
+	if redacted, ok := redactByRedactor(v); ok {
+		return redacted
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		ret := reflect.New(t.Elem())
+		ret.Elem().Set(redactValue(v.Elem()))
+		return ret
+	case reflect.Interface:
+		ret := reflect.New(t).Elem()
+		ret.Set(redactValue(v.Elem()))
+		return ret
+	case reflect.Slice:
+		ret := reflect.MakeSlice(t, v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			ret.Index(i).Set(redactValue(v.Index(i)))
+		}
+		return ret
+	case reflect.Array:
+		ret := reflect.New(t).Elem()
+		for i := 0; i < v.Len(); i++ {
+			ret.Index(i).Set(redactValue(v.Index(i)))
+		}
+		return ret
+	case reflect.Map:
+		ret := reflect.MakeMapWithSize(t, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			ret.SetMapIndex(iter.Key(), redactValue(iter.Value()))
+		}
+		return ret
+	case reflect.Struct:
+		// the unexported fields are copied as is
+		ret := reflect.New(t).Elem()
+		ret.Set(v)
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if isSensitiveField(field) {
+				ret.Field(i).Set(mask(v.Field(i)))
+			} else {
+				ret.Field(i).Set(redactValue(v.Field(i)))
+			}
+		}
+		return ret
+	default:
+	}
+	return v
+}
+
+// redactByRedactor redacts v by its Redacted method, a result of another
+// type is dropped as it can't replace v.
+func redactByRedactor(v reflect.Value) (reflect.Value, bool) {
+	t := v.Type()
+	var redactor Redactor
+	switch {
+	case t.Implements(redactorType):
+		redactor = v.Interface().(Redactor)
+	case reflect.PtrTo(t).Implements(redactorType):
+		ptr := reflect.New(t)
+		ptr.Elem().Set(v)
+		redactor = ptr.Interface().(Redactor)
+	default:
+		return reflect.Value{}, false
+	}
+
+	redacted := reflect.ValueOf(redactor.Redacted())
+	switch {
+	case !redacted.IsValid():
+	case redacted.Type() == t:
+		return redacted, true
+	case redacted.Kind() == reflect.Ptr && redacted.Type().Elem() == t && !redacted.IsNil():
+		return redacted.Elem(), true
+	default:
+	}
+	return reflect.Zero(t), true
+}
+
+func mask(v reflect.Value) reflect.Value {
+	t := v.Type()
+	if v.IsZero() {
+		return v
+	}
+	switch {
+	case t.Kind() == reflect.String:
+		return reflect.ValueOf(Mask).Convert(t)
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		return reflect.ValueOf([]byte(Mask)).Convert(t)
+	default:
+	}
+	return reflect.Zero(t)
+}
@@ -0,0 +1,77 @@
+package redact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type authConfig struct {
+	User     string `json:"user"`
+	Password string `json:"password" redact:"true"`
+	Token    []byte `json:"token" redact:"true"`
+	Port     int    `json:"port" redact:"true"`
+}
+
+type selfRedacted struct {
+	Key string
+}
+
+func (s *selfRedacted) Redacted() interface{} {
+	return &selfRedacted{Key: "redacted"}
+}
+
+type jobConfig struct {
+	Name      string
+	Auth      *authConfig
+	Upstreams []authConfig
+	Sinks     map[string]*authConfig
+	Extra     interface{}
+	Custom    selfRedacted
+	secret    string
+}
+
+type plainConfig struct {
+	Name  string
+	Hosts []string
+}
+
+func TestValue(t *testing.T) {
+	t.Parallel()
+
+	cfg := &jobConfig{
+		Name:      "job",
+		Auth:      &authConfig{User: "root", Password: "123", Token: []byte("abc"), Port: 3306},
+		Upstreams: []authConfig{{User: "u1", Password: "p1"}, {User: "u2"}},
+		Sinks:     map[string]*authConfig{"s1": {Password: "p2"}, "s2": nil},
+		Extra:     authConfig{Password: "p3"},
+		Custom:    selfRedacted{Key: "key"},
+		secret:    "kept",
+	}
+	redacted := Value(cfg).(*jobConfig)
+	require.Equal(t, &jobConfig{
+		Name:      "job",
+		Auth:      &authConfig{User: "root", Password: Mask, Token: []byte(Mask)},
+		Upstreams: []authConfig{{User: "u1", Password: Mask}, {User: "u2"}},
+		Sinks:     map[string]*authConfig{"s1": {Password: Mask}, "s2": nil},
+		Extra:     authConfig{Password: Mask},
+		Custom:    selfRedacted{Key: "redacted"},
+		secret:    "kept",
+	}, redacted)
+
+	// the original value is not modified
+	require.Equal(t, "123", cfg.Auth.Password)
+	require.Equal(t, "p1", cfg.Upstreams[0].Password)
+	require.Equal(t, "p2", cfg.Sinks["s1"].Password)
+	require.Equal(t, "key", cfg.Custom.Key)
+
+	require.Equal(t, authConfig{User: "root", Password: Mask}, Value(authConfig{User: "root", Password: "123"}))
+	require.Equal(t, &selfRedacted{Key: "redacted"}, Value(&selfRedacted{Key: "key"}))
+	require.Nil(t, Value(nil))
+	require.Equal(t, (*authConfig)(nil), Value((*authConfig)(nil)))
+
+	// a value that can't carry sensitive values is returned as is
+	plain := &plainConfig{Name: "plain"}
+	require.Same(t, plain, Value(plain))
+	require.Equal(t, "value", Value("value"))
+}
@@ -4,11 +4,25 @@ import (
 	"context"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/hanfei1991/microcosm/pb"
 	"github.com/hanfei1991/microcosm/pkg/errors"
+	perrors "github.com/pingcap/errors"
 	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/atomic"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultRetryBackoff    = 100 * time.Millisecond
+	defaultMaxRetryBackoff = 2 * time.Second
+	defaultMaxRetries      = 3
 )
 
 // CloseableConnIface defines an interface that supports Close(release resource)
@@ -27,6 +41,46 @@ type DialFunc[T FailoverRPCClientType] func(ctx context.Context, addr string) (T
 type clientHolder[T FailoverRPCClientType] struct {
 	conn   CloseableConnIface
 	client T
+	// unhealthy is set when the last RPC to the server failed in transport,
+	// and is reset after a RPC succeeds.
+	unhealthy atomic.Bool
+}
+
+// connStateGetter is implemented by *grpc.ClientConn.
+type connStateGetter interface {
+	GetState() connectivity.State
+}
+
+func (h *clientHolder[T]) healthy() bool {
+	if h.unhealthy.Load() {
+		return false
+	}
+	if conn, ok := h.conn.(connStateGetter); ok {
+		state := conn.GetState()
+		return state != connectivity.TransientFailure && state != connectivity.Shutdown
+	}
+	return true
+}
+
+// FailoverOption configures a FailoverRPCClients.
+type FailoverOption func(*failoverOptions)
+
+type failoverOptions struct {
+	retryBackoff    time.Duration
+	maxRetryBackoff time.Duration
+	maxRetries      int
+}
+
+// WithRetryBackoff configures the retry of DoFailoverRPC. A RPC failed on all
+// servers with a retryable error, such as the server is unavailable or the
+// leader is not ready, is retried at most maxRetries times, the interval
+// begins with backoff and doubles each time until maxBackoff.
+func WithRetryBackoff(backoff, maxBackoff time.Duration, maxRetries int) FailoverOption {
+	return func(opts *failoverOptions) {
+		opts.retryBackoff = backoff
+		opts.maxRetryBackoff = maxBackoff
+		opts.maxRetries = maxRetries
+	}
 }
 
 // FailoverRPCClients represent RPC on this type of client can use any client
@@ -36,6 +90,7 @@ type FailoverRPCClients[T FailoverRPCClientType] struct {
 	clientsLock sync.RWMutex
 	clients     map[string]*clientHolder[T] // addr -> clientHolder
 	dialer      DialFunc[T]
+	opts        failoverOptions
 }
 
 // NewFailoverRPCClients creates a FailoverRPCClients and initializes it
@@ -43,15 +98,28 @@ func NewFailoverRPCClients[T FailoverRPCClientType](
 	ctx context.Context,
 	urls []string,
 	dialer DialFunc[T],
+	opts ...FailoverOption,
 ) (*FailoverRPCClients[T], error) {
 	ret := &FailoverRPCClients[T]{
 		clients: make(map[string]*clientHolder[T]),
 		dialer:  dialer,
+		opts:    defaultFailoverOptions(),
+	}
+	for _, opt := range opts {
+		opt(&ret.opts)
 	}
 	err := ret.init(ctx, urls)
 	return ret, err
 }
 
+func defaultFailoverOptions() failoverOptions {
+	return failoverOptions{
+		retryBackoff:    defaultRetryBackoff,
+		maxRetryBackoff: defaultMaxRetryBackoff,
+		maxRetries:      defaultMaxRetries,
+	}
+}
+
 func (c *FailoverRPCClients[T]) init(ctx context.Context, urls []string) error {
 	// leader will be updated on heartbeat
 	c.UpdateClients(ctx, urls, "")
@@ -157,7 +225,53 @@ func (c *FailoverRPCClients[T]) GetLeaderClient() T {
 	return leader.client
 }
 
-// DoFailoverRPC calls RPC on given clients one by one until one succeeds.
+// followLeader switches the leader to the given address, which is told by
+// a server that is not the leader. The address is ignored if it is not one
+// of the clients, it will be added by UpdateClients later.
+func (c *FailoverRPCClients[T]) followLeader(addr string) {
+	c.clientsLock.Lock()
+	defer c.clientsLock.Unlock()
+
+	if addr == c.leader {
+		return
+	}
+	if _, ok := c.clients[addr]; ok {
+		log.L().Info("follow leader redirect", zap.String("old", c.leader), zap.String("leader", addr))
+		c.leader = addr
+	}
+}
+
+// orderedClients returns the clients in the order they should be tried, that
+// is, the leader, the healthy servers and then the unhealthy servers.
+func (c *FailoverRPCClients[T]) orderedClients() []*clientHolder[T] {
+	c.clientsLock.RLock()
+	defer c.clientsLock.RUnlock()
+
+	ret := make([]*clientHolder[T], 0, len(c.clients))
+	var unhealthy []*clientHolder[T]
+	leader, ok := c.clients[c.leader]
+	leaderHealthy := ok && leader.healthy()
+	if leaderHealthy {
+		ret = append(ret, leader)
+	}
+	for addr, cli := range c.clients {
+		if addr == c.leader && leaderHealthy {
+			continue
+		}
+		if cli.healthy() {
+			ret = append(ret, cli)
+		} else {
+			unhealthy = append(unhealthy, cli)
+		}
+	}
+	return append(ret, unhealthy...)
+}
+
+// DoFailoverRPC calls RPC on given clients one by one until one succeeds,
+// the leader is tried first. If the RPC fails on all clients with retryable
+// errors, it is retried with exponential backoff until the context is done.
+// An idempotency key is attached to the RPC and kept unchanged in retries,
+// so that the server can deduplicate the retried requests.
 // It should be a method of FailoverRPCClients, but golang can't let us do it, so
 // we use a public function.
 func DoFailoverRPC[
@@ -171,21 +285,101 @@ func DoFailoverRPC[
 	req Req,
 	rpc F,
 ) (resp Resp, err error) {
-	clients.clientsLock.RLock()
-	defer clients.clientsLock.RUnlock()
+	ctx = withIdempotencyKey(ctx)
+	backoff := clients.opts.retryBackoff
+	for retry := 0; ; retry++ {
+		var retryable bool
+		resp, err, retryable = doFailoverRPCOnce(ctx, clients, req, rpc)
+		if !retryable || retry >= clients.opts.maxRetries {
+			return resp, err
+		}
+		log.L().Warn("rpc failed on all servers, retry later",
+			zap.Duration("backoff", backoff), zap.Int("retry", retry), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return resp, err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > clients.opts.maxRetryBackoff {
+			backoff = clients.opts.maxRetryBackoff
+		}
+	}
+}
 
-	if len(clients.clients) == 0 {
-		return resp, errors.ErrNoRPCClient.GenWithStack("rpc: %#v, request: %#v", rpc, req)
+func doFailoverRPCOnce[
+	C FailoverRPCClientType,
+	Req any,
+	Resp any,
+	F func(C, context.Context, Req, ...grpc.CallOption) (Resp, error),
+](
+	ctx context.Context,
+	clients *FailoverRPCClients[C],
+	req Req,
+	rpc F,
+) (resp Resp, err error, retryable bool) {
+	ordered := clients.orderedClients()
+	if len(ordered) == 0 {
+		return resp, errors.ErrNoRPCClient.GenWithStack("rpc: %#v, request: %#v", rpc, req), false
 	}
 
-	for _, cli := range clients.clients {
-		resp, err = rpc(cli.client, ctx, req)
+	for _, cli := range ordered {
+		var header metadata.MD
+		resp, err = rpc(cli.client, ctx, req, grpc.Header(&header))
+		if leader := header.Get(LeaderAddrHeader); len(leader) > 0 && leader[0] != "" {
+			clients.followLeader(leader[0])
+		}
 		if err == nil {
-			return resp, nil
+			cli.unhealthy.Store(false)
+			if !isMasterNotReady(resp) {
+				return resp, nil, false
+			}
+			// the response is returned if all servers are not ready
+			retryable = true
+			continue
+		}
+		if isTransportError(err) {
+			cli.unhealthy.Store(true)
+		}
+		if isRetryableError(err) {
+			retryable = true
 		}
 	}
 	// return the last error
-	return resp, err
+	return resp, err, retryable
+}
+
+type errorResponse interface {
+	GetErr() *pb.Error
+}
+
+func isMasterNotReady(resp any) bool {
+	if r, ok := resp.(errorResponse); ok {
+		return r.GetErr().GetCode() == pb.ErrorCode_MasterNotReady
+	}
+	return false
+}
+
+func isTransportError(err error) bool {
+	return status.Code(perrors.Cause(err)) == codes.Unavailable
+}
+
+// isRetryableError returns whether the RPC may succeed later, such as the
+// server is unavailable, or the leader is being elected.
+func isRetryableError(err error) bool {
+	if isTransportError(err) {
+		return true
+	}
+	// errors from the server are converted to gRPC status with the message
+	for _, rfcErr := range []*perrors.Error{
+		errors.ErrMasterRPCNotForward,
+		errors.ErrMasterNotInitialized,
+	} {
+		if rfcErr.Equal(err) || strings.Contains(err.Error(), string(rfcErr.RFCCode())) {
+			return true
+		}
+	}
+	return false
 }
 
 // NewFailoverRPCClientsForTest creates a FailoverRPCClients for test
@@ -193,6 +387,7 @@ func NewFailoverRPCClientsForTest[T FailoverRPCClientType](
 	client T,
 ) *FailoverRPCClients[T] {
 	return &FailoverRPCClients[T]{
+		opts:   defaultFailoverOptions(),
 		leader: "leader",
 		clients: map[string]*clientHolder[T]{
 			"leader": {
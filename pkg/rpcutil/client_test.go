@@ -3,11 +3,15 @@ package rpcutil
 import (
 	"context"
 	"testing"
+	"time"
 
 	derror "github.com/hanfei1991/microcosm/pkg/errors"
 	"github.com/pingcap/errors"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 type (
@@ -110,3 +114,102 @@ func TestFailToDialLeaderAfterwards(t *testing.T) {
 	require.ErrorIs(t, err, derror.ErrNoRPCClient)
 	t.Log(err.Error())
 }
+
+type mockFailoverClient struct {
+	addr   string
+	leader string
+	// fails is the number of the RPCs that fail before succeeding
+	fails int
+	cnt   int
+	keys  []string
+}
+
+func (c *mockFailoverClient) MockRPC(ctx context.Context, req *Request, opts ...grpc.CallOption) (*Response, error) {
+	c.cnt++
+	md, _ := metadata.FromOutgoingContext(ctx)
+	c.keys = append(c.keys, md.Get(IdempotencyKeyHeader)...)
+	if c.leader != "" {
+		for _, opt := range opts {
+			if header, ok := opt.(grpc.HeaderCallOption); ok {
+				*header.HeaderAddr = metadata.Pairs(LeaderAddrHeader, c.leader)
+			}
+		}
+	}
+	if c.fails > 0 {
+		c.fails--
+		return nil, status.Error(codes.Unavailable, "mock unavailable")
+	}
+	return &Response{}, nil
+}
+
+func TestFailoverRPCRetry(t *testing.T) {
+	ctx := context.Background()
+	cli := &mockFailoverClient{addr: "url1", fails: 2}
+	dial := func(context.Context, string) (*mockFailoverClient, CloseableConnIface, error) {
+		return cli, &closer{}, nil
+	}
+	clients, err := NewFailoverRPCClients(ctx, []string{"url1"}, dial,
+		WithRetryBackoff(time.Millisecond, 2*time.Millisecond, 3))
+	require.NoError(t, err)
+
+	resp, err := DoFailoverRPC(ctx, clients, req, (*mockFailoverClient).MockRPC)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	require.Equal(t, 3, cli.cnt)
+	// the retries share the idempotency key
+	require.Len(t, cli.keys, 3)
+	require.NotEmpty(t, cli.keys[0])
+	require.Equal(t, cli.keys[0], cli.keys[1])
+	require.Equal(t, cli.keys[0], cli.keys[2])
+	require.False(t, clients.clients["url1"].unhealthy.Load())
+
+	// gives up after max retries
+	cli.cnt, cli.fails = 0, 10
+	_, err = DoFailoverRPC(ctx, clients, req, (*mockFailoverClient).MockRPC)
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	require.Equal(t, 4, cli.cnt)
+	require.True(t, clients.clients["url1"].unhealthy.Load())
+
+	// an idempotency key of the caller is kept
+	cli.fails, cli.keys = 0, nil
+	keyCtx := metadata.AppendToOutgoingContext(ctx, IdempotencyKeyHeader, "key-1")
+	_, err = DoFailoverRPC(keyCtx, clients, req, (*mockFailoverClient).MockRPC)
+	require.NoError(t, err)
+	require.Equal(t, []string{"key-1"}, cli.keys)
+}
+
+func TestFailoverRPCFollowLeader(t *testing.T) {
+	ctx := context.Background()
+	clis := map[string]*mockFailoverClient{
+		"url1": {addr: "url1", leader: "url2"},
+		"url2": {addr: "url2", leader: "url2"},
+		"url3": {addr: "url3", fails: 100},
+	}
+	dial := func(_ context.Context, addr string) (*mockFailoverClient, CloseableConnIface, error) {
+		return clis[addr], &closer{}, nil
+	}
+	clients, err := NewFailoverRPCClients(ctx, []string{"url1", "url2", "url3"}, dial,
+		WithRetryBackoff(time.Millisecond, time.Millisecond, 0))
+	require.NoError(t, err)
+	clients.UpdateClients(ctx, []string{"url1", "url2", "url3"}, "url1")
+
+	_, err = DoFailoverRPC(ctx, clients, req, (*mockFailoverClient).MockRPC)
+	require.NoError(t, err)
+	require.Equal(t, 1, clis["url1"].cnt)
+	require.Equal(t, "url2", clients.GetLeaderClient().addr)
+
+	// the leader is tried first
+	_, err = DoFailoverRPC(ctx, clients, req, (*mockFailoverClient).MockRPC)
+	require.NoError(t, err)
+	require.Equal(t, 1, clis["url1"].cnt)
+	require.Equal(t, 1, clis["url2"].cnt)
+
+	// unhealthy servers are tried last
+	clients.clients["url3"].unhealthy.Store(true)
+	clients.clients["url2"].unhealthy.Store(true)
+	ordered := clients.orderedClients()
+	require.Len(t, ordered, 3)
+	require.Equal(t, "url1", ordered[0].client.addr)
+	require.True(t, ordered[1].unhealthy.Load())
+	require.True(t, ordered[2].unhealthy.Load())
+}
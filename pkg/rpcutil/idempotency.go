@@ -0,0 +1,160 @@
+package rpcutil
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// IdempotencyKeyHeader is the gRPC metadata key of the idempotency key,
+	// which identifies a request among its retries.
+	IdempotencyKeyHeader = "x-idempotency-key"
+	// LeaderAddrHeader is the gRPC header key with which a server that is not
+	// the leader tells the client the address of the leader.
+	LeaderAddrHeader = "x-leader-addr"
+)
+
+func newIdempotencyKey() string {
+	var buf [16]byte
+	// crypto/rand.Read doesn't fail on supported platforms
+	_, _ = rand.Read(buf[:])
+	return hex.EncodeToString(buf[:])
+}
+
+// withIdempotencyKey attaches an idempotency key to the outgoing context if
+// it doesn't have one.
+func withIdempotencyKey(ctx context.Context) context.Context {
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get(IdempotencyKeyHeader)) > 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, IdempotencyKeyHeader, newIdempotencyKey())
+}
+
+// idempotencyKeyFromIncoming returns the idempotency key of the incoming
+// request, it is empty if the client doesn't attach one.
+func idempotencyKeyFromIncoming(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	keys := md.Get(IdempotencyKeyHeader)
+	if len(keys) == 0 {
+		return ""
+	}
+	return keys[0]
+}
+
+// forwardIdempotencyKey keeps the idempotency key of the incoming request
+// when the request is forwarded to the leader.
+func forwardIdempotencyKey(ctx context.Context) context.Context {
+	key := idempotencyKeyFromIncoming(ctx)
+	if key == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, IdempotencyKeyHeader, key)
+}
+
+// setLeaderHeader tells the client the address of the leader, the error is
+// ignored since the context may not come from a gRPC server, such as in tests.
+func setLeaderHeader(ctx context.Context, addr string) {
+	if addr == "" {
+		return
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(LeaderAddrHeader, addr))
+}
+
+// IdempotencyCache deduplicates the requests that have the same idempotency
+// key, which are usually retried by DoFailoverRPC after the response is lost.
+// The successful results are kept for a while, a retried request gets the
+// result of the first request instead of being executed again.
+type IdempotencyCache struct {
+	ttl time.Duration
+
+	mu        sync.Mutex
+	calls     map[string]*idempotentCall
+	lastSweep time.Time
+}
+
+type idempotentCall struct {
+	done     chan struct{}
+	resp     interface{}
+	err      error
+	expireAt time.Time
+}
+
+// NewIdempotencyCache creates a new IdempotencyCache, results are kept for ttl.
+func NewIdempotencyCache(ttl time.Duration) *IdempotencyCache {
+	return &IdempotencyCache{
+		ttl:       ttl,
+		calls:     make(map[string]*idempotentCall),
+		lastSweep: time.Now(),
+	}
+}
+
+// Do executes fn for the request of the method unless a request with the same
+// idempotency key has been executed, in which case the former result is
+// returned. A request in flight is waited for. A request without idempotency
+// key, or a nil IdempotencyCache, always executes fn. A failed request is not
+// cached so that it can be retried.
+func (c *IdempotencyCache) Do(
+	ctx context.Context,
+	method string,
+	fn func() (interface{}, error),
+) (interface{}, error) {
+	key := idempotencyKeyFromIncoming(ctx)
+	if c == nil || key == "" {
+		return fn()
+	}
+	key = method + "/" + key
+
+	c.mu.Lock()
+	now := time.Now()
+	c.sweepLocked(now)
+	if call, ok := c.calls[key]; ok && (call.expireAt.IsZero() || now.Before(call.expireAt)) {
+		c.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-call.done:
+		}
+		if call.err == nil {
+			return call.resp, nil
+		}
+		// the former request failed and has been removed, execute again
+		return c.Do(ctx, method, fn)
+	}
+	call := &idempotentCall{done: make(chan struct{})}
+	c.calls[key] = call
+	c.mu.Unlock()
+
+	call.resp, call.err = fn()
+
+	c.mu.Lock()
+	if call.err != nil {
+		delete(c.calls, key)
+	} else {
+		call.expireAt = time.Now().Add(c.ttl)
+	}
+	c.mu.Unlock()
+	close(call.done)
+	return call.resp, call.err
+}
+
+// sweepLocked removes the expired results, at most once a ttl.
+func (c *IdempotencyCache) sweepLocked(now time.Time) {
+	if now.Sub(c.lastSweep) < c.ttl {
+		return
+	}
+	c.lastSweep = now
+	for key, call := range c.calls {
+		if !call.expireAt.IsZero() && now.After(call.expireAt) {
+			delete(c.calls, key)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package rpcutil
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestIdempotencyCache(t *testing.T) {
+	t.Parallel()
+
+	cache := NewIdempotencyCache(time.Minute)
+	cnt := 0
+	fn := func() (interface{}, error) {
+		cnt++
+		return cnt, nil
+	}
+	ctx := metadata.NewIncomingContext(context.Background(),
+		metadata.Pairs(IdempotencyKeyHeader, "key-1"))
+
+	resp, err := cache.Do(ctx, "SubmitJob", fn)
+	require.NoError(t, err)
+	require.Equal(t, 1, resp)
+	// the retried request gets the former result
+	resp, err = cache.Do(ctx, "SubmitJob", fn)
+	require.NoError(t, err)
+	require.Equal(t, 1, resp)
+	// the same key of another method is executed
+	resp, err = cache.Do(ctx, "CreateJobSchedule", fn)
+	require.NoError(t, err)
+	require.Equal(t, 2, resp)
+	// requests without keys are always executed
+	resp, err = cache.Do(context.Background(), "SubmitJob", fn)
+	require.NoError(t, err)
+	require.Equal(t, 3, resp)
+	var nilCache *IdempotencyCache
+	resp, err = nilCache.Do(ctx, "SubmitJob", fn)
+	require.NoError(t, err)
+	require.Equal(t, 4, resp)
+
+	// failed requests are not cached
+	ctx2 := metadata.NewIncomingContext(context.Background(),
+		metadata.Pairs(IdempotencyKeyHeader, "key-2"))
+	_, err = cache.Do(ctx2, "SubmitJob", func() (interface{}, error) {
+		return nil, errors.New("mock fail")
+	})
+	require.Error(t, err)
+	resp, err = cache.Do(ctx2, "SubmitJob", fn)
+	require.NoError(t, err)
+	require.Equal(t, 5, resp)
+}
+
+func TestIdempotencyCacheConcurrent(t *testing.T) {
+	t.Parallel()
+
+	cache := NewIdempotencyCache(time.Minute)
+	ctx := metadata.NewIncomingContext(context.Background(),
+		metadata.Pairs(IdempotencyKeyHeader, "key-1"))
+	var (
+		mu  sync.Mutex
+		cnt int
+		wg  sync.WaitGroup
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := cache.Do(ctx, "SubmitJob", func() (interface{}, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				cnt++
+				time.Sleep(10 * time.Millisecond)
+				return "job-1", nil
+			})
+			require.NoError(t, err)
+			require.Equal(t, "job-1", resp)
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, 1, cnt)
+}
+
+func TestForwardIdempotencyKey(t *testing.T) {
+	t.Parallel()
+
+	ctx := metadata.NewIncomingContext(context.Background(),
+		metadata.Pairs(IdempotencyKeyHeader, "key-1"))
+	md, ok := metadata.FromOutgoingContext(forwardIdempotencyKey(ctx))
+	require.True(t, ok)
+	require.Equal(t, []string{"key-1"}, md.Get(IdempotencyKeyHeader))
+
+	ctx = forwardIdempotencyKey(context.Background())
+	_, ok = metadata.FromOutgoingContext(ctx)
+	require.False(t, ok)
+}
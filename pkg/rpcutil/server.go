@@ -16,6 +16,7 @@ import (
 
 	"github.com/hanfei1991/microcosm/pb"
 	"github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/redact"
 )
 
 // Member stores server member information
@@ -139,10 +140,27 @@ func (h PreRPCHook[T]) PreRPC(
 	return
 }
 
+// PreStream checks the server before serving a stream, streams are not
+// forwarded to the leader, the client should connect to the leader told by
+// the header.
+func (h PreRPCHook[T]) PreStream(ctx context.Context) error {
+	isLeader, _ := h.isLeaderAndNeedForward(ctx)
+	if !isLeader {
+		if leader, exist := h.CheckLeader(); exist {
+			setLeaderHeader(ctx, leader.AdvertiseAddr)
+		}
+		return errors.ErrMasterRPCNotForward.GenWithStackByArgs()
+	}
+	if !h.initialized.Load() {
+		return errors.ErrMasterNotInitialized.GenWithStackByArgs()
+	}
+	return nil
+}
+
 func (h PreRPCHook[T]) logRateLimit(methodName string, req interface{}) {
 	// TODO: rate limiter based on different sender
 	if h.limiter.Allow() {
-		log.L().Info("", zap.Any("payload", req), zap.String("request", methodName))
+		log.L().Info("", redact.Any("payload", req), zap.String("request", methodName))
 	}
 }
 
@@ -173,13 +191,16 @@ func (h PreRPCHook[T]) forwardToLeader(
 	if isLeader {
 		return false, nil
 	}
+	if leader, exist := h.CheckLeader(); exist {
+		setLeaderHeader(ctx, leader.AdvertiseAddr)
+	}
 	if needForward {
 		inner := h.leaderCli.Get()
 		if inner == nil {
 			return true, errors.ErrMasterRPCNotForward.GenWithStackByArgs()
 		}
 
-		params := []reflect.Value{reflect.ValueOf(ctx), reflect.ValueOf(req)}
+		params := []reflect.Value{reflect.ValueOf(forwardIdempotencyKey(ctx)), reflect.ValueOf(req)}
 		results := reflect.ValueOf(inner.GetLeaderClient()).
 			MethodByName(methodName).
 			Call(params)
@@ -192,3 +192,21 @@ func TestCheckInitialized(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, pb.ErrorCode_MasterNotReady, resp.Err.Code)
 }
+
+func TestPreStream(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := newMockRPCServer()
+	require.NoError(t, s.hook.PreStream(ctx))
+
+	s.hook.initialized.Store(false)
+	require.True(t, errors.ErrMasterNotInitialized.Equal(s.hook.PreStream(ctx)))
+
+	// streams are not forwarded even if the forwarding client is ready
+	s.hook.leader.Store(&Member{Name: "another", AdvertiseAddr: "127.0.0.1:10240"})
+	s.hook.leaderCli.Set(NewFailoverRPCClientsForTest[mockRPCClientIface](
+		&mockRPCClientImpl{},
+	))
+	require.True(t, errors.ErrMasterRPCNotForward.Equal(s.hook.PreStream(ctx)))
+}
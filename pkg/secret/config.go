@@ -0,0 +1,48 @@
+package secret
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"strings"
+
+	"github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+// Config is the configuration of the secrets stored in the framework
+// metastore, the server masters and the executors must share the same key.
+type Config struct {
+	// KeyFile is the path of a file holding the base64 encoded AES key of
+	// 16, 24 or 32 bytes the secrets are encrypted with, empty means the
+	// secrets in the metastore can't be stored or resolved. The key is read
+	// from a file so that it never appears in the config or the logs.
+	KeyFile string `toml:"key-file" json:"key-file"`
+
+	key []byte
+}
+
+// Enabled returns whether secrets can be stored in the framework metastore
+func (c *Config) Enabled() bool {
+	return c != nil && c.KeyFile != ""
+}
+
+// Adjust validates the config and loads the key
+func (c *Config) Adjust() error {
+	if !c.Enabled() {
+		return nil
+	}
+	content, err := ioutil.ReadFile(c.KeyFile)
+	if err != nil {
+		return errors.ErrSecretInvalidConfig.GenWithStackByArgs(err.Error())
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(content)))
+	if err != nil {
+		return errors.ErrSecretInvalidConfig.GenWithStackByArgs("key is not base64 encoded")
+	}
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return errors.ErrSecretInvalidConfig.GenWithStackByArgs("key must be 16, 24 or 32 bytes")
+	}
+	c.key = key
+	return nil
+}
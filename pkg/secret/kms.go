@@ -0,0 +1,69 @@
+package secret
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"io"
+
+	"github.com/pingcap/errors"
+
+	derrors "github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+// KMS encrypts and decrypts the values of secrets. The ciphertext is bound
+// to the project, so that it can't be decrypted as a secret of another
+// project.
+type KMS interface {
+	Encrypt(ctx context.Context, projectID string, plaintext []byte) ([]byte, error)
+	Decrypt(ctx context.Context, projectID string, ciphertext []byte) ([]byte, error)
+}
+
+// NewKMS returns the KMS of the config, it returns nil if secrets are not
+// enabled. The config must have been adjusted.
+func NewKMS(cfg *Config) (KMS, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+	return NewAESKMS(cfg.key)
+}
+
+// aesKMS encrypts secrets in AES-GCM by a local key, the nonce is prepended
+// to the ciphertext and the project ID is the additional data.
+type aesKMS struct {
+	aead cipher.AEAD
+}
+
+// NewAESKMS creates a KMS encrypting in AES-GCM by the key
+func NewAESKMS(key []byte) (KMS, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, derrors.ErrSecretInvalidConfig.GenWithStackByArgs(err.Error())
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, derrors.ErrSecretInvalidConfig.GenWithStackByArgs(err.Error())
+	}
+	return &aesKMS{aead: aead}, nil
+}
+
+func (k *aesKMS) Encrypt(_ context.Context, projectID string, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, k.aead.NonceSize(), k.aead.NonceSize()+len(plaintext)+k.aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return k.aead.Seal(nonce, nonce, plaintext, []byte(projectID)), nil
+}
+
+func (k *aesKMS) Decrypt(_ context.Context, projectID string, ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < k.aead.NonceSize() {
+		return nil, errors.New("ciphertext is too short")
+	}
+	nonce, sealed := ciphertext[:k.aead.NonceSize()], ciphertext[k.aead.NonceSize():]
+	plaintext, err := k.aead.Open(nil, nonce, sealed, []byte(projectID))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,104 @@
+package secret
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	derrors "github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/tenant"
+)
+
+const (
+	// referencePrefix is the prefix of the env values referencing a secret
+	// in the framework metastore, such as "secret://db-password" for the
+	// secret db-password of the project of the job.
+	referencePrefix = "secret://"
+	// externalPrefix is the prefix of the env values referencing a secret
+	// resolved by a registered Resolver, such as "secret+vault://db/password"
+	// for the path db/password resolved by the resolver of scheme vault.
+	externalPrefix = "secret+"
+)
+
+// Resolver resolves the secrets stored outside the framework, such as in
+// an external KMS or vault.
+type Resolver interface {
+	Resolve(ctx context.Context, projectInfo tenant.ProjectInfo, path string) (string, error)
+}
+
+var resolvers = struct {
+	sync.RWMutex
+	m map[string]Resolver
+}{m: make(map[string]Resolver)}
+
+// RegisterResolver registers the resolver of the references of a scheme,
+// it is expected to be called in init functions.
+func RegisterResolver(scheme string, r Resolver) {
+	resolvers.Lock()
+	defer resolvers.Unlock()
+	resolvers.m[scheme] = r
+}
+
+func getResolver(scheme string) (Resolver, bool) {
+	resolvers.RLock()
+	defer resolvers.RUnlock()
+	r, ok := resolvers.m[scheme]
+	return r, ok
+}
+
+// IsReference returns whether an env value references a secret
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, referencePrefix) || strings.HasPrefix(value, externalPrefix)
+}
+
+// HasReference returns whether any value of env references a secret
+func HasReference(env map[string]string) bool {
+	for _, value := range env {
+		if IsReference(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveEnv returns a copy of env with the secret references replaced by
+// the values of the secrets of the project, env is returned as is if it
+// has no reference. The errors name the references, never the values.
+func (s *Store) ResolveEnv(
+	ctx context.Context, projectInfo tenant.ProjectInfo, env map[string]string,
+) (map[string]string, error) {
+	if !HasReference(env) {
+		return env, nil
+	}
+	ret := make(map[string]string, len(env))
+	for key, value := range env {
+		resolved, err := s.resolve(ctx, projectInfo, value)
+		if err != nil {
+			return nil, err
+		}
+		ret[key] = resolved
+	}
+	return ret, nil
+}
+
+func (s *Store) resolve(ctx context.Context, projectInfo tenant.ProjectInfo, value string) (string, error) {
+	if strings.HasPrefix(value, referencePrefix) {
+		plaintext, err := s.Get(ctx, projectInfo.ProjectID, strings.TrimPrefix(value, referencePrefix))
+		if err != nil {
+			return "", err
+		}
+		return string(plaintext), nil
+	}
+	if !strings.HasPrefix(value, externalPrefix) {
+		return value, nil
+	}
+	scheme, path, ok := strings.Cut(strings.TrimPrefix(value, externalPrefix), "://")
+	if !ok {
+		return "", derrors.ErrSecretUnknownReference.GenWithStackByArgs(value)
+	}
+	r, ok := getResolver(scheme)
+	if !ok {
+		return "", derrors.ErrSecretUnknownReference.GenWithStackByArgs(scheme)
+	}
+	return r.Resolve(ctx, projectInfo, path)
+}
@@ -0,0 +1,104 @@
+package secret
+
+import (
+	"context"
+	"regexp"
+	"sort"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	derrors "github.com/hanfei1991/microcosm/pkg/errors"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+	"github.com/hanfei1991/microcosm/pkg/orm/model"
+)
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,127}$`)
+
+// Store manages the secrets of projects in the framework metastore, the
+// values are encrypted by the KMS before being persisted. The values are
+// never logged, and never returned except by Get.
+type Store struct {
+	metaCli pkgOrm.SecretClient
+	kms     KMS
+}
+
+// NewStore creates a Store, secrets can only be listed and deleted if kms
+// is nil.
+func NewStore(metaCli pkgOrm.SecretClient, kms KMS) *Store {
+	return &Store{metaCli: metaCli, kms: kms}
+}
+
+// Put encrypts and persists a secret, a secret of the same name in the
+// project is replaced.
+func (s *Store) Put(ctx context.Context, projectID, name string, value []byte) error {
+	if !namePattern.MatchString(name) {
+		return derrors.ErrSecretInvalidName.GenWithStackByArgs(name)
+	}
+	if s.kms == nil {
+		return derrors.ErrSecretNotEnabled.GenWithStackByArgs()
+	}
+	ciphertext, err := s.kms.Encrypt(ctx, projectID, value)
+	if err != nil {
+		return err
+	}
+	err = s.metaCli.UpsertSecret(ctx, &model.Secret{
+		ProjectID:  projectID,
+		Name:       name,
+		Ciphertext: ciphertext,
+	})
+	if err != nil {
+		return err
+	}
+	log.L().Info("secret stored", zap.String("project-id", projectID), zap.String("name", name))
+	return nil
+}
+
+// Delete deletes a secret, the workers that have been dispatched keep the
+// value they are given.
+func (s *Store) Delete(ctx context.Context, projectID, name string) error {
+	result, err := s.metaCli.DeleteSecret(ctx, projectID, name)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return derrors.ErrSecretNotFound.GenWithStackByArgs(name, projectID)
+	}
+	log.L().Info("secret deleted", zap.String("project-id", projectID), zap.String("name", name))
+	return nil
+}
+
+// List returns the sorted names of the secrets of a project
+func (s *Store) List(ctx context.Context, projectID string) ([]string, error) {
+	secrets, err := s.metaCli.QuerySecrets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, secret := range secrets {
+		if secret.ProjectID == projectID {
+			names = append(names, secret.Name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Get returns the decrypted value of a secret
+func (s *Store) Get(ctx context.Context, projectID, name string) ([]byte, error) {
+	if s.kms == nil {
+		return nil, derrors.ErrSecretNotEnabled.GenWithStackByArgs()
+	}
+	secret, err := s.metaCli.GetSecret(ctx, projectID, name)
+	if err != nil {
+		if pkgOrm.IsNotFoundError(err) {
+			return nil, derrors.ErrSecretNotFound.GenWithStackByArgs(name, projectID)
+		}
+		return nil, err
+	}
+	value, err := s.kms.Decrypt(ctx, projectID, secret.Ciphertext)
+	if err != nil {
+		return nil, derrors.ErrSecretDecryptFailed.Wrap(err).GenWithStackByArgs(name)
+	}
+	return value, nil
+}
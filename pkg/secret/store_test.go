@@ -0,0 +1,114 @@
+package secret
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hanfei1991/microcosm/pkg/errors"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+	"github.com/hanfei1991/microcosm/pkg/tenant"
+)
+
+type mockResolver struct{}
+
+func (mockResolver) Resolve(_ context.Context, projectInfo tenant.ProjectInfo, path string) (string, error) {
+	return projectInfo.ProjectID + ":" + path, nil
+}
+
+func newTestStore(t *testing.T) *Store {
+	keyFile := filepath.Join(t.TempDir(), "key")
+	key := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+	require.NoError(t, os.WriteFile(keyFile, []byte(key+"\n"), 0o600))
+	cfg := &Config{KeyFile: keyFile}
+	require.NoError(t, cfg.Adjust())
+	kms, err := NewKMS(cfg)
+	require.NoError(t, err)
+
+	metaCli, err := pkgOrm.NewMockClient()
+	require.NoError(t, err)
+	return NewStore(metaCli, kms)
+}
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := newTestStore(t)
+
+	err := store.Put(ctx, "p1", "bad name", []byte("v"))
+	require.True(t, errors.ErrSecretInvalidName.Equal(err))
+	require.NoError(t, store.Put(ctx, "p1", "password", []byte("v1")))
+	require.NoError(t, store.Put(ctx, "p1", "password", []byte("v2")))
+	require.NoError(t, store.Put(ctx, "p1", "token", []byte("t1")))
+	require.NoError(t, store.Put(ctx, "p2", "password", []byte("p2")))
+
+	secret, err := store.metaCli.GetSecret(ctx, "p1", "password")
+	require.NoError(t, err)
+	require.NotContains(t, string(secret.Ciphertext), "v2")
+	// the ciphertext can't be decrypted as a secret of another project
+	_, err = store.kms.Decrypt(ctx, "p2", secret.Ciphertext)
+	require.Error(t, err)
+
+	value, err := store.Get(ctx, "p1", "password")
+	require.NoError(t, err)
+	require.Equal(t, "v2", string(value))
+	names, err := store.List(ctx, "p1")
+	require.NoError(t, err)
+	require.Equal(t, []string{"password", "token"}, names)
+
+	require.NoError(t, store.Delete(ctx, "p1", "token"))
+	err = store.Delete(ctx, "p1", "token")
+	require.True(t, errors.ErrSecretNotFound.Equal(err))
+	_, err = store.Get(ctx, "p1", "token")
+	require.True(t, errors.ErrSecretNotFound.Equal(err))
+
+	// secrets can be listed and deleted without the key
+	store = NewStore(store.metaCli, nil)
+	err = store.Put(ctx, "p1", "token", []byte("t1"))
+	require.True(t, errors.ErrSecretNotEnabled.Equal(err))
+	names, err = store.List(ctx, "p2")
+	require.NoError(t, err)
+	require.Equal(t, []string{"password"}, names)
+}
+
+func TestResolveEnv(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := newTestStore(t)
+	RegisterResolver("mock", mockResolver{})
+	projectInfo := tenant.ProjectInfo{TenantID: "t1", ProjectID: "p1"}
+	require.NoError(t, store.Put(ctx, "p1", "password", []byte("v1")))
+
+	env := map[string]string{"A": "a"}
+	resolved, err := store.ResolveEnv(ctx, projectInfo, env)
+	require.NoError(t, err)
+	require.Equal(t, env, resolved)
+
+	env = map[string]string{
+		"A":        "a",
+		"PASSWORD": "secret://password",
+		"TOKEN":    "secret+mock://db/token",
+		"URL":      "http://host",
+	}
+	resolved, err = store.ResolveEnv(ctx, projectInfo, env)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"A":        "a",
+		"PASSWORD": "v1",
+		"TOKEN":    "p1:db/token",
+		"URL":      "http://host",
+	}, resolved)
+	require.Equal(t, "secret://password", env["PASSWORD"])
+
+	// secrets are resolved in the project of the job only
+	_, err = store.ResolveEnv(ctx, tenant.ProjectInfo{ProjectID: "p2"}, env)
+	require.True(t, errors.ErrSecretNotFound.Equal(err))
+	_, err = store.ResolveEnv(ctx, projectInfo, map[string]string{"A": "secret+vault://a"})
+	require.True(t, errors.ErrSecretUnknownReference.Equal(err))
+}
@@ -2,6 +2,7 @@ package serverutils
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/hanfei1991/microcosm/model"
@@ -12,6 +13,10 @@ import (
 	"go.uber.org/zap"
 )
 
+// DiscoveryListener is notified of the nodes added and deleted found by
+// service discovery.
+type DiscoveryListener = func(addSet, delSet map[srvdiscovery.UUID]srvdiscovery.ServiceResource)
+
 // DiscoveryKeepaliver wraps wraps DiscoveryRunner and MessageRouter
 type DiscoveryKeepaliver struct {
 	info       *model.NodeInfo
@@ -22,6 +27,17 @@ type DiscoveryKeepaliver struct {
 	discoveryRunner     srvdiscovery.DiscoveryRunner
 	initDiscoveryRunner func() error
 	p2pMsgRouter        p2p.MessageRouter
+
+	mu sync.RWMutex
+	// masters caches the addresses of server masters found by discovery
+	masters map[srvdiscovery.UUID]string
+	// pendingInfo is the node info waiting to be registered again
+	pendingInfo   *model.NodeInfo
+	infoUpdatedCh chan struct{}
+
+	listenerMu     sync.Mutex
+	listeners      map[int]DiscoveryListener
+	nextListenerID int
 }
 
 // NewDiscoveryKeepaliver creates a new DiscoveryKeepaliver
@@ -33,11 +49,14 @@ func NewDiscoveryKeepaliver(
 	msgRouter p2p.MessageRouter,
 ) *DiscoveryKeepaliver {
 	k := &DiscoveryKeepaliver{
-		info:         info,
-		etcdCli:      etcdCli,
-		sessionTTL:   sessionTTL,
-		watchDur:     watchDur,
-		p2pMsgRouter: msgRouter,
+		info:          info,
+		etcdCli:       etcdCli,
+		sessionTTL:    sessionTTL,
+		watchDur:      watchDur,
+		p2pMsgRouter:  msgRouter,
+		masters:       make(map[srvdiscovery.UUID]string),
+		infoUpdatedCh: make(chan struct{}, 1),
+		listeners:     make(map[int]DiscoveryListener),
 	}
 	k.initDiscoveryRunner = k.InitRunnerImpl
 	return k
@@ -55,6 +74,92 @@ func (k *DiscoveryKeepaliver) InitRunnerImpl() error {
 	return nil
 }
 
+// MasterAddrs returns the addresses of server masters currently found by
+// service discovery.
+func (k *DiscoveryKeepaliver) MasterAddrs() []string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	addrs := make([]string, 0, len(k.masters))
+	for _, addr := range k.masters {
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
+func (k *DiscoveryKeepaliver) updateMasters(
+	addSet map[srvdiscovery.UUID]srvdiscovery.ServiceResource,
+	delSet map[srvdiscovery.UUID]srvdiscovery.ServiceResource,
+) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	for uuid, node := range addSet {
+		if node.Type == model.NodeTypeServerMaster {
+			k.masters[uuid] = node.Addr
+		}
+	}
+	for uuid := range delSet {
+		delete(k.masters, uuid)
+	}
+}
+
+// UpdateInfo registers the node info again asynchronously, so that other
+// nodes can find the changed metadata. The info must not be modified after
+// it is passed in.
+func (k *DiscoveryKeepaliver) UpdateInfo(info *model.NodeInfo) {
+	k.mu.Lock()
+	k.pendingInfo = info
+	k.mu.Unlock()
+
+	select {
+	case k.infoUpdatedCh <- struct{}{}:
+	default:
+	}
+}
+
+func (k *DiscoveryKeepaliver) applyInfoUpdate(ctx context.Context) error {
+	k.mu.Lock()
+	info := k.pendingInfo
+	k.pendingInfo = nil
+	k.mu.Unlock()
+	if info == nil {
+		return nil
+	}
+
+	k.info = info
+	value, err := info.ToJSON()
+	if err != nil {
+		return err
+	}
+	return k.discoveryRunner.UpdateValue(ctx, value)
+}
+
+// AddListener registers a listener of node changes, the returned function
+// removes the listener. A listener must not block, since it is called in
+// the discovery loop.
+func (k *DiscoveryKeepaliver) AddListener(listener DiscoveryListener) (remove func()) {
+	k.listenerMu.Lock()
+	defer k.listenerMu.Unlock()
+	id := k.nextListenerID
+	k.nextListenerID++
+	k.listeners[id] = listener
+	return func() {
+		k.listenerMu.Lock()
+		defer k.listenerMu.Unlock()
+		delete(k.listeners, id)
+	}
+}
+
+func (k *DiscoveryKeepaliver) notifyListeners(
+	addSet map[srvdiscovery.UUID]srvdiscovery.ServiceResource,
+	delSet map[srvdiscovery.UUID]srvdiscovery.ServiceResource,
+) {
+	k.listenerMu.Lock()
+	defer k.listenerMu.Unlock()
+	for _, listener := range k.listeners {
+		listener(addSet, delSet)
+	}
+}
+
 // Keepalive keeps discovery runner running, watches peer changes and applies
 // peer changes to message router.
 func (k *DiscoveryKeepaliver) Keepalive(ctx context.Context) error {
@@ -72,6 +177,8 @@ func (k *DiscoveryKeepaliver) Keepalive(ctx context.Context) error {
 		return err
 	}
 	executors := k.discoveryRunner.GetSnapshot()
+	k.updateMasters(executors, nil)
+	k.notifyListeners(executors, nil)
 	for uuid, exec := range executors {
 		if k.p2pMsgRouter != nil {
 			log.L().Info("add peer",
@@ -84,6 +191,11 @@ func (k *DiscoveryKeepaliver) Keepalive(ctx context.Context) error {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
+		case <-k.infoUpdatedCh:
+			if err := k.applyInfoUpdate(ctx); err != nil {
+				log.L().Warn("failed to update node info in discovery",
+					zap.String("id", string(k.info.ID)), zap.Error(err))
+			}
 		case <-session.Done():
 			log.L().Warn("metastore session is done", zap.String("executor-id", string(k.info.ID)))
 			session, err = k.discoveryRunner.ResetDiscovery(ctx, true /* resetSession*/)
@@ -114,6 +226,8 @@ func (k *DiscoveryKeepaliver) Keepalive(ctx context.Context) error {
 					k.p2pMsgRouter.RemovePeer(uuid)
 				}
 			}
+			k.updateMasters(resp.AddSet, resp.DelSet)
+			k.notifyListeners(resp.AddSet, resp.DelSet)
 			k.discoveryRunner.ApplyWatchResult(resp)
 		}
 	}
@@ -100,6 +100,27 @@ func TestDiscoveryKeepalive(t *testing.T) {
 		return nil
 	}
 
+	var (
+		listenerMu sync.Mutex
+		added      = make(map[srvdiscovery.UUID]string)
+		deleted    = make(map[srvdiscovery.UUID]struct{})
+	)
+	keeper.AddListener(func(addSet, delSet map[srvdiscovery.UUID]srvdiscovery.ServiceResource) {
+		listenerMu.Lock()
+		defer listenerMu.Unlock()
+		for uuid, node := range addSet {
+			added[uuid] = node.Addr
+		}
+		for uuid := range delSet {
+			deleted[uuid] = struct{}{}
+		}
+	})
+	removedCalled := false
+	removeListener := keeper.AddListener(func(_, _ map[srvdiscovery.UUID]srvdiscovery.ServiceResource) {
+		removedCalled = true
+	})
+	removeListener()
+
 	var wg sync.WaitGroup
 	wg.Add(1)
 	go func() {
@@ -121,7 +142,7 @@ func TestDiscoveryKeepalive(t *testing.T) {
 	nodeUpdate := srvdiscovery.WatchResp{
 		AddSet: map[srvdiscovery.UUID]srvdiscovery.ServiceResource{
 			"uuid-3": {Addr: "127.0.0.1:10003"},
-			"uuid-4": {Addr: "127.0.0.1:10004"},
+			"uuid-4": {Type: model.NodeTypeServerMaster, Addr: "127.0.0.1:10004"},
 		},
 		DelSet: map[srvdiscovery.UUID]srvdiscovery.ServiceResource{
 			"uuid-2": {Addr: "127.0.0.1:10002"},
@@ -136,6 +157,16 @@ func TestDiscoveryKeepalive(t *testing.T) {
 	require.Contains(t, peers, "uuid-1")
 	require.Contains(t, peers, "uuid-3")
 	require.Contains(t, peers, "uuid-4")
+	require.Eventually(t, func() bool {
+		addrs := keeper.MasterAddrs()
+		return len(addrs) == 1 && addrs[0] == "127.0.0.1:10004"
+	}, time.Second, time.Millisecond*20)
+	require.Eventually(t, func() bool {
+		listenerMu.Lock()
+		defer listenerMu.Unlock()
+		_, ok := deleted["uuid-2"]
+		return ok && len(added) == 4 && added["uuid-3"] == "127.0.0.1:10003"
+	}, time.Second, time.Millisecond*20)
 
 	// check will reconnect to discovery metastore when watch meets error
 	watchResp <- srvdiscovery.WatchResp{Err: stdErrors.New("mock discovery watch error")}
@@ -160,6 +191,26 @@ func TestDiscoveryKeepalive(t *testing.T) {
 	require.Contains(t, peers, "uuid-3")
 	require.Contains(t, peers, "uuid-4")
 
+	// check the node info can be registered again
+	updated := make(chan struct{})
+	runner.EXPECT().UpdateValue(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, value string) error {
+			require.Contains(t, value, `"idle-evictable":true`)
+			close(updated)
+			return nil
+		})
+	keeper.UpdateInfo(&model.NodeInfo{
+		Type:          model.NodeTypeExecutor,
+		ID:            "uuid-1",
+		IdleEvictable: true,
+	})
+	select {
+	case <-updated:
+	case <-time.After(time.Second):
+		require.FailNow(t, "node info is not updated")
+	}
+
 	cancel()
 	wg.Wait()
+	require.False(t, removedCalled)
 }
@@ -0,0 +1,19 @@
+package serverutils
+
+import (
+	"github.com/hanfei1991/microcosm/pkg/p2p"
+	"github.com/hanfei1991/microcosm/pkg/srvdiscovery"
+)
+
+// PeerCodecsListener returns a DiscoveryListener that records the p2p message
+// codecs published by the nodes in codecs.
+func PeerCodecsListener(codecs *p2p.PeerCodecs) DiscoveryListener {
+	return func(addSet, delSet map[srvdiscovery.UUID]srvdiscovery.ServiceResource) {
+		for uuid, node := range addSet {
+			codecs.Observe(uuid, node.MessageCodecs)
+		}
+		for uuid := range delSet {
+			codecs.Remove(uuid)
+		}
+	}
+}
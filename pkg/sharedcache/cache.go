@@ -0,0 +1,185 @@
+// Package sharedcache provides an in-memory LRU cache shared by the workers
+// on one executor. Entries are isolated by job, so that workers of the same
+// job can share hot lookup data, such as schema maps, instead of each keeping
+// a private copy or reading the metastore repeatedly.
+package sharedcache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"golang.org/x/sync/singleflight"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+)
+
+// DefaultCapacity is the default memory budget of a Cache in bytes.
+const DefaultCapacity = 64 * 1024 * 1024
+
+// LoadFunc loads a value and returns it with its estimated size in bytes.
+type LoadFunc func(ctx context.Context) (value interface{}, size int64, err error)
+
+// Client is the cache of one job. Values are shared by all workers of the
+// job on the executor, so they must not be modified after being set.
+type Client interface {
+	// Get returns the value of the key, and whether it is found.
+	Get(key string) (interface{}, bool)
+	// Set sets the value of the key. size is the estimated memory usage of
+	// the value in bytes, a value larger than the capacity is not cached.
+	Set(key string, value interface{}, size int64)
+	// Delete deletes the key.
+	Delete(key string)
+	// GetOrLoad returns the value of the key, or loads and caches it if it is
+	// not found. Concurrent loads of the same key are deduplicated.
+	GetOrLoad(ctx context.Context, key string, load LoadFunc) (interface{}, error)
+}
+
+type entryKey struct {
+	namespace libModel.MasterID
+	key       string
+}
+
+type entry struct {
+	key   entryKey
+	value interface{}
+	size  int64
+}
+
+// Cache is a memory bounded LRU cache with per-job namespaces.
+type Cache struct {
+	mu       sync.Mutex
+	capacity int64
+	size     int64
+	lru      *list.List
+	entries  map[entryKey]*list.Element
+
+	loads singleflight.Group
+}
+
+// NewCache creates a new Cache. capacity <= 0 means DefaultCapacity.
+func NewCache(capacity int64) *Cache {
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+	return &Cache{
+		capacity: capacity,
+		lru:      list.New(),
+		entries:  make(map[entryKey]*list.Element),
+	}
+}
+
+// Client returns the Client of the job.
+func (c *Cache) Client(jobID libModel.MasterID) Client {
+	return &jobClient{cache: c, jobID: jobID}
+}
+
+// Size returns the total size of cached values in bytes.
+func (c *Cache) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+// DropNamespace removes all entries of the job.
+func (c *Cache) DropNamespace(jobID libModel.MasterID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, elem := range c.entries {
+		if key.namespace == jobID {
+			c.removeElement(elem)
+		}
+	}
+}
+
+func (c *Cache) get(key entryKey) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	return elem.Value.(*entry).value, true
+}
+
+func (c *Cache) set(key entryKey, value interface{}, size int64) {
+	if size < 0 {
+		size = 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.removeElement(elem)
+	}
+	if size > c.capacity {
+		return
+	}
+	for c.size+size > c.capacity {
+		c.removeElement(c.lru.Back())
+	}
+	c.entries[key] = c.lru.PushFront(&entry{key: key, value: value, size: size})
+	c.size += size
+}
+
+func (c *Cache) delete(key entryKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.removeElement(elem)
+	}
+}
+
+// removeElement must be called with c.mu held.
+func (c *Cache) removeElement(elem *list.Element) {
+	e := c.lru.Remove(elem).(*entry)
+	delete(c.entries, e.key)
+	c.size -= e.size
+}
+
+type jobClient struct {
+	cache *Cache
+	jobID libModel.MasterID
+}
+
+func (c *jobClient) entryKey(key string) entryKey {
+	return entryKey{namespace: c.jobID, key: key}
+}
+
+func (c *jobClient) Get(key string) (interface{}, bool) {
+	return c.cache.get(c.entryKey(key))
+}
+
+func (c *jobClient) Set(key string, value interface{}, size int64) {
+	c.cache.set(c.entryKey(key), value, size)
+}
+
+func (c *jobClient) Delete(key string) {
+	c.cache.delete(c.entryKey(key))
+}
+
+func (c *jobClient) GetOrLoad(ctx context.Context, key string, load LoadFunc) (interface{}, error) {
+	if value, ok := c.Get(key); ok {
+		return value, nil
+	}
+
+	// job IDs and keys can't contain a NUL character in practice
+	value, err, _ := c.cache.loads.Do(c.jobID+"\x00"+key, func() (interface{}, error) {
+		if value, ok := c.Get(key); ok {
+			return value, nil
+		}
+		value, size, err := load(ctx)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		c.Set(key, value, size)
+		return value, nil
+	})
+	return value, err
+}
@@ -0,0 +1,80 @@
+package sharedcache
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/pingcap/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+func TestCacheEviction(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(10)
+	job1 := cache.Client("job-1")
+	job2 := cache.Client("job-2")
+
+	job1.Set("a", "a", 4)
+	job2.Set("a", "b", 4)
+	value, ok := job1.Get("a")
+	require.True(t, ok)
+	require.Equal(t, "a", value)
+	value, ok = job2.Get("a")
+	require.True(t, ok)
+	require.Equal(t, "b", value)
+
+	// job-1/a is the least recently used
+	job2.Set("b", "b", 4)
+	_, ok = job1.Get("a")
+	require.False(t, ok)
+	require.Equal(t, int64(8), cache.Size())
+
+	// too large to be cached
+	job1.Set("c", "c", 11)
+	_, ok = job1.Get("c")
+	require.False(t, ok)
+
+	job2.Delete("a")
+	require.Equal(t, int64(4), cache.Size())
+	job1.Set("d", "d", 2)
+	cache.DropNamespace("job-2")
+	_, ok = job2.Get("b")
+	require.False(t, ok)
+	require.Equal(t, int64(2), cache.Size())
+}
+
+func TestCacheGetOrLoad(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client := NewCache(0).Client("job-1")
+
+	var loadCount atomic.Int32
+	load := func(ctx context.Context) (interface{}, int64, error) {
+		loadCount.Add(1)
+		return map[string]int{"t1": 1}, 64, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := client.GetOrLoad(ctx, "schema", load)
+			require.NoError(t, err)
+			require.Equal(t, map[string]int{"t1": 1}, value)
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int32(1), loadCount.Load())
+
+	_, err := client.GetOrLoad(ctx, "other", func(ctx context.Context) (interface{}, int64, error) {
+		return nil, 0, errors.New("fake error")
+	})
+	require.Error(t, err)
+	_, ok := client.Get("other")
+	require.False(t, ok)
+}
@@ -0,0 +1,113 @@
+package simulation
+
+import (
+	"github.com/pingcap/errors"
+	"go.uber.org/dig"
+
+	"github.com/hanfei1991/microcosm/client"
+	"github.com/hanfei1991/microcosm/model"
+	"github.com/hanfei1991/microcosm/pkg/clock"
+	dcontext "github.com/hanfei1991/microcosm/pkg/context"
+	"github.com/hanfei1991/microcosm/pkg/deps"
+	"github.com/hanfei1991/microcosm/pkg/externalresource/broker"
+	extkv "github.com/hanfei1991/microcosm/pkg/meta/extension"
+	mockkv "github.com/hanfei1991/microcosm/pkg/meta/kvclient/mock"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+	"github.com/hanfei1991/microcosm/pkg/p2p"
+)
+
+// Env is a simulated cluster, in which masters and workers run on the
+// virtual clock of a Scheduler, talk through an in-memory Network and share
+// an in-memory metastore.
+type Env struct {
+	sched   *Scheduler
+	network *Network
+
+	frameMetaClient    pkgOrm.Client
+	userRawKVClient    extkv.KVClientEx
+	serverMasterClient *client.MockServerMasterClient
+}
+
+// NewEnv creates an Env, seed is used by all random decisions made in the
+// simulation.
+func NewEnv(seed int64) (*Env, error) {
+	frameMetaClient, err := pkgOrm.NewMockClient()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	sched := NewScheduler(seed)
+	return &Env{
+		sched:              sched,
+		network:            NewNetwork(sched),
+		frameMetaClient:    frameMetaClient,
+		userRawKVClient:    mockkv.NewMetaMock(),
+		serverMasterClient: &client.MockServerMasterClient{},
+	}, nil
+}
+
+// Scheduler returns the scheduler driving the simulation.
+func (e *Env) Scheduler() *Scheduler {
+	return e.sched
+}
+
+// Network returns the simulated network.
+func (e *Env) Network() *Network {
+	return e.network
+}
+
+// FrameMetaClient returns the framework metastore shared by all nodes.
+func (e *Env) FrameMetaClient() pkgOrm.Client {
+	return e.frameMetaClient
+}
+
+// ServerMasterClient returns the mock server master client shared by all
+// nodes, on which the calls expected by the test can be set.
+func (e *Env) ServerMasterClient() *client.MockServerMasterClient {
+	return e.serverMasterClient
+}
+
+// Close releases the resources of the Env.
+func (e *Env) Close() error {
+	return errors.Trace(e.frameMetaClient.Close())
+}
+
+type nodeDeps struct {
+	dig.Out
+
+	MessageHandlerManager p2p.MessageHandlerManager
+	MessageSender         p2p.MessageSender
+	FrameMetaClient       pkgOrm.Client
+	UserRawKVClient       extkv.KVClientEx
+	ExecutorClientManager client.ClientsManager
+	ServerMasterClient    client.MasterClient
+	ResourceBroker        broker.Broker
+	Clock                 clock.Clock
+}
+
+// NewContext creates a context to run a master or worker on the given node
+// of the simulated cluster. Each call creates a new network endpoint, so a
+// context should be created for each master or worker.
+func (e *Env) NewContext(nodeID p2p.NodeID) (*dcontext.Context, *Endpoint, error) {
+	endpoint := e.network.NewEndpoint(nodeID)
+	dp := deps.NewDeps()
+	err := dp.Provide(func() nodeDeps {
+		return nodeDeps{
+			MessageHandlerManager: endpoint,
+			MessageSender:         endpoint,
+			FrameMetaClient:       e.frameMetaClient,
+			UserRawKVClient:       e.userRawKVClient,
+			ExecutorClientManager: client.NewClientManager(),
+			ServerMasterClient:    e.serverMasterClient,
+			ResourceBroker:        broker.NewBrokerForTesting(model.ExecutorID(nodeID)),
+			Clock:                 e.sched.Clock(),
+		}
+	})
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	ctx := dcontext.Background().WithDeps(dp)
+	ctx.Environ.NodeID = nodeID
+	ctx.Environ.Addr = nodeID
+	return ctx, endpoint, nil
+}
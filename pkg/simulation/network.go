@@ -0,0 +1,276 @@
+package simulation
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	"github.com/hanfei1991/microcosm/pkg/p2p"
+)
+
+type link struct {
+	from, to p2p.NodeID
+}
+
+type handlerEntry struct {
+	tpi   reflect.Type
+	fn    p2p.HandlerFunc
+	owner *Endpoint
+}
+
+// Network is an in-memory network delivering p2p messages between simulated
+// nodes. A message is delivered after a random latency of virtual time, and
+// may be lost randomly or by a partition. Messages sent to a topic without
+// a handler on the target node are dropped.
+type Network struct {
+	sched *Scheduler
+
+	mu         sync.RWMutex
+	minLatency time.Duration
+	maxLatency time.Duration
+	lossRate   float64
+	partitions map[link]struct{}
+	handlers   map[p2p.NodeID]map[p2p.Topic]handlerEntry
+
+	sentCount    int
+	droppedCount int
+}
+
+// NewNetwork creates a Network driven by sched. The network has no latency
+// nor loss by default.
+func NewNetwork(sched *Scheduler) *Network {
+	return &Network{
+		sched:      sched,
+		partitions: make(map[link]struct{}),
+		handlers:   make(map[p2p.NodeID]map[p2p.Topic]handlerEntry),
+	}
+}
+
+// SetLatency sets the range of the latency of delivering a message.
+func (n *Network) SetLatency(min, max time.Duration) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.minLatency, n.maxLatency = min, max
+}
+
+// SetLossRate sets the probability that a message is lost.
+func (n *Network) SetLossRate(rate float64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.lossRate = rate
+}
+
+// Partition drops all messages between the two nodes in both directions.
+func (n *Network) Partition(a, b p2p.NodeID) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.partitions[link{from: a, to: b}] = struct{}{}
+	n.partitions[link{from: b, to: a}] = struct{}{}
+}
+
+// Heal recovers the connectivity between the two nodes.
+func (n *Network) Heal(a, b p2p.NodeID) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	delete(n.partitions, link{from: a, to: b})
+	delete(n.partitions, link{from: b, to: a})
+}
+
+// HealAll recovers all partitions.
+func (n *Network) HealAll() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.partitions = make(map[link]struct{})
+}
+
+// Stats returns the number of messages sent and dropped.
+func (n *Network) Stats() (sent, dropped int) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.sentCount, n.droppedCount
+}
+
+// NewEndpoint creates an endpoint on a simulated node, which implements both
+// p2p.MessageSender and p2p.MessageHandlerManager. Components on the same
+// node should use their own endpoints, as Clean only unregisters the
+// handlers registered through the endpoint.
+func (n *Network) NewEndpoint(id p2p.NodeID) *Endpoint {
+	return &Endpoint{
+		network: n,
+		nodeID:  id,
+		topics:  make(map[p2p.Topic]struct{}),
+	}
+}
+
+func (n *Network) send(from, to p2p.NodeID, topic p2p.Topic, message interface{}) error {
+	// Encode the message when sending, as the real network does, so that
+	// the receiver never shares the object with the sender.
+	data, err := json.Marshal(message)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	n.mu.Lock()
+	n.sentCount++
+	_, partitioned := n.partitions[link{from: from, to: to}]
+	minLatency, maxLatency, lossRate := n.minLatency, n.maxLatency, n.lossRate
+	n.mu.Unlock()
+
+	if partitioned || (lossRate > 0 && n.sched.randFloat() < lossRate) {
+		n.drop(from, to, topic, "lost")
+		return nil
+	}
+	latency := n.sched.randDuration(minLatency, maxLatency)
+	n.sched.After(latency, func() {
+		n.deliver(from, to, topic, data)
+	})
+	return nil
+}
+
+func (n *Network) deliver(from, to p2p.NodeID, topic p2p.Topic, data []byte) {
+	n.mu.RLock()
+	// a partition created after sending drops the messages in flight
+	_, partitioned := n.partitions[link{from: from, to: to}]
+	entry, ok := n.handlers[to][topic]
+	n.mu.RUnlock()
+	if partitioned {
+		n.drop(from, to, topic, "partitioned")
+		return
+	}
+	if !ok {
+		n.drop(from, to, topic, "no handler")
+		return
+	}
+
+	value := reflect.New(entry.tpi.Elem()).Interface()
+	if err := json.Unmarshal(data, value); err != nil {
+		log.L().Panic("failed to decode simulated message",
+			zap.String("topic", topic), zap.Error(err))
+	}
+	if err := entry.fn(from, value); err != nil {
+		n.mu.Lock()
+		if entry.owner.handlerErr == nil {
+			entry.owner.handlerErr = err
+		}
+		n.mu.Unlock()
+	}
+}
+
+func (n *Network) drop(from, to p2p.NodeID, topic p2p.Topic, reason string) {
+	n.mu.Lock()
+	n.droppedCount++
+	n.mu.Unlock()
+	log.L().Debug("simulated message dropped",
+		zap.String("from", from), zap.String("to", to),
+		zap.String("topic", topic), zap.String("reason", reason))
+}
+
+// Endpoint is the network interface of a simulated node.
+type Endpoint struct {
+	network *Network
+	nodeID  p2p.NodeID
+
+	// fields below are protected by network.mu
+	// topics registered through this endpoint
+	topics map[p2p.Topic]struct{}
+	// handlerErr records the first error returned by the handlers, which
+	// is reported by CheckError.
+	handlerErr error
+}
+
+// NodeID returns the ID of the node.
+func (e *Endpoint) NodeID() p2p.NodeID {
+	return e.nodeID
+}
+
+// SendToNode implements p2p.MessageSender.SendToNode
+func (e *Endpoint) SendToNode(
+	_ context.Context, targetNodeID p2p.NodeID, topic p2p.Topic, message interface{},
+) (bool, error) {
+	if err := e.network.send(e.nodeID, targetNodeID, topic, message); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// SendToNodeB implements p2p.MessageSender.SendToNodeB
+func (e *Endpoint) SendToNodeB(
+	_ context.Context, targetNodeID p2p.NodeID, topic p2p.Topic, message interface{},
+) error {
+	return e.network.send(e.nodeID, targetNodeID, topic, message)
+}
+
+// RegisterHandler implements p2p.MessageHandlerManager.RegisterHandler
+func (e *Endpoint) RegisterHandler(
+	_ context.Context, topic p2p.Topic, tpi p2p.TypeInformation, fn p2p.HandlerFunc,
+) (bool, error) {
+	tp := reflect.TypeOf(tpi)
+	if tp == nil || tp.Kind() != reflect.Ptr {
+		return false, errors.Errorf("type information of topic %s must be a pointer", topic)
+	}
+
+	n := e.network
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	handlers, ok := n.handlers[e.nodeID]
+	if !ok {
+		handlers = make(map[p2p.Topic]handlerEntry)
+		n.handlers[e.nodeID] = handlers
+	}
+	if _, ok := handlers[topic]; ok {
+		return false, nil
+	}
+	handlers[topic] = handlerEntry{tpi: tp, fn: fn, owner: e}
+	e.topics[topic] = struct{}{}
+	return true, nil
+}
+
+// UnregisterHandler implements p2p.MessageHandlerManager.UnregisterHandler
+func (e *Endpoint) UnregisterHandler(_ context.Context, topic p2p.Topic) (bool, error) {
+	n := e.network
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if _, ok := e.topics[topic]; !ok {
+		return false, nil
+	}
+	delete(n.handlers[e.nodeID], topic)
+	delete(e.topics, topic)
+	return true, nil
+}
+
+// CheckError implements p2p.MessageHandlerManager.CheckError
+func (e *Endpoint) CheckError(_ context.Context) error {
+	n := e.network
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	err := e.handlerErr
+	e.handlerErr = nil
+	return err
+}
+
+// Clean implements p2p.MessageHandlerManager.Clean
+func (e *Endpoint) Clean(_ context.Context) error {
+	n := e.network
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for topic := range e.topics {
+		delete(n.handlers[e.nodeID], topic)
+		delete(e.topics, topic)
+	}
+	return nil
+}
+
+// SetTimeout implements p2p.MessageHandlerManager.SetTimeout
+func (e *Endpoint) SetTimeout(_ time.Duration) {
+	// handler operations are synchronous in the simulated network
+}
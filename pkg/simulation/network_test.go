@@ -0,0 +1,183 @@
+package simulation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hanfei1991/microcosm/pkg/p2p"
+)
+
+type testMessage struct {
+	Seq int `json:"seq"`
+}
+
+const testTopic = "test-topic"
+
+type receivedMessage struct {
+	at     time.Duration
+	sender p2p.NodeID
+	seq    int
+}
+
+func registerRecorder(
+	t *testing.T, sched *Scheduler, endpoint *Endpoint, received *[]receivedMessage,
+) {
+	start := sched.Now()
+	ok, err := endpoint.RegisterHandler(context.Background(), testTopic, &testMessage{},
+		func(sender p2p.NodeID, value p2p.MessageValue) error {
+			*received = append(*received, receivedMessage{
+				at:     sched.Now().Sub(start),
+				sender: sender,
+				seq:    value.(*testMessage).Seq,
+			})
+			return nil
+		})
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestNetworkLatency(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	sched := NewScheduler(0)
+	network := NewNetwork(sched)
+	network.SetLatency(100*time.Millisecond, 100*time.Millisecond)
+	node1, node2 := network.NewEndpoint("node-1"), network.NewEndpoint("node-2")
+
+	var received []receivedMessage
+	registerRecorder(t, sched, node2, &received)
+
+	msg := &testMessage{Seq: 1}
+	ok, err := node1.SendToNode(ctx, "node-2", testTopic, msg)
+	require.NoError(t, err)
+	require.True(t, ok)
+	// the receiver gets a copy of the message
+	msg.Seq = 2
+
+	sched.RunFor(99 * time.Millisecond)
+	require.Empty(t, received)
+	sched.RunFor(time.Millisecond)
+	require.Equal(t, []receivedMessage{{at: 100 * time.Millisecond, sender: "node-1", seq: 1}}, received)
+
+	// messages to unknown topics are dropped
+	err = node2.SendToNodeB(ctx, "node-1", testTopic, msg)
+	require.NoError(t, err)
+	sched.RunFor(time.Second)
+	sent, dropped := network.Stats()
+	require.Equal(t, 2, sent)
+	require.Equal(t, 1, dropped)
+}
+
+func TestNetworkPartition(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	sched := NewScheduler(0)
+	network := NewNetwork(sched)
+	network.SetLatency(10*time.Millisecond, 10*time.Millisecond)
+	node1, node2 := network.NewEndpoint("node-1"), network.NewEndpoint("node-2")
+
+	var received []receivedMessage
+	registerRecorder(t, sched, node2, &received)
+
+	network.Partition("node-2", "node-1")
+	_, err := node1.SendToNode(ctx, "node-2", testTopic, &testMessage{Seq: 1})
+	require.NoError(t, err)
+	sched.RunFor(time.Second)
+	require.Empty(t, received)
+
+	network.Heal("node-1", "node-2")
+	_, err = node1.SendToNode(ctx, "node-2", testTopic, &testMessage{Seq: 2})
+	require.NoError(t, err)
+	// messages in flight are dropped by a new partition
+	sched.RunFor(5 * time.Millisecond)
+	network.Partition("node-1", "node-2")
+	sched.RunFor(time.Second)
+	require.Empty(t, received)
+
+	network.HealAll()
+	_, err = node1.SendToNode(ctx, "node-2", testTopic, &testMessage{Seq: 3})
+	require.NoError(t, err)
+	sched.RunFor(time.Second)
+	require.Len(t, received, 1)
+	require.Equal(t, 3, received[0].seq)
+}
+
+func TestNetworkLossDeterministic(t *testing.T) {
+	t.Parallel()
+
+	run := func(seed int64) []receivedMessage {
+		ctx := context.Background()
+		sched := NewScheduler(seed)
+		network := NewNetwork(sched)
+		network.SetLatency(time.Millisecond, 50*time.Millisecond)
+		network.SetLossRate(0.3)
+		node1, node2 := network.NewEndpoint("node-1"), network.NewEndpoint("node-2")
+
+		var received []receivedMessage
+		registerRecorder(t, sched, node2, &received)
+		for i := 0; i < 100; i++ {
+			_, err := node1.SendToNode(ctx, "node-2", testTopic, &testMessage{Seq: i})
+			require.NoError(t, err)
+		}
+		sched.RunFor(time.Second)
+		return received
+	}
+
+	received := run(42)
+	require.Greater(t, len(received), 0)
+	require.Less(t, len(received), 100)
+	// the same seed reproduces the same losses and reordering
+	require.Equal(t, received, run(42))
+}
+
+func TestEndpointHandlers(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	sched := NewScheduler(0)
+	network := NewNetwork(sched)
+	sender := network.NewEndpoint("node-1")
+	// two components on the same node
+	comp1, comp2 := network.NewEndpoint("node-2"), network.NewEndpoint("node-2")
+
+	_, err := comp1.RegisterHandler(ctx, testTopic, testMessage{}, nil)
+	require.Error(t, err)
+
+	handlerErr := errors.New("handler error")
+	ok, err := comp1.RegisterHandler(ctx, testTopic, &testMessage{},
+		func(sender p2p.NodeID, value p2p.MessageValue) error {
+			return handlerErr
+		})
+	require.NoError(t, err)
+	require.True(t, ok)
+	// the topic is registered on the node already
+	ok, err = comp2.RegisterHandler(ctx, testTopic, &testMessage{}, nil)
+	require.NoError(t, err)
+	require.False(t, ok)
+	ok, err = comp2.UnregisterHandler(ctx, testTopic)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	_, err = sender.SendToNode(ctx, "node-2", testTopic, &testMessage{Seq: 1})
+	require.NoError(t, err)
+	sched.RunFor(time.Millisecond)
+	require.NoError(t, comp2.CheckError(ctx))
+	require.ErrorIs(t, comp1.CheckError(ctx), handlerErr)
+	require.NoError(t, comp1.CheckError(ctx))
+
+	// Clean only unregisters the handlers of the endpoint
+	require.NoError(t, comp1.Clean(ctx))
+	var received []receivedMessage
+	registerRecorder(t, sched, comp2, &received)
+	require.NoError(t, comp1.Clean(ctx))
+	_, err = sender.SendToNode(ctx, "node-2", testTopic, &testMessage{Seq: 2})
+	require.NoError(t, err)
+	sched.RunFor(time.Millisecond)
+	require.Len(t, received, 1)
+}
@@ -0,0 +1,218 @@
+// Package simulation provides a harness to test master/worker scenarios
+// deterministically. Time is virtual and only advances when the test drives
+// the Scheduler, messages between nodes go through an in-memory Network
+// with configurable latency and loss, and all nodes share an in-memory
+// metastore, so failover scenarios can be tested without sleeping.
+package simulation
+
+import (
+	"container/heap"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+
+	"github.com/hanfei1991/microcosm/pkg/clock"
+)
+
+// defaultStep is the largest step the virtual clock advances at a time, so
+// that timers of the components fire and their goroutines get the chance to
+// schedule new events in between.
+const defaultStep = 10 * time.Millisecond
+
+// startTime is the initial time of the virtual clock.
+var startTime = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+type event struct {
+	at  time.Time
+	seq uint64
+	fn  func()
+}
+
+// eventHeap orders events by time, and events scheduled at the same time
+// run in the order they are scheduled.
+type eventHeap []*event
+
+func (h eventHeap) Len() int { return len(h) }
+
+func (h eventHeap) Less(i, j int) bool {
+	if h[i].at.Equal(h[j].at) {
+		return h[i].seq < h[j].seq
+	}
+	return h[i].at.Before(h[j].at)
+}
+
+func (h eventHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *eventHeap) Push(x interface{}) { *h = append(*h, x.(*event)) }
+
+func (h *eventHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	*h = old[:n-1]
+	return e
+}
+
+// Scheduler runs events in the order of a virtual clock. All events run on
+// the goroutine driving the scheduler by RunFor or RunUntil. Events can be
+// scheduled from any goroutine.
+type Scheduler struct {
+	clk  *clock.Mock
+	step time.Duration
+
+	mu     sync.Mutex
+	events eventHeap
+	seq    uint64
+	rand   *rand.Rand
+}
+
+// NewScheduler creates a Scheduler, seed is used by all random decisions
+// made in the simulation so that a run can be reproduced.
+func NewScheduler(seed int64) *Scheduler {
+	clk := clock.NewMock()
+	clk.Set(startTime)
+	return &Scheduler{
+		clk:  clk,
+		step: defaultStep,
+		rand: rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Clock returns the virtual clock, which should be provided to the
+// components under test.
+func (s *Scheduler) Clock() clock.Clock {
+	return s.clk
+}
+
+// Now returns the current virtual time.
+func (s *Scheduler) Now() time.Time {
+	return s.clk.Now()
+}
+
+// SetStep sets the largest step the virtual clock advances at a time.
+func (s *Scheduler) SetStep(step time.Duration) {
+	s.step = step
+}
+
+// After schedules fn to run after the given duration of virtual time.
+func (s *Scheduler) After(d time.Duration, fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seq++
+	heap.Push(&s.events, &event{
+		at:  s.clk.Now().Add(d),
+		seq: s.seq,
+		fn:  fn,
+	})
+}
+
+// Every schedules fn to run periodically until it returns false.
+func (s *Scheduler) Every(interval time.Duration, fn func() bool) {
+	var tick func()
+	tick = func() {
+		if fn() {
+			s.After(interval, tick)
+		}
+	}
+	s.After(interval, tick)
+}
+
+// Pending returns the number of events not run yet.
+func (s *Scheduler) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.events)
+}
+
+// RunFor advances the virtual clock by d, running all events due.
+func (s *Scheduler) RunFor(d time.Duration) {
+	deadline := s.clk.Now().Add(d)
+	for {
+		if !s.runStep(deadline) {
+			return
+		}
+	}
+}
+
+// RunUntil advances the virtual clock until cond returns true, it returns
+// an error if cond is still false after limit has elapsed. cond is checked
+// after every step.
+func (s *Scheduler) RunUntil(cond func() bool, limit time.Duration) error {
+	deadline := s.clk.Now().Add(limit)
+	for !cond() {
+		if !s.runStep(deadline) {
+			return errors.Errorf("condition not met after %s of virtual time", limit)
+		}
+	}
+	return nil
+}
+
+// runStep runs the events due at the next step, returns false if the
+// deadline has been reached.
+func (s *Scheduler) runStep(deadline time.Time) bool {
+	now := s.clk.Now()
+	if !now.Before(deadline) {
+		return false
+	}
+	next := now.Add(s.step)
+	if next.After(deadline) {
+		next = deadline
+	}
+	if ev := s.peek(); ev != nil && ev.at.Before(next) {
+		next = ev.at
+	}
+	if next.After(now) {
+		// Set fires the timers of the components and yields to their
+		// goroutines.
+		s.clk.Set(next)
+	}
+
+	for {
+		ev := s.popDue(next)
+		if ev == nil {
+			break
+		}
+		ev.fn()
+	}
+	return true
+}
+
+func (s *Scheduler) peek() *event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.events) == 0 {
+		return nil
+	}
+	return s.events[0]
+}
+
+func (s *Scheduler) popDue(now time.Time) *event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.events) == 0 || s.events[0].at.After(now) {
+		return nil
+	}
+	return heap.Pop(&s.events).(*event)
+}
+
+// randDuration returns a random duration in [min, max].
+func (s *Scheduler) randDuration(min, max time.Duration) time.Duration {
+	if max <= min {
+		return min
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return min + time.Duration(s.rand.Int63n(int64(max-min)+1))
+}
+
+// randFloat returns a random number in [0.0, 1.0).
+func (s *Scheduler) randFloat() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rand.Float64()
+}
@@ -0,0 +1,85 @@
+package simulation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedulerOrder(t *testing.T) {
+	t.Parallel()
+
+	sched := NewScheduler(0)
+	start := sched.Now()
+
+	var (
+		order []int
+		times []time.Duration
+	)
+	record := func(i int) func() {
+		return func() {
+			order = append(order, i)
+			times = append(times, sched.Now().Sub(start))
+		}
+	}
+	sched.After(30*time.Millisecond, record(3))
+	sched.After(10*time.Millisecond, record(1))
+	// events at the same time run in the order they are scheduled
+	sched.After(20*time.Millisecond, record(2))
+	sched.After(20*time.Millisecond, func() {
+		record(4)()
+		sched.After(0, record(5))
+	})
+
+	sched.RunFor(25 * time.Millisecond)
+	require.Equal(t, []int{1, 2, 4, 5}, order)
+	require.Equal(t, 25*time.Millisecond, sched.Now().Sub(start))
+	require.Equal(t, 1, sched.Pending())
+
+	sched.RunFor(time.Second)
+	require.Equal(t, []int{1, 2, 4, 5, 3}, order)
+	require.Equal(t, []time.Duration{
+		10 * time.Millisecond,
+		20 * time.Millisecond,
+		20 * time.Millisecond,
+		20 * time.Millisecond,
+		30 * time.Millisecond,
+	}, times)
+	require.Equal(t, 0, sched.Pending())
+}
+
+func TestSchedulerEvery(t *testing.T) {
+	t.Parallel()
+
+	sched := NewScheduler(0)
+	count := 0
+	sched.Every(time.Second, func() bool {
+		count++
+		return count < 3
+	})
+	sched.RunFor(time.Minute)
+	require.Equal(t, 3, count)
+}
+
+func TestSchedulerRunUntil(t *testing.T) {
+	t.Parallel()
+
+	sched := NewScheduler(0)
+	start := sched.Now()
+	ticker := sched.Clock().Ticker(time.Second)
+	defer ticker.Stop()
+
+	fired := false
+	sched.After(3*time.Second, func() {
+		fired = true
+	})
+	err := sched.RunUntil(func() bool { return fired }, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, 3*time.Second, sched.Now().Sub(start))
+	// the timers of the virtual clock fire as time advances
+	require.Len(t, ticker.C, 1)
+
+	err = sched.RunUntil(func() bool { return false }, time.Second)
+	require.Error(t, err)
+}
@@ -0,0 +1,25 @@
+package sink
+
+import (
+	"github.com/hanfei1991/microcosm/pkg/eventbus"
+)
+
+// SubscribeWorkerEvents exports the worker online and offline events
+// published on the bus, until unsubscribe is called.
+func (e *Exporter) SubscribeWorkerEvents(bus *eventbus.Bus) (unsubscribe func()) {
+	if e == nil {
+		return func() {}
+	}
+	unsubscribeOnline := eventbus.Subscribe(bus, func(event eventbus.WorkerOnline) {
+		e.Emit(NewWorkerEvent(EventWorkerOnline, event.Time, event.TenantID,
+			event.MasterID, event.WorkerID, event.Status, nil))
+	})
+	unsubscribeOffline := eventbus.Subscribe(bus, func(event eventbus.WorkerOffline) {
+		e.Emit(NewWorkerEvent(EventWorkerOffline, event.Time, event.TenantID,
+			event.MasterID, event.WorkerID, event.Status, event.Reason))
+	})
+	return func() {
+		unsubscribeOnline()
+		unsubscribeOffline()
+	}
+}
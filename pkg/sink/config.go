@@ -0,0 +1,111 @@
+package sink
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+// defines the types of sinks
+const (
+	// TypeKafka writes events to a Kafka topic
+	TypeKafka = "kafka"
+	// TypeHTTP posts events to an HTTP collector in JSON
+	TypeHTTP = "http"
+)
+
+const (
+	defaultBatchSize     = 128
+	defaultQueueSize     = 10240
+	defaultFlushInterval = "1s"
+)
+
+// Config is the configuration of exporting events to an external sink
+type Config struct {
+	// Type is the type of the sink, empty means events are not exported
+	Type string `toml:"type" json:"type"`
+
+	// KafkaBrokers and KafkaTopic are used by the kafka sink
+	KafkaBrokers []string `toml:"kafka-brokers" json:"kafka-brokers"`
+	KafkaTopic   string   `toml:"kafka-topic" json:"kafka-topic"`
+
+	// HTTPURL is the address events are posted to by the http sink
+	HTTPURL string `toml:"http-url" json:"http-url"`
+
+	// BatchSize is the max number of events written in a batch
+	BatchSize int `toml:"batch-size" json:"batch-size"`
+	// QueueSize is the max number of events waiting to be written, events
+	// are dropped if the queue is full.
+	QueueSize        int    `toml:"queue-size" json:"queue-size"`
+	FlushIntervalStr string `toml:"flush-interval" json:"flush-interval"`
+
+	// Tenants overrides the sink of the events of the given tenants, the
+	// events of other tenants are written to the sink of the cluster.
+	Tenants map[string]*Config `toml:"tenants" json:"tenants"`
+
+	FlushInterval time.Duration `toml:"-" json:"-"`
+}
+
+// Enabled returns whether any event is exported
+func (c *Config) Enabled() bool {
+	return c != nil && (c.Type != "" || len(c.Tenants) > 0)
+}
+
+// Adjust validates the config and fills the default values
+func (c *Config) Adjust() error {
+	if err := c.adjust(); err != nil {
+		return err
+	}
+	for tenant, tenantCfg := range c.Tenants {
+		if tenantCfg == nil || tenantCfg.Type == "" {
+			return errors.ErrSinkInvalidConfig.GenWithStackByArgs(
+				fmt.Sprintf("sink type of tenant %s is not specified", tenant))
+		}
+		if len(tenantCfg.Tenants) > 0 {
+			return errors.ErrSinkInvalidConfig.GenWithStackByArgs(
+				fmt.Sprintf("tenants of tenant %s can't be specified", tenant))
+		}
+		if err := tenantCfg.adjust(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Config) adjust() error {
+	switch c.Type {
+	case "":
+	case TypeKafka:
+		if len(c.KafkaBrokers) == 0 || c.KafkaTopic == "" {
+			return errors.ErrSinkInvalidConfig.GenWithStackByArgs(
+				"kafka-brokers and kafka-topic are required by kafka sink")
+		}
+	case TypeHTTP:
+		if c.HTTPURL == "" {
+			return errors.ErrSinkInvalidConfig.GenWithStackByArgs("http-url is required by http sink")
+		}
+	default:
+		return errors.ErrSinkInvalidConfig.GenWithStackByArgs(
+			fmt.Sprintf("unknown sink type %s", c.Type))
+	}
+
+	if c.BatchSize <= 0 {
+		c.BatchSize = defaultBatchSize
+	}
+	if c.QueueSize <= 0 {
+		c.QueueSize = defaultQueueSize
+	}
+	if c.FlushIntervalStr == "" {
+		c.FlushIntervalStr = defaultFlushInterval
+	}
+	interval, err := time.ParseDuration(c.FlushIntervalStr)
+	if err != nil {
+		return errors.ErrSinkInvalidConfig.GenWithStackByArgs(fmt.Sprintf("flush-interval: %v", err))
+	}
+	if interval <= 0 {
+		return errors.ErrSinkInvalidConfig.GenWithStackByArgs("flush-interval must be positive")
+	}
+	c.FlushInterval = interval
+	return nil
+}
@@ -0,0 +1,70 @@
+package sink
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+func TestConfigAdjust(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{}
+	require.NoError(t, cfg.Adjust())
+	require.False(t, cfg.Enabled())
+
+	cfg = &Config{
+		Type:    TypeHTTP,
+		HTTPURL: "http://127.0.0.1:8080/events",
+		Tenants: map[string]*Config{
+			"tenant-1": {
+				Type:             TypeKafka,
+				KafkaBrokers:     []string{"127.0.0.1:9092"},
+				KafkaTopic:       "events",
+				BatchSize:        16,
+				FlushIntervalStr: "100ms",
+			},
+		},
+	}
+	require.NoError(t, cfg.Adjust())
+	require.True(t, cfg.Enabled())
+	require.Equal(t, defaultBatchSize, cfg.BatchSize)
+	require.Equal(t, defaultQueueSize, cfg.QueueSize)
+	require.Equal(t, time.Second, cfg.FlushInterval)
+	tenantCfg := cfg.Tenants["tenant-1"]
+	require.Equal(t, 16, tenantCfg.BatchSize)
+	require.Equal(t, 100*time.Millisecond, tenantCfg.FlushInterval)
+
+	// only tenants are exported
+	cfg = &Config{Tenants: map[string]*Config{
+		"tenant-1": {Type: TypeHTTP, HTTPURL: "http://127.0.0.1:8080/events"},
+	}}
+	require.NoError(t, cfg.Adjust())
+	require.True(t, cfg.Enabled())
+}
+
+func TestConfigAdjustInvalid(t *testing.T) {
+	t.Parallel()
+
+	invalid := []*Config{
+		{Type: "unknown"},
+		{Type: TypeHTTP},
+		{Type: TypeKafka, KafkaTopic: "events"},
+		{Type: TypeHTTP, HTTPURL: "http://127.0.0.1", FlushIntervalStr: "1x"},
+		{Type: TypeHTTP, HTTPURL: "http://127.0.0.1", FlushIntervalStr: "-1s"},
+		{Tenants: map[string]*Config{"tenant-1": {}}},
+		{Tenants: map[string]*Config{"tenant-1": {
+			Type:    TypeHTTP,
+			HTTPURL: "http://127.0.0.1",
+			Tenants: map[string]*Config{"tenant-2": {Type: TypeHTTP, HTTPURL: "http://127.0.0.1"}},
+		}}},
+	}
+	for _, cfg := range invalid {
+		err := cfg.Adjust()
+		require.Error(t, err)
+		require.True(t, errors.ErrSinkInvalidConfig.Equal(err), "%+v", cfg)
+	}
+}
@@ -0,0 +1,78 @@
+package sink
+
+import (
+	"time"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+)
+
+// EventType is the type of an exported event
+type EventType string
+
+// defines the types of exported events
+const (
+	// EventWorkerOnline is emitted when a worker comes online
+	EventWorkerOnline = EventType("worker-online")
+	// EventWorkerOffline is emitted when a worker exits or times out
+	EventWorkerOffline = EventType("worker-offline")
+	// EventWorkerStatusUpdated is emitted when the status of a worker changes
+	EventWorkerStatusUpdated = EventType("worker-status-updated")
+	// EventWorkerDispatchFailed is emitted when a worker fails to be dispatched
+	EventWorkerDispatchFailed = EventType("worker-dispatch-failed")
+
+	// EventJobSubmitted is emitted when a job is submitted
+	EventJobSubmitted = EventType("job-submitted")
+	// EventJobFinished is emitted when a job finishes
+	EventJobFinished = EventType("job-finished")
+	// EventJobStopped is emitted when a job is paused
+	EventJobStopped = EventType("job-stopped")
+	// EventJobFailed is emitted when a job master exits unexpectedly and
+	// the job is going to fail over
+	EventJobFailed = EventType("job-failed")
+	// EventJobCanceled is emitted when a job is canceled
+	EventJobCanceled = EventType("job-canceled")
+	// EventJobExpired is emitted when a finished job is deleted after its
+	// retention
+	EventJobExpired = EventType("job-expired")
+)
+
+// Event is an operational event exported to the sink
+type Event struct {
+	Type EventType `json:"type"`
+	Time time.Time `json:"time"`
+	// TenantID is the project the event belongs to, empty means the default
+	TenantID string            `json:"tenant-id,omitempty"`
+	JobID    libModel.MasterID `json:"job-id"`
+	WorkerID libModel.WorkerID `json:"worker-id,omitempty"`
+	// StatusCode and ErrorMessage are the status of the worker, for worker
+	// events only
+	StatusCode   libModel.WorkerStatusCode `json:"status-code,omitempty"`
+	ErrorMessage string                    `json:"error-message,omitempty"`
+}
+
+// NewWorkerEvent creates an event of the worker, status and err are optional.
+func NewWorkerEvent(
+	tp EventType,
+	tm time.Time,
+	tenantID string,
+	jobID libModel.MasterID,
+	workerID libModel.WorkerID,
+	status *libModel.WorkerStatus,
+	err error,
+) *Event {
+	event := &Event{
+		Type:     tp,
+		Time:     tm,
+		TenantID: tenantID,
+		JobID:    jobID,
+		WorkerID: workerID,
+	}
+	if status != nil {
+		event.StatusCode = status.Code
+		event.ErrorMessage = status.ErrorMessage
+	}
+	if err != nil {
+		event.ErrorMessage = err.Error()
+	}
+	return event
+}
@@ -0,0 +1,226 @@
+package sink
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/time/rate"
+
+	"github.com/hanfei1991/microcosm/pkg/redact"
+)
+
+const (
+	clusterPipelineName = "cluster"
+
+	minRetryInterval = 100 * time.Millisecond
+	maxRetryInterval = 10 * time.Second
+	// closeFlushTimeout is how long the remaining events are flushed for
+	// after the exporter is canceled.
+	closeFlushTimeout = 5 * time.Second
+)
+
+// Exporter exports events to the sinks asynchronously. Events are batched
+// and written at least once, a failed batch is retried until it succeeds.
+// A nil Exporter is valid and drops all events.
+type Exporter struct {
+	cluster *pipeline
+	tenants map[string]*pipeline
+}
+
+// NewExporter creates an Exporter, the config must be adjusted.
+func NewExporter(cfg *Config) (*Exporter, error) {
+	e := &Exporter{
+		tenants: make(map[string]*pipeline, len(cfg.Tenants)),
+	}
+	if cfg.Type != "" {
+		s, err := newSink(cfg)
+		if err != nil {
+			return nil, err
+		}
+		e.cluster = newPipeline(clusterPipelineName, cfg, s)
+	}
+	for tenant, tenantCfg := range cfg.Tenants {
+		s, err := newSink(tenantCfg)
+		if err != nil {
+			_ = e.Close()
+			return nil, err
+		}
+		e.tenants[tenant] = newPipeline("tenant-"+tenant, tenantCfg, s)
+	}
+	return e, nil
+}
+
+// Emit queues an event without blocking. The event is written to the sink of
+// its tenant, or the sink of the cluster if the tenant has no sink. It is
+// dropped if the queue is full. The sensitive values of the event are
+// redacted, as the sinks are outside of the cluster.
+func (e *Exporter) Emit(event *Event) {
+	if e == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	p, ok := e.tenants[event.TenantID]
+	if !ok {
+		p = e.cluster
+	}
+	if p == nil {
+		return
+	}
+	p.emit(redact.Value(event).(*Event))
+}
+
+// Run writes the queued events until ctx is canceled, then the remaining
+// events are flushed once in best effort.
+func (e *Exporter) Run(ctx context.Context) error {
+	wg, ctx := errgroup.WithContext(ctx)
+	for _, p := range e.pipelines() {
+		p := p
+		wg.Go(func() error {
+			p.run(ctx)
+			return nil
+		})
+	}
+	return wg.Wait()
+}
+
+// Close closes the sinks, it should be called after Run returns.
+func (e *Exporter) Close() error {
+	var firstErr error
+	for _, p := range e.pipelines() {
+		if err := p.sink.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (e *Exporter) pipelines() []*pipeline {
+	ret := make([]*pipeline, 0, len(e.tenants)+1)
+	if e.cluster != nil {
+		ret = append(ret, e.cluster)
+	}
+	for _, p := range e.tenants {
+		ret = append(ret, p)
+	}
+	return ret
+}
+
+type pipeline struct {
+	name          string
+	sink          Sink
+	queue         chan *Event
+	batchSize     int
+	flushInterval time.Duration
+
+	dropLogLimiter *rate.Limiter
+}
+
+func newPipeline(name string, cfg *Config, s Sink) *pipeline {
+	return &pipeline{
+		name:           name,
+		sink:           s,
+		queue:          make(chan *Event, cfg.QueueSize),
+		batchSize:      cfg.BatchSize,
+		flushInterval:  cfg.FlushInterval,
+		dropLogLimiter: rate.NewLimiter(rate.Every(time.Second*5), 1 /*burst*/),
+	}
+}
+
+func (p *pipeline) emit(event *Event) {
+	select {
+	case p.queue <- event:
+	default:
+		droppedEventCounter.WithLabelValues(p.name).Inc()
+		if p.dropLogLimiter.Allow() {
+			log.L().Warn("sink queue is full, event is dropped",
+				zap.String("sink", p.name), zap.Any("event", event))
+		}
+	}
+}
+
+func (p *pipeline) run(ctx context.Context) {
+	ticker := time.NewTicker(p.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*Event, 0, p.batchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			p.flushRemaining(batch)
+			return
+		case event := <-p.queue:
+			batch = append(batch, event)
+			if len(batch) < p.batchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		}
+		if !p.writeWithRetry(ctx, batch) {
+			p.flushRemaining(batch)
+			return
+		}
+		batch = batch[:0]
+	}
+}
+
+// writeWithRetry writes the batch until it succeeds, it returns false if ctx
+// is canceled before that.
+func (p *pipeline) writeWithRetry(ctx context.Context, batch []*Event) bool {
+	interval := minRetryInterval
+	for {
+		err := p.sink.Write(ctx, batch)
+		if err == nil {
+			exportedEventCounter.WithLabelValues(p.name).Add(float64(len(batch)))
+			return true
+		}
+		writeErrorCounter.WithLabelValues(p.name).Inc()
+		log.L().Warn("write events to sink failed, will retry",
+			zap.String("sink", p.name), zap.Int("events", len(batch)),
+			zap.Duration("retry-interval", interval), zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(interval):
+		}
+		interval *= 2
+		if interval > maxRetryInterval {
+			interval = maxRetryInterval
+		}
+	}
+}
+
+// flushRemaining writes the batch and the queued events once, the events are
+// lost if the write fails.
+func (p *pipeline) flushRemaining(batch []*Event) {
+drain:
+	for {
+		select {
+		case event := <-p.queue:
+			batch = append(batch, event)
+		default:
+			break drain
+		}
+	}
+	if len(batch) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), closeFlushTimeout)
+	defer cancel()
+	if err := p.sink.Write(ctx, batch); err != nil {
+		writeErrorCounter.WithLabelValues(p.name).Inc()
+		log.L().Warn("flush events to sink failed, events are lost",
+			zap.String("sink", p.name), zap.Int("events", len(batch)), zap.Error(err))
+		return
+	}
+	exportedEventCounter.WithLabelValues(p.name).Add(float64(len(batch)))
+}
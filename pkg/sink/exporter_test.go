@@ -0,0 +1,193 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/stretchr/testify/require"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/pkg/eventbus"
+)
+
+type mockCollector struct {
+	mu       sync.Mutex
+	failures int
+	batches  [][]*Event
+}
+
+func (c *mockCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.failures > 0 {
+		c.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	var events []*Event
+	if err := json.NewDecoder(r.Body).Decode(&events); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	c.batches = append(c.batches, events)
+}
+
+func (c *mockCollector) events() []*Event {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var ret []*Event
+	for _, batch := range c.batches {
+		ret = append(ret, batch...)
+	}
+	return ret
+}
+
+func newTestExporter(t *testing.T, cfg *Config) (*Exporter, func()) {
+	require.NoError(t, cfg.Adjust())
+	exporter, err := NewExporter(cfg)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		require.NoError(t, exporter.Run(ctx))
+	}()
+	return exporter, func() {
+		cancel()
+		wg.Wait()
+		require.NoError(t, exporter.Close())
+	}
+}
+
+func TestExporterBatchAndRetry(t *testing.T) {
+	t.Parallel()
+
+	collector := &mockCollector{failures: 2}
+	server := httptest.NewServer(collector)
+	defer server.Close()
+
+	exporter, stop := newTestExporter(t, &Config{
+		Type:             TypeHTTP,
+		HTTPURL:          server.URL,
+		BatchSize:        3,
+		FlushIntervalStr: "10ms",
+	})
+	for i := 0; i < 3; i++ {
+		exporter.Emit(&Event{Type: EventWorkerOnline, JobID: "job-1", WorkerID: "worker-1"})
+	}
+	// the batch is written after the failures are retried
+	require.Eventually(t, func() bool {
+		return len(collector.events()) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// a partial batch is written after the flush interval
+	exporter.Emit(&Event{Type: EventJobFinished, JobID: "job-1"})
+	require.Eventually(t, func() bool {
+		return len(collector.events()) == 4
+	}, 5*time.Second, 10*time.Millisecond)
+	stop()
+
+	events := collector.events()
+	require.Equal(t, EventJobFinished, events[3].Type)
+	require.False(t, events[3].Time.IsZero())
+	collector.mu.Lock()
+	require.Len(t, collector.batches, 2)
+	collector.mu.Unlock()
+}
+
+func TestExporterTenants(t *testing.T) {
+	t.Parallel()
+
+	cluster, tenant := &mockCollector{}, &mockCollector{}
+	clusterServer, tenantServer := httptest.NewServer(cluster), httptest.NewServer(tenant)
+	defer clusterServer.Close()
+	defer tenantServer.Close()
+
+	exporter, stop := newTestExporter(t, &Config{
+		Type:             TypeHTTP,
+		HTTPURL:          clusterServer.URL,
+		FlushIntervalStr: "1h",
+		Tenants: map[string]*Config{
+			"tenant-1": {Type: TypeHTTP, HTTPURL: tenantServer.URL, FlushIntervalStr: "1h"},
+		},
+	})
+	exporter.Emit(&Event{Type: EventJobSubmitted, TenantID: "tenant-1", JobID: "job-1"})
+	exporter.Emit(&Event{Type: EventJobSubmitted, TenantID: "tenant-2", JobID: "job-2"})
+	exporter.Emit(&Event{Type: EventJobSubmitted, JobID: "job-3"})
+	// the queued events are flushed when the exporter stops
+	stop()
+
+	require.Len(t, tenant.events(), 1)
+	require.Equal(t, "job-1", tenant.events()[0].JobID)
+	require.Len(t, cluster.events(), 2)
+}
+
+func TestExporterQueueFull(t *testing.T) {
+	t.Parallel()
+
+	collector := &mockCollector{}
+	server := httptest.NewServer(collector)
+	defer server.Close()
+
+	cfg := &Config{Type: TypeHTTP, HTTPURL: server.URL, QueueSize: 2}
+	require.NoError(t, cfg.Adjust())
+	exporter, err := NewExporter(cfg)
+	require.NoError(t, err)
+	// the exporter is not running, so the queue is never consumed
+	for i := 0; i < 5; i++ {
+		exporter.Emit(&Event{Type: EventWorkerOnline, JobID: "job-1"})
+	}
+	require.Len(t, exporter.cluster.queue, 2)
+	require.NoError(t, exporter.Close())
+
+	// a nil exporter drops all events
+	var nilExporter *Exporter
+	nilExporter.Emit(&Event{Type: EventWorkerOnline})
+}
+
+func TestExporterSubscribeWorkerEvents(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{Type: TypeHTTP, HTTPURL: "http://127.0.0.1:1"}
+	require.NoError(t, cfg.Adjust())
+	exporter, err := NewExporter(cfg)
+	require.NoError(t, err)
+	defer exporter.Close()
+
+	bus := eventbus.NewBus()
+	defer bus.Close()
+	unsubscribe := exporter.SubscribeWorkerEvents(bus)
+	defer unsubscribe()
+
+	eventbus.Publish(bus, eventbus.WorkerOnline{
+		MasterID: "job-1",
+		WorkerID: "worker-1",
+		Status:   &libModel.WorkerStatus{Code: libModel.WorkerStatusNormal},
+	})
+	eventbus.Publish(bus, eventbus.WorkerOffline{
+		MasterID: "job-1",
+		WorkerID: "worker-1",
+		Reason:   errors.New("worker exited"),
+	})
+	// the exporter is not running, so the events stay in the queue
+	require.Eventually(t, func() bool {
+		return len(exporter.cluster.queue) == 2
+	}, time.Second, 10*time.Millisecond)
+	// the events of different types are not ordered
+	events := make(map[EventType]*Event)
+	for i := 0; i < 2; i++ {
+		event := <-exporter.cluster.queue
+		events[event.Type] = event
+	}
+	require.Equal(t, "worker-1", events[EventWorkerOnline].WorkerID)
+	require.Equal(t, libModel.WorkerStatusNormal, events[EventWorkerOnline].StatusCode)
+	require.Equal(t, "worker exited", events[EventWorkerOffline].ErrorMessage)
+}
@@ -0,0 +1,38 @@
+package sink
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	exportedEventCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "dataflow",
+			Subsystem: "sink",
+			Name:      "exported_event_total",
+			Help:      "number of events written to the sink",
+		}, []string{"sink"})
+
+	droppedEventCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "dataflow",
+			Subsystem: "sink",
+			Name:      "dropped_event_total",
+			Help:      "number of events dropped because the queue of the sink is full",
+		}, []string{"sink"})
+
+	writeErrorCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "dataflow",
+			Subsystem: "sink",
+			Name:      "write_error_total",
+			Help:      "number of failed writes to the sink",
+		}, []string{"sink"})
+)
+
+// InitMetrics registers the sink metrics
+func InitMetrics(registry *prometheus.Registry) {
+	registry.MustRegister(exportedEventCounter)
+	registry.MustRegister(droppedEventCounter)
+	registry.MustRegister(writeErrorCounter)
+}
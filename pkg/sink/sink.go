@@ -0,0 +1,121 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/Shopify/sarama"
+
+	"github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+// Sink writes batches of events to an external system
+type Sink interface {
+	// Write writes the events, the events are written again if an error is
+	// returned, so the receiver should tolerate duplicated events.
+	Write(ctx context.Context, events []*Event) error
+	Close() error
+}
+
+// newSink creates a Sink by the config, the config must be adjusted.
+func newSink(cfg *Config) (Sink, error) {
+	switch cfg.Type {
+	case TypeKafka:
+		return newKafkaSink(cfg)
+	case TypeHTTP:
+		return newHTTPSink(cfg), nil
+	default:
+		return nil, errors.ErrSinkInvalidConfig.GenWithStackByArgs("unknown sink type " + cfg.Type)
+	}
+}
+
+type httpSink struct {
+	url    string
+	client *http.Client
+}
+
+func newHTTPSink(cfg *Config) *httpSink {
+	return &httpSink{
+		url:    cfg.HTTPURL,
+		client: &http.Client{},
+	}
+}
+
+// Write posts the events in a JSON array.
+func (s *httpSink) Write(ctx context.Context, events []*Event) error {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return errors.Wrap(errors.ErrSinkWriteFailed, err, TypeHTTP)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(errors.ErrSinkWriteFailed, err, TypeHTTP)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return errors.Wrap(errors.ErrSinkWriteFailed, err, TypeHTTP)
+	}
+	defer resp.Body.Close()
+	// drain the body to reuse the connection
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.ErrSinkWriteFailed.GenWithStack(
+			"writing events to http sink failed, status: %s", resp.Status)
+	}
+	return nil
+}
+
+func (s *httpSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+type kafkaSink struct {
+	topic    string
+	producer sarama.SyncProducer
+}
+
+func newKafkaSink(cfg *Config) (*kafkaSink, error) {
+	saramaCfg := sarama.NewConfig()
+	saramaCfg.Producer.RequiredAcks = sarama.WaitForAll
+	// required by the sync producer
+	saramaCfg.Producer.Return.Successes = true
+	producer, err := sarama.NewSyncProducer(cfg.KafkaBrokers, saramaCfg)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrSinkWriteFailed, err, TypeKafka)
+	}
+	return &kafkaSink{
+		topic:    cfg.KafkaTopic,
+		producer: producer,
+	}, nil
+}
+
+// Write produces each event as a JSON message keyed by the job ID, so that
+// the events of a job are kept in order in a partition.
+func (s *kafkaSink) Write(_ context.Context, events []*Event) error {
+	msgs := make([]*sarama.ProducerMessage, 0, len(events))
+	for _, event := range events {
+		value, err := json.Marshal(event)
+		if err != nil {
+			return errors.Wrap(errors.ErrSinkWriteFailed, err, TypeKafka)
+		}
+		msgs = append(msgs, &sarama.ProducerMessage{
+			Topic: s.topic,
+			Key:   sarama.StringEncoder(event.JobID),
+			Value: sarama.ByteEncoder(value),
+		})
+	}
+	if err := s.producer.SendMessages(msgs); err != nil {
+		return errors.Wrap(errors.ErrSinkWriteFailed, err, TypeKafka)
+	}
+	return nil
+}
+
+func (s *kafkaSink) Close() error {
+	return s.producer.Close()
+}
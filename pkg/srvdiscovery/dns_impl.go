@@ -0,0 +1,67 @@
+package srvdiscovery
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hanfei1991/microcosm/model"
+	"github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+var _ Discovery = (*DNSSrvDiscovery)(nil)
+
+type lookupSRVFunc = func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+
+// DNSSrvDiscovery implements Discovery interface based on DNS SRV records,
+// such as the records of a Kubernetes headless service. All nodes found are
+// of the same node type, the target of an SRV record is used as the node ID.
+type DNSSrvDiscovery struct {
+	*pollingDiscovery
+
+	service  string
+	proto    string
+	name     string
+	nodeType model.NodeType
+
+	lookupSRV lookupSRVFunc
+}
+
+// NewDNSSrvDiscovery creates a new DNSSrvDiscovery, which looks up
+// _service._proto.name every pollInterval. If service and proto are both
+// empty, name is looked up directly.
+func NewDNSSrvDiscovery(
+	service, proto, name string,
+	nodeType model.NodeType,
+	pollInterval time.Duration,
+) *DNSSrvDiscovery {
+	d := &DNSSrvDiscovery{
+		service:   service,
+		proto:     proto,
+		name:      name,
+		nodeType:  nodeType,
+		lookupSRV: net.DefaultResolver.LookupSRV,
+	}
+	d.pollingDiscovery = newPollingDiscovery(d.load, pollInterval)
+	return d
+}
+
+func (d *DNSSrvDiscovery) load(ctx context.Context) (Snapshot, error) {
+	_, records, err := d.lookupSRV(ctx, d.service, d.proto, d.name)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrDiscoveryDNSLookupFail, err, d.name)
+	}
+	snapshot := make(Snapshot, len(records))
+	for _, record := range records {
+		host := strings.TrimSuffix(record.Target, ".")
+		addr := net.JoinHostPort(host, strconv.Itoa(int(record.Port)))
+		snapshot[host] = ServiceResource{
+			Type: d.nodeType,
+			ID:   model.DeployNodeID(host),
+			Addr: addr,
+		}
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,85 @@
+package srvdiscovery
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hanfei1991/microcosm/model"
+)
+
+type mockResolver struct {
+	mu      sync.Mutex
+	records []*net.SRV
+	err     error
+}
+
+func (r *mockResolver) set(records []*net.SRV, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records, r.err = records, err
+}
+
+func (r *mockResolver) lookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return name, r.records, r.err
+}
+
+func TestDNSSrvDiscovery(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	resolver := &mockResolver{}
+	resolver.set([]*net.SRV{
+		{Target: "executor-0.executor.default.svc.", Port: 10241},
+		{Target: "executor-1.executor.default.svc.", Port: 10241},
+	}, nil)
+	d := NewDNSSrvDiscovery("", "", "executor.default.svc", model.NodeTypeExecutor, 50*time.Millisecond)
+	d.lookupSRV = resolver.lookupSRV
+
+	snapshot, err := d.Snapshot(ctx)
+	require.NoError(t, err)
+	require.Equal(t, Snapshot{
+		"executor-0.executor.default.svc": {
+			Type: model.NodeTypeExecutor,
+			ID:   "executor-0.executor.default.svc",
+			Addr: "executor-0.executor.default.svc:10241",
+		},
+		"executor-1.executor.default.svc": {
+			Type: model.NodeTypeExecutor,
+			ID:   "executor-1.executor.default.svc",
+			Addr: "executor-1.executor.default.svc:10241",
+		},
+	}, snapshot)
+
+	ch := d.Watch(ctx)
+	// lookup errors are retried
+	resolver.set(nil, errors.New("fake error"))
+	time.Sleep(100 * time.Millisecond)
+	resolver.set([]*net.SRV{
+		{Target: "executor-1.executor.default.svc.", Port: 10241},
+		{Target: "executor-2.executor.default.svc.", Port: 10241},
+	}, nil)
+	select {
+	case wresp := <-ch:
+		require.NoError(t, wresp.Err)
+		require.Len(t, wresp.AddSet, 1)
+		require.Contains(t, wresp.AddSet, "executor-2.executor.default.svc")
+		require.Len(t, wresp.DelSet, 1)
+		require.Contains(t, wresp.DelSet, "executor-0.executor.default.svc")
+	case <-time.After(time.Second):
+		require.Fail(t, "watch from service discovery timeout")
+	}
+
+	d.Close()
+	wresp := <-ch
+	require.ErrorIs(t, wresp.Err, context.Canceled)
+}
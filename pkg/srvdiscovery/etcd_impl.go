@@ -3,41 +3,65 @@ package srvdiscovery
 import (
 	"context"
 	"encoding/json"
+	"reflect"
+	"sync"
 	"time"
 
 	"github.com/hanfei1991/microcosm/pkg/adapter"
 	"github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/pingcap/tiflow/dm/pkg/log"
 	clientv3 "go.etcd.io/etcd/client/v3"
-	"go.uber.org/atomic"
+	"go.uber.org/zap"
 )
 
 const defaultWatchChanSize = 8
 
-// EtcdSrvDiscovery implements Discovery interface based on etcd as backend storage
-// Note this struct is not thread-safe, and can be watched only once.
+// EtcdSrvDiscovery implements Discovery interface based on etcd as backend storage.
+// Multiple watchers can be started on one EtcdSrvDiscovery, they share one
+// etcd watch, which resumes from the last applied revision after transient
+// errors, and falls back to a full snapshot if the revision is compacted.
 type EtcdSrvDiscovery struct {
 	keyAdapter   adapter.KeyAdapter
 	etcdCli      *clientv3.Client
-	snapshot     Snapshot
 	watchTickDur time.Duration
-	watchCancel  context.CancelFunc
-	watched      *atomic.Bool
+
+	mu sync.Mutex
+	// snapshot is the service resources at revision, revision is 0 if
+	// snapshot is not read from etcd by the watch loop yet.
+	snapshot   Snapshot
+	revision   int64
+	watchers   map[*watcher]struct{}
+	loopCancel context.CancelFunc
+	loopDone   chan struct{}
+}
+
+type watcher struct {
+	notifyCh chan struct{}
+	cancel   context.CancelFunc
 }
 
-// NewEtcdSrvDiscovery creates a new EtcdSrvDiscovery
+func (w *watcher) notify() {
+	select {
+	case w.notifyCh <- struct{}{}:
+	default:
+	}
+}
+
+// NewEtcdSrvDiscovery creates a new EtcdSrvDiscovery, watchTickDur is the
+// interval to retry the watch after an error.
 func NewEtcdSrvDiscovery(etcdCli *clientv3.Client, ka adapter.KeyAdapter, watchTickDur time.Duration) *EtcdSrvDiscovery {
 	return &EtcdSrvDiscovery{
 		keyAdapter:   ka,
 		etcdCli:      etcdCli,
 		watchTickDur: watchTickDur,
-		watched:      atomic.NewBool(false),
 		snapshot:     make(map[UUID]ServiceResource),
+		watchers:     make(map[*watcher]struct{}),
 	}
 }
 
 // Snapshot implements Discovery.Snapshot
 func (d *EtcdSrvDiscovery) Snapshot(ctx context.Context) (Snapshot, error) {
-	snapshot, err := d.getSnapshot(ctx)
+	snapshot, _, err := d.getSnapshot(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -45,100 +69,254 @@ func (d *EtcdSrvDiscovery) Snapshot(ctx context.Context) (Snapshot, error) {
 	return snapshot, nil
 }
 
-// Watch implements Discovery.Watch, note when `Watch` starts, we should avoid
-// to get Snapshot with `updateCache=true`
+// Watch implements Discovery.Watch. Each watcher receives the changes since
+// the snapshot of the discovery when it starts watching. Changes that are not
+// received in time are merged, so a slow watcher doesn't block others.
+// The channel receives an error and stops when ctx is canceled or the
+// discovery is closed.
 func (d *EtcdSrvDiscovery) Watch(ctx context.Context) <-chan WatchResp {
-	notWatched := d.watched.CAS(false, true)
-	if !notWatched {
-		ch := make(chan WatchResp, 1)
-		ch <- WatchResp{Err: errors.ErrDiscoveryDuplicateWatch.GenWithStackByArgs()}
-		return ch
-	}
 	ch := make(chan WatchResp, defaultWatchChanSize)
 	cctx, cancel := context.WithCancel(ctx)
-	d.watchCancel = cancel
-	go d.tickedWatch(cctx, ch)
+	w := &watcher{
+		notifyCh: make(chan struct{}, 1),
+		cancel:   cancel,
+	}
+
+	d.mu.Lock()
+	view := d.snapshot.Clone()
+	d.watchers[w] = struct{}{}
+	if d.loopCancel == nil {
+		var loopCtx context.Context
+		loopCtx, d.loopCancel = context.WithCancel(context.Background())
+		d.loopDone = make(chan struct{})
+		go d.watchLoop(loopCtx, d.loopDone)
+	}
+	d.mu.Unlock()
+
+	go d.runWatcher(cctx, w, view, ch)
 	return ch
 }
 
 // CopySnapshot implements Discovery.CopySnapshot
 func (d *EtcdSrvDiscovery) CopySnapshot(snapshot Snapshot) {
-	d.snapshot = snapshot
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.loopCancel != nil {
+		// the snapshot is maintained by the watch loop
+		return
+	}
+	d.snapshot = snapshot.Clone()
+	d.revision = 0
 }
 
-// Close implements Discovery.Close
+// Close implements Discovery.Close, it stops all watchers. Watch can still
+// be called after Close.
 func (d *EtcdSrvDiscovery) Close() {
-	if d.watchCancel != nil {
-		d.watchCancel()
+	d.mu.Lock()
+	for w := range d.watchers {
+		w.cancel()
 	}
+	d.mu.Unlock()
 }
 
-func (d *EtcdSrvDiscovery) tickedWatch(ctx context.Context, ch chan<- WatchResp) {
-	ticker := time.NewTicker(d.watchTickDur)
-	defer ticker.Stop()
+func (d *EtcdSrvDiscovery) runWatcher(ctx context.Context, w *watcher, view Snapshot, ch chan<- WatchResp) {
 	for {
 		select {
 		case <-ctx.Done():
-			ch <- WatchResp{Err: ctx.Err()}
+			d.stopWatcher(ctx, w, ch)
 			return
-		case <-ticker.C:
-			addSet, delSet, err := d.delta(ctx)
-			if err != nil {
-				ch <- WatchResp{Err: err}
+		case <-w.notifyCh:
+		}
+
+		d.mu.Lock()
+		current := d.snapshot.Clone()
+		d.mu.Unlock()
+
+		addSet, delSet := diffSnapshot(view, current)
+		view = current
+		if len(addSet) == 0 && len(delSet) == 0 {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			d.stopWatcher(ctx, w, ch)
+			return
+		case ch <- WatchResp{AddSet: addSet, DelSet: delSet}:
+		}
+	}
+}
+
+// stopWatcher removes the watcher before sending the error, so that the
+// watch loop is stopped when the last watcher receives the error.
+func (d *EtcdSrvDiscovery) stopWatcher(ctx context.Context, w *watcher, ch chan<- WatchResp) {
+	d.removeWatcher(w)
+	ch <- WatchResp{Err: ctx.Err()}
+}
+
+func (d *EtcdSrvDiscovery) removeWatcher(w *watcher) {
+	w.cancel()
+
+	d.mu.Lock()
+	delete(d.watchers, w)
+	if len(d.watchers) > 0 || d.loopCancel == nil {
+		d.mu.Unlock()
+		return
+	}
+	// stops the watch loop with the last watcher, the next watch loop resumes
+	// from the current revision.
+	cancel, done := d.loopCancel, d.loopDone
+	d.loopCancel, d.loopDone = nil, nil
+	d.mu.Unlock()
+
+	cancel()
+	<-done
+}
+
+// watchLoop watches the changes of service resources and applies them to
+// the snapshot, and notifies the watchers.
+func (d *EtcdSrvDiscovery) watchLoop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+	for {
+		d.mu.Lock()
+		rev := d.revision
+		d.mu.Unlock()
+
+		var err error
+		if rev == 0 {
+			err = d.resync(ctx)
+		} else {
+			err = d.watchFrom(ctx, rev+1)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.L().Warn("service discovery watch met error, retry later",
+				zap.Int64("revision", rev), zap.Error(err))
+			select {
+			case <-ctx.Done():
 				return
-			}
-			if len(addSet) > 0 || len(delSet) > 0 {
-				ch <- WatchResp{
-					AddSet: addSet,
-					DelSet: delSet,
-				}
+			case <-time.After(d.watchTickDur):
 			}
 		}
 	}
 }
 
-// delta returns snapshot diff compared with current snapshot, note delta will
-// update current snapshot and revision.
-func (d *EtcdSrvDiscovery) delta(ctx context.Context) (
-	map[UUID]ServiceResource, map[UUID]ServiceResource, error,
-) {
-	snapshot, err := d.getSnapshot(ctx)
+// resync reads a full snapshot, the watchers get the diff between their views
+// and the new snapshot.
+func (d *EtcdSrvDiscovery) resync(ctx context.Context) error {
+	snapshot, rev, err := d.getSnapshot(ctx)
 	if err != nil {
-		return nil, nil, err
+		return err
 	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.snapshot = snapshot
+	d.revision = rev
+	d.notifyAll()
+	return nil
+}
+
+// watchFrom watches from the given revision until an error happens. If the
+// revision is compacted, the revision is reset so that a full snapshot is read.
+func (d *EtcdSrvDiscovery) watchFrom(ctx context.Context, rev int64) error {
+	wctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	wch := d.etcdCli.Watch(wctx, d.keyAdapter.Path(), clientv3.WithPrefix(), clientv3.WithRev(rev))
+	for resp := range wch {
+		if resp.CompactRevision != 0 {
+			log.L().Info("service discovery watch revision is compacted, read full snapshot",
+				zap.Int64("revision", rev), zap.Int64("compact-revision", resp.CompactRevision))
+			d.mu.Lock()
+			d.revision = 0
+			d.mu.Unlock()
+			return nil
+		}
+		if err := resp.Err(); err != nil {
+			return errors.Wrap(errors.ErrEtcdAPIError, err)
+		}
+		d.applyEvents(resp.Events, resp.Header.Revision)
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return errors.ErrDiscoveryWatchClosed.GenWithStackByArgs()
+}
+
+func (d *EtcdSrvDiscovery) applyEvents(events []*clientv3.Event, rev int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, ev := range events {
+		switch ev.Type {
+		case clientv3.EventTypePut:
+			uuid, resc, err := unmarshal(d.keyAdapter, ev.Kv.Key, ev.Kv.Value)
+			if err != nil {
+				log.L().Warn("skip invalid service resource", zap.ByteString("key", ev.Kv.Key), zap.Error(err))
+				continue
+			}
+			d.snapshot[uuid] = resc
+		case clientv3.EventTypeDelete:
+			keys, err := d.keyAdapter.Decode(string(ev.Kv.Key))
+			if err != nil {
+				log.L().Warn("skip invalid service resource", zap.ByteString("key", ev.Kv.Key), zap.Error(err))
+				continue
+			}
+			delete(d.snapshot, keys[len(keys)-1])
+		}
+	}
+	if rev > d.revision {
+		d.revision = rev
+	}
+	d.notifyAll()
+}
+
+// notifyAll must be called with d.mu held.
+func (d *EtcdSrvDiscovery) notifyAll() {
+	for w := range d.watchers {
+		w.notify()
+	}
+}
+
+// diffSnapshot returns the service resources added and deleted from old to new.
+func diffSnapshot(old, new Snapshot) (
+	map[UUID]ServiceResource, map[UUID]ServiceResource,
+) {
 	addSet := make(map[UUID]ServiceResource)
 	delSet := make(map[UUID]ServiceResource)
-	for k, v := range snapshot {
-		if _, ok := d.snapshot[k]; !ok {
+	for k, v := range new {
+		// a resource whose address or metadata has changed, such as a
+		// restarted executor, is added again so that watchers refresh it.
+		if oldV, ok := old[k]; !ok || !reflect.DeepEqual(oldV, v) {
 			addSet[k] = v
 		}
 	}
-	for k, v := range d.snapshot {
-		if _, ok := snapshot[k]; !ok {
+	for k, v := range old {
+		if _, ok := new[k]; !ok {
 			delSet[k] = v
 		}
 	}
-	d.snapshot = snapshot
-	return addSet, delSet, nil
+	return addSet, delSet
 }
 
-// getSnapshot queries etcd and get a full set of service resource
+// getSnapshot queries etcd and get a full set of service resource, and the
+// revision of the snapshot
 func (d *EtcdSrvDiscovery) getSnapshot(ctx context.Context) (
-	map[UUID]ServiceResource, error,
+	Snapshot, int64, error,
 ) {
 	resp, err := d.etcdCli.Get(ctx, d.keyAdapter.Path(), clientv3.WithPrefix())
 	if err != nil {
-		return nil, errors.Wrap(errors.ErrEtcdAPIError, err)
+		return nil, 0, errors.Wrap(errors.ErrEtcdAPIError, err)
 	}
 	snapshot := make(map[UUID]ServiceResource, resp.Count)
 	for _, kv := range resp.Kvs {
 		uuid, resc, err := unmarshal(d.keyAdapter, kv.Key, kv.Value)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		snapshot[uuid] = resc
 	}
-	return snapshot, nil
+	return snapshot, resp.Header.Revision, nil
 }
 
 // unmarshal wraps the unmarshal processing for key/value used in service discovery
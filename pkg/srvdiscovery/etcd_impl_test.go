@@ -7,7 +7,6 @@ import (
 	"time"
 
 	"github.com/hanfei1991/microcosm/pkg/adapter"
-	"github.com/hanfei1991/microcosm/pkg/errors"
 	"github.com/hanfei1991/microcosm/test"
 	"github.com/pingcap/tiflow/dm/pkg/log"
 	"github.com/stretchr/testify/require"
@@ -102,8 +101,48 @@ func TestEtcdDiscoveryAPI(t *testing.T) {
 	wresp := <-ch
 	require.Error(t, wresp.Err, context.Canceled.Error())
 
-	// test duplicate watch from service discovery
+	// test multiple watchers on one service discovery
+	ctx = context.Background()
+	put := func(uuid, addr string) {
+		value, err := json.Marshal(&ServiceResource{Addr: addr})
+		require.Nil(t, err)
+		_, err = client.Put(ctx, keyAdapter.Encode(uuid), string(value))
+		require.Nil(t, err)
+	}
+	ch1, ch2 := d.Watch(ctx), d.Watch(ctx)
+	put("uuid-6", "127.0.0.1:10006")
+	for _, ch := range []<-chan WatchResp{ch1, ch2} {
+		select {
+		case wresp := <-ch:
+			require.Nil(t, wresp.Err)
+			require.Equal(t, 1, len(wresp.AddSet))
+			require.Contains(t, wresp.AddSet, "uuid-6")
+			require.Empty(t, wresp.DelSet)
+		case <-time.After(time.Second):
+			require.Fail(t, "watch from service discovery timeout")
+		}
+	}
+	d.Close()
+	require.Error(t, (<-ch1).Err)
+	require.Error(t, (<-ch2).Err)
+
+	// test watch resumes with a full snapshot if the revision is compacted
+	_, err = client.Delete(ctx, keyAdapter.Encode("uuid-2"))
+	require.Nil(t, err)
+	resp, err := client.Put(ctx, keyAdapter.Encode("uuid-7"), "{}")
+	require.Nil(t, err)
+	_, err = client.Compact(ctx, resp.Header.Revision)
+	require.Nil(t, err)
 	ch = d.Watch(ctx)
-	wresp = <-ch
-	require.Error(t, wresp.Err, errors.ErrDiscoveryDuplicateWatch.GetMsg())
+	select {
+	case wresp := <-ch:
+		require.Nil(t, wresp.Err)
+		require.Equal(t, 1, len(wresp.AddSet))
+		require.Contains(t, wresp.AddSet, "uuid-7")
+		require.Equal(t, 1, len(wresp.DelSet))
+		require.Contains(t, wresp.DelSet, "uuid-2")
+	case <-time.After(time.Second):
+		require.Fail(t, "watch from service discovery timeout")
+	}
+	d.Close()
 }
@@ -0,0 +1,121 @@
+package srvdiscovery
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+)
+
+// loadFunc loads a full set of service resources from the backend.
+type loadFunc = func(ctx context.Context) (Snapshot, error)
+
+// pollingDiscovery implements Discovery for backends that don't support
+// watching, by loading the full set of service resources periodically.
+type pollingDiscovery struct {
+	load         loadFunc
+	pollInterval time.Duration
+
+	mu           sync.Mutex
+	snapshot     Snapshot
+	nextWatchID  int
+	watchCancels map[int]context.CancelFunc
+}
+
+func newPollingDiscovery(load loadFunc, pollInterval time.Duration) *pollingDiscovery {
+	return &pollingDiscovery{
+		load:         load,
+		pollInterval: pollInterval,
+		snapshot:     make(Snapshot),
+		watchCancels: make(map[int]context.CancelFunc),
+	}
+}
+
+// Snapshot implements Discovery.Snapshot
+func (d *pollingDiscovery) Snapshot(ctx context.Context) (Snapshot, error) {
+	snapshot, err := d.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	d.CopySnapshot(snapshot)
+	return snapshot, nil
+}
+
+// Watch implements Discovery.Watch. Each watcher loads the service resources
+// on its own interval, and receives the changes since the snapshot of the
+// discovery when it starts watching. Load errors are retried in the next
+// interval. The channel receives an error and stops when ctx is canceled or
+// the discovery is closed.
+func (d *pollingDiscovery) Watch(ctx context.Context) <-chan WatchResp {
+	ch := make(chan WatchResp, defaultWatchChanSize)
+	cctx, cancel := context.WithCancel(ctx)
+
+	d.mu.Lock()
+	view := d.snapshot.Clone()
+	watchID := d.nextWatchID
+	d.nextWatchID++
+	d.watchCancels[watchID] = cancel
+	d.mu.Unlock()
+
+	go func() {
+		defer func() {
+			d.mu.Lock()
+			delete(d.watchCancels, watchID)
+			d.mu.Unlock()
+			cancel()
+		}()
+		d.poll(cctx, view, ch)
+	}()
+	return ch
+}
+
+// CopySnapshot implements Discovery.CopySnapshot
+func (d *pollingDiscovery) CopySnapshot(snapshot Snapshot) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.snapshot = snapshot.Clone()
+}
+
+// Close implements Discovery.Close, it stops all watchers. Watch can still
+// be called after Close.
+func (d *pollingDiscovery) Close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, cancel := range d.watchCancels {
+		cancel()
+	}
+}
+
+func (d *pollingDiscovery) poll(ctx context.Context, view Snapshot, ch chan<- WatchResp) {
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			ch <- WatchResp{Err: ctx.Err()}
+			return
+		case <-ticker.C:
+		}
+
+		snapshot, err := d.load(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.L().Warn("service discovery failed to load service resources, retry later", zap.Error(err))
+			}
+			continue
+		}
+		addSet, delSet := diffSnapshot(view, snapshot)
+		view = snapshot
+		if len(addSet) == 0 && len(delSet) == 0 {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			ch <- WatchResp{Err: ctx.Err()}
+			return
+		case ch <- WatchResp{AddSet: addSet, DelSet: delSet}:
+		}
+	}
+}
@@ -24,6 +24,9 @@ type DiscoveryRunner interface {
 	GetSnapshot() Snapshot
 	// ApplyWatchResult applies changed ServiceResource to the snapshot of runner
 	ApplyWatchResult(WatchResp)
+	// UpdateValue updates the value registered by this node, the value is
+	// also used when the session is recreated.
+	UpdateValue(ctx context.Context, value string) error
 }
 
 // Snapshot alias to a map mapping from uuid to service resource (node info)
@@ -45,6 +48,8 @@ type DiscoveryRunnerImpl struct {
 	watchDur   time.Duration
 	key        string
 	value      string
+	// session is the latest session, the key is bound to its lease
+	session *concurrency.Session
 
 	snapshot         Snapshot
 	discovery        Discovery
@@ -87,6 +92,9 @@ func (dr *DiscoveryRunnerImpl) connectToEtcdDiscovery(
 	// initialize a new service discovery, if old discovery exists, clones its
 	// snapshot to the new one.
 	old := dr.snapshot.Clone()
+	if dr.discovery != nil {
+		dr.discovery.Close()
+	}
 	dr.discovery = NewEtcdSrvDiscovery(
 		dr.etcdCli, adapter.NodeInfoKeyAdapter, dr.watchDur)
 
@@ -114,6 +122,7 @@ func (dr *DiscoveryRunnerImpl) createSession(ctx context.Context, etcdCli *clien
 	if err != nil {
 		return nil, err
 	}
+	dr.session = session
 	return session, nil
 }
 
@@ -123,7 +132,6 @@ func (dr *DiscoveryRunnerImpl) ResetDiscovery(ctx context.Context, resetSession
 	if err != nil {
 		return nil, err
 	}
-	dr.discovery.Close()
 	dr.discoveryWatcher = dr.discovery.Watch(ctx)
 	return session, nil
 }
@@ -138,6 +146,16 @@ func (dr *DiscoveryRunnerImpl) GetWatcher() <-chan WatchResp {
 	return dr.discoveryWatcher
 }
 
+// UpdateValue implements DiscoveryRunner.UpdateValue
+func (dr *DiscoveryRunnerImpl) UpdateValue(ctx context.Context, value string) error {
+	dr.value = value
+	if dr.session == nil {
+		return nil
+	}
+	_, err := dr.etcdCli.Put(ctx, dr.key, dr.value, clientv3.WithLease(dr.session.Lease()))
+	return err
+}
+
 // ApplyWatchResult implements DiscoveryRunner.ApplyWatchResult
 func (dr *DiscoveryRunnerImpl) ApplyWatchResult(resp WatchResp) {
 	for uuid, add := range resp.AddSet {
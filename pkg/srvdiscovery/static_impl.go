@@ -0,0 +1,52 @@
+package srvdiscovery
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+var _ Discovery = (*StaticSrvDiscovery)(nil)
+
+// StaticSrvDiscovery implements Discovery interface based on a static file,
+// which is a JSON array of node infos, such as
+// `[{"type": 2, "id": "executor-1", "addr": "127.0.0.1:10241"}]`.
+// The file is read again every poll interval, so nodes can be changed by
+// editing the file.
+type StaticSrvDiscovery struct {
+	*pollingDiscovery
+
+	path string
+}
+
+// NewStaticSrvDiscovery creates a new StaticSrvDiscovery
+func NewStaticSrvDiscovery(path string, pollInterval time.Duration) *StaticSrvDiscovery {
+	d := &StaticSrvDiscovery{path: path}
+	d.pollingDiscovery = newPollingDiscovery(d.load, pollInterval)
+	return d
+}
+
+func (d *StaticSrvDiscovery) load(_ context.Context) (Snapshot, error) {
+	content, err := os.ReadFile(d.path)
+	if err != nil {
+		return nil, errors.ErrDiscoveryInvalidStaticFile.GenWithStack(
+			"service discovery failed to load static file %s: %v", d.path, err)
+	}
+	var nodes []ServiceResource
+	if err := json.Unmarshal(content, &nodes); err != nil {
+		return nil, errors.ErrDiscoveryInvalidStaticFile.GenWithStack(
+			"service discovery failed to load static file %s: %v", d.path, err)
+	}
+	snapshot := make(Snapshot, len(nodes))
+	for _, node := range nodes {
+		if node.ID == "" {
+			return nil, errors.ErrDiscoveryInvalidStaticFile.GenWithStack(
+				"service discovery static file %s contains a node without id", d.path)
+		}
+		snapshot[string(node.ID)] = node
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,55 @@
+package srvdiscovery
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	derrors "github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+func TestStaticSrvDiscovery(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	path := filepath.Join(t.TempDir(), "nodes.json")
+	d := NewStaticSrvDiscovery(path, 50*time.Millisecond)
+
+	_, err := d.Snapshot(ctx)
+	require.True(t, derrors.ErrDiscoveryInvalidStaticFile.Equal(err))
+
+	err = os.WriteFile(path, []byte(`[
+		{"type": 2, "id": "executor-1", "addr": "127.0.0.1:10241"},
+		{"type": 2, "id": "executor-2", "addr": "127.0.0.1:10242"}
+	]`), 0o600)
+	require.NoError(t, err)
+	snapshot, err := d.Snapshot(ctx)
+	require.NoError(t, err)
+	require.Len(t, snapshot, 2)
+	require.Equal(t, "127.0.0.1:10242", snapshot["executor-2"].Addr)
+
+	ch := d.Watch(ctx)
+	err = os.WriteFile(path, []byte(`[
+		{"type": 2, "id": "executor-2", "addr": "127.0.0.1:10242"},
+		{"type": 2, "id": "executor-3", "addr": "127.0.0.1:10243"}
+	]`), 0o600)
+	require.NoError(t, err)
+	select {
+	case wresp := <-ch:
+		require.NoError(t, wresp.Err)
+		require.Len(t, wresp.AddSet, 1)
+		require.Contains(t, wresp.AddSet, "executor-3")
+		require.Len(t, wresp.DelSet, 1)
+		require.Contains(t, wresp.DelSet, "executor-1")
+	case <-time.After(time.Second):
+		require.Fail(t, "watch from service discovery timeout")
+	}
+
+	cancel()
+	wresp := <-ch
+	require.ErrorIs(t, wresp.Err, context.Canceled)
+}
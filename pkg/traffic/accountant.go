@@ -0,0 +1,192 @@
+package traffic
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	derrors "github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+// Source is the component that the traffic goes through
+type Source string
+
+// Defines all traffic sources
+const (
+	SourceP2P       = Source("p2p")
+	SourceMetastore = Source("metastore")
+)
+
+// Direction is the direction of traffic
+type Direction string
+
+// Defines all traffic directions
+const (
+	DirectionSent     = Direction("sent")
+	DirectionReceived = Direction("received")
+)
+
+// Stat is the accumulated traffic of a job
+type Stat struct {
+	SentBytes     int64 `json:"sent-bytes"`
+	ReceivedBytes int64 `json:"received-bytes"`
+}
+
+type jobStat struct {
+	sent     atomic.Int64
+	received atomic.Int64
+	// exceeded is set when the soft limit is exceeded for the first time
+	exceeded atomic.Bool
+}
+
+func (s *jobStat) total() int64 {
+	return s.sent.Load() + s.received.Load()
+}
+
+// Accountant accumulates network traffic per job.
+// A soft limit on the total bytes (sent + received) of a job can be set.
+// Exceeding the soft limit is logged, and when enforcement is enabled,
+// further traffic of the job is rejected by Check.
+type Accountant struct {
+	mu   sync.RWMutex
+	jobs map[libModel.MasterID]*jobStat
+
+	softLimit int64
+	enforce   bool
+}
+
+// NewAccountant creates a new Accountant. softLimit <= 0 means no limit.
+func NewAccountant(softLimit int64, enforce bool) *Accountant {
+	return &Accountant{
+		jobs:      make(map[libModel.MasterID]*jobStat),
+		softLimit: softLimit,
+		enforce:   enforce,
+	}
+}
+
+func (a *Accountant) getOrCreate(jobID libModel.MasterID) *jobStat {
+	a.mu.RLock()
+	stat, ok := a.jobs[jobID]
+	a.mu.RUnlock()
+	if ok {
+		return stat
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if stat, ok := a.jobs[jobID]; ok {
+		return stat
+	}
+	stat = &jobStat{}
+	a.jobs[jobID] = stat
+	return stat
+}
+
+// Record records n bytes of traffic for the job.
+func (a *Accountant) Record(jobID libModel.MasterID, source Source, direction Direction, n int) {
+	if n <= 0 {
+		return
+	}
+	stat := a.getOrCreate(jobID)
+	switch direction {
+	case DirectionSent:
+		stat.sent.Add(int64(n))
+	case DirectionReceived:
+		stat.received.Add(int64(n))
+	}
+	jobTrafficBytesCounter.WithLabelValues(jobID, string(source), string(direction)).Add(float64(n))
+
+	if a.softLimit > 0 && stat.total() > a.softLimit && stat.exceeded.CAS(false, true) {
+		log.L().Warn("job traffic exceeds soft limit",
+			zap.String("job-id", jobID),
+			zap.Int64("soft-limit", a.softLimit),
+			zap.Int64("sent-bytes", stat.sent.Load()),
+			zap.Int64("received-bytes", stat.received.Load()),
+			zap.Bool("enforce", a.enforce))
+	}
+}
+
+// RecordStat records the traffic accumulated elsewhere, such as in a worker
+// process, to jobID.
+func (a *Accountant) RecordStat(jobID libModel.MasterID, source Source, stat Stat) {
+	a.Record(jobID, source, DirectionSent, int(stat.SentBytes))
+	a.Record(jobID, source, DirectionReceived, int(stat.ReceivedBytes))
+}
+
+// Check returns an error if the soft limit is enforced and the job
+// has exceeded it.
+func (a *Accountant) Check(jobID libModel.MasterID) error {
+	if !a.enforce || a.softLimit <= 0 {
+		return nil
+	}
+	a.mu.RLock()
+	stat, ok := a.jobs[jobID]
+	a.mu.RUnlock()
+	if ok && stat.exceeded.Load() {
+		return derrors.ErrJobTrafficExceedSoftLimit.GenWithStackByArgs(jobID, a.softLimit)
+	}
+	return nil
+}
+
+// Stat returns the accumulated traffic of the job.
+func (a *Accountant) Stat(jobID libModel.MasterID) (Stat, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	stat, ok := a.jobs[jobID]
+	if !ok {
+		return Stat{}, false
+	}
+	return Stat{SentBytes: stat.sent.Load(), ReceivedBytes: stat.received.Load()}, true
+}
+
+// Stats returns the accumulated traffic of all jobs.
+func (a *Accountant) Stats() map[libModel.MasterID]Stat {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	ret := make(map[libModel.MasterID]Stat, len(a.jobs))
+	for jobID, stat := range a.jobs {
+		ret[jobID] = Stat{SentBytes: stat.sent.Load(), ReceivedBytes: stat.received.Load()}
+	}
+	return ret
+}
+
+// RemoveJob removes the statistics of a job, and its metrics.
+func (a *Accountant) RemoveJob(jobID libModel.MasterID) {
+	a.mu.Lock()
+	delete(a.jobs, jobID)
+	a.mu.Unlock()
+
+	for _, source := range []Source{SourceP2P, SourceMetastore} {
+		for _, direction := range []Direction{DirectionSent, DirectionReceived} {
+			jobTrafficBytesCounter.DeleteLabelValues(jobID, string(source), string(direction))
+		}
+	}
+}
+
+type jobIDCtxKey struct{}
+
+// WithJobID returns a context tagged with the job ID. Traffic made with
+// the context is accounted to the job, regardless of the job the client
+// is created for.
+func WithJobID(ctx context.Context, jobID libModel.MasterID) context.Context {
+	return context.WithValue(ctx, jobIDCtxKey{}, jobID)
+}
+
+// JobIDFromContext returns the job ID tagged by WithJobID.
+func JobIDFromContext(ctx context.Context) (libModel.MasterID, bool) {
+	jobID, ok := ctx.Value(jobIDCtxKey{}).(libModel.MasterID)
+	return jobID, ok
+}
+
+func jobIDOrDefault(ctx context.Context, defaultJobID libModel.MasterID) libModel.MasterID {
+	if jobID, ok := JobIDFromContext(ctx); ok {
+		return jobID
+	}
+	return defaultJobID
+}
@@ -0,0 +1,149 @@
+package traffic
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/lib/statusutil"
+	"github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/meta/kvclient/mock"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+	"github.com/hanfei1991/microcosm/pkg/p2p"
+)
+
+type testMessage struct {
+	Value string `json:"value"`
+}
+
+func TestAccountantSoftLimit(t *testing.T) {
+	t.Parallel()
+
+	acct := NewAccountant(10, false)
+	acct.Record("job-1", SourceP2P, DirectionSent, 6)
+	acct.Record("job-1", SourceMetastore, DirectionReceived, 6)
+	acct.Record("job-2", SourceP2P, DirectionSent, 1)
+	// not enforced
+	require.NoError(t, acct.Check("job-1"))
+
+	stat, ok := acct.Stat("job-1")
+	require.True(t, ok)
+	require.Equal(t, Stat{SentBytes: 6, ReceivedBytes: 6}, stat)
+	require.Len(t, acct.Stats(), 2)
+
+	acct.RemoveJob("job-1")
+	_, ok = acct.Stat("job-1")
+	require.False(t, ok)
+
+	acct = NewAccountant(10, true)
+	acct.Record("job-1", SourceP2P, DirectionSent, 6)
+	require.NoError(t, acct.Check("job-1"))
+	acct.Record("job-1", SourceP2P, DirectionSent, 6)
+	err := acct.Check("job-1")
+	require.True(t, errors.ErrJobTrafficExceedSoftLimit.Equal(err))
+	require.NoError(t, acct.Check("job-2"))
+}
+
+func TestMessageSenderAndHandler(t *testing.T) {
+	t.Parallel()
+
+	acct := NewAccountant(0, false)
+	sender := NewMessageSender(p2p.NewMockMessageSender(), acct, "job-1")
+	ok, err := sender.SendToNode(context.Background(), "executor-1", "topic", &testMessage{Value: "a"})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// traffic with a tagged context goes to the tagged job
+	ctx := WithJobID(context.Background(), "job-2")
+	err = sender.SendToNodeB(ctx, "executor-1", "topic", &testMessage{Value: "a"})
+	require.NoError(t, err)
+
+	// size of `{"value":"a"}`
+	stat, ok := acct.Stat("job-1")
+	require.True(t, ok)
+	require.Equal(t, Stat{SentBytes: 13}, stat)
+	stat, ok = acct.Stat("job-2")
+	require.True(t, ok)
+	require.Equal(t, Stat{SentBytes: 13}, stat)
+
+	mockManager := p2p.NewMockMessageHandlerManager()
+	manager := NewMessageHandlerManager(mockManager, acct, "job-1")
+	received := false
+	ok, err = manager.RegisterHandler(context.Background(), "topic", &testMessage{},
+		func(sender p2p.NodeID, value p2p.MessageValue) error {
+			received = true
+			return nil
+		})
+	require.NoError(t, err)
+	require.True(t, ok)
+	err = mockManager.InvokeHandler(t, "topic", "executor-1", &testMessage{Value: "a"})
+	require.NoError(t, err)
+	require.True(t, received)
+
+	stat, _ = acct.Stat("job-1")
+	require.Equal(t, Stat{SentBytes: 13, ReceivedBytes: 13}, stat)
+}
+
+func TestMessageSenderExemptsControlTopics(t *testing.T) {
+	t.Parallel()
+
+	acct := NewAccountant(10, true)
+	sender := NewMessageSender(p2p.NewMockMessageSender(), acct, "job-1")
+	ctx := context.Background()
+	err := sender.SendToNodeB(ctx, "executor-1", "topic", &testMessage{Value: "a"})
+	require.NoError(t, err)
+
+	// the job is over the limit, only the heartbeats and statuses are sent
+	err = sender.SendToNodeB(ctx, "executor-1", "topic", &testMessage{Value: "a"})
+	require.True(t, errors.ErrJobTrafficExceedSoftLimit.Equal(err))
+	err = sender.SendToNodeB(ctx, "executor-1", libModel.HeartbeatPingTopic("job-1"),
+		&libModel.HeartbeatPingMessage{FromWorkerID: "worker-1"})
+	require.NoError(t, err)
+	_, err = sender.SendToNode(ctx, "executor-1", statusutil.WorkerStatusTopic("job-1"),
+		&statusutil.WorkerStatusMessage{Worker: "worker-1"})
+	require.NoError(t, err)
+
+	stat, _ := acct.Stat("job-1")
+	require.Greater(t, stat.SentBytes, int64(13))
+}
+
+func TestKVClient(t *testing.T) {
+	t.Parallel()
+
+	acct := NewAccountant(0, false)
+	cli := NewKVClient(mock.NewMetaMock(), acct, "job-1")
+	ctx := context.Background()
+
+	_, err := cli.Put(ctx, "key", "value")
+	require.NoError(t, err)
+	resp, err := cli.Get(ctx, "key")
+	require.NoError(t, err)
+	require.Len(t, resp.Kvs, 1)
+
+	stat, ok := acct.Stat("job-1")
+	require.True(t, ok)
+	require.Equal(t, Stat{SentBytes: 11, ReceivedBytes: 8}, stat)
+}
+
+func TestFrameMetaClient(t *testing.T) {
+	t.Parallel()
+
+	metaCli, err := pkgOrm.NewMockClient()
+	require.NoError(t, err)
+	defer metaCli.Close()
+
+	acct := NewAccountant(0, false)
+	cli := NewFrameMetaClient(metaCli, acct, "job-1")
+	ctx := context.Background()
+	err = cli.UpsertJob(ctx, &libModel.MasterMetaKVData{ID: "job-1"})
+	require.NoError(t, err)
+	_, err = cli.GetJobByID(ctx, "job-1")
+	require.NoError(t, err)
+
+	stat, ok := acct.Stat("job-1")
+	require.True(t, ok)
+	require.Greater(t, stat.SentBytes, int64(0))
+	require.Greater(t, stat.ReceivedBytes, int64(0))
+}
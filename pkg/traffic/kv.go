@@ -0,0 +1,153 @@
+package traffic
+
+import (
+	"context"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/pkg/meta/extension"
+	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
+)
+
+type accountError struct {
+	cause error
+}
+
+func (e *accountError) IsRetryable() bool {
+	return false
+}
+
+func (e *accountError) Error() string {
+	return e.cause.Error()
+}
+
+type kvClient struct {
+	extension.KVClientEx
+	accountant *Accountant
+	jobID      libModel.MasterID
+}
+
+// NewKVClient wraps a metastore client so that the bytes of keys and values
+// transmitted are accounted to jobID, or to the job tagged in the context.
+func NewKVClient(
+	cli extension.KVClientEx, accountant *Accountant, jobID libModel.MasterID,
+) extension.KVClientEx {
+	return &kvClient{
+		KVClientEx: cli,
+		accountant: accountant,
+		jobID:      jobID,
+	}
+}
+
+func (c *kvClient) Put(ctx context.Context, key, val string) (*metaclient.PutResponse, metaclient.Error) {
+	r, err := c.Do(ctx, metaclient.OpPut(key, val))
+	if err != nil {
+		return nil, err
+	}
+	return r.Put(), nil
+}
+
+func (c *kvClient) Get(ctx context.Context, key string, opts ...metaclient.OpOption) (*metaclient.GetResponse, metaclient.Error) {
+	r, err := c.Do(ctx, metaclient.OpGet(key, opts...))
+	if err != nil {
+		return nil, err
+	}
+	return r.Get(), nil
+}
+
+func (c *kvClient) Delete(ctx context.Context, key string, opts ...metaclient.OpOption) (*metaclient.DeleteResponse, metaclient.Error) {
+	r, err := c.Do(ctx, metaclient.OpDelete(key, opts...))
+	if err != nil {
+		return nil, err
+	}
+	return r.Del(), nil
+}
+
+func (c *kvClient) Do(ctx context.Context, op metaclient.Op) (metaclient.OpResponse, metaclient.Error) {
+	jobID := jobIDOrDefault(ctx, c.jobID)
+	if err := c.accountant.Check(jobID); err != nil {
+		return metaclient.OpResponse{}, &accountError{cause: err}
+	}
+	c.accountant.Record(jobID, SourceMetastore, DirectionSent, opSize(op))
+	r, err := c.KVClientEx.Do(ctx, op)
+	if err != nil {
+		return r, err
+	}
+	switch {
+	case r.Get() != nil:
+		c.accountant.Record(jobID, SourceMetastore, DirectionReceived, getResponseSize(r.Get()))
+	case r.Txn() != nil:
+		c.accountant.Record(jobID, SourceMetastore, DirectionReceived, txnResponseSize(r.Txn()))
+	}
+	return r, nil
+}
+
+type txn struct {
+	metaclient.Txn
+	kv    *kvClient
+	jobID libModel.MasterID
+}
+
+func (c *kvClient) Txn(ctx context.Context) metaclient.Txn {
+	return &txn{
+		Txn:   c.KVClientEx.Txn(ctx),
+		kv:    c,
+		jobID: jobIDOrDefault(ctx, c.jobID),
+	}
+}
+
+func (t *txn) Do(ops ...metaclient.Op) metaclient.Txn {
+	for _, op := range ops {
+		t.kv.accountant.Record(t.jobID, SourceMetastore, DirectionSent, opSize(op))
+	}
+	t.Txn = t.Txn.Do(ops...)
+	return t
+}
+
+func (t *txn) Commit() (*metaclient.TxnResponse, metaclient.Error) {
+	if err := t.kv.accountant.Check(t.jobID); err != nil {
+		return nil, &accountError{cause: err}
+	}
+	resp, err := t.Txn.Commit()
+	if err != nil {
+		return nil, err
+	}
+	t.kv.accountant.Record(t.jobID, SourceMetastore, DirectionReceived, txnResponseSize(resp))
+	return resp, nil
+}
+
+func opSize(op metaclient.Op) int {
+	if op.IsTxn() {
+		size := 0
+		for _, sub := range op.Txn() {
+			size += opSize(sub)
+		}
+		return size
+	}
+	return len(op.KeyBytes()) + len(op.RangeBytes()) + len(op.ValueBytes())
+}
+
+func getResponseSize(resp *metaclient.GetResponse) int {
+	size := 0
+	for _, kv := range resp.Kvs {
+		size += len(kv.Key) + len(kv.Value)
+	}
+	return size
+}
+
+func txnResponseSize(resp *metaclient.TxnResponse) int {
+	size := 0
+	for _, r := range resp.Responses {
+		switch tv := r.Response.(type) {
+		case *metaclient.ResponseOpResponseGet:
+			if tv.ResponseGet != nil {
+				size += getResponseSize(tv.ResponseGet)
+			}
+		case *metaclient.ResponseOpResponseTxn:
+			if tv.ResponseTxn != nil {
+				size += txnResponseSize(tv.ResponseTxn)
+			}
+		default:
+		}
+	}
+	return size
+}
@@ -0,0 +1,18 @@
+package traffic
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var jobTrafficBytesCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "dataflow",
+		Subsystem: "traffic",
+		Name:      "job_bytes_total",
+		Help:      "network traffic in bytes accounted to a job",
+	}, []string{"job_id", "source", "direction"})
+
+// InitMetrics registers the traffic metrics
+func InitMetrics(registry *prometheus.Registry) {
+	registry.MustRegister(jobTrafficBytesCounter)
+}
@@ -0,0 +1,18 @@
+package traffic
+
+import (
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+)
+
+// NewFrameMetaClient returns a framework metastore client whose statements
+// are accounted to jobID. The statements are never rejected, as the
+// framework metastore keeps the statuses of the job like the heartbeats.
+func NewFrameMetaClient(
+	cli pkgOrm.Client, accountant *Accountant, jobID libModel.MasterID,
+) pkgOrm.Client {
+	return cli.WithObserver(func(sent, received int) {
+		accountant.Record(jobID, SourceMetastore, DirectionSent, sent)
+		accountant.Record(jobID, SourceMetastore, DirectionReceived, received)
+	})
+}
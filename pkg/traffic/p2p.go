@@ -0,0 +1,101 @@
+package traffic
+
+import (
+	"context"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/lib/statusutil"
+	"github.com/hanfei1991/microcosm/pkg/p2p"
+)
+
+// isControlTopic returns whether the messages of topic keep the job alive.
+// They are accounted but never rejected, so that a job over the limit keeps
+// heartbeating and reporting its status instead of being timed out.
+func isControlTopic(topic p2p.Topic) bool {
+	return libModel.IsHeartbeatTopic(topic) || statusutil.IsWorkerStatusTopic(topic)
+}
+
+type messageSender struct {
+	p2p.MessageSender
+	accountant *Accountant
+	jobID      libModel.MasterID
+}
+
+// NewMessageSender wraps a p2p.MessageSender so that the sent bytes are
+// accounted to jobID, or to the job tagged in the context.
+func NewMessageSender(
+	sender p2p.MessageSender, accountant *Accountant, jobID libModel.MasterID,
+) p2p.MessageSender {
+	return &messageSender{
+		MessageSender: sender,
+		accountant:    accountant,
+		jobID:         jobID,
+	}
+}
+
+// withSentBytesRecorder checks the traffic of the job and returns a context
+// with which the size of the sent messages on the wire is recorded.
+func (s *messageSender) withSentBytesRecorder(
+	ctx context.Context, topic p2p.Topic,
+) (context.Context, error) {
+	jobID := jobIDOrDefault(ctx, s.jobID)
+	if !isControlTopic(topic) {
+		if err := s.accountant.Check(jobID); err != nil {
+			return nil, err
+		}
+	}
+	return p2p.WithWireSizeObserver(ctx, func(size int) {
+		s.accountant.Record(jobID, SourceP2P, DirectionSent, size)
+	}), nil
+}
+
+// SendToNode implements p2p.MessageSender.SendToNode
+func (s *messageSender) SendToNode(
+	ctx context.Context, targetNodeID p2p.NodeID, topic p2p.Topic, message interface{},
+) (bool, error) {
+	ctx, err := s.withSentBytesRecorder(ctx, topic)
+	if err != nil {
+		return false, err
+	}
+	return s.MessageSender.SendToNode(ctx, targetNodeID, topic, message)
+}
+
+// SendToNodeB implements p2p.MessageSender.SendToNodeB
+func (s *messageSender) SendToNodeB(
+	ctx context.Context, targetNodeID p2p.NodeID, topic p2p.Topic, message interface{},
+) error {
+	ctx, err := s.withSentBytesRecorder(ctx, topic)
+	if err != nil {
+		return err
+	}
+	return s.MessageSender.SendToNodeB(ctx, targetNodeID, topic, message)
+}
+
+type messageHandlerManager struct {
+	p2p.MessageHandlerManager
+	accountant *Accountant
+	jobID      libModel.MasterID
+}
+
+// NewMessageHandlerManager wraps a p2p.MessageHandlerManager so that the
+// bytes received by the handlers are accounted to jobID.
+func NewMessageHandlerManager(
+	manager p2p.MessageHandlerManager, accountant *Accountant, jobID libModel.MasterID,
+) p2p.MessageHandlerManager {
+	return &messageHandlerManager{
+		MessageHandlerManager: manager,
+		accountant:            accountant,
+		jobID:                 jobID,
+	}
+}
+
+// RegisterHandler implements p2p.MessageHandlerManager.RegisterHandler
+func (m *messageHandlerManager) RegisterHandler(
+	ctx context.Context, topic p2p.Topic, tpi p2p.TypeInformation, fn p2p.HandlerFunc,
+) (bool, error) {
+	jobID := jobIDOrDefault(ctx, m.jobID)
+	ctx = p2p.WithWireSizeObserver(ctx, func(size int) {
+		m.accountant.Record(jobID, SourceP2P, DirectionReceived, size)
+	})
+	return m.MessageHandlerManager.RegisterHandler(ctx, topic, tpi, fn)
+}
@@ -0,0 +1,117 @@
+package autoscaler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/hanfei1991/microcosm/model"
+	"github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+// TypeWebhook posts the demands to an HTTP endpoint in JSON, such as one
+// served by a Kubernetes operator managing the executors.
+const TypeWebhook = "webhook"
+
+// Demand describes the requests waiting for new executors
+type Demand struct {
+	PendingRequests int `json:"pending-requests"`
+	// Resources is the total resources required by the pending requests
+	Resources model.RescVector `json:"resources"`
+	// LargestRequest is the max resources required by a single request in
+	// each dimension, an executor smaller than it can't run every request.
+	LargestRequest model.RescVector `json:"largest-request"`
+}
+
+// Autoscaler adds executors to the cluster on demand. The executors are
+// expected to register to the server master after they are started, and
+// the pending requests are scheduled to them.
+type Autoscaler interface {
+	// ScaleOut asks for executors to run the pending requests, it doesn't
+	// need to wait for the executors to start. ScaleOut is called again
+	// after the scale-out interval if the requests are still pending, so
+	// the executors being added should be taken into account.
+	ScaleOut(ctx context.Context, demand *Demand) error
+	Close() error
+}
+
+// Factory creates an Autoscaler by the config, the config is adjusted.
+type Factory func(cfg *Config) (Autoscaler, error)
+
+var factories = struct {
+	sync.RWMutex
+	m map[string]Factory
+}{m: make(map[string]Factory)}
+
+// Register registers a factory of the given autoscaler type, so that an
+// autoscaler calling a cloud API for example can be built into the server
+// master and enabled by config. It should be called in init functions.
+func Register(tp string, factory Factory) {
+	factories.Lock()
+	defer factories.Unlock()
+	factories.m[tp] = factory
+}
+
+func getFactory(tp string) Factory {
+	factories.RLock()
+	defer factories.RUnlock()
+	return factories.m[tp]
+}
+
+// New creates an Autoscaler by the config, the config must be adjusted.
+func New(cfg *Config) (Autoscaler, error) {
+	if cfg.Type == TypeWebhook {
+		return newWebhookAutoscaler(cfg), nil
+	}
+	factory := getFactory(cfg.Type)
+	if factory == nil {
+		return nil, errors.ErrAutoscalerInvalidConfig.GenWithStackByArgs("unknown autoscaler type " + cfg.Type)
+	}
+	return factory(cfg)
+}
+
+type webhookAutoscaler struct {
+	url    string
+	client *http.Client
+}
+
+func newWebhookAutoscaler(cfg *Config) *webhookAutoscaler {
+	return &webhookAutoscaler{
+		url:    cfg.WebhookURL,
+		client: &http.Client{},
+	}
+}
+
+// ScaleOut posts the demand in JSON.
+func (s *webhookAutoscaler) ScaleOut(ctx context.Context, demand *Demand) error {
+	body, err := json.Marshal(demand)
+	if err != nil {
+		return errors.Wrap(errors.ErrAutoscalerScaleOutFailed, err, TypeWebhook)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(errors.ErrAutoscalerScaleOutFailed, err, TypeWebhook)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return errors.Wrap(errors.ErrAutoscalerScaleOutFailed, err, TypeWebhook)
+	}
+	defer resp.Body.Close()
+	// drain the body to reuse the connection
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.ErrAutoscalerScaleOutFailed.GenWithStack(
+			"scaling out executors by webhook autoscaler failed, status: %s", resp.Status)
+	}
+	return nil
+}
+
+func (s *webhookAutoscaler) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,95 @@
+package autoscaler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+const (
+	defaultMaxPendingWait     = "5s"
+	defaultScaleOutInterval   = "30s"
+	defaultMaxPendingRequests = 1024
+)
+
+// Config is the configuration of adding executors when the cluster doesn't
+// have enough resources to schedule workers
+type Config struct {
+	// Type is the type of the autoscaler, empty means no autoscaler is used
+	// and the requests are rejected at once if they can't be scheduled.
+	Type string `toml:"type" json:"type"`
+
+	// WebhookURL is the address the demands are posted to by the webhook
+	// autoscaler
+	WebhookURL string `toml:"webhook-url" json:"webhook-url"`
+	// Params are passed to the autoscalers registered by Register
+	Params map[string]string `toml:"params" json:"params"`
+
+	// MaxPendingWait is how long a request waits for new executors, it
+	// should be shorter than the timeout of scheduling a worker.
+	MaxPendingWaitStr string `toml:"max-pending-wait" json:"max-pending-wait"`
+	// ScaleOutInterval is the min interval between the scale-outs, so that
+	// the executors being added are not requested again.
+	ScaleOutIntervalStr string `toml:"scale-out-interval" json:"scale-out-interval"`
+	// MaxPendingRequests is the max number of requests waiting for new
+	// executors, other requests are rejected at once.
+	MaxPendingRequests int `toml:"max-pending-requests" json:"max-pending-requests"`
+
+	MaxPendingWait   time.Duration `toml:"-" json:"-"`
+	ScaleOutInterval time.Duration `toml:"-" json:"-"`
+}
+
+// Enabled returns whether an autoscaler is used
+func (c *Config) Enabled() bool {
+	return c != nil && c.Type != ""
+}
+
+// Adjust validates the config and fills the default values
+func (c *Config) Adjust() error {
+	if !c.Enabled() {
+		return nil
+	}
+	switch c.Type {
+	case TypeWebhook:
+		if c.WebhookURL == "" {
+			return errors.ErrAutoscalerInvalidConfig.GenWithStackByArgs(
+				"webhook-url is required by webhook autoscaler")
+		}
+	default:
+		if getFactory(c.Type) == nil {
+			return errors.ErrAutoscalerInvalidConfig.GenWithStackByArgs(
+				fmt.Sprintf("unknown autoscaler type %s", c.Type))
+		}
+	}
+
+	if c.MaxPendingRequests <= 0 {
+		c.MaxPendingRequests = defaultMaxPendingRequests
+	}
+	var err error
+	c.MaxPendingWait, err = parsePositiveDuration(
+		&c.MaxPendingWaitStr, defaultMaxPendingWait, "max-pending-wait")
+	if err != nil {
+		return err
+	}
+	c.ScaleOutInterval, err = parsePositiveDuration(
+		&c.ScaleOutIntervalStr, defaultScaleOutInterval, "scale-out-interval")
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func parsePositiveDuration(str *string, defaultStr string, name string) (time.Duration, error) {
+	if *str == "" {
+		*str = defaultStr
+	}
+	d, err := time.ParseDuration(*str)
+	if err != nil {
+		return 0, errors.ErrAutoscalerInvalidConfig.GenWithStackByArgs(fmt.Sprintf("%s: %v", name, err))
+	}
+	if d <= 0 {
+		return 0, errors.ErrAutoscalerInvalidConfig.GenWithStackByArgs(name + " must be positive")
+	}
+	return d, nil
+}
@@ -0,0 +1,203 @@
+package autoscaler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	"github.com/hanfei1991/microcosm/model"
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+type pendingRequest struct {
+	required model.RescVector
+}
+
+// PendingQueue holds the requests that can't be scheduled for the lack of
+// resources. It asks the autoscaler for executors to run the pending
+// requests, and retries scheduling them when new executors register.
+// A nil PendingQueue is valid and doesn't queue any request.
+type PendingQueue struct {
+	scaler           Autoscaler
+	maxPendingWait   time.Duration
+	scaleOutInterval time.Duration
+	maxPending       int
+
+	mu      sync.Mutex
+	pending map[*pendingRequest]struct{}
+	// registered is closed when an executor registers, and then replaced
+	// by a new channel.
+	registered   chan struct{}
+	lastScaleOut time.Time
+
+	notifyCh chan struct{}
+}
+
+// NewPendingQueue creates a PendingQueue, the config must be adjusted.
+func NewPendingQueue(scaler Autoscaler, cfg *Config) *PendingQueue {
+	return &PendingQueue{
+		scaler:           scaler,
+		maxPendingWait:   cfg.MaxPendingWait,
+		scaleOutInterval: cfg.ScaleOutInterval,
+		maxPending:       cfg.MaxPendingRequests,
+		pending:          make(map[*pendingRequest]struct{}),
+		registered:       make(chan struct{}),
+		notifyCh:         make(chan struct{}, 1),
+	}
+}
+
+// Schedule calls schedule to schedule a request requiring the given
+// resources. If the cluster doesn't have enough resources, the request is
+// queued and schedule is called again every time an executor registers,
+// until it succeeds or the max pending wait elapses. The last error is
+// returned if the request is never scheduled.
+func (q *PendingQueue) Schedule(
+	ctx context.Context, required model.RescVector, schedule func() error,
+) error {
+	if q == nil {
+		return schedule()
+	}
+	// gets the channel before scheduling, so that the executors registered
+	// during the scheduling are not missed.
+	registered := q.registeredCh()
+	err := schedule()
+	if err == nil || !derror.ErrClusterResourceNotEnough.Equal(err) {
+		return err
+	}
+
+	req := &pendingRequest{required: required}
+	if !q.enqueue(req) {
+		log.L().Warn("too many pending requests, request is rejected",
+			zap.Int("max-pending-requests", q.maxPending))
+		return err
+	}
+	defer q.dequeue(req)
+
+	timer := time.NewTimer(q.maxPendingWait)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return err
+		case <-timer.C:
+			return err
+		case <-registered:
+		}
+		registered = q.registeredCh()
+		err = schedule()
+		if err == nil || !derror.ErrClusterResourceNotEnough.Equal(err) {
+			return err
+		}
+	}
+}
+
+// OnExecutorRegistered wakes up the pending requests to retry scheduling.
+func (q *PendingQueue) OnExecutorRegistered() {
+	if q == nil {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	close(q.registered)
+	q.registered = make(chan struct{})
+}
+
+// PendingCount returns the number of the pending requests.
+func (q *PendingQueue) PendingCount() int {
+	if q == nil {
+		return 0
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending)
+}
+
+// Run scales out the cluster for the pending requests until ctx is canceled.
+func (q *PendingQueue) Run(ctx context.Context) error {
+	ticker := time.NewTicker(q.scaleOutInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case <-ticker.C:
+		case <-q.notifyCh:
+		}
+		q.scaleOut(ctx)
+	}
+}
+
+// Close closes the autoscaler, it should be called after Run returns.
+func (q *PendingQueue) Close() error {
+	return q.scaler.Close()
+}
+
+func (q *PendingQueue) scaleOut(ctx context.Context) {
+	q.mu.Lock()
+	demand := q.demandLocked()
+	if demand == nil || time.Since(q.lastScaleOut) < q.scaleOutInterval {
+		q.mu.Unlock()
+		return
+	}
+	// a failed scale-out is not retried until the next interval either, to
+	// avoid overwhelming the autoscaler.
+	q.lastScaleOut = time.Now()
+	q.mu.Unlock()
+
+	log.L().Info("scaling out executors for pending requests",
+		zap.Int("pending-requests", demand.PendingRequests),
+		zap.Stringer("resources", demand.Resources),
+		zap.Stringer("largest-request", demand.LargestRequest))
+	if err := q.scaler.ScaleOut(ctx, demand); err != nil {
+		log.L().Warn("failed to scale out executors", zap.Error(err))
+	}
+}
+
+// demandLocked returns the demand of the pending requests, or nil if no
+// request is pending.
+func (q *PendingQueue) demandLocked() *Demand {
+	if len(q.pending) == 0 {
+		return nil
+	}
+	demand := &Demand{
+		PendingRequests: len(q.pending),
+		Resources:       model.RescVector{},
+		LargestRequest:  model.RescVector{},
+	}
+	for req := range q.pending {
+		demand.Resources = demand.Resources.Add(req.required)
+		demand.LargestRequest = demand.LargestRequest.Max(req.required)
+	}
+	return demand
+}
+
+func (q *PendingQueue) enqueue(req *pendingRequest) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.pending) >= q.maxPending {
+		return false
+	}
+	q.pending[req] = struct{}{}
+	select {
+	case q.notifyCh <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+func (q *PendingQueue) dequeue(req *pendingRequest) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.pending, req)
+}
+
+func (q *PendingQueue) registeredCh() <-chan struct{} {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.registered
+}
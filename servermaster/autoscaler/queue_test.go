@@ -0,0 +1,171 @@
+package autoscaler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+
+	"github.com/hanfei1991/microcosm/model"
+	"github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+const testAutoscalerType = "test"
+
+type mockAutoscaler struct {
+	mu      sync.Mutex
+	demands []*Demand
+}
+
+func (s *mockAutoscaler) ScaleOut(_ context.Context, demand *Demand) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.demands = append(s.demands, demand)
+	return nil
+}
+
+func (s *mockAutoscaler) Close() error {
+	return nil
+}
+
+func (s *mockAutoscaler) getDemands() []*Demand {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*Demand(nil), s.demands...)
+}
+
+func init() {
+	Register(testAutoscalerType, func(cfg *Config) (Autoscaler, error) {
+		return &mockAutoscaler{}, nil
+	})
+}
+
+func TestConfigAdjust(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{}
+	require.NoError(t, cfg.Adjust())
+	require.False(t, cfg.Enabled())
+
+	cfg = &Config{Type: TypeWebhook, WebhookURL: "http://127.0.0.1:8080/scale-out"}
+	require.NoError(t, cfg.Adjust())
+	require.True(t, cfg.Enabled())
+	require.Equal(t, 5*time.Second, cfg.MaxPendingWait)
+	require.Equal(t, 30*time.Second, cfg.ScaleOutInterval)
+	require.Equal(t, defaultMaxPendingRequests, cfg.MaxPendingRequests)
+
+	cfg = &Config{Type: testAutoscalerType, MaxPendingWaitStr: "1s"}
+	require.NoError(t, cfg.Adjust())
+	require.Equal(t, time.Second, cfg.MaxPendingWait)
+	scaler, err := New(cfg)
+	require.NoError(t, err)
+	require.IsType(t, &mockAutoscaler{}, scaler)
+
+	for _, cfg := range []*Config{
+		{Type: TypeWebhook},
+		{Type: "unknown"},
+		{Type: testAutoscalerType, MaxPendingWaitStr: "-1s"},
+		{Type: testAutoscalerType, ScaleOutIntervalStr: "1"},
+	} {
+		err := cfg.Adjust()
+		require.True(t, errors.ErrAutoscalerInvalidConfig.Equal(err), "config: %+v, err: %v", cfg, err)
+	}
+}
+
+func TestPendingQueue(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	scaler := &mockAutoscaler{}
+	cfg := &Config{
+		Type:                testAutoscalerType,
+		MaxPendingWaitStr:   "10s",
+		ScaleOutIntervalStr: "1h",
+		MaxPendingRequests:  2,
+	}
+	require.NoError(t, cfg.Adjust())
+	q := NewPendingQueue(scaler, cfg)
+
+	// the request is scheduled at once
+	calls := 0
+	err := q.Schedule(ctx, model.RescVector{model.ResourceCPU: 1}, func() error {
+		calls++
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, calls)
+
+	// the requests are scheduled after executors register
+	executors := atomic.NewInt32(0)
+	schedule := func() error {
+		if executors.Load() == 0 {
+			return errors.ErrClusterResourceNotEnough.GenWithStackByArgs()
+		}
+		return nil
+	}
+	errCh := make(chan error, 2)
+	for _, cpu := range []int64{1, 3} {
+		required := model.RescVector{model.ResourceCPU: cpu, model.ResourceMemory: 1}
+		go func() {
+			errCh <- q.Schedule(ctx, required, schedule)
+		}()
+	}
+	require.Eventually(t, func() bool {
+		return q.PendingCount() == 2
+	}, time.Second, 10*time.Millisecond)
+	// the queue is full
+	err = q.Schedule(ctx, model.RescVector{model.ResourceCPU: 1}, schedule)
+	require.True(t, errors.ErrClusterResourceNotEnough.Equal(err))
+
+	// scales out for both the pending requests
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = q.Run(ctx)
+	}()
+	require.Eventually(t, func() bool {
+		return len(scaler.getDemands()) == 1
+	}, time.Second, 10*time.Millisecond)
+	demand := scaler.getDemands()[0]
+	require.Equal(t, &Demand{
+		PendingRequests: 2,
+		Resources:       model.RescVector{model.ResourceCPU: 4, model.ResourceMemory: 2},
+		LargestRequest:  model.RescVector{model.ResourceCPU: 3, model.ResourceMemory: 1},
+	}, demand)
+
+	executors.Inc()
+	q.OnExecutorRegistered()
+	for i := 0; i < 2; i++ {
+		require.NoError(t, <-errCh)
+	}
+	require.Equal(t, 0, q.PendingCount())
+	// no more scale-out in the interval
+	require.Len(t, scaler.getDemands(), 1)
+
+	// the request fails after the max pending wait
+	cfg.MaxPendingWait = 50 * time.Millisecond
+	timeoutQueue := NewPendingQueue(scaler, cfg)
+	err = timeoutQueue.Schedule(ctx, model.RescVector{model.ResourceCPU: 1}, func() error {
+		return errors.ErrClusterResourceNotEnough.GenWithStackByArgs()
+	})
+	require.True(t, errors.ErrClusterResourceNotEnough.Equal(err))
+
+	// a nil queue doesn't queue requests
+	var nilQueue *PendingQueue
+	calls = 0
+	err = nilQueue.Schedule(ctx, model.RescVector{model.ResourceCPU: 1}, func() error {
+		calls++
+		return errors.ErrClusterResourceNotEnough.GenWithStackByArgs()
+	})
+	require.Error(t, err)
+	require.Equal(t, 1, calls)
+	nilQueue.OnExecutorRegistered()
+
+	cancel()
+	wg.Wait()
+}
@@ -83,7 +83,8 @@ func (s *Server) leaderLoop(ctx context.Context) error {
 		err = s.leaderServiceFn(s.leaderCtx)
 		if err != nil {
 			if perrors.Cause(err) == context.Canceled ||
-				errors.ErrEtcdLeaderChanged.Equal(err) {
+				errors.ErrEtcdLeaderChanged.Equal(err) ||
+				errors.ErrLeaderCtxCanceled.Equal(err) {
 				log.L().Info("leader service exits", zap.Error(err))
 			} else if errors.ErrMasterSessionDone.Equal(err) {
 				log.L().Info("server master session done, reset session now", zap.Error(err))
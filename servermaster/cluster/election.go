@@ -15,6 +15,13 @@ import (
 	"golang.org/x/time/rate"
 )
 
+const (
+	observeRetryInterval = time.Second
+	// defaultSessionTTL is the TTL in seconds of the session if TTL is not
+	// configured, the same as the default of etcd.
+	defaultSessionTTL = 60
+)
+
 // Election is an interface that performs leader elections.
 type Election interface {
 	// Campaign returns only after being elected.
@@ -23,6 +30,15 @@ type Election interface {
 	// - resign: a function used to resign the leader.
 	// - err: indicates an IRRECOVERABLE error during election.
 	Campaign(ctx context.Context, selfID NodeID, timeout time.Duration) (leaderCtx context.Context, resignFn context.CancelFunc, err error)
+
+	// GetLeader returns the ID of the current leader, ErrMasterNoLeader is
+	// returned if there is no leader.
+	GetLeader(ctx context.Context) (NodeID, error)
+
+	// Observe returns a channel that receives the ID of the current leader,
+	// and then the ID of each new leader. It doesn't require the node to
+	// campaign. The channel is closed after ctx is canceled.
+	Observe(ctx context.Context) <-chan NodeID
 }
 
 // EtcdElectionConfig defines configurations used in etcd election
@@ -54,16 +70,25 @@ func NewEtcdElection(
 	session *concurrency.Session,
 	config EtcdElectionConfig,
 ) (*EtcdElection, error) {
-	ctx, cancel := context.WithTimeout(ctx, config.CreateSessionTimeout)
-	defer cancel()
-
 	var sess *concurrency.Session
 	if session == nil {
-		var err error
+		// The lease is granted within CreateSessionTimeout, but the session
+		// keeps it alive with ctx, a canceled context would make the
+		// session done as soon as it is created.
+		ttl := int64(config.TTL.Seconds())
+		if ttl <= 0 {
+			ttl = defaultSessionTTL
+		}
+		grantCtx, cancel := context.WithTimeout(ctx, config.CreateSessionTimeout)
+		lease, err := etcdClient.Grant(grantCtx, ttl)
+		cancel()
+		if err != nil {
+			return nil, derror.ErrMasterEtcdCreateSessionFail.Wrap(err).GenWithStackByArgs()
+		}
 		sess, err = concurrency.NewSession(
 			etcdClient,
 			concurrency.WithContext(ctx),
-			concurrency.WithTTL(int(config.TTL.Seconds())))
+			concurrency.WithLease(lease.ID))
 		if err != nil {
 			return nil, derror.ErrMasterEtcdCreateSessionFail.Wrap(err).GenWithStackByArgs()
 		}
@@ -115,7 +140,9 @@ func (e *EtcdElection) doCampaign(ctx context.Context, selfID NodeID, timeout ti
 	if err != nil {
 		return nil, nil, derror.ErrMasterEtcdElectionCampaignFail.Wrap(err)
 	}
-	retCtx := newLeaderCtx(ctx, e.session)
+	// The revision at which the leader key is created increases strictly
+	// with each elected leader, so it serves as the fencing token.
+	retCtx := newLeaderCtx(ctx, e.session, e.election.Rev())
 	resignFn := func() {
 		resignCtx, cancel := context.WithCancel(ctx)
 		defer cancel()
@@ -128,58 +155,136 @@ func (e *EtcdElection) doCampaign(ctx context.Context, selfID NodeID, timeout ti
 	return retCtx, resignFn, nil
 }
 
+// GetLeader implements Election.GetLeader
+func (e *EtcdElection) GetLeader(ctx context.Context) (NodeID, error) {
+	resp, err := e.election.Leader(ctx)
+	if err != nil {
+		if errors.Cause(err) == concurrency.ErrElectionNoLeader {
+			return "", derror.ErrMasterNoLeader.GenWithStackByArgs()
+		}
+		return "", derror.ErrEtcdAPIError.Wrap(err).GenWithStackByArgs()
+	}
+	return NodeID(resp.Kvs[0].Value), nil
+}
+
+// Observe implements Election.Observe
+func (e *EtcdElection) Observe(ctx context.Context) <-chan NodeID {
+	ch := make(chan NodeID, 1)
+	go func() {
+		defer close(ch)
+		var lastLeader NodeID
+		for {
+			// the channel returned by etcd is closed when ctx is canceled
+			// or the watch fails, in the latter case we observe again.
+			for resp := range e.election.Observe(ctx) {
+				if len(resp.Kvs) == 0 {
+					continue
+				}
+				leader := NodeID(resp.Kvs[0].Value)
+				if leader == lastLeader {
+					continue
+				}
+				lastLeader = leader
+				select {
+				case ch <- leader:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(observeRetryInterval):
+			}
+			log.L().Warn("observe leader interrupted, retry")
+		}
+	}()
+	return ch
+}
+
+type fencingTokenKey struct{}
+
+// FencingToken returns the fencing token of the leadership that ctx is
+// derived from. Tokens of later leaderships are always larger, so a write
+// carrying the token can be rejected if a newer leader has written since.
+// The second return value is false if ctx is not a leader context.
+func FencingToken(ctx context.Context) (int64, bool) {
+	token, ok := ctx.Value(fencingTokenKey{}).(int64)
+	return token, ok
+}
+
+// leaderCtx is canceled when the parent context is canceled, the session
+// expires or the leader resigns. A single goroutine waits for the three
+// events, so Done can be called any number of times without extra cost.
 type leaderCtx struct {
 	context.Context
-	sess     *concurrency.Session
-	closeCh  chan struct{}
-	innerErr struct {
-		sync.RWMutex
-		err error
+	sess         *concurrency.Session
+	fencingToken int64
+
+	doneCh     chan struct{}
+	cancelOnce sync.Once
+	mu         sync.RWMutex
+	err        error
+}
+
+func newLeaderCtx(parent context.Context, session *concurrency.Session, fencingToken int64) *leaderCtx {
+	c := &leaderCtx{
+		Context:      parent,
+		sess:         session,
+		fencingToken: fencingToken,
+		doneCh:       make(chan struct{}),
+	}
+	go c.watch()
+	return c
+}
+
+func (c *leaderCtx) watch() {
+	select {
+	case <-c.Context.Done():
+		// the upstream context is canceled
+		c.cancel(c.Context.Err())
+	case <-c.sess.Done():
+		// the session goes out
+		c.cancel(derror.ErrMasterSessionDone.GenWithStackByArgs())
+	case <-c.doneCh:
+		// we voluntarily resigned
 	}
 }
 
-func newLeaderCtx(parent context.Context, session *concurrency.Session) *leaderCtx {
-	return &leaderCtx{
-		Context: parent,
-		sess:    session,
-		closeCh: make(chan struct{}),
+func (c *leaderCtx) Value(key interface{}) interface{} {
+	if _, ok := key.(fencingTokenKey); ok {
+		return c.fencingToken
 	}
+	return c.Context.Value(key)
 }
 
+// OnResigned cancels the context with ErrLeaderCtxCanceled, unless it has
+// been canceled for another reason.
 func (c *leaderCtx) OnResigned() {
-	close(c.closeCh)
+	c.cancel(derror.ErrLeaderCtxCanceled.GenWithStackByArgs())
 }
 
-func (c *leaderCtx) Done() <-chan struct{} {
-	doneCh := make(chan struct{})
-	go func() {
-		// Handles the three situations where the context needs to be canceled.
-		select {
-		case <-c.Context.Done():
-			// the upstream context is canceled
-		case <-c.sess.Done():
-			// the session goes out
-			c.setError(derror.ErrMasterSessionDone.GenWithStackByArgs())
-		case <-c.closeCh:
-			// we voluntarily resigned
-			c.setError(derror.ErrLeaderCtxCanceled.GenWithStackByArgs())
-		}
-		close(doneCh)
-	}()
-	return doneCh
+func (c *leaderCtx) cancel(err error) {
+	c.cancelOnce.Do(func() {
+		c.mu.Lock()
+		c.err = err
+		c.mu.Unlock()
+		close(c.doneCh)
+	})
 }
 
-func (c *leaderCtx) Err() error {
-	c.innerErr.RLock()
-	defer c.innerErr.RUnlock()
-	if c.innerErr.err != nil {
-		return c.innerErr.err
-	}
-	return c.Context.Err()
+func (c *leaderCtx) Done() <-chan struct{} {
+	return c.doneCh
 }
 
-func (c *leaderCtx) setError(err error) {
-	c.innerErr.Lock()
-	defer c.innerErr.Unlock()
-	c.innerErr.err = err
+// Err returns nil if Done is not closed yet. Otherwise it returns the reason
+// of the first cancellation:
+// - ErrLeaderCtxCanceled if the leader resigned.
+// - ErrMasterSessionDone if the session expired.
+// - the error of the parent context if the parent is canceled.
+func (c *leaderCtx) Err() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.err
 }
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
 	"github.com/hanfei1991/microcosm/pkg/etcdutils"
 	"github.com/stretchr/testify/require"
 	clientv3 "go.etcd.io/etcd/client/v3"
@@ -117,8 +118,97 @@ func TestLeaderCtxCancelPropagate(t *testing.T) {
 	nodeID := "node-cancel-propagate"
 	sessCtx, resignFn, err := election.Campaign(ctx, nodeID, time.Second*5)
 	require.NoError(t, err)
-	_, cancel := context.WithCancel(sessCtx)
+	derivedCtx, cancel := context.WithCancel(sessCtx)
 	defer cancel()
+	require.NoError(t, sessCtx.Err())
+	// Done returns the same channel on every call
+	require.Equal(t, sessCtx.Done(), sessCtx.Done())
 	resignFn()
-	require.EqualError(t, sessCtx.Err(), "[DFLOW:ErrMasterSessionDone]master session is done")
+	<-derivedCtx.Done()
+	require.True(t, derror.ErrLeaderCtxCanceled.Equal(sessCtx.Err()))
+
+	// the leader context is canceled with the error of the parent context
+	parentCtx, cancelParent := context.WithCancel(ctx)
+	sessCtx, resignFn, err = election.Campaign(parentCtx, nodeID, time.Second*5)
+	require.NoError(t, err)
+	cancelParent()
+	<-sessCtx.Done()
+	require.ErrorIs(t, sessCtx.Err(), context.Canceled)
+	// resigning after cancellation doesn't change the error
+	resignFn()
+	require.ErrorIs(t, sessCtx.Err(), context.Canceled)
+}
+
+func TestEtcdElectionObserve(t *testing.T) {
+	newClient, closeFn := setUpTest(t)
+	defer closeFn()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	newElection := func() *EtcdElection {
+		election, err := NewEtcdElection(ctx, newClient(), nil, EtcdElectionConfig{
+			CreateSessionTimeout: 1 * time.Second,
+			TTL:                  5,
+			Prefix:               "/test-election-observe",
+		})
+		require.NoError(t, err)
+		return election
+	}
+
+	observer := newElection()
+	_, err := observer.GetLeader(ctx)
+	require.True(t, derror.ErrMasterNoLeader.Equal(err))
+
+	observeCtx, cancelObserve := context.WithCancel(ctx)
+	leaderCh := observer.Observe(observeCtx)
+
+	_, resignFn, err := newElection().Campaign(ctx, "node-1", time.Second*5)
+	require.NoError(t, err)
+	require.Equal(t, "node-1", <-leaderCh)
+	leader, err := observer.GetLeader(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "node-1", leader)
+
+	resignFn()
+	_, resignFn, err = newElection().Campaign(ctx, "node-2", time.Second*5)
+	require.NoError(t, err)
+	defer resignFn()
+	require.Equal(t, "node-2", <-leaderCh)
+
+	cancelObserve()
+	for range leaderCh {
+	}
+}
+
+func TestEtcdElectionFencingToken(t *testing.T) {
+	newClient, closeFn := setUpTest(t)
+	defer closeFn()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, ok := FencingToken(ctx)
+	require.False(t, ok)
+
+	var lastToken int64
+	for i := 0; i < 3; i++ {
+		election, err := NewEtcdElection(ctx, newClient(), nil, EtcdElectionConfig{
+			CreateSessionTimeout: 1 * time.Second,
+			TTL:                  5,
+			Prefix:               "/test-election-fencing",
+		})
+		require.NoError(t, err)
+
+		sessCtx, resignFn, err := election.Campaign(ctx, fmt.Sprintf("node-%d", i), time.Second*5)
+		require.NoError(t, err)
+		// the token is inherited by derived contexts
+		derivedCtx, cancelDerived := context.WithCancel(sessCtx)
+		token, ok := FencingToken(derivedCtx)
+		require.True(t, ok)
+		require.Greater(t, token, lastToken)
+		lastToken = token
+		cancelDerived()
+		resignFn()
+	}
 }
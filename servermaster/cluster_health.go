@@ -0,0 +1,91 @@
+package servermaster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	"github.com/hanfei1991/microcosm/lib/metadata"
+	"github.com/hanfei1991/microcosm/pb"
+)
+
+// metastoreProbeTimeout is the timeout of probing the framework metastore
+// when summarizing the cluster health.
+const metastoreProbeTimeout = 3 * time.Second
+
+// ClusterHealth implements pb.MasterServer.ClusterHealth
+func (s *Server) ClusterHealth(
+	ctx context.Context, req *pb.ClusterHealthRequest,
+) (*pb.ClusterHealthResponse, error) {
+	resp2 := &pb.ClusterHealthResponse{}
+	shouldRet, err := s.masterRPCHook.PreRPC(ctx, req, &resp2)
+	if shouldRet {
+		return resp2, err
+	}
+
+	resp := &pb.ClusterHealthResponse{
+		Healthy: true,
+		Leader:  s.name(),
+	}
+
+	// the job manager keeps its meta in the framework metastore, reading it
+	// is a cheap probe of the metastore.
+	probeCtx, cancel := context.WithTimeout(ctx, metastoreProbeTimeout)
+	defer cancel()
+	if _, err := s.frameMetaClient.GetJobByID(probeCtx, metadata.JobManagerUUID); err != nil {
+		resp.Healthy = false
+		resp.Problems = append(resp.Problems, fmt.Sprintf("framework metastore is unreachable: %v", err))
+	} else {
+		resp.MetastoreReachable = true
+	}
+
+	resp.Executors = int32(len(s.executorManager.ListExecutors()))
+	if resp.Executors == 0 {
+		resp.Problems = append(resp.Problems, "no executor is registered")
+	}
+	for _, executorID := range s.executorManager.StaleExecutors() {
+		resp.StaleExecutors = append(resp.StaleExecutors, string(executorID))
+	}
+	if len(resp.StaleExecutors) > 0 {
+		resp.Problems = append(resp.Problems,
+			fmt.Sprintf("%d executors are missing heartbeats", len(resp.StaleExecutors)))
+	}
+
+	resp.ErrorJobs = s.jobManager.ErrorJobs()
+	if len(resp.ErrorJobs) > 0 {
+		resp.Problems = append(resp.Problems,
+			fmt.Sprintf("%d jobs are in error states", len(resp.ErrorJobs)))
+	}
+	return resp, nil
+}
+
+// healthzHandler serves the cluster health summary for load balancer checks,
+// it responds 200 if the cluster is healthy and 503 otherwise. The request is
+// forwarded to the leader if this server is a follower.
+func (s *Server) healthzHandler(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.ClusterHealth(r.Context(), &pb.ClusterHealthRequest{})
+	if err == nil && resp.Err != nil {
+		err = fmt.Errorf("%s", resp.Err.Message)
+	}
+	if err != nil {
+		resp = &pb.ClusterHealthResponse{Problems: []string{err.Error()}}
+	}
+	writeClusterHealth(w, resp)
+}
+
+func writeClusterHealth(w http.ResponseWriter, resp *pb.ClusterHealthResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	if resp.Healthy {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.L().Warn("failed to write cluster health", zap.Error(err))
+	}
+}
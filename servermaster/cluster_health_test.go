@@ -0,0 +1,32 @@
+package servermaster
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hanfei1991/microcosm/pb"
+)
+
+func TestWriteClusterHealth(t *testing.T) {
+	t.Parallel()
+
+	w := httptest.NewRecorder()
+	writeClusterHealth(w, &pb.ClusterHealthResponse{
+		Healthy:            true,
+		MetastoreReachable: true,
+		StaleExecutors:     []string{"executor-1"},
+		Problems:           []string{"1 executors are missing heartbeats"},
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	resp := &pb.ClusterHealthResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), resp))
+	require.Equal(t, []string{"executor-1"}, resp.StaleExecutors)
+
+	w = httptest.NewRecorder()
+	writeClusterHealth(w, &pb.ClusterHealthResponse{Problems: []string{"no leader"}})
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
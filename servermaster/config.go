@@ -20,14 +20,22 @@ import (
 	"flag"
 	"fmt"
 	"net/url"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/hanfei1991/microcosm/pkg/errors"
 	"github.com/hanfei1991/microcosm/pkg/etcdutils"
+	"github.com/hanfei1991/microcosm/pkg/interceptor"
+	"github.com/hanfei1991/microcosm/pkg/meta/encryption"
 	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
 	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+	"github.com/hanfei1991/microcosm/pkg/p2p"
+	"github.com/hanfei1991/microcosm/pkg/redact"
+	"github.com/hanfei1991/microcosm/pkg/secret"
+	"github.com/hanfei1991/microcosm/pkg/sink"
+	"github.com/hanfei1991/microcosm/servermaster/autoscaler"
 	"github.com/pingcap/tiflow/dm/pkg/log"
 	"go.etcd.io/etcd/server/v3/embed"
 	"go.uber.org/zap"
@@ -42,9 +50,18 @@ const (
 	defaultCampaignTimeout    = 5 * time.Second
 	defaultDiscoverTicker     = 3 * time.Second
 	defaultMetricInterval     = 15 * time.Second
+	// idempotencyTTL is how long the results of the non-idempotent requests
+	// are kept for the retries from clients.
+	idempotencyTTL = time.Minute
 
 	defaultPeerUrls            = "http://127.0.0.1:8291"
 	defaultInitialClusterState = embed.ClusterStateFlagNew
+
+	defaultStandaloneMasterAddr   = "127.0.0.1:10240"
+	defaultStandaloneExecutorAddr = "127.0.0.1:10241"
+	defaultStandaloneName         = "standalone"
+	defaultStandaloneDataDir      = "standalone-data"
+	standaloneFrameMetaFile       = "framework-meta.db"
 )
 
 var (
@@ -84,6 +101,10 @@ func NewConfig() *Config {
 	fs.StringVar(&cfg.FrameMetaConf.Auth.Passwd, "frame-meta-password", pkgOrm.DefaultFrameMetaPassword, `framework metastore password`)
 	fs.StringVar(&cfg.UserMetaConf.Endpoints[0], "user-meta-endpoints", metaclient.DefaultUserMetaEndpoints, `user metastore endpoint`)
 
+	fs.StringVar(&cfg.DebugLockHoldThresholdStr, "debug-lock-hold-threshold", "", "log the stacks of locks held or waited for longer than the threshold, for debugging only")
+	fs.StringVar(&cfg.AdminAddr, "admin-addr", "", "listen address of the admin HTTP server for diagnosing, disabled if empty")
+	fs.BoolVar(&cfg.Standalone, "standalone", false, "run server master, an embedded metastore and an executor in a single process, for local development only")
+	fs.StringVar(&cfg.StandaloneExecutorAddr, "standalone-executor-addr", defaultStandaloneExecutorAddr, "listen address of the executor in standalone mode")
 	fs.StringVar(&cfg.Etcd.InitialCluster, "initial-cluster", "", fmt.Sprintf("initial cluster configuration for bootstrapping, e.g. dm-master=%s", defaultPeerUrls))
 	fs.StringVar(&cfg.Etcd.PeerUrls, "peer-urls", defaultPeerUrls, "URLs for peer traffic")
 	fs.StringVar(&cfg.Etcd.AdvertisePeerUrls, "advertise-peer-urls", "", `advertise URLs for peer traffic (default "${peer-urls}")`)
@@ -118,16 +139,66 @@ type Config struct {
 	KeepAliveIntervalStr string `toml:"keepalive-interval" json:"keepalive-interval"`
 	RPCTimeoutStr        string `toml:"rpc-timeout" json:"rpc-timeout"`
 
-	KeepAliveTTL      time.Duration `toml:"-" json:"-"`
-	KeepAliveInterval time.Duration `toml:"-" json:"-"`
-	RPCTimeout        time.Duration `toml:"-" json:"-"`
+	// SchedulerHeadroomPercent is the percentage of the cluster capacity
+	// reserved for re-dispatching workers after executor failures.
+	SchedulerHeadroomPercent int `toml:"scheduler-headroom-percent" json:"scheduler-headroom-percent"`
+
+	// Standalone runs an executor in the same process, and stores the
+	// framework metadata in a SQLite file and the user metadata in the
+	// embedded etcd, so that no external dependency is needed.
+	Standalone             bool   `toml:"standalone" json:"standalone"`
+	StandaloneExecutorAddr string `toml:"standalone-executor-addr" json:"standalone-executor-addr"`
+
+	// DebugLockHoldThresholdStr enables logging the stacks of instrumented
+	// locks held or waited for longer than it, empty means disabled.
+	DebugLockHoldThresholdStr string `toml:"debug-lock-hold-threshold" json:"debug-lock-hold-threshold"`
+	// AdminAddr is the listen address of the admin HTTP server, which serves
+	// pprof, runtime stats, goroutine dumps and the in-memory states of
+	// masters for diagnosing, empty means disabled.
+	AdminAddr string `toml:"admin-addr" json:"admin-addr"`
+	// GRPCServer configures the slow request logging and rate limiting of
+	// the gRPC server.
+	GRPCServer interceptor.Config `toml:"grpc-server" json:"grpc-server"`
+	// P2PMessage configures the size limit and the compression of the p2p
+	// messages sent and received by the job manager.
+	P2PMessage p2p.MessageConfig `toml:"p2p-message" json:"p2p-message"`
+
+	// Sink exports worker statuses and job events to an external system,
+	// events are not exported if the type of the sink is empty.
+	Sink sink.Config `toml:"sink" json:"sink"`
+
+	// Secret configures the key of the secrets in the framework metastore,
+	// which are referenced by the env of workers.
+	Secret secret.Config `toml:"secret" json:"secret"`
+
+	// UserMetaEncryption configures the keys the values in the user
+	// metastore are encrypted with, so that the credentials in the configs
+	// of user jobs aren't stored in plaintext.
+	UserMetaEncryption encryption.Config `toml:"user-meta-encryption" json:"user-meta-encryption"`
+
+	// Autoscaler adds executors when workers can't be scheduled for the
+	// lack of resources, no executor is added if the type is empty.
+	Autoscaler autoscaler.Config `toml:"autoscaler" json:"autoscaler"`
+
+	// JobRetention deletes finished jobs by their ages and numbers, finished
+	// jobs are kept forever if neither limit is set.
+	JobRetention JobRetentionConfig `toml:"job-retention" json:"job-retention"`
+
+	// ScheduleQueue holds the queued schedule requests waiting for the
+	// resources of the cluster.
+	ScheduleQueue ScheduleQueueConfig `toml:"schedule-queue" json:"schedule-queue"`
+
+	KeepAliveTTL           time.Duration `toml:"-" json:"-"`
+	KeepAliveInterval      time.Duration `toml:"-" json:"-"`
+	RPCTimeout             time.Duration `toml:"-" json:"-"`
+	DebugLockHoldThreshold time.Duration `toml:"-" json:"-"`
 
 	printVersion      bool
 	printSampleConfig bool
 }
 
 func (c *Config) String() string {
-	cfg, err := json.Marshal(c)
+	cfg, err := json.Marshal(redact.Value(c))
 	if err != nil {
 		log.L().Error("marshal to json", zap.Reflect("master config", c), log.ShortError(err))
 	}
@@ -189,11 +260,19 @@ func (c *Config) Parse(arguments []string) error {
 }
 
 func (c *Config) adjust() (err error) {
+	if c.Standalone {
+		c.adjustStandalone()
+	}
+
 	c.Etcd.Adjust(defaultPeerUrls, defaultInitialClusterState)
 
 	if c.AdvertiseAddr == "" {
 		c.AdvertiseAddr = c.MasterAddr
 	}
+	if c.Standalone {
+		// the embedded etcd serves as the user metastore
+		c.UserMetaConf.Endpoints = []string{c.AdvertiseAddr}
+	}
 
 	if c.KeepAliveIntervalStr == "" {
 		c.KeepAliveIntervalStr = defaultKeepAliveInterval
@@ -218,9 +297,67 @@ func (c *Config) adjust() (err error) {
 	if err != nil {
 		return err
 	}
+
+	if c.DebugLockHoldThresholdStr != "" {
+		c.DebugLockHoldThreshold, err = time.ParseDuration(c.DebugLockHoldThresholdStr)
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.SchedulerHeadroomPercent < 0 || c.SchedulerHeadroomPercent > 100 {
+		return errors.ErrMasterConfigInvalidFlag.GenWithStackByArgs("scheduler-headroom-percent")
+	}
+
+	if err := c.Sink.Adjust(); err != nil {
+		return err
+	}
+	if err := c.Secret.Adjust(); err != nil {
+		return err
+	}
+	if err := c.UserMetaEncryption.Adjust(); err != nil {
+		return err
+	}
+	if err := c.Autoscaler.Adjust(); err != nil {
+		return err
+	}
+	if err := c.JobRetention.Adjust(); err != nil {
+		return err
+	}
+	if err := c.ScheduleQueue.Adjust(); err != nil {
+		return err
+	}
+	if err := c.GRPCServer.Adjust(); err != nil {
+		return err
+	}
+	if err := c.P2PMessage.Adjust(); err != nil {
+		return err
+	}
 	return nil
 }
 
+// adjustStandalone fills the config items that don't need to be specified
+// in standalone mode, and replaces the external metastores with local ones.
+func (c *Config) adjustStandalone() {
+	if c.MasterAddr == "" {
+		c.MasterAddr = defaultStandaloneMasterAddr
+	}
+	if c.Etcd.Name == "" {
+		c.Etcd.Name = defaultStandaloneName
+	}
+	if c.Etcd.DataDir == "" {
+		c.Etcd.DataDir = defaultStandaloneDataDir
+	}
+	if c.StandaloneExecutorAddr == "" {
+		c.StandaloneExecutorAddr = defaultStandaloneExecutorAddr
+	}
+	c.FrameMetaConf = &metaclient.StoreConfigParams{
+		StoreID:   metaclient.FrameMetaID,
+		StoreType: metaclient.StoreTypeSQLite,
+		Endpoints: []string{filepath.Join(c.Etcd.DataDir, standaloneFrameMetaFile)},
+	}
+}
+
 // configFromFile loads config from file.
 func (c *Config) configFromFile(path string) error {
 	metaData, err := toml.DecodeFile(path, c)
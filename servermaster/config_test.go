@@ -2,7 +2,9 @@ package servermaster
 
 import (
 	"testing"
+	"time"
 
+	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
 	"github.com/stretchr/testify/require"
 )
 
@@ -34,3 +36,35 @@ func TestMetaStoreConfig(t *testing.T) {
 	require.Regexp(t, "root123", config.FrameMetaConf.Auth.Passwd)
 	require.Regexp(t, "...:2222$", config.UserMetaConf.Endpoints[0])
 }
+
+func TestStandaloneConfig(t *testing.T) {
+	t.Parallel()
+
+	config := NewConfig()
+	err := config.Parse([]string{"--standalone", "--data-dir", "/tmp/df-standalone"})
+	require.Nil(t, err)
+	require.Equal(t, defaultStandaloneMasterAddr, config.MasterAddr)
+	require.Equal(t, defaultStandaloneExecutorAddr, config.StandaloneExecutorAddr)
+	require.Equal(t, defaultStandaloneName, config.Etcd.Name)
+	require.Equal(t, metaclient.StoreTypeSQLite, config.FrameMetaConf.StoreType)
+	require.Equal(t, "/tmp/df-standalone/framework-meta.db", config.FrameMetaConf.Endpoints[0])
+	require.Equal(t, []string{defaultStandaloneMasterAddr}, config.UserMetaConf.Endpoints)
+}
+
+func TestDebugLockHoldThresholdConfig(t *testing.T) {
+	t.Parallel()
+
+	config := NewConfig()
+	err := config.Parse([]string{"--debug-lock-hold-threshold", "2s"})
+	require.Nil(t, err)
+	require.Equal(t, 2*time.Second, config.DebugLockHoldThreshold)
+
+	config = NewConfig()
+	err = config.Parse(nil)
+	require.Nil(t, err)
+	require.Equal(t, time.Duration(0), config.DebugLockHoldThreshold)
+
+	config = NewConfig()
+	err = config.Parse([]string{"--debug-lock-hold-threshold", "invalid"})
+	require.Error(t, err)
+}
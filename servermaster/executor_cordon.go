@@ -0,0 +1,102 @@
+package servermaster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	"github.com/hanfei1991/microcosm/model"
+	"github.com/hanfei1991/microcosm/pb"
+	derrors "github.com/hanfei1991/microcosm/pkg/errors"
+	ormModel "github.com/hanfei1991/microcosm/pkg/orm/model"
+)
+
+// CordonExecutor implements pb.MasterServer.CordonExecutor. A cordoned
+// executor keeps running its workers but gets no new placements, while a
+// blacklisted executor is treated as failed. The state is persisted, so it
+// survives the failover of server master.
+func (s *Server) CordonExecutor(
+	ctx context.Context, req *pb.CordonExecutorRequest,
+) (*pb.CordonExecutorResponse, error) {
+	resp2 := &pb.CordonExecutorResponse{}
+	shouldRet, err := s.masterRPCHook.PreRPC(ctx, req, &resp2)
+	if shouldRet {
+		return resp2, err
+	}
+
+	executorID := model.ExecutorID(req.GetExecutorId())
+	state := model.CordonState(req.GetState())
+	if err := s.cordonExecutor(ctx, executorID, state); err != nil {
+		return &pb.CordonExecutorResponse{Err: derrors.ToPBError(err)}, nil
+	}
+	return &pb.CordonExecutorResponse{}, nil
+}
+
+func (s *Server) cordonExecutor(ctx context.Context, executorID model.ExecutorID, state model.CordonState) error {
+	if executorID == "" {
+		return derrors.ErrInvalidExecutorCordon.GenWithStackByArgs("executor id is empty")
+	}
+	if _, ok := model.CordonStateNameMapping[state]; !ok {
+		return derrors.ErrInvalidExecutorCordon.GenWithStackByArgs(fmt.Sprintf("unknown state %d", state))
+	}
+
+	var err error
+	if state == model.Uncordoned {
+		_, err = s.frameMetaClient.DeleteExecutorCordon(ctx, string(executorID))
+	} else {
+		err = s.frameMetaClient.UpsertExecutorCordon(ctx, &ormModel.ExecutorCordon{
+			ExecutorID:  string(executorID),
+			Blacklisted: state == model.Blacklisted,
+		})
+	}
+	if err != nil {
+		return err
+	}
+	s.executorManager.CordonExecutor(executorID, state)
+	return nil
+}
+
+// QueryExecutorCordons implements pb.MasterServer.QueryExecutorCordons
+func (s *Server) QueryExecutorCordons(
+	ctx context.Context, req *pb.QueryExecutorCordonsRequest,
+) (*pb.QueryExecutorCordonsResponse, error) {
+	resp2 := &pb.QueryExecutorCordonsResponse{}
+	shouldRet, err := s.masterRPCHook.PreRPC(ctx, req, &resp2)
+	if shouldRet {
+		return resp2, err
+	}
+
+	resp := &pb.QueryExecutorCordonsResponse{}
+	for executorID, state := range s.executorManager.Cordons() {
+		resp.Cordons = append(resp.Cordons, &pb.ExecutorCordon{
+			ExecutorId: string(executorID),
+			State:      pb.CordonState(state),
+		})
+	}
+	sort.Slice(resp.Cordons, func(i, j int) bool {
+		return resp.Cordons[i].ExecutorId < resp.Cordons[j].ExecutorId
+	})
+	return resp, nil
+}
+
+// loadExecutorCordons restores the cordons persisted by the previous leaders
+func (s *Server) loadExecutorCordons(ctx context.Context) error {
+	cordons, err := s.frameMetaClient.QueryExecutorCordons(ctx)
+	if err != nil {
+		return err
+	}
+	states := make(map[model.ExecutorID]model.CordonState, len(cordons))
+	for _, cordon := range cordons {
+		state := model.Cordoned
+		if cordon.Blacklisted {
+			state = model.Blacklisted
+		}
+		states[model.ExecutorID(cordon.ExecutorID)] = state
+	}
+	s.executorManager.ResetCordons(states)
+	log.L().Info("executor cordons loaded", zap.Int("count", len(states)))
+	return nil
+}
@@ -2,6 +2,7 @@ package servermaster
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 
@@ -11,7 +12,9 @@ import (
 
 	"github.com/hanfei1991/microcosm/model"
 	"github.com/hanfei1991/microcosm/pb"
+	"github.com/hanfei1991/microcosm/pkg/compat"
 	"github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/eventbus"
 	"github.com/hanfei1991/microcosm/pkg/uuid"
 	"github.com/hanfei1991/microcosm/servermaster/resource"
 	"github.com/hanfei1991/microcosm/servermaster/scheduler"
@@ -30,6 +33,20 @@ type ExecutorManager interface {
 	ListExecutors() []string
 	CapacityProvider() scheduler.CapacityProvider
 	GetAddr(executorID model.ExecutorID) (string, bool)
+	// ClusterProtocolVersion returns the oldest protocol version of the
+	// server master and the executors.
+	ClusterProtocolVersion() compat.ProtocolVersion
+	// CordonExecutor sets the cordon state of an executor, which may not
+	// have registered yet. A blacklisted executor is removed at once.
+	CordonExecutor(executorID model.ExecutorID, state model.CordonState)
+	// ResetCordons replaces the cordon states of all executors, it is called
+	// with the persisted states when the server master becomes leader.
+	ResetCordons(cordons map[model.ExecutorID]model.CordonState)
+	// Cordons returns the executors that are cordoned or blacklisted.
+	Cordons() map[model.ExecutorID]model.CordonState
+	// StaleExecutors returns the executors missing heartbeats for over half
+	// of their heartbeat TTL, they are removed soon if they keep silent.
+	StaleExecutors() []model.ExecutorID
 }
 
 // ExecutorManagerImpl holds all the executors info, including liveness, status, resource usage.
@@ -38,6 +55,8 @@ type ExecutorManagerImpl struct {
 
 	mu        sync.Mutex
 	executors map[model.ExecutorID]*Executor
+	// cordons are the executors cordoned or blacklisted by operators
+	cordons map[model.ExecutorID]model.CordonState
 
 	idAllocator       uuid.Generator
 	initHeartbeatTTL  time.Duration
@@ -45,18 +64,29 @@ type ExecutorManagerImpl struct {
 
 	rescMgr resource.RescMgr
 	logRL   *rate.Limiter
+	// versionGate negotiates the protocol version with executors
+	versionGate *compat.Gate
+	// eventBus publishes the executors joined and gone
+	eventBus *eventbus.Bus
 }
 
 // NewExecutorManagerImpl creates a new ExecutorManagerImpl instance
-func NewExecutorManagerImpl(initHeartbeatTTL, keepAliveInterval time.Duration, ctx *test.Context) *ExecutorManagerImpl {
+func NewExecutorManagerImpl(
+	initHeartbeatTTL, keepAliveInterval time.Duration,
+	eventBus *eventbus.Bus,
+	ctx *test.Context,
+) *ExecutorManagerImpl {
 	return &ExecutorManagerImpl{
 		testContext:       ctx,
 		executors:         make(map[model.ExecutorID]*Executor),
+		cordons:           make(map[model.ExecutorID]model.CordonState),
 		idAllocator:       uuid.NewGenerator(),
 		initHeartbeatTTL:  initHeartbeatTTL,
 		keepAliveInterval: keepAliveInterval,
 		rescMgr:           resource.NewCapRescMgr(),
 		logRL:             rate.NewLimiter(rate.Every(time.Second*5), 1 /*burst*/),
+		versionGate:       compat.NewGate(),
+		eventBus:          eventBus,
 	}
 }
 
@@ -71,7 +101,12 @@ func (e *ExecutorManagerImpl) removeExecutorImpl(id model.ExecutorID) error {
 	}
 	delete(e.executors, id)
 	e.rescMgr.Unregister(id)
+	e.observeVersionChange(e.versionGate.Remove(string(id)))
 	log.L().Logger.Info("notify to offline exec")
+	eventbus.Publish(e.eventBus, eventbus.ExecutorOffline{
+		Time:       time.Now(),
+		ExecutorID: string(id),
+	})
 	if test.GetGlobalTestFlag() {
 		e.testContext.NotifyExecutorChange(&test.ExecutorChangeEvent{
 			Tp:   test.Delete,
@@ -87,6 +122,12 @@ func (e *ExecutorManagerImpl) HandleHeartbeat(req *pb.HeartbeatRequest) (*pb.Hea
 		log.L().Logger.Info("handle heart beat", zap.Stringer("req", req))
 	}
 	e.mu.Lock()
+	if e.cordons[model.ExecutorID(req.ExecutorId)] == model.Blacklisted {
+		// the executor exits once it knows it is regarded as dead
+		e.mu.Unlock()
+		err := errors.ErrTombstoneExecutor.FastGenByArgs(req.ExecutorId)
+		return &pb.HeartbeatResponse{Err: errors.ToPBError(err)}, nil
+	}
 	exec, ok := e.executors[model.ExecutorID(req.ExecutorId)]
 
 	// executor not exists
@@ -107,16 +148,39 @@ func (e *ExecutorManagerImpl) HandleHeartbeat(req *pb.HeartbeatRequest) (*pb.Hea
 	exec.lastUpdateTime = time.Now()
 	exec.heartbeatTTL = time.Duration(req.Ttl) * time.Millisecond
 	exec.Status = model.ExecutorStatus(req.Status)
-	usage := model.RescUnit(req.GetResourceUsage())
+	e.observeVersionChange(e.versionGate.Observe(
+		string(exec.ID), compat.ProtocolVersion(req.GetProtocolVersion())))
+	if exec.IdleEvictable != req.GetIdleEvictable() {
+		log.L().Info("executor idle-evictable state changes",
+			zap.String("executor-id", string(exec.ID)),
+			zap.Bool("idle-evictable", req.GetIdleEvictable()))
+		exec.IdleEvictable = req.GetIdleEvictable()
+	}
+	// resource_usage is sent by executors not aware of the other dimensions.
+	usage := model.RescUnit(req.GetResourceUsage()).Vector().Merge(req.GetResourceUsages())
 	// TODO: update reserve resources by heartbeats.
-	err := e.rescMgr.Update(exec.ID, usage, usage, exec.Status)
+	err := e.rescMgr.Update(exec.ID, usage, usage, exec.Status, exec.IdleEvictable)
 	if err != nil {
 		return nil, err
 	}
-	resp := &pb.HeartbeatResponse{}
+	resp := &pb.HeartbeatResponse{
+		ClusterProtocolVersion: int32(e.versionGate.ClusterVersion()),
+	}
 	return resp, nil
 }
 
+// ClusterProtocolVersion implements ExecutorManager.ClusterProtocolVersion
+func (e *ExecutorManagerImpl) ClusterProtocolVersion() compat.ProtocolVersion {
+	return e.versionGate.ClusterVersion()
+}
+
+func (e *ExecutorManagerImpl) observeVersionChange(changed bool) {
+	if changed {
+		log.L().Info("cluster protocol version changes",
+			zap.Int32("version", int32(e.versionGate.ClusterVersion())))
+	}
+}
+
 // RegisterExec registers executor to both executor manager and resource manager
 func (e *ExecutorManagerImpl) RegisterExec(info *model.NodeInfo) {
 	log.L().Info("register executor", zap.Any("info", info))
@@ -128,32 +192,138 @@ func (e *ExecutorManagerImpl) RegisterExec(info *model.NodeInfo) {
 		logRL:          rate.NewLimiter(rate.Every(time.Second*5), 1 /*burst*/),
 	}
 	e.mu.Lock()
+	state := e.cordons[info.ID]
+	if state == model.Blacklisted {
+		e.mu.Unlock()
+		log.L().Info("skip blacklisted executor", zap.String("executor-id", string(info.ID)))
+		return
+	}
 	e.executors[info.ID] = exec
 	e.mu.Unlock()
-	e.rescMgr.Register(exec.ID, exec.Addr, model.RescUnit(exec.Capability))
+	e.rescMgr.Register(exec.ID, exec.Addr, exec.CapacityVector(), exec.Zone())
+	if state == model.Cordoned {
+		if err := e.rescMgr.SetCordoned(exec.ID, true); err != nil {
+			log.L().Warn("failed to cordon executor", zap.String("executor-id", string(exec.ID)), zap.Error(err))
+		}
+	}
 }
 
 // AllocateNewExec allocates new executor info to a give RegisterExecutorRequest
-// and then registers the executor.
+// and then registers the executor. An executor that registers again after
+// a master failover carries its previous ID, which is kept.
 func (e *ExecutorManagerImpl) AllocateNewExec(req *pb.RegisterExecutorRequest) (*model.NodeInfo, error) {
 	log.L().Logger.Info("allocate new executor", zap.Stringer("req", req))
 
+	version := compat.ProtocolVersion(req.GetProtocolVersion())
+	if err := compat.CheckCompatible(version); err != nil {
+		return nil, err
+	}
+
+	id := model.ExecutorID(req.GetExecutorId())
+	if id == "" {
+		id = model.ExecutorID(e.idAllocator.NewString())
+	}
+
 	e.mu.Lock()
 	info := &model.NodeInfo{
-		ID:         model.ExecutorID(e.idAllocator.NewString()),
+		ID:         id,
 		Addr:       req.Address,
 		Capability: int(req.Capability),
+		Resources:  req.GetResources(),
+		Labels:     req.GetLabels(),
 	}
 	if _, ok := e.executors[info.ID]; ok {
 		e.mu.Unlock()
-		return nil, errors.ErrExecutorDupRegister.GenWithStackByArgs()
+		return nil, errors.ErrExecutorDupRegister.GenWithStackByArgs(info.ID)
+	}
+	if e.cordons[info.ID] == model.Blacklisted {
+		e.mu.Unlock()
+		return nil, errors.ErrExecutorBlacklisted.GenWithStackByArgs(info.ID)
 	}
 	e.mu.Unlock()
 
 	e.RegisterExec(info)
+	e.observeVersionChange(e.versionGate.Observe(string(info.ID), version))
+	eventbus.Publish(e.eventBus, eventbus.ExecutorJoined{
+		Time:       time.Now(),
+		ExecutorID: string(info.ID),
+		Addr:       info.Addr,
+	})
 	return info, nil
 }
 
+// CordonExecutor implements ExecutorManager.CordonExecutor
+func (e *ExecutorManagerImpl) CordonExecutor(executorID model.ExecutorID, state model.CordonState) {
+	e.mu.Lock()
+	if state == model.Uncordoned {
+		delete(e.cordons, executorID)
+	} else {
+		e.cordons[executorID] = state
+	}
+	_, exists := e.executors[executorID]
+	e.mu.Unlock()
+	log.L().Info("executor cordon state changes",
+		zap.String("executor-id", string(executorID)), zap.Stringer("state", state))
+
+	if state == model.Blacklisted {
+		if exists {
+			if err := e.removeExecutorImpl(executorID); err != nil {
+				log.L().Warn("failed to remove blacklisted executor",
+					zap.String("executor-id", string(executorID)), zap.Error(err))
+			}
+		}
+		// the workers on the executor are known by masters even if the
+		// executor has not registered again after failover
+		eventbus.Publish(e.eventBus, eventbus.ExecutorBlacklisted{
+			Time:       time.Now(),
+			ExecutorID: string(executorID),
+		})
+		return
+	}
+	if exists {
+		// the executor may have been removed in the meantime
+		_ = e.rescMgr.SetCordoned(executorID, state == model.Cordoned)
+	}
+}
+
+// ResetCordons implements ExecutorManager.ResetCordons
+func (e *ExecutorManagerImpl) ResetCordons(cordons map[model.ExecutorID]model.CordonState) {
+	for executorID := range e.Cordons() {
+		if _, ok := cordons[executorID]; !ok {
+			e.CordonExecutor(executorID, model.Uncordoned)
+		}
+	}
+	for executorID, state := range cordons {
+		e.CordonExecutor(executorID, state)
+	}
+}
+
+// Cordons implements ExecutorManager.Cordons
+func (e *ExecutorManagerImpl) Cordons() map[model.ExecutorID]model.CordonState {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	ret := make(map[model.ExecutorID]model.CordonState, len(e.cordons))
+	for executorID, state := range e.cordons {
+		ret[executorID] = state
+	}
+	return ret
+}
+
+// StaleExecutors implements ExecutorManager.StaleExecutors
+func (e *ExecutorManagerImpl) StaleExecutors() []model.ExecutorID {
+	now := time.Now()
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var ret []model.ExecutorID
+	for id, exec := range e.executors {
+		if exec.isStale(now) {
+			ret = append(ret, id)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i] < ret[j] })
+	return ret
+}
+
 // HasExecutor implements ExecutorManager.HasExecutor
 func (e *ExecutorManagerImpl) HasExecutor(executorID string) bool {
 	e.mu.Lock()
@@ -202,6 +372,12 @@ func (e *Executor) checkAlive() bool {
 	return true
 }
 
+func (e *Executor) isStale(now time.Time) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return now.Sub(e.lastUpdateTime) > e.heartbeatTTL/2
+}
+
 // Start check alive goroutine.
 func (e *ExecutorManagerImpl) Start(ctx context.Context) {
 	go e.checkAlive(ctx)
@@ -222,6 +398,7 @@ func (e *ExecutorManagerImpl) checkAlive(ctx context.Context) {
 				log.L().Logger.Info("check alive meet error", zap.Error(err))
 			}
 		case <-ctx.Done():
+			return
 		}
 	}
 }
@@ -9,6 +9,8 @@ import (
 
 	"github.com/hanfei1991/microcosm/model"
 	"github.com/hanfei1991/microcosm/pb"
+	"github.com/hanfei1991/microcosm/pkg/compat"
+	"github.com/hanfei1991/microcosm/pkg/errors"
 )
 
 func TestExecutorManager(t *testing.T) {
@@ -18,7 +20,7 @@ func TestExecutorManager(t *testing.T) {
 	defer cancel()
 	heartbeatTTL := time.Millisecond * 100
 	checkInterval := time.Millisecond * 10
-	mgr := NewExecutorManagerImpl(heartbeatTTL, checkInterval, nil)
+	mgr := NewExecutorManagerImpl(heartbeatTTL, checkInterval, nil, nil)
 
 	// register an executor server
 	executorAddr := "127.0.0.1:10001"
@@ -65,3 +67,126 @@ func TestExecutorManager(t *testing.T) {
 	require.NotNil(t, resp.Err)
 	require.Equal(t, pb.ErrorCode_UnknownExecutor, resp.Err.GetCode())
 }
+
+func TestExecutorManagerReregister(t *testing.T) {
+	t.Parallel()
+
+	mgr := NewExecutorManagerImpl(time.Second, time.Second, nil, nil)
+	info, err := mgr.AllocateNewExec(&pb.RegisterExecutorRequest{
+		Address:    "127.0.0.1:10001",
+		Capability: 2,
+		ExecutorId: "executor-1",
+	})
+	require.NoError(t, err)
+	require.Equal(t, model.ExecutorID("executor-1"), info.ID)
+	require.True(t, mgr.HasExecutor("executor-1"))
+
+	// the executor is still known, the duplicated registration is rejected
+	_, err = mgr.AllocateNewExec(&pb.RegisterExecutorRequest{
+		Address:    "127.0.0.1:10001",
+		Capability: 2,
+		ExecutorId: "executor-1",
+	})
+	require.Error(t, err)
+}
+
+func TestExecutorManagerProtocolVersion(t *testing.T) {
+	t.Parallel()
+
+	mgr := NewExecutorManagerImpl(time.Second, time.Second, nil, nil)
+	require.Equal(t, compat.CurrentProtocolVersion, mgr.ClusterProtocolVersion())
+
+	_, err := mgr.AllocateNewExec(&pb.RegisterExecutorRequest{
+		Address:         "127.0.0.1:10001",
+		Capability:      2,
+		ProtocolVersion: int32(compat.MaxCompatibleProtocolVersion + 1),
+	})
+	require.True(t, errors.ErrIncompatibleProtocolVersion.Equal(err))
+
+	// an executor of the old version lowers the cluster version
+	oldExec, err := mgr.AllocateNewExec(&pb.RegisterExecutorRequest{
+		Address:    "127.0.0.1:10001",
+		Capability: 2,
+	})
+	require.NoError(t, err)
+	newExec, err := mgr.AllocateNewExec(&pb.RegisterExecutorRequest{
+		Address:         "127.0.0.1:10002",
+		Capability:      2,
+		ProtocolVersion: int32(compat.CurrentProtocolVersion),
+	})
+	require.NoError(t, err)
+	require.Equal(t, compat.ProtocolVersionBase, mgr.ClusterProtocolVersion())
+
+	// the cluster version is raised after the old executor is removed
+	resp, err := mgr.HandleHeartbeat(&pb.HeartbeatRequest{
+		ExecutorId:      string(newExec.ID),
+		Status:          int32(model.Running),
+		ProtocolVersion: int32(compat.CurrentProtocolVersion),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(compat.ProtocolVersionBase), resp.ClusterProtocolVersion)
+	require.NoError(t, mgr.removeExecutorImpl(oldExec.ID))
+	resp, err = mgr.HandleHeartbeat(&pb.HeartbeatRequest{
+		ExecutorId:      string(newExec.ID),
+		Status:          int32(model.Running),
+		ProtocolVersion: int32(compat.CurrentProtocolVersion),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(compat.CurrentProtocolVersion), resp.ClusterProtocolVersion)
+}
+
+func TestExecutorManagerCordon(t *testing.T) {
+	t.Parallel()
+
+	mgr := NewExecutorManagerImpl(time.Second, time.Second, nil, nil)
+	registerReq := &pb.RegisterExecutorRequest{
+		Address:    "127.0.0.1:10001",
+		Capability: 2,
+		ExecutorId: "executor-1",
+	}
+	_, err := mgr.AllocateNewExec(registerReq)
+	require.NoError(t, err)
+	capacities := mgr.CapacityProvider()
+
+	// a cordoned executor keeps running but gets no new placements
+	mgr.CordonExecutor("executor-1", model.Cordoned)
+	require.Empty(t, capacities.CapacitiesForAllExecutors())
+	status, ok := capacities.CapacityForExecutor("executor-1")
+	require.True(t, ok)
+	require.True(t, status.Cordoned)
+	resp, err := mgr.HandleHeartbeat(&pb.HeartbeatRequest{
+		ExecutorId: "executor-1",
+		Status:     int32(model.Running),
+	})
+	require.NoError(t, err)
+	require.Nil(t, resp.Err)
+	mgr.CordonExecutor("executor-1", model.Uncordoned)
+	require.Contains(t, capacities.CapacitiesForAllExecutors(), model.ExecutorID("executor-1"))
+
+	// a blacklisted executor is removed and told to exit
+	mgr.CordonExecutor("executor-1", model.Blacklisted)
+	require.False(t, mgr.HasExecutor("executor-1"))
+	resp, err = mgr.HandleHeartbeat(&pb.HeartbeatRequest{
+		ExecutorId: "executor-1",
+		Status:     int32(model.Running),
+	})
+	require.NoError(t, err)
+	require.Equal(t, pb.ErrorCode_TombstoneExecutor, resp.Err.GetCode())
+	_, err = mgr.AllocateNewExec(registerReq)
+	require.True(t, errors.ErrExecutorBlacklisted.Equal(err))
+	require.Equal(t, map[model.ExecutorID]model.CordonState{
+		"executor-1": model.Blacklisted,
+	}, mgr.Cordons())
+
+	// the cordons are replaced by the persisted ones after failover
+	mgr.ResetCordons(map[model.ExecutorID]model.CordonState{"executor-2": model.Cordoned})
+	require.Equal(t, map[model.ExecutorID]model.CordonState{
+		"executor-2": model.Cordoned,
+	}, mgr.Cordons())
+	_, err = mgr.AllocateNewExec(registerReq)
+	require.NoError(t, err)
+	mgr.RegisterExec(&model.NodeInfo{ID: "executor-2", Addr: "127.0.0.1:10002", Capability: 2})
+	status, ok = capacities.CapacityForExecutor("executor-2")
+	require.True(t, ok)
+	require.True(t, status.Cordoned)
+}
@@ -3,6 +3,9 @@ package servermaster
 import (
 	"net/http"
 	"net/http/pprof"
+
+	"github.com/hanfei1991/microcosm/lib/master"
+	"github.com/hanfei1991/microcosm/lib/metadata"
 )
 
 // getDebugHandler returns a HTTP handler to handle debug information.
@@ -15,3 +18,21 @@ func getDebugHandler() http.Handler {
 	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
 	return mux
 }
+
+type workerManagerDumper interface {
+	WorkerManagerDebugInfo() *master.DebugInfo
+}
+
+// dumpMaster implements adminserver.MasterDumper. Only the job manager runs
+// in the server master, the states of job masters are dumped by the admin
+// server of the executors running them.
+func (s *Server) dumpMaster(masterID string) (interface{}, bool) {
+	if masterID != metadata.JobManagerUUID || !s.leaderInitialized.Load() {
+		return nil, false
+	}
+	dumper, ok := s.jobManager.(workerManagerDumper)
+	if !ok {
+		return nil, false
+	}
+	return dumper.WorkerManagerDebugInfo(), true
+}
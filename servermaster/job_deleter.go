@@ -0,0 +1,206 @@
+package servermaster
+
+import (
+	"context"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	"github.com/hanfei1991/microcosm/lib/metadata"
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/pkg/adapter"
+	derrors "github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/logutil"
+	"github.com/hanfei1991/microcosm/pkg/meta/extension"
+	"github.com/hanfei1991/microcosm/pkg/meta/kvclient"
+	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+	"github.com/hanfei1991/microcosm/pkg/orm/model"
+	"github.com/hanfei1991/microcosm/pkg/tenant"
+)
+
+// Stages of a job deletion, they are run in order and each of them is
+// idempotent, so a deletion can be resumed from the stage it has recorded.
+// The master meta is deleted at last, a job is visible until all of its
+// data has been cleaned.
+const (
+	jobDeletionStageWorkers    = "workers"
+	jobDeletionStageUserMeta   = "user-meta"
+	jobDeletionStageResources  = "resources"
+	jobDeletionStageAudit      = "audit"
+	jobDeletionStageMasterMeta = "master-meta"
+)
+
+var jobDeletionStages = []string{
+	jobDeletionStageWorkers,
+	jobDeletionStageUserMeta,
+	jobDeletionStageResources,
+	jobDeletionStageAudit,
+	jobDeletionStageMasterMeta,
+}
+
+// jobScopedKeyAdapters are the key adapters whose keys start with a job ID in
+// user metastore
+var jobScopedKeyAdapters = []adapter.KeyAdapter{
+	adapter.BarrierKeyAdapter,
+	adapter.BarrierReachedKeyAdapter,
+	adapter.EffectKeyAdapter,
+	adapter.DMJobKeyAdapter,
+}
+
+// jobDeleter deletes a job together with all the data belonging to it. The
+// progress is persisted in framework metastore, so that a deletion is resumed
+// by the next server master leader if the current one fails midway.
+// Note the event journal of a job master is kept in memory only, it is gone
+// together with the job master.
+type jobDeleter struct {
+	frameMetaClient pkgOrm.Client
+	// barriers are persisted in the raw user metastore, while the meta of
+	// job masters and workers are in the default user tenant.
+	userKVClients []metaclient.KV
+}
+
+func newJobDeleter(frameMetaClient pkgOrm.Client, userRawKVClient extension.KVClientEx) *jobDeleter {
+	return &jobDeleter{
+		frameMetaClient: frameMetaClient,
+		userKVClients: []metaclient.KV{
+			userRawKVClient,
+			kvclient.NewPrefixKVClient(userRawKVClient, tenant.DefaultUserTenantID),
+		},
+	}
+}
+
+// Delete deletes a job, only finished or stopped jobs can be deleted unless
+// force is true. A job whose master meta has been deleted can still be
+// deleted again if its previous deletion is not finished.
+func (d *jobDeleter) Delete(ctx context.Context, jobID libModel.MasterID, force bool) error {
+	job, err := d.frameMetaClient.GetJobByID(ctx, jobID)
+	if err != nil {
+		if !pkgOrm.IsNotFoundError(err) {
+			return err
+		}
+		deletion, err := d.queryDeletion(ctx, jobID)
+		if err != nil {
+			return err
+		}
+		if deletion == nil {
+			return derrors.ErrJobNotFound.GenWithStackByArgs(jobID)
+		}
+		return d.run(ctx, deletion)
+	}
+
+	if !force && job.StatusCode != libModel.MasterStatusFinished &&
+		job.StatusCode != libModel.MasterStatusStopped {
+		return derrors.ErrJobNotTerminated.GenWithStackByArgs(jobID, job.StatusCode)
+	}
+
+	deletion := &model.JobDeletion{
+		JobID: jobID,
+		Force: force,
+		Stage: jobDeletionStages[0],
+	}
+	if err := d.frameMetaClient.UpsertJobDeletion(ctx, deletion); err != nil {
+		return err
+	}
+	return d.run(ctx, deletion)
+}
+
+// Resume runs all unfinished deletions, it returns the IDs of jobs whose
+// deletion failed again.
+func (d *jobDeleter) Resume(ctx context.Context) (map[libModel.MasterID]struct{}, error) {
+	deletions, err := d.frameMetaClient.QueryJobDeletions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	failed := make(map[libModel.MasterID]struct{})
+	for _, deletion := range deletions {
+		logger := logutil.WithJobID(log.L(), deletion.JobID)
+		logger.Info("resume job deletion", zap.String("stage", deletion.Stage))
+		if err := d.run(ctx, deletion); err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			logger.Warn("resume job deletion failed", zap.Error(err))
+			failed[deletion.JobID] = struct{}{}
+		}
+	}
+	return failed, nil
+}
+
+func (d *jobDeleter) queryDeletion(ctx context.Context, jobID libModel.MasterID) (*model.JobDeletion, error) {
+	deletions, err := d.frameMetaClient.QueryJobDeletions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, deletion := range deletions {
+		if deletion.JobID == jobID {
+			return deletion, nil
+		}
+	}
+	return nil, nil
+}
+
+func (d *jobDeleter) run(ctx context.Context, deletion *model.JobDeletion) error {
+	logger := logutil.WithJobID(log.L(), deletion.JobID)
+
+	start := 0
+	for i, stage := range jobDeletionStages {
+		if stage == deletion.Stage {
+			start = i
+			break
+		}
+	}
+	for i := start; i < len(jobDeletionStages); i++ {
+		stage := jobDeletionStages[i]
+		if stage != deletion.Stage {
+			deletion.Stage = stage
+			if err := d.frameMetaClient.UpsertJobDeletion(ctx, deletion); err != nil {
+				return err
+			}
+		}
+		if err := d.runStage(ctx, deletion.JobID, stage); err != nil {
+			logger.Warn("job deletion stage failed", zap.String("stage", stage), zap.Error(err))
+			return err
+		}
+		logger.Info("job deletion stage finished", zap.String("stage", stage))
+	}
+
+	_, err := d.frameMetaClient.DeleteJobDeletion(ctx, deletion.JobID)
+	return err
+}
+
+func (d *jobDeleter) runStage(ctx context.Context, jobID libModel.MasterID, stage string) error {
+	switch stage {
+	case jobDeletionStageWorkers:
+		if _, err := d.frameMetaClient.DeleteWorkersByMasterID(ctx, jobID); err != nil {
+			return err
+		}
+		// the job master itself is a worker of the job manager
+		_, err := d.frameMetaClient.DeleteWorker(ctx, metadata.JobManagerUUID, jobID)
+		return err
+	case jobDeletionStageUserMeta:
+		for _, kv := range d.userKVClients {
+			for _, keyAdapter := range jobScopedKeyAdapters {
+				if _, err := kv.Delete(ctx, keyAdapter.Encode(jobID)); err != nil {
+					return err
+				}
+				if _, err := kv.Delete(ctx, keyAdapter.Curry(jobID).Path(), metaclient.WithPrefix()); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	case jobDeletionStageResources:
+		// TODO: clean the files of local resources on executors
+		_, err := d.frameMetaClient.DeleteResourcesByJobID(ctx, jobID)
+		return err
+	case jobDeletionStageAudit:
+		_, err := d.frameMetaClient.DeleteProjectOperationsByJobID(ctx, jobID)
+		return err
+	case jobDeletionStageMasterMeta:
+		_, err := d.frameMetaClient.DeleteJob(ctx, jobID)
+		return err
+	}
+	log.L().Panic("unknown job deletion stage", zap.String("stage", stage))
+	return nil
+}
@@ -0,0 +1,134 @@
+package servermaster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hanfei1991/microcosm/lib/metadata"
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/pkg/adapter"
+	"github.com/hanfei1991/microcosm/pkg/errors"
+	resourcemeta "github.com/hanfei1991/microcosm/pkg/externalresource/resourcemeta/model"
+	"github.com/hanfei1991/microcosm/pkg/meta/kvclient"
+	mockkv "github.com/hanfei1991/microcosm/pkg/meta/kvclient/mock"
+	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+	"github.com/hanfei1991/microcosm/pkg/orm/model"
+	"github.com/hanfei1991/microcosm/pkg/tenant"
+)
+
+func countKeys(ctx context.Context, t *testing.T, kv metaclient.KV, prefix string) int {
+	resp, err := kv.Get(ctx, prefix, metaclient.WithPrefix())
+	require.Nil(t, err)
+	return len(resp.Kvs)
+}
+
+func TestJobDeleterDelete(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	metaCli, err := pkgOrm.NewMockClient()
+	require.NoError(t, err)
+	rawKV := mockkv.NewMetaMock()
+	userKV := kvclient.NewPrefixKVClient(rawKV, tenant.DefaultUserTenantID)
+	deleter := newJobDeleter(metaCli, rawKV)
+
+	const jobID = "job-1"
+	err = metaCli.UpsertJob(ctx, &libModel.MasterMetaKVData{
+		ID:         jobID,
+		StatusCode: libModel.MasterStatusInit,
+	})
+	require.NoError(t, err)
+	err = metaCli.UpsertWorker(ctx, &libModel.WorkerStatus{JobID: metadata.JobManagerUUID, ID: jobID})
+	require.NoError(t, err)
+	err = metaCli.UpsertWorker(ctx, &libModel.WorkerStatus{JobID: jobID, ID: "worker-1"})
+	require.NoError(t, err)
+	err = metaCli.UpsertResource(ctx, &resourcemeta.ResourceMeta{ID: "/local/resource-1", Job: jobID})
+	require.NoError(t, err)
+	err = metaCli.CreateProjectOperation(ctx, &model.ProjectOperation{ProjectID: "project-1", Operation: "submit", JobID: jobID})
+	require.NoError(t, err)
+	// keys of job-10 share the hex prefix with job-1, they should be kept
+	for _, id := range []string{jobID, "job-10"} {
+		_, err = rawKV.Put(ctx, adapter.BarrierKeyAdapter.Encode(id, "barrier-1"), "barrier")
+		require.Nil(t, err)
+		_, err = userKV.Put(ctx, adapter.DMJobKeyAdapter.Encode(id), "dm-job")
+		require.Nil(t, err)
+	}
+
+	err = deleter.Delete(ctx, jobID, false)
+	require.True(t, errors.ErrJobNotTerminated.Equal(err))
+
+	err = deleter.Delete(ctx, jobID, true)
+	require.NoError(t, err)
+
+	_, err = metaCli.GetJobByID(ctx, jobID)
+	require.True(t, pkgOrm.IsNotFoundError(err))
+	workers, err := metaCli.QueryWorkersByMasterID(ctx, jobID)
+	require.NoError(t, err)
+	require.Len(t, workers, 0)
+	_, err = metaCli.GetWorkerByID(ctx, metadata.JobManagerUUID, jobID)
+	require.True(t, pkgOrm.IsNotFoundError(err))
+	resources, err := metaCli.QueryResourcesByJobID(ctx, jobID)
+	require.NoError(t, err)
+	require.Len(t, resources, 0)
+	ops, err := metaCli.QueryProjectOperations(ctx, "project-1")
+	require.NoError(t, err)
+	require.Len(t, ops, 0)
+	deletions, err := metaCli.QueryJobDeletions(ctx)
+	require.NoError(t, err)
+	require.Len(t, deletions, 0)
+
+	require.Equal(t, 1, countKeys(ctx, t, rawKV, adapter.BarrierKeyAdapter.Path()))
+	require.Equal(t, 1, countKeys(ctx, t, rawKV, adapter.BarrierKeyAdapter.Curry("job-10").Path()))
+	require.Equal(t, 1, countKeys(ctx, t, userKV, adapter.DMJobKeyAdapter.Path()))
+	require.Equal(t, 1, countKeys(ctx, t, userKV, adapter.DMJobKeyAdapter.Encode("job-10")))
+
+	err = deleter.Delete(ctx, jobID, false)
+	require.True(t, errors.ErrJobNotFound.Equal(err))
+}
+
+func TestJobDeleterResume(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	metaCli, err := pkgOrm.NewMockClient()
+	require.NoError(t, err)
+	deleter := newJobDeleter(metaCli, mockkv.NewMetaMock())
+
+	// the previous leader failed after deleting user meta of job-1, and
+	// after deleting master meta of job-2
+	err = metaCli.UpsertJob(ctx, &libModel.MasterMetaKVData{
+		ID:         "job-1",
+		StatusCode: libModel.MasterStatusStopped,
+	})
+	require.NoError(t, err)
+	err = metaCli.UpsertJobDeletion(ctx, &model.JobDeletion{JobID: "job-1", Stage: jobDeletionStageResources})
+	require.NoError(t, err)
+	err = metaCli.UpsertJobDeletion(ctx, &model.JobDeletion{JobID: "job-2", Stage: jobDeletionStageMasterMeta})
+	require.NoError(t, err)
+
+	failed, err := deleter.Resume(ctx)
+	require.NoError(t, err)
+	require.Len(t, failed, 0)
+
+	_, err = metaCli.GetJobByID(ctx, "job-1")
+	require.True(t, pkgOrm.IsNotFoundError(err))
+	deletions, err := metaCli.QueryJobDeletions(ctx)
+	require.NoError(t, err)
+	require.Len(t, deletions, 0)
+
+	// an unfinished deletion can be resumed by deleting the job again
+	err = metaCli.UpsertJobDeletion(ctx, &model.JobDeletion{JobID: "job-3", Stage: jobDeletionStageAudit})
+	require.NoError(t, err)
+	err = deleter.Delete(ctx, "job-3", false)
+	require.NoError(t, err)
+	deletions, err = metaCli.QueryJobDeletions(ctx)
+	require.NoError(t, err)
+	require.Len(t, deletions, 0)
+}
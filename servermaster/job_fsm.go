@@ -1,6 +1,7 @@
 package servermaster
 
 import (
+	"sort"
 	"sync"
 
 	"github.com/hanfei1991/microcosm/lib"
@@ -18,6 +19,8 @@ type jobHolder struct {
 	// True means the job is loaded from metastore during jobmanager failover.
 	// Otherwise it is added by SubmitJob.
 	addFromFailover bool
+	// True means the job master is re-dispatched after it went offline.
+	failover bool
 }
 
 // JobFsm manages state of all job masters, job master state forms a finite-state
@@ -70,19 +73,25 @@ type JobFsm struct {
 	pendingJobs map[libModel.MasterID]*libModel.MasterMetaKVData
 	waitAckJobs map[libModel.MasterID]*jobHolder
 	onlineJobs  map[libModel.MasterID]*jobHolder
+	// failoverJobs are the pending jobs that went offline, rather than
+	// the ones failed to be dispatched for the first time.
+	failoverJobs map[libModel.MasterID]struct{}
 }
 
 // JobStats defines a statistics interface for JobFsm
 type JobStats interface {
 	JobCount(pb.QueryJobResponse_JobStatus) int
+	// ErrorJobs returns the jobs in error states, sorted by job ID.
+	ErrorJobs() []libModel.MasterID
 }
 
 // NewJobFsm creates a new job fsm
 func NewJobFsm() *JobFsm {
 	return &JobFsm{
-		pendingJobs: make(map[libModel.MasterID]*libModel.MasterMetaKVData),
-		waitAckJobs: make(map[libModel.MasterID]*jobHolder),
-		onlineJobs:  make(map[libModel.MasterID]*jobHolder),
+		pendingJobs:  make(map[libModel.MasterID]*libModel.MasterMetaKVData),
+		waitAckJobs:  make(map[libModel.MasterID]*jobHolder),
+		onlineJobs:   make(map[libModel.MasterID]*jobHolder),
+		failoverJobs: make(map[libModel.MasterID]struct{}),
 	}
 }
 
@@ -104,9 +113,11 @@ func (fsm *JobFsm) QueryJob(jobID libModel.MasterID) *pb.QueryJobResponse {
 			return nil
 		}
 		resp := &pb.QueryJobResponse{
-			Tp:     int64(meta.Tp),
-			Config: meta.Config,
-			Status: pb.QueryJobResponse_pending,
+			Tp:                         int64(meta.Tp),
+			Config:                     meta.Config,
+			Status:                     pb.QueryJobResponse_pending,
+			MaxCreateWorkerConcurrency: meta.MaxCreateWorkerConcurrency,
+			Labels:                     meta.Labels,
 		}
 		return resp
 	}
@@ -121,9 +132,11 @@ func (fsm *JobFsm) QueryJob(jobID libModel.MasterID) *pb.QueryJobResponse {
 		}
 		meta := job.MasterMetaKVData
 		resp := &pb.QueryJobResponse{
-			Tp:     int64(meta.Tp),
-			Config: meta.Config,
-			Status: pb.QueryJobResponse_dispatched,
+			Tp:                         int64(meta.Tp),
+			Config:                     meta.Config,
+			Status:                     pb.QueryJobResponse_dispatched,
+			MaxCreateWorkerConcurrency: meta.MaxCreateWorkerConcurrency,
+			Labels:                     meta.Labels,
 		}
 		return resp
 	}
@@ -137,9 +150,11 @@ func (fsm *JobFsm) QueryJob(jobID libModel.MasterID) *pb.QueryJobResponse {
 			return nil
 		}
 		resp := &pb.QueryJobResponse{
-			Tp:     int64(job.Tp),
-			Config: job.Config,
-			Status: pb.QueryJobResponse_online,
+			Tp:                         int64(job.Tp),
+			Config:                     job.Config,
+			Status:                     pb.QueryJobResponse_online,
+			MaxCreateWorkerConcurrency: job.MaxCreateWorkerConcurrency,
+			Labels:                     job.Labels,
 		}
 		jobInfo, err := job.ToPB()
 		// TODO (zixiong) ToPB should handle the tombstone situation gracefully.
@@ -166,6 +181,24 @@ func (fsm *JobFsm) QueryJob(jobID libModel.MasterID) *pb.QueryJobResponse {
 	return checkOnlineJob()
 }
 
+// JobStatus returns the status of a job managed by the fsm, false means the
+// job is not running.
+func (fsm *JobFsm) JobStatus(jobID libModel.MasterID) (pb.QueryJobResponse_JobStatus, bool) {
+	fsm.jobsMu.RLock()
+	defer fsm.jobsMu.RUnlock()
+
+	if _, ok := fsm.pendingJobs[jobID]; ok {
+		return pb.QueryJobResponse_pending, true
+	}
+	if _, ok := fsm.waitAckJobs[jobID]; ok {
+		return pb.QueryJobResponse_dispatched, true
+	}
+	if _, ok := fsm.onlineJobs[jobID]; ok {
+		return pb.QueryJobResponse_online, true
+	}
+	return 0, false
+}
+
 // JobDispatched is called when a job is firstly created or server master is failovered
 func (fsm *JobFsm) JobDispatched(job *libModel.MasterMetaKVData, addFromFailover bool) {
 	fsm.jobsMu.Lock()
@@ -177,19 +210,25 @@ func (fsm *JobFsm) JobDispatched(job *libModel.MasterMetaKVData, addFromFailover
 }
 
 // IterPendingJobs iterates all pending jobs and dispatch(via create worker) them again.
-func (fsm *JobFsm) IterPendingJobs(dispatchJobFn func(job *libModel.MasterMetaKVData) (string, error)) error {
+// failover is true if the job went offline and is dispatched for failover.
+func (fsm *JobFsm) IterPendingJobs(
+	dispatchJobFn func(job *libModel.MasterMetaKVData, failover bool) (string, error),
+) error {
 	fsm.jobsMu.Lock()
 	defer fsm.jobsMu.Unlock()
 
 	for oldJobID, job := range fsm.pendingJobs {
-		id, err := dispatchJobFn(job)
+		_, failover := fsm.failoverJobs[oldJobID]
+		id, err := dispatchJobFn(job, failover)
 		if err != nil {
 			return err
 		}
 		delete(fsm.pendingJobs, oldJobID)
+		delete(fsm.failoverJobs, oldJobID)
 		job.ID = id
 		fsm.waitAckJobs[id] = &jobHolder{
 			MasterMetaKVData: job,
+			failover:         failover,
 		}
 		log.L().Info("job master recovered", zap.Any("job", job))
 	}
@@ -252,6 +291,7 @@ func (fsm *JobFsm) JobOffline(worker lib.WorkerHandle, needFailover bool) {
 	}
 	if needFailover {
 		fsm.pendingJobs[worker.ID()] = job.MasterMetaKVData
+		fsm.failoverJobs[worker.ID()] = struct{}{}
 	}
 }
 
@@ -265,6 +305,9 @@ func (fsm *JobFsm) JobDispatchFailed(worker lib.WorkerHandle) error {
 		return errors.ErrWorkerNotFound.GenWithStackByArgs(worker.ID())
 	}
 	fsm.pendingJobs[worker.ID()] = job.MasterMetaKVData
+	if job.failover {
+		fsm.failoverJobs[worker.ID()] = struct{}{}
+	}
 	delete(fsm.waitAckJobs, worker.ID())
 	return nil
 }
@@ -285,3 +328,22 @@ func (fsm *JobFsm) JobCount(status pb.QueryJobResponse_JobStatus) int {
 		return 0
 	}
 }
+
+// ErrorJobs implements JobStats.ErrorJobs, the jobs in error states are the
+// pending jobs whose job masters are lost or failed to be dispatched, and the
+// online jobs whose job masters report errors.
+func (fsm *JobFsm) ErrorJobs() []libModel.MasterID {
+	fsm.jobsMu.RLock()
+	defer fsm.jobsMu.RUnlock()
+	ret := make([]libModel.MasterID, 0)
+	for id := range fsm.pendingJobs {
+		ret = append(ret, id)
+	}
+	for id, job := range fsm.onlineJobs {
+		if status := job.Status(); status != nil && status.Code == libModel.WorkerStatusError {
+			ret = append(ret, id)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
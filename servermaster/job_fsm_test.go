@@ -62,7 +62,8 @@ func TestJobFsmStateTrans(t *testing.T) {
 
 	// Tick, process pending jobs, Pending -> WaitAck
 	dispatchedJobs := make([]*libModel.MasterMetaKVData, 0)
-	err = fsm.IterPendingJobs(func(job *libModel.MasterMetaKVData) (string, error) {
+	err = fsm.IterPendingJobs(func(job *libModel.MasterMetaKVData, failover bool) (string, error) {
+		require.True(t, failover)
 		dispatchedJobs = append(dispatchedJobs, job)
 		return id, nil
 	})
@@ -80,8 +81,9 @@ func TestJobFsmStateTrans(t *testing.T) {
 	require.Equal(t, 1, fsm.JobCount(pb.QueryJobResponse_pending))
 	require.Equal(t, 0, fsm.JobCount(pb.QueryJobResponse_dispatched))
 
-	// Tick, Pending -> WaitAck
-	err = fsm.IterPendingJobs(func(job *libModel.MasterMetaKVData) (string, error) {
+	// Tick, Pending -> WaitAck, the job is still dispatched for failover
+	err = fsm.IterPendingJobs(func(job *libModel.MasterMetaKVData, failover bool) (string, error) {
+		require.True(t, failover)
 		return id, nil
 	})
 	require.Nil(t, err)
@@ -103,3 +105,31 @@ func TestJobFsmStateTrans(t *testing.T) {
 
 	fsm.JobOffline(invalidWorker, true)
 }
+
+func TestJobFsmErrorJobs(t *testing.T) {
+	t.Parallel()
+
+	fsm := NewJobFsm()
+	for id, code := range map[string]libModel.WorkerStatusCode{
+		"job-normal": libModel.WorkerStatusNormal,
+		"job-error":  libModel.WorkerStatusError,
+		"job-lost":   libModel.WorkerStatusNormal,
+	} {
+		fsm.JobDispatched(&libModel.MasterMetaKVData{ID: id}, false)
+		err := fsm.JobOnline(&master.MockHandle{
+			WorkerID:     id,
+			WorkerStatus: &libModel.WorkerStatus{Code: code},
+			ExecutorID:   "executor-1",
+		})
+		require.Nil(t, err)
+	}
+	require.Equal(t, []libModel.MasterID{"job-error"}, fsm.ErrorJobs())
+
+	// the job master goes offline and waits for failover
+	fsm.JobOffline(&master.MockHandle{
+		WorkerID:     "job-lost",
+		WorkerStatus: &libModel.WorkerStatus{Code: libModel.WorkerStatusNormal},
+		IsTombstone:  true,
+	}, true /* needFailover */)
+	require.Equal(t, []libModel.MasterID{"job-error", "job-lost"}, fsm.ErrorJobs())
+}
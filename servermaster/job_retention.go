@@ -0,0 +1,146 @@
+package servermaster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/pkg/clock"
+	derrors "github.com/hanfei1991/microcosm/pkg/errors"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+)
+
+const (
+	defaultJobRetentionCheckInterval = "1m"
+	// maxJobExpirationsPerCheck limits the jobs deleted by a check, so that
+	// the tick of job manager is not blocked for long. The remaining expired
+	// jobs are deleted by the following checks.
+	maxJobExpirationsPerCheck = 1000
+)
+
+// JobRetentionConfig is the configuration of deleting finished jobs, a
+// finished job is deleted once it either exceeds the retention or is beyond
+// the max number of finished jobs.
+type JobRetentionConfig struct {
+	// Retention is how long a job is kept after it finishes, empty means
+	// finished jobs are not deleted by time.
+	RetentionStr string `toml:"retention" json:"retention"`
+	// MaxFinishedJobs is the max number of finished jobs kept, the jobs
+	// finished earliest are deleted first, 0 means no limit.
+	MaxFinishedJobs int `toml:"max-finished-jobs" json:"max-finished-jobs"`
+	// CheckInterval is the interval of checking the expired jobs
+	CheckIntervalStr string `toml:"check-interval" json:"check-interval"`
+
+	Retention     time.Duration `toml:"-" json:"-"`
+	CheckInterval time.Duration `toml:"-" json:"-"`
+}
+
+// Enabled returns whether finished jobs are deleted
+func (c *JobRetentionConfig) Enabled() bool {
+	return c != nil && (c.RetentionStr != "" || c.MaxFinishedJobs > 0)
+}
+
+// Adjust validates the config and fills the default values
+func (c *JobRetentionConfig) Adjust() (err error) {
+	if c.MaxFinishedJobs < 0 {
+		return derrors.ErrJobRetentionInvalidConfig.GenWithStackByArgs("max-finished-jobs must not be negative")
+	}
+	if c.RetentionStr != "" {
+		c.Retention, err = time.ParseDuration(c.RetentionStr)
+		if err != nil {
+			return derrors.ErrJobRetentionInvalidConfig.GenWithStackByArgs(fmt.Sprintf("retention: %v", err))
+		}
+		if c.Retention <= 0 {
+			return derrors.ErrJobRetentionInvalidConfig.GenWithStackByArgs("retention must be positive")
+		}
+	}
+	if c.CheckIntervalStr == "" {
+		c.CheckIntervalStr = defaultJobRetentionCheckInterval
+	}
+	c.CheckInterval, err = time.ParseDuration(c.CheckIntervalStr)
+	if err != nil {
+		return derrors.ErrJobRetentionInvalidConfig.GenWithStackByArgs(fmt.Sprintf("check-interval: %v", err))
+	}
+	if c.CheckInterval <= 0 {
+		return derrors.ErrJobRetentionInvalidConfig.GenWithStackByArgs("check-interval must be positive")
+	}
+	return nil
+}
+
+type jobExpireFunc func(ctx context.Context, job *libModel.MasterMetaKVData) error
+
+// jobRetention deletes the finished jobs exceeding the retention config. It
+// is driven by the Tick of job manager, so jobs are deleted by the server
+// master leader only. A deletion interrupted by a leader failover is resumed
+// by the job deleter of the next leader.
+type jobRetention struct {
+	cfg             *JobRetentionConfig
+	frameMetaClient pkgOrm.Client
+	clocker         clock.Clock
+	expire          jobExpireFunc
+
+	lastCheck time.Time
+}
+
+func newJobRetention(
+	cfg *JobRetentionConfig,
+	frameMetaClient pkgOrm.Client,
+	clocker clock.Clock,
+	expire jobExpireFunc,
+) *jobRetention {
+	return &jobRetention{
+		cfg:             cfg,
+		frameMetaClient: frameMetaClient,
+		clocker:         clocker,
+		expire:          expire,
+	}
+}
+
+// Tick deletes the expired jobs if the check interval has passed since the
+// last check.
+func (r *jobRetention) Tick(ctx context.Context) {
+	now := r.clocker.Now()
+	if now.Sub(r.lastCheck) < r.cfg.CheckInterval {
+		return
+	}
+	r.lastCheck = now
+
+	jobs, err := r.frameMetaClient.QueryJobsByStatusCode(ctx, libModel.MasterStatusFinished)
+	if err != nil {
+		log.L().Warn("query finished jobs failed", zap.Error(err))
+		return
+	}
+	for _, job := range expiredJobs(jobs, now, r.cfg) {
+		if err := r.expire(ctx, job); err != nil {
+			// the job is deleted again by the next check
+			log.L().Warn("delete expired job failed", zap.String("job-id", job.ID), zap.Error(err))
+			if ctx.Err() != nil {
+				return
+			}
+		}
+	}
+}
+
+// expiredJobs returns the jobs to delete at now, jobs are sorted by the time
+// they finished.
+func expiredJobs(
+	jobs []*libModel.MasterMetaKVData, now time.Time, cfg *JobRetentionConfig,
+) []*libModel.MasterMetaKVData {
+	n := 0
+	if cfg.MaxFinishedJobs > 0 && len(jobs) > cfg.MaxFinishedJobs {
+		n = len(jobs) - cfg.MaxFinishedJobs
+	}
+	if cfg.Retention > 0 {
+		for n < len(jobs) && now.Sub(jobs[n].UpdatedAt) > cfg.Retention {
+			n++
+		}
+	}
+	if n > maxJobExpirationsPerCheck {
+		n = maxJobExpirationsPerCheck
+	}
+	return jobs[:n]
+}
@@ -0,0 +1,96 @@
+package servermaster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	"github.com/hanfei1991/microcosm/pkg/clock"
+	"github.com/hanfei1991/microcosm/pkg/errors"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+	"github.com/hanfei1991/microcosm/pkg/orm/model"
+)
+
+func TestJobRetentionConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := &JobRetentionConfig{}
+	require.False(t, cfg.Enabled())
+	require.NoError(t, cfg.Adjust())
+
+	cfg = &JobRetentionConfig{RetentionStr: "24h"}
+	require.True(t, cfg.Enabled())
+	require.NoError(t, cfg.Adjust())
+	require.Equal(t, 24*time.Hour, cfg.Retention)
+	require.Equal(t, time.Minute, cfg.CheckInterval)
+
+	cfg = &JobRetentionConfig{RetentionStr: "-1h"}
+	require.True(t, errors.ErrJobRetentionInvalidConfig.Equal(cfg.Adjust()))
+	cfg = &JobRetentionConfig{MaxFinishedJobs: -1}
+	require.True(t, errors.ErrJobRetentionInvalidConfig.Equal(cfg.Adjust()))
+}
+
+func TestExpiredJobs(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2022, 3, 15, 10, 0, 0, 0, time.UTC)
+	var jobs []*libModel.MasterMetaKVData
+	for i := 5; i > 0; i-- {
+		jobs = append(jobs, &libModel.MasterMetaKVData{
+			Model: model.Model{UpdatedAt: now.Add(-time.Duration(i) * time.Hour)},
+		})
+	}
+
+	cfg := &JobRetentionConfig{Retention: 150 * time.Minute}
+	require.Equal(t, jobs[:3], expiredJobs(jobs, now, cfg))
+	cfg = &JobRetentionConfig{MaxFinishedJobs: 4}
+	require.Equal(t, jobs[:1], expiredJobs(jobs, now, cfg))
+	// the job is deleted if it exceeds either limit
+	cfg = &JobRetentionConfig{Retention: 150 * time.Minute, MaxFinishedJobs: 1}
+	require.Equal(t, jobs[:4], expiredJobs(jobs, now, cfg))
+	cfg = &JobRetentionConfig{Retention: 10 * time.Hour, MaxFinishedJobs: 10}
+	require.Empty(t, expiredJobs(jobs, now, cfg))
+}
+
+func TestJobRetentionTick(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	metaCli, err := pkgOrm.NewMockClient()
+	require.NoError(t, err)
+	require.NoError(t, metaCli.UpsertJob(ctx, &libModel.MasterMetaKVData{
+		ID: "job-1", StatusCode: libModel.MasterStatusFinished,
+	}))
+	require.NoError(t, metaCli.UpsertJob(ctx, &libModel.MasterMetaKVData{
+		ID: "job-2", StatusCode: libModel.MasterStatusInit,
+	}))
+
+	cfg := &JobRetentionConfig{RetentionStr: "1h"}
+	require.NoError(t, cfg.Adjust())
+	clocker := clock.NewMock()
+	clocker.Set(time.Now().Add(2 * time.Hour))
+	var expired []string
+	retention := newJobRetention(cfg, metaCli, clocker,
+		func(ctx context.Context, job *libModel.MasterMetaKVData) error {
+			expired = append(expired, job.ID)
+			_, err := metaCli.DeleteJob(ctx, job.ID)
+			return err
+		})
+
+	retention.Tick(ctx)
+	require.Equal(t, []string{"job-1"}, expired)
+
+	// jobs are not checked again until the check interval passes
+	require.NoError(t, metaCli.UpsertJob(ctx, &libModel.MasterMetaKVData{
+		ID: "job-3", StatusCode: libModel.MasterStatusFinished,
+	}))
+	clocker.Set(time.Now().Add(2 * time.Hour))
+	retention.Tick(ctx)
+	require.Equal(t, []string{"job-1"}, expired)
+	clocker.Set(time.Now().Add(3 * time.Hour))
+	retention.Tick(ctx)
+	require.Equal(t, []string{"job-1", "job-3"}, expired)
+}
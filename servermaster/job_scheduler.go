@@ -0,0 +1,307 @@
+package servermaster
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	"github.com/hanfei1991/microcosm/pb"
+	"github.com/hanfei1991/microcosm/pkg/clock"
+	"github.com/hanfei1991/microcosm/pkg/cron"
+	derrors "github.com/hanfei1991/microcosm/pkg/errors"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+	"github.com/hanfei1991/microcosm/pkg/orm/model"
+	"github.com/hanfei1991/microcosm/pkg/uuid"
+)
+
+const (
+	// jobScheduleMissThreshold is how late a fire can be before it is
+	// regarded as missed, a fire is usually late by a tick interval only.
+	jobScheduleMissThreshold = time.Minute
+	// maxCatchUpFires limits the jobs submitted for the missed fires of a
+	// schedule with CatchUpAll policy, older fires are skipped.
+	maxCatchUpFires = 64
+)
+
+var catchUpPolicies = map[pb.CatchUpPolicy]string{
+	pb.CatchUpPolicy_CatchUpSkip: model.CatchUpSkip,
+	pb.CatchUpPolicy_CatchUpOnce: model.CatchUpOnce,
+	pb.CatchUpPolicy_CatchUpAll:  model.CatchUpAll,
+}
+
+type jobSubmitFunc func(ctx context.Context, req *pb.SubmitJobRequest) *pb.SubmitJobResponse
+
+type jobScheduleEntry struct {
+	meta *model.JobSchedule
+	cron *cron.Schedule
+}
+
+// jobScheduler submits jobs periodically according to the job schedules in
+// framework metastore. It is driven by the Tick of job manager, so jobs are
+// submitted by the server master leader only. The last fire time of each
+// schedule is persisted after its job is submitted, the fires missed during
+// a leader failover are handled by the catch-up policy of the schedule. A
+// fire may be submitted twice if the leader fails before persisting it.
+type jobScheduler struct {
+	frameMetaClient pkgOrm.Client
+	clocker         clock.Clock
+	uuidGen         uuid.Generator
+	submit          jobSubmitFunc
+
+	mu        sync.Mutex
+	schedules map[string]*jobScheduleEntry
+}
+
+func newJobScheduler(
+	frameMetaClient pkgOrm.Client,
+	clocker clock.Clock,
+	uuidGen uuid.Generator,
+	submit jobSubmitFunc,
+) *jobScheduler {
+	return &jobScheduler{
+		frameMetaClient: frameMetaClient,
+		clocker:         clocker,
+		uuidGen:         uuidGen,
+		submit:          submit,
+		schedules:       make(map[string]*jobScheduleEntry),
+	}
+}
+
+// Load loads all the schedules from metastore, it is called when a server
+// master becomes leader.
+func (s *jobScheduler) Load(ctx context.Context) error {
+	metas, err := s.frameMetaClient.QueryJobSchedules(ctx)
+	if err != nil {
+		return err
+	}
+
+	schedules := make(map[string]*jobScheduleEntry, len(metas))
+	for _, meta := range metas {
+		sched, err := cron.Parse(meta.Cron)
+		if err != nil {
+			// should not happen, the expression is checked before persisted
+			log.L().Warn("skip job schedule with invalid cron expression",
+				zap.String("schedule-id", meta.ScheduleID), zap.Error(err))
+			continue
+		}
+		meta.LastFireTime = meta.LastFireTime.UTC()
+		schedules[meta.ScheduleID] = &jobScheduleEntry{meta: meta, cron: sched}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.schedules = schedules
+	return nil
+}
+
+// Create creates a schedule and returns its ID, the schedule starts firing
+// after it is created.
+func (s *jobScheduler) Create(ctx context.Context, spec *pb.JobSchedule) (string, error) {
+	entry, err := s.newEntry(spec)
+	if err != nil {
+		return "", err
+	}
+	entry.meta.ScheduleID = s.uuidGen.NewString()
+	entry.meta.LastFireTime = s.clocker.Now().UTC()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.frameMetaClient.UpsertJobSchedule(ctx, entry.meta); err != nil {
+		return "", err
+	}
+	s.schedules[entry.meta.ScheduleID] = entry
+	log.L().Info("job schedule created",
+		zap.String("schedule-id", entry.meta.ScheduleID), zap.String("cron", entry.meta.Cron))
+	return entry.meta.ScheduleID, nil
+}
+
+// Update replaces the spec of a schedule. The fires while a schedule is
+// paused are skipped regardless of its catch-up policy.
+func (s *jobScheduler) Update(ctx context.Context, spec *pb.JobSchedule) error {
+	entry, err := s.newEntry(spec)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, ok := s.schedules[spec.GetScheduleId()]
+	if !ok {
+		return derrors.ErrJobScheduleNotFound.GenWithStackByArgs(spec.GetScheduleId())
+	}
+	entry.meta.Model = old.meta.Model
+	entry.meta.ScheduleID = old.meta.ScheduleID
+	entry.meta.LastFireTime = old.meta.LastFireTime
+	if old.meta.Paused && !entry.meta.Paused {
+		entry.meta.LastFireTime = s.clocker.Now().UTC()
+	}
+	if err := s.frameMetaClient.UpsertJobSchedule(ctx, entry.meta); err != nil {
+		return err
+	}
+	s.schedules[entry.meta.ScheduleID] = entry
+	return nil
+}
+
+// Delete deletes a schedule, jobs submitted by it are not affected.
+func (s *jobScheduler) Delete(ctx context.Context, scheduleID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.schedules[scheduleID]; !ok {
+		return derrors.ErrJobScheduleNotFound.GenWithStackByArgs(scheduleID)
+	}
+	if _, err := s.frameMetaClient.DeleteJobSchedule(ctx, scheduleID); err != nil {
+		return err
+	}
+	delete(s.schedules, scheduleID)
+	log.L().Info("job schedule deleted", zap.String("schedule-id", scheduleID))
+	return nil
+}
+
+// Query returns the schedule of the given ID, or all schedules if the ID is
+// empty.
+func (s *jobScheduler) Query(scheduleID string) ([]*pb.JobSchedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if scheduleID != "" {
+		entry, ok := s.schedules[scheduleID]
+		if !ok {
+			return nil, derrors.ErrJobScheduleNotFound.GenWithStackByArgs(scheduleID)
+		}
+		return []*pb.JobSchedule{entry.toPB()}, nil
+	}
+	ret := make([]*pb.JobSchedule, 0, len(s.schedules))
+	for _, entry := range s.schedules {
+		ret = append(ret, entry.toPB())
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].ScheduleId < ret[j].ScheduleId
+	})
+	return ret, nil
+}
+
+// Tick submits jobs for all the schedules whose fire times have come.
+func (s *jobScheduler) Tick(ctx context.Context) {
+	now := s.clocker.Now().UTC()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, entry := range s.schedules {
+		if entry.meta.Paused {
+			continue
+		}
+		fires, latest := entry.dueFires(now)
+		for _, fireTime := range fires {
+			s.fire(ctx, entry, fireTime)
+		}
+		if len(fires) == 0 && !latest.IsZero() {
+			// all the due fires are skipped
+			s.record(ctx, entry, latest)
+		}
+	}
+}
+
+// fire submits a job for the fire time of the schedule and records it. A
+// failed submission is not retried, the schedule waits for its next fire.
+func (s *jobScheduler) fire(ctx context.Context, entry *jobScheduleEntry, fireTime time.Time) {
+	logger := log.L().With(
+		zap.String("schedule-id", entry.meta.ScheduleID), zap.Time("fire-time", fireTime))
+	resp := s.submit(ctx, &pb.SubmitJobRequest{
+		Tp:     pb.JobType(entry.meta.JobType),
+		Config: entry.meta.Config,
+		User:   entry.meta.ProjectID,
+	})
+	if resp.Err != nil {
+		logger.Warn("submit scheduled job failed", zap.Any("err", resp.Err))
+	} else {
+		logger.Info("scheduled job submitted", zap.String("job-id", resp.JobIdStr))
+	}
+	s.record(ctx, entry, fireTime)
+}
+
+func (s *jobScheduler) record(ctx context.Context, entry *jobScheduleEntry, fireTime time.Time) {
+	entry.meta.LastFireTime = fireTime
+	if err := s.frameMetaClient.UpsertJobSchedule(ctx, entry.meta); err != nil {
+		// the fire may be handled again by the next leader
+		log.L().Warn("persist job schedule failed",
+			zap.String("schedule-id", entry.meta.ScheduleID), zap.Error(err))
+	}
+}
+
+func (s *jobScheduler) newEntry(spec *pb.JobSchedule) (*jobScheduleEntry, error) {
+	sched, err := cron.Parse(spec.GetCron())
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := pb.JobType_name[int32(spec.GetTp())]; !ok {
+		return nil, derrors.ErrBuildJobFailed.GenWithStack("unknown job type: %s", spec.GetTp())
+	}
+	catchUp, ok := catchUpPolicies[spec.GetCatchUp()]
+	if !ok {
+		return nil, derrors.ErrBuildJobFailed.GenWithStack("unknown catch-up policy: %s", spec.GetCatchUp())
+	}
+	return &jobScheduleEntry{
+		meta: &model.JobSchedule{
+			ProjectID: spec.GetUser(),
+			JobType:   int32(spec.GetTp()),
+			Config:    spec.GetConfig(),
+			Cron:      spec.GetCron(),
+			CatchUp:   catchUp,
+			Paused:    spec.GetPaused(),
+		},
+		cron: sched,
+	}, nil
+}
+
+// dueFires returns the fire times to submit jobs for at now according to the
+// catch-up policy of the schedule, and the latest fire time that is due.
+func (e *jobScheduleEntry) dueFires(now time.Time) (fires []time.Time, latest time.Time) {
+	for next := e.cron.Next(e.meta.LastFireTime); !next.IsZero() && !next.After(now); next = e.cron.Next(next) {
+		fires = append(fires, next)
+		if len(fires) > maxCatchUpFires {
+			fires = fires[1:]
+		}
+	}
+	if len(fires) == 0 {
+		return nil, time.Time{}
+	}
+
+	latest = fires[len(fires)-1]
+	switch e.meta.CatchUp {
+	case model.CatchUpAll:
+		return fires, latest
+	case model.CatchUpOnce:
+		return []time.Time{latest}, latest
+	default:
+		if now.Sub(latest) > jobScheduleMissThreshold {
+			return nil, latest
+		}
+		return []time.Time{latest}, latest
+	}
+}
+
+func (e *jobScheduleEntry) toPB() *pb.JobSchedule {
+	ret := &pb.JobSchedule{
+		ScheduleId:     e.meta.ScheduleID,
+		Tp:             pb.JobType(e.meta.JobType),
+		Config:         e.meta.Config,
+		User:           e.meta.ProjectID,
+		Cron:           e.meta.Cron,
+		Paused:         e.meta.Paused,
+		LastFireTimeMs: e.meta.LastFireTime.UnixMilli(),
+	}
+	for policy, name := range catchUpPolicies {
+		if name == e.meta.CatchUp {
+			ret.CatchUp = policy
+		}
+	}
+	if !e.meta.Paused {
+		if next := e.cron.Next(e.meta.LastFireTime); !next.IsZero() {
+			ret.NextFireTimeMs = next.UnixMilli()
+		}
+	}
+	return ret
+}
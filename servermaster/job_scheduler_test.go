@@ -0,0 +1,157 @@
+package servermaster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hanfei1991/microcosm/pb"
+	"github.com/hanfei1991/microcosm/pkg/clock"
+	"github.com/hanfei1991/microcosm/pkg/errors"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+	"github.com/hanfei1991/microcosm/pkg/orm/model"
+	"github.com/hanfei1991/microcosm/pkg/uuid"
+)
+
+type mockJobSubmitter struct {
+	submitted []string
+}
+
+func (s *mockJobSubmitter) submit(ctx context.Context, req *pb.SubmitJobRequest) *pb.SubmitJobResponse {
+	s.submitted = append(s.submitted, string(req.Config))
+	return &pb.SubmitJobResponse{JobIdStr: "job-" + string(req.Config)}
+}
+
+func newTestJobScheduler(
+	metaCli pkgOrm.Client, now time.Time, ids ...string,
+) (*jobScheduler, *clock.Mock, *mockJobSubmitter) {
+	clocker := clock.NewMock()
+	clocker.Set(now)
+	uuidGen := uuid.NewMock()
+	for _, id := range ids {
+		uuidGen.Push(id)
+	}
+	submitter := &mockJobSubmitter{}
+	return newJobScheduler(metaCli, clocker, uuidGen, submitter.submit), clocker, submitter
+}
+
+func TestJobSchedulerCRUD(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	metaCli, err := pkgOrm.NewMockClient()
+	require.NoError(t, err)
+	now := time.Date(2022, 3, 15, 10, 3, 0, 0, time.UTC)
+	scheduler, _, _ := newTestJobScheduler(metaCli, now, "schedule-1")
+
+	_, err = scheduler.Create(ctx, &pb.JobSchedule{Tp: pb.JobType_FakeJob, Cron: "* *"})
+	require.True(t, errors.ErrInvalidCronExpr.Equal(err))
+
+	id, err := scheduler.Create(ctx, &pb.JobSchedule{
+		Tp:     pb.JobType_FakeJob,
+		Config: []byte("config"),
+		Cron:   "*/10 * * * *",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "schedule-1", id)
+	schedules, err := scheduler.Query("")
+	require.NoError(t, err)
+	require.Equal(t, []*pb.JobSchedule{{
+		ScheduleId:     id,
+		Tp:             pb.JobType_FakeJob,
+		Config:         []byte("config"),
+		Cron:           "*/10 * * * *",
+		LastFireTimeMs: now.UnixMilli(),
+		NextFireTimeMs: time.Date(2022, 3, 15, 10, 10, 0, 0, time.UTC).UnixMilli(),
+	}}, schedules)
+
+	err = scheduler.Update(ctx, &pb.JobSchedule{ScheduleId: "schedule-2", Cron: "@daily"})
+	require.True(t, errors.ErrJobScheduleNotFound.Equal(err))
+	err = scheduler.Update(ctx, &pb.JobSchedule{
+		ScheduleId: id,
+		Tp:         pb.JobType_FakeJob,
+		Cron:       "@daily",
+		CatchUp:    pb.CatchUpPolicy_CatchUpOnce,
+	})
+	require.NoError(t, err)
+	meta, err := metaCli.GetJobScheduleByID(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, "@daily", meta.Cron)
+	require.Equal(t, model.CatchUpOnce, meta.CatchUp)
+
+	require.NoError(t, scheduler.Delete(ctx, id))
+	_, err = scheduler.Query(id)
+	require.True(t, errors.ErrJobScheduleNotFound.Equal(err))
+	_, err = metaCli.GetJobScheduleByID(ctx, id)
+	require.True(t, pkgOrm.IsNotFoundError(err))
+	require.True(t, errors.ErrJobScheduleNotFound.Equal(scheduler.Delete(ctx, id)))
+}
+
+func TestJobSchedulerTick(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	metaCli, err := pkgOrm.NewMockClient()
+	require.NoError(t, err)
+	scheduler, clocker, submitter := newTestJobScheduler(
+		metaCli, time.Date(2022, 3, 15, 10, 3, 0, 0, time.UTC), "schedule-1", "schedule-2")
+
+	_, err = scheduler.Create(ctx, &pb.JobSchedule{Config: []byte("a"), Cron: "*/10 * * * *"})
+	require.NoError(t, err)
+	_, err = scheduler.Create(ctx, &pb.JobSchedule{Config: []byte("b"), Cron: "*/10 * * * *", Paused: true})
+	require.NoError(t, err)
+
+	clocker.Add(2 * time.Minute)
+	scheduler.Tick(ctx)
+	require.Empty(t, submitter.submitted)
+
+	clocker.Add(5*time.Minute + time.Second)
+	scheduler.Tick(ctx)
+	scheduler.Tick(ctx)
+	require.Equal(t, []string{"a"}, submitter.submitted)
+
+	// the fires while a schedule is paused are skipped
+	clocker.Add(10 * time.Minute)
+	err = scheduler.Update(ctx, &pb.JobSchedule{ScheduleId: "schedule-2", Config: []byte("b"), Cron: "*/10 * * * *"})
+	require.NoError(t, err)
+	scheduler.Tick(ctx)
+	require.Equal(t, []string{"a", "a"}, submitter.submitted)
+}
+
+func TestJobSchedulerCatchUp(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	metaCli, err := pkgOrm.NewMockClient()
+	require.NoError(t, err)
+	start := time.Date(2022, 3, 15, 0, 30, 0, 0, time.UTC)
+	scheduler, _, _ := newTestJobScheduler(metaCli, start, "schedule-1", "schedule-2", "schedule-3")
+	for _, policy := range []pb.CatchUpPolicy{
+		pb.CatchUpPolicy_CatchUpSkip, pb.CatchUpPolicy_CatchUpOnce, pb.CatchUpPolicy_CatchUpAll,
+	} {
+		_, err = scheduler.Create(ctx, &pb.JobSchedule{
+			Config:  []byte(policy.String()),
+			Cron:    "0 * * * *",
+			CatchUp: policy,
+		})
+		require.NoError(t, err)
+	}
+
+	// a new leader is elected after the fires of 01:00, 02:00 and 03:00
+	scheduler2, _, submitter := newTestJobScheduler(metaCli, start.Add(3*time.Hour+15*time.Minute))
+	require.NoError(t, scheduler2.Load(ctx))
+	scheduler2.Tick(ctx)
+	require.ElementsMatch(t, []string{
+		"CatchUpOnce", "CatchUpAll", "CatchUpAll", "CatchUpAll",
+	}, submitter.submitted)
+
+	for _, id := range []string{"schedule-1", "schedule-2", "schedule-3"} {
+		meta, err := metaCli.GetJobScheduleByID(ctx, id)
+		require.NoError(t, err)
+		require.True(t, meta.LastFireTime.Equal(start.Add(150*time.Minute)), id)
+	}
+	scheduler2.Tick(ctx)
+	require.Len(t, submitter.submitted, 4)
+}
@@ -0,0 +1,147 @@
+package servermaster
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	"github.com/hanfei1991/microcosm/pb"
+	derrors "github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/jobtemplate"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+	"github.com/hanfei1991/microcosm/pkg/orm/model"
+)
+
+// jobTemplateStore manages the job config templates in framework metastore,
+// so that a job can be submitted by supplying the parameter values of a
+// template instead of a whole config.
+type jobTemplateStore struct {
+	frameMetaClient pkgOrm.Client
+}
+
+func newJobTemplateStore(frameMetaClient pkgOrm.Client) *jobTemplateStore {
+	return &jobTemplateStore{frameMetaClient: frameMetaClient}
+}
+
+// Register checks and persists a template. A template without required
+// parameters is rendered and its config is checked as well.
+func (s *jobTemplateStore) Register(ctx context.Context, spec *pb.JobTemplate) error {
+	if spec.GetTemplateId() == "" {
+		return derrors.ErrJobTemplateInvalid.GenWithStackByArgs("empty template ID")
+	}
+	tmpl, err := jobtemplate.Parse(spec.GetTemplate())
+	if err != nil {
+		return err
+	}
+	if _, ok := pb.JobType_name[int32(spec.GetTp())]; !ok {
+		return derrors.ErrBuildJobFailed.GenWithStack("unknown job type: %s", spec.GetTp())
+	}
+	if config, err := tmpl.Render(nil); err == nil {
+		if _, err := jobMasterType(spec.GetTp(), config); err != nil {
+			return err
+		}
+	}
+
+	err = s.frameMetaClient.UpsertJobTemplate(ctx, &model.JobTemplate{
+		TemplateID: spec.GetTemplateId(),
+		JobType:    int32(spec.GetTp()),
+		Template:   spec.GetTemplate(),
+	})
+	if err != nil {
+		return err
+	}
+	log.L().Info("job template registered", zap.String("template-id", spec.GetTemplateId()))
+	return nil
+}
+
+// Delete deletes a template, jobs submitted from it are not affected.
+func (s *jobTemplateStore) Delete(ctx context.Context, templateID string) error {
+	result, err := s.frameMetaClient.DeleteJobTemplate(ctx, templateID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return derrors.ErrJobTemplateNotFound.GenWithStackByArgs(templateID)
+	}
+	log.L().Info("job template deleted", zap.String("template-id", templateID))
+	return nil
+}
+
+// Query returns the template of the given ID, or all templates if the ID is
+// empty.
+func (s *jobTemplateStore) Query(ctx context.Context, templateID string) ([]*pb.JobTemplate, error) {
+	var metas []*model.JobTemplate
+	if templateID != "" {
+		meta, err := s.get(ctx, templateID)
+		if err != nil {
+			return nil, err
+		}
+		metas = append(metas, meta)
+	} else {
+		var err error
+		if metas, err = s.frameMetaClient.QueryJobTemplates(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	ret := make([]*pb.JobTemplate, 0, len(metas))
+	for _, meta := range metas {
+		template := &pb.JobTemplate{
+			TemplateId: meta.TemplateID,
+			Tp:         pb.JobType(meta.JobType),
+			Template:   meta.Template,
+		}
+		// the template has been checked when it is registered
+		if tmpl, err := jobtemplate.Parse(meta.Template); err == nil {
+			for _, ph := range tmpl.Placeholders() {
+				template.Params = append(template.Params, &pb.JobTemplateParam{
+					Name:         ph.Name,
+					DefaultValue: ph.Default,
+					Required:     !ph.HasDefault,
+				})
+			}
+		}
+		ret = append(ret, template)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].TemplateId < ret[j].TemplateId
+	})
+	return ret, nil
+}
+
+// Render renders a template with the parameter values, and checks the
+// rendered config.
+func (s *jobTemplateStore) Render(
+	ctx context.Context, templateID string, params map[string]string,
+) (pb.JobType, []byte, error) {
+	meta, err := s.get(ctx, templateID)
+	if err != nil {
+		return 0, nil, err
+	}
+	tmpl, err := jobtemplate.Parse(meta.Template)
+	if err != nil {
+		return 0, nil, err
+	}
+	config, err := tmpl.Render(params)
+	if err != nil {
+		return 0, nil, err
+	}
+	tp := pb.JobType(meta.JobType)
+	if _, err := jobMasterType(tp, config); err != nil {
+		return 0, nil, err
+	}
+	return tp, config, nil
+}
+
+func (s *jobTemplateStore) get(ctx context.Context, templateID string) (*model.JobTemplate, error) {
+	meta, err := s.frameMetaClient.GetJobTemplateByID(ctx, templateID)
+	if err != nil {
+		if pkgOrm.IsNotFoundError(err) {
+			return nil, derrors.ErrJobTemplateNotFound.GenWithStackByArgs(templateID)
+		}
+		return nil, err
+	}
+	return meta, nil
+}
@@ -0,0 +1,62 @@
+package servermaster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hanfei1991/microcosm/pb"
+	"github.com/hanfei1991/microcosm/pkg/errors"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+)
+
+func TestJobTemplateStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	metaCli, err := pkgOrm.NewMockClient()
+	require.NoError(t, err)
+	store := newJobTemplateStore(metaCli)
+
+	err = store.Register(ctx, &pb.JobTemplate{TemplateId: "cvs", Template: []byte(`{"srcDir": "${dir`)})
+	require.True(t, errors.ErrJobTemplateInvalid.Equal(err))
+	// the config is checked if the template has no required parameters
+	err = store.Register(ctx, &pb.JobTemplate{TemplateId: "cvs", Template: []byte(`{"srcDir": ${dir:-data}`)})
+	require.True(t, errors.ErrBuildJobFailed.Equal(err))
+
+	content := `{"srcHost": "${host}", "dstHost": "${host}", "srcDir": "${dir:-data}", "fileNum": ${files}}`
+	err = store.Register(ctx, &pb.JobTemplate{TemplateId: "cvs", Tp: pb.JobType_CVSDemo, Template: []byte(content)})
+	require.NoError(t, err)
+	templates, err := store.Query(ctx, "")
+	require.NoError(t, err)
+	require.Equal(t, []*pb.JobTemplate{{
+		TemplateId: "cvs",
+		Tp:         pb.JobType_CVSDemo,
+		Template:   []byte(content),
+		Params: []*pb.JobTemplateParam{
+			{Name: "dir", DefaultValue: "data"},
+			{Name: "files", Required: true},
+			{Name: "host", Required: true},
+		},
+	}}, templates)
+
+	tp, config, err := store.Render(ctx, "cvs", map[string]string{"host": "127.0.0.1:1234", "files": "10"})
+	require.NoError(t, err)
+	require.Equal(t, pb.JobType_CVSDemo, tp)
+	require.Equal(t,
+		`{"srcHost": "127.0.0.1:1234", "dstHost": "127.0.0.1:1234", "srcDir": "data", "fileNum": 10}`,
+		string(config))
+	_, _, err = store.Render(ctx, "cvs", map[string]string{"host": "127.0.0.1:1234"})
+	require.True(t, errors.ErrJobTemplateParams.Equal(err))
+	// the rendered config is checked
+	_, _, err = store.Render(ctx, "cvs", map[string]string{"host": "127.0.0.1:1234", "files": "ten"})
+	require.True(t, errors.ErrBuildJobFailed.Equal(err))
+
+	require.NoError(t, store.Delete(ctx, "cvs"))
+	_, _, err = store.Render(ctx, "cvs", nil)
+	require.True(t, errors.ErrJobTemplateNotFound.Equal(err))
+	_, err = store.Query(ctx, "cvs")
+	require.True(t, errors.ErrJobTemplateNotFound.Equal(err))
+	require.True(t, errors.ErrJobTemplateNotFound.Equal(store.Delete(ctx, "cvs")))
+}
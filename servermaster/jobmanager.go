@@ -6,19 +6,27 @@ import (
 	"time"
 
 	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/dig"
 	"go.uber.org/zap"
 
 	cvs "github.com/hanfei1991/microcosm/jobmaster/cvsJob"
+	dmconfig "github.com/hanfei1991/microcosm/jobmaster/dm/config"
 	"github.com/hanfei1991/microcosm/lib"
+	"github.com/hanfei1991/microcosm/lib/master"
 	"github.com/hanfei1991/microcosm/lib/metadata"
 	libModel "github.com/hanfei1991/microcosm/lib/model"
 	"github.com/hanfei1991/microcosm/pb"
 	"github.com/hanfei1991/microcosm/pkg/clock"
 	dcontext "github.com/hanfei1991/microcosm/pkg/context"
 	derrors "github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/logutil"
+	extkv "github.com/hanfei1991/microcosm/pkg/meta/extension"
 	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
 	"github.com/hanfei1991/microcosm/pkg/p2p"
+	"github.com/hanfei1991/microcosm/pkg/redact"
+	"github.com/hanfei1991/microcosm/pkg/sink"
 	"github.com/hanfei1991/microcosm/pkg/uuid"
+	schedModel "github.com/hanfei1991/microcosm/servermaster/scheduler/model"
 )
 
 // JobManager defines manager of job master
@@ -28,13 +36,47 @@ type JobManager interface {
 
 	SubmitJob(ctx context.Context, req *pb.SubmitJobRequest) *pb.SubmitJobResponse
 	QueryJob(ctx context.Context, req *pb.QueryJobRequest) *pb.QueryJobResponse
+	QueryJobs(ctx context.Context, req *pb.QueryJobsRequest) *pb.QueryJobsResponse
+	ListWorkers(ctx context.Context, req *pb.ListWorkersRequest) *pb.ListWorkersResponse
 	CancelJob(ctx context.Context, req *pb.CancelJobRequest) *pb.CancelJobResponse
 	PauseJob(ctx context.Context, req *pb.PauseJobRequest) *pb.PauseJobResponse
+	UpdateJobTimeouts(ctx context.Context, req *pb.UpdateJobTimeoutsRequest) *pb.UpdateJobTimeoutsResponse
+	SetJobLogLevel(ctx context.Context, req *pb.SetJobLogLevelRequest) *pb.SetJobLogLevelResponse
+	OperateJobTasks(ctx context.Context, req *pb.OperateJobTasksRequest) *pb.OperateJobTasksResponse
+	UpdateJobSource(ctx context.Context, req *pb.UpdateJobSourceRequest) *pb.UpdateJobSourceResponse
+	// DeleteJob deletes a job with all its data, only finished or stopped
+	// jobs can be deleted unless force is true.
+	DeleteJob(ctx context.Context, jobID libModel.MasterID, force bool) error
+
+	CreateJobSchedule(ctx context.Context, req *pb.CreateJobScheduleRequest) *pb.CreateJobScheduleResponse
+	UpdateJobSchedule(ctx context.Context, req *pb.UpdateJobScheduleRequest) *pb.UpdateJobScheduleResponse
+	DeleteJobSchedule(ctx context.Context, req *pb.DeleteJobScheduleRequest) *pb.DeleteJobScheduleResponse
+	QueryJobSchedules(ctx context.Context, req *pb.QueryJobSchedulesRequest) *pb.QueryJobSchedulesResponse
+
+	RegisterJobTemplate(ctx context.Context, req *pb.RegisterJobTemplateRequest) *pb.RegisterJobTemplateResponse
+	DeleteJobTemplate(ctx context.Context, req *pb.DeleteJobTemplateRequest) *pb.DeleteJobTemplateResponse
+	QueryJobTemplates(ctx context.Context, req *pb.QueryJobTemplatesRequest) *pb.QueryJobTemplatesResponse
 
 	GetJobStatuses(ctx context.Context) (map[libModel.MasterID]libModel.MasterStatusCode, error)
 }
 
-const defaultJobMasterCost = 1
+// JobReserver reserves the capacity of the jobs declaring resource specs,
+// it is implemented by scheduler.Scheduler.
+type JobReserver interface {
+	ReserveJob(jobID string, spec *schedModel.JobResourceSpec) error
+	RestoreJobReservation(jobID string, spec *schedModel.JobResourceSpec)
+	ReleaseJob(jobID string)
+}
+
+const (
+	defaultJobMasterCost = 1
+
+	defaultListWorkersPageSize = 100
+	maxListWorkersPageSize     = 1000
+	// listWorkersTimeout limits the time waiting for the job master to
+	// reply the workers query.
+	listWorkersTimeout = 5 * time.Second
+)
 
 // JobManagerImplV2 is a special job master that manages all the job masters, and notify the offline executor to them.
 // worker state transition
@@ -50,7 +92,24 @@ type JobManagerImplV2 struct {
 	uuidGen          uuid.Generator
 	clocker          clock.Clock
 	frameMetaClient  pkgOrm.Client
+	jobDeleter       *jobDeleter
+	jobScheduler     *jobScheduler
+	jobTemplates     *jobTemplateStore
+	// jobRetention is nil if finished jobs are kept forever.
+	jobRetention     *jobRetention
 	tombstoneCleaned bool
+	// sinkExporter is nil unless job events are exported.
+	sinkExporter *sink.Exporter
+	// jobReserver is nil if the resource specs of jobs are ignored.
+	jobReserver JobReserver
+}
+
+type jobManagerParams struct {
+	dig.In
+
+	SinkExporter *sink.Exporter      `optional:"true"`
+	JobReserver  JobReserver         `optional:"true"`
+	JobRetention *JobRetentionConfig `optional:"true"`
 }
 
 // PauseJob implements proto/Master.PauseJob
@@ -61,15 +120,8 @@ func (jm *JobManagerImplV2) PauseJob(ctx context.Context, req *pb.PauseJobReques
 			Code: pb.ErrorCode_UnKnownJob,
 		}}
 	}
-	topic := libModel.WorkerStatusChangeRequestTopic(jm.BaseMaster.MasterID(), job.WorkerHandle.ID())
-	msg := &libModel.StatusChangeRequest{
-		SendTime:     jm.clocker.Mono(),
-		FromMasterID: jm.BaseMaster.MasterID(),
-		Epoch:        jm.BaseMaster.MasterMeta().Epoch,
-		ExpectState:  libModel.WorkerStatusStopped,
-	}
 	if handle := job.WorkerHandle.Unwrap(); handle != nil {
-		err := handle.SendMessage(ctx, topic, msg, true /*nonblocking*/)
+		err := jm.sendStopRequest(ctx, handle)
 		return &pb.PauseJobResponse{Err: derrors.ToPBError(err)}
 	}
 	// The job is a tombstone, which means that the job has already exited.
@@ -78,13 +130,169 @@ func (jm *JobManagerImplV2) PauseJob(ctx context.Context, req *pb.PauseJobReques
 	}}
 }
 
+func (jm *JobManagerImplV2) sendStopRequest(ctx context.Context, handle master.RunningHandle) error {
+	topic := libModel.WorkerStatusChangeRequestTopic(jm.BaseMaster.MasterID(), handle.ID())
+	msg := &libModel.StatusChangeRequest{
+		SendTime:     jm.clocker.Mono(),
+		FromMasterID: jm.BaseMaster.MasterID(),
+		Epoch:        jm.BaseMaster.MasterMeta().Epoch,
+		ExpectState:  libModel.WorkerStatusStopped,
+	}
+	return handle.SendMessage(ctx, topic, msg, true /*nonblocking*/)
+}
+
+// UpdateJobTimeouts implements proto/Master.UpdateJobTimeouts
+func (jm *JobManagerImplV2) UpdateJobTimeouts(
+	ctx context.Context, req *pb.UpdateJobTimeoutsRequest,
+) *pb.UpdateJobTimeoutsResponse {
+	if req.WorkerTimeoutMs < 0 || req.WorkerTimeoutGracefulMs < 0 || req.WorkerHeartbeatIntervalMs < 0 {
+		err := derrors.ErrInvalidTimeoutConfig.GenWithStackByArgs("timeouts must not be negative")
+		return &pb.UpdateJobTimeoutsResponse{Err: derrors.ToPBError(err)}
+	}
+	job := jm.JobFsm.QueryOnlineJob(req.JobId)
+	if job == nil {
+		return &pb.UpdateJobTimeoutsResponse{Err: &pb.Error{
+			Code: pb.ErrorCode_UnKnownJob,
+		}}
+	}
+	handle := job.WorkerHandle.Unwrap()
+	if handle == nil {
+		// The job is a tombstone, which means that the job has already exited.
+		return &pb.UpdateJobTimeoutsResponse{Err: &pb.Error{
+			Code: pb.ErrorCode_UnKnownJob,
+		}}
+	}
+	topic := libModel.TimeoutsUpdateRequestTopic(jm.BaseMaster.MasterID(), handle.ID())
+	msg := &libModel.TimeoutsUpdateRequest{
+		SendTime:                      jm.clocker.Mono(),
+		FromMasterID:                  jm.BaseMaster.MasterID(),
+		Epoch:                         jm.BaseMaster.MasterMeta().Epoch,
+		WorkerTimeoutDuration:         time.Duration(req.WorkerTimeoutMs) * time.Millisecond,
+		WorkerTimeoutGracefulDuration: time.Duration(req.WorkerTimeoutGracefulMs) * time.Millisecond,
+		WorkerHeartbeatInterval:       time.Duration(req.WorkerHeartbeatIntervalMs) * time.Millisecond,
+	}
+	err := handle.SendMessage(ctx, topic, msg, true /*nonblocking*/)
+	return &pb.UpdateJobTimeoutsResponse{Err: derrors.ToPBError(err)}
+}
+
+// SetJobLogLevel implements proto/Master.SetJobLogLevel. The level is sent
+// to the job master, which forwards it to its workers, and the logs of the
+// job in the server master follow it as well.
+func (jm *JobManagerImplV2) SetJobLogLevel(
+	ctx context.Context, req *pb.SetJobLogLevelRequest,
+) *pb.SetJobLogLevelResponse {
+	if req.Level != "" {
+		if _, err := logutil.ParseLevel(req.Level); err != nil {
+			err = derrors.ErrInvalidLogLevel.GenWithStackByArgs(req.Level)
+			return &pb.SetJobLogLevelResponse{Err: derrors.ToPBError(err)}
+		}
+	}
+	job := jm.JobFsm.QueryOnlineJob(req.JobId)
+	if job == nil {
+		return &pb.SetJobLogLevelResponse{Err: &pb.Error{
+			Code: pb.ErrorCode_UnKnownJob,
+		}}
+	}
+	handle := job.WorkerHandle.Unwrap()
+	if handle == nil {
+		// The job is a tombstone, which means that the job has already exited.
+		return &pb.SetJobLogLevelResponse{Err: &pb.Error{
+			Code: pb.ErrorCode_UnKnownJob,
+		}}
+	}
+	topic := libModel.LogLevelUpdateRequestTopic(jm.BaseMaster.MasterID(), handle.ID())
+	msg := &libModel.LogLevelUpdateRequest{
+		SendTime:     jm.clocker.Mono(),
+		FromMasterID: jm.BaseMaster.MasterID(),
+		Epoch:        jm.BaseMaster.MasterMeta().Epoch,
+		JobID:        handle.ID(),
+		Level:        req.Level,
+	}
+	if err := handle.SendMessage(ctx, topic, msg, true /*nonblocking*/); err != nil {
+		return &pb.SetJobLogLevelResponse{Err: derrors.ToPBError(err)}
+	}
+	err := logutil.UpdateJobLevel(req.JobId, req.Level)
+	return &pb.SetJobLogLevelResponse{Err: derrors.ToPBError(err)}
+}
+
+// OperateJobTasks implements proto/Master.OperateJobTasks. The operation is
+// sent to the job master, which applies it if it supports task operations.
+func (jm *JobManagerImplV2) OperateJobTasks(
+	ctx context.Context, req *pb.OperateJobTasksRequest,
+) *pb.OperateJobTasksResponse {
+	op, ok := taskOperations[req.Op]
+	if !ok {
+		err := derrors.ErrInvalidTaskOperation.GenWithStackByArgs(req.Op)
+		return &pb.OperateJobTasksResponse{Err: derrors.ToPBError(err)}
+	}
+	job := jm.JobFsm.QueryOnlineJob(req.JobId)
+	if job == nil {
+		return &pb.OperateJobTasksResponse{Err: &pb.Error{
+			Code: pb.ErrorCode_UnKnownJob,
+		}}
+	}
+	handle := job.WorkerHandle.Unwrap()
+	if handle == nil {
+		// The job is a tombstone, which means that the job has already exited.
+		return &pb.OperateJobTasksResponse{Err: &pb.Error{
+			Code: pb.ErrorCode_UnKnownJob,
+		}}
+	}
+	topic := libModel.TasksOperateRequestTopic(jm.BaseMaster.MasterID(), handle.ID())
+	msg := &libModel.TasksOperateRequest{
+		SendTime:     jm.clocker.Mono(),
+		FromMasterID: jm.BaseMaster.MasterID(),
+		Epoch:        jm.BaseMaster.MasterMeta().Epoch,
+		Op:           op,
+		Tasks:        req.Tasks,
+	}
+	err := handle.SendMessage(ctx, topic, msg, true /*nonblocking*/)
+	return &pb.OperateJobTasksResponse{Err: derrors.ToPBError(err)}
+}
+
+// UpdateJobSource implements proto/Master.UpdateJobSource. The new config is
+// sent to the job master, which validates and applies it if it supports
+// source updates.
+func (jm *JobManagerImplV2) UpdateJobSource(
+	ctx context.Context, req *pb.UpdateJobSourceRequest,
+) *pb.UpdateJobSourceResponse {
+	if req.SourceId == "" || len(req.Config) == 0 {
+		err := derrors.ErrInvalidSourceUpdate.GenWithStackByArgs("source id and config should not be empty")
+		return &pb.UpdateJobSourceResponse{Err: derrors.ToPBError(err)}
+	}
+	job := jm.JobFsm.QueryOnlineJob(req.JobId)
+	if job == nil {
+		return &pb.UpdateJobSourceResponse{Err: &pb.Error{
+			Code: pb.ErrorCode_UnKnownJob,
+		}}
+	}
+	handle := job.WorkerHandle.Unwrap()
+	if handle == nil {
+		// The job is a tombstone, which means that the job has already exited.
+		return &pb.UpdateJobSourceResponse{Err: &pb.Error{
+			Code: pb.ErrorCode_UnKnownJob,
+		}}
+	}
+	topic := libModel.SourceUpdateRequestTopic(jm.BaseMaster.MasterID(), handle.ID())
+	msg := &libModel.SourceUpdateRequest{
+		SendTime:     jm.clocker.Mono(),
+		FromMasterID: jm.BaseMaster.MasterID(),
+		Epoch:        jm.BaseMaster.MasterMeta().Epoch,
+		SourceID:     req.SourceId,
+		Config:       req.Config,
+	}
+	err := handle.SendMessage(ctx, topic, msg, true /*nonblocking*/)
+	return &pb.UpdateJobSourceResponse{Err: derrors.ToPBError(err)}
+}
+
+var taskOperations = map[pb.JobTaskOp]libModel.TaskOperation{
+	pb.JobTaskOp_TaskOpPause:  libModel.TaskOperationPause,
+	pb.JobTaskOp_TaskOpResume: libModel.TaskOperationResume,
+	pb.JobTaskOp_TaskOpStop:   libModel.TaskOperationStop,
+}
+
 // CancelJob implements proto/Master.CancelJob
 func (jm *JobManagerImplV2) CancelJob(ctx context.Context, req *pb.CancelJobRequest) *pb.CancelJobResponse {
-	// This is a draft implementation.
-	// TODO:
-	// (1) Handle potential race conditions.
-	// (2) Refine error handling.
-
 	job, err := jm.frameMetaClient.GetJobByID(ctx, req.GetJobIdStr())
 	if pkgOrm.IsNotFoundError(err) {
 		return &pb.CancelJobResponse{Err: &pb.Error{
@@ -105,23 +313,61 @@ func (jm *JobManagerImplV2) CancelJob(ctx context.Context, req *pb.CancelJobRequ
 		}}
 	}
 
-	// Note that DeleteJob is a soft delete.
-	res, err := jm.frameMetaClient.DeleteJob(ctx, req.JobIdStr)
-	if err != nil {
+	if err := jm.DeleteJob(ctx, req.JobIdStr, false /*force*/); err != nil {
+		if derrors.ErrJobNotFound.Equal(err) {
+			logutil.WithJobID(log.L(), req.JobIdStr).Warn(
+				"Job not found in meta (or already deleted)", zap.Any("req", req))
+			return &pb.CancelJobResponse{}
+		}
 		return &pb.CancelJobResponse{Err: &pb.Error{
 			Code:    pb.ErrorCode_UnknownError,
 			Message: err.Error(),
 		}}
 	}
-	if res.RowsAffected() == 0 {
-		log.L().Warn("Job not found in meta (or already deleted)",
-			zap.Any("req", req))
-	}
+	jm.emitJobEvent(sink.EventJobCanceled, job.ProjectID, job.ID)
 	return &pb.CancelJobResponse{}
 }
 
+// DeleteJob implements JobManager.DeleteJob
+func (jm *JobManagerImplV2) DeleteJob(ctx context.Context, jobID libModel.MasterID, force bool) error {
+	logger := logutil.WithJobID(log.L(), jobID)
+	if force {
+		// stop the job master on a best-effort basis, its data is deleted
+		// regardless of whether it has exited
+		if job := jm.JobFsm.QueryOnlineJob(jobID); job != nil {
+			if handle := job.WorkerHandle.Unwrap(); handle != nil {
+				if err := jm.sendStopRequest(ctx, handle); err != nil {
+					logger.Warn("failed to stop job before force deletion", zap.Error(err))
+				}
+			}
+		}
+	}
+
+	if err := jm.jobDeleter.Delete(ctx, jobID, force); err != nil {
+		return err
+	}
+	jm.releaseJob(jobID)
+	logger.Info("job deleted", zap.Bool("force", force))
+	return nil
+}
+
+// expireJob deletes a finished job exceeding the retention config
+func (jm *JobManagerImplV2) expireJob(ctx context.Context, job *libModel.MasterMetaKVData) error {
+	if err := jm.DeleteJob(ctx, job.ID, false /*force*/); err != nil {
+		return err
+	}
+	jm.emitJobEvent(sink.EventJobExpired, job.ProjectID, job.ID)
+	return nil
+}
+
 // QueryJob implements proto/Master.QueryJob
 func (jm *JobManagerImplV2) QueryJob(ctx context.Context, req *pb.QueryJobRequest) *pb.QueryJobResponse {
+	resp := jm.queryJob(ctx, req)
+	resp.Config = redactJobConfig(libModel.WorkerType(resp.Tp), resp.Config)
+	return resp
+}
+
+func (jm *JobManagerImplV2) queryJob(ctx context.Context, req *pb.QueryJobRequest) *pb.QueryJobResponse {
 	resp := jm.JobFsm.QueryJob(req.JobId)
 	if resp != nil {
 		return resp
@@ -130,12 +376,14 @@ func (jm *JobManagerImplV2) QueryJob(ctx context.Context, req *pb.QueryJobReques
 	// TODO: refine the Load method here, seems strange
 	mcli := metadata.NewMasterMetadataClient(req.JobId, jm.frameMetaClient)
 	if masterMeta, err := mcli.Load(ctx); err != nil {
-		log.L().Warn("failed to load master kv meta from meta store", zap.Any("id", req.JobId), zap.Error(err))
+		logutil.WithJobID(log.L(), req.JobId).Warn("failed to load master kv meta from meta store", zap.Error(err))
 	} else {
 		if masterMeta != nil {
 			resp := &pb.QueryJobResponse{
-				Tp:     int64(masterMeta.Tp),
-				Config: masterMeta.Config,
+				Tp:                         int64(masterMeta.Tp),
+				Config:                     masterMeta.Config,
+				MaxCreateWorkerConcurrency: masterMeta.MaxCreateWorkerConcurrency,
+				Labels:                     masterMeta.Labels,
 			}
 			switch masterMeta.StatusCode {
 			case libModel.MasterStatusFinished:
@@ -145,8 +393,8 @@ func (jm *JobManagerImplV2) QueryJob(ctx context.Context, req *pb.QueryJobReques
 				resp.Status = pb.QueryJobResponse_stopped
 				return resp
 			default:
-				log.L().Warn("load master kv meta from meta store, but status is not expected",
-					zap.Any("id", req.JobId), zap.Any("status", masterMeta.StatusCode), zap.Any("meta", masterMeta))
+				logutil.WithJobID(log.L(), req.JobId).Warn("load master kv meta from meta store, but status is not expected",
+					zap.Any("status", masterMeta.StatusCode), redact.Any("meta", masterMeta))
 			}
 		}
 	}
@@ -157,40 +405,178 @@ func (jm *JobManagerImplV2) QueryJob(ctx context.Context, req *pb.QueryJobReques
 	}
 }
 
+// QueryJobs implements proto/Master.QueryJobs
+func (jm *JobManagerImplV2) QueryJobs(ctx context.Context, req *pb.QueryJobsRequest) *pb.QueryJobsResponse {
+	selector, err := libModel.ParseLabelSelector(req.GetLabelSelector())
+	if err != nil {
+		err = derrors.ErrInvalidLabelSelector.GenWithStackByArgs(err.Error())
+		return &pb.QueryJobsResponse{Err: derrors.ToPBError(err)}
+	}
+	// the jobs are selected by the indexes of the job table, and then
+	// filtered by the labels, which are not indexed.
+	filter := &pkgOrm.JobFilter{ProjectID: req.GetProjectId()}
+	for _, code := range req.GetStatusCodes() {
+		filter.StatusCodes = append(filter.StatusCodes, libModel.MasterStatusCode(code))
+	}
+	for _, tp := range req.GetTypes() {
+		filter.Types = append(filter.Types, libModel.WorkerType(tp))
+	}
+	jobs, err := jm.frameMetaClient.QueryJobsByFilter(ctx, filter)
+	if err != nil {
+		return &pb.QueryJobsResponse{Err: derrors.ToPBError(err)}
+	}
+
+	resp := &pb.QueryJobsResponse{}
+	for _, job := range jobs {
+		// the job manager itself is stored as a job as well
+		if job.ID == metadata.JobManagerUUID || !selector.Matches(job.Labels) {
+			continue
+		}
+		resp.Jobs = append(resp.Jobs, &pb.JobInfo{
+			JobId:     job.ID,
+			ProjectId: job.ProjectID,
+			Tp:        int64(job.Tp),
+			Status:    jm.jobStatus(job),
+			Labels:    job.Labels,
+		})
+	}
+	return resp
+}
+
+// ListWorkers implements proto/Master.ListWorkers. The workers are queried
+// from the job master, which keeps the latest states of them.
+func (jm *JobManagerImplV2) ListWorkers(ctx context.Context, req *pb.ListWorkersRequest) *pb.ListWorkersResponse {
+	job := jm.JobFsm.QueryOnlineJob(req.JobId)
+	if job == nil {
+		return &pb.ListWorkersResponse{Err: &pb.Error{
+			Code: pb.ErrorCode_UnKnownJob,
+		}}
+	}
+	handle := job.WorkerHandle.Unwrap()
+	if handle == nil {
+		// The job is a tombstone, which means that the job has already exited.
+		return &pb.ListWorkersResponse{Err: &pb.Error{
+			Code: pb.ErrorCode_UnKnownJob,
+		}}
+	}
+
+	query := &libModel.WorkersQuery{
+		ExecutorID: req.ExecutorId,
+		PageToken:  req.PageToken,
+		PageSize:   int(req.PageSize),
+	}
+	if query.PageSize <= 0 {
+		query.PageSize = defaultListWorkersPageSize
+	} else if query.PageSize > maxListWorkersPageSize {
+		query.PageSize = maxListWorkersPageSize
+	}
+	for _, code := range req.StatusCodes {
+		query.StatusCodes = append(query.StatusCodes, libModel.WorkerStatusCode(code))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, listWorkersTimeout)
+	defer cancel()
+	result, err := jm.BaseMaster.QueryWorkers(ctx, handle.ID(), query)
+	if err != nil {
+		return &pb.ListWorkersResponse{Err: derrors.ToPBError(err)}
+	}
+	resp := &pb.ListWorkersResponse{NextPageToken: result.NextPageToken}
+	for _, w := range result.Workers {
+		worker := &pb.WorkerSummary{
+			Id:           w.ID,
+			ExecutorId:   w.ExecutorID,
+			State:        w.State,
+			StatusCode:   int32(w.StatusCode),
+			ErrorMessage: w.ErrorMessage,
+		}
+		if !w.HeartbeatAt.IsZero() {
+			worker.HeartbeatTime = w.HeartbeatAt.UnixMilli()
+		}
+		resp.Workers = append(resp.Workers, worker)
+	}
+	return resp
+}
+
+// jobStatus returns the status of a job, which is managed by the fsm unless
+// the job has terminated.
+func (jm *JobManagerImplV2) jobStatus(job *libModel.MasterMetaKVData) pb.QueryJobResponse_JobStatus {
+	if status, ok := jm.JobFsm.JobStatus(job.ID); ok {
+		return status
+	}
+	switch job.StatusCode {
+	case libModel.MasterStatusFinished:
+		return pb.QueryJobResponse_finished
+	case libModel.MasterStatusStopped:
+		return pb.QueryJobResponse_stopped
+	default:
+		return pb.QueryJobResponse_init
+	}
+}
+
 // SubmitJob processes "SubmitJobRequest".
 func (jm *JobManagerImplV2) SubmitJob(ctx context.Context, req *pb.SubmitJobRequest) *pb.SubmitJobResponse {
-	log.L().Logger.Info("submit job", zap.String("config", string(req.Config)))
 	resp := &pb.SubmitJobResponse{}
 	var (
 		id  libModel.WorkerID
 		err error
 	)
 
+	if req.GetMaxCreateWorkerConcurrency() < 0 {
+		err = derrors.ErrBuildJobFailed.GenWithStack(
+			"invalid max create worker concurrency: %d", req.GetMaxCreateWorkerConcurrency())
+		resp.Err = derrors.ToPBError(err)
+		return resp
+	}
+	var resourceSpec *libModel.JobResourceSpec
+	if spec := req.GetResourceSpec(); spec != nil {
+		resourceSpec = &libModel.JobResourceSpec{
+			MinWorkers:      spec.GetMinWorkers(),
+			MaxWorkers:      spec.GetMaxWorkers(),
+			WorkerResources: spec.GetWorkerResources(),
+		}
+		if err = resourceSpec.Validate(); err != nil {
+			err = derrors.ErrBuildJobFailed.GenWithStack("invalid resource spec: %v", err)
+			resp.Err = derrors.ToPBError(err)
+			return resp
+		}
+	}
+	labels := libModel.JobLabels(req.GetLabels())
+	if err = labels.Validate(); err != nil {
+		err = derrors.ErrBuildJobFailed.GenWithStack("invalid labels: %v", err)
+		resp.Err = derrors.ToPBError(err)
+		return resp
+	}
+	tp, config := req.GetTp(), req.GetConfig()
+	if req.GetTemplateId() != "" {
+		tp, config, err = jm.jobTemplates.Render(ctx, req.GetTemplateId(), req.GetTemplateParams())
+		if err != nil {
+			resp.Err = derrors.ToPBError(err)
+			return resp
+		}
+	}
 	meta := &libModel.MasterMetaKVData{
 		ProjectID: req.GetUser(),
 		// TODO: we can use job name provided from user, but we must check the
 		// job name is unique before using it.
 		ID:         jm.uuidGen.NewString(),
-		Config:     req.GetConfig(),
+		Config:     config,
 		StatusCode: libModel.MasterStatusUninit,
+
+		MaxCreateWorkerConcurrency: req.GetMaxCreateWorkerConcurrency(),
+		ResourceSpec:               resourceSpec,
+		Labels:                     labels,
 	}
-	switch req.Tp {
-	case pb.JobType_CVSDemo:
-		// TODO: check config is valid, refine it later
-		extConfig := &cvs.Config{}
-		err = json.Unmarshal(req.Config, extConfig)
-		if err != nil {
-			err := derrors.ErrBuildJobFailed.GenWithStack("failed to decode config: %s", req.Config)
-			resp.Err = derrors.ToPBError(err)
-			return resp
-		}
-		meta.Tp = lib.CvsJobMaster
-	case pb.JobType_DM:
-		meta.Tp = lib.DMJobMaster
-	case pb.JobType_FakeJob:
-		meta.Tp = lib.FakeJobMaster
-	default:
-		err := derrors.ErrBuildJobFailed.GenWithStack("unknown job type: %s", req.Tp)
+	meta.Tp, err = jobMasterType(tp, config)
+	if err != nil {
+		resp.Err = derrors.ToPBError(err)
+		return resp
+	}
+	log.L().Logger.Info("submit job", zap.ByteString("config", redactJobConfig(meta.Tp, config)),
+		zap.String("template-id", req.GetTemplateId()))
+
+	// The capacity of the min workers is reserved before the job is created,
+	// so that a job that can't run is rejected at once.
+	if err = jm.reserveJob(meta); err != nil {
 		resp.Err = derrors.ToPBError(err)
 		return resp
 	}
@@ -198,6 +584,7 @@ func (jm *JobManagerImplV2) SubmitJob(ctx context.Context, req *pb.SubmitJobRequ
 	// Store job master meta data before creating it
 	err = metadata.StoreMasterMeta(ctx, jm.frameMetaClient, meta)
 	if err != nil {
+		jm.releaseJob(meta.ID)
 		resp.Err = derrors.ToPBError(err)
 		return resp
 	}
@@ -207,22 +594,190 @@ func (jm *JobManagerImplV2) SubmitJob(ctx context.Context, req *pb.SubmitJobRequ
 	id, err = jm.BaseMaster.CreateWorker(
 		meta.Tp, meta, defaultJobMasterCost)
 	if err != nil {
+		jm.releaseJob(meta.ID)
+		logger := logutil.WithJobID(log.L(), meta.ID)
 		err2 := metadata.DeleteMasterMeta(ctx, jm.frameMetaClient, meta.ID)
 		if err2 != nil {
 			// TODO: add more GC mechanism if master meta is failed to delete
-			log.L().Error("failed to delete master meta", zap.Error(err2))
+			logger.Error("failed to delete master meta", zap.Error(err2))
 		}
 
-		log.L().Error("create job master met error", zap.Error(err))
+		logger.Error("create job master met error", zap.Error(err))
 		resp.Err = derrors.ToPBError(err)
 		return resp
 	}
 
 	jm.JobFsm.JobDispatched(meta, false /*addFromFailover*/)
+	jm.emitJobEvent(sink.EventJobSubmitted, meta.ProjectID, id)
 	resp.JobIdStr = id
 	return resp
 }
 
+// reserveJob reserves the capacity of a job if it declares a resource spec.
+func (jm *JobManagerImplV2) reserveJob(meta *libModel.MasterMetaKVData) error {
+	if jm.jobReserver == nil || meta.ResourceSpec == nil {
+		return nil
+	}
+	return jm.jobReserver.ReserveJob(meta.ID, toSchedulerSpec(meta.ResourceSpec))
+}
+
+func (jm *JobManagerImplV2) releaseJob(jobID libModel.MasterID) {
+	if jm.jobReserver != nil {
+		jm.jobReserver.ReleaseJob(jobID)
+	}
+}
+
+func toSchedulerSpec(spec *libModel.JobResourceSpec) *schedModel.JobResourceSpec {
+	return &schedModel.JobResourceSpec{
+		MinWorkers:      int(spec.MinWorkers),
+		MaxWorkers:      int(spec.MaxWorkers),
+		WorkerResources: spec.WorkerResources,
+	}
+}
+
+// CreateJobSchedule implements proto/Master.CreateJobSchedule
+func (jm *JobManagerImplV2) CreateJobSchedule(
+	ctx context.Context, req *pb.CreateJobScheduleRequest,
+) *pb.CreateJobScheduleResponse {
+	id, err := jm.jobScheduler.Create(ctx, req.GetSchedule())
+	if err != nil {
+		return &pb.CreateJobScheduleResponse{Err: derrors.ToPBError(err)}
+	}
+	return &pb.CreateJobScheduleResponse{ScheduleId: id}
+}
+
+// UpdateJobSchedule implements proto/Master.UpdateJobSchedule
+func (jm *JobManagerImplV2) UpdateJobSchedule(
+	ctx context.Context, req *pb.UpdateJobScheduleRequest,
+) *pb.UpdateJobScheduleResponse {
+	err := jm.jobScheduler.Update(ctx, req.GetSchedule())
+	return &pb.UpdateJobScheduleResponse{Err: derrors.ToPBError(err)}
+}
+
+// DeleteJobSchedule implements proto/Master.DeleteJobSchedule
+func (jm *JobManagerImplV2) DeleteJobSchedule(
+	ctx context.Context, req *pb.DeleteJobScheduleRequest,
+) *pb.DeleteJobScheduleResponse {
+	err := jm.jobScheduler.Delete(ctx, req.GetScheduleId())
+	return &pb.DeleteJobScheduleResponse{Err: derrors.ToPBError(err)}
+}
+
+// QueryJobSchedules implements proto/Master.QueryJobSchedules
+func (jm *JobManagerImplV2) QueryJobSchedules(
+	ctx context.Context, req *pb.QueryJobSchedulesRequest,
+) *pb.QueryJobSchedulesResponse {
+	schedules, err := jm.jobScheduler.Query(req.GetScheduleId())
+	if err != nil {
+		return &pb.QueryJobSchedulesResponse{Err: derrors.ToPBError(err)}
+	}
+	for _, schedule := range schedules {
+		// a config that can't be decoded has no master type and is masked
+		masterTp, _ := jobMasterType(schedule.Tp, schedule.Config)
+		schedule.Config = redactJobConfig(masterTp, schedule.Config)
+	}
+	return &pb.QueryJobSchedulesResponse{Schedules: schedules}
+}
+
+// jobMasterType returns the type of job master of the job type, after
+// checking the config can be decoded.
+func jobMasterType(tp pb.JobType, config []byte) (libModel.WorkerType, error) {
+	switch tp {
+	case pb.JobType_CVSDemo:
+		// TODO: check config is valid, refine it later
+		extConfig := &cvs.Config{}
+		if err := json.Unmarshal(config, extConfig); err != nil {
+			// the config is not put in the error, which may carry credentials
+			return 0, derrors.ErrBuildJobFailed.GenWithStack("failed to decode config: %s", err)
+		}
+		return lib.CvsJobMaster, nil
+	case pb.JobType_DM:
+		if err := (&dmconfig.JobCfg{}).Decode(config); err != nil {
+			return 0, derrors.ErrBuildJobFailed.GenWithStack("failed to decode config: %s", err)
+		}
+		return lib.DMJobMaster, nil
+	case pb.JobType_FakeJob:
+		return lib.FakeJobMaster, nil
+	default:
+		return 0, derrors.ErrBuildJobFailed.GenWithStack("unknown job type: %s", tp)
+	}
+}
+
+// redactJobConfig returns the config of the job master type with the
+// sensitive values replaced, which is logged or returned by the APIs. A
+// config that can't be decoded is masked as a whole.
+func redactJobConfig(tp libModel.WorkerType, config []byte) []byte {
+	if len(config) == 0 {
+		return config
+	}
+	switch tp {
+	case lib.CvsJobMaster:
+		extConfig := &cvs.Config{}
+		if err := json.Unmarshal(config, extConfig); err != nil {
+			break
+		}
+		redacted, err := json.Marshal(redact.Value(extConfig))
+		if err != nil {
+			break
+		}
+		return redacted
+	case lib.DMJobMaster:
+		jobCfg := &dmconfig.JobCfg{}
+		if err := jobCfg.Decode(config); err != nil {
+			break
+		}
+		redacted, err := redact.Value(jobCfg).(*dmconfig.JobCfg).Yaml()
+		if err != nil {
+			break
+		}
+		return []byte(redacted)
+	case lib.FakeJobMaster:
+		// the config of fake jobs carries no credentials
+		return config
+	default:
+	}
+	return []byte(redact.Mask)
+}
+
+// RegisterJobTemplate implements proto/Master.RegisterJobTemplate
+func (jm *JobManagerImplV2) RegisterJobTemplate(
+	ctx context.Context, req *pb.RegisterJobTemplateRequest,
+) *pb.RegisterJobTemplateResponse {
+	err := jm.jobTemplates.Register(ctx, req.GetTemplate())
+	return &pb.RegisterJobTemplateResponse{Err: derrors.ToPBError(err)}
+}
+
+// DeleteJobTemplate implements proto/Master.DeleteJobTemplate
+func (jm *JobManagerImplV2) DeleteJobTemplate(
+	ctx context.Context, req *pb.DeleteJobTemplateRequest,
+) *pb.DeleteJobTemplateResponse {
+	err := jm.jobTemplates.Delete(ctx, req.GetTemplateId())
+	return &pb.DeleteJobTemplateResponse{Err: derrors.ToPBError(err)}
+}
+
+// QueryJobTemplates implements proto/Master.QueryJobTemplates
+func (jm *JobManagerImplV2) QueryJobTemplates(
+	ctx context.Context, req *pb.QueryJobTemplatesRequest,
+) *pb.QueryJobTemplatesResponse {
+	templates, err := jm.jobTemplates.Query(ctx, req.GetTemplateId())
+	if err != nil {
+		return &pb.QueryJobTemplatesResponse{Err: derrors.ToPBError(err)}
+	}
+	return &pb.QueryJobTemplatesResponse{Templates: templates}
+}
+
+// emitJobEvent exports an event of the job if a sink is configured.
+func (jm *JobManagerImplV2) emitJobEvent(tp sink.EventType, tenantID string, jobID libModel.MasterID) {
+	if jm.sinkExporter == nil {
+		return
+	}
+	jm.sinkExporter.Emit(&sink.Event{
+		Type:     tp,
+		Time:     jm.clocker.Now(),
+		TenantID: tenantID,
+		JobID:    jobID,
+	})
+}
+
 // GetJobStatuses returns the status code of all jobs that are not deleted.
 func (jm *JobManagerImplV2) GetJobStatuses(
 	ctx context.Context,
@@ -251,6 +806,18 @@ func NewJobManagerImplV2(
 		return nil, err
 	}
 
+	userRawKVCli, err := dctx.Deps().Construct(func(cli extkv.KVClientEx) (extkv.KVClientEx, error) {
+		return cli, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var params jobManagerParams
+	if err := dctx.Deps().Fill(&params); err != nil {
+		return nil, err
+	}
+
 	metaClient := metaCli.(pkgOrm.Client)
 	cli := metadata.NewMasterMetadataClient(id, metaClient)
 	impl := &JobManagerImplV2{
@@ -259,6 +826,14 @@ func NewJobManagerImplV2(
 		masterMetaClient: cli,
 		clocker:          clock.New(),
 		frameMetaClient:  metaClient,
+		jobDeleter:       newJobDeleter(metaClient, userRawKVCli.(extkv.KVClientEx)),
+		jobTemplates:     newJobTemplateStore(metaClient),
+		sinkExporter:     params.SinkExporter,
+		jobReserver:      params.JobReserver,
+	}
+	impl.jobScheduler = newJobScheduler(metaClient, impl.clocker, impl.uuidGen, impl.SubmitJob)
+	if params.JobRetention != nil {
+		impl.jobRetention = newJobRetention(params.JobRetention, metaClient, impl.clocker, impl.expireJob)
 	}
 	impl.BaseMaster = lib.NewBaseMaster(
 		dctx,
@@ -302,7 +877,11 @@ func (jm *JobManagerImplV2) Tick(ctx context.Context) error {
 	}
 
 	err := jm.JobFsm.IterPendingJobs(
-		func(job *libModel.MasterMetaKVData) (string, error) {
+		func(job *libModel.MasterMetaKVData, failover bool) (string, error) {
+			if failover {
+				return jm.BaseMaster.RecreateWorker(
+					job.Tp, job, defaultJobMasterCost)
+			}
 			return jm.BaseMaster.CreateWorker(
 				job.Tp, job, defaultJobMasterCost)
 		})
@@ -310,6 +889,11 @@ func (jm *JobManagerImplV2) Tick(ctx context.Context) error {
 		return err
 	}
 
+	jm.jobScheduler.Tick(ctx)
+	if jm.jobRetention != nil {
+		jm.jobRetention.Tick(ctx)
+	}
+
 	if !jm.tombstoneCleaned && jm.BaseMaster.IsMasterReady() {
 		for _, worker := range jm.BaseMaster.GetWorkers() {
 			// clean tombstone workers from worker manager and they will be
@@ -330,7 +914,7 @@ func (jm *JobManagerImplV2) Tick(ctx context.Context) error {
 		}
 		err = jm.JobFsm.IterWaitAckJobs(
 			func(job *libModel.MasterMetaKVData) (string, error) {
-				return jm.BaseMaster.CreateWorker(
+				return jm.BaseMaster.RecreateWorker(
 					job.Tp, job, defaultJobMasterCost)
 			})
 		exceedQuota, err := filterQuotaError(err)
@@ -349,6 +933,17 @@ func (jm *JobManagerImplV2) Tick(ctx context.Context) error {
 
 // OnMasterRecovered implements lib.MasterImpl.OnMasterRecovered
 func (jm *JobManagerImplV2) OnMasterRecovered(ctx context.Context) error {
+	// finish the deletions interrupted by the previous leader first, jobs
+	// still being deleted are not recovered.
+	deleting, err := jm.jobDeleter.Resume(ctx)
+	if err != nil {
+		return err
+	}
+	// the missed fires of schedules are caught up in the following ticks
+	if err := jm.jobScheduler.Load(ctx); err != nil {
+		return err
+	}
+
 	jobs, err := jm.masterMetaClient.LoadAllMasters(ctx)
 	if err != nil {
 		return err
@@ -357,10 +952,18 @@ func (jm *JobManagerImplV2) OnMasterRecovered(ctx context.Context) error {
 		if job.Tp == lib.JobManager {
 			continue
 		}
+		if _, ok := deleting[job.ID]; ok {
+			log.L().Info("skip job being deleted", zap.Any("job", job))
+			continue
+		}
 		if job.StatusCode == libModel.MasterStatusFinished || job.StatusCode == libModel.MasterStatusStopped {
 			log.L().Info("skip finished or stopped job", zap.Any("job", job))
 			continue
 		}
+		// the capacity of the job has been checked when it was submitted
+		if jm.jobReserver != nil && job.ResourceSpec != nil {
+			jm.jobReserver.RestoreJobReservation(job.ID, toSchedulerSpec(job.ResourceSpec))
+		}
 		jm.JobFsm.JobDispatched(job, true /*addFromFailover*/)
 		log.L().Info("recover job, move it to WaitAck job queue", zap.Any("job", job))
 	}
@@ -370,7 +973,7 @@ func (jm *JobManagerImplV2) OnMasterRecovered(ctx context.Context) error {
 // OnWorkerDispatched implements lib.MasterImpl.OnWorkerDispatched
 func (jm *JobManagerImplV2) OnWorkerDispatched(worker lib.WorkerHandle, result error) error {
 	if result != nil {
-		log.L().Warn("dispatch worker met error", zap.Error(result))
+		logutil.WithJobID(log.L(), worker.ID()).Warn("dispatch worker met error", zap.Error(result))
 		return jm.JobFsm.JobDispatchFailed(worker)
 	}
 	return nil
@@ -378,21 +981,33 @@ func (jm *JobManagerImplV2) OnWorkerDispatched(worker lib.WorkerHandle, result e
 
 // OnWorkerOnline implements lib.MasterImpl.OnWorkerOnline
 func (jm *JobManagerImplV2) OnWorkerOnline(worker lib.WorkerHandle) error {
-	log.L().Info("on worker online", zap.Any("id", worker.ID()))
+	logutil.WithJobID(log.L(), worker.ID()).Info("on worker online")
 	return jm.JobFsm.JobOnline(worker)
 }
 
 // OnWorkerOffline implements lib.MasterImpl.OnWorkerOffline
 func (jm *JobManagerImplV2) OnWorkerOffline(worker lib.WorkerHandle, reason error) error {
+	logger := logutil.WithJobID(log.L(), worker.ID())
 	needFailover := true
+	eventType := sink.EventJobFailed
 	if derrors.ErrWorkerFinish.Equal(reason) {
-		log.L().Info("job master finished", zap.String("id", worker.ID()))
+		logger.Info("job master finished")
 		needFailover = false
+		eventType = sink.EventJobFinished
 	} else if derrors.ErrWorkerStop.Equal(reason) {
-		log.L().Info("job master stopped", zap.String("id", worker.ID()))
+		logger.Info("job master stopped")
 		needFailover = false
+		eventType = sink.EventJobStopped
 	} else {
-		log.L().Info("on worker offline", zap.Any("id", worker.ID()), zap.Any("reason", reason))
+		logger.Info("on worker offline", zap.Any("reason", reason))
+	}
+	var tenantID string
+	if job := jm.JobFsm.QueryOnlineJob(worker.ID()); job != nil {
+		tenantID = job.ProjectID
+	}
+	jm.emitJobEvent(eventType, tenantID, worker.ID())
+	if !needFailover {
+		jm.releaseJob(worker.ID())
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
@@ -405,13 +1020,13 @@ func (jm *JobManagerImplV2) OnWorkerOffline(worker lib.WorkerHandle, reason erro
 
 // OnWorkerMessage implements lib.MasterImpl.OnWorkerMessage
 func (jm *JobManagerImplV2) OnWorkerMessage(worker lib.WorkerHandle, topic p2p.Topic, message interface{}) error {
-	log.L().Info("on worker message", zap.Any("id", worker.ID()), zap.Any("topic", topic), zap.Any("message", message))
+	logutil.WithJobID(log.L(), worker.ID()).Info("on worker message", zap.Any("topic", topic), zap.Any("message", message))
 	return nil
 }
 
 // OnWorkerStatusUpdated implements lib.MasterImpl.OnWorkerStatusUpdated
 func (jm *JobManagerImplV2) OnWorkerStatusUpdated(worker lib.WorkerHandle, newStatus *libModel.WorkerStatus) error {
-	log.L().Info("on worker status updated", zap.String("worker-id", worker.ID()), zap.Any("status", newStatus))
+	logutil.WithJobID(log.L(), worker.ID()).Info("on worker status updated", zap.Any("status", newStatus))
 	return nil
 }
 
@@ -2,11 +2,14 @@ package servermaster
 
 import (
 	"context"
+	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
 
 	"github.com/hanfei1991/microcosm/lib"
 	"github.com/hanfei1991/microcosm/lib/master"
@@ -17,7 +20,11 @@ import (
 	"github.com/hanfei1991/microcosm/pkg/clock"
 	"github.com/hanfei1991/microcosm/pkg/errors"
 	resourcemeta "github.com/hanfei1991/microcosm/pkg/externalresource/resourcemeta/model"
+	"github.com/hanfei1991/microcosm/pkg/logutil"
+	mockkv "github.com/hanfei1991/microcosm/pkg/meta/kvclient/mock"
+	"github.com/hanfei1991/microcosm/pkg/redact"
 	"github.com/hanfei1991/microcosm/pkg/uuid"
+	schedModel "github.com/hanfei1991/microcosm/servermaster/scheduler/model"
 )
 
 func TestJobManagerSubmitJob(t *testing.T) {
@@ -37,6 +44,7 @@ func TestJobManagerSubmitJob(t *testing.T) {
 		JobFsm:          NewJobFsm(),
 		uuidGen:         uuid.NewGenerator(),
 		frameMetaClient: mockMaster.GetFrameMetaClient(),
+		jobScheduler:    newJobScheduler(mockMaster.GetFrameMetaClient(), clock.New(), uuid.NewGenerator(), nil),
 	}
 	// set master impl to JobManagerImplV2
 	mockMaster.Impl = mgr
@@ -61,6 +69,89 @@ func TestJobManagerSubmitJob(t *testing.T) {
 	require.Equal(t, pb.QueryJobResponse_dispatched, queryResp.Status)
 }
 
+type mockJobReserver struct {
+	err      error
+	reserved map[string]*schedModel.JobResourceSpec
+}
+
+func (r *mockJobReserver) ReserveJob(jobID string, spec *schedModel.JobResourceSpec) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.reserved[jobID] = spec
+	return nil
+}
+
+func (r *mockJobReserver) RestoreJobReservation(jobID string, spec *schedModel.JobResourceSpec) {
+	r.reserved[jobID] = spec
+}
+
+func (r *mockJobReserver) ReleaseJob(jobID string) {
+	delete(r.reserved, jobID)
+}
+
+func TestJobManagerSubmitJobWithResourceSpec(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockMaster := lib.NewMockMasterImpl("", "submit-job-with-resource-spec-test")
+	mockMaster.On("InitImpl", mock.Anything).Return(nil)
+	mockMaster.MasterClient().On(
+		"ScheduleTask", mock.Anything, mock.Anything, mock.Anything).Return(
+		&pb.ScheduleTaskResponse{}, errors.ErrClusterResourceNotEnough.FastGenByArgs(),
+	)
+	reserver := &mockJobReserver{reserved: make(map[string]*schedModel.JobResourceSpec)}
+	mgr := &JobManagerImplV2{
+		BaseMaster:      mockMaster.DefaultBaseMaster,
+		JobFsm:          NewJobFsm(),
+		uuidGen:         uuid.NewGenerator(),
+		frameMetaClient: mockMaster.GetFrameMetaClient(),
+		jobScheduler:    newJobScheduler(mockMaster.GetFrameMetaClient(), clock.New(), uuid.NewGenerator(), nil),
+		jobReserver:     reserver,
+	}
+	mockMaster.Impl = mgr
+	err := mockMaster.Init(ctx)
+	require.Nil(t, err)
+
+	newReq := func(spec *pb.JobResourceSpec) *pb.SubmitJobRequest {
+		return &pb.SubmitJobRequest{
+			Tp:           pb.JobType_CVSDemo,
+			Config:       []byte("{\"srcHost\":\"0.0.0.0:1234\", \"dstHost\":\"0.0.0.0:1234\", \"srcDir\":\"data\", \"dstDir\":\"data1\"}"),
+			ResourceSpec: spec,
+		}
+	}
+
+	resp := mgr.SubmitJob(ctx, newReq(&pb.JobResourceSpec{MinWorkers: 3, MaxWorkers: 2}))
+	require.Equal(t, pb.ErrorCode_SubJobBuildFailed, resp.Err.GetCode())
+
+	reserver.err = errors.ErrClusterResourceNotEnough.GenWithStackByArgs()
+	resp = mgr.SubmitJob(ctx, newReq(&pb.JobResourceSpec{MinWorkers: 2}))
+	require.Equal(t, pb.ErrorCode_NotEnoughResource, resp.Err.GetCode())
+	require.Equal(t, 0, mgr.JobFsm.JobCount(pb.QueryJobResponse_dispatched))
+
+	reserver.err = nil
+	resp = mgr.SubmitJob(ctx, newReq(&pb.JobResourceSpec{
+		MinWorkers:      2,
+		MaxWorkers:      4,
+		WorkerResources: map[string]int64{"gpu": 1},
+	}))
+	require.Nil(t, resp.Err)
+	require.Equal(t, &schedModel.JobResourceSpec{
+		MinWorkers:      2,
+		MaxWorkers:      4,
+		WorkerResources: model.RescVector{"gpu": 1},
+	}, reserver.reserved[resp.JobIdStr])
+	meta, err := mockMaster.GetFrameMetaClient().GetJobByID(ctx, resp.JobIdStr)
+	require.NoError(t, err)
+	require.Equal(t, &libModel.JobResourceSpec{
+		MinWorkers:      2,
+		MaxWorkers:      4,
+		WorkerResources: model.RescVector{"gpu": 1},
+	}, meta.ResourceSpec)
+}
+
 type mockBaseMasterCreateWorkerFailed struct {
 	*lib.MockMasterImpl
 }
@@ -74,6 +165,15 @@ func (m *mockBaseMasterCreateWorkerFailed) CreateWorker(
 	return "", errors.ErrMasterConcurrencyExceeded.FastGenByArgs()
 }
 
+func (m *mockBaseMasterCreateWorkerFailed) RecreateWorker(
+	workerType lib.WorkerType,
+	config lib.WorkerConfig,
+	cost model.RescUnit,
+	resources ...resourcemeta.ResourceID,
+) (libModel.WorkerID, error) {
+	return "", errors.ErrMasterConcurrencyExceeded.FastGenByArgs()
+}
+
 func TestCreateWorkerReturnError(t *testing.T) {
 	t.Parallel()
 
@@ -89,6 +189,7 @@ func TestCreateWorkerReturnError(t *testing.T) {
 		JobFsm:          NewJobFsm(),
 		uuidGen:         uuid.NewGenerator(),
 		frameMetaClient: mockMaster.GetFrameMetaClient(),
+		jobScheduler:    newJobScheduler(mockMaster.GetFrameMetaClient(), clock.New(), uuid.NewGenerator(), nil),
 	}
 	mockMaster.Impl = mgr
 	err := mockMaster.Init(ctx)
@@ -115,6 +216,7 @@ func TestJobManagerPauseJob(t *testing.T) {
 		JobFsm:          NewJobFsm(),
 		clocker:         clock.New(),
 		frameMetaClient: mockMaster.GetFrameMetaClient(),
+		jobScheduler:    newJobScheduler(mockMaster.GetFrameMetaClient(), clock.New(), uuid.NewGenerator(), nil),
 	}
 
 	pauseWorkerID := "pause-worker-id"
@@ -139,6 +241,182 @@ func TestJobManagerPauseJob(t *testing.T) {
 	require.Equal(t, pb.ErrorCode_UnKnownJob, resp.Err.Code)
 }
 
+func TestJobManagerUpdateJobTimeouts(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockMaster := lib.NewMockMasterImpl("", "update-timeouts-test")
+	mockMaster.On("InitImpl", mock.Anything).Return(nil)
+	mgr := &JobManagerImplV2{
+		BaseMaster:      mockMaster.DefaultBaseMaster,
+		JobFsm:          NewJobFsm(),
+		clocker:         clock.New(),
+		frameMetaClient: mockMaster.GetFrameMetaClient(),
+		jobScheduler:    newJobScheduler(mockMaster.GetFrameMetaClient(), clock.New(), uuid.NewGenerator(), nil),
+	}
+
+	jobID := "update-timeouts-job-id"
+	meta := &libModel.MasterMetaKVData{ID: jobID}
+	mgr.JobFsm.JobDispatched(meta, false)
+
+	mockWorkerHandle := &master.MockHandle{WorkerID: jobID, ExecutorID: "executor-1"}
+	err := mgr.JobFsm.JobOnline(mockWorkerHandle)
+	require.Nil(t, err)
+
+	req := &pb.UpdateJobTimeoutsRequest{
+		JobId:           jobID,
+		WorkerTimeoutMs: 30000,
+	}
+	resp := mgr.UpdateJobTimeouts(ctx, req)
+	require.Nil(t, resp.Err)
+	require.Equal(t, 1, mockWorkerHandle.SendMessageCount())
+
+	req.WorkerTimeoutMs = -1
+	resp = mgr.UpdateJobTimeouts(ctx, req)
+	require.NotNil(t, resp.Err)
+	require.Equal(t, 1, mockWorkerHandle.SendMessageCount())
+
+	req.WorkerTimeoutMs = 30000
+	req.JobId = jobID + "-unknown"
+	resp = mgr.UpdateJobTimeouts(ctx, req)
+	require.NotNil(t, resp.Err)
+	require.Equal(t, pb.ErrorCode_UnKnownJob, resp.Err.Code)
+}
+
+func TestJobManagerSetJobLogLevel(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockMaster := lib.NewMockMasterImpl("", "set-log-level-test")
+	mockMaster.On("InitImpl", mock.Anything).Return(nil)
+	mgr := &JobManagerImplV2{
+		BaseMaster:      mockMaster.DefaultBaseMaster,
+		JobFsm:          NewJobFsm(),
+		clocker:         clock.New(),
+		frameMetaClient: mockMaster.GetFrameMetaClient(),
+		jobScheduler:    newJobScheduler(mockMaster.GetFrameMetaClient(), clock.New(), uuid.NewGenerator(), nil),
+	}
+
+	jobID := "set-log-level-job-id"
+	meta := &libModel.MasterMetaKVData{ID: jobID}
+	mgr.JobFsm.JobDispatched(meta, false)
+
+	mockWorkerHandle := &master.MockHandle{WorkerID: jobID, ExecutorID: "executor-1"}
+	err := mgr.JobFsm.JobOnline(mockWorkerHandle)
+	require.Nil(t, err)
+
+	req := &pb.SetJobLogLevelRequest{JobId: jobID, Level: "debug"}
+	resp := mgr.SetJobLogLevel(ctx, req)
+	require.Nil(t, resp.Err)
+	require.Equal(t, 1, mockWorkerHandle.SendMessageCount())
+	level, ok := logutil.JobLevel(jobID)
+	require.True(t, ok)
+	require.Equal(t, zapcore.DebugLevel, level)
+
+	req.Level = "verbose"
+	resp = mgr.SetJobLogLevel(ctx, req)
+	require.NotNil(t, resp.Err)
+	require.Equal(t, 1, mockWorkerHandle.SendMessageCount())
+
+	req.Level = ""
+	resp = mgr.SetJobLogLevel(ctx, req)
+	require.Nil(t, resp.Err)
+	require.Equal(t, 2, mockWorkerHandle.SendMessageCount())
+	_, ok = logutil.JobLevel(jobID)
+	require.False(t, ok)
+
+	req.JobId = jobID + "-unknown"
+	resp = mgr.SetJobLogLevel(ctx, req)
+	require.NotNil(t, resp.Err)
+	require.Equal(t, pb.ErrorCode_UnKnownJob, resp.Err.Code)
+}
+
+func TestJobManagerOperateJobTasks(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockMaster := lib.NewMockMasterImpl("", "operate-job-tasks-test")
+	mockMaster.On("InitImpl", mock.Anything).Return(nil)
+	mgr := &JobManagerImplV2{
+		BaseMaster:      mockMaster.DefaultBaseMaster,
+		JobFsm:          NewJobFsm(),
+		clocker:         clock.New(),
+		frameMetaClient: mockMaster.GetFrameMetaClient(),
+		jobScheduler:    newJobScheduler(mockMaster.GetFrameMetaClient(), clock.New(), uuid.NewGenerator(), nil),
+	}
+
+	jobID := "operate-job-tasks-job-id"
+	meta := &libModel.MasterMetaKVData{ID: jobID}
+	mgr.JobFsm.JobDispatched(meta, false)
+
+	mockWorkerHandle := &master.MockHandle{WorkerID: jobID, ExecutorID: "executor-1"}
+	err := mgr.JobFsm.JobOnline(mockWorkerHandle)
+	require.Nil(t, err)
+
+	req := &pb.OperateJobTasksRequest{JobId: jobID, Op: pb.JobTaskOp_TaskOpStop, Tasks: []string{"task-1"}}
+	resp := mgr.OperateJobTasks(ctx, req)
+	require.Nil(t, resp.Err)
+	require.Equal(t, 1, mockWorkerHandle.SendMessageCount())
+
+	req.Op = pb.JobTaskOp(100)
+	resp = mgr.OperateJobTasks(ctx, req)
+	require.NotNil(t, resp.Err)
+	require.Equal(t, 1, mockWorkerHandle.SendMessageCount())
+
+	req.Op = pb.JobTaskOp_TaskOpResume
+	req.JobId = jobID + "-unknown"
+	resp = mgr.OperateJobTasks(ctx, req)
+	require.NotNil(t, resp.Err)
+	require.Equal(t, pb.ErrorCode_UnKnownJob, resp.Err.Code)
+}
+
+func TestJobManagerUpdateJobSource(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockMaster := lib.NewMockMasterImpl("", "update-job-source-test")
+	mockMaster.On("InitImpl", mock.Anything).Return(nil)
+	mgr := &JobManagerImplV2{
+		BaseMaster:      mockMaster.DefaultBaseMaster,
+		JobFsm:          NewJobFsm(),
+		clocker:         clock.New(),
+		frameMetaClient: mockMaster.GetFrameMetaClient(),
+		jobScheduler:    newJobScheduler(mockMaster.GetFrameMetaClient(), clock.New(), uuid.NewGenerator(), nil),
+	}
+
+	jobID := "update-job-source-job-id"
+	meta := &libModel.MasterMetaKVData{ID: jobID}
+	mgr.JobFsm.JobDispatched(meta, false)
+
+	mockWorkerHandle := &master.MockHandle{WorkerID: jobID, ExecutorID: "executor-1"}
+	err := mgr.JobFsm.JobOnline(mockWorkerHandle)
+	require.Nil(t, err)
+
+	req := &pb.UpdateJobSourceRequest{JobId: jobID, SourceId: "source-1", Config: []byte("source-id: source-1")}
+	resp := mgr.UpdateJobSource(ctx, req)
+	require.Nil(t, resp.Err)
+	require.Equal(t, 1, mockWorkerHandle.SendMessageCount())
+
+	req.Config = nil
+	resp = mgr.UpdateJobSource(ctx, req)
+	require.NotNil(t, resp.Err)
+	require.Equal(t, 1, mockWorkerHandle.SendMessageCount())
+
+	req.Config = []byte("source-id: source-1")
+	req.JobId = jobID + "-unknown"
+	resp = mgr.UpdateJobSource(ctx, req)
+	require.NotNil(t, resp.Err)
+	require.Equal(t, pb.ErrorCode_UnKnownJob, resp.Err.Code)
+}
+
 func TestJobManagerCancelJob(t *testing.T) {
 	t.Parallel()
 
@@ -153,6 +431,8 @@ func TestJobManagerCancelJob(t *testing.T) {
 		clocker:          clock.New(),
 		frameMetaClient:  mockMaster.GetFrameMetaClient(),
 		masterMetaClient: metadata.NewMasterMetadataClient(metadata.JobManagerUUID, mockMaster.GetFrameMetaClient()),
+		jobDeleter:       newJobDeleter(mockMaster.GetFrameMetaClient(), mockkv.NewMetaMock()),
+		jobScheduler:     newJobScheduler(mockMaster.GetFrameMetaClient(), clock.New(), uuid.NewGenerator(), nil),
 	}
 
 	err := mgr.frameMetaClient.UpsertJob(ctx, &libModel.MasterMetaKVData{
@@ -212,6 +492,7 @@ func TestJobManagerQueryJob(t *testing.T) {
 		uuidGen:          uuid.NewGenerator(),
 		masterMetaClient: metadata.NewMasterMetadataClient(metadata.JobManagerUUID, mockMaster.GetFrameMetaClient()),
 		frameMetaClient:  mockMaster.GetFrameMetaClient(),
+		jobScheduler:     newJobScheduler(mockMaster.GetFrameMetaClient(), clock.New(), uuid.NewGenerator(), nil),
 	}
 
 	statuses, err := mgr.GetJobStatuses(ctx)
@@ -231,6 +512,141 @@ func TestJobManagerQueryJob(t *testing.T) {
 	}
 }
 
+func TestRedactJobConfig(t *testing.T) {
+	t.Parallel()
+
+	content, err := os.ReadFile("../jobmaster/dm/config/job_template.yaml")
+	require.NoError(t, err)
+	dmConfig := []byte(strings.ReplaceAll(string(content), "'******'", "'secret-password'"))
+	redacted := redactJobConfig(lib.DMJobMaster, dmConfig)
+	require.NotContains(t, string(redacted), "secret-password")
+	require.Contains(t, string(redacted), redact.Mask)
+
+	cvsConfig := []byte(`{"srcHost":"127.0.0.1:1234","srcDir":"data","dstHost":"127.0.0.1:1235","dstDir":"data1","fileNum":10}`)
+	require.Equal(t, cvsConfig, redactJobConfig(lib.CvsJobMaster, cvsConfig))
+	fakeConfig := []byte(`{"job-name":"fake"}`)
+	require.Equal(t, fakeConfig, redactJobConfig(lib.FakeJobMaster, fakeConfig))
+
+	// a config that can't be decoded is masked as a whole
+	require.Equal(t, []byte(redact.Mask), redactJobConfig(lib.DMJobMaster, []byte("password: [")))
+	require.Equal(t, []byte(redact.Mask), redactJobConfig(0, []byte("password: secret")))
+}
+
+func TestJobManagerQueryJobs(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockMaster := lib.NewMockMasterImpl("", "job-manager-query-jobs-test")
+	metas := []*libModel.MasterMetaKVData{
+		{
+			ProjectID:  "project-1",
+			ID:         "job-1",
+			Tp:         lib.FakeJobMaster,
+			StatusCode: libModel.MasterStatusFinished,
+			Labels:     libModel.JobLabels{"team": "payment", "env": "prod"},
+		},
+		{
+			ProjectID:  "project-1",
+			ID:         "job-2",
+			Tp:         lib.FakeJobMaster,
+			StatusCode: libModel.MasterStatusInit,
+			Labels:     libModel.JobLabels{"team": "payment", "env": "test"},
+		},
+		{
+			ProjectID:  "project-2",
+			ID:         "job-3",
+			Tp:         lib.CvsJobMaster,
+			StatusCode: libModel.MasterStatusStopped,
+			Labels:     libModel.JobLabels{"team": "search"},
+		},
+	}
+	for _, meta := range metas {
+		cli := metadata.NewMasterMetadataClient(meta.ID, mockMaster.GetFrameMetaClient())
+		require.NoError(t, cli.Store(ctx, meta))
+	}
+
+	mgr := &JobManagerImplV2{
+		BaseMaster:      mockMaster.DefaultBaseMaster,
+		JobFsm:          NewJobFsm(),
+		uuidGen:         uuid.NewGenerator(),
+		frameMetaClient: mockMaster.GetFrameMetaClient(),
+	}
+	mgr.JobFsm.JobDispatched(metas[1], false /*addFromFailover*/)
+
+	testCases := []struct {
+		projectID   string
+		selector    string
+		statusCodes []int32
+		types       []int64
+		expected    map[string]pb.QueryJobResponse_JobStatus
+	}{
+		{
+			expected: map[string]pb.QueryJobResponse_JobStatus{
+				"job-1": pb.QueryJobResponse_finished,
+				"job-2": pb.QueryJobResponse_dispatched,
+				"job-3": pb.QueryJobResponse_stopped,
+			},
+		},
+		{
+			projectID: "project-1",
+			expected: map[string]pb.QueryJobResponse_JobStatus{
+				"job-1": pb.QueryJobResponse_finished,
+				"job-2": pb.QueryJobResponse_dispatched,
+			},
+		},
+		{
+			selector: "team=payment,env!=prod",
+			expected: map[string]pb.QueryJobResponse_JobStatus{
+				"job-2": pb.QueryJobResponse_dispatched,
+			},
+		},
+		{
+			projectID: "project-1",
+			selector:  "!env",
+			expected:  map[string]pb.QueryJobResponse_JobStatus{},
+		},
+		{
+			statusCodes: []int32{int32(libModel.MasterStatusFinished), int32(libModel.MasterStatusStopped)},
+			expected: map[string]pb.QueryJobResponse_JobStatus{
+				"job-1": pb.QueryJobResponse_finished,
+				"job-3": pb.QueryJobResponse_stopped,
+			},
+		},
+		{
+			selector: "team",
+			types:    []int64{int64(lib.FakeJobMaster)},
+			expected: map[string]pb.QueryJobResponse_JobStatus{
+				"job-1": pb.QueryJobResponse_finished,
+				"job-2": pb.QueryJobResponse_dispatched,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		resp := mgr.QueryJobs(ctx, &pb.QueryJobsRequest{
+			ProjectId:     tc.projectID,
+			LabelSelector: tc.selector,
+			StatusCodes:   tc.statusCodes,
+			Types:         tc.types,
+		})
+		require.Nil(t, resp.Err)
+		actual := make(map[string]pb.QueryJobResponse_JobStatus)
+		for _, job := range resp.Jobs {
+			actual[job.JobId] = job.Status
+		}
+		require.Equal(t, tc.expected, actual, "selector %s", tc.selector)
+	}
+
+	resp := mgr.QueryJobs(ctx, &pb.QueryJobsRequest{ProjectId: "project-2", LabelSelector: "team=search"})
+	require.Nil(t, resp.Err)
+	require.Len(t, resp.Jobs, 1)
+	require.Equal(t, map[string]string{"team": "search"}, resp.Jobs[0].Labels)
+
+	resp = mgr.QueryJobs(ctx, &pb.QueryJobsRequest{LabelSelector: "-team=payment"})
+	require.Contains(t, resp.Err.GetMessage(), "ErrInvalidLabelSelector")
+}
+
 func TestJobManagerOnlineJob(t *testing.T) {
 	t.Parallel()
 
@@ -248,6 +664,7 @@ func TestJobManagerOnlineJob(t *testing.T) {
 		JobFsm:          NewJobFsm(),
 		uuidGen:         uuid.NewGenerator(),
 		frameMetaClient: mockMaster.GetFrameMetaClient(),
+		jobScheduler:    newJobScheduler(mockMaster.GetFrameMetaClient(), clock.New(), uuid.NewGenerator(), nil),
 	}
 	// set master impl to JobManagerImplV2
 	mockMaster.Impl = mgr
@@ -301,6 +718,8 @@ func TestJobManagerRecover(t *testing.T) {
 		uuidGen:          uuid.NewGenerator(),
 		masterMetaClient: metadata.NewMasterMetadataClient(metadata.JobManagerUUID, mockMaster.GetFrameMetaClient()),
 		frameMetaClient:  mockMaster.GetFrameMetaClient(),
+		jobDeleter:       newJobDeleter(mockMaster.GetFrameMetaClient(), mockkv.NewMetaMock()),
+		jobScheduler:     newJobScheduler(mockMaster.GetFrameMetaClient(), clock.New(), uuid.NewGenerator(), nil),
 	}
 	err := mgr.OnMasterRecovered(ctx)
 	require.Nil(t, err)
@@ -325,6 +744,7 @@ func TestJobManagerTickExceedQuota(t *testing.T) {
 		JobFsm:          NewJobFsm(),
 		uuidGen:         uuid.NewGenerator(),
 		frameMetaClient: mockMaster.GetFrameMetaClient(),
+		jobScheduler:    newJobScheduler(mockMaster.GetFrameMetaClient(), clock.New(), uuid.NewGenerator(), nil),
 	}
 	mockMaster.Impl = mgr
 	err := mockMaster.Init(ctx)
@@ -0,0 +1,415 @@
+package servermaster
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	"github.com/hanfei1991/microcosm/pb"
+	derrors "github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+	"github.com/hanfei1991/microcosm/pkg/orm/migration"
+)
+
+const (
+	// defaultMetaFenceTimeout is how long the leader waits for the executors
+	// to fence their writes before switching, and to reconnect after it.
+	defaultMetaFenceTimeout = 10 * time.Second
+	metaAckCheckInterval    = 100 * time.Millisecond
+)
+
+type executorLister interface {
+	ListExecutors() []string
+}
+
+// metaMigration drives the online migration of the framework metastore of
+// the leader. The state of the migration is persisted, so that the next
+// leader resumes it, and the executors follow it. To switch, the writes of
+// the executors are fenced first, and the switch is not complete until the
+// executors have reconnected to the target metastore.
+type metaMigration struct {
+	migrator         *migration.Migrator
+	metaStoreManager MetaStoreManager
+	executors        executorLister
+	// store persists the state of the migration, it is set once the
+	// embedded etcd is started.
+	store        migration.StateStore
+	fenceTimeout time.Duration
+
+	// opMu serializes the steps changing the state.
+	opMu sync.Mutex
+	// registerMu is held for reading by the registrations of executors, so
+	// that no executor registers unnoticed while the writes are fenced.
+	registerMu sync.RWMutex
+
+	mu    sync.Mutex
+	state migration.State
+}
+
+func newMetaMigration(
+	migrator *migration.Migrator,
+	metaStoreManager MetaStoreManager,
+	executors executorLister,
+) *metaMigration {
+	return &metaMigration{
+		migrator:         migrator,
+		metaStoreManager: metaStoreManager,
+		executors:        executors,
+		fenceTimeout:     defaultMetaFenceTimeout,
+		state:            migration.State{Phase: migration.PhaseNone},
+	}
+}
+
+func (m *metaMigration) getState() migration.State {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.state
+}
+
+func (m *metaMigration) setState(state migration.State) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.state = state
+}
+
+// saveState persists state, a failed write is checked by reading the state
+// back, since it may have been persisted.
+func (m *metaMigration) saveState(ctx context.Context, state migration.State) error {
+	err := m.store.Save(ctx, state)
+	if err == nil {
+		return nil
+	}
+	saved, loadErr := m.store.Load(ctx)
+	if loadErr == nil && saved.Phase == state.Phase && saved.Generation == state.Generation {
+		return nil
+	}
+	return err
+}
+
+// lockRegister blocks switching while an executor is being registered, it
+// returns false if the writes are being fenced, the executor should retry
+// after the switch.
+func (m *metaMigration) lockRegister() (unlock func(), ok bool) {
+	m.registerMu.RLock()
+	if m.getState().Phase == migration.PhaseSwitching {
+		m.registerMu.RUnlock()
+		return nil, false
+	}
+	return m.registerMu.RUnlock, true
+}
+
+// recover resumes the migration persisted by the previous leader, it is
+// called before the leader serves.
+func (m *metaMigration) recover(ctx context.Context) error {
+	m.opMu.Lock()
+	defer m.opMu.Unlock()
+
+	state, err := m.store.Load(ctx)
+	if err != nil {
+		return err
+	}
+	current := m.getState()
+	if state.Phase == current.Phase && state.Generation == current.Generation {
+		return nil
+	}
+	// the migration run by this server master as a former leader is
+	// replaced by the persisted one
+	if m.migrator.Status().Phase == migration.PhaseDualWrite {
+		if err := m.migrator.Abort(); err != nil {
+			return err
+		}
+	}
+
+	switch state.Phase {
+	case migration.PhaseDualWrite, migration.PhaseSwitching:
+		cli, err := pkgOrm.NewClient(*state.Target, pkgOrm.NewDefaultDBConfig())
+		if err != nil {
+			return err
+		}
+		if err := m.migrator.Start(ctx, cli); err != nil {
+			_ = cli.Close()
+			return err
+		}
+		if state.Phase == migration.PhaseSwitching {
+			// the switch has not been persisted, the executors resume
+			// their writes to the source metastore
+			state.Phase = migration.PhaseDualWrite
+			state.Generation++
+			if err := m.saveState(ctx, state); err != nil {
+				_ = m.migrator.Abort()
+				return err
+			}
+		}
+		m.verifyInBackground()
+	case migration.PhaseSwitched:
+		if err := m.reconnect(ctx, state); err != nil {
+			return err
+		}
+	}
+	m.setState(state)
+	log.L().Info("metastore migration recovered", zap.String("phase", string(state.Phase)))
+	return nil
+}
+
+// reconnect serves with the target metastore switched to by a former
+// leader, unless the server master has been configured with it.
+func (m *metaMigration) reconnect(ctx context.Context, state migration.State) error {
+	registered := m.metaStoreManager.GetMetaStore(metaclient.FrameMetaID)
+	if reflect.DeepEqual(registered, state.Target) {
+		return nil
+	}
+	cli, err := pkgOrm.NewClient(*state.Target, pkgOrm.NewDefaultDBConfig())
+	if err != nil {
+		return err
+	}
+	if err := m.migrator.Reconnect(cli); err != nil {
+		_ = cli.Close()
+		return err
+	}
+	if err := m.registerTarget(state.Target); err != nil {
+		return err
+	}
+	if reflect.DeepEqual(registered, state.Source) {
+		m.fenceRetired(ctx)
+	}
+	return nil
+}
+
+// registerTarget makes the executors started from now on connect to the
+// target metastore.
+func (m *metaMigration) registerTarget(target *metaclient.StoreConfigParams) error {
+	m.metaStoreManager.UnRegister(metaclient.FrameMetaID)
+	return m.metaStoreManager.Register(metaclient.FrameMetaID, target)
+}
+
+// fenceRetired fences the source metastore, so that the stale leaders
+// can't write to it. It is retried by the next leader if it fails.
+func (m *metaMigration) fenceRetired(ctx context.Context) {
+	if err := m.migrator.FenceRetired(ctx); err != nil {
+		log.L().Warn("failed to fence the source metastore", zap.Error(err))
+	}
+}
+
+func (m *metaMigration) verifyInBackground() {
+	// the existing records are copied by the first verification
+	go func() {
+		if _, err := m.migrator.Verify(context.Background()); err != nil {
+			log.L().Warn("failed to copy the records to the target metastore", zap.Error(err))
+		}
+	}()
+}
+
+func (m *metaMigration) start(ctx context.Context, target string) error {
+	conf := &metaclient.StoreConfigParams{}
+	if err := json.Unmarshal([]byte(target), conf); err != nil {
+		return derrors.ErrMetaParamsInvalid.GenWithStackByArgs("invalid target metastore config: " + err.Error())
+	}
+	conf.StoreID = metaclient.FrameMetaID
+
+	state := m.getState()
+	if state.Phase == migration.PhaseDualWrite || state.Phase == migration.PhaseSwitching {
+		return derrors.ErrMetaMigrationPhase.GenWithStackByArgs(state.Phase, "start")
+	}
+	cli, err := pkgOrm.NewClient(*conf, pkgOrm.NewDefaultDBConfig())
+	if err != nil {
+		return err
+	}
+	if err := m.migrator.Start(ctx, cli); err != nil {
+		cli.Close()
+		return err
+	}
+
+	state = migration.State{
+		Phase:      migration.PhaseDualWrite,
+		Generation: state.Generation + 1,
+		Source:     m.metaStoreManager.GetMetaStore(metaclient.FrameMetaID),
+		Target:     conf,
+	}
+	if err := m.store.ClearAcks(ctx); err != nil {
+		_ = m.migrator.Abort()
+		return err
+	}
+	if err := m.saveState(ctx, state); err != nil {
+		_ = m.migrator.Abort()
+		return err
+	}
+	m.setState(state)
+	m.verifyInBackground()
+	return nil
+}
+
+func (m *metaMigration) abort(ctx context.Context) error {
+	state := m.getState()
+	if state.Phase != migration.PhaseDualWrite {
+		return derrors.ErrMetaMigrationPhase.GenWithStackByArgs(state.Phase, "abort")
+	}
+	state.Phase = migration.PhaseNone
+	state.Generation++
+	if err := m.saveState(ctx, state); err != nil {
+		return err
+	}
+	m.setState(state)
+	return m.migrator.Abort()
+}
+
+// switchStore fences the writes of the executors, switches the metastore
+// of the leader, and then waits for the executors to reconnect to the
+// target metastore.
+func (m *metaMigration) switchStore(ctx context.Context) error {
+	state := m.getState()
+	if state.Phase != migration.PhaseDualWrite {
+		return derrors.ErrMetaMigrationPhase.GenWithStackByArgs(state.Phase, "switch")
+	}
+	state.Phase = migration.PhaseSwitching
+	state.Generation++
+	m.registerMu.Lock()
+	err := m.saveState(ctx, state)
+	if err == nil {
+		m.setState(state)
+	}
+	m.registerMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if missing := m.waitAcks(ctx, state); len(missing) > 0 {
+		m.resumeDualWrite(ctx, state)
+		return derrors.ErrMetaMigrationFenceTimeout.GenWithStackByArgs(missing)
+	}
+	switched := state
+	switched.Phase = migration.PhaseSwitched
+	switched.Generation++
+	if err := m.migrator.Switch(ctx, func() error {
+		return m.saveState(ctx, switched)
+	}); err != nil {
+		m.resumeDualWrite(ctx, state)
+		return err
+	}
+	m.setState(switched)
+	if err := m.registerTarget(switched.Target); err != nil {
+		return err
+	}
+	m.fenceRetired(ctx)
+
+	if missing := m.waitAcks(ctx, switched); len(missing) > 0 {
+		return derrors.ErrMetaMigrationLagging.GenWithStackByArgs(missing)
+	}
+	log.L().Info("framework metastore switched, the config of the server masters "+
+		"should be updated before the source metastore is removed",
+		zap.Strings("endpoints", switched.Target.Endpoints))
+	return nil
+}
+
+// resumeDualWrite resumes the writes of the executors after a switch fails.
+func (m *metaMigration) resumeDualWrite(ctx context.Context, state migration.State) {
+	state.Phase = migration.PhaseDualWrite
+	state.Generation++
+	if err := m.saveState(ctx, state); err != nil {
+		// the next leader resumes the writes when it recovers the migration
+		log.L().Warn("failed to resume the writes of the executors", zap.Error(err))
+		return
+	}
+	m.setState(state)
+}
+
+// waitAcks waits for the executors to apply state, it returns the executors
+// that have not applied it in time.
+func (m *metaMigration) waitAcks(ctx context.Context, state migration.State) []string {
+	ctx, cancel := context.WithTimeout(ctx, m.fenceTimeout)
+	defer cancel()
+	ticker := time.NewTicker(metaAckCheckInterval)
+	defer ticker.Stop()
+
+	expected := migration.Ack{Phase: state.Phase, Generation: state.Generation}
+	for {
+		executors := m.executors.ListExecutors()
+		missing := executors
+		acks, err := m.store.Acks(ctx)
+		if err != nil {
+			log.L().Warn("failed to load the acknowledgements of executors", zap.Error(err))
+		} else {
+			missing = nil
+			for _, id := range executors {
+				if acks[id] != expected {
+					missing = append(missing, id)
+				}
+			}
+		}
+		if len(missing) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return missing
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *metaMigration) handle(ctx context.Context, req *pb.MigrateMetaStoreRequest) *pb.MigrateMetaStoreResponse {
+	var err error
+	switch req.GetOp() {
+	case pb.MigrateMetaStoreRequest_Start:
+		m.opMu.Lock()
+		err = m.start(ctx, req.GetTarget())
+		m.opMu.Unlock()
+	case pb.MigrateMetaStoreRequest_Verify:
+		_, err = m.migrator.Verify(ctx)
+	case pb.MigrateMetaStoreRequest_Switch:
+		m.opMu.Lock()
+		err = m.switchStore(ctx)
+		m.opMu.Unlock()
+	case pb.MigrateMetaStoreRequest_Abort:
+		m.opMu.Lock()
+		err = m.abort(ctx)
+		m.opMu.Unlock()
+	}
+	if err != nil {
+		return &pb.MigrateMetaStoreResponse{Err: derrors.ToPBError(err)}
+	}
+
+	state := m.getState()
+	status := m.migrator.Status()
+	resp := &pb.MigrateMetaStoreResponse{
+		Phase:        string(state.Phase),
+		FailedWrites: status.FailedWrites,
+		Verifying:    status.Verifying,
+		Mismatches:   make(map[string]int64, len(status.Mismatches)),
+	}
+	if req.GetOp() == pb.MigrateMetaStoreRequest_Start {
+		// the first verification has been started in the background
+		resp.Verifying = true
+	}
+	if state.Target != nil && state.Phase != migration.PhaseNone {
+		resp.TargetEndpoints = state.Target.Endpoints
+	}
+	if !status.LastVerifyTime.IsZero() {
+		resp.LastVerifyTime = status.LastVerifyTime.Unix()
+	}
+	if status.LastVerifyError != nil {
+		resp.LastVerifyError = status.LastVerifyError.Error()
+	}
+	for kind, n := range status.Mismatches {
+		resp.Mismatches[kind] = int64(n)
+	}
+	return resp
+}
+
+// MigrateMetaStore implements pb.MasterServer.MigrateMetaStore
+func (s *Server) MigrateMetaStore(
+	ctx context.Context, req *pb.MigrateMetaStoreRequest,
+) (*pb.MigrateMetaStoreResponse, error) {
+	resp2 := &pb.MigrateMetaStoreResponse{}
+	shouldRet, err := s.masterRPCHook.PreRPC(ctx, req, &resp2)
+	if shouldRet {
+		return resp2, err
+	}
+	return s.metaMigration.handle(ctx, req), nil
+}
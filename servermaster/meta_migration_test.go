@@ -0,0 +1,148 @@
+package servermaster
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	libModel "github.com/hanfei1991/microcosm/lib/model"
+	derrors "github.com/hanfei1991/microcosm/pkg/errors"
+	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+	"github.com/hanfei1991/microcosm/pkg/orm/migration"
+)
+
+type mockExecutorLister struct {
+	mu        sync.Mutex
+	executors []string
+}
+
+func (l *mockExecutorLister) ListExecutors() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.executors...)
+}
+
+func (l *mockExecutorLister) set(executors ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.executors = executors
+}
+
+func newTestMetaMigration(
+	t *testing.T, conf metaclient.StoreConfigParams, executors executorLister, store migration.StateStore,
+) (*metaMigration, MetaStoreManager) {
+	cli, err := pkgOrm.NewClient(conf, pkgOrm.NewDefaultDBConfig())
+	require.NoError(t, err)
+	migrator := migration.NewMigrator(cli)
+	t.Cleanup(func() {
+		_ = migrator.Close()
+	})
+	metaStoreManager := NewMetaStoreManager()
+	require.NoError(t, metaStoreManager.Register(metaclient.FrameMetaID, &conf))
+	m := newMetaMigration(migrator, metaStoreManager, executors)
+	m.store = store
+	m.fenceTimeout = 500 * time.Millisecond
+	return m, metaStoreManager
+}
+
+func TestMetaMigrationSwitch(t *testing.T) {
+	t.Parallel()
+
+	sourceConf, source := pkgOrm.NewSQLiteTestClient(t, "source.db")
+	targetConf, target := pkgOrm.NewSQLiteTestClient(t, "target.db")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store := migration.NewMockStateStore()
+	executors := &mockExecutorLister{}
+	m, metaStoreManager := newTestMetaMigration(t, sourceConf, executors, store)
+
+	executorCli, err := pkgOrm.NewClient(sourceConf, pkgOrm.NewDefaultDBConfig())
+	require.NoError(t, err)
+	executorMigrator := migration.NewMigrator(executorCli)
+	defer executorMigrator.Close()
+	follower := migration.NewFollower(executorMigrator, sourceConf, nil)
+	go func() {
+		_ = follower.Run(ctx, store, "executor-1")
+	}()
+
+	err = m.switchStore(ctx)
+	require.True(t, derrors.ErrMetaMigrationPhase.Equal(err))
+	targetJSON, err := json.Marshal(targetConf)
+	require.NoError(t, err)
+	require.NoError(t, m.start(ctx, string(targetJSON)))
+	require.Equal(t, migration.PhaseDualWrite, m.getState().Phase)
+
+	// an executor not following the migration fails the switch, and the
+	// writes are resumed
+	executors.set("executor-1", "executor-2")
+	err = m.switchStore(ctx)
+	require.True(t, derrors.ErrMetaMigrationFenceTimeout.Equal(err))
+	state, err := store.Load(ctx)
+	require.NoError(t, err)
+	require.Equal(t, migration.PhaseDualWrite, state.Phase)
+	require.Equal(t, state, m.getState())
+	require.Equal(t, &sourceConf, metaStoreManager.GetMetaStore(metaclient.FrameMetaID))
+
+	executors.set("executor-1")
+	require.NoError(t, m.switchStore(ctx))
+	state, err = store.Load(ctx)
+	require.NoError(t, err)
+	require.Equal(t, migration.PhaseSwitched, state.Phase)
+	require.Equal(t, &targetConf, metaStoreManager.GetMetaStore(metaclient.FrameMetaID))
+	require.Equal(t, targetConf, follower.Conf())
+
+	// the executor writes to the target metastore, and the retired one is
+	// fenced
+	job := &libModel.MasterMetaKVData{ProjectID: "project-1", ID: "job-1"}
+	require.NoError(t, executorMigrator.Client().UpsertJob(ctx, job))
+	_, err = target.GetJobByID(ctx, "job-1")
+	require.NoError(t, err)
+	err = source.WithFencingToken(1).UpsertJob(ctx, job)
+	require.True(t, derrors.ErrMetaLeaderFenced.Equal(err))
+
+	// the next leader, configured with the source metastore, serves with
+	// the target metastore
+	next, nextMetaStoreManager := newTestMetaMigration(t, sourceConf, executors, store)
+	require.NoError(t, next.recover(ctx))
+	require.Equal(t, state, next.getState())
+	require.Equal(t, &targetConf, nextMetaStoreManager.GetMetaStore(metaclient.FrameMetaID))
+	_, err = next.migrator.Client().GetJobByID(ctx, "job-1")
+	require.NoError(t, err)
+}
+
+func TestMetaMigrationRecoverSwitching(t *testing.T) {
+	t.Parallel()
+
+	sourceConf, _ := pkgOrm.NewSQLiteTestClient(t, "source.db")
+	targetConf, _ := pkgOrm.NewSQLiteTestClient(t, "target.db")
+	ctx := context.Background()
+
+	// the previous leader failed over while switching
+	store := migration.NewMockStateStore()
+	require.NoError(t, store.Save(ctx, migration.State{
+		Phase:      migration.PhaseSwitching,
+		Generation: 2,
+		Source:     &sourceConf,
+		Target:     &targetConf,
+	}))
+
+	m, metaStoreManager := newTestMetaMigration(t, sourceConf, &mockExecutorLister{}, store)
+	require.NoError(t, m.recover(ctx))
+	state, err := store.Load(ctx)
+	require.NoError(t, err)
+	require.Equal(t, migration.State{
+		Phase:      migration.PhaseDualWrite,
+		Generation: 3,
+		Source:     &sourceConf,
+		Target:     &targetConf,
+	}, state)
+	require.Equal(t, state, m.getState())
+	require.Equal(t, migration.PhaseDualWrite, m.migrator.Status().Phase)
+	require.Equal(t, &sourceConf, metaStoreManager.GetMetaStore(metaclient.FrameMetaID))
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/hanfei1991/microcosm/pkg/errors"
 	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
 	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+	"github.com/hanfei1991/microcosm/pkg/redact"
 	"github.com/pingcap/tiflow/dm/pkg/log"
 	"go.uber.org/zap"
 )
@@ -35,7 +36,7 @@ func NewMetaStoreManager() MetaStoreManager {
 // Register implements MetaStoreManager.Register
 func (m *metaStoreManagerImpl) Register(id string, store *metaclient.StoreConfigParams) error {
 	if _, exists := m.id2Store.LoadOrStore(id, store); exists {
-		log.L().Error("register metastore fail", zap.Any("config", store), zap.String("err", "Duplicate storeID"))
+		log.L().Error("register metastore fail", redact.Any("config", store), zap.String("err", "Duplicate storeID"))
 		return errors.ErrMetaStoreIDDuplicate.GenWithStackByArgs()
 	}
 	return nil
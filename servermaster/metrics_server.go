@@ -2,6 +2,15 @@ package servermaster
 
 import (
 	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/hanfei1991/microcosm/lib"
+	"github.com/hanfei1991/microcosm/lib/master"
+	"github.com/hanfei1991/microcosm/pkg/eventbus"
+	"github.com/hanfei1991/microcosm/pkg/interceptor"
+	"github.com/hanfei1991/microcosm/pkg/lockdiag"
+	"github.com/hanfei1991/microcosm/pkg/notifier"
+	"github.com/hanfei1991/microcosm/pkg/p2p"
+	"github.com/hanfei1991/microcosm/pkg/sink"
 )
 
 var (
@@ -25,4 +34,12 @@ var (
 func initServerMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(serverExecutorNumGauge)
 	registry.MustRegister(serverJobNumGauge)
+	notifier.InitMetrics(registry)
+	eventbus.InitMetrics(registry)
+	p2p.InitMetrics(registry)
+	lib.InitMetrics(registry)
+	master.InitMetrics(registry)
+	lockdiag.InitMetrics(registry)
+	interceptor.InitMetrics(registry)
+	sink.InitMetrics(registry)
 }
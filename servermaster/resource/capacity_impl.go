@@ -31,16 +31,18 @@ func NewCapRescMgr() *CapRescMgr {
 }
 
 // Register implements RescMgr.Register
-func (m *CapRescMgr) Register(id model.ExecutorID, addr string, capacity model.RescUnit) {
+func (m *CapRescMgr) Register(id model.ExecutorID, addr string, capacity model.RescVector, zone string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.executors[id] = &ExecutorResource{
 		ID:       id,
-		Capacity: capacity,
+		Capacity: capacity.Clone(),
 		Addr:     addr,
+		Zone:     zone,
 	}
 	log.L().Info("executor resource is registered",
-		zap.String("executor-id", string(id)), zap.Int("capacity", int(capacity)))
+		zap.String("executor-id", string(id)), zap.Stringer("capacity", capacity),
+		zap.String("zone", zone))
 }
 
 // Unregister implements RescMgr.Unregister
@@ -53,16 +55,32 @@ func (m *CapRescMgr) Unregister(id model.ExecutorID) {
 }
 
 // Update implements RescMgr.Update
-func (m *CapRescMgr) Update(id model.ExecutorID, used, reserved model.RescUnit, status model.ExecutorStatus) error {
+func (m *CapRescMgr) Update(
+	id model.ExecutorID, used, reserved model.RescVector,
+	status model.ExecutorStatus, idleEvictable bool,
+) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	exec, ok := m.executors[id]
 	if !ok {
 		return errors.ErrUnknownExecutorID.GenWithStackByArgs(id)
 	}
-	exec.Used = used
-	exec.Reserved = reserved
+	exec.Used = used.Clone()
+	exec.Reserved = reserved.Clone()
 	exec.Status = status
+	exec.IdleEvictable = idleEvictable
+	return nil
+}
+
+// SetCordoned implements RescMgr.SetCordoned
+func (m *CapRescMgr) SetCordoned(id model.ExecutorID, cordoned bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	exec, ok := m.executors[id]
+	if !ok {
+		return errors.ErrUnknownExecutorID.GenWithStackByArgs(id)
+	}
+	exec.Cordoned = cordoned
 	return nil
 }
 
@@ -79,10 +97,18 @@ func (m *CapRescMgr) CapacitiesForAllExecutors() map[model.ExecutorID]*schedMode
 	// scheduling happens only sporadically, and the number of executors
 	// is limited to <= 100.
 	for executorID, resc := range m.executors {
+		if resc.Status == model.Draining || resc.Cordoned {
+			// A draining executor is shutting down, and a cordoned executor
+			// is excluded by operators, no more tasks should be scheduled
+			// to them.
+			continue
+		}
 		resourceStatus := &schedModel.ExecutorResourceStatus{
-			Capacity: resc.Capacity,
-			Reserved: resc.Reserved,
-			Used:     resc.Used,
+			Capacity:      resc.Capacity.Clone(),
+			Reserved:      resc.Reserved.Clone(),
+			Used:          resc.Used.Clone(),
+			IdleEvictable: resc.IdleEvictable,
+			Zone:          resc.Zone,
 		}
 		ret[executorID] = resourceStatus
 	}
@@ -100,8 +126,11 @@ func (m *CapRescMgr) CapacityForExecutor(executor model.ExecutorID) (*schedModel
 	}
 
 	return &schedModel.ExecutorResourceStatus{
-		Capacity: resc.Capacity,
-		Reserved: resc.Reserved,
-		Used:     resc.Used,
+		Capacity:      resc.Capacity.Clone(),
+		Reserved:      resc.Reserved.Clone(),
+		Used:          resc.Used.Clone(),
+		IdleEvictable: resc.IdleEvictable,
+		Zone:          resc.Zone,
+		Cordoned:      resc.Cordoned,
 	}, true
 }
@@ -11,14 +11,22 @@ type RescMgr interface {
 	// to provide capacity info to scheduler.Scheduler.
 	scheduler.CapacityProvider
 
-	// Register registers new executor, it is called when an executor joins
-	Register(id model.ExecutorID, addr string, capacity model.RescUnit)
+	// Register registers new executor, it is called when an executor joins.
+	// zone is the zone the executor runs in, empty means unknown.
+	Register(id model.ExecutorID, addr string, capacity model.RescVector, zone string)
 
 	// Unregister is called when an executor exits
 	Unregister(id model.ExecutorID)
 
 	// Update updates executor resource usage and running status
-	Update(id model.ExecutorID, used, reserved model.RescUnit, status model.ExecutorStatus) error
+	Update(
+		id model.ExecutorID, used, reserved model.RescVector,
+		status model.ExecutorStatus, idleEvictable bool,
+	) error
+
+	// SetCordoned sets whether an executor is cordoned, a cordoned executor
+	// is excluded from scheduling
+	SetCordoned(id model.ExecutorID, cordoned bool) error
 }
 
 // ExecutorResource defines the capacity usage of an executor
@@ -27,12 +35,19 @@ type ExecutorResource struct {
 	Status model.ExecutorStatus
 
 	// Capacity of the resource in this executor.
-	Capacity model.RescUnit
+	Capacity model.RescVector
 	// Reserved resource in this node, meaning the max resource possible to use.
 	// It's supposed to be the total cost of running tasks in this executor.
-	Reserved model.RescUnit
+	Reserved model.RescVector
 	// Actually used resource in this node. It's supposed to be less than the reserved resource.
 	// But if the estimated reserved is not accurate, `Used` might be larger than `Reserved`.
-	Used model.RescUnit
+	Used model.RescVector
 	Addr string
+	// IdleEvictable means the executor has run no task for a while and may be
+	// removed by autoscalers.
+	IdleEvictable bool
+	// Zone is the zone the executor runs in, empty means unknown.
+	Zone string
+	// Cordoned means the executor is cordoned by operators.
+	Cordoned bool
 }
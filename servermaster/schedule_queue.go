@@ -0,0 +1,376 @@
+package servermaster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	"github.com/hanfei1991/microcosm/pb"
+	"github.com/hanfei1991/microcosm/pkg/clock"
+	derrors "github.com/hanfei1991/microcosm/pkg/errors"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+	ormModel "github.com/hanfei1991/microcosm/pkg/orm/model"
+)
+
+const (
+	defaultScheduleQueueMaxWait       = "10m"
+	defaultScheduleQueueRetryInterval = "1s"
+	defaultMaxQueuedRequests          = 1024
+
+	// deleteQueuedTaskTimeout is the timeout of deleting a queued request
+	// whose waiters have gone away.
+	deleteQueuedTaskTimeout = 5 * time.Second
+)
+
+// ScheduleQueueConfig is the configuration of the schedule queue, where the
+// queued schedule requests wait for the resources of the cluster.
+type ScheduleQueueConfig struct {
+	// MaxWait is how long a queued request waits at most, a longer wait
+	// timeout of the request is capped by it.
+	MaxWaitStr string `toml:"max-wait" json:"max-wait"`
+	// RetryInterval is the interval of retrying the queued requests, they
+	// are also retried when executors join or workers exit.
+	RetryIntervalStr string `toml:"retry-interval" json:"retry-interval"`
+	// MaxQueuedRequests is the max number of the queued requests, other
+	// requests are rejected at once.
+	MaxQueuedRequests int `toml:"max-queued-requests" json:"max-queued-requests"`
+
+	MaxWait       time.Duration `toml:"-" json:"-"`
+	RetryInterval time.Duration `toml:"-" json:"-"`
+}
+
+// Adjust validates the config and fills the default values
+func (c *ScheduleQueueConfig) Adjust() (err error) {
+	if c.MaxQueuedRequests < 0 {
+		return derrors.ErrScheduleQueueInvalidConfig.GenWithStackByArgs("max-queued-requests must not be negative")
+	}
+	if c.MaxQueuedRequests == 0 {
+		c.MaxQueuedRequests = defaultMaxQueuedRequests
+	}
+	if c.MaxWaitStr == "" {
+		c.MaxWaitStr = defaultScheduleQueueMaxWait
+	}
+	c.MaxWait, err = time.ParseDuration(c.MaxWaitStr)
+	if err != nil {
+		return derrors.ErrScheduleQueueInvalidConfig.GenWithStackByArgs(fmt.Sprintf("max-wait: %v", err))
+	}
+	if c.MaxWait <= 0 {
+		return derrors.ErrScheduleQueueInvalidConfig.GenWithStackByArgs("max-wait must be positive")
+	}
+	if c.RetryIntervalStr == "" {
+		c.RetryIntervalStr = defaultScheduleQueueRetryInterval
+	}
+	c.RetryInterval, err = time.ParseDuration(c.RetryIntervalStr)
+	if err != nil {
+		return derrors.ErrScheduleQueueInvalidConfig.GenWithStackByArgs(fmt.Sprintf("retry-interval: %v", err))
+	}
+	if c.RetryInterval <= 0 {
+		return derrors.ErrScheduleQueueInvalidConfig.GenWithStackByArgs("retry-interval must be positive")
+	}
+	return nil
+}
+
+type queueResult struct {
+	resp *pb.ScheduleTaskResponse
+	err  error
+}
+
+type queuedRequest struct {
+	req        *pb.ScheduleTaskRequest
+	deadline   time.Time
+	enqueuedAt time.Time
+	// waiters receive the result of the request. A request is placed only
+	// if someone waits for it, since the decision is useless otherwise.
+	waiters map[chan queueResult]struct{}
+	// reloaded is set if the request is queued by a previous leader, it is
+	// kept without waiters until its job master sends it again.
+	reloaded bool
+}
+
+// scheduleQueue holds the queued schedule requests that can't be placed for
+// the lack of resources, and retries them in the order of their priorities
+// until they are placed or their deadlines pass. The requests are persisted,
+// so that they keep their places in the queue after the failover of server
+// master, when the job masters send them again with the same task IDs.
+type scheduleQueue struct {
+	cfg             *ScheduleQueueConfig
+	frameMetaClient pkgOrm.Client
+	schedule        scheduleFunc
+	clocker         clock.Clock
+
+	mu       sync.Mutex
+	requests map[string]*queuedRequest
+
+	notifyCh chan struct{}
+}
+
+func newScheduleQueue(
+	cfg *ScheduleQueueConfig,
+	frameMetaClient pkgOrm.Client,
+	schedule scheduleFunc,
+	clocker clock.Clock,
+) *scheduleQueue {
+	return &scheduleQueue{
+		cfg:             cfg,
+		frameMetaClient: frameMetaClient,
+		schedule:        schedule,
+		clocker:         clocker,
+		requests:        make(map[string]*queuedRequest),
+		notifyCh:        make(chan struct{}, 1),
+	}
+}
+
+// Wait queues the request and waits until it is placed, its deadline passes,
+// or ctx is done. If the request is queued already, it keeps its place.
+func (q *scheduleQueue) Wait(ctx context.Context, req *pb.ScheduleTaskRequest) (*pb.ScheduleTaskResponse, error) {
+	if req.GetTaskId() == "" {
+		return nil, derrors.ErrMasterScheduleInvalidRequest.GenWithStackByArgs("task id is not set")
+	}
+	ch := make(chan queueResult, 1)
+	if err := q.enqueue(ctx, req, ch); err != nil {
+		return nil, err
+	}
+	defer q.removeWaiter(req.GetTaskId(), ch)
+	q.notify()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-ch:
+		return result.resp, result.err
+	}
+}
+
+func (q *scheduleQueue) enqueue(ctx context.Context, req *pb.ScheduleTaskRequest, ch chan queueResult) error {
+	taskID := req.GetTaskId()
+	q.mu.Lock()
+	if queued, ok := q.requests[taskID]; ok {
+		queued.waiters[ch] = struct{}{}
+		q.mu.Unlock()
+		return nil
+	}
+	full := len(q.requests) >= q.cfg.MaxQueuedRequests
+	q.mu.Unlock()
+	if full {
+		return derrors.ErrScheduleQueueFull.GenWithStackByArgs(q.cfg.MaxQueuedRequests)
+	}
+
+	wait := q.cfg.MaxWait
+	if timeout := time.Duration(req.GetWaitTimeoutMs()) * time.Millisecond; timeout > 0 && timeout < wait {
+		wait = timeout
+	}
+	now := q.clocker.Now()
+	queued := &queuedRequest{
+		req:        req,
+		deadline:   now.Add(wait),
+		enqueuedAt: now,
+		waiters:    map[chan queueResult]struct{}{ch: {}},
+	}
+	data, err := req.Marshal()
+	if err != nil {
+		return derrors.ErrMasterScheduleInvalidRequest.Wrap(err)
+	}
+	if err := q.frameMetaClient.UpsertQueuedTask(ctx, &ormModel.QueuedTask{
+		TaskID:   taskID,
+		JobID:    req.GetJobId(),
+		Priority: req.GetPriority(),
+		Deadline: queued.deadline,
+		Request:  data,
+	}); err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if existing, ok := q.requests[taskID]; ok {
+		// queued by a concurrent request of the same task
+		existing.waiters[ch] = struct{}{}
+		return nil
+	}
+	q.requests[taskID] = queued
+	log.L().Info("schedule request is queued",
+		zap.String("task-id", taskID),
+		zap.String("job-id", req.GetJobId()),
+		zap.Int32("priority", req.GetPriority()),
+		zap.Time("deadline", queued.deadline))
+	return nil
+}
+
+// removeWaiter removes a waiter of the request. The request is removed if
+// it has no waiter left, since its job master has gone away, unless it is
+// waiting for its job master to send it again after failover.
+func (q *scheduleQueue) removeWaiter(taskID string, ch chan queueResult) {
+	q.mu.Lock()
+	queued, ok := q.requests[taskID]
+	if !ok {
+		q.mu.Unlock()
+		return
+	}
+	delete(queued.waiters, ch)
+	if len(queued.waiters) > 0 || queued.reloaded {
+		q.mu.Unlock()
+		return
+	}
+	delete(q.requests, taskID)
+	q.mu.Unlock()
+
+	log.L().Info("queued schedule request is abandoned", zap.String("task-id", taskID))
+	ctx, cancel := context.WithTimeout(context.Background(), deleteQueuedTaskTimeout)
+	defer cancel()
+	if _, err := q.frameMetaClient.DeleteQueuedTask(ctx, taskID); err != nil {
+		// the request is removed again after failover when it expires
+		log.L().Warn("failed to delete queued task", zap.String("task-id", taskID), zap.Error(err))
+	}
+}
+
+// notify wakes up the queue to retry the queued requests, it is called
+// when executors join or workers exit.
+func (q *scheduleQueue) notify() {
+	select {
+	case q.notifyCh <- struct{}{}:
+	default:
+	}
+}
+
+// Len returns the number of the queued requests.
+func (q *scheduleQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.requests)
+}
+
+// load restores the requests queued by the previous leaders, they are placed
+// after the job masters send them again.
+func (q *scheduleQueue) load(ctx context.Context) error {
+	tasks, err := q.frameMetaClient.QueryQueuedTasks(ctx)
+	if err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, task := range tasks {
+		if _, ok := q.requests[task.TaskID]; ok {
+			continue
+		}
+		req := &pb.ScheduleTaskRequest{}
+		if err := req.Unmarshal(task.Request); err != nil {
+			log.L().Warn("drop invalid queued task", zap.String("task-id", task.TaskID), zap.Error(err))
+			continue
+		}
+		q.requests[task.TaskID] = &queuedRequest{
+			req:        req,
+			deadline:   task.Deadline,
+			enqueuedAt: task.CreatedAt,
+			waiters:    make(map[chan queueResult]struct{}),
+			reloaded:   true,
+		}
+	}
+	log.L().Info("schedule queue loaded", zap.Int("count", len(q.requests)))
+	return nil
+}
+
+// Run retries the queued requests until ctx is canceled, the waiters are
+// told to send their requests to the new leader then.
+func (q *scheduleQueue) Run(ctx context.Context) {
+	defer q.reset()
+
+	ticker := q.clocker.Ticker(q.cfg.RetryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-q.notifyCh:
+		}
+		q.retry(ctx)
+	}
+}
+
+// retry places the queued requests in the order of their priorities, and
+// removes the ones whose deadlines have passed.
+func (q *scheduleQueue) retry(ctx context.Context) {
+	now := q.clocker.Now()
+	q.mu.Lock()
+	queued := make([]*queuedRequest, 0, len(q.requests))
+	for _, req := range q.requests {
+		queued = append(queued, req)
+	}
+	q.mu.Unlock()
+	sort.Slice(queued, func(i, j int) bool {
+		if queued[i].req.GetPriority() != queued[j].req.GetPriority() {
+			return queued[i].req.GetPriority() > queued[j].req.GetPriority()
+		}
+		return queued[i].enqueuedAt.Before(queued[j].enqueuedAt)
+	})
+
+	for _, req := range queued {
+		if ctx.Err() != nil {
+			return
+		}
+		taskID := req.req.GetTaskId()
+		if !now.Before(req.deadline) {
+			log.L().Info("queued schedule request expires", zap.String("task-id", taskID))
+			q.finish(ctx, taskID, queueResult{
+				err: derrors.ErrScheduleQueueTimeout.GenWithStackByArgs(taskID),
+			})
+			continue
+		}
+		if !q.hasWaiters(taskID) {
+			continue
+		}
+		resp, err := q.schedule(ctx, req.req)
+		if derrors.ErrClusterResourceNotEnough.Equal(err) || derrors.ErrUnknownExecutorID.Equal(err) {
+			continue
+		}
+		// the requests failed for other reasons, such as resource conflicts,
+		// are not retried
+		q.finish(ctx, taskID, queueResult{resp: resp, err: err})
+	}
+}
+
+func (q *scheduleQueue) hasWaiters(taskID string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	queued, ok := q.requests[taskID]
+	return ok && len(queued.waiters) > 0
+}
+
+// finish removes a request from the queue and delivers the result to its
+// waiters.
+func (q *scheduleQueue) finish(ctx context.Context, taskID string, result queueResult) {
+	if _, err := q.frameMetaClient.DeleteQueuedTask(ctx, taskID); err != nil {
+		// the request is removed again after failover when it expires
+		log.L().Warn("failed to delete queued task", zap.String("task-id", taskID), zap.Error(err))
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	queued, ok := q.requests[taskID]
+	if !ok {
+		return
+	}
+	delete(q.requests, taskID)
+	for ch := range queued.waiters {
+		ch <- result
+	}
+}
+
+// reset drops the requests in memory when the leader steps down, they are
+// loaded by the next leader.
+func (q *scheduleQueue) reset() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for taskID, queued := range q.requests {
+		for ch := range queued.waiters {
+			ch <- queueResult{err: derrors.ErrMasterNotInitialized.GenWithStackByArgs()}
+		}
+		delete(q.requests, taskID)
+	}
+}
@@ -0,0 +1,228 @@
+package servermaster
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hanfei1991/microcosm/pb"
+	"github.com/hanfei1991/microcosm/pkg/clock"
+	"github.com/hanfei1991/microcosm/pkg/errors"
+	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+	ormModel "github.com/hanfei1991/microcosm/pkg/orm/model"
+)
+
+// mockPlacer places at most capacity tasks, in the order they are tried.
+type mockPlacer struct {
+	mu       sync.Mutex
+	capacity int
+	placed   []string
+}
+
+func (p *mockPlacer) place(_ context.Context, req *pb.ScheduleTaskRequest) (*pb.ScheduleTaskResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.placed) >= p.capacity {
+		return nil, errors.ErrClusterResourceNotEnough.GenWithStackByArgs()
+	}
+	p.placed = append(p.placed, req.GetTaskId())
+	return &pb.ScheduleTaskResponse{ExecutorId: "executor-1"}, nil
+}
+
+func (p *mockPlacer) setCapacity(capacity int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.capacity = capacity
+}
+
+func (p *mockPlacer) getPlaced() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.placed...)
+}
+
+func newScheduleQueueForTest(t *testing.T) (*scheduleQueue, pkgOrm.Client, *mockPlacer, *clock.Mock) {
+	cfg := &ScheduleQueueConfig{}
+	require.NoError(t, cfg.Adjust())
+	metaCli, err := pkgOrm.NewMockClient()
+	require.NoError(t, err)
+	placer := &mockPlacer{}
+	clocker := clock.NewMock()
+	clocker.Set(time.Now())
+	return newScheduleQueue(cfg, metaCli, placer.place, clocker), metaCli, placer, clocker
+}
+
+type waitResult struct {
+	resp *pb.ScheduleTaskResponse
+	err  error
+}
+
+func waitAsync(q *scheduleQueue, req *pb.ScheduleTaskRequest) <-chan waitResult {
+	ch := make(chan waitResult, 1)
+	go func() {
+		resp, err := q.Wait(context.Background(), req)
+		ch <- waitResult{resp: resp, err: err}
+	}()
+	return ch
+}
+
+func TestScheduleQueueConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := &ScheduleQueueConfig{}
+	require.NoError(t, cfg.Adjust())
+	require.Equal(t, 10*time.Minute, cfg.MaxWait)
+	require.Equal(t, time.Second, cfg.RetryInterval)
+	require.Equal(t, defaultMaxQueuedRequests, cfg.MaxQueuedRequests)
+
+	for _, cfg := range []*ScheduleQueueConfig{
+		{MaxWaitStr: "-1m"},
+		{RetryIntervalStr: "1"},
+		{MaxQueuedRequests: -1},
+	} {
+		require.True(t, errors.ErrScheduleQueueInvalidConfig.Equal(cfg.Adjust()), "config: %+v", cfg)
+	}
+}
+
+func TestScheduleQueuePriority(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	q, metaCli, placer, clocker := newScheduleQueueForTest(t)
+	low := waitAsync(q, &pb.ScheduleTaskRequest{TaskId: "task-low", Queued: true, Priority: 1})
+	require.Eventually(t, func() bool { return q.hasWaiters("task-low") }, time.Second, 10*time.Millisecond)
+	clocker.Add(time.Second)
+	high := waitAsync(q, &pb.ScheduleTaskRequest{TaskId: "task-high", Queued: true, Priority: 2})
+	require.Eventually(t, func() bool { return q.hasWaiters("task-high") }, time.Second, 10*time.Millisecond)
+
+	// the requests are persisted until they are placed
+	q.retry(ctx)
+	require.Equal(t, 2, q.Len())
+	tasks, err := metaCli.QueryQueuedTasks(ctx)
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+
+	// the request of higher priority is placed first
+	placer.setCapacity(1)
+	q.retry(ctx)
+	result := <-high
+	require.NoError(t, result.err)
+	require.Equal(t, "executor-1", result.resp.GetExecutorId())
+	require.Equal(t, []string{"task-high"}, placer.getPlaced())
+	_, err = metaCli.GetQueuedTaskByID(ctx, "task-high")
+	require.True(t, pkgOrm.IsNotFoundError(err))
+
+	placer.setCapacity(2)
+	q.retry(ctx)
+	result = <-low
+	require.NoError(t, result.err)
+	require.Equal(t, 0, q.Len())
+}
+
+func TestScheduleQueueDeadline(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	q, metaCli, _, clocker := newScheduleQueueForTest(t)
+	ch := waitAsync(q, &pb.ScheduleTaskRequest{TaskId: "task-1", Queued: true, WaitTimeoutMs: 1000})
+	require.Eventually(t, func() bool { return q.hasWaiters("task-1") }, time.Second, 10*time.Millisecond)
+
+	q.retry(ctx)
+	clocker.Add(2 * time.Second)
+	q.retry(ctx)
+	result := <-ch
+	require.True(t, errors.ErrScheduleQueueTimeout.Equal(result.err))
+	tasks, err := metaCli.QueryQueuedTasks(ctx)
+	require.NoError(t, err)
+	require.Empty(t, tasks)
+}
+
+func TestScheduleQueueFailover(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	q, metaCli, placer, clocker := newScheduleQueueForTest(t)
+
+	// the request queued by the previous leader keeps its deadline
+	req := &pb.ScheduleTaskRequest{TaskId: "task-1", Queued: true}
+	data, err := req.Marshal()
+	require.NoError(t, err)
+	deadline := clocker.Now().Add(time.Minute)
+	require.NoError(t, metaCli.UpsertQueuedTask(ctx, &ormModel.QueuedTask{
+		TaskID:   "task-1",
+		Deadline: deadline,
+		Request:  data,
+	}))
+	require.NoError(t, q.load(ctx))
+	require.Equal(t, 1, q.Len())
+
+	// the request is not placed until the job master sends it again
+	placer.setCapacity(1)
+	q.retry(ctx)
+	require.Empty(t, placer.getPlaced())
+
+	ch := waitAsync(q, req)
+	require.Eventually(t, func() bool { return q.hasWaiters("task-1") }, time.Second, 10*time.Millisecond)
+	q.mu.Lock()
+	require.True(t, deadline.Equal(q.requests["task-1"].deadline))
+	q.mu.Unlock()
+	q.retry(ctx)
+	result := <-ch
+	require.NoError(t, result.err)
+
+	// the waiters are told to retry on the new leader
+	ch = waitAsync(q, &pb.ScheduleTaskRequest{TaskId: "task-2", Queued: true})
+	require.Eventually(t, func() bool { return q.hasWaiters("task-2") }, time.Second, 10*time.Millisecond)
+	q.reset()
+	result = <-ch
+	require.True(t, errors.ErrMasterNotInitialized.Equal(result.err))
+	require.Equal(t, 0, q.Len())
+}
+
+func TestScheduleQueueAbandoned(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	q, metaCli, _, _ := newScheduleQueueForTest(t)
+
+	// the request is removed once its job master goes away
+	waitCtx, cancel := context.WithCancel(ctx)
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := q.Wait(waitCtx, &pb.ScheduleTaskRequest{TaskId: "task-1", Queued: true})
+		errCh <- err
+	}()
+	require.Eventually(t, func() bool { return q.hasWaiters("task-1") }, time.Second, 10*time.Millisecond)
+	cancel()
+	require.ErrorIs(t, <-errCh, context.Canceled)
+	require.Equal(t, 0, q.Len())
+	tasks, err := metaCli.QueryQueuedTasks(ctx)
+	require.NoError(t, err)
+	require.Empty(t, tasks)
+
+	// the request reloaded after failover is kept for its job master to
+	// send it again
+	req := &pb.ScheduleTaskRequest{TaskId: "task-2", Queued: true}
+	data, err := req.Marshal()
+	require.NoError(t, err)
+	require.NoError(t, metaCli.UpsertQueuedTask(ctx, &ormModel.QueuedTask{
+		TaskID:   "task-2",
+		Deadline: time.Now().Add(time.Minute),
+		Request:  data,
+	}))
+	require.NoError(t, q.load(ctx))
+	waitCtx, cancel = context.WithCancel(ctx)
+	go func() {
+		_, err := q.Wait(waitCtx, req)
+		errCh <- err
+	}()
+	require.Eventually(t, func() bool { return q.hasWaiters("task-2") }, time.Second, 10*time.Millisecond)
+	cancel()
+	require.ErrorIs(t, <-errCh, context.Canceled)
+	require.Equal(t, 1, q.Len())
+	_, err = metaCli.GetQueuedTaskByID(ctx, "task-2")
+	require.NoError(t, err)
+}
@@ -0,0 +1,163 @@
+package servermaster
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	"github.com/hanfei1991/microcosm/pb"
+	derrors "github.com/hanfei1991/microcosm/pkg/errors"
+)
+
+// revokeCheckInterval is how often the executors of the decisions on the
+// schedule streams are checked.
+const revokeCheckInterval = time.Second
+
+// Schedule implements pb.MasterServer.Schedule
+func (s *Server) Schedule(stream pb.Master_ScheduleServer) error {
+	if err := s.masterRPCHook.PreStream(stream.Context()); err != nil {
+		return err
+	}
+	return newScheduleStream(stream, s.scheduleTask, s.executorManager.HasExecutor).run()
+}
+
+type scheduleFunc func(ctx context.Context, req *pb.ScheduleTaskRequest) (*pb.ScheduleTaskResponse, error)
+
+// scheduleStream serves a schedule stream of a job master. The requests are
+// scheduled concurrently and the decisions are sent back once they are made,
+// which are tracked until the job master tells they are done, and revoked if
+// their executors are gone before that.
+type scheduleStream struct {
+	stream        pb.Master_ScheduleServer
+	schedule      scheduleFunc
+	executorAlive func(executorID string) bool
+
+	sendMu sync.Mutex
+
+	mu sync.Mutex
+	// decisions are the executors of the decisions not done
+	decisions map[int64]string
+}
+
+func newScheduleStream(
+	stream pb.Master_ScheduleServer,
+	schedule scheduleFunc,
+	executorAlive func(executorID string) bool,
+) *scheduleStream {
+	return &scheduleStream{
+		stream:        stream,
+		schedule:      schedule,
+		executorAlive: executorAlive,
+		decisions:     make(map[int64]string),
+	}
+}
+
+// run serves the stream until the job master closes it, the requests being
+// scheduled are canceled then.
+func (s *scheduleStream) run() error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(s.stream.Context())
+	defer cancel()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.revokeLoop(ctx)
+	}()
+
+	for {
+		req, err := s.stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if req.GetDone() {
+			s.mu.Lock()
+			delete(s.decisions, req.GetRequestId())
+			s.mu.Unlock()
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.handle(ctx, req)
+		}()
+	}
+}
+
+func (s *scheduleStream) handle(ctx context.Context, req *pb.ScheduleRequest) {
+	if req.GetTask() == nil {
+		s.send(&pb.ScheduleResponse{
+			RequestId: req.GetRequestId(),
+			Err: derrors.ToPBError(derrors.ErrMasterScheduleInvalidRequest.GenWithStackByArgs(
+				"task is not set")),
+		})
+		return
+	}
+	resp, err := s.schedule(ctx, req.GetTask())
+	if err != nil {
+		s.send(&pb.ScheduleResponse{
+			RequestId: req.GetRequestId(),
+			Err:       derrors.ToPBError(err),
+		})
+		return
+	}
+	s.mu.Lock()
+	s.decisions[req.GetRequestId()] = resp.GetExecutorId()
+	s.mu.Unlock()
+	s.send(&pb.ScheduleResponse{
+		RequestId: req.GetRequestId(),
+		Decision:  resp,
+	})
+}
+
+func (s *scheduleStream) revokeLoop(ctx context.Context) {
+	ticker := time.NewTicker(revokeCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		s.revokeDeadExecutors()
+	}
+}
+
+func (s *scheduleStream) revokeDeadExecutors() {
+	var revoked []int64
+	s.mu.Lock()
+	for requestID, executorID := range s.decisions {
+		if !s.executorAlive(executorID) {
+			revoked = append(revoked, requestID)
+			delete(s.decisions, requestID)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, requestID := range revoked {
+		log.L().Info("revoke schedule decision since executor is gone",
+			zap.Int64("request-id", requestID))
+		s.send(&pb.ScheduleResponse{
+			RequestId: requestID,
+			Revoked:   true,
+		})
+	}
+}
+
+// send sends a response, the error is ignored since Recv fails then.
+func (s *scheduleStream) send(resp *pb.ScheduleResponse) {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	if err := s.stream.Send(resp); err != nil {
+		log.L().Warn("send schedule response failed",
+			zap.Int64("request-id", resp.GetRequestId()), zap.Error(err))
+	}
+}
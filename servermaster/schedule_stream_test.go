@@ -0,0 +1,103 @@
+package servermaster
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/hanfei1991/microcosm/pb"
+)
+
+type mockScheduleServerStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	recvCh chan *pb.ScheduleRequest
+	sendCh chan *pb.ScheduleResponse
+}
+
+func newMockScheduleServerStream(ctx context.Context) *mockScheduleServerStream {
+	return &mockScheduleServerStream{
+		ctx:    ctx,
+		recvCh: make(chan *pb.ScheduleRequest, 16),
+		sendCh: make(chan *pb.ScheduleResponse, 16),
+	}
+}
+
+func (s *mockScheduleServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *mockScheduleServerStream) Send(resp *pb.ScheduleResponse) error {
+	s.sendCh <- resp
+	return nil
+}
+
+func (s *mockScheduleServerStream) Recv() (*pb.ScheduleRequest, error) {
+	req, ok := <-s.recvCh
+	if !ok {
+		return nil, io.EOF
+	}
+	return req, nil
+}
+
+func TestScheduleStream(t *testing.T) {
+	t.Parallel()
+
+	stream := newMockScheduleServerStream(context.Background())
+	var (
+		mu    sync.Mutex
+		alive = map[string]bool{"executor-1": true}
+	)
+	ss := newScheduleStream(stream,
+		func(ctx context.Context, req *pb.ScheduleTaskRequest) (*pb.ScheduleTaskResponse, error) {
+			return &pb.ScheduleTaskResponse{ExecutorId: "executor-1", ExecutorAddr: "127.0.0.1:10241"}, nil
+		},
+		func(executorID string) bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return alive[executorID]
+		})
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- ss.run()
+	}()
+
+	stream.recvCh <- &pb.ScheduleRequest{RequestId: 1, Task: &pb.ScheduleTaskRequest{TaskId: "worker-1"}}
+	resp := <-stream.sendCh
+	require.Equal(t, int64(1), resp.GetRequestId())
+	require.Equal(t, "executor-1", resp.GetDecision().GetExecutorId())
+
+	stream.recvCh <- &pb.ScheduleRequest{RequestId: 2}
+	resp = <-stream.sendCh
+	require.Equal(t, int64(2), resp.GetRequestId())
+	require.NotNil(t, resp.GetErr())
+
+	stream.recvCh <- &pb.ScheduleRequest{RequestId: 3, Task: &pb.ScheduleTaskRequest{TaskId: "worker-3"}}
+	resp = <-stream.sendCh
+	require.Equal(t, int64(3), resp.GetRequestId())
+	// the decision of request 3 is done, only request 1 is revoked
+	stream.recvCh <- &pb.ScheduleRequest{RequestId: 3, Done: true}
+	require.Eventually(t, func() bool {
+		ss.mu.Lock()
+		defer ss.mu.Unlock()
+		return len(ss.decisions) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	alive["executor-1"] = false
+	mu.Unlock()
+	ss.revokeDeadExecutors()
+	resp = <-stream.sendCh
+	require.Equal(t, int64(1), resp.GetRequestId())
+	require.True(t, resp.GetRevoked())
+	ss.revokeDeadExecutors()
+	require.Len(t, stream.sendCh, 0)
+
+	close(stream.recvCh)
+	require.NoError(t, <-errCh)
+}
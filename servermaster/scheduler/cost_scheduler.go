@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"math/rand"
+	"sort"
 	"time"
 
 	"github.com/hanfei1991/microcosm/model"
@@ -30,8 +31,25 @@ func NewDeterministicCostScheduler(capacityProvider CapacityProvider, seed int64
 	}
 }
 
-// ScheduleByCost is a native random based scheduling strategy
+// ScheduleByCost is a native random based scheduling strategy, idle-evictable
+// executors are chosen only if no other executor has enough capacity.
 func (s *CostScheduler) ScheduleByCost(cost schedModel.ResourceUnit) (model.ExecutorID, bool) {
+	return s.ScheduleByResources(cost.Vector())
+}
+
+// ScheduleByResources works like ScheduleByCost, but chooses an executor
+// with enough remaining resource in every dimension of required.
+func (s *CostScheduler) ScheduleByResources(required schedModel.ResourceVector) (model.ExecutorID, bool) {
+	return s.ScheduleByResourcesWithSpread(required, nil)
+}
+
+// ScheduleByResourcesWithSpread works like ScheduleByResources, but prefers
+// the executors in the zones running less tasks of the same job, where
+// zoneTasks is the number of the tasks of the job in each zone.
+func (s *CostScheduler) ScheduleByResourcesWithSpread(
+	required schedModel.ResourceVector,
+	zoneTasks map[string]int,
+) (model.ExecutorID, bool) {
 	executorCaps := s.capacityProvider.CapacitiesForAllExecutors()
 	executorList := make([]model.ExecutorID, 0, len(executorCaps))
 	for executorID := range executorCaps {
@@ -41,9 +59,16 @@ func (s *CostScheduler) ScheduleByCost(cost schedModel.ResourceUnit) (model.Exec
 	s.random.Shuffle(len(executorCaps), func(i, j int) {
 		executorList[i], executorList[j] = executorList[j], executorList[i]
 	})
+	sort.SliceStable(executorList, func(i, j int) bool {
+		capI, capJ := executorCaps[executorList[i]], executorCaps[executorList[j]]
+		if capI.IdleEvictable != capJ.IdleEvictable {
+			return !capI.IdleEvictable
+		}
+		return zoneTasks[capI.Zone] < zoneTasks[capJ.Zone]
+	})
 
 	for _, executorID := range executorList {
-		if executorCaps[executorID].Remaining() > cost {
+		if required.Fits(executorCaps[executorID].Remaining()) {
 			return executorID, true
 		}
 	}
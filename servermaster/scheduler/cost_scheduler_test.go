@@ -10,23 +10,27 @@ import (
 	schedModel "github.com/hanfei1991/microcosm/servermaster/scheduler/model"
 )
 
+func cpu(amount int64) schedModel.ResourceVector {
+	return schedModel.ResourceVector{model.ResourceCPU: amount}
+}
+
 func getMockCapacityData() CapacityProvider {
 	return &MockCapacityProvider{
 		Capacities: map[model.ExecutorID]*schedModel.ExecutorResourceStatus{
 			"executor-1": {
-				Capacity: 100,
-				Reserved: 50,
-				Used:     50,
+				Capacity: cpu(100),
+				Reserved: cpu(50),
+				Used:     cpu(50),
 			},
 			"executor-2": {
-				Capacity: 100,
-				Reserved: 30,
-				Used:     30,
+				Capacity: cpu(100),
+				Reserved: cpu(30),
+				Used:     cpu(30),
 			},
 			"executor-3": {
-				Capacity: 100,
-				Reserved: 10,
-				Used:     10,
+				Capacity: cpu(100),
+				Reserved: cpu(10),
+				Used:     cpu(10),
 			},
 		},
 	}
@@ -45,6 +49,64 @@ func TestScheduleByCostBasics(t *testing.T) {
 	require.False(t, ok)
 }
 
+func TestScheduleByResources(t *testing.T) {
+	capacities := getMockCapacityData().(*MockCapacityProvider)
+	capacities.Capacities["executor-1"].Capacity = schedModel.ResourceVector{
+		model.ResourceCPU:    100,
+		model.ResourceMemory: 1024,
+		"gpu":                2,
+	}
+	capacities.Capacities["executor-1"].Used[model.ResourceMemory] = 512
+	capacities.Capacities["executor-2"].Capacity[model.ResourceMemory] = 2048
+	costSched := NewDeterministicCostScheduler(capacities, randomSeedForTest)
+
+	// only executor-1 has the custom resource
+	for i := 0; i < 10; i++ {
+		target, ok := costSched.ScheduleByResources(schedModel.ResourceVector{
+			model.ResourceCPU: 10,
+			"gpu":             2,
+		})
+		require.True(t, ok)
+		require.Equal(t, model.ExecutorID("executor-1"), target)
+	}
+	_, ok := costSched.ScheduleByResources(schedModel.ResourceVector{"gpu": 3})
+	require.False(t, ok)
+
+	// executor-1 has only 512 memory remaining
+	for i := 0; i < 10; i++ {
+		target, ok := costSched.ScheduleByResources(schedModel.ResourceVector{
+			model.ResourceCPU:    10,
+			model.ResourceMemory: 1000,
+		})
+		require.True(t, ok)
+		require.Equal(t, model.ExecutorID("executor-2"), target)
+	}
+	// cpu is enough on executor-3, but memory isn't
+	_, ok = costSched.ScheduleByResources(schedModel.ResourceVector{
+		model.ResourceCPU:    85,
+		model.ResourceMemory: 100,
+	})
+	require.False(t, ok)
+}
+
+func TestScheduleByCostPreferNonEvictable(t *testing.T) {
+	capacities := getMockCapacityData().(*MockCapacityProvider)
+	capacities.Capacities["executor-2"].IdleEvictable = true
+	capacities.Capacities["executor-3"].IdleEvictable = true
+	costSched := NewDeterministicCostScheduler(capacities, randomSeedForTest)
+
+	for i := 0; i < 10; i++ {
+		target, ok := costSched.ScheduleByCost(5)
+		require.True(t, ok)
+		require.Equal(t, model.ExecutorID("executor-1"), target)
+	}
+
+	// idle-evictable executors are used if other executors are full
+	target, ok := costSched.ScheduleByCost(60)
+	require.True(t, ok)
+	require.NotEqual(t, model.ExecutorID("executor-1"), target)
+}
+
 func TestScheduleByCostBalance(t *testing.T) {
 	costSched := NewDeterministicCostScheduler(getMockCapacityData(), randomSeedForTest)
 	counters := make(map[model.ExecutorID]int)
@@ -60,3 +122,26 @@ func TestScheduleByCostBalance(t *testing.T) {
 		math.Pow(float64(counters["executor-3"]-333), 2)/3.0)
 	require.Less(t, stddev, 100.0)
 }
+
+func TestScheduleByResourcesWithSpread(t *testing.T) {
+	capacities := getMockCapacityData().(*MockCapacityProvider)
+	capacities.Capacities["executor-1"].Zone = "zone-1"
+	capacities.Capacities["executor-2"].Zone = "zone-1"
+	capacities.Capacities["executor-3"].Zone = "zone-2"
+	costSched := NewDeterministicCostScheduler(capacities, randomSeedForTest)
+
+	for i := 0; i < 10; i++ {
+		target, ok := costSched.ScheduleByResourcesWithSpread(cpu(5), map[string]int{"zone-2": 1})
+		require.True(t, ok)
+		require.NotEqual(t, model.ExecutorID("executor-3"), target)
+	}
+	for i := 0; i < 10; i++ {
+		target, ok := costSched.ScheduleByResourcesWithSpread(cpu(5), map[string]int{"zone-1": 2, "zone-2": 1})
+		require.True(t, ok)
+		require.Equal(t, model.ExecutorID("executor-3"), target)
+	}
+	// the zone running more tasks is used if the other zone is full
+	target, ok := costSched.ScheduleByResourcesWithSpread(cpu(80), map[string]int{"zone-2": 1})
+	require.True(t, ok)
+	require.Equal(t, model.ExecutorID("executor-3"), target)
+}
@@ -3,21 +3,32 @@ package model
 import "github.com/hanfei1991/microcosm/model"
 
 // ResourceUnit is a type representing the value of
-// resource used. Although currently it is a scalar,
-// we expect expanding it to a vector, so that multiple
-// scalars can be held together, such as (CPU time, memory).
+// resource used in the cpu dimension, it is kept for
+// the callers not aware of the other dimensions.
 type ResourceUnit = model.RescUnit
 
+// ResourceVector is a type representing the value of
+// resource used in multiple dimensions, such as
+// (CPU time, memory, disk) and custom resources.
+type ResourceVector = model.RescVector
+
 // ExecutorResourceStatus represents an overview of
 // resource usage on a given executor.
 type ExecutorResourceStatus struct {
-	Capacity, Reserved, Used ResourceUnit
+	Capacity, Reserved, Used ResourceVector
+	// IdleEvictable means the executor may be removed by autoscalers soon,
+	// new tasks are scheduled to other executors if possible.
+	IdleEvictable bool
+	// Zone is the zone the executor runs in, the tasks of a job are spread
+	// across zones. Empty means unknown.
+	Zone string
+	// Cordoned means the executor is cordoned by operators, no new task is
+	// scheduled to it.
+	Cordoned bool
 }
 
-// Remaining calculates the available resource unit of given resource
-func (s *ExecutorResourceStatus) Remaining() ResourceUnit {
-	if s.Used > s.Reserved {
-		return s.Capacity - s.Used
-	}
-	return s.Capacity - s.Reserved
+// Remaining calculates the available resource of given resource in each
+// dimension.
+func (s *ExecutorResourceStatus) Remaining() ResourceVector {
+	return s.Capacity.Sub(s.Used.Max(s.Reserved))
 }
@@ -8,9 +8,24 @@ import (
 // SchedulerRequest represents a request for an executor to run a given task.
 type SchedulerRequest struct {
 	TenantID string // reserved for future use.
+	// JobID is the job the task belongs to, the task uses the capacity
+	// reserved for the job if there is any.
+	JobID string
 
-	Cost              ResourceUnit
+	// Cost is the requirement in the cpu dimension, it is used if Resources
+	// doesn't specify the cpu dimension.
+	Cost ResourceUnit
+	// Resources is the requirement in all dimensions.
+	Resources         ResourceVector
 	ExternalResources []resourcemeta.ResourceID
+	// Failover is true if the task is re-dispatched after its executor
+	// fails, such a task can use the headroom reserved by the scheduler.
+	Failover bool
+}
+
+// Requirement returns the requirement of the request in all dimensions.
+func (r *SchedulerRequest) Requirement() ResourceVector {
+	return r.Cost.Vector().Merge(r.Resources)
 }
 
 // SchedulerResponse represents a response to a task scheduling request.
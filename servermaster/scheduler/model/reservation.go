@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// JobResourceSpec describes the capacity reserved for a job.
+type JobResourceSpec struct {
+	// MinWorkers is the number of workers whose capacity is reserved when
+	// the job is submitted.
+	MinWorkers int
+	// MaxWorkers limits the workers granted to the job, 0 means no limit.
+	MaxWorkers int
+	// WorkerResources is the requirement of each worker.
+	WorkerResources ResourceVector
+}
+
+// ScaleUpResult represents a response to a scale-up request of a job.
+type ScaleUpResult struct {
+	// GrantedWorkers is the number of workers whose capacity is reserved
+	// for the job, it may be less than requested.
+	GrantedWorkers int
+	// Backoff is how long the job should wait before requesting again, it
+	// is set if no worker is granted.
+	Backoff time.Duration
+}
@@ -0,0 +1,175 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+	schedModel "github.com/hanfei1991/microcosm/servermaster/scheduler/model"
+)
+
+const (
+	// defaultScaleUpBackoff is the backoff hinted to a job after its first
+	// scale-up request granting nothing, it doubles with every consecutive
+	// denial up to maxScaleUpBackoff.
+	defaultScaleUpBackoff = time.Second
+	maxScaleUpBackoff     = 30 * time.Second
+)
+
+type jobReservation struct {
+	spec schedModel.JobResourceSpec
+	// reserved is the number of workers whose capacity is reserved but
+	// are not scheduled yet.
+	reserved int
+	// denials is the number of consecutive scale-up requests granting
+	// nothing.
+	denials int
+}
+
+func (r *jobReservation) capacity() schedModel.ResourceVector {
+	return r.spec.WorkerResources.Multiply(r.reserved)
+}
+
+// ReserveJob reserves the capacity of the min workers of a job, it fails if
+// the free capacity of the cluster is not enough. The reservation replaces
+// the previous one of the job if there is any.
+func (s *Scheduler) ReserveJob(jobID string, spec *schedModel.JobResourceSpec) error {
+	s.reservationMu.Lock()
+	defer s.reservationMu.Unlock()
+
+	required := spec.WorkerResources.Multiply(spec.MinWorkers)
+	free := s.freeCapacity(s.reservedLocked(jobID))
+	if !required.Fits(free) {
+		log.L().Info("job reservation rejected",
+			zap.String("job-id", jobID),
+			zap.Stringer("required", required),
+			zap.Stringer("free", free))
+		return derror.ErrClusterResourceNotEnough.GenWithStackByArgs()
+	}
+	s.reservations[jobID] = &jobReservation{spec: *spec, reserved: spec.MinWorkers}
+	log.L().Info("job capacity reserved",
+		zap.String("job-id", jobID), zap.Stringer("reserved", required))
+	return nil
+}
+
+// RestoreJobReservation works like ReserveJob but doesn't check the free
+// capacity, it is used to restore the reservations of the jobs recovered
+// after server master failover, whose capacity has been checked before.
+func (s *Scheduler) RestoreJobReservation(jobID string, spec *schedModel.JobResourceSpec) {
+	s.reservationMu.Lock()
+	defer s.reservationMu.Unlock()
+
+	s.reservations[jobID] = &jobReservation{spec: *spec, reserved: spec.MinWorkers}
+}
+
+// ReleaseJob releases the capacity reserved for a job, and forgets the
+// placements of its tasks.
+func (s *Scheduler) ReleaseJob(jobID string) {
+	s.forgetPlacements(jobID)
+
+	s.reservationMu.Lock()
+	defer s.reservationMu.Unlock()
+
+	delete(s.reservations, jobID)
+}
+
+// ScaleUpJob reserves the capacity of more workers for a job running
+// currentWorkers workers. Less workers than requested are granted if the free
+// capacity is not enough, and a backoff is hinted if none is granted.
+func (s *Scheduler) ScaleUpJob(jobID string, currentWorkers, workers int) (*schedModel.ScaleUpResult, error) {
+	s.reservationMu.Lock()
+	defer s.reservationMu.Unlock()
+
+	r, ok := s.reservations[jobID]
+	if !ok {
+		return nil, derror.ErrJobNotReserved.GenWithStackByArgs(jobID)
+	}
+	if workers <= 0 {
+		return &schedModel.ScaleUpResult{}, nil
+	}
+	if maxWorkers := r.spec.MaxWorkers; maxWorkers > 0 {
+		limit := maxWorkers - currentWorkers - r.reserved
+		if limit <= 0 {
+			return nil, derror.ErrJobMaxWorkersExceeded.GenWithStackByArgs(jobID, maxWorkers)
+		}
+		if workers > limit {
+			workers = limit
+		}
+	}
+
+	// the capacity reserved for the job itself is not free either
+	free := s.freeCapacity(s.reservedLocked(""))
+	granted := fitWorkers(free, r.spec.WorkerResources, workers)
+	if granted == 0 {
+		r.denials++
+		backoff := scaleUpBackoff(r.denials)
+		log.L().Info("job scale-up denied",
+			zap.String("job-id", jobID),
+			zap.Int("workers", workers),
+			zap.Stringer("free", free),
+			zap.Duration("backoff", backoff))
+		return &schedModel.ScaleUpResult{Backoff: backoff}, nil
+	}
+	r.denials = 0
+	r.reserved += granted
+	log.L().Info("job scale-up granted",
+		zap.String("job-id", jobID),
+		zap.Int("workers", workers),
+		zap.Int("granted", granted))
+	return &schedModel.ScaleUpResult{GrantedWorkers: granted}, nil
+}
+
+// consumeReservation is called after a task of the job is scheduled, the
+// task uses the capacity reserved for a worker.
+func (s *Scheduler) consumeReservation(jobID string) {
+	s.reservationMu.Lock()
+	defer s.reservationMu.Unlock()
+
+	if r, ok := s.reservations[jobID]; ok && r.reserved > 0 {
+		r.reserved--
+	}
+}
+
+// reservedLocked returns the capacity reserved for the jobs except the given
+// one, which is not scheduled yet.
+func (s *Scheduler) reservedLocked(excludedJobID string) schedModel.ResourceVector {
+	ret := schedModel.ResourceVector{}
+	for jobID, r := range s.reservations {
+		if jobID == excludedJobID {
+			continue
+		}
+		ret = ret.Add(r.capacity())
+	}
+	return ret
+}
+
+// fitWorkers returns the max number of workers, no more than workers, that
+// fit in the free capacity.
+func fitWorkers(free, perWorker schedModel.ResourceVector, workers int) int {
+	ret := int64(workers)
+	for name, amount := range perWorker {
+		if amount <= 0 {
+			continue
+		}
+		if fit := free[name] / amount; fit < ret {
+			ret = fit
+		}
+	}
+	if ret < 0 {
+		return 0
+	}
+	return int(ret)
+}
+
+func scaleUpBackoff(denials int) time.Duration {
+	backoff := defaultScaleUpBackoff
+	for i := 1; i < denials && backoff < maxScaleUpBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxScaleUpBackoff {
+		backoff = maxScaleUpBackoff
+	}
+	return backoff
+}
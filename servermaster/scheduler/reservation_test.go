@@ -0,0 +1,86 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hanfei1991/microcosm/model"
+	derror "github.com/hanfei1991/microcosm/pkg/errors"
+	schedModel "github.com/hanfei1991/microcosm/servermaster/scheduler/model"
+)
+
+func TestSchedulerJobReservation(t *testing.T) {
+	// total remaining = 100
+	sched := NewScheduler(
+		getMockCapacityDataForScheduler(),
+		getMockResourceConstraintForScheduler())
+
+	err := sched.ReserveJob("job-1", &schedModel.JobResourceSpec{
+		MinWorkers:      2,
+		MaxWorkers:      3,
+		WorkerResources: cpu(20),
+	})
+	require.NoError(t, err)
+	err = sched.ReserveJob("job-2", &schedModel.JobResourceSpec{
+		MinWorkers:      4,
+		WorkerResources: cpu(20),
+	})
+	require.Error(t, err)
+	require.Regexp(t, ".*ErrClusterResourceNotEnough.*", err)
+	_, err = sched.ScaleUpJob("job-2", 0, 1)
+	require.True(t, derror.ErrJobNotReserved.Equal(err))
+
+	// the capacity reserved for job-1 can't be used by others
+	require.False(t, sched.checkClusterAllows(&schedModel.SchedulerRequest{Cost: 61}))
+	require.True(t, sched.checkClusterAllows(&schedModel.SchedulerRequest{Cost: 60}))
+	require.True(t, sched.checkClusterAllows(&schedModel.SchedulerRequest{Cost: 100, JobID: "job-1"}))
+
+	_, err = sched.ScheduleTask(context.Background(), &schedModel.SchedulerRequest{
+		Cost:  20,
+		JobID: "job-1",
+	})
+	require.NoError(t, err)
+	require.True(t, sched.checkClusterAllows(&schedModel.SchedulerRequest{Cost: 80}))
+
+	// job-1 is limited by its max workers
+	result, err := sched.ScaleUpJob("job-1", 1, 5)
+	require.NoError(t, err)
+	require.Equal(t, &schedModel.ScaleUpResult{GrantedWorkers: 1}, result)
+	_, err = sched.ScaleUpJob("job-1", 1, 1)
+	require.True(t, derror.ErrJobMaxWorkersExceeded.Equal(err))
+
+	// job-3 is limited by the free capacity
+	sched.RestoreJobReservation("job-3", &schedModel.JobResourceSpec{
+		WorkerResources: cpu(30),
+	})
+	result, err = sched.ScaleUpJob("job-3", 0, 5)
+	require.NoError(t, err)
+	require.Equal(t, &schedModel.ScaleUpResult{GrantedWorkers: 2}, result)
+	result, err = sched.ScaleUpJob("job-3", 0, 1)
+	require.NoError(t, err)
+	require.Equal(t, &schedModel.ScaleUpResult{Backoff: time.Second}, result)
+	result, err = sched.ScaleUpJob("job-3", 0, 1)
+	require.NoError(t, err)
+	require.Equal(t, &schedModel.ScaleUpResult{Backoff: 2 * time.Second}, result)
+
+	sched.ReleaseJob("job-1")
+	result, err = sched.ScaleUpJob("job-3", 0, 1)
+	require.NoError(t, err)
+	require.Equal(t, &schedModel.ScaleUpResult{GrantedWorkers: 1}, result)
+}
+
+func TestFitWorkers(t *testing.T) {
+	free := schedModel.ResourceVector{model.ResourceCPU: 100, model.ResourceMemory: 50}
+	require.Equal(t, 3, fitWorkers(free, schedModel.ResourceVector{model.ResourceCPU: 30}, 5))
+	require.Equal(t, 2, fitWorkers(free, schedModel.ResourceVector{model.ResourceCPU: 30, model.ResourceMemory: 20}, 5))
+	require.Equal(t, 0, fitWorkers(free, schedModel.ResourceVector{"gpu": 1}, 5))
+	require.Equal(t, 5, fitWorkers(free, schedModel.ResourceVector{}, 5))
+	require.Equal(t, 0, fitWorkers(schedModel.ResourceVector{model.ResourceCPU: -10}, cpu(1), 5))
+
+	require.Equal(t, time.Second, scaleUpBackoff(1))
+	require.Equal(t, 4*time.Second, scaleUpBackoff(3))
+	require.Equal(t, maxScaleUpBackoff, scaleUpBackoff(10))
+}
@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"context"
+	"sync"
 
 	"github.com/pingcap/tiflow/dm/pkg/log"
 	"go.uber.org/zap"
@@ -18,25 +19,79 @@ type Scheduler struct {
 	capacityProvider     CapacityProvider
 	costScheduler        *CostScheduler
 	placementConstrainer PlacementConstrainer
+
+	// headroomPercent is the percentage of the cluster capacity that is
+	// kept unallocated for re-dispatching tasks after executor failures.
+	headroomPercent int
+
+	reservationMu sync.Mutex
+	// reservations are the capacity reserved for jobs, keyed by job IDs.
+	reservations map[string]*jobReservation
+
+	spreadMu sync.Mutex
+	// zoneTasks is the number of the tasks of each job scheduled to each
+	// zone, keyed by job IDs. The exited tasks are not subtracted, so the
+	// tasks of a job are spread by all its placements until the job is
+	// released.
+	zoneTasks map[string]map[string]int
+}
+
+// Option is used to configure a Scheduler
+type Option func(*Scheduler)
+
+// WithHeadroomPercent reserves the given percentage of the cluster capacity
+// for failover re-dispatches. Normal requests can't consume the reserved
+// headroom, so that the failed tasks can still be recovered when the cluster
+// is full.
+func WithHeadroomPercent(percent int) Option {
+	return func(s *Scheduler) {
+		s.headroomPercent = percent
+	}
 }
 
 // NewScheduler creates a new Scheduler instance
 func NewScheduler(
 	capacityProvider CapacityProvider,
 	placementConstrainer PlacementConstrainer,
+	opts ...Option,
 ) *Scheduler {
-	return &Scheduler{
+	s := &Scheduler{
 		capacityProvider:     capacityProvider,
 		costScheduler:        NewRandomizedCostScheduler(capacityProvider),
 		placementConstrainer: placementConstrainer,
+		reservations:         make(map[string]*jobReservation),
+		zoneTasks:            make(map[string]map[string]int),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
-// ScheduleTask tries to assign an executor to a given task.
+// ScheduleTask tries to assign an executor to a given task. A task of a job
+// having reserved capacity consumes the reservation of a worker. The tasks
+// of a job are spread across the zones of executors if possible.
 func (s *Scheduler) ScheduleTask(
 	ctx context.Context,
 	request *schedModel.SchedulerRequest,
 ) (*schedModel.SchedulerResponse, error) {
+	resp, err := s.scheduleTask(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	s.consumeReservation(request.JobID)
+	s.recordPlacement(request.JobID, resp.ExecutorID)
+	return resp, nil
+}
+
+func (s *Scheduler) scheduleTask(
+	ctx context.Context,
+	request *schedModel.SchedulerRequest,
+) (*schedModel.SchedulerResponse, error) {
+	if !request.Failover && !s.checkClusterAllows(request) {
+		return nil, derror.ErrClusterResourceNotEnough.GenWithStackByArgs()
+	}
+
 	if len(request.ExternalResources) == 0 {
 		// There is no requirement for external resources.
 		return s.scheduleByCostOnly(request)
@@ -62,7 +117,8 @@ func (s *Scheduler) ScheduleTask(
 func (s *Scheduler) scheduleByCostOnly(
 	request *schedModel.SchedulerRequest,
 ) (*schedModel.SchedulerResponse, error) {
-	target, ok := s.costScheduler.ScheduleByCost(request.Cost)
+	target, ok := s.costScheduler.ScheduleByResourcesWithSpread(
+		request.Requirement(), s.jobZoneTasks(request.JobID))
 	if ok {
 		return &schedModel.SchedulerResponse{
 			ExecutorID: target,
@@ -80,8 +136,50 @@ func (s *Scheduler) checkCostAllows(
 		// Executor is gone.
 		return false
 	}
-	remaining := executorResc.Remaining()
-	return remaining >= request.Cost
+	if executorResc.Cordoned {
+		return false
+	}
+	return request.Requirement().Fits(executorResc.Remaining())
+}
+
+// checkClusterAllows checks that the cluster still has the reserved headroom
+// and the capacity reserved for other jobs after the request is scheduled.
+func (s *Scheduler) checkClusterAllows(request *schedModel.SchedulerRequest) bool {
+	s.reservationMu.Lock()
+	reserved := s.reservedLocked(request.JobID)
+	s.reservationMu.Unlock()
+	if s.headroomPercent <= 0 && reserved.IsZero() {
+		return true
+	}
+
+	free := s.freeCapacity(reserved)
+	required := request.Requirement()
+	for name, amount := range required {
+		if amount > 0 && free[name] < amount {
+			log.L().Info("request rejected to keep headroom and reserved capacity",
+				zap.Stringer("required", required),
+				zap.Stringer("free", free),
+				zap.Stringer("reserved", reserved))
+			return false
+		}
+	}
+	return true
+}
+
+// freeCapacity returns the remaining capacity of the cluster, excluding the
+// headroom for failover and the given reserved capacity.
+func (s *Scheduler) freeCapacity(reserved schedModel.ResourceVector) schedModel.ResourceVector {
+	capacity := schedModel.ResourceVector{}
+	remaining := schedModel.ResourceVector{}
+	for _, status := range s.capacityProvider.CapacitiesForAllExecutors() {
+		capacity = capacity.Add(status.Capacity)
+		remaining = remaining.Add(status.Remaining())
+	}
+	free := remaining.Sub(reserved)
+	if s.headroomPercent > 0 {
+		free = free.Sub(capacity.Scale(s.headroomPercent))
+	}
+	return free
 }
 
 func (s *Scheduler) getConstraint(
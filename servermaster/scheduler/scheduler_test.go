@@ -15,19 +15,19 @@ func getMockCapacityDataForScheduler() CapacityProvider {
 	return &MockCapacityProvider{
 		Capacities: map[model.ExecutorID]*schedModel.ExecutorResourceStatus{
 			"executor-1": {
-				Capacity: 100,
-				Reserved: 40,
-				Used:     60,
+				Capacity: cpu(100),
+				Reserved: cpu(40),
+				Used:     cpu(60),
 			}, // available = 40
 			"executor-2": {
-				Capacity: 100,
-				Reserved: 30,
-				Used:     70,
+				Capacity: cpu(100),
+				Reserved: cpu(30),
+				Used:     cpu(70),
 			}, // available = 30
 			"executor-3": {
-				Capacity: 100,
-				Reserved: 70,
-				Used:     30,
+				Capacity: cpu(100),
+				Reserved: cpu(70),
+				Used:     cpu(30),
 			}, // available = 30
 		},
 	}
@@ -54,6 +54,35 @@ func TestSchedulerByCost(t *testing.T) {
 	require.Equal(t, &schedModel.SchedulerResponse{ExecutorID: "executor-1"}, resp)
 }
 
+func TestSchedulerByResources(t *testing.T) {
+	capacities := getMockCapacityDataForScheduler().(*MockCapacityProvider)
+	capacities.Capacities["executor-2"].Capacity["gpu"] = 1
+	sched := NewScheduler(capacities, getMockResourceConstraintForScheduler())
+
+	// Cost is used as the cpu dimension
+	resp, err := sched.ScheduleTask(context.Background(), &schedModel.SchedulerRequest{
+		Cost:      20,
+		Resources: schedModel.ResourceVector{"gpu": 1},
+	})
+	require.NoError(t, err)
+	require.Equal(t, &schedModel.SchedulerResponse{ExecutorID: "executor-2"}, resp)
+
+	// Resources overrides Cost in the cpu dimension
+	_, err = sched.ScheduleTask(context.Background(), &schedModel.SchedulerRequest{
+		Cost:      20,
+		Resources: schedModel.ResourceVector{model.ResourceCPU: 35, "gpu": 1},
+	})
+	require.Error(t, err)
+	require.Regexp(t, ".*ErrClusterResourceNotEnough.*", err)
+
+	_, err = sched.ScheduleTask(context.Background(), &schedModel.SchedulerRequest{
+		Resources:         schedModel.ResourceVector{"gpu": 1},
+		ExternalResources: []resourcemeta.ResourceID{"resource-1"},
+	})
+	require.Error(t, err)
+	require.Regexp(t, ".*ErrClusterResourceNotEnough.*", err)
+}
+
 func TestSchedulerByConstraint(t *testing.T) {
 	sched := NewScheduler(
 		getMockCapacityDataForScheduler(),
@@ -137,3 +166,55 @@ func TestSchedulerConstraintConflict(t *testing.T) {
 	require.Error(t, err)
 	require.Regexp(t, ".*Scheduler could not assign executor due to conflicting.*", err)
 }
+
+func TestSchedulerHeadroom(t *testing.T) {
+	// total capacity = 300, available = 100, headroom = 90
+	sched := NewScheduler(
+		getMockCapacityDataForScheduler(),
+		getMockResourceConstraintForScheduler(),
+		WithHeadroomPercent(30))
+
+	_, err := sched.ScheduleTask(context.Background(), &schedModel.SchedulerRequest{
+		Cost: 20,
+	})
+	require.Error(t, err)
+	require.Regexp(t, ".*ErrClusterResourceNotEnough.*", err)
+
+	_, err = sched.ScheduleTask(context.Background(), &schedModel.SchedulerRequest{
+		Cost: 10,
+	})
+	require.NoError(t, err)
+
+	resp, err := sched.ScheduleTask(context.Background(), &schedModel.SchedulerRequest{
+		Cost:              20,
+		ExternalResources: []resourcemeta.ResourceID{"resource-2"},
+		Failover:          true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, &schedModel.SchedulerResponse{ExecutorID: "executor-2"}, resp)
+}
+
+func TestSchedulerSpreadByZone(t *testing.T) {
+	capacities := getMockCapacityDataForScheduler().(*MockCapacityProvider)
+	capacities.Capacities["executor-1"].Zone = "zone-1"
+	capacities.Capacities["executor-2"].Zone = "zone-1"
+	capacities.Capacities["executor-3"].Zone = "zone-2"
+	sched := NewScheduler(capacities, getMockResourceConstraintForScheduler())
+
+	zoneTasks := make(map[string]int)
+	for i := 0; i < 4; i++ {
+		resp, err := sched.ScheduleTask(context.Background(), &schedModel.SchedulerRequest{
+			Cost:  5,
+			JobID: "job-1",
+		})
+		require.NoError(t, err)
+		zoneTasks[capacities.Capacities[resp.ExecutorID].Zone]++
+	}
+	require.Equal(t, map[string]int{"zone-1": 2, "zone-2": 2}, zoneTasks)
+	require.Equal(t, zoneTasks, sched.jobZoneTasks("job-1"))
+
+	// the tasks of other jobs are spread independently
+	require.Nil(t, sched.jobZoneTasks("job-2"))
+	sched.ReleaseJob("job-1")
+	require.Nil(t, sched.jobZoneTasks("job-1"))
+}
@@ -0,0 +1,54 @@
+package scheduler
+
+import (
+	"github.com/hanfei1991/microcosm/model"
+)
+
+// jobZoneTasks returns a copy of the number of the tasks of a job scheduled
+// to each zone, nil is returned if the job is unknown.
+func (s *Scheduler) jobZoneTasks(jobID string) map[string]int {
+	if jobID == "" {
+		return nil
+	}
+	s.spreadMu.Lock()
+	defer s.spreadMu.Unlock()
+
+	zoneTasks, ok := s.zoneTasks[jobID]
+	if !ok {
+		return nil
+	}
+	ret := make(map[string]int, len(zoneTasks))
+	for zone, count := range zoneTasks {
+		ret[zone] = count
+	}
+	return ret
+}
+
+// recordPlacement records that a task of a job is scheduled to an executor,
+// so that the following tasks of the job are spread to other zones.
+func (s *Scheduler) recordPlacement(jobID string, executorID model.ExecutorID) {
+	if jobID == "" {
+		return
+	}
+	status, ok := s.capacityProvider.CapacityForExecutor(executorID)
+	if !ok {
+		return
+	}
+	s.spreadMu.Lock()
+	defer s.spreadMu.Unlock()
+
+	zoneTasks, ok := s.zoneTasks[jobID]
+	if !ok {
+		zoneTasks = make(map[string]int)
+		s.zoneTasks[jobID] = zoneTasks
+	}
+	zoneTasks[status.Zone]++
+}
+
+// forgetPlacements forgets the tasks scheduled for a job.
+func (s *Scheduler) forgetPlacements(jobID string) {
+	s.spreadMu.Lock()
+	defer s.spreadMu.Unlock()
+
+	delete(s.zoneTasks, jobID)
+}
@@ -33,19 +33,31 @@ import (
 	"github.com/hanfei1991/microcosm/model"
 	"github.com/hanfei1991/microcosm/pb"
 	"github.com/hanfei1991/microcosm/pkg/adapter"
+	"github.com/hanfei1991/microcosm/pkg/adminserver"
+	"github.com/hanfei1991/microcosm/pkg/clock"
 	dcontext "github.com/hanfei1991/microcosm/pkg/context"
 	"github.com/hanfei1991/microcosm/pkg/deps"
 	derrors "github.com/hanfei1991/microcosm/pkg/errors"
 	"github.com/hanfei1991/microcosm/pkg/etcdutils"
+	"github.com/hanfei1991/microcosm/pkg/eventbus"
 	externRescManager "github.com/hanfei1991/microcosm/pkg/externalresource/manager"
+	"github.com/hanfei1991/microcosm/pkg/faultinject"
+	"github.com/hanfei1991/microcosm/pkg/interceptor"
+	"github.com/hanfei1991/microcosm/pkg/lockdiag"
+	"github.com/hanfei1991/microcosm/pkg/meta/encryption"
 	extkv "github.com/hanfei1991/microcosm/pkg/meta/extension"
 	"github.com/hanfei1991/microcosm/pkg/meta/kvclient"
 	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
 	pkgOrm "github.com/hanfei1991/microcosm/pkg/orm"
+	"github.com/hanfei1991/microcosm/pkg/orm/migration"
 	"github.com/hanfei1991/microcosm/pkg/p2p"
+	"github.com/hanfei1991/microcosm/pkg/redact"
 	"github.com/hanfei1991/microcosm/pkg/rpcutil"
+	"github.com/hanfei1991/microcosm/pkg/secret"
 	"github.com/hanfei1991/microcosm/pkg/serverutils"
+	"github.com/hanfei1991/microcosm/pkg/sink"
 	"github.com/hanfei1991/microcosm/pkg/tenant"
+	"github.com/hanfei1991/microcosm/servermaster/autoscaler"
 	"github.com/hanfei1991/microcosm/servermaster/cluster"
 	"github.com/hanfei1991/microcosm/servermaster/scheduler"
 	schedModel "github.com/hanfei1991/microcosm/servermaster/scheduler/model"
@@ -89,6 +101,7 @@ type Server struct {
 
 	msgService      *p2p.MessageRPCService
 	p2pMsgRouter    p2p.MessageRouter
+	peerCodecs      *p2p.PeerCodecs
 	rpcLogRL        *rate.Limiter
 	discoveryKeeper *serverutils.DiscoveryKeepaliver
 
@@ -103,8 +116,27 @@ type Server struct {
 
 	// framework metastore client
 	frameMetaClient pkgOrm.Client
+	// metaMigration migrates the framework metastore to another backend,
+	// frameMetaClient is created by its migrator.
+	metaMigration *metaMigration
+	// secrets stores the secrets of projects in the framework metastore
+	secrets *secret.Store
 	// user metastore kvclient
 	userMetaKVClient extkv.KVClientEx
+	// sinkExporter exports the job events, it is nil if no sink is configured.
+	sinkExporter *sink.Exporter
+	// pendingQueue queues the workers waiting for the executors added by the
+	// autoscaler, it is nil if no autoscaler is configured.
+	pendingQueue *autoscaler.PendingQueue
+	// scheduleQueue holds the queued schedule requests until the cluster
+	// has enough resources for them.
+	scheduleQueue *scheduleQueue
+	// idempotency deduplicates the retried requests that are not idempotent,
+	// such as SubmitJob.
+	idempotency *rpcutil.IdempotencyCache
+	// eventBus delivers the events of the executors, the workers and the
+	// leadership to the subsystems interested in them.
+	eventBus *eventbus.Bus
 }
 
 // PersistResource implements pb.MasterServer.PersistResource
@@ -144,7 +176,8 @@ func genServerMasterUUID(etcdName string) string {
 
 // NewServer creates a new master-server.
 func NewServer(cfg *Config, ctx *test.Context) (*Server, error) {
-	executorManager := NewExecutorManagerImpl(cfg.KeepAliveTTL, cfg.KeepAliveInterval, ctx)
+	eventBus := eventbus.NewBus()
+	executorManager := NewExecutorManagerImpl(cfg.KeepAliveTTL, cfg.KeepAliveInterval, eventBus, ctx)
 
 	urls, err := parseURLs(cfg.MasterAddr)
 	if err != nil {
@@ -157,9 +190,10 @@ func NewServer(cfg *Config, ctx *test.Context) (*Server, error) {
 
 	id := genServerMasterUUID(cfg.Etcd.Name)
 	info := &model.NodeInfo{
-		Type: model.NodeTypeServerMaster,
-		ID:   model.DeployNodeID(id),
-		Addr: cfg.AdvertiseAddr,
+		Type:          model.NodeTypeServerMaster,
+		ID:            model.DeployNodeID(id),
+		Addr:          cfg.AdvertiseAddr,
+		MessageCodecs: p2p.SupportedCodecs(),
 	}
 	p2pMsgRouter := p2p.NewMessageRouter(p2p.NodeID(info.ID), info.Addr)
 
@@ -174,9 +208,12 @@ func NewServer(cfg *Config, ctx *test.Context) (*Server, error) {
 		masterCli:         &rpcutil.LeaderClientWithLock[pb.MasterClient]{},
 		resourceCli:       &rpcutil.LeaderClientWithLock[pb.ResourceManagerClient]{},
 		p2pMsgRouter:      p2pMsgRouter,
+		peerCodecs:        p2p.NewPeerCodecs(),
 		rpcLogRL:          rate.NewLimiter(rate.Every(time.Second*5), 3 /*burst*/),
 		metrics:           newServerMasterMetric(),
 		metaStoreManager:  NewMetaStoreManager(),
+		idempotency:       rpcutil.NewIdempotencyCache(idempotencyTTL),
+		eventBus:          eventBus,
 	}
 	server.leaderServiceFn = server.runLeaderService
 	masterRPCHook := rpcutil.NewPreRPCHook[pb.MasterClient](
@@ -222,7 +259,13 @@ func (s *Server) SubmitJob(ctx context.Context, req *pb.SubmitJobRequest) (*pb.S
 	if shouldRet {
 		return resp2, err
 	}
-	return s.jobManager.SubmitJob(ctx, req), nil
+	resp, err := s.idempotency.Do(ctx, "SubmitJob", func() (interface{}, error) {
+		return s.jobManager.SubmitJob(ctx, req), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.SubmitJobResponse), nil
 }
 
 // QueryJob implements pb.MasterServer.QueryJob
@@ -235,6 +278,26 @@ func (s *Server) QueryJob(ctx context.Context, req *pb.QueryJobRequest) (*pb.Que
 	return s.jobManager.QueryJob(ctx, req), nil
 }
 
+// QueryJobs implements pb.MasterServer.QueryJobs
+func (s *Server) QueryJobs(ctx context.Context, req *pb.QueryJobsRequest) (*pb.QueryJobsResponse, error) {
+	resp2 := &pb.QueryJobsResponse{}
+	shouldRet, err := s.masterRPCHook.PreRPC(ctx, req, &resp2)
+	if shouldRet {
+		return resp2, err
+	}
+	return s.jobManager.QueryJobs(ctx, req), nil
+}
+
+// ListWorkers implements pb.MasterServer.ListWorkers
+func (s *Server) ListWorkers(ctx context.Context, req *pb.ListWorkersRequest) (*pb.ListWorkersResponse, error) {
+	resp2 := &pb.ListWorkersResponse{}
+	shouldRet, err := s.masterRPCHook.PreRPC(ctx, req, &resp2)
+	if shouldRet {
+		return resp2, err
+	}
+	return s.jobManager.ListWorkers(ctx, req), nil
+}
+
 // CancelJob implements pb.MasterServer.CancelJob
 func (s *Server) CancelJob(ctx context.Context, req *pb.CancelJobRequest) (*pb.CancelJobResponse, error) {
 	resp2 := &pb.CancelJobResponse{}
@@ -255,6 +318,194 @@ func (s *Server) PauseJob(ctx context.Context, req *pb.PauseJobRequest) (*pb.Pau
 	return s.jobManager.PauseJob(ctx, req), nil
 }
 
+// UpdateJobTimeouts implements pb.MasterServer.UpdateJobTimeouts
+func (s *Server) UpdateJobTimeouts(
+	ctx context.Context, req *pb.UpdateJobTimeoutsRequest,
+) (*pb.UpdateJobTimeoutsResponse, error) {
+	resp2 := &pb.UpdateJobTimeoutsResponse{}
+	shouldRet, err := s.masterRPCHook.PreRPC(ctx, req, &resp2)
+	if shouldRet {
+		return resp2, err
+	}
+	return s.jobManager.UpdateJobTimeouts(ctx, req), nil
+}
+
+// SetJobLogLevel implements pb.MasterServer.SetJobLogLevel
+func (s *Server) SetJobLogLevel(
+	ctx context.Context, req *pb.SetJobLogLevelRequest,
+) (*pb.SetJobLogLevelResponse, error) {
+	resp2 := &pb.SetJobLogLevelResponse{}
+	shouldRet, err := s.masterRPCHook.PreRPC(ctx, req, &resp2)
+	if shouldRet {
+		return resp2, err
+	}
+	return s.jobManager.SetJobLogLevel(ctx, req), nil
+}
+
+// OperateJobTasks implements pb.MasterServer.OperateJobTasks
+func (s *Server) OperateJobTasks(
+	ctx context.Context, req *pb.OperateJobTasksRequest,
+) (*pb.OperateJobTasksResponse, error) {
+	resp2 := &pb.OperateJobTasksResponse{}
+	shouldRet, err := s.masterRPCHook.PreRPC(ctx, req, &resp2)
+	if shouldRet {
+		return resp2, err
+	}
+	return s.jobManager.OperateJobTasks(ctx, req), nil
+}
+
+// UpdateJobSource implements pb.MasterServer.UpdateJobSource
+func (s *Server) UpdateJobSource(
+	ctx context.Context, req *pb.UpdateJobSourceRequest,
+) (*pb.UpdateJobSourceResponse, error) {
+	resp2 := &pb.UpdateJobSourceResponse{}
+	shouldRet, err := s.masterRPCHook.PreRPC(ctx, req, &resp2)
+	if shouldRet {
+		return resp2, err
+	}
+	return s.jobManager.UpdateJobSource(ctx, req), nil
+}
+
+// CreateJobSchedule implements pb.MasterServer.CreateJobSchedule
+func (s *Server) CreateJobSchedule(
+	ctx context.Context, req *pb.CreateJobScheduleRequest,
+) (*pb.CreateJobScheduleResponse, error) {
+	resp2 := &pb.CreateJobScheduleResponse{}
+	shouldRet, err := s.masterRPCHook.PreRPC(ctx, req, &resp2)
+	if shouldRet {
+		return resp2, err
+	}
+	resp, err := s.idempotency.Do(ctx, "CreateJobSchedule", func() (interface{}, error) {
+		return s.jobManager.CreateJobSchedule(ctx, req), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.CreateJobScheduleResponse), nil
+}
+
+// UpdateJobSchedule implements pb.MasterServer.UpdateJobSchedule
+func (s *Server) UpdateJobSchedule(
+	ctx context.Context, req *pb.UpdateJobScheduleRequest,
+) (*pb.UpdateJobScheduleResponse, error) {
+	resp2 := &pb.UpdateJobScheduleResponse{}
+	shouldRet, err := s.masterRPCHook.PreRPC(ctx, req, &resp2)
+	if shouldRet {
+		return resp2, err
+	}
+	return s.jobManager.UpdateJobSchedule(ctx, req), nil
+}
+
+// DeleteJobSchedule implements pb.MasterServer.DeleteJobSchedule
+func (s *Server) DeleteJobSchedule(
+	ctx context.Context, req *pb.DeleteJobScheduleRequest,
+) (*pb.DeleteJobScheduleResponse, error) {
+	resp2 := &pb.DeleteJobScheduleResponse{}
+	shouldRet, err := s.masterRPCHook.PreRPC(ctx, req, &resp2)
+	if shouldRet {
+		return resp2, err
+	}
+	return s.jobManager.DeleteJobSchedule(ctx, req), nil
+}
+
+// QueryJobSchedules implements pb.MasterServer.QueryJobSchedules
+func (s *Server) QueryJobSchedules(
+	ctx context.Context, req *pb.QueryJobSchedulesRequest,
+) (*pb.QueryJobSchedulesResponse, error) {
+	resp2 := &pb.QueryJobSchedulesResponse{}
+	shouldRet, err := s.masterRPCHook.PreRPC(ctx, req, &resp2)
+	if shouldRet {
+		return resp2, err
+	}
+	return s.jobManager.QueryJobSchedules(ctx, req), nil
+}
+
+// RegisterJobTemplate implements pb.MasterServer.RegisterJobTemplate
+func (s *Server) RegisterJobTemplate(
+	ctx context.Context, req *pb.RegisterJobTemplateRequest,
+) (*pb.RegisterJobTemplateResponse, error) {
+	resp2 := &pb.RegisterJobTemplateResponse{}
+	shouldRet, err := s.masterRPCHook.PreRPC(ctx, req, &resp2)
+	if shouldRet {
+		return resp2, err
+	}
+	return s.jobManager.RegisterJobTemplate(ctx, req), nil
+}
+
+// DeleteJobTemplate implements pb.MasterServer.DeleteJobTemplate
+func (s *Server) DeleteJobTemplate(
+	ctx context.Context, req *pb.DeleteJobTemplateRequest,
+) (*pb.DeleteJobTemplateResponse, error) {
+	resp2 := &pb.DeleteJobTemplateResponse{}
+	shouldRet, err := s.masterRPCHook.PreRPC(ctx, req, &resp2)
+	if shouldRet {
+		return resp2, err
+	}
+	return s.jobManager.DeleteJobTemplate(ctx, req), nil
+}
+
+// QueryJobTemplates implements pb.MasterServer.QueryJobTemplates
+func (s *Server) QueryJobTemplates(
+	ctx context.Context, req *pb.QueryJobTemplatesRequest,
+) (*pb.QueryJobTemplatesResponse, error) {
+	resp2 := &pb.QueryJobTemplatesResponse{}
+	shouldRet, err := s.masterRPCHook.PreRPC(ctx, req, &resp2)
+	if shouldRet {
+		return resp2, err
+	}
+	return s.jobManager.QueryJobTemplates(ctx, req), nil
+}
+
+// PutSecret implements pb.MasterServer.PutSecret
+func (s *Server) PutSecret(
+	ctx context.Context, req *pb.PutSecretRequest,
+) (*pb.PutSecretResponse, error) {
+	resp2 := &pb.PutSecretResponse{}
+	shouldRet, err := s.masterRPCHook.PreRPC(ctx, req, &resp2)
+	if shouldRet {
+		return resp2, err
+	}
+	err = s.secrets.Put(ctx, req.GetProjectId(), req.GetName(), []byte(req.GetValue()))
+	return &pb.PutSecretResponse{Err: derrors.ToPBError(err)}, nil
+}
+
+// DeleteSecret implements pb.MasterServer.DeleteSecret
+func (s *Server) DeleteSecret(
+	ctx context.Context, req *pb.DeleteSecretRequest,
+) (*pb.DeleteSecretResponse, error) {
+	resp2 := &pb.DeleteSecretResponse{}
+	shouldRet, err := s.masterRPCHook.PreRPC(ctx, req, &resp2)
+	if shouldRet {
+		return resp2, err
+	}
+	err = s.secrets.Delete(ctx, req.GetProjectId(), req.GetName())
+	return &pb.DeleteSecretResponse{Err: derrors.ToPBError(err)}, nil
+}
+
+// ListSecrets implements pb.MasterServer.ListSecrets, only the names of the
+// secrets are returned.
+func (s *Server) ListSecrets(
+	ctx context.Context, req *pb.ListSecretsRequest,
+) (*pb.ListSecretsResponse, error) {
+	resp2 := &pb.ListSecretsResponse{}
+	shouldRet, err := s.masterRPCHook.PreRPC(ctx, req, &resp2)
+	if shouldRet {
+		return resp2, err
+	}
+	names, err := s.secrets.List(ctx, req.GetProjectId())
+	if err != nil {
+		return &pb.ListSecretsResponse{Err: derrors.ToPBError(err)}, nil
+	}
+	resp := &pb.ListSecretsResponse{}
+	for _, name := range names {
+		resp.Secrets = append(resp.Secrets, &pb.SecretMeta{
+			ProjectId: req.GetProjectId(),
+			Name:      name,
+		})
+	}
+	return resp, nil
+}
+
 // RegisterExecutor implements grpc interface, and passes request onto executor manager.
 func (s *Server) RegisterExecutor(ctx context.Context, req *pb.RegisterExecutorRequest) (*pb.RegisterExecutorResponse, error) {
 	resp2 := &pb.RegisterExecutorResponse{}
@@ -262,6 +513,20 @@ func (s *Server) RegisterExecutor(ctx context.Context, req *pb.RegisterExecutorR
 	if shouldRet {
 		return resp2, err
 	}
+	// executors can't register while the writes to the framework metastore
+	// are fenced, they retry after the switch
+	if s.metaMigration != nil {
+		unlock, ok := s.metaMigration.lockRegister()
+		if !ok {
+			return &pb.RegisterExecutorResponse{
+				Err: &pb.Error{
+					Code:    pb.ErrorCode_MasterNotReady,
+					Message: "framework metastore is being switched",
+				},
+			}, nil
+		}
+		defer unlock()
+	}
 	// register executor to scheduler
 	// TODO: check leader, if not leader, return notLeader error.
 	execInfo, err := s.executorManager.AllocateNewExec(req)
@@ -272,7 +537,8 @@ func (s *Server) RegisterExecutor(ctx context.Context, req *pb.RegisterExecutorR
 		}, nil
 	}
 	return &pb.RegisterExecutorResponse{
-		ExecutorId: string(execInfo.ID),
+		ExecutorId:             string(execInfo.ID),
+		ClusterProtocolVersion: int32(s.executorManager.ClusterProtocolVersion()),
 	}, nil
 }
 
@@ -286,23 +552,48 @@ func (s *Server) ScheduleTask(ctx context.Context, req *pb.ScheduleTaskRequest)
 	if shouldRet {
 		return resp2, err
 	}
+	return s.scheduleTask(ctx, req)
+}
 
-	schedulerReq := &schedModel.SchedulerRequest{
-		Cost:              schedModel.ResourceUnit(req.GetCost()),
-		ExternalResources: req.GetResourceRequirements(),
+// scheduleTask schedules a task for ScheduleTask and Schedule, the errors
+// are converted to gRPC errors. A queued request waits in the schedule queue
+// if the cluster doesn't have enough resources.
+func (s *Server) scheduleTask(ctx context.Context, req *pb.ScheduleTaskRequest) (*pb.ScheduleTaskResponse, error) {
+	var (
+		resp *pb.ScheduleTaskResponse
+		err  error
+	)
+	if req.GetQueued() {
+		resp, err = s.scheduleQueue.Wait(ctx, req)
+	} else {
+		requirement := newSchedulerRequest(req).Requirement()
+		err = s.pendingQueue.Schedule(ctx, requirement, func() (err error) {
+			resp, err = s.placeTask(ctx, req)
+			return err
+		})
+	}
+	if derrors.ErrUnknownExecutorID.Equal(err) {
+		return nil, status.Error(codes.Internal, err.Error())
 	}
-	schedulerResp, err := s.scheduler.ScheduleTask(ctx, schedulerReq)
 	if err != nil {
 		return nil, schedModel.SchedulerErrorToGRPCError(err)
 	}
+	return resp, nil
+}
+
+// placeTask makes a placement decision for a task without waiting.
+func (s *Server) placeTask(ctx context.Context, req *pb.ScheduleTaskRequest) (*pb.ScheduleTaskResponse, error) {
+	schedulerResp, err := s.scheduler.ScheduleTask(ctx, newSchedulerRequest(req))
+	if err != nil {
+		return nil, err
+	}
 
 	addr, ok := s.executorManager.GetAddr(schedulerResp.ExecutorID)
 	if !ok {
 		log.L().Warn("Executor is gone, RPC call needs retry",
 			zap.Any("request", req),
 			zap.String("executor-id", string(schedulerResp.ExecutorID)))
-		errOut := derrors.ErrUnknownExecutorID.GenWithStackByArgs(string(schedulerResp.ExecutorID))
-		return nil, status.Error(codes.Internal, errOut.Error())
+		return nil, derrors.ErrUnknownExecutorID.GenWithStackByArgs(string(schedulerResp.ExecutorID))
 	}
 
 	return &pb.ScheduleTaskResponse{
@@ -311,6 +602,16 @@ func (s *Server) ScheduleTask(ctx context.Context, req *pb.ScheduleTaskRequest)
 	}, nil
 }
 
+func newSchedulerRequest(req *pb.ScheduleTaskRequest) *schedModel.SchedulerRequest {
+	return &schedModel.SchedulerRequest{
+		Cost:              schedModel.ResourceUnit(req.GetCost()),
+		JobID:             req.GetJobId(),
+		Resources:         req.GetResources(),
+		ExternalResources: req.GetResourceRequirements(),
+		Failover:          req.GetFailover(),
+	}
+}
+
 // DeleteExecutor deletes an executor, but have yet implemented.
 func (s *Server) DeleteExecutor() {
 	// To implement
@@ -374,6 +675,25 @@ func (s *Server) QueryMetaStore(
 	}
 }
 
+// ScaleUpJob implements pb.MasterServer.ScaleUpJob
+func (s *Server) ScaleUpJob(ctx context.Context, req *pb.ScaleUpJobRequest) (*pb.ScaleUpJobResponse, error) {
+	resp2 := &pb.ScaleUpJobResponse{}
+	shouldRet, err := s.masterRPCHook.PreRPC(ctx, req, &resp2)
+	if shouldRet {
+		return resp2, err
+	}
+
+	result, err := s.scheduler.ScaleUpJob(
+		req.GetJobId(), int(req.GetCurrentWorkers()), int(req.GetWorkers()))
+	if err != nil {
+		return &pb.ScaleUpJobResponse{Err: derrors.ToPBError(err)}, nil
+	}
+	return &pb.ScaleUpJobResponse{
+		GrantedWorkers: int32(result.GrantedWorkers),
+		BackoffMs:      result.Backoff.Milliseconds(),
+	}, nil
+}
+
 // ReportExecutorWorkload implements pb.MasterServer.ReportExecutorWorkload
 func (s *Server) ReportExecutorWorkload(
 	ctx context.Context, req *pb.ExecWorkloadRequest,
@@ -426,6 +746,29 @@ func (s *Server) Stop() {
 	if s.userMetaKVClient != nil {
 		s.userMetaKVClient.Close()
 	}
+	if s.sinkExporter != nil {
+		if err := s.sinkExporter.Close(); err != nil {
+			log.L().Warn("failed to close sink exporter", zap.Error(err))
+		}
+	}
+	if s.pendingQueue != nil {
+		if err := s.pendingQueue.Close(); err != nil {
+			log.L().Warn("failed to close autoscaler", zap.Error(err))
+		}
+	}
+	s.eventBus.Close()
+}
+
+// LeaderInitialized returns whether this server master is the leader and
+// has finished initializing leader services.
+func (s *Server) LeaderInitialized() bool {
+	return s.leaderInitialized.Load()
+}
+
+// HasExecutor returns whether the executor is registered and not yet
+// removed for heartbeat timeout.
+func (s *Server) HasExecutor(executorID model.ExecutorID) bool {
+	return s.executorManager.HasExecutor(string(executorID))
 }
 
 // Run the server master.
@@ -435,6 +778,7 @@ func (s *Server) Run(ctx context.Context) (err error) {
 	}
 
 	registerMetrics()
+	lockdiag.SetLongHoldThreshold(s.cfg.DebugLockHoldThreshold)
 
 	err = s.registerMetaStore()
 	if err != nil {
@@ -451,13 +795,58 @@ func (s *Server) Run(ctx context.Context) (err error) {
 	if err != nil {
 		return
 	}
+	// the state of the metastore migration is persisted in the embedded etcd
+	s.metaMigration.store = migration.NewEtcdStateStore(s.etcdClient)
 	err = s.reset(ctx)
 	if err != nil {
 		return
 	}
 
+	// discoveryKeeper is created before the leader loop, since the leader
+	// subscribes to executor changes from it.
+	s.discoveryKeeper = serverutils.NewDiscoveryKeepaliver(
+		s.info, s.etcdClient, int(defaultSessionTTL/time.Second),
+		defaultDiscoverTicker, s.p2pMsgRouter,
+	)
+	s.discoveryKeeper.AddListener(serverutils.PeerCodecsListener(s.peerCodecs))
+
 	wg, ctx := errgroup.WithContext(ctx)
 
+	if s.cfg.Sink.Enabled() {
+		s.sinkExporter, err = sink.NewExporter(&s.cfg.Sink)
+		if err != nil {
+			return err
+		}
+		wg.Go(func() error {
+			return s.sinkExporter.Run(ctx)
+		})
+		unsubscribe := s.sinkExporter.SubscribeWorkerEvents(s.eventBus)
+		defer unsubscribe()
+	}
+
+	if s.cfg.Autoscaler.Enabled() {
+		var scaler autoscaler.Autoscaler
+		scaler, err = autoscaler.New(&s.cfg.Autoscaler)
+		if err != nil {
+			return err
+		}
+		s.pendingQueue = autoscaler.NewPendingQueue(scaler, &s.cfg.Autoscaler)
+		// wakes up the pending requests to retry scheduling
+		unsubscribe := eventbus.Subscribe(s.eventBus, func(eventbus.ExecutorJoined) {
+			s.pendingQueue.OnExecutorRegistered()
+		})
+		defer unsubscribe()
+		wg.Go(func() error {
+			return s.pendingQueue.Run(ctx)
+		})
+	}
+
+	if s.cfg.AdminAddr != "" {
+		wg.Go(func() error {
+			return adminserver.Serve(ctx, s.cfg.AdminAddr, adminserver.NewHandler(s.dumpMaster))
+		})
+	}
+
 	wg.Go(func() error {
 		return s.msgService.GetMessageServer().Run(ctx)
 	})
@@ -470,10 +859,6 @@ func (s *Server) Run(ctx context.Context) (err error) {
 		return s.memberLoop(ctx)
 	})
 
-	s.discoveryKeeper = serverutils.NewDiscoveryKeepaliver(
-		s.info, s.etcdClient, int(defaultSessionTTL/time.Second),
-		defaultDiscoverTicker, s.p2pMsgRouter,
-	)
 	wg.Go(func() error {
 		return s.discoveryKeeper.Keepalive(ctx)
 	})
@@ -487,14 +872,22 @@ func (s *Server) registerMetaStore() error {
 	if err := s.metaStoreManager.Register(cfg.FrameMetaConf.StoreID, cfg.FrameMetaConf); err != nil {
 		return err
 	}
-	var err error
 	// TODO: replace default db config
-	if s.frameMetaClient, err = pkgOrm.NewClient(*cfg.FrameMetaConf, pkgOrm.NewDefaultDBConfig()); err != nil {
-		log.L().Error("connect to framework metastore fail", zap.Any("config", cfg.FrameMetaConf), zap.Error(err))
+	frameMetaClient, err := pkgOrm.NewClient(*cfg.FrameMetaConf, pkgOrm.NewDefaultDBConfig())
+	if err != nil {
+		log.L().Error("connect to framework metastore fail", redact.Any("config", cfg.FrameMetaConf), zap.Error(err))
 		return err
 	}
+	migrator := migration.NewMigrator(frameMetaClient)
+	s.frameMetaClient = migrator.Client()
+	s.metaMigration = newMetaMigration(migrator, s.metaStoreManager, s.executorManager)
+	kms, err := secret.NewKMS(&cfg.Secret)
+	if err != nil {
+		return err
+	}
+	s.secrets = secret.NewStore(s.frameMetaClient, kms)
 
-	log.L().Info("register framework metastore successfully", zap.Any("metastore", cfg.FrameMetaConf))
+	log.L().Info("register framework metastore successfully", redact.Any("metastore", cfg.FrameMetaConf))
 
 	// register metastore for user
 	err = s.metaStoreManager.Register(cfg.UserMetaConf.StoreID, cfg.UserMetaConf)
@@ -502,10 +895,10 @@ func (s *Server) registerMetaStore() error {
 		return err
 	}
 	if s.userMetaKVClient, err = kvclient.NewKVClient(cfg.UserMetaConf); err != nil {
-		log.L().Error("connect to user metastore fail", zap.Any("config", cfg.UserMetaConf), zap.Error(err))
+		log.L().Error("connect to user metastore fail", redact.Any("config", cfg.UserMetaConf), zap.Error(err))
 		return err
 	}
-	log.L().Info("register user metastore successfully", zap.Any("metastore", cfg.UserMetaConf))
+	log.L().Info("register user metastore successfully", redact.Any("metastore", cfg.UserMetaConf))
 
 	return nil
 }
@@ -523,7 +916,8 @@ func (s *Server) startResourceManager() error {
 		s.executorManager,
 		resourceRPCHook,
 	)
-	s.scheduler = makeScheduler(s.executorManager, s.resourceManagerService)
+	s.scheduler = makeScheduler(s.cfg, s.executorManager, s.resourceManagerService)
+	s.scheduleQueue = newScheduleQueue(&s.cfg.ScheduleQueue, s.frameMetaClient, s.placeTask, clock.New())
 	return nil
 }
 
@@ -548,16 +942,21 @@ func (s *Server) startGrpcSrv(ctx context.Context) (err error) {
 		return
 	}
 
+	// the gRPC server is created by the embedded etcd, so interceptors are
+	// added when registering the services.
+	unaryInterceptor := interceptor.NewUnaryServerInterceptor(&s.cfg.GRPCServer)
 	gRPCSvr := func(gs *grpc.Server) {
-		pb.RegisterMasterServer(gs, s)
-		pb.RegisterResourceManagerServer(gs, s.resourceManagerService)
+		interceptor.RegisterService(gs, pb.MasterServiceDesc, s, unaryInterceptor)
+		interceptor.RegisterService(gs, pb.ResourceManagerServiceDesc, s.resourceManagerService, unaryInterceptor)
 		s.msgService = p2p.NewMessageRPCServiceWithRPCServer(s.name(), nil, gs)
+		s.msgService.SetMessageConfig(&s.cfg.P2PMessage)
 		p2pProtocol.RegisterCDCPeerToPeerServer(gs, s.msgService.GetMessageServer())
 	}
 
 	httpHandlers := map[string]http.Handler{
 		"/debug/":  getDebugHandler(),
 		"/metrics": promhttp.Handler(),
+		"/healthz": http.HandlerFunc(s.healthzHandler),
 	}
 
 	// generate grpcServer
@@ -636,12 +1035,30 @@ func (s *Server) initializedBackendMeta(ctx context.Context) error {
 }
 
 func (s *Server) runLeaderService(ctx context.Context) (err error) {
+	// the framework metastore may have been switched by the previous leader
+	err = s.metaMigration.recover(ctx)
+	if err != nil {
+		return
+	}
+
 	// leader master need Initialize all backend tables first
 	err = s.initializedBackendMeta(ctx)
 	if err != nil {
 		return
 	}
 
+	// the cordons are loaded before the executors, so that blacklisted
+	// executors are not registered again
+	err = s.loadExecutorCordons(ctx)
+	if err != nil {
+		return
+	}
+	// the queued requests keep their places when they are sent again
+	err = s.scheduleQueue.load(ctx)
+	if err != nil {
+		return
+	}
+
 	// rebuild states from existing meta if needed
 	err = s.resetExecutor(ctx)
 	if err != nil {
@@ -654,11 +1071,51 @@ func (s *Server) runLeaderService(ctx context.Context) (err error) {
 	defer func() {
 		s.resourceManagerService.Stop()
 	}()
+	// the resources on the offline executors are cleaned up by the resource
+	// manager
+	unsubscribe := eventbus.Subscribe(s.eventBus, func(event eventbus.ExecutorOffline) {
+		if err := s.resourceManagerService.OnExecutorOffline(model.ExecutorID(event.ExecutorID)); err != nil {
+			log.L().Warn("failed to handle offline executor in resource manager",
+				zap.String("executor-id", event.ExecutorID), zap.Error(err))
+		}
+	})
+	defer unsubscribe()
+	// the queued schedule requests are retried when executors join or
+	// workers exit, in addition to the periodic retries
+	unsubscribeJoined := eventbus.Subscribe(s.eventBus, func(eventbus.ExecutorJoined) {
+		s.scheduleQueue.notify()
+	})
+	defer unsubscribeJoined()
+	unsubscribeWorkerOffline := eventbus.Subscribe(s.eventBus, func(eventbus.WorkerOffline) {
+		s.scheduleQueue.notify()
+	})
+	defer unsubscribeWorkerOffline()
+	var queueWg sync.WaitGroup
+	queueCtx, cancelQueue := context.WithCancel(ctx)
+	queueWg.Add(1)
+	go func() {
+		defer queueWg.Done()
+		s.scheduleQueue.Run(queueCtx)
+	}()
+	// the queue is reset before the next term loads it again
+	defer func() {
+		cancelQueue()
+		queueWg.Wait()
+	}()
 	clients := client.NewClientManager()
 	err = clients.AddMasterClient(ctx, []string{s.cfg.MasterAddr})
 	if err != nil {
 		return
 	}
+	// invalidate the cached executor clients when executors move or
+	// register again, so that job masters are not dispatched to stale
+	// addresses.
+	if s.discoveryKeeper != nil {
+		removeListener := s.discoveryKeeper.AddListener(clients.HandleDiscoveryEvent)
+		defer removeListener()
+	}
+	// evicts idle executor clients and breaks the circuit to broken executors
+	go clients.Run(ctx)
 	dctx := dcontext.NewContext(ctx, log.L())
 	dctx.Environ.Addr = s.cfg.AdvertiseAddr
 	dctx.Environ.NodeID = s.name()
@@ -675,9 +1132,16 @@ func (s *Server) runLeaderService(ctx context.Context) (err error) {
 	}
 	dctx.Environ.MasterMetaBytes = masterMetaBytes
 
+	// writes of the job manager are fenced by the leadership, so that the
+	// writes in flight are rejected after a new leader takes over.
+	metaClient := s.frameMetaClient
+	if token, ok := cluster.FencingToken(ctx); ok {
+		metaClient = metaClient.WithFencingToken(token)
+	}
+
 	dp := deps.NewDeps()
 	if err := dp.Provide(func() pkgOrm.Client {
-		return s.frameMetaClient
+		return metaClient
 	}); err != nil {
 		return err
 	}
@@ -701,7 +1165,7 @@ func (s *Server) runLeaderService(ctx context.Context) (err error) {
 	}
 
 	if err := dp.Provide(func() p2p.MessageSender {
-		return p2p.NewMessageSender(s.p2pMsgRouter)
+		return p2p.NewMessageSenderWithCodecs(s.p2pMsgRouter, &s.cfg.P2PMessage, s.peerCodecs)
 	}); err != nil {
 		return err
 	}
@@ -712,6 +1176,52 @@ func (s *Server) runLeaderService(ctx context.Context) (err error) {
 		return err
 	}
 
+	if s.scheduler != nil {
+		if err := dp.Provide(func() JobReserver {
+			return s.scheduler
+		}); err != nil {
+			return err
+		}
+	}
+
+	if s.sinkExporter != nil {
+		if err := dp.Provide(func() *sink.Exporter {
+			return s.sinkExporter
+		}); err != nil {
+			return err
+		}
+	}
+
+	if s.cfg.JobRetention.Enabled() {
+		if err := dp.Provide(func() *JobRetentionConfig {
+			return &s.cfg.JobRetention
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := dp.Provide(func() *eventbus.Bus {
+		return s.eventBus
+	}); err != nil {
+		return err
+	}
+
+	if keyring := s.cfg.UserMetaEncryption.Keyring(); keyring != nil {
+		if err := dp.Provide(func() *encryption.Keyring {
+			return keyring
+		}); err != nil {
+			return err
+		}
+	}
+
+	if s.testCtx != nil && s.testCtx.FaultInjector() != nil {
+		if err := dp.Provide(func() *faultinject.Injector {
+			return s.testCtx.FaultInjector()
+		}); err != nil {
+			return err
+		}
+	}
+
 	s.leader.Store(&Member{
 		Name:          s.name(),
 		IsServLeader:  true,
@@ -736,6 +1246,11 @@ func (s *Server) runLeaderService(ctx context.Context) (err error) {
 		}
 	}()
 	s.leaderInitialized.Store(true)
+	eventbus.Publish(s.eventBus, eventbus.LeaderElected{
+		Time:   time.Now(),
+		NodeID: s.name(),
+		Addr:   s.cfg.AdvertiseAddr,
+	})
 
 	metricTicker := time.NewTicker(defaultMetricInterval)
 	defer metricTicker.Stop()
@@ -805,11 +1320,13 @@ func (s *Server) collectLeaderMetric() {
 // This function makes it clear how a Scheduler is supposed to be constructed
 // using concrete type, from the perspective of Server.
 func makeScheduler(
+	cfg *Config,
 	executorManager ExecutorManager,
 	externalResourceManager *externRescManager.Service,
 ) *scheduler.Scheduler {
 	return scheduler.NewScheduler(
 		executorManager.CapacityProvider(),
 		externalResourceManager,
+		scheduler.WithHeadroomPercent(cfg.SchedulerHeadroomPercent),
 	)
 }
@@ -15,6 +15,7 @@ import (
 	libModel "github.com/hanfei1991/microcosm/lib/model"
 	"github.com/hanfei1991/microcosm/model"
 	"github.com/hanfei1991/microcosm/pb"
+	"github.com/hanfei1991/microcosm/pkg/compat"
 	"github.com/hanfei1991/microcosm/servermaster/scheduler"
 
 	"github.com/phayes/freeport"
@@ -233,6 +234,10 @@ func (m *mockJobManager) JobCount(status pb.QueryJobResponse_JobStatus) int {
 	return m.jobs[status]
 }
 
+func (m *mockJobManager) ErrorJobs() []libModel.MasterID {
+	panic("not implemented")
+}
+
 func (m *mockJobManager) SubmitJob(ctx context.Context, req *pb.SubmitJobRequest) *pb.SubmitJobResponse {
 	panic("not implemented")
 }
@@ -241,6 +246,14 @@ func (m *mockJobManager) QueryJob(ctx context.Context, req *pb.QueryJobRequest)
 	panic("not implemented")
 }
 
+func (m *mockJobManager) QueryJobs(ctx context.Context, req *pb.QueryJobsRequest) *pb.QueryJobsResponse {
+	panic("not implemented")
+}
+
+func (m *mockJobManager) ListWorkers(ctx context.Context, req *pb.ListWorkersRequest) *pb.ListWorkersResponse {
+	panic("not implemented")
+}
+
 func (m *mockJobManager) CancelJob(ctx context.Context, req *pb.CancelJobRequest) *pb.CancelJobResponse {
 	panic("not implemented")
 }
@@ -249,6 +262,54 @@ func (m *mockJobManager) PauseJob(ctx context.Context, req *pb.PauseJobRequest)
 	panic("not implemented")
 }
 
+func (m *mockJobManager) UpdateJobTimeouts(ctx context.Context, req *pb.UpdateJobTimeoutsRequest) *pb.UpdateJobTimeoutsResponse {
+	panic("not implemented")
+}
+
+func (m *mockJobManager) SetJobLogLevel(ctx context.Context, req *pb.SetJobLogLevelRequest) *pb.SetJobLogLevelResponse {
+	panic("not implemented")
+}
+
+func (m *mockJobManager) OperateJobTasks(ctx context.Context, req *pb.OperateJobTasksRequest) *pb.OperateJobTasksResponse {
+	panic("not implemented")
+}
+
+func (m *mockJobManager) UpdateJobSource(ctx context.Context, req *pb.UpdateJobSourceRequest) *pb.UpdateJobSourceResponse {
+	panic("not implemented")
+}
+
+func (m *mockJobManager) CreateJobSchedule(ctx context.Context, req *pb.CreateJobScheduleRequest) *pb.CreateJobScheduleResponse {
+	panic("not implemented")
+}
+
+func (m *mockJobManager) UpdateJobSchedule(ctx context.Context, req *pb.UpdateJobScheduleRequest) *pb.UpdateJobScheduleResponse {
+	panic("not implemented")
+}
+
+func (m *mockJobManager) DeleteJobSchedule(ctx context.Context, req *pb.DeleteJobScheduleRequest) *pb.DeleteJobScheduleResponse {
+	panic("not implemented")
+}
+
+func (m *mockJobManager) QueryJobSchedules(ctx context.Context, req *pb.QueryJobSchedulesRequest) *pb.QueryJobSchedulesResponse {
+	panic("not implemented")
+}
+
+func (m *mockJobManager) RegisterJobTemplate(ctx context.Context, req *pb.RegisterJobTemplateRequest) *pb.RegisterJobTemplateResponse {
+	panic("not implemented")
+}
+
+func (m *mockJobManager) DeleteJobTemplate(ctx context.Context, req *pb.DeleteJobTemplateRequest) *pb.DeleteJobTemplateResponse {
+	panic("not implemented")
+}
+
+func (m *mockJobManager) QueryJobTemplates(ctx context.Context, req *pb.QueryJobTemplatesRequest) *pb.QueryJobTemplatesResponse {
+	panic("not implemented")
+}
+
+func (m *mockJobManager) DeleteJob(ctx context.Context, jobID libModel.MasterID, force bool) error {
+	panic("not implemented")
+}
+
 func (m *mockJobManager) GetJobStatuses(ctx context.Context) (map[libModel.MasterID]libModel.MasterStatusCode, error) {
 	panic("not implemented")
 }
@@ -290,6 +351,26 @@ func (m *mockExecutorManager) ListExecutors() []string {
 	panic("not implemented")
 }
 
+func (m *mockExecutorManager) ClusterProtocolVersion() compat.ProtocolVersion {
+	panic("not implemented")
+}
+
+func (m *mockExecutorManager) CordonExecutor(executorID model.ExecutorID, state model.CordonState) {
+	panic("not implemented")
+}
+
+func (m *mockExecutorManager) ResetCordons(cordons map[model.ExecutorID]model.CordonState) {
+	panic("not implemented")
+}
+
+func (m *mockExecutorManager) Cordons() map[model.ExecutorID]model.CordonState {
+	panic("not implemented")
+}
+
+func (m *mockExecutorManager) StaleExecutors() []model.ExecutorID {
+	panic("not implemented")
+}
+
 func (m *mockExecutorManager) ExecutorCount(status model.ExecutorStatus) int {
 	m.executorMu.RLock()
 	defer m.executorMu.RUnlock()
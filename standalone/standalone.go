@@ -0,0 +1,108 @@
+package standalone
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/hanfei1991/microcosm/executor"
+	"github.com/hanfei1991/microcosm/servermaster"
+)
+
+const waitMasterTick = 200 * time.Millisecond
+
+// Cluster runs a server master and an executor in a single process, it is
+// used for local development and demos only.
+type Cluster struct {
+	masterCfg *servermaster.Config
+	execCfg   *executor.Config
+
+	master *servermaster.Server
+	exec   *executor.Server
+}
+
+// NewCluster creates a standalone cluster from the config of server master,
+// the executor joins the server master with default configurations.
+func NewCluster(cfg *servermaster.Config) (*Cluster, error) {
+	execCfg := executor.NewConfig()
+	err := execCfg.Parse([]string{
+		"--join", cfg.AdvertiseAddr,
+		"--worker-addr", cfg.StandaloneExecutorAddr,
+		"--name", cfg.Etcd.Name + "-executor",
+		"-L", cfg.LogLevel,
+		// the threshold is global, keep the one of server master
+		"--debug-lock-hold-threshold", cfg.DebugLockHoldThresholdStr,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	master, err := servermaster.NewServer(cfg, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cluster{
+		masterCfg: cfg,
+		execCfg:   execCfg,
+		master:    master,
+		exec:      executor.NewServer(execCfg, nil),
+	}, nil
+}
+
+// GracefulShutdown drains the executor, and the cluster exits after the
+// executor exits.
+func (c *Cluster) GracefulShutdown() {
+	c.exec.GracefulShutdown()
+}
+
+// Run runs the server master, then runs the executor after the server master
+// becomes ready. It returns when either of them exits.
+func (c *Cluster) Run(ctx context.Context) error {
+	defer c.master.Stop()
+	defer c.exec.Stop()
+
+	wg, wgCtx := errgroup.WithContext(ctx)
+	wg.Go(func() error {
+		return c.master.Run(wgCtx)
+	})
+	wg.Go(func() error {
+		if err := c.waitMasterReady(wgCtx); err != nil {
+			return err
+		}
+		log.L().Info("standalone server master is ready, start executor",
+			zap.String("master-addr", c.masterCfg.AdvertiseAddr),
+			zap.String("executor-addr", c.execCfg.WorkerAddr))
+		if err := c.exec.Run(wgCtx); err != nil {
+			return err
+		}
+		// the executor exits normally after a graceful shutdown, stop the
+		// whole process then.
+		return context.Canceled
+	})
+	err := wg.Wait()
+	if ctx.Err() != nil {
+		// the servers may exit with errors like closed listeners after the
+		// cluster is canceled, which are not failures.
+		return ctx.Err()
+	}
+	return err
+}
+
+func (c *Cluster) waitMasterReady(ctx context.Context) error {
+	ticker := time.NewTicker(waitMasterTick)
+	defer ticker.Stop()
+	for {
+		if c.master.LeaderInitialized() {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package standalone
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/phayes/freeport"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hanfei1991/microcosm/pb"
+	"github.com/hanfei1991/microcosm/pkg/meta/metaclient"
+	"github.com/hanfei1991/microcosm/servermaster"
+)
+
+func init() {
+	err := log.InitLogger(&log.Config{Level: "warn"})
+	if err != nil {
+		panic(err)
+	}
+}
+
+func TestRunStandaloneCluster(t *testing.T) {
+	ports, err := freeport.GetFreePorts(3)
+	require.Nil(t, err)
+	cfg := servermaster.NewConfig()
+	err = cfg.Parse([]string{
+		"--standalone",
+		"--data-dir", t.TempDir(),
+		"--master-addr", fmt.Sprintf("127.0.0.1:%d", ports[0]),
+		"--standalone-executor-addr", fmt.Sprintf("127.0.0.1:%d", ports[1]),
+		"--peer-urls", fmt.Sprintf("http://127.0.0.1:%d", ports[2]),
+		"-L", "warn",
+	})
+	require.Nil(t, err)
+	require.Equal(t, metaclient.StoreTypeSQLite, cfg.FrameMetaConf.StoreType)
+
+	cluster, err := NewCluster(cfg)
+	require.Nil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- cluster.Run(ctx)
+	}()
+
+	// the framework metastore is a SQLite file in standalone mode, and the
+	// executor registers to the server master in the same process.
+	require.Eventually(t, func() bool {
+		select {
+		case err := <-errCh:
+			require.FailNow(t, "standalone cluster exits unexpectedly", "%v", err)
+		default:
+		}
+		if !cluster.master.LeaderInitialized() {
+			return false
+		}
+		resp, err := cluster.master.ClusterHealth(ctx, &pb.ClusterHealthRequest{})
+		require.Nil(t, err)
+		return resp.MetastoreReachable && resp.Executors == 1
+	}, 30*time.Second, 100*time.Millisecond)
+
+	cancel()
+	select {
+	case err := <-errCh:
+		require.Equal(t, context.Canceled, errors.Cause(err))
+	case <-time.After(30 * time.Second):
+		require.FailNow(t, "standalone cluster doesn't exit after canceled")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/hanfei1991/microcosm/pkg/faultinject"
 	"github.com/hanfei1991/microcosm/pkg/metadata"
 )
 
@@ -26,6 +27,7 @@ type Context struct {
 	executorChangeCh chan *ExecutorChangeEvent
 	dataCh           chan interface{}
 	metaKV           metadata.MetaKV
+	faultInjector    *faultinject.Injector
 }
 
 // NewContext creates a new Context instance
@@ -49,6 +51,17 @@ func (c *Context) GetMetaKV() metadata.MetaKV {
 	return c.metaKV
 }
 
+// SetFaultInjector sets the fault injector provided to the masters and
+// workers running in the server
+func (c *Context) SetFaultInjector(injector *faultinject.Injector) {
+	c.faultInjector = injector
+}
+
+// FaultInjector returns the fault injector, nil means no fault is injected
+func (c *Context) FaultInjector() *faultinject.Injector {
+	return c.faultInjector
+}
+
 // ExecutorChange returns the notify channel of executor change
 func (c *Context) ExecutorChange() <-chan *ExecutorChangeEvent {
 	return c.executorChangeCh
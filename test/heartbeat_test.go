@@ -4,9 +4,7 @@ import (
 	"testing"
 	"time"
 
-	"github.com/hanfei1991/microcosm/executor"
-	"github.com/hanfei1991/microcosm/pkg/etcdutils"
-	"github.com/hanfei1991/microcosm/servermaster"
+	"github.com/hanfei1991/microcosm/model"
 	"github.com/hanfei1991/microcosm/test"
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tiflow/dm/pkg/log"
@@ -41,41 +39,58 @@ func (t *testHeartbeatSuite) SetUpSuite(c *C) {
 }
 
 func (t *testHeartbeatSuite) TestHeartbeatExecutorCrush(c *C) {
-	masterCfg := &servermaster.Config{
-		Etcd: &etcdutils.ConfigParams{
-			Name:    "master1",
-			DataDir: "/tmp/df",
-		},
-		MasterAddr:        "127.0.0.1:1991",
+	cluster, err := NewMiniCluster(MiniClusterConfig{
 		KeepAliveTTL:      t.keepAliveTTL,
 		KeepAliveInterval: t.keepAliveInterval,
 		RPCTimeout:        t.rpcTimeout,
-	}
-	// one master + one executor
-	executorCfg := &executor.Config{
-		Join:              "127.0.0.1:1991",
-		WorkerAddr:        "127.0.0.1:1992",
+	})
+	c.Assert(err, IsNil)
+	masterCtx, err := cluster.StartMaster()
+	c.Assert(err, IsNil)
+	executorID, executorCtx, err := cluster.AddExecutor("")
+	c.Assert(err, IsNil)
+
+	err = cluster.RemoveExecutor(executorID)
+	c.Assert(err, IsNil)
+
+	executorEvent := <-executorCtx.ExecutorChange()
+	masterEvent := <-masterCtx.ExecutorChange()
+	c.Assert(executorEvent.Time.Add(t.keepAliveTTL), Less, masterEvent.Time)
+	cluster.StopCluster()
+}
+
+func (t *testHeartbeatSuite) TestScaleExecutors(c *C) {
+	cluster, err := NewMiniCluster(MiniClusterConfig{
 		KeepAliveTTL:      t.keepAliveTTL,
 		KeepAliveInterval: t.keepAliveInterval,
 		RPCTimeout:        t.rpcTimeout,
+	})
+	c.Assert(err, IsNil)
+	_, err = cluster.StartMaster()
+	c.Assert(err, IsNil)
+
+	var executorIDs []model.ExecutorID
+	for i := 0; i < 3; i++ {
+		id, _, err := cluster.AddExecutor("")
+		c.Assert(err, IsNil)
+		executorIDs = append(executorIDs, id)
 	}
+	c.Assert(cluster.ExecutorIDs(), HasLen, 3)
 
-	cluster := new(MiniCluster)
-	masterCtx, err := cluster.CreateMaster(masterCfg)
+	// one executor fails, the others are kept alive by heartbeats
+	err = cluster.RemoveExecutor(executorIDs[0])
 	c.Assert(err, IsNil)
-	executorCtx := cluster.CreateExecutor(executorCfg)
-	// Start cluster
-	err = cluster.AsyncStartMaster()
+	err = cluster.WaitExecutorRemoved(executorIDs[0])
 	c.Assert(err, IsNil)
+	c.Assert(cluster.ExecutorIDs(), HasLen, 2)
+	for _, id := range executorIDs[1:] {
+		err = cluster.WaitExecutorReady(id)
+		c.Assert(err, IsNil)
+	}
 
-	err = cluster.AsyncStartExector()
+	// scale out again
+	_, _, err = cluster.AddExecutor("")
 	c.Assert(err, IsNil)
-
-	time.Sleep(2 * time.Second)
-	cluster.StopExec()
-
-	executorEvent := <-executorCtx.ExecutorChange()
-	masterEvent := <-masterCtx.ExecutorChange()
-	c.Assert(executorEvent.Time.Add(t.keepAliveTTL), Less, masterEvent.Time)
-	cluster.StopMaster()
+	c.Assert(cluster.ExecutorIDs(), HasLen, 3)
+	cluster.StopCluster()
 }
@@ -90,6 +90,20 @@ func (mr *MockDiscoveryRunnerMockRecorder) ResetDiscovery(ctx, resetSession inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetDiscovery", reflect.TypeOf((*MockDiscoveryRunner)(nil).ResetDiscovery), ctx, resetSession)
 }
 
+// UpdateValue mocks base method.
+func (m *MockDiscoveryRunner) UpdateValue(ctx context.Context, value string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateValue", ctx, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateValue indicates an expected call of UpdateValue.
+func (mr *MockDiscoveryRunnerMockRecorder) UpdateValue(ctx, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateValue", reflect.TypeOf((*MockDiscoveryRunner)(nil).UpdateValue), ctx, value)
+}
+
 // MockSession is a mock of Session interface.
 type MockSession struct {
 	ctrl     *gomock.Controller
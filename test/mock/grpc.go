@@ -8,6 +8,8 @@ import (
 	"github.com/pingcap/tiflow/dm/pkg/log"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/hanfei1991/microcosm/pb"
 )
@@ -58,6 +60,48 @@ func (s *masterServerConn) sendRequest(ctx context.Context, req interface{}) (in
 		return s.server.Heartbeat(ctx, x)
 	case *pb.CancelJobRequest:
 		return s.server.CancelJob(ctx, x)
+	case *pb.UpdateJobTimeoutsRequest:
+		return s.server.UpdateJobTimeouts(ctx, x)
+	case *pb.SetJobLogLevelRequest:
+		return s.server.SetJobLogLevel(ctx, x)
+	case *pb.OperateJobTasksRequest:
+		return s.server.OperateJobTasks(ctx, x)
+	case *pb.UpdateJobSourceRequest:
+		return s.server.UpdateJobSource(ctx, x)
+	case *pb.CreateJobScheduleRequest:
+		return s.server.CreateJobSchedule(ctx, x)
+	case *pb.UpdateJobScheduleRequest:
+		return s.server.UpdateJobSchedule(ctx, x)
+	case *pb.DeleteJobScheduleRequest:
+		return s.server.DeleteJobSchedule(ctx, x)
+	case *pb.QueryJobSchedulesRequest:
+		return s.server.QueryJobSchedules(ctx, x)
+	case *pb.RegisterJobTemplateRequest:
+		return s.server.RegisterJobTemplate(ctx, x)
+	case *pb.DeleteJobTemplateRequest:
+		return s.server.DeleteJobTemplate(ctx, x)
+	case *pb.QueryJobTemplatesRequest:
+		return s.server.QueryJobTemplates(ctx, x)
+	case *pb.PutSecretRequest:
+		return s.server.PutSecret(ctx, x)
+	case *pb.DeleteSecretRequest:
+		return s.server.DeleteSecret(ctx, x)
+	case *pb.ListSecretsRequest:
+		return s.server.ListSecrets(ctx, x)
+	case *pb.ScaleUpJobRequest:
+		return s.server.ScaleUpJob(ctx, x)
+	case *pb.MigrateMetaStoreRequest:
+		return s.server.MigrateMetaStore(ctx, x)
+	case *pb.QueryJobsRequest:
+		return s.server.QueryJobs(ctx, x)
+	case *pb.ListWorkersRequest:
+		return s.server.ListWorkers(ctx, x)
+	case *pb.CordonExecutorRequest:
+		return s.server.CordonExecutor(ctx, x)
+	case *pb.QueryExecutorCordonsRequest:
+		return s.server.QueryExecutorCordons(ctx, x)
+	case *pb.ClusterHealthRequest:
+		return s.server.ClusterHealth(ctx, x)
 	}
 	return nil, errors.New("unknown request")
 }
@@ -71,6 +115,18 @@ func (c *masterServerClient) ScheduleTask(ctx context.Context, req *pb.ScheduleT
 	return resp.(*pb.ScheduleTaskResponse), err
 }
 
+func (c *masterServerClient) Schedule(ctx context.Context, opts ...grpc.CallOption) (pb.Master_ScheduleClient, error) {
+	return nil, status.Error(codes.Unimplemented, "schedule stream is not supported by mock grpc")
+}
+
+func (c *masterServerClient) ScaleUpJob(ctx context.Context, req *pb.ScaleUpJobRequest, opts ...grpc.CallOption) (*pb.ScaleUpJobResponse, error) {
+	resp, err := c.conn.sendRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.ScaleUpJobResponse), nil
+}
+
 func (c *masterServerClient) RegisterExecutor(ctx context.Context, req *pb.RegisterExecutorRequest, opts ...grpc.CallOption) (*pb.RegisterExecutorResponse, error) {
 	resp, err := c.conn.sendRequest(ctx, req)
 	return resp.(*pb.RegisterExecutorResponse), err
@@ -86,6 +142,76 @@ func (c *masterServerClient) SubmitJob(ctx context.Context, req *pb.SubmitJobReq
 	return resp.(*pb.SubmitJobResponse), err
 }
 
+func (c *masterServerClient) UpdateJobTimeouts(ctx context.Context, req *pb.UpdateJobTimeoutsRequest, opts ...grpc.CallOption) (*pb.UpdateJobTimeoutsResponse, error) {
+	resp, err := c.conn.sendRequest(ctx, req)
+	return resp.(*pb.UpdateJobTimeoutsResponse), err
+}
+
+func (c *masterServerClient) SetJobLogLevel(ctx context.Context, req *pb.SetJobLogLevelRequest, opts ...grpc.CallOption) (*pb.SetJobLogLevelResponse, error) {
+	resp, err := c.conn.sendRequest(ctx, req)
+	return resp.(*pb.SetJobLogLevelResponse), err
+}
+
+func (c *masterServerClient) OperateJobTasks(ctx context.Context, req *pb.OperateJobTasksRequest, opts ...grpc.CallOption) (*pb.OperateJobTasksResponse, error) {
+	resp, err := c.conn.sendRequest(ctx, req)
+	return resp.(*pb.OperateJobTasksResponse), err
+}
+
+func (c *masterServerClient) UpdateJobSource(ctx context.Context, req *pb.UpdateJobSourceRequest, opts ...grpc.CallOption) (*pb.UpdateJobSourceResponse, error) {
+	resp, err := c.conn.sendRequest(ctx, req)
+	return resp.(*pb.UpdateJobSourceResponse), err
+}
+
+func (c *masterServerClient) CreateJobSchedule(ctx context.Context, req *pb.CreateJobScheduleRequest, opts ...grpc.CallOption) (*pb.CreateJobScheduleResponse, error) {
+	resp, err := c.conn.sendRequest(ctx, req)
+	return resp.(*pb.CreateJobScheduleResponse), err
+}
+
+func (c *masterServerClient) UpdateJobSchedule(ctx context.Context, req *pb.UpdateJobScheduleRequest, opts ...grpc.CallOption) (*pb.UpdateJobScheduleResponse, error) {
+	resp, err := c.conn.sendRequest(ctx, req)
+	return resp.(*pb.UpdateJobScheduleResponse), err
+}
+
+func (c *masterServerClient) DeleteJobSchedule(ctx context.Context, req *pb.DeleteJobScheduleRequest, opts ...grpc.CallOption) (*pb.DeleteJobScheduleResponse, error) {
+	resp, err := c.conn.sendRequest(ctx, req)
+	return resp.(*pb.DeleteJobScheduleResponse), err
+}
+
+func (c *masterServerClient) QueryJobSchedules(ctx context.Context, req *pb.QueryJobSchedulesRequest, opts ...grpc.CallOption) (*pb.QueryJobSchedulesResponse, error) {
+	resp, err := c.conn.sendRequest(ctx, req)
+	return resp.(*pb.QueryJobSchedulesResponse), err
+}
+
+func (c *masterServerClient) RegisterJobTemplate(ctx context.Context, req *pb.RegisterJobTemplateRequest, opts ...grpc.CallOption) (*pb.RegisterJobTemplateResponse, error) {
+	resp, err := c.conn.sendRequest(ctx, req)
+	return resp.(*pb.RegisterJobTemplateResponse), err
+}
+
+func (c *masterServerClient) DeleteJobTemplate(ctx context.Context, req *pb.DeleteJobTemplateRequest, opts ...grpc.CallOption) (*pb.DeleteJobTemplateResponse, error) {
+	resp, err := c.conn.sendRequest(ctx, req)
+	return resp.(*pb.DeleteJobTemplateResponse), err
+}
+
+func (c *masterServerClient) QueryJobTemplates(ctx context.Context, req *pb.QueryJobTemplatesRequest, opts ...grpc.CallOption) (*pb.QueryJobTemplatesResponse, error) {
+	resp, err := c.conn.sendRequest(ctx, req)
+	return resp.(*pb.QueryJobTemplatesResponse), err
+}
+
+func (c *masterServerClient) PutSecret(ctx context.Context, req *pb.PutSecretRequest, opts ...grpc.CallOption) (*pb.PutSecretResponse, error) {
+	resp, err := c.conn.sendRequest(ctx, req)
+	return resp.(*pb.PutSecretResponse), err
+}
+
+func (c *masterServerClient) DeleteSecret(ctx context.Context, req *pb.DeleteSecretRequest, opts ...grpc.CallOption) (*pb.DeleteSecretResponse, error) {
+	resp, err := c.conn.sendRequest(ctx, req)
+	return resp.(*pb.DeleteSecretResponse), err
+}
+
+func (c *masterServerClient) ListSecrets(ctx context.Context, req *pb.ListSecretsRequest, opts ...grpc.CallOption) (*pb.ListSecretsResponse, error) {
+	resp, err := c.conn.sendRequest(ctx, req)
+	return resp.(*pb.ListSecretsResponse), err
+}
+
 func (c *masterServerClient) CancelJob(ctx context.Context, req *pb.CancelJobRequest, opts ...grpc.CallOption) (*pb.CancelJobResponse, error) {
 	resp, err := c.conn.sendRequest(ctx, req)
 	return resp.(*pb.CancelJobResponse), err
@@ -116,6 +242,16 @@ func (c *masterServerClient) QueryMetaStore(
 	return resp.(*pb.QueryMetaStoreResponse), nil
 }
 
+func (c *masterServerClient) MigrateMetaStore(
+	ctx context.Context, req *pb.MigrateMetaStoreRequest, opts ...grpc.CallOption,
+) (*pb.MigrateMetaStoreResponse, error) {
+	resp, err := c.conn.sendRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.MigrateMetaStoreResponse), nil
+}
+
 func (c *masterServerClient) QueryJob(
 	ctx context.Context, req *pb.QueryJobRequest, opts ...grpc.CallOption,
 ) (*pb.QueryJobResponse, error) {
@@ -126,6 +262,56 @@ func (c *masterServerClient) QueryJob(
 	return resp.(*pb.QueryJobResponse), nil
 }
 
+func (c *masterServerClient) QueryJobs(
+	ctx context.Context, req *pb.QueryJobsRequest, opts ...grpc.CallOption,
+) (*pb.QueryJobsResponse, error) {
+	resp, err := c.conn.sendRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.QueryJobsResponse), nil
+}
+
+func (c *masterServerClient) ListWorkers(
+	ctx context.Context, req *pb.ListWorkersRequest, opts ...grpc.CallOption,
+) (*pb.ListWorkersResponse, error) {
+	resp, err := c.conn.sendRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.ListWorkersResponse), nil
+}
+
+func (c *masterServerClient) CordonExecutor(
+	ctx context.Context, req *pb.CordonExecutorRequest, opts ...grpc.CallOption,
+) (*pb.CordonExecutorResponse, error) {
+	resp, err := c.conn.sendRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.CordonExecutorResponse), nil
+}
+
+func (c *masterServerClient) QueryExecutorCordons(
+	ctx context.Context, req *pb.QueryExecutorCordonsRequest, opts ...grpc.CallOption,
+) (*pb.QueryExecutorCordonsResponse, error) {
+	resp, err := c.conn.sendRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.QueryExecutorCordonsResponse), nil
+}
+
+func (c *masterServerClient) ClusterHealth(
+	ctx context.Context, req *pb.ClusterHealthRequest, opts ...grpc.CallOption,
+) (*pb.ClusterHealthResponse, error) {
+	resp, err := c.conn.sendRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.ClusterHealthResponse), nil
+}
+
 func (c *masterServerClient) PersistResource(
 	ctx context.Context, req *pb.PersistResourceRequest, opts ...grpc.CallOption,
 ) (*pb.PersistResourceResponse, error) {
@@ -190,6 +376,14 @@ func (c *executorClient) ConfirmDispatchTask(ctx context.Context, in *pb.Confirm
 	panic("implement me")
 }
 
+func (c *executorClient) Shutdown(ctx context.Context, in *pb.ShutdownRequest, opts ...grpc.CallOption) (*pb.ShutdownResponse, error) {
+	resp, err := c.conn.sendRequest(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.ShutdownResponse), nil
+}
+
 // Close closes executor server conn
 func (s *executorServerConn) Close() error {
 	return nil
@@ -206,6 +400,8 @@ func (s *executorServerConn) sendRequest(ctx context.Context, req interface{}) (
 		return s.server.PreDispatchTask(ctx, x)
 	case *pb.ConfirmDispatchTaskRequest:
 		return s.server.ConfirmDispatchTask(ctx, x)
+	case *pb.ShutdownRequest:
+		return s.server.Shutdown(ctx, x)
 	default:
 	}
 	return nil, errors.New("unknown request")
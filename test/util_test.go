@@ -3,9 +3,11 @@ package test_test
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/hanfei1991/microcosm/executor"
+	"github.com/hanfei1991/microcosm/model"
 	"github.com/hanfei1991/microcosm/pkg/etcdutils"
 	"github.com/hanfei1991/microcosm/pkg/metadata"
 	"github.com/hanfei1991/microcosm/servermaster"
@@ -13,113 +15,283 @@ import (
 	"github.com/hanfei1991/microcosm/test/mock"
 	"github.com/phayes/freeport"
 	. "github.com/pingcap/check"
+	"github.com/pingcap/errors"
 )
 
-// TODO: support multi master / executor
+const (
+	defaultKeepAliveTTL      = 20000000 * time.Second
+	defaultKeepAliveInterval = 200 * time.Millisecond
+	defaultRPCTimeout        = time.Second
+	defaultReadyTimeout      = 10 * time.Second
+	readyProbeInterval       = 50 * time.Millisecond
+)
+
+// MiniClusterConfig configures a MiniCluster, zero values are replaced by
+// the defaults.
+type MiniClusterConfig struct {
+	// MasterAddr is the address of the server master, a free port is
+	// allocated if it is empty.
+	MasterAddr        string
+	KeepAliveTTL      time.Duration
+	KeepAliveInterval time.Duration
+	RPCTimeout        time.Duration
+	// ReadyTimeout is how long to wait for a server to become ready.
+	ReadyTimeout time.Duration
+}
+
+func (cfg *MiniClusterConfig) adjust() error {
+	if cfg.MasterAddr == "" {
+		addr, err := freeAddr()
+		if err != nil {
+			return err
+		}
+		cfg.MasterAddr = addr
+	}
+	if cfg.KeepAliveTTL == 0 {
+		cfg.KeepAliveTTL = defaultKeepAliveTTL
+	}
+	if cfg.KeepAliveInterval == 0 {
+		cfg.KeepAliveInterval = defaultKeepAliveInterval
+	}
+	if cfg.RPCTimeout == 0 {
+		cfg.RPCTimeout = defaultRPCTimeout
+	}
+	if cfg.ReadyTimeout == 0 {
+		cfg.ReadyTimeout = defaultReadyTimeout
+	}
+	return nil
+}
+
+func freeAddr() (string, error) {
+	port, err := freeport.GetFreePort()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return fmt.Sprintf("127.0.0.1:%d", port), nil
+}
+
+type miniExecutor struct {
+	server  *executor.Server
+	cancel  func()
+	testCtx *test.Context
+	addr    string
+}
+
+// MiniCluster runs one server master and any number of executors in the
+// test mode, executors can be added or removed at runtime.
+// TODO: support multi master
 type MiniCluster struct {
+	cfg MiniClusterConfig
+
 	master       *servermaster.Server
 	masterCancel func()
 
-	exec       *executor.Server
-	execCancel func()
+	mu        sync.Mutex
+	executors map[model.ExecutorID]*miniExecutor
 
 	metastore metadata.MetaKV
 }
 
+// NewEmptyMiniCluster creates a MiniCluster with the default config.
 func NewEmptyMiniCluster() *MiniCluster {
-	c := new(MiniCluster)
-	c.metastore = metadata.NewMetaMock()
+	c, err := NewMiniCluster(MiniClusterConfig{})
+	if err != nil {
+		panic(err)
+	}
 	return c
 }
 
-func (c *MiniCluster) CreateMaster(cfg *servermaster.Config) (*test.Context, error) {
+// NewMiniCluster creates a MiniCluster, no server is started.
+func NewMiniCluster(cfg MiniClusterConfig) (*MiniCluster, error) {
+	if err := cfg.adjust(); err != nil {
+		return nil, err
+	}
+	return &MiniCluster{
+		cfg:       cfg,
+		executors: make(map[model.ExecutorID]*miniExecutor),
+		metastore: metadata.NewMetaMock(),
+	}, nil
+}
+
+// MasterAddr returns the address of the server master.
+func (c *MiniCluster) MasterAddr() string {
+	return c.cfg.MasterAddr
+}
+
+// StartMaster starts the server master and waits until it is ready.
+func (c *MiniCluster) StartMaster() (*test.Context, error) {
+	masterCfg := &servermaster.Config{
+		Etcd: &etcdutils.ConfigParams{
+			Name:    "master1",
+			DataDir: "/tmp/df",
+		},
+		MasterAddr:        c.cfg.MasterAddr,
+		AdvertiseAddr:     c.cfg.MasterAddr,
+		KeepAliveTTL:      c.cfg.KeepAliveTTL,
+		KeepAliveInterval: c.cfg.KeepAliveInterval,
+		RPCTimeout:        c.cfg.RPCTimeout,
+	}
 	masterCtx := test.NewContext()
 	masterCtx.SetMetaKV(c.metastore)
-	master, err := servermaster.NewServer(cfg, masterCtx)
+	master, err := servermaster.NewServer(masterCfg, masterCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := master.Run(ctx); err != nil {
+		cancel()
+		return nil, err
+	}
 	c.master = master
-	return masterCtx, err
+	c.masterCancel = cancel
+
+	if err := c.WaitMasterReady(); err != nil {
+		return nil, err
+	}
+	return masterCtx, nil
 }
 
-func (c *MiniCluster) AsyncStartMaster() error {
-	ctx := context.Background()
-	masterCtx, masterCancel := context.WithCancel(ctx)
-	err := c.master.Run(masterCtx)
-	c.masterCancel = masterCancel
-	return err
+// StopMaster stops the server master.
+func (c *MiniCluster) StopMaster() {
+	c.masterCancel()
+	c.master.Stop()
 }
 
-func (c *MiniCluster) CreateExecutor(cfg *executor.Config) *test.Context {
-	execContext := test.NewContext()
-	execContext.SetMetaKV(c.metastore)
-	exec := executor.NewServer(cfg, execContext)
-	c.exec = exec
-	return execContext
+// AddExecutor starts a new executor listening on addr, a free port is
+// allocated if addr is empty. It returns after the executor is registered
+// to the server master.
+func (c *MiniCluster) AddExecutor(addr string) (model.ExecutorID, *test.Context, error) {
+	if addr == "" {
+		var err error
+		addr, err = freeAddr()
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	executorCfg := &executor.Config{
+		Join:              c.cfg.MasterAddr,
+		WorkerAddr:        addr,
+		AdvertiseAddr:     addr,
+		KeepAliveTTL:      c.cfg.KeepAliveTTL,
+		KeepAliveInterval: c.cfg.KeepAliveInterval,
+		RPCTimeout:        c.cfg.RPCTimeout,
+	}
+	execCtx := test.NewContext()
+	execCtx.SetMetaKV(c.metastore)
+	exec := executor.NewServer(executorCfg, execCtx)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := exec.Run(ctx); err != nil {
+		cancel()
+		exec.Stop()
+		return "", nil, err
+	}
+	id := exec.ExecutorID()
+
+	c.mu.Lock()
+	c.executors[id] = &miniExecutor{
+		server:  exec,
+		cancel:  cancel,
+		testCtx: execCtx,
+		addr:    addr,
+	}
+	c.mu.Unlock()
+
+	if err := c.WaitExecutorReady(id); err != nil {
+		return "", nil, err
+	}
+	return id, execCtx, nil
 }
 
-func (c *MiniCluster) AsyncStartExector() error {
-	ctx := context.Background()
-	execCtx, execCancel := context.WithCancel(ctx)
-	err := c.exec.Run(execCtx)
-	c.execCancel = execCancel
-	return err
+// RemoveExecutor stops an executor, the server master notices the removal
+// after the keepalive TTL, which can be waited by WaitExecutorRemoved.
+func (c *MiniCluster) RemoveExecutor(id model.ExecutorID) error {
+	c.mu.Lock()
+	exec, ok := c.executors[id]
+	delete(c.executors, id)
+	c.mu.Unlock()
+	if !ok {
+		return errors.Errorf("executor %s not found", id)
+	}
+
+	exec.cancel()
+	exec.server.Stop()
+	return nil
 }
 
-func (c *MiniCluster) StopExec() {
-	c.execCancel()
-	c.exec.Stop()
+// ExecutorIDs returns the IDs of the running executors.
+func (c *MiniCluster) ExecutorIDs() []model.ExecutorID {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ret := make([]model.ExecutorID, 0, len(c.executors))
+	for id := range c.executors {
+		ret = append(ret, id)
+	}
+	return ret
 }
 
-func (c *MiniCluster) StopMaster() {
-	c.masterCancel()
-	c.master.Stop()
+// WaitMasterReady waits until the server master has initialized the leader
+// services.
+func (c *MiniCluster) WaitMasterReady() error {
+	return c.waitFor("server master ready", c.master.LeaderInitialized)
+}
+
+// WaitExecutorReady waits until the executor is known by the server master.
+func (c *MiniCluster) WaitExecutorReady(id model.ExecutorID) error {
+	return c.waitFor(fmt.Sprintf("executor %s ready", id), func() bool {
+		return c.master.HasExecutor(id)
+	})
+}
+
+// WaitExecutorRemoved waits until the server master removes the executor,
+// which takes the keepalive TTL after the executor stops.
+func (c *MiniCluster) WaitExecutorRemoved(id model.ExecutorID) error {
+	timeout := c.cfg.ReadyTimeout + c.cfg.KeepAliveTTL + c.cfg.RPCTimeout
+	return c.waitForWithTimeout(fmt.Sprintf("executor %s removed", id), func() bool {
+		return !c.master.HasExecutor(id)
+	}, timeout)
 }
 
-// Start 1 master 1 executor.
+func (c *MiniCluster) waitFor(what string, cond func() bool) error {
+	return c.waitForWithTimeout(what, cond, c.cfg.ReadyTimeout)
+}
+
+func (c *MiniCluster) waitForWithTimeout(what string, cond func() bool, timeout time.Duration) error {
+	ticker := time.NewTicker(readyProbeInterval)
+	defer ticker.Stop()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			return errors.Errorf("waiting for %s timeout after %s", what, timeout)
+		}
+		<-ticker.C
+	}
+	return nil
+}
+
+// Start1M1E starts 1 master 1 executor.
 func (c *MiniCluster) Start1M1E(cc *C) (
 	masterAddr string, workerAddr string,
 	masterCtx *test.Context, workerCtx *test.Context,
 ) {
-	ports, err := freeport.GetFreePorts(2)
-	cc.Assert(err, IsNil)
-	masterAddr = fmt.Sprintf("127.0.0.1:%d", ports[0])
-	workerAddr = fmt.Sprintf("127.0.0.1:%d", ports[1])
-	masterCfg := &servermaster.Config{
-		Etcd: &etcdutils.ConfigParams{
-			Name:    "master1",
-			DataDir: "/tmp/df",
-		},
-		MasterAddr:        masterAddr,
-		AdvertiseAddr:     masterAddr,
-		KeepAliveTTL:      20000000 * time.Second,
-		KeepAliveInterval: 200 * time.Millisecond,
-		RPCTimeout:        time.Second,
-	}
-	// one master + one executor
-	executorCfg := &executor.Config{
-		Join:              masterAddr,
-		WorkerAddr:        workerAddr,
-		AdvertiseAddr:     workerAddr,
-		KeepAliveTTL:      20000000 * time.Second,
-		KeepAliveInterval: 200 * time.Millisecond,
-		RPCTimeout:        time.Second,
-	}
-
-	masterCtx, err = c.CreateMaster(masterCfg)
-	cc.Assert(err, IsNil)
-	workerCtx = c.CreateExecutor(executorCfg)
-	// Start cluster
-	err = c.AsyncStartMaster()
+	masterCtx, err := c.StartMaster()
 	cc.Assert(err, IsNil)
-
-	err = c.AsyncStartExector()
+	id, workerCtx, err := c.AddExecutor("")
 	cc.Assert(err, IsNil)
 
-	time.Sleep(2 * time.Second)
-	return
+	c.mu.Lock()
+	workerAddr = c.executors[id].addr
+	c.mu.Unlock()
+	return c.cfg.MasterAddr, workerAddr, masterCtx, workerCtx
 }
 
+// StopCluster stops all executors and the server master.
 func (c *MiniCluster) StopCluster() {
-	c.StopExec()
+	for _, id := range c.ExecutorIDs() {
+		_ = c.RemoveExecutor(id)
+	}
 	c.StopMaster()
 	mock.ResetGrpcCtx()
 }